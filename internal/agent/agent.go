@@ -0,0 +1,280 @@
+// Package agent runs scans for a central `hunter serve` from the network
+// segment the agent runs in, so one server can scan networks it cannot
+// reach itself. An agent polls the server for the scans dispatched to it,
+// runs them one at a time, and reports their progress, logs, and results
+// back as they come in.
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/internal/config"
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/web/api"
+	"github.com/buemura/hunter/internal/web/jobs"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// DefaultInterval is how often an agent polls for work and reports on the
+// scan it runs.
+const DefaultInterval = 5 * time.Second
+
+// errRejected reports that the server refused the agent, as with a wrong
+// token, which retrying does not fix.
+var errRejected = errors.New("server rejected the agent")
+
+// errStop reports that the server no longer wants the running scan.
+var errStop = errors.New("scan was deleted or ended on the server")
+
+// Agent polls a server for scans and runs them.
+type Agent struct {
+	// Server is the base URL of `hunter serve`, including any base path.
+	Server string
+	// Name identifies the agent; scans are dispatched to it by name.
+	Name string
+	// Token is the server's agent_token.
+	Token string
+	// Interval is how often to poll and report; zero means DefaultInterval.
+	Interval time.Duration
+	// Client sends requests to the server; nil means a client with a 30
+	// second timeout.
+	Client *http.Client
+
+	// Runner runs the scans, with Config's per-scanner settings and
+	// severity overrides.
+	Runner *scanner.Runner
+	Config *config.Config
+	// Authorize, if set, refuses targets the agent may not scan; the scan
+	// then fails with its error.
+	Authorize func(types.Target) error
+	// Logf, if set, reports what the agent does.
+	Logf func(format string, args ...interface{})
+}
+
+// Run polls for scans and runs them until ctx is done, when it returns nil,
+// or the server rejects the agent.
+func (a *Agent) Run(ctx context.Context) error {
+	for {
+		job, err := a.next(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case errors.Is(err, errRejected):
+			return err
+		case err != nil:
+			a.logf("Polling %s failed: %v", a.Server, err)
+		case job != nil:
+			a.run(ctx, job)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(a.interval()):
+		}
+	}
+}
+
+// next asks the server for the next scan dispatched to the agent, nil if
+// there is none.
+func (a *Agent) next(ctx context.Context) (*api.AgentJob, error) {
+	resp, err := a.do(ctx, http.MethodGet, "/jobs/next", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return nil, nil
+	}
+	var job api.AgentJob
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		return nil, fmt.Errorf("invalid job: %w", err)
+	}
+	return &job, nil
+}
+
+// run runs a scan and reports on it every interval until it ends, the
+// server stops wanting it, or ctx is done.
+func (a *Agent) run(ctx context.Context, job *api.AgentJob) {
+	m, local, err := a.start(job)
+	if err != nil {
+		a.logf("Scan %s refused: %v", job.ID, err)
+		msg := fmt.Sprintf("agent %s refused the scan: %v", a.Name, err)
+		a.send(ctx, job.ID, jobs.Report{Status: jobs.StatusFailed, Error: msg})
+		return
+	}
+	a.logf("Scan %s of %s started", job.ID, describe(local.Target))
+
+	var sent jobs.Job
+	ticker := time.NewTicker(a.interval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			m.Cancel(local.ID)
+			// The server fails the scan once the agent stops responding,
+			// but need not wait for it.
+			stopCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			a.send(stopCtx, job.ID, jobs.Report{Status: jobs.StatusFailed, Error: "agent stopped"})
+			cancel()
+			return
+		case <-ticker.C:
+		}
+
+		snapshot, _ := m.Snapshot(local.ID)
+		err := a.send(ctx, job.ID, report(sent, snapshot))
+		switch {
+		case errors.Is(err, errStop):
+			a.logf("Scan %s stopped: %v", job.ID, err)
+			m.Cancel(local.ID)
+			return
+		case err != nil:
+			// Nothing was recorded, so it is sent again with the next
+			// report.
+			a.logf("Reporting on scan %s failed: %v", job.ID, err)
+			continue
+		}
+		sent = snapshot
+		if snapshot.Status == jobs.StatusCompleted || snapshot.Status == jobs.StatusFailed {
+			a.logf("Scan %s %s", job.ID, snapshot.Status)
+			return
+		}
+	}
+}
+
+// start checks the scan request and starts it on a manager of its own.
+func (a *Agent) start(job *api.AgentJob) (*jobs.Manager, *jobs.Job, error) {
+	var req api.CreateScanRequest
+	if err := json.Unmarshal(job.Request, &req); err != nil {
+		return nil, nil, fmt.Errorf("invalid scan request: %w", err)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, nil, err
+	}
+	target, err := types.ParseTarget(req.Target)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid target: %w", err)
+	}
+	if a.Authorize != nil {
+		if err := a.Authorize(target); err != nil {
+			return nil, nil, err
+		}
+	}
+	cfg := a.Config
+	if cfg == nil {
+		defaults := config.Defaults()
+		cfg = &defaults
+	}
+	opts, err := api.ScanOptions(&req, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	m := jobs.NewManager(a.Runner)
+	local := m.Create(target, req.Scanners, opts)
+	if err := m.Start(local.ID); err != nil {
+		return nil, nil, err
+	}
+	return m, local, nil
+}
+
+// report describes what changed in a job between two snapshots.
+func report(sent, now jobs.Job) jobs.Report {
+	r := jobs.Report{
+		Status:      now.Status,
+		Progress:    now.Progress,
+		Results:     now.Results[len(sent.Results):],
+		Logs:        now.Logs[len(sent.Logs):],
+		LogsDropped: now.LogsDropped - sent.LogsDropped,
+		Error:       now.Error,
+	}
+	if r.Status == jobs.StatusPaused {
+		r.Status = jobs.StatusRunning
+	}
+	return r
+}
+
+// send posts a report on a scan to the server.
+func (a *Agent) send(ctx context.Context, jobID string, r jobs.Report) error {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	resp, err := a.do(ctx, http.MethodPost, "/jobs/"+url.PathEscape(jobID), body)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// do sends a request to the agent's endpoints on the server and checks its
+// status.
+func (a *Agent) do(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	u := strings.TrimRight(a.Server, "/") + "/api/v1/agents/" + url.PathEscape(a.Name) + path
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+a.Token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := a.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 300 {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+	var apiErr api.ErrorResponse
+	json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&apiErr)
+	if apiErr.Error == "" {
+		apiErr.Error = resp.Status
+	}
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("%w: %s", errRejected, apiErr.Error)
+	case http.StatusNotFound, http.StatusConflict:
+		return nil, fmt.Errorf("%w: %s", errStop, apiErr.Error)
+	}
+	return nil, fmt.Errorf("server responded %s", apiErr.Error)
+}
+
+func (a *Agent) interval() time.Duration {
+	if a.Interval > 0 {
+		return a.Interval
+	}
+	return DefaultInterval
+}
+
+func (a *Agent) logf(format string, args ...interface{}) {
+	if a.Logf != nil {
+		a.Logf(format, args...)
+	}
+}
+
+func describe(t types.Target) string {
+	if t.URL != "" {
+		return t.URL
+	}
+	return t.Host
+}
@@ -0,0 +1,158 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/buemura/hunter/internal/config"
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/web"
+	"github.com/buemura/hunter/internal/web/jobs"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockScanner struct{ name string }
+
+func (m *mockScanner) Name() string        { return m.name }
+func (m *mockScanner) Description() string { return "mock" }
+func (m *mockScanner) Run(_ context.Context, target types.Target, _ scanner.Options) (*types.ScanResult, error) {
+	return &types.ScanResult{
+		ScannerName: m.name,
+		Target:      target,
+		Findings:    []types.Finding{{Title: m.name + " finding", Severity: types.SeverityInfo}},
+	}, nil
+}
+
+func newRegistry() *scanner.Registry {
+	reg := scanner.NewRegistry()
+	reg.Register(&mockScanner{name: "headers"})
+	reg.Register(&mockScanner{name: "port"})
+	return reg
+}
+
+// startServer runs a web server accepting agents with the token "s3cret".
+func startServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	cfg := config.Defaults()
+	cfg.AgentToken = "s3cret"
+	srv := web.NewServer(":0", newRegistry(), web.Options{Config: &cfg})
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// startAgent runs a until the test ends.
+func startAgent(t *testing.T, a *Agent) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		a.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+// dispatch creates a scan for the agent dmz-1 once it has connected.
+func dispatch(t *testing.T, ts *httptest.Server, target string) string {
+	t.Helper()
+	var id string
+	require.Eventually(t, func() bool {
+		body := `{"target": "` + target + `", "agent": "dmz-1"}`
+		resp, err := http.Post(ts.URL+"/api/v1/scans", "application/json", bytes.NewBufferString(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			return false
+		}
+		var created map[string]string
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+		id = created["id"]
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+	return id
+}
+
+func getJob(t *testing.T, ts *httptest.Server, id string) jobs.Job {
+	t.Helper()
+	resp, err := http.Get(ts.URL + "/api/v1/scans/" + id)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var job jobs.Job
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&job))
+	return job
+}
+
+func TestAgentRunsDispatchedScan(t *testing.T) {
+	ts := startServer(t)
+	startAgent(t, &Agent{
+		Server:   ts.URL,
+		Name:     "dmz-1",
+		Token:    "s3cret",
+		Interval: 10 * time.Millisecond,
+		Runner:   scanner.NewRunner(newRegistry()),
+	})
+
+	id := dispatch(t, ts, "https://intranet.local")
+	var job jobs.Job
+	require.Eventually(t, func() bool {
+		job = getJob(t, ts, id)
+		return job.Status == jobs.StatusCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, "dmz-1", job.Agent)
+	assert.Equal(t, 2, job.FindingCount())
+	assert.Equal(t, 2, job.Progress.CompletedScanners)
+
+	resp, err := http.Get(ts.URL + "/api/v1/scans/" + id + "/logs")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var logs struct {
+		Logs []scanner.LogEntry `json:"logs"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&logs))
+	assert.NotEmpty(t, logs.Logs, "the agent's logs are reported")
+}
+
+func TestAgentRefusesUnauthorizedTarget(t *testing.T) {
+	ts := startServer(t)
+	startAgent(t, &Agent{
+		Server:   ts.URL,
+		Name:     "dmz-1",
+		Token:    "s3cret",
+		Interval: 10 * time.Millisecond,
+		Runner:   scanner.NewRunner(newRegistry()),
+		Authorize: func(types.Target) error {
+			return errors.New("target is not in authorized_targets")
+		},
+	})
+
+	id := dispatch(t, ts, "https://intranet.local")
+	var job jobs.Job
+	require.Eventually(t, func() bool {
+		job = getJob(t, ts, id)
+		return job.Status == jobs.StatusFailed
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Contains(t, job.Error, "agent dmz-1 refused the scan")
+	assert.Zero(t, job.FindingCount())
+}
+
+func TestAgentStopsWhenRejected(t *testing.T) {
+	ts := startServer(t)
+	a := &Agent{Server: ts.URL, Name: "dmz-1", Token: "wrong", Runner: scanner.NewRunner(newRegistry())}
+
+	err := a.Run(context.Background())
+	assert.ErrorIs(t, err, errRejected)
+	assert.Contains(t, err.Error(), "invalid agent token")
+}
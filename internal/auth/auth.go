@@ -0,0 +1,139 @@
+// Package auth turns named credentials from the config file into request
+// authentication for scanners.
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/buemura/hunter/internal/config"
+)
+
+// Authenticator adds credentials to an outgoing request.
+type Authenticator func(req *http.Request)
+
+// New resolves cred's secrets and returns an Authenticator for it. For the
+// "login" type this performs the login request with client (nil means
+// http.DefaultClient) and authenticates with the token or cookies it returns.
+func New(ctx context.Context, cred config.Credential, client *http.Client) (Authenticator, error) {
+	switch cred.Type {
+	case "basic":
+		user, pass, err := resolvePair(cred)
+		if err != nil {
+			return nil, err
+		}
+		return func(req *http.Request) { req.SetBasicAuth(user, pass) }, nil
+
+	case "bearer":
+		token, err := config.ResolveSecret(cred.Token)
+		if err != nil {
+			return nil, fmt.Errorf("token: %w", err)
+		}
+		if token == "" {
+			return nil, fmt.Errorf("bearer credential has no token")
+		}
+		return bearer(cred.Header, token), nil
+
+	case "login":
+		return login(ctx, cred, client)
+	}
+	return nil, fmt.Errorf("unknown credential type %q", cred.Type)
+}
+
+// Hosts returns the host names cred is sent to besides the scan target's:
+// the login host of a "login" credential.
+func Hosts(cred config.Credential) []string {
+	if cred.Type != "login" {
+		return nil
+	}
+	u, err := url.Parse(cred.LoginURL)
+	if err != nil || u.Hostname() == "" {
+		return nil
+	}
+	return []string{u.Hostname()}
+}
+
+func resolvePair(cred config.Credential) (string, string, error) {
+	user, err := config.ResolveSecret(cred.Username)
+	if err != nil {
+		return "", "", fmt.Errorf("username: %w", err)
+	}
+	pass, err := config.ResolveSecret(cred.Password)
+	if err != nil {
+		return "", "", fmt.Errorf("password: %w", err)
+	}
+	return user, pass, nil
+}
+
+func bearer(header, token string) Authenticator {
+	if header == "" {
+		return func(req *http.Request) { req.Header.Set("Authorization", "Bearer "+token) }
+	}
+	return func(req *http.Request) { req.Header.Set(header, token) }
+}
+
+// login posts the username and password as JSON to cred.LoginURL. A token
+// found under TokenField in the JSON response is sent as a bearer token;
+// otherwise the session cookies set by the response are sent.
+func login(ctx context.Context, cred config.Credential, client *http.Client) (Authenticator, error) {
+	if cred.LoginURL == "" {
+		return nil, fmt.Errorf("login credential has no login_url")
+	}
+	user, pass, err := resolvePair(cred)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	body, _ := json.Marshal(map[string]string{
+		fieldOr(cred.UsernameField, "username"): user,
+		fieldOr(cred.PasswordField, "password"): pass,
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cred.LoginURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("logging in: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("logging in: %s returned %s", cred.LoginURL, resp.Status)
+	}
+
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	var payload map[string]interface{}
+	if json.Unmarshal(raw, &payload) == nil {
+		if token, ok := payload[fieldOr(cred.TokenField, "token")].(string); ok && token != "" {
+			return bearer(cred.Header, token), nil
+		}
+	}
+
+	cookies := resp.Cookies()
+	if len(cookies) == 0 {
+		return nil, fmt.Errorf("logging in: response had neither a %q field nor session cookies", fieldOr(cred.TokenField, "token"))
+	}
+	return func(req *http.Request) {
+		for _, c := range cookies {
+			req.AddCookie(&http.Cookie{Name: c.Name, Value: c.Value})
+		}
+	}, nil
+}
+
+func fieldOr(field, fallback string) string {
+	if field == "" {
+		return fallback
+	}
+	return field
+}
@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/buemura/hunter/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func apply(t *testing.T, a Authenticator) *http.Request {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+	a(req)
+	return req
+}
+
+func TestNew_Basic(t *testing.T) {
+	t.Setenv("HUNTER_TEST_PASSWORD", "s3cret")
+
+	a, err := New(context.Background(), config.Credential{Type: "basic", Username: "admin", Password: "env:HUNTER_TEST_PASSWORD"}, nil)
+	require.NoError(t, err)
+
+	user, pass, ok := apply(t, a).BasicAuth()
+	require.True(t, ok)
+	assert.Equal(t, "admin", user)
+	assert.Equal(t, "s3cret", pass)
+}
+
+func TestNew_Bearer(t *testing.T) {
+	a, err := New(context.Background(), config.Credential{Type: "bearer", Token: "abc"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer abc", apply(t, a).Header.Get("Authorization"))
+
+	a, err = New(context.Background(), config.Credential{Type: "bearer", Token: "abc", Header: "X-API-Key"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", apply(t, a).Header.Get("X-API-Key"))
+
+	_, err = New(context.Background(), config.Credential{Type: "bearer", Token: "env:HUNTER_TEST_UNSET_TOKEN"}, nil)
+	assert.ErrorContains(t, err, "HUNTER_TEST_UNSET_TOKEN")
+}
+
+func TestNew_LoginToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["email"] != "me@example.com" || body["password"] != "pw" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "tok"})
+	}))
+	defer srv.Close()
+
+	cred := config.Credential{
+		Type:          "login",
+		LoginURL:      srv.URL,
+		Username:      "me@example.com",
+		Password:      "pw",
+		UsernameField: "email",
+		TokenField:    "access_token",
+	}
+	a, err := New(context.Background(), cred, srv.Client())
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer tok", apply(t, a).Header.Get("Authorization"))
+
+	cred.Password = "wrong"
+	_, err = New(context.Background(), cred, srv.Client())
+	assert.ErrorContains(t, err, "401")
+}
+
+func TestNew_LoginCookies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "xyz"})
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	a, err := New(context.Background(), config.Credential{Type: "login", LoginURL: srv.URL, Username: "u", Password: "p"}, srv.Client())
+	require.NoError(t, err)
+
+	c, err := apply(t, a).Cookie("session")
+	require.NoError(t, err)
+	assert.Equal(t, "xyz", c.Value)
+}
+
+func TestNew_LoginWithoutSession(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	_, err := New(context.Background(), config.Credential{Type: "login", LoginURL: srv.URL}, srv.Client())
+	assert.ErrorContains(t, err, "neither")
+
+	_, err = New(context.Background(), config.Credential{Type: "login"}, nil)
+	assert.ErrorContains(t, err, "login_url")
+}
+
+func TestHosts(t *testing.T) {
+	assert.Equal(t, []string{"sso.example.com"}, Hosts(config.Credential{Type: "login", LoginURL: "https://sso.example.com:8443/api/login"}))
+	assert.Nil(t, Hosts(config.Credential{Type: "bearer", Token: "t"}))
+}
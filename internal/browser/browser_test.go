@@ -0,0 +1,74 @@
+package browser
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChrome writes a script standing in for Chrome: it prints a page for
+// --dump-dom and writes "PNG" plus its arguments to the --screenshot file.
+func fakeChrome(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "chrome")
+	script := `#!/bin/sh
+for arg in "$@"; do
+	case "$arg" in
+	--dump-dom) echo "<html><body>rendered</body></html>" ;;
+	--screenshot=*) shot="${arg#--screenshot=}" ;;
+	esac
+done
+if [ -n "$shot" ]; then printf 'PNG %s' "$*" > "$shot"; fi
+`
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o755))
+	return path
+}
+
+func TestFind(t *testing.T) {
+	_, err := Find("")
+	assert.Error(t, err)
+
+	_, err = Find(filepath.Join(t.TempDir(), "missing-chrome"))
+	assert.Error(t, err)
+
+	path := fakeChrome(t)
+	found, err := Find(path)
+	require.NoError(t, err)
+	assert.Equal(t, path, found)
+
+	t.Setenv("PATH", filepath.Dir(path))
+	orig := Candidates
+	defer func() { Candidates = orig }()
+	Candidates = []string{"chromium", "chrome"}
+	found, err = Find("auto")
+	require.NoError(t, err)
+	assert.Equal(t, path, found)
+}
+
+func TestDumpDOM(t *testing.T) {
+	dom, err := DumpDOM(context.Background(), fakeChrome(t), "http://example.com/", time.Second)
+	require.NoError(t, err)
+	assert.Contains(t, dom, "rendered")
+}
+
+func TestScreenshot(t *testing.T) {
+	png, err := Screenshot(context.Background(), fakeChrome(t), "http://example.com/admin", time.Second)
+	require.NoError(t, err)
+	assert.Contains(t, string(png), "PNG ")
+	assert.Contains(t, string(png), "--headless=new")
+	assert.Contains(t, string(png), "--window-size=1280,800")
+	assert.Contains(t, string(png), "http://example.com/admin")
+}
+
+func TestScreenshot_BrowserFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chrome")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\necho crashed >&2\nexit 1\n"), 0o755))
+
+	_, err := Screenshot(context.Background(), path, "http://example.com/", time.Second)
+	assert.ErrorContains(t, err, "crashed")
+}
@@ -0,0 +1,117 @@
+// Package cache keeps scanner results on disk so repeat scans of a target
+// within a TTL can reuse the results of scanners whose settings did not
+// change. It implements scanner.ResultCache; each result is stored as one
+// file in the cache directory, encrypted when the store has a key.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/buemura/hunter/internal/atomicfile"
+	"github.com/buemura/hunter/internal/vault"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// DefaultTTL is how long results are reused when no TTL is configured.
+const DefaultTTL = time.Hour
+
+// Store is a local cache directory.
+type Store struct {
+	Dir string
+	// TTL is how long a stored result is reused; zero means DefaultTTL.
+	TTL time.Duration
+	// Version is the version of the scanners. Results stored by other
+	// versions are not reused, since their checks may have changed.
+	Version string
+
+	// Key, when set, encrypts the results the store saves. Results saved
+	// without one can still be read.
+	Key vault.Key
+}
+
+// entry is the file a result is stored in.
+type entry struct {
+	StoredAt time.Time        `json:"stored_at"`
+	Result   types.ScanResult `json:"result"`
+}
+
+// NewStore returns a store rooted at dir, or at DefaultDir when dir is empty.
+func NewStore(dir string, ttl time.Duration, version string) *Store {
+	if dir == "" {
+		dir = DefaultDir()
+	}
+	return &Store{Dir: dir, TTL: ttl, Version: version}
+}
+
+// DefaultDir returns the default cache directory (~/.hunter/cache).
+func DefaultDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".hunter", "cache")
+	}
+	return filepath.Join(home, ".hunter", "cache")
+}
+
+// now is the clock entries are stamped and expired with. Extracted as a
+// variable for testing.
+var now = time.Now
+
+// Get returns the result stored under key by the store's version, unless it
+// is older than the TTL. Expired and unreadable entries are removed.
+func (s *Store) Get(key string) (*types.ScanResult, bool) {
+	path := s.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	if vault.Sealed(data) {
+		if s.Key == nil {
+			return nil, false
+		}
+		if data, err = vault.Open(s.Key, data); err != nil {
+			return nil, false
+		}
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil || now().Sub(e.StoredAt) > s.ttl() {
+		os.Remove(path)
+		return nil, false
+	}
+	return &e.Result, true
+}
+
+// Put stores result under key.
+func (s *Store) Put(key string, result types.ScanResult) error {
+	data, err := json.Marshal(entry{StoredAt: now(), Result: result})
+	if err != nil {
+		return err
+	}
+	if s.Key != nil {
+		if data, err = vault.Seal(s.Key, data); err != nil {
+			return fmt.Errorf("encrypting cached result: %w", err)
+		}
+	}
+	if err := atomicfile.Write(s.path(key), data); err != nil {
+		return fmt.Errorf("caching result: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) ttl() time.Duration {
+	if s.TTL > 0 {
+		return s.TTL
+	}
+	return DefaultTTL
+}
+
+// path returns the file of key, named after it and the store's version.
+func (s *Store) path(key string) string {
+	sum := sha256.Sum256([]byte(s.Version + "\x00" + key))
+	return filepath.Join(s.Dir, hex.EncodeToString(sum[:16])+".json")
+}
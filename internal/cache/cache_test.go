@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/buemura/hunter/internal/vault"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func result() types.ScanResult {
+	return types.ScanResult{
+		ScannerName: "headers",
+		Target:      types.Target{Host: "example.com"},
+		Findings:    []types.Finding{{Title: "Missing header", Severity: types.SeverityLow}},
+	}
+}
+
+func TestPutGet(t *testing.T) {
+	s := NewStore(t.TempDir(), time.Hour, "1.0.0")
+	_, ok := s.Get("k")
+	assert.False(t, ok)
+
+	require.NoError(t, s.Put("k", result()))
+	got, ok := s.Get("k")
+	require.True(t, ok)
+	assert.Equal(t, result(), *got)
+
+	_, ok = s.Get("other")
+	assert.False(t, ok)
+}
+
+func TestGet_Expired(t *testing.T) {
+	s := NewStore(t.TempDir(), time.Minute, "1.0.0")
+	require.NoError(t, s.Put("k", result()))
+
+	defer func() { now = time.Now }()
+	now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	_, ok := s.Get("k")
+	assert.False(t, ok)
+
+	now = time.Now
+	_, ok = s.Get("k")
+	assert.False(t, ok, "expired entries are removed")
+}
+
+func TestGet_OtherVersion(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, NewStore(dir, 0, "1.0.0").Put("k", result()))
+
+	_, ok := NewStore(dir, 0, "1.1.0").Get("k")
+	assert.False(t, ok)
+}
+
+func TestEncrypted(t *testing.T) {
+	dir := t.TempDir()
+	key, err := vault.DeriveKey("secret")
+	require.NoError(t, err)
+	s := NewStore(dir, 0, "1.0.0")
+	s.Key = key
+	require.NoError(t, s.Put("k", result()))
+
+	got, ok := s.Get("k")
+	require.True(t, ok)
+	assert.Equal(t, "Missing header", got.Findings[0].Title)
+
+	_, ok = NewStore(dir, 0, "1.0.0").Get("k")
+	assert.False(t, ok, "encrypted results need the key")
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/buemura/hunter/internal/agent"
+	"github.com/buemura/hunter/internal/config"
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/web/jobs"
+	"github.com/spf13/cobra"
+)
+
+var (
+	agentServerFlag   string
+	agentNameFlag     string
+	agentTokenFlag    string
+	agentIntervalFlag time.Duration
+)
+
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Run scans for a Hunter server from this network",
+	Long: `Runs as a worker for a central hunter serve, so its UI can scan networks
+the server cannot reach. The agent polls the server for the scans dispatched
+to it by name, runs them from this machine, and reports their progress, logs,
+and results back as they come in. Only outbound connections to the server
+are made.
+
+The server and agent share a token, agent_token in their config files or
+--token here. Scans use this machine's config for per-scanner settings,
+severity overrides, and authorized_targets, which refuses targets outside it
+unless the agent runs with --i-am-authorized.`,
+	Args: cobra.NoArgs,
+	RunE: runAgent,
+}
+
+func init() {
+	agentCmd.Flags().StringVar(&agentServerFlag, "server", "", "URL of the hunter serve to take scans from, e.g. https://hunter.example.com")
+	agentCmd.Flags().StringVar(&agentNameFlag, "name", "", "name scans are dispatched to this agent by (default: the host name)")
+	agentCmd.Flags().StringVar(&agentTokenFlag, "token", "", "the server's agent token: literal, env:NAME, or keyring:service/account (default: agent_token from the config)")
+	agentCmd.Flags().DurationVar(&agentIntervalFlag, "interval", agent.DefaultInterval, "how often to poll for scans and report progress")
+	agentCmd.MarkFlagRequired("server")
+	rootCmd.AddCommand(agentCmd)
+}
+
+func runAgent(cmd *cobra.Command, args []string) error {
+	name := agentNameFlag
+	if name == "" {
+		host, err := os.Hostname()
+		if err != nil {
+			return fmt.Errorf("naming the agent: %w; pass --name", err)
+		}
+		name = host
+	}
+	if !jobs.ValidAgentName(name) {
+		return fmt.Errorf("invalid agent name %q: use letters, digits, dots, hyphens, and underscores", name)
+	}
+
+	setting := agentTokenFlag
+	if setting == "" {
+		setting = appConfig.AgentToken
+	}
+	if setting == "" {
+		return fmt.Errorf("an agent token is required: set agent_token in the config or pass --token")
+	}
+	token, err := config.ResolveSecret(setting)
+	if err != nil {
+		return fmt.Errorf("agent token: %w", err)
+	}
+
+	a := &agent.Agent{
+		Server:    agentServerFlag,
+		Name:      name,
+		Token:     token,
+		Interval:  agentIntervalFlag,
+		Runner:    scanner.NewRunner(webRegistry()),
+		Config:    appConfig,
+		Authorize: authorizeTarget,
+		Logf: func(format string, args ...interface{}) {
+			statusf(cmd, format, args...)
+		},
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	statusf(cmd, "Hunter agent %s taking scans from %s", name, agentServerFlag)
+	return a.Run(ctx)
+}
@@ -1,47 +1,81 @@
 package cli
 
 import (
-	"context"
 	"fmt"
 	"os"
 
-	"github.com/buemura/hunter/internal/output"
 	"github.com/buemura/hunter/internal/scanner"
 	"github.com/buemura/hunter/internal/scanner/api"
+	"github.com/buemura/hunter/internal/scanner/cloud"
+	"github.com/buemura/hunter/internal/scanner/csrf"
+	"github.com/buemura/hunter/internal/scanner/custom"
+	"github.com/buemura/hunter/internal/scanner/deserialization"
 	"github.com/buemura/hunter/internal/scanner/dirs"
+	"github.com/buemura/hunter/internal/scanner/forms"
 	"github.com/buemura/hunter/internal/scanner/headers"
+	"github.com/buemura/hunter/internal/scanner/mixedcontent"
 	"github.com/buemura/hunter/internal/scanner/port"
+	"github.com/buemura/hunter/internal/scanner/protocols"
+	"github.com/buemura/hunter/internal/scanner/redirects"
 	"github.com/buemura/hunter/internal/scanner/ssl"
+	"github.com/buemura/hunter/internal/scanner/subdomain"
+	"github.com/buemura/hunter/internal/scanner/tech"
 	"github.com/buemura/hunter/internal/scanner/vuln"
-	"github.com/buemura/hunter/pkg/types"
 	"github.com/spf13/cobra"
 )
 
+var profileFlag string
+
+// reconScannerNames lists the scanners that map the target's surface from
+// passive sources.
+var reconScannerNames = []string{"subdomain"}
+
 // allScannerNames lists every scanner name for the combined profile.
-var allScannerNames = append(append([]string{}, webScannerNames...), apiScannerNames...)
+var allScannerNames = append(append(append([]string{}, webScannerNames...), apiScannerNames...), reconScannerNames...)
 
 var allCmd = &cobra.Command{
 	Use:   "all",
 	Short: "Run all scanners",
-	Long:  "Runs every web and API scanner against the target concurrently.",
+	Long:  "Runs every web, API, and recon scanner against the target concurrently.",
 	RunE:  runAll,
 }
 
 func init() {
+	allCmd.Flags().StringVar(&profileFlag, "profile", "", "run the scanners, and use the credential, of a scan profile from the config file")
+	addSelectionFlags(allCmd)
 	rootCmd.AddCommand(allCmd)
 }
 
 func runAll(cmd *cobra.Command, args []string) error {
-	if targetFlag == "" {
-		return fmt.Errorf("--target (-t) is required")
+	target, err := resolveTarget(cmd)
+	if err != nil {
+		return err
 	}
 
-	target, err := types.ParseTarget(targetFlag)
+	formatter, err := resultFormatter()
 	if err != nil {
-		return fmt.Errorf("invalid target: %w", err)
+		return err
+	}
+
+	names := allScannerNames
+	if profileFlag != "" {
+		profile := appConfig.GetProfile(profileFlag)
+		if profile == nil {
+			return fmt.Errorf("scan profile %q is not defined in the config file", profileFlag)
+		}
+		names = profile.Scanners
+		if profile.Credential != "" && credentialFlag == "" {
+			if err := useCredential(profile.Credential); err != nil {
+				return err
+			}
+		}
 	}
 
-	formatter, err := output.GetFormatter(outputFlag)
+	names, err = selectScanners(names, categoryFlag, excludeFlag)
+	if err != nil {
+		return err
+	}
+	names, err = dropDisabled(cmd, names)
 	if err != nil {
 		return err
 	}
@@ -51,24 +85,43 @@ func runAll(cmd *cobra.Command, args []string) error {
 	reg.Register(port.New())
 	reg.Register(headers.New())
 	reg.Register(ssl.New())
+	reg.Register(protocols.New())
+	reg.Register(cloud.New())
 	reg.Register(dirs.New())
 	reg.Register(vuln.New())
+	reg.Register(forms.New())
+	reg.Register(csrf.New())
+	reg.Register(tech.New())
+	reg.Register(redirects.New())
+	reg.Register(mixedcontent.New())
+	reg.Register(deserialization.New())
+	reg.Register(custom.New())
 	// API scanners
 	reg.Register(api.New())
 	reg.Register(api.NewAuthScanner())
 	reg.Register(api.NewCORSScanner())
+	reg.Register(api.NewDataExposureScanner())
 	reg.Register(api.NewRateLimitScanner())
+	// Recon scanners
+	reg.Register(subdomain.New())
 
 	runner := scanner.NewRunner(reg)
-	opts := scanner.Options{
-		Concurrency: concurrencyFlag,
-		Timeout:     timeoutFlag,
-		Verbose:     verboseFlag,
+	opts := baseOptions(cmd)
+	names, err = applyOrdering(names, &opts)
+	if err != nil {
+		return err
 	}
+	progress := attachProgress(cmd, runner)
+	opts.ExtraArgs["wordlist"] = resolveWordlist()
+	applyDefaultCredentials(&opts)
+	applyTechData(&opts)
+	dedupeCSRF(names, &opts)
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeoutFlag*100)
+	ctx, cancel := scanContext(timeoutFlag * 100)
 	defer cancel()
 
-	results := runner.RunAll(ctx, allScannerNames, target, opts)
+	results := runner.RunAll(ctx, names, target, opts)
+	progress.Finish()
+	logTimings(cmd, results)
 	return formatter.Format(os.Stdout, results)
 }
@@ -1,6 +1,11 @@
 package cli
 
-import "github.com/spf13/cobra"
+import (
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/spf13/cobra"
+)
+
+var apiPathsFlag string
 
 var apiCmd = &cobra.Command{
 	Use:   "api",
@@ -9,5 +14,13 @@ var apiCmd = &cobra.Command{
 }
 
 func init() {
+	apiCmd.PersistentFlags().StringVar(&apiPathsFlag, "paths", "", `API routes the auth, cors, and ratelimit checks test instead of common paths: a file with one per line, or a comma-separated list, e.g. "/v2/users,POST /v2/orders"`)
 	rootCmd.AddCommand(apiCmd)
 }
+
+// applyAPIPaths passes --paths to the api scanners as their endpoints arg.
+func applyAPIPaths(opts *scanner.Options) {
+	if apiPathsFlag != "" {
+		opts.ExtraArgs["endpoints"] = apiPathsFlag
+	}
+}
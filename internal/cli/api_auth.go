@@ -1,11 +1,8 @@
 package cli
 
 import (
-	"context"
-	"fmt"
 	"os"
 
-	"github.com/buemura/hunter/internal/output"
 	"github.com/buemura/hunter/internal/scanner"
 	"github.com/buemura/hunter/internal/scanner/api"
 	"github.com/buemura/hunter/pkg/types"
@@ -19,21 +16,21 @@ var apiAuthCmd = &cobra.Command{
 	RunE:  runAPIAuthScan,
 }
 
+// apiAuthWriteMethodsFlag sends write method probes at any intensity.
+var apiAuthWriteMethodsFlag bool
+
 func init() {
+	apiAuthCmd.Flags().BoolVar(&apiAuthWriteMethodsFlag, "write-methods", false, "Also probe endpoints with POST, PUT, and PATCH, which could change data (default only at aggressive intensity)")
 	apiCmd.AddCommand(apiAuthCmd)
 }
 
 func runAPIAuthScan(cmd *cobra.Command, args []string) error {
-	if targetFlag == "" {
-		return fmt.Errorf("--target (-t) is required")
-	}
-
-	target, err := types.ParseTarget(targetFlag)
+	target, err := resolveTarget(cmd)
 	if err != nil {
-		return fmt.Errorf("invalid target: %w", err)
+		return err
 	}
 
-	formatter, err := output.GetFormatter(outputFlag)
+	formatter, err := resultFormatter()
 	if err != nil {
 		return err
 	}
@@ -42,19 +39,24 @@ func runAPIAuthScan(cmd *cobra.Command, args []string) error {
 	reg.Register(api.NewAuthScanner())
 
 	runner := scanner.NewRunner(reg)
-	opts := scanner.Options{
-		Concurrency: concurrencyFlag,
-		Timeout:     timeoutFlag,
-		Verbose:     verboseFlag,
+	opts := baseOptions(cmd)
+	applyAPIPaths(&opts)
+	applyDefaultCredentials(&opts)
+	if apiAuthWriteMethodsFlag {
+		setFlagArg(cmd, &opts, "api-auth", "write-methods", "write_methods", true)
 	}
+	progress := attachProgress(cmd, runner)
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeoutFlag*20)
+	ctx, cancel := scanContext(timeoutFlag * 20)
 	defer cancel()
 
 	result, err := runner.RunOne(ctx, "api-auth", target, opts)
+	progress.Finish()
 	if err != nil {
 		return err
 	}
 
-	return formatter.Format(os.Stdout, []types.ScanResult{*result})
+	results := []types.ScanResult{*result}
+	logTimings(cmd, results)
+	return formatter.Format(os.Stdout, results)
 }
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/scanner/api"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var apiDataExposureCmd = &cobra.Command{
+	Use:   "dataexposure",
+	Short: "Detect sensitive data in API responses",
+	Long:  "Samples JSON responses from discovered or given API endpoints for password hashes, tokens and secrets, Social Security numbers, email addresses in bulk, and internal IP addresses, with the values redacted in the evidence.",
+	RunE:  runAPIDataExposure,
+}
+
+func init() {
+	apiCmd.AddCommand(apiDataExposureCmd)
+}
+
+func runAPIDataExposure(cmd *cobra.Command, args []string) error {
+	target, err := resolveTarget(cmd)
+	if err != nil {
+		return err
+	}
+
+	formatter, err := resultFormatter()
+	if err != nil {
+		return err
+	}
+
+	reg := scanner.NewRegistry()
+	reg.Register(api.NewDataExposureScanner())
+
+	runner := scanner.NewRunner(reg)
+	opts := baseOptions(cmd)
+	applyAPIPaths(&opts)
+	progress := attachProgress(cmd, runner)
+
+	ctx, cancel := scanContext(timeoutFlag * 100)
+	defer cancel()
+
+	result, err := runner.RunOne(ctx, "api-dataexposure", target, opts)
+	progress.Finish()
+	if err != nil {
+		return err
+	}
+
+	results := []types.ScanResult{*result}
+	logTimings(cmd, results)
+	return formatter.Format(os.Stdout, results)
+}
@@ -1,11 +1,8 @@
 package cli
 
 import (
-	"context"
-	"fmt"
 	"os"
 
-	"github.com/buemura/hunter/internal/output"
 	"github.com/buemura/hunter/internal/scanner"
 	"github.com/buemura/hunter/internal/scanner/api"
 	"github.com/buemura/hunter/pkg/types"
@@ -24,16 +21,12 @@ func init() {
 }
 
 func runAPIDiscover(cmd *cobra.Command, args []string) error {
-	if targetFlag == "" {
-		return fmt.Errorf("--target (-t) is required")
-	}
-
-	target, err := types.ParseTarget(targetFlag)
+	target, err := resolveTarget(cmd)
 	if err != nil {
-		return fmt.Errorf("invalid target: %w", err)
+		return err
 	}
 
-	formatter, err := output.GetFormatter(outputFlag)
+	formatter, err := resultFormatter()
 	if err != nil {
 		return err
 	}
@@ -42,19 +35,19 @@ func runAPIDiscover(cmd *cobra.Command, args []string) error {
 	reg.Register(api.New())
 
 	runner := scanner.NewRunner(reg)
-	opts := scanner.Options{
-		Concurrency: concurrencyFlag,
-		Timeout:     timeoutFlag,
-		Verbose:     verboseFlag,
-	}
+	opts := baseOptions(cmd)
+	progress := attachProgress(cmd, runner)
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeoutFlag*100)
+	ctx, cancel := scanContext(timeoutFlag * 100)
 	defer cancel()
 
 	result, err := runner.RunOne(ctx, "api-discover", target, opts)
+	progress.Finish()
 	if err != nil {
 		return err
 	}
 
-	return formatter.Format(os.Stdout, []types.ScanResult{*result})
+	results := []types.ScanResult{*result}
+	logTimings(cmd, results)
+	return formatter.Format(os.Stdout, results)
 }
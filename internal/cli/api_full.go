@@ -1,24 +1,20 @@
 package cli
 
 import (
-	"context"
-	"fmt"
 	"os"
 
-	"github.com/buemura/hunter/internal/output"
 	"github.com/buemura/hunter/internal/scanner"
 	"github.com/buemura/hunter/internal/scanner/api"
-	"github.com/buemura/hunter/pkg/types"
 	"github.com/spf13/cobra"
 )
 
 // apiScannerNames lists all API scanner names in execution order.
-var apiScannerNames = []string{"api-discover", "api-auth", "api-cors", "api-ratelimit"}
+var apiScannerNames = []string{"api-discover", "api-auth", "api-cors", "api-ratelimit", "api-dataexposure"}
 
 var apiFullCmd = &cobra.Command{
 	Use:   "full",
 	Short: "Run all API scanners",
-	Long:  "Runs every API scanner (discover, auth, cors, ratelimit) against the target concurrently.",
+	Long:  "Runs every API scanner (discover, auth, cors, ratelimit, dataexposure) against the target concurrently.",
 	RunE:  runAPIFull,
 }
 
@@ -27,16 +23,17 @@ func init() {
 }
 
 func runAPIFull(cmd *cobra.Command, args []string) error {
-	if targetFlag == "" {
-		return fmt.Errorf("--target (-t) is required")
+	target, err := resolveTarget(cmd)
+	if err != nil {
+		return err
 	}
 
-	target, err := types.ParseTarget(targetFlag)
+	formatter, err := resultFormatter()
 	if err != nil {
-		return fmt.Errorf("invalid target: %w", err)
+		return err
 	}
 
-	formatter, err := output.GetFormatter(outputFlag)
+	names, err := dropDisabled(cmd, apiScannerNames)
 	if err != nil {
 		return err
 	}
@@ -45,18 +42,20 @@ func runAPIFull(cmd *cobra.Command, args []string) error {
 	reg.Register(api.New())
 	reg.Register(api.NewAuthScanner())
 	reg.Register(api.NewCORSScanner())
+	reg.Register(api.NewDataExposureScanner())
 	reg.Register(api.NewRateLimitScanner())
 
 	runner := scanner.NewRunner(reg)
-	opts := scanner.Options{
-		Concurrency: concurrencyFlag,
-		Timeout:     timeoutFlag,
-		Verbose:     verboseFlag,
-	}
+	opts := baseOptions(cmd)
+	applyAPIPaths(&opts)
+	applyDefaultCredentials(&opts)
+	progress := attachProgress(cmd, runner)
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeoutFlag*100)
+	ctx, cancel := scanContext(timeoutFlag * 100)
 	defer cancel()
 
-	results := runner.RunAll(ctx, apiScannerNames, target, opts)
+	results := runner.RunAll(ctx, names, target, opts)
+	progress.Finish()
+	logTimings(cmd, results)
 	return formatter.Format(os.Stdout, results)
 }
@@ -1,11 +1,8 @@
 package cli
 
 import (
-	"context"
-	"fmt"
 	"os"
 
-	"github.com/buemura/hunter/internal/output"
 	"github.com/buemura/hunter/internal/scanner"
 	"github.com/buemura/hunter/internal/scanner/api"
 	"github.com/buemura/hunter/pkg/types"
@@ -22,21 +19,17 @@ var apiRateLimitCmd = &cobra.Command{
 }
 
 func init() {
-	apiRateLimitCmd.Flags().IntVar(&requestsFlag, "requests", 50, "number of requests to send")
+	apiRateLimitCmd.Flags().IntVar(&requestsFlag, "requests", 0, "number of requests to send (default: 20, 50, or 200 by --intensity)")
 	apiCmd.AddCommand(apiRateLimitCmd)
 }
 
 func runAPIRateLimit(cmd *cobra.Command, args []string) error {
-	if targetFlag == "" {
-		return fmt.Errorf("--target (-t) is required")
-	}
-
-	target, err := types.ParseTarget(targetFlag)
+	target, err := resolveTarget(cmd)
 	if err != nil {
-		return fmt.Errorf("invalid target: %w", err)
+		return err
 	}
 
-	formatter, err := output.GetFormatter(outputFlag)
+	formatter, err := resultFormatter()
 	if err != nil {
 		return err
 	}
@@ -45,20 +38,23 @@ func runAPIRateLimit(cmd *cobra.Command, args []string) error {
 	reg.Register(api.NewRateLimitScanner())
 
 	runner := scanner.NewRunner(reg)
-	opts := scanner.Options{
-		Concurrency: concurrencyFlag,
-		Timeout:     timeoutFlag,
-		Verbose:     verboseFlag,
-		ExtraArgs:   map[string]interface{}{"requests": requestsFlag},
+	opts := baseOptions(cmd)
+	applyAPIPaths(&opts)
+	progress := attachProgress(cmd, runner)
+	if requestsFlag > 0 {
+		setFlagArg(cmd, &opts, "api-ratelimit", "requests", "requests", requestsFlag)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeoutFlag*100)
+	ctx, cancel := scanContext(timeoutFlag * 100)
 	defer cancel()
 
 	result, err := runner.RunOne(ctx, "api-ratelimit", target, opts)
+	progress.Finish()
 	if err != nil {
 		return err
 	}
 
-	return formatter.Format(os.Stdout, []types.ScanResult{*result})
+	results := []types.ScanResult{*result}
+	logTimings(cmd, results)
+	return formatter.Format(os.Stdout, results)
 }
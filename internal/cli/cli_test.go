@@ -2,18 +2,35 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/buemura/hunter/internal/config"
+	"github.com/buemura/hunter/internal/output"
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/scanner/tech"
+	"github.com/buemura/hunter/internal/vault"
+	"github.com/buemura/hunter/internal/web"
 	"github.com/buemura/hunter/pkg/types"
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 func executeCmd(args ...string) (string, error) {
@@ -135,6 +152,100 @@ func TestScanHeadersDetectsMissingHeaders(t *testing.T) {
 	assert.Contains(t, output, "Missing X-Content-Type-Options header")
 }
 
+func TestLangFlag(t *testing.T) {
+	defer func() {
+		langFlag, locale = "", nil
+		rootCmd.PersistentFlags().Lookup("lang").Changed = false
+	}()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	output, err := executeCmd("scan", "headers", "-t", srv.URL, "-o", "markdown", "--lang", "pt-BR")
+	require.NoError(t, err)
+	assert.Contains(t, output, "## Resumo executivo")
+	assert.Contains(t, output, "Cabeçalho Content-Security-Policy ausente")
+
+	output, err = executeCmd("scan", "headers", "-t", srv.URL, "-o", "json", "--lang", "pt-BR")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Missing Content-Security-Policy header", "JSON stays in English")
+
+	_, err = executeCmd("scan", "headers", "-t", srv.URL, "--lang", "xx")
+	assert.ErrorContains(t, err, "unsupported language")
+}
+
+func TestEngagementFlags(t *testing.T) {
+	defer func() {
+		engagementFlags = types.Engagement{}
+		for _, name := range []string{"client", "engagement-id"} {
+			rootCmd.PersistentFlags().Lookup(name).Changed = false
+		}
+	}()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	output, err := executeCmd("scan", "headers", "-t", srv.URL, "-o", "markdown", "--client", "ACME Corp", "--engagement-id", "ENG-42")
+	require.NoError(t, err)
+	assert.Contains(t, output, "**Client:** ACME Corp")
+	assert.Contains(t, output, "**Engagement ID:** ENG-42")
+}
+
+func TestAuthorizedTargets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".hunter.yaml"), []byte("authorized_targets:\n  - 10.0.0.0/8\n"), 0o600))
+	defer func() {
+		iAmAuthorizedFlag = false
+		rootCmd.PersistentFlags().Lookup("i-am-authorized").Changed = false
+	}()
+
+	_, err := executeCmd("scan", "headers", "-t", srv.URL, "-o", "json")
+	assert.ErrorIs(t, err, config.ErrUnauthorizedTarget)
+	assert.ErrorContains(t, err, "--i-am-authorized")
+
+	output, err := executeCmd("scan", "headers", "-t", srv.URL, "-o", "json", "--i-am-authorized")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Missing Content-Security-Policy header")
+}
+
+func TestReportPassword(t *testing.T) {
+	defer func() {
+		reportPasswordFlag = ""
+		rootCmd.PersistentFlags().Lookup("report-password").Changed = false
+	}()
+	t.Setenv("REPORT_PASSWORD", "s3cret")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	output, err := executeCmd("scan", "headers", "-t", srv.URL, "-o", "json", "--report-password", "env:REPORT_PASSWORD")
+	require.NoError(t, err)
+	assert.True(t, vault.PasswordSealed([]byte(output)))
+	assert.NotContains(t, output, "Content-Security-Policy")
+
+	path := filepath.Join(t.TempDir(), "report.json.enc")
+	require.NoError(t, os.WriteFile(path, []byte(output), 0o600))
+	output, err = executeCmd("decrypt", path, "--report-password", "env:REPORT_PASSWORD")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Missing Content-Security-Policy header")
+
+	_, err = executeCmd("decrypt", path, "--report-password", "wrong")
+	assert.ErrorIs(t, err, vault.ErrDecrypt)
+
+	reportPasswordFlag = ""
+	rootCmd.PersistentFlags().Lookup("report-password").Changed = false
+	_, err = executeCmd("decrypt", path)
+	assert.ErrorContains(t, err, "password-protected")
+}
+
 func TestScanHeadersNoFindingsWhenAllPresent(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Strict-Transport-Security", "max-age=31536000")
@@ -164,6 +275,195 @@ func TestScanHelpListsHeaders(t *testing.T) {
 	assert.Contains(t, output, "headers")
 }
 
+func TestScanFormsDetectsPasswordAutocomplete(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<form action="/login" method="post"><input type="password" name="pass"></form>`)
+	}))
+	defer srv.Close()
+
+	output, err := executeCmd("scan", "forms", "-t", srv.URL, "-o", "table")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Password field allows autocomplete")
+}
+
+func TestScanFormsDetectsMissingCSRFToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<form action="/transfer" method="post"><input name="to"></form>`)
+	}))
+	defer srv.Close()
+
+	output, err := executeCmd("scan", "forms", "-t", srv.URL, "-o", "table")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Form without CSRF protection")
+}
+
+func TestDedupeCSRF(t *testing.T) {
+	opts := scanner.Options{ExtraArgs: map[string]interface{}{}}
+	dedupeCSRF([]string{"headers", "forms"}, &opts)
+	assert.False(t, opts.BoolArg("skip_csrf"), "forms checks tokens when csrf does not run")
+	dedupeCSRF([]string{"forms", "csrf"}, &opts)
+	assert.True(t, opts.BoolArg("skip_csrf"))
+}
+
+func TestScanCSRFDetectsMissingToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<form action="/transfer" method="post"><input name="to"></form>`)
+	}))
+	defer srv.Close()
+
+	output, err := executeCmd("scan", "csrf", "-t", srv.URL, "-o", "table")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Missing CSRF protection")
+}
+
+func TestScanTechFavicons(t *testing.T) {
+	defer func() { faviconsFlag = "" }()
+
+	icon := []byte("icon bytes")
+	favicons := filepath.Join(t.TempDir(), "favicons.json")
+	require.NoError(t, os.WriteFile(favicons, []byte(fmt.Sprintf(`{"%d": "Example CMS"}`, tech.FaviconHash(icon))), 0o644))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/favicon.ico" {
+			w.Header().Set("Content-Type", "image/x-icon")
+			w.Write(icon)
+			return
+		}
+		fmt.Fprint(w, "<html>Home</html>")
+	}))
+	defer srv.Close()
+
+	output, err := executeCmd("scan", "tech", "-t", srv.URL, "-o", "table", "--favicons", favicons)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Technology identified: Example CMS")
+}
+
+func TestScanRedirectsDetectsLoop(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			http.Redirect(w, r, "/sso", http.StatusFound)
+			return
+		}
+		http.Redirect(w, r, "/", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	output, err := executeCmd("scan", "redirects", "-t", srv.URL, "-o", "table")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Redirect loop")
+}
+
+func TestScanMixedContentPlainHTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<script src="http://cdn.example.com/app.js"></script>`)
+	}))
+	defer srv.Close()
+
+	output, err := executeCmd("scan", "mixed-content", "-t", srv.URL, "-o", "json")
+	require.NoError(t, err)
+	var results []types.ScanResult
+	require.NoError(t, json.Unmarshal([]byte(output), &results))
+	assert.Equal(t, "mixed-content", results[0].ScannerName)
+	assert.Empty(t, results[0].Findings, "pages served over plain HTTP are not checked")
+}
+
+func TestScanDeserialization(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "prefs", Value: "TzoxMDoiVXNlclByZWZzIjoxOntzOjU6InRoZW1lIjtzOjQ6ImRhcmsiO30="})
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<form><input type="hidden" name="__VIEWSTATE" value="/wEPDwUKMTIzNDU2Nzg5MGRk"></form>`)
+	}))
+	defer srv.Close()
+
+	output, err := executeCmd("scan", "deserialization", "-t", srv.URL, "-o", "table")
+	require.NoError(t, err)
+	assert.Contains(t, output, "PHP serialized data")
+	assert.Contains(t, output, ".NET ViewState without MAC")
+}
+
+func TestScanCustom(t *testing.T) {
+	defer func() { customDirFlag, customChecksFlag = "", "" }()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/debug/vars" {
+			fmt.Fprint(w, `{"cmdline": ["/srv/app"], "memstats": {}}`)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "debug.yaml"), []byte(`id: debug-vars
+name: Go expvar endpoint exposed
+severity: medium
+request:
+  path: /debug/vars
+match:
+  status: [200]
+  body: ['"memstats"']
+---
+id: debug-pprof
+name: Go pprof endpoint exposed
+request:
+  path: /debug/pprof/
+match:
+  status: [200]
+`), 0o644))
+
+	output, err := executeCmd("scan", "custom", "-t", srv.URL, "-o", "table", "--dir", dir)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Go expvar endpoint exposed")
+	assert.NotContains(t, output, "Go pprof endpoint exposed")
+}
+
+func TestScanProtocols(t *testing.T) {
+	srv := httptest.NewServer(h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}), &http2.Server{}))
+	defer srv.Close()
+
+	output, err := executeCmd("scan", "protocols", "-t", srv.URL, "-o", "table")
+	require.NoError(t, err)
+	assert.Contains(t, output, "h2c upgrade accepted")
+}
+
+func TestScanHeadersAreas(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.Host, "localhost:") {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"status":"ok"}`)
+			return
+		}
+		fmt.Fprint(w, "<h1>Welcome</h1>")
+	}))
+	defer srv.Close()
+	defer func() { headersAreasFlag, headersAreaHostsFlag = false, "" }()
+
+	output, err := executeCmd("scan", "headers", "-t", srv.URL, "-o", "json", "--areas", "--area-hosts", "localhost")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Application area: api")
+	assert.Contains(t, output, `"area": "api"`)
+}
+
+func TestScanCloud(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/metrics" {
+			fmt.Fprint(w, "# HELP kubelet_running_pods Pods running.\n# TYPE kubelet_running_pods gauge\nkubelet_running_pods 4\n")
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	output, err := executeCmd("scan", "cloud", "-t", srv.URL, "-o", "table")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Kubernetes control-plane metrics exposed")
+}
+
 func TestScanVulnMissingTarget(t *testing.T) {
 	targetFlag = ""
 	_, err := executeCmd("scan", "vuln")
@@ -183,6 +483,94 @@ func TestScanVulnDetectsXSS(t *testing.T) {
 	assert.Contains(t, output, "Potential reflected XSS")
 }
 
+func TestScanVulnData(t *testing.T) {
+	defer func() { vulnDataFlag, vulnChecksFlag = "", "" }()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		fmt.Fprint(w, "<html>"+r.PostForm.Get("comment")+"</html>")
+	}))
+	defer srv.Close()
+
+	output, err := executeCmd("scan", "vuln", "-t", srv.URL+"/post", "-o", "json", "--checks", "xss", "--data", "comment=hello")
+	require.NoError(t, err)
+	var results []types.ScanResult
+	require.NoError(t, json.Unmarshal([]byte(output), &results))
+	require.NotEmpty(t, results[0].Findings)
+	assert.Equal(t, "comment", results[0].Findings[0].Metadata["param"])
+	assert.Equal(t, "form", results[0].Findings[0].Metadata["param_in"])
+}
+
+func TestScanVulnBrowserFlag(t *testing.T) {
+	defer func() { browserFlag, vulnChecksFlag = "", "" }()
+
+	// Not HTML, so no browser is ever started.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, "{}")
+	}))
+	defer srv.Close()
+
+	output, err := executeCmd("scan", "vuln", "-t", srv.URL, "-o", "json", "--checks", "dom-xss", "--browser")
+	require.NoError(t, err)
+	assert.Equal(t, "auto", browserFlag, "--browser alone finds a browser on PATH")
+
+	var results []types.ScanResult
+	require.NoError(t, json.Unmarshal([]byte(output), &results))
+	assert.Empty(t, results[0].Findings)
+}
+
+func TestScanDirsBrowserScreenshots(t *testing.T) {
+	defer func() { browserFlag, wordlistFlag, artifactsFlag = "", "", false }()
+
+	// A stand-in for Chrome that writes a fake PNG where --screenshot asks.
+	chrome := filepath.Join(t.TempDir(), "chrome")
+	script := "#!/bin/sh\nfor a in \"$@\"; do case \"$a\" in --screenshot=*) printf PNG > \"${a#--screenshot=}\" ;; esac; done\n"
+	require.NoError(t, os.WriteFile(chrome, []byte(script), 0o755))
+	wordlist := filepath.Join(t.TempDir(), "wordlist.txt")
+	require.NoError(t, os.WriteFile(wordlist, []byte("/nothing-here\n"), 0o644))
+
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	output, err := executeCmd("scan", "dirs", "-t", srv.URL, "-o", "json", "--artifacts", "--wordlist", wordlist, "--browser="+chrome)
+	require.NoError(t, err)
+	var results []types.ScanResult
+	require.NoError(t, json.Unmarshal([]byte(output), &results))
+	require.Len(t, results[0].Findings, 1)
+	assert.Equal(t, "Screenshot of the target's root page", results[0].Findings[0].Title)
+	assert.Equal(t, []byte("PNG"), results[0].Findings[0].Artifacts[0].Screenshot)
+}
+
+func TestScanVulnCrawl(t *testing.T) {
+	defer func() { crawlFlag, vulnChecksFlag = false, "" }()
+
+	// The home page has no parameters; the search page it links to
+	// reflects its query.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/search" {
+			fmt.Fprint(w, "<html>Results for "+r.URL.Query().Get("q")+"</html>")
+			return
+		}
+		fmt.Fprint(w, `<html><a href="/search?q=shoes">Search</a></html>`)
+	}))
+	defer srv.Close()
+
+	output, err := executeCmd("scan", "vuln", "-t", srv.URL, "-o", "json", "--checks", "xss")
+	require.NoError(t, err)
+	var results []types.ScanResult
+	require.NoError(t, json.Unmarshal([]byte(output), &results))
+	assert.Empty(t, results[0].Findings)
+
+	output, err = executeCmd("scan", "vuln", "-t", srv.URL, "-o", "json", "--checks", "xss", "--crawl")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(output), &results))
+	require.NotEmpty(t, results[0].Findings)
+	assert.Equal(t, "q", results[0].Findings[0].Metadata["param"])
+	assert.Contains(t, results[0].Findings[0].Metadata["url"], "/search?")
+}
+
 func TestScanVulnJSONOutput(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -200,6 +588,55 @@ func TestScanVulnJSONOutput(t *testing.T) {
 	assert.Equal(t, "vuln", results[0].ScannerName)
 }
 
+func TestVerifyFinding(t *testing.T) {
+	defer func() { vulnChecksFlag = "" }()
+
+	fixed := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		if fixed {
+			q = "sanitized"
+		}
+		fmt.Fprint(w, "<html>"+q+"</html>")
+	}))
+	defer srv.Close()
+
+	output, err := executeCmd("scan", "vuln", "-t", srv.URL+"?q=test", "-o", "json", "--checks", "xss")
+	require.NoError(t, err)
+	var results []types.ScanResult
+	require.NoError(t, json.Unmarshal([]byte(output), &results))
+	require.NotEmpty(t, results[0].Findings)
+	fingerprint := results[0].Findings[0].Fingerprint
+	require.Len(t, fingerprint, 12)
+
+	path := filepath.Join(t.TempDir(), "results.json")
+	require.NoError(t, os.WriteFile(path, []byte(output), 0o644))
+
+	output, err = executeCmd("verify", path, "--finding", fingerprint[:8])
+	assert.ErrorContains(t, err, "still reproduces")
+	assert.Contains(t, output, "REPRODUCES  "+fingerprint)
+
+	fixed = true
+	output, err = executeCmd("verify", path, "--finding", fingerprint)
+	require.NoError(t, err)
+	assert.Contains(t, output, "FIXED       "+fingerprint)
+
+	_, err = executeCmd("verify", path, "--finding", "zzzz")
+	assert.ErrorContains(t, err, "no finding with fingerprint")
+}
+
+func TestFindByFingerprintDuplicates(t *testing.T) {
+	results := []types.ScanResult{{ScannerName: "vuln", Findings: []types.Finding{{Title: "a"}, {Title: "b"}, {Title: "a"}}}}
+
+	_, _, err := findByFingerprint(results, "")
+	assert.Error(t, err)
+
+	fp := types.Fingerprint("vuln", types.Finding{Title: "a"})
+	_, f, err := findByFingerprint(results, fp)
+	require.NoError(t, err, "identical findings count as one")
+	assert.Equal(t, "a", f.Title)
+}
+
 func TestScanHelpListsVuln(t *testing.T) {
 	output, err := executeCmd("scan", "--help")
 	require.NoError(t, err)
@@ -221,7 +658,7 @@ func TestScanFullRunsAllWebScanners(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	output, err := executeCmd("scan", "full", "-t", srv.URL, "-o", "json")
+	output, err := executeCmdLarge("scan", "full", "-t", srv.URL, "-o", "json")
 	require.NoError(t, err)
 
 	var results []types.ScanResult
@@ -232,7 +669,7 @@ func TestScanFullRunsAllWebScanners(t *testing.T) {
 	for _, r := range results {
 		scannerNames[r.ScannerName] = true
 	}
-	for _, name := range []string{"port", "headers", "ssl", "dirs", "vuln"} {
+	for _, name := range []string{"port", "headers", "ssl", "protocols", "cloud", "dirs", "vuln", "forms", "csrf", "tech", "redirects", "mixed-content", "deserialization", "custom"} {
 		assert.True(t, scannerNames[name], "expected scanner %q in results", name)
 	}
 }
@@ -258,7 +695,7 @@ func TestAPIFullRunsAllAPIScanners(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	output, err := executeCmd("api", "full", "-t", srv.URL, "-o", "json")
+	output, err := executeCmdLarge("api", "full", "-t", srv.URL, "-o", "json")
 	require.NoError(t, err)
 
 	var results []types.ScanResult
@@ -269,11 +706,34 @@ func TestAPIFullRunsAllAPIScanners(t *testing.T) {
 	for _, r := range results {
 		scannerNames[r.ScannerName] = true
 	}
-	for _, name := range []string{"api-discover", "api-auth", "api-cors", "api-ratelimit"} {
+	for _, name := range []string{"api-discover", "api-auth", "api-cors", "api-ratelimit", "api-dataexposure"} {
 		assert.True(t, scannerNames[name], "expected scanner %q in results", name)
 	}
 }
 
+func TestAPIAuthPaths(t *testing.T) {
+	var mu sync.Mutex
+	requested := map[string]bool{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.URL.Path] = true
+		mu.Unlock()
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+	defer func() {
+		apiPathsFlag = ""
+		intensityFlag, intensity = "", ""
+		rootCmd.PersistentFlags().Lookup("intensity").Changed = false
+	}()
+
+	_, err := executeCmd("api", "auth", "-t", srv.URL, "--paths", "/v2/users,/v2/orders", "--intensity", "safe", "-o", "json")
+	require.NoError(t, err)
+	assert.True(t, requested["/v2/users"])
+	assert.True(t, requested["/v2/orders"])
+	assert.False(t, requested["/api/v1"], "common paths are not probed")
+}
+
 func TestAPIHelpListsFull(t *testing.T) {
 	output, err := executeCmd("api", "--help")
 	require.NoError(t, err)
@@ -307,7 +767,7 @@ func TestAllRunsEverything(t *testing.T) {
 	for _, r := range results {
 		scannerNames[r.ScannerName] = true
 	}
-	all := []string{"port", "headers", "ssl", "dirs", "vuln", "api-discover", "api-auth", "api-cors", "api-ratelimit"}
+	all := []string{"port", "headers", "ssl", "dirs", "vuln", "api-discover", "api-auth", "api-cors", "api-ratelimit", "api-dataexposure", "subdomain"}
 	for _, name := range all {
 		assert.True(t, scannerNames[name], "expected scanner %q in results", name)
 	}
@@ -318,3 +778,843 @@ func TestRootHelpListsAll(t *testing.T) {
 	require.NoError(t, err)
 	assert.Contains(t, output, "all")
 }
+
+// --- quiet / verbose ---
+
+// resetVerbosity clears -q/-v between tests, since cobra keeps flag values
+// and their "changed" state across executions of the same command tree.
+func resetVerbosity() {
+	quietFlag, verboseFlag = false, 0
+	for _, name := range []string{"quiet", "verbose"} {
+		rootCmd.PersistentFlags().Lookup(name).Changed = false
+	}
+}
+
+func TestQuietAndVerboseAreMutuallyExclusive(t *testing.T) {
+	defer resetVerbosity()
+
+	_, err := executeCmd("scan", "headers", "-t", "http://127.0.0.1:1", "-q", "-v")
+	assert.Error(t, err)
+}
+
+func TestVerboseLogsScannerTiming(t *testing.T) {
+	defer resetVerbosity()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	output, err := executeCmd("scan", "headers", "-t", srv.URL, "-o", "json", "-v")
+	require.NoError(t, err)
+	assert.Contains(t, output, "[headers] finished in")
+	assert.NotContains(t, output, "[http]")
+}
+
+func TestVeryVerboseLogsRequests(t *testing.T) {
+	defer resetVerbosity()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	output, err := executeCmd("scan", "headers", "-t", srv.URL, "-o", "json", "-vv")
+	require.NoError(t, err)
+	assert.Contains(t, output, "[http] GET "+srv.URL+" → 200")
+}
+
+func TestQuietSuppressesDiagnostics(t *testing.T) {
+	defer resetVerbosity()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	output, err := executeCmd("scan", "headers", "-t", srv.URL, "-o", "json", "-q")
+	require.NoError(t, err)
+
+	var results []types.ScanResult
+	require.NoError(t, json.Unmarshal([]byte(output), &results))
+	require.Len(t, results, 1)
+}
+
+// --- progress ---
+
+func TestProgressBarRendersCombinedProgress(t *testing.T) {
+	var buf bytes.Buffer
+	p := newProgressBar(&buf)
+
+	p.Update("dirs", 50, 100)
+	p.Update("port", 100, 100)
+
+	line := p.line(time.Now())
+	assert.Contains(t, line, "75%")
+	assert.Contains(t, line, "dirs 50/100 · port 100/100")
+	assert.Contains(t, line, "ETA")
+
+	p.Finish()
+	assert.True(t, strings.HasSuffix(buf.String(), "\r\033[K"))
+}
+
+func TestProgressETA(t *testing.T) {
+	assert.Equal(t, "--", eta(time.Second, 0, 10))
+	assert.Equal(t, "0s", eta(time.Second, 10, 10))
+	assert.Equal(t, "3s", eta(time.Second, 25, 100))
+}
+
+func TestAttachProgressDisabledWithoutTTY(t *testing.T) {
+	// Under `go test` stdout is not a terminal.
+	p := attachProgress(rootCmd, scanner.NewRunner(scanner.NewRegistry()))
+	assert.Nil(t, p)
+	p.Finish()
+}
+
+// --- scanner selection ---
+
+func TestSelectScannersByCategory(t *testing.T) {
+	names, err := selectScanners(allScannerNames, []string{"network", "api"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"port", "ssl", "protocols", "cloud", "api-discover", "api-auth", "api-cors", "api-ratelimit", "api-dataexposure"}, names)
+}
+
+func TestSelectScannersExclude(t *testing.T) {
+	names, err := selectScanners(webScannerNames, nil, []string{"port", "dirs"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"headers", "ssl", "protocols", "cloud", "vuln", "forms", "csrf", "tech", "redirects", "mixed-content", "deserialization", "custom"}, names)
+}
+
+func TestSelectScannersErrors(t *testing.T) {
+	_, err := selectScanners(webScannerNames, []string{"cloud"}, nil)
+	assert.ErrorContains(t, err, "unknown category")
+
+	_, err = selectScanners(webScannerNames, nil, []string{"api-cors"})
+	assert.ErrorContains(t, err, "unknown scanner")
+
+	_, err = selectScanners(webScannerNames, []string{"api"}, nil)
+	assert.ErrorContains(t, err, "no scanners left")
+}
+
+func TestApplyOrdering(t *testing.T) {
+	defer func() { orderFlag, onFailureFlag, dependsOnFlag = nil, "", nil }()
+
+	orderFlag, onFailureFlag, dependsOnFlag = []string{"tech", "port"}, "abort-remaining", []string{"vuln:tech"}
+	var opts scanner.Options
+	names, err := applyOrdering([]string{"port", "headers", "vuln", "tech"}, &opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tech", "port", "headers", "vuln"}, names)
+	assert.True(t, opts.Sequential)
+	assert.Equal(t, scanner.FailureAbort, opts.OnFailure)
+	assert.Equal(t, map[string][]string{"vuln": {"tech"}}, opts.DependsOn)
+
+	orderFlag = []string{"api-cors"}
+	_, err = applyOrdering([]string{"port"}, &opts)
+	assert.ErrorContains(t, err, "not part of this scan")
+
+	orderFlag, onFailureFlag = nil, "stop"
+	_, err = applyOrdering([]string{"port"}, &opts)
+	assert.ErrorContains(t, err, "invalid failure policy")
+}
+
+func TestApplyOrdering_Watchdog(t *testing.T) {
+	defer func() { watchdogFlag, watchdogThresholdFlag, watchdogActionFlag = 0, 0.8, "" }()
+
+	watchdogFlag, watchdogActionFlag = 10*time.Second, "pause"
+	var opts scanner.Options
+	_, err := applyOrdering([]string{"port"}, &opts)
+	require.NoError(t, err)
+	assert.Equal(t, scanner.WatchdogSettings{Interval: 10 * time.Second, Threshold: 0.8, Action: scanner.WatchdogPause}, opts.Watchdog)
+
+	watchdogActionFlag = "stop"
+	_, err = applyOrdering([]string{"port"}, &opts)
+	assert.ErrorContains(t, err, "invalid watchdog action")
+
+	watchdogActionFlag, watchdogThresholdFlag = "", 1.5
+	_, err = applyOrdering([]string{"port"}, &opts)
+	assert.ErrorContains(t, err, "--watchdog-threshold")
+}
+
+func TestScanFullExclude(t *testing.T) {
+	defer func() {
+		excludeFlag = nil
+		scanFullCmd.Flags().Lookup("exclude").Changed = false
+	}()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	output, err := executeCmd("scan", "full", "-t", srv.URL, "-o", "json", "--exclude", "port,ssl,protocols,cloud,dirs,vuln,forms,csrf,tech,redirects,mixed-content,deserialization,custom")
+	require.NoError(t, err)
+
+	var results []types.ScanResult
+	require.NoError(t, json.Unmarshal([]byte(output), &results))
+	require.Len(t, results, 1)
+	assert.Equal(t, "headers", results[0].ScannerName)
+}
+
+// --- interactive target prompt ---
+
+func TestResolveTargetPromptsOnTTY(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	oldTTY := stdinIsTerminal
+	stdinIsTerminal = func() bool { return true }
+	defer func() { stdinIsTerminal = oldTTY; targetFlag = "" }()
+
+	cmd := &cobra.Command{}
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+	cmd.SetIn(strings.NewReader("\nhttps://\nexample.com:8080\nn\n"))
+
+	targetFlag = ""
+	target, err := resolveTarget(cmd)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", target.Host)
+	assert.Equal(t, "http", target.Scheme)
+	assert.Contains(t, stderr.String(), "Invalid target")
+	assert.Contains(t, stderr.String(), "Using http://example.com:8080")
+	assert.NoFileExists(t, config.ConfigFilePath())
+}
+
+func TestResolveTargetPromptSavesDefault(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	oldTTY := stdinIsTerminal
+	stdinIsTerminal = func() bool { return true }
+	defer func() { stdinIsTerminal = oldTTY; targetFlag = "" }()
+
+	cmd := &cobra.Command{}
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetIn(strings.NewReader("example.com\ny\n"))
+
+	targetFlag = ""
+	_, err := resolveTarget(cmd)
+	require.NoError(t, err)
+
+	cfg, err := config.LoadFromFile(config.ConfigFilePath())
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", cfg.DefaultTarget)
+}
+
+func TestResolveTargetWithoutTTYRequiresFlag(t *testing.T) {
+	oldTTY := stdinIsTerminal
+	stdinIsTerminal = func() bool { return false }
+	defer func() { stdinIsTerminal = oldTTY }()
+
+	targetFlag = ""
+	_, err := resolveTarget(&cobra.Command{})
+	assert.ErrorContains(t, err, "--target (-t) is required")
+}
+
+func TestResultFormatterQuery(t *testing.T) {
+	defer func() { queryFlag = "" }()
+
+	queryFlag = ".[].scanner_name"
+	f, err := resultFormatter()
+	require.NoError(t, err)
+	assert.IsType(t, &output.QueryFormatter{}, f)
+
+	queryFlag = ".["
+	_, err = resultFormatter()
+	assert.Error(t, err)
+}
+
+func TestResultFormatterArtifacts(t *testing.T) {
+	defer func() { outputFlag, artifactsFlag = "table", false }()
+
+	outputFlag, artifactsFlag = "json", true
+	f, err := resultFormatter()
+	require.NoError(t, err)
+	assert.Equal(t, &output.JSONFormatter{Artifacts: true}, f)
+
+	artifactsFlag = false
+	f, err = resultFormatter()
+	require.NoError(t, err)
+	assert.Equal(t, &output.JSONFormatter{}, f)
+}
+
+func TestRedactFlag(t *testing.T) {
+	defer func() { redactFlag = false }()
+
+	assert.False(t, baseOptions(rootCmd).Redact)
+	redactFlag = true
+	assert.True(t, baseOptions(rootCmd).Redact)
+}
+
+func TestDoctorCheckConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	env := &doctorEnv{ConfigPaths: []string{dir + "/missing.yaml"}}
+	res := checkConfig(context.Background(), env)
+	assert.Equal(t, doctorOK, res.Status)
+	assert.Contains(t, res.Detail, "using defaults")
+	require.NotNil(t, env.Config)
+
+	bad := dir + "/bad.yaml"
+	require.NoError(t, os.WriteFile(bad, []byte("concurrency: [1, 2\n"), 0o644))
+	res = checkConfig(context.Background(), &doctorEnv{ConfigPaths: []string{bad}})
+	assert.Equal(t, doctorFail, res.Status)
+	assert.NotEmpty(t, res.Hint)
+
+	invalid := dir + "/invalid.yaml"
+	require.NoError(t, os.WriteFile(invalid, []byte("output_format: xml\n"), 0o644))
+	res = checkConfig(context.Background(), &doctorEnv{ConfigPaths: []string{invalid}})
+	assert.Equal(t, doctorFail, res.Status)
+	assert.Contains(t, res.Detail, "output_format")
+
+	badTarget := dir + "/bad-target.yaml"
+	require.NoError(t, os.WriteFile(badTarget, []byte("saved_targets:\n  - name: staging\n    target: \"staging:99999\"\n"), 0o644))
+	res = checkConfig(context.Background(), &doctorEnv{ConfigPaths: []string{badTarget}})
+	assert.Equal(t, doctorFail, res.Status)
+	assert.Contains(t, res.Detail, "saved_targets: staging")
+
+	badAllowlist := dir + "/bad-allowlist.yaml"
+	require.NoError(t, os.WriteFile(badAllowlist, []byte("authorized_targets:\n  - https://example.com\n"), 0o644))
+	res = checkConfig(context.Background(), &doctorEnv{ConfigPaths: []string{badAllowlist}})
+	assert.Equal(t, doctorFail, res.Status)
+	assert.Contains(t, res.Detail, "authorized_targets")
+
+	badToken := dir + "/bad-token.yaml"
+	require.NoError(t, os.WriteFile(badToken, []byte("agent_token: env:HUNTER_TEST_UNSET_AGENT_TOKEN\n"), 0o644))
+	res = checkConfig(context.Background(), &doctorEnv{ConfigPaths: []string{badToken}})
+	assert.Equal(t, doctorFail, res.Status)
+	assert.Contains(t, res.Detail, "agent_token")
+
+	badTheme := dir + "/bad-theme.yaml"
+	require.NoError(t, os.WriteFile(badTheme, []byte("tui:\n  theme: solarized\n"), 0o644))
+	res = checkConfig(context.Background(), &doctorEnv{ConfigPaths: []string{badTheme}})
+	assert.Equal(t, doctorFail, res.Status)
+	assert.Contains(t, res.Detail, "unknown theme")
+
+	badKeys := dir + "/bad-keys.yaml"
+	require.NoError(t, os.WriteFile(badKeys, []byte("tui:\n  keybindings:\n    quit: [j]\n"), 0o644))
+	res = checkConfig(context.Background(), &doctorEnv{ConfigPaths: []string{badKeys}})
+	assert.Equal(t, doctorFail, res.Status)
+	assert.Contains(t, res.Detail, "tui.keybindings")
+}
+
+func TestReloadServeConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hunter.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("scan_profiles:\n  - name: quick\n    scanners: [headers]\n"), 0o644))
+
+	initial, err := config.LoadFiles(path)
+	require.NoError(t, err)
+	s := web.NewServer(":0", scanner.NewRegistry(), web.Options{Config: initial})
+
+	var stderr bytes.Buffer
+	serveCmd.SetErr(&stderr)
+	defer serveCmd.SetErr(nil)
+
+	require.NoError(t, os.WriteFile(path, []byte("concurrency: 4\nscan_profiles:\n  - name: quick\n    scanners: [headers, ssl]\n"), 0o644))
+	reloadServeConfig(serveCmd, s, []string{path})
+	assert.Equal(t, 4, s.Config().Concurrency)
+	assert.Equal(t, []string{"headers", "ssl"}, s.Config().GetProfile("quick").Scanners)
+	assert.Contains(t, stderr.String(), "concurrency: 10 -> 4")
+	assert.Contains(t, stderr.String(), "scan_profiles.quick changed")
+
+	stderr.Reset()
+	require.NoError(t, os.WriteFile(path, []byte("concurrency: 8\nseverity_overrides:\n  \"X-Frame\": SEVERE\n"), 0o644))
+	reloadServeConfig(serveCmd, s, []string{path})
+	assert.Contains(t, stderr.String(), "rejected")
+	assert.Equal(t, 4, s.Config().Concurrency, "an invalid config is rejected as a whole")
+	assert.NotNil(t, s.Config().GetProfile("quick"))
+}
+
+func TestValidateCORS(t *testing.T) {
+	detail, _ := validateCORS(config.CORS{AllowedOrigins: []string{"https://dashboard.example.com", "http://localhost:5173/"}, AllowCredentials: true})
+	assert.Empty(t, detail)
+
+	detail, _ = validateCORS(config.CORS{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+	assert.Contains(t, detail, "allow_credentials")
+
+	detail, _ = validateCORS(config.CORS{AllowedOrigins: []string{"dashboard.example.com"}})
+	assert.Contains(t, detail, "is not an origin")
+
+	detail, _ = validateCORS(config.CORS{AllowedOrigins: []string{"https://dashboard.example.com/app"}})
+	assert.Contains(t, detail, "is not an origin")
+}
+
+func TestDoctorCheckConnectivity(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	env := &doctorEnv{Host: "127.0.0.1", Port: port, Timeout: time.Second}
+	assert.Equal(t, doctorOK, checkConnectivity(context.Background(), env).Status)
+	assert.Equal(t, doctorOK, checkDNS(context.Background(), env).Status)
+
+	ln.Close()
+	res := checkConnectivity(context.Background(), env)
+	assert.Equal(t, doctorFail, res.Status)
+	assert.NotEmpty(t, res.Hint)
+}
+
+func TestDoctorCheckProxy(t *testing.T) {
+	orig := proxyForRequest
+	defer func() { proxyForRequest = orig }()
+
+	proxyForRequest = func(*http.Request) (*url.URL, error) { return nil, nil }
+	res := checkProxy(context.Background(), &doctorEnv{Host: "example.com", Timeout: time.Second})
+	assert.Equal(t, doctorOK, res.Status)
+	assert.Equal(t, "no proxy configured", res.Detail)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	proxy := &url.URL{Scheme: "http", Host: ln.Addr().String()}
+	proxyForRequest = func(*http.Request) (*url.URL, error) { return proxy, nil }
+	res = checkProxy(context.Background(), &doctorEnv{Host: "example.com", Timeout: time.Second})
+	assert.Equal(t, doctorOK, res.Status)
+
+	ln.Close()
+	res = checkProxy(context.Background(), &doctorEnv{Host: "example.com", Timeout: time.Second})
+	assert.Equal(t, doctorFail, res.Status)
+	assert.Contains(t, res.Detail, "unreachable")
+}
+
+func TestDoctorCheckRawSocketIsInformational(t *testing.T) {
+	orig := listenRaw
+	defer func() { listenRaw = orig }()
+
+	listenRaw = func() (io.Closer, error) { return nil, fmt.Errorf("operation not permitted") }
+	res := checkRawSocket(context.Background(), &doctorEnv{})
+	assert.Equal(t, doctorOK, res.Status, "port scans do not need raw sockets")
+	assert.Contains(t, res.Detail, "operation not permitted")
+	assert.Contains(t, res.Detail, "no SYN mode")
+}
+
+func TestDoctorCheckWordlist(t *testing.T) {
+	cfg := config.Defaults()
+	res := checkWordlist(context.Background(), &doctorEnv{Config: &cfg})
+	assert.Equal(t, doctorOK, res.Status)
+	assert.Contains(t, res.Detail, "embedded wordlist")
+
+	cfg.WordlistPath = t.TempDir() + "/missing.txt"
+	res = checkWordlist(context.Background(), &doctorEnv{Config: &cfg})
+	assert.Equal(t, doctorFail, res.Status)
+}
+
+func TestDoctorCommandReportsChecks(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	out, err := executeCmd("doctor", "-t", ln.Addr().String())
+	require.NoError(t, err)
+	for _, name := range []string{"config", "dns", "connectivity", "proxy", "raw sockets", "wordlist"} {
+		assert.Contains(t, out, name)
+	}
+	assert.Contains(t, out, "0 failed")
+}
+
+func TestDataUpdateRequiresSource(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	_, err := executeCmd("data", "update")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no update source")
+}
+
+func TestDataListShowsEmbeddedFallback(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	out, err := executeCmd("data", "list")
+	require.NoError(t, err)
+	assert.Contains(t, out, "wordlist")
+	assert.Contains(t, out, "embedded")
+}
+
+func TestRulesList(t *testing.T) {
+	defer func() { rulesScannerFlag = "" }()
+
+	out, err := executeCmd("rules", "list")
+	require.NoError(t, err)
+	assert.Contains(t, out, "HUNTER-HEADERS-001")
+	assert.Contains(t, out, "HUNTER-PORT-001")
+
+	out, err = executeCmd("rules", "list", "--scanner", "ssl")
+	require.NoError(t, err)
+	assert.Contains(t, out, "HUNTER-SSL-001")
+	assert.Contains(t, out, "HUNTER-SCAN-001", "rules any scanner reports are listed")
+	assert.NotContains(t, out, "HUNTER-HEADERS-001")
+}
+
+func TestResolveWordlistPrefersInstalledData(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	defer func() { appConfig = nil }()
+
+	cfg := config.Defaults()
+	appConfig = &cfg
+	assert.Equal(t, "", resolveWordlist())
+
+	path := home + "/.hunter/data/wordlist/wordlist.txt"
+	require.NoError(t, os.MkdirAll(home+"/.hunter/data/wordlist", 0o755))
+	require.NoError(t, os.WriteFile(path, []byte("admin\n"), 0o644))
+	assert.Equal(t, path, resolveWordlist())
+
+	cfg.WordlistPath = "/custom.txt"
+	assert.Equal(t, "/custom.txt", resolveWordlist())
+}
+
+func TestApplyDefaultCredentialsUsesInstalledData(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	defer func() { appConfig = nil }()
+
+	cfg := config.Defaults()
+	appConfig = &cfg
+	opts := scanner.Options{ExtraArgs: map[string]interface{}{}}
+	applyDefaultCredentials(&opts)
+	assert.Empty(t, opts.ExtraArgs, "the embedded credentials are used when none are installed")
+
+	path := home + "/.hunter/data/default-creds/default-creds.json"
+	require.NoError(t, os.MkdirAll(home+"/.hunter/data/default-creds", 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(`[{"username":"admin","password":"admin"}]`), 0o644))
+	applyDefaultCredentials(&opts)
+	assert.Equal(t, path, opts.ExtraArgs["default_credentials"])
+
+	opts = scanner.Options{ExtraArgs: map[string]interface{}{}, ScannerArgs: map[string]map[string]interface{}{"api-auth": {"default_credentials": "/custom.json"}}}
+	applyDefaultCredentials(&opts)
+	assert.Equal(t, "/custom.json", opts.ForScanner("api-auth").StringArg("default_credentials"), "the config's file wins")
+}
+
+func TestApplyTechDataUsesInstalledData(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	defer func() { appConfig = nil }()
+
+	cfg := config.Defaults()
+	appConfig = &cfg
+	opts := scanner.Options{ExtraArgs: map[string]interface{}{}}
+	applyTechData(&opts)
+	assert.Empty(t, opts.ExtraArgs, "the embedded data is used when none is installed")
+
+	path := home + "/.hunter/data/cve-map/cve-map.json"
+	require.NoError(t, os.MkdirAll(home+"/.hunter/data/cve-map", 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(`[]`), 0o644))
+	applyTechData(&opts)
+	assert.Equal(t, path, opts.ExtraArgs["cve_map"])
+	assert.NotContains(t, opts.ExtraArgs, "js_vulns")
+
+	opts = scanner.Options{ExtraArgs: map[string]interface{}{}, ScannerArgs: map[string]map[string]interface{}{"tech": {"cve_map": "/custom.json"}}}
+	applyTechData(&opts)
+	assert.Equal(t, "/custom.json", opts.ForScanner("tech").StringArg("cve_map"), "the config's file wins")
+}
+
+func TestSetFlagArgPrefersConfigOverFlagDefault(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	var ports string
+	cmd.Flags().StringVar(&ports, "ports", "common", "")
+
+	opts := scanner.Options{ScannerArgs: map[string]map[string]interface{}{"port": {"ports": "1-1024"}}}
+	setFlagArg(cmd, &opts, "port", "ports", "ports", ports)
+	assert.Nil(t, opts.ExtraArgs, "config default is kept when the flag is not set")
+
+	require.NoError(t, cmd.Flags().Set("ports", "22"))
+	setFlagArg(cmd, &opts, "port", "ports", "ports", ports)
+	assert.Equal(t, "22", opts.ForScanner("port").ExtraArgs["ports"])
+
+	opts = scanner.Options{}
+	cmd.Flags().Lookup("ports").Changed = false
+	setFlagArg(cmd, &opts, "port", "ports", "ports", "common")
+	assert.Equal(t, "common", opts.ExtraArgs["ports"], "flag default applies without config")
+}
+
+func TestAllProfileUsesCredential(t *testing.T) {
+	var authHeaders []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+	}))
+	defer srv.Close()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("HUNTER_TEST_TOKEN", "tok")
+	cfg := `credentials:
+  api:
+    type: bearer
+    token: env:HUNTER_TEST_TOKEN
+scan_profiles:
+  - name: authed
+    scanners: [headers]
+    credential: api
+`
+	require.NoError(t, os.WriteFile(home+"/.hunter.yaml", []byte(cfg), 0o644))
+	defer func() { profileFlag = ""; authenticator = nil }()
+
+	_, err := executeCmd("all", "--profile", "authed", "-t", srv.URL)
+	require.NoError(t, err)
+	require.NotEmpty(t, authHeaders)
+	assert.Equal(t, "Bearer tok", authHeaders[0])
+
+	_, err = executeCmd("all", "--profile", "missing", "-t", srv.URL)
+	assert.ErrorContains(t, err, "not defined")
+}
+
+func TestSeverityOverridesFromConfig(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	cfg := `severity_overrides:
+  "Missing *": ignore
+`
+	require.NoError(t, os.WriteFile(home+"/.hunter.yaml", []byte(cfg), 0o644))
+	defer func() { severityOverrides = nil }()
+
+	out, err := executeCmd("scan", "headers", "-t", srv.URL, "-o", "json")
+	require.NoError(t, err)
+	assert.NotContains(t, out, "Missing")
+
+	require.NoError(t, os.WriteFile(home+"/.hunter.yaml", []byte("severity_overrides:\n  \"Missing *\": urgent\n"), 0o644))
+	_, err = executeCmd("scan", "headers", "-t", srv.URL)
+	assert.ErrorContains(t, err, "unknown severity")
+}
+
+func TestEnvProdDisablesIntrusiveScanners(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	t.Setenv("HOME", t.TempDir())
+	defer func() {
+		envFlag = ""
+		activeEnv = nil
+		rootCmd.PersistentFlags().Lookup("env").Changed = false
+		excludeFlag = nil
+		scanFullCmd.Flags().Lookup("exclude").Changed = false
+		requestsFlag = 0
+		apiRateLimitCmd.Flags().Lookup("requests").Changed = false
+	}()
+
+	_, err := executeCmd("api", "ratelimit", "-t", srv.URL, "--env", "prod", "--requests", "2")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not allowed in environment \"prod\"")
+
+	out, err := executeCmd("scan", "full", "-t", srv.URL, "--env", "prod", "--exclude", "port,ssl,dirs", "-o", "json")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Skipping vuln")
+	assert.Contains(t, out, `"scanner_name": "headers"`)
+	assert.NotContains(t, out, `"scanner_name": "vuln"`)
+}
+
+func TestEnvUnknown(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	defer func() {
+		envFlag = ""
+		activeEnv = nil
+		rootCmd.PersistentFlags().Lookup("env").Changed = false
+	}()
+
+	_, err := executeCmd("scan", "headers", "-t", "https://example.com", "--env", "qa")
+	assert.ErrorContains(t, err, "unknown environment")
+}
+
+func TestIPVersionFlag(t *testing.T) {
+	defer func() { ipVersionFlag, ipVersion = "", 0 }()
+
+	_, err := executeCmd("scan", "headers", "-t", "http://127.0.0.1:1", "--ip-version", "5")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid IP version")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	output, err := executeCmd("scan", "headers", "-t", srv.URL, "-o", "json", "--ip-version", "4")
+	require.NoError(t, err)
+	assert.Contains(t, output, `"address_family": "ipv4"`)
+}
+
+func TestResolveFlag(t *testing.T) {
+	defer func() { resolverFlag, resolveFlag, resolver = "", nil, nil }()
+
+	_, err := executeCmd("scan", "headers", "-t", "http://127.0.0.1:1", "--resolver", "dns.example.com")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid resolver")
+	resolverFlag = ""
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	_, port, _ := net.SplitHostPort(strings.TrimPrefix(srv.URL, "http://"))
+
+	output, err := executeCmd("scan", "headers", "-t", "http://staging.hunter.test:"+port, "--resolve", "staging.hunter.test:127.0.0.1", "-o", "json")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Missing Content-Security-Policy header")
+	assert.Contains(t, output, `"preflight_addresses": "127.0.0.1"`)
+}
+
+func TestEndpointsFlag(t *testing.T) {
+	defer func() { endpointsFlag, endpoints = "", nil }()
+
+	_, err := executeCmd("api", "discover", "-t", "http://127.0.0.1:1", "--endpoints", filepath.Join(t.TempDir(), "missing.har"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reading endpoints")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete && r.URL.Path == "/v2/orders/7" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "traffic.har")
+	har := `{"log":{"entries":[{"request":{"method":"DELETE","url":"` + srv.URL + `/v2/orders/7","headers":[]}}]}}`
+	require.NoError(t, os.WriteFile(path, []byte(har), 0o644))
+
+	output, err := executeCmd("api", "discover", "-t", srv.URL, "--endpoints", path, "-o", "json")
+	require.NoError(t, err)
+	assert.Contains(t, output, "API endpoint discovered: DELETE /v2/orders/7")
+}
+
+func TestPassiveFlag(t *testing.T) {
+	defer func() { passiveFlag = false }()
+
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer srv.Close()
+
+	output, err := executeCmd("scan", "headers", "-t", srv.URL, "--passive", "-o", "json")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Scanner skipped")
+	assert.Contains(t, output, "passive mode forbids contacting the target")
+	assert.Zero(t, hits, "the target must not be contacted")
+}
+
+func TestIntensityFlag(t *testing.T) {
+	defer func() {
+		intensityFlag, intensity = "", ""
+		rootCmd.PersistentFlags().Lookup("intensity").Changed = false
+	}()
+
+	_, err := executeCmd("scan", "headers", "-t", "http://127.0.0.1:1", "--intensity", "reckless")
+	assert.ErrorContains(t, err, "invalid intensity")
+
+	var count atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count.Add(1)
+	}))
+	defer srv.Close()
+
+	_, err = executeCmd("api", "ratelimit", "-t", srv.URL, "--intensity", "safe", "--no-preflight", "-o", "json")
+	require.NoError(t, err)
+	assert.Equal(t, int32(20), count.Load())
+}
+
+func TestWindowFlag(t *testing.T) {
+	defer func() {
+		windowFlag, scanWindow = "", nil
+		rootCmd.PersistentFlags().Lookup("window").Changed = false
+	}()
+
+	_, err := executeCmd("scan", "headers", "-t", "http://127.0.0.1:1", "--window", "late")
+	assert.ErrorContains(t, err, "invalid window")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	now := time.Now().UTC()
+	from, to := now.Add(-time.Hour), now.Add(time.Hour)
+	window := fmt.Sprintf("%02d:%02d-%02d:%02d UTC", from.Hour(), from.Minute(), to.Hour(), to.Minute())
+
+	output, err := executeCmd("scan", "headers", "-t", srv.URL, "--window", window, "--no-preflight", "-o", "json")
+	require.NoError(t, err)
+	assert.Contains(t, output, `"scanner_name": "headers"`, "within the window the scan runs at once")
+}
+
+func TestMaxDurationFlag(t *testing.T) {
+	defer func() {
+		maxDurationFlag = 0
+		rootCmd.PersistentFlags().Lookup("max-duration").Changed = false
+	}()
+	t.Setenv("HOME", t.TempDir())
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	start := time.Now()
+	output, err := executeCmd("scan", "headers", "-t", srv.URL, "--no-preflight", "--max-duration", "200ms", "-o", "json")
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 4*time.Second)
+	assert.Contains(t, output, `"partial": "true"`)
+}
+
+func TestScanSubdomainSkipsIPTarget(t *testing.T) {
+	output, err := executeCmd("scan", "subdomain", "-t", "http://127.0.0.1:1", "-o", "json")
+	require.NoError(t, err)
+	assert.Contains(t, output, "the target is an IP address")
+}
+
+func TestPortScanDeadline(t *testing.T) {
+	assert.Equal(t, 100*time.Second, portScanDeadline("common", 10, time.Second))
+	assert.Equal(t, 100*time.Second, portScanDeadline("bogus", 10, time.Second))
+	// Every one of 65535 ports timing out at a concurrency of 100, twice over.
+	assert.Equal(t, 1310*time.Second, portScanDeadline("1-65535", 100, time.Second))
+}
+
+// --- import ---
+
+func TestImportNmap(t *testing.T) {
+	defer resetVerbosity()
+	defer func() { importScanFlag, noPreflightFlag = nil, false }()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	path := filepath.Join(t.TempDir(), "nmap.xml")
+	xml := `<nmaprun><host><status state="up"/><address addr="127.0.0.1" addrtype="ipv4"/><ports>` +
+		`<port protocol="tcp" portid="` + port + `"><state state="open"/><service name="http"/></port>` +
+		`</ports></host></nmaprun>`
+	require.NoError(t, os.WriteFile(path, []byte(xml), 0o644))
+
+	output, err := executeCmd("import", "nmap", path, "-o", "json", "-q")
+	require.NoError(t, err)
+	var results []types.ScanResult
+	require.NoError(t, json.Unmarshal([]byte(output), &results))
+	require.Len(t, results, 1)
+	assert.Equal(t, "Open port: "+port+"/http", results[0].Findings[0].Title)
+
+	// The imported target carries its open ports, so a port scan of it
+	// checks just those.
+	output, err = executeCmd("import", "nmap", path, "-o", "json", "-q", "--scan", "port", "--no-preflight")
+	require.NoError(t, err)
+	results = nil
+	require.NoError(t, json.Unmarshal([]byte(output), &results))
+	require.Len(t, results, 2)
+	require.Len(t, results[1].Findings, 1)
+	assert.Equal(t, port, results[1].Findings[0].Metadata["port"])
+
+	_, err = executeCmd("import", "nmap", path, "--scan", "nope")
+	assert.ErrorContains(t, err, `unknown scanner "nope"`)
+}
+
+func TestScaffoldSecurityTxt(t *testing.T) {
+	defer func() {
+		securityTxtFlags = config.SecurityTxt{}
+		securityTxtFileFlag = ""
+	}()
+
+	output, err := executeCmd("scaffold", "security-txt", "--contact", "security@example.com", "--policy", "https://example.com/disclosure")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Contact: mailto:security@example.com\n")
+	assert.Contains(t, output, "Policy: https://example.com/disclosure\n")
+	assert.Contains(t, output, "Expires: ")
+
+	path := filepath.Join(t.TempDir(), ".well-known", "security.txt")
+	_, err = executeCmd("scaffold", "security-txt", "--contact", "https://example.com/report", "--file", path)
+	require.NoError(t, err)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "Contact: https://example.com/report\n")
+
+	_, err = executeCmd("scaffold", "security-txt", "--contact", "http://example.com/report", "--file", "")
+	assert.ErrorContains(t, err, "must be a mailto:, tel: or https: URI")
+}
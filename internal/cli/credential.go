@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/buemura/hunter/internal/auth"
+)
+
+// authenticator authenticates scanner requests when a credential is in use.
+// It is set by useCredential and picked up by baseOptions.
+var authenticator auth.Authenticator
+
+// authHosts are the hosts besides the target's the credential is sent to.
+var authHosts []string
+
+// useCredential resolves the named credential from the config file and makes
+// subsequent scans use it.
+func useCredential(name string) error {
+	if appConfig == nil {
+		return fmt.Errorf("credential %q: no configuration loaded", name)
+	}
+	cred, err := appConfig.GetCredential(name)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeoutFlag*2)
+	defer cancel()
+
+	a, err := auth.New(ctx, cred, &http.Client{Timeout: timeoutFlag})
+	if err != nil {
+		return fmt.Errorf("credential %q: %w", name, err)
+	}
+	authenticator = a
+	authHosts = auth.Hosts(cred)
+	return nil
+}
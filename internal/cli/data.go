@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"text/tabwriter"
+
+	"github.com/buemura/hunter/internal/config"
+	"github.com/buemura/hunter/internal/data"
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dataSourceFlag string
+	dataPinFlag    map[string]string
+)
+
+var dataCmd = &cobra.Command{
+	Use:   "data",
+	Short: "Manage downloadable scanner data",
+	Long: `Manage the wordlist used by the dirs scanner, the default-credential pack
+used by api-auth, and the JavaScript library vulnerability data and CVE
+mapping used by tech. Installed copies live in the data directory (default
+~/.hunter/data); scanners fall back to their embedded copies when a data set
+is not installed.`,
+}
+
+var dataUpdateCmd = &cobra.Command{
+	Use:   "update [data-set...]",
+	Short: "Download the latest (or pinned) data sets",
+	Long: `Downloads data sets from the update source into the data directory. Pass
+data set names to update only those. Versions can be pinned with --pin or the
+data_pins config map; pinned data sets are not moved to newer versions.`,
+	RunE: runDataUpdate,
+}
+
+var dataListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List data sets and their installed versions",
+	RunE:  runDataList,
+}
+
+func init() {
+	dataUpdateCmd.Flags().StringVar(&dataSourceFlag, "source", "", "base URL serving index.json and the data files (default: data_source from config)")
+	dataUpdateCmd.Flags().StringToStringVar(&dataPinFlag, "pin", nil, "pin a data set to a version, e.g. --pin wordlist=2024.06")
+
+	dataCmd.AddCommand(dataUpdateCmd)
+	dataCmd.AddCommand(dataListCmd)
+	rootCmd.AddCommand(dataCmd)
+}
+
+// dataStore returns the store for the configured data directory.
+func dataStore() *data.Store {
+	if appConfig == nil {
+		return data.NewStore("")
+	}
+	return data.NewStore(appConfig.DataDir)
+}
+
+// resolveWordlist picks the wordlist for the dirs scanner: --wordlist, then
+// scanners.dirs.wordlist and wordlist_path from config, then an installed
+// data set. An empty result makes the scanner use its embedded wordlist.
+func resolveWordlist() string {
+	if wordlistFlag != "" {
+		return wordlistFlag
+	}
+	if appConfig != nil {
+		if wl := (scanner.Options{ScannerArgs: appConfig.Scanners}).ForScanner("dirs").StringArg("wordlist"); wl != "" {
+			return wl
+		}
+	}
+	if appConfig != nil && appConfig.WordlistPath != "" {
+		return appConfig.WordlistPath
+	}
+	return dataStore().Path("wordlist")
+}
+
+// applyDefaultCredentials points api-auth at the installed default-creds
+// data set, unless scanners.api-auth.default_credentials names a file.
+func applyDefaultCredentials(opts *scanner.Options) {
+	if opts.ForScanner("api-auth").StringArg("default_credentials") != "" {
+		return
+	}
+	if path := dataStore().Path("default-creds"); path != "" {
+		opts.ExtraArgs["default_credentials"] = path
+	}
+}
+
+// applyTechData points tech at the installed js-vulns and cve-map data
+// sets, unless scanners.tech names files of its own.
+func applyTechData(opts *scanner.Options) {
+	for _, d := range []struct{ name, key string }{{"js-vulns", "js_vulns"}, {"cve-map", "cve_map"}} {
+		if opts.ForScanner("tech").StringArg(d.key) != "" {
+			continue
+		}
+		if path := dataStore().Path(d.name); path != "" {
+			opts.ExtraArgs[d.key] = path
+		}
+	}
+}
+
+func runDataUpdate(cmd *cobra.Command, args []string) error {
+	source := dataSourceFlag
+	pins := map[string]string{}
+	if appConfig != nil {
+		if source == "" {
+			source = appConfig.DataSource
+		}
+		for name, version := range appConfig.DataPins {
+			pins[name] = version
+		}
+	}
+	for name, version := range dataPinFlag {
+		pins[name] = version
+	}
+	if source == "" {
+		return fmt.Errorf("no update source: pass --source or set data_source in %s", config.ConfigFilePath())
+	}
+
+	store := dataStore()
+	statusf(cmd, "Updating data in %s from %s", store.Dir, source)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeoutFlag*100)
+	defer cancel()
+
+	results, err := store.Update(ctx, data.UpdateOptions{
+		Source: source,
+		Names:  args,
+		Pins:   pins,
+		Client: &http.Client{Transport: baseOptions(cmd).Transport},
+	})
+	if err != nil && results == nil {
+		return fmt.Errorf("%w (scanners keep using installed or embedded data)", err)
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	failed := 0
+	for _, r := range results {
+		switch {
+		case r.Err != nil:
+			failed++
+			fmt.Fprintf(w, "%s\tfailed\t%v\n", r.Name, r.Err)
+		case r.Updated:
+			fmt.Fprintf(w, "%s\tupdated\t%s → %s\n", r.Name, versionOrNone(r.From), r.To)
+		default:
+			fmt.Fprintf(w, "%s\tup to date\t%s\n", r.Name, r.To)
+		}
+	}
+	w.Flush()
+
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d data set(s) failed to update; previous copies remain in use", failed)
+	}
+	return nil
+}
+
+func runDataList(cmd *cobra.Command, args []string) error {
+	store := dataStore()
+	manifest, err := store.Manifest()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tVERSION\tSOURCE\tDESCRIPTION")
+	for _, d := range data.Datasets {
+		version, source := "-", "embedded"
+		if store.Path(d.Name) != "" {
+			version, source = manifest[d.Name].Version, store.Path(d.Name)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", d.Name, versionOrNone(version), source, d.Description)
+	}
+	return w.Flush()
+}
+
+func versionOrNone(v string) string {
+	if v == "" {
+		return "none"
+	}
+	return v
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/buemura/hunter/internal/config"
+	"github.com/buemura/hunter/internal/vault"
+	"github.com/spf13/cobra"
+)
+
+var decryptCmd = &cobra.Command{
+	Use:   "decrypt <file>",
+	Short: "Decrypt a password-protected report or an encrypted history entry",
+	Long: `Writes the plaintext of a file Hunter encrypted to stdout: a report written
+with --report-password, opened with the same --report-password, or an entry
+of the scan history, opened with the configured encryption_key. HTML reports
+written with --report-password need no decrypting; they ask for the password
+when opened in a browser.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDecrypt,
+}
+
+func init() {
+	rootCmd.AddCommand(decryptCmd)
+}
+
+func runDecrypt(cmd *cobra.Command, args []string) error {
+	raw, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	if !vault.Sealed(raw) && !vault.PasswordSealed(raw) {
+		return fmt.Errorf("%s is not encrypted by hunter", args[0])
+	}
+	plain, err := openSealed(raw)
+	if err != nil {
+		return fmt.Errorf("decrypting %s: %w", args[0], err)
+	}
+	_, err = cmd.OutOrStdout().Write(plain)
+	return err
+}
+
+// openSealed decrypts raw when it was sealed with --report-password or the
+// history's encryption key, and returns anything else as it is.
+func openSealed(raw []byte) ([]byte, error) {
+	switch {
+	case vault.PasswordSealed(raw):
+		if reportPasswordFlag == "" {
+			return nil, errors.New("the file is password-protected; give its --report-password")
+		}
+		password, err := config.ResolveSecret(reportPasswordFlag)
+		if err != nil {
+			return nil, fmt.Errorf("--report-password: %w", err)
+		}
+		return vault.OpenPassword(password, raw)
+	case vault.Sealed(raw):
+		store, err := historyStore()
+		if err != nil {
+			return nil, err
+		}
+		if store.Key == nil {
+			return nil, errors.New("the file is encrypted with a key; set encryption_key")
+		}
+		return vault.Open(store.Key, raw)
+	}
+	return raw, nil
+}
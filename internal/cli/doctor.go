@@ -0,0 +1,415 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/internal/config"
+	"github.com/buemura/hunter/internal/data"
+	"github.com/buemura/hunter/internal/i18n"
+	"github.com/buemura/hunter/internal/output"
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/scanner/dirs"
+	"github.com/buemura/hunter/internal/tui/styles"
+	"github.com/buemura/hunter/internal/tui/views"
+	"github.com/buemura/hunter/internal/web/jobs"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// doctorProbeHost is contacted for the connectivity checks when no target
+// is given.
+const doctorProbeHost = "example.com"
+
+type doctorStatus int
+
+const (
+	doctorOK doctorStatus = iota
+	doctorWarn
+	doctorFail
+)
+
+func (s doctorStatus) String() string {
+	switch s {
+	case doctorOK:
+		return " OK "
+	case doctorWarn:
+		return "WARN"
+	default:
+		return "FAIL"
+	}
+}
+
+// doctorResult is the outcome of a single environment check. Hint tells the
+// user what to do about a warning or failure.
+type doctorResult struct {
+	Name   string
+	Status doctorStatus
+	Detail string
+	Hint   string
+}
+
+// doctorEnv carries what the checks need to know about the environment.
+type doctorEnv struct {
+	ConfigPaths []string
+	Config      *config.Config
+	Host        string
+	Port        int
+	Timeout     time.Duration
+}
+
+type doctorCheck func(ctx context.Context, env *doctorEnv) doctorResult
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the environment for common problems",
+	Long: `Runs a series of environment checks — config file, DNS resolution, outbound
+connectivity, proxy reachability, raw-socket capability, and wordlists — and
+prints actionable diagnostics. Connectivity is tested against --target when
+given, otherwise against example.com.`,
+	// Skip the root config loading: a broken config file is one of the
+	// things doctor reports on, so it must not prevent doctor from running.
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+	SilenceUsage:      true,
+	RunE:              runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	env := &doctorEnv{
+		ConfigPaths: []string{config.ConfigFilePath(), config.ProjectConfigFile},
+		Host:        doctorProbeHost,
+		Port:        443,
+		Timeout:     timeoutFlag,
+	}
+	if env.Timeout <= 0 {
+		env.Timeout = 5 * time.Second
+	}
+
+	out := cmd.OutOrStdout()
+	results := []doctorResult{checkConfig(cmd.Context(), env)}
+	printDoctorResult(out, results[0])
+
+	// The remaining checks use default_target and wordlist_path from the
+	// config loaded above.
+	if err := env.applyTarget(); err != nil {
+		return err
+	}
+	for _, check := range []doctorCheck{checkDNS, checkConnectivity, checkProxy, checkRawSocket, checkWordlist} {
+		res := check(cmd.Context(), env)
+		printDoctorResult(out, res)
+		results = append(results, res)
+	}
+
+	failed, warned := 0, 0
+	for _, res := range results {
+		switch res.Status {
+		case doctorFail:
+			failed++
+		case doctorWarn:
+			warned++
+		}
+	}
+
+	fmt.Fprintf(out, "\n%d checks passed, %d warnings, %d failed\n", len(results)-failed-warned, warned, failed)
+	if failed > 0 {
+		return fmt.Errorf("doctor found %d problem(s)", failed)
+	}
+	return nil
+}
+
+// applyTarget points the connectivity checks at --target, or the configured
+// default target, when one is set.
+func (env *doctorEnv) applyTarget() error {
+	raw := targetFlag
+	if raw == "" && env.Config != nil {
+		raw = env.Config.DefaultTarget
+	}
+	if raw == "" {
+		return nil
+	}
+
+	target, err := types.ParseTarget(raw)
+	if err != nil {
+		return fmt.Errorf("invalid target: %w", err)
+	}
+	env.Host = target.Host
+	switch {
+	case len(target.Ports) > 0:
+		env.Port = target.Ports[0]
+	case target.Scheme == "http":
+		env.Port = 80
+	}
+	return nil
+}
+
+func printDoctorResult(w io.Writer, r doctorResult) {
+	fmt.Fprintf(w, "[%s] %-14s %s\n", r.Status, r.Name, r.Detail)
+	if r.Hint != "" && r.Status != doctorOK {
+		fmt.Fprintf(w, "       %-14s → %s\n", "", r.Hint)
+	}
+}
+
+func checkConfig(ctx context.Context, env *doctorEnv) doctorResult {
+	res := doctorResult{Name: "config"}
+
+	var found []string
+	for _, path := range env.ConfigPaths {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		cfg, err := config.LoadFromFile(path)
+		if err != nil {
+			res.Status = doctorFail
+			res.Detail = err.Error()
+			res.Hint = fmt.Sprintf("fix the YAML in %s or move it aside", path)
+			return res
+		}
+		if detail, hint := validateConfig(cfg); detail != "" {
+			res.Status = doctorFail
+			res.Detail = fmt.Sprintf("%s: %s", path, detail)
+			res.Hint = hint
+			return res
+		}
+		found = append(found, path)
+	}
+
+	cfg, err := config.LoadFiles(found...)
+	if err != nil {
+		res.Status = doctorFail
+		res.Detail = err.Error()
+		return res
+	}
+	env.Config = cfg
+
+	if len(found) == 0 {
+		res.Detail = "no config file found, using defaults"
+		return res
+	}
+	res.Detail = fmt.Sprintf("%s valid", strings.Join(found, " and "))
+	return res
+}
+
+// validateConfig returns a description of the first invalid setting in cfg,
+// and a hint for fixing it, or empty strings when cfg is valid.
+func validateConfig(cfg *config.Config) (string, string) {
+	if _, err := output.GetFormatter(cfg.OutputFormat); err != nil {
+		return fmt.Sprintf("output_format: %v", err), "set output_format to one of " + strings.Join(output.Formats, ", ")
+	}
+	if _, err := i18n.Load(cfg.Lang); err != nil {
+		return fmt.Sprintf("lang: %v", err), "set lang to one of " + strings.Join(i18n.Tags(), ", ")
+	}
+	if cfg.Concurrency < 1 {
+		return fmt.Sprintf("concurrency must be at least 1, got %d", cfg.Concurrency), "set concurrency to a positive number"
+	}
+	if cfg.Timeout <= 0 {
+		return fmt.Sprintf("timeout must be positive, got %s", cfg.Timeout), `set timeout to a duration such as "5s"`
+	}
+	if _, err := styles.Resolve(cfg.TUI.Theme, cfg.TUI.Colors); err != nil {
+		return fmt.Sprintf("tui: %v", err), "set tui.theme to one of " + strings.Join(styles.ThemeNames(), ", ")
+	}
+	if _, err := views.NewKeyMap(cfg.TUI.Keybindings); err != nil {
+		return fmt.Sprintf("tui.keybindings: %v", err), "fix or remove the tui.keybindings entry; press ? in hunter interactive to list actions"
+	}
+	for _, t := range cfg.SavedTargets {
+		if _, err := types.ParseTarget(t.Target); err != nil {
+			return fmt.Sprintf("saved_targets: %s: %v", t.Name, err), "set target to a host, host:port, or URL"
+		}
+	}
+	if _, err := scanner.ParseWindow(cfg.ScanWindow); err != nil {
+		return fmt.Sprintf("scan_window: %v", err), `set scan_window to a daily window such as "22:00-06:00", optionally followed by a time zone`
+	}
+	if err := config.ValidateAuthorizedTargets(cfg.AuthorizedTargets); err != nil {
+		return fmt.Sprintf("authorized_targets: %v", err), `list targets as IP addresses, CIDR ranges, host names, or "*." and a domain`
+	}
+	if _, err := encryptionKey(cfg); err != nil {
+		return err.Error(), "set encryption_key to a key, or an env: or keyring: reference that holds one"
+	}
+	if cfg.AgentToken != "" {
+		if _, err := config.ResolveSecret(cfg.AgentToken); err != nil {
+			return fmt.Sprintf("agent_token: %v", err), "set agent_token to a token, or an env: or keyring: reference that holds one"
+		}
+	}
+	if detail, hint := validateCORS(cfg.CORS); detail != "" {
+		return detail, hint
+	}
+	if _, err := jobs.ParseSLA(cfg.SLA); err != nil {
+		return err.Error(), "key sla by critical, high, medium, low, or info, with a number of days"
+	}
+	return "", ""
+}
+
+// validateCORS checks the cors section: every allowed origin is * or
+// scheme://host[:port], and credentials are only allowed for named origins.
+func validateCORS(cors config.CORS) (string, string) {
+	for _, o := range cors.AllowedOrigins {
+		if o == "*" {
+			if cors.AllowCredentials {
+				return "cors: allow_credentials cannot be combined with the * origin", "list the origins allowed to send credentials by name"
+			}
+			continue
+		}
+		if u, err := url.Parse(o); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || strings.Trim(u.Path, "/") != "" {
+			return fmt.Sprintf("cors.allowed_origins: %q is not an origin", o), "list origins as scheme://host[:port], e.g. https://dashboard.example.com"
+		}
+	}
+	return "", ""
+}
+
+func checkDNS(ctx context.Context, env *doctorEnv) doctorResult {
+	res := doctorResult{Name: "dns"}
+	if net.ParseIP(env.Host) != nil {
+		res.Detail = fmt.Sprintf("%s is an IP address, nothing to resolve", env.Host)
+		return res
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, env.Timeout)
+	defer cancel()
+
+	addrs, err := net.DefaultResolver.LookupHost(ctx, env.Host)
+	if err != nil {
+		res.Status = doctorFail
+		res.Detail = fmt.Sprintf("cannot resolve %s: %v", env.Host, err)
+		res.Hint = "check your network connection and the resolvers in /etc/resolv.conf"
+		return res
+	}
+	res.Detail = fmt.Sprintf("%s resolves to %s", env.Host, addrs[0])
+	return res
+}
+
+func checkConnectivity(ctx context.Context, env *doctorEnv) doctorResult {
+	res := doctorResult{Name: "connectivity"}
+	addr := net.JoinHostPort(env.Host, strconv.Itoa(env.Port))
+
+	d := net.Dialer{Timeout: env.Timeout}
+	start := time.Now()
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		res.Status = doctorFail
+		res.Detail = fmt.Sprintf("cannot connect to %s: %v", addr, err)
+		res.Hint = "check that outbound connections are allowed by your firewall, or configure HTTPS_PROXY"
+		return res
+	}
+	conn.Close()
+
+	res.Detail = fmt.Sprintf("connected to %s in %s", addr, time.Since(start).Round(time.Millisecond))
+	return res
+}
+
+// proxyForRequest resolves the proxy for a request. Extracted as a variable
+// for testing, since http.ProxyFromEnvironment caches the environment.
+var proxyForRequest = http.ProxyFromEnvironment
+
+func checkProxy(ctx context.Context, env *doctorEnv) doctorResult {
+	res := doctorResult{Name: "proxy"}
+
+	req := &http.Request{URL: &url.URL{Scheme: "https", Host: env.Host}}
+	proxyURL, err := proxyForRequest(req)
+	if err != nil {
+		res.Status = doctorFail
+		res.Detail = fmt.Sprintf("invalid proxy setting: %v", err)
+		res.Hint = "fix the HTTPS_PROXY / HTTP_PROXY environment variables"
+		return res
+	}
+	if proxyURL == nil {
+		res.Detail = "no proxy configured"
+		return res
+	}
+
+	port := proxyURL.Port()
+	if port == "" {
+		port = "80"
+		if proxyURL.Scheme == "https" {
+			port = "443"
+		}
+	}
+	addr := net.JoinHostPort(proxyURL.Hostname(), port)
+
+	d := net.Dialer{Timeout: env.Timeout}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		res.Status = doctorFail
+		res.Detail = fmt.Sprintf("proxy %s is unreachable: %v", proxyURL.Redacted(), err)
+		res.Hint = "start the proxy or unset HTTPS_PROXY / HTTP_PROXY"
+		return res
+	}
+	conn.Close()
+
+	res.Detail = fmt.Sprintf("proxy %s is reachable", proxyURL.Redacted())
+	return res
+}
+
+// listenRaw opens a raw IP socket. Extracted as a variable for testing.
+var listenRaw = func() (io.Closer, error) {
+	return net.ListenPacket("ip4:tcp", "0.0.0.0")
+}
+
+// checkRawSocket reports whether raw sockets, which SYN scanning needs, can
+// be opened. The port scanner has no SYN mode and always uses TCP connect,
+// so the result is informational and never a warning.
+func checkRawSocket(ctx context.Context, env *doctorEnv) doctorResult {
+	res := doctorResult{Name: "raw sockets"}
+
+	conn, err := listenRaw()
+	if err != nil {
+		res.Detail = fmt.Sprintf("unavailable (%v); not needed, as port scans use TCP connect (there is no SYN mode)", err)
+		return res
+	}
+	conn.Close()
+
+	res.Detail = "available; not used yet, as port scans use TCP connect (there is no SYN mode)"
+	return res
+}
+
+func checkWordlist(ctx context.Context, env *doctorEnv) doctorResult {
+	res := doctorResult{Name: "wordlist"}
+
+	path := ""
+	if env.Config != nil {
+		path = env.Config.WordlistPath
+		if path == "" {
+			path = data.NewStore(env.Config.DataDir).Path("wordlist")
+		}
+	}
+
+	// Count the entries without holding the list in memory; it may be huge.
+	wordlist, err := dirs.OpenWordlist(path)
+	words := 0
+	if err == nil {
+		for _, ok := wordlist.Next(); ok; _, ok = wordlist.Next() {
+			words++
+		}
+		err = wordlist.Err()
+		wordlist.Close()
+	}
+	if err != nil {
+		res.Status = doctorFail
+		res.Detail = fmt.Sprintf("cannot read wordlist_path: %v", err)
+		res.Hint = "point wordlist_path at a readable file, or remove it to use the embedded wordlist"
+		return res
+	}
+	if words == 0 {
+		res.Status = doctorWarn
+		res.Detail = fmt.Sprintf("%s contains no entries", path)
+		res.Hint = "add one path per line; lines starting with # are ignored"
+		return res
+	}
+
+	if path == "" {
+		res.Detail = fmt.Sprintf("embedded wordlist, %d entries", words)
+	} else {
+		res.Detail = fmt.Sprintf("%s, %d entries", path, words)
+	}
+	return res
+}
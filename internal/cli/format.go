@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/buemura/hunter/internal/config"
+	"github.com/buemura/hunter/internal/output"
+)
+
+// resultFormatter returns the formatter selected by --query or --output,
+// in the language selected by --lang, and encrypted with
+// --report-password when one is given.
+// A query takes precedence, since it defines its own output shape.
+func resultFormatter() (output.Formatter, error) {
+	f, err := selectedFormatter()
+	if err != nil || reportPasswordFlag == "" {
+		return f, err
+	}
+	password, err := config.ResolveSecret(reportPasswordFlag)
+	if err != nil {
+		return nil, fmt.Errorf("--report-password: %w", err)
+	}
+	return &output.ProtectedFormatter{Formatter: f, Password: password}, nil
+}
+
+func selectedFormatter() (output.Formatter, error) {
+	if queryFlag != "" {
+		return output.NewQueryFormatter(queryFlag)
+	}
+	if outputFlag == "json" {
+		return &output.JSONFormatter{Artifacts: artifactsFlag}, nil
+	}
+	f, err := output.GetFormatter(outputFlag)
+	if err != nil {
+		return nil, err
+	}
+	return output.Localize(f, locale), nil
+}
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/buemura/hunter/internal/importer"
+	"github.com/buemura/hunter/internal/redact"
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/scanner/api"
+	"github.com/buemura/hunter/internal/scanner/cloud"
+	"github.com/buemura/hunter/internal/scanner/csrf"
+	"github.com/buemura/hunter/internal/scanner/custom"
+	"github.com/buemura/hunter/internal/scanner/deserialization"
+	"github.com/buemura/hunter/internal/scanner/dirs"
+	"github.com/buemura/hunter/internal/scanner/forms"
+	"github.com/buemura/hunter/internal/scanner/headers"
+	"github.com/buemura/hunter/internal/scanner/mixedcontent"
+	"github.com/buemura/hunter/internal/scanner/port"
+	"github.com/buemura/hunter/internal/scanner/protocols"
+	"github.com/buemura/hunter/internal/scanner/redirects"
+	"github.com/buemura/hunter/internal/scanner/ssl"
+	"github.com/buemura/hunter/internal/scanner/subdomain"
+	"github.com/buemura/hunter/internal/scanner/tech"
+	"github.com/buemura/hunter/internal/scanner/vuln"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var importScanFlag []string
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Convert results from other tools into Hunter results",
+	Long: `Reads the output of other security tools and prints it as Hunter results, in
+any output format, so existing recon data can be reported alongside Hunter's
+own findings.`,
+}
+
+var importNmapCmd = &cobra.Command{
+	Use:   "nmap <results.xml>",
+	Short: "Import nmap XML output (-oX)",
+	Long: `Converts nmap XML output into port scanner results: one result per host that
+was up, with a finding for each open port and the service nmap identified.
+Each result's target lists the host's open TCP ports, so --scan can run
+Hunter scanners against the imported hosts; a port scan then rechecks just
+those ports.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImportNmap,
+}
+
+func init() {
+	importNmapCmd.Flags().StringSliceVar(&importScanFlag, "scan", nil, "comma-separated scanners to run against each imported host")
+	importCmd.AddCommand(importNmapCmd)
+	rootCmd.AddCommand(importCmd)
+}
+
+func runImportNmap(cmd *cobra.Command, args []string) error {
+	formatter, err := resultFormatter()
+	if err != nil {
+		return err
+	}
+	var scanners []string
+	if len(importScanFlag) > 0 {
+		for _, name := range importScanFlag {
+			if !slices.Contains(allScannerNames, name) {
+				return fmt.Errorf("unknown scanner %q for --scan", name)
+			}
+		}
+		if scanners, err = dropDisabled(cmd, importScanFlag); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("reading nmap results: %w", err)
+	}
+	defer f.Close()
+	results, err := importer.ParseNmap(f)
+	if err != nil {
+		return err
+	}
+
+	ports := 0
+	for i := range results {
+		severityOverrides.Apply(&results[i])
+		if appConfig != nil {
+			for k, v := range appConfig.Engagement.Metadata() {
+				results[i].Metadata[k] = v
+			}
+		}
+		if redactFlag {
+			redact.Result(&results[i])
+		}
+		ports += len(results[i].Findings)
+	}
+	statusf(cmd, "Imported %d open ports on %d hosts from %s", ports, len(results), args[0])
+
+	if len(scanners) > 0 {
+		for _, r := range results {
+			if err := authorizeTarget(r.Target); err != nil {
+				return err
+			}
+		}
+		results = append(results, scanImported(cmd, scanners, results)...)
+	}
+	return formatter.Format(os.Stdout, results)
+}
+
+// scanImported runs the named scanners against the target of each imported
+// result.
+func scanImported(cmd *cobra.Command, names []string, imported []types.ScanResult) []types.ScanResult {
+	reg := scanner.NewRegistry()
+	reg.Register(port.New())
+	reg.Register(headers.New())
+	reg.Register(ssl.New())
+	reg.Register(protocols.New())
+	reg.Register(cloud.New())
+	reg.Register(dirs.New())
+	reg.Register(vuln.New())
+	reg.Register(forms.New())
+	reg.Register(csrf.New())
+	reg.Register(tech.New())
+	reg.Register(redirects.New())
+	reg.Register(mixedcontent.New())
+	reg.Register(deserialization.New())
+	reg.Register(custom.New())
+	reg.Register(api.New())
+	reg.Register(api.NewAuthScanner())
+	reg.Register(api.NewCORSScanner())
+	reg.Register(api.NewDataExposureScanner())
+	reg.Register(api.NewRateLimitScanner())
+	reg.Register(subdomain.New())
+
+	runner := scanner.NewRunner(reg)
+	opts := baseOptions(cmd)
+	progress := attachProgress(cmd, runner)
+	opts.ExtraArgs["wordlist"] = resolveWordlist()
+	applyDefaultCredentials(&opts)
+	applyTechData(&opts)
+
+	var results []types.ScanResult
+	for _, r := range imported {
+		ctx, cancel := scanContext(timeoutFlag * 100)
+		results = append(results, runner.RunAll(ctx, names, r.Target, opts)...)
+		cancel()
+	}
+	progress.Finish()
+	logTimings(cmd, results)
+	return results
+}
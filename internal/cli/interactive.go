@@ -1,14 +1,29 @@
 package cli
 
 import (
+	"fmt"
+
+	"github.com/buemura/hunter/internal/cache"
+	"github.com/buemura/hunter/internal/config"
+	"github.com/buemura/hunter/internal/history"
 	"github.com/buemura/hunter/internal/scanner"
 	"github.com/buemura/hunter/internal/scanner/api"
+	"github.com/buemura/hunter/internal/scanner/cloud"
+	"github.com/buemura/hunter/internal/scanner/csrf"
+	"github.com/buemura/hunter/internal/scanner/custom"
+	"github.com/buemura/hunter/internal/scanner/deserialization"
 	"github.com/buemura/hunter/internal/scanner/dirs"
+	"github.com/buemura/hunter/internal/scanner/forms"
 	"github.com/buemura/hunter/internal/scanner/headers"
+	"github.com/buemura/hunter/internal/scanner/mixedcontent"
 	"github.com/buemura/hunter/internal/scanner/port"
+	"github.com/buemura/hunter/internal/scanner/protocols"
+	"github.com/buemura/hunter/internal/scanner/redirects"
 	"github.com/buemura/hunter/internal/scanner/ssl"
+	"github.com/buemura/hunter/internal/scanner/tech"
 	"github.com/buemura/hunter/internal/scanner/vuln"
 	"github.com/buemura/hunter/internal/tui"
+	"github.com/buemura/hunter/internal/vault"
 	"github.com/spf13/cobra"
 )
 
@@ -30,12 +45,71 @@ func runInteractive(cmd *cobra.Command, args []string) error {
 	reg.Register(port.New())
 	reg.Register(headers.New())
 	reg.Register(ssl.New())
+	reg.Register(protocols.New())
+	reg.Register(cloud.New())
 	reg.Register(vuln.New())
+	reg.Register(forms.New())
+	reg.Register(csrf.New())
+	reg.Register(tech.New())
+	reg.Register(redirects.New())
+	reg.Register(mixedcontent.New())
+	reg.Register(deserialization.New())
+	reg.Register(custom.New())
 	reg.Register(dirs.New())
 	reg.Register(api.New())
 	reg.Register(api.NewAuthScanner())
 	reg.Register(api.NewCORSScanner())
+	reg.Register(api.NewDataExposureScanner())
 	reg.Register(api.NewRateLimitScanner())
 
-	return tui.Run(reg)
+	store, err := historyStore()
+	if err != nil {
+		return err
+	}
+	return tui.Run(reg, store, appConfig, authorizeTarget)
+}
+
+// historyStore returns the default scan history, encrypted with the
+// configured encryption_key when there is one.
+func historyStore() (*history.Store, error) {
+	store := history.NewStore("")
+	if appConfig == nil {
+		return store, nil
+	}
+	var err error
+	if store.Key, err = encryptionKey(appConfig); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// resultCache returns the scanner results cache of the config's cache
+// section, encrypted like the history, or nil when it is not enabled.
+func resultCache() (*cache.Store, error) {
+	if appConfig == nil || !appConfig.Cache.Enabled {
+		return nil, nil
+	}
+	store := cache.NewStore(appConfig.Cache.Dir, appConfig.Cache.TTL, version)
+	var err error
+	if store.Key, err = encryptionKey(appConfig); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// encryptionKey derives the key cfg's encryption_key refers to, or returns
+// nil when none is set.
+func encryptionKey(cfg *config.Config) (vault.Key, error) {
+	if cfg.EncryptionKey == "" {
+		return nil, nil
+	}
+	secret, err := config.ResolveSecret(cfg.EncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("encryption_key: %w", err)
+	}
+	key, err := vault.DeriveKey(secret)
+	if err != nil {
+		return nil, fmt.Errorf("encryption_key: %w", err)
+	}
+	return key, nil
 }
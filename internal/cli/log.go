@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// Verbosity levels selected with -v / -vv.
+const (
+	verbosityTiming   = 1 // per-scanner timing
+	verbosityRequests = 2 // every HTTP request sent by the scanners
+)
+
+// logf writes a diagnostic line to stderr if the current verbosity is at
+// least level. Diagnostics never go to stdout so piped output stays clean.
+func logf(cmd *cobra.Command, level int, format string, args ...interface{}) {
+	if quietFlag || verboseFlag < level {
+		return
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), format+"\n", args...)
+}
+
+// statusf writes a status or banner line to stderr unless --quiet is set.
+func statusf(cmd *cobra.Command, format string, args ...interface{}) {
+	if quietFlag {
+		return
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), format+"\n", args...)
+}
+
+// baseOptions returns the scanner options shared by every scan command,
+// wiring up request logging when -vv is given and the --env policy.
+func baseOptions(cmd *cobra.Command) scanner.Options {
+	opts := scanner.Options{
+		Concurrency: concurrencyFlag,
+		Timeout:     timeoutFlag,
+		Verbose:     verboseFlag > 0,
+		Overrides:   severityOverrides,
+		ExtraArgs:   map[string]interface{}{},
+	}
+	if appConfig != nil {
+		opts.ScannerArgs = appConfig.Scanners
+		opts.Engagement = appConfig.Engagement
+	}
+	opts.Authorize = authorizeTarget
+	if authenticator != nil {
+		opts.Authenticate = authenticator
+		opts.AuthHosts = authHosts
+	}
+	if !noPreflightFlag && !passiveFlag {
+		opts.Preflight = scanner.NewPreflight()
+	}
+	if crawlFlag && !passiveFlag {
+		opts.Crawler = scanner.NewCrawler(crawlDepthFlag, crawlPagesFlag)
+	}
+	if browserFlag != "" {
+		// Every scanner using a browser reads the same argument, so the
+		// flag goes to all of them.
+		opts.ExtraArgs["browser"] = browserFlag
+	}
+	opts.Passive = passiveFlag
+	opts.Redact = redactFlag
+	// Time the scan waits outside its window does not count towards the
+	// deadline of scanContext.
+	scanGate = nil
+	if scanWindow != nil {
+		opts.Window = scanWindow
+		opts.Gate = scanner.NewGate()
+		scanGate = opts.Gate
+		if now := time.Now(); !scanWindow.Open(now) {
+			statusf(cmd, "Outside the scan window %s; waiting until it opens at %s", scanWindow, scanWindow.Next(now).Format(time.RFC3339))
+		}
+	}
+	opts.Intensity = intensity
+	if maxDurationFlag > 0 {
+		opts.MaxDuration = maxDurationFlag
+		// Without a usable encryption key, an encrypted history yields no
+		// durations, and the slices are even.
+		if store, err := historyStore(); err == nil {
+			opts.Durations = store.Durations()
+		}
+	}
+
+	opts.IPVersion = ipVersion
+	opts.Resolver = resolver
+	opts.Endpoints = endpoints
+	opts.Transport = scanner.BaseTransport(opts)
+
+	switch store, err := resultCache(); {
+	case err != nil:
+		statusf(cmd, "Not caching results: %v", err)
+	case store != nil:
+		opts.Cache = store
+	}
+
+	if !quietFlag && verboseFlag >= verbosityRequests {
+		w := cmd.ErrOrStderr()
+		opts.Transport = scanner.NewLoggingTransport(opts.Transport, func(format string, args ...interface{}) {
+			fmt.Fprintf(w, "[http] "+format+"\n", args...)
+		})
+	}
+	// Passive sources are third parties, not the target, so the target's
+	// rate limit does not apply to them.
+	opts.Sources = passiveSources(cmd, opts.Transport)
+	if activeEnv != nil {
+		opts.Disabled = disabledScanners(*activeEnv)
+		if activeEnv.RateLimit > 0 {
+			opts.Transport = scanner.NewRateLimitTransport(opts.Transport, activeEnv.RateLimit)
+		}
+	}
+	// Scanners that fetch the same resources share one response, above the
+	// logging and rate limiting so only requests actually sent count.
+	opts.Transport = scanner.NewCacheTransport(opts.Transport)
+
+	return opts
+}
+
+// scanGate is the gate of the options baseOptions last returned, if the scan
+// has a window, or nil.
+var scanGate *scanner.Gate
+
+// scanContext returns the context a scan command runs in: bounded by
+// deadline, or with --max-duration by that budget instead. The budget gets
+// one more timeout of grace, so scanners stopped at the end of their slice
+// can still report what they found. Time spent outside the --window does
+// not count.
+func scanContext(deadline time.Duration) (context.Context, context.CancelFunc) {
+	if maxDurationFlag > 0 {
+		deadline = maxDurationFlag + timeoutFlag
+	}
+	return scanGate.WithTimeout(context.Background(), deadline)
+}
+
+// setFlagArg stores a flag value in opts.ExtraArgs under key. A flag left at
+// its default does not override a value from the scanners section of the
+// config file; only an explicitly set flag does.
+func setFlagArg(cmd *cobra.Command, opts *scanner.Options, scannerName, flagName, key string, value interface{}) {
+	if !cmd.Flags().Changed(flagName) {
+		if _, configured := opts.ForScanner(scannerName).ExtraArgs[key]; configured {
+			return
+		}
+	}
+	if opts.ExtraArgs == nil {
+		opts.ExtraArgs = map[string]interface{}{}
+	}
+	opts.ExtraArgs[key] = value
+}
+
+// logTimings reports how long each scanner took at -v and above.
+func logTimings(cmd *cobra.Command, results []types.ScanResult) {
+	for _, r := range results {
+		logf(cmd, verbosityTiming, "%s", timingLine(r))
+	}
+}
+
+// timingLine summarises a single scanner run for verbose output.
+func timingLine(r types.ScanResult) string {
+	status := fmt.Sprintf("%d findings", len(r.Findings))
+	if r.Error != "" {
+		status = "error: " + r.Error
+	}
+	if r.StartedAt.IsZero() || r.CompletedAt.IsZero() {
+		return fmt.Sprintf("[%s] %s", r.ScannerName, status)
+	}
+	elapsed := r.CompletedAt.Sub(r.StartedAt).Round(time.Millisecond)
+	return fmt.Sprintf("[%s] finished in %s — %s", r.ScannerName, elapsed, status)
+}
@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"net/http"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/scanner/passive"
+	"github.com/spf13/cobra"
+)
+
+// passiveSources returns the passive data sources recon scanners query over
+// transport: crt.sh and the passive_dns providers from the config file.
+// Providers whose secrets cannot be read are skipped with a warning.
+func passiveSources(cmd *cobra.Command, transport http.RoundTripper) []scanner.PassiveSource {
+	sources := []scanner.PassiveSource{passive.NewCrtSh(transport)}
+	if appConfig == nil {
+		return sources
+	}
+	for _, provider := range appConfig.PassiveDNS {
+		resolved, err := provider.Resolve()
+		if err != nil {
+			statusf(cmd, "Skipping %v", err)
+			continue
+		}
+		sources = append(sources, &passive.DNS{
+			Label:     resolved.Name,
+			URL:       resolved.URL,
+			Headers:   resolved.Headers,
+			Transport: transport,
+		})
+	}
+	return sources
+}
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+const (
+	progressBarWidth    = 24
+	progressRenderEvery = 100 * time.Millisecond
+)
+
+// progressBar renders live scanner progress on a single stderr line, with a
+// combined completion bar and an overall ETA.
+type progressBar struct {
+	mu       sync.Mutex
+	w        io.Writer
+	start    time.Time
+	rendered time.Time
+	order    []string
+	done     map[string]int
+	total    map[string]int
+}
+
+// newProgressBar creates a progress bar that writes to w.
+func newProgressBar(w io.Writer) *progressBar {
+	return &progressBar{
+		w:     w,
+		start: time.Now(),
+		done:  make(map[string]int),
+		total: make(map[string]int),
+	}
+}
+
+// attachProgress subscribes a progress bar to runner's scans when progress
+// output is appropriate: not in --quiet mode and only when stdout is a
+// terminal. It returns nil otherwise; Finish is safe to call on a nil bar.
+// At -v it also prints findings that scanners stream as they make them.
+func attachProgress(cmd *cobra.Command, runner *scanner.Runner) *progressBar {
+	if quietFlag {
+		return nil
+	}
+	var p *progressBar
+	if isTerminal(os.Stdout) {
+		p = newProgressBar(cmd.ErrOrStderr())
+		runner.Use(scanner.Hooks{OnProgress: p.Update})
+	}
+	if verboseFlag >= verbosityTiming {
+		w := cmd.ErrOrStderr()
+		runner.Use(scanner.Hooks{OnFound: func(name string, f types.Finding) {
+			p.printAbove(w, fmt.Sprintf("[%s] found: %s", name, f.Title))
+		}})
+	}
+	return p
+}
+
+// printAbove writes line to w, above the progress line if there is one. A
+// nil bar just writes the line.
+func (p *progressBar) printAbove(w io.Writer, line string) {
+	if p == nil {
+		fmt.Fprintln(w, line)
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fmt.Fprintf(p.w, "\r\033[K%s\n", line)
+	if len(p.order) > 0 {
+		fmt.Fprint(p.w, p.line(time.Now()))
+	}
+}
+
+// Update records progress for a scanner and re-renders the bar, throttled
+// so fast scanners do not flood the terminal.
+func (p *progressBar) Update(name string, done, total int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, seen := p.total[name]; !seen {
+		p.order = append(p.order, name)
+	}
+	if done > p.done[name] {
+		p.done[name] = done
+	}
+	p.total[name] = total
+
+	now := time.Now()
+	if now.Sub(p.rendered) < progressRenderEvery && done < total {
+		return
+	}
+	p.rendered = now
+	fmt.Fprintf(p.w, "\r\033[K%s", p.line(now))
+}
+
+// Finish clears the progress line so subsequent output starts clean.
+func (p *progressBar) Finish() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.order) > 0 {
+		fmt.Fprint(p.w, "\r\033[K")
+	}
+}
+
+// line formats the current progress state. Callers must hold p.mu.
+func (p *progressBar) line(now time.Time) string {
+	var done, total int
+	parts := make([]string, 0, len(p.order))
+	for _, name := range p.order {
+		done += p.done[name]
+		total += p.total[name]
+		parts = append(parts, fmt.Sprintf("%s %d/%d", name, p.done[name], p.total[name]))
+	}
+
+	pct := 0.0
+	if total > 0 {
+		pct = float64(done) / float64(total)
+	}
+	filled := int(pct * progressBarWidth)
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+
+	return fmt.Sprintf("[%s] %3.0f%%  %s  ETA %s", bar, pct*100, strings.Join(parts, " · "), eta(now.Sub(p.start), done, total))
+}
+
+// eta estimates the time remaining from the elapsed time and completion ratio.
+func eta(elapsed time.Duration, done, total int) string {
+	if done == 0 || total == 0 {
+		return "--"
+	}
+	if done >= total {
+		return "0s"
+	}
+	remaining := time.Duration(float64(elapsed) * float64(total-done) / float64(done))
+	return remaining.Round(time.Second).String()
+}
+
+// isTerminal reports whether f is attached to a character device (a TTY).
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
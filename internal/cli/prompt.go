@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/buemura/hunter/internal/config"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// stdinIsTerminal reports whether interactive prompts are possible.
+// Extracted as a variable for testing.
+var stdinIsTerminal = func() bool { return isTerminal(os.Stdin) }
+
+// resolveTarget returns the target from --target, the config default, or —
+// when neither is set and stdin is a terminal — an interactive prompt.
+func resolveTarget(cmd *cobra.Command) (types.Target, error) {
+	if targetFlag == "" {
+		if !stdinIsTerminal() {
+			return types.Target{}, fmt.Errorf("--target (-t) is required")
+		}
+		raw, err := promptTarget(cmd)
+		if err != nil {
+			return types.Target{}, err
+		}
+		targetFlag = raw
+	}
+
+	target, err := types.ParseTarget(targetFlag)
+	if err != nil {
+		return types.Target{}, fmt.Errorf("invalid target: %w", err)
+	}
+	if err := authorizeTarget(target); err != nil {
+		return types.Target{}, err
+	}
+	return target, nil
+}
+
+// authorizeTarget refuses targets outside the config file's
+// authorized_targets, unless --i-am-authorized is given.
+func authorizeTarget(target types.Target) error {
+	if iAmAuthorizedFlag {
+		return nil
+	}
+	if err := appConfig.AuthorizeTarget(target); err != nil {
+		return fmt.Errorf("%w; add it to authorized_targets, or pass --i-am-authorized if you have permission to test it", err)
+	}
+	return nil
+}
+
+// promptTarget asks for a target until a valid one is entered, then offers to
+// save it as default_target in the config file.
+func promptTarget(cmd *cobra.Command) (string, error) {
+	in := bufio.NewReader(cmd.InOrStdin())
+	out := cmd.ErrOrStderr()
+
+	var raw string
+	for {
+		fmt.Fprint(out, "Target URL or host: ")
+		line, err := readLine(in)
+		if err != nil {
+			return "", fmt.Errorf("--target (-t) is required")
+		}
+		if line == "" {
+			continue
+		}
+
+		raw = types.InferScheme(line)
+		if _, err := types.ParseTarget(raw); err != nil {
+			fmt.Fprintf(out, "Invalid target: %v\n", err)
+			continue
+		}
+		if raw != line {
+			fmt.Fprintf(out, "Using %s\n", raw)
+		}
+		break
+	}
+
+	path := config.ConfigFilePath()
+	fmt.Fprintf(out, "Save %s as default_target in %s? [y/N]: ", raw, path)
+	answer, err := readLine(in)
+	if err == nil && (strings.EqualFold(answer, "y") || strings.EqualFold(answer, "yes")) {
+		if err := config.SaveDefaultTarget(path, raw); err != nil {
+			fmt.Fprintf(out, "Could not save default target: %v\n", err)
+		}
+	}
+
+	return raw, nil
+}
+
+// readLine reads one trimmed line. A final line without a newline is accepted.
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
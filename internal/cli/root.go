@@ -5,22 +5,84 @@ import (
 	"time"
 
 	"github.com/buemura/hunter/internal/config"
+	"github.com/buemura/hunter/internal/crawl"
+	"github.com/buemura/hunter/internal/i18n"
+	"github.com/buemura/hunter/internal/importer"
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
 	"github.com/spf13/cobra"
 )
 
 var version = "dev"
 
 var (
-	targetFlag      string
-	outputFlag      string
-	verboseFlag     bool
-	concurrencyFlag int
-	timeoutFlag     time.Duration
+	targetFlag         string
+	outputFlag         string
+	queryFlag          string
+	artifactsFlag      bool
+	credentialFlag     string
+	envFlag            string
+	quietFlag          bool
+	verboseFlag        int
+	concurrencyFlag    int
+	timeoutFlag        time.Duration
+	maxDurationFlag    time.Duration
+	intensityFlag      string
+	noPreflightFlag    bool
+	passiveFlag        bool
+	ipVersionFlag      string
+	resolverFlag       string
+	resolveFlag        []string
+	endpointsFlag      string
+	crawlFlag          bool
+	crawlDepthFlag     int
+	crawlPagesFlag     int
+	browserFlag        string
+	langFlag           string
+	redactFlag         bool
+	reportPasswordFlag string
+	iAmAuthorizedFlag  bool
+	cacheFlag          bool
+	noCacheFlag        bool
+	windowFlag         string
 )
 
+// engagementFlags holds --client, --engagement-id, --tester,
+// --authorization-ref, and --notes; config.ApplyFlags copies the ones given
+// over the config file's engagement section.
+var engagementFlags types.Engagement
+
 // appConfig holds the loaded configuration, available after PersistentPreRunE.
 var appConfig *config.Config
 
+// activeEnv is the environment selected with --env, or nil.
+var activeEnv *config.Environment
+
+// intensity is the parsed --intensity, from the flag, environment, or
+// config file.
+var intensity scanner.Intensity
+
+// scanWindow is the parsed --window, from the flag, environment, or config
+// file, or nil if scans may run at any time.
+var scanWindow *scanner.Window
+
+// ipVersion is the parsed --ip-version: 4, 6, or 0 for either.
+var ipVersion int
+
+// resolver is built from --resolver and --resolve, or nil if neither is
+// given.
+var resolver *scanner.Resolver
+
+// endpoints is the API scope loaded from --endpoints, or nil.
+var endpoints []types.Endpoint
+
+// locale is the language of reports selected with --lang or lang in the
+// config file, or nil for English.
+var locale *i18n.Locale
+
+// severityOverrides holds the parsed severity_overrides from appConfig.
+var severityOverrides scanner.SeverityOverrides
+
 var rootCmd = &cobra.Command{
 	Use:   "hunter",
 	Short: "Hunter — CLI pentesting tool for developers",
@@ -35,14 +97,65 @@ web applications and APIs.`,
 
 		config.ApplyFlags(cfg, cmd)
 
+		activeEnv = nil
+		if envFlag != "" {
+			env, err := cfg.Environment(envFlag)
+			if err != nil {
+				return err
+			}
+			config.ApplyEnvironment(cfg, env, cmd)
+			activeEnv = &env
+		}
+
 		// Sync config values back to flag variables so all existing commands
 		// pick up config-file and env-var defaults transparently.
 		targetFlag = cfg.DefaultTarget
 		outputFlag = cfg.OutputFormat
 		concurrencyFlag = cfg.Concurrency
 		timeoutFlag = cfg.Timeout
+		intensityFlag = cfg.Intensity
 
 		appConfig = cfg
+
+		overrides, err := scanner.ParseSeverityOverrides(cfg.SeverityOverrides)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		severityOverrides = overrides
+
+		if locale, err = i18n.Load(cfg.Lang); err != nil {
+			return err
+		}
+		if intensity, err = scanner.ParseIntensity(intensityFlag); err != nil {
+			return err
+		}
+		if ipVersion, err = scanner.ParseIPVersion(ipVersionFlag); err != nil {
+			return err
+		}
+		if scanWindow, err = scanner.ParseWindow(cfg.ScanWindow); err != nil {
+			return err
+		}
+
+		resolver = nil
+		if resolverFlag != "" || len(resolveFlag) > 0 {
+			if resolver, err = scanner.NewResolver(resolverFlag, resolveFlag); err != nil {
+				return err
+			}
+		}
+
+		endpoints = nil
+		if endpointsFlag != "" {
+			if endpoints, err = importer.LoadEndpoints(endpointsFlag); err != nil {
+				return err
+			}
+		}
+
+		authenticator, authHosts = nil, nil
+		if credentialFlag != "" {
+			if err := useCredential(credentialFlag); err != nil {
+				return err
+			}
+		}
 		return nil
 	},
 }
@@ -54,10 +167,42 @@ func Execute() error {
 
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&targetFlag, "target", "t", "", "target host, IP, or URL")
-	rootCmd.PersistentFlags().StringVarP(&outputFlag, "output", "o", "table", "output format: table, json, markdown, html")
-	rootCmd.PersistentFlags().BoolVarP(&verboseFlag, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().StringVarP(&outputFlag, "output", "o", "table", "output format: table, json, markdown, html, csv, sarif, zap, burp")
+	rootCmd.PersistentFlags().StringVar(&queryFlag, "query", "", "jq-like expression to extract values from the JSON results (overrides --output)")
+	rootCmd.PersistentFlags().StringVar(&langFlag, "lang", "", "language of table, markdown, and html reports, e.g. pt-BR (default: English)")
+	rootCmd.PersistentFlags().BoolVar(&artifactsFlag, "artifacts", false, "include the raw HTTP requests and responses behind findings in JSON output")
+	rootCmd.PersistentFlags().StringVar(&reportPasswordFlag, "report-password", "", "encrypt the report with a password: literal, env:NAME, or keyring:service/account; HTML reports decrypt in the browser, others with hunter decrypt")
+	rootCmd.PersistentFlags().BoolVar(&redactFlag, "redact", false, "mask credentials, tokens, cookies, and IP addresses in evidence and artifacts, for reports shared outside the team")
+	rootCmd.PersistentFlags().StringVar(&credentialFlag, "credential", "", "named credential from the config file to authenticate scans with")
+	rootCmd.PersistentFlags().StringVar(&envFlag, "env", "", "environment tier whose defaults to apply: prod, staging, dev, or one from the config file")
+	rootCmd.PersistentFlags().BoolVarP(&quietFlag, "quiet", "q", false, "suppress progress and banners, print only the final output")
+	rootCmd.PersistentFlags().CountVarP(&verboseFlag, "verbose", "v", "verbose output to stderr (-v scanner timing, -vv every request)")
+	rootCmd.MarkFlagsMutuallyExclusive("quiet", "verbose")
 	rootCmd.PersistentFlags().IntVarP(&concurrencyFlag, "concurrency", "c", 10, "max concurrent operations")
 	rootCmd.PersistentFlags().DurationVar(&timeoutFlag, "timeout", 5*time.Second, "connection timeout")
+	rootCmd.PersistentFlags().DurationVar(&maxDurationFlag, "max-duration", 0, "bound the whole scan, e.g. 10m, dividing it between the scanners and stopping those that overrun with partial results")
+	rootCmd.PersistentFlags().StringVar(&windowFlag, "window", "", `only scan within this daily window, e.g. "22:00-06:00 Europe/Berlin", waiting or pausing outside it`)
+	rootCmd.PersistentFlags().StringVar(&intensityFlag, "intensity", "", "how hard scanners push the target: safe, normal, or aggressive (default: normal)")
+	rootCmd.PersistentFlags().StringVar(&ipVersionFlag, "ip-version", "", "only scan the target's IPv4 (4) or IPv6 (6) addresses (default: either)")
+	rootCmd.PersistentFlags().StringVar(&resolverFlag, "resolver", "", "DNS server to resolve targets with, e.g. 1.1.1.1 (default: the system resolver)")
+	rootCmd.PersistentFlags().StringArrayVar(&resolveFlag, "resolve", nil, "resolve a host to a fixed address, as host:ip (repeatable)")
+	rootCmd.PersistentFlags().StringVar(&endpointsFlag, "endpoints", "", "HAR file or Postman collection whose requests the api scanners test instead of common paths, and the vuln scanner injects into")
+	rootCmd.PersistentFlags().BoolVar(&crawlFlag, "crawl", false, "spider the target first and test the pages, links with parameters, and forms found as well")
+	rootCmd.PersistentFlags().IntVar(&crawlDepthFlag, "crawl-depth", crawl.DefaultMaxDepth, "with --crawl, how many links away from the target to follow")
+	rootCmd.PersistentFlags().IntVar(&crawlPagesFlag, "crawl-pages", crawl.DefaultMaxPages, "with --crawl, the most pages to fetch")
+	rootCmd.PersistentFlags().StringVar(&browserFlag, "browser", "", "headless Chrome or Chromium for the DOM XSS check and screenshots of discovered pages; alone, finds one on PATH")
+	rootCmd.PersistentFlags().Lookup("browser").NoOptDefVal = "auto"
+	rootCmd.PersistentFlags().BoolVar(&noPreflightFlag, "no-preflight", false, "skip probing the target before scanning, and run every scanner regardless")
+	rootCmd.PersistentFlags().StringVar(&engagementFlags.Client, "client", "", "client the scan is run for, recorded in reports")
+	rootCmd.PersistentFlags().StringVar(&engagementFlags.ID, "engagement-id", "", "engagement reference, such as a contract or ticket number, recorded in reports")
+	rootCmd.PersistentFlags().StringVar(&engagementFlags.Tester, "tester", "", "person running the scan, recorded in reports")
+	rootCmd.PersistentFlags().StringVar(&engagementFlags.Authorization, "authorization-ref", "", "reference to the authorization to test, such as a signed statement of work, recorded in reports")
+	rootCmd.PersistentFlags().StringVar(&engagementFlags.Notes, "notes", "", "notes on the engagement, recorded in reports")
+	rootCmd.PersistentFlags().BoolVar(&iAmAuthorizedFlag, "i-am-authorized", false, "scan targets outside the config file's authorized_targets, acknowledging you have permission to test them")
+	rootCmd.PersistentFlags().BoolVar(&cacheFlag, "cache", false, "reuse the results of scanners that ran against the target with the same settings within the cache TTL (default 1h)")
+	rootCmd.PersistentFlags().BoolVar(&noCacheFlag, "no-cache", false, "run every scanner even when cache.enabled is set in the config file")
+	rootCmd.MarkFlagsMutuallyExclusive("cache", "no-cache")
+	rootCmd.PersistentFlags().BoolVar(&passiveFlag, "passive", false, "never contact the target: run only the scanners that work from passive sources (crt.sh, passive DNS)")
 
 	rootCmd.AddCommand(scanCmd)
 	rootCmd.AddCommand(versionCmd)
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/buemura/hunter/internal/rules"
+	"github.com/spf13/cobra"
+)
+
+var rulesScannerFlag string
+
+var rulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Inspect the rules findings are reported under",
+	Long: `Every kind of finding Hunter reports has a stable rule ID, such as
+HUNTER-HEADERS-001, recorded under the rule_id key of the finding's metadata.
+Severity overrides and SARIF output key off rule IDs, which do not change when
+a finding's title is reworded.`,
+}
+
+var rulesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List rule IDs and the scanners that report them",
+	RunE:  runRulesList,
+}
+
+func init() {
+	rulesListCmd.Flags().StringVar(&rulesScannerFlag, "scanner", "", "only list rules reported by this scanner")
+
+	rulesCmd.AddCommand(rulesListCmd)
+	rootCmd.AddCommand(rulesCmd)
+}
+
+func runRulesList(cmd *cobra.Command, args []string) error {
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSCANNERS\tNAME")
+	for _, r := range rules.All() {
+		if rulesScannerFlag != "" && r.Scanners != nil && !slices.Contains(r.Scanners, rulesScannerFlag) {
+			continue
+		}
+		scanners := "any"
+		if r.Scanners != nil {
+			scanners = strings.Join(r.Scanners, ",")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", r.ID, scanners, r.Name)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/buemura/hunter/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	securityTxtFileFlag string
+	securityTxtFlags    config.SecurityTxt
+)
+
+var scaffoldCmd = &cobra.Command{
+	Use:   "scaffold",
+	Short: "Generate files that fix common findings",
+}
+
+var scaffoldSecurityTxtCmd = &cobra.Command{
+	Use:   "security-txt",
+	Short: "Generate a /.well-known/security.txt file",
+	Long: `Generates an RFC 9116 security.txt file, telling researchers how to report
+vulnerabilities, from the security_txt section of the config file. Flags
+override the config values. The file is printed unless --file is given; serve
+it at /.well-known/security.txt over HTTPS.`,
+	Example: `  hunter scaffold security-txt --contact security@example.com --policy https://example.com/disclosure
+  hunter scaffold security-txt --file public/.well-known/security.txt`,
+	RunE: runScaffoldSecurityTxt,
+}
+
+func init() {
+	f := scaffoldSecurityTxtCmd.Flags()
+	f.StringVar(&securityTxtFileFlag, "file", "", "write the file to this path instead of printing it")
+	f.StringSliceVar(&securityTxtFlags.Contact, "contact", nil, "where to report vulnerabilities: an email address or mailto:, tel:, or https: URI (repeatable)")
+	f.DurationVar(&securityTxtFlags.ExpiresIn, "expires-in", 0, "how long until the file expires (default 4320h, 180 days)")
+	f.StringSliceVar(&securityTxtFlags.Encryption, "encryption", nil, "URI of the key to encrypt reports with")
+	f.StringSliceVar(&securityTxtFlags.Acknowledgments, "acknowledgments", nil, "URL of the page thanking reporters")
+	f.StringSliceVar(&securityTxtFlags.PreferredLanguages, "preferred-languages", nil, "languages reports may be written in, e.g. en,fr")
+	f.StringSliceVar(&securityTxtFlags.Canonical, "canonical", nil, "URL the file is served at")
+	f.StringSliceVar(&securityTxtFlags.Policy, "policy", nil, "URL of the vulnerability disclosure policy")
+	f.StringSliceVar(&securityTxtFlags.Hiring, "hiring", nil, "URL of security job openings")
+
+	scaffoldCmd.AddCommand(scaffoldSecurityTxtCmd)
+	rootCmd.AddCommand(scaffoldCmd)
+}
+
+func runScaffoldSecurityTxt(cmd *cobra.Command, args []string) error {
+	fields := securityTxt(cmd)
+	if len(fields.Contact) == 0 {
+		return fmt.Errorf("no contact: pass --contact or set security_txt.contact in %s", config.ConfigFilePath())
+	}
+	content, err := fields.Render(time.Now())
+	if err != nil {
+		return err
+	}
+
+	if securityTxtFileFlag == "" {
+		_, err := cmd.OutOrStdout().Write(content)
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(securityTxtFileFlag), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(securityTxtFileFlag, content, 0o644); err != nil {
+		return err
+	}
+	statusf(cmd, "Wrote %s", securityTxtFileFlag)
+	return nil
+}
+
+// securityTxt returns the security_txt config section with the fields given
+// as flags replaced.
+func securityTxt(cmd *cobra.Command) config.SecurityTxt {
+	var fields config.SecurityTxt
+	if appConfig != nil {
+		fields = appConfig.SecurityTxt
+	}
+	flags := cmd.Flags()
+	if flags.Changed("contact") {
+		fields.Contact = securityTxtFlags.Contact
+	}
+	if flags.Changed("expires-in") {
+		fields.ExpiresIn = securityTxtFlags.ExpiresIn
+	}
+	if flags.Changed("encryption") {
+		fields.Encryption = securityTxtFlags.Encryption
+	}
+	if flags.Changed("acknowledgments") {
+		fields.Acknowledgments = securityTxtFlags.Acknowledgments
+	}
+	if flags.Changed("preferred-languages") {
+		fields.PreferredLanguages = securityTxtFlags.PreferredLanguages
+	}
+	if flags.Changed("canonical") {
+		fields.Canonical = securityTxtFlags.Canonical
+	}
+	if flags.Changed("policy") {
+		fields.Policy = securityTxtFlags.Policy
+	}
+	if flags.Changed("hiring") {
+		fields.Hiring = securityTxtFlags.Hiring
+	}
+	return fields
+}
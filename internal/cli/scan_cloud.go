@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/scanner/cloud"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var scanCloudCmd = &cobra.Command{
+	Use:   "cloud",
+	Short: "Check for exposed Kubernetes, Docker, and cloud metadata endpoints",
+	Long: `Probes the target's host for the control plane of the infrastructure it
+runs on: kubelets (ports 10250 and 10255), the Kubernetes API server (6443,
+and the insecure port 8080), etcd (2379), and the Docker API (2375). It also
+fetches /metrics on the target, and asks for the AWS, Google Cloud, and Azure
+instance metadata services through it, as a forward proxy and with their
+address in the Host header. Services that answer anonymously are reported as
+CRITICAL, reachable ones as HIGH.`,
+	RunE: runCloudScan,
+}
+
+func init() {
+	scanCmd.AddCommand(scanCloudCmd)
+}
+
+func runCloudScan(cmd *cobra.Command, args []string) error {
+	target, err := resolveTarget(cmd)
+	if err != nil {
+		return err
+	}
+
+	formatter, err := resultFormatter()
+	if err != nil {
+		return err
+	}
+
+	reg := scanner.NewRegistry()
+	reg.Register(cloud.New())
+
+	runner := scanner.NewRunner(reg)
+	opts := baseOptions(cmd)
+	progress := attachProgress(cmd, runner)
+
+	ctx, cancel := scanContext(timeoutFlag * 100)
+	defer cancel()
+
+	result, err := runner.RunOne(ctx, "cloud", target, opts)
+	progress.Finish()
+	if err != nil {
+		return err
+	}
+
+	results := []types.ScanResult{*result}
+	logTimings(cmd, results)
+	return formatter.Format(os.Stdout, results)
+}
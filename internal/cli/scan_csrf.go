@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/scanner/csrf"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var scanCSRFCmd = &cobra.Command{
+	Use:   "csrf",
+	Short: "Detect missing CSRF protection",
+	Long: `Checks the POST forms on the target's page for anti-CSRF tokens and the
+target's session cookies for SameSite protection. Unless --intensity is safe,
+forms are also replayed without their token from another origin, skipping
+those that delete data or end the session, to confirm the server accepts them.
+With --crawl or --endpoints, the forms on the pages found and the POST requests
+a form could send are checked too.`,
+	RunE: runCSRFScan,
+}
+
+func init() {
+	scanCmd.AddCommand(scanCSRFCmd)
+}
+
+func runCSRFScan(cmd *cobra.Command, args []string) error {
+	target, err := resolveTarget(cmd)
+	if err != nil {
+		return err
+	}
+
+	formatter, err := resultFormatter()
+	if err != nil {
+		return err
+	}
+
+	reg := scanner.NewRegistry()
+	reg.Register(csrf.New())
+
+	runner := scanner.NewRunner(reg)
+	opts := baseOptions(cmd)
+	progress := attachProgress(cmd, runner)
+
+	ctx, cancel := scanContext(timeoutFlag * 100)
+	defer cancel()
+
+	result, err := runner.RunOne(ctx, "csrf", target, opts)
+	progress.Finish()
+	if err != nil {
+		return err
+	}
+
+	results := []types.ScanResult{*result}
+	logTimings(cmd, results)
+	return formatter.Format(os.Stdout, results)
+}
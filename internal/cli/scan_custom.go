@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/scanner/custom"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	customDirFlag    string
+	customChecksFlag string
+)
+
+var scanCustomCmd = &cobra.Command{
+	Use:   "custom",
+	Short: "Run custom checks defined in YAML",
+	Long: `Runs the checks defined in the .yaml and .yml files of a directory,
+~/.hunter/checks unless --dir names another, against the target. Each check
+is a request to send, with a path from the target's root, and conditions on
+the response's status, headers, and body that make it a finding, so checks
+specific to an organisation need no Go. Checks may compute payloads and
+conditions with CEL-like expressions. Without checks, nothing is sent.`,
+	RunE: runCustomScan,
+}
+
+func init() {
+	scanCustomCmd.Flags().StringVar(&customDirFlag, "dir", "", "Directory of YAML check files (default: ~/.hunter/checks)")
+	scanCustomCmd.Flags().StringVar(&customChecksFlag, "checks", "", "Comma-separated IDs of the checks to run (default: all)")
+	scanCmd.AddCommand(scanCustomCmd)
+}
+
+func runCustomScan(cmd *cobra.Command, args []string) error {
+	target, err := resolveTarget(cmd)
+	if err != nil {
+		return err
+	}
+
+	formatter, err := resultFormatter()
+	if err != nil {
+		return err
+	}
+
+	reg := scanner.NewRegistry()
+	reg.Register(custom.New())
+
+	runner := scanner.NewRunner(reg)
+	opts := baseOptions(cmd)
+	progress := attachProgress(cmd, runner)
+
+	if customDirFlag != "" {
+		setFlagArg(cmd, &opts, "custom", "dir", "dir", customDirFlag)
+	}
+	if customChecksFlag != "" {
+		setFlagArg(cmd, &opts, "custom", "checks", "checks", customChecksFlag)
+	}
+
+	ctx, cancel := scanContext(timeoutFlag * 100)
+	defer cancel()
+
+	result, err := runner.RunOne(ctx, "custom", target, opts)
+	progress.Finish()
+	if err != nil {
+		return err
+	}
+
+	results := []types.ScanResult{*result}
+	logTimings(cmd, results)
+	return formatter.Format(os.Stdout, results)
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/scanner/deserialization"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var scanDeserializationCmd = &cobra.Command{
+	Use:   "deserialization",
+	Short: "Detect serialized objects in responses and cookies",
+	Long: `Reads the target's page, and with --crawl or --endpoints the pages found,
+for serialized objects the site hands to clients: Java serialized objects in
+cookies, form fields, or response bodies, PHP serialized data in cookies and
+form fields, and ASP.NET ViewState without a MAC. Nothing is sent but the
+page requests; no deserialization payloads are tried.`,
+	RunE: runDeserializationScan,
+}
+
+func init() {
+	scanCmd.AddCommand(scanDeserializationCmd)
+}
+
+func runDeserializationScan(cmd *cobra.Command, args []string) error {
+	target, err := resolveTarget(cmd)
+	if err != nil {
+		return err
+	}
+
+	formatter, err := resultFormatter()
+	if err != nil {
+		return err
+	}
+
+	reg := scanner.NewRegistry()
+	reg.Register(deserialization.New())
+
+	runner := scanner.NewRunner(reg)
+	opts := baseOptions(cmd)
+	progress := attachProgress(cmd, runner)
+
+	ctx, cancel := scanContext(timeoutFlag * 100)
+	defer cancel()
+
+	result, err := runner.RunOne(ctx, "deserialization", target, opts)
+	progress.Finish()
+	if err != nil {
+		return err
+	}
+
+	results := []types.ScanResult{*result}
+	logTimings(cmd, results)
+	return formatter.Format(os.Stdout, results)
+}
@@ -1,11 +1,8 @@
 package cli
 
 import (
-	"context"
-	"fmt"
 	"os"
 
-	"github.com/buemura/hunter/internal/output"
 	"github.com/buemura/hunter/internal/scanner"
 	"github.com/buemura/hunter/internal/scanner/dirs"
 	"github.com/buemura/hunter/pkg/types"
@@ -22,21 +19,17 @@ var scanDirsCmd = &cobra.Command{
 }
 
 func init() {
-	scanDirsCmd.Flags().StringVar(&wordlistFlag, "wordlist", "", "path to custom wordlist file (default: embedded wordlist)")
+	scanDirsCmd.Flags().StringVar(&wordlistFlag, "wordlist", "", "path to custom wordlist file (default: wordlist_path, then installed data, then embedded wordlist)")
 	scanCmd.AddCommand(scanDirsCmd)
 }
 
 func runDirsScan(cmd *cobra.Command, args []string) error {
-	if targetFlag == "" {
-		return fmt.Errorf("--target (-t) is required")
-	}
-
-	target, err := types.ParseTarget(targetFlag)
+	target, err := resolveTarget(cmd)
 	if err != nil {
-		return fmt.Errorf("invalid target: %w", err)
+		return err
 	}
 
-	formatter, err := output.GetFormatter(outputFlag)
+	formatter, err := resultFormatter()
 	if err != nil {
 		return err
 	}
@@ -45,20 +38,20 @@ func runDirsScan(cmd *cobra.Command, args []string) error {
 	reg.Register(dirs.New())
 
 	runner := scanner.NewRunner(reg)
-	opts := scanner.Options{
-		Concurrency: concurrencyFlag,
-		Timeout:     timeoutFlag,
-		Verbose:     verboseFlag,
-		ExtraArgs:   map[string]interface{}{"wordlist": wordlistFlag},
-	}
+	opts := baseOptions(cmd)
+	progress := attachProgress(cmd, runner)
+	opts.ExtraArgs["wordlist"] = resolveWordlist()
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeoutFlag*100)
+	ctx, cancel := scanContext(timeoutFlag * 100)
 	defer cancel()
 
 	result, err := runner.RunOne(ctx, "dirs", target, opts)
+	progress.Finish()
 	if err != nil {
 		return err
 	}
 
-	return formatter.Format(os.Stdout, []types.ScanResult{*result})
+	results := []types.ScanResult{*result}
+	logTimings(cmd, results)
+	return formatter.Format(os.Stdout, results)
 }
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/scanner/forms"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var scanFormsCmd = &cobra.Command{
+	Use:   "forms",
+	Short: "Test HTML forms",
+	Long: `Tests the HTML forms on the target's page for missing CSRF protection,
+password fields browsers may autocomplete, and reflected XSS and SQL injection
+through their fields. With --crawl or --endpoints, the forms on the pages found
+are tested too. "scan csrf" also checks whether tokens are validated.`,
+	RunE: runFormsScan,
+}
+
+func init() {
+	scanCmd.AddCommand(scanFormsCmd)
+}
+
+func runFormsScan(cmd *cobra.Command, args []string) error {
+	target, err := resolveTarget(cmd)
+	if err != nil {
+		return err
+	}
+
+	formatter, err := resultFormatter()
+	if err != nil {
+		return err
+	}
+
+	reg := scanner.NewRegistry()
+	reg.Register(forms.New())
+
+	runner := scanner.NewRunner(reg)
+	opts := baseOptions(cmd)
+	progress := attachProgress(cmd, runner)
+
+	ctx, cancel := scanContext(timeoutFlag * 100)
+	defer cancel()
+
+	result, err := runner.RunOne(ctx, "forms", target, opts)
+	progress.Finish()
+	if err != nil {
+		return err
+	}
+
+	results := []types.ScanResult{*result}
+	logTimings(cmd, results)
+	return formatter.Format(os.Stdout, results)
+}
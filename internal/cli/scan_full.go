@@ -1,23 +1,28 @@
 package cli
 
 import (
-	"context"
-	"fmt"
 	"os"
 
-	"github.com/buemura/hunter/internal/output"
 	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/scanner/cloud"
+	"github.com/buemura/hunter/internal/scanner/csrf"
+	"github.com/buemura/hunter/internal/scanner/custom"
+	"github.com/buemura/hunter/internal/scanner/deserialization"
 	"github.com/buemura/hunter/internal/scanner/dirs"
+	"github.com/buemura/hunter/internal/scanner/forms"
 	"github.com/buemura/hunter/internal/scanner/headers"
+	"github.com/buemura/hunter/internal/scanner/mixedcontent"
 	"github.com/buemura/hunter/internal/scanner/port"
+	"github.com/buemura/hunter/internal/scanner/protocols"
+	"github.com/buemura/hunter/internal/scanner/redirects"
 	"github.com/buemura/hunter/internal/scanner/ssl"
+	"github.com/buemura/hunter/internal/scanner/tech"
 	"github.com/buemura/hunter/internal/scanner/vuln"
-	"github.com/buemura/hunter/pkg/types"
 	"github.com/spf13/cobra"
 )
 
 // webScannerNames lists all web scanner names in execution order.
-var webScannerNames = []string{"port", "headers", "ssl", "dirs", "vuln"}
+var webScannerNames = []string{"port", "headers", "ssl", "protocols", "cloud", "dirs", "vuln", "forms", "csrf", "tech", "redirects", "mixed-content", "deserialization", "custom"}
 
 var scanFullCmd = &cobra.Command{
 	Use:   "full",
@@ -27,20 +32,26 @@ var scanFullCmd = &cobra.Command{
 }
 
 func init() {
+	addSelectionFlags(scanFullCmd)
 	scanCmd.AddCommand(scanFullCmd)
 }
 
 func runScanFull(cmd *cobra.Command, args []string) error {
-	if targetFlag == "" {
-		return fmt.Errorf("--target (-t) is required")
+	target, err := resolveTarget(cmd)
+	if err != nil {
+		return err
 	}
 
-	target, err := types.ParseTarget(targetFlag)
+	formatter, err := resultFormatter()
 	if err != nil {
-		return fmt.Errorf("invalid target: %w", err)
+		return err
 	}
 
-	formatter, err := output.GetFormatter(outputFlag)
+	names, err := selectScanners(webScannerNames, categoryFlag, excludeFlag)
+	if err != nil {
+		return err
+	}
+	names, err = dropDisabled(cmd, names)
 	if err != nil {
 		return err
 	}
@@ -49,19 +60,34 @@ func runScanFull(cmd *cobra.Command, args []string) error {
 	reg.Register(port.New())
 	reg.Register(headers.New())
 	reg.Register(ssl.New())
+	reg.Register(protocols.New())
+	reg.Register(cloud.New())
 	reg.Register(dirs.New())
 	reg.Register(vuln.New())
+	reg.Register(forms.New())
+	reg.Register(csrf.New())
+	reg.Register(tech.New())
+	reg.Register(redirects.New())
+	reg.Register(mixedcontent.New())
+	reg.Register(deserialization.New())
+	reg.Register(custom.New())
 
 	runner := scanner.NewRunner(reg)
-	opts := scanner.Options{
-		Concurrency: concurrencyFlag,
-		Timeout:     timeoutFlag,
-		Verbose:     verboseFlag,
+	opts := baseOptions(cmd)
+	names, err = applyOrdering(names, &opts)
+	if err != nil {
+		return err
 	}
+	progress := attachProgress(cmd, runner)
+	opts.ExtraArgs["wordlist"] = resolveWordlist()
+	applyTechData(&opts)
+	dedupeCSRF(names, &opts)
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeoutFlag*100)
+	ctx, cancel := scanContext(timeoutFlag * 100)
 	defer cancel()
 
-	results := runner.RunAll(ctx, webScannerNames, target, opts)
+	results := runner.RunAll(ctx, names, target, opts)
+	progress.Finish()
+	logTimings(cmd, results)
 	return formatter.Format(os.Stdout, results)
 }
@@ -1,39 +1,46 @@
 package cli
 
 import (
-	"context"
-	"fmt"
 	"os"
 
-	"github.com/buemura/hunter/internal/output"
 	"github.com/buemura/hunter/internal/scanner"
 	"github.com/buemura/hunter/internal/scanner/headers"
 	"github.com/buemura/hunter/pkg/types"
 	"github.com/spf13/cobra"
 )
 
+var (
+	headersAreasFlag     bool
+	headersAreaHostsFlag string
+)
+
 var scanHeadersCmd = &cobra.Command{
 	Use:   "headers",
 	Short: "Analyze HTTP security headers",
-	Long:  "Checks the target for missing or misconfigured HTTP security headers.",
-	RunE:  runHeadersScan,
+	Long: `Checks the target for missing or misconfigured HTTP security headers and
+cookie attributes.
+
+With --areas, the other hosts of the target's domain, from passive sources
+and --area-hosts, are sorted into application areas: the public site,
+applications (including those behind single sign-on), APIs, and sign-in
+portals. Each host is checked against its area's header profile, and
+findings are grouped by area.`,
+	RunE: runHeadersScan,
 }
 
 func init() {
+	scanHeadersCmd.Flags().BoolVar(&headersAreasFlag, "areas", false, "Also check the other hosts of the target's domain, grouped by application area")
+	scanHeadersCmd.Flags().StringVar(&headersAreaHostsFlag, "area-hosts", "", "Comma-separated hosts to check as application areas, besides those passive sources know of")
 	scanCmd.AddCommand(scanHeadersCmd)
 }
 
 func runHeadersScan(cmd *cobra.Command, args []string) error {
-	if targetFlag == "" {
-		return fmt.Errorf("--target (-t) is required")
-	}
-
-	target, err := types.ParseTarget(targetFlag)
+	target, err := resolveTarget(cmd)
 	if err != nil {
-		return fmt.Errorf("invalid target: %w", err)
+		return err
 	}
 
-	formatter, err := output.GetFormatter(outputFlag)
+	formatter, err := resultFormatter()
 	if err != nil {
 		return err
 	}
@@ -42,19 +49,26 @@ func runHeadersScan(cmd *cobra.Command, args []string) error {
 	reg.Register(headers.New())
 
 	runner := scanner.NewRunner(reg)
-	opts := scanner.Options{
-		Concurrency: concurrencyFlag,
-		Timeout:     timeoutFlag,
-		Verbose:     verboseFlag,
+	opts := baseOptions(cmd)
+	progress := attachProgress(cmd, runner)
+
+	if headersAreasFlag {
+		setFlagArg(cmd, &opts, "headers", "areas", "areas", true)
+	}
+	if headersAreaHostsFlag != "" {
+		setFlagArg(cmd, &opts, "headers", "area-hosts", "area_hosts", headersAreaHostsFlag)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeoutFlag*10)
+	ctx, cancel := scanContext(timeoutFlag * 10)
 	defer cancel()
 
 	result, err := runner.RunOne(ctx, "headers", target, opts)
+	progress.Finish()
 	if err != nil {
 		return err
 	}
 
-	return formatter.Format(os.Stdout, []types.ScanResult{*result})
+	results := []types.ScanResult{*result}
+	logTimings(cmd, results)
+	return formatter.Format(os.Stdout, results)
 }
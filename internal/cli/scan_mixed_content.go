@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/scanner/mixedcontent"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var scanMixedContentCmd = &cobra.Command{
+	Use:   "mixed-content",
+	Short: "Detect HTTPS pages loading resources over plain HTTP",
+	Long: `Parses the target's page, and with --crawl or --endpoints the pages found,
+for subresources loaded over plain HTTP when the page itself is served over
+HTTPS. Scripts, frames, stylesheets, and plugins are reported as active mixed
+content (MEDIUM), images, audio, and video as passive mixed content (LOW).`,
+	RunE: runMixedContentScan,
+}
+
+func init() {
+	scanCmd.AddCommand(scanMixedContentCmd)
+}
+
+func runMixedContentScan(cmd *cobra.Command, args []string) error {
+	target, err := resolveTarget(cmd)
+	if err != nil {
+		return err
+	}
+
+	formatter, err := resultFormatter()
+	if err != nil {
+		return err
+	}
+
+	reg := scanner.NewRegistry()
+	reg.Register(mixedcontent.New())
+
+	runner := scanner.NewRunner(reg)
+	opts := baseOptions(cmd)
+	progress := attachProgress(cmd, runner)
+
+	ctx, cancel := scanContext(timeoutFlag * 100)
+	defer cancel()
+
+	result, err := runner.RunOne(ctx, "mixed-content", target, opts)
+	progress.Finish()
+	if err != nil {
+		return err
+	}
+
+	results := []types.ScanResult{*result}
+	logTimings(cmd, results)
+	return formatter.Format(os.Stdout, results)
+}
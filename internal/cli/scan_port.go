@@ -1,42 +1,44 @@
 package cli
 
 import (
-	"context"
-	"fmt"
 	"os"
+	"time"
 
-	"github.com/buemura/hunter/internal/output"
 	"github.com/buemura/hunter/internal/scanner"
 	"github.com/buemura/hunter/internal/scanner/port"
 	"github.com/buemura/hunter/pkg/types"
 	"github.com/spf13/cobra"
 )
 
-var portsFlag string
+var (
+	portsFlag string
+	pingFlag  bool
+)
 
 var scanPortCmd = &cobra.Command{
 	Use:   "port",
 	Short: "Scan for open TCP ports",
-	Long:  "Performs a TCP connect scan to discover open ports on the target.",
-	RunE:  runPortScan,
+	Long: `Performs a TCP connect scan to discover open ports on the target.
+
+Open ports are printed as they are found at -v. The scan stops early when
+none of the first probes get an answer, as happens when the host is down;
+--ping checks whether it is up before scanning at all.`,
+	RunE: runPortScan,
 }
 
 func init() {
 	scanPortCmd.Flags().StringVar(&portsFlag, "ports", "common", "ports to scan: single, range (1-1024), comma-separated, or 'common'")
+	scanPortCmd.Flags().BoolVar(&pingFlag, "ping", false, "skip the scan if the host does not answer a ping or a connection to port 80 or 443")
 	scanCmd.AddCommand(scanPortCmd)
 }
 
 func runPortScan(cmd *cobra.Command, args []string) error {
-	if targetFlag == "" {
-		return fmt.Errorf("--target (-t) is required")
-	}
-
-	target, err := types.ParseTarget(targetFlag)
+	target, err := resolveTarget(cmd)
 	if err != nil {
-		return fmt.Errorf("invalid target: %w", err)
+		return err
 	}
 
-	formatter, err := output.GetFormatter(outputFlag)
+	formatter, err := resultFormatter()
 	if err != nil {
 		return err
 	}
@@ -45,20 +47,32 @@ func runPortScan(cmd *cobra.Command, args []string) error {
 	reg.Register(port.New())
 
 	runner := scanner.NewRunner(reg)
-	opts := scanner.Options{
-		Concurrency: concurrencyFlag,
-		Timeout:     timeoutFlag,
-		Verbose:     verboseFlag,
-		ExtraArgs:   map[string]interface{}{"ports": portsFlag},
-	}
+	opts := baseOptions(cmd)
+	progress := attachProgress(cmd, runner)
+	setFlagArg(cmd, &opts, "port", "ports", "ports", portsFlag)
+	setFlagArg(cmd, &opts, "port", "ping", "ping", pingFlag)
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeoutFlag*100)
+	ctx, cancel := scanContext(portScanDeadline(opts.ForScanner("port").StringArg("ports"), concurrencyFlag, timeoutFlag))
 	defer cancel()
 
 	result, err := runner.RunOne(ctx, "port", target, opts)
+	progress.Finish()
 	if err != nil {
 		return err
 	}
 
-	return formatter.Format(os.Stdout, []types.ScanResult{*result})
+	results := []types.ScanResult{*result}
+	logTimings(cmd, results)
+	return formatter.Format(os.Stdout, results)
+}
+
+// portScanDeadline bounds a port scan: long enough for every port to time
+// out at the given concurrency, twice over, and never less than the 100
+// timeouts every other scan gets.
+func portScanDeadline(spec string, concurrency int, timeout time.Duration) time.Duration {
+	ports, err := port.ParsePortRange(spec)
+	if err != nil || concurrency < 1 {
+		return timeout * 100
+	}
+	return timeout * time.Duration(max(100, 2*len(ports)/concurrency))
 }
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/scanner/protocols"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var scanProtocolsCmd = &cobra.Command{
+	Use:   "protocols",
+	Short: "Check for HTTP/2 misconfigurations",
+	Long: `Checks how the target speaks HTTP/2: whether it switches connections to
+cleartext HTTP/2 (h2c) on request, which lets requests be smuggled past a
+proxy in front of it, whether it accepts oversized header lists, and whether
+requests for the other hosts its certificate covers, which browsers send over
+the same connection, get the page for unknown hosts instead of 421
+Misdirected Request. The oversized header check is skipped at safe
+intensity.`,
+	RunE: runProtocolsScan,
+}
+
+func init() {
+	scanCmd.AddCommand(scanProtocolsCmd)
+}
+
+func runProtocolsScan(cmd *cobra.Command, args []string) error {
+	target, err := resolveTarget(cmd)
+	if err != nil {
+		return err
+	}
+
+	formatter, err := resultFormatter()
+	if err != nil {
+		return err
+	}
+
+	reg := scanner.NewRegistry()
+	reg.Register(protocols.New())
+
+	runner := scanner.NewRunner(reg)
+	opts := baseOptions(cmd)
+	progress := attachProgress(cmd, runner)
+
+	ctx, cancel := scanContext(timeoutFlag * 100)
+	defer cancel()
+
+	result, err := runner.RunOne(ctx, "protocols", target, opts)
+	progress.Finish()
+	if err != nil {
+		return err
+	}
+
+	results := []types.ScanResult{*result}
+	logTimings(cmd, results)
+	return formatter.Format(os.Stdout, results)
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/scanner/redirects"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var scanRedirectsCmd = &cobra.Command{
+	Use:   "redirects",
+	Short: "Analyze the redirect chains from the target's roots",
+	Long: `Follows every redirect, by Location header or meta refresh, from the
+target's http:// and https:// roots, and reports plain HTTP that is not
+redirected to HTTPS, redirect loops, hops from HTTPS down to HTTP, and meta
+refreshes that send visitors to another domain.`,
+	RunE: runRedirectsScan,
+}
+
+func init() {
+	scanCmd.AddCommand(scanRedirectsCmd)
+}
+
+func runRedirectsScan(cmd *cobra.Command, args []string) error {
+	target, err := resolveTarget(cmd)
+	if err != nil {
+		return err
+	}
+
+	formatter, err := resultFormatter()
+	if err != nil {
+		return err
+	}
+
+	reg := scanner.NewRegistry()
+	reg.Register(redirects.New())
+
+	runner := scanner.NewRunner(reg)
+	opts := baseOptions(cmd)
+	progress := attachProgress(cmd, runner)
+
+	ctx, cancel := scanContext(timeoutFlag * 100)
+	defer cancel()
+
+	result, err := runner.RunOne(ctx, "redirects", target, opts)
+	progress.Finish()
+	if err != nil {
+		return err
+	}
+
+	results := []types.ScanResult{*result}
+	logTimings(cmd, results)
+	return formatter.Format(os.Stdout, results)
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/scanner/subdomain"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var scanSubdomainCmd = &cobra.Command{
+	Use:   "subdomain",
+	Short: "Enumerate subdomains from passive sources",
+	Long:  "Lists the names under the target's domain known to certificate transparency logs (crt.sh) and the passive DNS providers in the config file, and checks which still resolve. With --passive, nothing is resolved.",
+	RunE:  runSubdomainScan,
+}
+
+func init() {
+	scanCmd.AddCommand(scanSubdomainCmd)
+}
+
+func runSubdomainScan(cmd *cobra.Command, args []string) error {
+	target, err := resolveTarget(cmd)
+	if err != nil {
+		return err
+	}
+
+	formatter, err := resultFormatter()
+	if err != nil {
+		return err
+	}
+
+	reg := scanner.NewRegistry()
+	reg.Register(subdomain.New())
+
+	runner := scanner.NewRunner(reg)
+	opts := baseOptions(cmd)
+	progress := attachProgress(cmd, runner)
+
+	ctx, cancel := scanContext(timeoutFlag * 100)
+	defer cancel()
+
+	result, err := runner.RunOne(ctx, "subdomain", target, opts)
+	progress.Finish()
+	if err != nil {
+		return err
+	}
+
+	results := []types.ScanResult{*result}
+	logTimings(cmd, results)
+	return formatter.Format(os.Stdout, results)
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/scanner/tech"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var faviconsFlag string
+
+var scanTechCmd = &cobra.Command{
+	Use:   "tech",
+	Short: "Identify the products the target runs",
+	Long: `Hashes the favicons of the target's page, and with --crawl or --endpoints of
+the pages found, the way Shodan indexes them, and looks the hashes up in a
+bundled database of products. --favicons adds entries from a local JSON file
+mapping hashes to product names. Icons not in the database are reported with
+their hash.
+
+The versions of the JavaScript libraries the pages load, and of the server
+software their Server and X-Powered-By headers name, are checked against
+known-vulnerable versions. The bundled data is replaced by the js-vulns and
+cve-map data sets once "hunter data update" installs them.`,
+	Example: `  hunter scan tech -t https://example.com
+  hunter scan tech -t https://example.com --favicons ./favicons.json`,
+	RunE: runTechScan,
+}
+
+func init() {
+	scanTechCmd.Flags().StringVar(&faviconsFlag, "favicons", "", `JSON file of favicon hashes to product names, e.g. {"81586312": "Jenkins"}, extending the bundled database`)
+	scanCmd.AddCommand(scanTechCmd)
+}
+
+func runTechScan(cmd *cobra.Command, args []string) error {
+	target, err := resolveTarget(cmd)
+	if err != nil {
+		return err
+	}
+
+	formatter, err := resultFormatter()
+	if err != nil {
+		return err
+	}
+
+	reg := scanner.NewRegistry()
+	reg.Register(tech.New())
+
+	runner := scanner.NewRunner(reg)
+	opts := baseOptions(cmd)
+	setFlagArg(cmd, &opts, "tech", "favicons", "favicons", faviconsFlag)
+	applyTechData(&opts)
+	progress := attachProgress(cmd, runner)
+
+	ctx, cancel := scanContext(timeoutFlag * 100)
+	defer cancel()
+
+	result, err := runner.RunOne(ctx, "tech", target, opts)
+	progress.Finish()
+	if err != nil {
+		return err
+	}
+
+	results := []types.ScanResult{*result}
+	logTimings(cmd, results)
+	return formatter.Format(os.Stdout, results)
+}
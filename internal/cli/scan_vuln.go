@@ -1,42 +1,64 @@
 package cli
 
 import (
-	"context"
-	"fmt"
 	"os"
 
-	"github.com/buemura/hunter/internal/output"
 	"github.com/buemura/hunter/internal/scanner"
 	"github.com/buemura/hunter/internal/scanner/vuln"
 	"github.com/buemura/hunter/pkg/types"
 	"github.com/spf13/cobra"
 )
 
-var vulnChecksFlag string
+var (
+	vulnChecksFlag string
+	vulnDataFlag   string
+	vulnNoMineFlag bool
+	vulnParamsFlag string
+)
 
 var scanVulnCmd = &cobra.Command{
 	Use:   "vuln",
 	Short: "Basic vulnerability detection",
-	Long:  "Performs basic vulnerability detection checks including reflected XSS, SQL injection, and open redirect tests against the target.",
-	RunE:  runVulnScan,
+	Long: `Performs basic vulnerability detection checks including reflected XSS, SQL
+injection, and open redirect tests against the target. Payloads go into the
+target URL's query parameters, the fields of a --data body POSTed to it, and
+the query parameters and form or JSON body fields of --endpoints requests.
+
+Before the checks run, common parameter names the target does not link to
+are sent to the target URL and the --data body in batches, and those whose
+value comes back, or that change the status or size of the response, are
+tested along with the others. --param-wordlist replaces the built-in names,
+and --no-param-mining skips this.
+
+JSON bodies POSTed by --data or --endpoints requests are also sent with
+__proto__ and constructor.prototype keys, to find server-side prototype
+pollution in Node.js APIs.
+
+On HTTPS targets, the same points and each page with a hunter_breach
+parameter are requested with compression, to find responses that reflect
+input while carrying anti-CSRF tokens or other secrets (BREACH).
+
+With --browser, the target page is also loaded in a headless Chrome or
+Chromium with payloads in its URL fragment, to find DOM-based XSS that only
+the page's own scripts trigger.`,
+	RunE: runVulnScan,
 }
 
 func init() {
-	scanVulnCmd.Flags().StringVar(&vulnChecksFlag, "checks", "", "Comma-separated checks to run (default: all). Options: xss,sqli,redirect")
+	scanVulnCmd.Flags().StringVar(&vulnChecksFlag, "checks", "", "Comma-separated checks to run (default: all). Options: xss,sqli,redirect,dom-xss,proto-pollution,breach")
+	scanVulnCmd.Flags().StringVar(&vulnDataFlag, "data", "", "Form-encoded or JSON body to POST to the target, whose fields are injected into")
+	scanVulnCmd.Flags().BoolVar(&vulnNoMineFlag, "no-param-mining", false, "Do not look for hidden parameters to test")
+	scanVulnCmd.Flags().StringVar(&vulnParamsFlag, "param-wordlist", "", "File of parameter names, one per line, to look for instead of the built-in ones")
 	scanCmd.AddCommand(scanVulnCmd)
 }
 
 func runVulnScan(cmd *cobra.Command, args []string) error {
-	if targetFlag == "" {
-		return fmt.Errorf("--target (-t) is required")
-	}
-
-	target, err := types.ParseTarget(targetFlag)
+	target, err := resolveTarget(cmd)
 	if err != nil {
-		return fmt.Errorf("invalid target: %w", err)
+		return err
 	}
 
-	formatter, err := output.GetFormatter(outputFlag)
+	formatter, err := resultFormatter()
 	if err != nil {
 		return err
 	}
@@ -45,25 +67,32 @@ func runVulnScan(cmd *cobra.Command, args []string) error {
 	reg.Register(vuln.New())
 
 	runner := scanner.NewRunner(reg)
-	opts := scanner.Options{
-		Concurrency: concurrencyFlag,
-		Timeout:     timeoutFlag,
-		Verbose:     verboseFlag,
-	}
+	opts := baseOptions(cmd)
+	progress := attachProgress(cmd, runner)
 
 	if vulnChecksFlag != "" {
-		opts.ExtraArgs = map[string]interface{}{
-			"checks": vulnChecksFlag,
-		}
+		setFlagArg(cmd, &opts, "vuln", "checks", "checks", vulnChecksFlag)
+	}
+	if vulnDataFlag != "" {
+		setFlagArg(cmd, &opts, "vuln", "data", "data", vulnDataFlag)
+	}
+	if vulnNoMineFlag {
+		setFlagArg(cmd, &opts, "vuln", "no-param-mining", "no_param_mining", true)
+	}
+	if vulnParamsFlag != "" {
+		setFlagArg(cmd, &opts, "vuln", "param-wordlist", "param_wordlist", vulnParamsFlag)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeoutFlag*100)
+	ctx, cancel := scanContext(timeoutFlag * 100)
 	defer cancel()
 
 	result, err := runner.RunOne(ctx, "vuln", target, opts)
+	progress.Finish()
 	if err != nil {
 		return err
 	}
 
-	return formatter.Format(os.Stdout, []types.ScanResult{*result})
+	results := []types.ScanResult{*result}
+	logTimings(cmd, results)
+	return formatter.Format(os.Stdout, results)
 }
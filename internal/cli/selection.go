@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/internal/config"
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	excludeFlag   []string
+	categoryFlag  []string
+	orderFlag     []string
+	onFailureFlag string
+	dependsOnFlag []string
+
+	watchdogFlag          time.Duration
+	watchdogThresholdFlag float64
+	watchdogActionFlag    string
+)
+
+// scannerCategories groups scanner names by the kind of surface they test.
+var scannerCategories = map[string][]string{
+	"network": {"port", "ssl", "protocols", "cloud"},
+	"web":     {"headers", "dirs", "vuln", "forms", "csrf", "tech", "redirects", "mixed-content", "deserialization", "custom"},
+	"api":     apiScannerNames,
+	"recon":   reconScannerNames,
+}
+
+// intrusiveScanners send request floods or attack payloads. Environments that
+// disallow destructive checks disable them.
+var intrusiveScanners = []string{"api-ratelimit", "api-auth", "vuln", "forms", "csrf"}
+
+// addSelectionFlags registers --exclude and --category on a multi-scanner
+// command, --order, --on-failure, and --depends-on, and the --watchdog
+// flags.
+func addSelectionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&excludeFlag, "exclude", nil, "comma-separated scanners to skip")
+	cmd.Flags().StringSliceVar(&categoryFlag, "category", nil, "only run scanners in these categories: "+strings.Join(categoryNames(), ", "))
+	cmd.Flags().StringSliceVar(&orderFlag, "order", nil, "run the scanners one at a time, these first in the order given")
+	cmd.Flags().StringVar(&onFailureFlag, "on-failure", "", "when a scanner fails: continue, abort-remaining, or skip-dependents (default continue)")
+	cmd.Flags().StringSliceVar(&dependsOnFlag, "depends-on", nil, "comma-separated scanner:dependency pairs; a scanner starts after its dependencies finish")
+	cmd.Flags().DurationVar(&watchdogFlag, "watchdog", 0, "probe the target this often during the scan, e.g. 10s, and act if it stops responding")
+	cmd.Flags().Float64Var(&watchdogThresholdFlag, "watchdog-threshold", 0.8, "with --watchdog, the share of the last 5 probes that must fail for the target to be judged down")
+	cmd.Flags().StringVar(&watchdogActionFlag, "watchdog-action", "", "with --watchdog, what to do once the target is down: abort the scanners not yet started, or pause until it responds (default abort)")
+}
+
+// selectScanners narrows names to the requested categories (all of names when
+// none are given) and removes excluded scanners, preserving the input order.
+func selectScanners(names, categories, exclude []string) ([]string, error) {
+	known := make(map[string]bool, len(names))
+	for _, n := range names {
+		known[n] = true
+	}
+
+	var inCategory map[string]bool
+	if len(categories) > 0 {
+		inCategory = make(map[string]bool)
+		for _, c := range categories {
+			members, ok := scannerCategories[strings.TrimSpace(c)]
+			if !ok {
+				return nil, fmt.Errorf("unknown category %q (supported: %s)", c, strings.Join(categoryNames(), ", "))
+			}
+			for _, m := range members {
+				inCategory[m] = true
+			}
+		}
+	}
+
+	skip := make(map[string]bool, len(exclude))
+	for _, e := range exclude {
+		e = strings.TrimSpace(e)
+		if !known[e] {
+			return nil, fmt.Errorf("cannot exclude unknown scanner %q", e)
+		}
+		skip[e] = true
+	}
+
+	var selected []string
+	for _, n := range names {
+		if skip[n] || (inCategory != nil && !inCategory[n]) {
+			continue
+		}
+		selected = append(selected, n)
+	}
+
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no scanners left to run after applying --category and --exclude")
+	}
+	return selected, nil
+}
+
+// applyOrdering puts the scanners named by --order first, in that order,
+// and sets the failure policy and dependencies of --on-failure and
+// --depends-on, and the watchdog of the --watchdog flags, on opts. With
+// --order, the scanners run one at a time.
+func applyOrdering(names []string, opts *scanner.Options) ([]string, error) {
+	policy, err := scanner.ParseFailurePolicy(onFailureFlag)
+	if err != nil {
+		return nil, err
+	}
+	action, err := scanner.ParseWatchdogAction(watchdogActionFlag)
+	if err != nil {
+		return nil, err
+	}
+	if watchdogThresholdFlag <= 0 || watchdogThresholdFlag > 1 {
+		return nil, fmt.Errorf("invalid --watchdog-threshold %v: want a share between 0 and 1", watchdogThresholdFlag)
+	}
+	opts.Watchdog = scanner.WatchdogSettings{Interval: watchdogFlag, Threshold: watchdogThresholdFlag, Action: action}
+	deps, err := scanner.ParseDependencies(dependsOnFlag)
+	if err != nil {
+		return nil, err
+	}
+	opts.OnFailure, opts.DependsOn = policy, deps
+	if len(orderFlag) == 0 {
+		return names, nil
+	}
+
+	known := make(map[string]bool, len(names))
+	for _, n := range names {
+		known[n] = true
+	}
+	ordered := make([]string, 0, len(names))
+	for _, n := range orderFlag {
+		n = strings.TrimSpace(n)
+		if !known[n] {
+			return nil, fmt.Errorf("cannot order scanner %q: it is not part of this scan", n)
+		}
+		known[n] = false
+		ordered = append(ordered, n)
+	}
+	for _, n := range names {
+		if known[n] {
+			ordered = append(ordered, n)
+		}
+	}
+	opts.Sequential = true
+	return ordered, nil
+}
+
+// categoryNames returns the supported category names in sorted order.
+func categoryNames() []string {
+	names := make([]string, 0, len(scannerCategories))
+	for name := range scannerCategories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// dedupeCSRF leaves forms without an anti-CSRF token to the csrf scanner
+// when it runs alongside the forms scanner, so each is reported once.
+func dedupeCSRF(names []string, opts *scanner.Options) {
+	if slices.Contains(names, "forms") && slices.Contains(names, "csrf") {
+		opts.ExtraArgs["skip_csrf"] = true
+	}
+}
+
+// disabledScanners returns the scanners env turns off, with the reason.
+func disabledScanners(env config.Environment) map[string]string {
+	disabled := make(map[string]string)
+	if !env.AllowsDestructive() {
+		for _, name := range intrusiveScanners {
+			disabled[name] = fmt.Sprintf("intrusive scanners are not allowed in environment %q", envFlag)
+		}
+	}
+	for _, name := range env.Exclude {
+		disabled[strings.TrimSpace(name)] = fmt.Sprintf("excluded in environment %q", envFlag)
+	}
+	return disabled
+}
+
+// dropDisabled removes the scanners disabled by the active environment from
+// names, noting each one on stderr.
+func dropDisabled(cmd *cobra.Command, names []string) ([]string, error) {
+	if activeEnv == nil {
+		return names, nil
+	}
+	disabled := disabledScanners(*activeEnv)
+
+	var kept []string
+	for _, n := range names {
+		if reason, ok := disabled[n]; ok {
+			statusf(cmd, "Skipping %s: %s", n, reason)
+			continue
+		}
+		kept = append(kept, n)
+	}
+	if len(kept) == 0 {
+		return nil, fmt.Errorf("no scanners left to run in environment %q", envFlag)
+	}
+	return kept, nil
+}
@@ -1,20 +1,52 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/buemura/hunter/internal/config"
 	"github.com/buemura/hunter/internal/scanner"
 	"github.com/buemura/hunter/internal/scanner/api"
+	"github.com/buemura/hunter/internal/scanner/cloud"
+	"github.com/buemura/hunter/internal/scanner/csrf"
+	"github.com/buemura/hunter/internal/scanner/custom"
+	"github.com/buemura/hunter/internal/scanner/deserialization"
 	"github.com/buemura/hunter/internal/scanner/dirs"
+	"github.com/buemura/hunter/internal/scanner/forms"
 	"github.com/buemura/hunter/internal/scanner/headers"
+	"github.com/buemura/hunter/internal/scanner/mixedcontent"
 	"github.com/buemura/hunter/internal/scanner/port"
+	"github.com/buemura/hunter/internal/scanner/protocols"
+	"github.com/buemura/hunter/internal/scanner/redirects"
 	"github.com/buemura/hunter/internal/scanner/ssl"
+	"github.com/buemura/hunter/internal/scanner/tech"
 	"github.com/buemura/hunter/internal/scanner/vuln"
 	"github.com/buemura/hunter/internal/web"
+	"github.com/buemura/hunter/internal/web/templates"
 	"github.com/spf13/cobra"
 )
 
-var addrFlag string
+var (
+	addrFlag     string
+	basePathFlag string
+	readOnlyFlag bool
+	grpcAddrFlag string
+	devFlag      bool
+
+	corsOriginsFlag     []string
+	corsCredentialsFlag bool
+)
+
+// devWebDir is where --dev reads templates and static files from, relative
+// to the root of a checkout.
+var devWebDir = filepath.Join("internal", "web")
+
+// configWatchInterval is how often serve checks the config files for changes.
+var configWatchInterval = 2 * time.Second
 
 var serveCmd = &cobra.Command{
 	Use:   "serve",
@@ -25,23 +57,156 @@ var serveCmd = &cobra.Command{
 
 func init() {
 	serveCmd.Flags().StringVar(&addrFlag, "addr", ":3000", "listen address (host:port)")
+	serveCmd.Flags().StringVar(&basePathFlag, "base-path", "", "URL prefix when served behind a reverse proxy, e.g. /hunter")
+	serveCmd.Flags().BoolVar(&readOnlyFlag, "read-only", false, "disable scan creation and deletion")
+	serveCmd.Flags().StringVar(&grpcAddrFlag, "grpc-addr", "", "also serve the gRPC API on this address (host:port); empty disables it")
+	serveCmd.Flags().BoolVar(&devFlag, "dev", false, "serve templates and static files from ./internal/web, reloading them on each request, for UI development")
+	serveCmd.Flags().StringSliceVar(&corsOriginsFlag, "cors-origin", nil, "origin allowed to call the API from a browser, e.g. https://dashboard.example.com, or * for any (repeatable)")
+	serveCmd.Flags().BoolVar(&corsCredentialsFlag, "cors-credentials", false, "let allowed origins send cookies and HTTP authentication")
 	rootCmd.AddCommand(serveCmd)
 }
 
 func runServe(cmd *cobra.Command, args []string) error {
+	reg := webRegistry()
+
+	applyCORSFlags(cmd, appConfig)
+	if detail, _ := validateCORS(appConfig.CORS); detail != "" {
+		return fmt.Errorf("%s", detail)
+	}
+
+	opts := web.Options{
+		BasePath: basePathFlag,
+		ReadOnly: readOnlyFlag,
+		Config:   appConfig,
+	}
+	if devFlag {
+		if err := templates.SetDir(filepath.Join(devWebDir, "templates")); err != nil {
+			return fmt.Errorf("--dev must be run from the root of a Hunter checkout: %w", err)
+		}
+		opts.DevDir = devWebDir
+	}
+	s := web.NewServer(addrFlag, reg, opts)
+
+	paths := []string{config.ConfigFilePath(), config.ProjectConfigFile}
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+	go config.Watch(ctx, paths, configWatchInterval, func() {
+		reloadServeConfig(cmd, s, paths)
+	})
+
+	if grpcAddrFlag != "" {
+		lis, err := net.Listen("tcp", grpcAddrFlag)
+		if err != nil {
+			return fmt.Errorf("gRPC API: %w", err)
+		}
+		g := s.GRPCServer()
+		defer g.Stop()
+		go g.Serve(lis)
+		statusf(cmd, "Hunter gRPC API listening on %s", lis.Addr())
+	}
+
+	statusf(cmd, "Hunter web server listening on %s%s", addrFlag, basePathFlag)
+	if readOnlyFlag {
+		statusf(cmd, "Read-only mode: scan creation and deletion are disabled")
+	}
+	if devFlag {
+		statusf(cmd, "Development mode: serving templates and static files from %s", devWebDir)
+	}
+	if origins := appConfig.CORS.AllowedOrigins; len(origins) > 0 {
+		statusf(cmd, "API accepts cross-origin requests from %s", strings.Join(origins, ", "))
+	}
+	return s.Start()
+}
+
+// webRegistry returns the scanners the web server and its agents run.
+func webRegistry() *scanner.Registry {
 	reg := scanner.NewRegistry()
 
 	reg.Register(port.New())
 	reg.Register(headers.New())
 	reg.Register(ssl.New())
+	reg.Register(protocols.New())
+	reg.Register(cloud.New())
 	reg.Register(dirs.New())
 	reg.Register(vuln.New())
+	reg.Register(forms.New())
+	reg.Register(csrf.New())
+	reg.Register(tech.New())
+	reg.Register(redirects.New())
+	reg.Register(mixedcontent.New())
+	reg.Register(deserialization.New())
+	reg.Register(custom.New())
 	reg.Register(api.New())
 	reg.Register(api.NewAuthScanner())
 	reg.Register(api.NewCORSScanner())
+	reg.Register(api.NewDataExposureScanner())
 	reg.Register(api.NewRateLimitScanner())
+	return reg
+}
 
-	s := web.NewServer(addrFlag, reg)
-	fmt.Fprintf(cmd.OutOrStdout(), "Hunter web server listening on %s\n", addrFlag)
-	return s.Start()
+// reloadServeConfig re-reads the config files and swaps the result into the
+// running server, logging what changed. An invalid config is rejected as a
+// whole and the previous one stays in effect.
+func reloadServeConfig(cmd *cobra.Command, s *web.Server, paths []string) {
+	cfg, err := loadServeConfig(cmd, paths)
+	if err != nil {
+		statusf(cmd, "Config reload rejected, keeping the previous config: %v", err)
+		return
+	}
+
+	changes := config.Diff(s.Config(), cfg)
+	s.SetConfig(cfg)
+	if len(changes) == 0 {
+		statusf(cmd, "Config reloaded, no changes")
+		return
+	}
+	statusf(cmd, "Config reloaded:")
+	for _, c := range changes {
+		statusf(cmd, "  %s", c)
+	}
+}
+
+// loadServeConfig loads and validates the config the same way the root
+// command does at startup, including flag and --env overrides.
+func loadServeConfig(cmd *cobra.Command, paths []string) (*config.Config, error) {
+	cfg, err := config.LoadFiles(paths...)
+	if err != nil {
+		return nil, err
+	}
+	config.ApplyFlags(cfg, cmd)
+	applyCORSFlags(cmd, cfg)
+	if envFlag != "" {
+		env, err := cfg.Environment(envFlag)
+		if err != nil {
+			return nil, err
+		}
+		config.ApplyEnvironment(cfg, env, cmd)
+	}
+
+	if detail, _ := validateConfig(cfg); detail != "" {
+		return nil, fmt.Errorf("%s", detail)
+	}
+	if _, err := scanner.ParseSeverityOverrides(cfg.SeverityOverrides); err != nil {
+		return nil, err
+	}
+	for _, p := range cfg.ScanProfiles {
+		if p.Credential == "" {
+			continue
+		}
+		if _, err := cfg.GetCredential(p.Credential); err != nil {
+			return nil, fmt.Errorf("scan profile %s: %w", p.Name, err)
+		}
+	}
+	return cfg, nil
+}
+
+// applyCORSFlags overrides the config's cors section with the --cors-origin
+// and --cors-credentials flags when they are given.
+func applyCORSFlags(cmd *cobra.Command, cfg *config.Config) {
+	if cmd.Flags().Changed("cors-origin") {
+		cfg.CORS.AllowedOrigins = corsOriginsFlag
+	}
+	if cmd.Flags().Changed("cors-credentials") {
+		cfg.CORS.AllowCredentials = corsCredentialsFlag
+	}
 }
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/scanner/api"
+	"github.com/buemura/hunter/internal/scanner/cloud"
+	"github.com/buemura/hunter/internal/scanner/csrf"
+	"github.com/buemura/hunter/internal/scanner/custom"
+	"github.com/buemura/hunter/internal/scanner/deserialization"
+	"github.com/buemura/hunter/internal/scanner/dirs"
+	"github.com/buemura/hunter/internal/scanner/forms"
+	"github.com/buemura/hunter/internal/scanner/headers"
+	"github.com/buemura/hunter/internal/scanner/mixedcontent"
+	"github.com/buemura/hunter/internal/scanner/port"
+	"github.com/buemura/hunter/internal/scanner/protocols"
+	"github.com/buemura/hunter/internal/scanner/redirects"
+	"github.com/buemura/hunter/internal/scanner/ssl"
+	"github.com/buemura/hunter/internal/scanner/tech"
+	"github.com/buemura/hunter/internal/scanner/vuln"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var verifyFindingFlag string
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify <results.json>",
+	Short: "Check whether a finding from earlier results still reproduces",
+	Long: `Re-issues only the probe that produced one finding of a JSON results file
+(written with -o json, and opened with --report-password if it was written
+with one) and reports whether the finding still reproduces, for
+retesting after a fix. The finding is selected by its fingerprint, or a
+unique prefix of it. Exits non-zero while the finding still reproduces.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runVerify,
+}
+
+func init() {
+	verifyCmd.Flags().StringVar(&verifyFindingFlag, "finding", "", "fingerprint (or unique prefix) of the finding to verify")
+	verifyCmd.MarkFlagRequired("finding")
+	rootCmd.AddCommand(verifyCmd)
+}
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	raw, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("reading results: %w", err)
+	}
+	if raw, err = openSealed(raw); err != nil {
+		return fmt.Errorf("reading results: %w", err)
+	}
+	var results []types.ScanResult
+	if err := json.Unmarshal(raw, &results); err != nil {
+		return fmt.Errorf("reading results: %s is not a JSON results file: %w", args[0], err)
+	}
+
+	result, finding, err := findByFingerprint(results, verifyFindingFlag)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeTarget(result.Target); err != nil {
+		return err
+	}
+
+	reg := scanner.NewRegistry()
+	reg.Register(port.New())
+	reg.Register(headers.New())
+	reg.Register(ssl.New())
+	reg.Register(protocols.New())
+	reg.Register(cloud.New())
+	reg.Register(dirs.New())
+	reg.Register(vuln.New())
+	reg.Register(forms.New())
+	reg.Register(csrf.New())
+	reg.Register(tech.New())
+	reg.Register(redirects.New())
+	reg.Register(mixedcontent.New())
+	reg.Register(deserialization.New())
+	reg.Register(custom.New())
+	reg.Register(api.New())
+	reg.Register(api.NewAuthScanner())
+	reg.Register(api.NewCORSScanner())
+	reg.Register(api.NewDataExposureScanner())
+	reg.Register(api.NewRateLimitScanner())
+
+	runner := scanner.NewRunner(reg)
+	ctx, cancel := context.WithTimeout(context.Background(), timeoutFlag*10)
+	defer cancel()
+
+	fingerprint := types.Fingerprint(result.ScannerName, finding)
+	statusf(cmd, "Verifying %s finding %s: %s", result.ScannerName, fingerprint, finding.Title)
+	reproduces, err := runner.Verify(ctx, result.ScannerName, result.Target, finding, baseOptions(cmd))
+	if err != nil {
+		return fmt.Errorf("verifying finding %s: %w", fingerprint, err)
+	}
+
+	if reproduces {
+		fmt.Fprintf(cmd.OutOrStdout(), "REPRODUCES  %s  %s\n", fingerprint, finding.Title)
+		return fmt.Errorf("finding %s still reproduces", fingerprint)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "FIXED       %s  %s\n", fingerprint, finding.Title)
+	return nil
+}
+
+// findByFingerprint returns the finding whose fingerprint starts with
+// prefix, and the result it belongs to. The prefix must match exactly one
+// finding.
+func findByFingerprint(results []types.ScanResult, prefix string) (types.ScanResult, types.Finding, error) {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if prefix == "" {
+		return types.ScanResult{}, types.Finding{}, fmt.Errorf("--finding is required")
+	}
+
+	// Identical findings share a fingerprint and count as one match.
+	matched := map[string]bool{}
+	var (
+		result  types.ScanResult
+		finding types.Finding
+	)
+	for _, r := range results {
+		for _, f := range r.Findings {
+			fp := types.Fingerprint(r.ScannerName, f)
+			if strings.HasPrefix(fp, prefix) && !matched[fp] {
+				matched[fp] = true
+				result, finding = r, f
+			}
+		}
+	}
+
+	switch len(matched) {
+	case 0:
+		return types.ScanResult{}, types.Finding{}, fmt.Errorf("no finding with fingerprint %q", prefix)
+	case 1:
+		return result, finding, nil
+	default:
+		return types.ScanResult{}, types.Finding{}, fmt.Errorf("fingerprint %q matches %d findings; give more characters", prefix, len(matched))
+	}
+}
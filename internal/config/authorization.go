@@ -0,0 +1,95 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// ErrUnauthorizedTarget reports a target outside authorized_targets.
+var ErrUnauthorizedTarget = errors.New("not in authorized_targets")
+
+// AuthorizeTarget returns an error wrapping ErrUnauthorizedTarget when
+// authorized_targets is set and target's host matches none of its entries.
+// Without authorized_targets, every target is allowed.
+func (c *Config) AuthorizeTarget(target types.Target) error {
+	if c == nil || len(c.AuthorizedTargets) == 0 {
+		return nil
+	}
+	host := normalizeHost(target.Host)
+	for _, entry := range c.AuthorizedTargets {
+		if matchAuthorized(strings.ToLower(strings.TrimSpace(entry)), host) {
+			return nil
+		}
+	}
+	return fmt.Errorf("target %s is %w", target.Host, ErrUnauthorizedTarget)
+}
+
+// matchAuthorized reports whether host matches an authorized_targets entry:
+// an IP address or CIDR range, a host name, or "*." and a domain, which
+// matches every name under the domain but not the domain itself. Host names
+// are not resolved, so a name is only allowed when it is listed by name.
+func matchAuthorized(entry, host string) bool {
+	ip := net.ParseIP(host)
+	if _, network, err := net.ParseCIDR(entry); err == nil {
+		return ip != nil && network.Contains(ip)
+	}
+	if entryIP := net.ParseIP(entry); entryIP != nil {
+		return ip != nil && entryIP.Equal(ip)
+	}
+	if ip != nil {
+		return false
+	}
+	if domain, ok := strings.CutPrefix(entry, "*."); ok {
+		return strings.HasSuffix(host, "."+normalizeHost(domain))
+	}
+	return host == normalizeHost(entry)
+}
+
+// ValidateAuthorizedTargets checks that every entry of authorized_targets
+// is an IP address, a CIDR range, a host name, or "*." and a domain.
+func ValidateAuthorizedTargets(entries []string) error {
+	for _, entry := range entries {
+		e := strings.TrimSpace(entry)
+		if strings.Contains(e, "/") {
+			if _, _, err := net.ParseCIDR(e); err != nil {
+				return fmt.Errorf("%q is not a valid CIDR range", entry)
+			}
+			continue
+		}
+		if net.ParseIP(e) != nil {
+			continue
+		}
+		if !validHostName(strings.TrimPrefix(e, "*.")) {
+			return fmt.Errorf("%q is not an IP address, CIDR range, or host name", entry)
+		}
+	}
+	return nil
+}
+
+// validHostName reports whether s is a host name: dot-separated labels of
+// letters, digits, and hyphens.
+func validHostName(s string) bool {
+	s = strings.TrimSuffix(s, ".")
+	if s == "" {
+		return false
+	}
+	for _, label := range strings.Split(s, ".") {
+		if label == "" || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func normalizeHost(host string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.Trim(host, "[]")), ".")
+}
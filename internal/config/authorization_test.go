@@ -0,0 +1,34 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorizeTarget(t *testing.T) {
+	cfg := &Config{AuthorizedTargets: []string{"example.com", "*.staging.example.com", "10.0.0.0/24", "192.0.2.7", "2001:db8::/32"}}
+
+	for _, host := range []string{"example.com", "EXAMPLE.com.", "api.staging.example.com", "10.0.0.42", "192.0.2.7", "2001:db8::1"} {
+		assert.NoError(t, cfg.AuthorizeTarget(types.Target{Host: host}), host)
+	}
+	for _, host := range []string{"www.example.com", "staging.example.com", "evilexample.com", "10.0.1.1", "192.0.2.8", "2001:db9::1"} {
+		err := cfg.AuthorizeTarget(types.Target{Host: host})
+		assert.True(t, errors.Is(err, ErrUnauthorizedTarget), host)
+	}
+
+	// Without an allowlist, every target is allowed.
+	assert.NoError(t, (&Config{}).AuthorizeTarget(types.Target{Host: "example.org"}))
+	var none *Config
+	assert.NoError(t, none.AuthorizeTarget(types.Target{Host: "example.org"}))
+}
+
+func TestValidateAuthorizedTargets(t *testing.T) {
+	assert.NoError(t, ValidateAuthorizedTargets([]string{"example.com", "*.example.com", "10.0.0.0/8", "::1", "localhost"}))
+
+	for _, entry := range []string{"https://example.com", "10.0.0.0/33", "example.com:443", "*.", "exa mple.com"} {
+		assert.Error(t, ValidateAuthorizedTargets([]string{entry}), entry)
+	}
+}
@@ -1,6 +1,7 @@
 // Package config provides configuration loading for Hunter.
 // It supports a layered configuration approach with priority:
-// CLI flags > environment variables (HUNTER_*) > config file (~/.hunter.yaml).
+// CLI flags > environment variables (HUNTER_*) > project file (./hunter.yaml)
+// > home config file (~/.hunter.yaml).
 package config
 
 import (
@@ -9,6 +10,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/buemura/hunter/pkg/types"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -17,6 +19,15 @@ import (
 type ScanProfile struct {
 	Name     string   `mapstructure:"name" yaml:"name"`
 	Scanners []string `mapstructure:"scanners" yaml:"scanners"`
+	// Credential names an entry in the credentials section to scan with.
+	Credential string `mapstructure:"credential" yaml:"credential,omitempty"`
+}
+
+// SavedTarget is a named target offered by the interactive mode's target
+// picker.
+type SavedTarget struct {
+	Name   string `mapstructure:"name" yaml:"name"`
+	Target string `mapstructure:"target" yaml:"target"`
 }
 
 // Config holds all Hunter configuration options.
@@ -27,6 +38,133 @@ type Config struct {
 	Timeout       time.Duration `mapstructure:"timeout" yaml:"timeout"`
 	WordlistPath  string        `mapstructure:"wordlist_path" yaml:"wordlist_path"`
 	ScanProfiles  []ScanProfile `mapstructure:"scan_profiles" yaml:"scan_profiles"`
+	SavedTargets  []SavedTarget `mapstructure:"saved_targets" yaml:"saved_targets"`
+
+	// Intensity is safe, normal (the default), or aggressive: how many
+	// requests, paths, payloads, and login attempts scanners send.
+	Intensity string `mapstructure:"intensity" yaml:"intensity,omitempty"`
+
+	// ScanWindow is the daily period scans may run in, such as
+	// "22:00-06:00 Europe/Berlin"; outside it they wait, or pause. Empty
+	// allows any time.
+	ScanWindow string `mapstructure:"scan_window" yaml:"scan_window,omitempty"`
+
+	// Lang is the language of reports, such as pt-BR; empty means English.
+	Lang string `mapstructure:"lang" yaml:"lang,omitempty"`
+
+	// EncryptionKey, when set, encrypts the scan history at rest. Like the
+	// secrets of credentials, it may be an env: or keyring: reference.
+	EncryptionKey string `mapstructure:"encryption_key" yaml:"encryption_key,omitempty"`
+
+	// AgentToken authenticates the agents of `hunter agent` to `hunter
+	// serve`, which accepts no agents without one. Both ends read it, and
+	// like encryption_key it may be an env: or keyring: reference.
+	AgentToken string `mapstructure:"agent_token" yaml:"agent_token,omitempty"`
+
+	// Engagement identifies the engagement scans are run under: client,
+	// id, tester, authorization, and notes. Reports carry it.
+	Engagement types.Engagement `mapstructure:"engagement" yaml:"engagement,omitempty"`
+
+	// AuthorizedTargets, when set, lists the only targets scans may run
+	// against: IP addresses, CIDR ranges, host names, and "*." domains.
+	// Others are refused unless --i-am-authorized is passed.
+	AuthorizedTargets []string `mapstructure:"authorized_targets" yaml:"authorized_targets,omitempty"`
+
+	// Scanners holds per-scanner defaults keyed by scanner name, e.g.
+	// scanners.port.ports or scanners.ratelimit.requests. They are passed to
+	// scanners as ExtraArgs unless overridden by an explicit CLI flag.
+	Scanners map[string]map[string]interface{} `mapstructure:"scanners" yaml:"scanners"`
+
+	// SeverityOverrides remaps finding severities by title (wildcards
+	// allowed), e.g. "Missing Content-Security-Policy": HIGH. IGNORE drops
+	// matching findings.
+	SeverityOverrides map[string]string `mapstructure:"severity_overrides" yaml:"severity_overrides"`
+
+	// Environments holds per-tier defaults selected with --env. The prod,
+	// staging, and dev tiers have built-in presets these entries overlay.
+	Environments map[string]Environment `mapstructure:"environments" yaml:"environments"`
+
+	// Credentials holds named credentials for authenticated scanning,
+	// selected with --credential or a scan profile's credential field.
+	Credentials map[string]Credential `mapstructure:"credentials" yaml:"credentials"`
+
+	// PassiveDNS lists passive DNS providers the subdomain and ssl scanners
+	// query alongside crt.sh.
+	PassiveDNS []PassiveDNSProvider `mapstructure:"passive_dns" yaml:"passive_dns,omitempty"`
+
+	// DataDir is where `hunter data update` installs data sets
+	// (default ~/.hunter/data). DataSource is the base URL to download them
+	// from, and DataPins fixes data sets to specific versions.
+	DataDir    string            `mapstructure:"data_dir" yaml:"data_dir"`
+	DataSource string            `mapstructure:"data_source" yaml:"data_source"`
+	DataPins   map[string]string `mapstructure:"data_pins" yaml:"data_pins"`
+
+	// Cache reuses scanner results across repeat scans of a target.
+	Cache Cache `mapstructure:"cache" yaml:"cache,omitempty"`
+
+	// Retention limits how many finished scans `hunter serve` keeps in
+	// memory. Zero values keep everything.
+	Retention Retention `mapstructure:"retention" yaml:"retention"`
+
+	// SLA is how many days findings of each severity may stay open before
+	// `hunter serve` flags them as overdue, e.g. critical: 7. Severities
+	// left out have no deadline.
+	SLA map[string]int `mapstructure:"sla" yaml:"sla,omitempty"`
+
+	// CORS lets browser frontends on other origins call the `hunter serve`
+	// REST API.
+	CORS CORS `mapstructure:"cors" yaml:"cors,omitempty"`
+
+	// TUI holds settings for `hunter interactive`.
+	TUI TUI `mapstructure:"tui" yaml:"tui"`
+
+	// SecurityTxt holds the fields `hunter scaffold security-txt` puts in
+	// the security.txt file it generates.
+	SecurityTxt SecurityTxt `mapstructure:"security_txt" yaml:"security_txt,omitempty"`
+}
+
+// TUI configures the interactive mode.
+type TUI struct {
+	// Theme is dark (the default), light, high-contrast, or custom, which
+	// starts from dark and takes every color from Colors.
+	Theme string `mapstructure:"theme" yaml:"theme,omitempty"`
+	// Colors overrides individual theme colors by name, e.g.
+	// critical: "#FF00FF" or accent: "63".
+	Colors map[string]string `mapstructure:"colors" yaml:"colors,omitempty"`
+	// Keybindings replaces the keys of actions by name, e.g.
+	// quit: ["q", "ctrl+q"]. Press ? in the interactive mode to list them.
+	Keybindings map[string][]string `mapstructure:"keybindings" yaml:"keybindings,omitempty"`
+}
+
+// Cache configures the scanner results cache of CLI scans.
+type Cache struct {
+	// Enabled reuses the result of a scanner that ran against the same
+	// target with the same settings within TTL; --cache and --no-cache
+	// override it.
+	Enabled bool `mapstructure:"enabled" yaml:"enabled,omitempty"`
+	// TTL is how long results are reused; zero means an hour.
+	TTL time.Duration `mapstructure:"ttl" yaml:"ttl,omitempty"`
+	// Dir is where results are stored (default ~/.hunter/cache).
+	Dir string `mapstructure:"dir" yaml:"dir,omitempty"`
+}
+
+// Retention bounds the web server's finished scan history.
+type Retention struct {
+	// MaxJobs keeps at most this many finished scans, dropping the oldest.
+	MaxJobs int `mapstructure:"max_jobs" yaml:"max_jobs,omitempty"`
+	// MaxAge drops finished scans older than this.
+	MaxAge time.Duration `mapstructure:"max_age" yaml:"max_age,omitempty"`
+}
+
+// CORS configures the cross-origin requests the web server's API accepts.
+type CORS struct {
+	// AllowedOrigins lists the origins allowed to call the API, e.g.
+	// https://dashboard.example.com, or "*" for any. Empty sends no CORS
+	// headers, so browsers only allow same-origin calls.
+	AllowedOrigins []string `mapstructure:"allowed_origins" yaml:"allowed_origins,omitempty"`
+	// AllowCredentials lets allowed origins send cookies and HTTP
+	// authentication with their requests. It cannot be combined with "*".
+	AllowCredentials bool `mapstructure:"allow_credentials" yaml:"allow_credentials,omitempty"`
 }
 
 // Defaults returns a Config populated with default values.
@@ -38,7 +176,12 @@ func Defaults() Config {
 	}
 }
 
-// Load reads configuration from ~/.hunter.yaml and environment variables.
+// ProjectConfigFile is the name of the project-local config file looked up in
+// the working directory.
+const ProjectConfigFile = "hunter.yaml"
+
+// Load reads configuration from ~/.hunter.yaml, overlays ./hunter.yaml when
+// present, and applies environment variables.
 // It does NOT apply CLI flag overrides — call ApplyFlags for that.
 func Load() (*Config, error) {
 	v := viper.New()
@@ -61,6 +204,56 @@ func Load() (*Config, error) {
 		}
 	}
 
+	if path := ProjectConfigPath(); path != "" {
+		v.SetConfigFile(path)
+		if err := v.MergeInConfig(); err != nil {
+			return nil, fmt.Errorf("reading project config file: %w", err)
+		}
+	}
+
+	cfg := Defaults()
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("unmarshaling config: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+// ProjectConfigPath returns the path of ./hunter.yaml if it exists in the
+// working directory, or "" otherwise.
+func ProjectConfigPath() string {
+	info, err := os.Stat(ProjectConfigFile)
+	if err != nil || info.IsDir() {
+		return ""
+	}
+	abs, err := filepath.Abs(ProjectConfigFile)
+	if err != nil {
+		return ProjectConfigFile
+	}
+	return abs
+}
+
+// LoadFiles reads and merges the given config files in order, later files
+// overriding earlier ones, then applies environment variables. Paths that do
+// not exist are skipped.
+func LoadFiles(paths ...string) (*Config, error) {
+	v := viper.New()
+	setDefaults(v)
+	v.SetConfigType("yaml")
+
+	v.SetEnvPrefix("HUNTER")
+	v.AutomaticEnv()
+
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		v.SetConfigFile(path)
+		if err := v.MergeInConfig(); err != nil {
+			return nil, fmt.Errorf("reading config file %s: %w", path, err)
+		}
+	}
+
 	cfg := Defaults()
 	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("unmarshaling config: %w", err)
@@ -111,6 +304,38 @@ func ApplyFlags(cfg *Config, cmd *cobra.Command) {
 		val, _ := flags.GetDuration("timeout")
 		cfg.Timeout = val
 	}
+	if flags.Changed("intensity") {
+		val, _ := flags.GetString("intensity")
+		cfg.Intensity = val
+	}
+	if flags.Changed("window") {
+		val, _ := flags.GetString("window")
+		cfg.ScanWindow = val
+	}
+	if flags.Changed("lang") {
+		val, _ := flags.GetString("lang")
+		cfg.Lang = val
+	}
+	if flags.Changed("cache") {
+		val, _ := flags.GetBool("cache")
+		cfg.Cache.Enabled = val
+	}
+	if flags.Changed("no-cache") {
+		if val, _ := flags.GetBool("no-cache"); val {
+			cfg.Cache.Enabled = false
+		}
+	}
+	for flag, field := range map[string]*string{
+		"client":            &cfg.Engagement.Client,
+		"engagement-id":     &cfg.Engagement.ID,
+		"tester":            &cfg.Engagement.Tester,
+		"authorization-ref": &cfg.Engagement.Authorization,
+		"notes":             &cfg.Engagement.Notes,
+	} {
+		if flags.Changed(flag) {
+			*field, _ = flags.GetString(flag)
+		}
+	}
 }
 
 // GetProfile returns the scan profile with the given name, or nil if not found.
@@ -132,8 +357,38 @@ func ConfigFilePath() string {
 	return filepath.Join(home, ".hunter.yaml")
 }
 
+// SaveDefaultTarget writes default_target to the config file at path, keeping
+// any other settings already stored there. The file is created if missing.
+func SaveDefaultTarget(path, target string) error {
+	return saveSetting(path, "default_target", target)
+}
+
+// SaveTheme writes tui.theme to the config file at path, like
+// SaveDefaultTarget.
+func SaveTheme(path, theme string) error {
+	return saveSetting(path, "tui.theme", theme)
+}
+
+func saveSetting(path, key string, value interface{}) error {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+
+	if err := v.ReadInConfig(); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	v.Set(key, value)
+	if err := v.WriteConfigAs(path); err != nil {
+		return fmt.Errorf("writing config file: %w", err)
+	}
+	return nil
+}
+
 func setDefaults(v *viper.Viper) {
 	v.SetDefault("output_format", "table")
 	v.SetDefault("concurrency", 10)
 	v.SetDefault("timeout", 5*time.Second)
+	v.SetDefault("lang", "")
+	v.SetDefault("encryption_key", "")
 }
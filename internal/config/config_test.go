@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/buemura/hunter/pkg/types"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -97,12 +98,16 @@ func TestLoadFromFile_InvalidYAML(t *testing.T) {
 func TestLoad_EnvVarOverrides(t *testing.T) {
 	t.Setenv("HUNTER_CONCURRENCY", "50")
 	t.Setenv("HUNTER_OUTPUT_FORMAT", "json")
+	t.Setenv("HUNTER_LANG", "pt-BR")
+	t.Setenv("HUNTER_ENCRYPTION_KEY", "keyring:hunter/history")
 
 	cfg, err := Load()
 	require.NoError(t, err)
 
 	assert.Equal(t, 50, cfg.Concurrency)
 	assert.Equal(t, "json", cfg.OutputFormat)
+	assert.Equal(t, "pt-BR", cfg.Lang)
+	assert.Equal(t, "keyring:hunter/history", cfg.EncryptionKey)
 }
 
 func TestApplyFlags(t *testing.T) {
@@ -113,15 +118,23 @@ func TestApplyFlags(t *testing.T) {
 	cmd.Flags().String("output", "table", "")
 	cmd.Flags().Int("concurrency", 10, "")
 	cmd.Flags().Duration("timeout", 5*time.Second, "")
+	cmd.Flags().String("lang", "", "")
+	cmd.Flags().String("client", "", "")
+	cmd.Flags().String("tester", "", "")
 
 	// Simulate setting flags via command line.
 	err := cmd.Flags().Set("target", "https://test.com")
 	require.NoError(t, err)
 	err = cmd.Flags().Set("concurrency", "25")
 	require.NoError(t, err)
+	require.NoError(t, cmd.Flags().Set("lang", "pt-BR"))
+	require.NoError(t, cmd.Flags().Set("client", "ACME Corp"))
 
 	ApplyFlags(&cfg, cmd)
 
+	assert.Equal(t, types.Engagement{Client: "ACME Corp"}, cfg.Engagement)
+
+	assert.Equal(t, "pt-BR", cfg.Lang)
 	assert.Equal(t, "https://test.com", cfg.DefaultTarget)
 	assert.Equal(t, "table", cfg.OutputFormat) // Not changed — flag wasn't set.
 	assert.Equal(t, 25, cfg.Concurrency)
@@ -151,6 +164,28 @@ func TestApplyFlags_NoOverrideWhenUnchanged(t *testing.T) {
 	assert.Equal(t, 15*time.Second, cfg.Timeout)
 }
 
+func TestApplyFlags_Cache(t *testing.T) {
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{Use: "test"}
+		cmd.Flags().Bool("cache", false, "")
+		cmd.Flags().Bool("no-cache", false, "")
+		return cmd
+	}
+
+	cfg := Defaults()
+	cmd := newCmd()
+	require.NoError(t, cmd.Flags().Set("cache", "true"))
+	ApplyFlags(&cfg, cmd)
+	assert.True(t, cfg.Cache.Enabled)
+
+	cfg.Cache.TTL = 10 * time.Minute
+	cmd = newCmd()
+	require.NoError(t, cmd.Flags().Set("no-cache", "true"))
+	ApplyFlags(&cfg, cmd)
+	assert.False(t, cfg.Cache.Enabled)
+	assert.Equal(t, 10*time.Minute, cfg.Cache.TTL)
+}
+
 func TestGetProfile(t *testing.T) {
 	cfg := &Config{
 		ScanProfiles: []ScanProfile{
@@ -195,3 +230,122 @@ func TestLoadFromFile_PartialConfig(t *testing.T) {
 	assert.Equal(t, "table", cfg.OutputFormat)
 	assert.Equal(t, 5*time.Second, cfg.Timeout)
 }
+
+func TestSaveDefaultTarget_NewFile(t *testing.T) {
+	cfgFile := filepath.Join(t.TempDir(), ".hunter.yaml")
+
+	require.NoError(t, SaveDefaultTarget(cfgFile, "https://example.com"))
+
+	cfg, err := LoadFromFile(cfgFile)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", cfg.DefaultTarget)
+}
+
+func TestSaveDefaultTarget_KeepsExistingSettings(t *testing.T) {
+	cfgFile := filepath.Join(t.TempDir(), ".hunter.yaml")
+	err := os.WriteFile(cfgFile, []byte("concurrency: 42\ndefault_target: old.example.com\n"), 0644)
+	require.NoError(t, err)
+
+	require.NoError(t, SaveDefaultTarget(cfgFile, "https://new.example.com"))
+
+	cfg, err := LoadFromFile(cfgFile)
+	require.NoError(t, err)
+	assert.Equal(t, "https://new.example.com", cfg.DefaultTarget)
+	assert.Equal(t, 42, cfg.Concurrency)
+}
+
+func TestSaveTheme_KeepsColors(t *testing.T) {
+	cfgFile := filepath.Join(t.TempDir(), ".hunter.yaml")
+	err := os.WriteFile(cfgFile, []byte("tui:\n  theme: dark\n  colors:\n    critical: \"#FF00FF\"\n"), 0644)
+	require.NoError(t, err)
+
+	require.NoError(t, SaveTheme(cfgFile, "light"))
+
+	cfg, err := LoadFromFile(cfgFile)
+	require.NoError(t, err)
+	assert.Equal(t, "light", cfg.TUI.Theme)
+	assert.Equal(t, "#FF00FF", cfg.TUI.Colors["critical"])
+}
+
+func TestLoadFromFile_ScannerSections(t *testing.T) {
+	dir := t.TempDir()
+	cfgFile := filepath.Join(dir, ".hunter.yaml")
+
+	content := `scanners:
+  port:
+    ports: 1-1024
+  dirs:
+    wordlist: /opt/wordlists/common.txt
+  vuln:
+    checks: [xss, sqli]
+  ratelimit:
+    requests: 100
+`
+	require.NoError(t, os.WriteFile(cfgFile, []byte(content), 0644))
+
+	cfg, err := LoadFromFile(cfgFile)
+	require.NoError(t, err)
+
+	assert.Equal(t, "1-1024", cfg.Scanners["port"]["ports"])
+	assert.Equal(t, "/opt/wordlists/common.txt", cfg.Scanners["dirs"]["wordlist"])
+	assert.Equal(t, []interface{}{"xss", "sqli"}, cfg.Scanners["vuln"]["checks"])
+	assert.Equal(t, 100, cfg.Scanners["ratelimit"]["requests"])
+}
+
+func TestLoad_ProjectFileOverlaysHome(t *testing.T) {
+	for _, key := range []string{"HUNTER_DEFAULT_TARGET", "HUNTER_OUTPUT_FORMAT", "HUNTER_CONCURRENCY", "HUNTER_TIMEOUT", "HUNTER_WORDLIST_PATH"} {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".hunter.yaml"), []byte(`output_format: json
+concurrency: 20
+default_target: https://home.example.com
+`), 0644))
+
+	project := t.TempDir()
+	t.Chdir(project)
+	require.NoError(t, os.WriteFile(filepath.Join(project, ProjectConfigFile), []byte(`default_target: https://project.example.com
+scan_profiles:
+  - name: ci
+    scanners: [headers]
+`), 0644))
+
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://project.example.com", cfg.DefaultTarget, "project file wins over home file")
+	assert.Equal(t, "json", cfg.OutputFormat, "home settings not in the project file are kept")
+	assert.Equal(t, 20, cfg.Concurrency)
+	require.NotNil(t, cfg.GetProfile("ci"))
+
+	t.Setenv("HUNTER_DEFAULT_TARGET", "https://env.example.com")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "https://env.example.com", cfg.DefaultTarget, "environment wins over project file")
+}
+
+func TestLoad_InvalidProjectFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	project := t.TempDir()
+	t.Chdir(project)
+	require.NoError(t, os.WriteFile(filepath.Join(project, ProjectConfigFile), []byte("{{invalid"), 0644))
+
+	_, err := Load()
+	assert.ErrorContains(t, err, "project config")
+}
+
+func TestLoadFiles_SkipsMissing(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "a.yaml")
+	second := filepath.Join(dir, "b.yaml")
+	require.NoError(t, os.WriteFile(first, []byte("concurrency: 5\ntimeout: 2s\n"), 0644))
+	require.NoError(t, os.WriteFile(second, []byte("concurrency: 7\n"), 0644))
+
+	cfg, err := LoadFiles(first, filepath.Join(dir, "missing.yaml"), second)
+	require.NoError(t, err)
+	assert.Equal(t, 7, cfg.Concurrency)
+	assert.Equal(t, 2*time.Second, cfg.Timeout)
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Credential is a named set of authentication details used for
+// authenticated scanning. Secret fields (Password, Token) may hold a
+// reference instead of the value itself:
+//
+//	env:NAME                  read from the environment variable NAME
+//	keyring:service/account   read from the OS keyring
+//
+// Anything else is used literally.
+type Credential struct {
+	// Type is "basic", "bearer", or "login".
+	Type     string `mapstructure:"type" yaml:"type"`
+	Username string `mapstructure:"username" yaml:"username,omitempty"`
+	Password string `mapstructure:"password" yaml:"password,omitempty"`
+	Token    string `mapstructure:"token" yaml:"token,omitempty"`
+
+	// Header overrides the header a bearer token is sent in. When set, the
+	// token is sent as-is rather than with a "Bearer " prefix.
+	Header string `mapstructure:"header" yaml:"header,omitempty"`
+
+	// LoginURL, UsernameField, PasswordField, and TokenField describe a
+	// JSON login endpoint for the "login" type.
+	LoginURL      string `mapstructure:"login_url" yaml:"login_url,omitempty"`
+	UsernameField string `mapstructure:"username_field" yaml:"username_field,omitempty"`
+	PasswordField string `mapstructure:"password_field" yaml:"password_field,omitempty"`
+	TokenField    string `mapstructure:"token_field" yaml:"token_field,omitempty"`
+}
+
+// GetCredential returns the credential with the given name.
+func (c *Config) GetCredential(name string) (Credential, error) {
+	cred, ok := c.Credentials[name]
+	if !ok {
+		return Credential{}, fmt.Errorf("credential %q is not defined in the credentials section", name)
+	}
+	switch cred.Type {
+	case "basic", "bearer", "login":
+	default:
+		return Credential{}, fmt.Errorf("credential %q has unknown type %q (supported: basic, bearer, login)", name, cred.Type)
+	}
+	return cred, nil
+}
+
+// keyringLookup reads a secret from the OS keyring. Extracted as a variable
+// for testing.
+var keyringLookup = lookupKeyring
+
+// ResolveSecret returns the value a secret field refers to.
+func ResolveSecret(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, "env:"):
+		name := strings.TrimPrefix(value, "env:")
+		secret, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return secret, nil
+	case strings.HasPrefix(value, "keyring:"):
+		service, account, ok := strings.Cut(strings.TrimPrefix(value, "keyring:"), "/")
+		if !ok || service == "" || account == "" {
+			return "", fmt.Errorf("keyring reference %q must be keyring:service/account", value)
+		}
+		return keyringLookup(service, account)
+	}
+	return value, nil
+}
+
+// lookupKeyring shells out to the platform keyring tool: security(1) on
+// macOS and secret-tool(1) (libsecret) elsewhere.
+func lookupKeyring(service, account string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w")
+	case "windows":
+		return "", fmt.Errorf("keyring references are not supported on Windows; use env: instead")
+	default:
+		cmd = exec.Command("secret-tool", "lookup", "service", service, "account", account)
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("reading %s/%s from keyring: %w", service, account, err)
+	}
+	secret := strings.TrimRight(string(out), "\r\n")
+	if secret == "" {
+		return "", fmt.Errorf("keyring entry %s/%s not found", service, account)
+	}
+	return secret, nil
+}
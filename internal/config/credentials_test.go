@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFromFile_Credentials(t *testing.T) {
+	cfgFile := filepath.Join(t.TempDir(), ".hunter.yaml")
+	content := `credentials:
+  staging:
+    type: basic
+    username: admin
+    password: env:STAGING_PASSWORD
+  app:
+    type: login
+    login_url: https://app.example.com/api/login
+    username: me@example.com
+    password: keyring:hunter/app
+    token_field: access_token
+scan_profiles:
+  - name: authed
+    scanners: [headers, vuln]
+    credential: staging
+`
+	require.NoError(t, os.WriteFile(cfgFile, []byte(content), 0644))
+
+	cfg, err := LoadFromFile(cfgFile)
+	require.NoError(t, err)
+
+	cred, err := cfg.GetCredential("staging")
+	require.NoError(t, err)
+	assert.Equal(t, "basic", cred.Type)
+	assert.Equal(t, "env:STAGING_PASSWORD", cred.Password)
+
+	cred, err = cfg.GetCredential("app")
+	require.NoError(t, err)
+	assert.Equal(t, "https://app.example.com/api/login", cred.LoginURL)
+	assert.Equal(t, "access_token", cred.TokenField)
+
+	assert.Equal(t, "staging", cfg.GetProfile("authed").Credential)
+}
+
+func TestGetCredential_Errors(t *testing.T) {
+	cfg := &Config{Credentials: map[string]Credential{"odd": {Type: "digest"}}}
+
+	_, err := cfg.GetCredential("missing")
+	assert.ErrorContains(t, err, "not defined")
+
+	_, err = cfg.GetCredential("odd")
+	assert.ErrorContains(t, err, "unknown type")
+}
+
+func TestResolveSecret(t *testing.T) {
+	t.Setenv("HUNTER_TEST_SECRET", "from-env")
+
+	v, err := ResolveSecret("literal")
+	require.NoError(t, err)
+	assert.Equal(t, "literal", v)
+
+	v, err = ResolveSecret("env:HUNTER_TEST_SECRET")
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", v)
+
+	_, err = ResolveSecret("env:HUNTER_TEST_DEFINITELY_UNSET")
+	assert.Error(t, err)
+
+	_, err = ResolveSecret("keyring:no-account")
+	assert.ErrorContains(t, err, "service/account")
+}
+
+func TestResolveSecret_Keyring(t *testing.T) {
+	orig := keyringLookup
+	defer func() { keyringLookup = orig }()
+
+	keyringLookup = func(service, account string) (string, error) {
+		if service == "hunter" && account == "app" {
+			return "from-keyring", nil
+		}
+		return "", fmt.Errorf("not found")
+	}
+
+	v, err := ResolveSecret("keyring:hunter/app")
+	require.NoError(t, err)
+	assert.Equal(t, "from-keyring", v)
+
+	_, err = ResolveSecret("keyring:hunter/other")
+	assert.Error(t, err)
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Diff describes the settings that differ between old and updated, one line per
+// change, for logging a config reload. Map sections such as credentials are
+// compared key by key and report only the key, never the value, so secrets
+// stay out of logs.
+func Diff(old, updated *Config) []string {
+	var changes []string
+
+	ov := reflect.ValueOf(*old)
+	nv := reflect.ValueOf(*updated)
+	t := ov.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+		a, b := ov.Field(i).Interface(), nv.Field(i).Interface()
+		if reflect.DeepEqual(a, b) {
+			continue
+		}
+
+		if secretSettings[name] {
+			changes = append(changes, name+" changed")
+			continue
+		}
+
+		switch ov.Field(i).Kind() {
+		case reflect.Map:
+			changes = append(changes, diffMap(name, ov.Field(i), nv.Field(i))...)
+		case reflect.Slice:
+			if name == "scan_profiles" {
+				changes = append(changes, diffMap(name, profileMap(old.ScanProfiles), profileMap(updated.ScanProfiles))...)
+				continue
+			}
+			changes = append(changes, name+" changed")
+		case reflect.Struct:
+			changes = append(changes, fmt.Sprintf("%s: %+v -> %+v", name, a, b))
+		default:
+			changes = append(changes, fmt.Sprintf("%s: %v -> %v", name, a, b))
+		}
+	}
+	return changes
+}
+
+// secretSettings are settings whose values never appear in a diff.
+var secretSettings = map[string]bool{
+	"encryption_key": true,
+	"agent_token":    true,
+}
+
+func profileMap(profiles []ScanProfile) reflect.Value {
+	m := make(map[string]ScanProfile, len(profiles))
+	for _, p := range profiles {
+		m[p.Name] = p
+	}
+	return reflect.ValueOf(m)
+}
+
+// diffMap reports added, removed, and changed keys between two maps with
+// string keys.
+func diffMap(section string, old, updated reflect.Value) []string {
+	keys := map[string]bool{}
+	for _, k := range old.MapKeys() {
+		keys[k.String()] = true
+	}
+	for _, k := range updated.MapKeys() {
+		keys[k.String()] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var changes []string
+	for _, k := range sorted {
+		key := reflect.ValueOf(k)
+		a, b := old.MapIndex(key), updated.MapIndex(key)
+		switch {
+		case !a.IsValid():
+			changes = append(changes, fmt.Sprintf("%s.%s added", section, k))
+		case !b.IsValid():
+			changes = append(changes, fmt.Sprintf("%s.%s removed", section, k))
+		case !reflect.DeepEqual(a.Interface(), b.Interface()):
+			changes = append(changes, fmt.Sprintf("%s.%s changed", section, k))
+		}
+	}
+	return changes
+}
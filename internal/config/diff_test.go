@@ -0,0 +1,43 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	old := Defaults()
+	old.ScanProfiles = []ScanProfile{{Name: "quick", Scanners: []string{"headers"}}}
+	old.Credentials = map[string]Credential{"staging": {Type: "bearer", Token: "old-secret"}}
+
+	updated := old
+	updated.Concurrency = 20
+	updated.ScanProfiles = []ScanProfile{
+		{Name: "quick", Scanners: []string{"headers", "ssl"}},
+		{Name: "full", Scanners: []string{"port"}},
+	}
+	updated.Credentials = map[string]Credential{"staging": {Type: "bearer", Token: "new-secret"}}
+	updated.Retention = Retention{MaxJobs: 50, MaxAge: time.Hour}
+	updated.AgentToken = "agent-secret"
+
+	changes := Diff(&old, &updated)
+	assert.Equal(t, []string{
+		"concurrency: 10 -> 20",
+		"scan_profiles.full added",
+		"scan_profiles.quick changed",
+		"agent_token changed",
+		"credentials.staging changed",
+		"retention: {MaxJobs:0 MaxAge:0s} -> {MaxJobs:50 MaxAge:1h0m0s}",
+	}, changes)
+
+	for _, c := range changes {
+		assert.NotContains(t, c, "secret")
+	}
+}
+
+func TestDiffNoChanges(t *testing.T) {
+	a, b := Defaults(), Defaults()
+	assert.Empty(t, Diff(&a, &b))
+}
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Environment holds scan defaults for a deployment tier such as prod or
+// staging, selected with --env. Zero values leave the base setting alone.
+type Environment struct {
+	Target      string        `mapstructure:"target" yaml:"target,omitempty"`
+	Concurrency int           `mapstructure:"concurrency" yaml:"concurrency,omitempty"`
+	Timeout     time.Duration `mapstructure:"timeout" yaml:"timeout,omitempty"`
+
+	// RateLimit caps HTTP requests per second across all scanners; 0 means
+	// unlimited.
+	RateLimit float64 `mapstructure:"rate_limit" yaml:"rate_limit,omitempty"`
+
+	// Intensity is the scan intensity for the tier (safe, normal,
+	// aggressive). Empty keeps the base setting.
+	Intensity string `mapstructure:"intensity" yaml:"intensity,omitempty"`
+
+	// Destructive allows intrusive scanners (request floods, attack
+	// payloads). Nil keeps the built-in default for the tier.
+	Destructive *bool `mapstructure:"destructive" yaml:"destructive,omitempty"`
+
+	// Window is the daily period scans of this environment may run in,
+	// such as "22:00-06:00 Europe/Berlin". Empty keeps the base setting.
+	Window string `mapstructure:"window" yaml:"window,omitempty"`
+
+	// Exclude lists scanners that never run in this environment.
+	Exclude []string `mapstructure:"exclude" yaml:"exclude,omitempty"`
+
+	// Scanners overlays the top-level scanners section.
+	Scanners map[string]map[string]interface{} `mapstructure:"scanners" yaml:"scanners,omitempty"`
+}
+
+// AllowsDestructive reports whether intrusive scanners may run.
+func (e Environment) AllowsDestructive() bool {
+	return e.Destructive == nil || *e.Destructive
+}
+
+func boolPtr(b bool) *bool { return &b }
+
+// builtinEnvironments are the presets for the well-known tiers. Config
+// entries with the same name are layered on top of them.
+var builtinEnvironments = map[string]Environment{
+	"prod": {
+		Concurrency: 2,
+		RateLimit:   5,
+		Intensity:   "safe",
+		Destructive: boolPtr(false),
+	},
+	"staging": {
+		Concurrency: 5,
+		RateLimit:   20,
+		Destructive: boolPtr(true),
+	},
+	"dev": {
+		Destructive: boolPtr(true),
+	},
+}
+
+// Environment returns the named environment: the built-in preset, if any,
+// overlaid with the environments section of the config file.
+func (c *Config) Environment(name string) (Environment, error) {
+	base, builtin := builtinEnvironments[name]
+	custom, configured := c.Environments[name]
+	if !builtin && !configured {
+		return Environment{}, fmt.Errorf("unknown environment %q (available: %s)", name, strings.Join(c.environmentNames(), ", "))
+	}
+
+	if custom.Target != "" {
+		base.Target = custom.Target
+	}
+	if custom.Concurrency > 0 {
+		base.Concurrency = custom.Concurrency
+	}
+	if custom.Timeout > 0 {
+		base.Timeout = custom.Timeout
+	}
+	if custom.RateLimit > 0 {
+		base.RateLimit = custom.RateLimit
+	}
+	if custom.Intensity != "" {
+		base.Intensity = custom.Intensity
+	}
+	if custom.Window != "" {
+		base.Window = custom.Window
+	}
+	if custom.Destructive != nil {
+		base.Destructive = custom.Destructive
+	}
+	if len(custom.Exclude) > 0 {
+		base.Exclude = custom.Exclude
+	}
+	if len(custom.Scanners) > 0 {
+		base.Scanners = custom.Scanners
+	}
+	return base, nil
+}
+
+func (c *Config) environmentNames() []string {
+	seen := map[string]bool{}
+	for name := range builtinEnvironments {
+		seen[name] = true
+	}
+	for name := range c.Environments {
+		seen[name] = true
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyEnvironment applies env's defaults to cfg. Settings given explicitly
+// as CLI flags are left untouched.
+func ApplyEnvironment(cfg *Config, env Environment, cmd *cobra.Command) {
+	flags := cmd.Flags()
+
+	if env.Target != "" && !flags.Changed("target") {
+		cfg.DefaultTarget = env.Target
+	}
+	if env.Concurrency > 0 && !flags.Changed("concurrency") {
+		cfg.Concurrency = env.Concurrency
+	}
+	if env.Timeout > 0 && !flags.Changed("timeout") {
+		cfg.Timeout = env.Timeout
+	}
+	if env.Intensity != "" && !flags.Changed("intensity") {
+		cfg.Intensity = env.Intensity
+	}
+	if env.Window != "" && !flags.Changed("window") {
+		cfg.ScanWindow = env.Window
+	}
+
+	if len(env.Scanners) > 0 {
+		merged := make(map[string]map[string]interface{}, len(cfg.Scanners)+len(env.Scanners))
+		for name, args := range cfg.Scanners {
+			merged[name] = args
+		}
+		for name, args := range env.Scanners {
+			combined := make(map[string]interface{}, len(merged[name])+len(args))
+			for k, v := range merged[name] {
+				combined[k] = v
+			}
+			for k, v := range args {
+				combined[k] = v
+			}
+			merged[name] = combined
+		}
+		cfg.Scanners = merged
+	}
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvironment_BuiltinPresets(t *testing.T) {
+	cfg := &Config{}
+
+	prod, err := cfg.Environment("prod")
+	require.NoError(t, err)
+	assert.False(t, prod.AllowsDestructive())
+	assert.Equal(t, 2, prod.Concurrency)
+	assert.Greater(t, prod.RateLimit, 0.0)
+	assert.Equal(t, "safe", prod.Intensity)
+
+	dev, err := cfg.Environment("dev")
+	require.NoError(t, err)
+	assert.True(t, dev.AllowsDestructive())
+
+	_, err = cfg.Environment("qa")
+	assert.ErrorContains(t, err, "available: dev, prod, staging")
+}
+
+func TestEnvironment_ConfigOverlaysPreset(t *testing.T) {
+	allow := true
+	cfg := &Config{Environments: map[string]Environment{
+		"prod": {Target: "https://example.com", RateLimit: 1, Exclude: []string{"dirs"}},
+		"qa":   {Concurrency: 3, Destructive: &allow},
+	}}
+
+	prod, err := cfg.Environment("prod")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", prod.Target)
+	assert.Equal(t, 1.0, prod.RateLimit)
+	assert.Equal(t, 2, prod.Concurrency, "preset values not overridden are kept")
+	assert.False(t, prod.AllowsDestructive())
+	assert.Equal(t, []string{"dirs"}, prod.Exclude)
+
+	qa, err := cfg.Environment("qa")
+	require.NoError(t, err)
+	assert.Equal(t, 3, qa.Concurrency)
+}
+
+func TestApplyEnvironment_RespectsFlags(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().Int("concurrency", 10, "")
+	cmd.Flags().String("target", "", "")
+	cmd.Flags().Duration("timeout", 5*time.Second, "")
+	cmd.Flags().String("intensity", "", "")
+	require.NoError(t, cmd.Flags().Set("concurrency", "8"))
+
+	cfg := Defaults()
+	cfg.Concurrency = 8
+	cfg.Scanners = map[string]map[string]interface{}{"port": {"ports": "common", "banner": true}}
+
+	ApplyEnvironment(&cfg, Environment{
+		Target:      "https://prod.example.com",
+		Concurrency: 2,
+		Timeout:     10 * time.Second,
+		Intensity:   "safe",
+		Window:      "22:00-06:00",
+		Scanners:    map[string]map[string]interface{}{"port": {"ports": "443"}},
+	}, cmd)
+
+	assert.Equal(t, 8, cfg.Concurrency, "explicit flag wins")
+	assert.Equal(t, "https://prod.example.com", cfg.DefaultTarget)
+	assert.Equal(t, 10*time.Second, cfg.Timeout)
+	assert.Equal(t, "safe", cfg.Intensity)
+	assert.Equal(t, "22:00-06:00", cfg.ScanWindow)
+	assert.Equal(t, "443", cfg.Scanners["port"]["ports"])
+	assert.Equal(t, true, cfg.Scanners["port"]["banner"])
+}
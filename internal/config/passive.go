@@ -0,0 +1,38 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PassiveDNSProvider is a passive DNS API queried over HTTP for the names
+// under a domain. URL contains {domain} where the domain goes; header
+// values may be secret references, as in credentials.
+type PassiveDNSProvider struct {
+	Name    string            `mapstructure:"name" yaml:"name"`
+	URL     string            `mapstructure:"url" yaml:"url"`
+	Headers map[string]string `mapstructure:"headers" yaml:"headers,omitempty"`
+}
+
+// Resolve returns the provider with its header secrets read, checking that
+// its URL has a {domain} placeholder.
+func (p PassiveDNSProvider) Resolve() (PassiveDNSProvider, error) {
+	label := p.Name
+	if label == "" {
+		label = p.URL
+	}
+	if !strings.Contains(p.URL, "{domain}") {
+		return PassiveDNSProvider{}, fmt.Errorf("passive DNS provider %q: url must contain {domain}", label)
+	}
+
+	headers := make(map[string]string, len(p.Headers))
+	for name, value := range p.Headers {
+		secret, err := ResolveSecret(value)
+		if err != nil {
+			return PassiveDNSProvider{}, fmt.Errorf("passive DNS provider %q: header %s: %w", label, name, err)
+		}
+		headers[name] = secret
+	}
+	p.Headers = headers
+	return p, nil
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFromFile_PassiveDNS(t *testing.T) {
+	cfgFile := filepath.Join(t.TempDir(), ".hunter.yaml")
+	content := `passive_dns:
+  - name: circl
+    url: https://www.circl.lu/pdns/query/{domain}
+    headers:
+      Authorization: env:HUNTER_TEST_PDNS_KEY
+`
+	require.NoError(t, os.WriteFile(cfgFile, []byte(content), 0644))
+
+	cfg, err := LoadFromFile(cfgFile)
+	require.NoError(t, err)
+	require.Len(t, cfg.PassiveDNS, 1)
+	assert.Equal(t, "circl", cfg.PassiveDNS[0].Name)
+
+	t.Setenv("HUNTER_TEST_PDNS_KEY", "Basic c2VjcmV0")
+	p, err := cfg.PassiveDNS[0].Resolve()
+	require.NoError(t, err)
+	// Viper lowercases map keys; header names are case-insensitive anyway.
+	assert.Equal(t, "Basic c2VjcmV0", p.Headers["authorization"])
+	assert.Equal(t, "env:HUNTER_TEST_PDNS_KEY", cfg.PassiveDNS[0].Headers["authorization"], "resolving leaves the config untouched")
+}
+
+func TestPassiveDNSProvider_ResolveErrors(t *testing.T) {
+	_, err := PassiveDNSProvider{Name: "bad", URL: "https://pdns.example.net/query"}.Resolve()
+	assert.ErrorContains(t, err, "{domain}")
+
+	_, err = PassiveDNSProvider{
+		URL:     "https://pdns.example.net/{domain}",
+		Headers: map[string]string{"X-Api-Key": "env:HUNTER_TEST_DEFINITELY_UNSET"},
+	}.Resolve()
+	assert.ErrorContains(t, err, "X-Api-Key")
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultSecurityTxtExpiry is how long a generated security.txt stays valid
+// when security_txt.expires_in is not set. RFC 9116 recommends less than a
+// year.
+const DefaultSecurityTxtExpiry = 180 * 24 * time.Hour
+
+// SecurityTxt holds the fields of the /.well-known/security.txt file
+// `hunter scaffold security-txt` generates (RFC 9116). Every field but
+// ExpiresIn may be given more than once.
+type SecurityTxt struct {
+	// Contact lists where to report vulnerabilities: mailto:, tel:, or
+	// https: URIs. Bare email addresses get mailto: added. Required.
+	Contact []string `mapstructure:"contact" yaml:"contact,omitempty"`
+	// ExpiresIn is how long after generation the file expires; zero means
+	// DefaultSecurityTxtExpiry.
+	ExpiresIn          time.Duration `mapstructure:"expires_in" yaml:"expires_in,omitempty"`
+	Encryption         []string      `mapstructure:"encryption" yaml:"encryption,omitempty"`
+	Acknowledgments    []string      `mapstructure:"acknowledgments" yaml:"acknowledgments,omitempty"`
+	PreferredLanguages []string      `mapstructure:"preferred_languages" yaml:"preferred_languages,omitempty"`
+	Canonical          []string      `mapstructure:"canonical" yaml:"canonical,omitempty"`
+	Policy             []string      `mapstructure:"policy" yaml:"policy,omitempty"`
+	Hiring             []string      `mapstructure:"hiring" yaml:"hiring,omitempty"`
+}
+
+// Render returns the security.txt file for s, generated at now. It fails
+// when there is no contact or a field is not a URI the RFC allows there.
+func (s SecurityTxt) Render(now time.Time) ([]byte, error) {
+	if len(s.Contact) == 0 {
+		return nil, fmt.Errorf("security_txt: at least one contact is required")
+	}
+	expiresIn := s.ExpiresIn
+	if expiresIn == 0 {
+		expiresIn = DefaultSecurityTxtExpiry
+	}
+	if expiresIn < 0 {
+		return nil, fmt.Errorf("security_txt: expires_in must be positive, got %s", expiresIn)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Generated by hunter scaffold security-txt. Regenerate before it expires.\n")
+	for _, contact := range s.Contact {
+		if !strings.Contains(contact, ":") && strings.Contains(contact, "@") {
+			contact = "mailto:" + contact
+		}
+		if err := checkURI("contact", contact, "mailto", "tel", "https"); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&buf, "Contact: %s\n", contact)
+	}
+	fmt.Fprintf(&buf, "Expires: %s\n", now.Add(expiresIn).UTC().Truncate(time.Second).Format(time.RFC3339))
+
+	fields := []struct {
+		name    string
+		key     string
+		values  []string
+		schemes []string
+	}{
+		{"Encryption", "encryption", s.Encryption, []string{"https", "dns", "openpgp4fpr"}},
+		{"Acknowledgments", "acknowledgments", s.Acknowledgments, []string{"https"}},
+		{"Canonical", "canonical", s.Canonical, []string{"https"}},
+		{"Policy", "policy", s.Policy, []string{"https"}},
+		{"Hiring", "hiring", s.Hiring, []string{"https"}},
+	}
+	for _, f := range fields {
+		for _, value := range f.values {
+			if err := checkURI(f.key, value, f.schemes...); err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&buf, "%s: %s\n", f.name, value)
+		}
+	}
+	if len(s.PreferredLanguages) > 0 {
+		fmt.Fprintf(&buf, "Preferred-Languages: %s\n", strings.Join(s.PreferredLanguages, ", "))
+	}
+	return buf.Bytes(), nil
+}
+
+// checkURI checks that value, of the security_txt field key, is a URI with
+// one of schemes.
+func checkURI(key, value string, schemes ...string) error {
+	u, err := url.Parse(value)
+	if err == nil {
+		for _, scheme := range schemes {
+			if strings.EqualFold(u.Scheme, scheme) && (scheme != "https" || u.Host != "") {
+				return nil
+			}
+		}
+	}
+	allowed := strings.Join(schemes, ":, ") + ":"
+	if i := strings.LastIndex(allowed, ", "); i >= 0 {
+		allowed = allowed[:i] + " or " + allowed[i+2:]
+	}
+	return fmt.Errorf("security_txt: %s %q must be a %s URI", key, value, allowed)
+}
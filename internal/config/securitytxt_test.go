@@ -0,0 +1,60 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFromFile_SecurityTxt(t *testing.T) {
+	cfgFile := filepath.Join(t.TempDir(), ".hunter.yaml")
+	content := `security_txt:
+  contact: [security@example.com, "https://example.com/report"]
+  expires_in: 720h
+  preferred_languages: [en, fr]
+  policy: [https://example.com/disclosure]
+`
+	require.NoError(t, os.WriteFile(cfgFile, []byte(content), 0644))
+
+	cfg, err := LoadFromFile(cfgFile)
+	require.NoError(t, err)
+
+	now := time.Date(2026, 3, 1, 12, 30, 15, 500, time.FixedZone("CET", 3600))
+	out, err := cfg.SecurityTxt.Render(now)
+	require.NoError(t, err)
+	assert.Equal(t, `# Generated by hunter scaffold security-txt. Regenerate before it expires.
+Contact: mailto:security@example.com
+Contact: https://example.com/report
+Expires: 2026-03-31T11:30:15Z
+Policy: https://example.com/disclosure
+Preferred-Languages: en, fr
+`, string(out))
+}
+
+func TestSecurityTxt_DefaultExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	out, err := SecurityTxt{Contact: []string{"tel:+1-201-555-0123"}}.Render(now)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "Contact: tel:+1-201-555-0123\n")
+	assert.Contains(t, string(out), "Expires: "+now.Add(DefaultSecurityTxtExpiry).Format(time.RFC3339)+"\n")
+}
+
+func TestSecurityTxt_Invalid(t *testing.T) {
+	for name, s := range map[string]SecurityTxt{
+		"no contact":       {Policy: []string{"https://example.com/policy"}},
+		"http contact":     {Contact: []string{"http://example.com/report"}},
+		"bare contact":     {Contact: []string{"example.com"}},
+		"http policy":      {Contact: []string{"security@example.com"}, Policy: []string{"http://example.com/policy"}},
+		"negative expires": {Contact: []string{"security@example.com"}, ExpiresIn: -time.Hour},
+	} {
+		_, err := s.Render(time.Now())
+		assert.Error(t, err, name)
+	}
+
+	_, err := SecurityTxt{Contact: []string{"security@example.com"}, Encryption: []string{"openpgp4fpr:5f2de5521c63a801ab59ccb603d49de44b29100f"}}.Render(time.Now())
+	assert.NoError(t, err, "encryption keys may be given by fingerprint")
+}
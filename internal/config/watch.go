@@ -0,0 +1,57 @@
+package config
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// fileState is what Watch compares between polls to notice a change.
+type fileState struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+func statFiles(paths []string) []fileState {
+	states := make([]fileState, len(paths))
+	for i, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		states[i] = fileState{exists: true, size: info.Size(), modTime: info.ModTime()}
+	}
+	return states
+}
+
+// Watch polls the given config files every interval and calls onChange when
+// any of them is created, modified, or removed. It returns when ctx is done.
+// Polling, rather than file system events, also catches editors that save by
+// renaming a temporary file over the original.
+func Watch(ctx context.Context, paths []string, interval time.Duration, onChange func()) {
+	last := statFiles(paths)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current := statFiles(paths)
+		changed := false
+		for i := range current {
+			if current[i] != last[i] {
+				changed = true
+				break
+			}
+		}
+		last = current
+		if changed {
+			onChange()
+		}
+	}
+}
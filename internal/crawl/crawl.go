@@ -0,0 +1,354 @@
+// Package crawl spiders a web application from a start page for the pages,
+// links with parameters, and forms that scanners can test.
+package crawl
+
+import (
+	"context"
+	"html"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// Defaults for the bounds of a crawl.
+const (
+	DefaultMaxDepth = 2
+	DefaultMaxPages = 50
+)
+
+// maxPageSize bounds how much of each page is read.
+const maxPageSize = 1 << 20
+
+// Options bound a crawl and say how it sends requests.
+type Options struct {
+	// MaxDepth is how many links away from the start page pages are
+	// fetched; 0 means DefaultMaxDepth.
+	MaxDepth int
+	// MaxPages is how many pages are fetched at most; 0 means
+	// DefaultMaxPages.
+	MaxPages int
+	// Client sends the requests; nil means http.DefaultClient.
+	Client *http.Client
+}
+
+// skippedExtensions are those of files that are not pages, whose links are
+// not followed.
+var skippedExtensions = map[string]bool{
+	".css": true, ".js": true, ".json": true, ".xml": true, ".txt": true,
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".ico": true, ".webp": true,
+	".woff": true, ".woff2": true, ".ttf": true, ".eot": true,
+	".pdf": true, ".zip": true, ".gz": true, ".tar": true, ".mp3": true, ".mp4": true, ".webm": true,
+}
+
+// skippedWords in a link's path mark links that end the session the crawl
+// may be authenticated with.
+var skippedWords = []string{"logout", "logoff", "signout", "sign-out", "log-out"}
+
+var (
+	linkTag   = regexp.MustCompile(`(?is)<(?:a|area|iframe|frame)\b[^>]*?\s(?:href|src)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+	formTag   = regexp.MustCompile(`(?is)<form\b([^>]*)>(.*?)</form>`)
+	fieldTag  = regexp.MustCompile(`(?is)<(input|textarea|select|button)\b([^>]*)>`)
+	attribute = regexp.MustCompile(`(?is)([a-z][a-z0-9_-]*)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+
+	// csrfTokenName matches the names frameworks give their anti-CSRF
+	// token fields and headers: csrf_token, _csrf, authenticity_token,
+	// __RequestVerificationToken, X-XSRF-TOKEN, _token, nonce, and the like.
+	csrfTokenName = regexp.MustCompile(`(?i)csrf|xsrf|token|nonce|forgery`)
+)
+
+// Crawl fetches start and the same-origin pages it links to, breadth first
+// within opts' bounds, and returns the requests found: a GET for each page
+// fetched and each link with query parameters, and one for each form, its
+// fields filled with their default values. Requests are deduplicated and in
+// the order found. It fails only when start itself cannot be fetched.
+func Crawl(ctx context.Context, start string, opts Options) ([]types.Endpoint, error) {
+	maxDepth, maxPages := opts.MaxDepth, opts.MaxPages
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDepth
+	}
+	if maxPages <= 0 {
+		maxPages = DefaultMaxPages
+	}
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	startURL, err := url.Parse(start)
+	if err != nil {
+		return nil, err
+	}
+	startURL.Fragment, startURL.RawFragment = "", ""
+	if startURL.Path == "" {
+		startURL.Path = "/"
+	}
+
+	c := &crawler{seen: map[string]bool{}}
+	queue := []link{{url: startURL.String()}}
+	queued := map[string]bool{startURL.String(): true}
+
+	for fetched := 0; len(queue) > 0 && fetched < maxPages; fetched++ {
+		if ctx.Err() != nil {
+			break
+		}
+		next := queue[0]
+		queue = queue[1:]
+
+		page, body, err := fetch(ctx, client, next.url)
+		switch {
+		case err != nil && fetched == 0:
+			return nil, err
+		case err != nil:
+			continue
+		case fetched == 0:
+			// The origin is where the start page ended up, such as
+			// its https:// URL.
+			c.origin = page
+		case !c.inScope(page):
+			continue
+		}
+		queued[page.String()] = true
+		c.add(types.Endpoint{Method: http.MethodGet, URL: page.String()})
+
+		for _, u := range links(page, body) {
+			if !c.inScope(u) {
+				continue
+			}
+			if u.RawQuery != "" {
+				c.add(types.Endpoint{Method: http.MethodGet, URL: u.String()})
+			}
+			if next.depth < maxDepth && !queued[u.String()] {
+				queued[u.String()] = true
+				queue = append(queue, link{url: u.String(), depth: next.depth + 1})
+			}
+		}
+		for _, form := range ParseForms(page.String(), body) {
+			if u, err := url.Parse(form.Action); err == nil && c.inScope(u) {
+				c.add(form.Endpoint())
+			}
+		}
+	}
+	return c.found, nil
+}
+
+type link struct {
+	url   string
+	depth int
+}
+
+type crawler struct {
+	origin *url.URL
+	seen   map[string]bool
+	found  []types.Endpoint
+}
+
+// add records ep unless an identical request was recorded before.
+func (c *crawler) add(ep types.Endpoint) {
+	key := ep.Method + " " + ep.URL + "\x00" + ep.Body
+	if c.seen[key] {
+		return
+	}
+	c.seen[key] = true
+	c.found = append(c.found, ep)
+}
+
+// inScope reports whether u is a page of the start page's origin worth
+// crawling.
+func (c *crawler) inScope(u *url.URL) bool {
+	if !strings.EqualFold(u.Scheme, c.origin.Scheme) || !strings.EqualFold(u.Host, c.origin.Host) {
+		return false
+	}
+	if skippedExtensions[strings.ToLower(path.Ext(u.Path))] {
+		return false
+	}
+	lower := strings.ToLower(u.Path)
+	for _, word := range skippedWords {
+		if strings.Contains(lower, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// fetch requests rawURL and returns where it ended up after redirects and,
+// for HTML pages, its body.
+func fetch(ctx context.Context, client *http.Client, rawURL string) (*url.URL, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return resp.Request.URL, "", nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return nil, "", err
+	}
+	return resp.Request.URL, string(body), nil
+}
+
+// links returns the targets of the links in body, a page at base, resolved
+// and without fragments.
+func links(base *url.URL, body string) []*url.URL {
+	var found []*url.URL
+	for _, m := range linkTag.FindAllStringSubmatch(body, -1) {
+		if u := resolve(base, m[1]+m[2]+m[3]); u != nil {
+			found = append(found, u)
+		}
+	}
+	return found
+}
+
+// Form is an HTML form: where and how it submits, and its fields.
+type Form struct {
+	// Page is the URL of the page the form is on.
+	Page string
+	// Action is the URL the form submits to.
+	Action string
+	// Method is GET or POST.
+	Method string
+	// Autocomplete is the form's autocomplete attribute, if it has one.
+	Autocomplete string
+	Fields       []Field
+}
+
+// Field is a named control of a form.
+type Field struct {
+	Name string
+	// Type is an input's type, lowercased and "text" when missing, or
+	// "textarea", "select", or "button".
+	Type         string
+	Value        string
+	Autocomplete string
+}
+
+// ParseForms returns the forms in body, the page at pageURL, with their
+// actions resolved against it. Forms whose action is not an http or https
+// URL are left out.
+func ParseForms(pageURL, body string) []Form {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+
+	var found []Form
+	for _, m := range formTag.FindAllStringSubmatch(body, -1) {
+		attrs := attributes(m[1])
+		action := base
+		if a := attrs["action"]; a != "" {
+			if action = resolve(base, a); action == nil {
+				continue
+			}
+		}
+		form := Form{
+			Page:         base.String(),
+			Action:       action.String(),
+			Method:       http.MethodGet,
+			Autocomplete: strings.ToLower(attrs["autocomplete"]),
+		}
+		if strings.EqualFold(attrs["method"], http.MethodPost) {
+			form.Method = http.MethodPost
+		}
+
+		for _, f := range fieldTag.FindAllStringSubmatch(m[2], -1) {
+			fieldAttrs := attributes(f[2])
+			field := Field{
+				Name:         fieldAttrs["name"],
+				Type:         strings.ToLower(f[1]),
+				Value:        fieldAttrs["value"],
+				Autocomplete: strings.ToLower(fieldAttrs["autocomplete"]),
+			}
+			if field.Type == "input" {
+				field.Type = strings.ToLower(fieldAttrs["type"])
+				if field.Type == "" {
+					field.Type = "text"
+				}
+			}
+			form.Fields = append(form.Fields, field)
+		}
+		found = append(found, form)
+	}
+	return found
+}
+
+// CSRFToken returns the form's anti-CSRF token: a hidden field with a value
+// named like one.
+func (f Form) CSRFToken() (Field, bool) {
+	for _, field := range f.Fields {
+		if field.Type == "hidden" && field.Value != "" && IsCSRFTokenName(field.Name) {
+			return field, true
+		}
+	}
+	return Field{}, false
+}
+
+// IsCSRFTokenName reports whether name, of a form field, JSON field, or
+// header, is named like an anti-CSRF token.
+func IsCSRFTokenName(name string) bool {
+	return csrfTokenName.MatchString(name)
+}
+
+// Endpoint returns the request submitting the form with its fields' default
+// values: their values in the query string of a GET, or a form-encoded body
+// for a POST. Unnamed fields, file uploads, image and reset buttons are left
+// out.
+func (f Form) Endpoint() types.Endpoint {
+	fields := url.Values{}
+	for _, field := range f.Fields {
+		switch {
+		case field.Name == "":
+		case field.Type == "file" || field.Type == "image" || field.Type == "reset":
+		default:
+			fields.Add(field.Name, field.Value)
+		}
+	}
+
+	ep := types.Endpoint{Method: f.Method, URL: f.Action}
+	if f.Method == http.MethodPost {
+		ep.Headers = map[string]string{"Content-Type": "application/x-www-form-urlencoded"}
+		ep.Body = fields.Encode()
+	} else if len(fields) > 0 {
+		if u, err := url.Parse(f.Action); err == nil {
+			u.RawQuery = fields.Encode()
+			ep.URL = u.String()
+		}
+	}
+	return ep
+}
+
+// attributes returns the attributes of a tag, names lowercased and values
+// unescaped.
+func attributes(tag string) map[string]string {
+	attrs := map[string]string{}
+	for _, m := range attribute.FindAllStringSubmatch(tag, -1) {
+		name := strings.ToLower(m[1])
+		if _, ok := attrs[name]; !ok {
+			attrs[name] = html.UnescapeString(m[2] + m[3] + m[4])
+		}
+	}
+	return attrs
+}
+
+// resolve returns ref, an attribute value, resolved against base, or nil
+// when it is not an http or https URL.
+func resolve(base *url.URL, ref string) *url.URL {
+	u, err := base.Parse(strings.TrimSpace(html.UnescapeString(ref)))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil
+	}
+	u.Fragment, u.RawFragment = "", ""
+	return u
+}
@@ -0,0 +1,190 @@
+package crawl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// site serves a small application: pages linking to each other by path,
+// and records the paths requested.
+type site struct {
+	mu        sync.Mutex
+	requested []string
+	pages     map[string]string
+}
+
+func (s *site) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requested = append(s.requested, r.URL.Path)
+	s.mu.Unlock()
+
+	body, ok := s.pages[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, "<html><body>"+body+"</body></html>")
+}
+
+func urls(endpoints []types.Endpoint) []string {
+	var out []string
+	for _, ep := range endpoints {
+		out = append(out, ep.Method+" "+ep.URL)
+	}
+	return out
+}
+
+func TestCrawl_FindsPagesLinksAndForms(t *testing.T) {
+	s := &site{pages: map[string]string{
+		"/": `<a href="/products">Products</a> <a href='/search?q=shoes#results'>Search</a>
+			<a href="https://elsewhere.example/">Partner</a> <a href="mailto:hi@example.com">Mail</a>
+			<a href="/logout">Log out</a> <a href="/style.css">CSS</a> <a href="#top">Top</a>`,
+		"/products": `<a href="/products/1?ref=list&amp;page=2">One</a>
+			<form action="/cart" method="POST">
+				<input type="hidden" name="csrf" value="abc">
+				<input name="qty" value="1"><input type="file" name="photo">
+				<button type="submit">Add</button>
+			</form>
+			<form><input name="filter"><select name="sort"></select></form>`,
+		"/products/1": `<p>One</p>`,
+		"/search":     `<p>Results</p>`,
+	}}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	endpoints, err := Crawl(context.Background(), srv.URL+"/", Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"GET " + srv.URL + "/",
+		"GET " + srv.URL + "/search?q=shoes",
+		"GET " + srv.URL + "/products",
+		"GET " + srv.URL + "/products/1?ref=list&page=2",
+		"POST " + srv.URL + "/cart",
+		"GET " + srv.URL + "/products?filter=&sort=",
+	}, urls(endpoints))
+
+	cart := endpoints[4]
+	assert.Equal(t, "application/x-www-form-urlencoded", cart.Headers["Content-Type"])
+	form, err := url.ParseQuery(cart.Body)
+	require.NoError(t, err)
+	assert.Equal(t, url.Values{"csrf": {"abc"}, "qty": {"1"}}, form)
+
+	for _, path := range s.requested {
+		assert.NotEqual(t, "/logout", path, "logout links must not be followed")
+		assert.NotEqual(t, "/style.css", path)
+	}
+}
+
+func TestCrawl_BoundsDepthAndPages(t *testing.T) {
+	// A chain of pages, each linking to the next.
+	pages := map[string]string{}
+	for i := 0; i < 10; i++ {
+		pages[fmt.Sprintf("/p%d", i)] = fmt.Sprintf(`<a href="/p%d">next</a>`, i+1)
+	}
+	s := &site{pages: pages}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	_, err := Crawl(context.Background(), srv.URL+"/p0", Options{MaxDepth: 3})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/p0", "/p1", "/p2", "/p3"}, s.requested)
+
+	s.requested = nil
+	_, err = Crawl(context.Background(), srv.URL+"/p0", Options{MaxDepth: 10, MaxPages: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/p0", "/p1"}, s.requested)
+}
+
+func TestCrawl_FollowsStartRedirect(t *testing.T) {
+	s := &site{pages: map[string]string{
+		"/app/":      `<a href="/app/about">About</a>`,
+		"/app/about": `<p>About</p>`,
+	}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			http.Redirect(w, r, "/app/", http.StatusFound)
+			return
+		}
+		s.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	endpoints, err := Crawl(context.Background(), srv.URL, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GET " + srv.URL + "/app/", "GET " + srv.URL + "/app/about"}, urls(endpoints))
+}
+
+func TestCrawl_SkipsNonHTMLBodies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"html": "<a href=\"/secret\">x</a>"}`)
+	}))
+	defer srv.Close()
+
+	endpoints, err := Crawl(context.Background(), srv.URL, Options{})
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	assert.False(t, strings.Contains(endpoints[0].URL, "secret"))
+}
+
+func TestCrawl_StartUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	_, err := Crawl(context.Background(), srv.URL, Options{})
+	assert.Error(t, err)
+}
+
+func TestParseForms(t *testing.T) {
+	body := `<form action="/login" method="post" autocomplete="off">
+		<input type="hidden" name="csrf_token" value="a&amp;b">
+		<input name="user" autocomplete="username">
+		<INPUT TYPE="Password" name="pass">
+		<textarea name="note"></textarea>
+		<button type="submit" name="go" value="1">Go</button>
+	</form>
+	<form action="javascript:void(0)"><input name="x"></form>
+	<form><input name="q"></form>`
+
+	forms := ParseForms("https://example.com/account/", body)
+	require.Len(t, forms, 2)
+
+	login := forms[0]
+	assert.Equal(t, "https://example.com/account/", login.Page)
+	assert.Equal(t, "https://example.com/login", login.Action)
+	assert.Equal(t, http.MethodPost, login.Method)
+	assert.Equal(t, "off", login.Autocomplete)
+	assert.Equal(t, []Field{
+		{Name: "csrf_token", Type: "hidden", Value: "a&b"},
+		{Name: "user", Type: "text", Autocomplete: "username"},
+		{Name: "pass", Type: "password"},
+		{Name: "note", Type: "textarea"},
+		{Name: "go", Type: "button", Value: "1"},
+	}, login.Fields)
+
+	token, ok := login.CSRFToken()
+	assert.True(t, ok)
+	assert.Equal(t, "csrf_token", token.Name)
+
+	ep := login.Endpoint()
+	assert.Equal(t, "application/x-www-form-urlencoded", ep.Headers["Content-Type"])
+	assert.Equal(t, "csrf_token=a%26b&go=1&note=&pass=&user=", ep.Body)
+
+	search := forms[1]
+	assert.Equal(t, http.MethodGet, search.Method)
+	_, ok = search.CSRFToken()
+	assert.False(t, ok)
+	assert.Equal(t, "https://example.com/account/?q=", search.Endpoint().URL)
+}
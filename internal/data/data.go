@@ -0,0 +1,114 @@
+// Package data manages downloadable scanner data sets — wordlists,
+// vulnerability databases, credential packs — kept in a local data directory.
+// Scanners look up installed copies through a Store and fall back to their
+// embedded defaults when nothing has been installed.
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/buemura/hunter/internal/atomicfile"
+)
+
+// Dataset describes a data set that can be installed with Update.
+type Dataset struct {
+	Name        string
+	File        string
+	Description string
+}
+
+// Datasets lists every data set Hunter knows how to install.
+var Datasets = []Dataset{
+	{Name: "wordlist", File: "wordlist.txt", Description: "paths for directory and file brute-forcing"},
+	{Name: "js-vulns", File: "js-vulns.json", Description: "known-vulnerable JavaScript library versions"},
+	{Name: "default-creds", File: "default-creds.json", Description: "default credential pairs tried on login endpoints"},
+	{Name: "cve-map", File: "cve-map.json", Description: "CVEs of server software versions"},
+}
+
+const manifestFile = "manifest.json"
+
+// Installed records the installed version of a data set.
+type Installed struct {
+	Version   string    `json:"version"`
+	SHA256    string    `json:"sha256"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Manifest maps data set names to their installed versions.
+type Manifest map[string]Installed
+
+// Store is a local data directory.
+type Store struct {
+	Dir string
+}
+
+// NewStore returns a store rooted at dir, or at DefaultDir when dir is empty.
+func NewStore(dir string) *Store {
+	if dir == "" {
+		dir = DefaultDir()
+	}
+	return &Store{Dir: dir}
+}
+
+// DefaultDir returns the default data directory (~/.hunter/data).
+func DefaultDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".hunter", "data")
+	}
+	return filepath.Join(home, ".hunter", "data")
+}
+
+// Lookup returns the data set with the given name.
+func Lookup(name string) (Dataset, bool) {
+	for _, d := range Datasets {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	return Dataset{}, false
+}
+
+// Path returns the path of the installed copy of a data set, or "" when it is
+// not installed and callers should use their embedded copy.
+func (s *Store) Path(name string) string {
+	d, ok := Lookup(name)
+	if !ok {
+		return ""
+	}
+	path := filepath.Join(s.Dir, d.Name, d.File)
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// Manifest reads the manifest of installed data sets. A missing manifest
+// yields an empty one.
+func (s *Store) Manifest() (Manifest, error) {
+	raw, err := os.ReadFile(filepath.Join(s.Dir, manifestFile))
+	if os.IsNotExist(err) {
+		return Manifest{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m := Manifest{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", manifestFile, err)
+	}
+	return m, nil
+}
+
+func (s *Store) writeManifest(m Manifest) error {
+	raw, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(filepath.Join(s.Dir, manifestFile), raw)
+}
@@ -0,0 +1,199 @@
+package data
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func checksum(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// newSource serves an index with two wordlist versions and returns the
+// server and a counter of data file downloads.
+func newSource(t *testing.T) (*httptest.Server, *int) {
+	t.Helper()
+	files := map[string]string{
+		"/wordlist-1.txt": "admin\n",
+		"/wordlist-2.txt": "admin\nbackup\n",
+	}
+	index := Index{Datasets: map[string]IndexEntry{
+		"wordlist": {
+			Latest: "2",
+			Versions: map[string]IndexVersion{
+				"1": {File: "wordlist-1.txt", SHA256: checksum(files["/wordlist-1.txt"])},
+				"2": {File: "wordlist-2.txt", SHA256: checksum(files["/wordlist-2.txt"])},
+				"3": {File: "wordlist-2.txt"},
+			},
+		},
+		"default-creds": {
+			Latest:   "1",
+			Versions: map[string]IndexVersion{"1": {File: "default-creds.json", SHA256: checksum("tampered")}},
+		},
+	}}
+	files["/default-creds.json"] = "[]"
+
+	downloads := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/index.json" {
+			json.NewEncoder(w).Encode(index)
+			return
+		}
+		body, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		downloads++
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &downloads
+}
+
+func TestStore_PathWhenNotInstalled(t *testing.T) {
+	s := NewStore(t.TempDir())
+	assert.Equal(t, "", s.Path("wordlist"))
+	assert.Equal(t, "", s.Path("unknown"))
+
+	m, err := s.Manifest()
+	require.NoError(t, err)
+	assert.Empty(t, m)
+}
+
+func TestUpdate_InstallsLatest(t *testing.T) {
+	srv, _ := newSource(t)
+	s := NewStore(t.TempDir())
+
+	results, err := s.Update(context.Background(), UpdateOptions{Source: srv.URL, Names: []string{"wordlist"}})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].Updated)
+	assert.Equal(t, "2", results[0].To)
+
+	path := s.Path("wordlist")
+	require.NotEmpty(t, path)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "admin\nbackup\n", string(content))
+
+	m, err := s.Manifest()
+	require.NoError(t, err)
+	assert.Equal(t, "2", m["wordlist"].Version)
+}
+
+func TestUpdate_SkipsWhenUpToDate(t *testing.T) {
+	srv, downloads := newSource(t)
+	s := NewStore(t.TempDir())
+	opts := UpdateOptions{Source: srv.URL, Names: []string{"wordlist"}}
+
+	_, err := s.Update(context.Background(), opts)
+	require.NoError(t, err)
+	results, err := s.Update(context.Background(), opts)
+	require.NoError(t, err)
+
+	assert.False(t, results[0].Updated)
+	assert.Equal(t, 1, *downloads)
+}
+
+func TestUpdate_HonoursPins(t *testing.T) {
+	srv, _ := newSource(t)
+	s := NewStore(t.TempDir())
+
+	results, err := s.Update(context.Background(), UpdateOptions{
+		Source: srv.URL,
+		Names:  []string{"wordlist"},
+		Pins:   map[string]string{"wordlist": "1"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "1", results[0].To)
+
+	results, err = s.Update(context.Background(), UpdateOptions{
+		Source: srv.URL,
+		Names:  []string{"wordlist"},
+		Pins:   map[string]string{"wordlist": "9"},
+	})
+	require.NoError(t, err)
+	assert.ErrorContains(t, results[0].Err, "not available")
+	assert.NotEmpty(t, s.Path("wordlist"), "previous copy is kept")
+}
+
+func TestUpdate_PerDatasetFailures(t *testing.T) {
+	srv, _ := newSource(t)
+	s := NewStore(t.TempDir())
+
+	results, err := s.Update(context.Background(), UpdateOptions{Source: srv.URL})
+	require.NoError(t, err)
+	require.Len(t, results, len(Datasets))
+
+	byName := map[string]UpdateResult{}
+	for _, r := range results {
+		byName[r.Name] = r
+	}
+	assert.NoError(t, byName["wordlist"].Err)
+	assert.ErrorContains(t, byName["default-creds"].Err, "checksum mismatch")
+	assert.ErrorContains(t, byName["cve-map"].Err, "not published")
+	assert.Equal(t, "", s.Path("default-creds"))
+}
+
+func TestUpdate_RequiresChecksum(t *testing.T) {
+	srv, downloads := newSource(t)
+	s := NewStore(t.TempDir())
+
+	results, err := s.Update(context.Background(), UpdateOptions{
+		Source: srv.URL,
+		Names:  []string{"wordlist"},
+		Pins:   map[string]string{"wordlist": "3"},
+	})
+	require.NoError(t, err)
+	assert.ErrorContains(t, results[0].Err, "no sha256 checksum")
+	assert.Zero(t, *downloads, "nothing unverifiable is downloaded")
+	assert.Equal(t, "", s.Path("wordlist"))
+}
+
+func TestUpdate_NotPublished(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Index{})
+	}))
+	defer srv.Close()
+
+	results, err := NewStore(t.TempDir()).Update(context.Background(), UpdateOptions{Source: srv.URL, Names: []string{"default-creds"}})
+	require.NoError(t, err)
+	assert.ErrorContains(t, results[0].Err, "not published")
+}
+
+func TestUpdate_SourceUnavailable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	_, err := NewStore(t.TempDir()).Update(context.Background(), UpdateOptions{Source: srv.URL})
+	assert.ErrorContains(t, err, "fetching index")
+
+	_, err = NewStore(t.TempDir()).Update(context.Background(), UpdateOptions{})
+	assert.Error(t, err)
+}
+
+func TestUpdate_UnknownNames(t *testing.T) {
+	s := NewStore(t.TempDir())
+	_, err := s.Update(context.Background(), UpdateOptions{Source: "http://unused", Names: []string{"nope"}})
+	assert.ErrorContains(t, err, "unknown data set")
+
+	_, err = s.Update(context.Background(), UpdateOptions{Source: "http://unused", Pins: map[string]string{"nope": "1"}})
+	assert.ErrorContains(t, err, "unknown data set")
+}
+
+func TestDefaultDir(t *testing.T) {
+	t.Setenv("HOME", "/home/tester")
+	assert.Equal(t, filepath.Join("/home/tester", ".hunter", "data"), DefaultDir())
+}
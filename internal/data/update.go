@@ -0,0 +1,195 @@
+package data
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/internal/atomicfile"
+)
+
+// indexFile is fetched from the update source and lists the available
+// versions of each data set.
+const indexFile = "index.json"
+
+// maxDownloadSize caps a single data set download.
+const maxDownloadSize = 64 << 20
+
+// Index is the update source's catalogue of data sets.
+type Index struct {
+	Datasets map[string]IndexEntry `json:"datasets"`
+}
+
+// IndexEntry lists the published versions of one data set.
+type IndexEntry struct {
+	Latest   string                  `json:"latest"`
+	Versions map[string]IndexVersion `json:"versions"`
+}
+
+// IndexVersion locates one published version, relative to the source URL.
+type IndexVersion struct {
+	File   string `json:"file"`
+	SHA256 string `json:"sha256"`
+}
+
+// UpdateOptions controls an update run.
+type UpdateOptions struct {
+	// Source is the base URL serving index.json and the data files.
+	Source string
+	// Names restricts the update to these data sets; empty means all.
+	Names []string
+	// Pins fixes data sets to a specific version instead of the latest.
+	Pins map[string]string
+	// Client is the HTTP client to use; nil means http.DefaultClient.
+	Client *http.Client
+}
+
+// UpdateResult reports what happened to one data set.
+type UpdateResult struct {
+	Name    string
+	From    string
+	To      string
+	Updated bool
+	Err     error
+}
+
+// Update downloads the selected data sets from opts.Source into the store.
+// Data sets that are already at the wanted version are left alone. A failure
+// for one data set does not stop the others; the previously installed copy
+// (or the embedded one) stays in use.
+func (s *Store) Update(ctx context.Context, opts UpdateOptions) ([]UpdateResult, error) {
+	if opts.Source == "" {
+		return nil, fmt.Errorf("no update source configured")
+	}
+	names, err := selectDatasets(opts.Names)
+	if err != nil {
+		return nil, err
+	}
+	for name := range opts.Pins {
+		if _, ok := Lookup(name); !ok {
+			return nil, fmt.Errorf("unknown data set %q in pins", name)
+		}
+	}
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	source := strings.TrimSuffix(opts.Source, "/")
+
+	raw, err := fetch(ctx, client, source+"/"+indexFile)
+	if err != nil {
+		return nil, fmt.Errorf("fetching index: %w", err)
+	}
+	var index Index
+	if err := json.Unmarshal(raw, &index); err != nil {
+		return nil, fmt.Errorf("parsing index: %w", err)
+	}
+
+	manifest, err := s.Manifest()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []UpdateResult
+	for _, d := range names {
+		res := UpdateResult{Name: d.Name, From: manifest[d.Name].Version}
+		installed, err := s.updateOne(ctx, client, source, d, index, opts.Pins[d.Name], manifest)
+		if err != nil {
+			res.Err = err
+		} else {
+			res.To = installed.Version
+			res.Updated = installed.Version != res.From
+			manifest[d.Name] = installed
+		}
+		results = append(results, res)
+	}
+
+	if err := s.writeManifest(manifest); err != nil {
+		return results, fmt.Errorf("writing manifest: %w", err)
+	}
+	return results, nil
+}
+
+func (s *Store) updateOne(ctx context.Context, client *http.Client, source string, d Dataset, index Index, pin string, manifest Manifest) (Installed, error) {
+	entry, ok := index.Datasets[d.Name]
+	if !ok {
+		return Installed{}, fmt.Errorf("not published by the update source")
+	}
+
+	version := entry.Latest
+	if pin != "" {
+		version = pin
+	}
+	v, ok := entry.Versions[version]
+	if !ok {
+		return Installed{}, fmt.Errorf("version %q is not available", version)
+	}
+	if v.SHA256 == "" {
+		return Installed{}, fmt.Errorf("version %q is published with no sha256 checksum", version)
+	}
+
+	current := manifest[d.Name]
+	if current.Version == version && current.SHA256 == v.SHA256 && s.Path(d.Name) != "" {
+		return current, nil
+	}
+
+	body, err := fetch(ctx, client, source+"/"+v.File)
+	if err != nil {
+		return Installed{}, err
+	}
+	sum := sha256.Sum256(body)
+	if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, v.SHA256) {
+		return Installed{}, fmt.Errorf("checksum mismatch for %s: got %s, want %s", v.File, got, v.SHA256)
+	}
+
+	if err := atomicfile.Write(filepath.Join(s.Dir, d.Name, d.File), body); err != nil {
+		return Installed{}, err
+	}
+	return Installed{Version: version, SHA256: hex.EncodeToString(sum[:]), UpdatedAt: time.Now().UTC()}, nil
+}
+
+func selectDatasets(names []string) ([]Dataset, error) {
+	if len(names) == 0 {
+		return Datasets, nil
+	}
+	var selected []Dataset
+	for _, name := range names {
+		d, ok := Lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown data set %q", name)
+		}
+		selected = append(selected, d)
+	}
+	return selected, nil
+}
+
+func fetch(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxDownloadSize {
+		return nil, fmt.Errorf("GET %s: response exceeds %d bytes", url, maxDownloadSize)
+	}
+	return body, nil
+}
@@ -0,0 +1,172 @@
+// Package history keeps a local record of interactive scans so they can be
+// reopened, re-run with the same settings, or deleted later. Each scan is
+// stored as one JSON file in the history directory, encrypted when the store
+// has a key.
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/internal/atomicfile"
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/vault"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// Entry is one recorded scan.
+type Entry struct {
+	ID        string       `json:"id"`
+	Target    types.Target `json:"target"`
+	Scanners  []string     `json:"scanners"`
+	CreatedAt time.Time    `json:"created_at"`
+	// Settings holds the scan options as entered, keyed by option name,
+	// so the scan can be re-run with the same settings.
+	Settings map[string]string  `json:"settings,omitempty"`
+	Results  []types.ScanResult `json:"results"`
+}
+
+// FindingCount returns the total number of findings across all results.
+func (e Entry) FindingCount() int {
+	n := 0
+	for _, r := range e.Results {
+		n += len(r.Findings)
+	}
+	return n
+}
+
+// Store is a local history directory.
+type Store struct {
+	Dir string
+
+	// Key, when set, encrypts the entries the store saves. Entries saved
+	// without one can still be read, and are encrypted when saved again.
+	Key vault.Key
+}
+
+// NewStore returns a store rooted at dir, or at DefaultDir when dir is empty.
+func NewStore(dir string) *Store {
+	if dir == "" {
+		dir = DefaultDir()
+	}
+	return &Store{Dir: dir}
+}
+
+// DefaultDir returns the default history directory (~/.hunter/history).
+func DefaultDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".hunter", "history")
+	}
+	return filepath.Join(home, ".hunter", "history")
+}
+
+// newID derives an entry ID from its creation time. Extracted as a variable
+// for testing.
+var newID = func(t time.Time) string {
+	return t.UTC().Format("20060102T150405.000000000Z")
+}
+
+// Save records e, assigning an ID and creation time if it has none, and
+// returns the stored entry.
+func (s *Store) Save(e Entry) (Entry, error) {
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+	if e.ID == "" {
+		e.ID = newID(e.CreatedAt)
+	}
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return e, err
+	}
+	if s.Key != nil {
+		if data, err = vault.Seal(s.Key, data); err != nil {
+			return e, fmt.Errorf("encrypting scan history: %w", err)
+		}
+	}
+	if err := atomicfile.Write(s.path(e.ID), data); err != nil {
+		return e, fmt.Errorf("saving scan history: %w", err)
+	}
+	return e, nil
+}
+
+// List returns every recorded scan, newest first. Files that cannot be read
+// are skipped. A missing directory yields an empty history.
+func (s *Store) List() ([]Entry, error) {
+	files, err := os.ReadDir(s.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading scan history: %w", err)
+	}
+
+	var entries []Entry
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		e, err := s.Load(strings.TrimSuffix(f.Name(), ".json"))
+		if err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.After(entries[j].CreatedAt)
+	})
+	return entries, nil
+}
+
+// Load reads the entry with the given ID.
+func (s *Store) Load(id string) (Entry, error) {
+	var e Entry
+	data, err := os.ReadFile(s.path(id))
+	if err != nil {
+		return e, err
+	}
+	if vault.Sealed(data) {
+		if s.Key == nil {
+			return e, fmt.Errorf("scan history %s is encrypted; set encryption_key to read it", id)
+		}
+		if data, err = vault.Open(s.Key, data); err != nil {
+			return e, fmt.Errorf("decrypting scan history %s: %w", id, err)
+		}
+	}
+	if err := json.Unmarshal(data, &e); err != nil {
+		return e, fmt.Errorf("parsing scan history %s: %w", id, err)
+	}
+	return e, nil
+}
+
+// Durations returns how long each scanner took on average across the
+// recorded scans, for weighting a scan's time budget. An unreadable history
+// yields none.
+func (s *Store) Durations() map[string]time.Duration {
+	entries, _ := s.List()
+	var results []types.ScanResult
+	for _, e := range entries {
+		results = append(results, e.Results...)
+	}
+	return scanner.ObservedDurations(results)
+}
+
+// Delete removes the entry with the given ID.
+func (s *Store) Delete(id string) error {
+	if err := os.Remove(s.path(id)); err != nil {
+		return fmt.Errorf("deleting scan history: %w", err)
+	}
+	return nil
+}
+
+// path returns the file for an entry. IDs are reduced to their base name so
+// an ID can never point outside the history directory.
+func (s *Store) path(id string) string {
+	return filepath.Join(s.Dir, filepath.Base(id)+".json")
+}
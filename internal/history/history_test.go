@@ -0,0 +1,110 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/buemura/hunter/internal/vault"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreSaveListLoadDelete(t *testing.T) {
+	s := NewStore(t.TempDir())
+
+	entries, err := s.List()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	now := time.Now()
+	older, err := s.Save(Entry{
+		Target:    types.Target{Host: "example.com", Scheme: "https"},
+		Scanners:  []string{"headers"},
+		CreatedAt: now.Add(-time.Hour),
+		Settings:  map[string]string{"timeout": "2s"},
+		Results: []types.ScanResult{{ScannerName: "headers", Findings: []types.Finding{
+			{Title: "Missing HSTS", Severity: types.SeverityHigh},
+		}}},
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, older.ID)
+
+	newer, err := s.Save(Entry{Target: types.Target{Host: "example.org"}, Scanners: []string{"ssl"}, CreatedAt: now})
+	require.NoError(t, err)
+
+	// Unreadable files are skipped.
+	require.NoError(t, os.WriteFile(filepath.Join(s.Dir, "broken.json"), []byte("{"), 0o644))
+
+	entries, err = s.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, newer.ID, entries[0].ID)
+	assert.Equal(t, older.ID, entries[1].ID)
+	assert.Equal(t, 1, entries[1].FindingCount())
+	assert.Equal(t, "2s", entries[1].Settings["timeout"])
+
+	require.NoError(t, s.Delete(older.ID))
+	_, err = s.Load(older.ID)
+	assert.Error(t, err)
+	assert.Error(t, s.Delete(older.ID))
+}
+
+func TestStorePathStaysInDir(t *testing.T) {
+	s := NewStore("/tmp/history")
+	assert.Equal(t, "/tmp/history/passwd.json", s.path("../../etc/passwd"))
+}
+
+func TestStoreDurations(t *testing.T) {
+	s := NewStore(t.TempDir())
+	assert.Empty(t, s.Durations())
+
+	start := time.Now().UTC()
+	for _, d := range []time.Duration{4 * time.Second, 8 * time.Second} {
+		_, err := s.Save(Entry{Scanners: []string{"dirs"}, Results: []types.ScanResult{
+			{ScannerName: "dirs", StartedAt: start, CompletedAt: start.Add(d)},
+		}})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, map[string]time.Duration{"dirs": 6 * time.Second}, s.Durations())
+}
+
+func TestStoreEncrypted(t *testing.T) {
+	key, err := vault.DeriveKey("history key")
+	require.NoError(t, err)
+	dir := t.TempDir()
+
+	// An entry saved before encryption was turned on stays readable.
+	plain, err := NewStore(dir).Save(Entry{Target: types.Target{Host: "old.example.com"}})
+	require.NoError(t, err)
+
+	s := &Store{Dir: dir, Key: key}
+	e, err := s.Save(Entry{Target: types.Target{Host: "secret.example.com"}})
+	require.NoError(t, err)
+
+	raw, err := os.ReadFile(s.path(e.ID))
+	require.NoError(t, err)
+	assert.True(t, vault.Sealed(raw))
+	assert.NotContains(t, string(raw), "secret.example.com")
+
+	loaded, err := s.Load(e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "secret.example.com", loaded.Target.Host)
+	loaded, err = s.Load(plain.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "old.example.com", loaded.Target.Host)
+
+	_, err = NewStore(dir).Load(e.ID)
+	assert.ErrorContains(t, err, "encrypted")
+
+	other, err := vault.DeriveKey("wrong key")
+	require.NoError(t, err)
+	_, err = (&Store{Dir: dir, Key: other}).Load(e.ID)
+	assert.ErrorIs(t, err, vault.ErrDecrypt)
+
+	entries, err := NewStore(dir).List()
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "entries that cannot be decrypted are skipped")
+}
@@ -0,0 +1,204 @@
+// Package i18n translates reports into languages other than English: the
+// text of the reports themselves, such as headings and summaries, and the
+// titles, descriptions, and remediations of findings.
+//
+// Each language is a JSON catalog in locales/, named by its language tag.
+// Its "messages" map report text, which may hold fmt verbs, to the
+// translation. Its "findings" map finding text to the translation, with a
+// "*" standing for a part that varies, such as a port or a path; the parts
+// are carried over in order. Text a catalog does not cover stays in English.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// English is the tag of the language Hunter's text is written in, which
+// needs no catalog.
+const English = "en"
+
+//go:embed locales/*.json
+var locales embed.FS
+
+// Locale translates text into a language. A nil *Locale leaves text in
+// English.
+type Locale struct {
+	tag      string
+	messages map[string]string
+	exact    map[string]string
+	patterns []pattern
+}
+
+// pattern is a finding text with varying parts and its translation.
+type pattern struct {
+	re          *regexp.Regexp
+	translation string
+}
+
+// catalog is the JSON form of a language's translations.
+type catalog struct {
+	Messages map[string]string `json:"messages"`
+	Findings map[string]string `json:"findings"`
+}
+
+// Tags returns the tags of the supported languages, English first.
+func Tags() []string {
+	tags := []string{English}
+	entries, _ := locales.ReadDir("locales")
+	for _, e := range entries {
+		tags = append(tags, strings.TrimSuffix(e.Name(), path.Ext(e.Name())))
+	}
+	sort.Strings(tags[1:])
+	return tags
+}
+
+// Load returns the locale for a language tag such as "pt-BR", matched
+// case-insensitively; a bare language such as "pt" selects its only
+// regional variant. An empty tag, or English, returns a nil *Locale.
+func Load(tag string) (*Locale, error) {
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+	if tag == "" || strings.EqualFold(tag, English) {
+		return nil, nil
+	}
+
+	var matches []string
+	for _, t := range Tags()[1:] {
+		if strings.EqualFold(t, tag) {
+			matches = []string{t}
+			break
+		}
+		if lang, _, _ := strings.Cut(t, "-"); strings.EqualFold(lang, tag) {
+			matches = append(matches, t)
+		}
+	}
+	if len(matches) != 1 {
+		return nil, fmt.Errorf("unsupported language %q (supported: %s)", tag, strings.Join(Tags(), ", "))
+	}
+
+	raw, err := locales.ReadFile("locales/" + matches[0] + ".json")
+	if err != nil {
+		return nil, err
+	}
+	return parse(matches[0], raw)
+}
+
+func parse(tag string, raw []byte) (*Locale, error) {
+	var c catalog
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, fmt.Errorf("locale %s: %w", tag, err)
+	}
+
+	l := &Locale{tag: tag, messages: c.Messages, exact: map[string]string{}}
+	for text, translation := range c.Findings {
+		if !strings.Contains(text, "*") {
+			l.exact[text] = translation
+			continue
+		}
+		if strings.Count(text, "*") != strings.Count(translation, "*") {
+			return nil, fmt.Errorf("locale %s: %q and its translation have different numbers of *", tag, text)
+		}
+		parts := strings.Split(text, "*")
+		for i, p := range parts {
+			parts[i] = regexp.QuoteMeta(p)
+		}
+		re := regexp.MustCompile("(?s)^" + strings.Join(parts, "(.*?)") + "$")
+		l.patterns = append(l.patterns, pattern{re: re, translation: translation})
+	}
+
+	// The longest pattern is the most specific, so it is tried first.
+	sort.Slice(l.patterns, func(i, j int) bool {
+		a, b := l.patterns[i].re.String(), l.patterns[j].re.String()
+		if len(a) != len(b) {
+			return len(a) > len(b)
+		}
+		return a < b
+	})
+	return l, nil
+}
+
+// Tag returns the locale's language tag.
+func (l *Locale) Tag() string {
+	if l == nil {
+		return English
+	}
+	return l.tag
+}
+
+// T translates a message of a report and formats it with args as
+// fmt.Sprintf does.
+func (l *Locale) T(msg string, args ...any) string {
+	if l != nil {
+		if translation, ok := l.messages[msg]; ok {
+			msg = translation
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// N translates and formats a count of something: one when n is 1, other
+// otherwise. Both take n as their first argument, such as "%d finding".
+func (l *Locale) N(n int, one, other string) string {
+	if n == 1 {
+		return l.T(one, n)
+	}
+	return l.T(other, n)
+}
+
+// Text translates the text of a finding.
+func (l *Locale) Text(s string) string {
+	if l == nil || s == "" {
+		return s
+	}
+	if translation, ok := l.exact[s]; ok {
+		return translation
+	}
+	for _, p := range l.patterns {
+		m := p.re.FindStringSubmatch(s)
+		if m == nil {
+			continue
+		}
+		var b strings.Builder
+		for i, part := range strings.Split(p.translation, "*") {
+			if i > 0 {
+				b.WriteString(m[i])
+			}
+			b.WriteString(part)
+		}
+		return b.String()
+	}
+	return s
+}
+
+// Results returns a copy of results with the titles, descriptions, and
+// remediations of their findings translated. The input is not modified.
+func (l *Locale) Results(results []types.ScanResult) []types.ScanResult {
+	if l == nil {
+		return results
+	}
+	out := make([]types.ScanResult, len(results))
+	for i, r := range results {
+		out[i] = r
+		if len(r.Findings) == 0 {
+			continue
+		}
+		out[i].Findings = make([]types.Finding, len(r.Findings))
+		for j, f := range r.Findings {
+			f.Title = l.Text(f.Title)
+			f.Description = l.Text(f.Description)
+			f.Remediation = l.Text(f.Remediation)
+			out[i].Findings[j] = f
+		}
+	}
+	return out
+}
@@ -0,0 +1,103 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/buemura/hunter/internal/rules"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTags(t *testing.T) {
+	tags := Tags()
+	assert.Equal(t, English, tags[0])
+	assert.Contains(t, tags, "pt-BR")
+}
+
+func TestLoad(t *testing.T) {
+	for _, tag := range []string{"", "en", "EN"} {
+		l, err := Load(tag)
+		require.NoError(t, err, tag)
+		assert.Nil(t, l, tag)
+	}
+
+	for _, tag := range []string{"pt-BR", "pt-br", "pt_BR", "pt"} {
+		l, err := Load(tag)
+		require.NoError(t, err, tag)
+		assert.Equal(t, "pt-BR", l.Tag(), tag)
+	}
+
+	_, err := Load("xx")
+	assert.ErrorContains(t, err, `unsupported language "xx" (supported: en, pt-BR)`)
+}
+
+func TestParse_MismatchedWildcards(t *testing.T) {
+	_, err := parse("xx", []byte(`{"findings": {"Open port: *": "Porta aberta"}}`))
+	assert.ErrorContains(t, err, "different numbers of *")
+}
+
+// TestCatalogs_CoverRules checks that every locale translates the title of
+// every rule, written with its "*" wildcards as a catalog would.
+func TestCatalogs_CoverRules(t *testing.T) {
+	for _, tag := range Tags()[1:] {
+		l, err := Load(tag)
+		require.NoError(t, err, tag)
+		for _, r := range rules.All() {
+			assert.NotEqual(t, r.Title, l.Text(r.Title), "%s: %s", tag, r.ID)
+		}
+	}
+}
+
+func TestLocale_Text(t *testing.T) {
+	l, err := Load("pt-BR")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Certificado autoassinado", l.Text("Self-signed certificate"))
+	assert.Equal(t, "Porta aberta: 80/http", l.Text("Open port: 80/http"))
+	assert.Equal(t, "O caminho /old redireciona (301) para /new", l.Text("Path /old redirects (301) to /new"))
+	assert.Equal(t, "Versão de TLS obsoleta: TLS 1.0", l.Text("Deprecated TLS version: TLS 1.0"))
+	assert.Equal(t, "Something new", l.Text("Something new"), "untranslated text stays in English")
+	assert.Equal(t, "", l.Text(""))
+
+	var en *Locale
+	assert.Equal(t, "Open port: 80/http", en.Text("Open port: 80/http"))
+}
+
+func TestLocale_T(t *testing.T) {
+	l, err := Load("pt-BR")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Resumo executivo", l.T("Executive Summary"))
+	assert.Equal(t, "3 achados", l.T("%d findings", 3))
+	assert.Equal(t, "1 achado", l.N(1, "%d finding", "%d findings"))
+	assert.Equal(t, "0 achados", l.N(0, "%d finding", "%d findings"))
+	assert.Equal(t, "Not in the catalog 2", l.T("Not in the catalog %d", 2))
+
+	var en *Locale
+	assert.Equal(t, "en", en.Tag())
+	assert.Equal(t, "1 finding", en.N(1, "%d finding", "%d findings"))
+}
+
+func TestLocale_Results(t *testing.T) {
+	l, err := Load("pt-BR")
+	require.NoError(t, err)
+
+	results := []types.ScanResult{{
+		ScannerName: "headers",
+		Findings: []types.Finding{{
+			Title:       "Missing X-Frame-Options header",
+			Description: "The X-Frame-Options header is not set. The page may be vulnerable to clickjacking attacks.",
+			Remediation: "Add the header: X-Frame-Options: DENY or X-Frame-Options: SAMEORIGIN",
+			Evidence:    "X-Frame-Options: <absent>",
+		}},
+	}}
+	out := l.Results(results)
+
+	f := out[0].Findings[0]
+	assert.Equal(t, "Cabeçalho X-Frame-Options ausente", f.Title)
+	assert.Contains(t, f.Description, "clickjacking")
+	assert.Contains(t, f.Remediation, "Adicione o cabeçalho")
+	assert.Equal(t, "X-Frame-Options: <absent>", f.Evidence, "evidence is left as recorded")
+	assert.Equal(t, "Missing X-Frame-Options header", results[0].Findings[0].Title, "the input is not modified")
+}
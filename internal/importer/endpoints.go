@@ -0,0 +1,103 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// LoadEndpoints reads API scope from a HAR file or a Postman collection
+// (v2.0 or v2.1), telling them apart by their contents.
+func LoadEndpoints(path string) ([]types.Endpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading endpoints: %w", err)
+	}
+
+	var probe struct {
+		Log  json.RawMessage `json:"log"`
+		Info json.RawMessage `json:"info"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("reading endpoints: %s is not JSON: %w", path, err)
+	}
+	switch {
+	case probe.Log != nil:
+		return ParseHAR(bytes.NewReader(data))
+	case probe.Info != nil:
+		return ParsePostman(bytes.NewReader(data))
+	}
+	return nil, fmt.Errorf("reading endpoints: %s is neither a HAR file nor a Postman collection", path)
+}
+
+// droppedHeaders are request headers not worth replaying: set by the HTTP
+// client itself, or, with Accept-Encoding, stopping it from decompressing
+// responses.
+var droppedHeaders = map[string]bool{
+	"host":              true,
+	"content-length":    true,
+	"connection":        true,
+	"accept-encoding":   true,
+	"transfer-encoding": true,
+	"keep-alive":        true,
+	"upgrade":           true,
+	"te":                true,
+}
+
+// credentialHeader reports whether a header carries credentials. Captures
+// hold the session they were recorded in; scans authenticate with
+// --credential instead, and the auth scanner must be able to send requests
+// without any.
+func credentialHeader(name string) bool {
+	name = strings.ToLower(name)
+	switch name {
+	case "authorization", "proxy-authorization", "cookie":
+		return true
+	}
+	return strings.Contains(name, "api-key") || strings.Contains(name, "apikey") || strings.Contains(name, "token")
+}
+
+// endpointSet collects endpoints, keeping the first of each method and URL
+// in the order they were added.
+type endpointSet struct {
+	seen      map[string]bool
+	endpoints []types.Endpoint
+}
+
+// add adds an endpoint, dropping the headers not worth replaying.
+func (s *endpointSet) add(method, rawURL string, headers [][2]string, body string) {
+	method = strings.ToUpper(strings.TrimSpace(method))
+	if method == "" {
+		method = http.MethodGet
+	}
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return
+	}
+	key := method + " " + rawURL
+	if s.seen == nil {
+		s.seen = map[string]bool{}
+	}
+	if s.seen[key] {
+		return
+	}
+	s.seen[key] = true
+
+	ep := types.Endpoint{Method: method, URL: rawURL, Body: body}
+	for _, h := range headers {
+		name := strings.TrimSpace(h[0])
+		if name == "" || strings.HasPrefix(name, ":") || droppedHeaders[strings.ToLower(name)] || credentialHeader(name) {
+			continue
+		}
+		if ep.Headers == nil {
+			ep.Headers = map[string]string{}
+		}
+		ep.Headers[http.CanonicalHeaderKey(name)] = h[1]
+	}
+	s.endpoints = append(s.endpoints, ep)
+}
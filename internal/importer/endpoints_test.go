@@ -0,0 +1,117 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const harJSON = `{
+  "log": {
+    "version": "1.2",
+    "entries": [
+      {"request": {"method": "GET", "url": "https://api.example.com/v1/users?page=2", "headers": [
+        {"name": ":authority", "value": "api.example.com"},
+        {"name": "accept", "value": "application/json"},
+        {"name": "Cookie", "value": "session=abc"},
+        {"name": "Authorization", "value": "Bearer secret"},
+        {"name": "X-Api-Key", "value": "k"},
+        {"name": "Accept-Encoding", "value": "gzip"}
+      ]}},
+      {"request": {"method": "post", "url": "https://api.example.com/v1/users", "headers": [],
+        "postData": {"mimeType": "application/json", "text": "{\"name\":\"a\"}"}}},
+      {"request": {"method": "GET", "url": "https://api.example.com/v1/users?page=2", "headers": []}}
+    ]
+  }
+}`
+
+func TestParseHAR(t *testing.T) {
+	endpoints, err := ParseHAR(strings.NewReader(harJSON))
+	require.NoError(t, err)
+	require.Len(t, endpoints, 2, "repeated requests are imported once")
+
+	assert.Equal(t, types.Endpoint{
+		Method:  "GET",
+		URL:     "https://api.example.com/v1/users?page=2",
+		Headers: map[string]string{"Accept": "application/json"},
+	}, endpoints[0], "credentials, pseudo-headers, and transport headers are dropped")
+	assert.Equal(t, types.Endpoint{
+		Method:  "POST",
+		URL:     "https://api.example.com/v1/users",
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    `{"name":"a"}`,
+	}, endpoints[1])
+}
+
+const postmanJSON = `{
+  "info": {"name": "Users", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+  "variable": [{"key": "version", "value": "v1"}],
+  "item": [
+    {"name": "users", "item": [
+      {"name": "list", "request": {"method": "GET", "url": {"raw": "{{baseUrl}}/{{version}}/users"},
+        "header": [{"key": "Accept", "value": "application/json"}, {"key": "X-Debug", "value": "1", "disabled": true}]}},
+      {"name": "create", "request": {"method": "POST", "url": "{{baseUrl}}/{{version}}/users",
+        "header": [{"key": "Authorization", "value": "Bearer {{token}}"}],
+        "body": {"mode": "raw", "raw": "{\"name\":\"a\"}", "options": {"raw": {"language": "json"}}}}}
+    ]},
+    {"name": "login", "request": {"method": "POST", "url": "https://api.example.com/login",
+      "body": {"mode": "urlencoded", "urlencoded": [{"key": "user", "value": "a"}, {"key": "pass", "value": "b"}]}}},
+    {"name": "graphql", "request": {"method": "POST", "url": "https://api.example.com/graphql",
+      "body": {"mode": "graphql", "graphql": {"query": "{ me { id } }", "variables": ""}}}},
+    {"name": "health", "request": "https://api.example.com/health"}
+  ]
+}`
+
+func TestParsePostman(t *testing.T) {
+	endpoints, err := ParsePostman(strings.NewReader(postmanJSON))
+	require.NoError(t, err)
+	require.Len(t, endpoints, 5)
+
+	assert.Equal(t, types.Endpoint{
+		Method:  "GET",
+		URL:     "/v1/users",
+		Headers: map[string]string{"Accept": "application/json"},
+	}, endpoints[0], "an undefined server variable leaves a relative path; disabled headers are skipped")
+	assert.Equal(t, types.Endpoint{
+		Method:  "POST",
+		URL:     "/v1/users",
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    `{"name":"a"}`,
+	}, endpoints[1], "credentials are dropped")
+	assert.Equal(t, "pass=b&user=a", endpoints[2].Body)
+	assert.Equal(t, "application/x-www-form-urlencoded", endpoints[2].Headers["Content-Type"])
+	assert.JSONEq(t, `{"query":"{ me { id } }"}`, endpoints[3].Body)
+	assert.Equal(t, types.Endpoint{Method: "GET", URL: "https://api.example.com/health"}, endpoints[4],
+		"a request given as a URL string defaults to GET")
+}
+
+func TestLoadEndpoints(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	endpoints, err := LoadEndpoints(write("capture.har", harJSON))
+	require.NoError(t, err)
+	assert.Len(t, endpoints, 2)
+
+	endpoints, err = LoadEndpoints(write("collection.json", postmanJSON))
+	require.NoError(t, err)
+	assert.Len(t, endpoints, 5)
+
+	_, err = LoadEndpoints(write("other.json", `{"openapi": "3.0.0"}`))
+	assert.ErrorContains(t, err, "neither a HAR file nor a Postman collection")
+
+	_, err = LoadEndpoints(write("broken.json", `not json`))
+	assert.ErrorContains(t, err, "is not JSON")
+
+	_, err = LoadEndpoints(filepath.Join(dir, "missing.har"))
+	assert.Error(t, err)
+}
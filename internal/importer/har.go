@@ -0,0 +1,58 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// harFile is the subset of the HAR 1.2 format that is imported.
+type harFile struct {
+	Log struct {
+		Entries []struct {
+			Request struct {
+				Method  string `json:"method"`
+				URL     string `json:"url"`
+				Headers []struct {
+					Name  string `json:"name"`
+					Value string `json:"value"`
+				} `json:"headers"`
+				PostData *struct {
+					MimeType string `json:"mimeType"`
+					Text     string `json:"text"`
+				} `json:"postData"`
+			} `json:"request"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+// ParseHAR extracts the requests of a HAR file, as exported by browser
+// developer tools and intercepting proxies, as endpoints: one per distinct
+// method and URL, with their headers and body. Credentials (cookies,
+// Authorization, and API key headers) are left out.
+func ParseHAR(r io.Reader) ([]types.Endpoint, error) {
+	var har harFile
+	if err := json.NewDecoder(r).Decode(&har); err != nil {
+		return nil, fmt.Errorf("parsing HAR: %w", err)
+	}
+
+	var set endpointSet
+	for _, e := range har.Log.Entries {
+		req := e.Request
+		headers := make([][2]string, 0, len(req.Headers)+1)
+		for _, h := range req.Headers {
+			headers = append(headers, [2]string{h.Name, h.Value})
+		}
+		body := ""
+		if req.PostData != nil {
+			body = req.PostData.Text
+			if req.PostData.MimeType != "" {
+				headers = append(headers, [2]string{"Content-Type", req.PostData.MimeType})
+			}
+		}
+		set.add(req.Method, req.URL, headers, body)
+	}
+	return set.endpoints, nil
+}
@@ -0,0 +1,202 @@
+// Package importer converts the output of other security tools into Hunter
+// scan results, so existing recon data can be reported, filtered, and
+// compared like Hunter's own.
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/internal/rules"
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/scanner/port"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// nmapRun is the subset of nmap's XML output (-oX) that is imported.
+type nmapRun struct {
+	XMLName xml.Name   `xml:"nmaprun"`
+	Args    string     `xml:"args,attr"`
+	Version string     `xml:"version,attr"`
+	Start   int64      `xml:"start,attr"`
+	Hosts   []nmapHost `xml:"host"`
+	Finish  struct {
+		Time int64 `xml:"time,attr"`
+	} `xml:"runstats>finished"`
+}
+
+type nmapHost struct {
+	StartTime int64 `xml:"starttime,attr"`
+	EndTime   int64 `xml:"endtime,attr"`
+	Status    struct {
+		State string `xml:"state,attr"`
+	} `xml:"status"`
+	Addresses []struct {
+		Addr     string `xml:"addr,attr"`
+		AddrType string `xml:"addrtype,attr"`
+	} `xml:"address"`
+	Hostnames []struct {
+		Name string `xml:"name,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"hostnames>hostname"`
+	Ports []nmapPort `xml:"ports>port"`
+}
+
+type nmapPort struct {
+	Protocol string `xml:"protocol,attr"`
+	PortID   int    `xml:"portid,attr"`
+	State    struct {
+		State  string `xml:"state,attr"`
+		Reason string `xml:"reason,attr"`
+	} `xml:"state"`
+	Service struct {
+		Name      string `xml:"name,attr"`
+		Product   string `xml:"product,attr"`
+		Version   string `xml:"version,attr"`
+		ExtraInfo string `xml:"extrainfo,attr"`
+		Tunnel    string `xml:"tunnel,attr"`
+	} `xml:"service"`
+}
+
+// ParseNmap converts nmap XML output into one port scanner result per host
+// that was up. Each open port becomes a finding like the port scanner's own,
+// with the service nmap identified, and the result's target lists the open
+// TCP ports, so a port scan of it rechecks just those.
+func ParseNmap(r io.Reader) ([]types.ScanResult, error) {
+	var run nmapRun
+	if err := xml.NewDecoder(r).Decode(&run); err != nil {
+		return nil, fmt.Errorf("parsing nmap XML: %w", err)
+	}
+
+	var results []types.ScanResult
+	for _, h := range run.Hosts {
+		if h.Status.State != "" && h.Status.State != "up" {
+			continue
+		}
+		target, family, ok := h.target()
+		if !ok {
+			continue
+		}
+
+		result := types.ScanResult{
+			ScannerName: "port",
+			StartedAt:   unixTime(h.StartTime, run.Start),
+			CompletedAt: unixTime(h.EndTime, run.Finish.Time),
+			Metadata:    map[string]string{"source": "nmap"},
+		}
+		if run.Version != "" {
+			result.Metadata["nmap_version"] = run.Version
+		}
+		if run.Args != "" {
+			result.Metadata["nmap_args"] = run.Args
+		}
+
+		var open []string
+		for _, p := range h.Ports {
+			if p.State.State != "open" {
+				continue
+			}
+			result.Findings = append(result.Findings, p.finding(family))
+			if p.Protocol == "tcp" {
+				target.Ports = append(target.Ports, p.PortID)
+			}
+			open = append(open, fmt.Sprintf("%d/%s", p.PortID, p.Protocol))
+		}
+		sort.Ints(target.Ports)
+		if len(open) > 0 {
+			result.Metadata["open_ports"] = strings.Join(open, ",")
+		}
+		result.Target = target
+		rules.Label(&result)
+		for i := range result.Findings {
+			result.Findings[i].Fingerprint = types.Fingerprint(result.ScannerName, result.Findings[i])
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// target returns the host as a scan target, named by the host name given on
+// the nmap command line when there is one, and the family of its address.
+func (h nmapHost) target() (types.Target, string, bool) {
+	var addr, family string
+	for _, a := range h.Addresses {
+		if a.AddrType == scanner.FamilyIPv4 || a.AddrType == scanner.FamilyIPv6 {
+			addr, family = a.Addr, a.AddrType
+			break
+		}
+	}
+	host := addr
+	for _, name := range h.Hostnames {
+		if name.Type == "user" {
+			host = name.Name
+			break
+		}
+	}
+	if host == "" {
+		return types.Target{}, "", false
+	}
+	target, err := types.ParseTarget(host)
+	if err != nil {
+		return types.Target{}, "", false
+	}
+	return target, family, true
+}
+
+// finding returns the finding for an open port, matching the port scanner's.
+func (p nmapPort) finding(family string) types.Finding {
+	svc := port.IdentifyService(p.PortID)
+	if p.Service.Name != "" {
+		svc = p.Service.Name
+		if p.Service.Tunnel != "" {
+			svc = p.Service.Tunnel + "/" + svc
+		}
+	}
+	detail := svc
+	if product := strings.TrimSpace(strings.Join([]string{p.Service.Product, p.Service.Version}, " ")); product != "" {
+		detail += ": " + product
+	}
+
+	meta := map[string]string{
+		"port":     strconv.Itoa(p.PortID),
+		"protocol": p.Protocol,
+		"service":  svc,
+		"source":   "nmap",
+	}
+	if family != "" {
+		meta["address_family"] = family
+	}
+	for key, value := range map[string]string{
+		"product":    p.Service.Product,
+		"version":    p.Service.Version,
+		"extra_info": p.Service.ExtraInfo,
+		"reason":     p.State.Reason,
+	} {
+		if value != "" {
+			meta[key] = value
+		}
+	}
+
+	return types.Finding{
+		Title:       fmt.Sprintf("Open port: %d/%s", p.PortID, svc),
+		Description: fmt.Sprintf("%s port %d is open (%s)", strings.ToUpper(p.Protocol), p.PortID, detail),
+		Severity:    types.SeverityInfo,
+		Metadata:    meta,
+	}
+}
+
+// unixTime returns the first non-zero of the given Unix timestamps as a
+// time, or the zero time.
+func unixTime(stamps ...int64) time.Time {
+	for _, s := range stamps {
+		if s > 0 {
+			return time.Unix(s, 0)
+		}
+	}
+	return time.Time{}
+}
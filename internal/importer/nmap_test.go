@@ -0,0 +1,88 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const nmapXML = `<?xml version="1.0" encoding="UTF-8"?>
+<nmaprun scanner="nmap" args="nmap -sV -oX - example.com 10.0.0.9" start="1700000000" version="7.94">
+  <host starttime="1700000001" endtime="1700000060">
+    <status state="up" reason="syn-ack"/>
+    <address addr="93.184.216.34" addrtype="ipv4"/>
+    <hostnames>
+      <hostname name="example.com" type="user"/>
+      <hostname name="edge.example.net" type="PTR"/>
+    </hostnames>
+    <ports>
+      <port protocol="tcp" portid="443">
+        <state state="open" reason="syn-ack"/>
+        <service name="http" product="nginx" version="1.25.3" tunnel="ssl"/>
+      </port>
+      <port protocol="tcp" portid="22">
+        <state state="open" reason="syn-ack"/>
+        <service name="ssh" product="OpenSSH" version="9.6"/>
+      </port>
+      <port protocol="tcp" portid="25">
+        <state state="filtered" reason="no-response"/>
+      </port>
+      <port protocol="udp" portid="53">
+        <state state="open" reason="udp-response"/>
+      </port>
+    </ports>
+  </host>
+  <host>
+    <status state="down" reason="no-response"/>
+    <address addr="10.0.0.9" addrtype="ipv4"/>
+  </host>
+  <runstats><finished time="1700000061"/></runstats>
+</nmaprun>`
+
+func TestParseNmap(t *testing.T) {
+	results, err := ParseNmap(strings.NewReader(nmapXML))
+	require.NoError(t, err)
+	require.Len(t, results, 1, "hosts that were down are skipped")
+
+	r := results[0]
+	assert.Equal(t, "port", r.ScannerName)
+	assert.Equal(t, "example.com", r.Target.Host, "the host name given to nmap is kept")
+	assert.Equal(t, []int{22, 443}, r.Target.Ports, "open TCP ports are seeded on the target")
+	assert.Equal(t, int64(1700000001), r.StartedAt.Unix())
+	assert.Equal(t, int64(1700000060), r.CompletedAt.Unix())
+	assert.Equal(t, "nmap", r.Metadata["source"])
+	assert.Equal(t, "7.94", r.Metadata["nmap_version"])
+	assert.Equal(t, "443/tcp,22/tcp,53/udp", r.Metadata["open_ports"])
+
+	require.Len(t, r.Findings, 3)
+	https := r.Findings[0]
+	assert.Equal(t, "Open port: 443/ssl/http", https.Title)
+	assert.Equal(t, "TCP port 443 is open (ssl/http: nginx 1.25.3)", https.Description)
+	assert.Equal(t, types.SeverityInfo, https.Severity)
+	assert.Equal(t, "443", https.Metadata["port"])
+	assert.Equal(t, "nginx", https.Metadata["product"])
+	assert.Equal(t, "HUNTER-PORT-001", https.Metadata["rule_id"])
+	assert.Equal(t, "ipv4", https.Metadata["address_family"])
+	assert.Equal(t, types.Fingerprint("port", https), https.Fingerprint)
+
+	dns := r.Findings[2]
+	assert.Equal(t, "Open port: 53/DNS", dns.Title, "ports nmap did not identify are named like the port scanner does")
+	assert.Equal(t, "udp", dns.Metadata["protocol"])
+}
+
+func TestParseNmap_AddressWithoutHostName(t *testing.T) {
+	xml := `<nmaprun><host><status state="up"/><address addr="00:11:22:33:44:55" addrtype="mac"/><address addr="2001:db8::1" addrtype="ipv6"/></host></nmaprun>`
+	results, err := ParseNmap(strings.NewReader(xml))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "2001:db8::1", results[0].Target.Host)
+	assert.Empty(t, results[0].Findings)
+}
+
+func TestParseNmap_Invalid(t *testing.T) {
+	_, err := ParseNmap(strings.NewReader(`{"not": "xml"}`))
+	assert.ErrorContains(t, err, "parsing nmap XML")
+}
@@ -0,0 +1,178 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// postmanCollection is the subset of the Postman collection v2.0 and v2.1
+// formats that is imported.
+type postmanCollection struct {
+	Variables []postmanKeyValue `json:"variable"`
+	Items     []postmanItem     `json:"item"`
+}
+
+type postmanItem struct {
+	Items   []postmanItem   `json:"item"` // set on folders
+	Request json.RawMessage `json:"request"`
+}
+
+type postmanRequest struct {
+	Method  string            `json:"method"`
+	Headers []postmanKeyValue `json:"header"`
+	URL     json.RawMessage   `json:"url"`
+	Body    *struct {
+		Mode       string            `json:"mode"`
+		Raw        string            `json:"raw"`
+		URLEncoded []postmanKeyValue `json:"urlencoded"`
+		GraphQL    *struct {
+			Query     string `json:"query"`
+			Variables string `json:"variables"`
+		} `json:"graphql"`
+		Options struct {
+			Raw struct {
+				Language string `json:"language"`
+			} `json:"raw"`
+		} `json:"options"`
+	} `json:"body"`
+}
+
+type postmanKeyValue struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled"`
+}
+
+// postmanVariable matches a {{variable}} reference.
+var postmanVariable = regexp.MustCompile(`{{\s*([^{}]+?)\s*}}`)
+
+// ParsePostman extracts the requests of a Postman collection (v2.0 or v2.1)
+// as endpoints, with their headers and example bodies. {{variables}} are
+// filled in from the collection's variables; a URL starting with one that
+// is not defined there, like {{baseUrl}}, is kept as a path relative to the
+// target. Credentials are left out, as with ParseHAR.
+func ParsePostman(r io.Reader) ([]types.Endpoint, error) {
+	var c postmanCollection
+	if err := json.NewDecoder(r).Decode(&c); err != nil {
+		return nil, fmt.Errorf("parsing Postman collection: %w", err)
+	}
+
+	vars := map[string]string{}
+	for _, v := range c.Variables {
+		if !v.Disabled {
+			vars[v.Key] = v.Value
+		}
+	}
+	expand := func(s string) string {
+		return postmanVariable.ReplaceAllStringFunc(s, func(ref string) string {
+			name := postmanVariable.FindStringSubmatch(ref)[1]
+			if value, ok := vars[name]; ok {
+				return value
+			}
+			return ref
+		})
+	}
+
+	var set endpointSet
+	var walk func(items []postmanItem)
+	walk = func(items []postmanItem) {
+		for _, item := range items {
+			walk(item.Items)
+			if len(item.Request) == 0 {
+				continue
+			}
+			req, err := parsePostmanRequest(item.Request)
+			if err != nil {
+				continue
+			}
+			rawURL := expand(postmanURL(req.URL))
+			// An undefined variable standing for the server leaves a path.
+			if loc := postmanVariable.FindStringIndex(rawURL); loc != nil && loc[0] == 0 {
+				rawURL = "/" + strings.TrimLeft(rawURL[loc[1]:], "/")
+			}
+
+			var headers [][2]string
+			for _, h := range req.Headers {
+				if !h.Disabled {
+					headers = append(headers, [2]string{h.Key, expand(h.Value)})
+				}
+			}
+			body, contentType := postmanBody(req)
+			if contentType != "" {
+				headers = append([][2]string{{"Content-Type", contentType}}, headers...)
+			}
+			set.add(req.Method, rawURL, headers, expand(body))
+		}
+	}
+	walk(c.Items)
+	return set.endpoints, nil
+}
+
+// parsePostmanRequest decodes a request, which may also be given as just a
+// URL string.
+func parsePostmanRequest(raw json.RawMessage) (postmanRequest, error) {
+	var req postmanRequest
+	var rawURL string
+	if err := json.Unmarshal(raw, &rawURL); err == nil {
+		req.URL, _ = json.Marshal(rawURL)
+		return req, nil
+	}
+	err := json.Unmarshal(raw, &req)
+	return req, err
+}
+
+// postmanURL returns a request URL, given as a string or as an object.
+func postmanURL(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var u struct {
+		Raw string `json:"raw"`
+	}
+	json.Unmarshal(raw, &u)
+	return u.Raw
+}
+
+// postmanBody returns a request's example body and the content type it
+// implies. Form-data bodies, which may carry files, are left out.
+func postmanBody(req postmanRequest) (body, contentType string) {
+	if req.Body == nil {
+		return "", ""
+	}
+	switch req.Body.Mode {
+	case "raw":
+		if strings.EqualFold(req.Body.Options.Raw.Language, "json") {
+			contentType = "application/json"
+		}
+		return req.Body.Raw, contentType
+	case "urlencoded":
+		form := url.Values{}
+		for _, kv := range req.Body.URLEncoded {
+			if !kv.Disabled {
+				form.Add(kv.Key, kv.Value)
+			}
+		}
+		return form.Encode(), "application/x-www-form-urlencoded"
+	case "graphql":
+		if req.Body.GraphQL == nil {
+			return "", ""
+		}
+		payload := map[string]interface{}{"query": req.Body.GraphQL.Query}
+		if v := strings.TrimSpace(req.Body.GraphQL.Variables); v != "" {
+			payload["variables"] = json.RawMessage(v)
+		}
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return "", ""
+		}
+		return string(data), "application/json"
+	}
+	return "", ""
+}
@@ -0,0 +1,155 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// BurpFormatter renders results as a Burp Suite issues XML export, one
+// issue per finding, so they can be loaded alongside Burp's own for
+// manual follow-up. Scanner errors are left out.
+type BurpFormatter struct{}
+
+type burpIssues struct {
+	XMLName     xml.Name `xml:"issues"`
+	BurpVersion string   `xml:"burpVersion,attr"`
+	ExportTime  string   `xml:"exportTime,attr"`
+	// Engagement is not part of Burp's export; tools reading it skip it.
+	Engagement *burpEngagement `xml:"engagement,omitempty"`
+	Issues     []burpIssue     `xml:"issue"`
+}
+
+type burpEngagement struct {
+	Client        string `xml:"client,omitempty"`
+	ID            string `xml:"id,omitempty"`
+	Tester        string `xml:"tester,omitempty"`
+	Authorization string `xml:"authorization,omitempty"`
+	Notes         string `xml:"notes,omitempty"`
+}
+
+type burpIssue struct {
+	SerialNumber          int      `xml:"serialNumber"`
+	Type                  int      `xml:"type"`
+	Name                  string   `xml:"name"`
+	Host                  burpHost `xml:"host"`
+	Path                  cdata    `xml:"path"`
+	Location              cdata    `xml:"location"`
+	Severity              string   `xml:"severity"`
+	Confidence            string   `xml:"confidence"`
+	IssueBackground       *cdata   `xml:"issueBackground,omitempty"`
+	RemediationBackground *cdata   `xml:"remediationBackground,omitempty"`
+	References            *cdata   `xml:"references,omitempty"`
+	IssueDetail           *cdata   `xml:"issueDetail,omitempty"`
+}
+
+type burpHost struct {
+	IP  string `xml:"ip,attr"`
+	URL string `xml:",chardata"`
+}
+
+type cdata struct {
+	Text string `xml:",cdata"`
+}
+
+// burpExtensionIssue is the issue type Burp gives issues reported by
+// extensions, which is what imported issues are.
+const burpExtensionIssue = 134217728
+
+func (f *BurpFormatter) Format(w io.Writer, results []types.ScanResult) error {
+	doc := burpIssues{BurpVersion: "hunter"}
+
+	var exported time.Time
+	for _, result := range results {
+		if result.CompletedAt.After(exported) {
+			exported = result.CompletedAt
+		}
+		if result.Error != "" {
+			continue
+		}
+
+		for _, finding := range result.Findings {
+			uri, _, param := findingLocation(result.Target, finding)
+			site, path := uri, "/"
+			if u, err := url.Parse(uri); err == nil && u.Host != "" {
+				site = u.Scheme + "://" + u.Host
+				path = u.RequestURI()
+			}
+			location := path
+			if param != "" {
+				location += fmt.Sprintf(" [%s parameter]", param)
+			}
+
+			issue := burpIssue{
+				SerialNumber: len(doc.Issues) + 1,
+				Type:         burpExtensionIssue,
+				Name:         finding.Title,
+				Host:         burpHost{IP: finding.Metadata["address"], URL: site},
+				Path:         cdata{path},
+				Location:     cdata{location},
+				Severity:     burpSeverity(finding.Severity),
+				Confidence:   burpConfidence(finding),
+			}
+			if finding.Description != "" {
+				issue.IssueBackground = &cdata{htmlParagraph(finding.Description)}
+			}
+			if finding.Remediation != "" {
+				issue.RemediationBackground = &cdata{htmlParagraph(finding.Remediation)}
+			}
+			if len(finding.References) > 0 {
+				issue.References = &cdata{htmlReferences(finding.References)}
+			}
+			if finding.Evidence != "" {
+				issue.IssueDetail = &cdata{"<p>Evidence:</p><pre>" + html.EscapeString(finding.Evidence) + "</pre>"}
+			}
+			doc.Issues = append(doc.Issues, issue)
+		}
+	}
+	if !exported.IsZero() {
+		doc.ExportTime = exported.UTC().Format(time.UnixDate)
+	}
+	if e := types.EngagementOf(results); !e.IsZero() {
+		doc.Engagement = &burpEngagement{e.Client, e.ID, e.Tester, e.Authorization, e.Notes}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// burpSeverity maps Hunter severities onto Burp's, which stop at High.
+func burpSeverity(s types.Severity) string {
+	switch s {
+	case types.SeverityCritical, types.SeverityHigh:
+		return "High"
+	case types.SeverityMedium:
+		return "Medium"
+	case types.SeverityLow:
+		return "Low"
+	}
+	return "Information"
+}
+
+// burpConfidence maps a finding's confidence onto Burp's; findings that do
+// not grade it are firm.
+func burpConfidence(f types.Finding) string {
+	switch f.Metadata["confidence"] {
+	case types.ConfidenceTentative:
+		return "Tentative"
+	case types.ConfidenceCertain:
+		return "Certain"
+	}
+	return "Firm"
+}
@@ -0,0 +1,61 @@
+package output
+
+import (
+	"encoding/csv"
+	"io"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// CSVFormatter renders one row per finding, for spreadsheets and ticketing
+// imports. A scanner that failed gets a single row carrying its error.
+// Every row ends with the engagement the scan was run under.
+type CSVFormatter struct{}
+
+var csvHeader = []string{"scanner", "target", "severity", "title", "description", "evidence", "remediation", "error",
+	"client", "engagement_id", "tester", "authorization", "notes"}
+
+func (f *CSVFormatter) Format(w io.Writer, results []types.ScanResult) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		target := targetName(result.Target)
+		e := types.EngagementOf([]types.ScanResult{result})
+		engagement := []string{e.Client, e.ID, e.Tester, e.Authorization, e.Notes}
+		if result.Error != "" {
+			if err := cw.Write(append([]string{result.ScannerName, target, "", "", "", "", "", result.Error}, engagement...)); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, finding := range result.Findings {
+			row := []string{
+				result.ScannerName,
+				target,
+				string(finding.Severity),
+				finding.Title,
+				finding.Description,
+				finding.Evidence,
+				finding.Remediation,
+				"",
+			}
+			if err := cw.Write(append(row, engagement...)); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// targetName returns the URL of a target, or its host when it has none.
+func targetName(t types.Target) string {
+	if t.URL != "" {
+		return t.URL
+	}
+	return t.Host
+}
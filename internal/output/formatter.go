@@ -3,7 +3,9 @@ package output
 import (
 	"fmt"
 	"io"
+	"strings"
 
+	"github.com/buemura/hunter/internal/i18n"
 	"github.com/buemura/hunter/pkg/types"
 )
 
@@ -12,6 +14,40 @@ type Formatter interface {
 	Format(w io.Writer, results []types.ScanResult) error
 }
 
+// Formats lists the supported output format names.
+var Formats = []string{"table", "json", "markdown", "html", "csv", "sarif", "zap", "burp"}
+
+// FileExtension returns the conventional file extension, without the dot,
+// for a file written in the given format.
+func FileExtension(format string) string {
+	switch format {
+	case "table":
+		return "txt"
+	case "markdown":
+		return "md"
+	case "zap":
+		return "json"
+	case "burp":
+		return "xml"
+	}
+	return format
+}
+
+// Localize sets the language of f's output when it is a format people read
+// directly: table, markdown, or html. The other formats are fed to tools
+// and stay in English. It returns f.
+func Localize(f Formatter, l *i18n.Locale) Formatter {
+	switch f := f.(type) {
+	case *TableFormatter:
+		f.Locale = l
+	case *MarkdownFormatter:
+		f.Locale = l
+	case *HTMLFormatter:
+		f.Locale = l
+	}
+	return f
+}
+
 // GetFormatter returns the appropriate formatter for the given format string.
 func GetFormatter(format string) (Formatter, error) {
 	switch format {
@@ -23,7 +59,15 @@ func GetFormatter(format string) (Formatter, error) {
 		return &MarkdownFormatter{}, nil
 	case "html":
 		return &HTMLFormatter{}, nil
+	case "csv":
+		return &CSVFormatter{}, nil
+	case "sarif":
+		return &SARIFFormatter{}, nil
+	case "zap":
+		return &ZAPFormatter{}, nil
+	case "burp":
+		return &BurpFormatter{}, nil
 	default:
-		return nil, fmt.Errorf("unknown output format %q (supported: table, json, markdown, html)", format)
+		return nil, fmt.Errorf("unknown output format %q (supported: %s)", format, strings.Join(Formats, ", "))
 	}
 }
@@ -2,10 +2,17 @@ package output
 
 import (
 	"bytes"
+	"encoding/base64"
+	"encoding/csv"
 	"encoding/json"
+	"encoding/xml"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/buemura/hunter/internal/i18n"
+	"github.com/buemura/hunter/internal/vault"
 	"github.com/buemura/hunter/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -79,6 +86,17 @@ func TestTableFormatter_NoFindings(t *testing.T) {
 	assert.Contains(t, buf.String(), "No findings")
 }
 
+func TestTableFormatter_Partial(t *testing.T) {
+	var buf bytes.Buffer
+	f := &TableFormatter{}
+	results := []types.ScanResult{
+		{ScannerName: "dirs", Target: types.Target{Host: "example.com"}, Metadata: map[string]string{"partial": "true", "time_budget": "2m0s"}},
+	}
+	err := f.Format(&buf, results)
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "Partial: stopped when its 2m0s of the time budget ran out.")
+}
+
 func TestJSONFormatter(t *testing.T) {
 	var buf bytes.Buffer
 	f := &JSONFormatter{}
@@ -93,6 +111,87 @@ func TestJSONFormatter(t *testing.T) {
 	assert.Len(t, decoded[0].Findings, 2)
 }
 
+func TestJSONFormatter_Artifacts(t *testing.T) {
+	results := sampleResults()
+	results[0].Findings[0].Artifacts = []types.Artifact{{Request: "GET / HTTP/1.1\r\n", Response: "HTTP/1.1 200 OK\r\n"}}
+
+	var buf bytes.Buffer
+	require.NoError(t, (&JSONFormatter{}).Format(&buf, results))
+	assert.NotContains(t, buf.String(), "artifacts")
+
+	buf.Reset()
+	require.NoError(t, (&JSONFormatter{Artifacts: true}).Format(&buf, results))
+	var decoded []types.ScanResult
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Len(t, decoded[0].Findings[0].Artifacts, 1)
+	assert.Equal(t, "HTTP/1.1 200 OK\r\n", decoded[0].Findings[0].Artifacts[0].Response)
+}
+
+// --- Executive summary ---
+
+func summaryResults() []types.ScanResult {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	return []types.ScanResult{
+		{
+			ScannerName: "headers",
+			Target:      types.Target{URL: "https://example.com", Host: "example.com"},
+			StartedAt:   start,
+			CompletedAt: start.Add(time.Minute),
+			Findings: []types.Finding{
+				{Title: "Server banner", Severity: types.SeverityInfo},
+				{Title: "Missing HSTS header", Severity: types.SeverityMedium, Remediation: "Send Strict-Transport-Security."},
+			},
+		},
+		{
+			ScannerName: "vuln",
+			Target:      types.Target{Host: "api.example.com"},
+			StartedAt:   start.Add(10 * time.Second),
+			CompletedAt: start.Add(90 * time.Second),
+			Findings: []types.Finding{
+				{Title: "SQL injection", Severity: types.SeverityCritical, Remediation: "Use parameterized queries."},
+				{Title: "SQL injection", Severity: types.SeverityCritical, Remediation: "Use parameterized queries."},
+			},
+		},
+		{ScannerName: "port", Target: types.Target{Host: "api.example.com"}, Error: "connection refused"},
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	s := summarize(summaryResults(), nil)
+
+	assert.Equal(t, 4, s.Total)
+	assert.Equal(t, types.SeverityCritical, s.Highest)
+	assert.Equal(t, []string{"https://example.com", "api.example.com"}, s.Scope)
+	assert.Equal(t, 3, s.Scanners)
+	assert.Equal(t, []string{"port"}, s.Failed)
+	assert.Equal(t, 90*time.Second, s.Duration)
+	assert.Equal(t, []severityCount{
+		{types.SeverityCritical, 2}, {types.SeverityHigh, 0}, {types.SeverityMedium, 1}, {types.SeverityLow, 0}, {types.SeverityInfo, 1},
+	}, s.Counts)
+	assert.Equal(t, []risk{
+		{"SQL injection", types.SeverityCritical, 2},
+		{"Missing HSTS header", types.SeverityMedium, 1},
+	}, s.TopRisks)
+	assert.Equal(t, []fix{
+		{"Use parameterized queries.", types.SeverityCritical, 2},
+		{"Send Strict-Transport-Security.", types.SeverityMedium, 1},
+	}, s.Fixes)
+}
+
+func TestSummarize_Verdict(t *testing.T) {
+	assert.Equal(t, "The scan of the target found no security issues.", summarize(nil, nil).Verdict())
+
+	info := []types.ScanResult{{
+		ScannerName: "port",
+		Target:      types.Target{Host: "example.com"},
+		Findings:    []types.Finding{{Title: "Open port: 80/HTTP", Severity: types.SeverityInfo}},
+	}}
+	assert.Equal(t, "The scan of the target found no security issues, only 1 informational finding for reference.", summarize(info, nil).Verdict())
+
+	assert.Equal(t, "The scan of the target found 2 findings; the most serious is rated medium.", summarize(sampleResults(), nil).Verdict())
+	assert.Equal(t, "The scan of 2 targets found 4 findings; the most serious is rated critical. 2 issues rated critical or high should be fixed first.", summarize(summaryResults(), nil).Verdict())
+}
+
 // --- GetFormatter: Markdown & HTML ---
 
 func TestGetFormatter_Markdown(t *testing.T) {
@@ -124,6 +223,59 @@ func TestMarkdownFormatter(t *testing.T) {
 	assert.Contains(t, output, "**Summary:** 2 findings")
 }
 
+func TestMarkdownFormatter_Locale(t *testing.T) {
+	l, err := i18n.Load("pt-BR")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	f := &MarkdownFormatter{Locale: l}
+	require.NoError(t, f.Format(&buf, sampleResults()))
+	output := buf.String()
+
+	assert.Contains(t, output, "## Resumo executivo")
+	assert.Contains(t, output, "| Severidade | Título | Descrição |")
+	assert.Contains(t, output, "| **INFO** | Porta aberta: 80/HTTP | Port 80 is open |")
+	assert.Contains(t, output, "**Resumo:** 2 achados (0 críticos, 0 altos, 1 médios, 0 baixos, 1 informativos)")
+}
+
+func TestLocalize(t *testing.T) {
+	l, err := i18n.Load("pt-BR")
+	require.NoError(t, err)
+
+	table := Localize(&TableFormatter{}, l).(*TableFormatter)
+	assert.Equal(t, l, table.Locale)
+	assert.IsType(t, &SARIFFormatter{}, Localize(&SARIFFormatter{}, l), "tool formats stay in English")
+
+	var buf bytes.Buffer
+	require.NoError(t, table.Format(&buf, sampleResults()))
+	assert.Contains(t, buf.String(), "2 achados")
+}
+
+func TestMarkdownFormatter_ExecutiveSummary(t *testing.T) {
+	var buf bytes.Buffer
+	f := &MarkdownFormatter{}
+	require.NoError(t, f.Format(&buf, summaryResults()))
+	output := buf.String()
+
+	assert.True(t, strings.HasPrefix(output, "## Executive Summary\n"), "the summary comes first")
+	assert.Contains(t, output, "most serious is rated critical")
+	assert.Contains(t, output, "- **Scope:** https://example.com, api.example.com")
+	assert.Contains(t, output, "- **Scanners run:** 3 (1 failed: port)")
+	assert.Contains(t, output, "- **Duration:** 1m30s")
+	assert.Contains(t, output, "| **CRITICAL** | 2 | "+strings.Repeat("█", maxChartBar)+" |")
+	assert.Contains(t, output, "| **HIGH** | 0 |  |")
+	assert.Contains(t, output, "1. **CRITICAL** SQL injection (2 occurrences)\n2. **MEDIUM** Missing HSTS header (1 occurrence)\n")
+	assert.Contains(t, output, "1. **CRITICAL** Use parameterized queries. (2 findings)")
+	assert.NotContains(t, output, "Server banner (", "informational findings are not risks")
+}
+
+func TestMarkdownFormatter_References(t *testing.T) {
+	var buf bytes.Buffer
+	f := &MarkdownFormatter{}
+	require.NoError(t, f.Format(&buf, locatedResults()))
+	assert.Contains(t, buf.String(), `| **CRITICAL** | Reflected XSS | Input <q> is reflected<br>References: [XSS <cheat sheet>](https://example.org/xss?a=1&b=2) |`)
+}
+
 func TestMarkdownFormatter_Error(t *testing.T) {
 	var buf bytes.Buffer
 	f := &MarkdownFormatter{}
@@ -184,6 +336,101 @@ func TestHTMLFormatter(t *testing.T) {
 	assert.Contains(t, output, `class="badge info"`)
 }
 
+func TestHTMLFormatter_ExecutiveSummary(t *testing.T) {
+	var buf bytes.Buffer
+	f := &HTMLFormatter{}
+	require.NoError(t, f.Format(&buf, summaryResults()))
+	output := buf.String()
+
+	assert.Contains(t, output, `<section class="executive-summary">`)
+	assert.Contains(t, output, `<svg class="severity-chart"`)
+	assert.Contains(t, output, `<rect class="critical" x="80" y="4" width="240"`)
+	assert.Contains(t, output, `<rect class="medium" x="80" y="56" width="120"`)
+	assert.Contains(t, output, `<dd>api.example.com</dd>`)
+	assert.Contains(t, output, `<dd>1m30s</dd>`)
+	assert.Contains(t, output, `SQL injection <span class="count">(2 occurrences)</span>`)
+	assert.Contains(t, output, `Use parameterized queries. <span class="count">(2 findings)</span>`)
+	assert.Less(t, strings.Index(output, "Executive Summary"), strings.Index(output, "headers &mdash;"))
+}
+
+func TestHTMLFormatter_Locale(t *testing.T) {
+	l, err := i18n.Load("pt-BR")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	f := &HTMLFormatter{Locale: l}
+	require.NoError(t, f.Format(&buf, sampleResults()))
+	output := buf.String()
+
+	assert.Contains(t, output, `<html lang="pt-BR">`)
+	assert.Contains(t, output, "<h1>Relatório de varredura do Hunter</h1>")
+	assert.Contains(t, output, "A varredura do alvo encontrou 2 achados; o mais grave é classificado como médio.")
+	assert.Contains(t, output, "<td>Porta aberta: 80/HTTP</td>")
+	assert.Contains(t, output, `<span class="badge medium">MÉDIO</span>`)
+	assert.Contains(t, output, `var reportText = {"all":"{total} achados","some":"{shown} de {total} achados"};`)
+	assert.NotContains(t, output, "Executive Summary")
+}
+
+func TestProtectedFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	f := &ProtectedFormatter{Formatter: &MarkdownFormatter{}, Password: "s3cret"}
+	require.NoError(t, f.Format(&buf, sampleResults()))
+	assert.NotContains(t, buf.String(), "Open port")
+
+	report, err := vault.OpenPassword("s3cret", buf.Bytes())
+	require.NoError(t, err)
+	assert.Contains(t, string(report), "| **MEDIUM** | Open port: 22/SSH | SSH exposed |")
+}
+
+func TestProtectedFormatter_HTML(t *testing.T) {
+	l, err := i18n.Load("pt-BR")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	f := &ProtectedFormatter{Formatter: &HTMLFormatter{Locale: l}, Password: "s3cret"}
+	require.NoError(t, f.Format(&buf, sampleResults()))
+	page := buf.String()
+	assert.Contains(t, page, `<html lang="pt-BR">`)
+	assert.Contains(t, page, "Este relatório é protegido por senha.")
+	assert.NotContains(t, page, "Porta aberta")
+
+	// The page embeds the report sealed with the password.
+	m := regexp.MustCompile(`var sealed = ("[^"]*");`).FindStringSubmatch(page)
+	require.NotNil(t, m)
+	var encoded string
+	require.NoError(t, json.Unmarshal([]byte(m[1]), &encoded))
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	require.NoError(t, err)
+	report, err := vault.OpenPassword("s3cret", sealed)
+	require.NoError(t, err)
+	assert.Contains(t, string(report), "<td>Porta aberta: 80/HTTP</td>")
+}
+
+func TestHTMLFormatter_References(t *testing.T) {
+	var buf bytes.Buffer
+	f := &HTMLFormatter{}
+	require.NoError(t, f.Format(&buf, locatedResults()))
+	assert.Contains(t, buf.String(), `<li><a href="https://example.org/xss?a=1&amp;b=2" target="_blank" rel="noopener noreferrer">XSS &lt;cheat sheet&gt;</a></li>`)
+}
+
+func TestHTMLFormatter_Screenshots(t *testing.T) {
+	var buf bytes.Buffer
+	f := &HTMLFormatter{}
+	results := []types.ScanResult{{
+		ScannerName: "dirs",
+		Target:      types.Target{Host: "example.com"},
+		Findings: []types.Finding{{
+			Title:     "Found path: /admin (200 OK)",
+			Severity:  types.SeverityInfo,
+			Artifacts: []types.Artifact{{Request: "GET /admin HTTP/1.1"}, {Screenshot: []byte("PNG")}},
+		}},
+	}}
+	require.NoError(t, f.Format(&buf, results))
+	output := buf.String()
+	assert.Equal(t, 1, strings.Count(output, `<img class="screenshot"`), "only artifacts with a screenshot get one")
+	assert.Contains(t, output, `src="data:image/png;base64,UE5H"`)
+}
+
 func TestHTMLFormatter_Error(t *testing.T) {
 	var buf bytes.Buffer
 	f := &HTMLFormatter{}
@@ -233,3 +480,277 @@ func TestHTMLFormatter_ExpandableDetails(t *testing.T) {
 	assert.Contains(t, output, "proof")
 	assert.Contains(t, output, "fix it")
 }
+
+func TestHTMLFormatter_Interactive(t *testing.T) {
+	var buf bytes.Buffer
+	f := &HTMLFormatter{}
+	require.NoError(t, f.Format(&buf, sampleResults()))
+	output := buf.String()
+
+	assert.Contains(t, output, `data-filter="critical"`)
+	assert.Contains(t, output, `id="search"`)
+	assert.Contains(t, output, `<th data-sort="severity">`)
+	assert.Contains(t, output, `<tr data-severity="medium" data-rank="2">`)
+	assert.Contains(t, output, `<details class="scanner-section" open>`)
+	assert.Contains(t, output, `document.body.classList.add("js")`)
+	assert.NotContains(t, output, `src="http`, "the report loads no external assets")
+	assert.NotContains(t, output, `href="http`, "the report loads no external assets")
+}
+
+func TestGetFormatter_AllFormats(t *testing.T) {
+	for _, name := range Formats {
+		f, err := GetFormatter(name)
+		require.NoError(t, err, name)
+		assert.NotNil(t, f)
+	}
+	assert.Equal(t, "md", FileExtension("markdown"))
+	assert.Equal(t, "sarif", FileExtension("sarif"))
+	assert.Equal(t, "xml", FileExtension("burp"))
+}
+
+func TestCSVFormatter(t *testing.T) {
+	results := append(sampleResults(), types.ScanResult{ScannerName: "ssl", Error: "handshake failed"})
+	results[0].Findings[0].Description = `Port 80 is open, "plain" HTTP`
+
+	var buf bytes.Buffer
+	require.NoError(t, (&CSVFormatter{}).Format(&buf, results))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	assert.Equal(t, csvHeader, records[0])
+	assert.Equal(t, []string{"port", "example.com", "INFO", "Open port: 80/HTTP", `Port 80 is open, "plain" HTTP`, "", "", "", "", "", "", "", ""}, records[1])
+	assert.Equal(t, "handshake failed", records[3][7])
+}
+
+// engagedResults returns sampleResults as run under an engagement.
+func engagedResults() []types.ScanResult {
+	results := sampleResults()
+	results[0].Metadata = types.Engagement{Client: "ACME Corp", ID: "ENG-42", Tester: "J. Doe", Authorization: "SOW-7"}.Metadata()
+	return results
+}
+
+func TestFormatters_Engagement(t *testing.T) {
+	for _, tt := range []struct {
+		format string
+		want   []string
+	}{
+		{"table", []string{"Client: ACME Corp\n", "Engagement ID: ENG-42\n", "Authorization: SOW-7\n"}},
+		{"markdown", []string{"- **Client:** ACME Corp\n- **Engagement ID:** ENG-42\n- **Tester:** J. Doe\n"}},
+		{"html", []string{"<dt>Client</dt><dd>ACME Corp</dd><dt>Engagement ID</dt><dd>ENG-42</dd>"}},
+		{"json", []string{`"engagement_client": "ACME Corp"`}},
+		{"csv", []string{",,ACME Corp,ENG-42,J. Doe,SOW-7,\n"}},
+		{"sarif", []string{`"properties": {`, `"engagement_tester": "J. Doe"`}},
+		{"zap", []string{`"engagement": {`, `"client": "ACME Corp"`}},
+		{"burp", []string{"<engagement>\n    <client>ACME Corp</client>\n    <id>ENG-42</id>"}},
+	} {
+		t.Run(tt.format, func(t *testing.T) {
+			f, err := GetFormatter(tt.format)
+			require.NoError(t, err)
+			var buf bytes.Buffer
+			require.NoError(t, f.Format(&buf, engagedResults()))
+			for _, want := range tt.want {
+				assert.Contains(t, buf.String(), want)
+			}
+		})
+	}
+}
+
+func TestFormatters_NoEngagement(t *testing.T) {
+	for _, format := range []string{"table", "markdown", "html", "sarif", "zap", "burp"} {
+		f, err := GetFormatter(format)
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		require.NoError(t, f.Format(&buf, sampleResults()))
+		assert.NotContains(t, strings.ToLower(buf.String()), "engagement", format)
+	}
+}
+
+func TestSARIFFormatter(t *testing.T) {
+	results := sampleResults()
+	results = append(results, types.ScanResult{ScannerName: "ssl", Error: "handshake failed"})
+
+	var buf bytes.Buffer
+	require.NoError(t, (&SARIFFormatter{}).Format(&buf, results))
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+
+	run := log.Runs[0]
+	assert.Equal(t, "hunter", run.Tool.Driver.Name)
+	require.Len(t, run.Results, 2)
+	assert.Equal(t, "port/open-port-80-http", run.Results[0].RuleID)
+	assert.Equal(t, "note", run.Results[0].Level)
+	assert.Equal(t, "warning", run.Results[1].Level)
+	assert.Equal(t, "example.com", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Len(t, run.Tool.Driver.Rules, 2)
+
+	require.Len(t, run.Invocations, 1)
+	assert.False(t, run.Invocations[0].ExecutionSuccessful)
+	assert.Contains(t, run.Invocations[0].ToolExecutionNotifications[0].Message.Text, "handshake failed")
+}
+
+func TestSARIFFormatter_HelpURI(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, (&SARIFFormatter{}).Format(&buf, locatedResults()))
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	rules := log.Runs[0].Tool.Driver.Rules
+	require.Len(t, rules, 2)
+	assert.Equal(t, "https://example.org/xss?a=1&b=2", rules[0].HelpURI, "the first reference is the rule's help")
+	assert.Empty(t, rules[1].HelpURI)
+}
+
+func TestSARIFFormatter_StableRuleIDs(t *testing.T) {
+	results := []types.ScanResult{{
+		ScannerName: "port",
+		Target:      types.Target{Host: "example.com"},
+		Findings: []types.Finding{
+			{Title: "Open port: 80/HTTP", Severity: types.SeverityInfo, Metadata: map[string]string{"rule_id": "HUNTER-PORT-001"}},
+			{Title: "Open port: 443/HTTPS", Severity: types.SeverityInfo, Metadata: map[string]string{"rule_id": "HUNTER-PORT-001"}},
+		},
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, (&SARIFFormatter{}).Format(&buf, results))
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	run := log.Runs[0]
+	require.Len(t, run.Tool.Driver.Rules, 1, "findings of one rule share a SARIF rule")
+	assert.Equal(t, "HUNTER-PORT-001", run.Tool.Driver.Rules[0].ID)
+	assert.Equal(t, "Open port", run.Tool.Driver.Rules[0].Name)
+	require.Len(t, run.Results, 2)
+	assert.Equal(t, "HUNTER-PORT-001", run.Results[1].RuleID)
+}
+
+// locatedResults returns an http target's findings, one located by its
+// scanner at a path, method, and parameter.
+func locatedResults() []types.ScanResult {
+	completed := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	return []types.ScanResult{
+		{
+			ScannerName: "vuln",
+			Target:      types.Target{Host: "example.com", URL: "http://example.com:8080", Scheme: "http"},
+			CompletedAt: completed,
+			Findings: []types.Finding{
+				{Title: "Reflected XSS", Severity: types.SeverityCritical, Description: "Input <q> is reflected", Evidence: "<script>", Remediation: "Encode output",
+					References: []types.Reference{{Title: "XSS <cheat sheet>", URL: "https://example.org/xss?a=1&b=2"}},
+					Metadata:   map[string]string{"path": "/search?q=x", "method": "post", "param": "q"}},
+				{Title: "Reflected XSS", Severity: types.SeverityCritical,
+					Metadata: map[string]string{"url": "http://example.com:8080/find?s=x", "param": "s"}},
+				{Title: "Missing HSTS", Severity: types.SeverityLow},
+			},
+		},
+		{ScannerName: "ssl", Error: "handshake failed"},
+	}
+}
+
+func TestZAPFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, (&ZAPFormatter{}).Format(&buf, locatedResults()))
+
+	var report zapReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, "Fri, 01 Mar 2024 12:00:00 UTC", report.Generated)
+	require.Len(t, report.Sites, 1, "errors are left out")
+
+	site := report.Sites[0]
+	assert.Equal(t, "http://example.com:8080", site.Name)
+	assert.Equal(t, "8080", site.Port)
+	assert.Equal(t, "false", site.SSL)
+	require.Len(t, site.Alerts, 2, "findings sharing a title are instances of one alert")
+
+	xss := site.Alerts[0]
+	assert.Equal(t, "vuln/reflected-xss", xss.AlertRef)
+	assert.Equal(t, "3", xss.RiskCode, "critical maps to high")
+	assert.Equal(t, "High (Medium)", xss.RiskDesc)
+	assert.Equal(t, "<p>Input &lt;q&gt; is reflected</p>", xss.Desc)
+	assert.Equal(t, `<p><a href="https://example.org/xss?a=1&amp;b=2">XSS &lt;cheat sheet&gt;</a></p>`, xss.Reference)
+	assert.Equal(t, "2", xss.Count)
+	require.Len(t, xss.Instances, 2)
+	assert.Equal(t, zapInstance{URI: "http://example.com:8080/search?q=x", Method: "POST", Param: "q", Evidence: "<script>"}, xss.Instances[0])
+	assert.Equal(t, "http://example.com:8080/find?s=x", xss.Instances[1].URI)
+	assert.Equal(t, "GET", xss.Instances[1].Method)
+
+	hsts := site.Alerts[1]
+	assert.Equal(t, "1", hsts.RiskCode)
+	assert.Equal(t, "http://example.com:8080", hsts.Instances[0].URI, "findings without a location point at the target")
+}
+
+func TestZAPFormatter_HostTarget(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, (&ZAPFormatter{}).Format(&buf, sampleResults()))
+
+	var report zapReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	require.Len(t, report.Sites, 1)
+	assert.Equal(t, "https://example.com", report.Sites[0].Name)
+	assert.Equal(t, "443", report.Sites[0].Port)
+	assert.Equal(t, "https://example.com/", report.Sites[0].Alerts[0].Instances[0].URI)
+}
+
+func TestBurpFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, (&BurpFormatter{}).Format(&buf, locatedResults()))
+	assert.Contains(t, buf.String(), "<![CDATA[/search?q=x]]>")
+
+	var doc burpIssues
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "Fri Mar  1 12:00:00 UTC 2024", doc.ExportTime)
+	require.Len(t, doc.Issues, 3, "one issue per finding; errors are left out")
+
+	issue := doc.Issues[0]
+	assert.Equal(t, 1, issue.SerialNumber)
+	assert.Equal(t, "Reflected XSS", issue.Name)
+	assert.Equal(t, "http://example.com:8080", issue.Host.URL)
+	assert.Equal(t, "/search?q=x", issue.Path.Text)
+	assert.Equal(t, "/search?q=x [q parameter]", issue.Location.Text)
+	assert.Equal(t, "High", issue.Severity)
+	assert.Equal(t, "<p>Input &lt;q&gt; is reflected</p>", issue.IssueBackground.Text)
+	assert.Equal(t, "<p>Encode output</p>", issue.RemediationBackground.Text)
+	assert.Equal(t, `<p><a href="https://example.org/xss?a=1&amp;b=2">XSS &lt;cheat sheet&gt;</a></p>`, issue.References.Text)
+	assert.Nil(t, doc.Issues[1].References)
+	assert.Contains(t, issue.IssueDetail.Text, "&lt;script&gt;")
+
+	assert.Equal(t, "/find?s=x", doc.Issues[1].Path.Text)
+	assert.Equal(t, "Low", doc.Issues[2].Severity)
+	assert.Equal(t, "/", doc.Issues[2].Path.Text)
+	assert.Nil(t, doc.Issues[2].IssueDetail)
+}
+
+func TestZAPAndBurpConfidence(t *testing.T) {
+	results := []types.ScanResult{{
+		ScannerName: "vuln",
+		Target:      types.Target{Host: "example.com", URL: "http://example.com", Scheme: "http"},
+		Findings: []types.Finding{
+			{Title: "Potential SQL injection", Severity: types.SeverityCritical, Metadata: map[string]string{"confidence": types.ConfidenceTentative}},
+			{Title: "Blind SQL injection (time-based)", Severity: types.SeverityCritical, Metadata: map[string]string{"confidence": types.ConfidenceCertain}},
+			{Title: "Missing HSTS", Severity: types.SeverityLow},
+		},
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, (&ZAPFormatter{}).Format(&buf, results))
+	var report zapReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	alerts := report.Sites[0].Alerts
+	require.Len(t, alerts, 3)
+	assert.Equal(t, "1", alerts[0].Confidence)
+	assert.Equal(t, "High (Low)", alerts[0].RiskDesc)
+	assert.Equal(t, "3", alerts[1].Confidence)
+	assert.Equal(t, "High (High)", alerts[1].RiskDesc)
+	assert.Equal(t, "2", alerts[2].Confidence, "ungraded findings are medium")
+
+	buf.Reset()
+	require.NoError(t, (&BurpFormatter{}).Format(&buf, results))
+	var doc burpIssues
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	require.Len(t, doc.Issues, 3)
+	assert.Equal(t, "Tentative", doc.Issues[0].Confidence)
+	assert.Equal(t, "Certain", doc.Issues[1].Confidence)
+	assert.Equal(t, "Firm", doc.Issues[2].Confidence)
+}
@@ -1,19 +1,30 @@
 package output
 
 import (
+	"encoding/base64"
 	"fmt"
 	"html/template"
 	"io"
 	"sort"
 
+	"github.com/buemura/hunter/internal/i18n"
 	"github.com/buemura/hunter/pkg/types"
 )
 
-// HTMLFormatter renders results as a self-contained HTML report with
-// styled severity badges and expandable finding details.
-type HTMLFormatter struct{}
+// HTMLFormatter renders results as a self-contained HTML report: an
+// executive summary with a severity chart, then styled severity badges and
+// expandable finding details. An embedded script
+// filters findings by severity and text, sorts them by column, and collapses
+// scanner sections; without it the report shows everything.
+type HTMLFormatter struct {
+	// Locale is the language of the report; nil is English.
+	Locale *i18n.Locale
+}
 
 func (f *HTMLFormatter) Format(w io.Writer, results []types.ScanResult) error {
+	l := f.Locale
+	results = l.Results(results)
+
 	// Sort findings within each result before rendering.
 	for i := range results {
 		sort.Slice(results[i].Findings, func(a, b int) bool {
@@ -21,11 +32,92 @@ func (f *HTMLFormatter) Format(w io.Writer, results []types.ScanResult) error {
 		})
 	}
 
-	return htmlTpl.Execute(w, templateData{Results: results})
+	tpl, err := htmlTpl.Clone()
+	if err != nil {
+		return err
+	}
+	tpl.Funcs(localeFuncs(l))
+
+	summary := summarize(results, l)
+	return tpl.Execute(w, templateData{
+		Lang:    l.Tag(),
+		Results: results,
+		Summary: summary,
+		Chart:   severityChart(summary),
+		Script: scriptText{
+			All:  l.T("{total} findings"),
+			Some: l.T("{shown} of {total} findings"),
+		},
+	})
 }
 
 type templateData struct {
+	Lang    string
 	Results []types.ScanResult
+	Summary executiveSummary
+	Chart   chart
+	Script  scriptText
+}
+
+// scriptText is the text the report's script writes, with "{shown}" and
+// "{total}" standing for the number of findings shown and in all.
+type scriptText struct {
+	All  string `json:"all"`
+	Some string `json:"some"`
+}
+
+// Layout of the summary's severity chart, in SVG user units.
+const (
+	chartLabelWidth = 80
+	chartBarWidth   = 240
+	chartRowHeight  = 26
+)
+
+// chart is the summary's severity chart, drawn as inline SVG so the report
+// needs no external assets.
+type chart struct {
+	Width  int
+	Height int
+	Bars   []chartBar
+}
+
+// chartBar is a bar of the summary's severity chart.
+type chartBar struct {
+	Severity types.Severity
+	Count    int
+	X        int
+	Y        int
+	Width    int
+	// TextY is the baseline of the row's labels.
+	TextY int
+	// CountX is where the count is written, just past the bar.
+	CountX int
+}
+
+// severityChart lays out one bar per severity, scaled to the largest count.
+func severityChart(s executiveSummary) chart {
+	most := s.MaxCount()
+	c := chart{
+		Width:  chartLabelWidth + chartBarWidth + 40,
+		Height: len(s.Counts) * chartRowHeight,
+	}
+	for i, sc := range s.Counts {
+		width := 0
+		if sc.Count > 0 {
+			width = max(2, sc.Count*chartBarWidth/most)
+		}
+		y := i * chartRowHeight
+		c.Bars = append(c.Bars, chartBar{
+			Severity: sc.Severity,
+			Count:    sc.Count,
+			X:        chartLabelWidth,
+			Y:        y + 4,
+			Width:    width,
+			TextY:    y + 18,
+			CountX:   chartLabelWidth + width + 6,
+		})
+	}
+	return c
 }
 
 // severityClass maps a Severity to a CSS class name.
@@ -44,8 +136,32 @@ func severityClass(s types.Severity) string {
 	}
 }
 
+// screenshots returns the screenshots among a finding's artifacts as data
+// URIs, so the report stays a single file.
+func screenshots(f types.Finding) []template.URL {
+	var uris []template.URL
+	for _, a := range f.Artifacts {
+		if len(a.Screenshot) > 0 {
+			uris = append(uris, template.URL("data:image/png;base64,"+base64.StdEncoding.EncodeToString(a.Screenshot)))
+		}
+	}
+	return uris
+}
+
+// localeFuncs returns the template functions that word the report in the
+// language of l. The report is parsed with the English ones.
+func localeFuncs(l *i18n.Locale) template.FuncMap {
+	return template.FuncMap{
+		"t":        l.T,
+		"severity": func(s types.Severity) string { return l.T(string(s)) },
+		"plural":   func(n int, noun string) string { return plural(l, n, noun) },
+	}
+}
+
 var funcMap = template.FuncMap{
 	"severityClass": severityClass,
+	"severityRank":  types.SeverityRank,
+	"screenshots":   screenshots,
 	"findingsCount": func(results []types.ScanResult) int {
 		n := 0
 		for _, r := range results {
@@ -71,54 +187,97 @@ var funcMap = template.FuncMap{
 	"severityInfo":     func() types.Severity { return types.SeverityInfo },
 }
 
-var htmlTpl = template.Must(template.New("report").Funcs(funcMap).Parse(fmt.Sprintf(`<!DOCTYPE html>
-<html lang="en">
+var htmlTpl = template.Must(template.New("report").Funcs(funcMap).Funcs(localeFuncs(nil)).Parse(fmt.Sprintf(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
 <head>
 <meta charset="utf-8">
 <meta name="viewport" content="width=device-width, initial-scale=1">
-<title>Hunter Scan Report</title>
+<title>{{t "Hunter Scan Report"}}</title>
 <style>%s</style>
 </head>
 <body>
 <div class="container">
-  <h1>Hunter Scan Report</h1>
+  <h1>{{t "Hunter Scan Report"}}</h1>
+
+  <section class="executive-summary">
+    <h2>{{t "Executive Summary"}}</h2>
+    <p class="verdict">{{.Summary.Verdict}}</p>
+    <div class="overview">
+      <svg class="severity-chart" role="img" aria-label="{{t "Findings by Severity"}}" width="{{.Chart.Width}}" height="{{.Chart.Height}}" viewBox="0 0 {{.Chart.Width}} {{.Chart.Height}}">
+        {{range .Chart.Bars}}
+        <text x="0" y="{{.TextY}}">{{severity .Severity}}</text>
+        <rect class="{{severityClass .Severity}}" x="{{.X}}" y="{{.Y}}" width="{{.Width}}" height="18" rx="3"></rect>
+        <text x="{{.CountX}}" y="{{.TextY}}">{{.Count}}</text>
+        {{end}}
+      </svg>
+      <dl>
+        {{range .Summary.Engagement}}<dt>{{t .Label}}</dt><dd>{{.Value}}</dd>{{end}}
+        {{with .Summary.Scope}}<dt>{{t "Scope"}}</dt>{{range .}}<dd>{{.}}</dd>{{end}}{{end}}
+        <dt>{{t "Scanners run"}}</dt><dd>{{.Summary.Scanners}}{{with .Summary.Failed}} {{t "(%%d failed)" (len .)}}{{end}}</dd>
+        {{with .Summary.DurationText}}<dt>{{t "Duration"}}</dt><dd>{{.}}</dd>{{end}}
+      </dl>
+    </div>
+    {{with .Summary.TopRisks}}
+    <h3>{{t "Top Risks"}}</h3>
+    <ol>
+      {{range .}}<li><span class="badge {{severityClass .Severity}}">{{severity .Severity}}</span> {{.Title}} <span class="count">({{plural .Count "occurrence"}})</span></li>
+      {{end}}
+    </ol>
+    {{end}}
+    {{with .Summary.Fixes}}
+    <h3>{{t "Remediation Priorities"}}</h3>
+    <ol>
+      {{range .}}<li><span class="badge {{severityClass .Severity}}">{{severity .Severity}}</span> {{.Remediation}} <span class="count">({{plural .Count "finding"}})</span></li>
+      {{end}}
+    </ol>
+    {{end}}
+  </section>
 
   <div class="summary-bar">
-    <span class="badge critical">{{countSeverity .Results severityCritical}} Critical</span>
-    <span class="badge high">{{countSeverity .Results severityHigh}} High</span>
-    <span class="badge medium">{{countSeverity .Results severityMedium}} Medium</span>
-    <span class="badge low">{{countSeverity .Results severityLow}} Low</span>
-    <span class="badge info">{{countSeverity .Results severityInfo}} Info</span>
-    <span class="total">{{findingsCount .Results}} total findings</span>
+    <button type="button" class="badge critical" data-filter="critical" aria-pressed="true" title="{{t "Show or hide critical findings"}}">{{countSeverity .Results severityCritical}} {{t "Critical"}}</button>
+    <button type="button" class="badge high" data-filter="high" aria-pressed="true" title="{{t "Show or hide high findings"}}">{{countSeverity .Results severityHigh}} {{t "High"}}</button>
+    <button type="button" class="badge medium" data-filter="medium" aria-pressed="true" title="{{t "Show or hide medium findings"}}">{{countSeverity .Results severityMedium}} {{t "Medium"}}</button>
+    <button type="button" class="badge low" data-filter="low" aria-pressed="true" title="{{t "Show or hide low findings"}}">{{countSeverity .Results severityLow}} {{t "Low"}}</button>
+    <button type="button" class="badge info" data-filter="info" aria-pressed="true" title="{{t "Show or hide info findings"}}">{{countSeverity .Results severityInfo}} {{t "Info"}}</button>
+    <span class="total">{{t "%%d total findings" (findingsCount .Results)}}</span>
+  </div>
+
+  <div class="controls">
+    <input type="search" id="search" placeholder="{{t "Search findings"}}" aria-label="{{t "Search findings"}}">
+    <button type="button" id="expand-all">{{t "Expand all"}}</button>
+    <button type="button" id="collapse-all">{{t "Collapse all"}}</button>
   </div>
 
   {{range .Results}}
-  <section class="scanner-section">
+  <details class="scanner-section" open>
     {{if .Error}}
-      <h2>{{.ScannerName}} &mdash; Error</h2>
+      <summary><h2>{{.ScannerName}} &mdash; {{t "Error"}}</h2></summary>
       <div class="error-box">{{.Error}}</div>
     {{else}}
-      <h2>{{.ScannerName}} &mdash; {{.Target.Host}}</h2>
+      <summary><h2>{{.ScannerName}} &mdash; {{.Target.Host}}</h2> <span class="shown"></span></summary>
 
       {{if not .Findings}}
-        <p class="no-findings">No findings.</p>
+        <p class="no-findings">{{t "No findings."}}</p>
       {{else}}
         <table>
           <thead>
-            <tr><th>Severity</th><th>Title</th><th>Description</th></tr>
+            <tr><th data-sort="severity">{{t "Severity"}}</th><th data-sort="text">{{t "Title"}}</th><th data-sort="text">{{t "Description"}}</th></tr>
           </thead>
           <tbody>
             {{range .Findings}}
-            <tr>
-              <td><span class="badge {{severityClass .Severity}}">{{.Severity}}</span></td>
+            <tr data-severity="{{severityClass .Severity}}" data-rank="{{severityRank .Severity}}">
+              <td><span class="badge {{severityClass .Severity}}">{{severity .Severity}}</span></td>
               <td>{{.Title}}</td>
               <td>
                 {{.Description}}
-                {{if or .Evidence .Remediation}}
+                {{range screenshots .}}<a href="{{.}}" target="_blank"><img class="screenshot" src="{{.}}" alt="{{t "Screenshot"}}"></a>{{end}}
+                {{if or .Evidence .Remediation .References}}
                 <details>
-                  <summary>Details</summary>
-                  {{if .Evidence}}<p><strong>Evidence:</strong> {{.Evidence}}</p>{{end}}
-                  {{if .Remediation}}<p><strong>Remediation:</strong> {{.Remediation}}</p>{{end}}
+                  <summary>{{t "Details"}}</summary>
+                  {{if .Evidence}}<p><strong>{{t "Evidence:"}}</strong> {{.Evidence}}</p>{{end}}
+                  {{if .Remediation}}<p><strong>{{t "Remediation:"}}</strong> {{.Remediation}}</p>{{end}}
+                  {{if .References}}<p><strong>{{t "References:"}}</strong></p>
+                  <ul class="references">{{range .References}}<li><a href="{{.URL}}" target="_blank" rel="noopener noreferrer">{{.Title}}</a></li>{{end}}</ul>{{end}}
                 </details>
                 {{end}}
               </td>
@@ -126,13 +285,15 @@ var htmlTpl = template.Must(template.New("report").Funcs(funcMap).Parse(fmt.Spri
             {{end}}
           </tbody>
         </table>
+        <p class="no-findings no-match" hidden>{{t "No findings match the filters."}}</p>
       {{end}}
     {{end}}
-  </section>
+  </details>
   {{end}}
 </div>
+<script>var reportText = {{.Script}};%s</script>
 </body>
-</html>`, cssStyles)))
+</html>`, cssStyles, reportScript)))
 
 const cssStyles = `
 *{box-sizing:border-box;margin:0;padding:0}
@@ -154,8 +315,119 @@ th,td{text-align:left;padding:.5rem .75rem;border-bottom:1px solid #e0e0e0}
 th{background:#eaeaea;font-weight:600}
 tr:hover{background:#f0f0ff}
 details{margin-top:.4rem}
+.screenshot{display:block;max-width:320px;margin-top:.4rem;border:1px solid #e0e0e0;border-radius:4px}
 summary{cursor:pointer;color:#1565c0;font-size:.85rem}
 .error-box{background:#ffebee;color:#c62828;padding:.75rem 1rem;border-radius:6px;margin-bottom:1rem}
 .no-findings{color:#666;font-style:italic}
 .scanner-section{margin-bottom:2rem}
+.scanner-section>summary{list-style:none;display:flex;align-items:baseline;gap:.75rem;color:inherit;font-size:inherit}
+.scanner-section>summary::-webkit-details-marker{display:none}
+.scanner-section>summary h2{flex:1}
+.scanner-section>summary h2::before{content:"\25BE";display:inline-block;width:1.2rem;color:#999}
+.scanner-section:not([open])>summary h2::before{content:"\25B8"}
+.shown{color:#666;font-size:.85rem}
+button.badge{border:0;cursor:pointer;font-family:inherit}
+button.badge[aria-pressed=false]{opacity:.35;text-decoration:line-through}
+.controls{display:none;gap:.5rem;margin-bottom:1rem}
+.js .controls{display:flex}
+.controls input{flex:1;padding:.35rem .6rem;border:1px solid #ccc;border-radius:6px;font:inherit}
+.controls button{padding:.35rem .8rem;border:1px solid #ccc;border-radius:6px;background:#fff;cursor:pointer;font:inherit}
+th[data-sort]{cursor:pointer;user-select:none}
+th[aria-sort=ascending]::after{content:" \25B4"}
+th[aria-sort=descending]::after{content:" \25BE"}
+.executive-summary{background:#fff;border:1px solid #e0e0e0;border-radius:8px;padding:1rem 1.25rem;margin-bottom:1.5rem}
+.executive-summary h2{margin-top:0}
+.executive-summary h3{margin:1rem 0 .4rem;font-size:1.05rem}
+.executive-summary ol{padding-left:1.5rem}
+.executive-summary li{margin-bottom:.3rem}
+.verdict{font-size:1.05rem;margin-bottom:1rem}
+.overview{display:flex;gap:2rem;flex-wrap:wrap;align-items:flex-start}
+.overview dt{font-weight:600}
+.overview dd{margin-bottom:.3rem;word-break:break-all}
+.severity-chart text{font-size:12px;fill:#333;text-transform:uppercase}
+.severity-chart rect.critical{fill:#d32f2f}
+.severity-chart rect.high{fill:#e53935}
+.severity-chart rect.medium{fill:#f9a825}
+.severity-chart rect.low{fill:#0288d1}
+.severity-chart rect.info{fill:#757575}
+.count{color:#666;font-size:.85rem}
+.references{margin:.2rem 0 .4rem 1.25rem}
+@media print{body{background:#fff;padding:0}.controls,.summary-bar{display:none}.executive-summary{break-after:page}}
+`
+
+// reportScript filters, sorts, and collapses the report's findings. It
+// marks the body with the "js" class so the controls only show when it runs,
+// and words what it writes with the reportText the template declares.
+const reportScript = `
+(function () {
+  document.body.classList.add("js");
+  var hidden = {};
+  var query = "";
+
+  function apply() {
+    document.querySelectorAll(".scanner-section").forEach(function (section) {
+      var rows = section.querySelectorAll("tbody tr");
+      if (!rows.length) return;
+      var shown = 0;
+      rows.forEach(function (row) {
+        var visible = !hidden[row.dataset.severity] &&
+          (query === "" || row.textContent.toLowerCase().indexOf(query) >= 0);
+        row.hidden = !visible;
+        if (visible) shown++;
+      });
+      var text = shown === rows.length ? reportText.all : reportText.some;
+      section.querySelector(".shown").textContent = text.replace("{shown}", shown).replace("{total}", rows.length);
+      section.querySelector(".no-match").hidden = shown > 0;
+    });
+  }
+
+  document.querySelectorAll("[data-filter]").forEach(function (chip) {
+    chip.addEventListener("click", function () {
+      var severity = chip.dataset.filter;
+      hidden[severity] = !hidden[severity];
+      chip.setAttribute("aria-pressed", String(!hidden[severity]));
+      apply();
+    });
+  });
+
+  var search = document.getElementById("search");
+  search.addEventListener("input", function () {
+    query = search.value.trim().toLowerCase();
+    apply();
+  });
+
+  document.querySelectorAll("th[data-sort]").forEach(function (th) {
+    th.addEventListener("click", function () {
+      var table = th.closest("table");
+      var ascending = th.getAttribute("aria-sort") !== "ascending";
+      table.querySelectorAll("th[data-sort]").forEach(function (other) {
+        other.removeAttribute("aria-sort");
+      });
+      th.setAttribute("aria-sort", ascending ? "ascending" : "descending");
+
+      var column = th.cellIndex;
+      var key = function (row) {
+        return th.dataset.sort === "severity" ? Number(row.dataset.rank) : row.cells[column].textContent.trim().toLowerCase();
+      };
+      var body = table.tBodies[0];
+      Array.prototype.slice.call(body.rows).sort(function (a, b) {
+        var x = key(a), y = key(b);
+        var order = typeof x === "number" ? x - y : x.localeCompare(y);
+        return ascending ? order : -order;
+      }).forEach(function (row) {
+        body.appendChild(row);
+      });
+    });
+  });
+
+  function toggleAll(open) {
+    document.querySelectorAll(".scanner-section").forEach(function (section) {
+      section.open = open;
+    });
+  }
+  document.getElementById("expand-all").addEventListener("click", function () { toggleAll(true); });
+  document.getElementById("collapse-all").addEventListener("click", function () { toggleAll(false); });
+
+  apply();
+})();
 `
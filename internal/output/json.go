@@ -7,10 +7,16 @@ import (
 	"github.com/buemura/hunter/pkg/types"
 )
 
-// JSONFormatter renders results as indented JSON.
-type JSONFormatter struct{}
+// JSONFormatter renders results as indented JSON. The raw request/response
+// artifacts attached to findings are left out unless Artifacts is set.
+type JSONFormatter struct {
+	Artifacts bool
+}
 
 func (f *JSONFormatter) Format(w io.Writer, results []types.ScanResult) error {
+	if !f.Artifacts {
+		results = types.WithoutArtifacts(results)
+	}
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(results)
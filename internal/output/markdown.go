@@ -6,28 +6,39 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/buemura/hunter/internal/i18n"
 	"github.com/buemura/hunter/pkg/types"
 )
 
 // MarkdownFormatter renders results as Markdown tables suitable for
-// pasting into docs, issues, or pull-request descriptions.
-type MarkdownFormatter struct{}
+// pasting into docs, issues, or pull-request descriptions, after an
+// executive summary for readers who will not go through every finding.
+type MarkdownFormatter struct {
+	// Locale is the language of the report; nil is English.
+	Locale *i18n.Locale
+}
+
+// maxChartBar is the width, in characters, of the longest bar in the
+// summary's severity chart.
+const maxChartBar = 20
 
 func (f *MarkdownFormatter) Format(w io.Writer, results []types.ScanResult) error {
-	for i, result := range results {
-		if i > 0 {
-			fmt.Fprintln(w)
-		}
+	l := f.Locale
+	results = l.Results(results)
+	writeMarkdownSummary(w, summarize(results, l))
+
+	for _, result := range results {
+		fmt.Fprintln(w)
 
 		if result.Error != "" {
-			fmt.Fprintf(w, "## %s — Error\n\n> %s\n", result.ScannerName, result.Error)
+			fmt.Fprintf(w, "## %s — %s\n\n> %s\n", result.ScannerName, l.T("Error"), result.Error)
 			continue
 		}
 
 		fmt.Fprintf(w, "## %s — %s\n\n", result.ScannerName, result.Target.Host)
 
 		if len(result.Findings) == 0 {
-			fmt.Fprintln(w, "_No findings._")
+			fmt.Fprintf(w, "_%s_\n", l.T("No findings."))
 			continue
 		}
 
@@ -35,27 +46,78 @@ func (f *MarkdownFormatter) Format(w io.Writer, results []types.ScanResult) erro
 			return types.SeverityRank(result.Findings[i].Severity) < types.SeverityRank(result.Findings[j].Severity)
 		})
 
-		fmt.Fprintln(w, "| Severity | Title | Description |")
+		fmt.Fprintf(w, "| %s | %s | %s |\n", l.T("Severity"), l.T("Title"), l.T("Description"))
 		fmt.Fprintln(w, "|----------|-------|-------------|")
 
 		counts := map[types.Severity]int{}
 		for _, finding := range result.Findings {
 			counts[finding.Severity]++
-			sev := severityBadge(finding.Severity)
+			sev := severityBadge(l, finding.Severity)
 			title := escapeMarkdown(finding.Title)
 			desc := escapeMarkdown(finding.Description)
+			if len(finding.References) > 0 {
+				desc += "<br>" + l.T("References:") + " " + markdownLinks(finding.References)
+			}
 			fmt.Fprintf(w, "| %s | %s | %s |\n", sev, title, desc)
 		}
 
-		fmt.Fprintf(w, "\n%s\n", markdownSummary(counts))
+		fmt.Fprintf(w, "\n**%s** %s\n", l.T("Summary:"), formatSummary(l, counts))
 	}
 
 	return nil
 }
 
+// writeMarkdownSummary writes the executive summary: the overall verdict,
+// what was scanned, a text chart of findings by severity, and what to fix
+// first.
+func writeMarkdownSummary(w io.Writer, s executiveSummary) {
+	l := s.locale
+	fmt.Fprintf(w, "## %s\n\n%s\n\n", l.T("Executive Summary"), s.Verdict())
+
+	for _, f := range s.Engagement {
+		fmt.Fprintf(w, "- **%s** %s\n", l.T(f.Label+":"), f.Value)
+	}
+	if len(s.Scope) > 0 {
+		fmt.Fprintf(w, "- **%s** %s\n", l.T("Scope:"), strings.Join(s.Scope, ", "))
+	}
+	scanners := fmt.Sprintf("%d", s.Scanners)
+	if len(s.Failed) > 0 {
+		scanners += " " + l.T("(%d failed: %s)", len(s.Failed), strings.Join(s.Failed, ", "))
+	}
+	fmt.Fprintf(w, "- **%s** %s\n", l.T("Scanners run:"), scanners)
+	if d := s.DurationText(); d != "" {
+		fmt.Fprintf(w, "- **%s** %s\n", l.T("Duration:"), d)
+	}
+
+	fmt.Fprintf(w, "\n### %s\n\n", l.T("Findings by Severity"))
+	fmt.Fprintf(w, "| %s | %s | |\n", l.T("Severity"), l.T("Count"))
+	fmt.Fprintln(w, "|----------|------:|-|")
+	most := s.MaxCount()
+	for _, c := range s.Counts {
+		bar := ""
+		if c.Count > 0 {
+			bar = strings.Repeat("█", max(1, c.Count*maxChartBar/most))
+		}
+		fmt.Fprintf(w, "| %s | %d | %s |\n", severityBadge(l, c.Severity), c.Count, bar)
+	}
+
+	if len(s.TopRisks) > 0 {
+		fmt.Fprintf(w, "\n### %s\n\n", l.T("Top Risks"))
+		for i, r := range s.TopRisks {
+			fmt.Fprintf(w, "%d. %s %s (%s)\n", i+1, severityBadge(l, r.Severity), r.Title, plural(l, r.Count, "occurrence"))
+		}
+	}
+	if len(s.Fixes) > 0 {
+		fmt.Fprintf(w, "\n### %s\n\n", l.T("Remediation Priorities"))
+		for i, fx := range s.Fixes {
+			fmt.Fprintf(w, "%d. %s %s (%s)\n", i+1, severityBadge(l, fx.Severity), fx.Remediation, plural(l, fx.Count, "finding"))
+		}
+	}
+}
+
 // severityBadge returns a bold, uppercased severity label for Markdown.
-func severityBadge(s types.Severity) string {
-	return fmt.Sprintf("**%s**", string(s))
+func severityBadge(l *i18n.Locale, s types.Severity) string {
+	return fmt.Sprintf("**%s**", l.T(string(s)))
 }
 
 // escapeMarkdown escapes pipe characters that would break Markdown tables.
@@ -63,17 +125,12 @@ func escapeMarkdown(s string) string {
 	return strings.ReplaceAll(s, "|", "\\|")
 }
 
-func markdownSummary(counts map[types.Severity]int) string {
-	total := 0
-	for _, c := range counts {
-		total += c
+// markdownLinks renders references as a comma-separated list of links.
+func markdownLinks(refs []types.Reference) string {
+	links := make([]string, len(refs))
+	for i, ref := range refs {
+		title := strings.NewReplacer("[", "\\[", "]", "\\]").Replace(escapeMarkdown(ref.Title))
+		links[i] = fmt.Sprintf("[%s](%s)", title, escapeMarkdown(ref.URL))
 	}
-	return fmt.Sprintf("**Summary:** %d findings (%d critical, %d high, %d medium, %d low, %d info)",
-		total,
-		counts[types.SeverityCritical],
-		counts[types.SeverityHigh],
-		counts[types.SeverityMedium],
-		counts[types.SeverityLow],
-		counts[types.SeverityInfo],
-	)
+	return strings.Join(links, ", ")
 }
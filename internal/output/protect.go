@@ -0,0 +1,126 @@
+package output
+
+import (
+	"bytes"
+	"encoding/base64"
+	"html/template"
+	"io"
+
+	"github.com/buemura/hunter/internal/vault"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// ProtectedFormatter encrypts the output of another formatter with a
+// password, so a report can be sent over channels others can read. An HTML
+// report becomes a page that asks for the password and decrypts itself in
+// the browser; other formats are written sealed, to be opened with
+// `hunter decrypt`.
+type ProtectedFormatter struct {
+	Formatter Formatter
+	Password  string
+}
+
+func (f *ProtectedFormatter) Format(w io.Writer, results []types.ScanResult) error {
+	var buf bytes.Buffer
+	if err := f.Formatter.Format(&buf, results); err != nil {
+		return err
+	}
+	sealed, err := vault.SealPassword(f.Password, buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	html, ok := f.Formatter.(*HTMLFormatter)
+	if !ok {
+		_, err := w.Write(sealed)
+		return err
+	}
+	l := html.Locale
+	return protectedTpl.Execute(w, protectedPage{
+		Lang:        l.Tag(),
+		Title:       l.T("Hunter Scan Report"),
+		Prompt:      l.T("This report is protected with a password."),
+		Label:       l.T("Password"),
+		Button:      l.T("Open report"),
+		Wrong:       l.T("Wrong password."),
+		Unsupported: l.T("This browser cannot decrypt the report. Open it in a current browser, or from a local file or an HTTPS site."),
+		Sealed:      base64.StdEncoding.EncodeToString(sealed),
+		HeaderSize:  len(vault.PasswordHeader),
+		SaltSize:    vault.SaltSize,
+		NonceSize:   vault.NonceSize,
+		Iterations:  vault.Iterations,
+	})
+}
+
+// protectedPage is the data of a password-protected HTML report.
+type protectedPage struct {
+	Lang, Title, Prompt, Label, Button, Wrong, Unsupported string
+
+	// Sealed is the report sealed with vault.SealPassword, base64-encoded,
+	// and the rest describe its layout for the page's script.
+	Sealed                                      string
+	HeaderSize, SaltSize, NonceSize, Iterations int
+}
+
+// protectedTpl decrypts the report with the Web Crypto API and replaces
+// itself with it.
+var protectedTpl = template.Must(template.New("protected").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; background: #f5f5f5; color: #333; }
+form { max-width: 360px; margin: 80px auto; background: #fff; padding: 24px; border-radius: 6px; box-shadow: 0 1px 3px rgba(0,0,0,0.1); }
+input { width: 100%; box-sizing: border-box; padding: 8px; margin: 8px 0 16px; }
+button { padding: 8px 16px; }
+.error { color: #dc3545; }
+</style>
+</head>
+<body>
+<form id="unlock">
+  <h1>{{.Title}}</h1>
+  <p>{{.Prompt}}</p>
+  <label for="password">{{.Label}}</label>
+  <input type="password" id="password" autocomplete="off" autofocus>
+  <button type="submit">{{.Button}}</button>
+  <p class="error" id="error" role="alert"></p>
+</form>
+<script>
+(function() {
+  var sealed = {{.Sealed}};
+  var headerSize = {{.HeaderSize}}, saltSize = {{.SaltSize}}, nonceSize = {{.NonceSize}}, iterations = {{.Iterations}};
+  var form = document.getElementById('unlock');
+  var error = document.getElementById('error');
+
+  form.addEventListener('submit', async function(e) {
+    e.preventDefault();
+    error.textContent = '';
+    if (!window.crypto || !window.crypto.subtle) {
+      error.textContent = {{.Unsupported}};
+      return;
+    }
+    var data = Uint8Array.from(atob(sealed), function(c) { return c.charCodeAt(0); });
+    var salt = data.slice(headerSize, headerSize + saltSize);
+    var nonce = data.slice(headerSize + saltSize, headerSize + saltSize + nonceSize);
+    var ciphertext = data.slice(headerSize + saltSize + nonceSize);
+    try {
+      var password = new TextEncoder().encode(document.getElementById('password').value);
+      var base = await crypto.subtle.importKey('raw', password, 'PBKDF2', false, ['deriveKey']);
+      var key = await crypto.subtle.deriveKey(
+        {name: 'PBKDF2', salt: salt, iterations: iterations, hash: 'SHA-256'},
+        base, {name: 'AES-GCM', length: 256}, false, ['decrypt']);
+      var report = await crypto.subtle.decrypt({name: 'AES-GCM', iv: nonce}, key, ciphertext);
+      document.open();
+      document.write(new TextDecoder().decode(report));
+      document.close();
+    } catch (err) {
+      error.textContent = {{.Wrong}};
+    }
+  });
+})();
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,657 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// QueryFormatter evaluates a jq-like expression against the JSON form of the
+// results and prints each output value on its own line. Strings are printed
+// raw, everything else as compact JSON.
+//
+// Supported syntax:
+//
+//	.                 identity
+//	.field  .a.b      object field access
+//	.[]  .[n]         iterate an array (or object values), index an array
+//	a | b             pipe
+//	[ expr ]          collect all outputs into an array
+//	select(cond)      keep values for which cond is true
+//	length, keys      built-in functions
+//	== != < <= > >=   comparisons, combined with "and" / "or"
+//
+// Precedence follows jq: '|' binds loosest, then "or", then "and", then the
+// comparisons.
+type QueryFormatter struct {
+	expr   string
+	filter filter
+}
+
+// NewQueryFormatter parses expr and returns a formatter for it.
+func NewQueryFormatter(expr string) (*QueryFormatter, error) {
+	f, err := parseQuery(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query %q: %w", expr, err)
+	}
+	return &QueryFormatter{expr: expr, filter: f}, nil
+}
+
+func (f *QueryFormatter) Format(w io.Writer, results []types.ScanResult) error {
+	raw, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return err
+	}
+
+	values, err := f.filter(doc)
+	if err != nil {
+		return fmt.Errorf("query %q: %w", f.expr, err)
+	}
+	for _, v := range values {
+		line, err := renderQueryValue(v)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func renderQueryValue(v interface{}) (string, error) {
+	switch val := v.(type) {
+	case string:
+		return val, nil
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// filter maps one input value to zero or more output values.
+type filter func(v interface{}) ([]interface{}, error)
+
+type queryParser struct {
+	tokens []string
+	pos    int
+}
+
+func parseQuery(expr string) (filter, error) {
+	tokens, err := tokenizeQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+	p := &queryParser{tokens: tokens}
+	f, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return f, nil
+}
+
+func tokenizeQuery(expr string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case strings.ContainsRune(".[]()|", rune(c)):
+			tokens = append(tokens, string(c))
+			i++
+		case c == '=' || c == '!' || c == '<' || c == '>':
+			if i+1 < len(expr) && expr[i+1] == '=' {
+				tokens = append(tokens, expr[i:i+2])
+				i += 2
+			} else if c == '<' || c == '>' {
+				tokens = append(tokens, string(c))
+				i++
+			} else {
+				return nil, fmt.Errorf("unexpected %q at offset %d", c, i)
+			}
+		case c == '"':
+			j := i + 1
+			for j < len(expr) && expr[j] != '"' {
+				if expr[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(expr) {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			tokens = append(tokens, expr[i:j+1])
+			i = j + 1
+		case c == '-' || unicode.IsDigit(rune(c)):
+			j := i + 1
+			for j < len(expr) && (unicode.IsDigit(rune(expr[j])) || expr[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, expr[i:j])
+			i = j
+		case c == '_' || unicode.IsLetter(rune(c)):
+			j := i + 1
+			for j < len(expr) && (expr[j] == '_' || unicode.IsLetter(rune(expr[j])) || unicode.IsDigit(rune(expr[j]))) {
+				j++
+			}
+			tokens = append(tokens, expr[i:j])
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected %q at offset %d", c, i)
+		}
+	}
+	return tokens, nil
+}
+
+func (p *queryParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *queryParser) expect(tok string) error {
+	if p.peek() != tok {
+		if p.peek() == "" {
+			return fmt.Errorf("expected %q at end of expression", tok)
+		}
+		return fmt.Errorf("expected %q, got %q", tok, p.peek())
+	}
+	p.pos++
+	return nil
+}
+
+// parsePipe parses the loosest-binding level: expressions joined by '|'.
+// Precedence follows jq: '|' < "or" < "and" < comparisons < postfix.
+func (p *queryParser) parsePipe() (filter, error) {
+	left, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "|" {
+		p.pos++
+		right, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		left = pipeFilter(left, right)
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseOr() (filter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "or" {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalFilter("or", left, right)
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (filter, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "and" {
+		p.pos++
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalFilter("and", left, right)
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseComparison() (filter, error) {
+	left, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	switch op := p.peek(); op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		p.pos++
+		right, err := p.parsePostfix()
+		if err != nil {
+			return nil, err
+		}
+		return compareFilter(op, left, right), nil
+	}
+	return left, nil
+}
+
+func (p *queryParser) parsePostfix() (filter, error) {
+	f, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.peek() {
+		case ".":
+			p.pos++
+			next, err := p.parseAccessor()
+			if err != nil {
+				return nil, err
+			}
+			f = pipeFilter(f, next)
+		case "[":
+			next, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			f = pipeFilter(f, next)
+		default:
+			return f, nil
+		}
+	}
+}
+
+func (p *queryParser) parsePrimary() (filter, error) {
+	tok := p.peek()
+	switch {
+	case tok == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case tok == ".":
+		p.pos++
+		if isIdent(p.peek()) || p.peek() == "[" {
+			return p.parseAccessor()
+		}
+		return identityFilter, nil
+	case tok == "[":
+		p.pos++
+		inner, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		return collectFilter(inner), nil
+	case tok == "(":
+		p.pos++
+		inner, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	case tok == "select":
+		p.pos++
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		cond, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return selectFilter(cond), nil
+	case tok == "length":
+		p.pos++
+		return lengthFilter, nil
+	case tok == "keys":
+		p.pos++
+		return keysFilter, nil
+	case tok == "true" || tok == "false" || tok == "null":
+		p.pos++
+		var v interface{}
+		_ = json.Unmarshal([]byte(tok), &v)
+		return literalFilter(v), nil
+	case strings.HasPrefix(tok, `"`):
+		p.pos++
+		s, err := strconv.Unquote(tok)
+		if err != nil {
+			return nil, fmt.Errorf("invalid string %s", tok)
+		}
+		return literalFilter(s), nil
+	case tok[0] == '-' || unicode.IsDigit(rune(tok[0])):
+		p.pos++
+		n, err := strconv.ParseFloat(tok, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", tok)
+		}
+		return literalFilter(n), nil
+	}
+	return nil, fmt.Errorf("unexpected %q", tok)
+}
+
+// parseAccessor parses what follows a '.': a field name or a bracket.
+func (p *queryParser) parseAccessor() (filter, error) {
+	tok := p.peek()
+	if tok == "[" {
+		return p.parseBracket()
+	}
+	if !isIdent(tok) {
+		return nil, fmt.Errorf("expected field name after '.', got %q", tok)
+	}
+	p.pos++
+	return fieldFilter(tok), nil
+}
+
+// parseBracket parses "[]" or "[n]".
+func (p *queryParser) parseBracket() (filter, error) {
+	if err := p.expect("["); err != nil {
+		return nil, err
+	}
+	if p.peek() == "]" {
+		p.pos++
+		return iterateFilter, nil
+	}
+	n, err := strconv.Atoi(p.peek())
+	if err != nil {
+		return nil, fmt.Errorf("expected array index, got %q", p.peek())
+	}
+	p.pos++
+	if err := p.expect("]"); err != nil {
+		return nil, err
+	}
+	return indexFilter(n), nil
+}
+
+func isIdent(tok string) bool {
+	return tok != "" && (tok[0] == '_' || unicode.IsLetter(rune(tok[0])))
+}
+
+func identityFilter(v interface{}) ([]interface{}, error) {
+	return []interface{}{v}, nil
+}
+
+func literalFilter(lit interface{}) filter {
+	return func(interface{}) ([]interface{}, error) {
+		return []interface{}{lit}, nil
+	}
+}
+
+func fieldFilter(name string) filter {
+	return func(v interface{}) ([]interface{}, error) {
+		switch val := v.(type) {
+		case nil:
+			return []interface{}{nil}, nil
+		case map[string]interface{}:
+			return []interface{}{val[name]}, nil
+		}
+		return nil, fmt.Errorf("cannot index %s with %q", typeName(v), name)
+	}
+}
+
+func indexFilter(i int) filter {
+	return func(v interface{}) ([]interface{}, error) {
+		switch val := v.(type) {
+		case nil:
+			return []interface{}{nil}, nil
+		case []interface{}:
+			idx := i
+			if idx < 0 {
+				idx += len(val)
+			}
+			if idx < 0 || idx >= len(val) {
+				return []interface{}{nil}, nil
+			}
+			return []interface{}{val[idx]}, nil
+		}
+		return nil, fmt.Errorf("cannot index %s with a number", typeName(v))
+	}
+}
+
+func iterateFilter(v interface{}) ([]interface{}, error) {
+	switch val := v.(type) {
+	case []interface{}:
+		return val, nil
+	case map[string]interface{}:
+		keys := sortedKeys(val)
+		out := make([]interface{}, len(keys))
+		for i, k := range keys {
+			out[i] = val[k]
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("cannot iterate over %s", typeName(v))
+}
+
+func lengthFilter(v interface{}) ([]interface{}, error) {
+	switch val := v.(type) {
+	case nil:
+		return []interface{}{0.0}, nil
+	case string:
+		return []interface{}{float64(len([]rune(val)))}, nil
+	case []interface{}:
+		return []interface{}{float64(len(val))}, nil
+	case map[string]interface{}:
+		return []interface{}{float64(len(val))}, nil
+	}
+	return nil, fmt.Errorf("%s has no length", typeName(v))
+}
+
+func keysFilter(v interface{}) ([]interface{}, error) {
+	val, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s has no keys", typeName(v))
+	}
+	keys := sortedKeys(val)
+	out := make([]interface{}, len(keys))
+	for i, k := range keys {
+		out[i] = k
+	}
+	return []interface{}{out}, nil
+}
+
+func pipeFilter(left, right filter) filter {
+	return func(v interface{}) ([]interface{}, error) {
+		in, err := left(v)
+		if err != nil {
+			return nil, err
+		}
+		var out []interface{}
+		for _, item := range in {
+			res, err := right(item)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, res...)
+		}
+		return out, nil
+	}
+}
+
+func collectFilter(inner filter) filter {
+	return func(v interface{}) ([]interface{}, error) {
+		out, err := inner(v)
+		if err != nil {
+			return nil, err
+		}
+		if out == nil {
+			out = []interface{}{}
+		}
+		return []interface{}{out}, nil
+	}
+}
+
+func selectFilter(cond filter) filter {
+	return func(v interface{}) ([]interface{}, error) {
+		res, err := cond(v)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range res {
+			if truthy(r) {
+				return []interface{}{v}, nil
+			}
+		}
+		return nil, nil
+	}
+}
+
+// logicalFilter evaluates the left side and, for each of its outputs that
+// does not short-circuit, every output of the right side, as jq does.
+func logicalFilter(op string, left, right filter) filter {
+	return func(v interface{}) ([]interface{}, error) {
+		ls, err := left(v)
+		if err != nil {
+			return nil, err
+		}
+		var out []interface{}
+		for _, l := range ls {
+			if op == "and" && !truthy(l) {
+				out = append(out, false)
+				continue
+			}
+			if op == "or" && truthy(l) {
+				out = append(out, true)
+				continue
+			}
+			rs, err := right(v)
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range rs {
+				out = append(out, truthy(r))
+			}
+		}
+		return out, nil
+	}
+}
+
+// compareFilter compares every pair of left and right outputs, producing one
+// boolean per pair. Like jq, the right side is the outer loop.
+func compareFilter(op string, left, right filter) filter {
+	return func(v interface{}) ([]interface{}, error) {
+		ls, err := left(v)
+		if err != nil {
+			return nil, err
+		}
+		rs, err := right(v)
+		if err != nil {
+			return nil, err
+		}
+		var out []interface{}
+		for _, r := range rs {
+			for _, l := range ls {
+				res, err := compareValues(op, l, r)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, res)
+			}
+		}
+		return out, nil
+	}
+}
+
+func compareValues(op string, l, r interface{}) (bool, error) {
+	switch op {
+	case "==":
+		return reflect.DeepEqual(l, r), nil
+	case "!=":
+		return !reflect.DeepEqual(l, r), nil
+	}
+
+	var cmp int
+	switch lv := l.(type) {
+	case float64:
+		rv, ok := r.(float64)
+		if !ok {
+			return false, fmt.Errorf("cannot compare number with %s", typeName(r))
+		}
+		cmp = compareNumbers(lv, rv)
+	case string:
+		rv, ok := r.(string)
+		if !ok {
+			return false, fmt.Errorf("cannot compare string with %s", typeName(r))
+		}
+		cmp = strings.Compare(lv, rv)
+	default:
+		return false, fmt.Errorf("cannot order %s", typeName(l))
+	}
+
+	switch op {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
+
+func compareNumbers(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func truthy(v interface{}) bool {
+	if v == nil {
+		return false
+	}
+	if b, ok := v.(bool); ok {
+		return b
+	}
+	return true
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
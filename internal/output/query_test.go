@@ -0,0 +1,115 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runQuery(t *testing.T, expr string, results []types.ScanResult) string {
+	t.Helper()
+	f, err := NewQueryFormatter(expr)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, f.Format(&buf, results))
+	return buf.String()
+}
+
+func queryResults() []types.ScanResult {
+	return []types.ScanResult{
+		{
+			ScannerName: "port",
+			Target:      types.Target{Host: "example.com", Scheme: "https"},
+			Findings: []types.Finding{
+				{Title: "Open port: 80/HTTP", Severity: types.SeverityInfo, Metadata: map[string]string{"port": "80"}},
+				{Title: "Open port: 22/SSH", Severity: types.SeverityMedium, Metadata: map[string]string{"port": "22"}},
+			},
+		},
+		{
+			ScannerName: "vuln",
+			Target:      types.Target{Host: "example.com", Scheme: "https"},
+			Findings: []types.Finding{
+				{Title: "SQL injection", Severity: types.SeverityCritical},
+				{Title: "Reflected XSS", Severity: types.SeverityHigh},
+			},
+		},
+	}
+}
+
+func TestQuery_FieldAccess(t *testing.T) {
+	assert.Equal(t, "port\n", runQuery(t, ".[0].scanner_name", queryResults()))
+	assert.Equal(t, "example.com\n", runQuery(t, ".[1].target.host", queryResults()))
+	assert.Equal(t, "vuln\n", runQuery(t, ".[-1].scanner_name", queryResults()))
+}
+
+func TestQuery_IterateAndPipe(t *testing.T) {
+	out := runQuery(t, `.[] | select(.scanner_name == "port") | .findings[].metadata.port`, queryResults())
+	assert.Equal(t, "80\n22\n", out)
+}
+
+func TestQuery_CountCritical(t *testing.T) {
+	out := runQuery(t, `[.[].findings[] | select(.severity == "CRITICAL")] | length`, queryResults())
+	assert.Equal(t, "1\n", out)
+}
+
+func TestQuery_LogicalOperators(t *testing.T) {
+	out := runQuery(t, `.[].findings[] | select(.severity == "CRITICAL" or .severity == "HIGH") | .title`, queryResults())
+	assert.Equal(t, "SQL injection\nReflected XSS\n", out)
+
+	out = runQuery(t, `.[] | select((.findings | length) > 1 and .scanner_name != "port") | .scanner_name`, queryResults())
+	assert.Equal(t, "vuln\n", out)
+}
+
+func TestQuery_OperatorPrecedence(t *testing.T) {
+	// "and" binds tighter than "or": true or (false and false).
+	assert.Equal(t, "true\n", runQuery(t, `true or false and false`, nil))
+	assert.Equal(t, "false\n", runQuery(t, `(true or false) and false`, nil))
+
+	// '|' binds looser than comparisons.
+	out := runQuery(t, `.[0].findings | length == 2`, queryResults())
+	assert.Equal(t, "true\n", out)
+}
+
+func TestQuery_MultiOutputComparison(t *testing.T) {
+	out := runQuery(t, `.[].scanner_name == "vuln"`, queryResults())
+	assert.Equal(t, "false\ntrue\n", out)
+
+	out = runQuery(t, `.[0].findings[].severity == (.[].findings[0].severity)`, queryResults())
+	assert.Equal(t, "true\nfalse\nfalse\nfalse\n", out)
+
+	out = runQuery(t, `[.[].findings[] | select(.severity == "HIGH" or .severity == "CRITICAL")] | length`, queryResults())
+	assert.Equal(t, "2\n", out)
+
+	out = runQuery(t, `.[] | select(.findings[].severity == "CRITICAL") | .scanner_name`, queryResults())
+	assert.Equal(t, "vuln\n", out)
+}
+
+func TestQuery_CompositeValuesAsJSON(t *testing.T) {
+	assert.Equal(t, `["host","scheme"]`+"\n", runQuery(t, ".[0].target | keys", queryResults()))
+	assert.Equal(t, `{"port":"80"}`+"\n", runQuery(t, ".[0].findings[0].metadata", queryResults()))
+	assert.Equal(t, "null\n", runQuery(t, ".[0].missing", queryResults()))
+}
+
+func TestQuery_EmptyResult(t *testing.T) {
+	assert.Equal(t, "", runQuery(t, `.[] | select(.scanner_name == "ssl")`, queryResults()))
+	assert.Equal(t, "[]\n", runQuery(t, `[.[] | select(.scanner_name == "ssl")]`, queryResults()))
+}
+
+func TestQuery_ParseErrors(t *testing.T) {
+	for _, expr := range []string{"", ".[", "select(.a", `.a == "x`, ".a $", ".[x]"} {
+		_, err := NewQueryFormatter(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestQuery_RuntimeError(t *testing.T) {
+	f, err := NewQueryFormatter(".[0].scanner_name[]")
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	err = f.Format(&buf, queryResults())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot iterate over string")
+}
@@ -0,0 +1,171 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/buemura/hunter/internal/rules"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// SARIFFormatter renders results as a SARIF 2.1.0 log, the format code
+// scanning dashboards such as GitHub's ingest. Each distinct finding title
+// becomes a rule; scanner errors are reported as tool notifications.
+type SARIFFormatter struct{}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Results     []sarifResult     `json:"results"`
+	Invocations []sarifInvocation `json:"invocations,omitempty"`
+	// Properties holds the engagement the scan was run under, keyed as in
+	// the metadata of results.
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	Name             string        `json:"name"`
+	ShortDescription sarifMessage  `json:"shortDescription"`
+	Help             *sarifMessage `json:"help,omitempty"`
+	HelpURI          string        `json:"helpUri,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level   string       `json:"level"`
+	Message sarifMessage `json:"message"`
+}
+
+func (f *SARIFFormatter) Format(w io.Writer, results []types.ScanResult) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "hunter",
+			InformationURI: "https://github.com/buemura/hunter",
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+
+	seen := map[string]bool{}
+	invocation := sarifInvocation{ExecutionSuccessful: true}
+	for _, result := range results {
+		if result.Error != "" {
+			invocation.ExecutionSuccessful = false
+			invocation.ToolExecutionNotifications = append(invocation.ToolExecutionNotifications, sarifNotification{
+				Level:   "error",
+				Message: sarifMessage{Text: result.ScannerName + ": " + result.Error},
+			})
+			continue
+		}
+
+		for _, finding := range result.Findings {
+			id, name := sarifRuleID(result.ScannerName, finding)
+			if !seen[id] {
+				seen[id] = true
+				rule := sarifRule{ID: id, Name: name, ShortDescription: sarifMessage{Text: name}}
+				if finding.Remediation != "" {
+					rule.Help = &sarifMessage{Text: finding.Remediation}
+				}
+				if len(finding.References) > 0 {
+					rule.HelpURI = finding.References[0].URL
+				}
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+			}
+
+			message := finding.Description
+			if message == "" {
+				message = finding.Title
+			}
+			sr := sarifResult{
+				RuleID:  id,
+				Level:   sarifLevel(finding.Severity),
+				Message: sarifMessage{Text: message},
+				Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: targetName(result.Target)},
+				}}},
+				Properties: map[string]string{"severity": string(finding.Severity)},
+			}
+			if finding.Evidence != "" {
+				sr.Properties["evidence"] = finding.Evidence
+			}
+			run.Results = append(run.Results, sr)
+		}
+	}
+	if len(invocation.ToolExecutionNotifications) > 0 {
+		run.Invocations = []sarifInvocation{invocation}
+	}
+	if e := types.EngagementOf(results); !e.IsZero() {
+		run.Properties = e.Metadata()
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{Version: "2.1.0", Schema: sarifSchema, Runs: []sarifRun{run}})
+}
+
+// sarifRuleID returns the ID and name of the SARIF rule for a finding: its
+// Hunter rule, so code-scanning alerts survive rewording and carry no ports
+// or paths, or for findings no rule covers, a slug of the title.
+func sarifRuleID(scanner string, f types.Finding) (id, name string) {
+	if r, ok := rules.Lookup(f.Metadata[rules.MetadataKey]); ok {
+		return r.ID, r.Name
+	}
+	return types.RuleID(scanner, f.Title), f.Title
+}
+
+// sarifLevel maps Hunter severities onto SARIF's error/warning/note levels.
+func sarifLevel(s types.Severity) string {
+	switch s {
+	case types.SeverityCritical, types.SeverityHigh:
+		return "error"
+	case types.SeverityMedium:
+		return "warning"
+	}
+	return "note"
+}
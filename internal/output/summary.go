@@ -0,0 +1,247 @@
+package output
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/internal/i18n"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// maxTopRisks is how many issues an executive summary lists as top risks.
+const maxTopRisks = 5
+
+// maxRemediations is how many fixes an executive summary lists.
+const maxRemediations = 10
+
+// severityOrder lists the severities from most to least severe.
+var severityOrder = []types.Severity{
+	types.SeverityCritical,
+	types.SeverityHigh,
+	types.SeverityMedium,
+	types.SeverityLow,
+	types.SeverityInfo,
+}
+
+// executiveSummary condenses results for readers who will not read every
+// finding: how bad things are, where, and what to fix first.
+type executiveSummary struct {
+	// Engagement lists the set fields of the engagement the scan was run
+	// under.
+	Engagement []engagementField
+
+	Total    int
+	Counts   []severityCount
+	Highest  types.Severity
+	Scope    []string
+	Scanners int
+	Failed   []string
+	Duration time.Duration
+	TopRisks []risk
+	Fixes    []fix
+
+	locale *i18n.Locale
+}
+
+// engagementField is a field of the engagement a scan was run under, with
+// its label in English.
+type engagementField struct {
+	Label string
+	Value string
+}
+
+// engagementFields returns the set fields of e in the order reports list
+// them.
+func engagementFields(e types.Engagement) []engagementField {
+	var fields []engagementField
+	for _, f := range []engagementField{
+		{"Client", e.Client},
+		{"Engagement ID", e.ID},
+		{"Tester", e.Tester},
+		{"Authorization", e.Authorization},
+		{"Notes", e.Notes},
+	} {
+		if f.Value != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// severityCount is how many findings have a severity.
+type severityCount struct {
+	Severity types.Severity
+	Count    int
+}
+
+// risk is an issue, the findings sharing a title, ranked by its severity and
+// how often it was found.
+type risk struct {
+	Title    string
+	Severity types.Severity
+	Count    int
+}
+
+// fix is a remediation shared by findings, ranked by the most severe of them.
+type fix struct {
+	Remediation string
+	Severity    types.Severity
+	Count       int
+}
+
+// summarize builds the executive summary of results, worded in the
+// language of l.
+func summarize(results []types.ScanResult, l *i18n.Locale) executiveSummary {
+	s := executiveSummary{Engagement: engagementFields(types.EngagementOf(results)), locale: l}
+	counts := map[types.Severity]int{}
+	risks := map[string]*risk{}
+	fixes := map[string]*fix{}
+	scope := map[string]bool{}
+	var started, completed time.Time
+
+	for _, r := range results {
+		s.Scanners++
+		if r.Error != "" {
+			s.Failed = append(s.Failed, r.ScannerName)
+		}
+		if t := targetName(r.Target); t != "" && !scope[t] {
+			scope[t] = true
+			s.Scope = append(s.Scope, t)
+		}
+		if !r.StartedAt.IsZero() && (started.IsZero() || r.StartedAt.Before(started)) {
+			started = r.StartedAt
+		}
+		if r.CompletedAt.After(completed) {
+			completed = r.CompletedAt
+		}
+
+		for _, f := range r.Findings {
+			s.Total++
+			counts[f.Severity]++
+			if f.Severity == types.SeverityInfo {
+				continue
+			}
+			if rk, ok := risks[f.Title]; ok {
+				rk.Count++
+			} else {
+				risks[f.Title] = &risk{Title: f.Title, Severity: f.Severity, Count: 1}
+			}
+			if f.Remediation == "" {
+				continue
+			}
+			if fx, ok := fixes[f.Remediation]; ok {
+				fx.Count++
+				if types.SeverityRank(f.Severity) < types.SeverityRank(fx.Severity) {
+					fx.Severity = f.Severity
+				}
+			} else {
+				fixes[f.Remediation] = &fix{Remediation: f.Remediation, Severity: f.Severity, Count: 1}
+			}
+		}
+	}
+
+	for _, sev := range severityOrder {
+		s.Counts = append(s.Counts, severityCount{Severity: sev, Count: counts[sev]})
+		if s.Highest == "" && counts[sev] > 0 {
+			s.Highest = sev
+		}
+	}
+	if !started.IsZero() && completed.After(started) {
+		s.Duration = completed.Sub(started).Round(time.Second)
+	}
+
+	for _, rk := range risks {
+		s.TopRisks = append(s.TopRisks, *rk)
+	}
+	sort.Slice(s.TopRisks, func(i, j int) bool {
+		a, b := s.TopRisks[i], s.TopRisks[j]
+		if a.Severity != b.Severity {
+			return types.SeverityRank(a.Severity) < types.SeverityRank(b.Severity)
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Title < b.Title
+	})
+	if len(s.TopRisks) > maxTopRisks {
+		s.TopRisks = s.TopRisks[:maxTopRisks]
+	}
+
+	for _, fx := range fixes {
+		s.Fixes = append(s.Fixes, *fx)
+	}
+	sort.Slice(s.Fixes, func(i, j int) bool {
+		a, b := s.Fixes[i], s.Fixes[j]
+		if a.Severity != b.Severity {
+			return types.SeverityRank(a.Severity) < types.SeverityRank(b.Severity)
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Remediation < b.Remediation
+	})
+	if len(s.Fixes) > maxRemediations {
+		s.Fixes = s.Fixes[:maxRemediations]
+	}
+	return s
+}
+
+// Verdict is the summary's opening sentence, in plain language.
+func (s executiveSummary) Verdict() string {
+	l := s.locale
+	targets := l.T("the target")
+	if len(s.Scope) > 1 {
+		targets = l.T("%d targets", len(s.Scope))
+	}
+	switch s.Highest {
+	case "":
+		return l.T("The scan of %s found no security issues.", targets)
+	case types.SeverityInfo:
+		return l.T("The scan of %s found no security issues, only %s for reference.", targets, plural(l, s.Total, "informational finding"))
+	}
+	serious := 0
+	for _, c := range s.Counts {
+		if c.Severity == types.SeverityCritical || c.Severity == types.SeverityHigh {
+			serious += c.Count
+		}
+	}
+	verdict := l.T("The scan of %s found %s; the most serious is rated %s.", targets, plural(l, s.Total, "finding"), strings.ToLower(l.T(string(s.Highest))))
+	if serious > 0 {
+		verdict += " " + l.T("%s rated critical or high should be fixed first.", capitalize(plural(l, serious, "issue")))
+	}
+	return verdict
+}
+
+// DurationText describes how long the scan took, or "" when unknown.
+func (s executiveSummary) DurationText() string {
+	if s.Duration <= 0 {
+		return ""
+	}
+	return s.Duration.String()
+}
+
+// MaxCount is the largest severity count, which scales the chart's bars.
+func (s executiveSummary) MaxCount() int {
+	n := 0
+	for _, c := range s.Counts {
+		if c.Count > n {
+			n = c.Count
+		}
+	}
+	return n
+}
+
+// plural formats n with noun, pluralized when n is not 1, in the language
+// of l.
+func plural(l *i18n.Locale, n int, noun string) string {
+	return l.N(n, "%d "+noun, "%d "+noun+"s")
+}
+
+// capitalize upper-cases the first letter of s.
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -5,25 +5,37 @@ import (
 	"io"
 	"sort"
 
+	"github.com/buemura/hunter/internal/i18n"
 	"github.com/buemura/hunter/pkg/types"
 	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
 )
 
 // TableFormatter renders results as a colored terminal table.
-type TableFormatter struct{}
+type TableFormatter struct {
+	// Locale is the language of the output; nil is English.
+	Locale *i18n.Locale
+}
 
 func (f *TableFormatter) Format(w io.Writer, results []types.ScanResult) error {
+	l := f.Locale
+	results = l.Results(results)
+	for _, f := range engagementFields(types.EngagementOf(results)) {
+		fmt.Fprintf(w, "%s %s\n", l.T(f.Label+":"), f.Value)
+	}
 	for _, result := range results {
 		if result.Error != "" {
-			fmt.Fprintf(w, "\n[%s] Error: %s\n", result.ScannerName, result.Error)
+			fmt.Fprintf(w, "\n[%s] %s %s\n", result.ScannerName, l.T("Error:"), result.Error)
 			continue
 		}
 
-		fmt.Fprintf(w, "\n[%s] %s — %d findings\n", result.ScannerName, result.Target.Host, len(result.Findings))
+		fmt.Fprintf(w, "\n[%s] %s — %s\n", result.ScannerName, result.Target.Host, l.T("%d findings", len(result.Findings)))
+		if result.Metadata["partial"] == "true" {
+			fmt.Fprintf(w, "  %s\n", l.T("Partial: stopped when its %s of the time budget ran out.", result.Metadata["time_budget"]))
+		}
 
 		if len(result.Findings) == 0 {
-			fmt.Fprintln(w, "  No findings.")
+			fmt.Fprintf(w, "  %s\n", l.T("No findings."))
 			continue
 		}
 
@@ -33,7 +45,7 @@ func (f *TableFormatter) Format(w io.Writer, results []types.ScanResult) error {
 		})
 
 		table := tablewriter.NewWriter(w)
-		table.SetHeader([]string{"Severity", "Title", "Description"})
+		table.SetHeader([]string{l.T("Severity"), l.T("Title"), l.T("Description")})
 		table.SetAutoWrapText(false)
 		table.SetBorder(false)
 		table.SetColumnSeparator("│")
@@ -42,41 +54,40 @@ func (f *TableFormatter) Format(w io.Writer, results []types.ScanResult) error {
 
 		for _, finding := range result.Findings {
 			counts[finding.Severity]++
-			sev := colorSeverity(finding.Severity)
+			sev := colorSeverity(l, finding.Severity)
 			table.Append([]string{sev, finding.Title, finding.Description})
 		}
 
 		table.Render()
 
-		fmt.Fprintf(w, "  Summary: %s\n", formatSummary(counts))
+		fmt.Fprintf(w, "  %s %s\n", l.T("Summary:"), formatSummary(l, counts))
 	}
 
 	return nil
 }
 
-func colorSeverity(s types.Severity) string {
+func colorSeverity(l *i18n.Locale, s types.Severity) string {
+	label := l.T(string(s))
 	switch s {
-	case types.SeverityCritical:
-		return color.RedString("CRITICAL")
-	case types.SeverityHigh:
-		return color.RedString("HIGH")
+	case types.SeverityCritical, types.SeverityHigh:
+		return color.RedString(label)
 	case types.SeverityMedium:
-		return color.YellowString("MEDIUM")
+		return color.YellowString(label)
 	case types.SeverityLow:
-		return color.CyanString("LOW")
+		return color.CyanString(label)
 	case types.SeverityInfo:
-		return color.WhiteString("INFO")
+		return color.WhiteString(label)
 	default:
-		return string(s)
+		return label
 	}
 }
 
-func formatSummary(counts map[types.Severity]int) string {
+func formatSummary(l *i18n.Locale, counts map[types.Severity]int) string {
 	total := 0
 	for _, c := range counts {
 		total += c
 	}
-	return fmt.Sprintf("%d findings (%d critical, %d high, %d medium, %d low, %d info)",
+	return l.T("%d findings (%d critical, %d high, %d medium, %d low, %d info)",
 		total,
 		counts[types.SeverityCritical],
 		counts[types.SeverityHigh],
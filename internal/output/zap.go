@@ -0,0 +1,235 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// ZAPFormatter renders results in the layout of OWASP ZAP's traditional
+// JSON report: one site per target, with an alert per distinct finding
+// title and an instance per finding. Tools that ingest ZAP reports, and
+// ZAP's own report viewers, can then pick up Hunter's findings for manual
+// follow-up. Scanner errors are left out.
+type ZAPFormatter struct{}
+
+type zapReport struct {
+	Version   string    `json:"@version"`
+	Generated string    `json:"@generated"`
+	Sites     []zapSite `json:"site"`
+	// Engagement is not part of ZAP's report; tools reading it skip it.
+	Engagement *types.Engagement `json:"engagement,omitempty"`
+}
+
+type zapSite struct {
+	Name   string     `json:"@name"`
+	Host   string     `json:"@host"`
+	Port   string     `json:"@port"`
+	SSL    string     `json:"@ssl"`
+	Alerts []zapAlert `json:"alerts"`
+}
+
+type zapAlert struct {
+	PluginID   string        `json:"pluginid"`
+	AlertRef   string        `json:"alertRef"`
+	Alert      string        `json:"alert"`
+	Name       string        `json:"name"`
+	RiskCode   string        `json:"riskcode"`
+	Confidence string        `json:"confidence"`
+	RiskDesc   string        `json:"riskdesc"`
+	Desc       string        `json:"desc"`
+	Instances  []zapInstance `json:"instances"`
+	Count      string        `json:"count"`
+	Solution   string        `json:"solution"`
+	OtherInfo  string        `json:"otherinfo"`
+	Reference  string        `json:"reference"`
+	CWEID      string        `json:"cweid"`
+	WASCID     string        `json:"wascid"`
+	SourceID   string        `json:"sourceid"`
+}
+
+type zapInstance struct {
+	URI       string `json:"uri"`
+	Method    string `json:"method"`
+	Param     string `json:"param"`
+	Attack    string `json:"attack"`
+	Evidence  string `json:"evidence"`
+	OtherInfo string `json:"otherinfo"`
+}
+
+// zapConfidence maps a finding's confidence onto ZAP's confidence codes;
+// findings that do not grade it get ZAP's middle level.
+func zapConfidence(f types.Finding) string {
+	switch f.Metadata["confidence"] {
+	case types.ConfidenceTentative:
+		return "1"
+	case types.ConfidenceCertain:
+		return "3"
+	}
+	return "2"
+}
+
+// zapConfidenceNames are ZAP's names for its confidence codes.
+var zapConfidenceNames = map[string]string{"1": "Low", "2": "Medium", "3": "High"}
+
+func (f *ZAPFormatter) Format(w io.Writer, results []types.ScanResult) error {
+	report := zapReport{Version: "hunter", Sites: []zapSite{}}
+
+	var generated time.Time
+	sites := map[string]int{}
+	alerts := map[string]map[string]int{}
+	for _, result := range results {
+		if result.CompletedAt.After(generated) {
+			generated = result.CompletedAt
+		}
+		if result.Error != "" || len(result.Findings) == 0 {
+			continue
+		}
+
+		site := zapSiteFor(result.Target)
+		i, ok := sites[site.Name]
+		if !ok {
+			i = len(report.Sites)
+			sites[site.Name] = i
+			alerts[site.Name] = map[string]int{}
+			report.Sites = append(report.Sites, site)
+		}
+
+		for _, finding := range result.Findings {
+			id := types.RuleID(result.ScannerName, finding.Title)
+			j, ok := alerts[site.Name][id]
+			if !ok {
+				j = len(report.Sites[i].Alerts)
+				alerts[site.Name][id] = j
+				risk, confidence := zapRisk(finding.Severity), zapConfidence(finding)
+				report.Sites[i].Alerts = append(report.Sites[i].Alerts, zapAlert{
+					PluginID:   id,
+					AlertRef:   id,
+					Alert:      finding.Title,
+					Name:       finding.Title,
+					RiskCode:   risk,
+					Confidence: confidence,
+					RiskDesc:   zapRiskNames[risk] + " (" + zapConfidenceNames[confidence] + ")",
+					Desc:       htmlParagraph(finding.Description),
+					Solution:   htmlParagraph(finding.Remediation),
+					Reference:  htmlReferences(finding.References),
+					CWEID:      "-1",
+					WASCID:     "-1",
+					SourceID:   "0",
+				})
+			}
+
+			uri, method, param := findingLocation(result.Target, finding)
+			alert := &report.Sites[i].Alerts[j]
+			alert.Instances = append(alert.Instances, zapInstance{
+				URI:      uri,
+				Method:   method,
+				Param:    param,
+				Evidence: finding.Evidence,
+			})
+			alert.Count = fmt.Sprintf("%d", len(alert.Instances))
+		}
+	}
+	if !generated.IsZero() {
+		report.Generated = generated.UTC().Format(time.RFC1123)
+	}
+	if e := types.EngagementOf(results); !e.IsZero() {
+		report.Engagement = &e
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// zapRiskNames are ZAP's names for its risk codes.
+var zapRiskNames = map[string]string{"0": "Informational", "1": "Low", "2": "Medium", "3": "High"}
+
+// zapRisk maps Hunter severities onto ZAP's risk codes, which stop at High.
+func zapRisk(s types.Severity) string {
+	switch s {
+	case types.SeverityCritical, types.SeverityHigh:
+		return "3"
+	case types.SeverityMedium:
+		return "2"
+	case types.SeverityLow:
+		return "1"
+	}
+	return "0"
+}
+
+// htmlParagraph wraps text in the HTML paragraph ZAP and Burp use for
+// descriptions and solutions.
+func htmlParagraph(text string) string {
+	if text == "" {
+		return ""
+	}
+	return "<p>" + html.EscapeString(text) + "</p>"
+}
+
+// htmlReferences lists references as the HTML paragraphs of links ZAP and
+// Burp use for them.
+func htmlReferences(refs []types.Reference) string {
+	var b strings.Builder
+	for _, ref := range refs {
+		fmt.Fprintf(&b, "<p><a href=\"%s\">%s</a></p>", html.EscapeString(ref.URL), html.EscapeString(ref.Title))
+	}
+	return b.String()
+}
+
+// zapSiteFor returns the site a target belongs to: its scheme, host, and
+// port.
+func zapSiteFor(t types.Target) zapSite {
+	scheme, host, port := t.Scheme, t.Host, ""
+	if u, err := url.Parse(t.URL); err == nil && u.Host != "" {
+		scheme, host, port = u.Scheme, u.Hostname(), u.Port()
+	}
+	if scheme == "" {
+		scheme = "https"
+	}
+	if port == "" {
+		port = "443"
+		if scheme == "http" {
+			port = "80"
+		}
+	}
+	name := scheme + "://" + host
+	if (scheme == "https" && port != "443") || (scheme == "http" && port != "80") {
+		name += ":" + port
+	}
+	return zapSite{Name: name, Host: host, Port: port, SSL: fmt.Sprintf("%t", scheme == "https"), Alerts: []zapAlert{}}
+}
+
+// findingLocation returns the URL, HTTP method, and parameter a finding
+// is about, from the url, endpoint, path, method, and param keys scanners
+// set in its metadata, defaulting to the target and GET.
+func findingLocation(t types.Target, f types.Finding) (uri, method, param string) {
+	uri = targetName(t)
+	if !strings.Contains(uri, "://") {
+		uri = zapSiteFor(t).Name + "/"
+	}
+	switch {
+	case strings.Contains(f.Metadata["url"], "://"):
+		uri = f.Metadata["url"]
+	case strings.Contains(f.Metadata["endpoint"], "://"):
+		uri = f.Metadata["endpoint"]
+	case strings.HasPrefix(f.Metadata["path"], "/"):
+		if base, err := url.Parse(uri); err == nil {
+			if ref, err := url.Parse(f.Metadata["path"]); err == nil {
+				uri = base.ResolveReference(ref).String()
+			}
+		}
+	}
+
+	method = strings.ToUpper(f.Metadata["method"])
+	if method == "" {
+		method = "GET"
+	}
+	return uri, method, f.Metadata["param"]
+}
@@ -0,0 +1,104 @@
+// Package redact masks sensitive material that scans capture as evidence:
+// credentials, tokens, cookies, and IP addresses. Redacted results can be
+// shared outside the team that ran the scan.
+package redact
+
+import (
+	"net"
+	"regexp"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// Mask replaces every value this package hides.
+const Mask = "[REDACTED]"
+
+// secretName matches parameter and cookie names that hold credentials.
+const secretName = `[\w.-]*(?:password|passwd|pwd|secret|token|api[_-]?key|apikey|session|sessid|credential)[\w.-]*`
+
+var (
+	// authHeader matches Authorization headers, keeping their scheme, such
+	// as "Bearer", in the second group.
+	authHeader = regexp.MustCompile(`(?im)^((?:proxy-)?authorization:[ \t]*)(?:([a-z][\w-]*)[ \t]+)?[^\r\n]*`)
+	// secretHeader matches other headers whose whole value is a secret.
+	secretHeader    = regexp.MustCompile(`(?im)^((?:x-)?api-key:|x-(?:auth|access|api|csrf|xsrf)-token:)[^\r\n]*`)
+	cookieHeader    = regexp.MustCompile(`(?im)^(cookie:)([^\r\n]*)`)
+	setCookieHeader = regexp.MustCompile(`(?im)^(set-cookie:[ \t]*[^=\r\n]+=)[^;\r\n]*`)
+	cookiePair      = regexp.MustCompile(`([^=;\s]+=)[^;]*`)
+
+	// formParam and jsonParam match query, form, and JSON parameters named
+	// like credentials.
+	formParam = regexp.MustCompile(`(?i)((?:^|[?&;\s])` + secretName + `=)[^&\s;"'<>]+`)
+	jsonParam = regexp.MustCompile(`(?i)("` + secretName + `"\s*:\s*")(?:[^"\\]|\\.)*"`)
+
+	jwt         = regexp.MustCompile(`\beyJ[\w-]{5,}\.eyJ[\w-]{5,}\.[\w-]*`)
+	urlUserInfo = regexp.MustCompile(`(?i)([a-z][a-z0-9+.-]*://)[^/@\s:]+:[^/@\s]*@`)
+
+	ipv4 = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+	ipv6 = regexp.MustCompile(`(?i)\b[0-9a-f]{0,4}(?::[0-9a-f]{0,4}){2,7}\b`)
+)
+
+// String masks the sensitive values in s, which may be free text or a raw
+// HTTP exchange.
+func String(s string) string {
+	if s == "" {
+		return s
+	}
+	s = authHeader.ReplaceAllStringFunc(s, func(m string) string {
+		sub := authHeader.FindStringSubmatch(m)
+		if sub[2] != "" {
+			return sub[1] + sub[2] + " " + Mask
+		}
+		return sub[1] + Mask
+	})
+	s = secretHeader.ReplaceAllString(s, "${1} "+Mask)
+	s = cookieHeader.ReplaceAllStringFunc(s, func(m string) string {
+		sub := cookieHeader.FindStringSubmatch(m)
+		return sub[1] + cookiePair.ReplaceAllString(sub[2], "${1}"+Mask)
+	})
+	s = setCookieHeader.ReplaceAllString(s, "${1}"+Mask)
+	s = urlUserInfo.ReplaceAllString(s, "${1}"+Mask+"@")
+	s = jwt.ReplaceAllString(s, Mask)
+	s = formParam.ReplaceAllString(s, "${1}"+Mask)
+	s = jsonParam.ReplaceAllString(s, `${1}`+Mask+`"`)
+	s = ipv4.ReplaceAllStringFunc(s, maskIP)
+	s = ipv6.ReplaceAllStringFunc(s, maskIP)
+	return s
+}
+
+// maskIP masks s when it is an IP address. Version numbers and times that
+// look like addresses are left alone.
+func maskIP(s string) string {
+	if net.ParseIP(s) == nil {
+		return s
+	}
+	return Mask
+}
+
+// Result masks the sensitive values in the evidence and artifacts of every
+// finding of result. Screenshots cannot be masked, so they are dropped.
+func Result(result *types.ScanResult) {
+	if result == nil {
+		return
+	}
+	for i := range result.Findings {
+		f := &result.Findings[i]
+		f.Evidence = String(f.Evidence)
+		if len(f.Artifacts) == 0 {
+			continue
+		}
+		artifacts := make([]types.Artifact, 0, len(f.Artifacts))
+		for _, a := range f.Artifacts {
+			a.Request = String(a.Request)
+			a.Response = String(a.Response)
+			a.Screenshot = nil
+			if a.Request != "" || a.Response != "" {
+				artifacts = append(artifacts, a)
+			}
+		}
+		f.Artifacts = artifacts
+		if len(f.Artifacts) == 0 {
+			f.Artifacts = nil
+		}
+	}
+}
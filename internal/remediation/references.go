@@ -0,0 +1,199 @@
+// Package remediation is Hunter's knowledge base of remediation references:
+// the OWASP, MDN, and vendor documentation on each kind of finding, keyed by
+// rule ID.
+package remediation
+
+import (
+	"slices"
+
+	"github.com/buemura/hunter/internal/rules"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// Documentation the knowledge base refers to more than once.
+var (
+	owaspInjection         = ref("OWASP Top 10: A03 Injection", "https://owasp.org/Top10/A03_2021-Injection/")
+	owaspCrypto            = ref("OWASP Top 10: A02 Cryptographic Failures", "https://owasp.org/Top10/A02_2021-Cryptographic_Failures/")
+	owaspIntegrity         = ref("OWASP Top 10: A08 Software and Data Integrity Failures", "https://owasp.org/Top10/A08_2021-Software_and_Data_Integrity_Failures/")
+	owaspComponents        = ref("OWASP Top 10: A06 Vulnerable and Outdated Components", "https://owasp.org/Top10/A06_2021-Vulnerable_and_Outdated_Components/")
+	owaspAccessControl     = ref("OWASP Top 10: A01 Broken Access Control", "https://owasp.org/Top10/A01_2021-Broken_Access_Control/")
+	owaspHeaders           = ref("OWASP HTTP Security Response Headers Cheat Sheet", "https://cheatsheetseries.owasp.org/cheatsheets/HTTP_Headers_Cheat_Sheet.html")
+	owaspTLS               = ref("OWASP Transport Layer Security Cheat Sheet", "https://cheatsheetseries.owasp.org/cheatsheets/Transport_Layer_Security_Cheat_Sheet.html")
+	owaspXSS               = ref("OWASP Cross Site Scripting Prevention Cheat Sheet", "https://cheatsheetseries.owasp.org/cheatsheets/Cross_Site_Scripting_Prevention_Cheat_Sheet.html")
+	owaspSQLi              = ref("OWASP SQL Injection Prevention Cheat Sheet", "https://cheatsheetseries.owasp.org/cheatsheets/SQL_Injection_Prevention_Cheat_Sheet.html")
+	owaspCSRF              = ref("OWASP Cross-Site Request Forgery Prevention Cheat Sheet", "https://cheatsheetseries.owasp.org/cheatsheets/Cross-Site_Request_Forgery_Prevention_Cheat_Sheet.html")
+	owaspRedirects         = ref("OWASP Unvalidated Redirects and Forwards Cheat Sheet", "https://cheatsheetseries.owasp.org/cheatsheets/Unvalidated_Redirects_and_Forwards_Cheat_Sheet.html")
+	owaspDeserialization   = ref("OWASP Deserialization Cheat Sheet", "https://cheatsheetseries.owasp.org/cheatsheets/Deserialization_Cheat_Sheet.html")
+	owaspAuthentication    = ref("OWASP Authentication Cheat Sheet", "https://cheatsheetseries.owasp.org/cheatsheets/Authentication_Cheat_Sheet.html")
+	owaspRESTSecurity      = ref("OWASP REST Security Cheat Sheet", "https://cheatsheetseries.owasp.org/cheatsheets/REST_Security_Cheat_Sheet.html")
+	apiBrokenAuth          = ref("OWASP API Security Top 10: API2 Broken Authentication", "https://owasp.org/API-Security/editions/2023/en/0xa2-broken-authentication/")
+	apiPropertyAuth        = ref("OWASP API Security Top 10: API3 Broken Object Property Level Authorization", "https://owasp.org/API-Security/editions/2023/en/0xa3-broken-object-property-level-authorization/")
+	apiResourceConsumption = ref("OWASP API Security Top 10: API4 Unrestricted Resource Consumption", "https://owasp.org/API-Security/editions/2023/en/0xa4-unrestricted-resource-consumption/")
+	apiMisconfiguration    = ref("OWASP API Security Top 10: API8 Security Misconfiguration", "https://owasp.org/API-Security/editions/2023/en/0xa8-security-misconfiguration/")
+	apiInventory           = ref("OWASP API Security Top 10: API9 Improper Inventory Management", "https://owasp.org/API-Security/editions/2023/en/0xa9-improper-inventory-management/")
+	mdnCORS                = ref("MDN: Cross-Origin Resource Sharing (CORS)", "https://developer.mozilla.org/en-US/docs/Web/HTTP/CORS")
+	mdnMixedContent        = ref("MDN: Mixed content", "https://developer.mozilla.org/en-US/docs/Web/Security/Mixed_content")
+	mdnHSTS                = ref("MDN: Strict-Transport-Security", "https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Strict-Transport-Security")
+	mdnCacheControl        = ref("MDN: Cache-Control", "https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Cache-Control")
+	letsEncrypt            = ref("Let's Encrypt: Getting Started", "https://letsencrypt.org/getting-started/")
+	mozillaSSLConfig       = ref("Mozilla SSL Configuration Generator", "https://ssl-config.mozilla.org/")
+	portswiggerSQLi        = ref("PortSwigger: Blind SQL injection", "https://portswigger.net/web-security/sql-injection/blind")
+	mdnContentTypeOptions  = ref("MDN: X-Content-Type-Options", "https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/X-Content-Type-Options")
+	portswiggerCSRF        = ref("PortSwigger: Cross-site request forgery", "https://portswigger.net/web-security/csrf")
+	portswiggerCORS        = ref("PortSwigger: CORS vulnerabilities", "https://portswigger.net/web-security/cors")
+)
+
+// knowledgeBase maps rule IDs (see the rules package) to references.
+// Informational rules, such as open ports or identified technologies, have
+// no entry.
+var knowledgeBase = map[string][]types.Reference{
+	// Security headers.
+	"HUNTER-HEADERS-001": {
+		mdnHSTS,
+		ref("OWASP HTTP Strict Transport Security Cheat Sheet", "https://cheatsheetseries.owasp.org/cheatsheets/HTTP_Strict_Transport_Security_Cheat_Sheet.html"),
+		ref("HSTS Preload List", "https://hstspreload.org/"),
+	},
+	"HUNTER-HEADERS-002": {
+		ref("MDN: Content-Security-Policy", "https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Content-Security-Policy"),
+		ref("OWASP Content Security Policy Cheat Sheet", "https://cheatsheetseries.owasp.org/cheatsheets/Content_Security_Policy_Cheat_Sheet.html"),
+	},
+	"HUNTER-HEADERS-003": {mdnContentTypeOptions, owaspHeaders},
+	"HUNTER-HEADERS-004": {mdnContentTypeOptions, owaspHeaders},
+	"HUNTER-HEADERS-005": {
+		ref("MDN: X-Frame-Options", "https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/X-Frame-Options"),
+		ref("OWASP Clickjacking Defense Cheat Sheet", "https://cheatsheetseries.owasp.org/cheatsheets/Clickjacking_Defense_Cheat_Sheet.html"),
+	},
+	"HUNTER-HEADERS-006": {
+		ref("MDN: X-XSS-Protection", "https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/X-XSS-Protection"),
+		owaspHeaders,
+	},
+	"HUNTER-HEADERS-007": {
+		ref("MDN: Referrer-Policy", "https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Referrer-Policy"),
+		owaspHeaders,
+	},
+	"HUNTER-HEADERS-008": {
+		ref("MDN: Permissions-Policy", "https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Permissions-Policy"),
+		owaspHeaders,
+	},
+	"HUNTER-HEADERS-009": {
+		mdnCacheControl,
+		ref("OWASP Session Management Cheat Sheet", "https://cheatsheetseries.owasp.org/cheatsheets/Session_Management_Cheat_Sheet.html"),
+	},
+	"HUNTER-HEADERS-010": {mdnCacheControl},
+
+	// TLS.
+	"HUNTER-SSL-001": {
+		ref("RFC 8996: Deprecating TLS 1.0 and TLS 1.1", "https://www.rfc-editor.org/rfc/rfc8996"),
+		mozillaSSLConfig,
+		owaspTLS,
+	},
+	"HUNTER-SSL-003":       {mozillaSSLConfig, owaspTLS, owaspCrypto},
+	"HUNTER-SSL-004":       {owaspTLS, letsEncrypt},
+	"HUNTER-SSL-005":       {owaspTLS, letsEncrypt},
+	"HUNTER-SSL-006":       {owaspTLS, letsEncrypt},
+	"HUNTER-SSL-007":       {owaspTLS, letsEncrypt},
+	"HUNTER-MIXED-001":     {mdnMixedContent, owaspTLS},
+	"HUNTER-MIXED-002":     {mdnMixedContent, owaspTLS},
+	"HUNTER-REDIRECTS-001": {mdnHSTS, owaspTLS},
+	"HUNTER-REDIRECTS-003": {owaspTLS, owaspCrypto},
+	"HUNTER-REDIRECTS-004": {owaspRedirects},
+
+	// Injection.
+	"HUNTER-VULN-001": {
+		owaspXSS,
+		ref("PortSwigger: Reflected XSS", "https://portswigger.net/web-security/cross-site-scripting/reflected"),
+		owaspInjection,
+	},
+	"HUNTER-VULN-002": {
+		ref("OWASP DOM based XSS Prevention Cheat Sheet", "https://cheatsheetseries.owasp.org/cheatsheets/DOM_based_XSS_Prevention_Cheat_Sheet.html"),
+		ref("PortSwigger: DOM-based XSS", "https://portswigger.net/web-security/cross-site-scripting/dom-based"),
+		owaspInjection,
+	},
+	"HUNTER-VULN-003": {owaspSQLi, owaspInjection},
+	"HUNTER-VULN-004": {owaspSQLi, portswiggerSQLi, owaspInjection},
+	"HUNTER-VULN-005": {owaspSQLi, portswiggerSQLi, owaspInjection},
+	"HUNTER-VULN-006": {
+		owaspRedirects,
+		ref("CWE-601: URL Redirection to Untrusted Site", "https://cwe.mitre.org/data/definitions/601.html"),
+	},
+	"HUNTER-VULN-007": {
+		ref("OWASP Prototype Pollution Prevention Cheat Sheet", "https://cheatsheetseries.owasp.org/cheatsheets/Prototype_Pollution_Prevention_Cheat_Sheet.html"),
+		ref("PortSwigger: Server-side prototype pollution", "https://portswigger.net/web-security/prototype-pollution/server-side"),
+	},
+
+	// Forms and CSRF.
+	"HUNTER-CSRF-001": {owaspCSRF, portswiggerCSRF},
+	"HUNTER-CSRF-002": {owaspCSRF, portswiggerCSRF},
+	"HUNTER-FORMS-001": {
+		ref("MDN: How to turn off form autocompletion", "https://developer.mozilla.org/en-US/docs/Web/Security/Practical_implementation_guides/Turning_off_form_autocompletion"),
+	},
+
+	// Deserialization.
+	"HUNTER-DESER-001": {
+		owaspDeserialization,
+		ref("Oracle: Serialization Filtering", "https://docs.oracle.com/en/java/javase/17/core/serialization-filtering1.html"),
+		owaspIntegrity,
+	},
+	"HUNTER-DESER-002": {
+		owaspDeserialization,
+		ref("PHP Manual: unserialize", "https://www.php.net/manual/en/function.unserialize.php"),
+		owaspIntegrity,
+	},
+	"HUNTER-DESER-003": {
+		owaspDeserialization,
+		ref("PortSwigger: Insecure deserialization", "https://portswigger.net/web-security/deserialization"),
+		owaspIntegrity,
+	},
+
+	// Technologies.
+	"HUNTER-TECH-003": {
+		owaspComponents,
+		ref("OWASP Vulnerable Dependency Management Cheat Sheet", "https://cheatsheetseries.owasp.org/cheatsheets/Vulnerable_Dependency_Management_Cheat_Sheet.html"),
+	},
+	"HUNTER-TECH-004": {owaspComponents},
+
+	// APIs.
+	"HUNTER-API-002": {
+		ref("OWASP GraphQL Cheat Sheet", "https://cheatsheetseries.owasp.org/cheatsheets/GraphQL_Cheat_Sheet.html"),
+		ref("PortSwigger: GraphQL API vulnerabilities", "https://portswigger.net/web-security/graphql"),
+		apiMisconfiguration,
+	},
+	"HUNTER-API-003": {apiInventory, apiMisconfiguration},
+	"HUNTER-API-004": {apiBrokenAuth, owaspAccessControl, owaspRESTSecurity},
+	"HUNTER-API-005": {apiBrokenAuth, owaspAccessControl, owaspRESTSecurity},
+	"HUNTER-API-006": {
+		ref("OWASP JSON Web Token Cheat Sheet", "https://cheatsheetseries.owasp.org/cheatsheets/JSON_Web_Token_for_Java_Cheat_Sheet.html"),
+		ref("PortSwigger: JWT attacks", "https://portswigger.net/web-security/jwt"),
+		apiBrokenAuth,
+	},
+	"HUNTER-API-007": {owaspAuthentication, apiBrokenAuth},
+	"HUNTER-API-008": {mdnCORS, portswiggerCORS, apiMisconfiguration},
+	"HUNTER-API-009": {mdnCORS, portswiggerCORS, apiMisconfiguration},
+	"HUNTER-API-010": {mdnCORS, portswiggerCORS, apiMisconfiguration},
+	"HUNTER-API-013": {apiResourceConsumption},
+	"HUNTER-API-014": {apiPropertyAuth, owaspRESTSecurity},
+}
+
+func ref(title, url string) types.Reference {
+	return types.Reference{Title: title, URL: url}
+}
+
+// References returns the documentation on findings of the rule with the
+// given ID, or nil when the knowledge base has none.
+func References(ruleID string) []types.Reference {
+	return slices.Clone(knowledgeBase[ruleID])
+}
+
+// Annotate sets the references of result's findings from the knowledge base
+// by their rule IDs, leaving those a scanner already gave references alone.
+// Findings must have been labeled with rules.Label first.
+func Annotate(result *types.ScanResult) {
+	if result == nil {
+		return
+	}
+	for i, f := range result.Findings {
+		if len(f.References) == 0 {
+			result.Findings[i].References = References(f.Metadata[rules.MetadataKey])
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package remediation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/buemura/hunter/internal/rules"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKnowledgeBase_Valid(t *testing.T) {
+	for id, refs := range knowledgeBase {
+		_, ok := rules.Lookup(id)
+		assert.True(t, ok, "%s is not a rule", id)
+		assert.NotEmpty(t, refs, id)
+		for _, ref := range refs {
+			assert.NotEmpty(t, ref.Title, id)
+			assert.True(t, strings.HasPrefix(ref.URL, "https://"), "%s: %s", id, ref.URL)
+		}
+	}
+}
+
+func TestReferences(t *testing.T) {
+	tests := []struct {
+		ruleID string
+		first  string
+	}{
+		{"HUNTER-HEADERS-001", "MDN: Strict-Transport-Security"},
+		{"HUNTER-HEADERS-004", "MDN: X-Content-Type-Options"},
+		{"HUNTER-SSL-001", "RFC 8996: Deprecating TLS 1.0 and TLS 1.1"},
+		{"HUNTER-SSL-005", "OWASP Transport Layer Security Cheat Sheet"},
+		{"HUNTER-VULN-001", "OWASP Cross Site Scripting Prevention Cheat Sheet"},
+		{"HUNTER-VULN-005", "OWASP SQL Injection Prevention Cheat Sheet"},
+		{"HUNTER-DESER-003", "OWASP Deserialization Cheat Sheet"},
+		{"HUNTER-API-006", "OWASP JSON Web Token Cheat Sheet"},
+		{"HUNTER-API-004", "OWASP API Security Top 10: API2 Broken Authentication"},
+		{"HUNTER-API-009", "MDN: Cross-Origin Resource Sharing (CORS)"},
+	}
+	for _, tt := range tests {
+		refs := References(tt.ruleID)
+		require.NotEmpty(t, refs, tt.ruleID)
+		assert.Equal(t, tt.first, refs[0].Title, tt.ruleID)
+	}
+
+	assert.Nil(t, References("HUNTER-PORT-001"))
+	assert.Nil(t, References("HUNTER-API-011"))
+	assert.Nil(t, References(""))
+}
+
+func TestReferences_ReturnsCopy(t *testing.T) {
+	refs := References("HUNTER-CSRF-001")
+	refs[0].Title = "changed"
+	assert.NotEqual(t, "changed", References("HUNTER-CSRF-001")[0].Title)
+}
+
+func TestAnnotate(t *testing.T) {
+	own := []types.Reference{{Title: "Vendor advisory", URL: "https://example.com/advisory"}}
+	result := &types.ScanResult{
+		ScannerName: "csrf",
+		Findings: []types.Finding{
+			{Title: "Missing CSRF protection", Metadata: map[string]string{rules.MetadataKey: "HUNTER-CSRF-001"}},
+			{Title: "CSRF token not validated", References: own, Metadata: map[string]string{rules.MetadataKey: "HUNTER-CSRF-002"}},
+			{Title: "Unlabelled finding"},
+		},
+	}
+	Annotate(result)
+	assert.Equal(t, "OWASP Cross-Site Request Forgery Prevention Cheat Sheet", result.Findings[0].References[0].Title)
+	assert.Equal(t, own, result.Findings[1].References, "a scanner's own references are kept")
+	assert.Nil(t, result.Findings[2].References)
+
+	Annotate(nil)
+}
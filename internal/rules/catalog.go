@@ -0,0 +1,207 @@
+// Package rules is Hunter's catalog of finding rules: every kind of finding
+// a check reports, under a stable ID such as HUNTER-HEADERS-001. Finding
+// titles carry details such as ports and paths and may be reworded; rule IDs
+// do not change, so severity overrides, SARIF rules, and the remediation
+// knowledge base key off them.
+package rules
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// MetadataKey is the finding metadata key a finding's rule ID is recorded
+// under.
+const MetadataKey = "rule_id"
+
+// Rule is a kind of finding.
+type Rule struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Scanners are the scanners that report the rule's findings; nil means
+	// any scanner.
+	Scanners []string `json:"scanners,omitempty"`
+	// Title matches the titles of the rule's findings. A "*" stands for the
+	// part that varies, such as a port, a path, or a TLS version.
+	Title string `json:"-"`
+}
+
+var idPattern = regexp.MustCompile(`^HUNTER-[A-Z]+-\d{3}$`)
+
+// IsID reports whether s has the form of a rule ID, in any case.
+func IsID(s string) bool {
+	return idPattern.MatchString(strings.ToUpper(s))
+}
+
+// injectionScanners report the vuln scanner's injection findings: the forms
+// scanner runs its checks on the forms it finds.
+var injectionScanners = []string{"vuln", "forms"}
+
+// catalog lists every rule. IDs are never reused: a retired rule's ID stays
+// out of the catalog rather than being given to a new one.
+var catalog = []Rule{
+	{ID: "HUNTER-SCAN-001", Name: "Scanner skipped", Title: "Scanner skipped"},
+	{ID: "HUNTER-SCAN-002", Name: "Target appears to be a tarpit", Scanners: []string{"port", "dirs"}, Title: "Target appears to be a tarpit"},
+
+	{ID: "HUNTER-PORT-001", Name: "Open port", Scanners: []string{"port"}, Title: "Open port: *"},
+	{ID: "HUNTER-PORT-002", Name: "Host appears to be down", Scanners: []string{"port"}, Title: "Host appears to be down"},
+
+	{ID: "HUNTER-HEADERS-001", Name: "Missing Strict-Transport-Security header", Scanners: []string{"headers"}, Title: "Missing Strict-Transport-Security header"},
+	{ID: "HUNTER-HEADERS-002", Name: "Missing Content-Security-Policy header", Scanners: []string{"headers"}, Title: "Missing Content-Security-Policy header"},
+	{ID: "HUNTER-HEADERS-003", Name: "Missing X-Content-Type-Options header", Scanners: []string{"headers"}, Title: "Missing X-Content-Type-Options header"},
+	{ID: "HUNTER-HEADERS-004", Name: "Misconfigured X-Content-Type-Options header", Scanners: []string{"headers"}, Title: "Misconfigured X-Content-Type-Options header"},
+	{ID: "HUNTER-HEADERS-005", Name: "Missing X-Frame-Options header", Scanners: []string{"headers"}, Title: "Missing X-Frame-Options header"},
+	{ID: "HUNTER-HEADERS-006", Name: "Missing X-XSS-Protection header", Scanners: []string{"headers"}, Title: "Missing X-XSS-Protection header"},
+	{ID: "HUNTER-HEADERS-007", Name: "Missing Referrer-Policy header", Scanners: []string{"headers"}, Title: "Missing Referrer-Policy header"},
+	{ID: "HUNTER-HEADERS-008", Name: "Missing Permissions-Policy header", Scanners: []string{"headers"}, Title: "Missing Permissions-Policy header"},
+	{ID: "HUNTER-HEADERS-009", Name: "Sensitive response cacheable", Scanners: []string{"headers"}, Title: "Sensitive response cacheable"},
+	{ID: "HUNTER-HEADERS-010", Name: "HTML document cached for too long", Scanners: []string{"headers"}, Title: "HTML document cached for too long"},
+
+	{ID: "HUNTER-SSL-001", Name: "Deprecated TLS version", Scanners: []string{"ssl"}, Title: "Deprecated TLS version: *"},
+	{ID: "HUNTER-SSL-002", Name: "TLS version", Scanners: []string{"ssl"}, Title: "TLS version: *"},
+	{ID: "HUNTER-SSL-003", Name: "Weak cipher suite", Scanners: []string{"ssl"}, Title: "Weak cipher suite: *"},
+	{ID: "HUNTER-SSL-004", Name: "Certificate expired", Scanners: []string{"ssl"}, Title: "Certificate expired"},
+	{ID: "HUNTER-SSL-005", Name: "Certificate expiring soon", Scanners: []string{"ssl"}, Title: "Certificate expires in * days"},
+	{ID: "HUNTER-SSL-006", Name: "Certificate hostname mismatch", Scanners: []string{"ssl"}, Title: "Certificate hostname mismatch"},
+	{ID: "HUNTER-SSL-007", Name: "Self-signed certificate", Scanners: []string{"ssl"}, Title: "Self-signed certificate"},
+	{ID: "HUNTER-SSL-008", Name: "SSL/TLS configuration looks good", Scanners: []string{"ssl"}, Title: "SSL/TLS configuration looks good"},
+	{ID: "HUNTER-SSL-009", Name: "No logged certificates", Scanners: []string{"ssl"}, Title: "No logged certificates"},
+	{ID: "HUNTER-SSL-010", Name: "Logged certificates", Scanners: []string{"ssl"}, Title: "Logged certificates: *"},
+	{ID: "HUNTER-SSL-011", Name: "Latest logged certificate expired", Scanners: []string{"ssl"}, Title: "Latest logged certificate expired"},
+	{ID: "HUNTER-SSL-012", Name: "Latest logged certificate expiring soon", Scanners: []string{"ssl"}, Title: "Latest logged certificate expires in * days"},
+	{ID: "HUNTER-SSL-013", Name: "Wildcard certificate", Scanners: []string{"ssl"}, Title: "Wildcard certificate"},
+
+	{ID: "HUNTER-DIRS-001", Name: "Found path", Scanners: []string{"dirs"}, Title: "Found path: *"},
+	{ID: "HUNTER-DIRS-002", Name: "Forbidden path", Scanners: []string{"dirs"}, Title: "Forbidden path: *"},
+	{ID: "HUNTER-DIRS-003", Name: "Redirect path", Scanners: []string{"dirs"}, Title: "Redirect path: *"},
+	{ID: "HUNTER-DIRS-004", Name: "Screenshot of the root page", Scanners: []string{"dirs"}, Title: "Screenshot of the target's root page"},
+
+	{ID: "HUNTER-VULN-001", Name: "Reflected XSS", Scanners: injectionScanners, Title: "Potential reflected XSS"},
+	{ID: "HUNTER-VULN-002", Name: "DOM-based XSS", Scanners: injectionScanners, Title: "Potential DOM-based XSS"},
+	{ID: "HUNTER-VULN-003", Name: "SQL injection", Scanners: injectionScanners, Title: "Potential SQL injection"},
+	{ID: "HUNTER-VULN-004", Name: "Blind SQL injection (boolean-based)", Scanners: injectionScanners, Title: "Blind SQL injection (boolean-based)"},
+	{ID: "HUNTER-VULN-005", Name: "Blind SQL injection (time-based)", Scanners: injectionScanners, Title: "Blind SQL injection (time-based)"},
+	{ID: "HUNTER-VULN-006", Name: "Open redirect", Scanners: injectionScanners, Title: "Potential open redirect"},
+	{ID: "HUNTER-VULN-007", Name: "Server-side prototype pollution", Scanners: injectionScanners, Title: "Server-side prototype pollution"},
+
+	{ID: "HUNTER-FORMS-001", Name: "Password field allows autocomplete", Scanners: []string{"forms"}, Title: "Password field allows autocomplete"},
+
+	{ID: "HUNTER-CSRF-001", Name: "Missing CSRF protection", Scanners: []string{"csrf"}, Title: "Missing CSRF protection"},
+	{ID: "HUNTER-CSRF-002", Name: "CSRF token not validated", Scanners: []string{"csrf"}, Title: "CSRF token not validated"},
+
+	{ID: "HUNTER-TECH-001", Name: "Technology identified", Scanners: []string{"tech"}, Title: "Technology identified: *"},
+	{ID: "HUNTER-TECH-002", Name: "Unrecognized favicon", Scanners: []string{"tech"}, Title: "Unrecognized favicon *"},
+	{ID: "HUNTER-TECH-003", Name: "Vulnerable JavaScript library", Scanners: []string{"tech"}, Title: "Vulnerable JavaScript library: *"},
+	{ID: "HUNTER-TECH-004", Name: "Vulnerable server software", Scanners: []string{"tech"}, Title: "Vulnerable server software: *"},
+
+	{ID: "HUNTER-REDIRECTS-001", Name: "HTTP not redirected to HTTPS", Scanners: []string{"redirects"}, Title: "HTTP not redirected to HTTPS"},
+	{ID: "HUNTER-REDIRECTS-002", Name: "Redirect loop", Scanners: []string{"redirects"}, Title: "Redirect loop"},
+	{ID: "HUNTER-REDIRECTS-003", Name: "Redirect downgrades HTTPS to HTTP", Scanners: []string{"redirects"}, Title: "Redirect downgrades HTTPS to HTTP"},
+	{ID: "HUNTER-REDIRECTS-004", Name: "Meta refresh to a foreign domain", Scanners: []string{"redirects"}, Title: "Meta refresh to a foreign domain"},
+
+	{ID: "HUNTER-MIXED-001", Name: "Active mixed content", Scanners: []string{"mixed-content"}, Title: "Active mixed content"},
+	{ID: "HUNTER-MIXED-002", Name: "Passive mixed content", Scanners: []string{"mixed-content"}, Title: "Passive mixed content"},
+
+	{ID: "HUNTER-DESER-001", Name: "Java serialized object", Scanners: []string{"deserialization"}, Title: "Java serialized object"},
+	{ID: "HUNTER-DESER-002", Name: "PHP serialized data", Scanners: []string{"deserialization"}, Title: "PHP serialized data"},
+	{ID: "HUNTER-DESER-003", Name: ".NET ViewState without MAC", Scanners: []string{"deserialization"}, Title: ".NET ViewState without MAC"},
+
+	{ID: "HUNTER-SUBDOMAIN-001", Name: "Subdomain discovered", Scanners: []string{"subdomain"}, Title: "Subdomain discovered: *"},
+	{ID: "HUNTER-SUBDOMAIN-002", Name: "No subdomains found", Scanners: []string{"subdomain"}, Title: "No subdomains found"},
+
+	{ID: "HUNTER-API-001", Name: "API endpoint discovered", Scanners: []string{"api-discover"}, Title: "API endpoint discovered: *"},
+	{ID: "HUNTER-API-002", Name: "GraphQL introspection enabled", Scanners: []string{"api-discover"}, Title: "GraphQL introspection enabled: *"},
+	{ID: "HUNTER-API-003", Name: "API console exposed", Scanners: []string{"api-discover"}, Title: "API console exposed: *"},
+	{ID: "HUNTER-API-004", Name: "Endpoint accessible without authentication", Scanners: []string{"api-auth"}, Title: "Endpoint accessible without authentication: *"},
+	{ID: "HUNTER-API-005", Name: "Authentication bypass", Scanners: []string{"api-auth"}, Title: "Authentication bypass via *"},
+	{ID: "HUNTER-API-006", Name: "JWT accepted with a forged token", Scanners: []string{"api-auth"}, Title: "JWT accepted with *"},
+	{ID: "HUNTER-API-007", Name: "Default credentials accepted", Scanners: []string{"api-auth"}, Title: "Default credentials accepted: *"},
+	{ID: "HUNTER-API-008", Name: "CORS credentials with permissive origin", Scanners: []string{"api-cors"}, Title: "CORS credentials with permissive origin *"},
+	{ID: "HUNTER-API-009", Name: "CORS origin reflected", Scanners: []string{"api-cors"}, Title: "CORS origin reflected *"},
+	{ID: "HUNTER-API-010", Name: "CORS allows null origin", Scanners: []string{"api-cors"}, Title: "CORS allows null origin *"},
+	{ID: "HUNTER-API-011", Name: "No CORS misconfigurations detected", Scanners: []string{"api-cors"}, Title: "No CORS misconfigurations detected"},
+	{ID: "HUNTER-API-012", Name: "Rate limit headers detected", Scanners: []string{"api-ratelimit"}, Title: "Rate limit headers detected"},
+	{ID: "HUNTER-API-013", Name: "No rate limiting detected", Scanners: []string{"api-ratelimit"}, Title: "No rate limiting detected"},
+	{ID: "HUNTER-API-014", Name: "Sensitive data exposed in API response", Scanners: []string{"api-dataexposure"}, Title: "* exposed in API response: *"},
+}
+
+// All returns every rule, ordered by ID.
+func All() []Rule {
+	all := append([]Rule(nil), catalog...)
+	sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+	return all
+}
+
+// Lookup returns the rule with the given ID, in any case.
+func Lookup(id string) (Rule, bool) {
+	id = strings.ToUpper(id)
+	for _, r := range catalog {
+		if r.ID == id {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+// Match returns the rule a finding with the given title, made by the named
+// scanner, falls under.
+func Match(scanner, title string) (Rule, bool) {
+	for _, r := range catalog {
+		if r.reportedBy(scanner) && matchTitle(r.Title, title) {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+// Label records the rule ID of each of result's findings that falls under a
+// rule and does not have one yet.
+func Label(result *types.ScanResult) {
+	if result == nil {
+		return
+	}
+	for i := range result.Findings {
+		f := &result.Findings[i]
+		if _, ok := f.Metadata[MetadataKey]; ok {
+			continue
+		}
+		r, ok := Match(result.ScannerName, f.Title)
+		if !ok {
+			continue
+		}
+		if f.Metadata == nil {
+			f.Metadata = map[string]string{}
+		}
+		f.Metadata[MetadataKey] = r.ID
+	}
+}
+
+func (r Rule) reportedBy(scanner string) bool {
+	return r.Scanners == nil || slices.Contains(r.Scanners, scanner)
+}
+
+// matchTitle reports whether title matches pattern, where each "*" matches
+// any run of characters, "/" included.
+func matchTitle(pattern, title string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == title
+	}
+	if !strings.HasPrefix(title, parts[0]) {
+		return false
+	}
+	title = title[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(title, part)
+		if i < 0 {
+			return false
+		}
+		title = title[i+len(part):]
+	}
+	return len(title) >= len(last) && strings.HasSuffix(title, last)
+}
@@ -0,0 +1,88 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalog_Valid(t *testing.T) {
+	seen := map[string]bool{}
+	for _, r := range catalog {
+		assert.True(t, IsID(r.ID), r.ID)
+		assert.False(t, seen[r.ID], "duplicate rule ID %s", r.ID)
+		seen[r.ID] = true
+		assert.NotEmpty(t, r.Name, r.ID)
+		assert.NotEmpty(t, r.Title, r.ID)
+	}
+}
+
+func TestAll_SortedCopy(t *testing.T) {
+	all := All()
+	require.Len(t, all, len(catalog))
+	for i := 1; i < len(all); i++ {
+		assert.Less(t, all[i-1].ID, all[i].ID)
+	}
+	all[0].ID = "changed"
+	assert.NotEqual(t, "changed", All()[0].ID)
+}
+
+func TestLookup(t *testing.T) {
+	r, ok := Lookup("hunter-headers-001")
+	require.True(t, ok)
+	assert.Equal(t, "HUNTER-HEADERS-001", r.ID)
+
+	_, ok = Lookup("HUNTER-HEADERS-999")
+	assert.False(t, ok)
+	_, ok = Lookup("")
+	assert.False(t, ok)
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		scanner, title string
+		want           string
+	}{
+		{"port", "Open port: 80/HTTP", "HUNTER-PORT-001"},
+		{"headers", "Missing Strict-Transport-Security header", "HUNTER-HEADERS-001"},
+		{"ssl", "Deprecated TLS version: TLS 1.0", "HUNTER-SSL-001"},
+		{"ssl", "TLS version: TLS 1.3", "HUNTER-SSL-002"},
+		{"ssl", "Certificate expires in 12 days", "HUNTER-SSL-005"},
+		{"dirs", "Found path: /admin (200 OK)", "HUNTER-DIRS-001"},
+		{"vuln", "Potential reflected XSS", "HUNTER-VULN-001"},
+		{"forms", "Potential reflected XSS", "HUNTER-VULN-001"},
+		{"api-auth", "JWT accepted with alg none: GET /users", "HUNTER-API-006"},
+		{"api-dataexposure", "PII exposed in API response: /users", "HUNTER-API-014"},
+		{"headers", "Scanner skipped", "HUNTER-SCAN-001"},
+		{"dirs", "Target appears to be a tarpit", "HUNTER-SCAN-002"},
+	}
+	for _, tt := range tests {
+		r, ok := Match(tt.scanner, tt.title)
+		require.True(t, ok, tt.title)
+		assert.Equal(t, tt.want, r.ID, tt.title)
+	}
+
+	_, ok := Match("headers", "Open port: 80/HTTP")
+	assert.False(t, ok, "rules only match their own scanners")
+	_, ok = Match("headers", "Something new")
+	assert.False(t, ok)
+}
+
+func TestLabel(t *testing.T) {
+	result := &types.ScanResult{
+		ScannerName: "port",
+		Findings: []types.Finding{
+			{Title: "Open port: 22/SSH"},
+			{Title: "Open port: 80/HTTP", Metadata: map[string]string{MetadataKey: "HUNTER-CUSTOM-001"}},
+			{Title: "Unknown"},
+		},
+	}
+	Label(result)
+	assert.Equal(t, "HUNTER-PORT-001", result.Findings[0].Metadata[MetadataKey])
+	assert.Equal(t, "HUNTER-CUSTOM-001", result.Findings[1].Metadata[MetadataKey], "recorded IDs are kept")
+	assert.Nil(t, result.Findings[2].Metadata)
+
+	Label(nil)
+}
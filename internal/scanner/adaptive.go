@@ -0,0 +1,149 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// AdaptiveLimiter bounds how many units of work (ports, paths) run at once,
+// adjusting the bound to how the target copes: it starts low and adds one
+// slot after every window of successful units that were not markedly slower
+// than the fastest seen, and halves the bound, at most once per window,
+// when a unit fails (timeouts, resets, 5xx responses).
+type AdaptiveLimiter struct {
+	min, max int
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	limit    int
+	inflight int
+
+	window       int           // units completed since the limit last changed
+	windowMax    time.Duration // slowest unit in the window
+	fastest      time.Duration
+	sinceBackoff int // units completed since the last backoff, or -1 for none yet
+
+	peak     int
+	backoffs int
+	done     int
+	failed   int
+	started  time.Time
+}
+
+// NewAdaptiveLimiter returns a limiter allowing between lo and hi units at
+// once, starting at a quarter of hi.
+func NewAdaptiveLimiter(lo, hi int) *AdaptiveLimiter {
+	hi = max(hi, 1)
+	lo = clampInt(lo, 1, hi)
+	l := &AdaptiveLimiter{min: lo, max: hi, limit: clampInt(hi/4, lo, hi), sinceBackoff: -1, started: time.Now()}
+	l.peak = l.limit
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+func clampInt(v, lo, hi int) int {
+	return max(lo, min(v, hi))
+}
+
+// Acquire waits for a free slot. It returns ctx's error if ctx ends first.
+func (l *AdaptiveLimiter) Acquire(ctx context.Context) error {
+	stop := context.AfterFunc(ctx, func() {
+		l.mu.Lock()
+		l.cond.Broadcast()
+		l.mu.Unlock()
+	})
+	defer stop()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for l.inflight >= l.limit {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		l.cond.Wait()
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	l.inflight++
+	return nil
+}
+
+// Release frees a slot taken by Acquire, reporting how long the unit took
+// and whether it failed in a way that suggests the target is struggling.
+func (l *AdaptiveLimiter) Release(latency time.Duration, failed bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inflight--
+	l.done++
+	l.window++
+	if l.sinceBackoff >= 0 {
+		l.sinceBackoff++
+	}
+
+	switch {
+	case failed:
+		l.failed++
+		// Units already in flight when the limit was halved fail too; only
+		// back off again once they have drained.
+		if l.sinceBackoff < 0 || l.sinceBackoff >= l.limit {
+			if halved := max(l.limit/2, l.min); halved < l.limit {
+				l.limit = halved
+				l.backoffs++
+			}
+			l.sinceBackoff = 0
+			l.resetWindow()
+		}
+	default:
+		if l.fastest == 0 || latency < l.fastest {
+			l.fastest = latency
+		}
+		l.windowMax = max(l.windowMax, latency)
+		if l.window >= l.limit {
+			if l.windowMax <= 4*l.fastest+10*time.Millisecond && l.limit < l.max {
+				l.limit++
+				l.peak = max(l.peak, l.limit)
+			}
+			l.resetWindow()
+		}
+	}
+	l.cond.Broadcast()
+}
+
+func (l *AdaptiveLimiter) resetWindow() {
+	l.window = 0
+	l.windowMax = 0
+}
+
+// Limit returns the current bound.
+func (l *AdaptiveLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// Metadata describes how the limiter behaved, for ScanResult metadata:
+// the final and peak concurrency, how often it backed off, the share of
+// failed units, and the effective rate in units per second.
+func (l *AdaptiveLimiter) Metadata() map[string]string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	rate := 0.0
+	if elapsed := time.Since(l.started).Seconds(); elapsed > 0 {
+		rate = float64(l.done) / elapsed
+	}
+	errorRate := 0.0
+	if l.done > 0 {
+		errorRate = float64(l.failed) / float64(l.done)
+	}
+	return map[string]string{
+		"concurrency":      strconv.Itoa(l.limit),
+		"peak_concurrency": strconv.Itoa(l.peak),
+		"backoffs":         strconv.Itoa(l.backoffs),
+		"error_rate":       fmt.Sprintf("%.2f", errorRate),
+		"effective_rate":   fmt.Sprintf("%.1f/s", rate),
+	}
+}
@@ -0,0 +1,80 @@
+package scanner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// complete runs n units through l one after another.
+func complete(t *testing.T, l *AdaptiveLimiter, n int, latency time.Duration, failed bool) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		require.NoError(t, l.Acquire(context.Background()))
+		l.Release(latency, failed)
+	}
+}
+
+func TestAdaptiveLimiter_RampsUp(t *testing.T) {
+	l := NewAdaptiveLimiter(1, 20)
+	assert.Equal(t, 5, l.Limit())
+
+	complete(t, l, 5, time.Millisecond, false)
+	assert.Equal(t, 6, l.Limit())
+
+	complete(t, l, 500, time.Millisecond, false)
+	assert.Equal(t, 20, l.Limit(), "never above the maximum")
+	assert.Equal(t, "20", l.Metadata()["peak_concurrency"])
+}
+
+func TestAdaptiveLimiter_HoldsWhenLatencyGrows(t *testing.T) {
+	l := NewAdaptiveLimiter(1, 20)
+	complete(t, l, 4, time.Millisecond, false)
+	complete(t, l, 1, time.Second, false)
+	assert.Equal(t, 5, l.Limit())
+}
+
+func TestAdaptiveLimiter_BacksOff(t *testing.T) {
+	l := NewAdaptiveLimiter(2, 40)
+	assert.Equal(t, 10, l.Limit())
+
+	complete(t, l, 1, time.Millisecond, true)
+	assert.Equal(t, 5, l.Limit())
+
+	// Failures of units already in flight do not halve it again.
+	complete(t, l, 4, time.Millisecond, true)
+	assert.Equal(t, 5, l.Limit())
+
+	complete(t, l, 100, time.Millisecond, true)
+	assert.Equal(t, 2, l.Limit(), "never below the minimum")
+
+	md := l.Metadata()
+	assert.Equal(t, "2", md["concurrency"])
+	assert.Equal(t, "10", md["peak_concurrency"])
+	assert.Equal(t, "2", md["backoffs"])
+	assert.Equal(t, "1.00", md["error_rate"])
+	assert.Contains(t, md["effective_rate"], "/s")
+}
+
+func TestAdaptiveLimiter_AcquireBlocksAtLimit(t *testing.T) {
+	l := NewAdaptiveLimiter(1, 4)
+	require.Equal(t, 1, l.Limit())
+	require.NoError(t, l.Acquire(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, l.Acquire(ctx), context.DeadlineExceeded)
+
+	acquired := make(chan error)
+	go func() { acquired <- l.Acquire(context.Background()) }()
+	l.Release(time.Millisecond, false)
+	select {
+	case err := <-acquired:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Acquire did not return after Release")
+	}
+}
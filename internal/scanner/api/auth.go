@@ -3,10 +3,13 @@ package api
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -14,13 +17,43 @@ import (
 	"github.com/buemura/hunter/pkg/types"
 )
 
-// defaultCredentials is the list of common default credential pairs to test.
-var defaultCredentials = []struct {
+// defaultCredential is a username and password pair shipped as a default.
+type defaultCredential struct {
 	Username string
 	Password string
-}{
+}
+
+// defaultCredentials is the list of common default credential pairs to
+// test, most common first, unless the default-creds data set is installed.
+// The "api-auth.default_credentials" intensity budget decides how many are
+// tried.
+var defaultCredentials = []defaultCredential{
 	{"admin", "admin"},
 	{"admin", "password"},
+	{"admin", "changeme"},
+	{"root", "root"},
+	{"test", "test"},
+	{"user", "user"},
+}
+
+// loadDefaultCredentials returns the credential pairs to try: those of the
+// JSON file named by the "default_credentials" argument, such as the
+// installed default-creds data set, a list of objects with "username" and
+// "password", or else defaultCredentials.
+func loadDefaultCredentials(opts scanner.Options) ([]defaultCredential, error) {
+	path := opts.StringArg("default_credentials")
+	if path == "" {
+		return defaultCredentials, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading default credentials: %w", err)
+	}
+	var creds []defaultCredential
+	if err := json.Unmarshal(raw, &creds); err != nil {
+		return nil, fmt.Errorf("parsing default credentials %s: %w", path, err)
+	}
+	return creds, nil
 }
 
 // loginPaths are common login/authentication endpoints.
@@ -34,18 +67,42 @@ var loginPaths = []string{
 	"/api/signin",
 }
 
-// bypassPayloads are Authorization header values used to test auth bypass.
-var bypassPayloads = []struct {
+// bypassPayload is an Authorization header value used to test auth bypass.
+type bypassPayload struct {
 	Name  string
 	Value string
-}{
+}
+
+// bypassPayloads are the Authorization header values tried, most likely to
+// work first. The "api-auth.bypass_payloads" intensity budget decides how
+// many are tried.
+var bypassPayloads = []bypassPayload{
 	{"empty header", ""},
 	{"Bearer null", "Bearer null"},
 	{"Bearer undefined", "Bearer undefined"},
 	{"Bearer empty", "Bearer "},
 	{"Basic empty", "Basic " + base64.StdEncoding.EncodeToString([]byte(":"))},
+	{"unsigned JWT", "Bearer eyJhbGciOiJub25lIiwidHlwIjoiSldUIn0.eyJzdWIiOiJhZG1pbiJ9."},
+	{"Basic admin without password", "Basic " + base64.StdEncoding.EncodeToString([]byte("admin:"))},
+	{"Token null", "Token null"},
 }
 
+// writeMethods are probed on every endpoint besides its own method, since
+// many APIs protect reads but leave writes unauthenticated. Writes could
+// change data, so they are only sent at aggressive intensity, as the
+// "api-auth.write_methods" budget decides, or when the write_methods
+// argument asks for them. DELETE is never sent, nor are imported DELETE
+// requests replayed: an unprotected one would delete real data.
+var writeMethods = []string{
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+}
+
+// writeProbeBody is the body sent with write method probes: an empty JSON
+// object, which carries no data to create or change.
+const writeProbeBody = `{}`
+
 // AuthScanner tests API endpoints for authentication weaknesses.
 type AuthScanner struct{}
 
@@ -74,61 +131,155 @@ func (s *AuthScanner) Run(ctx context.Context, target types.Target, opts scanner
 		timeout = 5 * time.Second
 	}
 
+	// Unauthenticated access is what this scanner probes for, so it ignores
+	// opts.Authenticate and uses the plain transport.
 	client := &http.Client{
-		Timeout: timeout,
+		Timeout:   timeout,
+		Transport: opts.Transport,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
 		},
 	}
 
 	// Determine which endpoints to test.
-	endpoints := endpointsFromOpts(baseURL, opts)
+	endpoints, err := endpointsFromOpts(baseURL, opts)
+	if err != nil {
+		return nil, err
+	}
+	creds, err := loadDefaultCredentials(opts)
+	if err != nil {
+		return nil, err
+	}
+	endpoints = slices.DeleteFunc(endpoints, func(ep types.Endpoint) bool {
+		if ep.Method != http.MethodDelete {
+			return false
+		}
+		opts.Logf(s.Name(), scanner.LogInfo, "skipping DELETE %s: deletes are never sent", ep.URL)
+		return true
+	})
+
+	// Each endpoint is tested with its own method and, when asked for, the
+	// write methods.
+	budget := opts.Budget("api-auth.write_methods")
+	if opts.BoolArg("write_methods") {
+		budget = scanner.Unlimited
+	}
+	methods := scanner.Limit(writeMethods, budget)
+	if len(methods) == 0 {
+		opts.Logf(s.Name(), scanner.LogInfo, "write methods: skipped at %s intensity; set write_methods to send them", opts.Intensity)
+	}
+	var probes []types.Endpoint
+	for _, ep := range endpoints {
+		probes = append(probes, methodProbes(ep, methods)...)
+	}
+
+	// A site serving a page for every path would have every endpoint
+	// succeed, so responses with that page do not count.
+	soft404 := scanner.DetectSoftNotFound(ctx, client, baseURL)
 
 	// Phase 1: Test endpoints without credentials (missing auth check).
-	for _, ep := range endpoints {
-		if finding := testNoAuth(ctx, client, ep); finding != nil {
+	for _, ep := range probes {
+		if finding := testNoAuth(ctx, client, ep, soft404); finding != nil {
 			result.Findings = append(result.Findings, *finding)
 		}
 	}
 
 	// Phase 2: Test authentication bypass techniques on endpoints.
-	for _, ep := range endpoints {
-		findings := testAuthBypass(ctx, client, ep)
+	payloads := scanner.Limit(bypassPayloads, opts.Budget("api-auth.bypass_payloads"))
+	techniques := scanner.Limit(pathBypasses, opts.Budget("api-auth.path_bypasses"))
+	for _, ep := range probes {
+		findings := testAuthBypass(ctx, client, ep, payloads, techniques, soft404)
 		result.Findings = append(result.Findings, findings...)
 	}
 
-	// Phase 3: Test default credentials on login endpoints.
-	loginEndpoints := discoverLoginEndpoints(ctx, client, baseURL)
-	for _, ep := range loginEndpoints {
-		findings := testDefaultCredentials(ctx, client, ep)
+	// Phase 3: Test whether endpoints accept forged JWTs: unsigned, alg
+	// none, or signature-stripped versions of the tokens the scan has.
+	tokens := capturedTokens(baseURL, endpoints, opts)
+	variants := opts.Budget("api-auth.jwt_variants")
+	for _, ep := range endpoints {
+		findings := testJWTBypass(ctx, client, ep, tokens, variants)
 		result.Findings = append(result.Findings, findings...)
 	}
 
+	// Phase 4: Test default credentials on login endpoints. Failed logins
+	// can lock accounts, so safe intensity skips them.
+	creds = scanner.Limit(creds, opts.Budget("api-auth.default_credentials"))
+	if len(creds) == 0 {
+		opts.Logf(s.Name(), scanner.LogInfo, "default credentials: skipped at %s intensity", opts.Intensity)
+	} else {
+		loginEndpoints := discoverLoginEndpoints(ctx, client, baseURL)
+		for _, ep := range loginEndpoints {
+			findings := testDefaultCredentials(ctx, client, ep, creds)
+			result.Findings = append(result.Findings, findings...)
+		}
+	}
+
 	result.CompletedAt = time.Now()
 	return result, nil
 }
 
-// endpointsFromOpts returns the list of endpoint URLs to test.
-// If the target has a URL path (not just root), it uses that single endpoint.
-// Otherwise it uses commonPaths from the discover module.
-func endpointsFromOpts(baseURL string, opts scanner.Options) []string {
+// endpointsFromOpts returns the endpoints to test: those given in the
+// "endpoints" arg, if any. Otherwise, if the target has a URL path (not just
+// root), it uses that single endpoint, or else the imported endpoints on the
+// target's host, if any, or commonPaths from the discover module.
+func endpointsFromOpts(baseURL string, opts scanner.Options) ([]types.Endpoint, error) {
+	if custom, err := customEndpoints(baseURL, opts); err != nil || len(custom) > 0 {
+		return custom, err
+	}
 	parsed, err := url.Parse(baseURL)
 	if err == nil && parsed.Path != "" && parsed.Path != "/" {
-		return []string{baseURL}
+		return []types.Endpoint{{Method: http.MethodGet, URL: baseURL}}, nil
+	}
+	if imported := opts.EndpointsOn(baseURL); len(imported) > 0 {
+		return imported, nil
 	}
 
 	base := strings.TrimRight(baseURL, "/")
-	endpoints := make([]string, 0, len(commonPaths))
+	endpoints := make([]types.Endpoint, 0, len(commonPaths))
 	for _, p := range commonPaths {
-		endpoints = append(endpoints, base+p)
+		endpoints = append(endpoints, types.Endpoint{Method: http.MethodGet, URL: base + p})
 	}
-	return endpoints
+	return endpoints, nil
 }
 
-// testNoAuth sends a GET request without credentials and checks if the endpoint
-// returns 200 OK instead of 401/403 (indicating missing authentication).
-func testNoAuth(ctx context.Context, client *http.Client, endpoint string) *types.Finding {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+// methodProbes returns ep as given followed by a copy of it for each of
+// methods other than its own, with an empty JSON body.
+func methodProbes(ep types.Endpoint, methods []string) []types.Endpoint {
+	if ep.Method == "" {
+		ep.Method = http.MethodGet
+	}
+	probes := []types.Endpoint{ep}
+	for _, method := range methods {
+		if method == ep.Method {
+			continue
+		}
+		headers := make(map[string]string, len(ep.Headers)+1)
+		for name, value := range ep.Headers {
+			headers[name] = value
+		}
+		headers["Content-Type"] = "application/json"
+		probes = append(probes, types.Endpoint{Method: method, URL: ep.URL, Headers: headers, Body: writeProbeBody})
+	}
+	return probes
+}
+
+// accepted reports whether a response with status means the request
+// succeeded: 200 OK, or for writes any 2xx, such as 201 Created or 204 No
+// Content.
+func accepted(method string, status int) bool {
+	if method == http.MethodGet || method == http.MethodHead {
+		return status == http.StatusOK
+	}
+	return status >= 200 && status < 300
+}
+
+// testNoAuth sends the endpoint's request without credentials and checks if
+// it succeeds instead of returning 401/403 (indicating missing
+// authentication), with a page other than the one soft404 says the site
+// serves for any path.
+func testNoAuth(ctx context.Context, client *http.Client, ep types.Endpoint, soft404 *scanner.SoftNotFound) *types.Finding {
+	endpoint := ep.URL
+	req, err := newEndpointRequest(ctx, ep)
 	if err != nil {
 		return nil
 	}
@@ -138,23 +289,24 @@ func testNoAuth(ctx context.Context, client *http.Client, endpoint string) *type
 		return nil
 	}
 	defer resp.Body.Close()
-	io.Copy(io.Discard, resp.Body)
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
 
 	// Skip non-existent endpoints.
-	if resp.StatusCode == http.StatusNotFound {
+	if resp.StatusCode == http.StatusNotFound || soft404.Matches(resp.StatusCode, string(body), endpointPath(endpoint)) {
 		return nil
 	}
 
-	// An endpoint returning 200 without any credentials is a concern.
-	if resp.StatusCode == http.StatusOK {
+	// An endpoint succeeding without any credentials is a concern.
+	if accepted(req.Method, resp.StatusCode) {
 		return &types.Finding{
-			Title:       fmt.Sprintf("Endpoint accessible without authentication: %s", endpoint),
-			Description: "The endpoint returned HTTP 200 without any credentials, which may indicate missing authentication.",
+			Title:       fmt.Sprintf("Endpoint accessible without authentication: %s %s", req.Method, endpoint),
+			Description: fmt.Sprintf("The endpoint returned HTTP %d to a %s request without any credentials, which may indicate missing authentication.", resp.StatusCode, req.Method),
 			Severity:    types.SeverityHigh,
-			Evidence:    fmt.Sprintf("GET %s → %d (no credentials)", endpoint, resp.StatusCode),
-			Remediation: "Ensure all sensitive API endpoints require proper authentication before granting access.",
+			Evidence:    fmt.Sprintf("%s %s → %d (no credentials)", req.Method, endpoint, resp.StatusCode),
+			Remediation: "Ensure all sensitive API endpoints require proper authentication before granting access, for every method they accept.",
 			Metadata: map[string]string{
 				"endpoint": endpoint,
+				"method":   req.Method,
 				"status":   fmt.Sprintf("%d", resp.StatusCode),
 				"check":    "no-auth",
 			},
@@ -164,12 +316,15 @@ func testNoAuth(ctx context.Context, client *http.Client, endpoint string) *type
 	return nil
 }
 
-// testAuthBypass attempts various authentication bypass techniques on the endpoint.
-func testAuthBypass(ctx context.Context, client *http.Client, endpoint string) []types.Finding {
+// testAuthBypass attempts the given Authorization header payloads and path
+// manipulation techniques on the endpoint. Successes with the page soft404
+// says the site serves for any path do not count.
+func testAuthBypass(ctx context.Context, client *http.Client, ep types.Endpoint, payloads []bypassPayload, techniques []pathBypass, soft404 *scanner.SoftNotFound) []types.Finding {
 	var findings []types.Finding
+	endpoint := ep.URL
 
 	// First check if the endpoint actually requires auth (expects 401/403).
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	req, err := newEndpointRequest(ctx, ep)
 	if err != nil {
 		return nil
 	}
@@ -186,8 +341,8 @@ func testAuthBypass(ctx context.Context, client *http.Client, endpoint string) [
 		return nil
 	}
 
-	for _, payload := range bypassPayloads {
-		bypassReq, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	for _, payload := range payloads {
+		bypassReq, err := newEndpointRequest(ctx, ep)
 		if err != nil {
 			continue
 		}
@@ -197,18 +352,19 @@ func testAuthBypass(ctx context.Context, client *http.Client, endpoint string) [
 		if err != nil {
 			continue
 		}
-		io.Copy(io.Discard, bypassResp.Body)
+		body, _ := io.ReadAll(io.LimitReader(bypassResp.Body, maxResponseSize))
 		bypassResp.Body.Close()
 
-		if bypassResp.StatusCode == http.StatusOK {
+		if accepted(bypassReq.Method, bypassResp.StatusCode) && !soft404.Matches(bypassResp.StatusCode, string(body), endpointPath(endpoint)) {
 			findings = append(findings, types.Finding{
-				Title:       fmt.Sprintf("Authentication bypass via %s: %s", payload.Name, endpoint),
-				Description: fmt.Sprintf("The endpoint returned HTTP 200 when using Authorization header value %q, bypassing authentication.", payload.Name),
+				Title:       fmt.Sprintf("Authentication bypass via %s: %s %s", payload.Name, bypassReq.Method, endpoint),
+				Description: fmt.Sprintf("The endpoint returned HTTP %d to a %s request using Authorization header value %q, bypassing authentication.", bypassResp.StatusCode, bypassReq.Method, payload.Name),
 				Severity:    types.SeverityHigh,
-				Evidence:    fmt.Sprintf("GET %s with Authorization: %q → %d", endpoint, payload.Value, bypassResp.StatusCode),
+				Evidence:    fmt.Sprintf("%s %s with Authorization: %q → %d", bypassReq.Method, endpoint, payload.Value, bypassResp.StatusCode),
 				Remediation: "Validate authentication tokens server-side. Reject null, empty, or malformed tokens.",
 				Metadata: map[string]string{
 					"endpoint":       endpoint,
+					"method":         bypassReq.Method,
 					"bypass_method":  payload.Name,
 					"bypass_value":   payload.Value,
 					"status":         fmt.Sprintf("%d", bypassResp.StatusCode),
@@ -218,6 +374,7 @@ func testAuthBypass(ctx context.Context, client *http.Client, endpoint string) [
 		}
 	}
 
+	findings = append(findings, testPathBypass(ctx, client, ep, techniques, soft404)...)
 	return findings
 }
 
@@ -248,11 +405,11 @@ func discoverLoginEndpoints(ctx context.Context, client *http.Client, baseURL st
 	return found
 }
 
-// testDefaultCredentials attempts to POST common default credentials to a login endpoint.
-func testDefaultCredentials(ctx context.Context, client *http.Client, endpoint string) []types.Finding {
+// testDefaultCredentials attempts to POST the given default credentials to a login endpoint.
+func testDefaultCredentials(ctx context.Context, client *http.Client, endpoint string, creds []defaultCredential) []types.Finding {
 	var findings []types.Finding
 
-	for _, cred := range defaultCredentials {
+	for _, cred := range creds {
 		body := fmt.Sprintf(`{"username":%q,"password":%q}`, cred.Username, cred.Password)
 		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(body))
 		if err != nil {
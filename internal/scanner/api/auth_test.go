@@ -5,6 +5,11 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/buemura/hunter/internal/scanner"
@@ -13,6 +18,9 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// randomPath matches the paths scanner.DetectSoftNotFound requests.
+var randomPath = regexp.MustCompile(`^/[0-9a-f]{16}$`)
+
 func TestAuthScanner_NameAndDescription(t *testing.T) {
 	s := NewAuthScanner()
 	assert.Equal(t, "api-auth", s.Name())
@@ -21,6 +29,10 @@ func TestAuthScanner_NameAndDescription(t *testing.T) {
 
 func TestAuthScanner_NoAuthEndpoint(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/users" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte(`{"data":"sensitive"}`))
 	}))
@@ -175,6 +187,31 @@ func TestAuthScanner_DefaultCredentials(t *testing.T) {
 	assert.True(t, credFound, "expected CRITICAL finding for default credentials")
 }
 
+func TestAuthScanner_SafeIntensitySkipsDefaultCredentials(t *testing.T) {
+	logins := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			if r.Method == http.MethodPost {
+				logins++
+			}
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	opts := scanner.DefaultOptions()
+	opts.Intensity = scanner.IntensitySafe
+	var logged []string
+	opts.Log = func(e scanner.LogEntry) { logged = append(logged, e.Message) }
+
+	_, err := NewAuthScanner().Run(context.Background(), types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}, opts)
+	require.NoError(t, err)
+	assert.Zero(t, logins, "no login attempts at safe intensity")
+	assert.Contains(t, logged, "default credentials: skipped at safe intensity")
+}
+
 func TestAuthScanner_DefaultCredentialsRejected(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/login" && r.Method == http.MethodPost {
@@ -246,3 +283,172 @@ func TestTruncate(t *testing.T) {
 	assert.Equal(t, "hel...", truncate("hello world", 3))
 	assert.Equal(t, "", truncate("", 5))
 }
+
+func TestAuthScanner_ImportedEndpoints(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Only the imported POST route is open.
+		if r.Method == http.MethodPost && r.URL.Path == "/v2/orders" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	opts := scanner.DefaultOptions()
+	opts.Endpoints = []types.Endpoint{{Method: "POST", URL: "/v2/orders", Body: `{}`}}
+	result, err := NewAuthScanner().Run(context.Background(), types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}, opts)
+	require.NoError(t, err)
+
+	var noAuth []types.Finding
+	for _, f := range result.Findings {
+		if f.Metadata["check"] == "no-auth" {
+			noAuth = append(noAuth, f)
+		}
+	}
+	require.Len(t, noAuth, 1)
+	assert.Equal(t, srv.URL+"/v2/orders", noAuth[0].Metadata["endpoint"])
+	assert.Contains(t, noAuth[0].Evidence, "POST "+srv.URL+"/v2/orders")
+}
+
+func TestAuthScanner_CustomEndpoints(t *testing.T) {
+	var paths []string
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if randomPath.MatchString(r.URL.Path) {
+			w.WriteHeader(http.StatusNotFound) // soft 404 detection
+			return
+		}
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		if r.URL.Path == "/internal/users" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	opts := scanner.DefaultOptions()
+	opts.Intensity = scanner.IntensitySafe
+	opts.Endpoints = []types.Endpoint{{Method: "GET", URL: "/v2/orders"}}
+	opts.ExtraArgs = map[string]interface{}{"endpoints": "/internal/users"}
+	result, err := NewAuthScanner().Run(context.Background(), types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}, opts)
+	require.NoError(t, err)
+
+	require.NotEmpty(t, result.Findings)
+	assert.Equal(t, srv.URL+"/internal/users", result.Findings[0].Metadata["endpoint"])
+	for _, p := range paths {
+		assert.Equal(t, "/internal/users", p, "only the given endpoint is tested, not imported or common paths")
+	}
+}
+
+func TestAuthScanner_WriteMethods(t *testing.T) {
+	var deletes atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Reads are protected, but anyone may create and delete orders.
+		switch r.Method {
+		case http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodDelete:
+			deletes.Add(1)
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodPut, http.MethodPatch:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+
+	for _, tt := range []struct {
+		name string
+		opts func(*scanner.Options)
+	}{
+		{"aggressive intensity", func(o *scanner.Options) { o.Intensity = scanner.IntensityAggressive }},
+		{"write_methods", func(o *scanner.Options) { o.ExtraArgs["write_methods"] = true }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := scanner.DefaultOptions()
+			opts.ExtraArgs = map[string]interface{}{"endpoints": "/v2/orders"}
+			opts.Endpoints = []types.Endpoint{{Method: http.MethodDelete, URL: srv.URL + "/v2/orders/1"}}
+			tt.opts(&opts)
+			result, err := NewAuthScanner().Run(context.Background(), types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}, opts)
+			require.NoError(t, err)
+
+			methods := map[string]string{}
+			for _, f := range result.Findings {
+				if f.Metadata["check"] == "no-auth" {
+					methods[f.Metadata["method"]] = f.Metadata["status"]
+					assert.Contains(t, f.Title, f.Metadata["method"]+" "+srv.URL+"/v2/orders")
+				}
+			}
+			assert.Equal(t, map[string]string{"POST": "201"}, methods)
+		})
+	}
+
+	// An imported DELETE request is not replayed either.
+	opts := scanner.DefaultOptions()
+	opts.Intensity = scanner.IntensityAggressive
+	opts.Endpoints = []types.Endpoint{{Method: http.MethodDelete, URL: srv.URL + "/v2/orders/1"}}
+	_, err := NewAuthScanner().Run(context.Background(), types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}, opts)
+	require.NoError(t, err)
+	assert.Zero(t, deletes.Load(), "DELETE is never sent")
+}
+
+func TestAuthScanner_SkipsWriteMethodsByDefault(t *testing.T) {
+	var writes atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.URL.Path == "/v2/orders" {
+			writes.Add(1)
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	for _, intensity := range []scanner.Intensity{scanner.IntensitySafe, scanner.IntensityNormal} {
+		opts := scanner.DefaultOptions()
+		opts.Intensity = intensity
+		opts.ExtraArgs = map[string]interface{}{"endpoints": "/v2/orders"}
+		_, err := NewAuthScanner().Run(context.Background(), types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}, opts)
+		require.NoError(t, err)
+	}
+	assert.Zero(t, writes.Load())
+}
+
+func TestLoadDefaultCredentials(t *testing.T) {
+	creds, err := loadDefaultCredentials(scanner.DefaultOptions())
+	require.NoError(t, err)
+	assert.Equal(t, defaultCredentials, creds)
+
+	path := filepath.Join(t.TempDir(), "default-creds.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{"username":"tomcat","password":"s3cret"}]`), 0o644))
+	opts := scanner.DefaultOptions()
+	opts.ExtraArgs = map[string]interface{}{"default_credentials": path}
+	creds, err = loadDefaultCredentials(opts)
+	require.NoError(t, err)
+	assert.Equal(t, []defaultCredential{{"tomcat", "s3cret"}}, creds)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{`), 0o644))
+	_, err = loadDefaultCredentials(opts)
+	assert.ErrorContains(t, err, "parsing default credentials")
+}
+
+func TestMethodProbes(t *testing.T) {
+	ep := types.Endpoint{URL: "https://api.example.com/v2/orders", Headers: map[string]string{"X-Api-Version": "2"}}
+	probes := methodProbes(ep, writeMethods)
+	require.Len(t, probes, 4)
+	assert.Equal(t, "GET", probes[0].Method)
+	assert.Empty(t, probes[0].Body)
+	for _, p := range probes[1:] {
+		assert.Equal(t, writeProbeBody, p.Body)
+		assert.Equal(t, "application/json", p.Headers["Content-Type"])
+		assert.Equal(t, "2", p.Headers["X-Api-Version"])
+	}
+	assert.Empty(t, ep.Headers["Content-Type"], "the original endpoint's headers are left alone")
+
+	probes = methodProbes(types.Endpoint{Method: "POST", URL: ep.URL, Body: `{"id":1}`}, writeMethods)
+	require.Len(t, probes, 3, "the endpoint's own method is not probed twice")
+	assert.Equal(t, `{"id":1}`, probes[0].Body)
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// consolePaths are where interactive API consoles are commonly served,
+// probed besides commonPaths. Unlike those, they are only reported when
+// they serve a console.
+var consolePaths = []string{
+	"/swagger-ui/",
+	"/swagger-ui.html",
+	"/swagger/",
+	"/swagger/index.html",
+	"/api/swagger-ui/",
+	"/docs",
+	"/api/docs",
+	"/redoc",
+	"/playground",
+	"/graphql/playground",
+}
+
+// maxConsolePage is the most of a page read when looking for a console.
+const maxConsolePage = 256 << 10
+
+// apiConsole is an interactive API console, recognized by markers in its
+// page's HTML.
+type apiConsole struct {
+	Name    string
+	Markers []string
+}
+
+// apiConsoles are checked in order: GraphQL Playground's page mentions
+// GraphiQL, so it comes first.
+var apiConsoles = []apiConsole{
+	{Name: "GraphQL Playground", Markers: []string{"graphql-playground", "graphql playground"}},
+	{Name: "GraphiQL", Markers: []string{"graphiql"}},
+	{Name: "Swagger UI", Markers: []string{"swagger-ui"}},
+	{Name: "Redoc", Markers: []string{"<redoc", "redoc.standalone"}},
+}
+
+// detectConsole returns the name of the API console an HTML page is, or ""
+// when it is none.
+func detectConsole(contentType, body string) string {
+	if !strings.Contains(contentType, "html") {
+		return ""
+	}
+	body = strings.ToLower(body)
+	for _, c := range apiConsoles {
+		for _, marker := range c.Markers {
+			if strings.Contains(body, marker) {
+				return c.Name
+			}
+		}
+	}
+	return ""
+}
+
+// probeConsole requests url and returns a finding if it serves an
+// interactive API console.
+func probeConsole(ctx context.Context, client *http.Client, url, path string) *types.Finding {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil
+	}
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxConsolePage))
+	console := detectConsole(resp.Header.Get("Content-Type"), string(body))
+	if console == "" {
+		return nil
+	}
+	return consoleFinding(url, path, console, resp)
+}
+
+// consoleFinding reports the console served at url.
+func consoleFinding(url, path, console string, resp *http.Response) *types.Finding {
+	return &types.Finding{
+		Title:       fmt.Sprintf("API console exposed: %s at %s", console, path),
+		Description: fmt.Sprintf("%s is served at %s. Anyone can browse the API's operations in it and send requests to them from the browser, which maps the attack surface and makes probing it easy.", console, path),
+		Severity:    types.SeverityLow,
+		Evidence:    fmt.Sprintf("GET %s → %d, %s page", url, resp.StatusCode, console),
+		Remediation: "Disable the console in production, or serve it only behind authentication or on an internal network.",
+		Metadata: map[string]string{
+			"path":         path,
+			"status":       fmt.Sprintf("%d", resp.StatusCode),
+			"content_type": resp.Header.Get("Content-Type"),
+			"console":      console,
+		},
+	}
+}
@@ -55,22 +55,46 @@ func (s *CORSScanner) Run(ctx context.Context, target types.Target, opts scanner
 	}
 
 	client := &http.Client{
-		Timeout: timeout,
+		Timeout:   timeout,
+		Transport: opts.HTTPTransport(),
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
 		},
 	}
 
-	url := strings.TrimRight(baseURL, "/") + "/"
-
-	for _, check := range corsChecks {
-		if ctx.Err() != nil {
-			result.Error = ctx.Err().Error()
-			break
+	// Given or imported endpoints are checked path by path, since CORS
+	// policies are often set per route; otherwise the target's root is.
+	custom, err := customEndpoints(baseURL, opts)
+	if err != nil {
+		return nil, err
+	}
+	urls := []string{strings.TrimRight(baseURL, "/") + "/"}
+	endpoints := custom
+	if len(endpoints) == 0 {
+		endpoints = opts.EndpointsOn(baseURL)
+	}
+	if len(endpoints) > 0 {
+		urls = urls[:0]
+		seen := map[string]bool{}
+		for _, ep := range endpoints {
+			if !seen[ep.URL] {
+				seen[ep.URL] = true
+				urls = append(urls, ep.URL)
+			}
 		}
+	}
+
+checks:
+	for _, url := range urls {
+		for _, check := range corsChecks {
+			if ctx.Err() != nil {
+				result.Error = ctx.Err().Error()
+				break checks
+			}
 
-		findings := probeOrigin(ctx, client, url, check)
-		result.Findings = append(result.Findings, findings...)
+			findings := probeOrigin(ctx, client, url, check)
+			result.Findings = append(result.Findings, findings...)
+		}
 	}
 
 	if len(result.Findings) == 0 {
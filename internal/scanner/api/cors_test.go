@@ -244,3 +244,34 @@ func TestCORSScanner_FindingMetadata(t *testing.T) {
 		}
 	}
 }
+
+func TestCORSScanner_ImportedEndpoints(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Only one route reflects the origin.
+		if origin := r.Header.Get("Origin"); origin != "" && r.URL.Path == "/v2/orders" {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	opts := scanner.DefaultOptions()
+	opts.Endpoints = []types.Endpoint{
+		{Method: "GET", URL: "/v2/users"},
+		{Method: "POST", URL: "/v2/orders"},
+		{Method: "GET", URL: "/v2/orders"},
+	}
+	result, err := NewCORSScanner().Run(context.Background(), types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}, opts)
+	require.NoError(t, err)
+
+	var reflected []types.Finding
+	for _, f := range result.Findings {
+		if f.Severity == types.SeverityHigh {
+			reflected = append(reflected, f)
+		}
+	}
+	require.NotEmpty(t, reflected, "the route reflecting the origin is found although the root does not")
+	for _, f := range reflected {
+		assert.Contains(t, f.Evidence, srv.URL+"/v2/orders ")
+	}
+}
@@ -0,0 +1,329 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// maxSampled is the most endpoints whose responses are sampled.
+const maxSampled = 50
+
+// maxResponseSize is the most of a response body read.
+const maxResponseSize = 1 << 20
+
+// bulkEmails is how many distinct email addresses one response must hold
+// to be reported: a user's own address is expected, a list of them is not.
+const bulkEmails = 5
+
+// maxExposureEvidence is how many fields a finding's evidence lists.
+const maxExposureEvidence = 5
+
+var (
+	passwordHashValue = regexp.MustCompile(`^(?:\$2[abxy]?\$\d{2}\$[./A-Za-z0-9]{53}|\$argon2(?:id|i|d)\$\S+|\$[156]\$[^$]+\$\S+|\$pbkdf2[^$]*\$\S+)$`)
+	ssnValue          = regexp.MustCompile(`^\d{3}-\d{2}-\d{4}$`)
+	emailValue        = regexp.MustCompile(`^[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}$`)
+	awsKeyValue       = regexp.MustCompile(`^(?:AKIA|ASIA)[0-9A-Z]{16}$`)
+	ipInValue         = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+)
+
+// Sensitive field names, lowercased with separators removed.
+var (
+	passwordFields = map[string]bool{"password": true, "passwd": true, "pwd": true, "passwordhash": true, "hashedpassword": true, "pwhash": true, "passhash": true}
+	tokenFields    = map[string]bool{"token": true, "accesstoken": true, "refreshtoken": true, "authtoken": true, "apikey": true, "apisecret": true, "secret": true, "secretkey": true, "clientsecret": true, "privatekey": true, "sessionid": true, "sessiontoken": true}
+	ssnFields      = map[string]bool{"ssn": true, "socialsecuritynumber": true, "socialsecurity": true}
+)
+
+// exposureKind is a kind of sensitive data an API response can expose.
+type exposureKind struct {
+	Name        string
+	Title       string
+	Severity    types.Severity
+	Remediation string
+}
+
+var (
+	kindPassword = exposureKind{"password", "Password hashes", types.SeverityHigh, "Never return passwords or their hashes; select only the fields the client needs, or serialize responses through an explicit allow-list."}
+	kindToken    = exposureKind{"token", "Secrets and tokens", types.SeverityHigh, "Remove tokens, API keys, and secrets from responses, and rotate the ones exposed."}
+	kindSSN      = exposureKind{"ssn", "Social Security numbers", types.SeverityHigh, "Return identity numbers only where required, masked to their last digits, and only to authorized users."}
+	kindEmail    = exposureKind{"email", "Email addresses in bulk", types.SeverityMedium, "Return other users' email addresses only where required, paginate and authorize list endpoints, and mask addresses otherwise."}
+	kindIP       = exposureKind{"internal-ip", "Internal IP addresses", types.SeverityLow, "Remove internal addresses from responses; they map the private network for an attacker."}
+)
+
+// exposure is a sensitive value found in a response, at a JSON path such as
+// users.0.password.
+type exposure struct {
+	Kind  exposureKind
+	Path  string
+	Value string
+}
+
+// DataExposureScanner samples API responses for data clients should not
+// receive.
+type DataExposureScanner struct{}
+
+// NewDataExposureScanner creates a new excessive data exposure scanner.
+func NewDataExposureScanner() *DataExposureScanner {
+	return &DataExposureScanner{}
+}
+
+func (s *DataExposureScanner) Name() string        { return "api-dataexposure" }
+func (s *DataExposureScanner) Description() string { return "API excessive data exposure detection" }
+
+// Run fetches the given or imported GET endpoints, or the common API paths,
+// and reports sensitive fields in their JSON responses.
+func (s *DataExposureScanner) Run(ctx context.Context, target types.Target, opts scanner.Options) (*types.ScanResult, error) {
+	result := &types.ScanResult{
+		ScannerName: s.Name(),
+		Target:      target,
+		StartedAt:   time.Now(),
+	}
+
+	baseURL := resolveURL(target)
+	if baseURL == "" {
+		return nil, fmt.Errorf("cannot determine URL for target %q", target.Host)
+	}
+
+	endpoints, err := customEndpoints(baseURL, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(endpoints) == 0 {
+		endpoints = opts.EndpointsOn(baseURL)
+	}
+	if len(endpoints) == 0 {
+		base := strings.TrimRight(baseURL, "/")
+		for _, p := range commonPaths {
+			endpoints = append(endpoints, types.Endpoint{Method: http.MethodGet, URL: base + p})
+		}
+	}
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	// What an authenticated user is sent matters as much as what anyone
+	// is, so the scan's credentials are used.
+	client := &http.Client{Timeout: timeout, Transport: opts.HTTPTransport()}
+
+	// An internal target's own addresses are expected in its responses.
+	internalTarget := isInternalIP(target.Host)
+
+	sampled := 0
+	for _, ep := range endpoints {
+		if ctx.Err() != nil || sampled == maxSampled {
+			break
+		}
+		if ep.Method != "" && ep.Method != http.MethodGet {
+			continue
+		}
+		sampled++
+
+		doc, err := fetchJSON(ctx, client, ep)
+		if err != nil || doc == nil {
+			continue
+		}
+		found := scanExposures(doc)
+		if internalTarget {
+			found = dropKind(found, kindIP)
+		}
+		result.Findings = append(result.Findings, exposureFindings(ep.URL, found)...)
+	}
+	opts.Logf(s.Name(), scanner.LogInfo, "sampled %d endpoints", sampled)
+
+	result.CompletedAt = time.Now()
+	return result, nil
+}
+
+// fetchJSON requests ep and decodes its response when it is successful JSON,
+// returning nil otherwise.
+func fetchJSON(ctx context.Context, client *http.Client, ep types.Endpoint) (interface{}, error) {
+	req, err := newEndpointRequest(ctx, ep)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		return nil, nil
+	}
+	var doc interface{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&doc); err != nil {
+		return nil, nil
+	}
+	return doc, nil
+}
+
+// scanExposures walks a decoded JSON document and returns the sensitive
+// values in it, by field name or by the shape of the value.
+func scanExposures(doc interface{}) []exposure {
+	var found []exposure
+	emails := map[string]exposure{}
+
+	var walk func(path, key string, v interface{})
+	walk = func(path, key string, v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for k, child := range v {
+				walk(joinPath(path, k), k, child)
+			}
+		case []interface{}:
+			for i, child := range v {
+				walk(joinPath(path, fmt.Sprint(i)), key, child)
+			}
+		case string:
+			if v == "" {
+				return
+			}
+			name := normalizeField(key)
+			switch {
+			case passwordHashValue.MatchString(v) || passwordFields[name]:
+				found = append(found, exposure{kindPassword, path, v})
+			case tokenFields[name] || jwtPattern.MatchString(v) || awsKeyValue.MatchString(v):
+				found = append(found, exposure{kindToken, path, v})
+			case ssnValue.MatchString(v) || ssnFields[name]:
+				found = append(found, exposure{kindSSN, path, v})
+			case emailValue.MatchString(v):
+				emails[strings.ToLower(v)] = exposure{kindEmail, path, v}
+			default:
+				for _, ip := range ipInValue.FindAllString(v, -1) {
+					if isInternalIP(ip) {
+						found = append(found, exposure{kindIP, path, ip})
+						break
+					}
+				}
+			}
+		}
+	}
+	walk("", "", doc)
+
+	if len(emails) >= bulkEmails {
+		for _, e := range emails {
+			found = append(found, e)
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Path < found[j].Path })
+	return found
+}
+
+// exposureFindings reports the exposures in the response of endpoint, one
+// finding per kind.
+func exposureFindings(endpoint string, found []exposure) []types.Finding {
+	var findings []types.Finding
+	for _, kind := range []exposureKind{kindPassword, kindToken, kindSSN, kindEmail, kindIP} {
+		var fields []string
+		var evidence strings.Builder
+		seen := map[string]bool{}
+		count := 0
+		for _, e := range found {
+			if e.Kind != kind {
+				continue
+			}
+			count++
+			if field := genericPath(e.Path); !seen[field] {
+				seen[field] = true
+				fields = append(fields, field)
+			}
+			if count <= maxExposureEvidence {
+				fmt.Fprintf(&evidence, "%s: %s\n", e.Path, redact(kind, e.Value))
+			}
+		}
+		if count == 0 {
+			continue
+		}
+		if count > maxExposureEvidence {
+			fmt.Fprintf(&evidence, "... and %d more\n", count-maxExposureEvidence)
+		}
+		path := endpointPath(endpoint)
+		findings = append(findings, types.Finding{
+			Title:       fmt.Sprintf("%s exposed in API response: %s", kind.Title, path),
+			Description: fmt.Sprintf("The JSON response of %s contains %d value(s) of this kind, in %s. APIs that return whole records and leave filtering to the client expose them to anyone who reads the response.", path, count, strings.Join(fields, ", ")),
+			Severity:    kind.Severity,
+			Evidence:    strings.TrimSpace(evidence.String()),
+			Remediation: kind.Remediation,
+			Metadata: map[string]string{
+				"endpoint": endpoint,
+				"category": kind.Name,
+				"fields":   strings.Join(fields, ","),
+				"count":    fmt.Sprint(count),
+			},
+		})
+	}
+	return findings
+}
+
+// redact hides most of a sensitive value, keeping enough to recognize it.
+func redact(kind exposureKind, v string) string {
+	switch kind {
+	case kindEmail:
+		user, domain, _ := strings.Cut(v, "@")
+		return user[:1] + "***@" + domain
+	case kindSSN:
+		if len(v) >= 4 {
+			return "***-**-" + v[len(v)-4:]
+		}
+	case kindIP:
+		return v
+	}
+	if len(v) <= 8 {
+		return strings.Repeat("*", len(v))
+	}
+	return fmt.Sprintf("%s*** (%d chars)", v[:4], len(v))
+}
+
+// dropKind returns found without the exposures of kind.
+func dropKind(found []exposure, kind exposureKind) []exposure {
+	var kept []exposure
+	for _, e := range found {
+		if e.Kind != kind {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+// joinPath appends a key to a JSON path.
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// genericPath replaces the array indexes in a JSON path with *, so that the
+// same field of every element is named once.
+func genericPath(path string) string {
+	parts := strings.Split(path, ".")
+	for i, p := range parts {
+		if p != "" && strings.Trim(p, "0123456789") == "" {
+			parts[i] = "*"
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// normalizeField lowercases a field name and removes separators, so that
+// password_hash, passwordHash, and password-hash compare equal.
+func normalizeField(name string) string {
+	return strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(name))
+}
+
+// isInternalIP reports whether s is a private IP address.
+func isInternalIP(s string) bool {
+	ip := net.ParseIP(s)
+	return ip != nil && ip.IsPrivate()
+}
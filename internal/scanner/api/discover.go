@@ -62,17 +62,39 @@ func (s *Scanner) Run(ctx context.Context, target types.Target, opts scanner.Opt
 	}
 
 	client := &http.Client{
-		Timeout: timeout,
+		Timeout:   timeout,
+		Transport: opts.HTTPTransport(),
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
 		},
 	}
 
+	if endpoints := opts.EndpointsOn(baseURL); len(endpoints) > 0 {
+		for _, ep := range endpoints {
+			finding := probeEndpoint(ctx, client, ep)
+			if finding != nil {
+				result.Findings = append(result.Findings, *finding)
+			}
+		}
+		result.CompletedAt = time.Now()
+		return result, nil
+	}
+
+	// A site serving a page for every path would have every common path
+	// discovered, so paths are only reported when their page is not that
+	// one.
+	soft404 := scanner.DetectSoftNotFound(ctx, client, baseURL)
+
+	// Each console is reported once, at the first path it is found at.
+	consoles := map[string]bool{}
 	for _, path := range commonPaths {
 		url := strings.TrimRight(baseURL, "/") + path
 
-		finding := probePath(ctx, client, url, path)
+		finding := probePath(ctx, client, url, path, soft404)
 		if finding != nil {
+			if console := finding.Metadata["console"]; console != "" {
+				consoles[console] = true
+			}
 			result.Findings = append(result.Findings, *finding)
 		}
 
@@ -82,29 +104,44 @@ func (s *Scanner) Run(ctx context.Context, target types.Target, opts scanner.Opt
 			}
 		}
 	}
+	for _, path := range consolePaths {
+		url := strings.TrimRight(baseURL, "/") + path
+		if finding := probeConsole(ctx, client, url, path); finding != nil && !consoles[finding.Metadata["console"]] {
+			consoles[finding.Metadata["console"]] = true
+			result.Findings = append(result.Findings, *finding)
+		}
+	}
 
 	result.CompletedAt = time.Now()
 	return result, nil
 }
 
 // probePath sends a GET request to the given URL and returns a finding if the
-// endpoint responds with a non-404 status.
-func probePath(ctx context.Context, client *http.Client, url, path string) *types.Finding {
+// endpoint responds with a non-404 status and a page other than the one
+// soft404 says the site serves for any path. A page that is an interactive
+// API console is reported as one.
+func probePath(ctx context.Context, client *http.Client, url, path string, soft404 *scanner.SoftNotFound) *types.Finding {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil
 	}
+	req.Header.Set("Accept", "text/html, application/json;q=0.9, */*;q=0.8")
 
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil
 	}
 	defer resp.Body.Close()
-	io.Copy(io.Discard, resp.Body)
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxConsolePage))
 
-	if resp.StatusCode == http.StatusNotFound {
+	if resp.StatusCode == http.StatusNotFound || soft404.Matches(resp.StatusCode, string(body), path) {
 		return nil
 	}
+	if resp.StatusCode == http.StatusOK {
+		if console := detectConsole(resp.Header.Get("Content-Type"), string(body)); console != "" {
+			return consoleFinding(url, path, console, resp)
+		}
+	}
 
 	return &types.Finding{
 		Title:       fmt.Sprintf("API endpoint discovered: %s", path),
@@ -119,6 +156,41 @@ func probePath(ctx context.Context, client *http.Client, url, path string) *type
 	}
 }
 
+// probeEndpoint replays an imported endpoint with its method, headers, and
+// example body, and returns a finding if it responds with a non-404 status.
+func probeEndpoint(ctx context.Context, client *http.Client, ep types.Endpoint) *types.Finding {
+	req, err := newEndpointRequest(ctx, ep)
+	if err != nil {
+		return nil
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	path := endpointPath(ep.URL)
+	return &types.Finding{
+		Title:       fmt.Sprintf("API endpoint discovered: %s %s", req.Method, path),
+		Description: fmt.Sprintf("Imported endpoint %s %s responded with status %d.", req.Method, path, resp.StatusCode),
+		Severity:    types.SeverityInfo,
+		Evidence:    fmt.Sprintf("%s %s → %d", req.Method, ep.URL, resp.StatusCode),
+		Metadata: map[string]string{
+			"path":         path,
+			"method":       req.Method,
+			"status":       fmt.Sprintf("%d", resp.StatusCode),
+			"content_type": resp.Header.Get("Content-Type"),
+			"source":       "import",
+		},
+	}
+}
+
 // probeGraphQL sends a GraphQL introspection query and reports back if successful.
 func probeGraphQL(ctx context.Context, client *http.Client, url string) *types.Finding {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(graphQLIntrospectionQuery))
@@ -171,5 +243,5 @@ func resolveURL(target types.Target) string {
 	if target.Host == "" {
 		return ""
 	}
-	return scheme + "://" + target.Host
+	return scheme + "://" + target.URLHost()
 }
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -223,3 +224,84 @@ func TestScanner_EmptyTarget(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "cannot determine URL")
 }
+
+func TestScanner_ImportedEndpoints(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.RequestURI()+" "+r.Header.Get("Content-Type")+" "+string(body))
+		if r.URL.Path == "/v2/orders" {
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	opts := scanner.DefaultOptions()
+	opts.Endpoints = []types.Endpoint{
+		{Method: "POST", URL: "/v2/orders", Headers: map[string]string{"Content-Type": "application/json"}, Body: `{"id":1}`},
+		{URL: srv.URL + "/v2/missing?x=1"},
+		{Method: "GET", URL: "https://other.example.com/v2/orders"},
+	}
+	result, err := New().Run(context.Background(), types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}, opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		`POST /v2/orders application/json {"id":1}`,
+		"GET /v2/missing?x=1  ",
+	}, requests, "only imported endpoints on the target are requested, instead of the common paths")
+	require.Len(t, result.Findings, 1)
+	f := result.Findings[0]
+	assert.Equal(t, "API endpoint discovered: POST /v2/orders", f.Title)
+	assert.Equal(t, "POST", f.Metadata["method"])
+	assert.Equal(t, "201", f.Metadata["status"])
+	assert.Equal(t, "import", f.Metadata["source"])
+}
+
+func TestScanner_APIConsoles(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		switch r.URL.Path {
+		case "/graphiql":
+			io.WriteString(w, `<html><head><title>GraphiQL</title></head><body><div id="graphiql">Loading...</div></body></html>`)
+		case "/swagger-ui/", "/swagger-ui.html":
+			io.WriteString(w, `<html><body><div id="swagger-ui"></div><script src="./swagger-ui-bundle.js"></script></body></html>`)
+		case "/docs":
+			io.WriteString(w, `<html><body><redoc spec-url="/openapi.json"></redoc></body></html>`)
+		case "/playground":
+			io.WriteString(w, `<html><body>Welcome to our playground!</body></html>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	target := types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}
+	result, err := New().Run(context.Background(), target, scanner.DefaultOptions())
+	require.NoError(t, err)
+
+	consoles := map[string]types.Finding{}
+	for _, f := range result.Findings {
+		assert.NotEqual(t, "API endpoint discovered: /graphiql", f.Title, "a console replaces the generic finding")
+		if c := f.Metadata["console"]; c != "" {
+			assert.NotContains(t, consoles, c, "each console is reported once")
+			consoles[c] = f
+		}
+	}
+	require.Len(t, consoles, 3, "a page merely mentioning a playground is not one")
+	assert.Equal(t, "API console exposed: GraphiQL at /graphiql", consoles["GraphiQL"].Title)
+	assert.Equal(t, "/swagger-ui/", consoles["Swagger UI"].Metadata["path"])
+	assert.Equal(t, "/docs", consoles["Redoc"].Metadata["path"])
+	for _, f := range consoles {
+		assert.Equal(t, types.SeverityLow, f.Severity)
+		assert.NotEmpty(t, f.Remediation)
+	}
+}
+
+func TestDetectConsole(t *testing.T) {
+	assert.Equal(t, "GraphQL Playground", detectConsole("text/html", `<title>GraphQL Playground</title><script src="//cdn.jsdelivr.net/npm/graphql-playground-react/build/static/js/middleware.js"></script><!-- based on GraphiQL -->`))
+	assert.Equal(t, "Swagger UI", detectConsole("text/html", `<link rel="stylesheet" href="swagger-ui.css">`))
+	assert.Empty(t, detectConsole("application/json", `{"swagger-ui": true}`), "only HTML pages are consoles")
+	assert.Empty(t, detectConsole("text/html", `<h1>Welcome</h1>`))
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// customEndpoints returns the endpoints given in the "endpoints" ExtraArg,
+// which the api-auth, api-cors, and api-ratelimit scanners test instead of
+// imported endpoints or common paths. The arg is a file with one endpoint
+// per line, or a comma-separated list. Each endpoint is a path, joined to
+// baseURL, or an absolute URL, optionally preceded by a method as in
+// "POST /api/users"; lines starting with # are comments.
+func customEndpoints(baseURL string, opts scanner.Options) ([]types.Endpoint, error) {
+	value := strings.TrimSpace(opts.StringArg("endpoints"))
+	if value == "" {
+		return nil, nil
+	}
+
+	var entries []string
+	if info, err := os.Stat(value); err == nil && info.Mode().IsRegular() {
+		f, err := os.Open(value)
+		if err != nil {
+			return nil, fmt.Errorf("reading endpoints: %w", err)
+		}
+		defer f.Close()
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			entries = append(entries, sc.Text())
+		}
+		if err := sc.Err(); err != nil {
+			return nil, fmt.Errorf("reading endpoints: %w", err)
+		}
+	} else {
+		entries = strings.Split(value, ",")
+	}
+
+	base := strings.TrimRight(baseURL, "/")
+	var endpoints []types.Endpoint
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		method := http.MethodGet
+		if m, rest, ok := strings.Cut(entry, " "); ok {
+			method, entry = strings.ToUpper(m), strings.TrimSpace(rest)
+		}
+		switch method {
+		case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		default:
+			return nil, fmt.Errorf("endpoints: %q: unknown method %s", entry, method)
+		}
+
+		raw := entry
+		if !strings.Contains(entry, "://") {
+			raw = base + "/" + strings.TrimLeft(entry, "/")
+		}
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("endpoints: %q is not a path or http(s) URL", entry)
+		}
+		endpoints = append(endpoints, types.Endpoint{Method: method, URL: u.String()})
+	}
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("endpoints: no endpoints in %q", value)
+	}
+	return endpoints, nil
+}
+
+// newEndpointRequest returns the request ep describes.
+func newEndpointRequest(ctx context.Context, ep types.Endpoint) (*http.Request, error) {
+	method := ep.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	req, err := http.NewRequestWithContext(ctx, method, ep.URL, strings.NewReader(ep.Body))
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range ep.Headers {
+		req.Header.Set(name, value)
+	}
+	return req, nil
+}
+
+// endpointPath returns the path and query of an endpoint URL, for titles.
+func endpointPath(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u.RequestURI()
+}
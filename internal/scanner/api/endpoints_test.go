@@ -0,0 +1,51 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomEndpoints(t *testing.T) {
+	opts := scanner.DefaultOptions()
+	endpoints, err := customEndpoints("https://api.example.com", opts)
+	require.NoError(t, err)
+	assert.Nil(t, endpoints, "no arg falls back to the scanner's defaults")
+
+	opts.ExtraArgs = map[string]interface{}{"endpoints": "/v2/users, post /v2/orders,https://other.example.com/health"}
+	endpoints, err = customEndpoints("https://api.example.com/", opts)
+	require.NoError(t, err)
+	assert.Equal(t, []types.Endpoint{
+		{Method: "GET", URL: "https://api.example.com/v2/users"},
+		{Method: "POST", URL: "https://api.example.com/v2/orders"},
+		{Method: "GET", URL: "https://other.example.com/health"},
+	}, endpoints)
+
+	path := filepath.Join(t.TempDir(), "routes.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# orders API\nGET /v2/orders\n\nDELETE v2/orders/1\n"), 0o644))
+	opts.ExtraArgs = map[string]interface{}{"endpoints": path}
+	endpoints, err = customEndpoints("https://api.example.com", opts)
+	require.NoError(t, err)
+	assert.Equal(t, []types.Endpoint{
+		{Method: "GET", URL: "https://api.example.com/v2/orders"},
+		{Method: "DELETE", URL: "https://api.example.com/v2/orders/1"},
+	}, endpoints)
+
+	opts.ExtraArgs = map[string]interface{}{"endpoints": []interface{}{"/a", "/b"}}
+	endpoints, err = customEndpoints("https://api.example.com", opts)
+	require.NoError(t, err)
+	assert.Len(t, endpoints, 2, "a config list works as well as a string")
+
+	opts.ExtraArgs = map[string]interface{}{"endpoints": "FETCH /v2/users"}
+	_, err = customEndpoints("https://api.example.com", opts)
+	assert.ErrorContains(t, err, "unknown method")
+
+	opts.ExtraArgs = map[string]interface{}{"endpoints": "ftp://api.example.com/files"}
+	_, err = customEndpoints("https://api.example.com", opts)
+	assert.ErrorContains(t, err, "not a path or http(s) URL")
+}
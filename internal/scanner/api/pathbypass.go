@@ -0,0 +1,153 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// pathBypass is a way of requesting an endpoint under a path that access
+// rules written for its canonical path may not match, while the router still
+// serves it.
+type pathBypass struct {
+	Name string
+	// Path rewrites the endpoint's escaped path.
+	Path func(p string) string
+	// Header, when set, carries the endpoint's path instead: the request
+	// goes to the site's root, and a proxy or framework honoring the header
+	// routes it to the endpoint.
+	Header string
+}
+
+// pathBypasses are the path manipulation techniques tried, most likely to
+// work first. The "api-auth.path_bypasses" intensity budget decides how many
+// are tried.
+var pathBypasses = []pathBypass{
+	{Name: "trailing slash", Path: func(p string) string {
+		if strings.HasSuffix(p, "/") {
+			return strings.TrimRight(p, "/")
+		}
+		return p + "/"
+	}},
+	{Name: "double slash", Path: func(p string) string { return "/" + p }},
+	{Name: "case variation", Path: strings.ToUpper},
+	{Name: "encoded dot segment (%2e)", Path: func(p string) string { return "/%2e" + p }},
+	{Name: "encoded slash (%2f)", Path: func(p string) string {
+		i := strings.LastIndex(p, "/")
+		if i <= 0 {
+			return p
+		}
+		return p[:i] + "%2f" + p[i+1:]
+	}},
+	{Name: "X-Original-URL header", Header: "X-Original-URL"},
+	{Name: "X-Rewrite-URL header", Header: "X-Rewrite-URL"},
+}
+
+// testPathBypass requests ep, which requires authentication, with each of
+// the given path manipulation techniques, and reports those that succeed
+// with a page other than the one soft404 says the site serves for any path.
+func testPathBypass(ctx context.Context, client *http.Client, ep types.Endpoint, techniques []pathBypass, soft404 *scanner.SoftNotFound) []types.Finding {
+	u, err := url.Parse(ep.URL)
+	if err != nil {
+		return nil
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	query := ""
+	if u.RawQuery != "" {
+		query = "?" + u.RawQuery
+	}
+	root := u.Scheme + "://" + u.Host
+
+	// A root that is open to anyone would make every header technique look
+	// like a bypass, so it is checked once before they are tried.
+	rootOpen := func() bool {
+		probe := ep
+		probe.URL = root + "/"
+		status, _, err := sendProbe(ctx, client, probe, nil)
+		return err != nil || accepted(ep.Method, status)
+	}
+	checkedRoot, skipHeaders := false, false
+
+	var findings []types.Finding
+	for _, technique := range techniques {
+		probe := ep
+		var header http.Header
+		if technique.Header != "" {
+			if path == "/" {
+				continue
+			}
+			if !checkedRoot {
+				checkedRoot, skipHeaders = true, rootOpen()
+			}
+			if skipHeaders {
+				continue
+			}
+			probe.URL = root + "/"
+			header = http.Header{technique.Header: {path + query}}
+		} else {
+			rewritten := technique.Path(path)
+			if rewritten == path || rewritten == "" {
+				continue
+			}
+			probe.URL = root + rewritten + query
+		}
+
+		status, body, err := sendProbe(ctx, client, probe, header)
+		if err != nil || !accepted(ep.Method, status) || soft404.Matches(status, body, endpointPath(probe.URL)) {
+			continue
+		}
+
+		evidence := fmt.Sprintf("%s %s → %d", ep.Method, probe.URL, status)
+		if technique.Header != "" {
+			evidence = fmt.Sprintf("%s %s with %s: %s → %d", ep.Method, probe.URL, technique.Header, path+query, status)
+		}
+		metadata := map[string]string{
+			"endpoint":      ep.URL,
+			"method":        ep.Method,
+			"bypass_method": technique.Name,
+			"bypass_url":    probe.URL,
+			"status":        fmt.Sprintf("%d", status),
+			"check":         "path-bypass",
+		}
+		if technique.Header != "" {
+			metadata["bypass_header"] = technique.Header
+		}
+		findings = append(findings, types.Finding{
+			Title:       fmt.Sprintf("Authentication bypass via %s: %s %s", technique.Name, ep.Method, ep.URL),
+			Description: fmt.Sprintf("The endpoint requires authentication, but requesting it by %s returned HTTP %d without credentials: the access rule matches the path differently than the router does.", technique.Name, status),
+			Severity:    types.SeverityHigh,
+			Evidence:    evidence,
+			Remediation: "Enforce authentication in the application or on normalized paths, not with proxy rules on the raw path, and ignore X-Original-URL and X-Rewrite-URL from clients.",
+			Metadata:    metadata,
+		})
+	}
+	return findings
+}
+
+// sendProbe sends ep's request without credentials, with header added, and
+// returns the response status and body.
+func sendProbe(ctx context.Context, client *http.Client, ep types.Endpoint, header http.Header) (int, string, error) {
+	req, err := newEndpointRequest(ctx, ep)
+	if err != nil {
+		return 0, "", err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	resp.Body.Close()
+	return resp.StatusCode, string(body), nil
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pathBypassServer protects /admin/users with a rule matching the raw path
+// exactly, like a naive proxy ACL, and routes requests the way a lenient
+// framework does: case-insensitively, ignoring slashes, and honoring
+// X-Original-URL.
+func pathBypassServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw := r.URL.EscapedPath()
+		if raw == "/admin/users" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		route := raw
+		if original := r.Header.Get("X-Original-URL"); original != "" {
+			route = original
+		}
+		route = strings.ToLower(strings.NewReplacer("%2e/", "", "%2f", "/").Replace(route))
+		if strings.Trim(strings.ReplaceAll(route, "//", "/"), "/") == "admin/users" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestTestPathBypass(t *testing.T) {
+	srv := pathBypassServer(t)
+	ep := types.Endpoint{Method: http.MethodGet, URL: srv.URL + "/admin/users"}
+
+	findings := testPathBypass(context.Background(), srv.Client(), ep, pathBypasses, nil)
+
+	techniques := map[string]types.Finding{}
+	for _, f := range findings {
+		techniques[f.Metadata["bypass_method"]] = f
+		assert.Equal(t, "path-bypass", f.Metadata["check"])
+		assert.Equal(t, types.SeverityHigh, f.Severity)
+	}
+	for _, name := range []string{"trailing slash", "double slash", "case variation", "encoded dot segment (%2e)", "encoded slash (%2f)", "X-Original-URL header"} {
+		assert.Contains(t, techniques, name)
+	}
+	assert.NotContains(t, techniques, "X-Rewrite-URL header", "the server ignores X-Rewrite-URL")
+
+	assert.Equal(t, srv.URL+"/%2e/admin/users", techniques["encoded dot segment (%2e)"].Metadata["bypass_url"])
+	assert.Equal(t, srv.URL+"/admin%2fusers", techniques["encoded slash (%2f)"].Metadata["bypass_url"])
+	assert.Equal(t, srv.URL+"/", techniques["X-Original-URL header"].Metadata["bypass_url"])
+	assert.Contains(t, techniques["X-Original-URL header"].Evidence, "X-Original-URL: /admin/users")
+	assert.Contains(t, techniques["case variation"].Title, "GET "+srv.URL+"/admin/users")
+}
+
+func TestTestPathBypass_OpenRootSkipsHeaders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin/users" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusOK) // the home page, whatever the headers
+	}))
+	defer srv.Close()
+
+	ep := types.Endpoint{Method: http.MethodGet, URL: srv.URL + "/admin/users"}
+	findings := testPathBypass(context.Background(), srv.Client(), ep, pathBypasses[5:], nil)
+	assert.Empty(t, findings)
+}
+
+func TestAuthScanner_PathBypassIgnoresSoft404(t *testing.T) {
+	// A single-page application behind a proxy protecting /admin/users
+	// serves its shell for every other path, however it is spelled.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin/users" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprintf(w, `<!doctype html><html><body><div id="root" data-route="%s"></div><script src="/app.js"></script></body></html>`, r.URL.Path)
+	}))
+	defer srv.Close()
+
+	opts := scanner.DefaultOptions()
+	opts.ExtraArgs = map[string]interface{}{"endpoints": "/admin/users"}
+	result, err := NewAuthScanner().Run(context.Background(), types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}, opts)
+	require.NoError(t, err)
+	for _, f := range result.Findings {
+		assert.NotEqual(t, "path-bypass", f.Metadata["check"], "%s serves the application shell, not the endpoint", f.Metadata["bypass_url"])
+	}
+}
+
+func TestAuthScanner_PathBypass(t *testing.T) {
+	srv := pathBypassServer(t)
+
+	opts := scanner.DefaultOptions()
+	opts.Intensity = scanner.IntensitySafe
+	opts.ExtraArgs = map[string]interface{}{"endpoints": "/admin/users"}
+	result, err := NewAuthScanner().Run(context.Background(), types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}, opts)
+	require.NoError(t, err)
+
+	var names []string
+	for _, f := range result.Findings {
+		if f.Metadata["check"] == "path-bypass" {
+			names = append(names, f.Metadata["bypass_method"])
+		}
+	}
+	assert.Equal(t, []string{"trailing slash", "double slash"}, names, "safe intensity tries the first two techniques")
+}
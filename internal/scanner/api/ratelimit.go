@@ -11,8 +11,6 @@ import (
 	"github.com/buemura/hunter/pkg/types"
 )
 
-const defaultRequests = 50
-
 // RateLimitScanner checks whether a target endpoint enforces rate limiting.
 type RateLimitScanner struct{}
 
@@ -36,37 +34,52 @@ func (s *RateLimitScanner) Run(ctx context.Context, target types.Target, opts sc
 		return nil, fmt.Errorf("cannot determine URL for target %q", target.Host)
 	}
 
-	numRequests := defaultRequests
-	if opts.ExtraArgs != nil {
-		if v, ok := opts.ExtraArgs["requests"].(int); ok && v > 0 {
-			numRequests = v
+	// With given or imported endpoints, the first GET one in scope is
+	// tested: an actual API route rather than the site's root.
+	endpoints, err := customEndpoints(baseURL, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(endpoints) == 0 {
+		endpoints = opts.EndpointsOn(baseURL)
+	}
+	for _, ep := range endpoints {
+		if ep.Method == http.MethodGet {
+			baseURL = ep.URL
+			break
 		}
 	}
 
+	numRequests := opts.Budget("api-ratelimit.requests")
+	if v := opts.IntArg("requests"); v > 0 {
+		numRequests = v
+	}
+
 	timeout := opts.Timeout
 	if timeout == 0 {
 		timeout = 5 * time.Second
 	}
 
-	client := &http.Client{Timeout: timeout}
+	client := &http.Client{Timeout: timeout, Transport: opts.HTTPTransport()}
 
 	rateLimited := false
 	var rateLimitHeaders map[string]string
 
 	for i := 0; i < numRequests; i++ {
-		select {
-		case <-ctx.Done():
+		if opts.Gate.Wait(ctx) != nil {
 			result.CompletedAt = time.Now()
 			return result, nil
-		default:
 		}
 
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL, nil)
 		if err != nil {
 			return nil, fmt.Errorf("creating request: %w", err)
 		}
+		// Every request must reach the target, not a response cache.
+		req.Header.Set("Cache-Control", "no-cache")
 
 		resp, err := client.Do(req)
+		opts.ReportProgress(s.Name(), i+1, numRequests)
 		if err != nil {
 			continue
 		}
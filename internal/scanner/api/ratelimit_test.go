@@ -41,6 +41,23 @@ func TestRateLimitScanner_NoRateLimiting(t *testing.T) {
 	assert.Equal(t, int32(10), count.Load())
 }
 
+func TestRateLimitScanner_BypassesResponseCache(t *testing.T) {
+	var count atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count.Add(1)
+	}))
+	defer srv.Close()
+
+	target := types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}
+	opts := scanner.DefaultOptions()
+	opts.Transport = scanner.NewCacheTransport(nil)
+	opts.ExtraArgs = map[string]interface{}{"requests": 10}
+
+	_, err := NewRateLimitScanner().Run(context.Background(), target, opts)
+	require.NoError(t, err)
+	assert.Equal(t, int32(10), count.Load())
+}
+
 func TestRateLimitScanner_RateLimitedWith429(t *testing.T) {
 	var count atomic.Int32
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -125,11 +142,27 @@ func TestRateLimitScanner_DefaultRequestCount(t *testing.T) {
 	result, err := s.Run(context.Background(), target, opts)
 
 	require.NoError(t, err)
-	assert.Equal(t, int32(defaultRequests), count.Load())
+	assert.Equal(t, int32(50), count.Load())
 	require.Len(t, result.Findings, 1)
 	assert.Equal(t, "50", result.Findings[0].Metadata["requests_sent"])
 }
 
+func TestRateLimitScanner_SafeIntensity(t *testing.T) {
+	var count atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	opts := scanner.DefaultOptions()
+	opts.Intensity = scanner.IntensitySafe
+
+	_, err := NewRateLimitScanner().Run(context.Background(), types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, int32(20), count.Load())
+}
+
 func TestRateLimitScanner_EmptyTarget(t *testing.T) {
 	s := NewRateLimitScanner()
 	target := types.Target{}
@@ -192,3 +225,19 @@ func TestRateLimitScanner_RetryAfterHeader(t *testing.T) {
 	assert.True(t, hasHeaders)
 	assert.False(t, hasNoLimit)
 }
+
+func TestRateLimitScanner_CustomEndpoint(t *testing.T) {
+	var count atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/search" {
+			count.Add(1)
+		}
+	}))
+	defer srv.Close()
+
+	opts := scanner.DefaultOptions()
+	opts.ExtraArgs = map[string]interface{}{"requests": 5, "endpoints": "POST /v2/login,/v2/search"}
+	_, err := NewRateLimitScanner().Run(context.Background(), types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, int32(5), count.Load(), "the first GET endpoint given is tested")
+}
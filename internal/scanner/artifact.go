@@ -0,0 +1,47 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httputil"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// MaxArtifactSize bounds each side of an artifact, the raw request and the
+// raw response, in bytes. Anything beyond it is cut and the artifact marked
+// truncated.
+const MaxArtifactSize = 64 << 10
+
+// NewArtifact records an HTTP exchange as evidence for a finding. reqBody and
+// respBody are the bodies as sent and received, since by the time a finding
+// is made the request body has been sent and the response body read; either
+// may be nil. Credentials added by HTTPTransport are not part of req and so
+// are never recorded.
+func NewArtifact(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte) types.Artifact {
+	var a types.Artifact
+
+	if req != nil {
+		head, err := httputil.DumpRequestOut(req, false)
+		if err == nil {
+			a.Request, a.Truncated = bound(append(head, reqBody...))
+		}
+	}
+	if resp != nil {
+		head, err := httputil.DumpResponse(resp, false)
+		if err == nil {
+			var truncated bool
+			a.Response, truncated = bound(append(head, respBody...))
+			a.Truncated = a.Truncated || truncated
+		}
+	}
+	return a
+}
+
+// bound returns raw as a string cut at MaxArtifactSize, and whether it was
+// cut.
+func bound(raw []byte) (string, bool) {
+	if len(raw) <= MaxArtifactSize {
+		return string(raw), false
+	}
+	return string(raw[:MaxArtifactSize]), true
+}
@@ -0,0 +1,49 @@
+package scanner
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewArtifact_RecordsExchange(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Test", "yes")
+		w.Write([]byte("hello"))
+	}))
+	defer srv.Close()
+
+	opts := Options{Authenticate: func(req *http.Request) { req.Header.Set("Authorization", "Bearer secret") }}
+	client := &http.Client{Transport: opts.HTTPTransport()}
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/path?q=1", nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	a := NewArtifact(req, nil, resp, body)
+	assert.True(t, strings.HasPrefix(a.Request, "GET /path?q=1 HTTP/1.1\r\n"))
+	assert.NotContains(t, a.Request, "secret", "credentials must not be recorded")
+	assert.True(t, strings.HasPrefix(a.Response, "HTTP/1.1 200 OK\r\n"))
+	assert.Contains(t, a.Response, "X-Test: yes")
+	assert.True(t, strings.HasSuffix(a.Response, "hello"))
+	assert.False(t, a.Truncated)
+}
+
+func TestNewArtifact_Truncates(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "http://example.com/", nil)
+	require.NoError(t, err)
+	resp := &http.Response{StatusCode: http.StatusOK, ProtoMajor: 1, ProtoMinor: 1, Header: http.Header{}}
+
+	a := NewArtifact(req, []byte("small"), resp, []byte(strings.Repeat("x", MaxArtifactSize+1)))
+	assert.Contains(t, a.Request, "small")
+	assert.Len(t, a.Response, MaxArtifactSize)
+	assert.True(t, a.Truncated)
+}
@@ -0,0 +1,127 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// metadataService is a cloud provider's instance metadata service.
+type metadataService struct {
+	provider string
+	url      string
+	// header is the header the service requires on requests.
+	header http.Header
+	// identify reports whether resp came from the service.
+	identify func(resp *response) bool
+}
+
+// metadataServices are the metadata services asked for through the target.
+var metadataServices = []metadataService{
+	{
+		provider: "AWS",
+		url:      "http://169.254.169.254/latest/meta-data/",
+		identify: func(resp *response) bool {
+			return resp.status == http.StatusOK && strings.Contains(resp.body, "ami-id") && strings.Contains(resp.body, "instance-id")
+		},
+	},
+	{
+		provider: "Google Cloud",
+		url:      "http://metadata.google.internal/computeMetadata/v1/instance/",
+		header:   http.Header{"Metadata-Flavor": {"Google"}},
+		identify: func(resp *response) bool {
+			return resp.status == http.StatusOK && resp.header.Get("Metadata-Flavor") == "Google"
+		},
+	},
+	{
+		provider: "Azure",
+		url:      "http://169.254.169.254/metadata/instance?api-version=2021-02-01",
+		header:   http.Header{"Metadata": {"true"}},
+		identify: func(resp *response) bool {
+			return resp.status == http.StatusOK && strings.Contains(resp.body, `"azEnvironment"`)
+		},
+	},
+}
+
+// checkMetadata asks for each metadata service through the target, both as
+// a forward proxy (an absolute URL in the request line) and as a reverse
+// proxy routing on the Host header. A proxy that forwards either to the
+// metadata service hands out the instance's identity and the short-lived
+// credentials of its role. One finding is made per way through.
+func (p *prober) checkMetadata(ctx context.Context) []types.Finding {
+	var findings []types.Finding
+	for _, via := range []string{"proxy", "host"} {
+		for _, svc := range metadataServices {
+			if ctx.Err() != nil {
+				return findings
+			}
+			req, client, err := p.metadataRequest(ctx, svc, via)
+			if err != nil {
+				continue
+			}
+			resp, err := p.get(ctx, client, req)
+			if err != nil || !svc.identify(resp) {
+				continue
+			}
+			findings = append(findings, metadataFinding(svc, via, req, resp))
+			break
+		}
+	}
+	return findings
+}
+
+// metadataRequest returns the request for svc through the target, sent
+// via "proxy" or "host", and the client to send it with.
+func (p *prober) metadataRequest(ctx context.Context, svc metadataService, via string) (*http.Request, *http.Client, error) {
+	target, host, client := svc.url, "", p.proxied
+	if via == "host" {
+		u, err := url.Parse(svc.url)
+		if err != nil {
+			return nil, nil, err
+		}
+		target = p.root.ResolveReference(&url.URL{Path: u.Path, RawQuery: u.RawQuery}).String()
+		host, client = u.Host, p.client
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if host != "" {
+		req.Host = host
+	}
+	for k, v := range svc.header {
+		req.Header[k] = v
+	}
+	return req, client, nil
+}
+
+// metadataFinding reports that svc answered req, sent through the target
+// via "proxy" or "host".
+func metadataFinding(svc metadataService, via string, req *http.Request, resp *response) types.Finding {
+	how := fmt.Sprintf("GET %s sent to the target as a proxy", svc.url)
+	if via == "host" {
+		how = fmt.Sprintf("GET %s with Host: %s", req.URL, req.Host)
+	}
+	return types.Finding{
+		Title:       "Cloud metadata reachable through the target",
+		Description: fmt.Sprintf("The target forwards requests to the %s instance metadata service. Anyone can read the instance's metadata through it, including the temporary credentials of the role attached to it, which give access to the cloud account.", svc.provider),
+		Severity:    types.SeverityCritical,
+		Evidence:    fmt.Sprintf("%s → %d from %s metadata", how, resp.status, svc.provider),
+		Remediation: "Do not let the proxy forward requests for hosts other than its own upstreams; reject absolute URLs in the request line and unknown Host headers. Require session tokens for the metadata service, e.g. IMDSv2 on AWS, and block 169.254.169.254 from the proxy's network.",
+		References: []types.Reference{
+			{Title: "AWS: Use IMDSv2", URL: "https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/configuring-instance-metadata-service.html"},
+			{Title: "OWASP: Server Side Request Forgery", URL: "https://owasp.org/www-community/attacks/Server_Side_Request_Forgery"},
+		},
+		Metadata: map[string]string{
+			"check":    "metadata",
+			"provider": svc.provider,
+			"via":      via,
+			"url":      req.URL.String(),
+		},
+		Artifacts: []types.Artifact{resp.artifact},
+	}
+}
@@ -0,0 +1,195 @@
+// Package cloud checks whether the target exposes the control plane of the
+// infrastructure it runs on: kubelets, the Kubernetes API server, etcd, the
+// Docker API, control-plane metrics, and cloud metadata services reached
+// through it.
+package cloud
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// userAgent is the User-Agent the probes send.
+const userAgent = "Mozilla/5.0 (compatible; hunter)"
+
+// Scanner checks the target for exposed Kubernetes, Docker, and cloud
+// metadata endpoints.
+type Scanner struct{}
+
+// New creates a new cloud scanner.
+func New() *Scanner {
+	return &Scanner{}
+}
+
+func (s *Scanner) Name() string        { return "cloud" }
+func (s *Scanner) Description() string { return "Kubernetes and cloud metadata exposure checks" }
+
+func (s *Scanner) Run(ctx context.Context, target types.Target, opts scanner.Options) (*types.ScanResult, error) {
+	result := &types.ScanResult{
+		ScannerName: s.Name(),
+		Target:      target,
+		StartedAt:   time.Now(),
+	}
+
+	root, err := rootOf(target)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	p := newProber(root, opts)
+
+	// Closed and filtered ports only cost a timeout, so every service is
+	// probed at once.
+	var tasks []func(context.Context) []types.Finding
+	for _, svc := range services {
+		tasks = append(tasks, func(ctx context.Context) []types.Finding {
+			base := svc.scheme() + "://" + net.JoinHostPort(root.Hostname(), fmt.Sprint(svc.port))
+			return svc.probe(ctx, p, svc, base)
+		})
+	}
+	tasks = append(tasks, p.checkMetrics, p.checkMetadata)
+
+	findings := make([][]types.Finding, len(tasks))
+	var wg sync.WaitGroup
+	var done atomic.Int32
+	for i, task := range tasks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := opts.Gate.Wait(ctx); err != nil {
+				return
+			}
+			findings[i] = task(ctx)
+			opts.ReportProgress(s.Name(), int(done.Add(1)), len(tasks))
+		}()
+	}
+	wg.Wait()
+	for _, f := range findings {
+		result.Findings = append(result.Findings, f...)
+	}
+
+	result.CompletedAt = time.Now()
+	return result, nil
+}
+
+// rootOf returns the root URL of target.
+func rootOf(target types.Target) (*url.URL, error) {
+	raw := target.URL
+	if raw == "" {
+		if target.Host == "" {
+			return nil, fmt.Errorf("cannot determine URL for target %q", target.Host)
+		}
+		scheme := target.Scheme
+		if scheme == "" {
+			scheme = "https"
+		}
+		raw = scheme + "://" + target.URLHost()
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid target URL %q", raw)
+	}
+	return &url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/"}, nil
+}
+
+// prober sends the scanner's requests, over the scan's transport so they
+// are logged, rate limited, and pooled like any other scanner's.
+type prober struct {
+	root   *url.URL
+	opts   scanner.Options
+	client *http.Client
+	// insecure reaches the TLS services. Control-plane services mostly
+	// run with self-signed certificates, so they are not verified.
+	insecure *http.Client
+	// proxied sends requests through the target as a forward proxy.
+	proxied *http.Client
+}
+
+func newProber(root *url.URL, opts scanner.Options) *prober {
+	insecure := scanner.ConfigureTransport(opts.Transport, func(t *http.Transport) {
+		tlsConfig := &tls.Config{}
+		if t.TLSClientConfig != nil {
+			tlsConfig = t.TLSClientConfig.Clone()
+		}
+		tlsConfig.InsecureSkipVerify = true
+		t.TLSClientConfig = tlsConfig
+	})
+	proxied := scanner.ConfigureTransport(opts.Transport, func(t *http.Transport) {
+		t.Proxy = http.ProxyURL(root)
+	})
+	noRedirect := func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	return &prober{
+		root:     root,
+		opts:     opts,
+		client:   &http.Client{Timeout: opts.Timeout, Transport: opts.Transport, CheckRedirect: noRedirect},
+		insecure: &http.Client{Timeout: opts.Timeout, Transport: insecure, CheckRedirect: noRedirect},
+		proxied:  &http.Client{Timeout: opts.Timeout, Transport: proxied, CheckRedirect: noRedirect},
+	}
+}
+
+// response is a response to a probe.
+type response struct {
+	status   int
+	header   http.Header
+	body     string
+	artifact types.Artifact
+}
+
+// get sends req with client, after waiting on the scan's gate.
+func (p *prober) get(ctx context.Context, client *http.Client, req *http.Request) (*response, error) {
+	if err := p.opts.Gate.Wait(ctx); err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	return &response{
+		status:   resp.StatusCode,
+		header:   resp.Header,
+		body:     string(body),
+		artifact: scanner.NewArtifact(req, nil, resp, body),
+	}, nil
+}
+
+// getURL sends a GET for rawURL directly.
+func (p *prober) getURL(ctx context.Context, rawURL string) (*response, error) {
+	return p.getWith(ctx, p.client, rawURL)
+}
+
+// getService sends a GET for rawURL, on svc, directly. The certificates of
+// TLS services are not verified.
+func (p *prober) getService(ctx context.Context, svc service, rawURL string) (*response, error) {
+	client := p.client
+	if svc.tls {
+		client = p.insecure
+	}
+	return p.getWith(ctx, client, rawURL)
+}
+
+func (p *prober) getWith(ctx context.Context, client *http.Client, rawURL string) (*response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	return p.get(ctx, client, req)
+}
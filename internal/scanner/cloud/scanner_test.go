@@ -0,0 +1,225 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanner_NameAndDescription(t *testing.T) {
+	s := New()
+	assert.Equal(t, "cloud", s.Name())
+	assert.Equal(t, "Kubernetes and cloud metadata exposure checks", s.Description())
+}
+
+// withServices replaces the services probed for the rest of the test.
+func withServices(t *testing.T, svcs ...service) {
+	saved := services
+	services = svcs
+	t.Cleanup(func() { services = saved })
+}
+
+// portOf returns the port srv listens on.
+func portOf(t *testing.T, srv *httptest.Server) int {
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+	return port
+}
+
+// run scans a site serving site, with the services probed on its host,
+// and returns the findings.
+func run(t *testing.T, site http.HandlerFunc) []types.Finding {
+	srv := httptest.NewServer(site)
+	defer srv.Close()
+	result, err := New().Run(context.Background(), types.Target{URL: srv.URL + "/app"}, scanner.DefaultOptions())
+	require.NoError(t, err)
+	return result.Findings
+}
+
+func notFound(w http.ResponseWriter, r *http.Request) { http.NotFound(w, r) }
+
+func TestScanner_Kubelet(t *testing.T) {
+	anonymous := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/pods" {
+			fmt.Fprint(w, `{"kind":"PodList","apiVersion":"v1","items":[{"metadata":{"name":"web","uid":"1"}},{"metadata":{"name":"db","uid":"2"}}]}`)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer anonymous.Close()
+	withServices(t, service{name: "kubelet", port: portOf(t, anonymous), tls: true, probe: probeKubelet})
+	findings := run(t, notFound)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "Kubelet API allows anonymous access", findings[0].Title)
+	assert.Equal(t, types.SeverityCritical, findings[0].Severity)
+	assert.Contains(t, findings[0].Evidence, "/pods → 200, listing 2 pods")
+	assert.Equal(t, "kubelet", findings[0].Metadata["check"])
+
+	authenticated := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	}))
+	defer authenticated.Close()
+	withServices(t, service{name: "kubelet", port: portOf(t, authenticated), tls: true, probe: probeKubelet})
+	findings = run(t, notFound)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "Kubelet API exposed", findings[0].Title)
+	assert.Equal(t, types.SeverityHigh, findings[0].Severity)
+}
+
+func TestScanner_KubeletReadOnly(t *testing.T) {
+	readOnly := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"kind":"PodList","apiVersion":"v1","items":[]}`)
+	}))
+	defer readOnly.Close()
+	withServices(t, service{name: "kubelet read-only API", port: portOf(t, readOnly), probe: probeKubelet})
+	findings := run(t, notFound)
+	require.Len(t, findings, 1)
+	assert.Equal(t, types.SeverityHigh, findings[0].Severity)
+	assert.Contains(t, findings[0].Description, "read-only API")
+}
+
+func TestScanner_APIServer(t *testing.T) {
+	apiServer := func(anonymous bool) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/version":
+				fmt.Fprint(w, `{"major":"1","minor":"30","gitVersion":"v1.30.2","platform":"linux/amd64"}`)
+			case r.URL.Path == "/api/v1/namespaces" && anonymous:
+				fmt.Fprint(w, `{"kind":"NamespaceList","apiVersion":"v1","items":[]}`)
+			default:
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Forbidden","code":403}`)
+			}
+		}
+	}
+
+	open := httptest.NewTLSServer(apiServer(true))
+	defer open.Close()
+	withServices(t, service{name: "Kubernetes API server", port: portOf(t, open), tls: true, probe: probeAPIServer})
+	findings := run(t, notFound)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "Kubernetes API server allows anonymous access", findings[0].Title)
+	assert.Equal(t, types.SeverityCritical, findings[0].Severity)
+	assert.Contains(t, findings[0].Evidence, "Kubernetes v1.30.2")
+
+	closed := httptest.NewTLSServer(apiServer(false))
+	defer closed.Close()
+	withServices(t, service{name: "Kubernetes API server", port: portOf(t, closed), tls: true, probe: probeAPIServer})
+	findings = run(t, notFound)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "Kubernetes API server exposed", findings[0].Title)
+	assert.Equal(t, types.SeverityHigh, findings[0].Severity)
+
+	// A web server that is not an API server is not reported.
+	web := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"version":"2.1.0"}`)
+	}))
+	defer web.Close()
+	withServices(t, service{name: "Kubernetes API server", port: portOf(t, web), tls: true, probe: probeAPIServer})
+	assert.Empty(t, run(t, notFound))
+}
+
+func TestScanner_DockerAndEtcd(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		probe func(context.Context, *prober, service, string) []types.Finding
+		title string
+	}{
+		{"docker", `{"Version":"24.0.7","ApiVersion":"1.43","Os":"linux"}`, probeDocker, "Docker API exposed without authentication"},
+		{"etcd", `{"etcdserver":"3.5.9","etcdcluster":"3.5.0"}`, probeEtcd, "etcd exposed without authentication"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/version" {
+					http.NotFound(w, r)
+					return
+				}
+				fmt.Fprint(w, tt.body)
+			}))
+			defer srv.Close()
+			withServices(t, service{name: tt.name, port: portOf(t, srv), probe: tt.probe})
+			findings := run(t, notFound)
+			require.Len(t, findings, 1)
+			assert.Equal(t, tt.title, findings[0].Title)
+			assert.Equal(t, types.SeverityCritical, findings[0].Severity)
+			assert.Equal(t, tt.name, findings[0].Metadata["check"])
+		})
+	}
+}
+
+func TestScanner_Metrics(t *testing.T) {
+	withServices(t)
+	metrics := func(body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/metrics" {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprint(w, body)
+		}
+	}
+
+	findings := run(t, metrics("# HELP http_requests_total Requests.\n# TYPE http_requests_total counter\nhttp_requests_total 12\n"))
+	require.Len(t, findings, 1)
+	assert.Equal(t, "Prometheus metrics exposed", findings[0].Title)
+	assert.Equal(t, types.SeverityMedium, findings[0].Severity)
+
+	findings = run(t, metrics("# HELP apiserver_request_total Requests.\n# TYPE apiserver_request_total counter\napiserver_request_total{verb=\"GET\"} 3\n"))
+	require.Len(t, findings, 1)
+	assert.Equal(t, "Kubernetes control-plane metrics exposed", findings[0].Title)
+	assert.Equal(t, types.SeverityHigh, findings[0].Severity)
+	assert.Contains(t, findings[0].Evidence, "apiserver_request_total")
+
+	assert.Empty(t, run(t, metrics("ok")))
+}
+
+func TestScanner_Metadata(t *testing.T) {
+	withServices(t)
+	metadata := func(proxyOnly bool) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			absolute := strings.HasPrefix(r.RequestURI, "http://")
+			if r.Host == "169.254.169.254" && r.URL.Path == "/latest/meta-data/" && (absolute || !proxyOnly) {
+				fmt.Fprint(w, "ami-id\nhostname\niam/\ninstance-id\n")
+				return
+			}
+			http.NotFound(w, r)
+		}
+	}
+
+	findings := run(t, metadata(false))
+	require.Len(t, findings, 2)
+	assert.Equal(t, "proxy", findings[0].Metadata["via"])
+	assert.Equal(t, "host", findings[1].Metadata["via"])
+	for _, f := range findings {
+		assert.Equal(t, "Cloud metadata reachable through the target", f.Title)
+		assert.Equal(t, types.SeverityCritical, f.Severity)
+		assert.Equal(t, "AWS", f.Metadata["provider"])
+	}
+	assert.Contains(t, findings[1].Evidence, "with Host: 169.254.169.254")
+
+	findings = run(t, metadata(true))
+	require.Len(t, findings, 1)
+	assert.Equal(t, "proxy", findings[0].Metadata["via"])
+}
+
+func TestScanner_NothingExposed(t *testing.T) {
+	closed := httptest.NewServer(http.HandlerFunc(notFound))
+	port := portOf(t, closed)
+	closed.Close()
+	withServices(t, service{name: "Docker API", port: port, probe: probeDocker})
+	assert.Empty(t, run(t, func(w http.ResponseWriter, r *http.Request) { fmt.Fprint(w, "<html>hello</html>") }))
+}
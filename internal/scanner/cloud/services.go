@@ -0,0 +1,247 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// service is a control-plane service probed on the target's host.
+type service struct {
+	// name is the service as findings show it, such as "kubelet".
+	name string
+	port int
+	tls  bool
+	// probe checks the service at base, its scheme, host, and port.
+	probe func(ctx context.Context, p *prober, svc service, base string) []types.Finding
+}
+
+func (s service) scheme() string {
+	if s.tls {
+		return "https"
+	}
+	return "http"
+}
+
+// services are the services probed, on their default ports. Extracted as
+// a variable for testing.
+var services = []service{
+	{name: "kubelet", port: 10250, tls: true, probe: probeKubelet},
+	{name: "kubelet read-only API", port: 10255, probe: probeKubelet},
+	{name: "Kubernetes API server", port: 6443, tls: true, probe: probeAPIServer},
+	{name: "Kubernetes API server insecure port", port: 8080, probe: probeAPIServer},
+	{name: "etcd", port: 2379, probe: probeEtcd},
+	{name: "Docker API", port: 2375, probe: probeDocker},
+}
+
+// finding returns a finding about svc, reached at rawURL.
+func (svc service) finding(check, rawURL string, f types.Finding) types.Finding {
+	f.Metadata = map[string]string{
+		"check":   check,
+		"service": svc.name,
+		"port":    strconv.Itoa(svc.port),
+		"url":     rawURL,
+	}
+	return f
+}
+
+// probeKubelet lists the pods of the kubelet at base. A kubelet that lists
+// them anonymously also runs commands in their containers for anyone; the
+// read-only port lists them, with their environment, to anyone by design.
+// A kubelet that refuses the request is still reported, as it is the
+// control plane reachable from outside the cluster.
+func probeKubelet(ctx context.Context, p *prober, svc service, base string) []types.Finding {
+	pods := base + "/pods"
+	resp, err := p.getService(ctx, svc, pods)
+	if err != nil {
+		return nil
+	}
+	evidence := fmt.Sprintf("GET %s → %d", pods, resp.status)
+	switch {
+	case resp.status == http.StatusOK && strings.Contains(resp.body, `"PodList"`):
+		severity := types.SeverityCritical
+		description := fmt.Sprintf("The kubelet on port %d lists the pods of its node to anonymous requests. Its API also lets anyone run commands in those containers (/run, /exec), which gives control of the node and the credentials of every pod on it.", svc.port)
+		if !svc.tls {
+			severity = types.SeverityHigh
+			description = fmt.Sprintf("The kubelet read-only API on port %d lists the pods of its node, with their images, commands, and environment variables, to anyone who can reach it.", svc.port)
+		}
+		return []types.Finding{svc.finding("kubelet", pods, types.Finding{
+			Title:       "Kubelet API allows anonymous access",
+			Description: description,
+			Severity:    severity,
+			Evidence:    fmt.Sprintf("%s, listing %d pods", evidence, strings.Count(resp.body, `"uid"`)),
+			Remediation: "Start the kubelet with --anonymous-auth=false, --authorization-mode=Webhook, and --read-only-port=0, and keep ports 10250 and 10255 unreachable from outside the cluster network.",
+			References: []types.Reference{
+				{Title: "Kubernetes: Kubelet authentication/authorization", URL: "https://kubernetes.io/docs/reference/access-authn-authz/kubelet-authn-authz/"},
+			},
+			Artifacts: []types.Artifact{resp.artifact},
+		})}
+	case resp.status == http.StatusUnauthorized && strings.TrimSpace(resp.body) == "Unauthorized",
+		resp.status == http.StatusForbidden && strings.Contains(resp.body, "system:anonymous"):
+		return []types.Finding{svc.finding("kubelet", pods, types.Finding{
+			Title:       "Kubelet API exposed",
+			Description: fmt.Sprintf("A kubelet answers on port %d. It refused the anonymous request, but the API of a node agent should not be reachable from outside the cluster, where stolen credentials or a kubelet vulnerability give control of the node.", svc.port),
+			Severity:    types.SeverityHigh,
+			Evidence:    evidence,
+			Remediation: "Keep port 10250 unreachable from outside the cluster network.",
+			References: []types.Reference{
+				{Title: "Kubernetes: Kubelet authentication/authorization", URL: "https://kubernetes.io/docs/reference/access-authn-authz/kubelet-authn-authz/"},
+			},
+			Artifacts: []types.Artifact{resp.artifact},
+		})}
+	}
+	return nil
+}
+
+// kubeVersion is the body of the API server's /version.
+type kubeVersion struct {
+	GitVersion string `json:"gitVersion"`
+	Platform   string `json:"platform"`
+}
+
+// probeAPIServer asks the Kubernetes API server at base for its version,
+// then for its namespaces, which only an API server that authorizes
+// anonymous requests (or the long-removed insecure port) lists.
+func probeAPIServer(ctx context.Context, p *prober, svc service, base string) []types.Finding {
+	versionURL := base + "/version"
+	resp, err := p.getService(ctx, svc, versionURL)
+	if err != nil {
+		return nil
+	}
+	var version kubeVersion
+	switch {
+	case resp.status == http.StatusOK && json.Unmarshal([]byte(resp.body), &version) == nil && version.GitVersion != "" && version.Platform != "":
+	case (resp.status == http.StatusUnauthorized || resp.status == http.StatusForbidden) && strings.Contains(resp.body, `"kind":"Status"`):
+	default:
+		return nil
+	}
+	evidence := fmt.Sprintf("GET %s → %d", versionURL, resp.status)
+	if version.GitVersion != "" {
+		evidence += " (Kubernetes " + version.GitVersion + ")"
+	}
+	references := []types.Reference{
+		{Title: "Kubernetes: Controlling Access to the Kubernetes API", URL: "https://kubernetes.io/docs/concepts/security/controlling-access/"},
+	}
+
+	namespacesURL := base + "/api/v1/namespaces"
+	if ns, err := p.getService(ctx, svc, namespacesURL); err == nil && ns.status == http.StatusOK && strings.Contains(ns.body, `"NamespaceList"`) {
+		return []types.Finding{svc.finding("kube-apiserver", namespacesURL, types.Finding{
+			Title:       "Kubernetes API server allows anonymous access",
+			Description: fmt.Sprintf("The Kubernetes API server on port %d lists the cluster's namespaces without credentials. Anonymous requests it authorizes to read, and likely to change, cluster resources give access to its secrets and workloads.", svc.port),
+			Severity:    types.SeverityCritical,
+			Evidence:    fmt.Sprintf("%s; GET %s → %d", evidence, namespacesURL, ns.status),
+			Remediation: "Start the API server with --anonymous-auth=false, remove RBAC bindings granting anything to system:anonymous or system:unauthenticated, and keep it off the public internet or behind an allowlist.",
+			References:  references,
+			Artifacts:   []types.Artifact{resp.artifact, ns.artifact},
+		})}
+	}
+	return []types.Finding{svc.finding("kube-apiserver", versionURL, types.Finding{
+		Title:       "Kubernetes API server exposed",
+		Description: fmt.Sprintf("A Kubernetes API server answers on port %d. It did not list resources anonymously, but a cluster's control plane reachable from outside its network is one stolen token or API server vulnerability away from compromise.", svc.port),
+		Severity:    types.SeverityHigh,
+		Evidence:    evidence,
+		Remediation: "Keep the API server off the public internet, or restrict it to known networks, e.g. with authorized networks on managed clusters.",
+		References:  references,
+		Artifacts:   []types.Artifact{resp.artifact},
+	})}
+}
+
+// probeEtcd asks the etcd server at base for its version. etcd holds the
+// whole state of a Kubernetes cluster, secrets included, and one that
+// answers over plain HTTP does not check client certificates.
+func probeEtcd(ctx context.Context, p *prober, svc service, base string) []types.Finding {
+	versionURL := base + "/version"
+	resp, err := p.getService(ctx, svc, versionURL)
+	if err != nil || resp.status != http.StatusOK {
+		return nil
+	}
+	var version struct {
+		Server string `json:"etcdserver"`
+	}
+	if json.Unmarshal([]byte(resp.body), &version) != nil || version.Server == "" {
+		return nil
+	}
+	return []types.Finding{svc.finding("etcd", versionURL, types.Finding{
+		Title:       "etcd exposed without authentication",
+		Description: fmt.Sprintf("An etcd server answers plain HTTP on port %d without a client certificate. etcd stores the whole state of the cluster it backs, including every Kubernetes secret, and anyone who can reach it can read and rewrite it.", svc.port),
+		Severity:    types.SeverityCritical,
+		Evidence:    fmt.Sprintf("GET %s → %d (etcd %s)", versionURL, resp.status, version.Server),
+		Remediation: "Serve etcd over TLS with --client-cert-auth=true, and keep it reachable only from the API servers.",
+		References: []types.Reference{
+			{Title: "etcd: Transport security model", URL: "https://etcd.io/docs/latest/op-guide/security/"},
+		},
+		Artifacts: []types.Artifact{resp.artifact},
+	})}
+}
+
+// probeDocker asks the Docker API at base for its version. Anyone who can
+// reach an unauthenticated Docker API can start a privileged container
+// mounting the host's filesystem.
+func probeDocker(ctx context.Context, p *prober, svc service, base string) []types.Finding {
+	versionURL := base + "/version"
+	resp, err := p.getService(ctx, svc, versionURL)
+	if err != nil || resp.status != http.StatusOK {
+		return nil
+	}
+	var version struct {
+		Version    string `json:"Version"`
+		APIVersion string `json:"ApiVersion"`
+	}
+	if json.Unmarshal([]byte(resp.body), &version) != nil || version.APIVersion == "" {
+		return nil
+	}
+	return []types.Finding{svc.finding("docker", versionURL, types.Finding{
+		Title:       "Docker API exposed without authentication",
+		Description: fmt.Sprintf("The Docker Engine API answers plain HTTP on port %d. Anyone who can reach it can run a privileged container that mounts the host's filesystem, which gives root on the host.", svc.port),
+		Severity:    types.SeverityCritical,
+		Evidence:    fmt.Sprintf("GET %s → %d (Docker %s, API %s)", versionURL, resp.status, version.Version, version.APIVersion),
+		Remediation: "Stop listening on TCP, or protect the socket with TLS client certificates (--tlsverify) on port 2376, and keep it unreachable from untrusted networks.",
+		References: []types.Reference{
+			{Title: "Docker: Protect the Docker daemon socket", URL: "https://docs.docker.com/engine/security/protect-access/"},
+		},
+		Artifacts: []types.Artifact{resp.artifact},
+	})}
+}
+
+// controlPlaneMetric matches metrics exported by Kubernetes components and
+// etcd.
+var controlPlaneMetric = regexp.MustCompile(`(?m)^(apiserver|etcd|kubelet|kube|scheduler)_\w+`)
+
+// checkMetrics fetches /metrics on the target. Prometheus metrics tell an
+// attacker about the software, traffic, and internals behind it; those of
+// Kubernetes components describe the cluster itself.
+func (p *prober) checkMetrics(ctx context.Context) []types.Finding {
+	metricsURL := p.root.JoinPath("metrics").String()
+	resp, err := p.getURL(ctx, metricsURL)
+	if err != nil || resp.status != http.StatusOK || !strings.Contains(resp.body, "# TYPE ") {
+		return nil
+	}
+	f := types.Finding{
+		Title:       "Prometheus metrics exposed",
+		Description: "The target serves Prometheus metrics at /metrics to anyone. They reveal the software behind it, its versions, internal names, and traffic.",
+		Severity:    types.SeverityMedium,
+		Evidence:    fmt.Sprintf("GET %s → %d, %d metric types", metricsURL, resp.status, strings.Count(resp.body, "# TYPE ")),
+		Remediation: "Serve metrics on a separate port or path reachable only by the monitoring system, or require authentication for them.",
+		References: []types.Reference{
+			{Title: "Prometheus: Security model", URL: "https://prometheus.io/docs/operating/security/"},
+		},
+		Metadata: map[string]string{
+			"check": "metrics",
+			"url":   metricsURL,
+		},
+		Artifacts: []types.Artifact{resp.artifact},
+	}
+	if m := controlPlaneMetric.FindString(resp.body); m != "" {
+		f.Title = "Kubernetes control-plane metrics exposed"
+		f.Description = "The target serves the Prometheus metrics of a Kubernetes component at /metrics to anyone. They describe the cluster: its nodes, workloads, request paths, and the versions of its components."
+		f.Severity = types.SeverityHigh
+		f.Evidence += ", including " + m
+	}
+	return []types.Finding{f}
+}
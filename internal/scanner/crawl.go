@@ -0,0 +1,117 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/buemura/hunter/internal/crawl"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// Crawler spiders the target once before its first scanner runs, for the
+// pages, links with parameters, and forms of the whole application. Set
+// Options.Crawler to a Crawler to have the Runner add what it finds to
+// Options.Endpoints for every scanner; it may be shared by every scanner of
+// one scan.
+type Crawler struct {
+	opts crawl.Options
+
+	once      sync.Once
+	endpoints []types.Endpoint
+}
+
+// NewCrawler returns a Crawler fetching at most maxPages pages up to
+// maxDepth links from the target; zero uses the crawl package's defaults.
+func NewCrawler(maxDepth, maxPages int) *Crawler {
+	return &Crawler{opts: crawl.Options{MaxDepth: maxDepth, MaxPages: maxPages}}
+}
+
+// Fresh returns a Crawler with c's settings that has not crawled yet, or
+// nil if c is nil.
+func (c *Crawler) Fresh() *Crawler {
+	if c == nil {
+		return nil
+	}
+	return &Crawler{opts: c.opts}
+}
+
+// Endpoints crawls the target on first use and returns the requests found;
+// later calls return the same ones. The crawl starts from the target's URL,
+// or where the pre-flight probe, if any, ended up on an authorized host.
+func (c *Crawler) Endpoints(ctx context.Context, target types.Target, preflight *PreflightResult, opts Options) []types.Endpoint {
+	c.once.Do(func() {
+		start := crawlStart(target, preflight, opts)
+		if start == "" {
+			return
+		}
+
+		timeout := opts.Timeout
+		if timeout == 0 {
+			timeout = 5 * time.Second
+		}
+		copts := c.opts
+		copts.Client = &http.Client{Timeout: timeout, Transport: opts.HTTPTransport()}
+
+		endpoints, err := crawl.Crawl(ctx, start, copts)
+		if err != nil {
+			opts.Logf("crawl", LogWarn, "crawling %s: %v", start, err)
+			return
+		}
+		opts.Logf("crawl", LogInfo, "found %d requests to test from %s", len(endpoints), start)
+		c.endpoints = endpoints
+	})
+	return c.endpoints
+}
+
+// crawlStart returns the URL a crawl of target starts from. Where the
+// pre-flight probe ended up is only followed if it is on the target's host
+// or opts.Authorize accepts its host: a redirect to a single sign-on
+// provider or a CDN must not make the crawl spider a host no one named.
+func crawlStart(target types.Target, preflight *PreflightResult, opts Options) string {
+	switch {
+	case target.URL != "":
+		return target.URL
+	case target.Host == "":
+		return ""
+	}
+	scheme := target.Scheme
+	if preflight != nil {
+		if preflight.FinalURL != "" && mayCrawl(target, preflight.FinalURL, opts) {
+			return preflight.FinalURL
+		}
+		if scheme == "" {
+			scheme = preflight.Scheme()
+		}
+	}
+	if scheme == "" {
+		scheme = "https"
+	}
+	return scheme + "://" + target.URLHost() + "/"
+}
+
+// mayCrawl reports whether a crawl of target may start at rawURL.
+func mayCrawl(target types.Target, rawURL string, opts Options) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	if strings.EqualFold(u.Hostname(), target.Host) {
+		return true
+	}
+	if opts.Authorize == nil {
+		return false
+	}
+	other, err := types.ParseTarget(rawURL)
+	if err != nil {
+		return false
+	}
+	if err := opts.Authorize(other); err != nil {
+		opts.Logf("crawl", LogInfo, "not crawling from %s: %v", rawURL, err)
+		return false
+	}
+	return true
+}
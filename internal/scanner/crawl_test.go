@@ -0,0 +1,110 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// endpointsScanner records the endpoints it was given.
+type endpointsScanner struct {
+	mockScanner
+	mu        sync.Mutex
+	endpoints []types.Endpoint
+}
+
+func (s *endpointsScanner) Run(ctx context.Context, target types.Target, opts Options) (*types.ScanResult, error) {
+	s.mu.Lock()
+	s.endpoints = opts.Endpoints
+	s.mu.Unlock()
+	return s.mockScanner.Run(ctx, target, opts)
+}
+
+func TestRunner_CrawlerAddsEndpoints(t *testing.T) {
+	var home atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			home.Add(1)
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<a href="/search?q=x">Search</a>`)
+	}))
+	defer srv.Close()
+
+	target, err := types.ParseTarget(srv.URL)
+	require.NoError(t, err)
+
+	first := &endpointsScanner{mockScanner: mockScanner{name: "first"}}
+	second := &endpointsScanner{mockScanner: mockScanner{name: "second"}}
+	reg := NewRegistry()
+	reg.Register(first)
+	reg.Register(second)
+
+	imported := types.Endpoint{Method: http.MethodGet, URL: "/api/users"}
+	opts := Options{Concurrency: 2, Timeout: 2 * time.Second, Crawler: NewCrawler(0, 0), Endpoints: []types.Endpoint{imported}}
+	NewRunner(reg).RunAll(context.Background(), []string{"first", "second"}, target, opts)
+
+	want := []types.Endpoint{
+		imported,
+		{Method: http.MethodGet, URL: srv.URL + "/"},
+		{Method: http.MethodGet, URL: srv.URL + "/search?q=x"},
+	}
+	assert.Equal(t, want, first.endpoints)
+	assert.Equal(t, want, second.endpoints)
+	assert.Equal(t, int32(1), home.Load(), "the target is crawled once per scan")
+	assert.Len(t, opts.Endpoints, 1, "the caller's endpoints are left alone")
+}
+
+func TestRunner_PassiveDoesNotCrawl(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer srv.Close()
+
+	target, err := types.ParseTarget(srv.URL)
+	require.NoError(t, err)
+
+	reg := NewRegistry()
+	reg.Register(&passiveMock{mockScanner: mockScanner{name: "recon"}})
+	opts := Options{Timeout: 2 * time.Second, Crawler: NewCrawler(0, 0), Passive: true}
+	_, err = NewRunner(reg).RunOne(context.Background(), "recon", target, opts)
+	require.NoError(t, err)
+	assert.Zero(t, requests.Load())
+}
+
+func TestCrawlStart(t *testing.T) {
+	opts := DefaultOptions()
+	assert.Equal(t, "http://example.com/app", crawlStart(types.Target{URL: "http://example.com/app", Host: "example.com"}, nil, opts))
+	assert.Equal(t, "https://example.com/home", crawlStart(types.Target{Host: "example.com"}, &PreflightResult{FinalURL: "https://example.com/home"}, opts))
+	assert.Equal(t, "https://example.com/", crawlStart(types.Target{Host: "example.com"}, nil, opts))
+	assert.Equal(t, "", crawlStart(types.Target{}, nil, opts))
+}
+
+func TestCrawlStart_OffHostRedirect(t *testing.T) {
+	target := types.Target{Host: "example.com"}
+	preflight := &PreflightResult{HTTPS: true, FinalURL: "https://sso.example.net/login"}
+
+	opts := DefaultOptions()
+	assert.Equal(t, "https://example.com/", crawlStart(target, preflight, opts), "an off-host redirect is not followed without an authorizer")
+
+	opts.Authorize = func(target types.Target) error {
+		if target.Host != "sso.example.net" {
+			return fmt.Errorf("%s is not in authorized_targets", target.Host)
+		}
+		return nil
+	}
+	assert.Equal(t, "https://sso.example.net/login", crawlStart(target, preflight, opts))
+
+	preflight.FinalURL = "https://cdn.example.org/"
+	assert.Equal(t, "https://example.com/", crawlStart(target, preflight, opts))
+}
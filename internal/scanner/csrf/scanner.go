@@ -0,0 +1,429 @@
+package csrf
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/internal/crawl"
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// foreignOrigin is the Origin and Referer replays claim, as a request sent
+// from another site's page would.
+const foreignOrigin = "https://csrf.hunter.invalid"
+
+// unsafeWords in a request's path mark actions not replayed: those that
+// delete data, or end or reset the session or account being scanned with.
+var unsafeWords = []string{"delete", "remove", "destroy", "logout", "logoff", "signout", "sign-out", "deactivate", "unsubscribe", "reset"}
+
+// sessionCookieName matches the names of cookies likely to carry a session.
+var sessionCookieName = regexp.MustCompile(`(?i)sess|sid|auth|token|jwt|login|remember|user`)
+
+// Scanner checks the target's state-changing requests that other sites can
+// forge, its POST forms and the imported and crawled POST requests a form
+// could send, for anti-CSRF tokens, and its session cookies for SameSite
+// protection.
+type Scanner struct{}
+
+// New creates a new CSRF scanner.
+func New() *Scanner {
+	return &Scanner{}
+}
+
+func (s *Scanner) Name() string        { return "csrf" }
+func (s *Scanner) Description() string { return "CSRF protection detection" }
+
+// request is a state-changing request under test.
+type request struct {
+	endpoint types.Endpoint
+	// page is the URL of the page of a form, "" for other requests.
+	page string
+	// token is the name of the request's anti-CSRF token field or header,
+	// "" when it has none.
+	token    string
+	artifact *types.Artifact
+}
+
+// Run collects the POST forms on the target's page and on the pages of
+// Options.Endpoints, and the state-changing requests among those, and
+// reports the ones another site could make a logged-in browser send: those
+// without a token, and, replaying them from another origin where safe, those
+// whose token the server does not check.
+func (s *Scanner) Run(ctx context.Context, target types.Target, opts scanner.Options) (*types.ScanResult, error) {
+	result := &types.ScanResult{
+		ScannerName: s.Name(),
+		Target:      target,
+		StartedAt:   time.Now(),
+	}
+
+	targetURL := resolveURL(target)
+	if targetURL == "" {
+		return nil, fmt.Errorf("cannot determine URL for target %q", target.Host)
+	}
+
+	client := newClient(opts)
+	endpoints := opts.EndpointsOn(targetURL)
+	var requests []request
+	var cookies []*http.Cookie
+	seen := map[string]bool{}
+	for _, page := range pages(targetURL, endpoints) {
+		if ctx.Err() != nil {
+			break
+		}
+		forms, set, artifact, err := fetchPage(ctx, client, page)
+		if err != nil {
+			if page == targetURL {
+				return nil, err
+			}
+			continue
+		}
+		cookies = addCookies(cookies, set)
+		for _, form := range forms {
+			ep := form.Endpoint()
+			if form.Method != http.MethodPost || seen[requestKey(ep)] {
+				continue
+			}
+			seen[requestKey(ep)] = true
+			token, _ := form.CSRFToken()
+			requests = append(requests, request{endpoint: ep, page: form.Page, token: token.Name, artifact: &artifact})
+		}
+	}
+	for _, ep := range endpoints {
+		if !crossSite(ep) || seen[requestKey(ep)] {
+			continue
+		}
+		seen[requestKey(ep)] = true
+		requests = append(requests, request{endpoint: ep, token: endpointToken(ep)})
+	}
+	opts.Logf(s.Name(), scanner.LogInfo, "testing %d state-changing requests", len(requests))
+
+	exposed, protected := sessionCookies(cookies)
+	replays := opts.Budget("csrf.replays")
+	for _, req := range requests {
+		if ctx.Err() != nil {
+			break
+		}
+		replay := replays != 0 && !unsafe(req.endpoint)
+		if replay && replays > 0 {
+			replays--
+		}
+
+		if req.token == "" {
+			if f, ok := checkMissingToken(ctx, client, req, replay, exposed, protected); ok {
+				result.Findings = append(result.Findings, f)
+			}
+			continue
+		}
+		if !replay {
+			continue
+		}
+		if f, ok := checkTokenValidated(ctx, client, req); ok {
+			result.Findings = append(result.Findings, f)
+		}
+	}
+
+	result.CompletedAt = time.Now()
+	return result, nil
+}
+
+// checkMissingToken reports req, which carries no token. Replaying it from
+// another origin tells whether the server blocks cross-site requests some
+// other way, such as by checking Origin: a refused replay is not reported,
+// and an accepted one is HIGH unless the session cookies are SameSite.
+func checkMissingToken(ctx context.Context, client *http.Client, req request, replay bool, exposed, protected []string) (types.Finding, bool) {
+	f := types.Finding{
+		Title:       "Missing CSRF protection",
+		Description: "A state-changing request carries no anti-CSRF token, so another site may be able to make a logged-in user's browser send it.",
+		Severity:    types.SeverityMedium,
+		Evidence:    fmt.Sprintf("%s %s has no anti-CSRF token", req.endpoint.Method, req.endpoint.URL),
+		Remediation: "Add a per-session or per-request anti-CSRF token to the request and check it on the server, or use your framework's CSRF protection. Set SameSite=Lax or Strict on session cookies as well.",
+		Metadata:    metadata(req, "missing-token"),
+	}
+	if req.page != "" {
+		f.Evidence = fmt.Sprintf("Form on %s posting to %s has no anti-CSRF token", req.page, req.endpoint.URL)
+	}
+	if req.artifact != nil {
+		f.Artifacts = append(f.Artifacts, *req.artifact)
+	}
+
+	if replay {
+		status, artifact, err := send(ctx, client, req.endpoint, true)
+		if err != nil {
+			return types.Finding{}, false
+		}
+		if status >= 400 {
+			// Refused from another origin.
+			return types.Finding{}, false
+		}
+		f.Evidence += fmt.Sprintf("; a replay with Origin %s was accepted with status %d", foreignOrigin, status)
+		f.Metadata["replay_status"] = fmt.Sprint(status)
+		f.Artifacts = append(f.Artifacts, artifact)
+		if len(protected) == 0 || len(exposed) > 0 {
+			f.Severity = types.SeverityHigh
+		}
+	}
+
+	switch {
+	case len(exposed) > 0:
+		f.Evidence += "; session cookies without SameSite=Lax or Strict: " + strings.Join(exposed, ", ")
+	case len(protected) > 0:
+		f.Evidence += "; session cookies are SameSite, which blocks this request from other sites in current browsers: " + strings.Join(protected, ", ")
+	}
+	return f, true
+}
+
+// checkTokenValidated replays req without its token from another origin and
+// reports it when the server answers as it does to the request with the
+// token.
+func checkTokenValidated(ctx context.Context, client *http.Client, req request) (types.Finding, bool) {
+	forged := withoutToken(req.endpoint, req.token)
+	status, artifact, err := send(ctx, client, forged, true)
+	if err != nil || status >= 400 {
+		return types.Finding{}, false
+	}
+	baseline, _, err := send(ctx, client, req.endpoint, false)
+	if err != nil || baseline != status {
+		return types.Finding{}, false
+	}
+
+	f := types.Finding{
+		Title:       "CSRF token not validated",
+		Description: "The request carries an anti-CSRF token, but the server accepts the request without it, so the token protects nothing.",
+		Severity:    types.SeverityHigh,
+		Evidence:    fmt.Sprintf("%s %s without its %q token and with Origin %s returned %d, as it does with the token", req.endpoint.Method, req.endpoint.URL, req.token, foreignOrigin, status),
+		Remediation: "Reject state-changing requests whose anti-CSRF token is missing or does not match the user's session.",
+		Metadata:    metadata(req, "unvalidated-token"),
+	}
+	f.Metadata["token"] = req.token
+	f.Metadata["replay_status"] = fmt.Sprint(status)
+	if req.artifact != nil {
+		f.Artifacts = append(f.Artifacts, *req.artifact)
+	}
+	f.Artifacts = append(f.Artifacts, artifact)
+	return f, true
+}
+
+// metadata returns the metadata locating req, for a finding of check.
+func metadata(req request, check string) map[string]string {
+	m := map[string]string{
+		"check":  check,
+		"method": req.endpoint.Method,
+		"url":    req.endpoint.URL,
+	}
+	if req.page != "" {
+		m["page"] = req.page
+	}
+	return m
+}
+
+// requestKey identifies a request by where and how it is sent: a form and
+// the crawler's request for it are the same.
+func requestKey(ep types.Endpoint) string {
+	return ep.Method + " " + ep.URL
+}
+
+// crossSite reports whether ep is a state-changing request another site's
+// page could make a browser send without a CORS preflight: a POST with a
+// form-encoded, multipart, or text body, or none. PUT, PATCH, DELETE, and
+// JSON bodies need one.
+func crossSite(ep types.Endpoint) bool {
+	if ep.Method != http.MethodPost {
+		return false
+	}
+	mediaType, _, _ := mime.ParseMediaType(header(ep, "Content-Type"))
+	switch mediaType {
+	case "", "application/x-www-form-urlencoded", "multipart/form-data", "text/plain":
+		return true
+	}
+	return false
+}
+
+// endpointToken returns the name of ep's anti-CSRF header or form field, or
+// "" when it has none.
+func endpointToken(ep types.Endpoint) string {
+	for name := range ep.Headers {
+		if crawl.IsCSRFTokenName(name) {
+			return name
+		}
+	}
+	if fields, err := url.ParseQuery(ep.Body); err == nil {
+		for name := range fields {
+			if crawl.IsCSRFTokenName(name) {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// withoutToken returns ep without the header or form field named token.
+func withoutToken(ep types.Endpoint, token string) types.Endpoint {
+	forged := ep
+	forged.Headers = map[string]string{}
+	for name, value := range ep.Headers {
+		if name != token {
+			forged.Headers[name] = value
+		}
+	}
+	if fields, err := url.ParseQuery(ep.Body); err == nil && fields.Has(token) {
+		fields.Del(token)
+		forged.Body = fields.Encode()
+	}
+	return forged
+}
+
+// unsafe reports whether replaying ep could delete data or end the session.
+func unsafe(ep types.Endpoint) bool {
+	u, err := url.Parse(ep.URL)
+	if err != nil {
+		return true
+	}
+	lower := strings.ToLower(u.Path)
+	for _, word := range unsafeWords {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// sessionCookies returns the names of the session cookies among cookies
+// that browsers send on requests from other sites, and of those they do not.
+// Cookies without a SameSite attribute are taken as sent: only some
+// browsers default them to Lax.
+func sessionCookies(cookies []*http.Cookie) (exposed, protected []string) {
+	for _, c := range cookies {
+		if !sessionCookieName.MatchString(c.Name) && !c.HttpOnly {
+			continue
+		}
+		if c.SameSite == http.SameSiteLaxMode || c.SameSite == http.SameSiteStrictMode {
+			protected = append(protected, c.Name)
+		} else {
+			exposed = append(exposed, c.Name)
+		}
+	}
+	return exposed, protected
+}
+
+// addCookies adds the cookies in set not already in cookies, by name.
+func addCookies(cookies, set []*http.Cookie) []*http.Cookie {
+	for _, c := range set {
+		if !slices.ContainsFunc(cookies, func(o *http.Cookie) bool { return o.Name == c.Name }) {
+			cookies = append(cookies, c)
+		}
+	}
+	return cookies
+}
+
+// send sends ep, as if from a page on foreignOrigin when foreign is set, and
+// returns the response's status without following redirects.
+func send(ctx context.Context, client *http.Client, ep types.Endpoint, foreign bool) (int, types.Artifact, error) {
+	req, err := http.NewRequestWithContext(ctx, ep.Method, ep.URL, strings.NewReader(ep.Body))
+	if err != nil {
+		return 0, types.Artifact{}, err
+	}
+	for name, value := range ep.Headers {
+		req.Header.Set(name, value)
+	}
+	if foreign {
+		req.Header.Set("Origin", foreignOrigin)
+		req.Header.Set("Referer", foreignOrigin+"/")
+	}
+
+	noRedirect := *client
+	noRedirect.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	resp, err := noRedirect.Do(req)
+	if err != nil {
+		return 0, types.Artifact{}, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	return resp.StatusCode, scanner.NewArtifact(req, []byte(ep.Body), resp, body), nil
+}
+
+// fetchPage requests page and returns the forms on it, the cookies it set,
+// and the exchange as an artifact. Pages that are not HTML have no forms.
+func fetchPage(ctx context.Context, client *http.Client, page string) ([]crawl.Form, []*http.Cookie, types.Artifact, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, page, nil)
+	if err != nil {
+		return nil, nil, types.Artifact{}, fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, types.Artifact{}, fmt.Errorf("HTTP GET %s: %w", page, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20)) // 1 MB limit
+	if err != nil {
+		return nil, nil, types.Artifact{}, err
+	}
+	artifact := scanner.NewArtifact(req, nil, resp, body)
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, resp.Cookies(), artifact, nil
+	}
+	return crawl.ParseForms(resp.Request.URL.String(), string(body)), resp.Cookies(), artifact, nil
+}
+
+// header returns the value of ep's header name, matched case-insensitively.
+func header(ep types.Endpoint, name string) string {
+	for k, v := range ep.Headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+// pages returns the target URL and the distinct URLs of the GET requests in
+// endpoints.
+func pages(targetURL string, endpoints []types.Endpoint) []string {
+	pages := []string{targetURL}
+	seen := map[string]bool{targetURL: true}
+	for _, ep := range endpoints {
+		if ep.Method == http.MethodGet && !seen[ep.URL] {
+			seen[ep.URL] = true
+			pages = append(pages, ep.URL)
+		}
+	}
+	return pages
+}
+
+// newClient returns a client keeping the cookies the target sets, so that
+// forms are submitted in the session their tokens belong to.
+func newClient(opts scanner.Options) *http.Client {
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	jar, _ := cookiejar.New(nil) // only fails on bad options
+	return &http.Client{Timeout: timeout, Transport: opts.HTTPTransport(), Jar: jar}
+}
+
+// resolveURL determines the target URL from the Target struct.
+func resolveURL(target types.Target) string {
+	if target.URL != "" {
+		return target.URL
+	}
+	scheme := target.Scheme
+	if scheme == "" {
+		scheme = "https"
+	}
+	if target.Host == "" {
+		return ""
+	}
+	return scheme + "://" + target.URLHost()
+}
@@ -0,0 +1,196 @@
+package csrf
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// app serves page on GET, with cookie set when there is one, and records the
+// POSTs made to it. accept decides the status of each POST; nil accepts all.
+type app struct {
+	page   string
+	cookie *http.Cookie
+	accept func(r *http.Request) bool
+
+	mu    sync.Mutex
+	posts []*http.Request
+}
+
+func (a *app) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		r.ParseForm()
+		a.mu.Lock()
+		a.posts = append(a.posts, r)
+		a.mu.Unlock()
+		if a.accept != nil && !a.accept(r) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, "saved")
+		return
+	}
+	if a.cookie != nil {
+		http.SetCookie(w, a.cookie)
+	}
+	w.Header().Set("Content-Type", "text/html")
+	fmt.Fprint(w, "<html><body>"+a.page+"</body></html>")
+}
+
+func run(t *testing.T, a *app, opts scanner.Options) (*httptest.Server, []types.Finding) {
+	t.Helper()
+	srv := httptest.NewServer(a)
+	t.Cleanup(srv.Close)
+	result, err := New().Run(context.Background(), types.Target{URL: srv.URL}, opts)
+	require.NoError(t, err)
+	return srv, result.Findings
+}
+
+const transferForm = `<form action="/transfer" method="post"><input name="to"><input name="amount" value="1"></form>`
+
+func TestScanner_NameAndDescription(t *testing.T) {
+	s := New()
+	assert.Equal(t, "csrf", s.Name())
+	assert.Equal(t, "CSRF protection detection", s.Description())
+}
+
+func TestScanner_MissingTokenReplayAccepted(t *testing.T) {
+	a := &app{page: transferForm}
+	srv, findings := run(t, a, scanner.DefaultOptions())
+
+	require.Len(t, findings, 1)
+	f := findings[0]
+	assert.Equal(t, "Missing CSRF protection", f.Title)
+	assert.Equal(t, types.SeverityHigh, f.Severity, "an accepted replay without SameSite cookies is forgeable")
+	assert.Equal(t, map[string]string{
+		"check":         "missing-token",
+		"method":        http.MethodPost,
+		"url":           srv.URL + "/transfer",
+		"page":          srv.URL,
+		"replay_status": "200",
+	}, f.Metadata)
+	assert.Len(t, f.Artifacts, 2)
+
+	require.Len(t, a.posts, 1)
+	assert.Equal(t, foreignOrigin, a.posts[0].Header.Get("Origin"))
+	assert.Equal(t, "1", a.posts[0].PostForm.Get("amount"))
+}
+
+func TestScanner_MissingTokenSafeIntensity(t *testing.T) {
+	a := &app{page: transferForm}
+	opts := scanner.DefaultOptions()
+	opts.Intensity = scanner.IntensitySafe
+	_, findings := run(t, a, opts)
+
+	require.Len(t, findings, 1)
+	assert.Equal(t, types.SeverityMedium, findings[0].Severity)
+	assert.NotContains(t, findings[0].Metadata, "replay_status")
+	assert.Empty(t, a.posts, "nothing is submitted at safe intensity")
+}
+
+func TestScanner_MissingTokenOriginChecked(t *testing.T) {
+	a := &app{page: transferForm, accept: func(r *http.Request) bool {
+		return r.Header.Get("Origin") == ""
+	}}
+	_, findings := run(t, a, scanner.DefaultOptions())
+	assert.Empty(t, findings, "a server refusing other origins is protected without a token")
+}
+
+func TestScanner_SameSiteCookies(t *testing.T) {
+	a := &app{page: transferForm, cookie: &http.Cookie{Name: "session_id", Value: "s3cr3t", SameSite: http.SameSiteLaxMode}}
+	_, findings := run(t, a, scanner.DefaultOptions())
+	require.Len(t, findings, 1)
+	assert.Equal(t, types.SeverityMedium, findings[0].Severity)
+	assert.Contains(t, findings[0].Evidence, "session cookies are SameSite")
+
+	a = &app{page: transferForm, cookie: &http.Cookie{Name: "sid", Value: "s3cr3t", SameSite: http.SameSiteNoneMode, Secure: true}}
+	_, findings = run(t, a, scanner.DefaultOptions())
+	require.Len(t, findings, 1)
+	assert.Equal(t, types.SeverityHigh, findings[0].Severity)
+	assert.Contains(t, findings[0].Evidence, "without SameSite=Lax or Strict: sid")
+}
+
+func TestScanner_TokenValidated(t *testing.T) {
+	a := &app{
+		page: `<form action="/transfer" method="post"><input type="hidden" name="csrf_token" value="t0k3n"><input name="to"></form>`,
+		accept: func(r *http.Request) bool {
+			return r.PostForm.Get("csrf_token") == "t0k3n"
+		},
+	}
+	_, findings := run(t, a, scanner.DefaultOptions())
+	assert.Empty(t, findings)
+	require.Len(t, a.posts, 1, "the request with its token is only sent when the forged one is accepted")
+	assert.False(t, a.posts[0].PostForm.Has("csrf_token"))
+}
+
+func TestScanner_TokenNotValidated(t *testing.T) {
+	a := &app{page: `<form action="/transfer" method="post"><input type="hidden" name="authenticity_token" value="t0k3n"><input name="to"></form>`}
+	srv, findings := run(t, a, scanner.DefaultOptions())
+
+	require.Len(t, findings, 1)
+	f := findings[0]
+	assert.Equal(t, "CSRF token not validated", f.Title)
+	assert.Equal(t, types.SeverityHigh, f.Severity)
+	assert.Equal(t, "unvalidated-token", f.Metadata["check"])
+	assert.Equal(t, "authenticity_token", f.Metadata["token"])
+	assert.Equal(t, srv.URL+"/transfer", f.Metadata["url"])
+}
+
+func TestScanner_UnsafeActionsNotReplayed(t *testing.T) {
+	a := &app{page: `<form action="/account/delete" method="post"><input name="confirm" value="yes"></form>`}
+	_, findings := run(t, a, scanner.DefaultOptions())
+
+	require.Len(t, findings, 1)
+	assert.Equal(t, types.SeverityMedium, findings[0].Severity)
+	assert.Empty(t, a.posts)
+}
+
+func TestScanner_ImportedRequests(t *testing.T) {
+	a := &app{page: `<p>No forms here.</p>`}
+	srv := httptest.NewServer(a)
+	defer srv.Close()
+
+	opts := scanner.DefaultOptions()
+	opts.Intensity = scanner.IntensitySafe
+	opts.Endpoints = []types.Endpoint{
+		{Method: http.MethodPost, URL: srv.URL + "/profile", Headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, Body: "name=alice"},
+		{Method: http.MethodPost, URL: srv.URL + "/settings", Headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, Body: "_csrf=abc&theme=dark"},
+		{Method: http.MethodPost, URL: srv.URL + "/comments", Headers: map[string]string{"X-CSRF-Token": "abc"}, Body: "text=hi"},
+		{Method: http.MethodPost, URL: srv.URL + "/api/orders", Headers: map[string]string{"Content-Type": "application/json"}, Body: `{"id": 1}`},
+		{Method: http.MethodPut, URL: srv.URL + "/profile", Body: "name=alice"},
+	}
+	result, err := New().Run(context.Background(), types.Target{URL: srv.URL}, opts)
+	require.NoError(t, err)
+
+	require.Len(t, result.Findings, 1, "JSON bodies and PUT requests cannot be sent from another site without a preflight")
+	assert.Equal(t, srv.URL+"/profile", result.Findings[0].Metadata["url"])
+	assert.NotContains(t, result.Findings[0].Metadata, "page")
+}
+
+func TestScanner_TargetUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	_, err := New().Run(context.Background(), types.Target{URL: srv.URL}, scanner.DefaultOptions())
+	assert.Error(t, err)
+}
+
+func TestWithoutToken(t *testing.T) {
+	ep := types.Endpoint{Method: http.MethodPost, URL: "https://example.com/x", Headers: map[string]string{"X-XSRF-TOKEN": "abc", "Content-Type": "application/x-www-form-urlencoded"}, Body: "a=1&csrf=abc"}
+
+	forged := withoutToken(ep, "X-XSRF-TOKEN")
+	assert.Equal(t, map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, forged.Headers)
+	assert.Equal(t, "a=1&csrf=abc", forged.Body)
+	assert.Contains(t, ep.Headers, "X-XSRF-TOKEN", "the original request is left alone")
+
+	forged = withoutToken(ep, "csrf")
+	assert.Equal(t, "a=1", forged.Body)
+}
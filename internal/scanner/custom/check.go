@@ -0,0 +1,344 @@
+// Package custom runs checks users define in YAML files rather than Go: a
+// request to send to the target and the conditions on its response that
+// make a finding, for checks specific to an organisation, such as its own
+// debug endpoints or banned headers.
+package custom
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/buemura/hunter/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Check is a custom check, as a YAML document:
+//
+//	id: acme-debug-vars
+//	name: Go expvar endpoint exposed
+//	severity: medium
+//	description: /debug/vars publishes memory statistics and command lines.
+//	remediation: Do not register expvar's handler on public servers.
+//	request:
+//	  method: GET
+//	  path: /debug/vars
+//	  headers:
+//	    Accept: application/json
+//	match:
+//	  status: [200]
+//	  headers:
+//	    Content-Type: json
+//	  body:
+//	    - '"memstats"'
+//
+// A finding is reported when the response meets every condition under
+// match, or any of them with "condition: or". Header and body conditions
+// are regular expressions; header names match in any case.
+//
+// Checks may also compute values with expressions, a subset of CEL: vars
+// are computed before the request, in order, and match.expr is a condition
+// on the response:
+//
+//	vars:
+//	  marker: sha256(random)
+//	request:
+//	  path: /search?q={{marker}}
+//	match:
+//	  expr: body.contains(marker) && int(headers["Content-Length"]) < 100000
+type Check struct {
+	ID          string  `yaml:"id"`
+	Name        string  `yaml:"name"`
+	Severity    string  `yaml:"severity"`
+	Description string  `yaml:"description"`
+	Remediation string  `yaml:"remediation"`
+	Vars        Vars    `yaml:"vars"`
+	Request     Request `yaml:"request"`
+	Match       Match   `yaml:"match"`
+
+	// Source is the file the check was read from.
+	Source string `yaml:"-"`
+
+	severity   types.Severity
+	path       template
+	reqBody    template
+	reqHeaders map[string]template
+	headers    map[string]*regexp.Regexp
+	body       []*regexp.Regexp
+	expr       expr
+}
+
+// Vars are the variables a check computes before its request, each from an
+// expression that may use host, random, and the variables before it.
+type Vars []Var
+
+// Var is a variable of a check.
+type Var struct {
+	Name string
+	Expr string
+
+	expr expr
+}
+
+// UnmarshalYAML reads vars from a mapping of names to expressions, keeping
+// their order.
+func (v *Vars) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: vars must map names to expressions", node.Line)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		*v = append(*v, Var{Name: node.Content[i].Value, Expr: node.Content[i+1].Value})
+	}
+	return nil
+}
+
+// Request is the request a check sends. Expressions in {{ }} may appear in
+// the path, header values, and body, such as {{host}}, the target's host
+// and port, {{random}}, a value that differs on every run, or a variable.
+type Request struct {
+	// Method defaults to GET.
+	Method string `yaml:"method"`
+	// Path is requested on the target, from its root.
+	Path    string            `yaml:"path"`
+	Headers map[string]string `yaml:"headers"`
+	Body    string            `yaml:"body"`
+}
+
+// Match is what a response must show for a check to report it.
+type Match struct {
+	// Condition is "and", the default, for all of the conditions below, or
+	// "or" for any of them.
+	Condition string `yaml:"condition"`
+	// Status lists the statuses that match.
+	Status []int `yaml:"status"`
+	// Headers maps header names to patterns their value must match.
+	Headers map[string]string `yaml:"headers"`
+	// Body lists patterns the body must match.
+	Body []string `yaml:"body"`
+	// Expr is an expression that must be true, which may use the check's
+	// variables and status, body, and headers.
+	Expr string `yaml:"expr"`
+}
+
+// reservedNames are the variables every check has, and the words of the
+// expression syntax.
+var reservedNames = []string{"host", "random", "status", "body", "headers", "true", "false", "in"}
+
+var (
+	checkID = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+	varName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// compile validates c and prepares its patterns.
+func (c *Check) compile() error {
+	if !checkID.MatchString(c.ID) {
+		return fmt.Errorf("invalid id %q: use lowercase letters, digits, '.', '_', and '-'", c.ID)
+	}
+	if c.Name == "" {
+		return fmt.Errorf("check %s: name is required", c.ID)
+	}
+	c.severity = types.Severity(strings.ToUpper(strings.TrimSpace(c.Severity)))
+	if c.Severity == "" {
+		c.severity = types.SeverityInfo
+	}
+	if types.SeverityRank(c.severity) > types.SeverityRank(types.SeverityInfo) {
+		return fmt.Errorf("check %s: unknown severity %q (supported: critical, high, medium, low, info)", c.ID, c.Severity)
+	}
+
+	if c.Request.Method == "" {
+		c.Request.Method = http.MethodGet
+	}
+	c.Request.Method = strings.ToUpper(c.Request.Method)
+	if !strings.HasPrefix(c.Request.Path, "/") {
+		return fmt.Errorf("check %s: request path %q must start with /", c.ID, c.Request.Path)
+	}
+
+	names := map[string]bool{"host": true, "random": true}
+	for i, v := range c.Vars {
+		if !varName.MatchString(v.Name) || slices.Contains(reservedNames, v.Name) || names[v.Name] {
+			return fmt.Errorf("check %s: invalid variable name %q", c.ID, v.Name)
+		}
+		e, err := parseExpr(v.Expr, names)
+		if err != nil {
+			return fmt.Errorf("check %s: var %s: %w", c.ID, v.Name, err)
+		}
+		c.Vars[i].expr = e
+		names[v.Name] = true
+	}
+	var err error
+	if c.path, err = parseTemplate(c.Request.Path, names); err != nil {
+		return fmt.Errorf("check %s: request path: %w", c.ID, err)
+	}
+	if c.reqBody, err = parseTemplate(c.Request.Body, names); err != nil {
+		return fmt.Errorf("check %s: request body: %w", c.ID, err)
+	}
+	c.reqHeaders = make(map[string]template, len(c.Request.Headers))
+	for name, value := range c.Request.Headers {
+		if c.reqHeaders[name], err = parseTemplate(value, names); err != nil {
+			return fmt.Errorf("check %s: request header %s: %w", c.ID, name, err)
+		}
+	}
+
+	switch c.Match.Condition = strings.ToLower(c.Match.Condition); c.Match.Condition {
+	case "":
+		c.Match.Condition = "and"
+	case "and", "or":
+	default:
+		return fmt.Errorf("check %s: unknown match condition %q (supported: and, or)", c.ID, c.Match.Condition)
+	}
+	if len(c.Match.Status)+len(c.Match.Headers)+len(c.Match.Body) == 0 && c.Match.Expr == "" {
+		return fmt.Errorf("check %s: match needs a status, header, body, or expr condition", c.ID)
+	}
+	c.headers = make(map[string]*regexp.Regexp, len(c.Match.Headers))
+	for name, pattern := range c.Match.Headers {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("check %s: header %s: %w", c.ID, name, err)
+		}
+		c.headers[http.CanonicalHeaderKey(name)] = re
+	}
+	c.body = make([]*regexp.Regexp, len(c.Match.Body))
+	for i, pattern := range c.Match.Body {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("check %s: body: %w", c.ID, err)
+		}
+		c.body[i] = re
+	}
+	if c.Match.Expr != "" {
+		names["status"], names["body"], names["headers"] = true, true, true
+		if c.expr, err = parseExpr(c.Match.Expr, names); err != nil {
+			return fmt.Errorf("check %s: match expr: %w", c.ID, err)
+		}
+	}
+	return nil
+}
+
+// matches reports whether the response in env, its status, headers, and
+// body along with the check's variables, meets c's conditions, and
+// describes those it met. It fails if match.expr does.
+func (c *Check) matches(env map[string]any) (bool, []string, error) {
+	status := int(env["status"].(int64))
+	header := env["headers"].(http.Header)
+	body := env["body"].(string)
+	var met []string
+	total := 0
+	if len(c.Match.Status) > 0 {
+		total++
+		if slices.Contains(c.Match.Status, status) {
+			met = append(met, fmt.Sprintf("status %d", status))
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.headers)) {
+		total++
+		if value := header.Get(name); value != "" && c.headers[name].MatchString(value) {
+			met = append(met, fmt.Sprintf("%s: %s", name, value))
+		}
+	}
+	for _, re := range c.body {
+		total++
+		if loc := re.FindStringIndex(body); loc != nil {
+			met = append(met, fmt.Sprintf("body matched %q", truncate(body[loc[0]:loc[1]], 100)))
+		}
+	}
+	if c.expr != nil {
+		total++
+		v, err := c.expr(env)
+		if err != nil {
+			return false, nil, fmt.Errorf("match expr: %w", err)
+		}
+		ok, isBool := v.(bool)
+		if !isBool {
+			return false, nil, fmt.Errorf("match expr is %s, not bool", typeName(v))
+		}
+		if ok {
+			met = append(met, "expr "+c.Match.Expr)
+		}
+	}
+	if c.Match.Condition == "or" {
+		return len(met) > 0, met, nil
+	}
+	return len(met) == total, met, nil
+}
+
+// Parse reads the checks in data, one YAML document each, read from the
+// file named source.
+func Parse(data []byte, source string) ([]Check, error) {
+	var checks []Check
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	for {
+		var c Check
+		err := dec.Decode(&c)
+		if errors.Is(err, io.EOF) {
+			return checks, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", source, err)
+		}
+		if err := c.compile(); err != nil {
+			return nil, fmt.Errorf("%s: %w", source, err)
+		}
+		c.Source = source
+		checks = append(checks, c)
+	}
+}
+
+// Load reads the checks of every .yaml and .yml file in dir, in the order
+// of their names. Two checks may not share an ID.
+func Load(dir string) ([]Check, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var checks []Check
+	seen := map[string]string{}
+	for _, e := range entries {
+		if e.IsDir() || (filepath.Ext(e.Name()) != ".yaml" && filepath.Ext(e.Name()) != ".yml") {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := Parse(data, path)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range parsed {
+			if other, ok := seen[c.ID]; ok {
+				return nil, fmt.Errorf("%s: check %s is also defined in %s", path, c.ID, other)
+			}
+			seen[c.ID] = path
+		}
+		checks = append(checks, parsed...)
+	}
+	return checks, nil
+}
+
+// DefaultDir returns the directory checks are loaded from when none is
+// given: ~/.hunter/checks.
+func DefaultDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".hunter", "checks")
+	}
+	return filepath.Join(home, ".hunter", "checks")
+}
+
+// truncate shortens s to n bytes, appending "..." if it was longer.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
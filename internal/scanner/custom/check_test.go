@@ -0,0 +1,136 @@
+package custom
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const debugVarsCheck = `
+id: acme-debug-vars
+name: Go expvar endpoint exposed
+severity: medium
+remediation: Do not register expvar's handler on public servers.
+request:
+  path: /debug/vars
+match:
+  status: [200]
+  headers:
+    content-type: json
+  body:
+    - '"memstats"'
+`
+
+func TestParse(t *testing.T) {
+	checks, err := Parse([]byte(debugVarsCheck+"---\nid: banned-header\nname: X-Powered-By sent\nrequest: {path: /}\nmatch: {headers: {X-Powered-By: .}}\n"), "acme.yaml")
+	require.NoError(t, err)
+	require.Len(t, checks, 2)
+
+	c := checks[0]
+	assert.Equal(t, "acme-debug-vars", c.ID)
+	assert.Equal(t, types.SeverityMedium, c.severity)
+	assert.Equal(t, http.MethodGet, c.Request.Method)
+	assert.Equal(t, "and", c.Match.Condition)
+	assert.Equal(t, "acme.yaml", c.Source)
+	assert.Equal(t, types.SeverityInfo, checks[1].severity, "severity defaults to info")
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for name, doc := range map[string]string{
+		"bad id":        "id: Not An ID\nname: x\nrequest: {path: /}\nmatch: {status: [200]}",
+		"no name":       "id: x\nrequest: {path: /}\nmatch: {status: [200]}",
+		"severity":      "id: x\nname: x\nseverity: urgent\nrequest: {path: /}\nmatch: {status: [200]}",
+		"relative path": "id: x\nname: x\nrequest: {path: admin}\nmatch: {status: [200]}",
+		"no match":      "id: x\nname: x\nrequest: {path: /}",
+		"condition":     "id: x\nname: x\nrequest: {path: /}\nmatch: {condition: xor, status: [200]}",
+		"regexp":        "id: x\nname: x\nrequest: {path: /}\nmatch: {body: ['(']}",
+		"unknown field": "id: x\nname: x\nrequest: {path: /}\nmatch: {status: [200]}\nseverty: high",
+		"expr syntax":   "id: x\nname: x\nrequest: {path: /}\nmatch: {expr: 'status =='}",
+		"expr variable": "id: x\nname: x\nrequest: {path: /}\nmatch: {expr: 'stauts == 200'}",
+		"var order":     "id: x\nname: x\nvars: {a: b, b: random}\nrequest: {path: /}\nmatch: {status: [200]}",
+		"var name":      "id: x\nname: x\nvars: {body: random}\nrequest: {path: /}\nmatch: {status: [200]}",
+		"response var":  "id: x\nname: x\nvars: {a: status}\nrequest: {path: /}\nmatch: {status: [200]}",
+		"template":      "id: x\nname: x\nrequest: {path: '/{{nope}}'}\nmatch: {status: [200]}",
+	} {
+		_, err := Parse([]byte(doc), "bad.yaml")
+		assert.Error(t, err, name)
+	}
+}
+
+func TestCheck_Matches(t *testing.T) {
+	checks, err := Parse([]byte(debugVarsCheck), "acme.yaml")
+	require.NoError(t, err)
+	c := checks[0]
+	header := http.Header{"Content-Type": {"application/json"}}
+
+	ok, met, err := c.matches(response(200, header, `{"cmdline": ["app"], "memstats": {}}`))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"status 200", "Content-Type: application/json", `body matched "\"memstats\""`}, met)
+
+	ok, _, _ = c.matches(response(200, http.Header{"Content-Type": {"text/html"}}, `"memstats"`))
+	assert.False(t, ok, "every condition must hold")
+
+	c.Match.Condition = "or"
+	ok, met, _ = c.matches(response(404, http.Header{}, `"memstats"`))
+	assert.True(t, ok)
+	assert.Len(t, met, 1)
+}
+
+func TestCheck_MatchesExpr(t *testing.T) {
+	checks, err := Parse([]byte(`
+id: large-export
+name: Large export
+vars:
+  marker: sha256(random)
+request:
+  path: /export?m={{marker}}
+match:
+  status: [200]
+  expr: int(headers["Content-Length"]) > 1000 && body.contains(marker)
+`), "acme.yaml")
+	require.NoError(t, err)
+	c := checks[0]
+	assert.Equal(t, "marker", c.Vars[0].Name)
+
+	env := response(200, http.Header{"Content-Length": {"5000"}}, "data abc")
+	env["marker"] = "abc"
+	ok, met, err := c.matches(env)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Contains(t, met, `expr int(headers["Content-Length"]) > 1000 && body.contains(marker)`)
+
+	env["headers"] = http.Header{"Content-Length": {"10"}}
+	ok, _, err = c.matches(env)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	env["headers"] = http.Header{}
+	_, _, err = c.matches(env)
+	assert.ErrorContains(t, err, "not a number", "a failing expression is an error, not a mismatch")
+}
+
+// response returns the variables of a response with status, header, and
+// body.
+func response(status int, header http.Header, body string) map[string]any {
+	return map[string]any{"status": int64(status), "headers": header, "body": body}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.yaml"), []byte(debugVarsCheck), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a check"), 0o644))
+	checks, err := Load(dir)
+	require.NoError(t, err)
+	require.Len(t, checks, 1)
+	assert.Equal(t, filepath.Join(dir, "a.yaml"), checks[0].Source)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.yml"), []byte(debugVarsCheck), 0o644))
+	_, err = Load(dir)
+	assert.ErrorContains(t, err, "also defined in")
+}
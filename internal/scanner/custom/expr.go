@@ -0,0 +1,685 @@
+package custom
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// expr is a parsed expression, which computes a value from the variables of
+// a check run. Expressions let checks match on more than patterns and build
+// payloads from values they compute. The syntax is a subset of CEL:
+//
+//	200  "text"  'text'  true  [1, 2]   literals: ints, strings, bools, lists
+//	status  body  headers["Name"]       variables; header names match in any case
+//	a + b  a - b  a * b  a / b  a % b   arithmetic on ints; + also joins strings
+//	== != < <= > >=  in                 comparisons; in looks in lists and headers
+//	!a  a && b  a || b                  logic
+//	size(body)  body.contains("x")      functions, called either way
+//
+// Values are int64, string, bool, []any, or, for headers, http.Header.
+type expr func(env map[string]any) (any, error)
+
+// exprFuncs are the functions expressions may call. A method call, x.f(y),
+// is f(x, y).
+var exprFuncs = map[string]func(args []any) (any, error){
+	"size": func(args []any) (any, error) {
+		if err := arity(args, 1); err != nil {
+			return nil, err
+		}
+		switch v := args[0].(type) {
+		case string:
+			return int64(len(v)), nil
+		case []any:
+			return int64(len(v)), nil
+		case http.Header:
+			return int64(len(v)), nil
+		}
+		return nil, fmt.Errorf("size of %s", typeName(args[0]))
+	},
+	"int": func(args []any) (any, error) {
+		if err := arity(args, 1); err != nil {
+			return nil, err
+		}
+		switch v := args[0].(type) {
+		case int64:
+			return v, nil
+		case string:
+			n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("int(%q): not a number", v)
+			}
+			return n, nil
+		}
+		return nil, fmt.Errorf("int of %s", typeName(args[0]))
+	},
+	"string": func(args []any) (any, error) {
+		if err := arity(args, 1); err != nil {
+			return nil, err
+		}
+		return fmt.Sprint(args[0]), nil
+	},
+	"lower":        stringFunc(strings.ToLower),
+	"upper":        stringFunc(strings.ToUpper),
+	"trim":         stringFunc(strings.TrimSpace),
+	"md5":          stringFunc(func(s string) string { sum := md5.Sum([]byte(s)); return hex.EncodeToString(sum[:]) }),
+	"sha1":         stringFunc(func(s string) string { sum := sha1.Sum([]byte(s)); return hex.EncodeToString(sum[:]) }),
+	"sha256":       stringFunc(func(s string) string { sum := sha256.Sum256([]byte(s)); return hex.EncodeToString(sum[:]) }),
+	"hex":          stringFunc(func(s string) string { return hex.EncodeToString([]byte(s)) }),
+	"base64":       stringFunc(func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }),
+	"urlEncode":    stringFunc(url.QueryEscape),
+	"contains":     predicate(strings.Contains),
+	"startsWith":   predicate(strings.HasPrefix),
+	"endsWith":     predicate(strings.HasSuffix),
+	"base64Decode": base64Decode,
+	"matches":      matchesFunc,
+}
+
+func arity(args []any, n int) error {
+	if len(args) != n {
+		return fmt.Errorf("takes %d arguments, got %d", n, len(args))
+	}
+	return nil
+}
+
+func stringFunc(f func(string) string) func([]any) (any, error) {
+	return func(args []any) (any, error) {
+		if err := arity(args, 1); err != nil {
+			return nil, err
+		}
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("expects a string, got %s", typeName(args[0]))
+		}
+		return f(s), nil
+	}
+}
+
+func predicate(f func(s, sub string) bool) func([]any) (any, error) {
+	return func(args []any) (any, error) {
+		if err := arity(args, 2); err != nil {
+			return nil, err
+		}
+		s, ok1 := args[0].(string)
+		sub, ok2 := args[1].(string)
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("expects strings, got %s and %s", typeName(args[0]), typeName(args[1]))
+		}
+		return f(s, sub), nil
+	}
+}
+
+func base64Decode(args []any) (any, error) {
+	if err := arity(args, 1); err != nil {
+		return nil, err
+	}
+	s, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("expects a string, got %s", typeName(args[0]))
+	}
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 %q", truncate(s, 40))
+	}
+	return string(data), nil
+}
+
+func matchesFunc(args []any) (any, error) {
+	if err := arity(args, 2); err != nil {
+		return nil, err
+	}
+	s, ok1 := args[0].(string)
+	pattern, ok2 := args[1].(string)
+	if !ok1 || !ok2 {
+		return nil, fmt.Errorf("expects strings, got %s and %s", typeName(args[0]), typeName(args[1]))
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return re.MatchString(s), nil
+}
+
+type exprParser struct {
+	tokens []string
+	pos    int
+	// names are the variables the expression may use.
+	names map[string]bool
+}
+
+// parseExpr parses src, an expression that may use the variables in names.
+func parseExpr(src string, names map[string]bool) (expr, error) {
+	tokens, err := tokenizeExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+	p := &exprParser{tokens: tokens, names: names}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return e, nil
+}
+
+var twoCharOps = []string{"&&", "||", "==", "!=", "<=", ">="}
+
+func tokenizeExpr(src string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case i+1 < len(src) && slices.Contains(twoCharOps, src[i:i+2]):
+			tokens = append(tokens, src[i:i+2])
+			i += 2
+		case strings.ContainsRune("()[],.!<>+-*/%", rune(c)):
+			tokens = append(tokens, string(c))
+			i++
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(src) && src[j] != c {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			tokens = append(tokens, src[i:j+1])
+			i = j + 1
+		case unicode.IsDigit(rune(c)):
+			j := i + 1
+			for j < len(src) && unicode.IsDigit(rune(src[j])) {
+				j++
+			}
+			tokens = append(tokens, src[i:j])
+			i = j
+		case c == '_' || unicode.IsLetter(rune(c)):
+			j := i + 1
+			for j < len(src) && (src[j] == '_' || unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j]))) {
+				j++
+			}
+			tokens = append(tokens, src[i:j])
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected %q at offset %d", c, i)
+		}
+	}
+	return tokens, nil
+}
+
+func (p *exprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *exprParser) expect(tok string) error {
+	if p.peek() != tok {
+		if p.peek() == "" {
+			return fmt.Errorf("expected %q at end of expression", tok)
+		}
+		return fmt.Errorf("expected %q, got %q", tok, p.peek())
+	}
+	p.pos++
+	return nil
+}
+
+func (p *exprParser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "||" {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpr(false, left, right)
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (expr, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "&&" {
+		p.pos++
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpr(true, left, right)
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseComparison() (expr, error) {
+	left, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	switch op := p.peek(); op {
+	case "==", "!=", "<", "<=", ">", ">=", "in":
+		p.pos++
+		right, err := p.parseBinary(0)
+		if err != nil {
+			return nil, err
+		}
+		return binaryExpr(op, left, right), nil
+	}
+	return left, nil
+}
+
+// binaryLevels are the arithmetic operators, from the loosest binding.
+var binaryLevels = [][]string{{"+", "-"}, {"*", "/", "%"}}
+
+func (p *exprParser) parseBinary(level int) (expr, error) {
+	if level == len(binaryLevels) {
+		return p.parseUnary()
+	}
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for op := p.peek(); slices.Contains(binaryLevels[level], op); op = p.peek() {
+		p.pos++
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpr(op, left, right)
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (expr, error) {
+	switch op := p.peek(); op {
+	case "!", "-":
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(env map[string]any) (any, error) {
+			v, err := operand(env)
+			if err != nil {
+				return nil, err
+			}
+			if b, ok := v.(bool); ok && op == "!" {
+				return !b, nil
+			}
+			if n, ok := v.(int64); ok && op == "-" {
+				return -n, nil
+			}
+			return nil, fmt.Errorf("%s applied to %s", op, typeName(v))
+		}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *exprParser) parsePostfix() (expr, error) {
+	e, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.peek() {
+		case ".":
+			p.pos++
+			name := p.peek()
+			if exprFuncs[name] == nil {
+				return nil, fmt.Errorf("unknown function %q", name)
+			}
+			p.pos++
+			if err := p.expect("("); err != nil {
+				return nil, err
+			}
+			args, err := p.parseList(")")
+			if err != nil {
+				return nil, err
+			}
+			e = callExpr(name, append([]expr{e}, args...))
+		case "[":
+			p.pos++
+			index, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			e = indexExpr(e, index)
+		default:
+			return e, nil
+		}
+	}
+}
+
+func (p *exprParser) parsePrimary() (expr, error) {
+	tok := p.peek()
+	switch {
+	case tok == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case tok == "(":
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	case tok == "[":
+		p.pos++
+		items, err := p.parseList("]")
+		if err != nil {
+			return nil, err
+		}
+		return func(env map[string]any) (any, error) {
+			list := make([]any, len(items))
+			for i, item := range items {
+				v, err := item(env)
+				if err != nil {
+					return nil, err
+				}
+				list[i] = v
+			}
+			return list, nil
+		}, nil
+	case tok == "true" || tok == "false":
+		p.pos++
+		return literalExpr(tok == "true"), nil
+	case tok[0] == '"' || tok[0] == '\'':
+		p.pos++
+		s, err := unquote(tok)
+		if err != nil {
+			return nil, fmt.Errorf("invalid string %s", tok)
+		}
+		return literalExpr(s), nil
+	case unicode.IsDigit(rune(tok[0])):
+		p.pos++
+		n, err := strconv.ParseInt(tok, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", tok)
+		}
+		return literalExpr(n), nil
+	case tok[0] == '_' || unicode.IsLetter(rune(tok[0])):
+		p.pos++
+		if p.peek() == "(" {
+			if exprFuncs[tok] == nil {
+				return nil, fmt.Errorf("unknown function %q", tok)
+			}
+			p.pos++
+			args, err := p.parseList(")")
+			if err != nil {
+				return nil, err
+			}
+			return callExpr(tok, args), nil
+		}
+		if !p.names[tok] {
+			return nil, fmt.Errorf("unknown variable %q", tok)
+		}
+		return func(env map[string]any) (any, error) {
+			return env[tok], nil
+		}, nil
+	}
+	return nil, fmt.Errorf("unexpected %q", tok)
+}
+
+// parseList parses comma-separated expressions up to close.
+func (p *exprParser) parseList(close string) ([]expr, error) {
+	var items []expr
+	for p.peek() != close {
+		item, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		if p.peek() != "," {
+			break
+		}
+		p.pos++
+	}
+	return items, p.expect(close)
+}
+
+// unquote returns the value of a string literal in double or single quotes.
+func unquote(tok string) (string, error) {
+	if tok[0] == '\'' {
+		inner := strings.ReplaceAll(tok[1:len(tok)-1], `\'`, `'`)
+		tok = `"` + strings.ReplaceAll(inner, `"`, `\"`) + `"`
+	}
+	return strconv.Unquote(tok)
+}
+
+func literalExpr(v any) expr {
+	return func(map[string]any) (any, error) { return v, nil }
+}
+
+func callExpr(name string, args []expr) expr {
+	f := exprFuncs[name]
+	return func(env map[string]any) (any, error) {
+		values := make([]any, len(args))
+		for i, arg := range args {
+			v, err := arg(env)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+		v, err := f(values)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		return v, nil
+	}
+}
+
+func indexExpr(e, index expr) expr {
+	return func(env map[string]any) (any, error) {
+		v, err := e(env)
+		if err != nil {
+			return nil, err
+		}
+		i, err := index(env)
+		if err != nil {
+			return nil, err
+		}
+		switch container := v.(type) {
+		case http.Header:
+			if name, ok := i.(string); ok {
+				return container.Get(name), nil
+			}
+		case []any:
+			if n, ok := i.(int64); ok {
+				if n < 0 || n >= int64(len(container)) {
+					return nil, fmt.Errorf("index %d out of range", n)
+				}
+				return container[n], nil
+			}
+		}
+		return nil, fmt.Errorf("cannot index %s with %s", typeName(v), typeName(i))
+	}
+}
+
+// logicalExpr joins left and right with && if and is true, or ||, and only
+// evaluates right if it decides the result.
+func logicalExpr(and bool, left, right expr) expr {
+	return func(env map[string]any) (any, error) {
+		for _, e := range []expr{left, right} {
+			v, err := e(env)
+			if err != nil {
+				return nil, err
+			}
+			b, ok := v.(bool)
+			if !ok {
+				return nil, fmt.Errorf("&& and || need bools, got %s", typeName(v))
+			}
+			if b != and {
+				return b, nil
+			}
+		}
+		return and, nil
+	}
+}
+
+func binaryExpr(op string, left, right expr) expr {
+	return func(env map[string]any) (any, error) {
+		a, err := left(env)
+		if err != nil {
+			return nil, err
+		}
+		b, err := right(env)
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case "==":
+			return reflect.DeepEqual(a, b), nil
+		case "!=":
+			return !reflect.DeepEqual(a, b), nil
+		case "in":
+			switch container := b.(type) {
+			case []any:
+				for _, item := range container {
+					if reflect.DeepEqual(a, item) {
+						return true, nil
+					}
+				}
+				return false, nil
+			case http.Header:
+				if name, ok := a.(string); ok {
+					return len(container.Values(name)) > 0, nil
+				}
+			}
+		}
+
+		if x, ok := a.(int64); ok {
+			if y, ok := b.(int64); ok {
+				return intOp(op, x, y)
+			}
+		}
+		if x, ok := a.(string); ok {
+			if y, ok := b.(string); ok {
+				switch op {
+				case "+":
+					return x + y, nil
+				case "<":
+					return x < y, nil
+				case "<=":
+					return x <= y, nil
+				case ">":
+					return x > y, nil
+				case ">=":
+					return x >= y, nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("%s %s %s is not supported", typeName(a), op, typeName(b))
+	}
+}
+
+func intOp(op string, x, y int64) (any, error) {
+	switch op {
+	case "+":
+		return x + y, nil
+	case "-":
+		return x - y, nil
+	case "*":
+		return x * y, nil
+	case "/", "%":
+		if y == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		if op == "/" {
+			return x / y, nil
+		}
+		return x % y, nil
+	case "<":
+		return x < y, nil
+	case "<=":
+		return x <= y, nil
+	case ">":
+		return x > y, nil
+	case ">=":
+		return x >= y, nil
+	}
+	return nil, fmt.Errorf("int %s int is not supported", op)
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case int64:
+		return "int"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case []any:
+		return "list"
+	case http.Header:
+		return "headers"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// template is a string with expressions in {{ }}, such as a request path.
+type template func(env map[string]any) (string, error)
+
+// parseTemplate parses s, whose expressions may use the variables in names.
+func parseTemplate(s string, names map[string]bool) (template, error) {
+	var parts []expr
+	for s != "" {
+		start := strings.Index(s, "{{")
+		if start < 0 {
+			parts = append(parts, literalExpr(s))
+			break
+		}
+		end := strings.Index(s[start:], "}}")
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated {{ in %q", s)
+		}
+		if start > 0 {
+			parts = append(parts, literalExpr(s[:start]))
+		}
+		e, err := parseExpr(s[start+2:start+end], names)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", s[start:start+end+2], err)
+		}
+		parts = append(parts, e)
+		s = s[start+end+2:]
+	}
+	return func(env map[string]any) (string, error) {
+		var b strings.Builder
+		for _, part := range parts {
+			v, err := part(env)
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprint(&b, v)
+		}
+		return b.String(), nil
+	}, nil
+}
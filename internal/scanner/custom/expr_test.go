@@ -0,0 +1,63 @@
+package custom
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpr(t *testing.T) {
+	names := map[string]bool{"status": true, "body": true, "headers": true}
+	env := map[string]any{
+		"status":  int64(200),
+		"body":    `{"user": "admin"}`,
+		"headers": http.Header{"Content-Length": {"1024"}, "X-Powered-By": {"PHP/7.4"}},
+	}
+	for src, want := range map[string]any{
+		`status == 200 && body.contains("admin")`:             true,
+		`status in [301, 302] || size(body) > 10`:             true,
+		`!("Server" in headers) && "x-powered-by" in headers`: true,
+		`int(headers["content-length"]) / 2 + 1`:              int64(513),
+		`headers["X-Powered-By"].matches('PHP/[57]\\.')`:      true,
+		`md5("hunter")`: "6b1b36cbb04b41490bfc0ab2bfa26f86",
+		`upper(base64("ab")) + "-" + string(status % 7)`: "YWI=-4",
+		`base64Decode("aHVudGVy") == 'hunter'`:           true,
+		`-(1 - 3) * 2 >= 4`:                              true,
+		`"a" < "b" && headers["Missing"] == ""`:          true,
+	} {
+		e, err := parseExpr(src, names)
+		require.NoError(t, err, src)
+		got, err := e(env)
+		require.NoError(t, err, src)
+		assert.Equal(t, want, got, src)
+	}
+}
+
+func TestExpr_Errors(t *testing.T) {
+	names := map[string]bool{"status": true, "body": true}
+	for _, src := range []string{"", "status ==", "nope", "reverse(body)", "body.nope()", "(status", `"open`, "status $ 1"} {
+		_, err := parseExpr(src, names)
+		assert.Error(t, err, src)
+	}
+
+	env := map[string]any{"status": int64(200), "body": "x"}
+	for _, src := range []string{"status + body", "status / 0", "int(body)", "status && true", "[1][3]", "size(status)"} {
+		e, err := parseExpr(src, names)
+		require.NoError(t, err, src)
+		_, err = e(env)
+		assert.Error(t, err, src)
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	tmpl, err := parseTemplate("/search?q={{marker}}&h={{md5(marker)}}", map[string]bool{"marker": true})
+	require.NoError(t, err)
+	got, err := tmpl(map[string]any{"marker": "x"})
+	require.NoError(t, err)
+	assert.Equal(t, "/search?q=x&h=9dd4e461268c8034f5c8564e155c67a6", got)
+
+	_, err = parseTemplate("/{{marker", map[string]bool{"marker": true})
+	assert.Error(t, err)
+}
@@ -0,0 +1,247 @@
+package custom
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// maxBody is the most of a response body read.
+const maxBody = 1 << 20
+
+// Scanner runs the custom checks of a directory, the "dir" argument or
+// DefaultDir, against the target. Without checks it does nothing.
+type Scanner struct{}
+
+// New creates a new custom check scanner.
+func New() *Scanner {
+	return &Scanner{}
+}
+
+func (s *Scanner) Name() string        { return "custom" }
+func (s *Scanner) Description() string { return "Custom checks defined in YAML" }
+
+func (s *Scanner) Run(ctx context.Context, target types.Target, opts scanner.Options) (*types.ScanResult, error) {
+	result := &types.ScanResult{
+		ScannerName: s.Name(),
+		Target:      target,
+		StartedAt:   time.Now(),
+	}
+
+	base, err := baseURL(target)
+	if err != nil {
+		return nil, err
+	}
+	checks, err := s.checks(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	client := newClient(opts)
+	for i, c := range checks {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := opts.Gate.Wait(ctx); err != nil {
+			break
+		}
+		finding, ok, err := run(ctx, client, base, target, c)
+		opts.ReportProgress(s.Name(), i+1, len(checks))
+		if err != nil {
+			opts.Logf(s.Name(), scanner.LogWarn, "check %s: %v", c.ID, err)
+			continue
+		}
+		if ok {
+			result.Findings = append(result.Findings, finding)
+		}
+	}
+
+	result.CompletedAt = time.Now()
+	return result, nil
+}
+
+// Verify runs the check behind finding again.
+func (s *Scanner) Verify(ctx context.Context, target types.Target, finding types.Finding, opts scanner.Options) (bool, error) {
+	id := finding.Metadata["check_id"]
+	checks, err := s.checks(opts)
+	if err != nil {
+		return false, err
+	}
+	for _, c := range checks {
+		if c.ID != id {
+			continue
+		}
+		base, err := baseURL(target)
+		if err != nil {
+			return false, err
+		}
+		_, ok, err := run(ctx, newClient(opts), base, target, c)
+		return ok, err
+	}
+	return false, fmt.Errorf("custom check %q is not defined any more", id)
+}
+
+// checks loads the checks to run: those of the "dir" argument, or of
+// DefaultDir if it exists, limited to the IDs of the "checks" argument, if
+// given.
+func (s *Scanner) checks(opts scanner.Options) ([]Check, error) {
+	dir := opts.StringArg("dir")
+	explicit := dir != ""
+	if !explicit {
+		dir = DefaultDir()
+	}
+	checks, err := Load(dir)
+	if !explicit && errors.Is(err, fs.ErrNotExist) {
+		opts.Logf(s.Name(), scanner.LogInfo, "no custom checks: %s does not exist", dir)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading custom checks: %w", err)
+	}
+
+	ids := opts.StringArg("checks")
+	if ids == "" {
+		return checks, nil
+	}
+	var selected []Check
+	for _, id := range strings.Split(ids, ",") {
+		id = strings.TrimSpace(id)
+		found := false
+		for _, c := range checks {
+			if c.ID == id {
+				selected, found = append(selected, c), true
+			}
+		}
+		if !found {
+			opts.Logf(s.Name(), scanner.LogWarn, "unknown check %q ignored", id)
+		}
+	}
+	return selected, nil
+}
+
+// run sends c's request to the target at base and returns a finding if the
+// response meets its conditions.
+func run(ctx context.Context, client *http.Client, base string, target types.Target, c Check) (types.Finding, bool, error) {
+	env := map[string]any{"host": target.URLHost(), "random": randomValue()}
+	for _, v := range c.Vars {
+		value, err := v.expr(env)
+		if err != nil {
+			return types.Finding{}, false, fmt.Errorf("var %s: %w", v.Name, err)
+		}
+		env[v.Name] = value
+	}
+
+	path, err := c.path(env)
+	if err != nil {
+		return types.Finding{}, false, fmt.Errorf("request path: %w", err)
+	}
+	reqURL := base + path
+	var body io.Reader
+	if c.Request.Body != "" {
+		data, err := c.reqBody(env)
+		if err != nil {
+			return types.Finding{}, false, fmt.Errorf("request body: %w", err)
+		}
+		body = strings.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, c.Request.Method, reqURL, body)
+	if err != nil {
+		return types.Finding{}, false, err
+	}
+	for name, value := range c.reqHeaders {
+		v, err := value(env)
+		if err != nil {
+			return types.Finding{}, false, fmt.Errorf("request header %s: %w", name, err)
+		}
+		if strings.EqualFold(name, "Host") {
+			req.Host = v
+			continue
+		}
+		req.Header.Set(name, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return types.Finding{}, false, err
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+	resp.Body.Close()
+
+	env["status"], env["headers"], env["body"] = int64(resp.StatusCode), resp.Header, string(data)
+	ok, met, err := c.matches(env)
+	if err != nil || !ok {
+		return types.Finding{}, false, err
+	}
+	description := c.Description
+	if description == "" {
+		description = fmt.Sprintf("The response to %s %s met the conditions of the custom check %s.", req.Method, reqURL, c.ID)
+	}
+	return types.Finding{
+		Title:       c.Name,
+		Description: description,
+		Severity:    c.severity,
+		Evidence:    fmt.Sprintf("%s %s → %d; %s", req.Method, reqURL, resp.StatusCode, strings.Join(met, "; ")),
+		Remediation: c.Remediation,
+		Metadata: map[string]string{
+			"check_id": c.ID,
+			"source":   c.Source,
+			"method":   req.Method,
+			"url":      reqURL,
+			"status":   fmt.Sprintf("%d", resp.StatusCode),
+		},
+	}, true, nil
+}
+
+// baseURL returns the scheme and host of the target, which check paths are
+// requested under.
+func baseURL(target types.Target) (string, error) {
+	if target.URL != "" {
+		u, err := url.Parse(target.URL)
+		if err != nil || u.Host == "" {
+			return "", fmt.Errorf("invalid target URL %q", target.URL)
+		}
+		return u.Scheme + "://" + u.Host, nil
+	}
+	if target.Host == "" {
+		return "", fmt.Errorf("cannot determine URL for target %q", target.Host)
+	}
+	scheme := target.Scheme
+	if scheme == "" {
+		scheme = "https"
+	}
+	return scheme + "://" + target.URLHost(), nil
+}
+
+// newClient returns the client checks are sent with. Redirects are not
+// followed, so checks can match on them.
+func newClient(opts scanner.Options) *http.Client {
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: opts.HTTPTransport(),
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// randomValue returns a value for {{random}}.
+func randomValue() string {
+	b := make([]byte, 6)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,128 @@
+package custom
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanner_NameAndDescription(t *testing.T) {
+	s := New()
+	assert.Equal(t, "custom", s.Name())
+	assert.Equal(t, "Custom checks defined in YAML", s.Description())
+}
+
+// checksDir writes checks to a directory and returns options using it.
+func checksDir(t *testing.T, checks string) scanner.Options {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "checks.yaml"), []byte(checks), 0o644))
+	opts := scanner.DefaultOptions()
+	opts.ExtraArgs = map[string]interface{}{"dir": dir}
+	return opts
+}
+
+func TestScanner_Run(t *testing.T) {
+	var gotHost, gotToken string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/debug/vars":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"memstats": {}}`)
+		case "/api/echo":
+			gotHost, gotToken = r.Header.Get("X-Forwarded-Host"), r.URL.Query().Get("t")
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	opts := checksDir(t, debugVarsCheck+`---
+id: echo
+name: Echo answers
+request:
+  method: post
+  path: /api/echo?t={{random}}
+  headers: {X-Forwarded-Host: "{{host}}"}
+match: {status: [204]}
+---
+id: missing
+name: Missing page
+request: {path: /nowhere}
+match: {status: [200]}
+`)
+	target := types.Target{URL: srv.URL + "/app/", Host: "127.0.0.1", Scheme: "http"}
+	result, err := New().Run(context.Background(), target, opts)
+	require.NoError(t, err)
+
+	require.Len(t, result.Findings, 2)
+	f := result.Findings[0]
+	assert.Equal(t, "Go expvar endpoint exposed", f.Title)
+	assert.Equal(t, types.SeverityMedium, f.Severity)
+	assert.Equal(t, "acme-debug-vars", f.Metadata["check_id"])
+	assert.Equal(t, srv.URL+"/debug/vars", f.Metadata["url"], "paths are requested from the target's root")
+	assert.Contains(t, f.Evidence, "GET "+srv.URL+"/debug/vars → 200")
+	assert.Equal(t, "echo", result.Findings[1].Metadata["check_id"])
+	assert.Equal(t, target.URLHost(), gotHost)
+	assert.Len(t, gotToken, 12)
+
+	ok, err := New().Verify(context.Background(), target, f, opts)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestScanner_RunExpressions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Ratelimit-Remaining", "9000")
+		fmt.Fprintf(w, "<p>No results for %s</p>", r.URL.Query().Get("q"))
+	}))
+	defer srv.Close()
+
+	opts := checksDir(t, `id: reflected-hash
+name: Search reflects its query
+vars:
+  marker: sha256(random)
+  probe: '"hunter" + marker'
+request:
+  path: /search?q={{probe}}
+match:
+  expr: body.contains(probe) && int(headers["X-RateLimit-Remaining"]) > 1000
+`)
+	result, err := New().Run(context.Background(), types.Target{URL: srv.URL}, opts)
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 1)
+	assert.Regexp(t, `/search\?q=hunter[0-9a-f]{64}$`, result.Findings[0].Metadata["url"])
+}
+
+func TestScanner_RunSelectedChecks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	opts := checksDir(t, "id: a\nname: A\nrequest: {path: /}\nmatch: {status: [200]}\n---\nid: b\nname: B\nrequest: {path: /}\nmatch: {status: [200]}\n")
+	opts.ExtraArgs["checks"] = "b"
+	result, err := New().Run(context.Background(), types.Target{URL: srv.URL}, opts)
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 1)
+	assert.Equal(t, "B", result.Findings[0].Title)
+}
+
+func TestScanner_RunWithoutChecks(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	result, err := New().Run(context.Background(), types.Target{URL: "http://127.0.0.1:1"}, scanner.DefaultOptions())
+	require.NoError(t, err, "no default checks directory is not an error")
+	assert.Empty(t, result.Findings)
+
+	opts := scanner.DefaultOptions()
+	opts.ExtraArgs = map[string]interface{}{"dir": filepath.Join(t.TempDir(), "missing")}
+	_, err = New().Run(context.Background(), types.Target{URL: "http://127.0.0.1:1"}, opts)
+	assert.Error(t, err, "a directory given must exist")
+}
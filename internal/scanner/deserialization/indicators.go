@@ -0,0 +1,104 @@
+package deserialization
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Serialization formats the scanner recognizes.
+const (
+	formatJava = "java"
+	formatPHP  = "php"
+)
+
+// javaMagic starts every Java serialization stream: STREAM_MAGIC and
+// STREAM_VERSION.
+var javaMagic = []byte{0xAC, 0xED, 0x00, 0x05}
+
+// phpSerialized matches the start of a PHP serialize() string: an object
+// with its class name, an array, or a scalar.
+var phpSerialized = regexp.MustCompile(`^(?:O:\d+:"([A-Za-z_\\][A-Za-z0-9_\\]*)":\d+:\{|C:\d+:"([A-Za-z_\\][A-Za-z0-9_\\]*)":\d+:\{|a:\d+:\{(?:[is]:)|s:\d+:".*";$|i:-?\d+;$|b:[01];$|d:-?[0-9.E+-]+;$)`)
+
+// maxDecoded is the most of a gzipped value decompressed.
+const maxDecoded = 64 << 10
+
+// indicator is serialized data found in a value.
+type indicator struct {
+	Format string
+	// Encoding is how the serialized bytes were carried: "raw", "base64",
+	// "hex", "gzip+base64", or "url".
+	Encoding string
+	// Class is the PHP class an object is of, empty for other values.
+	Class string
+	// Object reports whether the data is an object, which the application
+	// instantiates when it deserializes it, rather than a scalar or an array.
+	Object bool
+}
+
+// inspect reports whether value, a cookie or form field value, holds a Java
+// or PHP serialized object, as sent or in one of the encodings applications
+// wrap them in.
+func inspect(value string) (indicator, bool) {
+	value = strings.TrimSpace(value)
+	if len(value) < 4 {
+		return indicator{}, false
+	}
+	if unescaped, err := url.QueryUnescape(value); err == nil && unescaped != value {
+		if ind, ok := inspect(unescaped); ok {
+			if ind.Encoding == "raw" {
+				ind.Encoding = "url"
+			}
+			return ind, true
+		}
+	}
+
+	if ind, ok := inspectBytes([]byte(value)); ok {
+		ind.Encoding = "raw"
+		return ind, true
+	}
+	if decoded, err := hex.DecodeString(value); err == nil && bytes.HasPrefix(decoded, javaMagic) {
+		return indicator{Format: formatJava, Encoding: "hex", Object: true}, true
+	}
+	if decoded, ok := decodeBase64(value); ok {
+		if ind, ok := inspectBytes(decoded); ok {
+			ind.Encoding = "base64"
+			return ind, true
+		}
+		if zr, err := gzip.NewReader(bytes.NewReader(decoded)); err == nil {
+			head, _ := io.ReadAll(io.LimitReader(zr, maxDecoded))
+			if bytes.HasPrefix(head, javaMagic) {
+				return indicator{Format: formatJava, Encoding: "gzip+base64", Object: true}, true
+			}
+		}
+	}
+	return indicator{}, false
+}
+
+// inspectBytes reports whether data is a Java serialization stream or a PHP
+// serialized value.
+func inspectBytes(data []byte) (indicator, bool) {
+	if bytes.HasPrefix(data, javaMagic) {
+		return indicator{Format: formatJava, Object: true}, true
+	}
+	if m := phpSerialized.FindSubmatch(data); m != nil {
+		class := string(m[1]) + string(m[2])
+		return indicator{Format: formatPHP, Class: class, Object: class != ""}, true
+	}
+	return indicator{}, false
+}
+
+// decodeBase64 decodes value as standard or URL-safe base64, padded or not.
+func decodeBase64(value string) ([]byte, bool) {
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if decoded, err := enc.DecodeString(value); err == nil {
+			return decoded, true
+		}
+	}
+	return nil, false
+}
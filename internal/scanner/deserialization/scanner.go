@@ -0,0 +1,281 @@
+package deserialization
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// maxPageSize is the most of a response read.
+const maxPageSize = 2 << 20
+
+// maxShown is how much of a serialized value a finding's evidence shows.
+const maxShown = 48
+
+var (
+	inputTag  = regexp.MustCompile(`(?is)<input\b[^>]*>`)
+	attribute = regexp.MustCompile(`(?is)([a-z][a-z0-9_-]*)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+)
+
+// Where serialized data was found.
+const (
+	locationCookie = "cookie"
+	locationField  = "form field"
+	locationBody   = "response body"
+)
+
+// Scanner finds serialized objects a site hands to clients, which it
+// deserializes when they come back. It only reads responses and never sends
+// payloads.
+type Scanner struct{}
+
+// New creates a new deserialization scanner.
+func New() *Scanner {
+	return &Scanner{}
+}
+
+func (s *Scanner) Name() string        { return "deserialization" }
+func (s *Scanner) Description() string { return "Insecure deserialization indicators" }
+
+// page is a fetched response.
+type page struct {
+	URL         string
+	ContentType string
+	Cookies     []*http.Cookie
+	Body        []byte
+}
+
+func (s *Scanner) Run(ctx context.Context, target types.Target, opts scanner.Options) (*types.ScanResult, error) {
+	result := &types.ScanResult{
+		ScannerName: s.Name(),
+		Target:      target,
+		StartedAt:   time.Now(),
+	}
+
+	pageURL := resolveURL(target)
+	if pageURL == "" {
+		return nil, fmt.Errorf("cannot determine URL for target %q", target.Host)
+	}
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	client := &http.Client{Timeout: timeout, Transport: opts.HTTPTransport()}
+
+	urls := []string{pageURL}
+	for _, ep := range opts.EndpointsOn(pageURL) {
+		if ep.Method == http.MethodGet {
+			urls = append(urls, ep.URL)
+		}
+	}
+
+	// A cookie or field is reported for the first page it was seen on,
+	// since a site sets its cookies and layout on every page.
+	reported := map[string]bool{}
+	for i, u := range urls {
+		if ctx.Err() != nil {
+			break
+		}
+		p, err := fetch(ctx, client, u)
+		if err != nil {
+			if i == 0 {
+				return nil, err
+			}
+			continue
+		}
+		for _, f := range inspectPage(p) {
+			key := f.Title + "\x00" + f.Metadata["location"] + "\x00" + f.Metadata["name"]
+			if f.Metadata["location"] == locationBody {
+				key += "\x00" + p.URL
+			}
+			if !reported[key] {
+				reported[key] = true
+				result.Findings = append(result.Findings, f)
+			}
+		}
+	}
+
+	result.CompletedAt = time.Now()
+	return result, nil
+}
+
+// fetch requests u and returns its response.
+func fetch(ctx context.Context, client *http.Client, u string) (*page, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP GET %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return nil, err
+	}
+	return &page{URL: u, ContentType: resp.Header.Get("Content-Type"), Cookies: resp.Cookies(), Body: body}, nil
+}
+
+// inspectPage returns the serialized data in p's cookies, its form fields,
+// and its body.
+func inspectPage(p *page) []types.Finding {
+	var findings []types.Finding
+	for _, c := range p.Cookies {
+		if ind, ok := inspect(c.Value); ok {
+			findings = append(findings, serializedFinding(p.URL, locationCookie, c.Name, c.Value, ind))
+		}
+	}
+
+	if bytes.HasPrefix(p.Body, javaMagic) || strings.Contains(p.ContentType, "java-serialized-object") {
+		findings = append(findings, serializedFinding(p.URL, locationBody, "", string(p.Body), indicator{Format: formatJava, Encoding: "raw", Object: true}))
+	}
+	if !strings.Contains(p.ContentType, "html") {
+		return findings
+	}
+
+	fields := inputFields(string(p.Body))
+	for _, field := range fields {
+		if field[0] == "__VIEWSTATE" {
+			if _, encrypted := fieldValue(fields, "__VIEWSTATEENCRYPTED"); encrypted {
+				continue
+			}
+			if viewStateMAC(field[1]) == viewStateUnsigned {
+				findings = append(findings, viewStateFinding(p.URL, field[1]))
+			}
+			continue
+		}
+		if ind, ok := inspect(field[1]); ok {
+			findings = append(findings, serializedFinding(p.URL, locationField, field[0], field[1], ind))
+		}
+	}
+	return findings
+}
+
+// inputFields returns the name and value of each <input> in body.
+func inputFields(body string) [][2]string {
+	var fields [][2]string
+	for _, tag := range inputTag.FindAllString(body, -1) {
+		attrs := map[string]string{}
+		for _, a := range attribute.FindAllStringSubmatch(tag, -1) {
+			name := strings.ToLower(a[1])
+			if _, ok := attrs[name]; !ok {
+				attrs[name] = html.UnescapeString(a[2] + a[3] + a[4])
+			}
+		}
+		if name := attrs["name"]; name != "" {
+			fields = append(fields, [2]string{name, attrs["value"]})
+		}
+	}
+	return fields
+}
+
+// fieldValue returns the value of the named field in fields.
+func fieldValue(fields [][2]string, name string) (string, bool) {
+	for _, f := range fields {
+		if strings.EqualFold(f[0], name) {
+			return f[1], true
+		}
+	}
+	return "", false
+}
+
+// serializedFinding reports Java or PHP serialized data found at location.
+func serializedFinding(pageURL, location, name, value string, ind indicator) types.Finding {
+	where := location
+	if name != "" {
+		where = fmt.Sprintf("%s %q", location, name)
+	}
+	metadata := map[string]string{
+		"url":      pageURL,
+		"format":   ind.Format,
+		"location": location,
+		"encoding": ind.Encoding,
+	}
+	if name != "" {
+		metadata["name"] = name
+	}
+
+	// Data the client sends back can be replaced by an attacker's; a
+	// response body shows the application speaks the format.
+	severity := types.SeverityHigh
+	if location == locationBody {
+		severity = types.SeverityMedium
+	}
+
+	if ind.Format == formatJava {
+		return types.Finding{
+			Title:       "Java serialized object",
+			Description: fmt.Sprintf("The %s of %s holds a Java serialized object (%s). An application that deserializes objects clients can modify runs whatever gadget chains its classpath allows, which commonly leads to remote code execution.", where, pageURL, ind.Encoding),
+			Severity:    severity,
+			Evidence:    fmt.Sprintf("%s: %s", where, shorten(value)),
+			Remediation: "Do not deserialize data from clients with ObjectInputStream. Send state as JSON or keep it server-side; where serialization cannot be avoided, sign the data and restrict the classes allowed with an ObjectInputFilter.",
+			Metadata:    metadata,
+		}
+	}
+
+	description := fmt.Sprintf("The %s of %s holds PHP serialized data (%s).", where, pageURL, ind.Encoding)
+	if ind.Object {
+		metadata["class"] = ind.Class
+		description = fmt.Sprintf("The %s of %s holds a serialized PHP object of class %s (%s).", where, pageURL, ind.Class, ind.Encoding)
+	} else if severity == types.SeverityHigh {
+		severity = types.SeverityMedium
+	}
+	return types.Finding{
+		Title:       "PHP serialized data",
+		Description: description + " Passing data clients can modify to unserialize() lets them instantiate any class the application loads, whose magic methods can be chained into file writes or code execution.",
+		Severity:    severity,
+		Evidence:    fmt.Sprintf("%s: %s", where, shorten(value)),
+		Remediation: "Do not call unserialize() on client data; use json_encode and json_decode instead, or pass ['allowed_classes' => false] and sign the value with an HMAC.",
+		Metadata:    metadata,
+	}
+}
+
+// viewStateFinding reports an ASP.NET ViewState sent without a MAC.
+func viewStateFinding(pageURL, value string) types.Finding {
+	return types.Finding{
+		Title:       ".NET ViewState without MAC",
+		Description: fmt.Sprintf("The __VIEWSTATE of %s is neither signed nor encrypted: nothing follows its serialized data. The server deserializes whatever ViewState a client posts back, which with ObjectStateFormatter gadgets leads to remote code execution.", pageURL),
+		Severity:    types.SeverityHigh,
+		Evidence:    "__VIEWSTATE: " + shorten(value),
+		Remediation: "Enable ViewState MAC validation (enableViewStateMac, on by default since .NET 4.5.2), set an explicit machineKey that is kept secret, and consider ViewStateEncryptionMode=\"Always\".",
+		Metadata: map[string]string{
+			"url":      pageURL,
+			"format":   "viewstate",
+			"location": locationField,
+			"name":     "__VIEWSTATE",
+		},
+	}
+}
+
+// shorten cuts value to maxShown characters for evidence.
+func shorten(value string) string {
+	if len(value) <= maxShown {
+		return fmt.Sprintf("%q", value)
+	}
+	return fmt.Sprintf("%q... (%d bytes)", value[:maxShown], len(value))
+}
+
+// resolveURL determines the target URL from the Target struct.
+func resolveURL(target types.Target) string {
+	if target.URL != "" {
+		return target.URL
+	}
+	scheme := target.Scheme
+	if scheme == "" {
+		scheme = "https"
+	}
+	if target.Host == "" {
+		return ""
+	}
+	return scheme + "://" + target.URLHost()
+}
@@ -0,0 +1,151 @@
+package deserialization
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	javaBase64 = "rO0ABXNyAA5qYXZhLnV0aWwuRGF0ZQ=="
+	// A ViewState holding Pair(Pair("1234567890", null), null), and the same
+	// with a SHA1-sized MAC after it.
+	unsignedViewState = "/wEPDwUKMTIzNDU2Nzg5MGRk"
+	signedViewState   = "/wEPDwUKMTIzNDU2Nzg5MGRkAAECAwQFBgcICQoLDA0ODxAREhM="
+)
+
+func TestInspect(t *testing.T) {
+	tests := []struct {
+		value    string
+		format   string
+		encoding string
+		class    string
+	}{
+		{javaBase64, formatJava, "base64", ""},
+		{"aced00057372000e6a6176612e7574696c2e44617465", formatJava, "hex", ""},
+		{"H4sIAAAAAAACA1vzloG1uIiBLyuxLFGvtCQzR88lsSQVAFgvdBMWAAAA", formatJava, "gzip+base64", ""},
+		{`O:4:"User":1:{s:4:"name";s:5:"alice";}`, formatPHP, "raw", "User"},
+		{`O%3A4%3A%22User%22%3A1%3A%7Bs%3A4%3A%22name%22%3Bs%3A5%3A%22alice%22%3B%7D`, formatPHP, "url", "User"},
+		{"TzoxMDoiVXNlclByZWZzIjoxOntzOjU6InRoZW1lIjtzOjQ6ImRhcmsiO30=", formatPHP, "base64", "UserPrefs"},
+		{`a:2:{i:0;s:1:"a";i:1;s:1:"b";}`, formatPHP, "raw", ""},
+	}
+	for _, tt := range tests {
+		ind, ok := inspect(tt.value)
+		require.True(t, ok, tt.value)
+		assert.Equal(t, tt.format, ind.Format, tt.value)
+		assert.Equal(t, tt.encoding, ind.Encoding, tt.value)
+		assert.Equal(t, tt.class, ind.Class, tt.value)
+		assert.Equal(t, tt.format == formatJava || tt.class != "", ind.Object, tt.value)
+	}
+
+	for _, value := range []string{"", "abc123", "eyJhbGciOiJIUzI1NiJ9", "s%3Aabc.def", "dark", "a:b:c"} {
+		_, ok := inspect(value)
+		assert.False(t, ok, value)
+	}
+}
+
+func TestViewStateMAC(t *testing.T) {
+	assert.Equal(t, viewStateUnsigned, viewStateMAC(unsignedViewState))
+	assert.Equal(t, viewStateSigned, viewStateMAC(signedViewState))
+
+	// Triplet("ab", HybridDictionary{"k": true}, ArrayList{300, "k"}), with
+	// and without a SHA256 MAC.
+	assert.Equal(t, viewStateUnsigned, viewStateMAC("/wEQBQJhYhgBHgFrZxYCAqwCHwA="))
+	assert.Equal(t, viewStateSigned, viewStateMAC("/wEQBQJhYhgBHgFrZxYCAqwCHwAAAQIDBAUGBwgJCgsMDQ4PEBESExQVFhcYGRobHB0eHw=="))
+
+	for _, value := range []string{"", "not base64!", "AAAA", "/wEPDwUK", "/wH/"} {
+		assert.Equal(t, viewStateUnknown, viewStateMAC(value), value)
+	}
+}
+
+func TestScanner_CookiesAndFields(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "JSESSIONID", Value: "0A1B2C3D"})
+		http.SetCookie(w, &http.Cookie{Name: "remember", Value: javaBase64})
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<form method="post">
+<input type="hidden" name="__VIEWSTATE" id="__VIEWSTATE" value="%s" />
+<input type="hidden" name="cart" value='a:1:{i:0;s:3:"abc";}'>
+<input type="text" name="q" value="">
+</form>`, unsignedViewState)
+	}))
+	defer srv.Close()
+
+	target := types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}
+	result, err := New().Run(context.Background(), target, scanner.DefaultOptions())
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 3)
+
+	java := result.Findings[0]
+	assert.Equal(t, "Java serialized object", java.Title)
+	assert.Equal(t, types.SeverityHigh, java.Severity)
+	assert.Equal(t, "cookie", java.Metadata["location"])
+	assert.Equal(t, "remember", java.Metadata["name"])
+	assert.Equal(t, "base64", java.Metadata["encoding"])
+
+	viewState := result.Findings[1]
+	assert.Equal(t, ".NET ViewState without MAC", viewState.Title)
+	assert.Equal(t, types.SeverityHigh, viewState.Severity)
+
+	php := result.Findings[2]
+	assert.Equal(t, "PHP serialized data", php.Title)
+	assert.Equal(t, types.SeverityMedium, php.Severity, "arrays are not objects")
+	assert.Equal(t, "form field", php.Metadata["location"])
+	assert.Equal(t, "cart", php.Metadata["name"])
+}
+
+func TestScanner_SignedAndEncryptedViewState(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/encrypted" {
+			fmt.Fprintf(w, `<input name="__VIEWSTATE" value="%s"><input name="__VIEWSTATEENCRYPTED" value="">`, unsignedViewState)
+			return
+		}
+		fmt.Fprintf(w, `<input name="__VIEWSTATE" value="%s">`, signedViewState)
+	}))
+	defer srv.Close()
+
+	opts := scanner.DefaultOptions()
+	opts.Endpoints = []types.Endpoint{{Method: http.MethodGet, URL: srv.URL + "/encrypted"}}
+	target := types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}
+	result, err := New().Run(context.Background(), target, opts)
+	require.NoError(t, err)
+	assert.Empty(t, result.Findings)
+}
+
+func TestScanner_JavaResponseBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-java-serialized-object")
+		w.Write([]byte{0xAC, 0xED, 0x00, 0x05, 0x73, 0x72})
+	}))
+	defer srv.Close()
+
+	target := types.Target{URL: srv.URL + "/invoker", Host: "127.0.0.1", Scheme: "http"}
+	result, err := New().Run(context.Background(), target, scanner.DefaultOptions())
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 1)
+	assert.Equal(t, types.SeverityMedium, result.Findings[0].Severity)
+	assert.Equal(t, "response body", result.Findings[0].Metadata["location"])
+}
+
+func TestScanner_ReportsCookieOnce(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "remember", Value: javaBase64})
+		w.Header().Set("Content-Type", "text/html")
+	}))
+	defer srv.Close()
+
+	opts := scanner.DefaultOptions()
+	opts.Endpoints = []types.Endpoint{{Method: http.MethodGet, URL: srv.URL + "/account"}}
+	target := types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}
+	result, err := New().Run(context.Background(), target, opts)
+	require.NoError(t, err)
+	assert.Len(t, result.Findings, 1)
+}
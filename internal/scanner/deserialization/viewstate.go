@@ -0,0 +1,281 @@
+package deserialization
+
+import (
+	"encoding/base64"
+	"errors"
+	"unicode/utf8"
+)
+
+// ObjectStateFormatter tokens, the format ASP.NET serializes ViewState in.
+const (
+	tokenInt16            = 1
+	tokenInt32            = 2
+	tokenByte             = 3
+	tokenChar             = 4
+	tokenString           = 5
+	tokenDateTime         = 6
+	tokenDouble           = 7
+	tokenSingle           = 8
+	tokenColor            = 9
+	tokenKnownColor       = 10
+	tokenIntEnum          = 11
+	tokenEmptyColor       = 12
+	tokenPair             = 15
+	tokenTriplet          = 16
+	tokenArray            = 20
+	tokenStringArray      = 21
+	tokenArrayList        = 22
+	tokenHashtable        = 23
+	tokenHybridDictionary = 24
+	tokenType             = 25
+	tokenUnit             = 27
+	tokenEmptyUnit        = 28
+	tokenIndexedStringAdd = 30
+	tokenIndexedString    = 31
+	tokenStringFormatted  = 40
+	tokenTypeRefAdd       = 41
+	tokenTypeRefAddLocal  = 42
+	tokenTypeRef          = 43
+	tokenBinarySerialized = 50
+	tokenSparseArray      = 60
+	tokenNull             = 100
+	tokenEmptyString      = 101
+	tokenZeroInt32        = 102
+	tokenTrue             = 103
+	tokenFalse            = 104
+)
+
+// maxViewStateDepth bounds how deeply nested a ViewState is parsed.
+const maxViewStateDepth = 64
+
+// macSizes are the sizes of the HMAC ASP.NET appends to a ViewState:
+// SHA1, SHA256, SHA384, and SHA512.
+var macSizes = map[int]bool{20: true, 32: true, 48: true, 64: true}
+
+// ViewState protection, as far as the serialized data tells.
+const (
+	viewStateUnsigned = "unsigned"
+	viewStateSigned   = "signed"
+	viewStateUnknown  = "unknown"
+)
+
+var errViewState = errors.New("malformed or unsupported ViewState")
+
+// viewStateMAC reports whether a __VIEWSTATE value carries a MAC: it parses
+// the serialized object graph and looks at what follows it. Nothing means
+// the ViewState is unsigned, and the server deserializes whatever a client
+// sends; a hash-sized tail means it is signed. A value that does not parse,
+// such as an encrypted one, is unknown.
+func viewStateMAC(value string) string {
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(data) < 2 || data[0] != 0xFF || data[1] != 0x01 {
+		return viewStateUnknown
+	}
+	r := &viewStateReader{data: data, pos: 2}
+	if err := r.object(0); err != nil {
+		return viewStateUnknown
+	}
+	switch rest := len(data) - r.pos; {
+	case rest == 0:
+		return viewStateUnsigned
+	case macSizes[rest]:
+		return viewStateSigned
+	}
+	return viewStateUnknown
+}
+
+// viewStateReader reads an ObjectStateFormatter stream.
+type viewStateReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *viewStateReader) byte() (byte, error) {
+	if r.pos >= len(r.data) {
+		return 0, errViewState
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *viewStateReader) skip(n int) error {
+	if n < 0 || n > len(r.data)-r.pos {
+		return errViewState
+	}
+	r.pos += n
+	return nil
+}
+
+// encoded reads a 7-bit encoded integer.
+func (r *viewStateReader) encoded() (int, error) {
+	n := 0
+	for shift := 0; shift < 35; shift += 7 {
+		b, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		n |= int(b&0x7F) << shift
+		if b&0x80 == 0 {
+			return n, nil
+		}
+	}
+	return 0, errViewState
+}
+
+// count reads a collection's length, which cannot exceed the bytes left
+// since every item takes at least one.
+func (r *viewStateReader) count() (int, error) {
+	n, err := r.encoded()
+	if err != nil || n > len(r.data)-r.pos {
+		return 0, errViewState
+	}
+	return n, nil
+}
+
+// string skips a length-prefixed string.
+func (r *viewStateReader) string() error {
+	n, err := r.encoded()
+	if err != nil {
+		return err
+	}
+	return r.skip(n)
+}
+
+// typeRef skips a type, named or referring to one named before.
+func (r *viewStateReader) typeRef() error {
+	token, err := r.byte()
+	if err != nil {
+		return err
+	}
+	switch token {
+	case tokenTypeRefAdd, tokenTypeRefAddLocal:
+		return r.string()
+	case tokenTypeRef:
+		_, err := r.encoded()
+		return err
+	}
+	return errViewState
+}
+
+// objects skips n objects.
+func (r *viewStateReader) objects(n, depth int) error {
+	for i := 0; i < n; i++ {
+		if err := r.object(depth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// object skips one serialized object.
+func (r *viewStateReader) object(depth int) error {
+	if depth > maxViewStateDepth {
+		return errViewState
+	}
+	token, err := r.byte()
+	if err != nil {
+		return err
+	}
+	switch token {
+	case tokenNull, tokenEmptyString, tokenZeroInt32, tokenTrue, tokenFalse, tokenEmptyColor, tokenEmptyUnit:
+		return nil
+	case tokenInt16:
+		return r.skip(2)
+	case tokenByte, tokenIndexedString:
+		return r.skip(1)
+	case tokenColor, tokenSingle:
+		return r.skip(4)
+	case tokenDateTime, tokenDouble:
+		return r.skip(8)
+	case tokenUnit:
+		return r.skip(12)
+	case tokenInt32, tokenKnownColor:
+		_, err := r.encoded()
+		return err
+	case tokenChar:
+		if r.pos == len(r.data) {
+			return errViewState
+		}
+		_, size := utf8.DecodeRune(r.data[r.pos:])
+		return r.skip(size)
+	case tokenString, tokenIndexedStringAdd:
+		return r.string()
+	case tokenType:
+		return r.typeRef()
+	case tokenIntEnum:
+		if err := r.typeRef(); err != nil {
+			return err
+		}
+		_, err := r.encoded()
+		return err
+	case tokenStringFormatted:
+		if err := r.typeRef(); err != nil {
+			return err
+		}
+		return r.string()
+	case tokenBinarySerialized:
+		n, err := r.encoded()
+		if err != nil {
+			return err
+		}
+		return r.skip(n)
+	case tokenPair:
+		return r.objects(2, depth+1)
+	case tokenTriplet:
+		return r.objects(3, depth+1)
+	case tokenArrayList:
+		n, err := r.count()
+		if err != nil {
+			return err
+		}
+		return r.objects(n, depth+1)
+	case tokenHashtable, tokenHybridDictionary:
+		n, err := r.count()
+		if err != nil {
+			return err
+		}
+		return r.objects(2*n, depth+1)
+	case tokenArray:
+		if err := r.typeRef(); err != nil {
+			return err
+		}
+		n, err := r.count()
+		if err != nil {
+			return err
+		}
+		return r.objects(n, depth+1)
+	case tokenStringArray:
+		n, err := r.count()
+		if err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			if err := r.string(); err != nil {
+				return err
+			}
+		}
+		return nil
+	case tokenSparseArray:
+		if err := r.typeRef(); err != nil {
+			return err
+		}
+		if _, err := r.encoded(); err != nil {
+			return err
+		}
+		n, err := r.count()
+		if err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			if _, err := r.encoded(); err != nil {
+				return err
+			}
+			if err := r.object(depth + 1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return errViewState
+}
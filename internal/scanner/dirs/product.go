@@ -0,0 +1,64 @@
+package dirs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/scanner/tech"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// maxIdentified caps how many admin panels found are fetched again to
+// identify them.
+const maxIdentified = 10
+
+// identifyProducts names the product behind the admin panels found, by the
+// favicon each page links to, recording it as the finding's "product".
+// Pages without an icon link are left alone, since the site's /favicon.ico
+// need not be the panel's.
+func identifyProducts(ctx context.Context, client *http.Client, result *types.ScanResult, opts scanner.Options) {
+	// The favicon database is configured on the tech scanner.
+	favicons, err := tech.LoadFavicons(opts.ForScanner("tech").StringArg("favicons"))
+	if err != nil {
+		opts.Logf("dirs", scanner.LogWarn, "loading favicons: %v", err)
+		return
+	}
+
+	identified := 0
+	for i := range result.Findings {
+		f := &result.Findings[i]
+		if f.Metadata["status_code"] != "200" || !adminPath.MatchString(f.Metadata["path"]) {
+			continue
+		}
+		if identified == maxIdentified || ctx.Err() != nil {
+			return
+		}
+		identified++
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.Metadata["url"], nil)
+		if err != nil {
+			continue
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			continue
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		resp.Body.Close()
+
+		for _, iconURL := range tech.IconLinks(f.Metadata["url"], string(body)) {
+			icon, err := tech.FetchIcon(ctx, client, iconURL)
+			if err != nil {
+				continue
+			}
+			if product, ok := favicons[tech.FaviconHash(icon)]; ok {
+				f.Metadata["product"] = product
+				f.Description += fmt.Sprintf("; its favicon identifies it as %s", product)
+				break
+			}
+		}
+	}
+}
@@ -3,7 +3,9 @@ package dirs
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptrace"
 	"strings"
 	"sync"
 	"time"
@@ -23,6 +25,14 @@ func New() *Scanner {
 func (s *Scanner) Name() string        { return "dirs" }
 func (s *Scanner) Description() string { return "Directory and path enumeration" }
 
+// defaultTarpitAfter is how many paths the scan requests before judging
+// whether the target is a tarpit.
+const defaultTarpitAfter = 50
+
+// maxBody is how much of a response is read. Longer ones are taken not to
+// end, as a tarpit's endless streams do not.
+const maxBody = 1 << 20
+
 func (s *Scanner) Run(ctx context.Context, target types.Target, opts scanner.Options) (*types.ScanResult, error) {
 	result := &types.ScanResult{
 		ScannerName: s.Name(),
@@ -30,17 +40,11 @@ func (s *Scanner) Run(ctx context.Context, target types.Target, opts scanner.Opt
 		StartedAt:   time.Now(),
 	}
 
-	wordlistPath := ""
-	if opts.ExtraArgs != nil {
-		if wl, ok := opts.ExtraArgs["wordlist"].(string); ok {
-			wordlistPath = wl
-		}
-	}
-
-	paths, err := LoadWordlist(wordlistPath)
+	wordlist, err := OpenWordlist(opts.StringArg("wordlist"))
 	if err != nil {
 		return nil, fmt.Errorf("loading wordlist: %w", err)
 	}
+	defer wordlist.Close()
 
 	baseURL := buildBaseURL(target)
 
@@ -53,67 +57,139 @@ func (s *Scanner) Run(ctx context.Context, target types.Target, opts scanner.Opt
 		timeout = 5 * time.Second
 	}
 
+	tarpitAfter := defaultTarpitAfter
+	if _, ok := opts.ExtraArgs["tarpit_after"]; ok {
+		tarpitAfter = opts.IntArg("tarpit_after") // 0 disables the check
+	}
+	tarpit := scanner.NewTarpitDetector(tarpitAfter, timeout/2)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	client := &http.Client{
-		Timeout: timeout,
+		Timeout:   timeout,
+		Transport: opts.HTTPTransport(),
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
 		},
 	}
 
-	sem := make(chan struct{}, concurrency)
+	// Sites serving a page for every path would have every path of the list
+	// found, so paths are only reported when their page is not that one.
+	soft404 := scanner.DetectSoftNotFound(ctx, client, baseURL)
+	if soft404 != nil {
+		opts.Logf(s.Name(), scanner.LogInfo, "%s serves a page for paths it does not have; ignoring paths that get it", baseURL)
+	}
+
+	// Paths are read from the list as workers are ready for them, so only
+	// the ones in flight are held in memory. Progress is how far through the
+	// file the scan is, in bytes, since the number of entries is not known
+	// up front.
+	limiter := scanner.NewAdaptiveLimiter(1, concurrency)
+	size := int(wordlist.Size())
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
-	for _, path := range paths {
-		select {
-		case <-ctx.Done():
-			result.CompletedAt = time.Now()
-			return result, nil
-		default:
-		}
-
+	queue := make(chan string)
+	for range concurrency {
 		wg.Add(1)
-		go func(p string) {
+		go func() {
 			defer wg.Done()
-
-			select {
-			case sem <- struct{}{}:
-				defer func() { <-sem }()
-			case <-ctx.Done():
-				return
+			for p := range queue {
+				if opts.Gate.Wait(ctx) != nil || limiter.Acquire(ctx) != nil {
+					continue
+				}
+
+				start := time.Now()
+				finding, ok, failed, signs := probe(ctx, client, baseURL, p, soft404, timeout/2)
+				limiter.Release(time.Since(start), failed && ctx.Err() == nil)
+				opts.ReportProgress(s.Name(), int(wordlist.Offset()), size)
+				if ctx.Err() == nil && tarpit.Observe(signs...) {
+					cancel()
+				}
+				if !ok {
+					continue
+				}
+
+				mu.Lock()
+				result.Findings = append(result.Findings, finding)
+				mu.Unlock()
 			}
-
-			finding, ok := probe(ctx, client, baseURL, p)
-			if !ok {
-				return
-			}
-
-			mu.Lock()
-			result.Findings = append(result.Findings, finding)
-			mu.Unlock()
-		}(path)
+		}()
 	}
 
+	// At safe intensity only the start of the list, its most common
+	// paths, is tried.
+	budget := opts.Budget("dirs.paths")
+	sent := 0
+feed:
+	for path, ok := wordlist.Next(); ok; path, ok = wordlist.Next() {
+		if sent == budget {
+			opts.Logf(s.Name(), scanner.LogInfo, "stopped after %d paths at %s intensity", budget, opts.Intensity)
+			break
+		}
+		select {
+		case queue <- path:
+			sent++
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(queue)
 	wg.Wait()
+
+	if f, ok := tarpit.Finding(s.Name()); ok {
+		opts.Logf(s.Name(), scanner.LogWarn, "stopped early: %s appears to be a tarpit", baseURL)
+		result.Findings = append(result.Findings, f)
+		result.Metadata = limiter.Metadata()
+		result.CompletedAt = time.Now()
+		return result, nil
+	}
+	if err := wordlist.Err(); err != nil && ctx.Err() == nil {
+		return nil, fmt.Errorf("reading wordlist: %w", err)
+	}
+	identifyProducts(ctx, client, result, opts)
+	addScreenshots(ctx, result, baseURL, opts)
+	result.Metadata = limiter.Metadata()
 	result.CompletedAt = time.Now()
 	return result, nil
 }
 
 // probe sends an HTTP request to baseURL+path and returns a Finding if the
-// response status is noteworthy (200, 301, 302, 403).
-func probe(ctx context.Context, client *http.Client, baseURL, path string) (types.Finding, bool) {
+// response status is noteworthy (200, 301, 302, 403) and, for 200 and 403,
+// the page is not the one soft404 says the site serves for any path. failed
+// reports a request that errored or got a 5xx response, a sign the target is
+// struggling. signs are the signs of a tarpit the request showed: an answer
+// that took slow or longer once connected, or a response that did not end.
+func probe(ctx context.Context, client *http.Client, baseURL, path string, soft404 *scanner.SoftNotFound, slow time.Duration) (finding types.Finding, found, failed bool, signs []string) {
 	url := baseURL + path
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	var connected time.Time
+	trace := &httptrace.ClientTrace{GotConn: func(httptrace.GotConnInfo) { connected = time.Now() }}
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), http.MethodGet, url, nil)
 	if err != nil {
-		return types.Finding{}, false
+		return types.Finding{}, false, false, nil
 	}
 
 	resp, err := client.Do(req)
+	if !connected.IsZero() && time.Since(connected) >= slow {
+		signs = append(signs, scanner.TarpitSlow)
+	}
 	if err != nil {
-		return types.Finding{}, false
+		return types.Finding{}, false, true, signs
 	}
+	// Reading the body lets the connection be reused, and shows whether it
+	// ends.
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBody+1))
 	resp.Body.Close()
+	if len(body) > maxBody || (err != nil && ctx.Err() == nil) {
+		signs = append(signs, scanner.TarpitEndless)
+	}
+	if resp.StatusCode >= 500 {
+		return types.Finding{}, false, true, signs
+	}
+	if (resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusForbidden) && soft404.Matches(resp.StatusCode, string(body), path) {
+		return types.Finding{}, false, false, signs
+	}
 
 	switch resp.StatusCode {
 	case http.StatusOK:
@@ -126,7 +202,7 @@ func probe(ctx context.Context, client *http.Client, baseURL, path string) (type
 				"status_code": "200",
 				"url":         url,
 			},
-		}, true
+		}, true, false, signs
 
 	case http.StatusForbidden:
 		return types.Finding{
@@ -138,7 +214,7 @@ func probe(ctx context.Context, client *http.Client, baseURL, path string) (type
 				"status_code": "403",
 				"url":         url,
 			},
-		}, true
+		}, true, false, signs
 
 	case http.StatusMovedPermanently, http.StatusFound:
 		location := resp.Header.Get("Location")
@@ -152,10 +228,10 @@ func probe(ctx context.Context, client *http.Client, baseURL, path string) (type
 				"url":         url,
 				"location":    location,
 			},
-		}, true
+		}, true, false, signs
 
 	default:
-		return types.Finding{}, false
+		return types.Finding{}, false, false, signs
 	}
 }
 
@@ -170,5 +246,5 @@ func buildBaseURL(target types.Target) string {
 		scheme = "https"
 	}
 
-	return fmt.Sprintf("%s://%s", scheme, target.Host)
+	return fmt.Sprintf("%s://%s", scheme, target.URLHost())
 }
@@ -1,15 +1,21 @@
 package dirs
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/scanner/tech"
 	"github.com/buemura/hunter/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -60,6 +66,36 @@ func TestScanner_DiscoversAdmin(t *testing.T) {
 	assert.Equal(t, types.SeverityInfo, result.Findings[0].Severity)
 }
 
+func TestScanner_SafeIntensityLimitsPaths(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/p") {
+			mu.Lock()
+			requested = append(requested, r.URL.Path)
+			mu.Unlock()
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	var paths []byte
+	for i := range 300 {
+		paths = fmt.Appendf(paths, "/p%d\n", i)
+	}
+	wordlist := filepath.Join(t.TempDir(), "wordlist.txt")
+	require.NoError(t, os.WriteFile(wordlist, paths, 0644))
+
+	opts := scanner.DefaultOptions()
+	opts.Intensity = scanner.IntensitySafe
+	opts.ExtraArgs = map[string]interface{}{"wordlist": wordlist}
+
+	_, err := New().Run(context.Background(), types.Target{Host: "localhost", URL: srv.URL}, opts)
+	require.NoError(t, err)
+	assert.Len(t, requested, 250)
+	assert.NotContains(t, requested, "/p250", "only the start of the list is tried")
+}
+
 func TestScanner_DoesNotReport404(t *testing.T) {
 	srv := newTestServer()
 	defer srv.Close()
@@ -82,6 +118,29 @@ func TestScanner_DoesNotReport404(t *testing.T) {
 	assert.Empty(t, result.Findings)
 }
 
+func TestScanner_IgnoresSoft404(t *testing.T) {
+	// A single-page application serves its shell, with the path and a
+	// fresh nonce in it, for any path but the one it has.
+	var n atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin" {
+			fmt.Fprint(w, "<html><body><h1>Admin</h1><form><input name=user><input name=password type=password></form></body></html>")
+			return
+		}
+		fmt.Fprintf(w, `<html><head><script nonce="%x9z%d"></script></head><body><div id="app" data-path="%s"></div></body></html>`, n.Add(1)*7919, time.Now().UnixNano(), r.URL.Path)
+	}))
+	defer srv.Close()
+
+	wordlist := filepath.Join(t.TempDir(), "wordlist.txt")
+	require.NoError(t, os.WriteFile(wordlist, []byte("/admin\n/backup\n/config\n"), 0644))
+	opts := scanner.Options{Concurrency: 2, Timeout: 2 * time.Second, ExtraArgs: map[string]interface{}{"wordlist": wordlist}}
+
+	result, err := New().Run(context.Background(), types.Target{Host: "localhost", URL: srv.URL}, opts)
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 1)
+	assert.Equal(t, "/admin", result.Findings[0].Metadata["path"])
+}
+
 func TestScanner_Reports403AsLow(t *testing.T) {
 	srv := newTestServer()
 	defer srv.Close()
@@ -131,6 +190,57 @@ func TestScanner_ReportsRedirect(t *testing.T) {
 	assert.Equal(t, "/new-page", result.Findings[0].Metadata["location"])
 }
 
+func TestScanner_StopsAtTarpit(t *testing.T) {
+	chunk := bytes.Repeat([]byte("x"), 64<<10)
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		sign    string
+	}{
+		{"endless stream", func(w http.ResponseWriter, r *http.Request) {
+			for r.Context().Err() == nil {
+				if _, err := w.Write(chunk); err != nil {
+					return
+				}
+			}
+		}, scanner.TarpitEndless},
+		{"slow answers", func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(120 * time.Millisecond)
+			w.WriteHeader(http.StatusNotFound)
+		}, scanner.TarpitSlow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int64
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				tt.handler(w, r)
+			}))
+			defer srv.Close()
+
+			var paths []byte
+			for i := range 200 {
+				paths = fmt.Appendf(paths, "/p%d\n", i)
+			}
+			wordlist := filepath.Join(t.TempDir(), "wordlist.txt")
+			require.NoError(t, os.WriteFile(wordlist, paths, 0o644))
+
+			opts := scanner.Options{
+				Concurrency: 5,
+				Timeout:     200 * time.Millisecond,
+				ExtraArgs:   map[string]interface{}{"wordlist": wordlist, "tarpit_after": 10},
+			}
+			result, err := New().Run(context.Background(), types.Target{URL: srv.URL}, opts)
+			require.NoError(t, err)
+
+			last := result.Findings[len(result.Findings)-1]
+			assert.Equal(t, "Target appears to be a tarpit", last.Title)
+			assert.Equal(t, tt.sign, last.Metadata["tarpit_sign"])
+			assert.Less(t, requests.Load(), int64(200), "the scan stopped early")
+		})
+	}
+}
+
 func TestScanner_ContextCancellation(t *testing.T) {
 	srv := newTestServer()
 	defer srv.Close()
@@ -176,6 +286,40 @@ func TestLoadWordlist_CustomFile(t *testing.T) {
 	assert.Equal(t, []string{"/custom1", "/custom2", "/custom3"}, paths)
 }
 
+func TestOpenWordlist_Streams(t *testing.T) {
+	tmp := filepath.Join(t.TempDir(), "words.txt")
+	content := "# comment\r\n/admin\r\n\n  /login  \n/api"
+	require.NoError(t, os.WriteFile(tmp, []byte(content), 0644))
+
+	w, err := OpenWordlist(tmp)
+	require.NoError(t, err)
+	defer w.Close()
+	assert.Equal(t, int64(len(content)), w.Size())
+
+	var got []string
+	var offsets []int64
+	for line, ok := w.Next(); ok; line, ok = w.Next() {
+		got = append(got, line)
+		offsets = append(offsets, w.Offset())
+	}
+	require.NoError(t, w.Err())
+	assert.Equal(t, []string{"/admin", "/login", "/api"}, got)
+	assert.Equal(t, []int64{19, 31, int64(len(content))}, offsets, "offsets count skipped lines and line endings")
+}
+
+func TestOpenWordlist_Embedded(t *testing.T) {
+	w, err := OpenWordlist("")
+	require.NoError(t, err)
+	defer w.Close()
+
+	n := 0
+	for _, ok := w.Next(); ok; _, ok = w.Next() {
+		n++
+	}
+	assert.Greater(t, n, 10)
+	assert.Equal(t, w.Size(), w.Offset())
+}
+
 func TestLoadWordlist_FileNotFound(t *testing.T) {
 	_, err := LoadWordlist("/nonexistent/file.txt")
 	assert.Error(t, err)
@@ -216,3 +360,156 @@ func TestBuildBaseURL(t *testing.T) {
 		})
 	}
 }
+
+func TestScanner_ReportsProgress(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+
+	wordlist := filepath.Join(t.TempDir(), "wordlist.txt")
+	err := os.WriteFile(wordlist, []byte("/admin\n/secret\n/nonexistent\n"), 0644)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var maxDone, lastTotal int
+	opts := scanner.Options{
+		Concurrency: 2,
+		Timeout:     2 * time.Second,
+		ExtraArgs:   map[string]interface{}{"wordlist": wordlist},
+		Progress: func(name string, done, total int) {
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, "dirs", name)
+			if done > maxDone {
+				maxDone = done
+			}
+			lastTotal = total
+		},
+	}
+
+	_, err = New().Run(context.Background(), types.Target{Host: "localhost", URL: srv.URL}, opts)
+	require.NoError(t, err)
+	// Progress is measured in bytes of the wordlist read.
+	assert.Equal(t, 28, lastTotal)
+	assert.Equal(t, 28, maxDone)
+}
+
+func TestScanner_BacksOffOnServerErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	var paths []byte
+	for i := 0; i < 50; i++ {
+		paths = append(paths, fmt.Sprintf("/path%d\n", i)...)
+	}
+	wordlist := filepath.Join(t.TempDir(), "wordlist.txt")
+	require.NoError(t, os.WriteFile(wordlist, paths, 0644))
+
+	opts := scanner.Options{
+		Concurrency: 16,
+		Timeout:     2 * time.Second,
+		ExtraArgs:   map[string]interface{}{"wordlist": wordlist},
+	}
+	result, err := New().Run(context.Background(), types.Target{Host: "localhost", URL: srv.URL}, opts)
+	require.NoError(t, err)
+	assert.Empty(t, result.Findings)
+	assert.Equal(t, "1", result.Metadata["concurrency"])
+	assert.NotEqual(t, "0", result.Metadata["backoffs"])
+	assert.Equal(t, "1.00", result.Metadata["error_rate"])
+	assert.Contains(t, result.Metadata, "effective_rate")
+}
+
+func TestScanner_Screenshots(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+
+	// Find only needs the browser to exist; screenshot is stubbed.
+	chrome := filepath.Join(t.TempDir(), "chrome")
+	require.NoError(t, os.WriteFile(chrome, []byte("#!/bin/sh\n"), 0o755))
+	orig := screenshot
+	defer func() { screenshot = orig }()
+	var mu sync.Mutex
+	var shot []string
+	screenshot = func(ctx context.Context, browser, pageURL string, timeout time.Duration) ([]byte, error) {
+		mu.Lock()
+		shot = append(shot, pageURL)
+		mu.Unlock()
+		return []byte("PNG " + pageURL), nil
+	}
+
+	wordlist := filepath.Join(t.TempDir(), "wordlist.txt")
+	require.NoError(t, os.WriteFile(wordlist, []byte("/admin\n/secret\n/old-page\n"), 0644))
+	opts := scanner.Options{
+		Concurrency: 2,
+		Timeout:     2 * time.Second,
+		ExtraArgs:   map[string]interface{}{"wordlist": wordlist, "browser": chrome},
+	}
+
+	result, err := New().Run(context.Background(), types.Target{Host: "localhost", URL: srv.URL}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{srv.URL + "/", srv.URL + "/admin"}, shot, "forbidden and non-admin redirects are not screenshotted")
+
+	byPath := map[string]types.Finding{}
+	for _, f := range result.Findings {
+		byPath[f.Metadata["path"]] = f
+	}
+	require.Contains(t, byPath, "/")
+	assert.Equal(t, "Screenshot of the target's root page", byPath["/"].Title)
+	assert.Equal(t, []byte("PNG "+srv.URL+"/"), byPath["/"].Artifacts[0].Screenshot)
+	require.Len(t, byPath["/admin"].Artifacts, 1)
+	assert.Equal(t, []byte("PNG "+srv.URL+"/admin"), byPath["/admin"].Artifacts[0].Screenshot)
+	assert.Empty(t, byPath["/secret"].Artifacts)
+}
+
+func TestWorthScreenshot(t *testing.T) {
+	page := func(path, status string) types.Finding {
+		return types.Finding{Metadata: map[string]string{"path": path, "status_code": status}}
+	}
+	assert.True(t, worthScreenshot(page("/login.php", "200")))
+	assert.True(t, worthScreenshot(page("/docs/", "200")))
+	assert.True(t, worthScreenshot(page("/admin", "302")))
+	assert.False(t, worthScreenshot(page("/old-page", "301")))
+	assert.False(t, worthScreenshot(page("/backup.zip", "200")))
+	assert.False(t, worthScreenshot(page("/admin", "403")))
+}
+
+func TestScanner_IdentifiesAdminPanels(t *testing.T) {
+	icon := []byte("panel icon")
+	favicons := filepath.Join(t.TempDir(), "favicons.json")
+	require.NoError(t, os.WriteFile(favicons, []byte(fmt.Sprintf(`{"%d": "Example Panel"}`, tech.FaviconHash(icon))), 0644))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/admin", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><link rel="icon" href="/static/panel.ico">Sign in</html>`))
+	})
+	mux.HandleFunc("/static/panel.ico", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/x-icon")
+		w.Write(icon)
+	})
+	mux.HandleFunc("/docs", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><link rel="icon" href="/static/panel.ico">Docs</html>`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	wordlist := filepath.Join(t.TempDir(), "wordlist.txt")
+	require.NoError(t, os.WriteFile(wordlist, []byte("/admin\n/docs\n"), 0644))
+	opts := scanner.Options{
+		Timeout:     2 * time.Second,
+		ExtraArgs:   map[string]interface{}{"wordlist": wordlist},
+		ScannerArgs: map[string]map[string]interface{}{"tech": {"favicons": favicons}},
+	}
+
+	result, err := New().Run(context.Background(), types.Target{Host: "localhost", URL: srv.URL}, opts)
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 2)
+	for _, f := range result.Findings {
+		if f.Metadata["path"] == "/admin" {
+			assert.Equal(t, "Example Panel", f.Metadata["product"])
+			assert.Contains(t, f.Description, "identifies it as Example Panel")
+		} else {
+			assert.Empty(t, f.Metadata["product"], "only admin panels are identified")
+		}
+	}
+}
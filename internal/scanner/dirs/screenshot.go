@@ -0,0 +1,117 @@
+package dirs
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/buemura/hunter/internal/browser"
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// maxScreenshots caps how many found paths are screenshotted, besides the
+// root page, as each takes a browser start.
+const maxScreenshots = 10
+
+// adminPath matches paths that look like login pages and admin panels,
+// screenshotted first and even when they only redirect.
+var adminPath = regexp.MustCompile(`(?i)admin|manager|console|dashboard|panel|login|signin|portal|phpmyadmin|jenkins|grafana|kibana|wp-login`)
+
+// pageExtensions are the extensions of paths likely to be pages worth
+// looking at, rather than files such as backups or archives.
+var pageExtensions = map[string]bool{
+	"": true, ".php": true, ".html": true, ".htm": true,
+	".asp": true, ".aspx": true, ".jsp": true, ".cgi": true,
+}
+
+// screenshot renders a page and returns a PNG of it.
+// Extracted as a variable for testing.
+var screenshot = browser.Screenshot
+
+// addScreenshots attaches screenshots taken with the "browser" argument to
+// result: a finding for the root page at baseURL, and artifacts on the
+// findings for admin panels and other pages found. It does nothing without
+// a browser.
+func addScreenshots(ctx context.Context, result *types.ScanResult, baseURL string, opts scanner.Options) {
+	name := opts.StringArg("browser")
+	if name == "" {
+		return
+	}
+	chrome, err := browser.Find(name)
+	if err != nil {
+		opts.Logf("dirs", scanner.LogWarn, "screenshots skipped: %v", err)
+		return
+	}
+
+	take := func(url string) []byte {
+		png, err := screenshot(ctx, chrome, url, opts.Timeout)
+		if err != nil {
+			opts.Logf("dirs", scanner.LogWarn, "screenshot of %s: %v", url, err)
+			return nil
+		}
+		return png
+	}
+
+	if png := take(baseURL + "/"); png != nil {
+		result.Findings = append(result.Findings, types.Finding{
+			Title:       "Screenshot of the target's root page",
+			Description: "How the target's root page looks in a headless browser",
+			Severity:    types.SeverityInfo,
+			Metadata: map[string]string{
+				"path": "/",
+				"url":  baseURL + "/",
+			},
+			Artifacts: []types.Artifact{{Screenshot: png}},
+		})
+	}
+
+	var pages []int
+	for i, f := range result.Findings {
+		if worthScreenshot(f) {
+			pages = append(pages, i)
+		}
+	}
+	sort.SliceStable(pages, func(a, b int) bool {
+		pa, pb := result.Findings[pages[a]].Metadata["path"], result.Findings[pages[b]].Metadata["path"]
+		if adminA, adminB := adminPath.MatchString(pa), adminPath.MatchString(pb); adminA != adminB {
+			return adminA
+		}
+		return pa < pb
+	})
+	if len(pages) > maxScreenshots {
+		opts.Logf("dirs", scanner.LogInfo, "screenshotted %d of %d pages found", maxScreenshots, len(pages))
+		pages = pages[:maxScreenshots]
+	}
+	for _, i := range pages {
+		if ctx.Err() != nil {
+			return
+		}
+		f := &result.Findings[i]
+		if png := take(f.Metadata["url"]); png != nil {
+			f.Artifacts = append(f.Artifacts, types.Artifact{Screenshot: png})
+		}
+	}
+}
+
+// worthScreenshot reports whether f is a page found by probing worth a
+// screenshot: one that loaded, or an admin path that redirects, say to a
+// login form.
+func worthScreenshot(f types.Finding) bool {
+	p := f.Metadata["path"]
+	if p == "" || f.Metadata["status_code"] == "" {
+		return false
+	}
+	if !pageExtensions[strings.ToLower(path.Ext(strings.TrimRight(p, "/")))] {
+		return false
+	}
+	switch f.Metadata["status_code"] {
+	case "200":
+		return true
+	case "301", "302":
+		return adminPath.MatchString(p)
+	}
+	return false
+}
@@ -3,38 +3,108 @@ package dirs
 import (
 	"bufio"
 	_ "embed"
+	"io"
 	"os"
 	"strings"
+	"sync/atomic"
 )
 
 //go:embed wordlist.txt
 var defaultWordlist string
 
-// LoadWordlist loads paths from the given file. If path is empty, it falls
-// back to the embedded default wordlist.
-func LoadWordlist(path string) ([]string, error) {
+// maxWordlistLine is the longest line a wordlist may have.
+const maxWordlistLine = 1 << 20
+
+// Wordlist streams paths from a wordlist file, or the embedded default list,
+// one line at a time, so lists of millions of entries scan in constant
+// memory. Offset and Size measure how far through the file it is.
+type Wordlist struct {
+	sc     *bufio.Scanner
+	closer io.Closer
+	size   int64
+	offset atomic.Int64
+}
+
+// OpenWordlist opens the wordlist at path, or the embedded default wordlist
+// if path is empty. The caller must Close it.
+func OpenWordlist(path string) (*Wordlist, error) {
 	if path == "" {
-		return parseLines(defaultWordlist), nil
+		return newWordlist(strings.NewReader(defaultWordlist), nil, int64(len(defaultWordlist))), nil
 	}
 
-	data, err := os.ReadFile(path)
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
 	if err != nil {
+		f.Close()
 		return nil, err
 	}
+	return newWordlist(f, f, info.Size()), nil
+}
 
-	return parseLines(string(data)), nil
+func newWordlist(r io.Reader, closer io.Closer, size int64) *Wordlist {
+	w := &Wordlist{sc: bufio.NewScanner(r), closer: closer, size: size}
+	w.sc.Buffer(make([]byte, 64*1024), maxWordlistLine)
+	w.sc.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := bufio.ScanLines(data, atEOF)
+		w.offset.Add(int64(advance))
+		return advance, token, err
+	})
+	return w
 }
 
-// parseLines splits text into non-empty, trimmed lines, skipping comments.
-func parseLines(text string) []string {
-	var lines []string
-	sc := bufio.NewScanner(strings.NewReader(text))
-	for sc.Scan() {
-		line := strings.TrimSpace(sc.Text())
+// Next returns the next path, skipping blank lines and comments, or false
+// at the end of the list or on a read error (see Err).
+func (w *Wordlist) Next() (string, bool) {
+	for w.sc.Scan() {
+		line := strings.TrimSpace(w.sc.Text())
 		if line == "" || strings.HasPrefix(line, "#") {
 			continue
 		}
+		return line, true
+	}
+	return "", false
+}
+
+// Err returns the error that stopped Next, if any.
+func (w *Wordlist) Err() error {
+	return w.sc.Err()
+}
+
+// Offset returns how many bytes of the list have been read. It is safe to
+// call while another goroutine calls Next.
+func (w *Wordlist) Offset() int64 {
+	return w.offset.Load()
+}
+
+// Size returns the size of the list in bytes.
+func (w *Wordlist) Size() int64 {
+	return w.size
+}
+
+// Close closes the underlying file.
+func (w *Wordlist) Close() error {
+	if w.closer == nil {
+		return nil
+	}
+	return w.closer.Close()
+}
+
+// LoadWordlist loads every path from the given file into memory. If path is
+// empty, it falls back to the embedded default wordlist. Scans stream the
+// list with OpenWordlist instead.
+func LoadWordlist(path string) ([]string, error) {
+	w, err := OpenWordlist(path)
+	if err != nil {
+		return nil, err
+	}
+	defer w.Close()
+
+	var lines []string
+	for line, ok := w.Next(); ok; line, ok = w.Next() {
 		lines = append(lines, line)
 	}
-	return lines
+	return lines, w.Err()
 }
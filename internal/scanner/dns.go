@@ -0,0 +1,76 @@
+package scanner
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dnsTTL is how long a scan reuses the addresses a host resolved to.
+const dnsTTL = 5 * time.Minute
+
+// dnsCache remembers the addresses host names resolve to, so the many
+// connections of a scan look each host up once. Concurrent lookups of the
+// same host share one query, and failed lookups are not remembered.
+type dnsCache struct {
+	lookup func(ctx context.Context, host string) ([]string, error)
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*dnsEntry
+}
+
+type dnsEntry struct {
+	done    chan struct{} // closed once addrs and err are set
+	addrs   []string
+	err     error
+	expires time.Time
+}
+
+func newDNSCache(lookup func(ctx context.Context, host string) ([]string, error), ttl time.Duration) *dnsCache {
+	return &dnsCache{lookup: lookup, ttl: ttl, entries: make(map[string]*dnsEntry)}
+}
+
+// LookupHost returns the addresses of host, from the cache when it has
+// them.
+func (c *dnsCache) LookupHost(ctx context.Context, host string) ([]string, error) {
+	key := strings.ToLower(host)
+
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if ok {
+		select {
+		case <-e.done:
+			if time.Now().After(e.expires) {
+				ok = false
+			}
+		default:
+		}
+	}
+	if !ok {
+		e = &dnsEntry{done: make(chan struct{})}
+		c.entries[key] = e
+		c.mu.Unlock()
+
+		e.addrs, e.err = c.lookup(ctx, host)
+		e.expires = time.Now().Add(c.ttl)
+		if e.err != nil {
+			c.mu.Lock()
+			if c.entries[key] == e {
+				delete(c.entries, key)
+			}
+			c.mu.Unlock()
+		}
+		close(e.done)
+		return e.addrs, e.err
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-e.done:
+		return e.addrs, e.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
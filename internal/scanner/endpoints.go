@@ -0,0 +1,38 @@
+package scanner
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// EndpointsOn returns the imported endpoints (o.Endpoints) that are on the
+// host of baseURL, with relative URLs resolved against it and methods
+// defaulting to GET. It returns nil when none were imported or none are on
+// the host.
+func (o Options) EndpointsOn(baseURL string) []types.Endpoint {
+	base, err := url.Parse(baseURL)
+	if err != nil || len(o.Endpoints) == 0 {
+		return nil
+	}
+
+	var endpoints []types.Endpoint
+	for _, ep := range o.Endpoints {
+		u, err := url.Parse(ep.URL)
+		if err != nil {
+			continue
+		}
+		u = base.ResolveReference(u)
+		if !strings.EqualFold(u.Host, base.Host) {
+			continue
+		}
+		ep.URL = u.String()
+		if ep.Method == "" {
+			ep.Method = http.MethodGet
+		}
+		endpoints = append(endpoints, ep)
+	}
+	return endpoints
+}
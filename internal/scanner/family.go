@@ -0,0 +1,99 @@
+package scanner
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// Address families reported in the address_family metadata of findings.
+const (
+	FamilyIPv4 = "ipv4"
+	FamilyIPv6 = "ipv6"
+)
+
+// ParseIPVersion parses an --ip-version value: "4" or "6" (also "ipv4",
+// "ipv6"), or "" or "any" for either, which is returned as 0.
+func ParseIPVersion(s string) (int, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "any":
+		return 0, nil
+	case "4", FamilyIPv4:
+		return 4, nil
+	case "6", FamilyIPv6:
+		return 6, nil
+	}
+	return 0, fmt.Errorf("invalid IP version %q (available: 4, 6, any)", s)
+}
+
+// Network returns the network scanners dial: "tcp4" or "tcp6" when
+// IPVersion restricts the scan to one address family, else "tcp".
+func (o Options) Network() string {
+	switch o.IPVersion {
+	case 4:
+		return "tcp4"
+	case 6:
+		return "tcp6"
+	}
+	return "tcp"
+}
+
+// Family returns the address family the scan is restricted to, or "" if
+// either may be used.
+func (o Options) Family() string {
+	switch o.IPVersion {
+	case 4:
+		return FamilyIPv4
+	case 6:
+		return FamilyIPv6
+	}
+	return ""
+}
+
+// AddressFamily returns the family of addr, a connection's remote address:
+// FamilyIPv4 or FamilyIPv6, or "" if it is not an IP address.
+func AddressFamily(addr net.Addr) string {
+	if tcp, ok := addr.(*net.TCPAddr); ok {
+		return IPFamily(tcp.IP)
+	}
+	return ""
+}
+
+// IPFamily returns the family of ip: FamilyIPv4 or FamilyIPv6, or "" for
+// nil.
+func IPFamily(ip net.IP) string {
+	switch {
+	case ip == nil:
+		return ""
+	case ip.To4() != nil:
+		return FamilyIPv4
+	}
+	return FamilyIPv6
+}
+
+// labelFamily records the address family in the address_family metadata of
+// every finding in result that does not have one, when it is known: the
+// scan is restricted to one family, or the target is an IP address.
+// Scanners that dial themselves label findings with the family of the
+// connection.
+func labelFamily(result *types.ScanResult, target types.Target, opts Options) {
+	family := opts.Family()
+	if family == "" {
+		family = IPFamily(net.ParseIP(target.Host))
+	}
+	if result == nil || family == "" {
+		return
+	}
+	for i := range result.Findings {
+		f := &result.Findings[i]
+		if _, ok := f.Metadata["address_family"]; ok {
+			continue
+		}
+		if f.Metadata == nil {
+			f.Metadata = map[string]string{}
+		}
+		f.Metadata["address_family"] = family
+	}
+}
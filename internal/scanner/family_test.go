@@ -0,0 +1,57 @@
+package scanner
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIPVersion(t *testing.T) {
+	for input, want := range map[string]int{"": 0, "any": 0, "4": 4, "ipv4": 4, "6": 6, "IPv6": 6} {
+		got, err := ParseIPVersion(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	_, err := ParseIPVersion("5")
+	assert.Error(t, err)
+}
+
+func TestOptions_Network(t *testing.T) {
+	assert.Equal(t, "tcp", Options{}.Network())
+	assert.Equal(t, "tcp4", Options{IPVersion: 4}.Network())
+	assert.Equal(t, "tcp6", Options{IPVersion: 6}.Network())
+	assert.Equal(t, "", Options{}.Family())
+	assert.Equal(t, FamilyIPv6, Options{IPVersion: 6}.Family())
+}
+
+func TestAddressFamily(t *testing.T) {
+	assert.Equal(t, FamilyIPv4, AddressFamily(&net.TCPAddr{IP: net.ParseIP("127.0.0.1")}))
+	assert.Equal(t, FamilyIPv6, AddressFamily(&net.TCPAddr{IP: net.ParseIP("::1")}))
+	assert.Equal(t, "", AddressFamily(&net.UnixAddr{Name: "/tmp/sock"}))
+}
+
+func TestRunner_LabelsAddressFamily(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&mockScanner{name: "test"})
+	runner := NewRunner(reg)
+
+	result, err := runner.RunOne(context.Background(), "test", types.Target{Host: "::1"}, DefaultOptions())
+	require.NoError(t, err)
+	assert.Equal(t, FamilyIPv6, result.Findings[0].Metadata["address_family"])
+
+	opts := DefaultOptions()
+	opts.IPVersion = 4
+	result, err = runner.RunOne(context.Background(), "test", types.Target{Host: "example.com"}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, FamilyIPv4, result.Findings[0].Metadata["address_family"])
+
+	// A host name scanned over either family is left unlabelled.
+	result, err = runner.RunOne(context.Background(), "test", types.Target{Host: "example.com"}, DefaultOptions())
+	require.NoError(t, err)
+	assert.NotContains(t, result.Findings[0].Metadata, "address_family")
+}
@@ -0,0 +1,254 @@
+package forms
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/internal/crawl"
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/scanner/vuln"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// Scanner tests the HTML forms of the target's pages: for missing CSRF
+// protection, for password fields browsers may autocomplete, and for
+// injection through their fields. Scans that also run the csrf scanner set
+// the skip_csrf argument, leaving missing tokens to it.
+type Scanner struct{}
+
+// New creates a new forms scanner.
+func New() *Scanner {
+	return &Scanner{}
+}
+
+func (s *Scanner) Name() string        { return "forms" }
+func (s *Scanner) Description() string { return "HTML form security testing" }
+
+// Run tests the forms on the target's page and on the pages of the imported
+// and crawled requests (Options.Endpoints), each distinct form once.
+func (s *Scanner) Run(ctx context.Context, target types.Target, opts scanner.Options) (*types.ScanResult, error) {
+	result := &types.ScanResult{
+		ScannerName: s.Name(),
+		Target:      target,
+		StartedAt:   time.Now(),
+	}
+
+	targetURL := resolveURL(target)
+	if targetURL == "" {
+		return nil, fmt.Errorf("cannot determine URL for target %q", target.Host)
+	}
+
+	client := newClient(opts)
+	seen := map[string]bool{}
+	var forms []crawl.Form
+	var artifacts []types.Artifact
+	for _, page := range pages(targetURL, opts.EndpointsOn(targetURL)) {
+		if ctx.Err() != nil {
+			break
+		}
+		pageForms, artifact, err := fetchForms(ctx, client, page)
+		if err != nil {
+			if page == targetURL {
+				return nil, err
+			}
+			continue
+		}
+		for _, form := range pageForms {
+			// Forms in a shared layout, such as a search box, appear on
+			// every page.
+			if key := formKey(form); !seen[key] {
+				seen[key] = true
+				forms = append(forms, form)
+				artifacts = append(artifacts, artifact)
+			}
+		}
+	}
+	opts.Logf(s.Name(), scanner.LogInfo, "found %d forms", len(forms))
+
+	checkTokens := !opts.BoolArg("skip_csrf")
+	var endpoints []types.Endpoint
+	for i, form := range forms {
+		if f, ok := checkCSRF(form); ok && checkTokens {
+			f.Artifacts = []types.Artifact{artifacts[i]}
+			result.Findings = append(result.Findings, f)
+		}
+		if f, ok := checkAutocomplete(form); ok {
+			f.Artifacts = []types.Artifact{artifacts[i]}
+			result.Findings = append(result.Findings, f)
+		}
+		endpoints = append(endpoints, form.Endpoint())
+	}
+	result.Findings = append(result.Findings, vuln.InjectEndpoints(ctx, endpoints, opts)...)
+
+	result.CompletedAt = time.Now()
+	return result, nil
+}
+
+// checkCSRF reports a POST form without an anti-CSRF token: another site
+// can submit it with the user's cookies. Tokens are told as the csrf
+// scanner tells them.
+func checkCSRF(form crawl.Form) (types.Finding, bool) {
+	if form.Method != http.MethodPost {
+		return types.Finding{}, false
+	}
+	if _, ok := form.CSRFToken(); ok {
+		return types.Finding{}, false
+	}
+	return types.Finding{
+		Title:       "Form without CSRF protection",
+		Description: "A form that submits with POST has no anti-CSRF token, so another site can make a logged-in user's browser submit it. SameSite cookies reduce but do not remove the risk.",
+		Severity:    types.SeverityMedium,
+		Evidence:    fmt.Sprintf("Form on %s posting to %s has no hidden token field among: %s", form.Page, form.Action, fieldNames(form)),
+		Remediation: "Add a per-session or per-request anti-CSRF token to the form and check it on submission, or use your framework's CSRF protection.",
+		Metadata:    formMetadata(form, "csrf"),
+	}, true
+}
+
+// checkAutocomplete reports a form whose password fields browsers may
+// autocomplete and store.
+func checkAutocomplete(form crawl.Form) (types.Finding, bool) {
+	if form.Autocomplete == "off" {
+		return types.Finding{}, false
+	}
+	var fields []string
+	for _, field := range form.Fields {
+		if field.Type == "password" && field.Autocomplete != "off" && field.Autocomplete != "new-password" {
+			fields = append(fields, field.Name)
+		}
+	}
+	if len(fields) == 0 {
+		return types.Finding{}, false
+	}
+	return types.Finding{
+		Title:       "Password field allows autocomplete",
+		Description: "Browsers may save and fill in the password fields of this form, exposing the password to anyone using the same computer.",
+		Severity:    types.SeverityLow,
+		Evidence:    fmt.Sprintf("Form on %s posting to %s has password fields without autocomplete=\"off\": %s", form.Page, form.Action, strings.Join(fields, ", ")),
+		Remediation: "Set autocomplete=\"off\" on sensitive password fields, or \"new-password\" on those that set a password.",
+		Metadata:    formMetadata(form, "autocomplete"),
+	}, true
+}
+
+// Verify fetches the page of a csrf or autocomplete finding again and
+// checks its form the same way; injection findings are replayed by the vuln
+// scanner.
+func (s *Scanner) Verify(ctx context.Context, target types.Target, finding types.Finding, opts scanner.Options) (bool, error) {
+	var check func(crawl.Form) (types.Finding, bool)
+	switch c := finding.Metadata["check"]; c {
+	case "csrf":
+		check = checkCSRF
+	case "autocomplete":
+		check = checkAutocomplete
+	case "xss", "sqli":
+		return vuln.New().Verify(ctx, target, finding, opts)
+	default:
+		return false, fmt.Errorf("findings of the %q check cannot be verified", c)
+	}
+
+	forms, _, err := fetchForms(ctx, newClient(opts), finding.Metadata["page"])
+	if err != nil {
+		return false, err
+	}
+	for _, form := range forms {
+		if form.Method == finding.Metadata["method"] && form.Action == finding.Metadata["action"] {
+			_, found := check(form)
+			return found, nil
+		}
+	}
+	// The form is gone.
+	return false, nil
+}
+
+// formMetadata returns the metadata locating form, for a finding of check.
+func formMetadata(form crawl.Form, check string) map[string]string {
+	return map[string]string{
+		"check":  check,
+		"page":   form.Page,
+		"action": form.Action,
+		"method": form.Method,
+	}
+}
+
+// formKey identifies a form by where and how it submits and its fields.
+func formKey(form crawl.Form) string {
+	return form.Method + " " + form.Action + " " + fieldNames(form)
+}
+
+// fieldNames lists the names of form's named fields.
+func fieldNames(form crawl.Form) string {
+	var names []string
+	for _, field := range form.Fields {
+		if field.Name != "" {
+			names = append(names, field.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func newClient(opts scanner.Options) *http.Client {
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	return &http.Client{Timeout: timeout, Transport: opts.HTTPTransport()}
+}
+
+// fetchForms requests page and returns the forms on it, with the exchange
+// as an artifact. Pages that are not HTML have none.
+func fetchForms(ctx context.Context, client *http.Client, page string) ([]crawl.Form, types.Artifact, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, page, nil)
+	if err != nil {
+		return nil, types.Artifact{}, fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, types.Artifact{}, fmt.Errorf("HTTP GET %s: %w", page, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20)) // 1 MB limit
+	if err != nil {
+		return nil, types.Artifact{}, err
+	}
+	artifact := scanner.NewArtifact(req, nil, resp, body)
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, artifact, nil
+	}
+	return crawl.ParseForms(resp.Request.URL.String(), string(body)), artifact, nil
+}
+
+// pages returns the target URL and the distinct URLs of the GET requests in
+// endpoints.
+func pages(targetURL string, endpoints []types.Endpoint) []string {
+	pages := []string{targetURL}
+	seen := map[string]bool{targetURL: true}
+	for _, ep := range endpoints {
+		if ep.Method == http.MethodGet && !seen[ep.URL] {
+			seen[ep.URL] = true
+			pages = append(pages, ep.URL)
+		}
+	}
+	return pages
+}
+
+// resolveURL determines the target URL from the Target struct.
+func resolveURL(target types.Target) string {
+	if target.URL != "" {
+		return target.URL
+	}
+	scheme := target.Scheme
+	if scheme == "" {
+		scheme = "https"
+	}
+	if target.Host == "" {
+		return ""
+	}
+	return scheme + "://" + target.URLHost()
+}
@@ -0,0 +1,244 @@
+package forms
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// formServer serves page as the HTML of every GET and answers form
+// submissions with handle, if set.
+func formServer(t *testing.T, page *atomic.Value, handle http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && handle != nil {
+			handle(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, "<html><body>"+page.Load().(string)+"</body></html>")
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func staticPage(html string) *atomic.Value {
+	var v atomic.Value
+	v.Store(html)
+	return &v
+}
+
+func titles(findings []types.Finding) []string {
+	var out []string
+	for _, f := range findings {
+		out = append(out, f.Title)
+	}
+	return out
+}
+
+func TestScanner_NameAndDescription(t *testing.T) {
+	s := New()
+	assert.Equal(t, "forms", s.Name())
+	assert.Equal(t, "HTML form security testing", s.Description())
+}
+
+func TestScanner_FormWithoutCSRFToken(t *testing.T) {
+	srv := formServer(t, staticPage(`<form action="/transfer" method="post">
+		<input name="to"><input name="amount"></form>`), nil)
+
+	result, err := New().Run(context.Background(), types.Target{URL: srv.URL}, scanner.DefaultOptions())
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"Form without CSRF protection"}, titles(result.Findings))
+	f := result.Findings[0]
+	assert.Equal(t, types.SeverityMedium, f.Severity)
+	assert.Contains(t, f.Evidence, "to, amount")
+	assert.Equal(t, map[string]string{
+		"check":  "csrf",
+		"page":   srv.URL,
+		"action": srv.URL + "/transfer",
+		"method": http.MethodPost,
+	}, f.Metadata)
+	assert.Len(t, f.Artifacts, 1)
+}
+
+func TestScanner_FormWithCSRFToken(t *testing.T) {
+	srv := formServer(t, staticPage(`<form action="/transfer" method="post">
+		<input type="hidden" name="authenticity_token" value="f00">
+		<input name="to"></form>
+		<form action="/search"><input name="q"></form>`), nil)
+
+	result, err := New().Run(context.Background(), types.Target{URL: srv.URL}, scanner.DefaultOptions())
+	require.NoError(t, err)
+	assert.Empty(t, result.Findings, "POST forms with a token and GET forms need no CSRF protection")
+}
+
+func TestScanner_SkipCSRF(t *testing.T) {
+	srv := formServer(t, staticPage(`<form action="/transfer" method="post">
+		<input name="to"></form>`), nil)
+
+	opts := scanner.DefaultOptions()
+	opts.ExtraArgs = map[string]interface{}{"skip_csrf": true}
+	result, err := New().Run(context.Background(), types.Target{URL: srv.URL}, opts)
+	require.NoError(t, err)
+	assert.Empty(t, result.Findings, "missing tokens are left to the csrf scanner running alongside")
+}
+
+func TestScanner_PasswordAutocomplete(t *testing.T) {
+	srv := formServer(t, staticPage(`<form action="/login" method="post">
+		<input type="hidden" name="csrf_token" value="f00">
+		<input name="user"><input type="password" name="pass"></form>
+		<form action="/signup" method="post">
+		<input type="hidden" name="csrf_token" value="f00">
+		<input type="password" name="new" autocomplete="new-password"></form>
+		<form action="/admin" method="post" autocomplete="off">
+		<input type="hidden" name="csrf_token" value="f00">
+		<input type="password" name="pin"></form>`), nil)
+
+	result, err := New().Run(context.Background(), types.Target{URL: srv.URL}, scanner.DefaultOptions())
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"Password field allows autocomplete"}, titles(result.Findings))
+	f := result.Findings[0]
+	assert.Equal(t, types.SeverityLow, f.Severity)
+	assert.Equal(t, map[string]string{
+		"check":  "autocomplete",
+		"page":   srv.URL,
+		"action": srv.URL + "/login",
+		"method": http.MethodPost,
+	}, f.Metadata)
+	assert.Contains(t, f.Evidence, "pass")
+	assert.Len(t, f.Artifacts, 1)
+}
+
+func TestScanner_InjectsFormFields(t *testing.T) {
+	srv := formServer(t, staticPage(`<form action="/comment" method="post">
+		<input type="hidden" name="csrf_token" value="f00">
+		<textarea name="text"></textarea></form>`),
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			// The comment is echoed unencoded; the token is not.
+			fmt.Fprint(w, "<p>"+r.PostFormValue("text")+"</p>"+html.EscapeString(r.PostFormValue("csrf_token")))
+		})
+
+	result, err := New().Run(context.Background(), types.Target{URL: srv.URL}, scanner.DefaultOptions())
+	require.NoError(t, err)
+
+	require.NotEmpty(t, result.Findings)
+	for _, f := range result.Findings {
+		assert.Equal(t, "Potential reflected XSS", f.Title)
+		assert.Equal(t, "xss", f.Metadata["check"])
+		assert.Contains(t, f.Description, "text")
+	}
+}
+
+func TestScanner_FormsOnEndpointPages(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprint(w, `<form action="/search"><input name="q"></form>`)
+		case "/account":
+			fmt.Fprint(w, `<form action="/search"><input name="q"></form>
+				<form action="/email" method="post"><input name="email"></form>`)
+		}
+	}))
+	defer srv.Close()
+
+	opts := scanner.DefaultOptions()
+	opts.Endpoints = []types.Endpoint{{Method: http.MethodGet, URL: srv.URL + "/account"}}
+	var logs []string
+	opts.Log = func(e scanner.LogEntry) { logs = append(logs, e.Message) }
+
+	result, err := New().Run(context.Background(), types.Target{URL: srv.URL}, opts)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"Form without CSRF protection"}, titles(result.Findings))
+	assert.Equal(t, srv.URL+"/account", result.Findings[0].Metadata["page"])
+	assert.Contains(t, logs, "found 2 forms", "the search form on both pages is tested once")
+}
+
+func TestScanner_TargetUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	_, err := New().Run(context.Background(), types.Target{URL: srv.URL}, scanner.DefaultOptions())
+	assert.Error(t, err)
+}
+
+func TestScanner_SkipsNonHTMLPages(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"html": "<form method=\"post\"><input name=\"x\"></form>"}`)
+	}))
+	defer srv.Close()
+
+	result, err := New().Run(context.Background(), types.Target{URL: srv.URL}, scanner.DefaultOptions())
+	require.NoError(t, err)
+	assert.Empty(t, result.Findings)
+}
+
+func TestVerify_CSRFFixed(t *testing.T) {
+	page := staticPage(`<form action="/transfer" method="post"><input name="to"></form>`)
+	srv := formServer(t, page, nil)
+	target := types.Target{URL: srv.URL}
+
+	result, err := New().Run(context.Background(), target, scanner.DefaultOptions())
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 1)
+	f := result.Findings[0]
+
+	reproduces, err := New().Verify(context.Background(), target, f, scanner.DefaultOptions())
+	require.NoError(t, err)
+	assert.True(t, reproduces)
+
+	page.Store(strings.Replace(page.Load().(string), "<input", `<input type="hidden" name="_csrf" value="f00"><input`, 1))
+	reproduces, err = New().Verify(context.Background(), target, f, scanner.DefaultOptions())
+	require.NoError(t, err)
+	assert.False(t, reproduces)
+
+	page.Store(`<p>Transfers are closed.</p>`)
+	reproduces, err = New().Verify(context.Background(), target, f, scanner.DefaultOptions())
+	require.NoError(t, err)
+	assert.False(t, reproduces, "a form that is gone no longer has the issue")
+}
+
+func TestVerify_AutocompleteFixed(t *testing.T) {
+	page := staticPage(`<form action="/login" method="post"><input type="hidden" name="_csrf" value="f00"><input type="password" name="pass"></form>`)
+	srv := formServer(t, page, nil)
+	target := types.Target{URL: srv.URL}
+
+	result, err := New().Run(context.Background(), target, scanner.DefaultOptions())
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 1)
+	f := result.Findings[0]
+
+	reproduces, err := New().Verify(context.Background(), target, f, scanner.DefaultOptions())
+	require.NoError(t, err)
+	assert.True(t, reproduces)
+
+	page.Store(strings.Replace(page.Load().(string), `<input type="password"`, `<input type="password" autocomplete="off"`, 1))
+	reproduces, err = New().Verify(context.Background(), target, f, scanner.DefaultOptions())
+	require.NoError(t, err)
+	assert.False(t, reproduces)
+
+	page.Store(`<p>Logins are closed.</p>`)
+	reproduces, err = New().Verify(context.Background(), target, f, scanner.DefaultOptions())
+	require.NoError(t, err)
+	assert.False(t, reproduces, "a form that is gone no longer has the issue")
+}
+
+func TestVerify_UnknownCheck(t *testing.T) {
+	_, err := New().Verify(context.Background(), types.Target{}, types.Finding{Metadata: map[string]string{"check": "nope"}}, scanner.DefaultOptions())
+	assert.Error(t, err)
+}
@@ -0,0 +1,123 @@
+package scanner
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Gate pauses a scan. The Runner waits on it before each scanner, and
+// scanners that work through many units (ports, paths, requests) wait on it
+// before each one, so pausing stops new load on the target within a unit.
+// Requests already in flight complete. A nil Gate is never paused.
+type Gate struct {
+	mu       sync.Mutex
+	paused   bool
+	resumed  chan struct{} // closed on Resume
+	pausedAt time.Time
+	idle     time.Duration // total time spent paused before pausedAt
+}
+
+// NewGate returns an open gate.
+func NewGate() *Gate {
+	return &Gate{}
+}
+
+// Pause closes the gate. It reports false if it was already paused.
+func (g *Gate) Pause() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.paused {
+		return false
+	}
+	g.paused = true
+	g.resumed = make(chan struct{})
+	g.pausedAt = time.Now()
+	return true
+}
+
+// Resume opens the gate, releasing everything waiting on it. It reports
+// false if it was not paused.
+func (g *Gate) Resume() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.paused {
+		return false
+	}
+	g.paused = false
+	g.idle += time.Since(g.pausedAt)
+	close(g.resumed)
+	return true
+}
+
+// Paused reports whether the gate is closed.
+func (g *Gate) Paused() bool {
+	if g == nil {
+		return false
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.paused
+}
+
+// Wait blocks while the gate is paused. It returns ctx's error if ctx is
+// done before or while waiting.
+func (g *Gate) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if g == nil {
+		return nil
+	}
+	g.mu.Lock()
+	if !g.paused {
+		g.mu.Unlock()
+		return nil
+	}
+	resumed := g.resumed
+	g.mu.Unlock()
+
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pausedFor returns the total time the gate has been paused.
+func (g *Gate) pausedFor() time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.paused {
+		return g.idle + time.Since(g.pausedAt)
+	}
+	return g.idle
+}
+
+// WithTimeout is like context.WithTimeout, except that time spent paused
+// does not count towards d, so pausing a scan does not make it time out.
+func (g *Gate) WithTimeout(parent context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if g == nil {
+		return context.WithTimeout(parent, d)
+	}
+	ctx, cancel := context.WithCancel(parent)
+	start := time.Now()
+	go func() {
+		for {
+			remaining := d - (time.Since(start) - g.pausedFor())
+			if remaining <= 0 {
+				cancel()
+				return
+			}
+			timer := time.NewTimer(remaining)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+		}
+	}()
+	return ctx, cancel
+}
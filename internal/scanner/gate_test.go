@@ -0,0 +1,93 @@
+package scanner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGate_PauseBlocksUntilResume(t *testing.T) {
+	g := NewGate()
+	require.NoError(t, g.Wait(context.Background()))
+
+	assert.True(t, g.Pause())
+	assert.False(t, g.Pause(), "already paused")
+	assert.True(t, g.Paused())
+
+	released := make(chan error, 1)
+	go func() { released <- g.Wait(context.Background()) }()
+
+	select {
+	case <-released:
+		t.Fatal("Wait returned while paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	assert.True(t, g.Resume())
+	assert.False(t, g.Resume(), "not paused")
+	select {
+	case err := <-released:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not return after Resume")
+	}
+}
+
+func TestGate_WaitHonoursContext(t *testing.T) {
+	g := NewGate()
+	g.Pause()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, g.Wait(ctx), context.DeadlineExceeded)
+
+	var nilGate *Gate
+	assert.NoError(t, nilGate.Wait(context.Background()))
+	assert.False(t, nilGate.Paused())
+}
+
+func TestGate_WithTimeoutExcludesPausedTime(t *testing.T) {
+	g := NewGate()
+	ctx, cancel := g.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	g.Pause()
+	time.Sleep(150 * time.Millisecond)
+	assert.NoError(t, ctx.Err(), "time spent paused must not count")
+	g.Resume()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context did not expire after running for the timeout")
+	}
+}
+
+func TestRunner_WaitsOnGate(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&mockScanner{name: "test"})
+	runner := NewRunner(reg)
+
+	opts := DefaultOptions()
+	opts.Gate = NewGate()
+	opts.Gate.Pause()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := runner.RunOne(context.Background(), "test", types.Target{Host: "localhost"}, opts)
+		assert.NoError(t, err)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("scanner ran while paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+	opts.Gate.Resume()
+	<-done
+}
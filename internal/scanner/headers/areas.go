@@ -0,0 +1,354 @@
+package headers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// area is a part of an application served from a host of its own: its
+// public site, the application users sign in to, its API, or the portal
+// they sign in through.
+type area string
+
+const (
+	areaSite area = "site"
+	areaApp  area = "app"
+	areaAPI  area = "api"
+	areaSSO  area = "sso"
+)
+
+// areaOrder is the order areas are reported in.
+var areaOrder = []area{areaSite, areaApp, areaAPI, areaSSO}
+
+// ssoLabel, apiLabel, and appLabel match the first label of hosts that
+// usually serve a sign-in portal, an API, or an application.
+var (
+	ssoLabel = regexp.MustCompile(`^(sso|login|logon|signin|auth|id|idp|identity|accounts?|adfs)(-|\d|$)`)
+	apiLabel = regexp.MustCompile(`^(api|apis|gateway|gw|graphql|rest)(-|\d|$)`)
+	appLabel = regexp.MustCompile(`^(app|apps|portal|dashboard|console|my|admin|manage|secure|client|customers?)(-|\d|$)`)
+)
+
+// identityProvider matches the hosts of hosted identity providers.
+var identityProvider = regexp.MustCompile(`(?i)(^|\.)(login\.microsoftonline\.com|login\.windows\.net|accounts\.google\.com|okta\.com|oktapreview\.com|auth0\.com|onelogin\.com|b2clogin\.com|amazoncognito\.com|pingidentity\.com|duosecurity\.com)$`)
+
+// signInPath matches the paths sign-in portals serve their login at.
+var signInPath = regexp.MustCompile(`(?i)/(saml2?|sso|adfs/ls|cas/login|oauth2?(/v[\d.]+)?/authorize|protocol/openid-connect/auth|login|signin|sign-in)(/|$)`)
+
+// ssoMarker matches markup only sign-in portals serve: SAML messages, and
+// OpenID Connect and ADFS endpoints in forms and scripts.
+var ssoMarker = regexp.MustCompile(`SAMLRequest|SAMLResponse|/protocol/openid-connect/|/adfs/ls/`)
+
+// passwordField matches a password input.
+var passwordField = regexp.MustCompile(`(?i)<input[^>]+type=["']?password`)
+
+// profile adapts the header rules to an area.
+type profile struct {
+	// skip names the header rules that do not apply.
+	skip map[string]bool
+	// raise maps the titles of findings that weigh more to their severity.
+	raise map[string]types.Severity
+}
+
+// profiles are the header profiles of the areas. API responses are not
+// rendered as pages, so the headers restricting what pages may do do not
+// apply to them. Applications and sign-in portals are where framing lets
+// clickjacking trick a signed-in user into acting, or into typing their
+// password into an attacker's page.
+var profiles = map[area]profile{
+	areaAPI: {skip: map[string]bool{
+		"Content-Security-Policy": true,
+		"X-Frame-Options":         true,
+		"X-XSS-Protection":        true,
+		"Referrer-Policy":         true,
+		"Permissions-Policy":      true,
+	}},
+	areaApp: {raise: map[string]types.Severity{"Missing X-Frame-Options header": types.SeverityMedium}},
+	areaSSO: {raise: map[string]types.Severity{"Missing X-Frame-Options header": types.SeverityMedium}},
+}
+
+// areaPage is a host's root page, fetched following redirects on that
+// host only.
+type areaPage struct {
+	url    *url.URL
+	status int
+	header http.Header
+	body   string
+}
+
+// areaHost is a host checked as an application area.
+type areaHost struct {
+	name string
+	area area
+	// sso is the host of the identity provider the host sends users to
+	// sign in at, if any.
+	sso  string
+	page *areaPage
+}
+
+// checkAreas sorts the hosts under the target's domain into application
+// areas: its public site, applications, APIs, and sign-in portals, and
+// checks each host's headers and cookies against its area's profile. Each
+// problem is reported once per area, for the first host it was seen on,
+// with the area and host in its metadata. One INFO finding per area lists
+// its hosts. Hosts opts.Authorize refuses are skipped.
+func checkAreas(ctx context.Context, targetURL string, opts scanner.Options) ([]types.Finding, map[string]string) {
+	root, err := url.Parse(targetURL)
+	if err != nil || root.Host == "" {
+		return nil, nil
+	}
+	client := &http.Client{
+		Timeout:   opts.Timeout,
+		Transport: opts.HTTPTransport(),
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if req.URL.Host != via[0].URL.Host || len(via) >= 10 {
+				return http.ErrUseLastResponse
+			}
+			return nil
+		},
+	}
+
+	names := append([]string{strings.ToLower(root.Hostname())}, scanner.Limit(authorizedHosts(areaHostNames(ctx, root, opts), opts), opts.Budget("headers.areas"))...)
+	var hosts []areaHost
+	for i, name := range names {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := opts.Gate.Wait(ctx); err != nil {
+			break
+		}
+		u := &url.URL{Scheme: root.Scheme, Host: name, Path: "/"}
+		if port := root.Port(); port != "" {
+			u.Host = net.JoinHostPort(name, port)
+		}
+		page, err := fetchAreaPage(ctx, client, u)
+		opts.ReportProgress("headers", i+1, len(names))
+		if err != nil {
+			opts.Logf("headers", scanner.LogInfo, "skipping area host %s: %v", name, err)
+			continue
+		}
+		a, sso := classify(name, page)
+		hosts = append(hosts, areaHost{name: name, area: a, sso: sso, page: page})
+	}
+
+	var findings []types.Finding
+	reported := map[area]map[string]bool{}
+	for _, h := range hosts {
+		if reported[h.area] == nil {
+			reported[h.area] = map[string]bool{}
+		}
+		// The target's own findings are made by the scan of its pages.
+		if h.name == names[0] {
+			continue
+		}
+		for _, f := range checkHost(h) {
+			if reported[h.area][f.Title] {
+				continue
+			}
+			reported[h.area][f.Title] = true
+			findings = append(findings, f)
+		}
+	}
+
+	byArea := map[area][]areaHost{}
+	for _, h := range hosts {
+		byArea[h.area] = append(byArea[h.area], h)
+	}
+	var summary []string
+	for _, a := range areaOrder {
+		if len(byArea[a]) == 0 {
+			continue
+		}
+		f := areaFinding(a, byArea[a])
+		findings = append(findings, f)
+		summary = append(summary, string(a)+": "+f.Metadata["hosts"])
+	}
+	if len(summary) == 0 {
+		return findings, nil
+	}
+	return findings, map[string]string{"areas": strings.Join(summary, "; ")}
+}
+
+// areaHostNames returns the other hosts under the target's domain: those
+// the passive sources know of, and those of the area_hosts argument. The
+// domain is the target's host without a leading www.
+func areaHostNames(ctx context.Context, root *url.URL, opts scanner.Options) []string {
+	host := strings.ToLower(root.Hostname())
+	domain := strings.TrimPrefix(host, "www.")
+	seen := map[string]bool{host: true}
+	var names []string
+	add := func(name string) {
+		name = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+		if name == "" || strings.Contains(name, "*") || seen[name] {
+			return
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+
+	for _, name := range strings.Split(opts.StringArg("area_hosts"), ",") {
+		add(name)
+	}
+	if len(opts.Sources) > 0 && net.ParseIP(domain) == nil {
+		data, errs := opts.PassiveLookup(ctx, domain)
+		for _, err := range errs {
+			opts.Logf("headers", scanner.LogWarn, "passive source failed: %v", err)
+		}
+		for _, h := range data.Hosts {
+			if h.Name == domain || strings.HasSuffix(h.Name, "."+domain) {
+				add(h.Name)
+			}
+		}
+	}
+	return names
+}
+
+// authorizedHosts returns the names opts.Authorize allows to be scanned,
+// logging those it refuses.
+func authorizedHosts(names []string, opts scanner.Options) []string {
+	if opts.Authorize == nil {
+		return names
+	}
+	var allowed []string
+	for _, name := range names {
+		if err := opts.Authorize(types.Target{Host: name}); err != nil {
+			opts.Logf("headers", scanner.LogInfo, "skipping area host %s: %v", name, err)
+			continue
+		}
+		allowed = append(allowed, name)
+	}
+	return allowed
+}
+
+// fetchAreaPage fetches u with client.
+func fetchAreaPage(ctx context.Context, client *http.Client, u *url.URL) (*areaPage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP GET %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 256<<10))
+	return &areaPage{url: resp.Request.URL, status: resp.StatusCode, header: resp.Header, body: string(body)}, nil
+}
+
+// classify returns the area of host from its name and root page, and the
+// identity provider it sends users to sign in at, if any. Sign-in portals
+// are told by their name or their SAML and OpenID Connect markup; a host
+// that redirects to one, or to a hosted identity provider, is an
+// application behind single sign-on.
+func classify(host string, page *areaPage) (area, string) {
+	label, _, _ := strings.Cut(host, ".")
+	if ssoLabel.MatchString(label) || ssoMarker.MatchString(page.body) {
+		return areaSSO, ""
+	}
+	if idp := signInRedirect(page); idp != "" {
+		return areaApp, idp
+	}
+	mediaType, _, _ := mime.ParseMediaType(page.header.Get("Content-Type"))
+	if apiLabel.MatchString(label) || mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+		return areaAPI, ""
+	}
+	if appLabel.MatchString(label) || passwordField.MatchString(page.body) {
+		return areaApp, ""
+	}
+	return areaSite, ""
+}
+
+// signInRedirect returns the host page redirects to if it is on another
+// host and is a hosted identity provider or a sign-in path.
+func signInRedirect(page *areaPage) string {
+	if page.status < 300 || page.status >= 400 {
+		return ""
+	}
+	loc, err := page.url.Parse(page.header.Get("Location"))
+	if err != nil || loc.Host == "" || strings.EqualFold(loc.Host, page.url.Host) {
+		return ""
+	}
+	if identityProvider.MatchString(loc.Hostname()) || signInPath.MatchString(loc.Path) {
+		return strings.ToLower(loc.Hostname())
+	}
+	return ""
+}
+
+// checkHost runs the header and cookie rules on h's root page, with the
+// profile of its area.
+func checkHost(h areaHost) []types.Finding {
+	p := profiles[h.area]
+	isHTTPS := h.page.url.Scheme == "https"
+	var findings []*types.Finding
+	for _, rule := range Rules() {
+		if !p.skip[rule.Name] {
+			findings = append(findings, rule.Check(h.page.header, isHTTPS))
+		}
+	}
+	for _, rule := range PageRules() {
+		findings = append(findings, rule.Check(h.page.header, pagePath(h.page.url.String())))
+	}
+	for _, rule := range CookieRules() {
+		findings = append(findings, rule.Check(cookiesOf(h.page.header), isHTTPS))
+	}
+
+	var out []types.Finding
+	for _, f := range findings {
+		if f == nil {
+			continue
+		}
+		if severity, ok := p.raise[f.Title]; ok {
+			f.Severity = severity
+		}
+		if f.Evidence == "" {
+			f.Evidence = "Seen on " + h.page.url.String()
+		}
+		f.Metadata = map[string]string{"url": h.page.url.String(), "host": h.name, "area": string(h.area)}
+		if h.sso != "" {
+			f.Metadata["sso"] = h.sso
+		}
+		out = append(out, *f)
+	}
+	return out
+}
+
+// areaFinding lists the hosts of an area.
+func areaFinding(a area, hosts []areaHost) types.Finding {
+	var names, idps []string
+	for _, h := range hosts {
+		names = append(names, h.name)
+		if h.sso != "" && !slices.Contains(idps, h.sso) {
+			idps = append(idps, h.sso)
+		}
+	}
+	sort.Strings(idps)
+	descriptions := map[area]string{
+		areaSite: "These hosts serve the public site, such as marketing pages.",
+		areaApp:  "These hosts serve an application users sign in to.",
+		areaAPI:  "These hosts serve an API. Headers that only apply to pages rendered in a browser are not expected of them.",
+		areaSSO:  "These hosts serve a sign-in portal, through SAML, OpenID Connect, or a login form of their own.",
+	}
+	f := types.Finding{
+		Title:       fmt.Sprintf("Application area: %s", a),
+		Description: descriptions[a],
+		Severity:    types.SeverityInfo,
+		Evidence:    strings.Join(names, ", "),
+		Metadata:    map[string]string{"area": string(a), "hosts": strings.Join(names, ",")},
+	}
+	if len(idps) > 0 {
+		f.Description += fmt.Sprintf(" Some send users to sign in at %s (single sign-on).", strings.Join(idps, ", "))
+		f.Metadata["sso"] = strings.Join(idps, ",")
+	}
+	return f
+}
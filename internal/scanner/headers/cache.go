@@ -0,0 +1,117 @@
+package headers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// maxHTMLCacheAge is the longest an HTML document may be cached before it
+// is reported: pages change, and a cached one keeps serving old markup,
+// scripts, and data after a fix.
+const maxHTMLCacheAge = 24 * time.Hour
+
+// sensitivePath matches the paths of pages likely to carry credentials or
+// personal data: sign-in, account, and API endpoints.
+var sensitivePath = regexp.MustCompile(`(?i)(?:^|/)(?:login|logon|signin|sign-in|logout|auth|oauth2?|sso|session|token|password|account|accounts|profile|settings|me|user|users|billing|checkout|api|graphql|admin)(?:/|\.|$)`)
+
+// PageRule is a header check that depends on the page's path as well as
+// its response headers.
+type PageRule struct {
+	Name  string
+	Check func(h http.Header, path string) *types.Finding
+}
+
+// PageRules returns the header rules whose applicability depends on the
+// page: caching of sensitive responses and of HTML documents.
+func PageRules() []PageRule {
+	return []PageRule{
+		{
+			Name: "Cache-Control (sensitive pages)",
+			Check: func(h http.Header, path string) *types.Finding {
+				if !sensitivePath.MatchString(path) {
+					return nil
+				}
+				if _, noStore := cacheDirectives(h)["no-store"]; noStore {
+					return nil
+				}
+				value := h.Get("Cache-Control")
+				if value == "" {
+					value = "not set"
+				}
+				return &types.Finding{
+					Title:       "Sensitive response cacheable",
+					Description: fmt.Sprintf("%s looks like an authentication, account, or API endpoint, but its response does not forbid caching. Browsers and shared proxies may store it, exposing session data or personal information to later users of the same machine or cache.", path),
+					Severity:    types.SeverityLow,
+					Evidence:    "Cache-Control: " + value,
+					Remediation: "Add the header: Cache-Control: no-store",
+				}
+			},
+		},
+		{
+			Name: "Cache-Control (HTML documents)",
+			Check: func(h http.Header, path string) *types.Finding {
+				if !strings.HasPrefix(h.Get("Content-Type"), "text/html") {
+					return nil
+				}
+				directives := cacheDirectives(h)
+				if _, noStore := directives["no-store"]; noStore {
+					return nil
+				}
+				age := cacheAge(directives)
+				if age <= maxHTMLCacheAge {
+					return nil
+				}
+				return &types.Finding{
+					Title:       "HTML document cached for too long",
+					Description: fmt.Sprintf("The HTML document at %s may be cached for %s. Caches keep serving it, with its old scripts and content, after the page changes or a vulnerability in it is fixed.", path, age),
+					Severity:    types.SeverityLow,
+					Evidence:    "Cache-Control: " + h.Get("Cache-Control"),
+					Remediation: "Cache HTML documents briefly or revalidate them (Cache-Control: no-cache), and give long lifetimes to versioned static assets instead",
+				}
+			},
+		},
+	}
+}
+
+// cacheDirectives returns the Cache-Control directives of h, names
+// lowercased, mapped to their values.
+func cacheDirectives(h http.Header) map[string]string {
+	directives := map[string]string{}
+	for _, value := range h.Values("Cache-Control") {
+		for _, d := range strings.Split(value, ",") {
+			name, arg, _ := strings.Cut(strings.TrimSpace(d), "=")
+			if name != "" {
+				directives[strings.ToLower(name)] = strings.Trim(arg, `"`)
+			}
+		}
+	}
+	return directives
+}
+
+// cacheAge returns the longest any cache may keep a response with the given
+// Cache-Control directives, by max-age or s-maxage.
+func cacheAge(directives map[string]string) time.Duration {
+	var age time.Duration
+	for _, name := range []string{"max-age", "s-maxage"} {
+		if seconds, err := strconv.Atoi(directives[name]); err == nil && time.Duration(seconds)*time.Second > age {
+			age = time.Duration(seconds) * time.Second
+		}
+	}
+	return age
+}
+
+// pagePath returns the path of rawURL, "/" when it has none.
+func pagePath(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Path == "" {
+		return "/"
+	}
+	return u.Path
+}
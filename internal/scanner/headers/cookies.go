@@ -0,0 +1,90 @@
+package headers
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// sessionCookieName matches the names of cookies likely to carry a session.
+var sessionCookieName = regexp.MustCompile(`(?i)sess|sid|auth|token|jwt|login|remember|user`)
+
+// CookieRule is a check of the cookies a response sets.
+type CookieRule struct {
+	Name  string
+	Check func(cookies []*http.Cookie, isHTTPS bool) *types.Finding
+}
+
+// CookieRules returns the rules for the attributes of cookies: Secure on
+// every cookie set over HTTPS, and HttpOnly and SameSite on those that look
+// like they carry a session.
+func CookieRules() []CookieRule {
+	return []CookieRule{
+		{
+			Name: "Secure",
+			Check: func(cookies []*http.Cookie, isHTTPS bool) *types.Finding {
+				if !isHTTPS {
+					return nil
+				}
+				names := cookieNames(cookies, func(c *http.Cookie) bool { return !c.Secure })
+				if len(names) == 0 {
+					return nil
+				}
+				return &types.Finding{
+					Title:       "Cookie without Secure flag",
+					Description: "Cookies set over HTTPS lack the Secure flag, so browsers also send them over plain HTTP, where anyone on the network can read them.",
+					Severity:    types.SeverityMedium,
+					Evidence:    "Set-Cookie without Secure: " + strings.Join(names, ", "),
+					Remediation: "Add the Secure attribute to every cookie set over HTTPS.",
+				}
+			},
+		},
+		{
+			Name: "HttpOnly",
+			Check: func(cookies []*http.Cookie, _ bool) *types.Finding {
+				names := cookieNames(cookies, func(c *http.Cookie) bool { return sessionCookieName.MatchString(c.Name) && !c.HttpOnly })
+				if len(names) == 0 {
+					return nil
+				}
+				return &types.Finding{
+					Title:       "Session cookie without HttpOnly flag",
+					Description: "Cookies that look like they carry a session lack the HttpOnly flag, so scripts on the page can read them, and an XSS flaw hands the session to an attacker.",
+					Severity:    types.SeverityMedium,
+					Evidence:    "Set-Cookie without HttpOnly: " + strings.Join(names, ", "),
+					Remediation: "Add the HttpOnly attribute to session cookies.",
+				}
+			},
+		},
+		{
+			Name: "SameSite",
+			Check: func(cookies []*http.Cookie, _ bool) *types.Finding {
+				names := cookieNames(cookies, func(c *http.Cookie) bool {
+					return sessionCookieName.MatchString(c.Name) && (c.SameSite == 0 || c.SameSite == http.SameSiteDefaultMode)
+				})
+				if len(names) == 0 {
+					return nil
+				}
+				return &types.Finding{
+					Title:       "Session cookie without SameSite attribute",
+					Description: "Cookies that look like they carry a session have no SameSite attribute. Browsers that do not default to Lax send them on requests from other sites, which leaves the session open to cross-site request forgery.",
+					Severity:    types.SeverityLow,
+					Evidence:    "Set-Cookie without SameSite: " + strings.Join(names, ", "),
+					Remediation: "Add SameSite=Lax, or Strict, to session cookies.",
+				}
+			},
+		},
+	}
+}
+
+// cookieNames returns the names of the cookies that match.
+func cookieNames(cookies []*http.Cookie, match func(*http.Cookie) bool) []string {
+	var names []string
+	for _, c := range cookies {
+		if match(c) {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
@@ -39,8 +39,92 @@ func (s *Scanner) Run(ctx context.Context, target types.Target, opts scanner.Opt
 		timeout = 5 * time.Second
 	}
 
-	client := &http.Client{Timeout: timeout}
+	client := &http.Client{Timeout: timeout, Transport: opts.HTTPTransport()}
 
+	header, err := fetchHeader(ctx, client, url)
+	if err != nil {
+		return nil, err
+	}
+
+	isHTTPS := strings.HasPrefix(url, "https://")
+
+	reported := map[string]bool{}
+	for _, rule := range Rules() {
+		if finding := rule.Check(header, isHTTPS); finding != nil {
+			reported[finding.Title] = true
+			result.Findings = append(result.Findings, *finding)
+		}
+	}
+	for _, rule := range PageRules() {
+		if finding := rule.Check(header, pagePath(url)); finding != nil {
+			reported[finding.Title] = true
+			finding.Metadata = map[string]string{"url": url}
+			result.Findings = append(result.Findings, *finding)
+		}
+	}
+	for _, rule := range CookieRules() {
+		if finding := rule.Check(cookiesOf(header), isHTTPS); finding != nil {
+			reported[finding.Title] = true
+			finding.Metadata = map[string]string{"url": url}
+			result.Findings = append(result.Findings, *finding)
+		}
+	}
+
+	// The pages of imported and crawled requests are checked too, since a
+	// header set on the home page is often missing elsewhere. Each problem
+	// is reported once, for the first page it was seen on.
+	for _, page := range pages(opts.EndpointsOn(url), url) {
+		if ctx.Err() != nil {
+			break
+		}
+		header, err := fetchHeader(ctx, client, page)
+		if err != nil {
+			continue
+		}
+		for _, rule := range Rules() {
+			finding := rule.Check(header, strings.HasPrefix(page, "https://"))
+			if finding == nil || reported[finding.Title] {
+				continue
+			}
+			reported[finding.Title] = true
+			finding.Evidence = "Seen on " + page
+			finding.Metadata = map[string]string{"url": page}
+			result.Findings = append(result.Findings, *finding)
+		}
+		for _, rule := range PageRules() {
+			finding := rule.Check(header, pagePath(page))
+			if finding == nil || reported[finding.Title] {
+				continue
+			}
+			reported[finding.Title] = true
+			finding.Metadata = map[string]string{"url": page}
+			result.Findings = append(result.Findings, *finding)
+		}
+		for _, rule := range CookieRules() {
+			finding := rule.Check(cookiesOf(header), strings.HasPrefix(page, "https://"))
+			if finding == nil || reported[finding.Title] {
+				continue
+			}
+			reported[finding.Title] = true
+			finding.Metadata = map[string]string{"url": page}
+			result.Findings = append(result.Findings, *finding)
+		}
+	}
+
+	// With areas, the other hosts of the target's domain are checked too,
+	// each against the profile of the part of the application it serves.
+	if opts.BoolArg("areas") {
+		findings, metadata := checkAreas(ctx, url, opts)
+		result.Findings = append(result.Findings, findings...)
+		result.Metadata = metadata
+	}
+
+	result.CompletedAt = time.Now()
+	return result, nil
+}
+
+// fetchHeader requests url and returns the response's header.
+func fetchHeader(ctx context.Context, client *http.Client, url string) (http.Header, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
@@ -50,18 +134,31 @@ func (s *Scanner) Run(ctx context.Context, target types.Target, opts scanner.Opt
 	if err != nil {
 		return nil, fmt.Errorf("HTTP GET %s: %w", url, err)
 	}
-	defer resp.Body.Close()
+	resp.Body.Close()
+	return resp.Header, nil
+}
 
-	isHTTPS := strings.HasPrefix(url, "https://")
+// cookiesOf returns the cookies a response with header sets.
+func cookiesOf(header http.Header) []*http.Cookie {
+	return (&http.Response{Header: header}).Cookies()
+}
 
-	for _, rule := range Rules() {
-		if finding := rule.Check(resp.Header, isHTTPS); finding != nil {
-			result.Findings = append(result.Findings, *finding)
+// pages returns the distinct pages of the GET requests in endpoints, without
+// their query strings, leaving out the target URL itself.
+func pages(endpoints []types.Endpoint, targetURL string) []string {
+	seen := map[string]bool{strings.TrimRight(targetURL, "/"): true}
+	var pages []string
+	for _, ep := range endpoints {
+		if ep.Method != http.MethodGet {
+			continue
+		}
+		page, _, _ := strings.Cut(ep.URL, "?")
+		if key := strings.TrimRight(page, "/"); !seen[key] {
+			seen[key] = true
+			pages = append(pages, page)
 		}
 	}
-
-	result.CompletedAt = time.Now()
-	return result, nil
+	return pages
 }
 
 // resolveURL determines the target URL from the Target struct.
@@ -76,5 +173,5 @@ func resolveURL(target types.Target) string {
 	if target.Host == "" {
 		return ""
 	}
-	return scheme + "://" + target.Host
+	return scheme + "://" + target.URLHost()
 }
@@ -2,8 +2,11 @@ package headers
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/buemura/hunter/internal/scanner"
@@ -212,3 +215,252 @@ func TestScanner_ResolveURL(t *testing.T) {
 		})
 	}
 }
+
+func TestScanner_ChecksEndpointPages(t *testing.T) {
+	// The home page is hardened; the admin pages are not.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			w.Header().Set("Content-Security-Policy", "default-src 'self'")
+			w.Header().Set("X-Content-Type-Options", "nosniff")
+			w.Header().Set("X-Frame-Options", "DENY")
+			w.Header().Set("Referrer-Policy", "no-referrer")
+			w.Header().Set("Permissions-Policy", "camera=()")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	opts := scanner.DefaultOptions()
+	opts.Endpoints = []types.Endpoint{
+		{Method: http.MethodGet, URL: "/admin?tab=users"},
+		{Method: http.MethodGet, URL: "/admin?tab=logs"},
+		{Method: http.MethodPost, URL: "/login"},
+		{Method: http.MethodGet, URL: "/"},
+	}
+	target := types.Target{URL: srv.URL + "/", Host: "127.0.0.1", Scheme: "http"}
+	result, err := New().Run(context.Background(), target, opts)
+	require.NoError(t, err)
+
+	byTitle := map[string]types.Finding{}
+	for _, f := range result.Findings {
+		byTitle[f.Title] = f
+	}
+	require.Contains(t, byTitle, "Missing Content-Security-Policy header")
+	csp := byTitle["Missing Content-Security-Policy header"]
+	assert.Equal(t, srv.URL+"/admin", csp.Metadata["url"])
+	assert.Len(t, result.Findings, len(byTitle), "each problem is reported once")
+}
+
+func TestPageRules_SensitiveResponseCacheable(t *testing.T) {
+	rule := PageRules()[0]
+	assert.Equal(t, "Cache-Control (sensitive pages)", rule.Name)
+
+	for _, path := range []string{"/login", "/account/settings", "/api/v1/orders", "/oauth2/token", "/graphql", "/signin.php"} {
+		finding := rule.Check(http.Header{}, path)
+		if assert.NotNil(t, finding, path) {
+			assert.Equal(t, "Sensitive response cacheable", finding.Title)
+			assert.Equal(t, "Cache-Control: not set", finding.Evidence)
+		}
+	}
+	for _, path := range []string{"/", "/blog/apis-explained", "/about", "/static/app.js"} {
+		assert.Nil(t, rule.Check(http.Header{}, path), path)
+	}
+
+	// no-cache still lets caches store the response; only no-store stops it.
+	assert.NotNil(t, rule.Check(http.Header{"Cache-Control": {"private, no-cache"}}, "/account"))
+	assert.Nil(t, rule.Check(http.Header{"Cache-Control": {"no-cache, No-Store"}}, "/account"))
+}
+
+func TestPageRules_HTMLCachedTooLong(t *testing.T) {
+	rule := PageRules()[1]
+	html := func(cacheControl string) http.Header {
+		return http.Header{"Content-Type": {"text/html; charset=utf-8"}, "Cache-Control": {cacheControl}}
+	}
+
+	finding := rule.Check(html("public, max-age=604800"), "/")
+	require.NotNil(t, finding)
+	assert.Equal(t, "HTML document cached for too long", finding.Title)
+	assert.Contains(t, finding.Description, "168h0m0s")
+
+	assert.NotNil(t, rule.Check(html("max-age=60, s-maxage=31536000"), "/"), "shared caches count too")
+	assert.Nil(t, rule.Check(html("max-age=3600"), "/"))
+	assert.Nil(t, rule.Check(html("no-store, max-age=604800"), "/"))
+	assert.Nil(t, rule.Check(http.Header{"Content-Type": {"text/css"}, "Cache-Control": {"max-age=31536000"}}, "/site.css"), "static assets may be cached")
+}
+
+func TestScanner_CacheRulesOnPages(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Strict-Transport-Security", "max-age=31536000")
+		w.Header().Set("Content-Security-Policy", "default-src 'self'")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("X-Frame-Options", "DENY")
+		w.Header().Set("X-XSS-Protection", "0")
+		w.Header().Set("Referrer-Policy", "strict-origin-when-cross-origin")
+		w.Header().Set("Permissions-Policy", "camera=()")
+		w.Header().Set("Cache-Control", "private, max-age=0")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	opts := scanner.DefaultOptions()
+	opts.Endpoints = []types.Endpoint{
+		{Method: http.MethodGet, URL: srv.URL + "/about"},
+		{Method: http.MethodGet, URL: srv.URL + "/account/profile"},
+		{Method: http.MethodGet, URL: srv.URL + "/api/orders"},
+	}
+	result, err := New().Run(context.Background(), types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}, opts)
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 1, "reported once, for the first sensitive page")
+	assert.Equal(t, "Sensitive response cacheable", result.Findings[0].Title)
+	assert.Equal(t, srv.URL+"/account/profile", result.Findings[0].Metadata["url"])
+	assert.Equal(t, "Cache-Control: private, max-age=0", result.Findings[0].Evidence)
+}
+
+func TestCookieRules(t *testing.T) {
+	cookies := []*http.Cookie{
+		{Name: "sessionid", Secure: true},
+		{Name: "theme"},
+		{Name: "auth_token", HttpOnly: true, SameSite: http.SameSiteLaxMode},
+	}
+	byName := map[string]*types.Finding{}
+	for _, rule := range CookieRules() {
+		byName[rule.Name] = rule.Check(cookies, true)
+	}
+	require.NotNil(t, byName["Secure"])
+	assert.Equal(t, "Set-Cookie without Secure: theme, auth_token", byName["Secure"].Evidence)
+	require.NotNil(t, byName["HttpOnly"])
+	assert.Equal(t, "Set-Cookie without HttpOnly: sessionid", byName["HttpOnly"].Evidence)
+	require.NotNil(t, byName["SameSite"])
+	assert.Equal(t, "Set-Cookie without SameSite: sessionid", byName["SameSite"].Evidence)
+
+	for _, rule := range CookieRules() {
+		assert.Nil(t, rule.Check([]*http.Cookie{{Name: "theme", HttpOnly: true}}, false), rule.Name)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	page := func(rawURL string, status int, header http.Header, body string) *areaPage {
+		u, err := url.Parse(rawURL)
+		require.NoError(t, err)
+		if header == nil {
+			header = http.Header{}
+		}
+		return &areaPage{url: u, status: status, header: header, body: body}
+	}
+	tests := []struct {
+		name string
+		host string
+		page *areaPage
+		area area
+		sso  string
+	}{
+		{"marketing site", "www.example.com", page("https://www.example.com/", 200, nil, "<h1>Welcome</h1>"), areaSite, ""},
+		{"api by name", "api.example.com", page("https://api.example.com/", 404, nil, ""), areaAPI, ""},
+		{"api by content type", "data.example.com", page("https://data.example.com/", 200, http.Header{"Content-Type": {"application/problem+json"}}, "{}"), areaAPI, ""},
+		{"app by name", "app.example.com", page("https://app.example.com/", 200, nil, ""), areaApp, ""},
+		{"app by login form", "crm.example.com", page("https://crm.example.com/", 200, nil, `<input type="password" name="pw">`), areaApp, ""},
+		{"app behind hosted SSO", "hr.example.com", page("https://hr.example.com/", 302, http.Header{"Location": {"https://login.microsoftonline.com/tenant/saml2"}}, ""), areaApp, "login.microsoftonline.com"},
+		{"app behind own SSO", "wiki.example.com", page("https://wiki.example.com/", 302, http.Header{"Location": {"https://sso.example.com/realms/corp/protocol/openid-connect/auth?client_id=wiki"}}, ""), areaApp, "sso.example.com"},
+		{"redirect to another site", "blog.example.com", page("https://blog.example.com/", 301, http.Header{"Location": {"https://www.example.com/blog"}}, ""), areaSite, ""},
+		{"sso by name", "login.example.com", page("https://login.example.com/", 200, nil, ""), areaSSO, ""},
+		{"sso by markup", "gate.example.com", page("https://gate.example.com/", 200, nil, `<form action="/adfs/ls/"><input name="SAMLRequest">`), areaSSO, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, sso := classify(tt.host, tt.page)
+			assert.Equal(t, tt.area, a)
+			assert.Equal(t, tt.sso, sso)
+		})
+	}
+}
+
+// stubSource answers every lookup with hosts.
+type stubSource struct{ hosts []string }
+
+func (s stubSource) Name() string { return "stub" }
+func (s stubSource) Lookup(context.Context, string) (*scanner.PassiveData, error) {
+	data := &scanner.PassiveData{}
+	for _, h := range s.hosts {
+		data.Hosts = append(data.Hosts, scanner.PassiveHost{Name: h})
+	}
+	return data, nil
+}
+
+func TestScanner_Areas(t *testing.T) {
+	// One server plays every host of example.test.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, _ := net.SplitHostPort(r.Host)
+		switch host {
+		case "app.example.test":
+			w.Header().Set("Location", "https://example.okta.com/app/sso/saml")
+			w.WriteHeader(http.StatusFound)
+		case "api.example.test":
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Add("Set-Cookie", "session=abc; Path=/")
+			fmt.Fprint(w, `{"status":"ok"}`)
+		case "portal.example.test":
+			fmt.Fprint(w, `<form method="post"><input type="password" name="pw"></form>`)
+		default:
+			fmt.Fprint(w, "<h1>Welcome</h1>")
+		}
+	}))
+	defer srv.Close()
+	_, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+
+	opts := scanner.DefaultOptions()
+	opts.Transport = &http.Transport{DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, srv.Listener.Addr().String())
+	}}
+	opts.Sources = []scanner.PassiveSource{stubSource{hosts: []string{"example.test", "app.example.test", "api.example.test", "*.example.test", "other.test"}}}
+	opts.ExtraArgs = map[string]interface{}{"areas": true, "area_hosts": "portal.example.test"}
+
+	target := types.Target{URL: "http://www.example.test:" + port + "/", Host: "www.example.test", Scheme: "http"}
+	result, err := New().Run(context.Background(), target, opts)
+	require.NoError(t, err)
+
+	byArea := map[string]map[string]types.Finding{}
+	for _, f := range result.Findings {
+		if a := f.Metadata["area"]; a != "" {
+			if byArea[a] == nil {
+				byArea[a] = map[string]types.Finding{}
+			}
+			byArea[a][f.Title] = f
+		}
+	}
+	assert.Equal(t, "site: www.example.test,example.test; app: portal.example.test,app.example.test; api: api.example.test", result.Metadata["areas"])
+
+	app := byArea["app"]
+	require.Contains(t, app, "Application area: app")
+	assert.Equal(t, "example.okta.com", app["Application area: app"].Metadata["sso"])
+	require.Contains(t, app, "Missing X-Frame-Options header")
+	assert.Equal(t, types.SeverityMedium, app["Missing X-Frame-Options header"].Severity, "framing matters more on applications")
+	assert.Equal(t, "portal.example.test", app["Missing X-Frame-Options header"].Metadata["host"])
+
+	api := byArea["api"]
+	assert.NotContains(t, api, "Missing Content-Security-Policy header", "API responses are not rendered")
+	assert.NotContains(t, api, "Missing X-Frame-Options header")
+	assert.Contains(t, api, "Missing X-Content-Type-Options header")
+	assert.Contains(t, api, "Session cookie without HttpOnly flag")
+
+	// The target's own host is only checked by the scan of its pages.
+	require.Contains(t, byArea["site"], "Missing Content-Security-Policy header")
+	assert.Equal(t, "example.test", byArea["site"]["Missing Content-Security-Policy header"].Metadata["host"])
+}
+
+func TestAuthorizedHosts(t *testing.T) {
+	var logged []string
+	opts := scanner.DefaultOptions()
+	opts.Log = func(e scanner.LogEntry) { logged = append(logged, e.Message) }
+	names := []string{"app.example.test", "api.example.test"}
+	assert.Equal(t, names, authorizedHosts(names, opts), "every host is allowed without a check")
+
+	opts.Authorize = func(target types.Target) error {
+		if target.Host == "api.example.test" {
+			return fmt.Errorf("target %s is not in authorized_targets", target.Host)
+		}
+		return nil
+	}
+	assert.Equal(t, []string{"app.example.test"}, authorizedHosts(names, opts))
+	require.Len(t, logged, 1)
+	assert.Contains(t, logged[0], "skipping area host api.example.test")
+}
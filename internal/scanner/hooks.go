@@ -0,0 +1,124 @@
+package scanner
+
+import "github.com/buemura/hunter/pkg/types"
+
+// Hooks are callbacks a Runner invokes as scanners run, so integrations such
+// as live output, notifications, metrics, or deduplication can follow a scan
+// without each re-implementing result handling. Any field may be nil. Hooks
+// may be called concurrently when scanners run in parallel.
+type Hooks struct {
+	// OnScannerStart is called just before a scanner runs.
+	OnScannerStart func(scanner string)
+
+	// OnProgress receives the scanner's incremental progress, like
+	// Options.Progress.
+	OnProgress ProgressFunc
+
+	// OnLog receives the scanner's log entries, like Options.Log.
+	OnLog LogFunc
+
+	// OnFound receives findings as scanners that stream them make them, like
+	// Options.Found. They are reported again with OnFinding.
+	OnFound FindingFunc
+
+	// OnFinding is called for each finding of a finished scanner, after
+	// severity overrides, before OnScannerComplete.
+	OnFinding func(scanner string, finding types.Finding)
+
+	// OnScannerComplete is called with each scanner's result. A scanner that
+	// failed, could not be found, or was disabled is reported with only
+	// ScannerName, Target, and Error set.
+	OnScannerComplete func(result types.ScanResult)
+}
+
+// Use registers hooks called for every scan the runner runs. It must not be
+// called while a scan is running; use With for hooks that belong to one scan.
+func (r *Runner) Use(h Hooks) {
+	r.hooks = append(r.hooks, h)
+}
+
+// With returns a runner sharing r's registry and hooks that also calls h,
+// e.g. to track the progress of a single web job.
+func (r *Runner) With(h Hooks) *Runner {
+	hooks := make([]Hooks, 0, len(r.hooks)+1)
+	hooks = append(hooks, r.hooks...)
+	return &Runner{registry: r.registry, hooks: append(hooks, h)}
+}
+
+func (r *Runner) scannerStarted(name string) {
+	for _, h := range r.hooks {
+		if h.OnScannerStart != nil {
+			h.OnScannerStart(name)
+		}
+	}
+}
+
+// withHooks returns opts with Progress, Log, and Found also feeding every
+// OnProgress, OnLog, and OnFound hook.
+func (r *Runner) withHooks(opts Options) Options {
+	var progress []ProgressFunc
+	var logs []LogFunc
+	var found []FindingFunc
+	if opts.Progress != nil {
+		progress = append(progress, opts.Progress)
+	}
+	if opts.Log != nil {
+		logs = append(logs, opts.Log)
+	}
+	if opts.Found != nil {
+		found = append(found, opts.Found)
+	}
+	for _, h := range r.hooks {
+		if h.OnProgress != nil {
+			progress = append(progress, h.OnProgress)
+		}
+		if h.OnLog != nil {
+			logs = append(logs, h.OnLog)
+		}
+		if h.OnFound != nil {
+			found = append(found, h.OnFound)
+		}
+	}
+
+	if len(progress) > 1 {
+		opts.Progress = func(scanner string, done, total int) {
+			for _, f := range progress {
+				f(scanner, done, total)
+			}
+		}
+	} else if len(progress) == 1 {
+		opts.Progress = progress[0]
+	}
+	if len(logs) > 1 {
+		opts.Log = func(entry LogEntry) {
+			for _, f := range logs {
+				f(entry)
+			}
+		}
+	} else if len(logs) == 1 {
+		opts.Log = logs[0]
+	}
+	if len(found) > 1 {
+		opts.Found = func(scanner string, finding types.Finding) {
+			for _, f := range found {
+				f(scanner, finding)
+			}
+		}
+	} else if len(found) == 1 {
+		opts.Found = found[0]
+	}
+	return opts
+}
+
+func (r *Runner) scannerCompleted(result types.ScanResult) {
+	for _, h := range r.hooks {
+		if h.OnFinding != nil {
+			for _, f := range result.Findings {
+				h.OnFinding(result.ScannerName, f)
+			}
+		}
+		if h.OnScannerComplete != nil {
+			h.OnScannerComplete(result)
+		}
+	}
+}
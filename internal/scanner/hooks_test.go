@@ -0,0 +1,121 @@
+package scanner
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// progressScanner reports two units of progress and streams one finding.
+type progressScanner struct {
+	name string
+}
+
+func (s *progressScanner) Name() string        { return s.name }
+func (s *progressScanner) Description() string { return "progress mock" }
+func (s *progressScanner) Run(_ context.Context, target types.Target, opts Options) (*types.ScanResult, error) {
+	opts.ReportProgress(s.name, 1, 2)
+	opts.ReportProgress(s.name, 2, 2)
+	finding := types.Finding{Title: "Missing HSTS", Severity: types.SeverityMedium}
+	opts.ReportFinding(s.name, finding)
+	return &types.ScanResult{
+		ScannerName: s.name,
+		Target:      target,
+		Findings:    []types.Finding{finding},
+	}, nil
+}
+
+// recorder collects hook calls as strings.
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) add(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) hooks() Hooks {
+	return Hooks{
+		OnScannerStart: func(name string) { r.add("start " + name) },
+		OnProgress: func(name string, done, total int) {
+			r.add(name + " progress")
+		},
+		OnFound:   func(name string, f types.Finding) { r.add(name + " found " + f.Title) },
+		OnFinding: func(name string, f types.Finding) { r.add(name + " finding " + f.Title + " " + string(f.Severity)) },
+		OnScannerComplete: func(result types.ScanResult) {
+			r.add("complete " + result.ScannerName + " " + result.Error)
+		},
+	}
+}
+
+func TestRunnerHooks(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&progressScanner{name: "headers"})
+
+	var rec recorder
+	runner := NewRunner(reg)
+	runner.Use(rec.hooks())
+
+	var progress, found int
+	opts := DefaultOptions()
+	opts.Progress = func(string, int, int) { progress++ }
+	opts.Found = func(string, types.Finding) { found++ }
+	overrides, err := ParseSeverityOverrides(map[string]string{"Missing HSTS": "HIGH"})
+	require.NoError(t, err)
+	opts.Overrides = overrides
+
+	_, err = runner.RunOne(context.Background(), "headers", types.Target{Host: "localhost"}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"start headers",
+		"headers progress",
+		"headers progress",
+		"headers found Missing HSTS",
+		"headers finding Missing HSTS HIGH",
+		"complete headers ",
+	}, rec.events)
+	assert.Equal(t, 2, progress, "Options.Progress still receives progress")
+	assert.Equal(t, 1, found, "Options.Found still receives streamed findings")
+}
+
+func TestRunnerHooksReportFailures(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&mockScanner{name: "test"})
+
+	var rec recorder
+	runner := NewRunner(reg).With(rec.hooks())
+
+	opts := DefaultOptions()
+	opts.Disabled = map[string]string{"test": "excluded"}
+	runner.RunAll(context.Background(), []string{"test", "unknown"}, types.Target{Host: "localhost"}, opts)
+
+	require.Len(t, rec.events, 2)
+	assert.Contains(t, rec.events[0], "complete test")
+	assert.Contains(t, rec.events[0], "disabled")
+	assert.Contains(t, rec.events[1], "complete unknown")
+	assert.Contains(t, rec.events[1], "not found")
+}
+
+func TestRunnerWithKeepsHooksPerScan(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&mockScanner{name: "test"})
+
+	var shared, job recorder
+	runner := NewRunner(reg)
+	runner.Use(shared.hooks())
+
+	_, err := runner.With(job.hooks()).RunOne(context.Background(), "test", types.Target{Host: "localhost"}, DefaultOptions())
+	require.NoError(t, err)
+	_, err = runner.RunOne(context.Background(), "test", types.Target{Host: "localhost"}, DefaultOptions())
+	require.NoError(t, err)
+
+	assert.Len(t, job.events, 3, "start, finding, complete of the one scan")
+	assert.Len(t, shared.events, 6)
+}
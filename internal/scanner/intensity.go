@@ -0,0 +1,82 @@
+package scanner
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Intensity is how hard a scan pushes the target: how many requests,
+// paths, payloads, and login attempts scanners send. The zero value is
+// IntensityNormal.
+type Intensity string
+
+const (
+	// IntensitySafe keeps request counts low and skips attempts that could
+	// lock accounts, for production targets.
+	IntensitySafe Intensity = "safe"
+	// IntensityNormal is the scanners' built-in behavior.
+	IntensityNormal Intensity = "normal"
+	// IntensityAggressive sends everything the scanners have, for lab and
+	// staging targets.
+	IntensityAggressive Intensity = "aggressive"
+)
+
+// ParseIntensity parses an --intensity value; "" is IntensityNormal.
+func ParseIntensity(s string) (Intensity, error) {
+	switch i := Intensity(strings.ToLower(strings.TrimSpace(s))); i {
+	case "":
+		return IntensityNormal, nil
+	case IntensitySafe, IntensityNormal, IntensityAggressive:
+		return i, nil
+	}
+	return "", fmt.Errorf("invalid intensity %q (available: safe, normal, aggressive)", s)
+}
+
+// Unlimited is the budget of units a scanner may send all of.
+const Unlimited = -1
+
+// intensityBudgets is the central policy scaling scanners by intensity: how
+// many units of each kind they send at safe, normal, and aggressive
+// intensity. Keys are "<scanner>.<unit>".
+var intensityBudgets = map[string][3]int{
+	"api-ratelimit.requests":       {20, 50, 200},
+	"api-auth.bypass_payloads":     {2, 5, Unlimited},
+	"api-auth.default_credentials": {0, 2, Unlimited},
+	"api-auth.jwt_variants":        {2, Unlimited, Unlimited},
+	"api-auth.path_bypasses":       {2, Unlimited, Unlimited},
+	"api-auth.write_methods":       {0, 0, Unlimited},
+	"csrf.replays":                 {0, 10, Unlimited},
+	"dirs.paths":                   {250, Unlimited, Unlimited},
+	"headers.areas":                {10, 25, Unlimited},
+	"protocols.oversized_headers":  {0, 1, 1},
+	"protocols.sans":               {5, 20, Unlimited},
+	"vuln.mined_params":            {25, Unlimited, Unlimited},
+	"vuln.payloads":                {1, 4, Unlimited},
+	"vuln.pollution_probes":        {1, Unlimited, Unlimited},
+}
+
+// Budget returns how many units of kind, such as "api-ratelimit.requests",
+// a scanner may send at o.Intensity, or Unlimited. Kinds missing from the
+// policy are unlimited.
+func (o Options) Budget(kind string) int {
+	budgets, ok := intensityBudgets[kind]
+	if !ok {
+		return Unlimited
+	}
+	switch o.Intensity {
+	case IntensitySafe:
+		return budgets[0]
+	case IntensityAggressive:
+		return budgets[2]
+	}
+	return budgets[1]
+}
+
+// Limit returns the first n items, or all of them when n is Unlimited or
+// more than there are.
+func Limit[T any](items []T, n int) []T {
+	if n < 0 || n >= len(items) {
+		return items
+	}
+	return items[:n]
+}
@@ -0,0 +1,35 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIntensity(t *testing.T) {
+	for input, want := range map[string]Intensity{"": IntensityNormal, "safe": IntensitySafe, "Normal": IntensityNormal, " aggressive ": IntensityAggressive} {
+		got, err := ParseIntensity(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	_, err := ParseIntensity("insane")
+	assert.ErrorContains(t, err, "available: safe, normal, aggressive")
+}
+
+func TestOptions_Budget(t *testing.T) {
+	assert.Equal(t, 50, Options{}.Budget("api-ratelimit.requests"), "the zero value is normal")
+	assert.Equal(t, 20, Options{Intensity: IntensitySafe}.Budget("api-ratelimit.requests"))
+	assert.Equal(t, 200, Options{Intensity: IntensityAggressive}.Budget("api-ratelimit.requests"))
+	assert.Equal(t, Unlimited, Options{}.Budget("dirs.paths"))
+	assert.Equal(t, Unlimited, Options{Intensity: IntensitySafe}.Budget("unknown.units"))
+}
+
+func TestLimit(t *testing.T) {
+	items := []string{"a", "b", "c"}
+	assert.Equal(t, []string{"a", "b"}, Limit(items, 2))
+	assert.Equal(t, items, Limit(items, 5))
+	assert.Equal(t, items, Limit(items, Unlimited))
+	assert.Empty(t, Limit(items, 0))
+}
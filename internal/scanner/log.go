@@ -0,0 +1,43 @@
+package scanner
+
+import (
+	"fmt"
+	"time"
+)
+
+// LogLevel grades a scan log entry.
+type LogLevel string
+
+// Log levels, from routine to failure.
+const (
+	LogInfo  LogLevel = "info"
+	LogWarn  LogLevel = "warn"
+	LogError LogLevel = "error"
+)
+
+// LogEntry is a diagnostic line produced during a scan, such as a request
+// sent, a check skipped, or an error, kept to explain why a scanner found
+// what it found (or nothing).
+type LogEntry struct {
+	Time    time.Time `json:"time"`
+	Scanner string    `json:"scanner,omitempty"`
+	Level   LogLevel  `json:"level"`
+	Message string    `json:"message"`
+}
+
+// LogFunc receives scan log entries. It may be called concurrently.
+type LogFunc func(entry LogEntry)
+
+// Logf formats a log entry for scanner and forwards it to o.Log if one is
+// set.
+func (o Options) Logf(scanner string, level LogLevel, format string, args ...interface{}) {
+	if o.Log == nil {
+		return
+	}
+	o.Log(LogEntry{
+		Time:    time.Now(),
+		Scanner: scanner,
+		Level:   level,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
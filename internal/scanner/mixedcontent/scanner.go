@@ -0,0 +1,250 @@
+package mixedcontent
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// maxPageSize is the most of a page read.
+const maxPageSize = 2 << 20
+
+// maxListed is how many insecure URLs a finding's evidence lists.
+const maxListed = 10
+
+var (
+	resourceTag = regexp.MustCompile(`(?is)<(script|iframe|frame|object|embed|link|img|audio|video|source|track)\b[^>]*>`)
+	attribute   = regexp.MustCompile(`(?is)([a-z][a-z0-9_-]*)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+)
+
+// Mixed content kinds. Active content can read and change the page, so
+// browsers block it; passive content can only be seen or heard, so they
+// load it and drop the padlock.
+const (
+	active  = "active"
+	passive = "passive"
+)
+
+// Scanner finds subresources loaded over plain HTTP by HTTPS pages.
+type Scanner struct{}
+
+// New creates a new mixed content scanner.
+func New() *Scanner {
+	return &Scanner{}
+}
+
+func (s *Scanner) Name() string        { return "mixed-content" }
+func (s *Scanner) Description() string { return "Mixed content detection" }
+
+// resource is a subresource a page loads over plain HTTP.
+type resource struct {
+	URL  string
+	Tag  string
+	Kind string
+}
+
+func (s *Scanner) Run(ctx context.Context, target types.Target, opts scanner.Options) (*types.ScanResult, error) {
+	result := &types.ScanResult{
+		ScannerName: s.Name(),
+		Target:      target,
+		StartedAt:   time.Now(),
+	}
+
+	pageURL := target.URL
+	if pageURL == "" {
+		pageURL = "https://" + target.URLHost() + "/"
+	}
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	client := &http.Client{Timeout: timeout, Transport: opts.HTTPTransport()}
+
+	pages := []string{pageURL}
+	for _, ep := range opts.EndpointsOn(pageURL) {
+		if ep.Method == http.MethodGet {
+			pages = append(pages, ep.URL)
+		}
+	}
+
+	// A resource is reported for the first page it was seen on, since a
+	// site's pages mostly share their layout.
+	reported := map[string]bool{}
+	checked := 0
+	for i, page := range pages {
+		if ctx.Err() != nil {
+			break
+		}
+		finalURL, body, err := fetch(ctx, client, page)
+		if err != nil {
+			if i == 0 {
+				return nil, err
+			}
+			continue
+		}
+		if !strings.HasPrefix(finalURL, "https://") {
+			continue
+		}
+		checked++
+
+		byKind := map[string][]resource{}
+		for _, r := range insecureResources(finalURL, body) {
+			if !reported[r.URL] {
+				reported[r.URL] = true
+				byKind[r.Kind] = append(byKind[r.Kind], r)
+			}
+		}
+		for _, kind := range []string{active, passive} {
+			if len(byKind[kind]) > 0 {
+				result.Findings = append(result.Findings, finding(finalURL, kind, byKind[kind]))
+			}
+		}
+	}
+	if checked == 0 {
+		opts.Logf(s.Name(), scanner.LogInfo, "no page was served over HTTPS; nothing to check")
+	}
+
+	result.CompletedAt = time.Now()
+	return result, nil
+}
+
+// fetch requests page, following redirects, and returns the URL it ended
+// at and its HTML, or "" for other content.
+func fetch(ctx context.Context, client *http.Client, page string) (string, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, page, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("HTTP GET %s: %w", page, err)
+	}
+	defer resp.Body.Close()
+	if !strings.Contains(resp.Header.Get("Content-Type"), "html") {
+		return resp.Request.URL.String(), "", nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return "", "", err
+	}
+	return resp.Request.URL.String(), string(body), nil
+}
+
+// insecureResources returns the subresources body, an HTML page at
+// pageURL, loads over plain HTTP, in the order they appear. Links to other
+// pages are not subresources and are left out, as are <link>s other than
+// stylesheets.
+func insecureResources(pageURL, body string) []resource {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+	var found []resource
+	for _, m := range resourceTag.FindAllStringSubmatch(body, -1) {
+		tag := strings.ToLower(m[1])
+		attrs := map[string]string{}
+		for _, a := range attribute.FindAllStringSubmatch(m[0], -1) {
+			name := strings.ToLower(a[1])
+			if _, ok := attrs[name]; !ok {
+				attrs[name] = html.UnescapeString(a[2] + a[3] + a[4])
+			}
+		}
+
+		kind, refs := passive, []string{attrs["src"]}
+		switch tag {
+		case "script", "iframe", "frame", "embed":
+			kind = active
+		case "object":
+			kind, refs = active, []string{attrs["data"]}
+		case "link":
+			if !hasToken(attrs["rel"], "stylesheet") {
+				continue
+			}
+			kind, refs = active, []string{attrs["href"]}
+		case "video":
+			refs = append(refs, attrs["poster"])
+		case "img", "source":
+			refs = append(refs, srcset(attrs["srcset"])...)
+		}
+
+		for _, ref := range refs {
+			ref = strings.TrimSpace(ref)
+			if ref == "" {
+				continue
+			}
+			u, err := base.Parse(ref)
+			if err != nil || u.Scheme != "http" {
+				continue
+			}
+			found = append(found, resource{URL: u.String(), Tag: tag, Kind: kind})
+		}
+	}
+	return found
+}
+
+// srcset returns the URLs of a srcset attribute's candidates.
+func srcset(value string) []string {
+	var urls []string
+	for _, candidate := range strings.Split(value, ",") {
+		if fields := strings.Fields(candidate); len(fields) > 0 {
+			urls = append(urls, fields[0])
+		}
+	}
+	return urls
+}
+
+// hasToken reports whether the space-separated list value contains token.
+func hasToken(value, token string) bool {
+	for _, t := range strings.Fields(value) {
+		if strings.EqualFold(t, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// finding reports the resources of one kind page loads over plain HTTP.
+func finding(page, kind string, resources []resource) types.Finding {
+	var evidence strings.Builder
+	for i, r := range resources {
+		if i == maxListed {
+			fmt.Fprintf(&evidence, "... and %d more\n", len(resources)-maxListed)
+			break
+		}
+		fmt.Fprintf(&evidence, "<%s> %s\n", r.Tag, r.URL)
+	}
+	metadata := map[string]string{
+		"url":       page,
+		"kind":      kind,
+		"resources": strconv.Itoa(len(resources)),
+	}
+
+	if kind == active {
+		return types.Finding{
+			Title:       "Active mixed content",
+			Description: fmt.Sprintf("%s loads %d script, frame, or stylesheet resource(s) over plain HTTP; an attacker on the network can replace them and take over the page, and browsers that block them break it", page, len(resources)),
+			Severity:    types.SeverityMedium,
+			Evidence:    strings.TrimSpace(evidence.String()),
+			Remediation: "Load every subresource over https://, and consider Content-Security-Policy: upgrade-insecure-requests",
+			Metadata:    metadata,
+		}
+	}
+	return types.Finding{
+		Title:       "Passive mixed content",
+		Description: fmt.Sprintf("%s loads %d image or media resource(s) over plain HTTP, which an attacker on the network can see and replace", page, len(resources)),
+		Severity:    types.SeverityLow,
+		Evidence:    strings.TrimSpace(evidence.String()),
+		Remediation: "Load every subresource over https://, and consider Content-Security-Policy: upgrade-insecure-requests",
+		Metadata:    metadata,
+	}
+}
@@ -0,0 +1,90 @@
+package mixedcontent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsecureResources(t *testing.T) {
+	body := `<html><head>
+<script src="http://cdn.example.com/app.js"></script>
+<script src="https://cdn.example.com/safe.js"></script>
+<link rel="stylesheet" href="http://cdn.example.com/site.css">
+<link rel="canonical" href="http://example.com/">
+</head><body>
+<a href="http://example.com/about">About</a>
+<img src="/relative.png" srcset="http://img.example.com/a.png 1x, https://img.example.com/b.png 2x">
+<iframe src='http://widgets.example.com/embed'></iframe>
+<video src="https://media.example.com/v.mp4" poster="http://media.example.com/poster.jpg"></video>
+<object data="http://example.com/movie.swf"></object>
+</body></html>`
+
+	assert.Equal(t, []resource{
+		{URL: "http://cdn.example.com/app.js", Tag: "script", Kind: active},
+		{URL: "http://cdn.example.com/site.css", Tag: "link", Kind: active},
+		{URL: "http://img.example.com/a.png", Tag: "img", Kind: passive},
+		{URL: "http://widgets.example.com/embed", Tag: "iframe", Kind: active},
+		{URL: "http://media.example.com/poster.jpg", Tag: "video", Kind: passive},
+		{URL: "http://example.com/movie.swf", Tag: "object", Kind: active},
+	}, insecureResources("https://example.com/", body))
+
+	// Relative URLs take the page's scheme.
+	assert.Equal(t, []resource{{URL: "http://example.com/a.png", Tag: "img", Kind: passive}},
+		insecureResources("http://example.com/", `<img src="/a.png">`))
+}
+
+func TestScanner_ActiveAndPassive(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprint(w, `<script src="http://cdn.example.com/app.js"></script><img src="http://img.example.com/logo.png">`)
+		case "/about":
+			// The shared logo was reported for the home page already.
+			fmt.Fprint(w, `<img src="http://img.example.com/logo.png"><img src="http://img.example.com/team.png">`)
+		}
+	}))
+	defer srv.Close()
+
+	opts := scanner.Options{
+		Timeout:   2 * time.Second,
+		Transport: srv.Client().Transport,
+		Endpoints: []types.Endpoint{{Method: http.MethodGet, URL: srv.URL + "/about"}},
+	}
+	result, err := New().Run(context.Background(), types.Target{Host: "127.0.0.1", URL: srv.URL + "/"}, opts)
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 3)
+
+	assert.Equal(t, "Active mixed content", result.Findings[0].Title)
+	assert.Equal(t, types.SeverityMedium, result.Findings[0].Severity)
+	assert.Equal(t, "<script> http://cdn.example.com/app.js", result.Findings[0].Evidence)
+
+	assert.Equal(t, "Passive mixed content", result.Findings[1].Title)
+	assert.Equal(t, types.SeverityLow, result.Findings[1].Severity)
+	assert.Equal(t, srv.URL+"/", result.Findings[1].Metadata["url"])
+
+	assert.Equal(t, srv.URL+"/about", result.Findings[2].Metadata["url"])
+	assert.Equal(t, "1", result.Findings[2].Metadata["resources"])
+	assert.Equal(t, "<img> http://img.example.com/team.png", result.Findings[2].Evidence)
+}
+
+func TestScanner_SkipsPlainHTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<script src="http://cdn.example.com/app.js"></script>`)
+	}))
+	defer srv.Close()
+
+	result, err := New().Run(context.Background(), types.Target{Host: "127.0.0.1", URL: srv.URL}, scanner.Options{Timeout: 2 * time.Second})
+	require.NoError(t, err)
+	assert.Empty(t, result.Findings, "an http:// page has no mixed content")
+}
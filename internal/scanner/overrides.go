@@ -0,0 +1,131 @@
+package scanner
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/buemura/hunter/internal/rules"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// SeverityIgnore as an override value drops matching findings entirely.
+const SeverityIgnore = "IGNORE"
+
+// severityOverride remaps findings of the rule with ID ruleID, or those
+// whose title matches pattern.
+type severityOverride struct {
+	ruleID   string
+	pattern  string
+	severity types.Severity // empty means ignore
+}
+
+// SeverityOverrides remaps finding severities by rule ID or title, so
+// organisational policy can raise, lower, or silence findings without code
+// changes.
+type SeverityOverrides []severityOverride
+
+// ParseSeverityOverrides builds overrides from a map of rule IDs
+// ("HUNTER-HEADERS-006") or titles to severities. Titles match
+// case-insensitively and may use shell-style wildcards ("Missing *").
+// Severities are CRITICAL, HIGH, MEDIUM, LOW, INFO, or IGNORE.
+func ParseSeverityOverrides(raw map[string]string) (SeverityOverrides, error) {
+	var overrides SeverityOverrides
+	for key, value := range raw {
+		var ov severityOverride
+		if rules.IsID(key) {
+			rule, ok := rules.Lookup(key)
+			if !ok {
+				return nil, fmt.Errorf("severity override %q: unknown rule ID (see hunter rules list)", key)
+			}
+			ov.ruleID = rule.ID
+		} else {
+			ov.pattern = strings.ToLower(key)
+			if _, err := path.Match(ov.pattern, ""); err != nil {
+				return nil, fmt.Errorf("severity override %q: invalid pattern: %w", key, err)
+			}
+		}
+
+		sev := strings.ToUpper(strings.TrimSpace(value))
+		switch types.Severity(sev) {
+		case types.SeverityCritical, types.SeverityHigh, types.SeverityMedium, types.SeverityLow, types.SeverityInfo:
+			ov.severity = types.Severity(sev)
+		default:
+			if sev != SeverityIgnore {
+				return nil, fmt.Errorf("severity override %q: unknown severity %q (supported: CRITICAL, HIGH, MEDIUM, LOW, INFO, IGNORE)", key, value)
+			}
+		}
+		overrides = append(overrides, ov)
+	}
+
+	// Rule IDs take precedence over titles, and exact titles over wildcard
+	// patterns; among patterns of the same kind the longer (more specific)
+	// one wins.
+	sort.Slice(overrides, func(i, j int) bool {
+		if ai, bi := overrides[i].ruleID, overrides[j].ruleID; ai != "" || bi != "" {
+			if (ai != "") != (bi != "") {
+				return ai != ""
+			}
+			return ai < bi
+		}
+		a, b := overrides[i].pattern, overrides[j].pattern
+		aw, bw := strings.ContainsAny(a, "*?["), strings.ContainsAny(b, "*?[")
+		if aw != bw {
+			return !aw
+		}
+		if len(a) != len(b) {
+			return len(a) > len(b)
+		}
+		return a < b
+	})
+	return overrides, nil
+}
+
+// Apply rewrites the severities of result's findings in place, dropping
+// those overridden to IGNORE. A remapped finding keeps its original severity
+// in the "original_severity" metadata key.
+func (o SeverityOverrides) Apply(result *types.ScanResult) {
+	if len(o) == 0 || result == nil {
+		return
+	}
+
+	kept := result.Findings[:0]
+	for _, f := range result.Findings {
+		ov, ok := o.match(f)
+		if !ok {
+			kept = append(kept, f)
+			continue
+		}
+		if ov.severity == "" {
+			continue
+		}
+		if ov.severity != f.Severity {
+			meta := make(map[string]string, len(f.Metadata)+1)
+			for k, v := range f.Metadata {
+				meta[k] = v
+			}
+			meta["original_severity"] = string(f.Severity)
+			f.Metadata = meta
+			f.Severity = ov.severity
+		}
+		kept = append(kept, f)
+	}
+	result.Findings = kept
+}
+
+func (o SeverityOverrides) match(f types.Finding) (severityOverride, bool) {
+	title := strings.ToLower(f.Title)
+	for _, ov := range o {
+		if ov.ruleID != "" {
+			if ov.ruleID == f.Metadata[rules.MetadataKey] {
+				return ov, true
+			}
+			continue
+		}
+		if ok, _ := path.Match(ov.pattern, title); ok {
+			return ov, true
+		}
+	}
+	return severityOverride{}, false
+}
@@ -0,0 +1,91 @@
+package scanner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func headerFindings() *types.ScanResult {
+	return &types.ScanResult{
+		ScannerName: "headers",
+		Findings: []types.Finding{
+			{Title: "Missing X-XSS-Protection", Severity: types.SeverityLow},
+			{Title: "Missing Content-Security-Policy", Severity: types.SeverityMedium},
+			{Title: "Missing Referrer-Policy", Severity: types.SeverityLow},
+			{Title: "Server version disclosed", Severity: types.SeverityInfo},
+		},
+	}
+}
+
+func TestSeverityOverrides_Apply(t *testing.T) {
+	o, err := ParseSeverityOverrides(map[string]string{
+		"missing x-xss-protection":        "ignore",
+		"Missing Content-Security-Policy": "high",
+		"Missing *":                       "INFO",
+	})
+	require.NoError(t, err)
+
+	result := headerFindings()
+	o.Apply(result)
+
+	require.Len(t, result.Findings, 3)
+	assert.Equal(t, "Missing Content-Security-Policy", result.Findings[0].Title)
+	assert.Equal(t, types.SeverityHigh, result.Findings[0].Severity, "exact title wins over wildcard")
+	assert.Equal(t, "MEDIUM", result.Findings[0].Metadata["original_severity"])
+	assert.Equal(t, types.SeverityInfo, result.Findings[1].Severity)
+	assert.Equal(t, types.SeverityInfo, result.Findings[2].Severity)
+	assert.Nil(t, result.Findings[2].Metadata, "unchanged findings are left alone")
+}
+
+func TestSeverityOverrides_RuleID(t *testing.T) {
+	o, err := ParseSeverityOverrides(map[string]string{
+		"hunter-headers-006": "ignore",
+		"Missing *":          "HIGH",
+	})
+	require.NoError(t, err)
+
+	result := &types.ScanResult{
+		ScannerName: "headers",
+		Findings: []types.Finding{
+			{Title: "Missing X-XSS-Protection header", Severity: types.SeverityLow, Metadata: map[string]string{"rule_id": "HUNTER-HEADERS-006"}},
+			{Title: "Missing Referrer-Policy header", Severity: types.SeverityLow, Metadata: map[string]string{"rule_id": "HUNTER-HEADERS-007"}},
+		},
+	}
+	o.Apply(result)
+
+	require.Len(t, result.Findings, 1, "the rule ID wins over the title pattern")
+	assert.Equal(t, "Missing Referrer-Policy header", result.Findings[0].Title)
+	assert.Equal(t, types.SeverityHigh, result.Findings[0].Severity)
+}
+
+func TestParseSeverityOverrides_Errors(t *testing.T) {
+	_, err := ParseSeverityOverrides(map[string]string{"Missing CSP": "urgent"})
+	assert.ErrorContains(t, err, "unknown severity")
+
+	_, err = ParseSeverityOverrides(map[string]string{"Missing [": "LOW"})
+	assert.ErrorContains(t, err, "invalid pattern")
+
+	_, err = ParseSeverityOverrides(map[string]string{"HUNTER-HEADERS-999": "LOW"})
+	assert.ErrorContains(t, err, "unknown rule ID")
+}
+
+func TestRunner_AppliesSeverityOverrides(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&mockScanner{name: "test"})
+
+	opts := DefaultOptions()
+	opts.Overrides, _ = ParseSeverityOverrides(map[string]string{"mock finding": "CRITICAL"})
+
+	result, err := NewRunner(reg).RunOne(context.Background(), "test", types.Target{Host: "localhost"}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, types.SeverityCritical, result.Findings[0].Severity)
+
+	opts.Overrides, _ = ParseSeverityOverrides(map[string]string{"mock *": "ignore"})
+	results := NewRunner(reg).RunAll(context.Background(), []string{"test"}, types.Target{Host: "localhost"}, opts)
+	require.Len(t, results, 1)
+	assert.Empty(t, results[0].Findings)
+}
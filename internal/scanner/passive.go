@@ -0,0 +1,133 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PassiveSource is a third-party data set, such as certificate transparency
+// logs or passive DNS, that knows about a domain's hosts and certificates.
+// Recon scanners query it instead of, or before, contacting the target.
+type PassiveSource interface {
+	Name() string
+	// Lookup returns what the source knows about domain and the names
+	// under it.
+	Lookup(ctx context.Context, domain string) (*PassiveData, error)
+}
+
+// PassiveData is what passive sources know about a domain.
+type PassiveData struct {
+	Hosts        []PassiveHost
+	Certificates []PassiveCertificate
+}
+
+// PassiveHost is a host name seen under a domain.
+type PassiveHost struct {
+	Name      string
+	Addresses []string // addresses it was seen resolving to, if recorded
+	Sources   []string // names of the sources that reported it
+}
+
+// PassiveCertificate is a certificate logged for a domain.
+type PassiveCertificate struct {
+	Source       string
+	ID           string // the source's identifier for the certificate
+	Names        []string
+	Issuer       string
+	SerialNumber string
+	NotBefore    time.Time
+	NotAfter     time.Time
+}
+
+// Covers reports whether the certificate is valid for host, directly or
+// through a wildcard name.
+func (c PassiveCertificate) Covers(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, name := range c.Names {
+		name = strings.ToLower(name)
+		if name == host {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(name, "*."); ok {
+			if label, rest, found := strings.Cut(host, "."); found && label != "" && rest == suffix {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PassiveLookup queries every source in o.Sources about domain and merges
+// their answers: hosts by name, sorted, and certificates by source and ID.
+// Sources that fail are reported in errs, each error prefixed with the
+// source's name; the others' data is still returned.
+func (o Options) PassiveLookup(ctx context.Context, domain string) (data PassiveData, errs []error) {
+	hosts := map[string]*PassiveHost{}
+	seenCerts := map[string]bool{}
+	for _, source := range o.Sources {
+		found, err := source.Lookup(ctx, domain)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source.Name(), err))
+			continue
+		}
+		if found == nil {
+			continue
+		}
+
+		for _, h := range found.Hosts {
+			name := strings.ToLower(strings.TrimSuffix(h.Name, "."))
+			host, ok := hosts[name]
+			if !ok {
+				host = &PassiveHost{Name: name}
+				hosts[name] = host
+			}
+			host.Addresses = appendMissing(host.Addresses, h.Addresses...)
+			host.Sources = appendMissing(host.Sources, source.Name())
+		}
+		for _, c := range found.Certificates {
+			key := source.Name() + "/" + c.ID
+			if c.ID != "" && seenCerts[key] {
+				continue
+			}
+			seenCerts[key] = true
+			if c.Source == "" {
+				c.Source = source.Name()
+			}
+			data.Certificates = append(data.Certificates, c)
+		}
+	}
+
+	for _, h := range hosts {
+		data.Hosts = append(data.Hosts, *h)
+	}
+	sort.Slice(data.Hosts, func(i, j int) bool { return data.Hosts[i].Name < data.Hosts[j].Name })
+	return data, errs
+}
+
+func appendMissing(list []string, values ...string) []string {
+	for _, v := range values {
+		if !slices.Contains(list, v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// PassiveScanner is implemented by scanners that can work from
+// Options.Sources alone. When Options.Passive forbids contacting the
+// target, the Runner skips every scanner that does not implement it.
+type PassiveScanner interface {
+	SupportsPassive() bool
+}
+
+func supportsPassive(s Scanner) bool {
+	p, ok := s.(PassiveScanner)
+	return ok && p.SupportsPassive()
+}
+
+// passiveSkipReason is why the Runner skips other scanners in passive mode.
+const passiveSkipReason = "passive mode forbids contacting the target"
@@ -0,0 +1,156 @@
+// Package passive provides the built-in passive data sources recon
+// scanners query instead of contacting the target: crt.sh for certificate
+// transparency logs, and a generic passive DNS provider.
+package passive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+)
+
+// defaultCrtShURL is where crt.sh is queried.
+const defaultCrtShURL = "https://crt.sh"
+
+// defaultTimeout bounds one query to a source. crt.sh in particular often
+// takes tens of seconds for popular domains.
+const defaultTimeout = 60 * time.Second
+
+// maxResponseSize caps how much of a source's answer is read.
+const maxResponseSize = 64 << 20
+
+// CrtSh queries crt.sh, a search engine over certificate transparency logs,
+// for the certificates issued to names under a domain.
+type CrtSh struct {
+	BaseURL   string            // empty for crt.sh itself
+	Transport http.RoundTripper // nil for http.DefaultTransport
+	Timeout   time.Duration     // zero for defaultTimeout
+}
+
+// NewCrtSh returns a crt.sh source sending its queries over transport.
+func NewCrtSh(transport http.RoundTripper) *CrtSh {
+	return &CrtSh{Transport: transport}
+}
+
+func (c *CrtSh) Name() string { return "crt.sh" }
+
+// crtShEntry is one certificate in crt.sh's JSON output.
+type crtShEntry struct {
+	ID           int64  `json:"id"`
+	IssuerName   string `json:"issuer_name"`
+	CommonName   string `json:"common_name"`
+	NameValue    string `json:"name_value"` // the certificate's names, one per line
+	SerialNumber string `json:"serial_number"`
+	NotBefore    string `json:"not_before"`
+	NotAfter     string `json:"not_after"`
+}
+
+// crtShTime is the layout of crt.sh timestamps, which are in UTC.
+const crtShTime = "2006-01-02T15:04:05"
+
+// Lookup returns the certificates logged for domain and the names under
+// it, and those names as hosts. Wildcard names count as their parent.
+func (c *CrtSh) Lookup(ctx context.Context, domain string) (*scanner.PassiveData, error) {
+	base := c.BaseURL
+	if base == "" {
+		base = defaultCrtShURL
+	}
+	// crt.sh matches names exactly or, with %, by pattern: the domain's own
+	// certificates and those of names under it take a query each.
+	var entries []crtShEntry
+	for _, q := range []string{domain, "%." + domain} {
+		query := url.Values{"q": {q}, "output": {"json"}}
+		body, err := get(ctx, c.Transport, c.Timeout, strings.TrimRight(base, "/")+"/?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		var page []crtShEntry
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("parsing response: %w", err)
+		}
+		entries = append(entries, page...)
+	}
+
+	data := &scanner.PassiveData{}
+	seen := map[string]bool{}
+	seenIDs := map[int64]bool{}
+	for _, e := range entries {
+		if seenIDs[e.ID] {
+			continue
+		}
+		seenIDs[e.ID] = true
+		cert := scanner.PassiveCertificate{
+			ID:           strconv.FormatInt(e.ID, 10),
+			Issuer:       e.IssuerName,
+			SerialNumber: e.SerialNumber,
+		}
+		cert.NotBefore, _ = time.Parse(crtShTime, e.NotBefore)
+		cert.NotAfter, _ = time.Parse(crtShTime, e.NotAfter)
+		for _, name := range strings.Split(e.NameValue+"\n"+e.CommonName, "\n") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" || !inDomain(strings.TrimPrefix(name, "*."), domain) {
+				continue
+			}
+			if !slices.Contains(cert.Names, name) {
+				cert.Names = append(cert.Names, name)
+			}
+			host := strings.TrimPrefix(name, "*.")
+			if !seen[host] {
+				seen[host] = true
+				data.Hosts = append(data.Hosts, scanner.PassiveHost{Name: host})
+			}
+		}
+		data.Certificates = append(data.Certificates, cert)
+	}
+	return data, nil
+}
+
+// inDomain reports whether name is domain or a name under it.
+func inDomain(name, domain string) bool {
+	name, domain = strings.ToLower(name), strings.ToLower(domain)
+	return name == domain || strings.HasSuffix(name, "."+domain)
+}
+
+// get fetches rawURL with the given headers and returns the response body,
+// failing on non-2xx statuses.
+func get(ctx context.Context, transport http.RoundTripper, timeout time.Duration, rawURL string, headers map[string]string) ([]byte, error) {
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	client := &http.Client{Transport: transport}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return body, nil
+}
@@ -0,0 +1,163 @@
+package passive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+)
+
+// DNS queries a passive DNS provider over HTTP. Providers differ in their
+// APIs, so the answer is read loosely: a JSON value or a stream of them
+// (NDJSON), in which strings are host names and objects are records with a
+// name field (rrname, hostname, host, name, domain, subdomain) and
+// optionally an address field (rdata, ip, address, value, answer). Records
+// nested in arrays or under other keys are found too, as are plain-text
+// answers with a host name, optionally followed by an address, per line.
+type DNS struct {
+	Label     string            // the source's name in findings; "passive-dns" if empty
+	URL       string            // the query URL, with {domain} where the domain goes
+	Headers   map[string]string // e.g. the provider's API key
+	Transport http.RoundTripper // nil for http.DefaultTransport
+	Timeout   time.Duration     // zero for defaultTimeout
+}
+
+func (d *DNS) Name() string {
+	if d.Label != "" {
+		return d.Label
+	}
+	return "passive-dns"
+}
+
+var (
+	nameKeys    = []string{"rrname", "hostname", "host", "name", "domain", "subdomain"}
+	addressKeys = []string{"rdata", "ip", "address", "value", "answer"}
+)
+
+// Lookup returns the hosts under domain the provider has seen, with the
+// addresses they resolved to when it records them.
+func (d *DNS) Lookup(ctx context.Context, domain string) (*scanner.PassiveData, error) {
+	if !strings.Contains(d.URL, "{domain}") {
+		return nil, fmt.Errorf("query URL %q has no {domain} placeholder", d.URL)
+	}
+	rawURL := strings.ReplaceAll(d.URL, "{domain}", url.QueryEscape(domain))
+	body, err := get(ctx, d.Transport, d.Timeout, rawURL, d.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := map[string][]string{}
+	add := func(name, address string) {
+		name = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+		name = strings.TrimPrefix(name, "*.")
+		if name == "" || !inDomain(name, domain) {
+			return
+		}
+		addrs := hosts[name]
+		if ip := net.ParseIP(strings.TrimSpace(address)); ip != nil && !slices.Contains(addrs, ip.String()) {
+			addrs = append(addrs, ip.String())
+		}
+		hosts[name] = addrs
+	}
+
+	if err := readRecords(body, add); err != nil {
+		return nil, err
+	}
+
+	data := &scanner.PassiveData{}
+	names := make([]string, 0, len(hosts))
+	for name := range hosts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		data.Hosts = append(data.Hosts, scanner.PassiveHost{Name: name, Addresses: hosts[name]})
+	}
+	return data, nil
+}
+
+// readRecords calls add for every host name, and its address if given,
+// in body: JSON values, or plain text if body is not JSON.
+func readRecords(body []byte, add func(name, address string)) error {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return nil
+	}
+	if trimmed[0] != '{' && trimmed[0] != '[' && trimmed[0] != '"' {
+		for _, line := range strings.Split(string(trimmed), "\n") {
+			fields := strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+			switch len(fields) {
+			case 0:
+			case 1:
+				add(fields[0], "")
+			default:
+				add(fields[0], fields[1])
+			}
+		}
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(trimmed))
+	for {
+		var v interface{}
+		err := dec.Decode(&v)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("parsing response: %w", err)
+		}
+		walkRecords(v, add)
+	}
+}
+
+// walkRecords finds the records in a decoded JSON value.
+func walkRecords(v interface{}, add func(name, address string)) {
+	switch v := v.(type) {
+	case string:
+		add(v, "")
+	case []interface{}:
+		for _, item := range v {
+			walkRecords(item, add)
+		}
+	case map[string]interface{}:
+		if name := stringField(v, nameKeys); name != "" {
+			add(name, stringField(v, addressKeys))
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			switch v[k].(type) {
+			case []interface{}, map[string]interface{}:
+				walkRecords(v[k], add)
+			}
+		}
+	}
+}
+
+// stringField returns the first of keys that holds a string in m,
+// matching keys case-insensitively.
+func stringField(m map[string]interface{}, keys []string) string {
+	for _, key := range keys {
+		for k, v := range m {
+			if s, ok := v.(string); ok && strings.EqualFold(k, key) {
+				return s
+			}
+		}
+	}
+	return ""
+}
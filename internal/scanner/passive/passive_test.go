@@ -0,0 +1,117 @@
+package passive
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrtSh_Lookup(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("q"))
+		assert.Equal(t, "json", r.URL.Query().Get("output"))
+		if r.URL.Query().Get("q") == "example.com" {
+			w.Write([]byte(`[{"id":1,"issuer_name":"CN=R3","common_name":"example.com","name_value":"example.com\nwww.example.com","serial_number":"0a","not_before":"2024-01-01T00:00:00","not_after":"2024-04-01T00:00:00"}]`))
+			return
+		}
+		w.Write([]byte(`[
+			{"id":1,"issuer_name":"CN=R3","common_name":"example.com","name_value":"example.com\nwww.example.com","serial_number":"0a","not_before":"2024-01-01T00:00:00","not_after":"2024-04-01T00:00:00"},
+			{"id":2,"issuer_name":"CN=R3","common_name":"*.api.example.com","name_value":"*.api.example.com\nother.test","serial_number":"0b","not_before":"2024-02-01T00:00:00","not_after":"2024-05-01T00:00:00"}
+		]`))
+	}))
+	defer srv.Close()
+
+	c := &CrtSh{BaseURL: srv.URL}
+	assert.Equal(t, "crt.sh", c.Name())
+	data, err := c.Lookup(context.Background(), "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.com", "%.example.com"}, queries)
+
+	names := make([]string, len(data.Hosts))
+	for i, h := range data.Hosts {
+		names[i] = h.Name
+	}
+	assert.Equal(t, []string{"example.com", "www.example.com", "api.example.com"}, names,
+		"wildcards count as their parent and names outside the domain are dropped")
+
+	require.Len(t, data.Certificates, 2, "certificates found by both queries are kept once")
+	cert := data.Certificates[1]
+	assert.Equal(t, "2", cert.ID)
+	assert.Equal(t, []string{"*.api.example.com"}, cert.Names)
+	assert.Equal(t, "CN=R3", cert.Issuer)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), cert.NotAfter)
+}
+
+func TestCrtSh_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	_, err := (&CrtSh{BaseURL: srv.URL}).Lookup(context.Background(), "example.com")
+	assert.ErrorContains(t, err, "502")
+}
+
+func TestDNS_Lookup(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []scanner.PassiveHost
+	}{
+		{
+			name: "NDJSON records",
+			body: `{"rrname":"www.example.com.","rrtype":"A","rdata":"192.0.2.1"}
+{"rrname":"www.example.com","rrtype":"A","rdata":"192.0.2.2"}
+{"rrname":"mail.example.com","rrtype":"CNAME","rdata":"mx.provider.test"}`,
+			want: []scanner.PassiveHost{
+				{Name: "mail.example.com"},
+				{Name: "www.example.com", Addresses: []string{"192.0.2.1", "192.0.2.2"}},
+			},
+		},
+		{
+			name: "nested records",
+			body: `{"status":"ok","data":{"records":[{"hostname":"API.example.com","ip":"2001:db8::1"},{"hostname":"x.other.test"}]}}`,
+			want: []scanner.PassiveHost{{Name: "api.example.com", Addresses: []string{"2001:db8::1"}}},
+		},
+		{
+			name: "list of names",
+			body: `["a.example.com","*.b.example.com"]`,
+			want: []scanner.PassiveHost{{Name: "a.example.com"}, {Name: "b.example.com"}},
+		},
+		{
+			name: "plain text",
+			body: "a.example.com,192.0.2.9\n\nb.example.com\n",
+			want: []scanner.PassiveHost{{Name: "a.example.com", Addresses: []string{"192.0.2.9"}}, {Name: "b.example.com"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/v1/example.com", r.URL.Path)
+				assert.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			d := &DNS{URL: srv.URL + "/v1/{domain}", Headers: map[string]string{"X-Api-Key": "secret"}}
+			data, err := d.Lookup(context.Background(), "example.com")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, data.Hosts)
+		})
+	}
+}
+
+func TestDNS_Name(t *testing.T) {
+	assert.Equal(t, "passive-dns", (&DNS{}).Name())
+	assert.Equal(t, "circl", (&DNS{Label: "circl"}).Name())
+
+	_, err := (&DNS{URL: "https://pdns.example.net/query"}).Lookup(context.Background(), "example.com")
+	assert.ErrorContains(t, err, "{domain}")
+}
@@ -0,0 +1,96 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubSource answers every lookup with data, or fails with err.
+type stubSource struct {
+	name string
+	data *PassiveData
+	err  error
+}
+
+func (s stubSource) Name() string { return s.name }
+func (s stubSource) Lookup(context.Context, string) (*PassiveData, error) {
+	return s.data, s.err
+}
+
+func TestPassiveLookup_MergesSources(t *testing.T) {
+	opts := Options{Sources: []PassiveSource{
+		stubSource{name: "ct", data: &PassiveData{
+			Hosts:        []PassiveHost{{Name: "www.example.com"}, {Name: "API.example.com."}},
+			Certificates: []PassiveCertificate{{ID: "1"}, {ID: "1"}, {ID: "2"}},
+		}},
+		stubSource{name: "pdns", data: &PassiveData{
+			Hosts: []PassiveHost{{Name: "api.example.com", Addresses: []string{"192.0.2.1"}}},
+		}},
+		stubSource{name: "down", err: errors.New("unreachable")},
+	}}
+
+	data, errs := opts.PassiveLookup(context.Background(), "example.com")
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "down: unreachable")
+
+	assert.Equal(t, []PassiveHost{
+		{Name: "api.example.com", Addresses: []string{"192.0.2.1"}, Sources: []string{"ct", "pdns"}},
+		{Name: "www.example.com", Sources: []string{"ct"}},
+	}, data.Hosts)
+	require.Len(t, data.Certificates, 2, "certificates are deduplicated by source and ID")
+	assert.Equal(t, "ct", data.Certificates[0].Source)
+}
+
+func TestPassiveCertificate_Covers(t *testing.T) {
+	cert := PassiveCertificate{Names: []string{"example.com", "*.api.example.com"}}
+	assert.True(t, cert.Covers("Example.com."))
+	assert.True(t, cert.Covers("v1.api.example.com"))
+	assert.False(t, cert.Covers("api.example.com"), "a wildcard does not cover its parent")
+	assert.False(t, cert.Covers("a.v1.api.example.com"), "a wildcard covers one label")
+	assert.False(t, cert.Covers("www.example.com"))
+}
+
+// passiveMock is a mock scanner that can run passively.
+type passiveMock struct {
+	mockScanner
+}
+
+func (s *passiveMock) SupportsPassive() bool { return true }
+
+func TestRunner_PassiveSkipsActiveScanners(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer srv.Close()
+
+	target, err := types.ParseTarget(srv.URL)
+	require.NoError(t, err)
+
+	reg := NewRegistry()
+	reg.Register(&mockScanner{name: "active"})
+	reg.Register(&passiveMock{mockScanner{name: "passive"}})
+
+	opts := Options{Concurrency: 1, Passive: true, Preflight: NewPreflight()}
+	results := NewRunner(reg).RunAll(context.Background(), []string{"active", "passive"}, target, opts)
+	require.Len(t, results, 2)
+	assert.Zero(t, requests.Load(), "the pre-flight probe is skipped too")
+
+	for _, r := range results {
+		require.Len(t, r.Findings, 1)
+		if r.ScannerName == "active" {
+			assert.Equal(t, "Scanner skipped", r.Findings[0].Title)
+			assert.Equal(t, passiveSkipReason, r.Findings[0].Metadata["skipped_reason"])
+		} else {
+			assert.Equal(t, "mock finding", r.Findings[0].Title)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FailurePolicy is what a scan does once one of its scanners fails.
+type FailurePolicy string
+
+const (
+	// FailureContinue runs the remaining scanners anyway. It is the
+	// default.
+	FailureContinue FailurePolicy = "continue"
+	// FailureAbort skips every scanner that has not started yet, e.g. to
+	// stop when the target goes down.
+	FailureAbort FailurePolicy = "abort-remaining"
+	// FailureSkipDependents skips the scanners that depend on the failed
+	// one, per Options.DependsOn, and those that depend on them in turn.
+	FailureSkipDependents FailurePolicy = "skip-dependents"
+)
+
+// ParseFailurePolicy parses an --on-failure value; empty means
+// FailureContinue.
+func ParseFailurePolicy(s string) (FailurePolicy, error) {
+	switch p := FailurePolicy(strings.ToLower(strings.TrimSpace(s))); p {
+	case "":
+		return FailureContinue, nil
+	case FailureContinue, FailureAbort, FailureSkipDependents:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid failure policy %q (available: continue, abort-remaining, skip-dependents)", s)
+}
+
+// ParseDependencies parses "scanner:dependency" pairs, as given to
+// --depends-on, into an Options.DependsOn map, and checks it with
+// CheckDependencies.
+func ParseDependencies(pairs []string) (map[string][]string, error) {
+	deps := map[string][]string{}
+	for _, pair := range pairs {
+		name, dep, ok := strings.Cut(pair, ":")
+		name, dep = strings.TrimSpace(name), strings.TrimSpace(dep)
+		if !ok || name == "" || dep == "" {
+			return nil, fmt.Errorf("invalid dependency %q: want scanner:dependency", pair)
+		}
+		deps[name] = append(deps[name], dep)
+	}
+	if err := CheckDependencies(deps); err != nil {
+		return nil, err
+	}
+	return deps, nil
+}
+
+// CheckDependencies reports a scanner that depends on itself, directly or
+// through others, as no order could run it.
+func CheckDependencies(deps map[string][]string) error {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[string]int{}
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("dependency cycle: %s", strings.Join(append(path, name), " -> "))
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		for _, dep := range deps[name] {
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		return nil
+	}
+
+	names := make([]string, 0, len(deps))
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Order returns names with each scanner moved after the scanners of names
+// it depends on, and otherwise in the order given. Dependencies on
+// scanners outside names are ignored.
+func Order(names []string, deps map[string][]string) []string {
+	if len(deps) == 0 {
+		return names
+	}
+	pending := make(map[string]bool, len(names))
+	for _, n := range names {
+		pending[n] = true
+	}
+	ordered := make([]string, 0, len(names))
+	for len(ordered) < len(names) {
+		progressed := false
+		for _, n := range names {
+			if !pending[n] || waitsFor(n, deps, pending) {
+				continue
+			}
+			pending[n] = false
+			ordered = append(ordered, n)
+			progressed = true
+			break
+		}
+		if !progressed {
+			// A cycle, which CheckDependencies rejects: keep the rest
+			// in the order given.
+			for _, n := range names {
+				if pending[n] {
+					ordered = append(ordered, n)
+				}
+			}
+			break
+		}
+	}
+	return ordered
+}
+
+// waitsFor reports whether name depends on a scanner still pending.
+func waitsFor(name string, deps map[string][]string, pending map[string]bool) bool {
+	for _, dep := range deps[name] {
+		if pending[dep] {
+			return true
+		}
+	}
+	return false
+}
+
+// Plan applies the OnFailure policy and DependsOn of Options across the
+// scanners of a scan. RunAll creates one; callers running scanners one by
+// one with RunOne create it with NewPlan, and run them in Order, so the
+// scanners share it. The methods of a nil Plan let every scanner run.
+type Plan struct {
+	policy FailurePolicy
+	deps   map[string][]string
+
+	mu sync.Mutex
+	// done is closed once the scanner of the scan it is keyed by has
+	// finished.
+	done map[string]chan struct{}
+	// failed holds the scanners that failed or were skipped.
+	failed map[string]bool
+	// aborted is the first scanner to fail under FailureAbort.
+	aborted string
+	// halted is why Abort stopped the scan, whatever the policy.
+	halted string
+}
+
+// NewPlan returns the plan of a scan running the named scanners with opts.
+func NewPlan(names []string, opts Options) *Plan {
+	p := &Plan{
+		policy: opts.OnFailure,
+		deps:   opts.DependsOn,
+		done:   make(map[string]chan struct{}, len(names)),
+		failed: map[string]bool{},
+	}
+	for _, n := range names {
+		p.done[n] = make(chan struct{})
+	}
+	return p
+}
+
+// wait blocks until the scanners of the scan name depends on have
+// finished, or ctx is done.
+func (p *Plan) wait(ctx context.Context, name string) error {
+	if p == nil {
+		return nil
+	}
+	for _, dep := range p.deps[name] {
+		ch, ok := p.done[dep]
+		if !ok {
+			continue
+		}
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Abort skips every scanner that has not started yet, for reason, whatever
+// the policy. Only the first reason is kept.
+func (p *Plan) Abort(reason string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.halted == "" {
+		p.halted = reason
+	}
+}
+
+// Skip reports why name must not run: the scan was aborted, an earlier
+// scanner failed under FailureAbort, or one it depends on failed or was
+// skipped under FailureSkipDependents.
+func (p *Plan) Skip(name string) (string, bool) {
+	if p == nil {
+		return "", false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.halted != "" {
+		return p.halted, true
+	}
+	switch p.policy {
+	case FailureAbort:
+		if p.aborted != "" {
+			return fmt.Sprintf("%s failed and the scan aborts on failure", p.aborted), true
+		}
+	case FailureSkipDependents:
+		for _, dep := range p.deps[name] {
+			if p.failed[dep] {
+				return fmt.Sprintf("depends on %s, which did not complete", dep), true
+			}
+		}
+	}
+	return "", false
+}
+
+// Record notes that name's scanner has finished, and whether it failed or
+// was skipped.
+func (p *Plan) Record(name string, failed bool) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if failed {
+		p.failed[name] = true
+		if p.policy == FailureAbort && p.aborted == "" {
+			p.aborted = name
+		}
+	}
+	if ch, ok := p.done[name]; ok {
+		select {
+		case <-ch:
+		default:
+			close(ch)
+		}
+	}
+}
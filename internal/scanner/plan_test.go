@@ -0,0 +1,164 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// orderScanner records when it runs in a shared log, and fails if told to.
+type orderScanner struct {
+	name  string
+	fail  bool
+	delay time.Duration
+	mu    *sync.Mutex
+	log   *[]string
+}
+
+func (s *orderScanner) Name() string        { return s.name }
+func (s *orderScanner) Description() string { return "order" }
+func (s *orderScanner) Run(_ context.Context, target types.Target, _ Options) (*types.ScanResult, error) {
+	time.Sleep(s.delay)
+	s.mu.Lock()
+	*s.log = append(*s.log, s.name)
+	s.mu.Unlock()
+	if s.fail {
+		return nil, errors.New("target is down")
+	}
+	return &types.ScanResult{ScannerName: s.name, Target: target}, nil
+}
+
+// orderRegistry registers scanners named by names; those in failing fail.
+func orderRegistry(names []string, failing ...string) (*Registry, func() []string) {
+	var mu sync.Mutex
+	var log []string
+	reg := NewRegistry()
+	for _, n := range names {
+		fail := false
+		for _, f := range failing {
+			fail = fail || f == n
+		}
+		reg.Register(&orderScanner{name: n, fail: fail, mu: &mu, log: &log})
+	}
+	return reg, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), log...)
+	}
+}
+
+func errorsByScanner(results []types.ScanResult) map[string]string {
+	errs := map[string]string{}
+	for _, r := range results {
+		errs[r.ScannerName] = r.Error
+	}
+	return errs
+}
+
+func TestParseFailurePolicy(t *testing.T) {
+	for in, want := range map[string]FailurePolicy{
+		"":                FailureContinue,
+		"continue":        FailureContinue,
+		"Abort-Remaining": FailureAbort,
+		"skip-dependents": FailureSkipDependents,
+	} {
+		got, err := ParseFailurePolicy(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	_, err := ParseFailurePolicy("stop")
+	assert.Error(t, err)
+}
+
+func TestParseDependencies(t *testing.T) {
+	deps, err := ParseDependencies([]string{"vuln:port", "vuln: tech", "forms:vuln"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"vuln": {"port", "tech"}, "forms": {"vuln"}}, deps)
+
+	_, err = ParseDependencies([]string{"vuln"})
+	assert.Error(t, err)
+	_, err = ParseDependencies([]string{"vuln:port", "port:forms", "forms:vuln"})
+	assert.ErrorContains(t, err, "dependency cycle")
+}
+
+func TestOrder(t *testing.T) {
+	names := []string{"vuln", "headers", "port", "tech"}
+	deps := map[string][]string{"vuln": {"port", "tech"}, "tech": {"subdomain"}}
+	assert.Equal(t, []string{"headers", "port", "tech", "vuln"}, Order(names, deps))
+	assert.Equal(t, names, Order(names, nil))
+}
+
+func TestRunAll_Sequential(t *testing.T) {
+	names := []string{"vuln", "port", "headers"}
+	reg, log := orderRegistry(names)
+	opts := Options{Sequential: true, DependsOn: map[string][]string{"vuln": {"port"}}}
+
+	results := NewRunner(reg).RunAll(context.Background(), names, types.Target{Host: "localhost"}, opts)
+	require.Len(t, results, 3)
+	assert.Equal(t, []string{"port", "vuln", "headers"}, log())
+	assert.Equal(t, "port", results[0].ScannerName, "results are in the order the scanners ran")
+}
+
+func TestRunAll_AbortRemaining(t *testing.T) {
+	names := []string{"port", "headers", "vuln"}
+	reg, log := orderRegistry(names, "port")
+	opts := Options{Sequential: true, OnFailure: FailureAbort}
+
+	results := NewRunner(reg).RunAll(context.Background(), names, types.Target{Host: "localhost"}, opts)
+	assert.Equal(t, []string{"port"}, log())
+	errs := errorsByScanner(results)
+	assert.Equal(t, "target is down", errs["port"])
+	assert.Contains(t, errs["headers"], "skipped: port failed")
+	assert.Contains(t, errs["vuln"], "skipped: port failed")
+}
+
+func TestRunAll_SkipDependents(t *testing.T) {
+	names := []string{"port", "tech", "vuln", "forms", "headers"}
+	reg, log := orderRegistry(names, "port")
+	opts := Options{
+		Concurrency: 4,
+		OnFailure:   FailureSkipDependents,
+		DependsOn:   map[string][]string{"vuln": {"port", "tech"}, "forms": {"vuln"}},
+	}
+
+	results := NewRunner(reg).RunAll(context.Background(), names, types.Target{Host: "localhost"}, opts)
+	assert.ElementsMatch(t, []string{"port", "tech", "headers"}, log())
+	errs := errorsByScanner(results)
+	assert.Contains(t, errs["vuln"], "depends on port")
+	assert.Contains(t, errs["forms"], "depends on vuln", "dependents of skipped scanners are skipped too")
+	assert.Empty(t, errs["headers"])
+	assert.Empty(t, errs["tech"])
+}
+
+func TestRunAll_WaitsForDependencies(t *testing.T) {
+	var mu sync.Mutex
+	var log []string
+	reg := NewRegistry()
+	reg.Register(&orderScanner{name: "subdomain", delay: 50 * time.Millisecond, mu: &mu, log: &log})
+	reg.Register(&orderScanner{name: "vuln", mu: &mu, log: &log})
+	opts := Options{Concurrency: 2, DependsOn: map[string][]string{"vuln": {"subdomain"}}}
+
+	results := NewRunner(reg).RunAll(context.Background(), []string{"vuln", "subdomain"}, types.Target{Host: "localhost"}, opts)
+	assert.Len(t, results, 2)
+	assert.Equal(t, []string{"subdomain", "vuln"}, log)
+}
+
+func TestRunOne_SharesPlan(t *testing.T) {
+	names := []string{"port", "vuln"}
+	reg, log := orderRegistry(names, "port")
+	opts := Options{OnFailure: FailureAbort}
+	opts.Plan = NewPlan(names, opts)
+
+	runner := NewRunner(reg)
+	_, err := runner.RunOne(context.Background(), "port", types.Target{Host: "localhost"}, opts)
+	assert.Error(t, err)
+	_, err = runner.RunOne(context.Background(), "vuln", types.Target{Host: "localhost"}, opts)
+	assert.ErrorContains(t, err, "skipped")
+	assert.Equal(t, []string{"port"}, log())
+}
@@ -0,0 +1,45 @@
+package port
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os/exec"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+)
+
+// pingHost reports whether the host at ip is up. It sends one ping with the
+// system ping command, which needs no privileges of its own, and falls back
+// to connecting to ports 80 and 443 when ping fails or is not installed:
+// many hosts drop ICMP but answer TCP. Extracted as a variable for testing.
+var pingHost = func(ctx context.Context, ip string, timeout time.Duration, opts scanner.Options) bool {
+	return icmpPing(ctx, ip, timeout) || tcpPing(ctx, ip, timeout, opts)
+}
+
+func icmpPing(ctx context.Context, ip string, timeout time.Duration) bool {
+	wait := max(int(timeout.Round(time.Second)/time.Second), 1)
+	ctx, cancel := context.WithTimeout(ctx, timeout+time.Second)
+	defer cancel()
+	return exec.CommandContext(ctx, "ping", "-c", "1", "-W", strconv.Itoa(wait), ip).Run() == nil
+}
+
+// tcpPing reports whether ip accepts or refuses a connection to port 80 or
+// 443. Either answer means something is there.
+func tcpPing(ctx context.Context, ip string, timeout time.Duration, opts scanner.Options) bool {
+	dialer := &net.Dialer{Timeout: timeout}
+	up := make(chan bool, 2)
+	for _, port := range []string{"80", "443"} {
+		go func() {
+			conn, err := dialer.DialContext(ctx, opts.Network(), net.JoinHostPort(ip, port))
+			if err == nil {
+				conn.Close()
+			}
+			up <- err == nil || errors.Is(err, syscall.ECONNREFUSED)
+		}()
+	}
+	return <-up || <-up
+}
@@ -2,10 +2,14 @@ package port
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"sort"
 	"strconv"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/buemura/hunter/internal/scanner"
@@ -22,6 +26,17 @@ func New() *Scanner {
 func (s *Scanner) Name() string        { return "port" }
 func (s *Scanner) Description() string { return "TCP port scanner" }
 
+// dial connects to one port. Extracted as a variable for testing.
+var dial = (*net.Dialer).DialContext
+
+// defaultHostDownAfter is how many probes in a row may go unanswered, with
+// no port answering at all, before the host is taken to be down.
+const defaultHostDownAfter = 500
+
+// defaultTarpitAfter is how many probes the scan makes before judging
+// whether the host is a tarpit. Scans of fewer ports are never judged.
+const defaultTarpitAfter = 100
+
 func (s *Scanner) Run(ctx context.Context, target types.Target, opts scanner.Options) (*types.ScanResult, error) {
 	result := &types.ScanResult{
 		ScannerName: s.Name(),
@@ -42,66 +57,193 @@ func (s *Scanner) Run(ctx context.Context, target types.Target, opts scanner.Opt
 	if timeout == 0 {
 		timeout = 3 * time.Second
 	}
+	hostDownAfter := defaultHostDownAfter
+	if _, ok := opts.ExtraArgs["host_down_after"]; ok {
+		hostDownAfter = opts.IntArg("host_down_after") // 0 disables the check
+	}
+	tarpitAfter := defaultTarpitAfter
+	if _, ok := opts.ExtraArgs["tarpit_after"]; ok {
+		tarpitAfter = opts.IntArg("tarpit_after") // 0 disables the check
+	}
+	// A host that fakes open ports accepts nearly every connection, or
+	// answers every one only as the timeout nears.
+	tarpit := scanner.NewTarpitDetector(tarpitAfter, timeout/2)
 
-	sem := make(chan struct{}, concurrency)
-	var mu sync.Mutex
-	var wg sync.WaitGroup
+	// Resolve once up front rather than on every dial.
+	ip, err := resolveAddress(ctx, target.Host, opts)
+	if err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", target.Host, err)
+	}
 
-	for _, p := range ports {
-		select {
-		case <-ctx.Done():
-			result.CompletedAt = time.Now()
-			return result, nil
-		default:
-		}
+	if opts.BoolArg("ping") && !pingHost(ctx, ip, timeout, opts) {
+		result.Findings = append(result.Findings, hostDown(ip, fmt.Sprintf("%s did not answer a ping or a connection to port 80 or 443, so its ports were not scanned.", ip), 0))
+		result.CompletedAt = time.Now()
+		return result, nil
+	}
 
-		wg.Add(1)
-		go func(port int) {
-			defer wg.Done()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Filtered ports time out as a matter of course, so the floor stays at a
+	// tenth of the requested concurrency.
+	limiter := scanner.NewAdaptiveLimiter(concurrency/10, concurrency)
+	dialer := &net.Dialer{Timeout: timeout}
+	network := opts.Network()
+	family := scanner.IPFamily(net.ParseIP(ip))
 
+	var mu sync.Mutex
+	var open []int
+	var completed, answered, unanswered int64
+	var down atomic.Bool
+
+	queue := make(chan int)
+	go func() {
+		defer close(queue)
+		for _, p := range ports {
 			select {
-			case sem <- struct{}{}:
-				defer func() { <-sem }()
+			case queue <- p:
 			case <-ctx.Done():
 				return
 			}
+		}
+	}()
 
-			addr := net.JoinHostPort(target.Host, strconv.Itoa(port))
-			conn, err := net.DialTimeout("tcp", addr, timeout)
-			if err != nil {
-				return
-			}
-			conn.Close()
-
-			svc := IdentifyService(port)
-			finding := types.Finding{
-				Title:       fmt.Sprintf("Open port: %d/%s", port, svc),
-				Description: fmt.Sprintf("TCP port %d is open (%s)", port, svc),
-				Severity:    types.SeverityInfo,
-				Metadata: map[string]string{
-					"port":     strconv.Itoa(port),
-					"protocol": "tcp",
-					"service":  svc,
-				},
+	var wg sync.WaitGroup
+	for range min(concurrency, len(ports)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for port := range queue {
+				if opts.Gate.Wait(ctx) != nil || limiter.Acquire(ctx) != nil {
+					return
+				}
+
+				start := time.Now()
+				conn, err := dial(dialer, ctx, network, net.JoinHostPort(ip, strconv.Itoa(port)))
+				latency := time.Since(start)
+				failed := err != nil && ctx.Err() == nil && overloaded(err)
+				limiter.Release(latency, failed)
+				opts.ReportProgress(s.Name(), int(atomic.AddInt64(&completed, 1)), len(ports))
+
+				if ctx.Err() != nil {
+					if conn != nil {
+						conn.Close()
+					}
+					return
+				}
+				if failed {
+					// No port has answered yet and too many have gone silent:
+					// the host is down or drops everything.
+					n := atomic.AddInt64(&unanswered, 1)
+					if hostDownAfter > 0 && n >= int64(hostDownAfter) && atomic.LoadInt64(&answered) == 0 && down.CompareAndSwap(false, true) {
+						cancel()
+					}
+					if tarpit.Observe() {
+						cancel()
+					}
+					continue
+				}
+				atomic.AddInt64(&answered, 1)
+				var signs []string
+				if err == nil {
+					signs = append(signs, scanner.TarpitAnswered)
+				}
+				if latency >= timeout/2 {
+					signs = append(signs, scanner.TarpitSlow)
+				}
+				if tarpit.Observe(signs...) {
+					cancel()
+				}
+				if err != nil {
+					continue
+				}
+				conn.Close()
+
+				opts.ReportFinding(s.Name(), openPort(port, family))
+				mu.Lock()
+				open = append(open, port)
+				mu.Unlock()
 			}
-
-			mu.Lock()
-			result.Findings = append(result.Findings, finding)
-			mu.Unlock()
-		}(p)
+		}()
 	}
-
 	wg.Wait()
+
+	sort.Ints(open)
+	for _, port := range open {
+		result.Findings = append(result.Findings, openPort(port, family))
+	}
+	if down.Load() {
+		probes := int(atomic.LoadInt64(&completed))
+		result.Findings = append(result.Findings, hostDown(ip, fmt.Sprintf("None of the first %d ports probed on %s answered, so the scan stopped early. The host is down or a firewall drops every probe.", probes, ip), probes))
+	}
+	if f, ok := tarpit.Finding(s.Name()); ok {
+		opts.Logf(s.Name(), scanner.LogWarn, "stopped early: %s appears to be a tarpit", ip)
+		result.Findings = append(result.Findings, f)
+	}
+	result.Metadata = limiter.Metadata()
 	result.CompletedAt = time.Now()
 	return result, nil
 }
 
+// openPort returns the finding for an open port.
+func openPort(port int, family string) types.Finding {
+	svc := IdentifyService(port)
+	return types.Finding{
+		Title:       fmt.Sprintf("Open port: %d/%s", port, svc),
+		Description: fmt.Sprintf("TCP port %d is open (%s)", port, svc),
+		Severity:    types.SeverityInfo,
+		Metadata: map[string]string{
+			"port":           strconv.Itoa(port),
+			"protocol":       "tcp",
+			"service":        svc,
+			"address_family": family,
+		},
+	}
+}
+
+// hostDown returns the finding for a host that did not answer.
+func hostDown(ip, description string, probes int) types.Finding {
+	return types.Finding{
+		Title:       "Host appears to be down",
+		Description: description,
+		Severity:    types.SeverityInfo,
+		Metadata: map[string]string{
+			"address": ip,
+			"probes":  strconv.Itoa(probes),
+		},
+	}
+}
+
+// resolveAddress returns the address to scan for host: host itself if it is
+// an IP address, or its first address in the scan's address family.
+func resolveAddress(ctx context.Context, host string, opts scanner.Options) (string, error) {
+	if net.ParseIP(host) != nil {
+		return host, nil
+	}
+	addrs, err := opts.Resolver.LookupHost(ctx, host)
+	if err != nil {
+		return "", err
+	}
+	family := opts.Family()
+	for _, addr := range addrs {
+		if family == "" || scanner.IPFamily(net.ParseIP(addr)) == family {
+			return addr, nil
+		}
+	}
+	return "", fmt.Errorf("no %s address", family)
+}
+
+// overloaded reports whether a failed dial suggests the target or the
+// network is struggling: anything but a refused connection, which is simply
+// a closed port.
+func overloaded(err error) bool {
+	return !errors.Is(err, syscall.ECONNREFUSED)
+}
+
 func resolvePorts(target types.Target, opts scanner.Options) ([]int, error) {
 	// Check ExtraArgs for port specification.
-	if opts.ExtraArgs != nil {
-		if spec, ok := opts.ExtraArgs["ports"].(string); ok && spec != "" {
-			return ParsePortRange(spec)
-		}
+	if spec := opts.StringArg("ports"); spec != "" {
+		return ParsePortRange(spec)
 	}
 
 	// Use ports from target if specified.
@@ -3,7 +3,11 @@ package port
 import (
 	"context"
 	"net"
+	"os"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -85,6 +89,141 @@ func TestScanner_DetectsOpenPort(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, result.Findings, 1)
 	assert.Equal(t, strconv.Itoa(port), result.Findings[0].Metadata["port"])
+	assert.Equal(t, "ipv4", result.Findings[0].Metadata["address_family"])
+	assert.Equal(t, "0", result.Metadata["backoffs"])
+	assert.Contains(t, result.Metadata, "effective_rate")
+}
+
+func TestScanner_IPv6(t *testing.T) {
+	listener, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Skip("IPv6 loopback not available")
+	}
+	defer listener.Close()
+
+	_, portStr, _ := net.SplitHostPort(listener.Addr().String())
+	target, err := types.ParseTarget("[::1]")
+	require.NoError(t, err)
+	opts := scanner.Options{
+		Timeout:   2 * time.Second,
+		ExtraArgs: map[string]interface{}{"ports": portStr},
+	}
+
+	result, err := New().Run(context.Background(), target, opts)
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 1)
+	assert.Equal(t, "ipv6", result.Findings[0].Metadata["address_family"])
+
+	// Restricted to IPv4, the IPv6 literal cannot be dialed.
+	opts.IPVersion = 4
+	result, err = New().Run(context.Background(), target, opts)
+	require.NoError(t, err)
+	assert.Empty(t, result.Findings)
+}
+
+func TestScanner_StreamsOpenPortsInOrder(t *testing.T) {
+	var ports []string
+	for range 3 {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer listener.Close()
+		_, port, _ := net.SplitHostPort(listener.Addr().String())
+		ports = append(ports, port)
+	}
+
+	var mu sync.Mutex
+	var streamed []string
+	opts := scanner.Options{
+		Concurrency: 3,
+		Timeout:     2 * time.Second,
+		ExtraArgs:   map[string]interface{}{"ports": strings.Join(ports, ",")},
+		Found: func(name string, f types.Finding) {
+			mu.Lock()
+			defer mu.Unlock()
+			streamed = append(streamed, f.Metadata["port"])
+		},
+	}
+
+	result, err := New().Run(context.Background(), types.Target{Host: "127.0.0.1"}, opts)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, ports, streamed, "each open port is reported as it is found")
+
+	var reported []int
+	for _, f := range result.Findings {
+		port, _ := strconv.Atoi(f.Metadata["port"])
+		reported = append(reported, port)
+	}
+	assert.Len(t, reported, 3)
+	assert.True(t, sort.IntsAreSorted(reported), "the result lists open ports in order")
+}
+
+func TestScanner_StopsWhenHostIsDown(t *testing.T) {
+	orig := dial
+	defer func() { dial = orig }()
+	dial = func(*net.Dialer, context.Context, string, string) (net.Conn, error) {
+		return nil, os.ErrDeadlineExceeded
+	}
+
+	opts := scanner.Options{
+		Concurrency: 5,
+		Timeout:     100 * time.Millisecond,
+		ExtraArgs:   map[string]interface{}{"ports": "1-1000", "host_down_after": 10},
+	}
+
+	result, err := New().Run(context.Background(), types.Target{Host: "192.0.2.1"}, opts)
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 1)
+	assert.Equal(t, "Host appears to be down", result.Findings[0].Title)
+	probes, _ := strconv.Atoi(result.Findings[0].Metadata["probes"])
+	assert.Less(t, probes, 1000, "the scan stopped early")
+}
+
+func TestScanner_StopsAtTarpit(t *testing.T) {
+	orig := dial
+	defer func() { dial = orig }()
+	dial = func(*net.Dialer, context.Context, string, string) (net.Conn, error) {
+		client, server := net.Pipe()
+		server.Close()
+		return client, nil
+	}
+
+	opts := scanner.Options{
+		Concurrency: 5,
+		Timeout:     100 * time.Millisecond,
+		ExtraArgs:   map[string]interface{}{"ports": "1-1000", "tarpit_after": 20},
+	}
+
+	result, err := New().Run(context.Background(), types.Target{Host: "192.0.2.1"}, opts)
+	require.NoError(t, err)
+	last := result.Findings[len(result.Findings)-1]
+	assert.Equal(t, "Target appears to be a tarpit", last.Title)
+	assert.Equal(t, scanner.TarpitAnswered, last.Metadata["tarpit_sign"])
+	assert.Less(t, len(result.Findings), 100, "the scan stopped early")
+}
+
+func TestScanner_PingSkipsDownHost(t *testing.T) {
+	orig := pingHost
+	defer func() { pingHost = orig }()
+	var pinged string
+	pingHost = func(_ context.Context, ip string, _ time.Duration, _ scanner.Options) bool {
+		pinged = ip
+		return false
+	}
+
+	opts := scanner.Options{
+		Timeout:   time.Second,
+		ExtraArgs: map[string]interface{}{"ports": "1-65535", "ping": true},
+	}
+	result, err := New().Run(context.Background(), types.Target{Host: "127.0.0.1"}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", pinged)
+	require.Len(t, result.Findings, 1)
+	assert.Equal(t, "Host appears to be down", result.Findings[0].Title)
+}
+
+func TestTCPPing_RefusedCountsAsUp(t *testing.T) {
+	// Loopback answers ports 80 and 443, if only to refuse the connection.
+	assert.True(t, tcpPing(context.Background(), "127.0.0.1", time.Second, scanner.Options{}))
 }
 
 func TestScanner_ClosedPort(t *testing.T) {
@@ -0,0 +1,201 @@
+package scanner
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// lookupHost resolves the target during pre-flight. Extracted as a variable
+// for testing.
+var lookupHost = net.DefaultResolver.LookupHost
+
+// maxPreflightRedirects bounds the redirects followed by the pre-flight probe.
+const maxPreflightRedirects = 10
+
+// PreflightResult is what the pre-flight probe learned about a target before
+// any scanner ran.
+type PreflightResult struct {
+	Addresses    []string // resolved IP addresses
+	ResolveError string   // why the host did not resolve, if it did not
+
+	HTTPS bool // the target answered HTTPS requests
+	HTTP  bool // the target answered plain HTTP requests
+
+	// FinalURL is where the request to the preferred scheme (HTTPS if it
+	// answered, else HTTP) ended up after Redirects redirects.
+	FinalURL  string
+	Redirects int
+}
+
+// Reachable reports whether the target answered over HTTP or HTTPS.
+func (p PreflightResult) Reachable() bool {
+	return p.HTTP || p.HTTPS
+}
+
+// Scheme returns the preferred scheme, "https" or "http", or "" if the target
+// answered neither.
+func (p PreflightResult) Scheme() string {
+	switch {
+	case p.HTTPS:
+		return "https"
+	case p.HTTP:
+		return "http"
+	}
+	return ""
+}
+
+// Metadata returns the result as ScanResult metadata.
+func (p PreflightResult) Metadata() map[string]string {
+	md := map[string]string{
+		"preflight_https":     strconv.FormatBool(p.HTTPS),
+		"preflight_http":      strconv.FormatBool(p.HTTP),
+		"preflight_reachable": strconv.FormatBool(p.Reachable()),
+	}
+	if len(p.Addresses) > 0 {
+		md["preflight_addresses"] = strings.Join(p.Addresses, ",")
+	}
+	if p.ResolveError != "" {
+		md["preflight_resolve_error"] = p.ResolveError
+	}
+	if scheme := p.Scheme(); scheme != "" {
+		md["preflight_scheme"] = scheme
+	}
+	if p.FinalURL != "" {
+		md["preflight_final_url"] = p.FinalURL
+		md["preflight_redirects"] = strconv.Itoa(p.Redirects)
+	}
+	return md
+}
+
+// Applicable is implemented by scanners that only apply to some targets,
+// such as the ssl scanner to targets that speak TLS. The Runner skips them,
+// with an explanatory INFO finding, when the pre-flight probe shows they
+// cannot apply.
+type Applicable interface {
+	// Applies reports whether the scanner applies to a target with the given
+	// pre-flight result, and if not, why.
+	Applies(p PreflightResult) (bool, string)
+}
+
+// Preflight probes a target once before its first scanner runs: it resolves
+// the host, and requests the target over HTTPS and plain HTTP, following
+// redirects. Set Options.Preflight to a Preflight to have the Runner use
+// it; it may be shared by every scanner of one scan.
+type Preflight struct {
+	once   sync.Once
+	result PreflightResult
+}
+
+// NewPreflight returns a Preflight that has not probed yet.
+func NewPreflight() *Preflight {
+	return &Preflight{}
+}
+
+// Probe runs the probe on first use and returns its result; later calls
+// return the same result.
+func (p *Preflight) Probe(ctx context.Context, target types.Target, opts Options) PreflightResult {
+	p.once.Do(func() {
+		p.result = probeTarget(ctx, target, opts)
+	})
+	return p.result
+}
+
+func probeTarget(ctx context.Context, target types.Target, opts Options) PreflightResult {
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	lookup := lookupHost
+	if opts.Resolver != nil {
+		lookup = opts.Resolver.LookupHost
+	}
+
+	var result PreflightResult
+	if net.ParseIP(target.Host) != nil {
+		result.Addresses = []string{target.Host}
+	} else if addrs, err := lookup(ctx, target.Host); err != nil {
+		result.ResolveError = err.Error()
+	} else {
+		for _, addr := range addrs {
+			if family := opts.Family(); family == "" || IPFamily(net.ParseIP(addr)) == family {
+				result.Addresses = append(result.Addresses, addr)
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	var secure, plain probeOutcome
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		secure = probeScheme(ctx, target, "https", opts)
+	}()
+	go func() {
+		defer wg.Done()
+		plain = probeScheme(ctx, target, "http", opts)
+	}()
+	wg.Wait()
+
+	result.HTTPS, result.HTTP = secure.ok, plain.ok
+	if len(target.Ports) > 0 && secure.ok {
+		// A TLS port answers plain requests with an error page (nginx's
+		// "plain HTTP request was sent to HTTPS port"); it does not serve
+		// plain HTTP.
+		result.HTTP = false
+	}
+	switch {
+	case secure.ok:
+		result.FinalURL, result.Redirects = secure.finalURL, secure.redirects
+	case plain.ok:
+		result.FinalURL, result.Redirects = plain.finalURL, plain.redirects
+	}
+	return result
+}
+
+type probeOutcome struct {
+	ok        bool
+	finalURL  string
+	redirects int
+}
+
+// probeScheme requests the target's root over scheme, on the target's port
+// if it has one, and follows redirects.
+func probeScheme(ctx context.Context, target types.Target, scheme string, opts Options) probeOutcome {
+	host := target.URLHost()
+	if len(target.Ports) > 0 {
+		host = net.JoinHostPort(target.Host, strconv.Itoa(target.Ports[0]))
+	}
+
+	redirects := 0
+	client := &http.Client{
+		Transport: opts.HTTPTransport(),
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			redirects = len(via)
+			if len(via) >= maxPreflightRedirects {
+				return http.ErrUseLastResponse
+			}
+			return nil
+		},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, scheme+"://"+host+"/", nil)
+	if err != nil {
+		return probeOutcome{}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return probeOutcome{}
+	}
+	resp.Body.Close()
+	return probeOutcome{ok: true, finalURL: resp.Request.URL.String(), redirects: redirects}
+}
@@ -0,0 +1,154 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreflight_PlainHTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			http.Redirect(w, r, "/home", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	target, err := types.ParseTarget(srv.URL)
+	require.NoError(t, err)
+
+	p := NewPreflight().Probe(context.Background(), target, Options{Timeout: 2 * time.Second})
+	assert.True(t, p.HTTP)
+	assert.False(t, p.HTTPS)
+	assert.Equal(t, "http", p.Scheme())
+	assert.Equal(t, srv.URL+"/home", p.FinalURL)
+	assert.Equal(t, 1, p.Redirects)
+	assert.Equal(t, []string{"127.0.0.1"}, p.Addresses)
+
+	md := p.Metadata()
+	assert.Equal(t, "http", md["preflight_scheme"])
+	assert.Equal(t, "false", md["preflight_https"])
+	assert.Equal(t, "1", md["preflight_redirects"])
+	assert.Equal(t, "127.0.0.1", md["preflight_addresses"])
+}
+
+func TestPreflight_HTTPS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	target, err := types.ParseTarget(srv.URL)
+	require.NoError(t, err)
+
+	p := NewPreflight().Probe(context.Background(), target, Options{Timeout: 2 * time.Second, Transport: srv.Client().Transport})
+	assert.True(t, p.HTTPS)
+	assert.False(t, p.HTTP)
+	assert.Equal(t, "https", p.Scheme())
+	assert.True(t, p.Reachable())
+}
+
+func TestPreflight_Unreachable(t *testing.T) {
+	orig := lookupHost
+	defer func() { lookupHost = orig }()
+	lookupHost = func(context.Context, string) ([]string, error) {
+		return nil, assert.AnError
+	}
+
+	target := types.Target{Host: "unresolvable.invalid", Ports: []int{1}}
+	p := NewPreflight().Probe(context.Background(), target, Options{Timeout: time.Second})
+	assert.False(t, p.Reachable())
+	assert.Empty(t, p.Addresses)
+	assert.Equal(t, assert.AnError.Error(), p.ResolveError)
+
+	md := p.Metadata()
+	assert.Equal(t, "false", md["preflight_reachable"])
+	assert.NotContains(t, md, "preflight_scheme")
+}
+
+func TestPreflight_ProbesOnce(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer srv.Close()
+
+	target, err := types.ParseTarget(srv.URL)
+	require.NoError(t, err)
+
+	p := NewPreflight()
+	first := p.Probe(context.Background(), target, Options{Timeout: 2 * time.Second})
+	second := p.Probe(context.Background(), target, Options{Timeout: 2 * time.Second})
+	assert.Equal(t, first, second)
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+// tlsOnlyScanner only applies to targets that speak HTTPS.
+type tlsOnlyScanner struct {
+	mockScanner
+	ran bool
+}
+
+func (s *tlsOnlyScanner) Applies(p PreflightResult) (bool, string) {
+	return p.HTTPS, "no HTTPS"
+}
+
+func (s *tlsOnlyScanner) Run(ctx context.Context, target types.Target, opts Options) (*types.ScanResult, error) {
+	s.ran = true
+	return s.mockScanner.Run(ctx, target, opts)
+}
+
+func TestRunner_PreflightSkipsInapplicable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	target, err := types.ParseTarget(srv.URL)
+	require.NoError(t, err)
+
+	tlsOnly := &tlsOnlyScanner{mockScanner: mockScanner{name: "tls"}}
+	reg := NewRegistry()
+	reg.Register(tlsOnly)
+	reg.Register(&mockScanner{name: "any"})
+
+	var completed []string
+	runner := NewRunner(reg).With(Hooks{
+		OnScannerComplete: func(result types.ScanResult) { completed = append(completed, result.ScannerName) },
+	})
+	opts := Options{Concurrency: 1, Timeout: 2 * time.Second, Preflight: NewPreflight()}
+
+	results := runner.RunAll(context.Background(), []string{"tls", "any"}, target, opts)
+	require.Len(t, results, 2)
+	assert.False(t, tlsOnly.ran)
+	assert.ElementsMatch(t, []string{"tls", "any"}, completed)
+
+	for _, r := range results {
+		assert.Equal(t, "http", r.Metadata["preflight_scheme"])
+		require.Len(t, r.Findings, 1)
+		if r.ScannerName == "tls" {
+			assert.Equal(t, "Scanner skipped", r.Findings[0].Title)
+			assert.Equal(t, types.SeverityInfo, r.Findings[0].Severity)
+			assert.Contains(t, r.Findings[0].Description, "no HTTPS")
+			assert.NotEmpty(t, r.Findings[0].Fingerprint)
+		} else {
+			assert.Equal(t, "mock finding", r.Findings[0].Title)
+		}
+	}
+}
+
+func TestRunner_NoPreflight(t *testing.T) {
+	tlsOnly := &tlsOnlyScanner{mockScanner: mockScanner{name: "tls"}}
+	reg := NewRegistry()
+	reg.Register(tlsOnly)
+
+	result, err := NewRunner(reg).RunOne(context.Background(), "tls", types.Target{Host: "localhost"}, DefaultOptions())
+	require.NoError(t, err)
+	assert.True(t, tlsOnly.ran)
+	assert.Nil(t, result.Metadata)
+}
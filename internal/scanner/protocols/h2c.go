@@ -0,0 +1,68 @@
+package protocols
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// userAgent is sent with the requests written by hand.
+const userAgent = "Mozilla/5.0 (compatible; hunter)"
+
+// h2cSettings is the HTTP2-Settings header of the upgrade request: the
+// client's SETTINGS, base64url-encoded, as curl sends them.
+const h2cSettings = "AAMAAABkAARAAAAAAAIAAAAA"
+
+// checkH2CUpgrade asks the target to switch the connection to cleartext
+// HTTP/2 (h2c). A server that agrees over TLS is behind a proxy that passed
+// the upgrade on: requests sent over the upgraded connection reach the
+// backend without the proxy seeing them, so its access rules do not apply.
+// One that agrees on a cleartext port lets the same be done through any
+// proxy in front of it that forwards upgrades.
+func checkH2CUpgrade(ctx context.Context, ep endpoint, opts scanner.Options) ([]types.Finding, error) {
+	conn, err := dial(ctx, ep, opts, "http/1.1")
+	if err != nil {
+		return nil, fmt.Errorf("h2c upgrade: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(opts.Timeout))
+
+	path := ep.url.RequestURI()
+	fmt.Fprintf(conn, "GET %s HTTP/1.1\r\nHost: %s\r\nUser-Agent: %s\r\nConnection: Upgrade, HTTP2-Settings\r\nUpgrade: h2c\r\nHTTP2-Settings: %s\r\n\r\n",
+		path, ep.url.Host, userAgent, h2cSettings)
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		return nil, fmt.Errorf("h2c upgrade: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols || !strings.EqualFold(resp.Header.Get("Upgrade"), "h2c") {
+		return nil, nil
+	}
+
+	severity := types.SeverityMedium
+	description := "The server switched a cleartext connection to HTTP/2 (h2c) on request. Any reverse proxy in front of it that forwards Upgrade headers hands it a connection whose later requests it no longer inspects, so they can reach paths its access rules block (h2c smuggling)."
+	if ep.tls {
+		severity = types.SeverityHigh
+		description = "A TLS connection was switched to cleartext HTTP/2 (h2c) on request, so whatever terminates TLS passed the upgrade on to a backend. Requests sent over the upgraded connection reach that backend without the proxy inspecting them, bypassing its access rules and routing (h2c smuggling)."
+	}
+	return []types.Finding{{
+		Title:       "h2c upgrade accepted",
+		Description: description,
+		Severity:    severity,
+		Evidence:    fmt.Sprintf("GET %s with Upgrade: h2c → %s, Upgrade: %s", path, resp.Status, resp.Header.Get("Upgrade")),
+		Remediation: "Do not forward Upgrade: h2c from proxies (only pass Upgrade for WebSocket), and disable h2c on backends that do not need it.",
+		References: []types.Reference{
+			{Title: "Bishop Fox: h2c Smuggling", URL: "https://bishopfox.com/blog/h2c-smuggling-request"},
+		},
+		Metadata: map[string]string{
+			"check": "h2c-upgrade",
+			"url":   ep.url.String(),
+		},
+	}}, nil
+}
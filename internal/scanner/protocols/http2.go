@@ -0,0 +1,295 @@
+package protocols
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+)
+
+// errNoHTTP2 is returned by the HTTP/2 checks when the target does not
+// negotiate HTTP/2, which they only try over TLS.
+var errNoHTTP2 = errors.New("no HTTP/2")
+
+// dialHTTP2 connects to ep offering HTTP/2 through ALPN, and returns the
+// connection if the server chose it.
+func dialHTTP2(ctx context.Context, ep endpoint, opts scanner.Options) (*tls.Conn, error) {
+	if !ep.tls {
+		return nil, errNoHTTP2
+	}
+	conn, err := dial(ctx, ep, opts, http2.NextProtoTLS, "http/1.1")
+	if err != nil {
+		return nil, err
+	}
+	tc := conn.(*tls.Conn)
+	if tc.ConnectionState().NegotiatedProtocol != http2.NextProtoTLS {
+		conn.Close()
+		return nil, errNoHTTP2
+	}
+	return tc, nil
+}
+
+// checkCoalescing sends requests for the other hosts the target's
+// certificate covers over one connection to the target, as browsers do
+// when those hosts resolve to the same address (connection coalescing).
+// A server that does not serve one of them should answer 421 Misdirected
+// Request so the browser connects to it separately; one that serves it
+// the page it serves for any unknown host breaks that site for visitors
+// whose browser coalesced.
+func checkCoalescing(ctx context.Context, ep endpoint, opts scanner.Options) ([]types.Finding, error) {
+	conn, err := dialHTTP2(ctx, ep, opts)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	if certs := conn.ConnectionState().PeerCertificates; len(certs) > 0 {
+		names = otherNames(certs[0].DNSNames, ep.host)
+	}
+	names = scanner.Limit(names, opts.Budget("protocols.sans"))
+	if len(names) == 0 {
+		conn.Close()
+		return nil, nil
+	}
+	cc, err := (&http2.Transport{}).NewClientConn(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("coalescing: %w", err)
+	}
+	defer cc.Close()
+
+	get := func(host string) (scanner.Page, error) {
+		ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, ep.url.String(), nil)
+		if err != nil {
+			return scanner.Page{}, err
+		}
+		req.Host = host
+		req.Header.Set("User-Agent", userAgent)
+		resp, err := cc.RoundTrip(req)
+		if err != nil {
+			return scanner.Page{}, err
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		return scanner.NewPage(resp.StatusCode, string(body), host), nil
+	}
+
+	own, err := get(ep.url.Host)
+	if err != nil {
+		return nil, fmt.Errorf("coalescing: %w", err)
+	}
+	var unknown [2]scanner.Page
+	for i := range unknown {
+		if unknown[i], err = get(authority(unknownHost(), ep)); err != nil {
+			return nil, fmt.Errorf("coalescing: %w", err)
+		}
+	}
+	if unknown[0].Status == http.StatusMisdirectedRequest {
+		return nil, nil
+	}
+	fallback := scanner.NewBaseline(unknown[0], unknown[1])
+	if fallback.Matches(own) {
+		opts.Logf("protocols", scanner.LogInfo, "%s serves the same page for every host; skipping the coalescing check", ep.addr)
+		return nil, nil
+	}
+
+	var misrouted []string
+	for _, name := range names {
+		if err := opts.Gate.Wait(ctx); err != nil {
+			break
+		}
+		page, err := get(authority(name, ep))
+		if err != nil {
+			opts.Logf("protocols", scanner.LogInfo, "coalescing %s: %v", name, err)
+			continue
+		}
+		if page.Status != http.StatusMisdirectedRequest && fallback.Matches(page) {
+			misrouted = append(misrouted, name)
+		}
+	}
+	if len(misrouted) == 0 {
+		return nil, nil
+	}
+	return []types.Finding{{
+		Title:       "HTTP/2 coalescing misroutes requests",
+		Description: fmt.Sprintf("The certificate of %s also covers %s, but requests for them sent over a connection to it get the page served for unknown hosts rather than 421 Misdirected Request. Browsers that reuse the connection for those hosts, as HTTP/2 lets them when they resolve to the same address, show visitors the wrong site.", ep.host, strings.Join(misrouted, ", ")),
+		Severity:    types.SeverityLow,
+		Evidence:    fmt.Sprintf("GET %s with :authority %s → %d, as for an unknown host", ep.url.RequestURI(), misrouted[0], unknown[0].Status),
+		Remediation: "Answer requests for hosts this server does not serve with 421 Misdirected Request, or leave those hosts out of its certificate.",
+		References: []types.Reference{
+			{Title: "RFC 9113: Connection Reuse", URL: "https://www.rfc-editor.org/rfc/rfc9113#section-9.1.1"},
+		},
+		Metadata: map[string]string{
+			"check": "coalescing",
+			"url":   ep.url.String(),
+			"hosts": strings.Join(misrouted, ","),
+		},
+	}}, nil
+}
+
+// otherNames returns the names of a certificate other than host, without
+// wildcards, which name no host to send.
+func otherNames(names []string, host string) []string {
+	var other []string
+	seen := map[string]bool{strings.ToLower(host): true}
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "*.") || seen[name] {
+			continue
+		}
+		seen[name] = true
+		other = append(other, name)
+	}
+	return other
+}
+
+// authority returns host with ep's port, unless it is the default one.
+func authority(host string, ep endpoint) string {
+	if port := ep.url.Port(); port != "" {
+		return host + ":" + port
+	}
+	return host
+}
+
+// unknownHost returns a host name no server serves.
+func unknownHost() string {
+	b := make([]byte, 6)
+	rand.Read(b)
+	return "hunter-" + hex.EncodeToString(b) + ".invalid"
+}
+
+// oversizedHeaders is how many bytes of padding headers the oversized
+// header check sends, in padHeaders headers: far more than any browser
+// sends, and more than common servers allow.
+const (
+	oversizedHeaders = 128 << 10
+	padHeaders       = 16
+)
+
+// checkOversizedHeaders sends a request whose header list is
+// oversizedHeaders bytes, in HEADERS and CONTINUATION frames. Servers
+// should refuse it, and advertise the limit they enforce in
+// SETTINGS_MAX_HEADER_LIST_SIZE; one that buffers it all can be made to
+// hold large amounts of memory per stream.
+func checkOversizedHeaders(ctx context.Context, ep endpoint, opts scanner.Options) ([]types.Finding, error) {
+	if opts.Budget("protocols.oversized_headers") == 0 {
+		return nil, nil
+	}
+	conn, err := dialHTTP2(ctx, ep, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(opts.Timeout))
+
+	if _, err := io.WriteString(conn, http2.ClientPreface); err != nil {
+		return nil, fmt.Errorf("oversized headers: %w", err)
+	}
+	framer := http2.NewFramer(conn, conn)
+	framer.ReadMetaHeaders = hpack.NewDecoder(4096, nil)
+	if err := framer.WriteSettings(); err != nil {
+		return nil, fmt.Errorf("oversized headers: %w", err)
+	}
+
+	var block bytes.Buffer
+	enc := hpack.NewEncoder(&block)
+	fields := []hpack.HeaderField{
+		{Name: ":method", Value: http.MethodGet},
+		{Name: ":scheme", Value: "https"},
+		{Name: ":authority", Value: ep.url.Host},
+		{Name: ":path", Value: ep.url.RequestURI()},
+		{Name: "user-agent", Value: userAgent},
+	}
+	pad := strings.Repeat("a", oversizedHeaders/padHeaders)
+	for i := range padHeaders {
+		fields = append(fields, hpack.HeaderField{Name: "x-hunter-pad-" + strconv.Itoa(i), Value: pad, Sensitive: true})
+	}
+	for _, f := range fields {
+		enc.WriteField(f)
+	}
+	if err := writeHeaderBlock(framer, 1, block.Bytes()); err != nil {
+		return nil, fmt.Errorf("oversized headers: %w", err)
+	}
+
+	limit := "none"
+	for {
+		frame, err := framer.ReadFrame()
+		if err != nil {
+			// A server that closes the connection refused the request.
+			return nil, nil
+		}
+		switch f := frame.(type) {
+		case *http2.SettingsFrame:
+			if v, ok := f.Value(http2.SettingMaxHeaderListSize); ok {
+				limit = strconv.FormatUint(uint64(v), 10)
+			}
+			if !f.IsAck() {
+				framer.WriteSettingsAck()
+			}
+		case *http2.PingFrame:
+			if !f.IsAck() {
+				framer.WritePing(true, f.Data)
+			}
+		case *http2.GoAwayFrame, *http2.RSTStreamFrame:
+			return nil, nil
+		case *http2.MetaHeadersFrame:
+			if f.StreamID != 1 {
+				continue
+			}
+			status, _ := strconv.Atoi(f.PseudoValue("status"))
+			if status == http.StatusRequestHeaderFieldsTooLarge || status >= 500 || status < 200 {
+				return nil, nil
+			}
+			return []types.Finding{{
+				Title:       "Oversized HTTP/2 headers accepted",
+				Description: fmt.Sprintf("The server answered a request carrying %d KiB of headers, split over HEADERS and CONTINUATION frames, as it would any other. A server that buffers header lists of any size can be made to hold large amounts of memory with few connections.", oversizedHeaders>>10),
+				Severity:    types.SeverityLow,
+				Evidence:    fmt.Sprintf("GET %s with %d headers of %d bytes → %d; SETTINGS_MAX_HEADER_LIST_SIZE: %s", ep.url.RequestURI(), padHeaders, len(pad), status, limit),
+				Remediation: "Limit the size of request header lists, e.g. with large_client_header_buffers in nginx or LimitRequestFieldSize in Apache, and advertise the limit in SETTINGS_MAX_HEADER_LIST_SIZE.",
+				References: []types.Reference{
+					{Title: "RFC 9113: SETTINGS_MAX_HEADER_LIST_SIZE", URL: "https://www.rfc-editor.org/rfc/rfc9113#section-6.5.2"},
+				},
+				Metadata: map[string]string{
+					"check":                "oversized-headers",
+					"url":                  ep.url.String(),
+					"header_bytes":         strconv.Itoa(oversizedHeaders),
+					"max_header_list_size": limit,
+				},
+			}}, nil
+		}
+	}
+}
+
+// writeHeaderBlock writes block as the headers of a GET on stream, in a
+// HEADERS frame followed by as many CONTINUATION frames as it takes.
+func writeHeaderBlock(framer *http2.Framer, stream uint32, block []byte) error {
+	const maxFrame = 16 << 10
+	first := block[:min(len(block), maxFrame)]
+	block = block[len(first):]
+	err := framer.WriteHeaders(http2.HeadersFrameParam{
+		StreamID:      stream,
+		BlockFragment: first,
+		EndStream:     true,
+		EndHeaders:    len(block) == 0,
+	})
+	for err == nil && len(block) > 0 {
+		chunk := block[:min(len(block), maxFrame)]
+		block = block[len(chunk):]
+		err = framer.WriteContinuation(stream, len(block) == 0, chunk)
+	}
+	return err
+}
@@ -0,0 +1,138 @@
+// Package protocols checks how the target speaks HTTP/2: whether it accepts
+// h2c upgrades that let requests be smuggled past a proxy, whether it
+// accepts oversized header lists, and whether the hosts its certificate
+// covers, which browsers send over one connection, reach the right site.
+package protocols
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// Scanner checks the target for HTTP/2 misconfigurations.
+type Scanner struct{}
+
+// New creates a new protocols scanner.
+func New() *Scanner {
+	return &Scanner{}
+}
+
+func (s *Scanner) Name() string        { return "protocols" }
+func (s *Scanner) Description() string { return "HTTP/2 misconfiguration checks" }
+
+func (s *Scanner) Run(ctx context.Context, target types.Target, opts scanner.Options) (*types.ScanResult, error) {
+	result := &types.ScanResult{
+		ScannerName: s.Name(),
+		Target:      target,
+		StartedAt:   time.Now(),
+	}
+
+	ep, err := endpointOf(target)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 5 * time.Second
+	}
+
+	steps := []func(context.Context, endpoint, scanner.Options) ([]types.Finding, error){
+		checkH2CUpgrade,
+		checkCoalescing,
+		checkOversizedHeaders,
+	}
+	for i, step := range steps {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := opts.Gate.Wait(ctx); err != nil {
+			break
+		}
+		findings, err := step(ctx, ep, opts)
+		opts.ReportProgress(s.Name(), i+1, len(steps))
+		if errors.Is(err, errNoHTTP2) {
+			opts.Logf(s.Name(), scanner.LogInfo, "%s does not negotiate HTTP/2 over TLS; skipping the HTTP/2 checks", ep.addr)
+			break
+		}
+		if err != nil {
+			opts.Logf(s.Name(), scanner.LogWarn, "%v", err)
+			continue
+		}
+		result.Findings = append(result.Findings, findings...)
+	}
+
+	result.CompletedAt = time.Now()
+	return result, nil
+}
+
+// endpoint is where the target is reached.
+type endpoint struct {
+	// url is the target URL, or its root.
+	url *url.URL
+	// host is the host name, without the port; addr is host and port.
+	host, addr string
+	tls        bool
+}
+
+// endpointOf returns the endpoint of target.
+func endpointOf(target types.Target) (endpoint, error) {
+	raw := target.URL
+	if raw == "" {
+		if target.Host == "" {
+			return endpoint{}, fmt.Errorf("cannot determine URL for target %q", target.Host)
+		}
+		scheme := target.Scheme
+		if scheme == "" {
+			scheme = "https"
+		}
+		raw = scheme + "://" + target.URLHost() + "/"
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return endpoint{}, fmt.Errorf("invalid target URL %q", raw)
+	}
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	return endpoint{
+		url:  u,
+		host: u.Hostname(),
+		addr: net.JoinHostPort(u.Hostname(), port),
+		tls:  u.Scheme == "https",
+	}, nil
+}
+
+// dial connects to ep, with TLS offering protocols through ALPN if ep uses
+// it.
+func dial(ctx context.Context, ep endpoint, opts scanner.Options, protocols ...string) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	raw, err := opts.Resolver.DialContext(ctx, opts.Network(), ep.addr)
+	if err != nil {
+		return nil, err
+	}
+	if !ep.tls {
+		return raw, nil
+	}
+	conn := tls.Client(raw, &tls.Config{ServerName: ep.host, InsecureSkipVerify: true, NextProtos: protocols})
+	if err := conn.HandshakeContext(ctx); err != nil {
+		raw.Close()
+		return nil, err
+	}
+	return conn, nil
+}
@@ -0,0 +1,118 @@
+package protocols
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func TestScanner_NameAndDescription(t *testing.T) {
+	s := New()
+	assert.Equal(t, "protocols", s.Name())
+	assert.Equal(t, "HTTP/2 misconfiguration checks", s.Description())
+}
+
+// newHTTP2Server starts a TLS server speaking HTTP/2 whose certificate
+// covers example.com, and returns its target.
+func newHTTP2Server(t *testing.T, handler http.Handler, maxHeaderBytes int) types.Target {
+	srv := httptest.NewUnstartedServer(handler)
+	srv.EnableHTTP2 = true
+	srv.Config.MaxHeaderBytes = maxHeaderBytes
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return types.Target{URL: srv.URL}
+}
+
+// findingsByCheck maps the findings of a run by their check.
+func findingsByCheck(t *testing.T, target types.Target, opts scanner.Options) map[string]types.Finding {
+	result, err := New().Run(context.Background(), target, opts)
+	require.NoError(t, err)
+	found := map[string]types.Finding{}
+	for _, f := range result.Findings {
+		found[f.Metadata["check"]] = f
+	}
+	return found
+}
+
+func TestScanner_H2CUpgrade(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { fmt.Fprint(w, "ok") })
+
+	upgrading := httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+	defer upgrading.Close()
+	found := findingsByCheck(t, types.Target{URL: upgrading.URL + "/admin"}, scanner.DefaultOptions())
+	require.Contains(t, found, "h2c-upgrade")
+	assert.Equal(t, types.SeverityMedium, found["h2c-upgrade"].Severity)
+	assert.Contains(t, found["h2c-upgrade"].Evidence, "GET /admin with Upgrade: h2c → 101")
+
+	plain := httptest.NewServer(handler)
+	defer plain.Close()
+	assert.Empty(t, findingsByCheck(t, types.Target{URL: plain.URL}, scanner.DefaultOptions()))
+}
+
+func TestScanner_Coalescing(t *testing.T) {
+	var misdirect atomic.Bool
+	target := newHTTP2Server(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.Host, "127.0.0.1:") {
+			fmt.Fprint(w, "<h1>Shop</h1><ul><li>Cart</li><li>Account</li></ul>")
+			return
+		}
+		if misdirect.Load() {
+			w.WriteHeader(http.StatusMisdirectedRequest)
+			return
+		}
+		fmt.Fprintf(w, "<h1>Welcome to nginx!</h1><p>%s</p>", r.Host)
+	}), 0)
+
+	found := findingsByCheck(t, target, scanner.DefaultOptions())
+	require.Contains(t, found, "coalescing")
+	assert.Equal(t, "example.com", found["coalescing"].Metadata["hosts"], "the certificate of httptest servers covers example.com")
+
+	misdirect.Store(true)
+	assert.NotContains(t, findingsByCheck(t, target, scanner.DefaultOptions()), "coalescing")
+}
+
+func TestScanner_CoalescingSameSiteEverywhere(t *testing.T) {
+	target := newHTTP2Server(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<h1>Shop</h1>")
+	}), 0)
+	assert.NotContains(t, findingsByCheck(t, target, scanner.DefaultOptions()), "coalescing")
+}
+
+func TestScanner_OversizedHeaders(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { fmt.Fprint(w, "ok") })
+
+	found := findingsByCheck(t, newHTTP2Server(t, handler, 1<<20), scanner.DefaultOptions())
+	require.Contains(t, found, "oversized-headers")
+	assert.Equal(t, "131072", found["oversized-headers"].Metadata["header_bytes"])
+	assert.NotEqual(t, "none", found["oversized-headers"].Metadata["max_header_list_size"], "Go servers advertise their limit")
+
+	assert.NotContains(t, findingsByCheck(t, newHTTP2Server(t, handler, 8<<10), scanner.DefaultOptions()), "oversized-headers")
+
+	opts := scanner.DefaultOptions()
+	opts.Intensity = scanner.IntensitySafe
+	assert.NotContains(t, findingsByCheck(t, newHTTP2Server(t, handler, 1<<20), opts), "oversized-headers", "not sent at safe intensity")
+}
+
+func TestScanner_NoHTTP2(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+	assert.Empty(t, findingsByCheck(t, types.Target{URL: srv.URL}, scanner.DefaultOptions()))
+}
+
+func TestOtherNames(t *testing.T) {
+	assert.Equal(t, []string{"www.example.com", "api.example.com"},
+		otherNames([]string{"Example.com", "www.example.com", "*.example.com", "api.example.com", "WWW.example.com"}, "example.com"))
+}
@@ -0,0 +1,297 @@
+package redirects
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// maxHops is how many redirects a chain may take before it is reported as
+// a loop, browsers giving up at about 20.
+const maxHops = 10
+
+// maxPageSize is the most of a page read when looking for a meta refresh.
+const maxPageSize = 1 << 20
+
+var (
+	metaTag     = regexp.MustCompile(`(?is)<meta\b[^>]*>`)
+	attribute   = regexp.MustCompile(`(?is)([a-z][a-z0-9_-]*)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+	refreshURL  = regexp.MustCompile(`(?i)^\s*\d*(?:\.\d*)?\s*[;,]\s*(?:url\s*=\s*)?['"]?([^'"]+)`)
+	redirectHdr = map[int]bool{
+		http.StatusMovedPermanently:  true,
+		http.StatusFound:             true,
+		http.StatusSeeOther:          true,
+		http.StatusTemporaryRedirect: true,
+		http.StatusPermanentRedirect: true,
+	}
+)
+
+// Scanner follows the redirect chains from a target's http:// and https://
+// roots.
+type Scanner struct{}
+
+// New creates a new redirect chain scanner.
+func New() *Scanner {
+	return &Scanner{}
+}
+
+func (s *Scanner) Name() string        { return "redirects" }
+func (s *Scanner) Description() string { return "HTTP redirect chain analysis" }
+
+// hop is one request of a redirect chain.
+type hop struct {
+	URL    string
+	Status int
+	// Meta reports that the hop was reached through the previous page's
+	// <meta http-equiv="refresh"> rather than a Location header.
+	Meta bool
+}
+
+// chain is the requests made following redirects from a root URL. Hops is
+// empty when the root could not be fetched.
+type chain struct {
+	Root string
+	Hops []hop
+	// Loop reports that the chain came back to a URL it had been at, or
+	// did not end within maxHops.
+	Loop bool
+}
+
+// final returns the URL the chain ended at.
+func (c chain) final() string {
+	if len(c.Hops) == 0 {
+		return ""
+	}
+	return c.Hops[len(c.Hops)-1].URL
+}
+
+func (s *Scanner) Run(ctx context.Context, target types.Target, opts scanner.Options) (*types.ScanResult, error) {
+	result := &types.ScanResult{
+		ScannerName: s.Name(),
+		Target:      target,
+		StartedAt:   time.Now(),
+	}
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	client := &http.Client{
+		Timeout:   timeout,
+		Transport: opts.HTTPTransport(),
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	plain := follow(ctx, client, rootURL(target, "http"))
+	secure := follow(ctx, client, rootURL(target, "https"))
+	if len(plain.Hops) == 0 && len(secure.Hops) == 0 {
+		return nil, fmt.Errorf("neither %s nor %s answered", plain.Root, secure.Root)
+	}
+	for _, c := range []chain{plain, secure} {
+		if len(c.Hops) > 0 {
+			opts.Logf(s.Name(), scanner.LogInfo, "%s: %d hops, ending at %s", c.Root, len(c.Hops)-1, c.final())
+		}
+	}
+
+	result.Findings = analyze(target, plain, secure)
+	result.CompletedAt = time.Now()
+	return result, nil
+}
+
+// rootURL returns the target's root over scheme, on the target's port if it
+// has one.
+func rootURL(target types.Target, scheme string) string {
+	host := target.URLHost()
+	if len(target.Ports) > 0 {
+		host = net.JoinHostPort(target.Host, strconv.Itoa(target.Ports[0]))
+	}
+	return scheme + "://" + host + "/"
+}
+
+// follow requests root and each URL it redirects to, by Location header or
+// meta refresh, until a page that does not redirect, a URL seen before, or
+// maxHops redirects.
+func follow(ctx context.Context, client *http.Client, root string) chain {
+	c := chain{Root: root}
+	seen := map[string]bool{}
+	next, meta := root, false
+	for {
+		if seen[next] || len(c.Hops) > maxHops {
+			c.Loop = true
+			return c
+		}
+		seen[next] = true
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, next, nil)
+		if err != nil {
+			return c
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return c
+		}
+		var body []byte
+		if strings.Contains(resp.Header.Get("Content-Type"), "html") {
+			body, _ = io.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+		}
+		resp.Body.Close()
+		c.Hops = append(c.Hops, hop{URL: next, Status: resp.StatusCode, Meta: meta})
+
+		var target string
+		if redirectHdr[resp.StatusCode] {
+			target, meta = resp.Header.Get("Location"), false
+		} else if resp.StatusCode == http.StatusOK {
+			target, meta = metaRefresh(string(body)), true
+		}
+		if target == "" {
+			return c
+		}
+		u, err := req.URL.Parse(target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return c
+		}
+		u.Fragment = ""
+		next = u.String()
+	}
+}
+
+// metaRefresh returns the URL a page's <meta http-equiv="refresh"> sends the
+// browser to, or "" when it has none.
+func metaRefresh(body string) string {
+	for _, tag := range metaTag.FindAllString(body, -1) {
+		attrs := map[string]string{}
+		for _, m := range attribute.FindAllStringSubmatch(tag, -1) {
+			attrs[strings.ToLower(m[1])] = html.UnescapeString(m[2] + m[3] + m[4])
+		}
+		if !strings.EqualFold(attrs["http-equiv"], "refresh") {
+			continue
+		}
+		if m := refreshURL.FindStringSubmatch(attrs["content"]); m != nil {
+			return strings.TrimSpace(m[1])
+		}
+	}
+	return ""
+}
+
+// analyze reports the problems with the chains from the target's http://
+// and https:// roots.
+func analyze(target types.Target, plain, secure chain) []types.Finding {
+	var findings []types.Finding
+
+	// Without HTTPS there is nothing to redirect to; that is for the ssl
+	// scanner to report.
+	if len(plain.Hops) > 0 && len(secure.Hops) > 0 && !plain.Loop && strings.HasPrefix(plain.final(), "http://") {
+		findings = append(findings, types.Finding{
+			Title:       "HTTP not redirected to HTTPS",
+			Description: fmt.Sprintf("%s is served over plain HTTP although the site supports HTTPS", plain.Root),
+			Severity:    types.SeverityMedium,
+			Evidence:    describe(plain),
+			Remediation: "Redirect every http:// request to its https:// URL with a 301, and set Strict-Transport-Security",
+			Metadata:    map[string]string{"url": plain.Root, "final_url": plain.final()},
+		})
+	}
+
+	for _, c := range []chain{plain, secure} {
+		if c.Loop {
+			findings = append(findings, types.Finding{
+				Title:       "Redirect loop",
+				Description: fmt.Sprintf("Following the redirects from %s never reaches a page", c.Root),
+				Severity:    types.SeverityLow,
+				Evidence:    describe(c),
+				Remediation: "Fix the redirect rules so every chain ends at a page",
+				Metadata:    map[string]string{"url": c.Root},
+			})
+		}
+		for i := 1; i < len(c.Hops); i++ {
+			from, to := c.Hops[i-1], c.Hops[i]
+			if strings.HasPrefix(from.URL, "https://") && strings.HasPrefix(to.URL, "http://") {
+				findings = append(findings, types.Finding{
+					Title:       "Redirect downgrades HTTPS to HTTP",
+					Description: fmt.Sprintf("%s redirects to %s, sending the browser's next request unencrypted", from.URL, to.URL),
+					Severity:    types.SeverityMedium,
+					Evidence:    describe(c),
+					Remediation: "Redirect only to https:// URLs",
+					Metadata:    map[string]string{"url": c.Root, "from": from.URL, "to": to.URL},
+				})
+			}
+			if to.Meta && foreign(to.URL, target.Host) {
+				findings = append(findings, types.Finding{
+					Title:       "Meta refresh to a foreign domain",
+					Description: fmt.Sprintf("%s sends visitors to %s with a <meta http-equiv=\"refresh\">", from.URL, to.URL),
+					Severity:    types.SeverityLow,
+					Evidence:    describe(c),
+					Remediation: "Confirm the redirect is intended; a page redirecting off-site it should not is a sign of tampering",
+					Metadata:    map[string]string{"url": c.Root, "from": from.URL, "to": to.URL},
+				})
+			}
+		}
+	}
+	return dedupe(findings)
+}
+
+// dedupe drops findings repeating an earlier one's title and from/to hop,
+// as when the http:// chain joins the https:// one.
+func dedupe(findings []types.Finding) []types.Finding {
+	seen := map[string]bool{}
+	var kept []types.Finding
+	for _, f := range findings {
+		key := f.Title + "\x00" + f.Metadata["from"] + "\x00" + f.Metadata["to"]
+		if f.Metadata["from"] == "" {
+			key += "\x00" + f.Metadata["url"]
+		}
+		if !seen[key] {
+			seen[key] = true
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+// describe writes a chain out hop by hop.
+func describe(c chain) string {
+	var b strings.Builder
+	for i, h := range c.Hops {
+		if i > 0 {
+			if h.Meta {
+				b.WriteString(" -> (meta refresh) ")
+			} else {
+				b.WriteString(" -> ")
+			}
+		}
+		fmt.Fprintf(&b, "%s [%d]", h.URL, h.Status)
+	}
+	if c.Loop {
+		b.WriteString(" -> ...")
+	}
+	return b.String()
+}
+
+// foreign reports whether rawURL is on a domain other than the target
+// host's: neither within it nor a parent of it, a leading "www." aside.
+func foreign(rawURL, targetHost string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	base := strings.TrimPrefix(strings.ToLower(targetHost), "www.")
+	return !inDomain(host, base) && !inDomain(base, host)
+}
+
+// inDomain reports whether name is domain or a name under it.
+func inDomain(name, domain string) bool {
+	return name == domain || strings.HasSuffix(name, "."+domain)
+}
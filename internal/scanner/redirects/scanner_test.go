@@ -0,0 +1,166 @@
+package redirects
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func noFollowClient() *http.Client {
+	return &http.Client{
+		Timeout: 2 * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func TestFollow_Chain(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/a", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/a", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/home", http.StatusFound)
+	})
+	mux.HandleFunc("/home", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "home")
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := follow(context.Background(), noFollowClient(), srv.URL+"/")
+	assert.False(t, c.Loop)
+	assert.Equal(t, []hop{
+		{URL: srv.URL + "/", Status: 301},
+		{URL: srv.URL + "/a", Status: 302},
+		{URL: srv.URL + "/home", Status: 200},
+	}, c.Hops)
+}
+
+func TestFollow_Loop(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			http.Redirect(w, r, "/login", http.StatusFound)
+			return
+		}
+		http.Redirect(w, r, "/", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	c := follow(context.Background(), noFollowClient(), srv.URL+"/")
+	assert.True(t, c.Loop)
+	assert.Len(t, c.Hops, 2)
+
+	// A chain that never repeats but never ends is a loop too.
+	endless := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(r.URL.Query().Get("n"))
+		http.Redirect(w, r, "/?n="+strconv.Itoa(n+1), http.StatusFound)
+	}))
+	defer endless.Close()
+	c = follow(context.Background(), noFollowClient(), endless.URL+"/")
+	assert.True(t, c.Loop)
+	assert.Len(t, c.Hops, maxHops+1)
+}
+
+func TestMetaRefresh(t *testing.T) {
+	assert.Equal(t, "https://other.example/", metaRefresh(`<meta http-equiv="refresh" content="0; url=https://other.example/">`))
+	assert.Equal(t, "/next", metaRefresh(`<META HTTP-EQUIV='Refresh' CONTENT='5;URL=/next'>`))
+	assert.Equal(t, "/quoted", metaRefresh(`<meta content="0; url='/quoted'" http-equiv="refresh">`))
+	assert.Equal(t, "", metaRefresh(`<meta http-equiv="refresh" content="30">`), "a plain reload goes nowhere")
+	assert.Equal(t, "", metaRefresh(`<meta name="description" content="0; url=/x">`))
+}
+
+func TestForeign(t *testing.T) {
+	assert.False(t, foreign("https://example.com/", "example.com"))
+	assert.False(t, foreign("https://app.example.com/", "www.example.com"))
+	assert.False(t, foreign("https://example.com/", "shop.example.com"))
+	assert.True(t, foreign("https://example.net/", "example.com"))
+	assert.True(t, foreign("https://notexample.com/", "example.com"))
+}
+
+func TestAnalyze_MissingHTTPSRedirect(t *testing.T) {
+	plain := chain{Root: "http://example.com/", Hops: []hop{{URL: "http://example.com/", Status: 200}}}
+	secure := chain{Root: "https://example.com/", Hops: []hop{{URL: "https://example.com/", Status: 200}}}
+	findings := analyze(types.Target{Host: "example.com"}, plain, secure)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "HTTP not redirected to HTTPS", findings[0].Title)
+	assert.Equal(t, types.SeverityMedium, findings[0].Severity)
+
+	// Redirected, or no HTTPS to redirect to: nothing to report.
+	plain.Hops = []hop{{URL: "http://example.com/", Status: 301}, {URL: "https://example.com/", Status: 200}}
+	assert.Empty(t, analyze(types.Target{Host: "example.com"}, plain, secure))
+	plain.Hops = []hop{{URL: "http://example.com/", Status: 200}}
+	assert.Empty(t, analyze(types.Target{Host: "example.com"}, plain, chain{Root: "https://example.com/"}))
+}
+
+func TestAnalyze_Downgrade(t *testing.T) {
+	secure := chain{Root: "https://example.com/", Hops: []hop{
+		{URL: "https://example.com/", Status: 302},
+		{URL: "http://example.com/login", Status: 200},
+	}}
+	// The http:// root joins the same chain; the hop is reported once.
+	plain := chain{Root: "http://example.com/", Hops: []hop{
+		{URL: "http://example.com/", Status: 301},
+		{URL: "https://example.com/", Status: 302},
+		{URL: "http://example.com/login", Status: 200},
+	}}
+	findings := analyze(types.Target{Host: "example.com"}, plain, secure)
+	var downgrades []types.Finding
+	for _, f := range findings {
+		if f.Title == "Redirect downgrades HTTPS to HTTP" {
+			downgrades = append(downgrades, f)
+		}
+	}
+	require.Len(t, downgrades, 1)
+	assert.Equal(t, "https://example.com/", downgrades[0].Metadata["from"])
+	assert.Equal(t, "http://example.com/login", downgrades[0].Metadata["to"])
+	assert.Contains(t, downgrades[0].Evidence, "https://example.com/ [302] -> http://example.com/login [200]")
+}
+
+func TestScanner_MetaRefreshAndLoop(t *testing.T) {
+	elsewhere := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "landing")
+	}))
+	defer elsewhere.Close()
+	// The same server under another name, so the refresh leaves the
+	// target's domain.
+	u, _ := url.Parse(elsewhere.URL)
+	foreignURL := "http://localhost:" + u.Port() + "/landing"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<html><head><meta http-equiv="refresh" content="0;url=%s"></head></html>`, foreignURL)
+	}))
+	defer srv.Close()
+
+	target, err := types.ParseTarget(srv.URL)
+	require.NoError(t, err)
+	result, err := New().Run(context.Background(), target, scanner.Options{Timeout: 2 * time.Second})
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 1, "the https:// root does not answer, so no missing redirect is reported")
+	f := result.Findings[0]
+	assert.Equal(t, "Meta refresh to a foreign domain", f.Title)
+	assert.Equal(t, foreignURL, f.Metadata["to"])
+	assert.Contains(t, f.Evidence, "(meta refresh)")
+}
+
+func TestScanner_Unreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	target, err := types.ParseTarget(srv.URL)
+	require.NoError(t, err)
+	srv.Close()
+
+	_, err = New().Run(context.Background(), target, scanner.Options{Timeout: time.Second})
+	assert.Error(t, err)
+}
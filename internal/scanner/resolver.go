@@ -0,0 +1,152 @@
+package scanner
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Resolver resolves target host names for every scanner of a scan: through
+// a chosen DNS server instead of the system's, and with hosts-file style
+// overrides that pin a host name to an address, e.g. to scan a
+// pre-production server behind the production host name. Requests keep the
+// host name, so TLS SNI and the Host header are unaffected.
+type Resolver struct {
+	// Nameserver is the DNS server to query as host:port, or "" for the
+	// system resolver.
+	Nameserver string
+	// Overrides maps lowercase host names to the address they resolve to.
+	Overrides map[string]string
+
+	dns *net.Resolver // querying Nameserver, built once by NewResolver
+}
+
+// NewResolver returns a Resolver querying nameserver, an IP address with an
+// optional port (53 by default), and applying overrides in host:ip form.
+// Both may be empty.
+func NewResolver(nameserver string, overrides []string) (*Resolver, error) {
+	r := &Resolver{Overrides: map[string]string{}}
+
+	if nameserver != "" {
+		if net.ParseIP(nameserver) != nil {
+			nameserver = net.JoinHostPort(nameserver, "53")
+		}
+		host, _, err := net.SplitHostPort(nameserver)
+		if err != nil || net.ParseIP(strings.Trim(host, "[]")) == nil {
+			return nil, fmt.Errorf("invalid resolver %q: expected an IP address with an optional port, e.g. 1.1.1.1 or 1.1.1.1:53", nameserver)
+		}
+		r.Nameserver = nameserver
+		r.dns = r.resolver()
+	}
+
+	for _, o := range overrides {
+		host, ip, ok := strings.Cut(o, ":")
+		host, ip = strings.ToLower(strings.TrimSpace(host)), strings.Trim(strings.TrimSpace(ip), "[]")
+		if !ok || host == "" || net.ParseIP(ip) == nil {
+			return nil, fmt.Errorf("invalid --resolve %q: expected host:ip, e.g. example.com:10.0.0.5", o)
+		}
+		r.Overrides[host] = ip
+	}
+	return r, nil
+}
+
+func (r *Resolver) resolver() *net.Resolver {
+	if r == nil || r.Nameserver == "" {
+		return net.DefaultResolver
+	}
+	if r.dns != nil {
+		return r.dns
+	}
+	nameserver := r.Nameserver
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, nameserver)
+		},
+	}
+}
+
+// LookupHost returns the addresses of host: its override if it has one,
+// else what the nameserver answers. A nil Resolver uses the system
+// resolver.
+func (r *Resolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if r != nil {
+		if ip, ok := r.Overrides[strings.ToLower(host)]; ok {
+			return []string{ip}, nil
+		}
+	}
+	return r.resolver().LookupHost(ctx, host)
+}
+
+// DialContext connects to addr on network like net.Dialer.DialContext, but
+// resolves its host with LookupHost. A nil Resolver dials directly. Addresses not in the network's family
+// (tcp4, tcp6) are skipped, and the others are tried in turn.
+func (r *Resolver) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	var d net.Dialer
+	if r == nil {
+		return d.DialContext(ctx, network, addr)
+	}
+	return dialVia(ctx, &d, network, addr, r.LookupHost)
+}
+
+// dialVia dials addr over network with d, resolving its host with lookup
+// and trying each address in the network's family in turn.
+func dialVia(ctx context.Context, d *net.Dialer, network, addr string, lookup func(ctx context.Context, host string) ([]string, error)) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || net.ParseIP(host) != nil {
+		return d.DialContext(ctx, network, addr)
+	}
+
+	addrs, err := lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	errs := []error{fmt.Errorf("no %s address for %s", network, host)}
+	for _, ip := range addrs {
+		family := IPFamily(net.ParseIP(ip))
+		if (network == "tcp4" && family != FamilyIPv4) || (network == "tcp6" && family != FamilyIPv6) {
+			continue
+		}
+		conn, err := d.DialContext(ctx, network, net.JoinHostPort(ip, port))
+		if err == nil {
+			return conn, nil
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) > 1 {
+		errs = errs[1:]
+	}
+	return nil, errors.Join(errs...)
+}
+
+// minIdleConnsPerHost is the fewest idle connections BaseTransport keeps
+// per host, however low Options.Concurrency is: several scanners run at
+// once against the same host.
+const minIdleConnsPerHost = 16
+
+// BaseTransport returns the transport to build Options.Transport on, shared
+// by every HTTP-based scanner of a scan. It keeps enough idle connections
+// per host for the scan's concurrency, resolves each host once (through
+// Resolver, if set), resumes TLS sessions instead of repeating full
+// handshakes, and connects over IPVersion.
+func BaseTransport(o Options) http.RoundTripper {
+	network := o.Network()
+	dns := newDNSCache(o.Resolver.LookupHost, dnsTTL)
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+
+	idle := max(o.Concurrency, minIdleConnsPerHost)
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConnsPerHost = idle
+	t.MaxIdleConns = max(t.MaxIdleConns, 4*idle)
+	t.TLSClientConfig = &tls.Config{ClientSessionCache: tls.NewLRUClientSessionCache(0)}
+	t.DialContext = func(ctx context.Context, _, addr string) (net.Conn, error) {
+		return dialVia(ctx, dialer, network, addr, dns.LookupHost)
+	}
+	return t
+}
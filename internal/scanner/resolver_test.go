@@ -0,0 +1,152 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewResolver(t *testing.T) {
+	for input, want := range map[string]string{
+		"1.1.1.1":         "1.1.1.1:53",
+		"1.1.1.1:5353":    "1.1.1.1:5353",
+		"2606:4700::1111": "[2606:4700::1111]:53",
+		"[::1]:5353":      "[::1]:5353",
+	} {
+		r, err := NewResolver(input, nil)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, r.Nameserver, input)
+	}
+
+	_, err := NewResolver("dns.example.com", nil)
+	assert.Error(t, err)
+
+	r, err := NewResolver("", []string{"Example.com:10.0.0.5", "v6.example.com:[2001:db8::1]"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"example.com": "10.0.0.5", "v6.example.com": "2001:db8::1"}, r.Overrides)
+
+	for _, bad := range []string{"example.com", "example.com:not-an-ip", ":10.0.0.5"} {
+		_, err := NewResolver("", []string{bad})
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestResolver_LookupHostOverride(t *testing.T) {
+	r, err := NewResolver("", []string{"app.example.com:10.0.0.5"})
+	require.NoError(t, err)
+
+	addrs, err := r.LookupHost(context.Background(), "APP.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.5"}, addrs)
+}
+
+func TestResolver_DialContext(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	r, err := NewResolver("", []string{"app.test:127.0.0.1"})
+	require.NoError(t, err)
+
+	conn, err := r.DialContext(context.Background(), "tcp", "app.test:"+port)
+	require.NoError(t, err)
+	conn.Close()
+
+	_, err = r.DialContext(context.Background(), "tcp6", "app.test:"+port)
+	assert.ErrorContains(t, err, "no tcp6 address for app.test")
+}
+
+func TestBaseTransport(t *testing.T) {
+	var host string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	r, err := NewResolver("", []string{"staging.example.com:127.0.0.1"})
+	require.NoError(t, err)
+	client := &http.Client{Transport: BaseTransport(Options{Resolver: r})}
+
+	resp, err := client.Get("http://staging.example.com:" + u.Port() + "/")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "staging.example.com:"+u.Port(), host, "the host name is kept in requests")
+
+	_, err = (&http.Client{Transport: BaseTransport(Options{IPVersion: 6})}).Get(srv.URL)
+	assert.Error(t, err, "an IPv4 server must not be reached over IPv6")
+}
+
+func TestBaseTransport_Pooling(t *testing.T) {
+	base := BaseTransport(Options{Concurrency: 50})
+	transport := base.(*http.Transport)
+	assert.Equal(t, 50, transport.MaxIdleConnsPerHost)
+	assert.NotNil(t, transport.TLSClientConfig.ClientSessionCache)
+	assert.Equal(t, minIdleConnsPerHost, BaseTransport(Options{}).(*http.Transport).MaxIdleConnsPerHost)
+
+	var mu sync.Mutex
+	conns := 0
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			mu.Lock()
+			conns++
+			mu.Unlock()
+		}
+	}
+	srv.StartTLS()
+	defer srv.Close()
+	transport.TLSClientConfig.RootCAs = srv.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+
+	// Each scanner builds its own client; they share the transport's
+	// connections.
+	for range 3 {
+		resp, err := (&http.Client{Transport: base}).Get(srv.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 1, conns)
+}
+
+func TestDNSCache(t *testing.T) {
+	var lookups atomic.Int32
+	fail := true
+	cache := newDNSCache(func(_ context.Context, host string) ([]string, error) {
+		lookups.Add(1)
+		if fail {
+			return nil, errors.New("no such host")
+		}
+		return []string{"127.0.0.1"}, nil
+	}, time.Minute)
+
+	_, err := cache.LookupHost(context.Background(), "app.test")
+	assert.Error(t, err)
+	fail = false
+	for range 3 {
+		addrs, err := cache.LookupHost(context.Background(), "APP.test")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"127.0.0.1"}, addrs)
+	}
+	assert.Equal(t, int32(2), lookups.Load(), "failures are retried, successes reused")
+
+	cache.ttl = 0
+	_, err = cache.LookupHost(context.Background(), "other.test")
+	require.NoError(t, err)
+	_, err = cache.LookupHost(context.Background(), "other.test")
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), lookups.Load(), "expired entries are looked up again")
+}
@@ -0,0 +1,75 @@
+package scanner
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// ResultCache keeps scanner results between scans, so a repeat scan of a
+// target can reuse the results of scanners whose settings did not change.
+// Set Options.Cache to one to have the Runner consult it before each
+// scanner. Implementations must be safe for concurrent use.
+type ResultCache interface {
+	// Get returns the result stored under key, unless it has expired. The
+	// caller may modify it.
+	Get(key string) (*types.ScanResult, bool)
+	// Put stores result under key. It must not keep references into it.
+	Put(key string, result types.ScanResult) error
+}
+
+// resultCacheKey identifies the result the named scanner makes of target with
+// opts: the target, the scanner's merged ExtraArgs, and every option that
+// changes what it sends or finds. Options the Runner applies afterwards,
+// such as severity overrides, redaction, and the engagement, are left out,
+// so changing them still reuses the result. It returns "" for scans whose
+// results must not be cached: authenticated ones, whose credentials it
+// cannot see, and those with arguments it cannot encode.
+func resultCacheKey(name string, target types.Target, opts Options) string {
+	if opts.Authenticate != nil {
+		return ""
+	}
+	key := struct {
+		Scanner    string                 `json:"scanner"`
+		Target     types.Target           `json:"target"`
+		Args       map[string]interface{} `json:"args,omitempty"`
+		Intensity  Intensity              `json:"intensity,omitempty"`
+		IPVersion  int                    `json:"ip_version,omitempty"`
+		Passive    bool                   `json:"passive,omitempty"`
+		Endpoints  []types.Endpoint       `json:"endpoints,omitempty"`
+		Crawl      []int                  `json:"crawl,omitempty"`
+		Nameserver string                 `json:"nameserver,omitempty"`
+		Resolve    map[string]string      `json:"resolve,omitempty"`
+	}{
+		Scanner:   name,
+		Target:    target,
+		Args:      opts.ForScanner(name).ExtraArgs,
+		Intensity: opts.Intensity,
+		IPVersion: opts.IPVersion,
+		Passive:   opts.Passive,
+		Endpoints: opts.Endpoints,
+	}
+	if opts.Crawler != nil {
+		key.Crawl = []int{opts.Crawler.opts.MaxDepth, opts.Crawler.opts.MaxPages}
+	}
+	if opts.Resolver != nil {
+		key.Nameserver = opts.Resolver.Nameserver
+		key.Resolve = opts.Resolver.Overrides
+	}
+	data, err := json.Marshal(key)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// reusable reports whether a scanner's outcome is worth caching: a
+// complete result, not an error, a cancelled scan, or a partial result.
+func reusable(ctx context.Context, result *types.ScanResult, err error) bool {
+	return err == nil && ctx.Err() == nil && result != nil &&
+		result.Error == "" && result.Metadata["partial"] != "true"
+}
@@ -0,0 +1,125 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mapCache is a ResultCache in memory.
+type mapCache struct {
+	mu      sync.Mutex
+	results map[string][]byte
+}
+
+func (c *mapCache) Get(key string) (*types.ScanResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, ok := c.results[key]
+	if !ok {
+		return nil, false
+	}
+	var r types.ScanResult
+	json.Unmarshal(data, &r)
+	return &r, true
+}
+
+func (c *mapCache) Put(key string, result types.ScanResult) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, err := json.Marshal(result)
+	c.results[key] = data
+	return err
+}
+
+// countingScanner counts its runs and fails while err is set.
+type countingScanner struct {
+	mu   sync.Mutex
+	runs int
+	err  error
+}
+
+func (s *countingScanner) Name() string        { return "counting" }
+func (s *countingScanner) Description() string { return "counts its runs" }
+func (s *countingScanner) Run(_ context.Context, target types.Target, _ Options) (*types.ScanResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runs++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &types.ScanResult{
+		ScannerName: s.Name(),
+		Target:      target,
+		Findings:    []types.Finding{{Title: "Counted", Severity: types.SeverityLow}},
+	}, nil
+}
+
+func TestRunner_ReusesCachedResults(t *testing.T) {
+	s := &countingScanner{}
+	reg := NewRegistry()
+	reg.Register(s)
+	runner := NewRunner(reg)
+	target := types.Target{Host: "example.com", Scheme: "https"}
+	cache := &mapCache{results: map[string][]byte{}}
+	opts := Options{Cache: cache, ScannerArgs: map[string]map[string]interface{}{"counting": {"depth": 1}}}
+
+	first, err := runner.RunOne(context.Background(), "counting", target, opts)
+	require.NoError(t, err)
+	assert.Empty(t, first.Metadata["cached"])
+
+	overrides, err := ParseSeverityOverrides(map[string]string{"Counted": "HIGH"})
+	require.NoError(t, err)
+	opts.Overrides = overrides
+	second, err := runner.RunOne(context.Background(), "counting", target, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 1, s.runs, "the cached result is reused")
+	assert.Equal(t, "true", second.Metadata["cached"])
+	assert.Equal(t, types.SeverityHigh, second.Findings[0].Severity, "overrides apply to cached results")
+
+	opts.ScannerArgs = map[string]map[string]interface{}{"counting": {"depth": 2}}
+	_, err = runner.RunOne(context.Background(), "counting", target, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 2, s.runs, "changed settings run the scanner again")
+
+	_, err = runner.RunOne(context.Background(), "counting", types.Target{Host: "other.example.com", Scheme: "https"}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 3, s.runs, "other targets run the scanner again")
+}
+
+func TestRunner_DoesNotCacheFailures(t *testing.T) {
+	s := &countingScanner{err: errors.New("connection refused")}
+	reg := NewRegistry()
+	reg.Register(s)
+	runner := NewRunner(reg)
+	target := types.Target{Host: "example.com", Scheme: "https"}
+	opts := Options{Cache: &mapCache{results: map[string][]byte{}}}
+
+	runner.RunOne(context.Background(), "counting", target, opts)
+	runner.RunOne(context.Background(), "counting", target, opts)
+	assert.Equal(t, 2, s.runs)
+}
+
+func TestResultCacheKey(t *testing.T) {
+	target := types.Target{Host: "example.com", Scheme: "https"}
+	base := resultCacheKey("headers", target, Options{})
+	assert.NotEmpty(t, base)
+
+	assert.Equal(t, base, resultCacheKey("headers", target, Options{Redact: true, Engagement: types.Engagement{Client: "ACME"}}),
+		"options applied after the scanner runs do not change the key")
+	assert.Equal(t, base, resultCacheKey("headers", target, Options{ScannerArgs: map[string]map[string]interface{}{"ssl": {"x": 1}}}),
+		"other scanners' settings do not change the key")
+	assert.NotEqual(t, base, resultCacheKey("ssl", target, Options{}))
+	assert.NotEqual(t, base, resultCacheKey("headers", target, Options{Intensity: IntensityAggressive}))
+	assert.NotEqual(t, base, resultCacheKey("headers", target, Options{Crawler: NewCrawler(2, 10)}))
+
+	assert.Empty(t, resultCacheKey("headers", target, Options{Authenticate: func(*http.Request) {}}),
+		"authenticated scans are not cached")
+}
@@ -2,14 +2,23 @@ package scanner
 
 import (
 	"context"
+	"fmt"
+	"net/url"
+	"slices"
 	"sync"
+	"time"
 
+	"github.com/buemura/hunter/internal/redact"
+	"github.com/buemura/hunter/internal/remediation"
+	"github.com/buemura/hunter/internal/rules"
 	"github.com/buemura/hunter/pkg/types"
 )
 
-// Runner orchestrates concurrent scanner execution.
+// Runner orchestrates concurrent scanner execution. Hooks registered with
+// Use or With follow every scanner it runs.
 type Runner struct {
 	registry *Registry
+	hooks    []Hooks
 }
 
 // NewRunner creates a runner backed by the given registry.
@@ -17,26 +26,66 @@ func NewRunner(registry *Registry) *Runner {
 	return &Runner{registry: registry}
 }
 
-// RunAll executes the named scanners concurrently, bounded by opts.Concurrency.
+// RunAll executes the named scanners concurrently, bounded by
+// opts.Concurrency, or one at a time with opts.Sequential. Scanners wait for
+// those they depend on in opts.DependsOn, and opts.OnFailure decides whether
+// the others still run once one fails. With opts.Watchdog, the results end
+// with the Watchdog's if the target stopped responding. Outside
+// opts.Window the scan waits for the window to open.
 func (r *Runner) RunAll(ctx context.Context, names []string, target types.Target, opts Options) []types.ScanResult {
 	concurrency := opts.Concurrency
-	if concurrency < 1 {
+	if concurrency < 1 || opts.Sequential {
 		concurrency = 1
 	}
 
+	if opts.MaxDuration > 0 && opts.TimeBudget == nil {
+		opts.TimeBudget = NewTimeBudget(opts.MaxDuration, names, opts.Durations, concurrency, opts.Gate)
+	}
+	if opts.Plan == nil {
+		opts.Plan = NewPlan(names, opts)
+	}
+	if (opts.Window != nil || opts.Watchdog.Interval > 0 && opts.Watchdog.Action == WatchdogPause) && opts.Gate == nil {
+		opts.Gate = NewGate()
+	}
+	release := r.holdWindow(ctx, opts)
+	defer release()
+	opts.Window = nil
+
+	watchdog := NewWatchdog(target, r.withHooks(opts))
+	watchdog.Start(ctx)
+	results := r.runAll(ctx, names, target, opts, concurrency)
+	if result := watchdog.Stop(); result != nil {
+		results = append(results, *result)
+	}
+	return results
+}
+
+// runAll runs the named scanners of RunAll, at most concurrency at once.
+func (r *Runner) runAll(ctx context.Context, names []string, target types.Target, opts Options, concurrency int) []types.ScanResult {
+	if opts.Sequential {
+		var results []types.ScanResult
+		for _, name := range Order(names, opts.DependsOn) {
+			result, err := r.RunOne(ctx, name, target, opts)
+			switch {
+			case err != nil:
+				results = append(results, types.ScanResult{ScannerName: name, Target: target, Error: err.Error()})
+			case result != nil:
+				results = append(results, *result)
+			}
+		}
+		return results
+	}
+
 	sem := make(chan struct{}, concurrency)
 	var mu sync.Mutex
 	var results []types.ScanResult
 	var wg sync.WaitGroup
 
 	for _, name := range names {
-		s, err := r.registry.Get(name)
+		s, err := r.get(name, opts)
 		if err != nil {
-			results = append(results, types.ScanResult{
-				ScannerName: name,
-				Target:      target,
-				Error:       err.Error(),
-			})
+			results = append(results, r.failed(name, target, err))
+			opts.Plan.Record(name, true)
 			continue
 		}
 
@@ -44,21 +93,25 @@ func (r *Runner) RunAll(ctx context.Context, names []string, target types.Target
 		go func(scanner Scanner) {
 			defer wg.Done()
 
-			select {
-			case sem <- struct{}{}:
-				defer func() { <-sem }()
-			case <-ctx.Done():
+			err := opts.Plan.wait(ctx, scanner.Name())
+			if err == nil {
+				select {
+				case sem <- struct{}{}:
+					defer func() { <-sem }()
+				case <-ctx.Done():
+					err = ctx.Err()
+				}
+			}
+			if err != nil {
+				failed := r.failed(scanner.Name(), target, err)
+				opts.Plan.Record(scanner.Name(), true)
 				mu.Lock()
-				results = append(results, types.ScanResult{
-					ScannerName: scanner.Name(),
-					Target:      target,
-					Error:       ctx.Err().Error(),
-				})
+				results = append(results, failed)
 				mu.Unlock()
 				return
 			}
 
-			result, err := scanner.Run(ctx, target, opts)
+			result, err := r.runPlanned(ctx, scanner, target, opts)
 			mu.Lock()
 			if err != nil {
 				results = append(results, types.ScanResult{
@@ -77,11 +130,234 @@ func (r *Runner) RunAll(ctx context.Context, names []string, target types.Target
 	return results
 }
 
-// RunOne executes a single scanner by name.
+// RunOne executes a single scanner by name, or skips it as opts.Plan
+// decides. Outside opts.Window it waits for the window to open.
 func (r *Runner) RunOne(ctx context.Context, name string, target types.Target, opts Options) (*types.ScanResult, error) {
-	s, err := r.registry.Get(name)
+	s, err := r.get(name, opts)
 	if err != nil {
+		r.failed(name, target, err)
+		opts.Plan.Record(name, true)
 		return nil, err
 	}
-	return s.Run(ctx, target, opts)
+	if opts.Window != nil && opts.Gate == nil {
+		opts.Gate = NewGate()
+	}
+	release := r.holdWindow(ctx, opts)
+	defer release()
+	if opts.MaxDuration > 0 && opts.TimeBudget == nil {
+		opts.TimeBudget = NewTimeBudget(opts.MaxDuration, []string{name}, opts.Durations, 1, opts.Gate)
+	}
+	return r.runPlanned(ctx, s, target, opts)
+}
+
+// holdWindow holds opts.Gate paused outside opts.Window, logging when the
+// scan waits for the window and resumes in it.
+func (r *Runner) holdWindow(ctx context.Context, opts Options) (release func()) {
+	logOpts := r.withHooks(opts)
+	return opts.Window.Hold(ctx, opts.Gate, func(open bool, next time.Time) {
+		if open {
+			logOpts.Logf("", LogInfo, "the scan window %s opened; resuming until it closes at %s", opts.Window, next.Format(time.RFC3339))
+			return
+		}
+		logOpts.Logf("", LogWarn, "outside the scan window %s; paused until it opens at %s", opts.Window, next.Format(time.RFC3339))
+	})
+}
+
+// runPlanned runs s unless opts.Plan skips it, and records its outcome
+// there.
+func (r *Runner) runPlanned(ctx context.Context, s Scanner, target types.Target, opts Options) (*types.ScanResult, error) {
+	if reason, skip := opts.Plan.Skip(s.Name()); skip {
+		err := fmt.Errorf("skipped: %s", reason)
+		r.failed(s.Name(), target, err)
+		opts.Plan.Record(s.Name(), true)
+		return nil, err
+	}
+	result, err := r.run(ctx, s, target, opts)
+	opts.Plan.Record(s.Name(), err != nil || result == nil || result.Error != "")
+	return result, err
+}
+
+// run executes s once opts.Gate lets it, applying severity overrides,
+// labelling and fingerprinting findings, and calling the hooks. With
+// opts.Preflight set, scanners that cannot apply to the target are skipped;
+// with opts.Passive, scanners that would contact it are. With opts.Crawler
+// set, the others get the requests it found in opts.Endpoints. With
+// opts.Redact, evidence is masked after fingerprinting, so fingerprints do
+// not depend on it. With opts.Cache set, a cached result of the scanner
+// stands in for running it, and the result of running it is cached before
+// any of that, so later scans apply their own overrides and redaction.
+func (r *Runner) run(ctx context.Context, s Scanner, target types.Target, opts Options) (*types.ScanResult, error) {
+	if err := opts.Gate.Wait(ctx); err != nil {
+		r.failed(s.Name(), target, err)
+		return nil, err
+	}
+	r.scannerStarted(s.Name())
+	if host := targetHost(target); opts.Authenticate != nil && host != "" {
+		opts.AuthHosts = append(slices.Clip(opts.AuthHosts), host)
+	}
+
+	var result *types.ScanResult
+	var err error
+	var preflight *PreflightResult
+	if opts.Preflight != nil && !opts.Passive {
+		p := opts.Preflight.Probe(ctx, target, opts)
+		preflight = &p
+	}
+	reason, skip := inapplicable(s, preflight)
+	if opts.Passive && !supportsPassive(s) {
+		reason, skip = passiveSkipReason, true
+	}
+	var key string
+	if opts.Cache != nil && !skip {
+		key = resultCacheKey(s.Name(), target, opts)
+	}
+	var cached bool
+	if key != "" {
+		result, cached = opts.Cache.Get(key)
+	}
+	switch {
+	case skip:
+		result = Skipped(s.Name(), target, reason)
+	case cached:
+		addMetadata(result, map[string]string{"cached": "true"})
+		r.withHooks(opts).Logf(s.Name(), LogInfo, "reused the result cached at %s", result.CompletedAt.Format(time.RFC3339))
+	default:
+		if opts.Crawler != nil && !opts.Passive {
+			crawled := opts.Crawler.Endpoints(ctx, target, preflight, opts)
+			opts.Endpoints = append(slices.Clip(opts.Endpoints), crawled...)
+		}
+		result, err = r.runSliced(ctx, s, target, opts)
+		if key != "" && reusable(ctx, result, err) {
+			if err := opts.Cache.Put(key, *result); err != nil {
+				r.withHooks(opts).Logf(s.Name(), LogWarn, "caching the result failed: %v", err)
+			}
+		}
+	}
+	rules.Label(result)
+	opts.Overrides.Apply(result)
+	labelFamily(result, target, opts)
+	fingerprint(result)
+	remediation.Annotate(result)
+	if opts.Redact {
+		redact.Result(result)
+	}
+	if result != nil && preflight != nil {
+		addMetadata(result, preflight.Metadata())
+	}
+	if result != nil {
+		addMetadata(result, opts.Engagement.Metadata())
+	}
+
+	switch {
+	case err != nil:
+		r.scannerCompleted(types.ScanResult{ScannerName: s.Name(), Target: target, Error: err.Error()})
+	case result != nil:
+		r.scannerCompleted(*result)
+	}
+	return result, err
+}
+
+// targetHost returns the host name of target, without its port.
+func targetHost(target types.Target) string {
+	if target.URL != "" {
+		if u, err := url.Parse(target.URL); err == nil && u.Hostname() != "" {
+			return u.Hostname()
+		}
+	}
+	return target.Host
+}
+
+// addMetadata records m in the metadata of result.
+func addMetadata(result *types.ScanResult, m map[string]string) {
+	if len(m) == 0 {
+		return
+	}
+	if result.Metadata == nil {
+		result.Metadata = map[string]string{}
+	}
+	for k, v := range m {
+		result.Metadata[k] = v
+	}
+}
+
+// runSliced runs s within its slice of opts.TimeBudget, if there is one.
+// A scanner stopped by its slice running out keeps what it found, in a
+// result marked partial; one that failed for it gets an empty one.
+func (r *Runner) runSliced(ctx context.Context, s Scanner, target types.Target, opts Options) (*types.ScanResult, error) {
+	opts = r.withHooks(opts.ForScanner(s.Name()))
+	if opts.TimeBudget == nil {
+		return s.Run(ctx, target, opts)
+	}
+
+	slice := opts.TimeBudget.Slice(s.Name())
+	sliceCtx, cancel := opts.Gate.WithTimeout(ctx, slice)
+	defer cancel()
+	started := time.Now()
+	result, err := s.Run(sliceCtx, target, opts)
+	if sliceCtx.Err() == nil || ctx.Err() != nil {
+		return result, err
+	}
+
+	if err != nil || result == nil {
+		result = &types.ScanResult{ScannerName: s.Name(), Target: target, StartedAt: started, CompletedAt: time.Now()}
+		err = nil
+	}
+	markPartial(result, slice)
+	opts.Logf(s.Name(), LogWarn, "stopped when its %s of the time budget ran out; results are partial", slice.Round(time.Millisecond))
+	return result, err
+}
+
+// inapplicable reports whether s cannot apply to a target with the given
+// pre-flight result, and why.
+func inapplicable(s Scanner, preflight *PreflightResult) (string, bool) {
+	a, ok := s.(Applicable)
+	if !ok || preflight == nil {
+		return "", false
+	}
+	applies, reason := a.Applies(*preflight)
+	return reason, !applies
+}
+
+// Skipped returns the result of a scanner that does not apply to the
+// target, for the given reason.
+func Skipped(name string, target types.Target, reason string) *types.ScanResult {
+	now := time.Now()
+	return &types.ScanResult{
+		ScannerName: name,
+		Target:      target,
+		StartedAt:   now,
+		CompletedAt: now,
+		Findings: []types.Finding{{
+			Title:       "Scanner skipped",
+			Description: fmt.Sprintf("The %s scanner does not apply to this target: %s.", name, reason),
+			Severity:    types.SeverityInfo,
+			Metadata:    map[string]string{"skipped_reason": reason},
+		}},
+	}
+}
+
+// fingerprint sets the Fingerprint of every finding in result.
+func fingerprint(result *types.ScanResult) {
+	if result == nil {
+		return
+	}
+	for i := range result.Findings {
+		result.Findings[i].Fingerprint = types.Fingerprint(result.ScannerName, result.Findings[i])
+	}
+}
+
+// failed reports a scanner that could not run to the hooks and returns its
+// result.
+func (r *Runner) failed(name string, target types.Target, err error) types.ScanResult {
+	result := types.ScanResult{ScannerName: name, Target: target, Error: err.Error()}
+	r.scannerCompleted(result)
+	return result
+}
+
+// get looks up a scanner, refusing those disabled in opts.
+func (r *Runner) get(name string, opts Options) (Scanner, error) {
+	if reason, disabled := opts.Disabled[name]; disabled {
+		return nil, fmt.Errorf("scanner %q is disabled: %s", name, reason)
+	}
+	return r.registry.Get(name)
 }
@@ -2,11 +2,13 @@ package scanner
 
 import (
 	"context"
+	"net/http"
 	"testing"
 	"time"
 
 	"github.com/buemura/hunter/pkg/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRunner_RunAll(t *testing.T) {
@@ -67,6 +69,102 @@ func TestRunner_RunOne(t *testing.T) {
 	assert.Equal(t, "test", result.ScannerName)
 }
 
+func TestRunner_FingerprintsFindings(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&mockScanner{name: "test"})
+
+	runner := NewRunner(reg)
+	result, err := runner.RunOne(context.Background(), "test", types.Target{Host: "localhost"}, DefaultOptions())
+	assert.NoError(t, err)
+	assert.Equal(t, types.Fingerprint("test", result.Findings[0]), result.Findings[0].Fingerprint)
+}
+
+// titledScanner reports one finding with the given title.
+type titledScanner struct {
+	name, title string
+}
+
+func (s *titledScanner) Name() string        { return s.name }
+func (s *titledScanner) Description() string { return "titled scanner" }
+func (s *titledScanner) Run(_ context.Context, target types.Target, _ Options) (*types.ScanResult, error) {
+	return &types.ScanResult{
+		ScannerName: s.name,
+		Target:      target,
+		Findings:    []types.Finding{{Title: s.title, Severity: types.SeverityMedium}},
+	}, nil
+}
+
+func TestRunner_AddsReferences(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&titledScanner{name: "headers", title: "Missing Strict-Transport-Security header"})
+
+	runner := NewRunner(reg)
+	result, err := runner.RunOne(context.Background(), "headers", types.Target{Host: "localhost"}, DefaultOptions())
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.Findings[0].References)
+}
+
+// leakyScanner reports a finding whose evidence and artifact hold a token.
+type leakyScanner struct{}
+
+func (leakyScanner) Name() string        { return "leaky" }
+func (leakyScanner) Description() string { return "leaky scanner" }
+func (leakyScanner) Run(_ context.Context, target types.Target, _ Options) (*types.ScanResult, error) {
+	return &types.ScanResult{
+		ScannerName: "leaky",
+		Target:      target,
+		Findings: []types.Finding{{
+			Title:    "Token in page",
+			Severity: types.SeverityHigh,
+			Evidence: "GET /?token=s3cr3t from 10.0.0.7",
+			Artifacts: []types.Artifact{{
+				Request:    "GET / HTTP/1.1\r\nAuthorization: Bearer s3cr3t\r\n\r\n",
+				Screenshot: []byte("png"),
+			}},
+		}},
+	}, nil
+}
+
+func TestRunner_Redact(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(leakyScanner{})
+	runner := NewRunner(reg)
+	target := types.Target{Host: "localhost"}
+
+	plain, err := runner.RunOne(context.Background(), "leaky", target, DefaultOptions())
+	require.NoError(t, err)
+
+	var stored types.ScanResult
+	opts := DefaultOptions()
+	opts.Redact = true
+	redacted, err := runner.With(Hooks{OnScannerComplete: func(r types.ScanResult) { stored = r }}).
+		RunOne(context.Background(), "leaky", target, opts)
+	require.NoError(t, err)
+
+	f := redacted.Findings[0]
+	assert.Equal(t, "GET /?token=[REDACTED] from [REDACTED]", f.Evidence)
+	assert.NotContains(t, f.Artifacts[0].Request, "s3cr3t")
+	assert.Nil(t, f.Artifacts[0].Screenshot)
+	assert.Equal(t, plain.Findings[0].Fingerprint, f.Fingerprint, "redaction must not change fingerprints")
+	assert.Equal(t, f.Evidence, stored.Findings[0].Evidence, "hooks must see the redacted result")
+}
+
+func TestRunner_RecordsEngagement(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&mockScanner{name: "test"})
+	runner := NewRunner(reg)
+
+	opts := DefaultOptions()
+	opts.Engagement = types.Engagement{Client: "ACME Corp", Tester: "jdoe"}
+	result, err := runner.RunOne(context.Background(), "test", types.Target{Host: "localhost"}, opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, "ACME Corp", result.Metadata["engagement_client"])
+	assert.Equal(t, "jdoe", result.Metadata["engagement_tester"])
+	assert.NotContains(t, result.Metadata, "engagement_id")
+	assert.Equal(t, opts.Engagement, types.EngagementOf([]types.ScanResult{*result}))
+}
+
 func TestRunner_RunOne_NotFound(t *testing.T) {
 	reg := NewRegistry()
 	runner := NewRunner(reg)
@@ -91,3 +189,48 @@ func (s *slowScanner) Run(ctx context.Context, target types.Target, _ Options) (
 		return &types.ScanResult{ScannerName: s.name, Target: target, Error: ctx.Err().Error()}, nil
 	}
 }
+
+func TestRunner_RefusesDisabledScanners(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&mockScanner{name: "test"})
+	reg.Register(&mockScanner{name: "other"})
+
+	opts := DefaultOptions()
+	opts.Disabled = map[string]string{"test": "excluded in environment \"prod\""}
+	target := types.Target{Host: "localhost", Scheme: "https"}
+
+	_, err := NewRunner(reg).RunOne(context.Background(), "test", target, opts)
+	assert.ErrorContains(t, err, "disabled")
+
+	results := NewRunner(reg).RunAll(context.Background(), []string{"test", "other"}, target, opts)
+	assert.Len(t, results, 2)
+	for _, r := range results {
+		if r.ScannerName == "test" {
+			assert.Contains(t, r.Error, "disabled")
+		} else {
+			assert.Empty(t, r.Error)
+		}
+	}
+}
+
+type authHostsScanner struct{ got []string }
+
+func (s *authHostsScanner) Name() string        { return "auth-hosts" }
+func (s *authHostsScanner) Description() string { return "records AuthHosts" }
+func (s *authHostsScanner) Run(_ context.Context, target types.Target, opts Options) (*types.ScanResult, error) {
+	s.got = opts.AuthHosts
+	return &types.ScanResult{ScannerName: s.Name(), Target: target}, nil
+}
+
+func TestRunner_AuthenticatesTargetHost(t *testing.T) {
+	s := &authHostsScanner{}
+	reg := NewRegistry()
+	reg.Register(s)
+
+	opts := DefaultOptions()
+	opts.Authenticate = func(*http.Request) {}
+	opts.AuthHosts = []string{"sso.example.com"}
+	_, err := NewRunner(reg).RunOne(context.Background(), "auth-hosts", types.Target{URL: "https://app.example.com:8443/", Host: "app.example.com"}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sso.example.com", "app.example.com"}, s.got)
+}
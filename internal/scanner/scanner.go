@@ -2,6 +2,10 @@ package scanner
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/buemura/hunter/pkg/types"
@@ -20,6 +24,261 @@ type Options struct {
 	Timeout     time.Duration
 	Verbose     bool
 	ExtraArgs   map[string]interface{}
+
+	// ScannerArgs holds per-scanner defaults keyed by scanner name, typically
+	// from the scanners section of the config file. The Runner merges the
+	// entry for each scanner under ExtraArgs, so ExtraArgs wins on conflicts.
+	// A name such as "api-ratelimit" may also be keyed as "ratelimit".
+	ScannerArgs map[string]map[string]interface{}
+
+	// Transport, when non-nil, is used by HTTP-based scanners for every
+	// request they send. A nil Transport falls back to http.DefaultTransport.
+	// Build it on BaseTransport so the scanners share pooled connections.
+	Transport http.RoundTripper
+
+	// Disabled maps scanner names to the reason they must not run, e.g. an
+	// environment policy. The Runner refuses to run them.
+	Disabled map[string]string
+
+	// Overrides remaps finding severities after each scanner runs.
+	Overrides SeverityOverrides
+
+	// Authenticate, when non-nil, adds credentials to outgoing requests of
+	// scanners that build their client with HTTPTransport.
+	Authenticate func(req *http.Request)
+
+	// AuthHosts are the host names whose requests Authenticate applies to,
+	// such as a login credential's login host. The Runner adds the target's
+	// host; requests to any other host are sent without credentials.
+	AuthHosts []string
+
+	// Authorize, when non-nil, returns an error for targets that may not be
+	// scanned. Scanners that reach hosts besides the target, such as the
+	// other hosts of its domain, skip those it refuses.
+	Authorize func(types.Target) error
+
+	// Progress, when non-nil, receives incremental progress from scanners
+	// that work through a known amount of units (ports, paths, requests).
+	Progress ProgressFunc
+
+	// Log, when non-nil, receives diagnostic lines from scanners, e.g. why
+	// a check was skipped. Scanners write them with Logf.
+	Log LogFunc
+
+	// Found, when non-nil, receives findings as soon as a scanner makes them,
+	// before it finishes, from scanners that stream them with ReportFinding.
+	Found FindingFunc
+
+	// Gate, when non-nil, lets the scan be paused and resumed. The Runner
+	// waits on it before each scanner, and scanners that work through many
+	// units before each unit.
+	Gate *Gate
+
+	// Preflight, when non-nil, probes the target once before its first
+	// scanner runs. The Runner records what it learned in the metadata of
+	// every result and skips scanners that cannot apply to the target.
+	Preflight *Preflight
+
+	// Crawler, when non-nil, spiders the target once before its first
+	// scanner runs. The Runner adds the requests it finds to Endpoints, so
+	// scanners test the whole application rather than the target URL alone.
+	Crawler *Crawler
+
+	// IPVersion restricts the scan to IPv4 (4) or IPv6 (6) addresses of the
+	// target; 0 allows either. Scanners that dial themselves use Network,
+	// and HTTP-based scanners a Transport built on BaseTransport.
+	IPVersion int
+
+	// Endpoints, when non-empty, is the API scope imported from a HAR file
+	// or Postman collection, and the requests Crawler found. The api-*
+	// scanners test the ones on the target's host instead of their built-in
+	// list of common paths, the vuln scanner injects into their parameters
+	// and body fields, and the headers scanner checks their pages.
+	Endpoints []types.Endpoint
+
+	// Sources are the passive data sources (certificate transparency logs,
+	// passive DNS) recon scanners query about the target's domain.
+	Sources []PassiveSource
+
+	// Passive forbids sending anything to the target. The Runner skips the
+	// pre-flight probe and every scanner that is not a PassiveScanner, and
+	// those that are work from Sources alone.
+	Passive bool
+
+	// MaxDuration, when positive, bounds the whole scan. The Runner gives
+	// each scanner a slice of it from TimeBudget and stops a scanner whose
+	// slice runs out, keeping what it found as a result marked partial.
+	MaxDuration time.Duration
+
+	// Durations are how long scanners usually take, keyed by name, e.g.
+	// from ObservedDurations over past scans. They weight the slices of
+	// MaxDuration.
+	Durations map[string]time.Duration
+
+	// TimeBudget, when non-nil, is the MaxDuration budget shared by the
+	// scanners of a scan. RunAll creates one; callers running scanners one
+	// by one with RunOne create it themselves so the scanners share it.
+	TimeBudget *TimeBudget
+
+	// Sequential makes RunAll run its scanners one at a time, in the order
+	// named, instead of concurrently.
+	Sequential bool
+
+	// OnFailure is what the scan does once a scanner fails; the zero value
+	// runs the others anyway. A scanner it skips fails with a reason.
+	OnFailure FailurePolicy
+
+	// DependsOn maps scanner names to the scanners they build on. RunAll
+	// starts a scanner only once those of the scan it depends on have
+	// finished, and with FailureSkipDependents skips it if one of them
+	// failed. See CheckDependencies.
+	DependsOn map[string][]string
+
+	// Plan, when non-nil, tracks the outcomes OnFailure and DependsOn act
+	// on across the scanners of a scan. RunAll creates one; callers running
+	// scanners one by one with RunOne create it with NewPlan.
+	Plan *Plan
+
+	// Watchdog, when its Interval is positive, has RunAll probe the target
+	// throughout the scan and abort or pause it once the target stops
+	// responding; see Watchdog. It is ignored with Passive.
+	Watchdog WatchdogSettings
+
+	// Window, when non-nil, is the daily period the scan may run in.
+	// RunAll and RunOne hold Gate paused, creating one if needed, whenever
+	// the time is outside it; callers running several scanners with RunOne
+	// hold it themselves with Window.Hold and clear Window.
+	Window *Window
+
+	// Intensity scales how many requests, paths, payloads, and login
+	// attempts scanners send; see Budget. The zero value is normal.
+	Intensity Intensity
+
+	// Resolver, when non-nil, resolves the target's host name for every
+	// scanner: scanners that dial themselves use its DialContext, and
+	// HTTP-based scanners a Transport built on BaseTransport.
+	Resolver *Resolver
+
+	// Redact masks credentials, tokens, cookies, and IP addresses in the
+	// evidence and artifacts of findings before the Runner returns them or
+	// calls its hooks, so reports can be shared outside the team.
+	Redact bool
+
+	// Engagement identifies the engagement the scan is run under. The
+	// Runner records it in the metadata of every result.
+	Engagement types.Engagement
+
+	// Cache, when non-nil, lets the Runner reuse a scanner's result from an
+	// earlier scan of the target with the same settings instead of running
+	// it, and store the results of those it runs.
+	Cache ResultCache
+}
+
+// HTTPTransport returns the transport HTTP-based scanners should use: the
+// configured Transport, authenticating requests to AuthHosts with
+// Authenticate when one is set. Scanners that deliberately probe
+// unauthenticated access use Transport.
+func (o Options) HTTPTransport() http.RoundTripper {
+	if o.Authenticate == nil {
+		return o.Transport
+	}
+	return &AuthTransport{Base: o.Transport, Authenticate: o.Authenticate, Hosts: o.AuthHosts}
+}
+
+// FindingFunc receives a finding as a scanner makes it. It may be called
+// concurrently.
+type FindingFunc func(scanner string, finding types.Finding)
+
+// ReportFinding forwards a finding to o.Found if one is set. The finding is
+// still returned in the scanner's result.
+func (o Options) ReportFinding(scanner string, finding types.Finding) {
+	if o.Found != nil {
+		o.Found(scanner, finding)
+	}
+}
+
+// ProgressFunc is called by a scanner after each unit of work: done of total
+// units have completed. It may be called concurrently.
+type ProgressFunc func(scanner string, done, total int)
+
+// ReportProgress forwards progress to o.Progress if one is set.
+func (o Options) ReportProgress(scanner string, done, total int) {
+	if o.Progress != nil {
+		o.Progress(scanner, done, total)
+	}
+}
+
+// ForScanner returns a copy of o whose ExtraArgs include the ScannerArgs
+// defaults for the named scanner.
+func (o Options) ForScanner(name string) Options {
+	defaults := o.ScannerArgs[name]
+	if short := strings.TrimPrefix(name, "api-"); defaults == nil && short != name {
+		defaults = o.ScannerArgs[short]
+	}
+	if len(defaults) == 0 {
+		return o
+	}
+
+	merged := make(map[string]interface{}, len(defaults)+len(o.ExtraArgs))
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	for k, v := range o.ExtraArgs {
+		merged[k] = v
+	}
+	o.ExtraArgs = merged
+	return o
+}
+
+// StringArg returns ExtraArgs[key] as a string. Numbers are formatted and
+// lists are joined with commas, so config values such as `ports: 443` or
+// `checks: [xss, sqli]` work as well as their string forms.
+func (o Options) StringArg(key string) string {
+	switch v := o.ExtraArgs[key].(type) {
+	case string:
+		return v
+	case int, int64, float64:
+		return fmt.Sprint(v)
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, ",")
+	case []string:
+		return strings.Join(v, ",")
+	}
+	return ""
+}
+
+// IntArg returns ExtraArgs[key] as an int, or 0 when it is missing or not a
+// number.
+func (o Options) IntArg(key string) int {
+	switch v := o.ExtraArgs[key].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	case string:
+		n, _ := strconv.Atoi(strings.TrimSpace(v))
+		return n
+	}
+	return 0
+}
+
+// BoolArg returns ExtraArgs[key] as a bool, accepting "true" and "false"
+// strings as well, or false when it is missing.
+func (o Options) BoolArg(key string) bool {
+	switch v := o.ExtraArgs[key].(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(strings.TrimSpace(v))
+		return b
+	}
+	return false
 }
 
 // DefaultOptions returns sensible defaults.
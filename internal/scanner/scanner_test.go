@@ -0,0 +1,99 @@
+package scanner
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions_ForScannerMergesDefaults(t *testing.T) {
+	opts := Options{
+		ExtraArgs: map[string]interface{}{"ports": "22"},
+		ScannerArgs: map[string]map[string]interface{}{
+			"port":      {"ports": "1-1024", "banner": true},
+			"ratelimit": {"requests": 20},
+		},
+	}
+
+	port := opts.ForScanner("port")
+	assert.Equal(t, "22", port.ExtraArgs["ports"], "ExtraArgs wins over config defaults")
+	assert.Equal(t, true, port.ExtraArgs["banner"])
+	assert.Len(t, opts.ExtraArgs, 1, "original options are not modified")
+
+	assert.Equal(t, 20, opts.ForScanner("api-ratelimit").ExtraArgs["requests"], "api- prefix may be omitted")
+	assert.Equal(t, opts.ExtraArgs, opts.ForScanner("ssl").ExtraArgs)
+}
+
+func TestOptions_StringArg(t *testing.T) {
+	opts := Options{ExtraArgs: map[string]interface{}{
+		"s":    "xss,sqli",
+		"n":    443,
+		"list": []interface{}{"xss", "sqli"},
+	}}
+	assert.Equal(t, "xss,sqli", opts.StringArg("s"))
+	assert.Equal(t, "443", opts.StringArg("n"))
+	assert.Equal(t, "xss,sqli", opts.StringArg("list"))
+	assert.Equal(t, "", opts.StringArg("missing"))
+}
+
+func TestOptions_IntArg(t *testing.T) {
+	opts := Options{ExtraArgs: map[string]interface{}{"i": 5, "f": 7.0, "s": " 9 ", "bad": "x"}}
+	assert.Equal(t, 5, opts.IntArg("i"))
+	assert.Equal(t, 7, opts.IntArg("f"))
+	assert.Equal(t, 9, opts.IntArg("s"))
+	assert.Equal(t, 0, opts.IntArg("bad"))
+	assert.Equal(t, 0, opts.IntArg("missing"))
+}
+
+type argsRecorder struct {
+	name string
+	mu   *sync.Mutex
+	seen map[string]map[string]interface{}
+}
+
+func (a *argsRecorder) Name() string        { return a.name }
+func (a *argsRecorder) Description() string { return "records ExtraArgs" }
+func (a *argsRecorder) Run(_ context.Context, target types.Target, opts Options) (*types.ScanResult, error) {
+	a.mu.Lock()
+	a.seen[a.name] = opts.ExtraArgs
+	a.mu.Unlock()
+	return &types.ScanResult{ScannerName: a.name, Target: target}, nil
+}
+
+func TestRunner_AppliesScannerArgs(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[string]map[string]interface{}{}
+	reg := NewRegistry()
+	reg.Register(&argsRecorder{name: "port", mu: &mu, seen: seen})
+	reg.Register(&argsRecorder{name: "dirs", mu: &mu, seen: seen})
+
+	opts := DefaultOptions()
+	opts.ScannerArgs = map[string]map[string]interface{}{
+		"port": {"ports": "80"},
+		"dirs": {"wordlist": "/tmp/words.txt"},
+	}
+
+	NewRunner(reg).RunAll(context.Background(), []string{"port", "dirs"}, types.Target{Host: "localhost"}, opts)
+	assert.Equal(t, "80", seen["port"]["ports"])
+	assert.Nil(t, seen["port"]["wordlist"])
+	assert.Equal(t, "/tmp/words.txt", seen["dirs"]["wordlist"])
+}
+
+func TestOptions_EndpointsOn(t *testing.T) {
+	opts := Options{Endpoints: []types.Endpoint{
+		{URL: "/users"},
+		{Method: "POST", URL: "https://API.example.com/login"},
+		{URL: "https://other.example.com/users"},
+	}}
+
+	endpoints := opts.EndpointsOn("https://api.example.com/v1/")
+	require.Len(t, endpoints, 2)
+	assert.Equal(t, types.Endpoint{Method: "GET", URL: "https://api.example.com/users"}, endpoints[0])
+	assert.Equal(t, "POST", endpoints[1].Method)
+
+	assert.Nil(t, Options{}.EndpointsOn("https://api.example.com"))
+}
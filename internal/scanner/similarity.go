@@ -0,0 +1,186 @@
+package scanner
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// dynamicContent matches the parts of a page that change from one request
+// to the next by themselves: UUIDs, timestamps, times of day, long numbers
+// such as Unix times, and hex tokens. Other tokens, such as CSRF tokens,
+// nonces, and session IDs, are matched by randomToken.
+var dynamicContent = regexp.MustCompile(`(?i)` + strings.Join([]string{
+	`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`,
+	`\d{4}-\d{2}-\d{2}[t ]\d{2}:\d{2}(:\d{2}(\.\d+)?)?(z|[+-]\d{2}:?\d{2})?`,
+	`(mon|tue|wed|thu|fri|sat|sun), \d{2} [a-z]{3} \d{4} \d{2}:\d{2}:\d{2} [a-z]+`,
+	`\b\d{1,2}:\d{2}:\d{2}\b`,
+	`\b\d{10,}\b`,
+	`\b[0-9a-f]{16,}\b`,
+}, "|"))
+
+// randomToken matches runs of characters long enough to be random tokens,
+// which they are taken to be if they mix letters and digits.
+var randomToken = regexp.MustCompile(`[A-Za-z0-9+_-]{20,}={0,2}`)
+
+// NormalizeBody returns body without its dynamic content, and without the
+// values a request sent that the page shows back, as sent and HTML- or
+// URL-encoded, so that pages can be compared across requests.
+func NormalizeBody(body string, reflected ...string) string {
+	for _, value := range reflected {
+		if value == "" {
+			continue
+		}
+		for _, v := range []string{value, html.EscapeString(value), url.QueryEscape(value)} {
+			body = strings.ReplaceAll(body, v, "")
+		}
+	}
+	body = dynamicContent.ReplaceAllString(body, "")
+	return randomToken.ReplaceAllStringFunc(body, func(t string) string {
+		if strings.ContainsAny(t, "0123456789") && strings.ContainsFunc(t, unicode.IsLetter) {
+			return ""
+		}
+		return t
+	})
+}
+
+// Page is a response reduced to what comparing it takes: its status, and
+// its body with NormalizeBody applied.
+type Page struct {
+	Status int
+	Body   string
+}
+
+// NewPage returns the Page of a response with status and body to a request
+// that sent the reflected values.
+func NewPage(status int, body string, reflected ...string) Page {
+	return Page{Status: status, Body: NormalizeBody(body, reflected...)}
+}
+
+// Same reports whether p and q have the same status and body.
+func (p Page) Same(q Page) bool {
+	return p.Status == q.Status && p.Body == q.Body
+}
+
+// pageToken splits a page into words and single symbols, such as the
+// brackets of HTML tags and the punctuation of JSON.
+var pageToken = regexp.MustCompile(`[\p{L}\p{N}_]+|[^\s\p{L}\p{N}_]`)
+
+// Similarity returns how alike two normalized bodies are, from 0 for
+// nothing in common to 1 for the same words and symbols the same number
+// of times. Their order is ignored, so it is cheap on pages of any size.
+func Similarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	counts := map[string]int{}
+	ta := pageToken.FindAllString(a, -1)
+	tb := pageToken.FindAllString(b, -1)
+	if len(ta)+len(tb) == 0 {
+		return 1
+	}
+	for _, t := range ta {
+		counts[t]++
+	}
+	shared := 0
+	for _, t := range tb {
+		if counts[t] > 0 {
+			counts[t]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(ta)+len(tb))
+}
+
+// Baseline is what a request usually gets back, learned from two responses
+// to it, and how much those varied, so that a response is only taken to
+// differ when it varies more than that.
+type Baseline struct {
+	page Page
+	// stableStatus is false if the two responses had different statuses,
+	// which are not compared then.
+	stableStatus bool
+	threshold    float64
+}
+
+// NewBaseline returns the Baseline of two responses to the same request.
+func NewBaseline(a, b Page) Baseline {
+	similarity := Similarity(a.Body, b.Body)
+	return Baseline{
+		page:         a,
+		stableStatus: a.Status == b.Status,
+		threshold:    max(0.5, min(similarity, 0.98)-0.05),
+	}
+}
+
+// Status returns the status of the first response.
+func (b Baseline) Status() int { return b.page.Status }
+
+// StableStatus reports whether both responses had the same status.
+func (b Baseline) StableStatus() bool { return b.stableStatus }
+
+// Matches reports whether p is the usual response.
+func (b Baseline) Matches(p Page) bool {
+	if b.stableStatus && p.Status != b.page.Status {
+		return false
+	}
+	return Similarity(b.page.Body, p.Body) >= b.threshold
+}
+
+// SoftNotFound is the page a site serves for paths it does not have when
+// that page is not a 404, as sites serving one page for every path, such
+// as single-page applications, do. Scanners that find paths by their
+// status use it to tell those apart. The methods of a nil SoftNotFound,
+// for sites answering 404, do nothing.
+type SoftNotFound struct {
+	baseline Baseline
+}
+
+// DetectSoftNotFound requests two paths under baseURL that no site has, and
+// returns the page served for them, or nil if they got a 404 or 410, or no
+// answer.
+func DetectSoftNotFound(ctx context.Context, client *http.Client, baseURL string) *SoftNotFound {
+	base := strings.TrimRight(baseURL, "/")
+	var pages [2]Page
+	for i := range pages {
+		name := randomPathName()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"/"+name, nil)
+		if err != nil {
+			return nil
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+			return nil
+		}
+		pages[i] = NewPage(resp.StatusCode, string(body), name)
+	}
+	return &SoftNotFound{baseline: NewBaseline(pages[0], pages[1])}
+}
+
+// Matches reports whether a response with status and body, to a request
+// for path, is the page served for paths the site does not have.
+func (s *SoftNotFound) Matches(status int, body, path string) bool {
+	if s == nil {
+		return false
+	}
+	return s.baseline.Matches(NewPage(status, body, strings.Trim(path, "/"), path))
+}
+
+// randomPathName returns a path segment no site has.
+func randomPathName() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
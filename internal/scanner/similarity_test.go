@@ -0,0 +1,69 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeBody(t *testing.T) {
+	a := `<p>Generated 2026-03-10T12:04:05Z by 3f2b8c1e-9d4a-4b6e-8f00-1c2d3e4f5a6b</p><input name="csrf" value="kT9xQ2mZ7pL4vB8nR1sW5yC3">`
+	b := `<p>Generated 2026-03-10T12:04:07Z by 0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d</p><input name="csrf" value="Zp3Lq8Rt1Vw6Xy2Ab5Cd9Ef0">`
+	assert.Equal(t, NormalizeBody(a), NormalizeBody(b))
+
+	assert.Equal(t, "<p>Results for </p>", NormalizeBody("<p>Results for &lt;b&gt;</p>", "<b>"))
+	assert.Contains(t, NormalizeBody("see /api/v1/users/profile-settings"), "/api/v1/users/profile-settings", "paths are not tokens")
+}
+
+func TestSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, Similarity("", ""))
+	assert.Equal(t, 1.0, Similarity("<h1>Hi</h1>", "<h1>Hi</h1>"))
+	assert.Equal(t, 0.0, Similarity("alpha beta", "gamma delta"))
+
+	page := "<html><body>" + strings.Repeat("<li>item</li>", 20) + "</body></html>"
+	near := Similarity(page, strings.Replace(page, "item", "other", 1))
+	assert.Greater(t, near, 0.9)
+	assert.Less(t, near, 1.0)
+}
+
+func TestBaseline(t *testing.T) {
+	page := func(status int, body string) Page { return NewPage(status, body) }
+	base := NewBaseline(page(200, "<p>Hello, guest</p><ul><li>a</li><li>b</li></ul>"), page(200, "<p>Hello, guest</p><ul><li>a</li><li>b</li></ul>"))
+
+	assert.True(t, base.Matches(page(200, "<p>Hello, guest</p><ul><li>a</li><li>b</li></ul>")))
+	assert.False(t, base.Matches(page(500, "<p>Hello, guest</p><ul><li>a</li><li>b</li></ul>")), "a different status differs")
+	assert.False(t, base.Matches(page(200, "<p>Debug mode</p><pre>stack trace</pre>")))
+
+	unstable := NewBaseline(page(200, "ok"), page(302, "ok"))
+	assert.False(t, unstable.StableStatus())
+	assert.True(t, unstable.Matches(page(500, "ok")), "statuses are not compared when they vary")
+}
+
+func TestDetectSoftNotFound(t *testing.T) {
+	spa := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin" {
+			fmt.Fprint(w, "<h1>Admin</h1><form><input name=user></form>")
+			return
+		}
+		fmt.Fprintf(w, `<div id="app" data-path="%s"></div><script src="/app.js"></script>`, r.URL.Path)
+	}))
+	defer spa.Close()
+
+	soft404 := DetectSoftNotFound(context.Background(), spa.Client(), spa.URL)
+	require.NotNil(t, soft404)
+	assert.True(t, soft404.Matches(200, `<div id="app" data-path="/backup"></div><script src="/app.js"></script>`, "/backup"))
+	assert.False(t, soft404.Matches(200, "<h1>Admin</h1><form><input name=user></form>", "/admin"))
+
+	plain := httptest.NewServer(http.NotFoundHandler())
+	defer plain.Close()
+	assert.Nil(t, DetectSoftNotFound(context.Background(), plain.Client(), plain.URL))
+
+	var none *SoftNotFound
+	assert.False(t, none.Matches(200, "", "/"), "a nil SoftNotFound matches nothing")
+}
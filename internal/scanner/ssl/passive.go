@@ -0,0 +1,155 @@
+package ssl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// SupportsPassive reports that the scanner can check the certificates
+// logged for the target instead of connecting to it.
+func (s *Scanner) SupportsPassive() bool { return true }
+
+// runPassive checks the certificates that certificate transparency logs,
+// queried through opts.Sources, hold for the target's host. The newest one
+// stands in for the certificate the server would present.
+func (s *Scanner) runPassive(ctx context.Context, target types.Target, opts scanner.Options) (*types.ScanResult, error) {
+	host := strings.ToLower(strings.TrimSuffix(target.Host, "."))
+	if net.ParseIP(host) != nil {
+		return scanner.Skipped(s.Name(), target, "certificates are not logged for IP addresses"), nil
+	}
+	if len(opts.Sources) == 0 {
+		return nil, fmt.Errorf("passive mode needs a passive data source")
+	}
+
+	result := &types.ScanResult{
+		ScannerName: s.Name(),
+		Target:      target,
+		StartedAt:   time.Now(),
+	}
+
+	// The parent domain's certificates include wildcards covering host.
+	domain := host
+	if _, parent, ok := strings.Cut(host, "."); ok && strings.Contains(parent, ".") {
+		domain = parent
+	}
+	data, errs := opts.PassiveLookup(ctx, domain)
+	for _, err := range errs {
+		opts.Logf(s.Name(), scanner.LogWarn, "passive source failed: %v", err)
+	}
+	if len(errs) == len(opts.Sources) {
+		result.Error = errors.Join(errs...).Error()
+		result.CompletedAt = time.Now()
+		return result, nil
+	}
+
+	var latest *scanner.PassiveCertificate
+	var issuers []string
+	count := 0
+	for i, cert := range data.Certificates {
+		if !cert.Covers(host) {
+			continue
+		}
+		count++
+		if !slices.Contains(issuers, cert.Issuer) {
+			issuers = append(issuers, cert.Issuer)
+		}
+		if latest == nil || cert.NotAfter.After(latest.NotAfter) {
+			latest = &data.Certificates[i]
+		}
+	}
+
+	if latest == nil {
+		result.Findings = append(result.Findings, types.Finding{
+			Title:       "No logged certificates",
+			Description: fmt.Sprintf("Certificate transparency logs hold no certificate for %s. Publicly trusted certificates are logged, so the host either does not serve TLS or uses a private CA.", host),
+			Severity:    types.SeverityInfo,
+			Metadata:    map[string]string{"hostname": host, "passive": "true"},
+		})
+	} else {
+		checkLoggedCertificate(*latest, host, result)
+		result.Findings = append(result.Findings, types.Finding{
+			Title:       fmt.Sprintf("Logged certificates: %d", count),
+			Description: fmt.Sprintf("Certificate transparency logs hold %d certificates for %s, issued by %s.", count, host, strings.Join(issuers, "; ")),
+			Severity:    types.SeverityInfo,
+			Evidence:    loggedEvidence(*latest),
+			Metadata: map[string]string{
+				"hostname":     host,
+				"certificates": strconv.Itoa(count),
+				"issuers":      strings.Join(issuers, "; "),
+				"passive":      "true",
+			},
+		})
+	}
+
+	result.CompletedAt = time.Now()
+	return result, nil
+}
+
+// checkLoggedCertificate reports on the newest certificate logged for host:
+// whether it has expired or expires soon, and whether it is a wildcard.
+func checkLoggedCertificate(cert scanner.PassiveCertificate, host string, result *types.ScanResult) {
+	metadata := func() map[string]string {
+		return map[string]string{
+			"hostname":      host,
+			"not_after":     cert.NotAfter.Format(time.RFC3339),
+			"issuer":        cert.Issuer,
+			"serial_number": cert.SerialNumber,
+			"source":        cert.Source,
+			"passive":       "true",
+		}
+	}
+
+	if time.Now().After(cert.NotAfter) {
+		result.Findings = append(result.Findings, types.Finding{
+			Title:       "Latest logged certificate expired",
+			Description: fmt.Sprintf("The newest certificate logged for %s expired on %s, and no later one has been issued. Unless the server uses a certificate from a private CA, it serves an expired one.", host, cert.NotAfter.Format(time.RFC3339)),
+			Severity:    types.SeverityMedium,
+			Evidence:    loggedEvidence(cert),
+			Remediation: "Renew the SSL/TLS certificate, or confirm the host no longer serves TLS.",
+			Metadata:    metadata(),
+		})
+	} else if days := int(time.Until(cert.NotAfter).Hours() / 24); days <= 30 {
+		m := metadata()
+		m["days_until_expiry"] = strconv.Itoa(days)
+		result.Findings = append(result.Findings, types.Finding{
+			Title:       fmt.Sprintf("Latest logged certificate expires in %d days", days),
+			Description: fmt.Sprintf("The newest certificate logged for %s expires on %s, and no renewal has been issued yet.", host, cert.NotAfter.Format(time.RFC3339)),
+			Severity:    types.SeverityLow,
+			Evidence:    loggedEvidence(cert),
+			Remediation: "Renew the SSL/TLS certificate before it expires.",
+			Metadata:    m,
+		})
+	}
+
+	if !slices.Contains(cert.Names, host) {
+		for _, name := range cert.Names {
+			if strings.HasPrefix(name, "*.") && (scanner.PassiveCertificate{Names: []string{name}}).Covers(host) {
+				result.Findings = append(result.Findings, types.Finding{
+					Title:       "Wildcard certificate",
+					Description: fmt.Sprintf("%s is covered by a certificate for %s, whose key is shared by every host under it.", host, name),
+					Severity:    types.SeverityInfo,
+					Evidence:    loggedEvidence(cert),
+					Remediation: "Prefer certificates naming their hosts, so a key leaked from one host does not expose the others.",
+					Metadata:    metadata(),
+				})
+				break
+			}
+		}
+	}
+}
+
+// loggedEvidence describes a logged certificate.
+func loggedEvidence(cert scanner.PassiveCertificate) string {
+	return fmt.Sprintf("%s certificate %s, issuer: %s, names: %s, valid %s to %s",
+		cert.Source, cert.ID, cert.Issuer, strings.Join(cert.Names, ", "),
+		cert.NotBefore.Format("2006-01-02"), cert.NotAfter.Format("2006-01-02"))
+}
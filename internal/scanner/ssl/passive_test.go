@@ -0,0 +1,81 @@
+package ssl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// certSource is a passive source that knows only certificates.
+type certSource []scanner.PassiveCertificate
+
+func (c certSource) Name() string { return "ct" }
+func (c certSource) Lookup(context.Context, string) (*scanner.PassiveData, error) {
+	return &scanner.PassiveData{Certificates: c}, nil
+}
+
+func passiveOptions(certs ...scanner.PassiveCertificate) scanner.Options {
+	opts := scanner.DefaultOptions()
+	opts.Passive = true
+	opts.Sources = []scanner.PassiveSource{certSource(certs)}
+	return opts
+}
+
+func findingTitles(result *types.ScanResult) []string {
+	titles := make([]string, len(result.Findings))
+	for i, f := range result.Findings {
+		titles[i] = f.Title
+	}
+	return titles
+}
+
+func TestScanner_PassiveLatestCertificateExpired(t *testing.T) {
+	now := time.Now()
+	opts := passiveOptions(
+		scanner.PassiveCertificate{ID: "1", Names: []string{"www.example.com"}, Issuer: "CN=R3", NotAfter: now.AddDate(0, -3, 0)},
+		scanner.PassiveCertificate{ID: "2", Names: []string{"www.example.com"}, Issuer: "CN=E1", NotAfter: now.AddDate(0, 0, -2)},
+		scanner.PassiveCertificate{ID: "3", Names: []string{"mail.example.com"}, Issuer: "CN=R3", NotAfter: now.AddDate(1, 0, 0)},
+	)
+
+	result, err := New().Run(context.Background(), types.Target{Host: "www.example.com"}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Latest logged certificate expired", "Logged certificates: 2"}, findingTitles(result))
+	assert.Equal(t, types.SeverityMedium, result.Findings[0].Severity)
+	assert.Contains(t, result.Findings[0].Evidence, "ct certificate 2")
+	assert.Equal(t, "CN=R3; CN=E1", result.Findings[1].Metadata["issuers"])
+	assert.Equal(t, "true", result.Findings[1].Metadata["passive"])
+}
+
+func TestScanner_PassiveWildcardExpiringSoon(t *testing.T) {
+	opts := passiveOptions(scanner.PassiveCertificate{
+		ID: "1", Names: []string{"*.example.com", "example.com"}, NotAfter: time.Now().Add(10*24*time.Hour + time.Hour),
+	})
+
+	result, err := New().Run(context.Background(), types.Target{Host: "api.example.com"}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"Latest logged certificate expires in 10 days",
+		"Wildcard certificate",
+		"Logged certificates: 1",
+	}, findingTitles(result))
+	assert.Equal(t, "10", result.Findings[0].Metadata["days_until_expiry"])
+}
+
+func TestScanner_PassiveNoCertificates(t *testing.T) {
+	opts := passiveOptions(scanner.PassiveCertificate{ID: "1", Names: []string{"*.a.example.com"}, NotAfter: time.Now().AddDate(1, 0, 0)})
+
+	result, err := New().Run(context.Background(), types.Target{Host: "b.example.com"}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"No logged certificates"}, findingTitles(result))
+}
+
+func TestScanner_PassiveIPTarget(t *testing.T) {
+	result, err := New().Run(context.Background(), types.Target{Host: "192.0.2.1"}, passiveOptions())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Scanner skipped"}, findingTitles(result))
+}
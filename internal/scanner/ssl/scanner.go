@@ -24,7 +24,20 @@ func New() *Scanner {
 func (s *Scanner) Name() string        { return "ssl" }
 func (s *Scanner) Description() string { return "SSL/TLS configuration checks" }
 
+// Applies reports whether the target speaks TLS. Targets that only answered
+// plain HTTP during pre-flight have nothing to check.
+func (s *Scanner) Applies(p scanner.PreflightResult) (bool, string) {
+	if p.HTTP && !p.HTTPS {
+		return false, "the target only serves plain HTTP"
+	}
+	return true, ""
+}
+
 func (s *Scanner) Run(ctx context.Context, target types.Target, opts scanner.Options) (*types.ScanResult, error) {
+	if opts.Passive {
+		return s.runPassive(ctx, target, opts)
+	}
+
 	result := &types.ScanResult{
 		ScannerName: s.Name(),
 		Target:      target,
@@ -39,10 +52,7 @@ func (s *Scanner) Run(ctx context.Context, target types.Target, opts scanner.Opt
 
 	addr := net.JoinHostPort(target.Host, strconv.Itoa(port))
 
-	dialer := &net.Dialer{Timeout: timeout}
-	conn, err := tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{
-		InsecureSkipVerify: true,
-	})
+	conn, err := dial(ctx, addr, target.Host, timeout, opts)
 	if err != nil {
 		result.Error = fmt.Sprintf("TLS connection failed: %v", err)
 		result.CompletedAt = time.Now()
@@ -70,10 +80,36 @@ func (s *Scanner) Run(ctx context.Context, target types.Target, opts scanner.Opt
 		})
 	}
 
+	family := scanner.AddressFamily(conn.RemoteAddr())
+	for i := range result.Findings {
+		if result.Findings[i].Metadata == nil {
+			result.Findings[i].Metadata = map[string]string{}
+		}
+		result.Findings[i].Metadata["address_family"] = family
+	}
+
 	result.CompletedAt = time.Now()
 	return result, nil
 }
 
+// dial opens a TLS connection to addr, resolving it with opts.Resolver.
+// Certificates are not verified here; the checks inspect them.
+func dial(ctx context.Context, addr, serverName string, timeout time.Duration, opts scanner.Options) (*tls.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	raw, err := opts.Resolver.DialContext(ctx, opts.Network(), addr)
+	if err != nil {
+		return nil, err
+	}
+	conn := tls.Client(raw, &tls.Config{ServerName: serverName, InsecureSkipVerify: true})
+	if err := conn.HandshakeContext(ctx); err != nil {
+		raw.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
 func resolvePort(target types.Target) int {
 	if len(target.Ports) > 0 {
 		return target.Ports[0]
@@ -201,3 +201,18 @@ func TestScanner_CustomPort(t *testing.T) {
 	target := types.Target{Host: "127.0.0.1", Ports: []int{8443}, Scheme: "https"}
 	assert.Equal(t, 8443, resolvePort(target))
 }
+
+func TestScanner_Applies(t *testing.T) {
+	s := New()
+
+	applies, reason := s.Applies(scanner.PreflightResult{HTTP: true})
+	assert.False(t, applies)
+	assert.Contains(t, reason, "plain HTTP")
+
+	applies, _ = s.Applies(scanner.PreflightResult{HTTP: true, HTTPS: true})
+	assert.True(t, applies)
+
+	// An unreachable target is left for the scanner to report.
+	applies, _ = s.Applies(scanner.PreflightResult{})
+	assert.True(t, applies)
+}
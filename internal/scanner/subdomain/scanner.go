@@ -0,0 +1,268 @@
+package subdomain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// Scanner enumerates the subdomains of the target's domain from passive
+// data sources (Options.Sources) and, unless the scan is passive, checks
+// which of them still resolve.
+type Scanner struct{}
+
+// New creates a new subdomain scanner.
+func New() *Scanner {
+	return &Scanner{}
+}
+
+func (s *Scanner) Name() string        { return "subdomain" }
+func (s *Scanner) Description() string { return "Subdomain enumeration from passive sources" }
+
+// SupportsPassive reports that the scanner can run without contacting the
+// target; it then skips resolving the names it finds.
+func (s *Scanner) SupportsPassive() bool { return true }
+
+func (s *Scanner) Run(ctx context.Context, target types.Target, opts scanner.Options) (*types.ScanResult, error) {
+	domain := strings.ToLower(strings.TrimSuffix(target.Host, "."))
+	if domain == "" {
+		return nil, fmt.Errorf("cannot determine domain for target %q", target.URL)
+	}
+	if net.ParseIP(domain) != nil {
+		return scanner.Skipped(s.Name(), target, "the target is an IP address"), nil
+	}
+	if len(opts.Sources) == 0 {
+		return nil, fmt.Errorf("no passive data sources configured")
+	}
+
+	result := &types.ScanResult{
+		ScannerName: s.Name(),
+		Target:      target,
+		StartedAt:   time.Now(),
+	}
+
+	data, errs := opts.PassiveLookup(ctx, domain)
+	for _, err := range errs {
+		opts.Logf(s.Name(), scanner.LogWarn, "passive source failed: %v", err)
+	}
+	if len(errs) == len(opts.Sources) {
+		result.Error = errors.Join(errs...).Error()
+		result.CompletedAt = time.Now()
+		return result, nil
+	}
+
+	var hosts []scanner.PassiveHost
+	for _, h := range data.Hosts {
+		if h.Name != domain {
+			hosts = append(hosts, h)
+		}
+	}
+
+	var resolved [][]string
+	var wildcard []string
+	if !opts.Passive {
+		wildcard = s.detectWildcard(ctx, domain, opts)
+		resolved = s.resolve(ctx, hosts, opts)
+	}
+	if len(wildcard) > 0 {
+		result.Findings = append(result.Findings, wildcardFinding(domain, wildcard))
+	}
+
+	wildcardMatches := 0
+	for i, h := range hosts {
+		finding := discovered(h)
+		if resolved != nil {
+			addrs := resolved[i]
+			finding.Metadata["resolves"] = strconv.FormatBool(len(addrs) > 0)
+			if len(addrs) > 0 {
+				finding.Metadata["resolved_addresses"] = strings.Join(addrs, ", ")
+			} else {
+				finding.Description += " It does not resolve any more."
+			}
+			if onlyWildcard(addrs, wildcard) {
+				// The name may resolve only because every name does, but
+				// the passive source still saw it: keep it, flagged.
+				wildcardMatches++
+				finding.Metadata["wildcard"] = "true"
+				finding.Description += " It resolves only to the wildcard's addresses, so its resolving says nothing of whether a host is there."
+			}
+		}
+		result.Findings = append(result.Findings, finding)
+		opts.ReportFinding(s.Name(), finding)
+	}
+
+	if len(hosts) == 0 {
+		result.Findings = append(result.Findings, types.Finding{
+			Title:       "No subdomains found",
+			Description: fmt.Sprintf("The passive data sources know of no names under %s.", domain),
+			Severity:    types.SeverityInfo,
+		})
+	}
+
+	sources := make([]string, len(opts.Sources))
+	for i, source := range opts.Sources {
+		sources[i] = source.Name()
+	}
+	result.Metadata = map[string]string{
+		"domain":     domain,
+		"sources":    strings.Join(sources, ", "),
+		"subdomains": strconv.Itoa(len(hosts)),
+	}
+	if len(wildcard) > 0 {
+		result.Metadata["wildcard_addresses"] = strings.Join(wildcard, ", ")
+		result.Metadata["wildcard_matches"] = strconv.Itoa(wildcardMatches)
+	}
+	result.CompletedAt = time.Now()
+	return result, nil
+}
+
+// resolve looks up every host with opts.Resolver, opts.Concurrency at a
+// time, and returns the addresses of each, nil for those that do not
+// resolve.
+func (s *Scanner) resolve(ctx context.Context, hosts []scanner.PassiveHost, opts scanner.Options) [][]string {
+	resolved := make([][]string, len(hosts))
+	concurrency := max(opts.Concurrency, 1)
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+	for range min(concurrency, len(hosts)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				if opts.Gate.Wait(ctx) == nil {
+					lookupCtx, cancel := context.WithTimeout(ctx, timeout)
+					addrs, err := opts.Resolver.LookupHost(lookupCtx, hosts[i].Name)
+					cancel()
+					if err == nil {
+						resolved[i] = addrs
+					}
+				}
+				mu.Lock()
+				done++
+				opts.ReportProgress(s.Name(), done, len(hosts))
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range hosts {
+		if ctx.Err() != nil {
+			break
+		}
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+	return resolved
+}
+
+// wildcardProbes is how many random names detectWildcard resolves. A
+// wildcard record served by several rotating addresses shows more of them
+// with each name.
+const wildcardProbes = 3
+
+// randomLabel returns a label no one would have registered. Extracted as a
+// variable for testing.
+var randomLabel = func() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return "hunter-" + hex.EncodeToString(b)
+}
+
+// detectWildcard resolves wildcardProbes random names under domain, and
+// returns the addresses any of them resolve to, sorted: those of a
+// wildcard record, if the domain has one.
+func (s *Scanner) detectWildcard(ctx context.Context, domain string, opts scanner.Options) []string {
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	seen := map[string]bool{}
+	var addrs []string
+	for range wildcardProbes {
+		if opts.Gate.Wait(ctx) != nil {
+			break
+		}
+		lookupCtx, cancel := context.WithTimeout(ctx, timeout)
+		found, err := opts.Resolver.LookupHost(lookupCtx, randomLabel()+"."+domain)
+		cancel()
+		if err != nil {
+			continue
+		}
+		for _, addr := range found {
+			if !seen[addr] {
+				seen[addr] = true
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+	sort.Strings(addrs)
+	if len(addrs) > 0 {
+		opts.Logf(s.Name(), scanner.LogInfo, "%s has wildcard DNS (%s); names resolving only there are flagged", domain, strings.Join(addrs, ", "))
+	}
+	return addrs
+}
+
+// onlyWildcard reports whether addrs, those a name resolves to, are all
+// addresses of the wildcard record.
+func onlyWildcard(addrs, wildcard []string) bool {
+	if len(addrs) == 0 || len(wildcard) == 0 {
+		return false
+	}
+	for _, addr := range addrs {
+		if !slices.Contains(wildcard, addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// wildcardFinding reports that every name under domain resolves to addrs.
+func wildcardFinding(domain string, addrs []string) types.Finding {
+	return types.Finding{
+		Title:       "Wildcard DNS record",
+		Description: fmt.Sprintf("Names under %s that no one registered resolve, so resolving a name says nothing of whether a host is there. Names that resolve only to the wildcard's addresses are flagged as wildcard matches.", domain),
+		Severity:    types.SeverityInfo,
+		Evidence:    fmt.Sprintf("*.%s resolves to %s", domain, strings.Join(addrs, ", ")),
+		Metadata: map[string]string{
+			"domain":             domain,
+			"wildcard_addresses": strings.Join(addrs, ", "),
+		},
+	}
+}
+
+// discovered returns the finding for a subdomain a passive source knows.
+func discovered(h scanner.PassiveHost) types.Finding {
+	finding := types.Finding{
+		Title:       fmt.Sprintf("Subdomain discovered: %s", h.Name),
+		Description: fmt.Sprintf("%s is listed by %s.", h.Name, strings.Join(h.Sources, " and ")),
+		Severity:    types.SeverityInfo,
+		Metadata: map[string]string{
+			"host":    h.Name,
+			"sources": strings.Join(h.Sources, ", "),
+		},
+	}
+	if len(h.Addresses) > 0 {
+		finding.Evidence = fmt.Sprintf("Seen resolving to %s", strings.Join(h.Addresses, ", "))
+		finding.Metadata["passive_addresses"] = strings.Join(h.Addresses, ", ")
+	}
+	return finding
+}
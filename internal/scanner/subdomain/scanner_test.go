@@ -0,0 +1,158 @@
+package subdomain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubSource answers every lookup with hosts, or fails with err.
+type stubSource struct {
+	name  string
+	hosts []scanner.PassiveHost
+	err   error
+}
+
+func (s stubSource) Name() string { return s.name }
+func (s stubSource) Lookup(context.Context, string) (*scanner.PassiveData, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &scanner.PassiveData{Hosts: s.hosts}, nil
+}
+
+func TestScanner_NameAndDescription(t *testing.T) {
+	s := New()
+	assert.Equal(t, "subdomain", s.Name())
+	assert.Equal(t, "Subdomain enumeration from passive sources", s.Description())
+	assert.True(t, s.SupportsPassive())
+}
+
+func TestScanner_ResolvesDiscoveredNames(t *testing.T) {
+	resolver, err := scanner.NewResolver("", []string{"www.hunter.invalid:192.0.2.10"})
+	require.NoError(t, err)
+
+	opts := scanner.DefaultOptions()
+	opts.Resolver = resolver
+	opts.Sources = []scanner.PassiveSource{
+		stubSource{name: "ct", hosts: []scanner.PassiveHost{{Name: "hunter.invalid"}, {Name: "www.hunter.invalid"}, {Name: "old.hunter.invalid"}}},
+		stubSource{name: "pdns", hosts: []scanner.PassiveHost{{Name: "www.hunter.invalid", Addresses: []string{"192.0.2.1"}}}},
+	}
+	var streamed []string
+	opts.Found = func(_ string, f types.Finding) { streamed = append(streamed, f.Title) }
+
+	result, err := New().Run(context.Background(), types.Target{Host: "hunter.invalid"}, opts)
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 2, "the domain itself is not a subdomain")
+	assert.Equal(t, []string{"Subdomain discovered: old.hunter.invalid", "Subdomain discovered: www.hunter.invalid"}, streamed)
+
+	old, www := result.Findings[0], result.Findings[1]
+	assert.Equal(t, "false", old.Metadata["resolves"])
+	assert.Contains(t, old.Description, "does not resolve")
+	assert.Equal(t, "true", www.Metadata["resolves"])
+	assert.Equal(t, "192.0.2.10", www.Metadata["resolved_addresses"])
+	assert.Equal(t, "192.0.2.1", www.Metadata["passive_addresses"])
+	assert.Equal(t, "ct, pdns", www.Metadata["sources"])
+
+	assert.Equal(t, "hunter.invalid", result.Metadata["domain"])
+	assert.Equal(t, "2", result.Metadata["subdomains"])
+}
+
+func TestScanner_FlagsWildcardMatches(t *testing.T) {
+	labels := []string{"probe1", "probe2", "probe3"}
+	saved := randomLabel
+	randomLabel = func() string {
+		label := labels[0]
+		labels = labels[1:]
+		return label
+	}
+	defer func() { randomLabel = saved }()
+
+	resolver, err := scanner.NewResolver("", []string{
+		"probe1.hunter.invalid:192.0.2.50",
+		"probe2.hunter.invalid:192.0.2.51",
+		"probe3.hunter.invalid:192.0.2.50",
+		"www.hunter.invalid:192.0.2.10",
+		"bogus.hunter.invalid:192.0.2.51",
+	})
+	require.NoError(t, err)
+
+	opts := scanner.DefaultOptions()
+	opts.Resolver = resolver
+	opts.Sources = []scanner.PassiveSource{
+		stubSource{name: "ct", hosts: []scanner.PassiveHost{{Name: "www.hunter.invalid"}, {Name: "bogus.hunter.invalid"}}},
+	}
+
+	result, err := New().Run(context.Background(), types.Target{Host: "hunter.invalid"}, opts)
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 3)
+	assert.Equal(t, "Wildcard DNS record", result.Findings[0].Title)
+	assert.Equal(t, "*.hunter.invalid resolves to 192.0.2.50, 192.0.2.51", result.Findings[0].Evidence)
+
+	bogus := result.Findings[1]
+	assert.Equal(t, "Subdomain discovered: bogus.hunter.invalid", bogus.Title)
+	assert.Equal(t, "true", bogus.Metadata["wildcard"])
+	assert.Equal(t, "192.0.2.51", bogus.Metadata["resolved_addresses"])
+
+	www := result.Findings[2]
+	assert.Equal(t, "Subdomain discovered: www.hunter.invalid", www.Title)
+	assert.NotContains(t, www.Metadata, "wildcard")
+
+	assert.Equal(t, "192.0.2.50, 192.0.2.51", result.Metadata["wildcard_addresses"])
+	assert.Equal(t, "1", result.Metadata["wildcard_matches"])
+	assert.Equal(t, "2", result.Metadata["subdomains"])
+}
+
+func TestScanner_PassiveDoesNotResolve(t *testing.T) {
+	opts := scanner.DefaultOptions()
+	opts.Passive = true
+	opts.Sources = []scanner.PassiveSource{
+		stubSource{name: "ct", hosts: []scanner.PassiveHost{{Name: "www.hunter.invalid"}}},
+		stubSource{name: "pdns", err: errors.New("quota exceeded")},
+	}
+	var logged []string
+	opts.Log = func(e scanner.LogEntry) { logged = append(logged, e.Message) }
+
+	result, err := New().Run(context.Background(), types.Target{Host: "hunter.invalid"}, opts)
+	require.NoError(t, err)
+	assert.Empty(t, result.Error, "one working source is enough")
+	require.Len(t, result.Findings, 1)
+	assert.NotContains(t, result.Findings[0].Metadata, "resolves")
+	assert.Equal(t, []string{"passive source failed: pdns: quota exceeded"}, logged)
+}
+
+func TestScanner_AllSourcesFail(t *testing.T) {
+	opts := scanner.DefaultOptions()
+	opts.Sources = []scanner.PassiveSource{stubSource{name: "ct", err: errors.New("timeout")}}
+
+	result, err := New().Run(context.Background(), types.Target{Host: "hunter.invalid"}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "ct: timeout", result.Error)
+}
+
+func TestScanner_NoSubdomains(t *testing.T) {
+	opts := scanner.DefaultOptions()
+	opts.Sources = []scanner.PassiveSource{stubSource{name: "ct"}}
+
+	result, err := New().Run(context.Background(), types.Target{Host: "hunter.invalid"}, opts)
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 1)
+	assert.Equal(t, "No subdomains found", result.Findings[0].Title)
+}
+
+func TestScanner_SkipsIPTargets(t *testing.T) {
+	result, err := New().Run(context.Background(), types.Target{Host: "192.0.2.1"}, scanner.DefaultOptions())
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 1)
+	assert.Equal(t, "Scanner skipped", result.Findings[0].Title)
+}
+
+func TestScanner_NoSources(t *testing.T) {
+	_, err := New().Run(context.Background(), types.Target{Host: "hunter.invalid"}, scanner.DefaultOptions())
+	assert.ErrorContains(t, err, "no passive data sources")
+}
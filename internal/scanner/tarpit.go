@@ -0,0 +1,112 @@
+package scanner
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// Signs of a tarpit that a probe may show.
+const (
+	// TarpitAnswered is a probe answered as though something were there,
+	// such as a port accepting the connection.
+	TarpitAnswered = "answered"
+	// TarpitSlow is a probe answered only after an extreme delay.
+	TarpitSlow = "slow"
+	// TarpitEndless is a response that did not end.
+	TarpitEndless = "endless"
+)
+
+// tarpitRatio is the share of probes, in percent, that must show a sign for
+// the target to be judged a tarpit.
+const tarpitRatio = 90
+
+// TarpitDetector judges, from a scanner's probes, whether the target is a
+// tarpit or honeypot built to waste scanners' time: one that fakes an open
+// port on every probe, answers only after long delays, or sends responses
+// that never end. Once it has seen enough probes and nearly all of them show
+// the same sign, the scanner should stop. A nil *TarpitDetector never trips.
+type TarpitDetector struct {
+	after int
+	slow  time.Duration
+
+	mu     sync.Mutex
+	probes int
+	counts map[string]int
+	sign   string
+}
+
+// NewTarpitDetector returns a detector that judges the target after after
+// probes, 0 disabling it. slow is the delay a probe must reach to show
+// TarpitSlow, used to describe it.
+func NewTarpitDetector(after int, slow time.Duration) *TarpitDetector {
+	if after <= 0 {
+		return nil
+	}
+	return &TarpitDetector{after: after, slow: slow, counts: map[string]int{}}
+}
+
+// Observe records a probe and the signs it showed, and reports whether the
+// target has just been judged a tarpit. It reports true once, to the probe
+// that tripped it.
+func (d *TarpitDetector) Observe(signs ...string) bool {
+	if d == nil {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.sign != "" {
+		return false
+	}
+	d.probes++
+	for _, s := range signs {
+		d.counts[s]++
+	}
+	if d.probes < d.after {
+		return false
+	}
+	for _, s := range []string{TarpitAnswered, TarpitSlow, TarpitEndless} {
+		if d.counts[s]*100 >= d.probes*tarpitRatio {
+			d.sign = s
+			return true
+		}
+	}
+	return false
+}
+
+// Finding returns the finding explaining why the named scanner stopped, and
+// whether the detector tripped at all.
+func (d *TarpitDetector) Finding(name string) (types.Finding, bool) {
+	if d == nil {
+		return types.Finding{}, false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.sign == "" {
+		return types.Finding{}, false
+	}
+
+	n := d.counts[d.sign]
+	var behavior string
+	switch d.sign {
+	case TarpitAnswered:
+		behavior = fmt.Sprintf("%d of the first %d probes were answered as if something were there, as hosts that fake open ports on every probe do", n, d.probes)
+	case TarpitSlow:
+		behavior = fmt.Sprintf("%d of the first %d probes were answered only after %s or more", n, d.probes, d.slow.Round(time.Millisecond))
+	case TarpitEndless:
+		behavior = fmt.Sprintf("%d of the first %d responses did not end", n, d.probes)
+	}
+	return types.Finding{
+		Title: "Target appears to be a tarpit",
+		Description: fmt.Sprintf("%s. The target looks built to waste scanners' time, so the %s scan stopped early; its other findings may be fake. "+
+			"If it is not a tarpit, set tarpit_after to 0 under scanners.%s to scan it fully.", behavior, name, name),
+		Severity: types.SeverityInfo,
+		Metadata: map[string]string{
+			"tarpit_sign": d.sign,
+			"probes":      strconv.Itoa(d.probes),
+		},
+	}, true
+}
@@ -0,0 +1,46 @@
+package scanner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTarpitDetector(t *testing.T) {
+	d := NewTarpitDetector(10, 2*time.Second)
+	for range 9 {
+		assert.False(t, d.Observe(TarpitAnswered), "too few probes to judge")
+	}
+	assert.True(t, d.Observe(TarpitAnswered))
+	assert.False(t, d.Observe(TarpitAnswered), "trips once")
+
+	f, ok := d.Finding("port")
+	require.True(t, ok)
+	assert.Equal(t, "Target appears to be a tarpit", f.Title)
+	assert.Equal(t, TarpitAnswered, f.Metadata["tarpit_sign"])
+	assert.Equal(t, "10", f.Metadata["probes"])
+	assert.Contains(t, f.Description, "scanners.port")
+}
+
+func TestTarpitDetector_MixedProbes(t *testing.T) {
+	d := NewTarpitDetector(10, 2*time.Second)
+	for i := range 100 {
+		if i%3 == 0 {
+			assert.False(t, d.Observe())
+		} else {
+			assert.False(t, d.Observe(TarpitSlow))
+		}
+	}
+	_, ok := d.Finding("dirs")
+	assert.False(t, ok, "a third of the probes were answered promptly")
+}
+
+func TestTarpitDetector_Disabled(t *testing.T) {
+	d := NewTarpitDetector(0, time.Second)
+	assert.Nil(t, d)
+	assert.False(t, d.Observe(TarpitEndless))
+	_, ok := d.Finding("dirs")
+	assert.False(t, ok)
+}
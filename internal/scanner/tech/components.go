@@ -0,0 +1,238 @@
+package tech
+
+import (
+	"cmp"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+//go:embed js-vulns.json
+var defaultJSVulns []byte
+
+//go:embed cve-map.json
+var defaultCVEMap []byte
+
+var scriptTag = regexp.MustCompile(`(?is)<script\b[^>]*>`)
+
+// Component is a product recognized by its version, together with the
+// versions of it known to be vulnerable.
+type Component struct {
+	Name string `json:"name"`
+	// Patterns are regular expressions whose first group captures the
+	// version: script URLs are matched for JavaScript libraries, Server
+	// and X-Powered-By headers for server software.
+	Patterns        []string        `json:"patterns"`
+	Vulnerabilities []Vulnerability `json:"vulnerabilities"`
+
+	patterns []*regexp.Regexp
+}
+
+// Vulnerability is a flaw of a component's versions from AtOrAbove, when
+// set, up to but excluding Below.
+type Vulnerability struct {
+	AtOrAbove   string         `json:"at_or_above,omitempty"`
+	Below       string         `json:"below"`
+	Severity    types.Severity `json:"severity"`
+	Identifiers []string       `json:"identifiers"`
+	Summary     string         `json:"summary"`
+}
+
+// Components is a database of components.
+type Components []*Component
+
+// Match is a component found, and the version it was found at.
+type Match struct {
+	Component *Component
+	Version   string
+}
+
+// LoadJSLibraries returns the embedded database of JavaScript libraries,
+// with the entries of the JSON file at path, when it is not empty,
+// replacing those of the same name. The js-vulns data set is such a file.
+func LoadJSLibraries(path string) (Components, error) {
+	return loadComponents(defaultJSVulns, path)
+}
+
+// LoadCVEMap returns the embedded database mapping server software versions
+// to CVEs, with the entries of the JSON file at path, when it is not empty,
+// replacing those of the same name. The cve-map data set is such a file.
+func LoadCVEMap(path string) (Components, error) {
+	return loadComponents(defaultCVEMap, path)
+}
+
+func loadComponents(embedded []byte, path string) (Components, error) {
+	var db Components
+	if err := db.merge(embedded); err != nil {
+		return nil, fmt.Errorf("embedded: %w", err)
+	}
+	if path == "" {
+		return db, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.merge(raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return db, nil
+}
+
+func (db *Components) merge(raw []byte) error {
+	var entries []*Component
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return err
+	}
+	for _, c := range entries {
+		if c.Name == "" {
+			return fmt.Errorf("component without a name")
+		}
+		for _, p := range c.Patterns {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return fmt.Errorf("%s: %w", c.Name, err)
+			}
+			if re.NumSubexp() == 0 {
+				return fmt.Errorf("%s: pattern %q captures no version", c.Name, p)
+			}
+			c.patterns = append(c.patterns, re)
+		}
+		for _, v := range c.Vulnerabilities {
+			if v.Below == "" {
+				return fmt.Errorf("%s: vulnerability %s has no fixed version", c.Name, strings.Join(v.Identifiers, ", "))
+			}
+			if types.SeverityRank(v.Severity) > types.SeverityRank(types.SeverityInfo) {
+				return fmt.Errorf("%s: unknown severity %q", c.Name, v.Severity)
+			}
+		}
+		i := slices.IndexFunc(*db, func(o *Component) bool { return strings.EqualFold(o.Name, c.Name) })
+		if i >= 0 {
+			(*db)[i] = c
+		} else {
+			*db = append(*db, c)
+		}
+	}
+	return nil
+}
+
+// Identify returns the components one of whose patterns matches s, each
+// with the version the pattern captured.
+func (db Components) Identify(s string) []Match {
+	var matches []Match
+	for _, c := range db {
+		for _, re := range c.patterns {
+			if m := re.FindStringSubmatch(s); m != nil && m[1] != "" {
+				matches = append(matches, Match{Component: c, Version: m[1]})
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// Affecting returns the vulnerabilities of c that version has.
+func (c *Component) Affecting(version string) []Vulnerability {
+	var affecting []Vulnerability
+	for _, v := range c.Vulnerabilities {
+		if compareVersions(version, v.Below) >= 0 {
+			continue
+		}
+		if v.AtOrAbove != "" && compareVersions(version, v.AtOrAbove) < 0 {
+			continue
+		}
+		affecting = append(affecting, v)
+	}
+	return affecting
+}
+
+// compareVersions compares dotted versions part by part. The leading
+// digits of a part compare as a number, so 1.10.0 follows 1.9.2. A suffix
+// after a dash, such as "-rc1", sorts before the release, and any other,
+// such as OpenSSL's letter releases, after it. Missing parts are zero.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := range max(len(as), len(bs)) {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		if c := comparePart(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func comparePart(a, b string) int {
+	an, arest := leadingNumber(a)
+	bn, brest := leadingNumber(b)
+	if c := cmp.Compare(an, bn); c != 0 {
+		return c
+	}
+	switch {
+	case arest == brest:
+		return 0
+	case arest == "":
+		return releaseOrder(brest)
+	case brest == "":
+		return -releaseOrder(arest)
+	}
+	return strings.Compare(arest, brest)
+}
+
+// releaseOrder is how a release compares with one of the same number
+// carrying suffix: after a pre-release such as "-beta", before a later
+// release such as "g".
+func releaseOrder(suffix string) int {
+	if strings.HasPrefix(suffix, "-") {
+		return 1
+	}
+	return -1
+}
+
+// leadingNumber splits part into the number its leading digits spell and
+// the rest.
+func leadingNumber(part string) (int, string) {
+	i := 0
+	for i < len(part) && part[i] >= '0' && part[i] <= '9' {
+		i++
+	}
+	n, _ := strconv.Atoi(part[:i])
+	return n, part[i:]
+}
+
+// ScriptSources returns the scripts a page at pageURL loads with
+// <script src>, resolved.
+func ScriptSources(pageURL, body string) []string {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+	var scripts []string
+	for _, tag := range scriptTag.FindAllString(body, -1) {
+		for _, m := range attribute.FindAllStringSubmatch(tag, -1) {
+			if !strings.EqualFold(m[1], "src") {
+				continue
+			}
+			u, err := base.Parse(strings.TrimSpace(html.UnescapeString(m[2] + m[3] + m[4])))
+			if err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+				scripts = append(scripts, u.String())
+			}
+			break
+		}
+	}
+	return scripts
+}
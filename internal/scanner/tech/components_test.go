@@ -0,0 +1,88 @@
+package tech
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 0, compareVersions("3.5.0", "3.5.0"))
+	assert.Equal(t, 0, compareVersions("3.5", "3.5.0"))
+	assert.Equal(t, -1, compareVersions("1.9.2", "1.10.0"))
+	assert.Equal(t, 1, compareVersions("4.17.21", "4.17.4"))
+	assert.Equal(t, -1, compareVersions("3.0.0-rc1", "3.0.0"), "pre-releases come before the release")
+	assert.Equal(t, 1, compareVersions("1.0.1f", "1.0.1"), "letter releases come after the release")
+	assert.Equal(t, -1, compareVersions("1.0.1f", "1.0.1g"))
+}
+
+func TestLoadJSLibraries(t *testing.T) {
+	db, err := LoadJSLibraries("")
+	require.NoError(t, err)
+	matches := db.Identify("https://code.jquery.com/jquery-1.12.4.min.js")
+	require.Len(t, matches, 1)
+	assert.Equal(t, "jQuery", matches[0].Component.Name)
+	assert.Equal(t, "1.12.4", matches[0].Version)
+
+	path := filepath.Join(t.TempDir(), "js-vulns.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[
+		{"name": "jquery", "patterns": ["/jq-(\\d+\\.\\d+\\.\\d+)\\.js"], "vulnerabilities": [{"below": "9.0.0", "severity": "LOW", "identifiers": ["CVE-2099-0001"]}]},
+		{"name": "Widgets", "patterns": ["/widgets-(\\d+\\.\\d+)\\.js"]}
+	]`), 0o644))
+	db, err = LoadJSLibraries(path)
+	require.NoError(t, err)
+	assert.Empty(t, db.Identify("https://code.jquery.com/jquery-1.12.4.min.js"), "file entries replace embedded ones of the same name")
+	require.Len(t, db.Identify("/static/jq-3.7.1.js"), 1)
+	require.Len(t, db.Identify("/static/widgets-2.1.js"), 1)
+	require.Len(t, db.Identify("https://cdn.jsdelivr.net/npm/lodash@4.17.15/lodash.min.js"), 1, "other embedded entries are kept")
+
+	for _, bad := range []string{
+		`{"name": "x"}`,
+		`[{"patterns": ["x-(\\d+)"]}]`,
+		`[{"name": "x", "patterns": ["x-\\d+"]}]`,
+		`[{"name": "x", "vulnerabilities": [{"severity": "HIGH"}]}]`,
+		`[{"name": "x", "vulnerabilities": [{"below": "2.0", "severity": "SEVERE"}]}]`,
+	} {
+		require.NoError(t, os.WriteFile(path, []byte(bad), 0o644))
+		_, err = LoadJSLibraries(path)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestLoadCVEMap(t *testing.T) {
+	db, err := LoadCVEMap("")
+	require.NoError(t, err)
+	matches := db.Identify("Apache/2.4.49 (Unix) OpenSSL/1.0.1f")
+	require.Len(t, matches, 2)
+	assert.Equal(t, "2.4.49", matches[0].Version)
+	assert.Equal(t, "1.0.1f", matches[1].Version)
+}
+
+func TestComponent_Affecting(t *testing.T) {
+	c := &Component{Name: "Bootstrap", Vulnerabilities: []Vulnerability{
+		{Below: "3.4.1", Severity: types.SeverityMedium, Identifiers: []string{"CVE-A"}},
+		{AtOrAbove: "4.0.0", Below: "4.3.1", Severity: types.SeverityMedium, Identifiers: []string{"CVE-B"}},
+	}}
+	assert.Len(t, c.Affecting("3.3.7"), 1)
+	assert.Empty(t, c.Affecting("3.4.1"), "the fixed version is not affected")
+	assert.Empty(t, c.Affecting("3.4.5"), "versions between ranges are not affected")
+	assert.Equal(t, []string{"CVE-B"}, c.Affecting("4.2.0")[0].Identifiers)
+	assert.Empty(t, c.Affecting("5.0.0"))
+}
+
+func TestScriptSources(t *testing.T) {
+	body := `<head>
+<script src="/static/jquery-1.12.4.min.js"></script>
+<script>var inline = true;</script>
+<SCRIPT type="module" SRC='https://cdn.example.com/app.js'></SCRIPT>
+<script src="data:text/javascript,alert(1)"></script>
+</head>`
+	assert.Equal(t, []string{
+		"http://example.com/static/jquery-1.12.4.min.js",
+		"https://cdn.example.com/app.js",
+	}, ScriptSources("http://example.com/app/", body))
+}
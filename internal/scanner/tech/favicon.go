@@ -0,0 +1,184 @@
+package tech
+
+import (
+	"context"
+	_ "embed"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"math/bits"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//go:embed favicons.json
+var defaultFavicons []byte
+
+// maxIconSize is the most of a favicon read; larger files are not icons.
+const maxIconSize = 1 << 20
+
+var (
+	linkTag   = regexp.MustCompile(`(?is)<link\b[^>]*>`)
+	attribute = regexp.MustCompile(`(?is)([a-z][a-z0-9_-]*)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+)
+
+// Favicons maps favicon hashes, as FaviconHash computes them, to the product
+// the icon belongs to.
+type Favicons map[int32]string
+
+// LoadFavicons returns the embedded favicon database, extended by the JSON
+// file at path when it is not empty. The file is an object mapping hashes
+// to product names, such as {"81586312": "Jenkins"}; its entries win over
+// the embedded ones.
+func LoadFavicons(path string) (Favicons, error) {
+	favicons := Favicons{}
+	if err := favicons.merge(defaultFavicons); err != nil {
+		return nil, fmt.Errorf("embedded favicons: %w", err)
+	}
+	if path == "" {
+		return favicons, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := favicons.merge(raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return favicons, nil
+}
+
+func (f Favicons) merge(raw []byte) error {
+	var entries map[string]string
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return err
+	}
+	for key, product := range entries {
+		hash, err := strconv.ParseInt(key, 10, 32)
+		if err != nil {
+			return fmt.Errorf("favicon hash %q is not a 32-bit integer", key)
+		}
+		f[int32(hash)] = product
+	}
+	return nil
+}
+
+// FaviconHash returns the hash Shodan indexes favicons by, and searches
+// with http.favicon.hash: MurmurHash3 (32-bit, seed 0) of the icon's
+// base64 encoding, broken into lines of 76 characters.
+func FaviconHash(icon []byte) int32 {
+	encoded := base64.StdEncoding.EncodeToString(icon)
+	var b strings.Builder
+	for len(encoded) > 76 {
+		b.WriteString(encoded[:76])
+		b.WriteByte('\n')
+		encoded = encoded[76:]
+	}
+	b.WriteString(encoded)
+	b.WriteByte('\n')
+	return int32(murmur3([]byte(b.String())))
+}
+
+// murmur3 returns the 32-bit MurmurHash3 of data with a seed of 0.
+func murmur3(data []byte) uint32 {
+	const c1, c2 = 0xcc9e2d51, 0x1b873593
+	var h uint32
+	n := len(data)
+	for ; len(data) >= 4; data = data[4:] {
+		k := binary.LittleEndian.Uint32(data)
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+		h = bits.RotateLeft32(h, 13)
+		h = h*5 + 0xe6546b64
+	}
+	var k uint32
+	switch len(data) {
+	case 3:
+		k ^= uint32(data[2]) << 16
+		fallthrough
+	case 2:
+		k ^= uint32(data[1]) << 8
+		fallthrough
+	case 1:
+		k ^= uint32(data[0])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+	}
+	h ^= uint32(n)
+	h ^= h >> 16
+	h *= 0x85ebca6b
+	h ^= h >> 13
+	h *= 0xc2b2ae35
+	h ^= h >> 16
+	return h
+}
+
+// IconLinks returns the icons a page at pageURL declares with
+// <link rel="icon"> or rel="shortcut icon", resolved.
+func IconLinks(pageURL, body string) []string {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+	var icons []string
+	for _, tag := range linkTag.FindAllString(body, -1) {
+		attrs := map[string]string{}
+		for _, m := range attribute.FindAllStringSubmatch(tag, -1) {
+			attrs[strings.ToLower(m[1])] = html.UnescapeString(m[2] + m[3] + m[4])
+		}
+		isIcon := false
+		for _, rel := range strings.Fields(strings.ToLower(attrs["rel"])) {
+			if rel == "icon" {
+				isIcon = true
+			}
+		}
+		if !isIcon || attrs["href"] == "" {
+			continue
+		}
+		u, err := base.Parse(strings.TrimSpace(attrs["href"]))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			continue
+		}
+		icons = append(icons, u.String())
+	}
+	return icons
+}
+
+// FetchIcon downloads the icon at iconURL. Error pages, including those
+// served with a 200 status as HTML, are not icons and fail.
+func FetchIcon(ctx context.Context, client *http.Client, iconURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, iconURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		return nil, fmt.Errorf("not an icon: %s", resp.Header.Get("Content-Type"))
+	}
+	icon, err := io.ReadAll(io.LimitReader(resp.Body, maxIconSize))
+	if err != nil {
+		return nil, err
+	}
+	if len(icon) == 0 {
+		return nil, fmt.Errorf("empty icon")
+	}
+	return icon, nil
+}
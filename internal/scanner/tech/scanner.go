@@ -0,0 +1,270 @@
+package tech
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// maxPageSize is the most of a page read when looking for its icons and
+// scripts.
+const maxPageSize = 1 << 20
+
+// Scanner fingerprints the technology behind a web target.
+type Scanner struct{}
+
+// New creates a new technology fingerprinting scanner.
+func New() *Scanner {
+	return &Scanner{}
+}
+
+func (s *Scanner) Name() string        { return "tech" }
+func (s *Scanner) Description() string { return "Technology fingerprinting" }
+
+// Run hashes the favicons of the target page, and of imported and crawled
+// pages, and reports the products they belong to. Icons not in the
+// database are reported with their hash, to look up elsewhere. The
+// versions of the JavaScript libraries the pages load, and of the server
+// software their Server and X-Powered-By headers name, are checked
+// against the known-vulnerable ones.
+func (s *Scanner) Run(ctx context.Context, target types.Target, opts scanner.Options) (*types.ScanResult, error) {
+	result := &types.ScanResult{
+		ScannerName: s.Name(),
+		Target:      target,
+		StartedAt:   time.Now(),
+	}
+
+	favicons, err := LoadFavicons(opts.StringArg("favicons"))
+	if err != nil {
+		return nil, fmt.Errorf("loading favicons: %w", err)
+	}
+	libraries, err := LoadJSLibraries(opts.StringArg("js_vulns"))
+	if err != nil {
+		return nil, fmt.Errorf("loading JavaScript library data: %w", err)
+	}
+	servers, err := LoadCVEMap(opts.StringArg("cve_map"))
+	if err != nil {
+		return nil, fmt.Errorf("loading CVE map: %w", err)
+	}
+
+	pageURL := resolveURL(target)
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	client := &http.Client{Timeout: timeout, Transport: opts.HTTPTransport()}
+
+	pages := []string{pageURL}
+	for _, ep := range opts.EndpointsOn(pageURL) {
+		if ep.Method == http.MethodGet {
+			pages = append(pages, ep.URL)
+		}
+	}
+
+	seenIcons := map[string]bool{}
+	reported := map[string]bool{}
+	for i, page := range pages {
+		if ctx.Err() != nil {
+			break
+		}
+		fetched, err := fetchPage(ctx, client, page)
+		if err != nil {
+			if i == 0 {
+				return nil, err
+			}
+			continue
+		}
+		for _, iconURL := range pageIcons(fetched) {
+			if seenIcons[iconURL] {
+				continue
+			}
+			seenIcons[iconURL] = true
+
+			icon, err := FetchIcon(ctx, client, iconURL)
+			if err != nil {
+				opts.Logf(s.Name(), scanner.LogInfo, "favicon %s: %v", iconURL, err)
+				continue
+			}
+			hash := FaviconHash(icon)
+			product, known := favicons[hash]
+			key := product
+			if !known {
+				key = fmt.Sprint(hash)
+			}
+			if reported[key] {
+				continue
+			}
+			reported[key] = true
+			result.Findings = append(result.Findings, faviconFinding(page, iconURL, hash, product))
+		}
+
+		for _, script := range ScriptSources(fetched.url, fetched.body) {
+			for _, m := range libraries.Identify(script) {
+				if key := m.Component.Name + " " + m.Version; !reported[key] {
+					reported[key] = true
+					result.Findings = append(result.Findings, componentFinding(m, "JavaScript library", script, map[string]string{
+						"source":     "script",
+						"page":       page,
+						"script_url": script,
+					}))
+				}
+			}
+		}
+		for _, name := range []string{"Server", "X-Powered-By"} {
+			value := strings.Join(fetched.header.Values(name), ", ")
+			for _, m := range servers.Identify(value) {
+				if key := m.Component.Name + " " + m.Version; !reported[key] {
+					reported[key] = true
+					result.Findings = append(result.Findings, componentFinding(m, "server software", name+": "+value, map[string]string{
+						"source": "header",
+						"page":   page,
+						"header": name,
+					}))
+				}
+			}
+		}
+	}
+
+	result.CompletedAt = time.Now()
+	return result, nil
+}
+
+// fetchedPage is a page as the server returned it.
+type fetchedPage struct {
+	url    string
+	header http.Header
+	body   string
+}
+
+// fetchPage downloads page, following redirects, and returns its final URL,
+// headers, and the start of its body.
+func fetchPage(ctx context.Context, client *http.Client, page string) (*fetchedPage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, page, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP GET %s: %w", page, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+	return &fetchedPage{url: resp.Request.URL.String(), header: resp.Header, body: string(body)}, nil
+}
+
+// pageIcons returns the icons p declares, or its site's /favicon.ico when
+// it declares none.
+func pageIcons(p *fetchedPage) []string {
+	if icons := IconLinks(p.url, p.body); len(icons) > 0 {
+		return icons
+	}
+	u, err := url.Parse(p.url)
+	if err != nil {
+		return nil
+	}
+	root := &url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/favicon.ico"}
+	return []string{root.String()}
+}
+
+// faviconFinding reports the icon at iconURL, found for page: the product
+// it belongs to, or its hash when product is empty.
+func faviconFinding(page, iconURL string, hash int32, product string) types.Finding {
+	metadata := map[string]string{
+		"source":       "favicon",
+		"page":         page,
+		"favicon_url":  iconURL,
+		"favicon_hash": fmt.Sprint(hash),
+	}
+	if product == "" {
+		return types.Finding{
+			Title:       fmt.Sprintf("Unrecognized favicon (hash %d)", hash),
+			Description: fmt.Sprintf("The favicon at %s is not in the favicon database", iconURL),
+			Severity:    types.SeverityInfo,
+			Evidence:    fmt.Sprintf("Search Shodan for http.favicon.hash:%d to find what else serves it", hash),
+			Metadata:    metadata,
+		}
+	}
+	metadata["technology"] = product
+	return types.Finding{
+		Title:       "Technology identified: " + product,
+		Description: fmt.Sprintf("The favicon at %s is %s's", iconURL, product),
+		Severity:    types.SeverityInfo,
+		Evidence:    fmt.Sprintf("Favicon hash %d (http.favicon.hash:%d)", hash, hash),
+		Remediation: "Confirm the product is meant to be exposed, and keep it patched",
+		Metadata:    metadata,
+	}
+}
+
+// componentFinding reports the component m found in evidence: as a
+// vulnerable kind of component when its version has known
+// vulnerabilities, and as an identified technology otherwise.
+func componentFinding(m Match, kind, evidence string, metadata map[string]string) types.Finding {
+	name := m.Component.Name + " " + m.Version
+	metadata["technology"] = m.Component.Name
+	metadata["version"] = m.Version
+
+	vulns := m.Component.Affecting(m.Version)
+	if len(vulns) == 0 {
+		return types.Finding{
+			Title:       "Technology identified: " + name,
+			Description: fmt.Sprintf("%s is in use, at a version with no known vulnerabilities", name),
+			Severity:    types.SeverityInfo,
+			Evidence:    evidence,
+			Remediation: "Confirm the product is meant to be exposed, and keep it patched",
+			Metadata:    metadata,
+		}
+	}
+
+	severity := types.SeverityInfo
+	fixed := ""
+	var ids, flaws []string
+	var references []types.Reference
+	for _, v := range vulns {
+		if types.SeverityRank(v.Severity) < types.SeverityRank(severity) {
+			severity = v.Severity
+		}
+		if compareVersions(v.Below, fixed) > 0 {
+			fixed = v.Below
+		}
+		flaws = append(flaws, fmt.Sprintf("%s (%s)", v.Summary, strings.Join(v.Identifiers, ", ")))
+		for _, id := range v.Identifiers {
+			if slices.Contains(ids, id) {
+				continue
+			}
+			ids = append(ids, id)
+			if strings.HasPrefix(id, "CVE-") {
+				references = append(references, types.Reference{Title: "NVD: " + id, URL: "https://nvd.nist.gov/vuln/detail/" + id})
+			}
+		}
+	}
+	metadata["cves"] = strings.Join(ids, ",")
+	return types.Finding{
+		Title:       fmt.Sprintf("Vulnerable %s: %s", kind, name),
+		Description: fmt.Sprintf("%s has known vulnerabilities: %s", name, strings.Join(flaws, "; ")),
+		Severity:    severity,
+		Evidence:    evidence,
+		Remediation: fmt.Sprintf("Upgrade %s to %s or later", m.Component.Name, fixed),
+		References:  references,
+		Metadata:    metadata,
+	}
+}
+
+// resolveURL determines the target URL from the Target struct.
+func resolveURL(target types.Target) string {
+	if target.URL != "" {
+		return target.URL
+	}
+	scheme := target.Scheme
+	if scheme == "" {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s", scheme, strings.TrimRight(target.URLHost(), "/"))
+}
@@ -0,0 +1,193 @@
+package tech
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMurmur3(t *testing.T) {
+	assert.Equal(t, uint32(0), murmur3(nil))
+	assert.Equal(t, uint32(0x2e4ff723), murmur3([]byte("The quick brown fox jumps over the lazy dog")))
+	assert.Equal(t, int32(-156908512), int32(murmur3([]byte("foo"))))
+}
+
+func TestFaviconHash_LineBreaks(t *testing.T) {
+	// 60 bytes encode to 80 characters, one line break in.
+	icon := make([]byte, 60)
+	encoded := "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA\nAAAA\n"
+	assert.Equal(t, int32(murmur3([]byte(encoded))), FaviconHash(icon))
+}
+
+func TestLoadFavicons(t *testing.T) {
+	favicons, err := LoadFavicons("")
+	require.NoError(t, err)
+	assert.Equal(t, "Jenkins", favicons[81586312])
+
+	path := filepath.Join(t.TempDir(), "favicons.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"81586312": "Jenkins LTS", "-42": "Internal portal"}`), 0o644))
+	favicons, err = LoadFavicons(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Jenkins LTS", favicons[81586312], "local entries win")
+	assert.Equal(t, "Internal portal", favicons[-42])
+	assert.Equal(t, "Apache Tomcat", favicons[-297069493], "embedded entries are kept")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"jenkins": "Jenkins"}`), 0o644))
+	_, err = LoadFavicons(path)
+	assert.Error(t, err)
+}
+
+func TestIconLinks(t *testing.T) {
+	body := `<head>
+<link rel="stylesheet" href="/style.css">
+<link href="/static/icon.png" rel="icon" type="image/png">
+<link rel='shortcut icon' href='https://cdn.example.com/fav.ico'>
+<link rel="apple-touch-icon" href="/touch.png">
+</head>`
+	assert.Equal(t, []string{
+		"http://example.com/static/icon.png",
+		"https://cdn.example.com/fav.ico",
+	}, IconLinks("http://example.com/app/", body))
+}
+
+func TestScanner_IdentifiesFavicon(t *testing.T) {
+	icon := []byte("\x00\x00\x01\x00known icon")
+	other := []byte("\x00\x00\x01\x00other icon")
+	favicons := filepath.Join(t.TempDir(), "favicons.json")
+	require.NoError(t, os.WriteFile(favicons, []byte(`{"`+itoa(FaviconHash(icon))+`": "Example CMS"}`), 0o644))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><link rel="icon" href="/assets/cms.ico"></head></html>`))
+	})
+	mux.HandleFunc("/assets/cms.ico", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/x-icon")
+		w.Write(icon)
+	})
+	mux.HandleFunc("/admin/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html>no icon declared</html>`))
+	})
+	mux.HandleFunc("/favicon.ico", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/x-icon")
+		w.Write(other)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	opts := scanner.Options{
+		Timeout:   2 * time.Second,
+		ExtraArgs: map[string]interface{}{"favicons": favicons},
+		Endpoints: []types.Endpoint{{Method: http.MethodGet, URL: srv.URL + "/admin/"}},
+	}
+	result, err := New().Run(context.Background(), types.Target{Host: "localhost", URL: srv.URL + "/"}, opts)
+	require.NoError(t, err)
+	require.Len(t, result.Findings, 2)
+
+	known := result.Findings[0]
+	assert.Equal(t, "Technology identified: Example CMS", known.Title)
+	assert.Equal(t, "Example CMS", known.Metadata["technology"])
+	assert.Equal(t, srv.URL+"/assets/cms.ico", known.Metadata["favicon_url"])
+
+	unknown := result.Findings[1]
+	assert.Equal(t, srv.URL+"/favicon.ico", unknown.Metadata["favicon_url"], "pages without icon links fall back to /favicon.ico")
+	assert.Equal(t, itoa(FaviconHash(other)), unknown.Metadata["favicon_hash"])
+	assert.Contains(t, unknown.Evidence, "http.favicon.hash:")
+}
+
+func TestScanner_SkipsHTMLErrorPages(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html>Welcome</html>"))
+	}))
+	defer srv.Close()
+
+	result, err := New().Run(context.Background(), types.Target{Host: "localhost", URL: srv.URL}, scanner.Options{Timeout: 2 * time.Second})
+	require.NoError(t, err)
+	assert.Empty(t, result.Findings)
+}
+
+func TestScanner_ChecksComponentVersions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "Apache/2.4.49 (Unix)")
+		w.Header().Set("X-Powered-By", "PHP/8.3.8")
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head>
+<script src="/js/jquery-1.12.4.min.js"></script>
+<script src="/js/jquery-1.12.4.min.js"></script>
+<script src="https://cdn.jsdelivr.net/npm/lodash@4.17.21/lodash.min.js"></script>
+</head></html>`))
+	}))
+	defer srv.Close()
+
+	result, err := New().Run(context.Background(), types.Target{Host: "localhost", URL: srv.URL}, scanner.Options{Timeout: 2 * time.Second})
+	require.NoError(t, err)
+	byTitle := map[string]types.Finding{}
+	for _, f := range result.Findings {
+		byTitle[f.Title] = f
+	}
+	require.Len(t, byTitle, len(result.Findings), "each component is reported once")
+
+	jquery, ok := byTitle["Vulnerable JavaScript library: jQuery 1.12.4"]
+	require.True(t, ok)
+	assert.Equal(t, types.SeverityMedium, jquery.Severity)
+	assert.Equal(t, srv.URL+"/js/jquery-1.12.4.min.js", jquery.Metadata["script_url"])
+	assert.Equal(t, "CVE-2015-9251,CVE-2019-11358,CVE-2020-11022,CVE-2020-11023", jquery.Metadata["cves"])
+	assert.Equal(t, "Upgrade jQuery to 3.5.0 or later", jquery.Remediation)
+	assert.Equal(t, "https://nvd.nist.gov/vuln/detail/CVE-2015-9251", jquery.References[0].URL)
+
+	apache, ok := byTitle["Vulnerable server software: Apache httpd 2.4.49"]
+	require.True(t, ok)
+	assert.Equal(t, types.SeverityCritical, apache.Severity)
+	assert.Equal(t, "Server", apache.Metadata["header"])
+	assert.Equal(t, "Server: Apache/2.4.49 (Unix)", apache.Evidence)
+	assert.Equal(t, "Upgrade Apache httpd to 2.4.56 or later", apache.Remediation)
+
+	lodash, ok := byTitle["Technology identified: Lodash 4.17.21"]
+	require.True(t, ok, "components without known vulnerabilities are identified")
+	assert.Equal(t, types.SeverityInfo, lodash.Severity)
+	assert.Contains(t, byTitle, "Technology identified: PHP 8.3.8")
+}
+
+func TestScanner_LocalComponentData(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "Acme/1.2")
+		w.Write([]byte(`<script src="/widgets-0.9.js"></script>`))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	jsVulns := filepath.Join(dir, "js-vulns.json")
+	cveMap := filepath.Join(dir, "cve-map.json")
+	require.NoError(t, os.WriteFile(jsVulns, []byte(`[{"name": "Widgets", "patterns": ["/widgets-(\\d+\\.\\d+)\\.js"], "vulnerabilities": [{"below": "1.0", "severity": "HIGH", "identifiers": ["GHSA-xxxx"], "summary": "XSS"}]}]`), 0o644))
+	require.NoError(t, os.WriteFile(cveMap, []byte(`[{"name": "Acme", "patterns": ["Acme/(\\d+\\.\\d+)"], "vulnerabilities": [{"below": "1.3", "severity": "LOW", "identifiers": ["CVE-2099-0001"], "summary": "banner leak"}]}]`), 0o644))
+
+	opts := scanner.Options{Timeout: 2 * time.Second, ExtraArgs: map[string]interface{}{"js_vulns": jsVulns, "cve_map": cveMap}}
+	result, err := New().Run(context.Background(), types.Target{Host: "localhost", URL: srv.URL}, opts)
+	require.NoError(t, err)
+	var titles []string
+	for _, f := range result.Findings {
+		titles = append(titles, f.Title)
+	}
+	assert.Contains(t, titles, "Vulnerable JavaScript library: Widgets 0.9")
+	assert.Contains(t, titles, "Vulnerable server software: Acme 1.2")
+
+	require.NoError(t, os.WriteFile(cveMap, []byte(`not json`), 0o644))
+	_, err = New().Run(context.Background(), types.Target{Host: "localhost", URL: srv.URL}, opts)
+	assert.ErrorContains(t, err, "loading CVE map")
+}
+
+func itoa(hash int32) string {
+	return strconv.Itoa(int(hash))
+}
@@ -0,0 +1,152 @@
+package scanner
+
+import (
+	"sync"
+	"time"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// typicalDurations are how long scanners take against an average target,
+// weighting their share of a TimeBudget when no history is known.
+var typicalDurations = map[string]time.Duration{
+	"port":             30 * time.Second,
+	"headers":          2 * time.Second,
+	"ssl":              5 * time.Second,
+	"protocols":        3 * time.Second,
+	"cloud":            3 * time.Second,
+	"dirs":             60 * time.Second,
+	"vuln":             15 * time.Second,
+	"forms":            10 * time.Second,
+	"csrf":             5 * time.Second,
+	"tech":             2 * time.Second,
+	"redirects":        3 * time.Second,
+	"mixed-content":    3 * time.Second,
+	"deserialization":  3 * time.Second,
+	"custom":           3 * time.Second,
+	"api-discover":     10 * time.Second,
+	"api-auth":         15 * time.Second,
+	"api-cors":         5 * time.Second,
+	"api-ratelimit":    10 * time.Second,
+	"api-dataexposure": 10 * time.Second,
+	"subdomain":        20 * time.Second,
+}
+
+// defaultDuration weighs scanners missing from both the history and
+// typicalDurations.
+const defaultDuration = 10 * time.Second
+
+// TimeBudget divides Options.MaxDuration between the scanners of a scan.
+// Each scanner gets a slice when it starts: the time left, shared among it
+// and the scanners yet to start in proportion to how long each usually
+// takes. Time a scanner leaves unused passes to those after it. Time spent
+// paused does not count.
+type TimeBudget struct {
+	total       time.Duration
+	durations   map[string]time.Duration
+	concurrency int
+	gate        *Gate
+	start       time.Time
+	pausedStart time.Duration
+
+	mu      sync.Mutex
+	pending map[string]time.Duration // weights of the scanners yet to start
+}
+
+// NewTimeBudget returns a budget of total for the named scanners, run
+// concurrency at a time. durations, typically from ObservedDurations, says
+// how long each scanner usually takes; missing ones use built-in estimates.
+// gate, which may be nil, is the scan's pause gate.
+func NewTimeBudget(total time.Duration, names []string, durations map[string]time.Duration, concurrency int, gate *Gate) *TimeBudget {
+	b := &TimeBudget{
+		total:       total,
+		durations:   durations,
+		concurrency: max(concurrency, 1),
+		gate:        gate,
+		start:       time.Now(),
+		pending:     make(map[string]time.Duration, len(names)),
+	}
+	if gate != nil {
+		b.pausedStart = gate.pausedFor()
+	}
+	for _, name := range names {
+		b.pending[name] = b.weight(name)
+	}
+	return b
+}
+
+func (b *TimeBudget) weight(name string) time.Duration {
+	if d := b.durations[name]; d > 0 {
+		return d
+	}
+	if d := typicalDurations[name]; d > 0 {
+		return d
+	}
+	return defaultDuration
+}
+
+// Remaining returns how much of the budget is left.
+func (b *TimeBudget) Remaining() time.Duration {
+	elapsed := time.Since(b.start)
+	if b.gate != nil {
+		elapsed -= b.gate.pausedFor() - b.pausedStart
+	}
+	return max(b.total-elapsed, 0)
+}
+
+// Slice returns how long the named scanner may run, starting now. Up to
+// concurrency scanners run at once, so the time left is multiplied by how
+// many of them will share it before being divided by weight; no slice
+// outlasts the budget.
+func (b *TimeBudget) Slice(name string) time.Duration {
+	remaining := b.Remaining()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w, ok := b.pending[name]
+	if !ok {
+		w = b.weight(name)
+	}
+	delete(b.pending, name)
+
+	total := w
+	for _, pw := range b.pending {
+		total += pw
+	}
+	parallel := min(b.concurrency, len(b.pending)+1)
+	slice := time.Duration(float64(remaining) * float64(parallel) * float64(w) / float64(total))
+	return min(slice, remaining)
+}
+
+// ObservedDurations returns the average time each scanner took in results,
+// ignoring skipped, failed, and partial runs.
+func ObservedDurations(results []types.ScanResult) map[string]time.Duration {
+	sums := map[string]time.Duration{}
+	counts := map[string]int{}
+	for _, r := range results {
+		if r.Error != "" || r.StartedAt.IsZero() || r.CompletedAt.IsZero() || r.Metadata["partial"] == "true" {
+			continue
+		}
+		if len(r.Findings) == 1 && r.Findings[0].Metadata["skipped_reason"] != "" {
+			continue
+		}
+		sums[r.ScannerName] += r.CompletedAt.Sub(r.StartedAt)
+		counts[r.ScannerName]++
+	}
+
+	durations := make(map[string]time.Duration, len(sums))
+	for name, sum := range sums {
+		durations[name] = sum / time.Duration(counts[name])
+	}
+	return durations
+}
+
+// markPartial records in result that the scanner was stopped when its slice
+// of the time budget, slice, ran out.
+func markPartial(result *types.ScanResult, slice time.Duration) {
+	if result.Metadata == nil {
+		result.Metadata = map[string]string{}
+	}
+	result.Metadata["partial"] = "true"
+	result.Metadata["time_budget"] = slice.Round(time.Millisecond).String()
+}
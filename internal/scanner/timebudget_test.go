@@ -0,0 +1,96 @@
+package scanner
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// overrunScanner finds one thing straight away, then works until ctx is done.
+// With fail set it reports the cancellation as an error instead.
+type overrunScanner struct {
+	name string
+	fail bool
+}
+
+func (s *overrunScanner) Name() string        { return s.name }
+func (s *overrunScanner) Description() string { return "slow scanner" }
+func (s *overrunScanner) Run(ctx context.Context, target types.Target, _ Options) (*types.ScanResult, error) {
+	<-ctx.Done()
+	if s.fail {
+		return nil, ctx.Err()
+	}
+	return &types.ScanResult{
+		ScannerName: s.name,
+		Target:      target,
+		Findings:    []types.Finding{{Title: "found early", Severity: types.SeverityLow}},
+	}, nil
+}
+
+func TestTimeBudget_SlicesByWeight(t *testing.T) {
+	durations := map[string]time.Duration{"quick": 10 * time.Second, "long": 30 * time.Second}
+
+	b := NewTimeBudget(40*time.Second, []string{"quick", "long"}, durations, 1, nil)
+	assert.InDelta(t, float64(10*time.Second), float64(b.Slice("quick")), float64(time.Second))
+	assert.InDelta(t, float64(40*time.Second), float64(b.Slice("long")), float64(time.Second),
+		"the last scanner gets whatever is left")
+
+	b = NewTimeBudget(40*time.Second, []string{"quick", "long"}, durations, 2, nil)
+	assert.InDelta(t, float64(20*time.Second), float64(b.Slice("quick")), float64(time.Second),
+		"scanners running side by side share the time twice over")
+	assert.InDelta(t, float64(40*time.Second), float64(b.Slice("long")), float64(time.Second))
+}
+
+func TestTimeBudget_DefaultWeights(t *testing.T) {
+	b := NewTimeBudget(62*time.Second, []string{"headers", "dirs"}, nil, 1, nil)
+	assert.InDelta(t, float64(2*time.Second), float64(b.Slice("headers")), float64(time.Second))
+}
+
+func TestObservedDurations(t *testing.T) {
+	start := time.Now()
+	results := []types.ScanResult{
+		{ScannerName: "dirs", StartedAt: start, CompletedAt: start.Add(10 * time.Second)},
+		{ScannerName: "dirs", StartedAt: start, CompletedAt: start.Add(20 * time.Second)},
+		{ScannerName: "dirs", StartedAt: start, CompletedAt: start.Add(time.Hour), Metadata: map[string]string{"partial": "true"}},
+		{ScannerName: "port", StartedAt: start, CompletedAt: start.Add(time.Second), Error: "refused"},
+		*Skipped("ssl", types.Target{}, "plain HTTP only"),
+	}
+	assert.Equal(t, map[string]time.Duration{"dirs": 15 * time.Second}, ObservedDurations(results))
+}
+
+func TestRunner_TimeBudgetMarksPartialResults(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&overrunScanner{name: "slow"})
+	reg.Register(&overrunScanner{name: "failing", fail: true})
+
+	var mu sync.Mutex
+	var logged []string
+	opts := DefaultOptions()
+	opts.Concurrency = 2
+	opts.MaxDuration = 100 * time.Millisecond
+	opts.Log = func(e LogEntry) {
+		mu.Lock()
+		logged = append(logged, e.Scanner)
+		mu.Unlock()
+	}
+
+	start := time.Now()
+	results := NewRunner(reg).RunAll(context.Background(), []string{"slow", "failing"}, types.Target{Host: "example.com"}, opts)
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	require.Len(t, results, 2)
+	for _, r := range results {
+		assert.Empty(t, r.Error, r.ScannerName)
+		assert.Equal(t, "true", r.Metadata["partial"], r.ScannerName)
+		assert.NotEmpty(t, r.Metadata["time_budget"], r.ScannerName)
+		if r.ScannerName == "slow" {
+			require.Len(t, r.Findings, 1, "what was found before the budget ran out is kept")
+		}
+	}
+	assert.ElementsMatch(t, []string{"slow", "failing"}, logged)
+}
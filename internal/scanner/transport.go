@@ -0,0 +1,366 @@
+package scanner
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LoggingTransport wraps an http.RoundTripper and reports every request it
+// sends, together with the response status and round-trip time.
+type LoggingTransport struct {
+	Base http.RoundTripper
+	Logf func(format string, args ...interface{})
+}
+
+// NewLoggingTransport returns a LoggingTransport around base. If base is nil,
+// http.DefaultTransport is used.
+func NewLoggingTransport(base http.RoundTripper, logf func(format string, args ...interface{})) *LoggingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &LoggingTransport{Base: base, Logf: logf}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.Base.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	if err != nil {
+		t.Logf("%s %s → error: %v (%s)", req.Method, req.URL, err, elapsed)
+		return nil, err
+	}
+	t.Logf("%s %s → %d (%s)", req.Method, req.URL, resp.StatusCode, elapsed)
+	return resp, nil
+}
+
+// AuthTransport wraps an http.RoundTripper and authenticates the requests
+// to Hosts that do not already carry an Authorization header. Requests to
+// other hosts, such as those redirects lead to, are passed on untouched, so
+// credentials only go where they were given for.
+type AuthTransport struct {
+	Base         http.RoundTripper
+	Authenticate func(req *http.Request)
+	// Hosts are the host names, without ports, whose requests are
+	// authenticated.
+	Hosts []string
+}
+
+// RoundTrip implements http.RoundTripper. The request is cloned before
+// credentials are added, as the RoundTripper contract requires.
+func (t *AuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if req.Header.Get("Authorization") != "" || !t.authenticates(req.URL.Hostname()) {
+		return base.RoundTrip(req)
+	}
+	authed := req.Clone(req.Context())
+	t.Authenticate(authed)
+	return base.RoundTrip(authed)
+}
+
+// authenticates reports whether requests to host get credentials.
+func (t *AuthTransport) authenticates(host string) bool {
+	for _, h := range t.Hosts {
+		if strings.EqualFold(h, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// RateLimitTransport wraps an http.RoundTripper and spaces requests so no
+// more than a fixed number are sent per second, across all goroutines.
+type RateLimitTransport struct {
+	Base     http.RoundTripper
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// NewRateLimitTransport returns a RateLimitTransport around base allowing
+// perSecond requests per second. If base is nil, http.DefaultTransport is used.
+func NewRateLimitTransport(base http.RoundTripper, perSecond float64) *RateLimitTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &RateLimitTransport{Base: base, interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+// RoundTrip implements http.RoundTripper. It waits for the request's slot,
+// giving up early if the request context is cancelled.
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.wait(req); err != nil {
+		return nil, err
+	}
+	return t.Base.RoundTrip(req)
+}
+
+// wait blocks until req's slot comes, or its context is cancelled.
+func (t *RateLimitTransport) wait(req *http.Request) error {
+	t.mu.Lock()
+	now := time.Now()
+	slot := t.next
+	if slot.Before(now) {
+		slot = now
+	}
+	t.next = slot.Add(t.interval)
+	t.mu.Unlock()
+
+	if wait := time.Until(slot); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			return req.Context().Err()
+		}
+	}
+	return nil
+}
+
+// rateLimitedTransport sends requests through base in the slots of a
+// RateLimitTransport it shares with other transports.
+type rateLimitedTransport struct {
+	limit *RateLimitTransport
+	base  http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limit.wait(req); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// HeaderTransport wraps an http.RoundTripper and adds fixed headers to every
+// request, replacing any the scanner set itself.
+type HeaderTransport struct {
+	Base    http.RoundTripper
+	Headers http.Header
+}
+
+// NewHeaderTransport returns a HeaderTransport around base. If base is nil,
+// http.DefaultTransport is used.
+func NewHeaderTransport(base http.RoundTripper, headers http.Header) *HeaderTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &HeaderTransport{Base: base, Headers: headers}
+}
+
+// RoundTrip implements http.RoundTripper. The request is cloned before the
+// headers are added, as the RoundTripper contract requires.
+func (t *HeaderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(t.Headers) == 0 {
+		return t.Base.RoundTrip(req)
+	}
+	out := req.Clone(req.Context())
+	for name, values := range t.Headers {
+		out.Header[name] = values
+	}
+	return t.Base.RoundTrip(out)
+}
+
+// MaxCachedBodySize is the largest response body CacheTransport keeps.
+const MaxCachedBodySize = 1 << 20
+
+// CacheTransport wraps an http.RoundTripper and serves repeated GET and HEAD
+// requests for the same URL with the same headers from memory, so scanners
+// that fetch the same resources (several start from the target's root) do
+// not each send the request. Concurrent identical requests wait for the
+// first. It is meant to live for one scan; responses are never expired.
+//
+// Requests with a body, or carrying Cache-Control: no-cache or no-store, are
+// always sent, as are identical requests whose earlier response failed or
+// had a body larger than MaxCachedBodySize.
+type CacheTransport struct {
+	Base http.RoundTripper
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	hits    int
+}
+
+type cacheEntry struct {
+	done chan struct{} // closed once the response is stored or given up on
+	ok   bool
+
+	status     int
+	proto      string
+	protoMajor int
+	protoMinor int
+	header     http.Header
+	body       []byte
+}
+
+// NewCacheTransport returns a CacheTransport around base. If base is nil,
+// http.DefaultTransport is used.
+func NewCacheTransport(base http.RoundTripper) *CacheTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &CacheTransport{Base: base, entries: map[string]*cacheEntry{}}
+}
+
+// Hits returns how many requests were served from the cache.
+func (t *CacheTransport) Hits() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.hits
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *CacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !cacheable(req) {
+		return t.Base.RoundTrip(req)
+	}
+	key := cacheKey(req)
+
+	t.mu.Lock()
+	entry, found := t.entries[key]
+	if !found {
+		entry = &cacheEntry{done: make(chan struct{})}
+		t.entries[key] = entry
+	}
+	t.mu.Unlock()
+
+	if found {
+		select {
+		case <-entry.done:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		if entry.ok {
+			t.mu.Lock()
+			t.hits++
+			t.mu.Unlock()
+			return entry.response(req), nil
+		}
+		return t.Base.RoundTrip(req)
+	}
+
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		t.forget(key, entry)
+		return nil, err
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxCachedBodySize+1))
+	if err != nil || len(body) > MaxCachedBodySize {
+		// Hand back what was read followed by the rest, uncached.
+		t.forget(key, entry)
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	entry.status, entry.header, entry.body = resp.StatusCode, resp.Header.Clone(), body
+	entry.proto, entry.protoMajor, entry.protoMinor = resp.Proto, resp.ProtoMajor, resp.ProtoMinor
+	entry.ok = true
+	close(entry.done)
+	return entry.response(req), nil
+}
+
+// forget removes an entry whose response will not be cached, releasing the
+// requests waiting for it to send their own.
+func (t *CacheTransport) forget(key string, entry *cacheEntry) {
+	t.mu.Lock()
+	delete(t.entries, key)
+	t.mu.Unlock()
+	close(entry.done)
+}
+
+// response returns a copy of the stored response for req.
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.status, http.StatusText(e.status)),
+		StatusCode:    e.status,
+		Proto:         e.proto,
+		ProtoMajor:    e.protoMajor,
+		ProtoMinor:    e.protoMinor,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+func cacheable(req *http.Request) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		return false
+	}
+	for _, v := range req.Header.Values("Cache-Control") {
+		if strings.Contains(v, "no-cache") || strings.Contains(v, "no-store") {
+			return false
+		}
+	}
+	return true
+}
+
+// cacheKey identifies a request by method, URL, and headers, including a
+// Host header that differs from the URL's.
+func cacheKey(req *http.Request) string {
+	var b strings.Builder
+	b.WriteString(req.Method + " " + req.URL.String() + "\n")
+	if req.Host != "" && req.Host != req.URL.Host {
+		b.WriteString("Host: " + req.Host + "\n")
+	}
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b.WriteString(name + ": " + strings.Join(req.Header[name], ", ") + "\n")
+	}
+	return b.String()
+}
+
+// ConfigureTransport returns a transport sending requests the way rt does,
+// logged, rate limited, and with the same headers and credentials, but
+// through a clone of the *http.Transport at its bottom changed by configure.
+// It is for scanners needing connection settings of their own, such as a
+// proxy or unverified certificates. The rate limit is shared with rt;
+// responses are not cached, as they may differ from rt's for the same URL.
+// A nil rt stands for http.DefaultTransport. Layers of other types are
+// kept as they are, without configure applying below them.
+func ConfigureTransport(rt http.RoundTripper, configure func(*http.Transport)) http.RoundTripper {
+	switch t := rt.(type) {
+	case nil:
+		return ConfigureTransport(http.DefaultTransport, configure)
+	case *http.Transport:
+		clone := t.Clone()
+		configure(clone)
+		return clone
+	case *LoggingTransport:
+		return &LoggingTransport{Base: ConfigureTransport(t.Base, configure), Logf: t.Logf}
+	case *AuthTransport:
+		return &AuthTransport{Base: ConfigureTransport(t.Base, configure), Authenticate: t.Authenticate, Hosts: t.Hosts}
+	case *HeaderTransport:
+		return &HeaderTransport{Base: ConfigureTransport(t.Base, configure), Headers: t.Headers}
+	case *RateLimitTransport:
+		return &rateLimitedTransport{limit: t, base: ConfigureTransport(t.Base, configure)}
+	case *rateLimitedTransport:
+		return &rateLimitedTransport{limit: t.limit, base: ConfigureTransport(t.base, configure)}
+	case *CacheTransport:
+		return ConfigureTransport(t.Base, configure)
+	}
+	return rt
+}
@@ -0,0 +1,302 @@
+package scanner
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggingTransport_LogsRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer srv.Close()
+
+	var lines []string
+	transport := NewLoggingTransport(nil, func(format string, args ...interface{}) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	})
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(srv.URL + "/path")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], "GET "+srv.URL+"/path → 418")
+}
+
+func TestLoggingTransport_LogsError(t *testing.T) {
+	var lines []string
+	transport := NewLoggingTransport(nil, func(format string, args ...interface{}) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	})
+	client := &http.Client{Transport: transport}
+
+	_, err := client.Get("http://127.0.0.1:1/")
+	require.Error(t, err)
+
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], "error")
+}
+
+func TestAuthTransport_AddsCredentials(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("Authorization"))
+	}))
+	defer srv.Close()
+
+	opts := Options{
+		Authenticate: func(req *http.Request) { req.Header.Set("Authorization", "Bearer tok") },
+		AuthHosts:    []string{"127.0.0.1"},
+	}
+	client := &http.Client{Transport: opts.HTTPTransport()}
+
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req.Header.Set("Authorization", "Bearer explicit")
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, []string{"Bearer tok", "Bearer explicit"}, got)
+	assert.Nil(t, Options{}.HTTPTransport(), "no wrapping without Authenticate")
+}
+
+func TestAuthTransport_OnlyAuthenticatesHosts(t *testing.T) {
+	var other string
+	elsewhere := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		other = r.Header.Get("Authorization")
+	}))
+	defer elsewhere.Close()
+	var target string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target = r.Header.Get("Authorization")
+		http.Redirect(w, r, strings.Replace(elsewhere.URL, "127.0.0.1", "localhost", 1), http.StatusFound)
+	}))
+	defer srv.Close()
+
+	opts := Options{
+		Authenticate: func(req *http.Request) { req.Header.Set("Authorization", "Bearer tok") },
+		AuthHosts:    []string{"127.0.0.1"},
+	}
+	resp, err := (&http.Client{Transport: opts.HTTPTransport()}).Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, "Bearer tok", target)
+	assert.Empty(t, other, "credentials must not follow a redirect to another host")
+}
+
+func TestHeaderTransport_AddsHeaders(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer srv.Close()
+
+	headers := http.Header{}
+	headers.Set("X-Api-Key", "secret")
+	headers.Set("User-Agent", "hunter-test")
+	client := &http.Client{Transport: NewHeaderTransport(nil, headers)}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req.Header.Set("User-Agent", "scanner")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, "secret", got.Get("X-Api-Key"))
+	assert.Equal(t, "hunter-test", got.Get("User-Agent"))
+	assert.Equal(t, "scanner", req.Header.Get("User-Agent"), "the original request is not modified")
+}
+
+func TestRateLimitTransport_SpacesRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: NewRateLimitTransport(nil, 20)}
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		resp, err := client.Get(srv.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	// Four requests at 20/s need at least three 50ms gaps.
+	assert.GreaterOrEqual(t, time.Since(start), 140*time.Millisecond)
+}
+
+func TestRateLimitTransport_HonoursContext(t *testing.T) {
+	tr := NewRateLimitTransport(nil, 0.5)
+	tr.next = time.Now().Add(time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://127.0.0.1:1", nil)
+	_, err := tr.RoundTrip(req)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestConfigureTransport(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	var logged []string
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	logging := NewLoggingTransport(base, func(format string, args ...interface{}) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	})
+	limit := NewRateLimitTransport(logging, 1000)
+	rt := NewCacheTransport(limit)
+
+	_, err := (&http.Client{Transport: rt}).Get(srv.URL)
+	require.Error(t, err, "the original transport verifies certificates")
+
+	insecure := ConfigureTransport(rt, func(t *http.Transport) {
+		t.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	})
+	resp, err := (&http.Client{Transport: insecure}).Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Len(t, logged, 2, "requests through the configured transport are logged")
+	assert.False(t, base.TLSClientConfig != nil && base.TLSClientConfig.InsecureSkipVerify)
+
+	// The configured transport waits on the same rate limit.
+	limit.next = time.Now().Add(time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	_, err = insecure.RoundTrip(req)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCacheTransport_ServesRepeatedRequests(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("X-Frame-Options", "DENY")
+		w.WriteHeader(http.StatusTeapot)
+		fmt.Fprint(w, "body")
+	}))
+	defer srv.Close()
+
+	cache := NewCacheTransport(nil)
+	client := &http.Client{Transport: cache}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(srv.URL + "/")
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			assert.Equal(t, http.StatusTeapot, resp.StatusCode)
+			assert.Equal(t, "DENY", resp.Header.Get("X-Frame-Options"))
+			assert.Equal(t, "body", string(body))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), requests.Load())
+	assert.Equal(t, 4, cache.Hits())
+}
+
+func TestCacheTransport_KeysOnHeadersAndMethod(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: NewCacheTransport(nil)}
+	send := func(method, origin string) {
+		req, err := http.NewRequest(method, srv.URL, nil)
+		require.NoError(t, err)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	send(http.MethodGet, "")
+	send(http.MethodGet, "https://evil.example")
+	send(http.MethodGet, "https://evil.example")
+	send(http.MethodHead, "")
+	send(http.MethodOptions, "")
+	send(http.MethodOptions, "")
+	assert.Equal(t, int32(5), requests.Load())
+}
+
+func TestCacheTransport_KeysOnHost(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: NewCacheTransport(nil)}
+	for _, host := range []string{"", "169.254.169.254", "169.254.169.254"} {
+		req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+		require.NoError(t, err)
+		if host != "" {
+			req.Host = host
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestCacheTransport_Bypass(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path == "/large" {
+			w.Write(make([]byte, MaxCachedBodySize+1))
+		}
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: NewCacheTransport(nil)}
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+		require.NoError(t, err)
+		req.Header.Set("Cache-Control", "no-cache")
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	assert.Equal(t, int32(2), requests.Load())
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(srv.URL + "/large")
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Len(t, body, MaxCachedBodySize+1)
+	}
+	assert.Equal(t, int32(4), requests.Load())
+}
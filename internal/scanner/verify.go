@@ -0,0 +1,30 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// Verifier is implemented by scanners that can replay the probe behind one
+// of their findings, to check whether it still reproduces after a fix.
+type Verifier interface {
+	// Verify re-issues only the probe that produced finding against target
+	// and reports whether the finding still reproduces.
+	Verify(ctx context.Context, target types.Target, finding types.Finding, opts Options) (bool, error)
+}
+
+// Verify replays the probe behind a finding made by the named scanner. It
+// fails if the scanner is unknown, disabled, or cannot verify its findings.
+func (r *Runner) Verify(ctx context.Context, name string, target types.Target, finding types.Finding, opts Options) (bool, error) {
+	s, err := r.get(name, opts)
+	if err != nil {
+		return false, err
+	}
+	v, ok := s.(Verifier)
+	if !ok {
+		return false, fmt.Errorf("scanner %q cannot verify its findings", name)
+	}
+	return v.Verify(ctx, target, finding, r.withHooks(opts.ForScanner(name)))
+}
@@ -0,0 +1,45 @@
+package scanner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// verifyingScanner reports findings whose title is in fixed as no longer
+// reproducing.
+type verifyingScanner struct {
+	mockScanner
+	fixed map[string]bool
+}
+
+func (v *verifyingScanner) Verify(_ context.Context, _ types.Target, finding types.Finding, _ Options) (bool, error) {
+	return !v.fixed[finding.Title], nil
+}
+
+func TestRunner_Verify(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&verifyingScanner{mockScanner: mockScanner{name: "v"}, fixed: map[string]bool{"gone": true}})
+	reg.Register(&mockScanner{name: "plain"})
+	runner := NewRunner(reg)
+	target := types.Target{Host: "localhost"}
+
+	reproduces, err := runner.Verify(context.Background(), "v", target, types.Finding{Title: "still here"}, DefaultOptions())
+	require.NoError(t, err)
+	assert.True(t, reproduces)
+
+	reproduces, err = runner.Verify(context.Background(), "v", target, types.Finding{Title: "gone"}, DefaultOptions())
+	require.NoError(t, err)
+	assert.False(t, reproduces)
+
+	_, err = runner.Verify(context.Background(), "plain", target, types.Finding{}, DefaultOptions())
+	assert.ErrorContains(t, err, "cannot verify")
+
+	opts := DefaultOptions()
+	opts.Disabled = map[string]string{"v": "prod policy"}
+	_, err = runner.Verify(context.Background(), "v", target, types.Finding{}, opts)
+	assert.ErrorContains(t, err, "disabled")
+}
@@ -0,0 +1,202 @@
+package vuln
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/internal/crawl"
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// breachParam is the query parameter the BREACH check adds to pages to see
+// whether they reflect their query string, as many do in canonical links,
+// form actions, and pagination.
+const breachParam = "hunter_breach"
+
+// acceptCompression is the Accept-Encoding the BREACH check sends.
+const acceptCompression = "gzip, deflate, br"
+
+// pageSecret matches secrets pages carry outside their forms: anti-CSRF
+// meta tags, and token fields of inline JSON.
+var pageSecret = regexp.MustCompile(`(?i)<meta[^>]+name=["']?((?:csrf|xsrf)[-_]?token)["']?[^>]*>|"(access_?token|auth_?token|(?:csrf|xsrf)_?token|api_?key|session_?id|secret)"\s*:\s*"[^"]{16,}"`)
+
+// CheckBREACH looks for HTTPS responses open to BREACH: compressed, showing
+// input from the request, and carrying a secret such as an anti-CSRF token.
+// An attacker who can make a victim's browser send requests, and see the
+// size of the encrypted responses, then learns the secret a character at a
+// time from how much better guesses of it compress. Each point of the
+// target URL and the scan's requests is sent a canary asking for a
+// compressed response, as is each page with breachParam added; a request
+// is reported once.
+func CheckBREACH(ctx context.Context, target types.Target, opts scanner.Options) []types.Finding {
+	var findings []types.Finding
+	reported := map[string]bool{}
+	for _, point := range breachPoints(target, opts) {
+		key := requestLine(point.request)
+		if reported[key] {
+			continue
+		}
+		if ctx.Err() != nil {
+			return findings
+		}
+		f, ok := breachProbe(ctx, point, newCanary(point), opts)
+		if ok {
+			reported[key] = true
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// breachPoints returns the points of the HTTPS requests of injectionPoints
+// the canary is sent to, other than headers, followed by breachParam on
+// each of their pages.
+func breachPoints(target types.Target, opts scanner.Options) []injectionPoint {
+	var points, added []injectionPoint
+	seen := map[string]bool{}
+	for _, point := range injectionPoints(target, opts) {
+		if point.location == locationHeader || !strings.HasPrefix(point.request.URL, "https://") {
+			continue
+		}
+		points = append(points, point)
+	}
+	pages := append([]types.Endpoint{{Method: http.MethodGet, URL: target.URL}}, opts.EndpointsOn(target.URL)...)
+	for _, ep := range pages {
+		if ep.Method != http.MethodGet || !strings.HasPrefix(ep.URL, "https://") || seen[ep.URL] {
+			continue
+		}
+		seen[ep.URL] = true
+		added = append(added, injectionPoint{request: ep, location: locationQuery, name: breachParam})
+	}
+	return append(points, added...)
+}
+
+// breachProbe sends point's request with canary in it, asking for a
+// compressed response, and reports it if the response is compressed,
+// reflects the canary, and carries a secret.
+func breachProbe(ctx context.Context, point injectionPoint, canary string, opts scanner.Options) (types.Finding, bool) {
+	sent := point.with(canary)
+	resp, encoding, err := sendCompressed(ctx, sent, opts)
+	if err != nil || encoding == "" {
+		return types.Finding{}, false
+	}
+	if !strings.Contains(resp.body, canary) && !strings.Contains(resp.body, html.EscapeString(canary)) {
+		return types.Finding{}, false
+	}
+	secret, ok := findSecret(sent.URL, resp.body)
+	if !ok {
+		return types.Finding{}, false
+	}
+
+	return types.Finding{
+		Title:       "Potential BREACH exposure",
+		Description: fmt.Sprintf("The HTTPS response to %s is compressed with %s, shows the value of %s, and carries a secret, the %s. An attacker who can make a victim's browser send this request with values of their choosing, and see the size of the encrypted responses, can recover the secret a character at a time from how much better correct guesses compress (BREACH).", requestLine(sent), encoding, point, secret),
+		Severity:    types.SeverityMedium,
+		Evidence:    fmt.Sprintf("%s with Accept-Encoding: %s → Content-Encoding: %s; reflected %q; %s", requestLine(sent), acceptCompression, encoding, canary, secret),
+		Remediation: "Mask secrets differently in every response, e.g. by XORing anti-CSRF tokens with a random value as Rails and Django do, keep secrets out of responses that show request input, or disable HTTP compression on those responses. SameSite cookies and rate limiting make the attack harder.",
+		References: []types.Reference{
+			{Title: "BREACH: SSL, gone in 30 seconds", URL: "https://www.breachattack.com/"},
+		},
+		Metadata: point.metadata(map[string]string{
+			"check":    "breach",
+			"payload":  canary,
+			"encoding": encoding,
+			"secret":   secret,
+		}, sent),
+		Artifacts: []types.Artifact{resp.artifact},
+	}, true
+}
+
+// findSecret describes the first secret in a page: a form's anti-CSRF
+// token, or a match of pageSecret.
+func findSecret(pageURL, body string) (string, bool) {
+	for _, form := range crawl.ParseForms(pageURL, body) {
+		if token, ok := form.CSRFToken(); ok && len(token.Value) >= 8 {
+			return fmt.Sprintf("anti-CSRF token in form field %q", token.Name), true
+		}
+	}
+	if m := pageSecret.FindStringSubmatch(body); m != nil {
+		if m[1] != "" {
+			return fmt.Sprintf("anti-CSRF token in the %s meta tag", m[1]), true
+		}
+		return fmt.Sprintf("token in JSON field %q", m[2]), true
+	}
+	return "", false
+}
+
+// sendCompressed sends ep asking for a compressed response, and returns the
+// response with its body decompressed and the encoding it was compressed
+// with, "" if it was not. Bodies in encodings the standard library cannot
+// decode, such as br, are fetched again in gzip.
+func sendCompressed(ctx context.Context, ep types.Endpoint, opts scanner.Options) (*response, string, error) {
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	client := &http.Client{Timeout: timeout, Transport: opts.HTTPTransport()}
+
+	req, err := newRequest(ctx, ep)
+	if err != nil {
+		return nil, "", err
+	}
+	// Setting Accept-Encoding keeps the transport from decompressing the
+	// body itself and dropping Content-Encoding.
+	req.Header.Set("Accept-Encoding", acceptCompression)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	var body io.Reader
+	switch encoding {
+	case "", "identity":
+		encoding, body = "", resp.Body
+	case "gzip", "x-gzip":
+		zr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, "", err
+		}
+		body = zr
+	case "deflate":
+		raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if err != nil {
+			return nil, "", err
+		}
+		// Servers send deflate both with and without the zlib wrapper.
+		if zr, err := zlib.NewReader(bytes.NewReader(raw)); err == nil {
+			body = zr
+		} else {
+			body = flate.NewReader(bytes.NewReader(raw))
+		}
+	default:
+		plain, err := send(ctx, ep, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		return plain, encoding, nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, 1<<20))
+	if err != nil {
+		return nil, "", err
+	}
+	return &response{
+		status:      resp.StatusCode,
+		contentType: resp.Header.Get("Content-Type"),
+		body:        string(data),
+		artifact:    scanner.NewArtifact(req, requestBody(ep), resp, data),
+	}, encoding, nil
+}
@@ -0,0 +1,103 @@
+package vuln
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// breachServer starts an HTTPS server answering with page, compressed with
+// encoding when the client accepts it, and returns options trusting it.
+func breachServer(t *testing.T, encoding string, page func(r *http.Request) string) (*httptest.Server, scanner.Options) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		var out io.Writer = w
+		if encoding != "" && strings.Contains(r.Header.Get("Accept-Encoding"), encoding) {
+			w.Header().Set("Content-Encoding", encoding)
+			var zw io.WriteCloser = gzip.NewWriter(w)
+			if encoding == "deflate" {
+				zw = zlib.NewWriter(w)
+			}
+			defer zw.Close()
+			out = zw
+		}
+		fmt.Fprint(out, page(r))
+	}))
+	t.Cleanup(srv.Close)
+	opts := scanner.DefaultOptions()
+	opts.Transport = srv.Client().Transport
+	return srv, opts
+}
+
+func searchPage(r *http.Request) string {
+	return fmt.Sprintf(`<p>Results for %s</p><form method="post" action="/cart"><input type="hidden" name="csrf_token" value="c2VjcmV0LXRva2VuLXZhbHVl"></form>`, html.EscapeString(r.URL.Query().Get("q")))
+}
+
+func TestCheckBREACH(t *testing.T) {
+	for _, encoding := range []string{"gzip", "deflate"} {
+		srv, opts := breachServer(t, encoding, searchPage)
+		target := types.Target{URL: srv.URL + "/search?q=shoes"}
+
+		findings := CheckBREACH(context.Background(), target, opts)
+		require.Len(t, findings, 1, encoding)
+		f := findings[0]
+		assert.Equal(t, "Potential BREACH exposure", f.Title)
+		assert.Equal(t, types.SeverityMedium, f.Severity)
+		assert.Equal(t, "q", f.Metadata["param"])
+		assert.Equal(t, encoding, f.Metadata["encoding"])
+		assert.Equal(t, `anti-CSRF token in form field "csrf_token"`, f.Metadata["secret"])
+		assert.Contains(t, f.Metadata["url"], f.Metadata["payload"])
+
+		ok, err := New().Verify(context.Background(), target, f, opts)
+		require.NoError(t, err)
+		assert.True(t, ok, encoding)
+	}
+}
+
+func TestCheckBREACH_ReflectedQueryString(t *testing.T) {
+	srv, opts := breachServer(t, "gzip", func(r *http.Request) string {
+		return fmt.Sprintf(`<link rel="canonical" href="%s"><script>window.app = {"csrfToken": "x", "access_token": "eyJhbGciOiJIUzI1NiJ9.e30"}</script>`, html.EscapeString(r.URL.String()))
+	})
+
+	findings := CheckBREACH(context.Background(), types.Target{URL: srv.URL + "/"}, opts)
+	require.Len(t, findings, 1)
+	assert.Equal(t, breachParam, findings[0].Metadata["param"])
+	assert.Equal(t, `token in JSON field "access_token"`, findings[0].Metadata["secret"])
+}
+
+func TestCheckBREACH_NotExposed(t *testing.T) {
+	cases := map[string]struct {
+		encoding string
+		page     func(r *http.Request) string
+	}{
+		"uncompressed": {"", searchPage},
+		"no reflection": {"gzip", func(r *http.Request) string {
+			return `<form><input type="hidden" name="csrf_token" value="c2VjcmV0LXRva2VuLXZhbHVl"></form>`
+		}},
+		"no secret": {"gzip", func(r *http.Request) string {
+			return "<p>Results for " + html.EscapeString(r.URL.Query().Get("q")) + "</p>"
+		}},
+	}
+	for name, c := range cases {
+		srv, opts := breachServer(t, c.encoding, c.page)
+		assert.Empty(t, CheckBREACH(context.Background(), types.Target{URL: srv.URL + "/search?q=shoes"}, opts), name)
+	}
+
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("plain HTTP targets are not sent BREACH probes")
+	}))
+	defer plain.Close()
+	assert.Empty(t, CheckBREACH(context.Background(), types.Target{URL: plain.URL + "/search?q=shoes"}, scanner.DefaultOptions()))
+}
@@ -0,0 +1,120 @@
+package vuln
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/buemura/hunter/internal/browser"
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// domPayloads are DOM XSS test vectors put in the target URL's fragment,
+// the most telling first. The fragment never reaches the server, so only a
+// browser running the page's own scripts sees them. {mark} is script that
+// tags the page with the canary when it runs, so a payload that ran is told
+// apart from one merely written into the page.
+var domPayloads = []string{
+	`<img src=x onerror={mark}>`,
+	`'-{mark}-'`,
+	`javascript:{mark}`,
+	`<svg onload={mark}>`,
+	`"-{mark}-"`,
+}
+
+// domMarker is the attribute {mark} sets on the root element.
+const domMarker = "data-hunter"
+
+// CheckDOMXSS tests for DOM-based cross-site scripting by loading the
+// target page in a headless Chrome or Chromium, the "browser" argument, with
+// payloads in its URL fragment, and reporting those that run. It is skipped
+// without a browser, and for pages that are not HTML.
+func CheckDOMXSS(ctx context.Context, target types.Target, opts scanner.Options) []types.Finding {
+	chrome, err := browser.Find(opts.StringArg("browser"))
+	if err != nil {
+		opts.Logf("vuln", scanner.LogInfo, "dom-xss: skipped: %v", err)
+		return nil
+	}
+
+	page := types.Endpoint{Method: http.MethodGet, URL: withFragment(target.URL, "")}
+	resp, err := send(ctx, page, opts)
+	if err != nil {
+		return nil
+	}
+	if !isHTML(resp.contentType) {
+		opts.Logf("vuln", scanner.LogInfo, "dom-xss: skipped %s, a %s response", page.URL, resp.contentType)
+		return nil
+	}
+
+	point := injectionPoint{request: page, location: locationFragment}
+	canary := newCanary(point)
+
+	var findings []types.Finding
+	for _, template := range scanner.Limit(domPayloads, opts.Budget("vuln.payloads")) {
+		if ctx.Err() != nil {
+			return findings
+		}
+
+		payload := domPayload(template, canary)
+		sent := point.with(payload)
+		dom, err := renderDOM(ctx, chrome, sent.URL, opts.Timeout)
+		if err != nil {
+			opts.Logf("vuln", scanner.LogWarn, "dom-xss: %s: %v", chrome, err)
+			return findings
+		}
+		if !ranIn(dom, canary) {
+			continue
+		}
+
+		findings = append(findings, types.Finding{
+			Title:       "Potential DOM-based XSS",
+			Description: "The page's scripts write the URL fragment into the document or run it as code, which may allow cross-site scripting attacks. The fragment is never sent to the server, so server-side filtering cannot stop it.",
+			Severity:    types.SeverityHigh,
+			Evidence:    fmt.Sprintf("Payload %q in the URL fragment ran when a headless browser loaded %s", payload, sent.URL),
+			Remediation: "Treat location.hash and other URL parts as untrusted: write them with textContent rather than innerHTML or document.write, and never pass them to eval, setTimeout, or location.",
+			Metadata: point.metadata(map[string]string{
+				"check":      "dom-xss",
+				"payload":    payload,
+				"canary":     canary,
+				"confidence": types.ConfidenceCertain,
+			}, sent),
+		})
+	}
+
+	return findings
+}
+
+// domPayload returns template with its mark filled in for canary.
+func domPayload(template, canary string) string {
+	mark := fmt.Sprintf("document.documentElement.setAttribute('%s','%s')", domMarker, canary)
+	return strings.ReplaceAll(template, "{mark}", mark)
+}
+
+// ranIn reports whether a payload marking the page with canary ran in dom,
+// the page as the browser left it.
+func ranIn(dom, canary string) bool {
+	return strings.Contains(dom, domMarker+`="`+canary+`"`)
+}
+
+// withFragment returns rawURL with its fragment replaced by fragment, or
+// removed when fragment is empty.
+func withFragment(rawURL, fragment string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	u.Fragment, u.RawFragment = "", ""
+	if fragment == "" {
+		return u.String()
+	}
+	// Left unescaped, as a link an attacker sends would be.
+	return u.String() + "#" + fragment
+}
+
+// renderDOM loads pageURL in browser, headless, lets its scripts run, and
+// returns the document as they left it. Extracted as a variable for
+// testing.
+var renderDOM = browser.DumpDOM
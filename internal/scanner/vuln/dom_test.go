@@ -0,0 +1,127 @@
+package vuln
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBrowser stands in for renderDOM with a page whose script writes the
+// URL fragment into the document with innerHTML: markup in it runs, script
+// in a string does not.
+func fakeBrowser(t *testing.T) *[]string {
+	t.Helper()
+	orig := renderDOM
+	t.Cleanup(func() { renderDOM = orig })
+
+	var loaded []string
+	renderDOM = func(_ context.Context, _, pageURL string, _ time.Duration) (string, error) {
+		loaded = append(loaded, pageURL)
+		_, fragment, _ := strings.Cut(pageURL, "#")
+		root := "<html>"
+		if strings.HasPrefix(fragment, "<img") {
+			if _, canary, ok := strings.Cut(fragment, "'data-hunter','"); ok {
+				root = fmt.Sprintf(`<html data-hunter="%s">`, strings.SplitN(canary, "'", 2)[0])
+			}
+		}
+		return root + "<body><div id=\"out\"></div></body></html>", nil
+	}
+	return &loaded
+}
+
+func htmlServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><body><div id="out"></div><script>out.innerHTML = decodeURIComponent(location.hash.slice(1))</script></body></html>`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCheckDOMXSS_DetectsFragmentSink(t *testing.T) {
+	loaded := fakeBrowser(t)
+	srv := htmlServer(t)
+
+	target := types.Target{URL: srv.URL + "/#section", Host: "127.0.0.1", Scheme: "http"}
+	opts := scanner.DefaultOptions()
+	opts.ExtraArgs = map[string]interface{}{"browser": "sh"}
+	findings := CheckDOMXSS(context.Background(), target, opts)
+
+	require.Len(t, findings, 1)
+	f := findings[0]
+	assert.Equal(t, "Potential DOM-based XSS", f.Title)
+	assert.Equal(t, types.SeverityHigh, f.Severity)
+	assert.Equal(t, "dom-xss", f.Metadata["check"])
+	assert.Equal(t, "fragment", f.Metadata["param_in"])
+	assert.Equal(t, types.ConfidenceCertain, f.Metadata["confidence"])
+	assert.True(t, strings.HasPrefix(f.Metadata["payload"], "<img"))
+	assert.Equal(t, srv.URL+"/#"+f.Metadata["payload"], f.Metadata["url"])
+	assert.Len(t, *loaded, 4, "normal intensity loads the page once per payload in budget")
+
+	reproduces, err := New().Verify(context.Background(), target, f, opts)
+	require.NoError(t, err)
+	assert.True(t, reproduces)
+}
+
+func TestCheckDOMXSS_SkippedWithoutBrowser(t *testing.T) {
+	loaded := fakeBrowser(t)
+	srv := htmlServer(t)
+
+	var logged []string
+	target := types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}
+	opts := scanner.DefaultOptions()
+	opts.Log = func(e scanner.LogEntry) { logged = append(logged, e.Message) }
+	findings := CheckDOMXSS(context.Background(), target, opts)
+
+	assert.Empty(t, findings)
+	assert.Empty(t, *loaded)
+	require.Len(t, logged, 1)
+	assert.Contains(t, logged[0], "--browser")
+}
+
+func TestCheckDOMXSS_SkipsNonHTML(t *testing.T) {
+	loaded := fakeBrowser(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"ok": true}`)
+	}))
+	defer srv.Close()
+
+	target := types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}
+	opts := scanner.DefaultOptions()
+	opts.ExtraArgs = map[string]interface{}{"browser": "sh"}
+	findings := CheckDOMXSS(context.Background(), target, opts)
+
+	assert.Empty(t, findings)
+	assert.Empty(t, *loaded)
+}
+
+func TestDOMPayload_MarksCanary(t *testing.T) {
+	for _, template := range domPayloads {
+		payload := domPayload(template, "hntabc")
+		assert.Contains(t, payload, "setAttribute('data-hunter','hntabc')")
+		assert.NotContains(t, payload, "{mark}")
+	}
+	assert.True(t, ranIn(`<html lang="en" data-hunter="hntabc"><head>`, "hntabc"))
+	assert.False(t, ranIn(`<div>setAttribute('data-hunter','hntabc')</div>`, "hntabc"), "a payload written as text did not run")
+}
+
+func TestWithFragment(t *testing.T) {
+	assert.Equal(t, "http://example.com/a?b=1#<svg onload=x>", withFragment("http://example.com/a?b=1#top", "<svg onload=x>"))
+	assert.Equal(t, "http://example.com/a", withFragment("http://example.com/a#top", ""))
+
+	u, err := url.Parse(withFragment("http://example.com/", "'-x-'"))
+	require.NoError(t, err)
+	assert.Equal(t, "'-x-'", u.Fragment)
+}
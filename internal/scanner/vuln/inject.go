@@ -0,0 +1,318 @@
+package vuln
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// Where an injection point's value is.
+const (
+	locationQuery  = "query"
+	locationForm   = "form"
+	locationJSON   = "json"
+	locationHeader = "header"
+	// locationFragment is the URL fragment, which only the DOM XSS check
+	// injects into.
+	locationFragment = "fragment"
+)
+
+// injectedHeaders are request headers applications commonly log, echo, or
+// store, and so put into pages and queries: the name and the value sent
+// when not injecting into it, {url} standing for the target URL.
+var injectedHeaders = [][2]string{
+	{"User-Agent", "Mozilla/5.0 (compatible; hunter)"},
+	{"Referer", "{url}"},
+	{"X-Forwarded-For", "203.0.113.10"},
+}
+
+// injectionPoint is one value of a request the checks put their payloads
+// in: a query parameter, a field of a form-encoded body, a field of a JSON
+// body, or a header.
+type injectionPoint struct {
+	request  types.Endpoint
+	location string
+	// name is the parameter, field, or header name; JSON fields are named
+	// by their path, such as "user.name" or "items.0.id".
+	name  string
+	value string
+}
+
+// String describes the point for finding descriptions.
+func (p injectionPoint) String() string {
+	switch p.location {
+	case locationForm:
+		return fmt.Sprintf("form field %q", p.name)
+	case locationJSON:
+		return fmt.Sprintf("JSON field %q", p.name)
+	case locationHeader:
+		return fmt.Sprintf("header %q", p.name)
+	case locationFragment:
+		return "the URL fragment"
+	}
+	return fmt.Sprintf("parameter %q", p.name)
+}
+
+// with returns the point's request with its value replaced by value.
+func (p injectionPoint) with(value string) types.Endpoint {
+	ep := p.request
+	switch p.location {
+	case locationQuery:
+		ep.URL = replaceQueryParam(ep.URL, p.name, value)
+	case locationForm:
+		form, _ := url.ParseQuery(ep.Body)
+		form.Set(p.name, value)
+		ep.Body = form.Encode()
+	case locationJSON:
+		var doc any
+		if json.Unmarshal([]byte(ep.Body), &doc) == nil {
+			if body, err := json.Marshal(setJSONField(doc, strings.Split(p.name, "."), value)); err == nil {
+				ep.Body = string(body)
+			}
+		}
+	case locationHeader:
+		ep.Headers = maps.Clone(ep.Headers)
+		if ep.Headers == nil {
+			ep.Headers = map[string]string{}
+		}
+		ep.Headers[p.name] = value
+	case locationFragment:
+		ep.URL = withFragment(ep.URL, value)
+	}
+	return ep
+}
+
+// metadata records where the point is in a finding's metadata, so Verify
+// can rebuild it. sent is the request carrying the payload. Query points
+// record only the URL, as findings always have, and fragment points are in
+// it; header points record the header name, their value being the finding's
+// payload.
+func (p injectionPoint) metadata(m map[string]string, sent types.Endpoint) map[string]string {
+	m["param"] = p.name
+	m["url"] = sent.URL
+	switch p.location {
+	case locationQuery:
+	case locationFragment:
+		m["param_in"] = p.location
+	case locationHeader:
+		m["param_in"] = p.location
+		m["header"] = p.name
+		m["method"] = sent.Method
+	default:
+		m["param_in"] = p.location
+		m["method"] = sent.Method
+		m["body"] = sent.Body
+		m["content_type"] = header(sent.Headers, "Content-Type")
+	}
+	return m
+}
+
+// findingPoint rebuilds the injection point a finding's probe was sent to,
+// its value already replaced by the payload.
+func findingPoint(f types.Finding) injectionPoint {
+	p := injectionPoint{
+		request:  types.Endpoint{Method: http.MethodGet, URL: f.Metadata["url"]},
+		location: locationQuery,
+		name:     f.Metadata["param"],
+	}
+	if location := f.Metadata["param_in"]; location != "" {
+		p.location = location
+		p.request.Method = f.Metadata["method"]
+		p.request.Body = f.Metadata["body"]
+		if ct := f.Metadata["content_type"]; ct != "" {
+			p.request.Headers = map[string]string{"Content-Type": ct}
+		}
+		if location == locationHeader {
+			p.request.Headers = map[string]string{p.name: f.Metadata["payload"]}
+		}
+	}
+	return p
+}
+
+// injectionPoints returns the points the checks inject into: the query
+// parameters of the target URL; the fields of the "data" argument, a body
+// POSTed to the target URL; and the query parameters and body fields of the
+// imported endpoints (Options.Endpoints) on the target's host.
+func injectionPoints(target types.Target, opts scanner.Options) []injectionPoint {
+	points := requestPoints(types.Endpoint{Method: http.MethodGet, URL: target.URL})
+
+	if data := opts.StringArg("data"); data != "" {
+		contentType := "application/x-www-form-urlencoded"
+		if isJSONBody(data) {
+			contentType = "application/json"
+		}
+		points = append(points, requestPoints(types.Endpoint{
+			Method:  http.MethodPost,
+			URL:     target.URL,
+			Headers: map[string]string{"Content-Type": contentType},
+			Body:    data,
+		})...)
+	}
+
+	for _, ep := range opts.EndpointsOn(target.URL) {
+		points = append(points, requestPoints(ep)...)
+	}
+	return points
+}
+
+// InjectEndpoints runs the reflected XSS and SQL injection checks against
+// the query parameters and body fields of endpoints alone, for scanners that
+// find requests of their own to test, such as the forms scanner.
+func InjectEndpoints(ctx context.Context, endpoints []types.Endpoint, opts scanner.Options) []types.Finding {
+	var points []injectionPoint
+	for _, ep := range endpoints {
+		points = append(points, requestPoints(ep)...)
+	}
+	findings := checkXSS(ctx, points, opts)
+	return append(findings, checkSQLi(ctx, points, opts)...)
+}
+
+// headerPoints returns the injectedHeaders of a GET request to the target
+// URL as injection points, for the checks whose payloads headers commonly
+// carry into pages and queries.
+func headerPoints(target types.Target) []injectionPoint {
+	points := make([]injectionPoint, 0, len(injectedHeaders))
+	for _, h := range injectedHeaders {
+		value := strings.ReplaceAll(h[1], "{url}", target.URL)
+		points = append(points, injectionPoint{
+			request:  types.Endpoint{Method: http.MethodGet, URL: target.URL, Headers: map[string]string{h[0]: value}},
+			location: locationHeader,
+			name:     h[0],
+			value:    value,
+		})
+	}
+	return points
+}
+
+// requestPoints returns the injection points of ep: its query parameters
+// and, for form-encoded and JSON bodies, its body fields.
+func requestPoints(ep types.Endpoint) []injectionPoint {
+	var points []injectionPoint
+	if u, err := url.Parse(ep.URL); err == nil {
+		query := u.Query()
+		for _, name := range sortedKeys(query) {
+			points = append(points, injectionPoint{request: ep, location: locationQuery, name: name, value: query.Get(name)})
+		}
+	}
+	if ep.Body == "" {
+		return points
+	}
+
+	contentType := strings.ToLower(header(ep.Headers, "Content-Type"))
+	switch {
+	case strings.Contains(contentType, "json") || (contentType == "" && isJSONBody(ep.Body)):
+		var doc any
+		if json.Unmarshal([]byte(ep.Body), &doc) != nil {
+			return points
+		}
+		for _, field := range jsonFields(doc, "") {
+			points = append(points, injectionPoint{request: ep, location: locationJSON, name: field[0], value: field[1]})
+		}
+	case strings.Contains(contentType, "x-www-form-urlencoded") || (contentType == "" && strings.Contains(ep.Body, "=")):
+		form, err := url.ParseQuery(ep.Body)
+		if err != nil {
+			return points
+		}
+		for _, name := range sortedKeys(form) {
+			points = append(points, injectionPoint{request: ep, location: locationForm, name: name, value: form.Get(name)})
+		}
+	}
+	return points
+}
+
+// isJSONBody reports whether body is a JSON object or array.
+func isJSONBody(body string) bool {
+	body = strings.TrimSpace(body)
+	return (strings.HasPrefix(body, "{") || strings.HasPrefix(body, "[")) && json.Valid([]byte(body))
+}
+
+// jsonFields returns the path and value of each string, number, and boolean
+// in doc, in a stable order.
+func jsonFields(doc any, path string) [][2]string {
+	join := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + "." + key
+	}
+
+	var fields [][2]string
+	switch v := doc.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			fields = append(fields, jsonFields(v[key], join(key))...)
+		}
+	case []any:
+		for i, item := range v {
+			fields = append(fields, jsonFields(item, join(strconv.Itoa(i)))...)
+		}
+	case string:
+		fields = append(fields, [2]string{path, v})
+	case float64, bool:
+		fields = append(fields, [2]string{path, fmt.Sprint(v)})
+	}
+	return fields
+}
+
+// setJSONField returns doc with the field at path set to the string value.
+func setJSONField(doc any, path []string, value string) any {
+	if len(path) == 0 {
+		return value
+	}
+	switch v := doc.(type) {
+	case map[string]any:
+		if child, ok := v[path[0]]; ok {
+			v[path[0]] = setJSONField(child, path[1:], value)
+		}
+	case []any:
+		if i, err := strconv.Atoi(path[0]); err == nil && i >= 0 && i < len(v) {
+			v[i] = setJSONField(v[i], path[1:], value)
+		}
+	}
+	return doc
+}
+
+// requestLine describes the request ep for evidence: its URL, with the
+// method when it is not a plain GET.
+func requestLine(ep types.Endpoint) string {
+	if ep.Method == "" || ep.Method == http.MethodGet {
+		return ep.URL
+	}
+	return ep.Method + " " + ep.URL
+}
+
+// header returns the value of the named header in headers, matching the
+// name case-insensitively.
+func header(headers map[string]string, name string) string {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+// sortedKeys returns the keys of values in order, so points are tested in
+// the same order every scan.
+func sortedKeys(values url.Values) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,174 @@
+package vuln
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pointNames(points []injectionPoint) []string {
+	var names []string
+	for _, p := range points {
+		names = append(names, p.location+":"+p.name+"="+p.value)
+	}
+	return names
+}
+
+func TestRequestPoints(t *testing.T) {
+	tests := []struct {
+		name string
+		ep   types.Endpoint
+		want []string
+	}{
+		{
+			name: "query",
+			ep:   types.Endpoint{Method: "GET", URL: "http://example.com/?b=2&a=1"},
+			want: []string{"query:a=1", "query:b=2"},
+		},
+		{
+			name: "form",
+			ep: types.Endpoint{Method: "POST", URL: "http://example.com/login?next=/home",
+				Headers: map[string]string{"content-type": "application/x-www-form-urlencoded"}, Body: "user=alice&pass=x"},
+			want: []string{"query:next=/home", "form:pass=x", "form:user=alice"},
+		},
+		{
+			name: "json",
+			ep: types.Endpoint{Method: "POST", URL: "http://example.com/api",
+				Headers: map[string]string{"Content-Type": "application/json"},
+				Body:    `{"user": {"name": "alice", "age": 30}, "tags": ["a", "b"], "admin": false, "note": null}`},
+			want: []string{"json:admin=false", "json:tags.0=a", "json:tags.1=b", "json:user.age=30", "json:user.name=alice"},
+		},
+		{
+			name: "json without content type",
+			ep:   types.Endpoint{Method: "POST", URL: "http://example.com/api", Body: `{"q": "x"}`},
+			want: []string{"json:q=x"},
+		},
+		{
+			name: "other body",
+			ep:   types.Endpoint{Method: "POST", URL: "http://example.com/api", Headers: map[string]string{"Content-Type": "text/plain"}, Body: "a=b"},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, pointNames(requestPoints(tt.ep)))
+		})
+	}
+}
+
+func TestInjectionPoint_With(t *testing.T) {
+	form := injectionPoint{
+		request:  types.Endpoint{Method: "POST", URL: "http://example.com/", Body: "user=alice&pass=x"},
+		location: locationForm,
+		name:     "user",
+	}
+	values, err := url.ParseQuery(form.with("<b>").Body)
+	require.NoError(t, err)
+	assert.Equal(t, "<b>", values.Get("user"))
+	assert.Equal(t, "x", values.Get("pass"))
+
+	js := injectionPoint{
+		request:  types.Endpoint{Method: "POST", URL: "http://example.com/", Body: `{"user": {"age": 30}, "tags": ["a"]}`},
+		location: locationJSON,
+		name:     "user.age",
+	}
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal([]byte(js.with("30 AND 1=1").Body), &doc))
+	assert.Equal(t, map[string]any{"age": "30 AND 1=1"}, doc["user"])
+	assert.Equal(t, []any{"a"}, doc["tags"])
+	assert.Contains(t, js.request.Body, `"age": 30`, "the original request is left alone")
+}
+
+func TestFindingPoint(t *testing.T) {
+	point := injectionPoint{
+		request:  types.Endpoint{Method: "POST", URL: "http://example.com/", Headers: map[string]string{"Content-Type": "application/json"}, Body: `{"q": "x"}`},
+		location: locationJSON,
+		name:     "q",
+	}
+	sent := point.with("payload")
+	f := types.Finding{Metadata: point.metadata(map[string]string{"check": "xss"}, sent)}
+	assert.Equal(t, "json", f.Metadata["param_in"])
+
+	rebuilt := findingPoint(f)
+	assert.Equal(t, sent, rebuilt.request)
+	assert.Equal(t, point.with("other"), rebuilt.with("other"))
+
+	query := injectionPoint{request: types.Endpoint{Method: "GET", URL: "http://example.com/?q=x"}, location: locationQuery, name: "q"}
+	m := query.metadata(map[string]string{}, query.with("y"))
+	assert.Equal(t, map[string]string{"param": "q", "url": "http://example.com/?q=y"}, m, "query findings keep their metadata")
+}
+
+func TestChecks_InjectIntoBodies(t *testing.T) {
+	// A search form POSTing JSON to an endpoint that renders HTML, and a
+	// login form whose next field redirects.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search":
+			var body struct {
+				Query string `json:"query"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprintf(w, "<html><body>Results for %s</body></html>", body.Query)
+		case "/login":
+			r.ParseForm()
+			if next := r.PostForm.Get("next"); next != "" {
+				http.Redirect(w, r, next, http.StatusFound)
+				return
+			}
+			if r.PostForm.Get("user") == "'" {
+				fmt.Fprint(w, "ERROR: unterminated quoted string at or near \"'\" (PostgreSQL)")
+			}
+		}
+	}))
+	defer srv.Close()
+
+	target := types.Target{URL: srv.URL + "/login", Host: "127.0.0.1", Scheme: "http"}
+	opts := scanner.DefaultOptions()
+	opts.Intensity = scanner.IntensitySafe
+	opts.Endpoints = []types.Endpoint{
+		{Method: "POST", URL: "/search", Headers: map[string]string{"Content-Type": "application/json"}, Body: `{"query": "shoes"}`},
+	}
+	opts.ExtraArgs = map[string]interface{}{"data": "user=alice&next="}
+
+	xss := CheckReflectedXSS(context.Background(), target, opts)
+	require.Len(t, xss, 1)
+	assert.Equal(t, "query", xss[0].Metadata["param"])
+	assert.Equal(t, "json", xss[0].Metadata["param_in"])
+	assert.Equal(t, "POST", xss[0].Metadata["method"])
+	assert.Contains(t, xss[0].Description, `JSON field "query"`)
+	assert.Contains(t, xss[0].Artifacts[0].Request, xss[0].Metadata["canary"])
+
+	sqli := CheckSQLi(context.Background(), target, opts)
+	require.NotEmpty(t, sqli)
+	assert.Equal(t, "user", sqli[0].Metadata["param"])
+	assert.Equal(t, "form", sqli[0].Metadata["param_in"])
+	assert.Equal(t, "postgresql", sqli[0].Metadata["error_pattern"])
+
+	var redirects []types.Finding
+	for _, f := range CheckOpenRedirect(context.Background(), target, opts) {
+		if f.Metadata["param_in"] == "form" {
+			redirects = append(redirects, f)
+		}
+	}
+	require.Len(t, redirects, 1)
+	assert.Equal(t, "next", redirects[0].Metadata["param"])
+	assert.Equal(t, redirectTarget, redirects[0].Metadata["location"])
+
+	// The body findings verify by replaying their POSTs.
+	s := New()
+	for _, f := range []types.Finding{xss[0], sqli[0], redirects[0]} {
+		reproduces, err := s.Verify(context.Background(), target, f, opts)
+		require.NoError(t, err)
+		assert.True(t, reproduces, f.Title)
+	}
+}
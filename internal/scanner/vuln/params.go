@@ -0,0 +1,279 @@
+package vuln
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// paramNames are parameter names applications commonly accept without
+// linking to them, tried by parameter mining unless the "param_wordlist"
+// argument names a file of others. The "vuln.mined_params" intensity budget
+// decides how many are tried.
+var paramNames = []string{
+	"id", "q", "search", "query", "s", "keyword", "page", "p", "limit", "offset",
+	"sort", "order", "dir", "filter", "type", "category", "cat", "tag", "lang", "locale",
+	"user", "username", "user_id", "uid", "email", "name", "account", "role", "admin", "debug",
+	"test", "mode", "view", "action", "cmd", "exec", "command", "file", "filename", "path",
+	"folder", "dir_path", "doc", "document", "template", "include", "page_id", "item", "item_id", "product",
+	"product_id", "pid", "article", "post", "post_id", "comment", "news", "date", "year", "month",
+	"from", "to", "start", "end", "format", "output", "callback", "jsonp", "redirect", "redirect_uri",
+	"return", "return_to", "returnUrl", "next", "url", "uri", "target", "dest", "destination", "continue",
+	"ref", "source", "src", "key", "api_key", "apikey", "token", "access_token", "auth", "session",
+	"sid", "code", "state", "hash", "sig", "signature", "version", "v", "preview", "draft",
+	"raw", "show", "hidden", "internal", "config", "env", "level", "group", "org", "team",
+	"project", "report", "export", "download", "upload", "image", "img", "width", "height", "size",
+	"color", "theme", "style", "host", "domain", "ip", "port", "proxy", "feed", "rss",
+}
+
+// paramBatch is how many candidate parameters one mining request carries.
+const paramBatch = 30
+
+// minedParam is a parameter mining found the endpoint to accept, and how.
+type minedParam struct {
+	name string
+	// sign is how the response gave it away: its value was reflected, or
+	// the status or the size of the response changed.
+	sign string
+}
+
+// mineParams finds hidden parameters of ep at location (query, form, or
+// JSON body) Arjun-style: it sends the candidate names in batches, each
+// with a value of its own, and keeps those whose value is reflected, or
+// whose batch changes the status or size of the response, narrowing the
+// batch down by halves to the names responsible. Names ep already has are
+// not tried.
+func mineParams(ctx context.Context, ep types.Endpoint, location string, names []string, opts scanner.Options) []minedParam {
+	base, ok := measureBaseline(ctx, ep, opts)
+	if !ok {
+		return nil
+	}
+	existing := map[string]bool{}
+	for _, p := range requestPoints(ep) {
+		existing[p.name] = true
+	}
+	var candidates []string
+	for _, name := range names {
+		if !existing[name] {
+			candidates = append(candidates, name)
+		}
+	}
+
+	var found []minedParam
+	for batch := range slices.Chunk(candidates, paramBatch) {
+		if ctx.Err() != nil {
+			break
+		}
+		found = append(found, probeParams(ctx, ep, location, batch, base, opts)...)
+	}
+	return found
+}
+
+// measureBaseline requests ep twice to learn its usual response.
+func measureBaseline(ctx context.Context, ep types.Endpoint, opts scanner.Options) (scanner.Baseline, bool) {
+	var pages [2]scanner.Page
+	for i := range pages {
+		resp, err := send(ctx, ep, opts)
+		if err != nil {
+			return scanner.Baseline{}, false
+		}
+		pages[i] = scanner.NewPage(resp.status, resp.body)
+	}
+	return scanner.NewBaseline(pages[0], pages[1]), true
+}
+
+// probeParams sends names to ep at once and returns those found, halving
+// the batch while a change cannot be pinned on a reflected value.
+func probeParams(ctx context.Context, ep types.Endpoint, location string, names []string, base scanner.Baseline, opts scanner.Options) []minedParam {
+	values := make(map[string]string, len(names))
+	for i, name := range names {
+		values[name] = fmt.Sprintf("hntr%03dq", i)
+	}
+	resp, err := send(ctx, withParams(ep, location, values), opts)
+	if err != nil {
+		return nil
+	}
+
+	var found []minedParam
+	var reflected []string
+	for _, name := range names {
+		if strings.Contains(resp.body, values[name]) {
+			found = append(found, minedParam{name: name, sign: "reflected"})
+			reflected = append(reflected, values[name])
+		}
+	}
+
+	var sign string
+	switch {
+	case base.StableStatus() && resp.status != base.Status():
+		sign = fmt.Sprintf("changed the status from %d to %d", base.Status(), resp.status)
+	case !base.Matches(scanner.NewPage(resp.status, resp.body, reflected...)):
+		sign = "changed the response"
+	default:
+		return found
+	}
+	if len(names) == 1 {
+		if len(found) == 0 {
+			found = append(found, minedParam{name: names[0], sign: sign})
+		}
+		return found
+	}
+	// A reflected value can change the page around it, so the rest are
+	// narrowed down without the names already found.
+	var rest []string
+	for _, name := range names {
+		if !slices.ContainsFunc(found, func(p minedParam) bool { return p.name == name }) {
+			rest = append(rest, name)
+		}
+	}
+	for half := range slices.Chunk(rest, (len(rest)+1)/2) {
+		if ctx.Err() != nil || len(rest) == 0 {
+			break
+		}
+		found = append(found, probeParams(ctx, ep, location, half, base, opts)...)
+	}
+	return found
+}
+
+// withParams returns ep with the parameters of values added at location.
+func withParams(ep types.Endpoint, location string, values map[string]string) types.Endpoint {
+	switch location {
+	case locationForm:
+		form, _ := url.ParseQuery(ep.Body)
+		for name, value := range values {
+			form.Set(name, value)
+		}
+		ep.Body = form.Encode()
+	case locationJSON:
+		var doc map[string]any
+		if json.Unmarshal([]byte(ep.Body), &doc) == nil {
+			for name, value := range values {
+				doc[name] = value
+			}
+			if body, err := json.Marshal(doc); err == nil {
+				ep.Body = string(body)
+			}
+		}
+	default:
+		u, err := url.Parse(ep.URL)
+		if err != nil {
+			return ep
+		}
+		query := u.Query()
+		for name, value := range values {
+			query.Set(name, value)
+		}
+		u.RawQuery = query.Encode()
+		ep.URL = u.String()
+	}
+	return ep
+}
+
+// minedValue is the value mined parameters are given for the checks to
+// build their payloads on.
+const minedValue = "1"
+
+// mineTargetParams runs parameter mining, unless the "no_param_mining"
+// argument is set, against the target URL and the body of the "data"
+// argument, and returns the target and options with the parameters found
+// added to them, so every check tests those as well. It also returns a
+// finding listing them, if it found any.
+func mineTargetParams(ctx context.Context, target types.Target, opts scanner.Options) (types.Target, scanner.Options, []types.Finding) {
+	if opts.BoolArg("no_param_mining") {
+		return target, opts, nil
+	}
+	names, err := mineNames(opts)
+	if err != nil {
+		opts.Logf("vuln", scanner.LogWarn, "parameter mining skipped: %v", err)
+		return target, opts, nil
+	}
+
+	var findings []types.Finding
+	ep := types.Endpoint{Method: http.MethodGet, URL: target.URL}
+	if mined := mineParams(ctx, ep, locationQuery, names, opts); len(mined) > 0 {
+		target.URL = withParams(ep, locationQuery, minedValues(mined)).URL
+		findings = append(findings, minedFinding(ep, mined))
+	}
+
+	if data := opts.StringArg("data"); data != "" {
+		location, contentType := locationForm, "application/x-www-form-urlencoded"
+		if isJSONBody(data) {
+			location, contentType = locationJSON, "application/json"
+		}
+		ep := types.Endpoint{Method: http.MethodPost, URL: target.URL, Headers: map[string]string{"Content-Type": contentType}, Body: data}
+		if mined := mineParams(ctx, ep, location, names, opts); len(mined) > 0 {
+			args := make(map[string]interface{}, len(opts.ExtraArgs)+1)
+			for k, v := range opts.ExtraArgs {
+				args[k] = v
+			}
+			args["data"] = withParams(ep, location, minedValues(mined)).Body
+			opts.ExtraArgs = args
+			findings = append(findings, minedFinding(ep, mined))
+		}
+	}
+	return target, opts, findings
+}
+
+// mineNames returns the candidate names: those of the "param_wordlist"
+// file, one per line, or paramNames, limited by the intensity budget.
+func mineNames(opts scanner.Options) ([]string, error) {
+	names := paramNames
+	if path := opts.StringArg("param_wordlist"); path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		names = nil
+		lines := bufio.NewScanner(f)
+		for lines.Scan() {
+			if name := strings.TrimSpace(lines.Text()); name != "" && !strings.HasPrefix(name, "#") {
+				names = append(names, name)
+			}
+		}
+		if err := lines.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return scanner.Limit(names, opts.Budget("vuln.mined_params")), nil
+}
+
+func minedValues(mined []minedParam) map[string]string {
+	values := make(map[string]string, len(mined))
+	for _, p := range mined {
+		values[p.name] = minedValue
+	}
+	return values
+}
+
+// minedFinding reports the hidden parameters found on ep.
+func minedFinding(ep types.Endpoint, mined []minedParam) types.Finding {
+	names := make([]string, len(mined))
+	lines := make([]string, len(mined))
+	for i, p := range mined {
+		names[i] = p.name
+		lines[i] = fmt.Sprintf("%s: %s", p.name, p.sign)
+	}
+	return types.Finding{
+		Title: "Hidden parameters found",
+		Description: fmt.Sprintf("%s accepts %d parameter(s) it does not link to: %s. They were found by sending common parameter names and watching the response, and are tested by the other checks. "+
+			"Hidden parameters often enable debugging, filtering, or internal behavior the visible interface does not.", requestLine(ep), len(mined), strings.Join(names, ", ")),
+		Severity: types.SeverityInfo,
+		Evidence: strings.Join(lines, "\n"),
+		Metadata: map[string]string{
+			"check":  "param-mining",
+			"url":    ep.URL,
+			"method": ep.Method,
+			"params": strings.Join(names, ","),
+		},
+	}
+}
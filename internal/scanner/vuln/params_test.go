@@ -0,0 +1,112 @@
+package vuln
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hiddenParamServer echoes its "search" parameter and shows extra output
+// for "debug", neither of which it links to.
+func hiddenParamServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		q := r.URL.Query()
+		fmt.Fprint(w, "<html><body>Welcome to the shop.")
+		if s := q.Get("search"); s != "" {
+			fmt.Fprintf(w, " Results for %s.", s)
+		}
+		if q.Get("debug") != "" {
+			fmt.Fprint(w, strings.Repeat(" debug: request handled by shop-7 in 4ms.", 5))
+		}
+		fmt.Fprint(w, "</body></html>")
+	}))
+}
+
+func TestMineParams(t *testing.T) {
+	srv := hiddenParamServer()
+	defer srv.Close()
+
+	ep := types.Endpoint{Method: http.MethodGet, URL: srv.URL + "/?page=1"}
+	mined := mineParams(context.Background(), ep, locationQuery, paramNames, scanner.DefaultOptions())
+
+	signs := map[string]string{}
+	for _, p := range mined {
+		signs[p.name] = p.sign
+	}
+	assert.Equal(t, "reflected", signs["search"])
+	assert.Equal(t, "changed the response", signs["debug"])
+	assert.Len(t, mined, 2, "names that change nothing are not reported: %v", signs)
+}
+
+func TestMineParams_Body(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err == nil && r.PostForm.Get("role") != "" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer srv.Close()
+
+	ep := types.Endpoint{Method: http.MethodPost, URL: srv.URL, Headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, Body: "user=a"}
+	mined := mineParams(context.Background(), ep, locationForm, paramNames, scanner.DefaultOptions())
+
+	require.Len(t, mined, 1)
+	assert.Equal(t, "role", mined[0].name)
+	assert.Equal(t, "changed the status from 200 to 403", mined[0].sign)
+}
+
+func TestScanner_RunParamWordlist(t *testing.T) {
+	srv := hiddenParamServer()
+	defer srv.Close()
+
+	wordlist := filepath.Join(t.TempDir(), "params.txt")
+	require.NoError(t, os.WriteFile(wordlist, []byte("# shop\nid\nsearch\n"), 0o644))
+	opts := scanner.DefaultOptions()
+	opts.ExtraArgs = map[string]interface{}{"checks": "sqli", "param_wordlist": wordlist}
+
+	result, err := New().Run(context.Background(), types.Target{URL: srv.URL}, opts)
+	require.NoError(t, err)
+
+	var mined []string
+	for _, f := range result.Findings {
+		if f.Metadata["check"] == "param-mining" {
+			mined = append(mined, f.Metadata["params"])
+		}
+	}
+	assert.Equal(t, []string{"search"}, mined, "only the wordlist's names are tried")
+}
+
+func TestScanner_RunNoParamMining(t *testing.T) {
+	srv := hiddenParamServer()
+	defer srv.Close()
+
+	opts := scanner.DefaultOptions()
+	opts.ExtraArgs = map[string]interface{}{"checks": "xss", "no_param_mining": true}
+	result, err := New().Run(context.Background(), types.Target{URL: srv.URL}, opts)
+	require.NoError(t, err)
+	assert.Empty(t, result.Findings, "without mining there is nothing to inject into")
+}
+
+func TestScanner_RunInjectsIntoMinedParams(t *testing.T) {
+	srv := hiddenParamServer()
+	defer srv.Close()
+
+	opts := scanner.DefaultOptions()
+	opts.ExtraArgs = map[string]interface{}{"checks": "xss"}
+	result, err := New().Run(context.Background(), types.Target{URL: srv.URL}, opts)
+	require.NoError(t, err)
+
+	titles := findingTitles(result.Findings)
+	assert.Contains(t, titles, "Hidden parameters found")
+	assert.Contains(t, titles, "Potential reflected XSS", "the mined search parameter is tested")
+}
@@ -0,0 +1,249 @@
+package vuln
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"strings"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// pollutionKeys are the two ways a JSON body reaches Object.prototype through
+// an unsafe merge or clone: the __proto__ accessor, and the constructor's
+// prototype for merges that filter __proto__ out.
+var pollutionKeys = []struct {
+	name string
+	wrap func(props map[string]any) any
+}{
+	{"__proto__", func(props map[string]any) any { return props }},
+	{"constructor.prototype", func(props map[string]any) any { return map[string]any{"prototype": props} }},
+}
+
+// pollutionStatus is the error status the status probe pollutes with: one no
+// server sends by chance.
+const pollutionStatus = 555
+
+// pollutionSpaces is the indentation the JSON spaces probe pollutes with.
+const pollutionSpaces = 10
+
+// malformedJSON is a body every JSON parser rejects.
+const malformedJSON = `{"hunter":`
+
+// pollutionProbe is a way of telling that a request polluted the server's
+// Object.prototype. It returns the polluted request and the evidence when it
+// did. Probes other than reflection change the server until it restarts, so
+// they use properties that only alter how it answers.
+type pollutionProbe struct {
+	name  string
+	probe func(ctx context.Context, ep types.Endpoint, key int, opts scanner.Options) (types.Endpoint, string, []types.Artifact, bool)
+}
+
+// pollutionProbes are the probes tried, least disruptive first. The
+// "vuln.pollution_probes" intensity budget decides how many are tried.
+var pollutionProbes = []pollutionProbe{
+	{"reflection", reflectionProbe},
+	{"json spaces", jsonSpacesProbe},
+	{"status code", statusProbe},
+}
+
+// CheckPrototypePollution tests the JSON bodies POSTed by the scan, the
+// "data" argument's and those of imported POST, PUT, and PATCH requests, for
+// server-side prototype pollution: each is sent with properties under a
+// __proto__ or constructor.prototype key, and the check watches for them
+// taking effect on objects the request never set them on. Node.js APIs that
+// merge request bodies into objects unsafely are vulnerable.
+func CheckPrototypePollution(ctx context.Context, target types.Target, opts scanner.Options) []types.Finding {
+	var findings []types.Finding
+
+	probes := scanner.Limit(pollutionProbes, opts.Budget("vuln.pollution_probes"))
+	for _, ep := range pollutionRequests(target, opts) {
+	probing:
+		for _, probe := range probes {
+			for key := range pollutionKeys {
+				if ctx.Err() != nil {
+					return findings
+				}
+				sent, evidence, artifacts, ok := probe.probe(ctx, ep, key, opts)
+				if !ok {
+					continue
+				}
+				findings = append(findings, types.Finding{
+					Title:       "Server-side prototype pollution",
+					Description: fmt.Sprintf("Properties sent under a %s key in the JSON body of %s reached Object.prototype (%s probe): the server merges request bodies into objects unsafely. Polluted properties apply to every object in the process, which can change application logic, bypass checks, or lead to remote code execution through gadgets in the application's libraries.", pollutionKeys[key].name, requestLine(ep), probe.name),
+					Severity:    types.SeverityHigh,
+					Evidence:    evidence,
+					Remediation: "Do not recursively merge or clone untrusted objects; skip __proto__, constructor, and prototype keys, validate bodies against a schema, or build objects with Object.create(null). Restart the server to clear the pollution left by this probe.",
+					Metadata: map[string]string{
+						"check":      "proto-pollution",
+						"technique":  probe.name,
+						"key":        pollutionKeys[key].name,
+						"url":        sent.URL,
+						"method":     sent.Method,
+						"body":       sent.Body,
+						"confidence": pollutionConfidence(probe.name),
+					},
+					Artifacts: artifacts,
+				})
+				break probing
+			}
+		}
+	}
+	return findings
+}
+
+// pollutionRequests returns the requests whose JSON bodies the check
+// pollutes: a POST of the "data" argument to the target URL, and the
+// imported requests that send a JSON object or may.
+func pollutionRequests(target types.Target, opts scanner.Options) []types.Endpoint {
+	var requests []types.Endpoint
+	if data := opts.StringArg("data"); isJSONObject(data) {
+		requests = append(requests, types.Endpoint{
+			Method:  http.MethodPost,
+			URL:     target.URL,
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Body:    data,
+		})
+	}
+	for _, ep := range opts.EndpointsOn(target.URL) {
+		switch ep.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+		default:
+			continue
+		}
+		contentType := strings.ToLower(header(ep.Headers, "Content-Type"))
+		switch {
+		case ep.Body == "" && (contentType == "" || strings.Contains(contentType, "json")):
+			ep.Body = "{}"
+		case !isJSONObject(ep.Body) || (contentType != "" && !strings.Contains(contentType, "json")):
+			continue
+		}
+		if contentType == "" {
+			ep.Headers = maps.Clone(ep.Headers)
+			if ep.Headers == nil {
+				ep.Headers = map[string]string{}
+			}
+			ep.Headers["Content-Type"] = "application/json"
+		}
+		requests = append(requests, ep)
+	}
+	return requests
+}
+
+// reflectionProbe sends a canary property under the pollution key and
+// reports whether it comes back in the response as a property of an object
+// outside that key: the merged object inherited it.
+func reflectionProbe(ctx context.Context, ep types.Endpoint, key int, opts scanner.Options) (types.Endpoint, string, []types.Artifact, bool) {
+	canary := newCanary(injectionPoint{request: ep, location: locationJSON, name: pollutionKeys[key].name})
+	sent := pollute(ep, key, map[string]any{canary: canary})
+	resp, err := send(ctx, sent, opts)
+	if err != nil || resp.status >= 400 {
+		return sent, "", nil, false
+	}
+	var doc any
+	if json.Unmarshal([]byte(resp.body), &doc) != nil || !inheritedProperty(doc, canary) {
+		return sent, "", nil, false
+	}
+	evidence := fmt.Sprintf("%s with property %q under %s returned it as an own property of the response object", requestLine(sent), canary, pollutionKeys[key].name)
+	return sent, evidence, []types.Artifact{resp.artifact}, true
+}
+
+// jsonSpacesProbe pollutes Express's "json spaces" setting and reports
+// whether the server's JSON responses, compact before, come back indented.
+func jsonSpacesProbe(ctx context.Context, ep types.Endpoint, key int, opts scanner.Options) (types.Endpoint, string, []types.Artifact, bool) {
+	sent := pollute(ep, key, map[string]any{"json spaces": pollutionSpaces})
+	indent := "\n" + strings.Repeat(" ", pollutionSpaces) + `"`
+
+	before, err := send(ctx, ep, opts)
+	if err != nil || !strings.Contains(before.contentType, "json") || strings.Contains(before.body, "\n") {
+		return sent, "", nil, false
+	}
+	if _, err := send(ctx, sent, opts); err != nil {
+		return sent, "", nil, false
+	}
+	after, err := send(ctx, ep, opts)
+	if err != nil || !strings.Contains(after.body, indent) {
+		return sent, "", nil, false
+	}
+	evidence := fmt.Sprintf("%s returned compact JSON; after %s with \"json spaces\": %d under %s, it returned JSON indented by %d spaces", requestLine(ep), requestLine(sent), pollutionSpaces, pollutionKeys[key].name, pollutionSpaces)
+	return sent, evidence, []types.Artifact{before.artifact, after.artifact}, true
+}
+
+// statusProbe pollutes the status of error objects and reports whether a
+// malformed body, rejected with another status before, is then rejected
+// with the polluted one.
+func statusProbe(ctx context.Context, ep types.Endpoint, key int, opts scanner.Options) (types.Endpoint, string, []types.Artifact, bool) {
+	sent := pollute(ep, key, map[string]any{"status": pollutionStatus})
+	malformed := ep
+	malformed.Body = malformedJSON
+
+	before, err := send(ctx, malformed, opts)
+	if err != nil || before.status < 400 || before.status == pollutionStatus {
+		return sent, "", nil, false
+	}
+	if _, err := send(ctx, sent, opts); err != nil {
+		return sent, "", nil, false
+	}
+	after, err := send(ctx, malformed, opts)
+	if err != nil || after.status != pollutionStatus {
+		return sent, "", nil, false
+	}
+	evidence := fmt.Sprintf("A malformed JSON body to %s got status %d; after %s with \"status\": %d under %s, it got status %d", requestLine(ep), before.status, requestLine(sent), pollutionStatus, pollutionKeys[key].name, after.status)
+	return sent, evidence, []types.Artifact{before.artifact, after.artifact}, true
+}
+
+// pollute returns ep with props added to its JSON body under the pollution
+// key.
+func pollute(ep types.Endpoint, key int, props map[string]any) types.Endpoint {
+	body := map[string]any{}
+	json.Unmarshal([]byte(ep.Body), &body)
+	name, _, _ := strings.Cut(pollutionKeys[key].name, ".")
+	body[name] = pollutionKeys[key].wrap(props)
+	encoded, err := json.Marshal(body)
+	if err == nil {
+		ep.Body = string(encoded)
+	}
+	return ep
+}
+
+// inheritedProperty reports whether doc holds an object with the property
+// canary outside a __proto__ or constructor key, where a server echoing the
+// request body would have put it.
+func inheritedProperty(doc any, canary string) bool {
+	switch v := doc.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if key == "__proto__" || key == "constructor" {
+				continue
+			}
+			if key == canary || inheritedProperty(child, canary) {
+				return true
+			}
+		}
+	case []any:
+		for _, child := range v {
+			if inheritedProperty(child, canary) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pollutionConfidence grades a probe's finding: a reflected property may
+// have been copied by the application on purpose, while a changed response
+// format confirms the pollution.
+func pollutionConfidence(technique string) string {
+	if technique == "reflection" {
+		return types.ConfidenceFirm
+	}
+	return types.ConfidenceCertain
+}
+
+// isJSONObject reports whether body is a JSON object.
+func isJSONObject(body string) bool {
+	return strings.HasPrefix(strings.TrimSpace(body), "{") && isJSONBody(body)
+}
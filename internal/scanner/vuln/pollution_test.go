@@ -0,0 +1,175 @@
+package vuln
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pollutableServer mimics an Express API that deep-merges request bodies
+// into a fresh object: properties under __proto__ (or constructor.prototype,
+// unless filterProto) land on a shared prototype that every later response
+// inherits from. reflect controls whether responses enumerate inherited
+// properties.
+func pollutableServer(t *testing.T, reflect, filterProto bool) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	proto := map[string]any{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			status := http.StatusBadRequest
+			if s, ok := proto["status"].(float64); ok {
+				status = int(s)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		merged := map[string]any{}
+		for k, v := range body {
+			switch {
+			case k == "__proto__" && !filterProto:
+				for pk, pv := range v.(map[string]any) {
+					proto[pk] = pv
+				}
+			case k == "constructor":
+				if p, ok := v.(map[string]any)["prototype"].(map[string]any); ok {
+					for pk, pv := range p {
+						proto[pk] = pv
+					}
+				}
+			case k != "__proto__":
+				merged[k] = v
+			}
+		}
+		if reflect {
+			for k, v := range proto {
+				merged[k] = v
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		var out []byte
+		if spaces, ok := proto["json spaces"].(float64); ok {
+			out, _ = json.MarshalIndent(merged, "", strings.Repeat(" ", int(spaces)))
+		} else {
+			out, _ = json.Marshal(merged)
+		}
+		w.Write(out)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func pollutionOptions(data string) scanner.Options {
+	opts := scanner.DefaultOptions()
+	opts.ExtraArgs = map[string]interface{}{"data": data}
+	return opts
+}
+
+func TestCheckPrototypePollution_Reflection(t *testing.T) {
+	srv := pollutableServer(t, true, false)
+	target := types.Target{URL: srv.URL + "/api/profile", Host: "127.0.0.1", Scheme: "http"}
+
+	findings := CheckPrototypePollution(context.Background(), target, pollutionOptions(`{"name":"alice"}`))
+	require.Len(t, findings, 1)
+	f := findings[0]
+	assert.Equal(t, "Server-side prototype pollution", f.Title)
+	assert.Equal(t, types.SeverityHigh, f.Severity)
+	assert.Equal(t, "proto-pollution", f.Metadata["check"])
+	assert.Equal(t, "reflection", f.Metadata["technique"])
+	assert.Equal(t, "__proto__", f.Metadata["key"])
+	assert.Equal(t, types.ConfidenceFirm, f.Metadata["confidence"])
+	assert.Contains(t, f.Metadata["body"], `"name":"alice"`)
+	assert.NotEmpty(t, f.Artifacts)
+}
+
+func TestCheckPrototypePollution_JSONSpaces(t *testing.T) {
+	srv := pollutableServer(t, false, false)
+	target := types.Target{URL: srv.URL + "/api/profile", Host: "127.0.0.1", Scheme: "http"}
+
+	findings := CheckPrototypePollution(context.Background(), target, pollutionOptions(`{"name":"alice"}`))
+	require.Len(t, findings, 1)
+	assert.Equal(t, "json spaces", findings[0].Metadata["technique"])
+	assert.Equal(t, types.ConfidenceCertain, findings[0].Metadata["confidence"])
+}
+
+func TestCheckPrototypePollution_ConstructorPrototype(t *testing.T) {
+	srv := pollutableServer(t, true, true)
+	target := types.Target{URL: srv.URL + "/api/profile", Host: "127.0.0.1", Scheme: "http"}
+
+	findings := CheckPrototypePollution(context.Background(), target, pollutionOptions(`{"name":"alice"}`))
+	require.Len(t, findings, 1)
+	assert.Equal(t, "constructor.prototype", findings[0].Metadata["key"])
+	assert.Contains(t, findings[0].Metadata["body"], `"constructor":{"prototype":`)
+}
+
+func TestStatusProbe(t *testing.T) {
+	srv := pollutableServer(t, false, false)
+	ep := types.Endpoint{Method: http.MethodPost, URL: srv.URL, Headers: map[string]string{"Content-Type": "application/json"}, Body: "{}"}
+
+	sent, evidence, _, ok := statusProbe(context.Background(), ep, 0, scanner.DefaultOptions())
+	require.True(t, ok)
+	assert.Contains(t, sent.Body, `"__proto__":{"status":555}`)
+	assert.Contains(t, evidence, "got status 400")
+	assert.Contains(t, evidence, "got status 555")
+}
+
+func TestCheckPrototypePollution_SafeServer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Echoes the body as parsed, __proto__ key and all, as JSON.parse
+		// and JSON.stringify do.
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(body)
+	}))
+	defer srv.Close()
+
+	target := types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}
+	findings := CheckPrototypePollution(context.Background(), target, pollutionOptions(`{"name":"alice"}`))
+	assert.Empty(t, findings)
+}
+
+func TestCheckPrototypePollution_SafeIntensity(t *testing.T) {
+	srv := pollutableServer(t, false, false)
+	target := types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}
+
+	opts := pollutionOptions(`{"name":"alice"}`)
+	opts.Intensity = scanner.IntensitySafe
+	assert.Empty(t, CheckPrototypePollution(context.Background(), target, opts), "only reflection is tried")
+}
+
+func TestPollutionRequests(t *testing.T) {
+	opts := scanner.DefaultOptions()
+	opts.Endpoints = []types.Endpoint{
+		{Method: http.MethodPost, URL: "http://example.com/api/users", Body: `{"name":"bob"}`},
+		{Method: http.MethodPut, URL: "http://example.com/api/users/1"},
+		{Method: http.MethodPost, URL: "http://example.com/login", Headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, Body: "user=bob"},
+		{Method: http.MethodGet, URL: "http://example.com/api/users"},
+		{Method: http.MethodPost, URL: "http://other.example.com/api", Body: `{}`},
+	}
+	target := types.Target{URL: "http://example.com/", Host: "example.com", Scheme: "http"}
+
+	requests := pollutionRequests(target, opts)
+	require.Len(t, requests, 2)
+	assert.Equal(t, "application/json", requests[0].Headers["Content-Type"])
+	assert.Equal(t, "{}", requests[1].Body)
+	assert.Nil(t, opts.Endpoints[0].Headers, "imported requests are not modified")
+}
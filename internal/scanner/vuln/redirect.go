@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 
@@ -26,17 +27,7 @@ const redirectTarget = "https://evil.com"
 // query parameters, each redirect param name found among them is tested.
 // If it has no parameters, each redirect param is appended with the evil value.
 func CheckOpenRedirect(ctx context.Context, target types.Target, opts scanner.Options) []types.Finding {
-	timeout := opts.Timeout
-	if timeout == 0 {
-		timeout = 5 * time.Second
-	}
-
-	client := &http.Client{
-		Timeout: timeout,
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse
-		},
-	}
+	client := redirectClient(opts)
 
 	u, err := url.Parse(target.URL)
 	if err != nil {
@@ -84,15 +75,69 @@ func CheckOpenRedirect(ctx context.Context, target types.Target, opts scanner.Op
 		}
 	}
 
+	// Body fields named like redirect params, in the "data" argument's body
+	// and imported endpoints.
+	for _, point := range injectionPoints(target, opts) {
+		if point.location == locationQuery || !slices.Contains(redirectParams, lastField(point.name)) {
+			continue
+		}
+		if ctx.Err() != nil {
+			return findings
+		}
+		if f := probeRedirectPoint(ctx, client, point); f != nil {
+			findings = append(findings, *f)
+		}
+	}
+
 	return findings
 }
 
+// lastField returns the last segment of a JSON field path, the field's own
+// name.
+func lastField(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// redirectClient returns a client that does not follow redirects, so the
+// 3xx response itself can be inspected.
+func redirectClient(opts scanner.Options) *http.Client {
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: opts.HTTPTransport(),
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// redirectsToTarget reports whether resp redirects to redirectTarget.
+func redirectsToTarget(resp *http.Response) bool {
+	return resp.StatusCode >= 300 && resp.StatusCode < 400 &&
+		strings.HasPrefix(resp.Header.Get("Location"), redirectTarget)
+}
+
 // probeRedirect sends a GET request with the given redirect param set to
 // evil.com and checks if the response is a 3xx redirect to that URL.
 func probeRedirect(ctx context.Context, client *http.Client, baseURL, param string) *types.Finding {
-	testURL := appendQueryParam(baseURL, param, redirectTarget)
+	point := injectionPoint{
+		request:  types.Endpoint{Method: http.MethodGet, URL: baseURL},
+		location: locationQuery,
+		name:     param,
+	}
+	return probeRedirectPoint(ctx, client, point)
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, testURL, nil)
+// probeRedirectPoint sends point's request with its value set to evil.com
+// and checks if the response is a 3xx redirect to that URL. The redirect
+// body is not read, so the artifact holds only the response head.
+func probeRedirectPoint(ctx context.Context, client *http.Client, point injectionPoint) *types.Finding {
+	sent := point.with(redirectTarget)
+	req, err := newRequest(ctx, sent)
 	if err != nil {
 		return nil
 	}
@@ -103,23 +148,20 @@ func probeRedirect(ctx context.Context, client *http.Client, baseURL, param stri
 	}
 	resp.Body.Close()
 
-	if resp.StatusCode >= 300 && resp.StatusCode < 400 {
+	if redirectsToTarget(resp) {
 		location := resp.Header.Get("Location")
-		if strings.HasPrefix(location, redirectTarget) {
-			return &types.Finding{
-				Title:       "Potential open redirect",
-				Description: fmt.Sprintf("The server redirects to an attacker-controlled URL when the %q parameter is set to an external domain.", param),
-				Severity:    types.SeverityMedium,
-				Evidence:    fmt.Sprintf("GET %s → %d Location: %s", testURL, resp.StatusCode, location),
-				Remediation: "Validate redirect targets against an allowlist of trusted domains. Avoid using user-supplied values directly in redirect URLs.",
-				Metadata: map[string]string{
-					"check":       "redirect",
-					"param":       param,
-					"url":         testURL,
-					"location":    location,
-					"status_code": fmt.Sprintf("%d", resp.StatusCode),
-				},
-			}
+		return &types.Finding{
+			Title:       "Potential open redirect",
+			Description: fmt.Sprintf("The server redirects to an attacker-controlled URL when %s is set to an external domain.", point),
+			Severity:    types.SeverityMedium,
+			Evidence:    fmt.Sprintf("%s %s → %d Location: %s", sent.Method, sent.URL, resp.StatusCode, location),
+			Remediation: "Validate redirect targets against an allowlist of trusted domains. Avoid using user-supplied values directly in redirect URLs.",
+			Metadata: point.metadata(map[string]string{
+				"check":       "redirect",
+				"location":    location,
+				"status_code": fmt.Sprintf("%d", resp.StatusCode),
+			}, sent),
+			Artifacts: []types.Artifact{scanner.NewArtifact(req, requestBody(sent), resp, nil)},
 		}
 	}
 
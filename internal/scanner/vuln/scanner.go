@@ -18,12 +18,17 @@ type CheckFunc func(ctx context.Context, target types.Target, opts scanner.Optio
 
 // checkRegistry maps short names to check functions for CLI filtering.
 var checkRegistry = map[string]CheckFunc{
-	"xss":      CheckReflectedXSS,
-	"sqli":     CheckSQLi,
-	"redirect": CheckOpenRedirect,
+	"xss":             CheckReflectedXSS,
+	"sqli":            CheckSQLi,
+	"redirect":        CheckOpenRedirect,
+	"dom-xss":         CheckDOMXSS,
+	"proto-pollution": CheckPrototypePollution,
+	"breach":          CheckBREACH,
 }
 
-// Scanner performs basic vulnerability detection (XSS, SQLi, open redirect).
+// Scanner performs basic vulnerability detection (XSS, SQLi, open redirect,
+// server-side prototype pollution, BREACH exposure, and, given a headless
+// browser, DOM XSS).
 type Scanner struct{}
 
 // New creates a new vulnerability scanner.
@@ -47,6 +52,9 @@ func (s *Scanner) Run(ctx context.Context, target types.Target, opts scanner.Opt
 	}
 	target.URL = targetURL
 
+	target, opts, mined := mineTargetParams(ctx, target, opts)
+	result.Findings = append(result.Findings, mined...)
+
 	checks := s.resolveChecks(opts)
 	for _, check := range checks {
 		if ctx.Err() != nil {
@@ -62,22 +70,23 @@ func (s *Scanner) Run(ctx context.Context, target types.Target, opts scanner.Opt
 }
 
 // resolveChecks returns the check functions to run. If opts.ExtraArgs contains
-// a "checks" key (comma-separated names), only those checks are returned.
+// a "checks" key (comma-separated names or a list), only those checks are returned.
 // Otherwise all checks are returned.
 func (s *Scanner) resolveChecks(opts scanner.Options) []CheckFunc {
-	if raw, ok := opts.ExtraArgs["checks"]; ok {
-		if names, ok := raw.(string); ok && names != "" {
-			var selected []CheckFunc
-			for _, name := range strings.Split(names, ",") {
-				name = strings.TrimSpace(name)
-				if fn, exists := checkRegistry[name]; exists {
-					selected = append(selected, fn)
-				}
-			}
-			if len(selected) > 0 {
-				return selected
+	if names := opts.StringArg("checks"); names != "" {
+		var selected []CheckFunc
+		for _, name := range strings.Split(names, ",") {
+			name = strings.TrimSpace(name)
+			if fn, exists := checkRegistry[name]; exists {
+				selected = append(selected, fn)
+			} else {
+				opts.Logf(s.Name(), scanner.LogWarn, "unknown check %q ignored", name)
 			}
 		}
+		if len(selected) > 0 {
+			return selected
+		}
+		opts.Logf(s.Name(), scanner.LogWarn, "no known checks in %q, running all checks", names)
 	}
 	return Checks()
 }
@@ -88,6 +97,9 @@ func Checks() []CheckFunc {
 		CheckReflectedXSS,
 		CheckSQLi,
 		CheckOpenRedirect,
+		CheckDOMXSS,
+		CheckPrototypePollution,
+		CheckBREACH,
 	}
 }
 
@@ -103,7 +115,7 @@ func resolveURL(target types.Target) string {
 	if target.Host == "" {
 		return ""
 	}
-	return scheme + "://" + target.Host
+	return scheme + "://" + target.URLHost()
 }
 
 // appendQueryParam adds a query parameter to a URL.
@@ -118,29 +130,80 @@ func appendQueryParam(rawURL, key, value string) string {
 	return u.String()
 }
 
-// httpGet performs a GET request and returns the response body as a string.
-func httpGet(ctx context.Context, targetURL string, timeout time.Duration) (string, error) {
+// response is a page fetched by a check.
+type response struct {
+	status int
+	// contentType is sniffed from the body, as browsers do, when the
+	// server sends none.
+	contentType string
+	body        string
+	elapsed     time.Duration
+	artifact    types.Artifact
+}
+
+// send sends the request ep describes and returns the response.
+func send(ctx context.Context, ep types.Endpoint, opts scanner.Options) (*response, error) {
+	timeout := opts.Timeout
 	if timeout == 0 {
 		timeout = 5 * time.Second
 	}
 
-	client := &http.Client{Timeout: timeout}
+	client := &http.Client{Timeout: timeout, Transport: opts.HTTPTransport()}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
+	req, err := newRequest(ctx, ep)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
+	start := time.Now()
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20)) // 1 MB limit
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20)) // 1 MB limit
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(respBody)
+	}
+	return &response{
+		status:      resp.StatusCode,
+		contentType: contentType,
+		body:        string(respBody),
+		elapsed:     time.Since(start),
+		artifact:    scanner.NewArtifact(req, requestBody(ep), resp, respBody),
+	}, nil
+}
+
+// newRequest returns the request ep describes.
+func newRequest(ctx context.Context, ep types.Endpoint) (*http.Request, error) {
+	method := ep.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	var body io.Reader
+	if ep.Body != "" {
+		body = strings.NewReader(ep.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, ep.URL, body)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	for name, value := range ep.Headers {
+		req.Header.Set(name, value)
 	}
+	return req, nil
+}
 
-	return string(body), nil
+// requestBody returns ep's body for artifacts, nil when it has none.
+func requestBody(ep types.Endpoint) []byte {
+	if ep.Body == "" {
+		return nil
+	}
+	return []byte(ep.Body)
 }
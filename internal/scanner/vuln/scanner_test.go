@@ -33,6 +33,7 @@ func TestScanner_RunAggregatesFindings(t *testing.T) {
 			body += " You have an error in your SQL syntax"
 		}
 
+		w.Header().Set("Content-Type", "text/html")
 		w.WriteHeader(http.StatusOK)
 		fmt.Fprint(w, body)
 	}))
@@ -102,7 +103,7 @@ func TestScanner_RunWithChecksFilter(t *testing.T) {
 
 func TestChecks_ReturnsAllModules(t *testing.T) {
 	checks := Checks()
-	assert.Len(t, checks, 3, "expected XSS, SQLi, and redirect check modules")
+	assert.Len(t, checks, 6, "expected XSS, SQLi, redirect, DOM XSS, prototype pollution, and BREACH check modules")
 }
 
 func TestResolveURL(t *testing.T) {
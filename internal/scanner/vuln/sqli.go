@@ -3,19 +3,24 @@ package vuln
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/buemura/hunter/internal/scanner"
 	"github.com/buemura/hunter/pkg/types"
 )
 
-// sqliPayloads are error-based SQL injection test vectors.
+// sqliPayloads are error-based SQL injection test vectors, the most telling
+// first. The "vuln.payloads" intensity budget decides how many are sent.
 var sqliPayloads = []string{
 	`'`,
 	`' OR '1'='1`,
 	`1; DROP TABLE`,
 	`' UNION SELECT NULL--`,
+	`"`,
+	`')`,
+	`1 AND 1=CONVERT(int, @@version)--`,
 }
 
 // sqlErrorPatterns are common database error signatures that indicate
@@ -30,56 +35,239 @@ var sqlErrorPatterns = []string{
 	"unclosed quotation",
 }
 
-// CheckSQLi tests for SQL injection by injecting error-based payloads into each
-// existing URL query parameter and looking for database error signatures in the
-// response. If the target URL has no query parameters the check is skipped.
-func CheckSQLi(ctx context.Context, target types.Target, opts scanner.Options) []types.Finding {
-	u, err := url.Parse(target.URL)
-	if err != nil {
-		return nil
-	}
+// sqliBooleanPayloads are appended to a parameter's value to add a condition
+// to the query, %[1]d = %[2]d, for string and numeric parameters.
+var sqliBooleanPayloads = []string{
+	`' AND '%[1]d'='%[2]d`,
+	` AND %[1]d=%[2]d`,
+	`" AND "%[1]d"="%[2]d`,
+}
 
-	params := u.Query()
-	if len(params) == 0 {
-		return nil
-	}
+// sqliTimePayloads are appended to a parameter's value to make the database
+// wait {sleep} seconds, for the database named.
+var sqliTimePayloads = []struct {
+	suffix string
+	dbms   string
+}{
+	{`' AND (SELECT 1 FROM (SELECT SLEEP({sleep}))x)-- -`, "MySQL"},
+	{` AND (SELECT 1 FROM (SELECT SLEEP({sleep}))x)`, "MySQL"},
+	{`'; SELECT pg_sleep({sleep})--`, "PostgreSQL"},
+	{`'; WAITFOR DELAY '00:00:{sleep}'--`, "SQL Server"},
+}
+
+// maxSleep caps how long time-based payloads make the database wait. It is
+// also kept under half the request timeout.
+const maxSleep = 5 * time.Second
 
+// CheckSQLi tests for SQL injection in each injection point, including the
+// commonly logged headers, three ways: error-based payloads, looking for
+// database error signatures in the response; boolean-based ones, comparing
+// the responses to a true and a false condition; and time-based ones, timing
+// a database sleep against the target's usual latency. Error messages alone
+// are tentative, since pages mention databases for innocent reasons; a
+// consistent boolean difference is firm, and a delay that goes away without
+// the sleep is certain.
+func CheckSQLi(ctx context.Context, target types.Target, opts scanner.Options) []types.Finding {
+	return checkSQLi(ctx, append(injectionPoints(target, opts), headerPoints(target)...), opts)
+}
+
+// checkSQLi tests points for SQL injection, as CheckSQLi describes.
+func checkSQLi(ctx context.Context, points []injectionPoint, opts scanner.Options) []types.Finding {
 	var findings []types.Finding
 
-	for param := range params {
-		for _, payload := range sqliPayloads {
+	payloads := scanner.Limit(sqliPayloads, opts.Budget("vuln.payloads"))
+	for _, point := range points {
+		for _, payload := range payloads {
 			if ctx.Err() != nil {
 				return findings
 			}
 
-			testURL := replaceQueryParam(target.URL, param, payload)
-			body, err := httpGet(ctx, testURL, opts.Timeout)
+			sent := point.with(payload)
+			resp, err := send(ctx, sent, opts)
 			if err != nil {
 				continue
 			}
 
-			lower := strings.ToLower(body)
+			lower := strings.ToLower(resp.body)
 			for _, pattern := range sqlErrorPatterns {
 				if strings.Contains(lower, pattern) {
 					findings = append(findings, types.Finding{
 						Title:       "Potential SQL injection",
-						Description: fmt.Sprintf("The server returned a database error message when parameter %q was set to a SQL injection test payload, suggesting improper input handling.", param),
+						Description: fmt.Sprintf("The server returned a database error message when %s was set to a SQL injection test payload, suggesting improper input handling.", point),
 						Severity:    types.SeverityCritical,
-						Evidence:    fmt.Sprintf("Error pattern %q found in response from %s", pattern, testURL),
+						Evidence:    fmt.Sprintf("Error pattern %q found in response from %s", pattern, requestLine(sent)),
 						Remediation: "Use parameterized queries or prepared statements. Never concatenate user input into SQL queries.",
-						Metadata: map[string]string{
+						Metadata: point.metadata(map[string]string{
 							"check":         "sqli",
-							"param":         param,
 							"payload":       payload,
-							"url":           testURL,
 							"error_pattern": pattern,
-						},
+							"confidence":    types.ConfidenceTentative,
+						}, sent),
+						Artifacts: []types.Artifact{resp.artifact},
 					})
 					break
 				}
 			}
 		}
+
+		if f, ok := checkBooleanSQLi(ctx, point, opts); ok {
+			findings = append(findings, f)
+		}
+		if f, ok := checkTimeSQLi(ctx, point, opts); ok {
+			findings = append(findings, f)
+		}
 	}
 
 	return findings
 }
+
+// checkBooleanSQLi appends true and false conditions to the point's value
+// and reports a finding if the two true ones get the same response and the
+// false one a different, stable one: the condition reached the query.
+func checkBooleanSQLi(ctx context.Context, point injectionPoint, opts scanner.Options) (types.Finding, bool) {
+	for _, template := range scanner.Limit(sqliBooleanPayloads, opts.Budget("vuln.payloads")) {
+		if ctx.Err() != nil {
+			return types.Finding{}, false
+		}
+
+		trueValues := [2]string{point.value + fmt.Sprintf(template, 1, 1), point.value + fmt.Sprintf(template, 2, 2)}
+		falseValue := point.value + fmt.Sprintf(template, 1, 2)
+		trueResp, falseResp, ok, err := booleanProbe(ctx, point, trueValues, falseValue, opts)
+		if err != nil || !ok {
+			continue
+		}
+
+		sent := point.with(falseValue)
+		return types.Finding{
+			Title:       "Blind SQL injection (boolean-based)",
+			Description: fmt.Sprintf("Appending a false SQL condition to %s changed the response while true ones did not, showing it is built into a SQL query.", point),
+			Severity:    types.SeverityCritical,
+			Evidence: fmt.Sprintf("%q got status %d with %d bytes; %q got status %d with %d bytes, consistently, from %s",
+				trueValues[0], trueResp.status, len(trueResp.body), falseValue, falseResp.status, len(falseResp.body), requestLine(sent)),
+			Remediation: "Use parameterized queries or prepared statements. Never concatenate user input into SQL queries.",
+			Metadata: point.metadata(map[string]string{
+				"check":        "sqli",
+				"technique":    "boolean",
+				"payload":      falseValue,
+				"true_payload": trueValues[0],
+				"confidence":   types.ConfidenceFirm,
+			}, sent),
+			Artifacts: []types.Artifact{trueResp.artifact, falseResp.artifact},
+		}, true
+	}
+	return types.Finding{}, false
+}
+
+// booleanProbe sends point the two trueValues and falseValue twice, and
+// reports whether the true ones got the same response and the false ones
+// a different one, the same both times. It returns the responses to the
+// first true and false values.
+func booleanProbe(ctx context.Context, point injectionPoint, trueValues [2]string, falseValue string, opts scanner.Options) (*response, *response, bool, error) {
+	var responses [4]*response
+	for i, value := range []string{trueValues[0], trueValues[1], falseValue, falseValue} {
+		resp, err := send(ctx, point.with(value), opts)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		responses[i] = resp
+		// Unless the true values agree and the false one differs, the
+		// repeat cannot change the outcome.
+		if i == 2 && (!sameResponse(responses[0], trueValues[0], responses[1], trueValues[1]) ||
+			sameResponse(responses[0], trueValues[0], resp, falseValue)) {
+			return nil, nil, false, nil
+		}
+	}
+	ok := sameResponse(responses[2], falseValue, responses[3], falseValue)
+	return responses[0], responses[2], ok, nil
+}
+
+// sameResponse reports whether a and b, the responses to values a and b,
+// have the same status and body once the values reflected in them, and
+// content that changes by itself, such as timestamps and CSRF tokens, are
+// removed.
+func sameResponse(a *response, aValue string, b *response, bValue string) bool {
+	return scanner.NewPage(a.status, a.body, aValue).Same(scanner.NewPage(b.status, b.body, bValue))
+}
+
+// checkTimeSQLi appends database sleeps to the point's value and reports a
+// finding if one delays the response by the time asked for, while the same
+// payload sleeping for zero seconds does not.
+func checkTimeSQLi(ctx context.Context, point injectionPoint, opts scanner.Options) (types.Finding, bool) {
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	delay := min(maxSleep, timeout/2)
+
+	// The target's usual latency, over two requests.
+	var baseline time.Duration
+	for range 2 {
+		resp, err := send(ctx, point.request, opts)
+		if err != nil {
+			return types.Finding{}, false
+		}
+		baseline = max(baseline, resp.elapsed)
+	}
+	if baseline+delay+delay/2 > timeout {
+		opts.Logf("vuln", scanner.LogInfo, "sqli: time-based test of %s skipped, responses take %s, too close to the %s timeout", point, baseline.Round(time.Millisecond), timeout)
+		return types.Finding{}, false
+	}
+
+	for _, p := range scanner.Limit(sqliTimePayloads, opts.Budget("vuln.payloads")) {
+		if ctx.Err() != nil {
+			return types.Finding{}, false
+		}
+
+		payload := point.value + sleepPayload(p.suffix, delay)
+		control := point.value + sleepPayload(p.suffix, 0)
+		sent := point.with(payload)
+		slow, err := send(ctx, sent, opts)
+		if err != nil || slow.elapsed < delay {
+			continue
+		}
+		fast, ok, err := timeProbe(ctx, sent, point.with(control), delay, opts)
+		if err != nil || !ok {
+			continue
+		}
+
+		return types.Finding{
+			Title:       "Blind SQL injection (time-based)",
+			Description: fmt.Sprintf("A %s sleep appended to %s delayed the response by the time it asked for, twice, and the same payload without the delay did not, showing it is executed as SQL.", p.dbms, point),
+			Severity:    types.SeverityCritical,
+			Evidence: fmt.Sprintf("%q took %s from %s; sleeping 0 seconds took %s, and the usual response %s",
+				payload, slow.elapsed.Round(time.Millisecond), requestLine(sent), fast.elapsed.Round(time.Millisecond), baseline.Round(time.Millisecond)),
+			Remediation: "Use parameterized queries or prepared statements. Never concatenate user input into SQL queries.",
+			Metadata: point.metadata(map[string]string{
+				"check":           "sqli",
+				"technique":       "time",
+				"payload":         payload,
+				"control_payload": control,
+				"dbms":            p.dbms,
+				"delay":           delay.String(),
+				"confidence":      types.ConfidenceCertain,
+			}, sent),
+			Artifacts: []types.Artifact{slow.artifact, fast.artifact},
+		}, true
+	}
+	return types.Finding{}, false
+}
+
+// timeProbe sends control, asking for no sleep, and sleep, asking for
+// delay, and reports whether only the latter took delay longer. It returns
+// the control's response.
+func timeProbe(ctx context.Context, sleep, control types.Endpoint, delay time.Duration, opts scanner.Options) (*response, bool, error) {
+	fast, err := send(ctx, control, opts)
+	if err != nil {
+		return nil, false, err
+	}
+	slow, err := send(ctx, sleep, opts)
+	if err != nil {
+		return nil, false, err
+	}
+	return fast, slow.elapsed >= delay && slow.elapsed-fast.elapsed >= delay/2, nil
+}
+
+// sleepPayload fills a time-based payload's {sleep} with delay in seconds.
+func sleepPayload(suffix string, delay time.Duration) string {
+	return strings.ReplaceAll(suffix, "{sleep}", strconv.FormatFloat(delay.Seconds(), 'f', -1, 64))
+}
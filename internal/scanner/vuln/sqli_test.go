@@ -2,10 +2,18 @@ package vuln
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
+	"html"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/buemura/hunter/internal/scanner"
 	"github.com/buemura/hunter/pkg/types"
@@ -32,6 +40,31 @@ func TestCheckSQLi_DetectsSQLError(t *testing.T) {
 	assert.Equal(t, "id", findings[0].Metadata["param"])
 }
 
+func TestCheckSQLi_IntensityScalesPayloads(t *testing.T) {
+	// For the parameter and each injected header: error-based payloads,
+	// three requests per boolean payload, two baseline requests, and one
+	// per time-based payload.
+	points := 1 + len(injectedHeaders)
+	for intensity, want := range map[scanner.Intensity]int{
+		scanner.IntensitySafe:       points * (1 + 3*1 + 2 + 1),
+		scanner.IntensityNormal:     points * (4 + 3*len(sqliBooleanPayloads) + 2 + len(sqliTimePayloads)),
+		scanner.IntensityAggressive: points * (len(sqliPayloads) + 3*len(sqliBooleanPayloads) + 2 + len(sqliTimePayloads)),
+	} {
+		var count atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			count.Add(1)
+		}))
+
+		opts := scanner.DefaultOptions()
+		opts.Intensity = intensity
+		target := types.Target{URL: srv.URL + "?id=1", Host: "127.0.0.1", Scheme: "http"}
+		CheckSQLi(context.Background(), target, opts)
+		srv.Close()
+
+		assert.Equal(t, int32(want), count.Load(), intensity)
+	}
+}
+
 func TestCheckSQLi_NoFindingsForSafeServer(t *testing.T) {
 	// Safe server: returns a generic error without SQL details.
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -46,16 +79,49 @@ func TestCheckSQLi_NoFindingsForSafeServer(t *testing.T) {
 	assert.Empty(t, findings)
 }
 
-func TestCheckSQLi_SkipsWhenNoQueryParams(t *testing.T) {
+func TestCheckSQLi_InjectsHeadersWithoutQueryParams(t *testing.T) {
+	var mu sync.Mutex
+	var agents []string
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		agents = append(agents, r.UserAgent())
+		mu.Unlock()
 		w.WriteHeader(http.StatusOK)
 	}))
 	defer srv.Close()
 
 	target := types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}
-	findings := CheckSQLi(context.Background(), target, scanner.DefaultOptions())
+	opts := scanner.DefaultOptions()
+	opts.Intensity = scanner.IntensitySafe
+	findings := CheckSQLi(context.Background(), target, opts)
 
 	assert.Empty(t, findings)
+	assert.Contains(t, agents, "'", "the User-Agent should get the error-based payload")
+}
+
+func TestCheckSQLi_HeaderInjection(t *testing.T) {
+	// An access log insert that breaks on quotes in the User-Agent.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Count(r.UserAgent(), "'")%2 == 1 {
+			fmt.Fprint(w, "You have an error in your SQL syntax")
+		}
+	}))
+	defer srv.Close()
+
+	target := types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}
+	opts := scanner.DefaultOptions()
+	opts.Intensity = scanner.IntensitySafe
+	findings := CheckSQLi(context.Background(), target, opts)
+
+	require.Len(t, findings, 1)
+	f := findings[0]
+	assert.Equal(t, "header", f.Metadata["param_in"])
+	assert.Equal(t, "User-Agent", f.Metadata["header"])
+	assert.Contains(t, f.Description, `header "User-Agent"`)
+
+	reproduces, err := New().Verify(context.Background(), target, f, opts)
+	require.NoError(t, err)
+	assert.True(t, reproduces)
 }
 
 func TestCheckSQLi_DetectsMultipleErrorPatterns(t *testing.T) {
@@ -101,3 +167,126 @@ func TestCheckSQLi_ContextCancelled(t *testing.T) {
 
 	assert.Empty(t, findings)
 }
+
+// booleanServer simulates a products page whose id parameter is built into
+// a SQL query: an appended false condition empties the result.
+func booleanServer(fixed *atomic.Bool) *httptest.Server {
+	condition := regexp.MustCompile(`AND ["']?(\d+)["']?=["']?(\d+)["']?$`)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Query().Get("id")
+		if m := condition.FindStringSubmatch(id); m != nil && m[1] != m[2] && (fixed == nil || !fixed.Load()) {
+			fmt.Fprintf(w, "<p>No products match %s</p>", html.EscapeString(id))
+			return
+		}
+		fmt.Fprintf(w, "<p>Widget, $10 (searched for %s)</p>", html.EscapeString(id))
+	}))
+}
+
+func TestCheckSQLi_BooleanBased(t *testing.T) {
+	srv := booleanServer(nil)
+	defer srv.Close()
+
+	target := types.Target{URL: srv.URL + "?id=1", Host: "127.0.0.1", Scheme: "http"}
+	findings := CheckSQLi(context.Background(), target, scanner.DefaultOptions())
+
+	require.Len(t, findings, 1)
+	f := findings[0]
+	assert.Equal(t, "Blind SQL injection (boolean-based)", f.Title)
+	assert.Equal(t, types.SeverityCritical, f.Severity)
+	assert.Equal(t, "boolean", f.Metadata["technique"])
+	assert.Equal(t, types.ConfidenceFirm, f.Metadata["confidence"])
+	assert.Equal(t, "1' AND '1'='2", f.Metadata["payload"])
+	assert.Equal(t, "1' AND '1'='1", f.Metadata["true_payload"])
+	assert.Len(t, f.Artifacts, 2)
+}
+
+func TestCheckSQLi_BooleanIgnoresInputDependentPages(t *testing.T) {
+	// Every distinct input gets a distinct page, so true and false
+	// conditions cannot be told apart from the input itself.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "<p>Checksum: %x</p>", sha256.Sum256([]byte(r.URL.Query().Get("id"))))
+	}))
+	defer srv.Close()
+
+	target := types.Target{URL: srv.URL + "?id=1", Host: "127.0.0.1", Scheme: "http"}
+	findings := CheckSQLi(context.Background(), target, scanner.DefaultOptions())
+
+	assert.Empty(t, findings)
+}
+
+// sleepServer simulates a MySQL-backed page whose q parameter reaches the
+// database, so SLEEP payloads delay the response.
+func sleepServer(fixed *atomic.Bool) *httptest.Server {
+	sleep := regexp.MustCompile(`SLEEP\(([\d.]+)\)`)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m := sleep.FindStringSubmatch(r.URL.Query().Get("q")); m != nil && (fixed == nil || !fixed.Load()) {
+			seconds, _ := strconv.ParseFloat(m[1], 64)
+			time.Sleep(time.Duration(seconds * float64(time.Second)))
+		}
+		fmt.Fprint(w, "<p>Results</p>")
+	}))
+}
+
+func TestCheckSQLi_TimeBased(t *testing.T) {
+	srv := sleepServer(nil)
+	defer srv.Close()
+
+	target := types.Target{URL: srv.URL + "?q=shoes", Host: "127.0.0.1", Scheme: "http"}
+	opts := scanner.DefaultOptions()
+	opts.Timeout = time.Second
+	findings := CheckSQLi(context.Background(), target, opts)
+
+	require.Len(t, findings, 1)
+	f := findings[0]
+	assert.Equal(t, "Blind SQL injection (time-based)", f.Title)
+	assert.Equal(t, "time", f.Metadata["technique"])
+	assert.Equal(t, types.ConfidenceCertain, f.Metadata["confidence"])
+	assert.Equal(t, "MySQL", f.Metadata["dbms"])
+	assert.Equal(t, "500ms", f.Metadata["delay"])
+	assert.Equal(t, "shoes' AND (SELECT 1 FROM (SELECT SLEEP(0.5))x)-- -", f.Metadata["payload"])
+	assert.Equal(t, "shoes' AND (SELECT 1 FROM (SELECT SLEEP(0))x)-- -", f.Metadata["control_payload"])
+}
+
+func TestCheckSQLi_TimeBasedIgnoresTarpit(t *testing.T) {
+	// A filter that slows down every suspicious request, sleep or not.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Query().Get("q"), "SELECT") {
+			time.Sleep(600 * time.Millisecond)
+		}
+		fmt.Fprint(w, "<p>Results</p>")
+	}))
+	defer srv.Close()
+
+	opts := scanner.DefaultOptions()
+	opts.Timeout = time.Second
+	opts.Intensity = scanner.IntensitySafe
+	_, ok := checkTimeSQLi(context.Background(), queryPoint(srv.URL, "q", "shoes"), opts)
+
+	assert.False(t, ok)
+}
+
+// queryPoint returns the injection point of query parameter name, set to
+// value, in a GET request to baseURL.
+func queryPoint(baseURL, name, value string) injectionPoint {
+	return injectionPoint{
+		request:  types.Endpoint{Method: http.MethodGet, URL: baseURL + "?" + name + "=" + value},
+		location: locationQuery,
+		name:     name,
+		value:    value,
+	}
+}
+
+func TestCheckSQLi_TimeBasedSkipsSlowTargets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	var logged []string
+	opts := scanner.DefaultOptions()
+	opts.Timeout = time.Second
+	opts.Log = func(e scanner.LogEntry) { logged = append(logged, e.Message) }
+	checkTimeSQLi(context.Background(), queryPoint(srv.URL, "q", "shoes"), opts)
+
+	assert.Contains(t, strings.Join(logged, "\n"), `time-based test of parameter "q" skipped`)
+}
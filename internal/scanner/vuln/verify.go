@@ -0,0 +1,87 @@
+package vuln
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/internal/browser"
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// Verify replays the probe behind a finding of the xss, sqli, redirect,
+// dom-xss, or breach check: the request recorded in its metadata, judged the same way the
+// check judged it.
+func (s *Scanner) Verify(ctx context.Context, target types.Target, finding types.Finding, opts scanner.Options) (bool, error) {
+	if finding.Metadata["url"] == "" {
+		return false, fmt.Errorf("finding %q has no probe URL to replay", finding.Title)
+	}
+	point := findingPoint(finding)
+
+	switch check := finding.Metadata["check"]; check {
+	case "xss":
+		resp, err := send(ctx, point.request, opts)
+		if err != nil {
+			return false, err
+		}
+		_, ok := executableReflection(resp.body, resp.contentType, finding.Metadata["payload"])
+		return ok, nil
+
+	case "sqli":
+		switch finding.Metadata["technique"] {
+		case "boolean":
+			trueValue := finding.Metadata["true_payload"]
+			_, _, ok, err := booleanProbe(ctx, point, [2]string{trueValue, trueValue}, finding.Metadata["payload"], opts)
+			return ok, err
+		case "time":
+			delay, err := time.ParseDuration(finding.Metadata["delay"])
+			if err != nil {
+				return false, fmt.Errorf("finding %q has no valid delay: %w", finding.Title, err)
+			}
+			_, ok, err := timeProbe(ctx, point.request, point.with(finding.Metadata["control_payload"]), delay, opts)
+			return ok, err
+		}
+		resp, err := send(ctx, point.request, opts)
+		if err != nil {
+			return false, err
+		}
+		return strings.Contains(strings.ToLower(resp.body), finding.Metadata["error_pattern"]), nil
+
+	case "dom-xss":
+		// Verifying needs a browser even when the scan was not given one.
+		name := opts.StringArg("browser")
+		if name == "" {
+			name = "auto"
+		}
+		path, err := browser.Find(name)
+		if err != nil {
+			return false, err
+		}
+		dom, err := renderDOM(ctx, path, point.request.URL, opts.Timeout)
+		if err != nil {
+			return false, err
+		}
+		return ranIn(dom, finding.Metadata["canary"]), nil
+
+	case "redirect":
+		req, err := newRequest(ctx, point.request)
+		if err != nil {
+			return false, err
+		}
+		resp, err := redirectClient(opts).Do(req)
+		if err != nil {
+			return false, err
+		}
+		resp.Body.Close()
+		return redirectsToTarget(resp), nil
+
+	case "breach":
+		_, ok := breachProbe(ctx, point, finding.Metadata["payload"], opts)
+		return ok, nil
+
+	default:
+		return false, fmt.Errorf("findings of the %q check cannot be verified", check)
+	}
+}
@@ -0,0 +1,129 @@
+package vuln
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify_XSSFixed(t *testing.T) {
+	var fixed atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("search")
+		if fixed.Load() {
+			q = html.EscapeString(q)
+		}
+		fmt.Fprintf(w, "<html><body>Results for: %s</body></html>", q)
+	}))
+	defer srv.Close()
+
+	target := types.Target{URL: srv.URL + "?search=test", Host: "127.0.0.1", Scheme: "http"}
+	findings := CheckReflectedXSS(context.Background(), target, scanner.DefaultOptions())
+	require.NotEmpty(t, findings)
+
+	s := New()
+	reproduces, err := s.Verify(context.Background(), target, findings[0], scanner.DefaultOptions())
+	require.NoError(t, err)
+	assert.True(t, reproduces)
+
+	fixed.Store(true)
+	reproduces, err = s.Verify(context.Background(), target, findings[0], scanner.DefaultOptions())
+	require.NoError(t, err)
+	assert.False(t, reproduces)
+}
+
+func TestVerify_XSSNowJSON(t *testing.T) {
+	var json atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if json.Load() {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		fmt.Fprintf(w, "<html><body>Results for: %s</body></html>", r.URL.Query().Get("search"))
+	}))
+	defer srv.Close()
+
+	target := types.Target{URL: srv.URL + "?search=test", Host: "127.0.0.1", Scheme: "http"}
+	findings := CheckReflectedXSS(context.Background(), target, scanner.DefaultOptions())
+	require.NotEmpty(t, findings)
+
+	json.Store(true)
+	reproduces, err := New().Verify(context.Background(), target, findings[0], scanner.DefaultOptions())
+	require.NoError(t, err)
+	assert.False(t, reproduces)
+}
+
+func TestVerify_BlindSQLiFixed(t *testing.T) {
+	for name, newServer := range map[string]func(*atomic.Bool) *httptest.Server{
+		"boolean": booleanServer,
+		"time":    sleepServer,
+	} {
+		t.Run(name, func(t *testing.T) {
+			var fixed atomic.Bool
+			srv := newServer(&fixed)
+			defer srv.Close()
+
+			target := types.Target{URL: srv.URL + "?id=1&q=shoes", Host: "127.0.0.1", Scheme: "http"}
+			opts := scanner.DefaultOptions()
+			opts.Timeout = time.Second
+			opts.Intensity = scanner.IntensitySafe
+			findings := CheckSQLi(context.Background(), target, opts)
+			require.Len(t, findings, 1)
+			require.Equal(t, name, findings[0].Metadata["technique"])
+
+			s := New()
+			reproduces, err := s.Verify(context.Background(), target, findings[0], opts)
+			require.NoError(t, err)
+			assert.True(t, reproduces)
+
+			fixed.Store(true)
+			reproduces, err = s.Verify(context.Background(), target, findings[0], opts)
+			require.NoError(t, err)
+			assert.False(t, reproduces)
+		})
+	}
+}
+
+func TestVerify_RedirectFixed(t *testing.T) {
+	var fixed atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if redir := r.URL.Query().Get("next"); redir != "" && !fixed.Load() {
+			http.Redirect(w, r, redir, http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	target := types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}
+	findings := CheckOpenRedirect(context.Background(), target, scanner.DefaultOptions())
+	require.Len(t, findings, 1)
+
+	s := New()
+	reproduces, err := s.Verify(context.Background(), target, findings[0], scanner.DefaultOptions())
+	require.NoError(t, err)
+	assert.True(t, reproduces)
+
+	fixed.Store(true)
+	reproduces, err = s.Verify(context.Background(), target, findings[0], scanner.DefaultOptions())
+	require.NoError(t, err)
+	assert.False(t, reproduces)
+}
+
+func TestVerify_UnknownCheck(t *testing.T) {
+	finding := types.Finding{Title: "x", Metadata: map[string]string{"check": "csrf", "url": "http://127.0.0.1/"}}
+	_, err := New().Verify(context.Background(), types.Target{}, finding, scanner.DefaultOptions())
+	assert.ErrorContains(t, err, "cannot be verified")
+
+	_, err = New().Verify(context.Background(), types.Target{}, types.Finding{Title: "x"}, scanner.DefaultOptions())
+	assert.ErrorContains(t, err, "no probe URL")
+}
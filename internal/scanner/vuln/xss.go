@@ -2,71 +2,228 @@ package vuln
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"mime"
 	"net/url"
+	"regexp"
 	"strings"
 
 	"github.com/buemura/hunter/internal/scanner"
 	"github.com/buemura/hunter/pkg/types"
 )
 
-// xssPayloads are common reflected XSS test vectors.
+// xssPayloads are common reflected XSS test vectors, the most telling
+// first. {canary} marks where the parameter's canary token goes, so a
+// reflection is known to be this probe's. The "vuln.payloads" intensity
+// budget decides how many are sent.
 var xssPayloads = []string{
-	`<script>alert(1)</script>`,
-	`"><img src=x onerror=alert(1)>`,
-	`javascript:alert(1)`,
+	`{canary}<script>alert(1)</script>`,
+	`{canary}"><img src=x onerror=alert(1)>`,
+	`javascript:alert(1)//{canary}`,
+	`{canary}'><svg onload=alert(1)>`,
+	`{canary}<details open ontoggle=alert(1)>`,
+	`{canary}</textarea><script>alert(1)</script>`,
 }
 
 // CheckReflectedXSS tests for reflected cross-site scripting by injecting
-// payloads into each existing URL query parameter and checking if the payload
-// appears unescaped in the response body. If the target URL has no query
-// parameters the check is skipped gracefully.
+// payloads into each injection point: the target URL's query parameters,
+// the fields of a body given as the "data" argument, those of imported
+// endpoints, and the commonly reflected headers. Each point gets its own
+// canary token, sent alone first: points whose canary does not come back in
+// an HTML response are not probed further, which keeps APIs that echo input
+// as JSON or text from being reported. A payload counts only when it comes
+// back unencoded where a browser would run it, not inside a comment, a
+// textarea, or an attribute it cannot break out of.
 func CheckReflectedXSS(ctx context.Context, target types.Target, opts scanner.Options) []types.Finding {
-	u, err := url.Parse(target.URL)
-	if err != nil {
-		return nil
-	}
-
-	params := u.Query()
-	if len(params) == 0 {
-		return nil
-	}
+	return checkXSS(ctx, append(injectionPoints(target, opts), headerPoints(target)...), opts)
+}
 
+// checkXSS tests points for reflected XSS, as CheckReflectedXSS describes.
+func checkXSS(ctx context.Context, points []injectionPoint, opts scanner.Options) []types.Finding {
 	var findings []types.Finding
 
-	for param := range params {
-		for _, payload := range xssPayloads {
+	payloads := scanner.Limit(xssPayloads, opts.Budget("vuln.payloads"))
+	for _, point := range points {
+		if ctx.Err() != nil {
+			return findings
+		}
+
+		canary := newCanary(point)
+		resp, err := send(ctx, point.with(canary), opts)
+		if err != nil || !strings.Contains(resp.body, canary) {
+			continue
+		}
+		if !isHTML(resp.contentType) {
+			opts.Logf("vuln", scanner.LogInfo, "xss: %s is reflected in a %s response, which browsers do not render as HTML", point, resp.contentType)
+			continue
+		}
+
+		for _, template := range payloads {
 			if ctx.Err() != nil {
 				return findings
 			}
 
-			testURL := replaceQueryParam(target.URL, param, payload)
-			body, err := httpGet(ctx, testURL, opts.Timeout)
+			payload := strings.ReplaceAll(template, "{canary}", canary)
+			sent := point.with(payload)
+			resp, err := send(ctx, sent, opts)
 			if err != nil {
 				continue
 			}
 
-			if strings.Contains(body, payload) {
-				findings = append(findings, types.Finding{
-					Title:       "Potential reflected XSS",
-					Description: fmt.Sprintf("The server reflects user input in parameter %q without proper encoding, which may allow cross-site scripting attacks.", param),
-					Severity:    types.SeverityHigh,
-					Evidence:    fmt.Sprintf("Payload %q reflected in response from %s", payload, testURL),
-					Remediation: "Sanitize and encode all user-supplied input before including it in HTML responses.",
-					Metadata: map[string]string{
-						"check":   "xss",
-						"param":   param,
-						"payload": payload,
-						"url":     testURL,
-					},
-				})
+			r, ok := executableReflection(resp.body, resp.contentType, payload)
+			if !ok {
+				continue
 			}
+			findings = append(findings, types.Finding{
+				Title:       "Potential reflected XSS",
+				Description: fmt.Sprintf("The server reflects user input in %s without proper encoding, which may allow cross-site scripting attacks.", point),
+				Severity:    types.SeverityHigh,
+				Evidence:    fmt.Sprintf("Payload %q reflected unencoded in %s of the HTML response from %s", payload, r, requestLine(sent)),
+				Remediation: "Sanitize and encode all user-supplied input before including it in HTML responses.",
+				Metadata: point.metadata(map[string]string{
+					"check":   "xss",
+					"payload": payload,
+					"canary":  canary,
+					"context": r.context,
+				}, sent),
+				Artifacts: []types.Artifact{resp.artifact},
+			})
 		}
 	}
 
 	return findings
 }
 
+// newCanary returns the canary token for p: one no page contains by chance,
+// different for each injection point but the same in every scan, so
+// findings keep their fingerprints.
+func newCanary(p injectionPoint) string {
+	sum := sha256.Sum256([]byte(p.request.Method + " " + p.request.URL + "\x00" + p.location + "\x00" + p.name))
+	return "hnt" + hex.EncodeToString(sum[:6])
+}
+
+// isHTML reports whether contentType is one browsers render as HTML.
+func isHTML(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "text/html" || mediaType == "application/xhtml+xml")
+}
+
+// reflection is where in an HTML page a payload came back.
+type reflection struct {
+	// context is "text", "comment", "tag", "attribute", or the element
+	// whose content is not markup the payload is inside, such as "textarea".
+	context string
+	attr    string // for "attribute", the attribute's name
+	quote   string // for "attribute", the quote around its value, if any
+	start   bool   // for "attribute", whether the payload starts the value
+}
+
+func (r reflection) String() string {
+	switch r.context {
+	case "text":
+		return "the page text"
+	case "comment":
+		return "a comment"
+	case "tag":
+		return "a tag"
+	case "attribute":
+		return fmt.Sprintf("the %s attribute", r.attr)
+	}
+	return fmt.Sprintf("a <%s> element", r.context)
+}
+
+// opaqueElements are the elements whose content is not parsed as markup.
+var opaqueElements = []string{"script", "style", "textarea", "title"}
+
+// urlAttributes are the attributes a javascript: URL runs from.
+var urlAttributes = map[string]bool{"href": true, "src": true, "action": true, "formaction": true}
+
+var (
+	// attributeValue matches the end of a tag cut inside an attribute value.
+	attributeValue = regexp.MustCompile(`([\w:-]+)\s*=\s*(?:"([^"]*)|'([^']*)|([^\s"'>]*))$`)
+	// injectedMarkup matches a start tag or an event handler attribute.
+	injectedMarkup = regexp.MustCompile(`(?i)<[a-z]|\son[a-z]+\s*=`)
+)
+
+// executableReflection reports whether payload comes back unencoded in
+// body, an HTML page, somewhere a browser would run it, and where.
+func executableReflection(body, contentType, payload string) (reflection, bool) {
+	if !isHTML(contentType) {
+		return reflection{}, false
+	}
+	for offset := 0; ; {
+		i := strings.Index(body[offset:], payload)
+		if i < 0 {
+			return reflection{}, false
+		}
+		r := reflectionAt(body[:offset+i])
+		if r.executes(payload) {
+			return r, true
+		}
+		offset += i + 1
+	}
+}
+
+// reflectionAt returns the context of a reflection preceded by prefix.
+func reflectionAt(prefix string) reflection {
+	lower := strings.ToLower(prefix)
+	if strings.LastIndex(lower, "<!--") > strings.LastIndex(lower, "-->") {
+		return reflection{context: "comment"}
+	}
+	for _, elem := range opaqueElements {
+		if strings.LastIndex(lower, "<"+elem) > strings.LastIndex(lower, "</"+elem) {
+			return reflection{context: elem}
+		}
+	}
+
+	open := strings.LastIndex(prefix, "<")
+	if open < 0 || open < strings.LastIndex(prefix, ">") {
+		return reflection{context: "text"}
+	}
+	m := attributeValue.FindStringSubmatchIndex(prefix[open:])
+	if m == nil {
+		return reflection{context: "tag"}
+	}
+	r := reflection{context: "attribute", attr: strings.ToLower(prefix[open+m[2] : open+m[3]])}
+	switch {
+	case m[4] >= 0:
+		r.quote, r.start = `"`, m[4] == m[5]
+	case m[6] >= 0:
+		r.quote, r.start = `'`, m[6] == m[7]
+	default:
+		r.start = m[8] == m[9]
+	}
+	return r
+}
+
+// executes reports whether payload, reflected in r, would run: whether it
+// escapes the context it lands in to inject markup, or is a javascript: URL
+// starting a URL attribute.
+func (r reflection) executes(payload string) bool {
+	if strings.HasPrefix(strings.ToLower(payload), "javascript:") {
+		return r.context == "attribute" && r.start && urlAttributes[r.attr]
+	}
+
+	var escape int
+	switch r.context {
+	case "text":
+		return injectedMarkup.MatchString(payload)
+	case "comment":
+		escape = strings.Index(payload, "-->")
+	case "tag", "attribute":
+		if r.quote != "" {
+			escape = strings.Index(payload, r.quote)
+		} else {
+			escape = strings.IndexAny(payload, " \t\n>")
+		}
+	default:
+		escape = strings.Index(strings.ToLower(payload), "</"+r.context)
+	}
+	return escape >= 0 && injectedMarkup.MatchString(payload[escape+1:])
+}
+
 // replaceQueryParam returns a copy of rawURL with the given query parameter
 // value replaced.
 func replaceQueryParam(rawURL, key, value string) string {
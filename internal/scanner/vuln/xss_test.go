@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/buemura/hunter/internal/scanner"
@@ -30,6 +32,12 @@ func TestCheckReflectedXSS_DetectsReflection(t *testing.T) {
 	assert.Equal(t, types.SeverityHigh, findings[0].Severity)
 	assert.Equal(t, "xss", findings[0].Metadata["check"])
 	assert.Equal(t, "search", findings[0].Metadata["param"])
+
+	require.Len(t, findings[0].Artifacts, 1)
+	artifact := findings[0].Artifacts[0]
+	assert.Contains(t, artifact.Request, "GET /?search=")
+	assert.Contains(t, artifact.Response, "HTTP/1.1 200 OK")
+	assert.Contains(t, artifact.Response, "Results for: "+findings[0].Metadata["payload"])
 }
 
 func TestCheckReflectedXSS_NoFindingsForEscapedServer(t *testing.T) {
@@ -62,6 +70,7 @@ func TestCheckReflectedXSS_SkipsWhenNoQueryParams(t *testing.T) {
 func TestCheckReflectedXSS_MultipleParams(t *testing.T) {
 	// Server that reflects all params.
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
 		w.WriteHeader(http.StatusOK)
 		for _, vals := range r.URL.Query() {
 			for _, v := range vals {
@@ -76,6 +85,175 @@ func TestCheckReflectedXSS_MultipleParams(t *testing.T) {
 
 	// Both params should yield findings (3 payloads each).
 	assert.GreaterOrEqual(t, len(findings), 2)
+
+	// Each parameter is probed with its own canary.
+	canaries := map[string]string{}
+	for _, f := range findings {
+		assert.Contains(t, f.Metadata["payload"], f.Metadata["canary"])
+		canaries[f.Metadata["param"]] = f.Metadata["canary"]
+	}
+	require.Len(t, canaries, 2)
+	assert.NotEqual(t, canaries["a"], canaries["b"])
+}
+
+func TestCheckReflectedXSS_CanaryStableAcrossScans(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "<html><body>%s</body></html>", r.URL.Query().Get("q"))
+	}))
+	defer srv.Close()
+
+	target := types.Target{URL: srv.URL + "?q=test", Host: "127.0.0.1", Scheme: "http"}
+	first := CheckReflectedXSS(context.Background(), target, scanner.DefaultOptions())
+	second := CheckReflectedXSS(context.Background(), target, scanner.DefaultOptions())
+
+	require.NotEmpty(t, first)
+	require.Len(t, second, len(first))
+	assert.Equal(t, first[0].Metadata, second[0].Metadata, "findings must keep their fingerprints")
+}
+
+func TestCheckReflectedXSS_HeaderInjection(t *testing.T) {
+	// A page showing visitors where they came from.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "<html><body>Back to %s</body></html>", r.Referer())
+	}))
+	defer srv.Close()
+
+	target := types.Target{URL: srv.URL, Host: "127.0.0.1", Scheme: "http"}
+	findings := CheckReflectedXSS(context.Background(), target, scanner.DefaultOptions())
+
+	require.NotEmpty(t, findings)
+	for _, f := range findings {
+		assert.Equal(t, "header", f.Metadata["param_in"])
+		assert.Equal(t, "Referer", f.Metadata["header"])
+	}
+	assert.Contains(t, findings[0].Artifacts[0].Request, "Referer: "+findings[0].Metadata["payload"])
+
+	reproduces, err := New().Verify(context.Background(), target, findings[0], scanner.DefaultOptions())
+	require.NoError(t, err)
+	assert.True(t, reproduces)
+}
+
+func TestCheckReflectedXSS_IgnoresJSONEcho(t *testing.T) {
+	// API that echoes its input unencoded, but as JSON browsers never render.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"query": %q}`, r.URL.Query().Get("q"))
+	}))
+	defer srv.Close()
+
+	target := types.Target{URL: srv.URL + "?q=test", Host: "127.0.0.1", Scheme: "http"}
+	findings := CheckReflectedXSS(context.Background(), target, scanner.DefaultOptions())
+
+	assert.Empty(t, findings)
+}
+
+func TestCheckReflectedXSS_SkipsUnreflectedParams(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		fmt.Fprint(w, "<html><body>Nothing to see</body></html>")
+	}))
+	defer srv.Close()
+
+	target := types.Target{URL: srv.URL + "?q=test", Host: "127.0.0.1", Scheme: "http"}
+	findings := CheckReflectedXSS(context.Background(), target, scanner.DefaultOptions())
+
+	assert.Empty(t, findings)
+	assert.Equal(t, int32(1+len(injectedHeaders)), requests.Load(), "only the canaries should be sent")
+}
+
+func TestCheckReflectedXSS_InertContexts(t *testing.T) {
+	tests := []struct {
+		name string
+		page string
+	}{
+		{"comment", "<html><!-- searched for %s --></html>"},
+		{"textarea without breakout", "<html><textarea>%s</textarea></html>"},
+		{"quoted attribute", `<html><input value="%s"></html>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html; charset=utf-8")
+				// Quotes are encoded, so attribute values cannot be broken out of.
+				q := strings.NewReplacer(`"`, "&quot;", "'", "&#39;").Replace(r.URL.Query().Get("q"))
+				if tt.name == "textarea without breakout" {
+					q = strings.ReplaceAll(q, "</", "&lt;/")
+				}
+				fmt.Fprintf(w, tt.page, q)
+			}))
+			defer srv.Close()
+
+			target := types.Target{URL: srv.URL + "?q=test", Host: "127.0.0.1", Scheme: "http"}
+			findings := CheckReflectedXSS(context.Background(), target, scanner.DefaultOptions())
+
+			assert.Empty(t, findings)
+		})
+	}
+}
+
+func TestCheckReflectedXSS_Contexts(t *testing.T) {
+	tests := []struct {
+		name    string
+		page    string
+		context string
+	}{
+		{"text", "<html><p>%s</p></html>", "text"},
+		{"textarea breakout", "<html><textarea>%s</textarea></html>", "textarea"},
+		{"unquoted attribute", "<html><input value=%s></html>", "attribute"},
+		{"link", `<html><a href="%s">back</a></html>`, "attribute"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html")
+				fmt.Fprintf(w, tt.page, r.URL.Query().Get("q"))
+			}))
+			defer srv.Close()
+
+			target := types.Target{URL: srv.URL + "?q=test", Host: "127.0.0.1", Scheme: "http"}
+			opts := scanner.DefaultOptions()
+			opts.Intensity = scanner.IntensityAggressive
+			findings := CheckReflectedXSS(context.Background(), target, opts)
+
+			require.NotEmpty(t, findings)
+			contexts := map[string]bool{}
+			for _, f := range findings {
+				contexts[f.Metadata["context"]] = true
+			}
+			assert.True(t, contexts[tt.context], "contexts: %v", contexts)
+		})
+	}
+}
+
+func TestReflection_Executes(t *testing.T) {
+	tests := []struct {
+		prefix  string
+		payload string
+		want    bool
+	}{
+		{"<p>", "c<script>alert(1)</script>", true},
+		{"<p>", "javascript:alert(1)//c", false},
+		{`<a href="`, "javascript:alert(1)//c", true},
+		{`<a title="`, "javascript:alert(1)//c", false},
+		{`<a href="/go?to=`, "javascript:alert(1)//c", false},
+		{`<input value="`, `c"><img src=x onerror=alert(1)>`, true},
+		{`<input value="`, `c'><svg onload=alert(1)>`, false},
+		{`<input value='`, `c'><svg onload=alert(1)>`, true},
+		{"<input value=", "c<script>alert(1)</script>", false},
+		{"<input value=", "c<details open ontoggle=alert(1)>", true},
+		{"<!-- ", "c<script>alert(1)</script>", false},
+		{"<textarea>", "c<script>alert(1)</script>", false},
+		{"<textarea>", "c</textarea><script>alert(1)</script>", true},
+		{"<script>var q = '", "c<script>alert(1)</script>", false},
+		{"<title>Results: ", "c<script>alert(1)</script>", false},
+		{"<textarea>x</textarea><p>", "c<script>alert(1)</script>", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prefix+tt.payload, func(t *testing.T) {
+			assert.Equal(t, tt.want, reflectionAt(tt.prefix).executes(tt.payload))
+		})
+	}
 }
 
 func TestCheckReflectedXSS_ContextCancelled(t *testing.T) {
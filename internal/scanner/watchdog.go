@@ -0,0 +1,328 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/buemura/hunter/internal/redact"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// WatchdogName is the scanner name the Watchdog reports its log entries and
+// result under.
+const WatchdogName = "watchdog"
+
+// watchdogWindow is the number of most recent probes the Watchdog judges
+// the target's error rate over.
+const watchdogWindow = 5
+
+// WatchdogAction is what the Watchdog does once the target stops responding.
+type WatchdogAction string
+
+const (
+	// WatchdogAbort skips every scanner that has not started yet. It is the
+	// default.
+	WatchdogAbort WatchdogAction = "abort"
+	// WatchdogPause pauses the scan through Options.Gate until the target
+	// responds again.
+	WatchdogPause WatchdogAction = "pause"
+)
+
+// ParseWatchdogAction parses a --watchdog-action value; empty means
+// WatchdogAbort.
+func ParseWatchdogAction(s string) (WatchdogAction, error) {
+	switch a := WatchdogAction(strings.ToLower(strings.TrimSpace(s))); a {
+	case "":
+		return WatchdogAbort, nil
+	case WatchdogAbort, WatchdogPause:
+		return a, nil
+	}
+	return "", fmt.Errorf("invalid watchdog action %q (available: abort, pause)", s)
+}
+
+// WatchdogSettings configure the Watchdog of a scan. The zero value
+// disables it.
+type WatchdogSettings struct {
+	// Interval is the time between probes; zero disables the watchdog.
+	Interval time.Duration
+	// Threshold is the share of the last probes, from 0 to 1, that must
+	// fail for the target to be judged down. Zero means 0.8.
+	Threshold float64
+	// Action is what the scan does then.
+	Action WatchdogAction
+}
+
+// outage is a period the target did not respond.
+type outage struct {
+	since     time.Time // first failed probe of the run that tripped
+	downAt    time.Time // when the target was judged down
+	upAt      time.Time // first probe answered again; zero if it never was
+	failed    int       // failed probes of the last window when judged down
+	probes    int       // probes of that window
+	lastError string
+}
+
+// Watchdog probes the target throughout a scan: URL targets with a GET of
+// the URL, others by connecting to their first port, or 443 or 80 by scheme.
+// A probe fails if it gets no answer within Options.Timeout, or, over HTTP,
+// a 502, 503, or 504. Once the share of failed probes among the last few
+// reaches the threshold, the target is judged down and the Watchdog aborts
+// or pauses the rest of the scan, and Stop returns a result explaining when
+// and for how long. While others pause the scan, the target is not probed.
+// RunAll creates one; callers running scanners one by one with RunOne create
+// it with NewWatchdog and start and stop it themselves. The methods of a nil
+// Watchdog do nothing.
+type Watchdog struct {
+	settings WatchdogSettings
+	target   types.Target
+	opts     Options
+	probe    func(ctx context.Context) error
+
+	started time.Time
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	mu      sync.Mutex
+	recent  []bool // outcomes of the last probes, true if failed
+	since   time.Time
+	down    bool
+	paused  bool // the Watchdog paused opts.Gate
+	outages []outage
+}
+
+// NewWatchdog returns the watchdog of a scan of target with opts, or nil if
+// opts.Watchdog disables it. With WatchdogPause it pauses opts.Gate, and with
+// WatchdogAbort it aborts opts.Plan, so set those first.
+func NewWatchdog(target types.Target, opts Options) *Watchdog {
+	s := opts.Watchdog
+	if s.Interval <= 0 || opts.Passive {
+		return nil
+	}
+	if s.Threshold <= 0 || s.Threshold > 1 {
+		s.Threshold = 0.8
+	}
+	if s.Action == "" {
+		s.Action = WatchdogAbort
+	}
+	w := &Watchdog{settings: s, target: target, opts: opts}
+	w.probe = w.probeTarget
+	return w
+}
+
+// Start begins probing the target until Stop is called or ctx is done.
+func (w *Watchdog) Start(ctx context.Context) {
+	if w == nil {
+		return
+	}
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.done = make(chan struct{})
+	w.started = time.Now()
+	go func() {
+		defer close(w.done)
+		ticker := time.NewTicker(w.settings.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+			if w.pausedByOthers() {
+				continue
+			}
+			err := w.probe(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			if !w.observe(err, time.Now()) {
+				return
+			}
+		}
+	}()
+}
+
+// pausedByOthers reports whether the scan is paused, by its user or
+// outside its Window, rather than by the Watchdog. The target is not
+// probed then.
+func (w *Watchdog) pausedByOthers() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return !w.paused && w.opts.Gate.Paused()
+}
+
+// observe records the outcome of a probe made at now and acts on it. It
+// reports whether probing should go on.
+func (w *Watchdog) observe(err error, now time.Time) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	failed := err != nil
+	if failed && (len(w.recent) == 0 || !w.recent[len(w.recent)-1]) {
+		w.since = now
+	}
+	w.recent = append(w.recent, failed)
+	if len(w.recent) > watchdogWindow {
+		w.recent = w.recent[1:]
+	}
+
+	if w.down {
+		if failed {
+			w.outages[len(w.outages)-1].lastError = err.Error()
+			return true
+		}
+		w.down = false
+		w.recent = nil
+		w.outages[len(w.outages)-1].upAt = now
+		if w.paused {
+			w.paused = false
+			w.opts.Gate.Resume()
+		}
+		w.opts.Logf(WatchdogName, LogInfo, "the target responds again; resuming the scan")
+		return true
+	}
+
+	n := 0
+	for _, f := range w.recent {
+		if f {
+			n++
+		}
+	}
+	if !failed || len(w.recent) < watchdogWindow || float64(n) < w.settings.Threshold*float64(len(w.recent)) {
+		return true
+	}
+
+	w.down = true
+	w.outages = append(w.outages, outage{since: w.since, downAt: now, failed: n, probes: len(w.recent), lastError: err.Error()})
+	if w.settings.Action == WatchdogPause {
+		w.paused = w.opts.Gate.Pause()
+		w.opts.Logf(WatchdogName, LogWarn, "%d of the last %d probes failed (%v); pausing the scan until the target responds again", n, len(w.recent), err)
+		return true
+	}
+	w.opts.Plan.Abort("the target became unresponsive")
+	w.opts.Logf(WatchdogName, LogWarn, "%d of the last %d probes failed (%v); skipping the scanners not yet started", n, len(w.recent), err)
+	return false
+}
+
+// Stop stops probing and resumes the scan if the Watchdog paused it. It
+// returns a result with a finding for each time the target became
+// unresponsive, or nil if it never did.
+func (w *Watchdog) Stop() *types.ScanResult {
+	if w == nil || w.cancel == nil {
+		return nil
+	}
+	w.cancel()
+	<-w.done
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.paused {
+		w.paused = false
+		w.opts.Gate.Resume()
+	}
+	if len(w.outages) == 0 {
+		return nil
+	}
+	result := &types.ScanResult{
+		ScannerName: WatchdogName,
+		Target:      w.target,
+		StartedAt:   w.started,
+		CompletedAt: time.Now(),
+	}
+	for _, o := range w.outages {
+		result.Findings = append(result.Findings, w.finding(o, result.CompletedAt))
+	}
+	fingerprint(result)
+	if w.opts.Redact {
+		redact.Result(result)
+	}
+	addMetadata(result, w.opts.Engagement.Metadata())
+	return result
+}
+
+// finding describes o, judged at the end of a scan that ended at end.
+func (w *Watchdog) finding(o outage, end time.Time) types.Finding {
+	var after string
+	switch {
+	case !o.upAt.IsZero():
+		after = fmt.Sprintf("It responded again at %s, %s later, and the scan resumed.",
+			o.upAt.Format(time.RFC3339), o.upAt.Sub(o.since).Round(time.Second))
+	case w.settings.Action == WatchdogPause:
+		after = fmt.Sprintf("It had not responded again when the scan ended at %s.", end.Format(time.RFC3339))
+	default:
+		after = "The scanners that had not started yet were skipped."
+	}
+	metadata := map[string]string{
+		"unresponsive_since": o.since.Format(time.RFC3339),
+		"judged_down_at":     o.downAt.Format(time.RFC3339),
+		"failed_probes":      fmt.Sprintf("%d/%d", o.failed, o.probes),
+		"probe_interval":     w.settings.Interval.String(),
+		"action":             string(w.settings.Action),
+		"last_error":         o.lastError,
+	}
+	if !o.upAt.IsZero() {
+		metadata["responsive_again_at"] = o.upAt.Format(time.RFC3339)
+		metadata["downtime"] = o.upAt.Sub(o.since).Round(time.Second).String()
+	}
+	return types.Finding{
+		Title: "Target became unresponsive",
+		Description: fmt.Sprintf("The target stopped responding during the scan: it failed probes from %s, and was judged down at %s when %d of the last %d probes had failed (last error: %s). %s "+
+			"Findings of scanners that ran meanwhile may be missing or wrong; scan again once the target is stable, or with a lower --intensity or --rate-limit if the scan overloaded it.",
+			o.since.Format(time.RFC3339), o.downAt.Format(time.RFC3339), o.failed, o.probes, o.lastError, after),
+		Severity: types.SeverityInfo,
+		Metadata: metadata,
+	}
+}
+
+// probeTarget makes one probe of the target.
+func (w *Watchdog) probeTarget(ctx context.Context) error {
+	timeout := w.opts.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if w.target.URL == "" {
+		port := 443
+		if w.target.Scheme == "http" {
+			port = 80
+		}
+		if len(w.target.Ports) > 0 {
+			port = w.target.Ports[0]
+		}
+		conn, err := w.opts.Resolver.DialContext(ctx, w.opts.Network(), net.JoinHostPort(w.target.Host, strconv.Itoa(port)))
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, w.target.URL, nil)
+	if err != nil {
+		return err
+	}
+	// A CacheTransport would answer the probe from the scan's cache.
+	req.Header.Set("Cache-Control", "no-cache")
+	client := &http.Client{
+		Transport: w.opts.HTTPTransport(),
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWatchdogAction(t *testing.T) {
+	for in, want := range map[string]WatchdogAction{"": WatchdogAbort, "abort": WatchdogAbort, "Pause": WatchdogPause} {
+		got, err := ParseWatchdogAction(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	_, err := ParseWatchdogAction("stop")
+	assert.Error(t, err)
+}
+
+func TestNewWatchdog_Disabled(t *testing.T) {
+	target := types.Target{Host: "localhost"}
+	assert.Nil(t, NewWatchdog(target, Options{}))
+	assert.Nil(t, NewWatchdog(target, Options{Passive: true, Watchdog: WatchdogSettings{Interval: time.Second}}))
+
+	var w *Watchdog
+	w.Start(context.Background())
+	assert.Nil(t, w.Stop(), "a nil Watchdog does nothing")
+}
+
+func TestWatchdog_JudgesOnTheErrorRate(t *testing.T) {
+	opts := Options{Watchdog: WatchdogSettings{Interval: time.Second, Threshold: 0.6}}
+	opts.Plan = NewPlan([]string{"port"}, opts)
+	w := NewWatchdog(types.Target{Host: "localhost"}, opts)
+	down := errors.New("connection refused")
+	now := time.Now()
+
+	for i, err := range []error{down, nil, down, nil} {
+		assert.True(t, w.observe(err, now.Add(time.Duration(i)*time.Second)))
+	}
+	_, skip := opts.Plan.Skip("port")
+	assert.False(t, skip, "2 of 4 probes failing is below the threshold")
+
+	assert.False(t, w.observe(down, now.Add(4*time.Second)), "3 of the last 5 probes failing trips it")
+	reason, skip := opts.Plan.Skip("port")
+	assert.True(t, skip)
+	assert.Equal(t, "the target became unresponsive", reason)
+}
+
+func TestWatchdog_PausesUntilTheTargetResponds(t *testing.T) {
+	opts := Options{Gate: NewGate(), Watchdog: WatchdogSettings{Interval: time.Second, Action: WatchdogPause}}
+	w := NewWatchdog(types.Target{Host: "localhost"}, opts)
+	// Probes are fed to observe instead.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w.Start(ctx)
+	down := errors.New("i/o timeout")
+	start := time.Now()
+
+	for i := 0; i < watchdogWindow; i++ {
+		assert.True(t, w.observe(down, start.Add(time.Duration(i)*time.Second)))
+	}
+	assert.True(t, opts.Gate.Paused())
+	w.observe(nil, start.Add(30*time.Second))
+	assert.False(t, opts.Gate.Paused(), "the scan resumes once the target responds")
+
+	result := w.Stop()
+	require.NotNil(t, result)
+	require.Len(t, result.Findings, 1)
+	f := result.Findings[0]
+	assert.Equal(t, "Target became unresponsive", f.Title)
+	assert.Equal(t, "30s", f.Metadata["downtime"])
+	assert.Equal(t, "5/5", f.Metadata["failed_probes"])
+	assert.Equal(t, "i/o timeout", f.Metadata["last_error"])
+	assert.NotEmpty(t, f.Fingerprint)
+}
+
+func TestRunAll_WatchdogAbortsWhenTheTargetGoesDown(t *testing.T) {
+	var down atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	names := []string{"port", "headers", "vuln"}
+	reg, log := orderRegistry(names)
+	reg.Register(&downScanner{down: &down})
+	opts := Options{
+		Sequential: true,
+		Timeout:    time.Second,
+		Watchdog:   WatchdogSettings{Interval: 5 * time.Millisecond},
+	}
+
+	target := types.Target{Host: "127.0.0.1", URL: srv.URL}
+	results := NewRunner(reg).RunAll(context.Background(), []string{"port", "takedown", "headers", "vuln"}, target, opts)
+
+	assert.Equal(t, []string{"port"}, log())
+	errs := errorsByScanner(results)
+	assert.Equal(t, "skipped: the target became unresponsive", errs["headers"])
+	assert.Equal(t, "skipped: the target became unresponsive", errs["vuln"])
+	last := results[len(results)-1]
+	assert.Equal(t, WatchdogName, last.ScannerName)
+	require.Len(t, last.Findings, 1)
+	assert.Equal(t, "HTTP 503", last.Findings[0].Metadata["last_error"])
+}
+
+// downScanner takes the target down and waits for the Watchdog to notice.
+type downScanner struct{ down *atomic.Bool }
+
+func (s *downScanner) Name() string        { return "takedown" }
+func (s *downScanner) Description() string { return "takedown" }
+func (s *downScanner) Run(_ context.Context, target types.Target, opts Options) (*types.ScanResult, error) {
+	s.down.Store(true)
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if _, skip := opts.Plan.Skip("headers"); skip {
+			break
+		}
+	}
+	return &types.ScanResult{ScannerName: s.Name(), Target: target}, nil
+}
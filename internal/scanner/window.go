@@ -0,0 +1,156 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Window is the daily period scans may send traffic in, such as 22:00-06:00
+// in the target's time zone, as the rules of an engagement often require.
+// A window whose end is before its start spans midnight. A nil Window is
+// always open.
+type Window struct {
+	start, end int // minutes since midnight
+	loc        *time.Location
+}
+
+// ParseWindow parses "HH:MM-HH:MM", optionally followed by an IANA time
+// zone such as "Europe/Berlin" in which the times are meant; without one
+// they are local. An empty string means no window.
+func ParseWindow(s string) (*Window, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	span, zone, _ := strings.Cut(s, " ")
+	from, to, ok := strings.Cut(span, "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid window %q: want HH:MM-HH:MM, optionally followed by a time zone", s)
+	}
+	start, err := parseClock(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid window %q: %w", s, err)
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid window %q: %w", s, err)
+	}
+	if start == end {
+		return nil, fmt.Errorf("invalid window %q: it starts and ends at the same time", s)
+	}
+	loc := time.Local
+	if zone = strings.TrimSpace(zone); zone != "" {
+		if loc, err = time.LoadLocation(zone); err != nil {
+			return nil, fmt.Errorf("invalid window %q: unknown time zone %q", s, zone)
+		}
+	}
+	return &Window{start: start, end: end, loc: loc}, nil
+}
+
+// parseClock parses "HH:MM" into minutes since midnight.
+func parseClock(s string) (int, error) {
+	h, m, ok := strings.Cut(strings.TrimSpace(s), ":")
+	hours, err1 := strconv.Atoi(h)
+	minutes, err2 := strconv.Atoi(m)
+	if !ok || err1 != nil || err2 != nil || hours < 0 || hours > 23 || minutes < 0 || minutes > 59 {
+		return 0, fmt.Errorf("%q is not a time of day as HH:MM", s)
+	}
+	return hours*60 + minutes, nil
+}
+
+// String returns the window as ParseWindow reads it.
+func (w *Window) String() string {
+	if w == nil {
+		return ""
+	}
+	s := fmt.Sprintf("%02d:%02d-%02d:%02d", w.start/60, w.start%60, w.end/60, w.end%60)
+	if w.loc != time.Local {
+		s += " " + w.loc.String()
+	}
+	return s
+}
+
+// Open reports whether t is within the window.
+func (w *Window) Open(t time.Time) bool {
+	if w == nil {
+		return true
+	}
+	t = t.In(w.loc)
+	m := t.Hour()*60 + t.Minute()
+	if w.start < w.end {
+		return m >= w.start && m < w.end
+	}
+	return m >= w.start || m < w.end
+}
+
+// Next returns when the window next opens or closes after t.
+func (w *Window) Next(t time.Time) time.Time {
+	if w == nil {
+		return time.Time{}
+	}
+	local := t.In(w.loc)
+	var next time.Time
+	for day := 0; day <= 1; day++ {
+		for _, m := range []int{w.start, w.end} {
+			c := time.Date(local.Year(), local.Month(), local.Day()+day, 0, m, 0, 0, w.loc)
+			if c.After(t) && (next.IsZero() || c.Before(next)) {
+				next = c
+			}
+		}
+	}
+	return next
+}
+
+// Hold pauses gate whenever the time is outside the window, and resumes it
+// when the window opens, until release is called or ctx is done. If the
+// window is closed, gate is paused before Hold returns, so nothing waiting
+// on it starts. onChange, when non-nil, is called each time Hold pauses or
+// resumes gate, with whether the window is open and when that changes next.
+// A gate paused by others is left to them.
+func (w *Window) Hold(ctx context.Context, gate *Gate, onChange func(open bool, next time.Time)) (release func()) {
+	if w == nil || gate == nil {
+		return func() {}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	paused := false
+	update := func(now time.Time) {
+		switch open := w.Open(now); {
+		case !open && !paused:
+			paused = gate.Pause()
+		case open && paused:
+			paused = false
+			gate.Resume()
+		default:
+			return
+		}
+		if onChange != nil {
+			onChange(w.Open(now), w.Next(now))
+		}
+	}
+	update(time.Now())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			timer := time.NewTimer(time.Until(w.Next(time.Now())))
+			select {
+			case <-timer.C:
+				update(time.Now())
+			case <-ctx.Done():
+				timer.Stop()
+				if paused {
+					gate.Resume()
+				}
+				return
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
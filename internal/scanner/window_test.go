@@ -0,0 +1,92 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// closedWindow returns a window in UTC that opens an hour from now.
+func closedWindow(t *testing.T) *Window {
+	now := time.Now().UTC()
+	from, to := now.Add(time.Hour), now.Add(2*time.Hour)
+	w, err := ParseWindow(fmt.Sprintf("%02d:%02d-%02d:%02d UTC", from.Hour(), from.Minute(), to.Hour(), to.Minute()))
+	require.NoError(t, err)
+	return w
+}
+
+func TestParseWindow(t *testing.T) {
+	w, err := ParseWindow("22:00-06:30 UTC")
+	require.NoError(t, err)
+	assert.Equal(t, "22:00-06:30 UTC", w.String())
+
+	w, err = ParseWindow("")
+	require.NoError(t, err)
+	assert.Nil(t, w)
+
+	for _, s := range []string{"22:00", "22-06", "25:00-06:00", "22:00-22:00", "22:00-06:00 Nowhere/Special"} {
+		_, err := ParseWindow(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestWindow_Open(t *testing.T) {
+	night, err := ParseWindow("22:00-06:00 UTC")
+	require.NoError(t, err)
+	day, err := ParseWindow("09:00-17:00 UTC")
+	require.NoError(t, err)
+	at := func(h, m int) time.Time { return time.Date(2026, 3, 10, h, m, 0, 0, time.UTC) }
+
+	assert.True(t, night.Open(at(23, 0)))
+	assert.True(t, night.Open(at(5, 59)))
+	assert.False(t, night.Open(at(6, 0)))
+	assert.False(t, night.Open(at(12, 0)))
+	assert.True(t, day.Open(at(9, 0)))
+	assert.False(t, day.Open(at(17, 0)))
+
+	assert.Equal(t, at(22, 0), night.Next(at(12, 0)))
+	assert.Equal(t, at(6, 0), night.Next(at(1, 0)))
+	assert.Equal(t, at(6, 0).AddDate(0, 0, 1), night.Next(at(23, 0)))
+
+	var none *Window
+	assert.True(t, none.Open(at(12, 0)), "a nil window is always open")
+}
+
+func TestWindow_Open_TimeZone(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no time zone database")
+	}
+	w, err := ParseWindow("22:00-06:00 America/New_York")
+	require.NoError(t, err)
+	assert.True(t, w.Open(time.Date(2026, 7, 1, 23, 0, 0, 0, loc)))
+	assert.False(t, w.Open(time.Date(2026, 7, 1, 23, 0, 0, 0, time.UTC)), "19:00 in New York")
+}
+
+func TestWindow_Hold(t *testing.T) {
+	gate := NewGate()
+	var changes []bool
+	release := closedWindow(t).Hold(context.Background(), gate, func(open bool, _ time.Time) {
+		changes = append(changes, open)
+	})
+	assert.True(t, gate.Paused(), "the gate is paused before Hold returns")
+	assert.Equal(t, []bool{false}, changes)
+
+	release()
+	assert.False(t, gate.Paused(), "releasing the window resumes the gate")
+}
+
+func TestRunOne_WaitsForTheWindow(t *testing.T) {
+	reg, log := orderRegistry([]string{"port"})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := NewRunner(reg).RunOne(ctx, "port", types.Target{Host: "localhost"}, Options{Window: closedWindow(t)})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Empty(t, log(), "nothing runs outside the window")
+}
@@ -3,13 +3,40 @@ package tui
 import (
 	"fmt"
 
+	"github.com/buemura/hunter/internal/config"
+	"github.com/buemura/hunter/internal/history"
 	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/tui/styles"
+	"github.com/buemura/hunter/internal/tui/views"
+	"github.com/buemura/hunter/pkg/types"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-// Run starts the interactive TUI with the given scanner registry.
-func Run(reg *scanner.Registry) error {
+// Run starts the interactive TUI with the given scanner registry. Finished
+// scans are recorded in hist, which may be nil to disable history. cfg
+// supplies saved targets, the theme, and key bindings and may be nil.
+// authorize, if not nil, refuses the targets it returns an error for.
+func Run(reg *scanner.Registry, hist *history.Store, cfg *config.Config, authorize func(types.Target) error) error {
 	m := NewModel(reg)
+	if hist != nil {
+		m.SetHistory(hist)
+	}
+	if cfg != nil {
+		theme, err := styles.Resolve(cfg.TUI.Theme, cfg.TUI.Colors)
+		if err != nil {
+			return fmt.Errorf("tui: %w", err)
+		}
+		styles.Apply(theme)
+
+		km, err := views.NewKeyMap(cfg.TUI.Keybindings)
+		if err != nil {
+			return fmt.Errorf("tui: keybindings: %w", err)
+		}
+		views.SetKeyMap(km)
+	}
+	m.SetConfig(cfg)
+	m.SetAuthorize(authorize)
+	m.SetConfigPath(config.ConfigFilePath())
 	p := tea.NewProgram(m, tea.WithAltScreen())
 
 	if _, err := p.Run(); err != nil {
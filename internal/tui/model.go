@@ -1,9 +1,22 @@
 package tui
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/internal/auth"
+	"github.com/buemura/hunter/internal/config"
+	"github.com/buemura/hunter/internal/history"
 	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/tui/styles"
 	"github.com/buemura/hunter/internal/tui/views"
 	"github.com/buemura/hunter/pkg/types"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -11,24 +24,61 @@ import (
 type appState int
 
 const (
-	stateMenu    appState = iota // Scanner selection menu
-	stateTarget                  // Target URL/host input
-	stateScan                    // Scan in progress
-	stateResults                 // Results display
+	stateMenu     appState = iota // Scanner selection menu
+	stateTarget                   // Target URL/host input
+	stateOptions                  // Scan options form
+	stateScan                     // Scan in progress
+	stateResults                  // Results display
+	stateHistory                  // Previous scans
+	stateProfiles                 // Scan profile launcher
 )
 
+// settingCredential is the scan setting naming the config credential to
+// authenticate with. It comes from a scan profile rather than the options
+// form, and is saved with the other settings so re-runs authenticate too.
+const settingCredential = "credential"
+
+// scanRun describes the scan in progress, so it can be saved to history.
+type scanRun struct {
+	target   types.Target
+	scanners []string
+	settings map[string]string
+}
+
 // Model is the root Bubble Tea model that manages view transitions.
 type Model struct {
 	state    appState
 	registry *scanner.Registry
 	width    int
 	height   int
+	// showHelp is set while the key help overlay covers the current view.
+	showHelp bool
 
 	// Sub-models for each view.
-	menu    views.MenuModel
-	target  views.TargetModel
-	scan    views.ScanModel
-	results views.ResultsModel
+	menu     views.MenuModel
+	target   views.TargetModel
+	options  views.OptionsModel
+	scan     views.ScanModel
+	results  views.ResultsModel
+	history  views.HistoryModel
+	profiles views.ProfilesModel
+
+	// historyStore records finished scans; nil disables history.
+	historyStore *history.Store
+	run          scanRun
+	// preset holds the settings of the scan profile picked in the launcher,
+	// applied to the options form; nil when the scanners came from the menu.
+	preset map[string]string
+	// profile is the name of that scan profile.
+	profile string
+
+	// config supplies saved targets; nil means none.
+	config *config.Config
+	// configPath is the config file a theme change is saved to; empty
+	// disables saving.
+	configPath string
+	// authorize refuses targets that may not be scanned; nil allows all.
+	authorize func(types.Target) error
 }
 
 // NewModel creates a root model with the given scanner registry.
@@ -42,14 +92,110 @@ func NewModel(reg *scanner.Registry) Model {
 		}
 	}
 
+	menu := views.NewMenuModel(items)
+	menu.SetGroups(scannerGroups(items))
+
 	return Model{
 		state:    stateMenu,
 		registry: reg,
-		menu:     views.NewMenuModel(items),
+		menu:     menu,
 		target:   views.NewTargetModel(),
 	}
 }
 
+// SetHistory enables the scan history screen and records finished scans in
+// store.
+func (m *Model) SetHistory(store *history.Store) {
+	m.historyStore = store
+}
+
+// SetConfig sets the loaded configuration, whose saved and default targets
+// are offered in the target dropdown.
+func (m *Model) SetConfig(cfg *config.Config) {
+	m.config = cfg
+}
+
+// SetConfigPath sets the config file that theme changes are saved to.
+func (m *Model) SetConfigPath(path string) {
+	m.configPath = path
+}
+
+// SetAuthorize sets a check that refuses the targets it returns an error
+// for, whether typed in or re-run; nil allows every target.
+func (m *Model) SetAuthorize(authorize func(types.Target) error) {
+	m.authorize = authorize
+}
+
+// maxRecentTargets caps how many previously scanned targets are suggested.
+const maxRecentTargets = 10
+
+// targetSuggestions lists the saved targets from the config file, then the
+// default target, then recently scanned targets, newest first, without
+// duplicates.
+func (m Model) targetSuggestions() []views.TargetSuggestion {
+	var out []views.TargetSuggestion
+	seen := map[string]bool{}
+	add := func(label, value string) {
+		if value == "" || seen[value] {
+			return
+		}
+		seen[value] = true
+		out = append(out, views.TargetSuggestion{Label: label, Value: value})
+	}
+
+	if m.config != nil {
+		for _, t := range m.config.SavedTargets {
+			add(t.Name, t.Target)
+		}
+		add("default", m.config.DefaultTarget)
+	}
+	if m.historyStore != nil {
+		entries, _ := m.historyStore.List()
+		recent := 0
+		for _, e := range entries {
+			if recent == maxRecentTargets {
+				break
+			}
+			value := e.Target.URL
+			if value == "" {
+				value = e.Target.Host
+			}
+			if !seen[value] {
+				recent++
+			}
+			add("recent", value)
+		}
+	}
+	return out
+}
+
+// scannerGroups builds the "all", "web", and "api" shortcut rows for the
+// menu. API scanners are the ones named api or api-*; the rest are web
+// scanners. Empty groups are left out.
+func scannerGroups(items []views.ScannerItem) []views.ScannerGroup {
+	var all, web, api []string
+	for _, item := range items {
+		all = append(all, item.Name)
+		if item.Name == "api" || strings.HasPrefix(item.Name, "api-") {
+			api = append(api, item.Name)
+		} else {
+			web = append(web, item.Name)
+		}
+	}
+
+	var groups []views.ScannerGroup
+	if len(all) > 0 {
+		groups = append(groups, views.ScannerGroup{Name: "all", Description: "Every scanner", Members: all})
+	}
+	if len(web) > 0 {
+		groups = append(groups, views.ScannerGroup{Name: "web", Description: "Web application scanners", Members: web})
+	}
+	if len(api) > 0 {
+		groups = append(groups, views.ScannerGroup{Name: "api", Description: "API scanners", Members: api})
+	}
+	return groups
+}
+
 // Init returns the initial command.
 func (m Model) Init() tea.Cmd {
 	return m.target.Init()
@@ -59,16 +205,31 @@ func (m Model) Init() tea.Cmd {
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c":
+		if msg.String() == "ctrl+c" {
 			return m, tea.Quit
-		case "esc":
+		}
+		if m.showHelp {
+			// Any key closes the help overlay.
+			m.showHelp = false
+			return m, nil
+		}
+		// In text inputs only a non-printable help key such as f1 opens the
+		// overlay; ? is typed.
+		if key.Matches(msg, views.Keys().Help) && (!m.typing() || msg.Type != tea.KeyRunes) {
+			m.showHelp = true
+			return m, nil
+		}
+		// Back closes the results search box, export dialog, or detail
+		// pane before it leaves the view.
+		if key.Matches(msg, views.Keys().Back) && (m.state != stateResults || !m.results.Capturing()) {
 			return m.handleBack()
 		}
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.resize()
+		return m, nil
 	}
 
 	switch m.state {
@@ -76,36 +237,113 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateMenu(msg)
 	case stateTarget:
 		return m.updateTarget(msg)
+	case stateOptions:
+		return m.updateOptions(msg)
 	case stateScan:
 		return m.updateScan(msg)
 	case stateResults:
 		return m.updateResults(msg)
+	case stateHistory:
+		return m.updateHistory(msg)
+	case stateProfiles:
+		return m.updateProfiles(msg)
 	}
 
 	return m, nil
 }
 
+// resize lays every view out for the current terminal size. Views created
+// later are sized when they are created.
+func (m *Model) resize() {
+	m.menu.SetSize(m.width, m.height)
+	m.target.SetSize(m.width, m.height)
+	m.options.SetSize(m.width, m.height)
+	m.scan.SetSize(m.width, m.height)
+	m.results.SetSize(m.width, m.height)
+	m.history.SetSize(m.width, m.height)
+	m.profiles.SetSize(m.width, m.height)
+}
+
+// typing reports whether keys are going to a text input.
+func (m Model) typing() bool {
+	switch m.state {
+	case stateTarget, stateOptions:
+		return true
+	case stateResults:
+		return m.results.Capturing() && !m.results.Detail()
+	}
+	return false
+}
+
 // View renders the current view.
 func (m Model) View() string {
+	if m.showHelp {
+		return m.helpView()
+	}
 	switch m.state {
 	case stateMenu:
 		return m.menu.View()
 	case stateTarget:
 		return m.target.View()
+	case stateOptions:
+		return m.options.View()
 	case stateScan:
 		return m.scan.View()
 	case stateResults:
 		return m.results.View()
+	case stateHistory:
+		return m.history.View()
+	case stateProfiles:
+		return m.profiles.View()
 	}
 	return ""
 }
 
+// helpView renders the help overlay: the keys of the current view, then the
+// ones that work everywhere.
+func (m Model) helpView() string {
+	km := views.Keys()
+	var current views.HelpSection
+	switch m.state {
+	case stateMenu:
+		current = views.HelpSection{Title: "Scanner menu", Bindings: m.menu.Bindings()}
+	case stateTarget:
+		current = views.HelpSection{Title: "Target", Bindings: m.target.Bindings()}
+	case stateOptions:
+		current = views.HelpSection{Title: "Scan options", Bindings: m.options.Bindings()}
+	case stateScan:
+		current = views.HelpSection{Title: "Scan", Bindings: m.scan.Bindings()}
+	case stateResults:
+		current = views.HelpSection{Title: "Results", Bindings: m.results.Bindings()}
+	case stateHistory:
+		current = views.HelpSection{Title: "Scan history", Bindings: m.history.Bindings()}
+	case stateProfiles:
+		current = views.HelpSection{Title: "Scan profiles", Bindings: m.profiles.Bindings()}
+	}
+
+	global := views.HelpSection{Title: "Everywhere", Bindings: []key.Binding{
+		km.Help,
+		key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit immediately")),
+	}}
+	width := m.width
+	if width <= 0 {
+		width = 80
+	}
+	return views.RenderHelp([]views.HelpSection{current, global}, width)
+}
+
 func (m Model) handleBack() (tea.Model, tea.Cmd) {
 	switch m.state {
 	case stateTarget:
 		m.state = stateMenu
+		if m.profile != "" {
+			m.state = stateProfiles
+		}
 		return m, nil
-	case stateResults:
+	case stateOptions:
+		m.state = stateTarget
+		return m, nil
+	case stateResults, stateHistory, stateProfiles:
 		m.state = stateMenu
 		return m, nil
 	}
@@ -113,13 +351,26 @@ func (m Model) handleBack() (tea.Model, tea.Cmd) {
 }
 
 func (m Model) updateMenu(msg tea.Msg) (tea.Model, tea.Cmd) {
-	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" {
-		selected := m.menu.Selected()
-		if selected != nil {
-			m.target = views.NewTargetModel()
-			m.target.SetScannerName(selected.Name)
-			m.state = stateTarget
-			return m, m.target.Init()
+	km := views.Keys()
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, km.Theme) {
+		return m.switchTheme()
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, km.History) && m.historyStore != nil {
+		m.history = views.NewHistoryModel(m.historyStore)
+		m.history.SetSize(m.width, m.height)
+		m.state = stateHistory
+		return m, nil
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, km.Profiles) {
+		m.profiles = views.NewProfilesModel(m.profileItems())
+		m.profiles.SetSize(m.width, m.height)
+		m.state = stateProfiles
+		return m, nil
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, km.Select) {
+		if selected := m.menu.Selection(); len(selected) > 0 {
+			m.preset, m.profile = nil, ""
+			return m.openTarget(selected)
 		}
 	}
 
@@ -128,19 +379,135 @@ func (m Model) updateMenu(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-func (m Model) updateTarget(msg tea.Msg) (tea.Model, tea.Cmd) {
-	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" {
-		target, err := m.target.ValidatedTarget()
-		if err == nil {
-			scannerName := m.target.ScannerName()
-			s, sErr := m.registry.Get(scannerName)
-			if sErr != nil {
+// openTarget switches to the target view for the given scanners.
+func (m Model) openTarget(names []string) (tea.Model, tea.Cmd) {
+	m.target = views.NewTargetModel()
+	m.target.SetScannerNames(names)
+	m.target.SetAuthorize(m.authorize)
+	m.target.SetSuggestions(m.targetSuggestions())
+	m.target.SetSize(m.width, m.height)
+	m.state = stateTarget
+	return m, m.target.Init()
+}
+
+// profileItems lists the scan profiles from the config file.
+func (m Model) profileItems() []views.ProfileItem {
+	if m.config == nil {
+		return nil
+	}
+	items := make([]views.ProfileItem, len(m.config.ScanProfiles))
+	for i, p := range m.config.ScanProfiles {
+		items[i] = views.ProfileItem{Name: p.Name, Scanners: p.Scanners, Credential: p.Credential}
+	}
+	return items
+}
+
+func (m Model) updateProfiles(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, views.Keys().Select) {
+		p := m.profiles.Selected()
+		if p == nil {
+			return m, nil
+		}
+		if len(p.Scanners) == 0 {
+			m.profiles.SetError(fmt.Sprintf("scan profile %s has no scanners", p.Name))
+			return m, nil
+		}
+		for _, name := range p.Scanners {
+			if _, err := m.registry.Get(name); err != nil {
+				m.profiles.SetError(fmt.Sprintf("scan profile %s: %v", p.Name, err))
 				return m, nil
 			}
-			m.scan = views.NewScanModel(s, target)
-			m.state = stateScan
-			return m, m.scan.Init()
 		}
+		m.preset, m.profile = m.profilePreset(*p), p.Name
+		return m.openTarget(p.Scanners)
+	}
+
+	updated, cmd := m.profiles.Update(msg)
+	m.profiles = updated.(views.ProfilesModel)
+	return m, cmd
+}
+
+// profilePreset returns the settings a scan profile fills the options form
+// with: the config file's per-scanner settings for the profile's scanners,
+// its timeout and concurrency, and the profile's credential, so the scan
+// matches what the CLI runs for the profile.
+func (m Model) profilePreset(p views.ProfileItem) map[string]string {
+	preset := map[string]string{}
+	for _, name := range p.Scanners {
+		keys := make([]string, 0, len(m.config.Scanners[name]))
+		for key := range m.config.Scanners[name] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			preset[key] = settingString(m.config.Scanners[name][key])
+		}
+	}
+	// The form's timeout and concurrency fields.
+	if m.config.Timeout > 0 {
+		preset["timeout"] = m.config.Timeout.String()
+	}
+	if m.config.Concurrency > 0 {
+		preset["concurrency"] = strconv.Itoa(m.config.Concurrency)
+	}
+	if p.Credential != "" {
+		preset[settingCredential] = p.Credential
+	}
+	return preset
+}
+
+// settingString formats a config file setting as typed into the options
+// form; lists such as ports: [22, 80] become "22,80".
+func settingString(value interface{}) string {
+	if list, ok := value.([]interface{}); ok {
+		parts := make([]string, len(list))
+		for i, v := range list {
+			parts[i] = fmt.Sprint(v)
+		}
+		return strings.Join(parts, ",")
+	}
+	return fmt.Sprint(value)
+}
+
+// switchTheme applies the next built-in theme and saves it as tui.theme.
+func (m Model) switchTheme() (tea.Model, tea.Cmd) {
+	theme := styles.Next(styles.Current())
+	if m.config != nil {
+		// Keep the configured color overrides across theme changes.
+		if custom, err := styles.Resolve(theme.Name, m.config.TUI.Colors); err == nil {
+			theme = custom
+		}
+	}
+	styles.Apply(theme)
+
+	status := "Theme: " + theme.Name
+	if m.configPath != "" {
+		if err := config.SaveTheme(m.configPath, theme.Name); err != nil {
+			status += fmt.Sprintf(" (not saved: %v)", err)
+		} else {
+			status += " (saved to " + m.configPath + ")"
+		}
+	}
+	m.menu.SetStatus(status)
+	return m, nil
+}
+
+func (m Model) updateTarget(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(views.TargetReadyMsg); ok {
+		m.options = views.NewOptionsModel(m.target.ScannerNames())
+		for key, value := range m.preset {
+			m.options.SetValue(key, value)
+		}
+		if m.profile != "" {
+			note := "Filled in from scan profile " + m.profile
+			if cred := m.preset[settingCredential]; cred != "" {
+				note += ", scanning as credential " + cred
+			}
+			m.options.SetNote(note)
+		}
+		m.options.SetSize(m.width, m.height)
+		m.state = stateOptions
+		return m, m.options.Init()
 	}
 
 	updated, cmd := m.target.Update(msg)
@@ -148,9 +515,113 @@ func (m Model) updateTarget(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+func (m Model) updateOptions(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" {
+		target, err := m.target.ValidatedTarget()
+		if _, oErr := m.options.Options(); err == nil && oErr == nil {
+			settings := m.options.Values()
+			if cred := m.preset[settingCredential]; cred != "" {
+				settings[settingCredential] = cred
+			}
+			return m.startScan(target, m.target.ScannerNames(), settings)
+		}
+	}
+
+	updated, cmd := m.options.Update(msg)
+	m.options = updated.(views.OptionsModel)
+	return m, cmd
+}
+
+// authorizeTarget applies the check set with SetAuthorize, if any.
+func (m Model) authorizeTarget(target types.Target) error {
+	if m.authorize == nil {
+		return nil
+	}
+	return m.authorize(target)
+}
+
+// startScan switches to the scan view for the given scanners, with options
+// rebuilt from settings as entered in the options form.
+func (m Model) startScan(target types.Target, names []string, settings map[string]string) (tea.Model, tea.Cmd) {
+	form := views.NewOptionsModel(names)
+	for key, value := range settings {
+		form.SetValue(key, value)
+	}
+	opts, err := form.Options()
+	if err != nil {
+		return m, nil
+	}
+	if cred := settings[settingCredential]; cred != "" {
+		a, hosts, aErr := m.authenticate(cred, opts.Timeout)
+		if aErr != nil {
+			switch m.state {
+			case stateOptions:
+				m.options.SetError(aErr.Error())
+			case stateResults:
+				m.results.SetNotice(aErr.Error())
+			}
+			return m, nil
+		}
+		opts.Authenticate = a
+		opts.AuthHosts = hosts
+	}
+	opts.Preflight = scanner.NewPreflight()
+	opts.Authorize = m.authorize
+
+	var scanners []scanner.Scanner
+	for _, name := range names {
+		s, sErr := m.registry.Get(name)
+		if sErr != nil {
+			return m, nil
+		}
+		scanners = append(scanners, s)
+	}
+
+	m.run = scanRun{target: target, scanners: names, settings: settings}
+	m.scan = views.NewScanModel(scanners, target, opts)
+	m.scan.SetSize(m.width, m.height)
+	m.state = stateScan
+	return m, m.scan.Init()
+}
+
+// authenticate resolves the named credential from the config file, logging
+// in if its type requires it. It also returns the hosts besides the
+// target's the credential is sent to.
+func (m Model) authenticate(name string, timeout time.Duration) (auth.Authenticator, []string, error) {
+	if m.config == nil {
+		return nil, nil, fmt.Errorf("credential %q: no configuration loaded", name)
+	}
+	cred, err := m.config.GetCredential(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout*2)
+	defer cancel()
+
+	a, err := auth.New(ctx, cred, &http.Client{Timeout: timeout})
+	if err != nil {
+		return nil, nil, fmt.Errorf("credential %q: %w", name, err)
+	}
+	return a, auth.Hosts(cred), nil
+}
+
 func (m Model) updateScan(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if scanMsg, ok := msg.(views.ScanCompleteMsg); ok {
-		m.results = views.NewResultsModel([]types.ScanResult{scanMsg.Result})
+		if m.historyStore != nil {
+			// History is best effort: a failed save must not hide the results.
+			_, _ = m.historyStore.Save(history.Entry{
+				Target:   m.run.target,
+				Scanners: m.run.scanners,
+				Settings: m.run.settings,
+				Results:  scanMsg.Results,
+			})
+		}
+		m.results = views.NewResultsModel(scanMsg.Results)
+		m.results.SetSize(m.width, m.height)
+		if scanMsg.Cancelled {
+			m.results.SetNotice("Scan cancelled — showing partial results")
+		}
 		m.state = stateResults
 		return m, nil
 	}
@@ -161,7 +632,42 @@ func (m Model) updateScan(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) updateResults(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Rerun runs the scan that produced these results again, e.g. to verify
+	// a fix.
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, views.Keys().Rerun) && !m.results.Capturing() && len(m.run.scanners) > 0 {
+		if err := m.authorizeTarget(m.run.target); err != nil {
+			m.results.SetNotice(err.Error())
+			return m, nil
+		}
+		return m.startScan(m.run.target, m.run.scanners, m.run.settings)
+	}
+
 	updated, cmd := m.results.Update(msg)
 	m.results = updated.(views.ResultsModel)
 	return m, cmd
 }
+
+func (m Model) updateHistory(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		if e := m.history.Selected(); e != nil {
+			switch km := views.Keys(); {
+			case key.Matches(keyMsg, km.Select):
+				m.results = views.NewResultsModel(e.Results)
+				m.results.SetSize(m.width, m.height)
+				m.run = scanRun{target: e.Target, scanners: e.Scanners, settings: e.Settings}
+				m.state = stateResults
+				return m, nil
+			case key.Matches(keyMsg, km.Rerun):
+				if err := m.authorizeTarget(e.Target); err != nil {
+					m.history.SetError(err.Error())
+					return m, nil
+				}
+				return m.startScan(e.Target, e.Scanners, e.Settings)
+			}
+		}
+	}
+
+	updated, cmd := m.history.Update(msg)
+	m.history = updated.(views.HistoryModel)
+	return m, cmd
+}
@@ -1,13 +1,24 @@
 package tui
 
 import (
+	"errors"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/buemura/hunter/internal/config"
+	"github.com/buemura/hunter/internal/history"
 	"github.com/buemura/hunter/internal/scanner"
 	"github.com/buemura/hunter/internal/scanner/port"
 	"github.com/buemura/hunter/internal/scanner/headers"
+	"github.com/buemura/hunter/internal/tui/styles"
+	"github.com/buemura/hunter/internal/tui/views"
+	"github.com/buemura/hunter/pkg/types"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func newTestRegistry() *scanner.Registry {
@@ -66,3 +77,321 @@ func TestModelWindowSizeMsg(t *testing.T) {
 	assert.Equal(t, 120, model.width)
 	assert.Equal(t, 40, model.height)
 }
+
+func TestModelWindowSizeAppliesToViews(t *testing.T) {
+	m := NewModel(newTestRegistry())
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 50, Height: 20})
+	m = updated.(Model)
+
+	for _, line := range strings.Split(m.View(), "\n") {
+		assert.LessOrEqual(t, lipgloss.Width(line), 50, line)
+	}
+
+	// Views created after the resize are sized too.
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	require.Equal(t, stateTarget, m.state)
+	for _, line := range strings.Split(m.View(), "\n") {
+		assert.LessOrEqual(t, lipgloss.Width(line), 50, line)
+	}
+}
+
+func TestNewModelAddsScannerGroups(t *testing.T) {
+	groups := scannerGroups([]views.ScannerItem{{Name: "port"}, {Name: "api"}, {Name: "api-cors"}})
+	require.Len(t, groups, 3)
+	assert.Equal(t, []string{"port", "api", "api-cors"}, groups[0].Members)
+	assert.Equal(t, []string{"port"}, groups[1].Members)
+	assert.Equal(t, []string{"api", "api-cors"}, groups[2].Members)
+}
+
+func TestModelEnterPassesSelectionToTarget(t *testing.T) {
+	m := NewModel(newTestRegistry())
+
+	// The cursor starts on the "all" group.
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model := updated.(Model)
+	assert.Equal(t, stateTarget, model.state)
+	assert.ElementsMatch(t, []string{"port", "headers"}, model.target.ScannerNames())
+}
+
+func TestModelTargetEnterOpensOptions(t *testing.T) {
+	m := NewModel(newTestRegistry())
+	m.state = stateTarget
+	m.target.SetScannerNames([]string{"port"})
+	for _, r := range "127.0.0.1" {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+
+	// An IP address needs no DNS check, so the target is ready at once.
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	require.NotNil(t, cmd)
+	ready, ok := cmd().(views.TargetReadyMsg)
+	require.True(t, ok)
+	assert.Equal(t, "127.0.0.1", ready.Target.Host)
+
+	updated, _ = m.Update(ready)
+	m = updated.(Model)
+	assert.Equal(t, stateOptions, m.state)
+	assert.Contains(t, m.View(), "Ports")
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	assert.Equal(t, stateTarget, updated.(Model).state)
+}
+
+func TestModelProfileLauncher(t *testing.T) {
+	m := NewModel(newTestRegistry())
+	m.SetConfig(&config.Config{
+		Timeout:      10 * time.Second,
+		ScanProfiles: []config.ScanProfile{{Name: "quick", Scanners: []string{"port", "headers"}}},
+		Scanners:     map[string]map[string]interface{}{"port": {"ports": []interface{}{22, 80}}},
+	})
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	m = updated.(Model)
+	require.Equal(t, stateProfiles, m.state)
+	assert.Contains(t, m.View(), "quick")
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	require.Equal(t, stateTarget, m.state)
+	assert.Equal(t, []string{"port", "headers"}, m.target.ScannerNames())
+
+	updated, _ = m.Update(views.TargetReadyMsg{Target: types.Target{Host: "127.0.0.1"}})
+	m = updated.(Model)
+	require.Equal(t, stateOptions, m.state)
+	assert.Equal(t, "22,80", m.options.Value("ports"))
+	assert.Equal(t, "10s", m.options.Value("timeout"))
+	assert.Contains(t, m.View(), "scan profile quick")
+
+	// Back from the target view returns to the launcher.
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	assert.Equal(t, stateProfiles, updated.(Model).state)
+}
+
+func TestModelProfileLauncherRejectsUnknownScanner(t *testing.T) {
+	m := NewModel(newTestRegistry())
+	m.SetConfig(&config.Config{ScanProfiles: []config.ScanProfile{{Name: "broken", Scanners: []string{"nope"}}}})
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	assert.Equal(t, stateProfiles, m.state)
+	assert.Contains(t, m.View(), "scan profile broken")
+}
+
+func TestModelProfileCredentialMustExist(t *testing.T) {
+	m := NewModel(newTestRegistry())
+	m.SetConfig(&config.Config{ScanProfiles: []config.ScanProfile{{Name: "authed", Scanners: []string{"headers"}, Credential: "missing"}}})
+	m.state = stateProfiles
+	m.profiles = views.NewProfilesModel(m.profileItems())
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updated, _ = updated.Update(views.TargetReadyMsg{Target: types.Target{Host: "127.0.0.1"}})
+	m = updated.(Model)
+	m.target.SetValue("127.0.0.1")
+	assert.Contains(t, m.View(), "scanning as credential missing")
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	assert.Equal(t, stateOptions, m.state)
+	assert.Contains(t, m.View(), `credential "missing" is not defined`)
+}
+
+func TestModelTargetSuggestions(t *testing.T) {
+	store := history.NewStore(t.TempDir())
+	_, err := store.Save(history.Entry{Target: types.Target{URL: "https://old.example.com"}, CreatedAt: time.Now().Add(-time.Hour)})
+	require.NoError(t, err)
+	_, err = store.Save(history.Entry{Target: types.Target{URL: "https://staging.example.com"}, CreatedAt: time.Now()})
+	require.NoError(t, err)
+
+	m := NewModel(newTestRegistry())
+	m.SetHistory(store)
+	m.SetConfig(&config.Config{
+		DefaultTarget: "https://example.com",
+		SavedTargets:  []config.SavedTarget{{Name: "staging", Target: "https://staging.example.com"}},
+	})
+
+	assert.Equal(t, []views.TargetSuggestion{
+		{Label: "staging", Value: "https://staging.example.com"},
+		{Label: "default", Value: "https://example.com"},
+		{Label: "recent", Value: "https://old.example.com"},
+	}, m.targetSuggestions())
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	require.Equal(t, stateTarget, m.state)
+	assert.Contains(t, m.View(), "https://old.example.com")
+}
+
+func TestModelSwitchesAndSavesTheme(t *testing.T) {
+	defer styles.Apply(styles.Current())
+	styles.Apply(styles.Dark)
+
+	path := filepath.Join(t.TempDir(), ".hunter.yaml")
+	m := NewModel(newTestRegistry())
+	m.SetConfig(&config.Config{TUI: config.TUI{Colors: map[string]string{"critical": "#FF00FF"}}})
+	m.SetConfigPath(path)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	m = updated.(Model)
+	assert.Equal(t, "light", styles.Current().Name)
+	assert.Equal(t, lipgloss.Color("#FF00FF"), styles.Current().Critical, "color overrides survive a theme change")
+	assert.Contains(t, m.View(), "Theme: light")
+
+	cfg, err := config.LoadFromFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "light", cfg.TUI.Theme)
+}
+
+func TestModelHelpOverlay(t *testing.T) {
+	m := NewModel(newTestRegistry())
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	m = updated.(Model)
+	require.True(t, m.showHelp)
+	view := m.View()
+	assert.Contains(t, view, "Keyboard Shortcuts")
+	assert.Contains(t, view, "Scanner menu")
+	assert.Contains(t, view, "ctrl+c")
+
+	// Any key closes the overlay without acting on the view.
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	m = updated.(Model)
+	assert.False(t, m.showHelp)
+	assert.Equal(t, 0, m.menu.Cursor())
+}
+
+func TestModelHelpKeyIsTypedInTextInputs(t *testing.T) {
+	m := NewModel(newTestRegistry())
+	m.state = stateTarget
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	m = updated.(Model)
+	assert.False(t, m.showHelp)
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyF1})
+	m = updated.(Model)
+	assert.True(t, m.showHelp)
+	assert.Contains(t, m.View(), "Target")
+}
+
+func TestModelUsesRemappedKeys(t *testing.T) {
+	km, err := views.NewKeyMap(map[string][]string{"back": {"backspace"}})
+	require.NoError(t, err)
+	views.SetKeyMap(km)
+	defer views.SetKeyMap(views.DefaultKeyMap())
+
+	m := NewModel(newTestRegistry())
+	m.state = stateHistory
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	assert.Equal(t, stateHistory, updated.(Model).state)
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	assert.Equal(t, stateMenu, updated.(Model).state)
+}
+
+func TestModelEscClosesResultsSearchFirst(t *testing.T) {
+	m := NewModel(newTestRegistry())
+	m.state = stateResults
+	m.results = views.NewResultsModel(nil)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m = updated.(Model)
+	require.True(t, m.results.Searching())
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	m = updated.(Model)
+	assert.Equal(t, stateResults, m.state)
+	assert.False(t, m.results.Searching())
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	assert.Equal(t, stateMenu, updated.(Model).state)
+}
+
+func TestModelSavesScanToHistoryAndReopens(t *testing.T) {
+	store := history.NewStore(t.TempDir())
+	m := NewModel(newTestRegistry())
+	m.SetHistory(store)
+	m.state = stateScan
+	m.run = scanRun{
+		target:   types.Target{Host: "example.com"},
+		scanners: []string{"port"},
+		settings: map[string]string{"ports": "80"},
+	}
+
+	results := []types.ScanResult{{ScannerName: "port", Findings: []types.Finding{{Title: "Open port: 80", Severity: types.SeverityInfo}}}}
+	updated, _ := m.Update(views.ScanCompleteMsg{Results: results})
+	m = updated.(Model)
+	assert.Equal(t, stateResults, m.state)
+
+	entries, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "80", entries[0].Settings["ports"])
+
+	// h opens the history from the menu; enter reopens the results.
+	m.state = stateMenu
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	m = updated.(Model)
+	require.Equal(t, stateHistory, m.state)
+	assert.Contains(t, m.View(), "example.com")
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	assert.Equal(t, stateResults, m.state)
+	assert.Contains(t, m.View(), "Open port: 80")
+
+	// r re-runs the scan with the saved settings.
+	m.state = stateHistory
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = updated.(Model)
+	assert.Equal(t, stateScan, m.state)
+	assert.Equal(t, []string{"port"}, m.run.scanners)
+	assert.Equal(t, "80", m.run.settings["ports"])
+}
+
+func TestModelCancelledScanShowsPartialResults(t *testing.T) {
+	m := NewModel(newTestRegistry())
+	m.state = stateScan
+
+	updated, _ := m.Update(views.ScanCompleteMsg{Cancelled: true})
+	m = updated.(Model)
+	assert.Equal(t, stateResults, m.state)
+	assert.Contains(t, m.View(), "partial results")
+}
+
+func TestModelResultsRerun(t *testing.T) {
+	m := NewModel(newTestRegistry())
+	m.state = stateResults
+	m.results = views.NewResultsModel(nil)
+
+	// Nothing to re-run yet.
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = updated.(Model)
+	assert.Equal(t, stateResults, m.state)
+
+	m.run = scanRun{target: types.Target{Host: "example.com"}, scanners: []string{"headers"}}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = updated.(Model)
+	assert.Equal(t, stateScan, m.state)
+	assert.NotNil(t, cmd)
+}
+
+func TestModelRerunRefusesUnauthorizedTarget(t *testing.T) {
+	m := NewModel(newTestRegistry())
+	m.SetAuthorize(func(target types.Target) error {
+		return errors.New("target " + target.Host + " is not in authorized_targets")
+	})
+	m.state = stateResults
+	m.results = views.NewResultsModel(nil)
+	m.run = scanRun{target: types.Target{Host: "example.com"}, scanners: []string{"headers"}}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = updated.(Model)
+	assert.Equal(t, stateResults, m.state)
+	assert.Contains(t, m.View(), "not in authorized_targets")
+}
@@ -4,53 +4,93 @@ import "github.com/charmbracelet/lipgloss"
 
 // Severity colors.
 var (
-	ColorCritical = lipgloss.Color("#FF0000")
-	ColorHigh     = lipgloss.Color("#FF6600")
-	ColorMedium   = lipgloss.Color("#FFCC00")
-	ColorLow      = lipgloss.Color("#00CC00")
-	ColorInfo     = lipgloss.Color("#0099FF")
-	ColorMuted    = lipgloss.Color("#666666")
-	ColorAccent   = lipgloss.Color("#7D56F4")
+	ColorCritical lipgloss.Color
+	ColorHigh     lipgloss.Color
+	ColorMedium   lipgloss.Color
+	ColorLow      lipgloss.Color
+	ColorInfo     lipgloss.Color
+	ColorMuted    lipgloss.Color
+	ColorAccent   lipgloss.Color
 )
 
-// Styles used across TUI views.
+// Styles used across TUI views. They are rebuilt by Apply.
 var (
+	TitleStyle    lipgloss.Style
+	HeaderStyle   lipgloss.Style
+	BorderStyle   lipgloss.Style
+	SelectedStyle lipgloss.Style
+	CursorStyle   lipgloss.Style
+	HelpStyle     lipgloss.Style
+	ErrorStyle    lipgloss.Style
+
+	SeverityCriticalStyle lipgloss.Style
+	SeverityHighStyle     lipgloss.Style
+	SeverityMediumStyle   lipgloss.Style
+	SeverityLowStyle      lipgloss.Style
+	SeverityInfoStyle     lipgloss.Style
+)
+
+func init() {
+	Apply(Dark)
+}
+
+// current is the theme last passed to Apply.
+var current Theme
+
+// Current returns the theme in use.
+func Current() Theme {
+	return current
+}
+
+// Apply switches every color and style to theme t. Views pick the change up
+// the next time they render.
+func Apply(t Theme) {
+	current = t
+
+	ColorCritical = t.Critical
+	ColorHigh = t.High
+	ColorMedium = t.Medium
+	ColorLow = t.Low
+	ColorInfo = t.Info
+	ColorMuted = t.Muted
+	ColorAccent = t.Accent
+
 	TitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#FAFAFA")).
-			Background(ColorAccent).
-			Padding(0, 1)
+		Bold(true).
+		Foreground(t.Title).
+		Background(t.Accent).
+		Padding(0, 1)
 
 	HeaderStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(ColorAccent).
-			MarginBottom(1)
+		Bold(true).
+		Foreground(t.Accent).
+		MarginBottom(1)
 
 	BorderStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(ColorAccent).
-			Padding(1, 2)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Accent).
+		Padding(1, 2)
 
 	SelectedStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(ColorAccent)
+		Bold(true).
+		Foreground(t.Accent)
 
 	CursorStyle = lipgloss.NewStyle().
-			Foreground(ColorAccent)
+		Foreground(t.Accent)
 
 	HelpStyle = lipgloss.NewStyle().
-			Foreground(ColorMuted)
+		Foreground(t.Muted)
 
 	ErrorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FF0000")).
-			Bold(true)
-
-	SeverityCriticalStyle = lipgloss.NewStyle().Bold(true).Foreground(ColorCritical)
-	SeverityHighStyle     = lipgloss.NewStyle().Bold(true).Foreground(ColorHigh)
-	SeverityMediumStyle   = lipgloss.NewStyle().Bold(true).Foreground(ColorMedium)
-	SeverityLowStyle      = lipgloss.NewStyle().Foreground(ColorLow)
-	SeverityInfoStyle     = lipgloss.NewStyle().Foreground(ColorInfo)
-)
+		Foreground(t.Error).
+		Bold(true)
+
+	SeverityCriticalStyle = lipgloss.NewStyle().Bold(true).Foreground(t.Critical)
+	SeverityHighStyle = lipgloss.NewStyle().Bold(true).Foreground(t.High)
+	SeverityMediumStyle = lipgloss.NewStyle().Bold(true).Foreground(t.Medium)
+	SeverityLowStyle = lipgloss.NewStyle().Foreground(t.Low)
+	SeverityInfoStyle = lipgloss.NewStyle().Foreground(t.Info)
+}
 
 // SeverityStyle returns the appropriate style for a severity level.
 func SeverityStyle(severity string) lipgloss.Style {
@@ -0,0 +1,167 @@
+package styles
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is a TUI color scheme.
+type Theme struct {
+	Name string
+
+	Critical lipgloss.Color
+	High     lipgloss.Color
+	Medium   lipgloss.Color
+	Low      lipgloss.Color
+	Info     lipgloss.Color
+
+	Muted  lipgloss.Color // help text and secondary columns
+	Accent lipgloss.Color // titles, cursor, and selection
+	Title  lipgloss.Color // title text on the accent background
+	Error  lipgloss.Color
+}
+
+// Built-in themes.
+var (
+	Dark = Theme{
+		Name:     "dark",
+		Critical: "#FF0000",
+		High:     "#FF6600",
+		Medium:   "#FFCC00",
+		Low:      "#00CC00",
+		Info:     "#0099FF",
+		Muted:    "#666666",
+		Accent:   "#7D56F4",
+		Title:    "#FAFAFA",
+		Error:    "#FF0000",
+	}
+
+	Light = Theme{
+		Name:     "light",
+		Critical: "#C00000",
+		High:     "#C04800",
+		Medium:   "#8A6D00",
+		Low:      "#007A00",
+		Info:     "#005FAF",
+		Muted:    "#767676",
+		Accent:   "#5A3DC8",
+		Title:    "#FFFFFF",
+		Error:    "#C00000",
+	}
+
+	HighContrast = Theme{
+		Name:     "high-contrast",
+		Critical: "#FF0000",
+		High:     "#FF8700",
+		Medium:   "#FFFF00",
+		Low:      "#00FF00",
+		Info:     "#00FFFF",
+		Muted:    "#FFFFFF",
+		Accent:   "#FFFF00",
+		Title:    "#000000",
+		Error:    "#FF5F5F",
+	}
+)
+
+// CustomTheme is the theme name for a scheme built entirely from the colors
+// section of the config; colors not set there come from the dark theme.
+const CustomTheme = "custom"
+
+// Themes lists the built-in themes in the order the theme switcher cycles
+// through them.
+var Themes = []Theme{Dark, Light, HighContrast}
+
+// ThemeNames returns the names accepted by Resolve.
+func ThemeNames() []string {
+	names := make([]string, 0, len(Themes)+1)
+	for _, t := range Themes {
+		names = append(names, t.Name)
+	}
+	return append(names, CustomTheme)
+}
+
+// colorPattern matches the color values lipgloss understands: a hex RGB
+// color or an ANSI color number.
+var colorPattern = regexp.MustCompile(`^(#[0-9A-Fa-f]{6}|#[0-9A-Fa-f]{3}|[0-9]{1,3})$`)
+
+// Resolve returns the named theme with colors applied on top of it. Keys in
+// colors are the Theme field names in lowercase (critical, high, medium,
+// low, info, muted, accent, title, error). An empty name means dark.
+func Resolve(name string, colors map[string]string) (Theme, error) {
+	var t Theme
+	switch name {
+	case "":
+		t = Dark
+	case CustomTheme:
+		t = Dark
+		t.Name = CustomTheme
+	default:
+		found := false
+		for _, builtin := range Themes {
+			if builtin.Name == name {
+				t, found = builtin, true
+				break
+			}
+		}
+		if !found {
+			return Theme{}, fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(ThemeNames(), ", "))
+		}
+	}
+
+	keys := make([]string, 0, len(colors))
+	for key := range colors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		field := t.color(key)
+		if field == nil {
+			return Theme{}, fmt.Errorf("unknown color %q (available: critical, high, medium, low, info, muted, accent, title, error)", key)
+		}
+		value := strings.TrimSpace(colors[key])
+		if !colorPattern.MatchString(value) {
+			return Theme{}, fmt.Errorf("color %s: %q is not a hex color such as #FF0000 or an ANSI color number", key, value)
+		}
+		*field = lipgloss.Color(value)
+	}
+	return t, nil
+}
+
+func (t *Theme) color(key string) *lipgloss.Color {
+	switch strings.ToLower(key) {
+	case "critical":
+		return &t.Critical
+	case "high":
+		return &t.High
+	case "medium":
+		return &t.Medium
+	case "low":
+		return &t.Low
+	case "info":
+		return &t.Info
+	case "muted":
+		return &t.Muted
+	case "accent":
+		return &t.Accent
+	case "title":
+		return &t.Title
+	case "error":
+		return &t.Error
+	}
+	return nil
+}
+
+// Next returns the built-in theme after t in Themes, wrapping around. A
+// custom theme is followed by the first built-in one.
+func Next(t Theme) Theme {
+	for i, builtin := range Themes {
+		if builtin.Name == t.Name {
+			return Themes[(i+1)%len(Themes)]
+		}
+	}
+	return Themes[0]
+}
@@ -0,0 +1,62 @@
+package styles
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveBuiltinThemes(t *testing.T) {
+	theme, err := Resolve("", nil)
+	require.NoError(t, err)
+	assert.Equal(t, Dark, theme)
+
+	for _, name := range []string{"dark", "light", "high-contrast"} {
+		theme, err := Resolve(name, nil)
+		require.NoError(t, err, name)
+		assert.Equal(t, name, theme.Name)
+	}
+}
+
+func TestResolveAppliesColors(t *testing.T) {
+	theme, err := Resolve("light", map[string]string{"critical": "#FF00FF", "accent": "63"})
+	require.NoError(t, err)
+	assert.Equal(t, lipgloss.Color("#FF00FF"), theme.Critical)
+	assert.Equal(t, lipgloss.Color("63"), theme.Accent)
+	assert.Equal(t, Light.High, theme.High)
+
+	theme, err = Resolve(CustomTheme, map[string]string{"info": "#123"})
+	require.NoError(t, err)
+	assert.Equal(t, CustomTheme, theme.Name)
+	assert.Equal(t, lipgloss.Color("#123"), theme.Info)
+	assert.Equal(t, Dark.Low, theme.Low)
+}
+
+func TestResolveRejectsInvalidSettings(t *testing.T) {
+	_, err := Resolve("solarized", nil)
+	assert.ErrorContains(t, err, "unknown theme")
+
+	_, err = Resolve("dark", map[string]string{"background": "#000000"})
+	assert.ErrorContains(t, err, "unknown color")
+
+	_, err = Resolve("dark", map[string]string{"critical": "red"})
+	assert.ErrorContains(t, err, "not a hex color")
+}
+
+func TestApplySwitchesStyles(t *testing.T) {
+	defer Apply(Current())
+
+	Apply(HighContrast)
+	assert.Equal(t, HighContrast, Current())
+	assert.Equal(t, HighContrast.Accent, ColorAccent)
+	assert.Equal(t, lipgloss.TerminalColor(HighContrast.Critical), SeverityCriticalStyle.GetForeground())
+}
+
+func TestNextCyclesThemes(t *testing.T) {
+	assert.Equal(t, "light", Next(Dark).Name)
+	assert.Equal(t, "high-contrast", Next(Light).Name)
+	assert.Equal(t, "dark", Next(HighContrast).Name)
+	assert.Equal(t, "dark", Next(Theme{Name: CustomTheme}).Name)
+}
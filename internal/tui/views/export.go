@@ -0,0 +1,137 @@
+package views
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/buemura/hunter/internal/output"
+	"github.com/buemura/hunter/internal/tui/styles"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// exportFormats are the formats offered by the export dialog, in order.
+var exportFormats = []string{"json", "markdown", "html", "csv", "sarif", "zap", "burp"}
+
+// exportBaseName is the default file name, without extension.
+const exportBaseName = "hunter-results"
+
+// ExportModel is the export dialog: a format picker and a file path. The
+// path follows the chosen format's extension until the user edits it.
+type ExportModel struct {
+	format int
+	path   textinput.Model
+	edited bool
+}
+
+// NewExportModel creates an export dialog defaulting to JSON.
+func NewExportModel() ExportModel {
+	ti := textinput.New()
+	ti.CharLimit = 512
+	ti.Width = 50
+	ti.PromptStyle = styles.CursorStyle
+	ti.TextStyle = styles.SelectedStyle
+	ti.Focus()
+
+	m := ExportModel{path: ti}
+	m.path.SetValue(m.defaultPath())
+	m.path.CursorEnd()
+	return m
+}
+
+// SetWidth fits the path input to a terminal of the given width.
+func (m *ExportModel) SetWidth(width int) {
+	// Leave room for the border, padding, and the "File:" label.
+	m.path.Width = clamp(width-16, 10, 50)
+}
+
+// Update cycles formats with tab/shift+tab and forwards other keys to the
+// path input.
+func (m ExportModel) Update(msg tea.Msg) (ExportModel, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "tab":
+			m.setFormat(m.format + 1)
+			return m, nil
+		case "shift+tab":
+			m.setFormat(m.format - 1)
+			return m, nil
+		}
+	}
+
+	before := m.path.Value()
+	var cmd tea.Cmd
+	m.path, cmd = m.path.Update(msg)
+	if m.path.Value() != before {
+		m.edited = true
+	}
+	return m, cmd
+}
+
+func (m *ExportModel) setFormat(i int) {
+	n := len(exportFormats)
+	m.format = (i%n + n) % n
+	if !m.edited {
+		m.path.SetValue(m.defaultPath())
+		m.path.CursorEnd()
+	}
+}
+
+func (m ExportModel) defaultPath() string {
+	return exportBaseName + "." + output.FileExtension(m.Format())
+}
+
+// Format returns the selected format name.
+func (m ExportModel) Format() string {
+	return exportFormats[m.format]
+}
+
+// Path returns the file path to write.
+func (m ExportModel) Path() string {
+	return strings.TrimSpace(m.path.Value())
+}
+
+// Save writes results to the chosen path in the chosen format.
+func (m ExportModel) Save(results []types.ScanResult) error {
+	path := m.Path()
+	if path == "" {
+		return fmt.Errorf("a file path is required")
+	}
+	formatter, err := output.GetFormatter(m.Format())
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := formatter.Format(f, results); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// View renders the dialog.
+func (m ExportModel) View() string {
+	var b strings.Builder
+	b.WriteString(styles.HeaderStyle.Render("Export results"))
+	b.WriteString("\n")
+
+	formats := make([]string, len(exportFormats))
+	for i, name := range exportFormats {
+		if i == m.format {
+			formats[i] = styles.SelectedStyle.Render("[" + name + "]")
+		} else {
+			formats[i] = styles.HelpStyle.Render(" " + name + " ")
+		}
+	}
+	b.WriteString("Format: " + strings.Join(formats, " ") + "\n")
+	b.WriteString("File:   " + m.path.View() + "\n")
+	b.WriteString(styles.HelpStyle.Render("tab format • enter save • esc cancel"))
+
+	return styles.BorderStyle.Render(b.String())
+}
@@ -0,0 +1,95 @@
+package views
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/buemura/hunter/pkg/types"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportModelFormatUpdatesDefaultPath(t *testing.T) {
+	m := NewExportModel()
+	assert.Equal(t, "json", m.Format())
+	assert.Equal(t, "hunter-results.json", m.Path())
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	assert.Equal(t, "markdown", m.Format())
+	assert.Equal(t, "hunter-results.md", m.Path())
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	assert.Equal(t, "burp", m.Format(), "shift+tab wraps around")
+	assert.Equal(t, "hunter-results.xml", m.Path())
+
+	// Once the user edits the path, changing format leaves it alone.
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	edited := m.Path()
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	assert.Equal(t, edited, m.Path())
+}
+
+func TestResultsModelExportDialog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.json")
+	m := NewResultsModel(newTestResults())
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	m = updated.(ResultsModel)
+	require.True(t, m.Capturing())
+	assert.Contains(t, m.View(), "Export results")
+
+	m.export.path.SetValue(path)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(ResultsModel)
+	assert.False(t, m.Capturing())
+	assert.Contains(t, m.View(), "Results exported to "+path)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var results []types.ScanResult
+	require.NoError(t, json.Unmarshal(data, &results))
+	assert.Len(t, results, 2)
+}
+
+func TestResultsModelExportHonoursFilter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.csv")
+	m := NewResultsModel(newTestResults())
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	m = updated.(ResultsModel)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	m = updated.(ResultsModel)
+	m.export, _ = m.export.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m.export, _ = m.export.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m.export, _ = m.export.Update(tea.KeyMsg{Type: tea.KeyTab})
+	require.Equal(t, "csv", m.export.Format())
+	m.export.path.SetValue(path)
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(ResultsModel)
+	require.Empty(t, m.exportErr)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Missing HSTS")
+	assert.NotContains(t, string(data), "Open port", "hidden findings are not exported")
+}
+
+func TestResultsModelExportErrorKeepsDialogOpen(t *testing.T) {
+	m := NewResultsModel(newTestResults())
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	m = updated.(ResultsModel)
+	m.export.path.SetValue(filepath.Join(t.TempDir(), "missing", "out.json"))
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(ResultsModel)
+	assert.True(t, m.Capturing())
+	assert.Contains(t, m.View(), "export failed")
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	assert.False(t, updated.(ResultsModel).Capturing())
+}
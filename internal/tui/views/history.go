@@ -0,0 +1,183 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/buemura/hunter/internal/history"
+	"github.com/buemura/hunter/internal/tui/styles"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// HistoryModel is the view model for the scan history browser. Opening and
+// re-running entries is handled by the root model through Selected; deleting
+// happens here.
+type HistoryModel struct {
+	store   *history.Store
+	entries []history.Entry
+	cursor  int
+	offset  int
+	err     string
+	width   int
+	height  int
+}
+
+// NewHistoryModel creates a history browser listing the entries in store.
+func NewHistoryModel(store *history.Store) HistoryModel {
+	m := HistoryModel{store: store, width: defaultWidth, height: defaultHeight}
+	m.reload()
+	return m
+}
+
+// SetSize fits the list to a terminal of the given size. Entries that do not
+// fit scroll with the cursor.
+func (m *HistoryModel) SetSize(width, height int) {
+	m.width, m.height = fitSize(width, height, m.width, m.height)
+	m.offset, _ = window(m.cursor, m.offset, len(m.entries), m.maxRows())
+}
+
+func historyHelp() string {
+	return joinHelp(
+		pairHelp(keys.Up, keys.Down, "navigate"),
+		shortHelp(keys.Select, "open"),
+		shortHelp(keys.Rerun, "re-run"),
+		shortHelp(keys.Delete, "delete"),
+		shortHelp(keys.Back, "back"),
+		shortHelp(keys.Quit, "quit"),
+	)
+}
+
+// Bindings returns the history browser's key bindings for the help overlay.
+func (m HistoryModel) Bindings() []key.Binding {
+	open, rerun := keys.Select, keys.Rerun
+	open.SetHelp(open.Help().Key, "open the scan's results")
+	rerun.SetHelp(rerun.Help().Key, "re-run the scan")
+	return []key.Binding{keys.Up, keys.Down, open, rerun, keys.Delete, keys.Back, keys.Quit}
+}
+
+// maxRows returns how many entries fit between the header and the help line.
+func (m HistoryModel) maxRows() int {
+	rows := m.height - 6 - lipgloss.Height(helpLine(historyHelp(), m.width))
+	if m.err != "" {
+		rows -= 2
+	}
+	return max(rows, 3)
+}
+
+// targetWidth returns the width of the target column: what is left after the
+// date and findings columns, keeping room for a few scanner names.
+func (m HistoryModel) targetWidth() int {
+	return clamp(m.width-2-17-1-9-1-20, 15, 60)
+}
+
+func (m *HistoryModel) reload() {
+	entries, err := m.store.List()
+	if err != nil {
+		m.err = err.Error()
+	}
+	m.entries = entries
+	if m.cursor >= len(m.entries) {
+		m.cursor = len(m.entries) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+// Init returns nil (no initial command).
+func (m HistoryModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles navigation and deletion.
+func (m HistoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(keyMsg, keys.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case key.Matches(keyMsg, keys.Down):
+			if m.cursor < len(m.entries)-1 {
+				m.cursor++
+			}
+		case key.Matches(keyMsg, keys.Delete):
+			if e := m.Selected(); e != nil {
+				m.err = ""
+				if err := m.store.Delete(e.ID); err != nil {
+					m.err = err.Error()
+				}
+				m.reload()
+			}
+		case key.Matches(keyMsg, keys.Quit):
+			return m, tea.Quit
+		}
+		m.offset, _ = window(m.cursor, m.offset, len(m.entries), m.maxRows())
+	}
+	return m, nil
+}
+
+// View renders the list of past scans.
+func (m HistoryModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(styles.TitleStyle.Render("Hunter — Scan History"))
+	b.WriteString("\n\n")
+
+	if len(m.entries) == 0 {
+		b.WriteString("No previous scans.\n")
+	} else {
+		targetWidth := m.targetWidth()
+		scannersWidth := max(m.width-2-17-1-targetWidth-1-9-1, 10)
+		header := fmt.Sprintf("  %-17s %-*s %-9s %s", "DATE", targetWidth, "TARGET", "FINDINGS", "SCANNERS")
+		b.WriteString(styles.HeaderStyle.Render(header))
+		b.WriteString("\n")
+		start, end := window(m.cursor, m.offset, len(m.entries), m.maxRows())
+		for i := start; i < end; i++ {
+			e := m.entries[i]
+			cursor := "  "
+			if i == m.cursor {
+				cursor = styles.CursorStyle.Render("> ")
+			}
+			b.WriteString(fmt.Sprintf("%s%-17s %-*s %-9d %s\n",
+				cursor,
+				e.CreatedAt.Local().Format("2006-01-02 15:04"),
+				targetWidth,
+				truncate(targetDisplay(e.Target), targetWidth),
+				e.FindingCount(),
+				styles.HelpStyle.Render(truncate(strings.Join(e.Scanners, ", "), scannersWidth)),
+			))
+		}
+		if end-start < len(m.entries) {
+			b.WriteString(styles.HelpStyle.Render(fmt.Sprintf("  %d-%d of %d", start+1, end, len(m.entries))))
+			b.WriteString("\n")
+		}
+	}
+
+	if m.err != "" {
+		b.WriteString("\n")
+		b.WriteString(styles.ErrorStyle.Render(m.err))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpLine(historyHelp(), m.width))
+
+	return b.String()
+}
+
+// SetError shows msg below the list, such as why an entry could not be
+// re-run.
+func (m *HistoryModel) SetError(msg string) {
+	m.err = msg
+}
+
+// Selected returns the entry under the cursor, or nil if the history is empty.
+func (m HistoryModel) Selected() *history.Entry {
+	if len(m.entries) == 0 {
+		return nil
+	}
+	return &m.entries[m.cursor]
+}
@@ -0,0 +1,43 @@
+package views
+
+import (
+	"testing"
+	"time"
+
+	"github.com/buemura/hunter/internal/history"
+	"github.com/buemura/hunter/pkg/types"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryModelListsAndDeletes(t *testing.T) {
+	store := history.NewStore(t.TempDir())
+	now := time.Now()
+	_, err := store.Save(history.Entry{Target: types.Target{Host: "old.example.com"}, Scanners: []string{"ssl"}, CreatedAt: now.Add(-time.Hour)})
+	require.NoError(t, err)
+	_, err = store.Save(history.Entry{Target: types.Target{URL: "https://new.example.com"}, Scanners: []string{"headers", "dirs"}, CreatedAt: now})
+	require.NoError(t, err)
+
+	m := NewHistoryModel(store)
+	view := m.View()
+	assert.Contains(t, view, "Scan History")
+	assert.Contains(t, view, "https://new.example.com")
+	assert.Contains(t, view, "headers, dirs")
+	require.NotNil(t, m.Selected())
+	assert.Equal(t, "https://new.example.com", m.Selected().Target.URL, "newest first")
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	m = updated.(HistoryModel)
+	require.NotNil(t, m.Selected())
+	assert.Equal(t, "old.example.com", m.Selected().Target.Host)
+
+	entries, err := store.List()
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	m = updated.(HistoryModel)
+	assert.Nil(t, m.Selected())
+	assert.Contains(t, m.View(), "No previous scans")
+}
@@ -0,0 +1,295 @@
+package views
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/buemura/hunter/internal/tui/styles"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+)
+
+// KeyMap holds the remappable key bindings of every view. Keys typed into
+// text inputs (target, options, search, export path) are not remappable.
+type KeyMap struct {
+	Up       key.Binding
+	Down     key.Binding
+	PageUp   key.Binding
+	PageDown key.Binding
+	Top      key.Binding
+	Bottom   key.Binding
+
+	Select key.Binding
+	Toggle key.Binding
+	Back   key.Binding
+	Quit   key.Binding
+	Help   key.Binding
+
+	History  key.Binding
+	Profiles key.Binding
+	Theme    key.Binding
+	Search   key.Binding
+	Export   key.Binding
+	Rerun    key.Binding
+	Delete   key.Binding
+	Cancel   key.Binding
+
+	FilterCritical key.Binding
+	FilterHigh     key.Binding
+	FilterMedium   key.Binding
+	FilterLow      key.Binding
+	FilterInfo     key.Binding
+}
+
+// DefaultKeyMap returns the built-in key bindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up:       binding("up", "up", "k"),
+		Down:     binding("down", "down", "j"),
+		PageUp:   binding("page up", "pgup", "b"),
+		PageDown: binding("page down", "pgdown", "f"),
+		Top:      binding("top", "g", "home"),
+		Bottom:   binding("bottom", "G", "end"),
+
+		Select: binding("select", "enter"),
+		Toggle: binding("toggle", " "),
+		Back:   binding("back", "esc"),
+		Quit:   binding("quit", "q"),
+		Help:   binding("help", "?", "f1"),
+
+		History:  binding("history", "h"),
+		Profiles: binding("profiles", "p"),
+		Theme:    binding("theme", "t"),
+		Search:   binding("search", "/"),
+		Export:   binding("export", "e"),
+		Rerun:    binding("re-run", "r"),
+		Delete:   binding("delete", "d"),
+		Cancel:   binding("cancel", "x"),
+
+		FilterCritical: binding("toggle critical", "c"),
+		FilterHigh:     binding("toggle high", "h"),
+		FilterMedium:   binding("toggle medium", "m"),
+		FilterLow:      binding("toggle low", "l"),
+		FilterInfo:     binding("toggle info", "i"),
+	}
+}
+
+func binding(desc string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(displayKeys(keys), desc))
+}
+
+// actions maps the names used in the tui.keybindings config section to
+// bindings.
+func (km *KeyMap) actions() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":              &km.Up,
+		"down":            &km.Down,
+		"page_up":         &km.PageUp,
+		"page_down":       &km.PageDown,
+		"top":             &km.Top,
+		"bottom":          &km.Bottom,
+		"select":          &km.Select,
+		"toggle":          &km.Toggle,
+		"back":            &km.Back,
+		"quit":            &km.Quit,
+		"help":            &km.Help,
+		"history":         &km.History,
+		"profiles":        &km.Profiles,
+		"theme":           &km.Theme,
+		"search":          &km.Search,
+		"export":          &km.Export,
+		"rerun":           &km.Rerun,
+		"delete":          &km.Delete,
+		"cancel":          &km.Cancel,
+		"filter_critical": &km.FilterCritical,
+		"filter_high":     &km.FilterHigh,
+		"filter_medium":   &km.FilterMedium,
+		"filter_low":      &km.FilterLow,
+		"filter_info":     &km.FilterInfo,
+	}
+}
+
+// keyScopes lists the actions active together on one screen. A key may only
+// be bound to one action per screen.
+var keyScopes = map[string][]string{
+	"menu":     {"up", "down", "toggle", "select", "history", "profiles", "theme", "quit", "back", "help"},
+	"profiles": {"up", "down", "select", "back", "quit", "help"},
+	"results":  {"up", "down", "select", "search", "export", "rerun", "back", "quit", "help", "filter_critical", "filter_high", "filter_medium", "filter_low", "filter_info"},
+	"detail":   {"up", "down", "page_up", "page_down", "top", "bottom", "select", "back", "quit", "help"},
+	"history":  {"up", "down", "select", "rerun", "delete", "back", "quit", "help"},
+	"scan":     {"cancel", "help"},
+}
+
+// NewKeyMap returns the default key bindings with overrides from the
+// tui.keybindings config section applied. Overrides map an action name to
+// the keys that trigger it, replacing its default keys.
+func NewKeyMap(overrides map[string][]string) (KeyMap, error) {
+	km := DefaultKeyMap()
+	actions := km.actions()
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b, ok := actions[name]
+		if !ok {
+			return KeyMap{}, fmt.Errorf("unknown action %q (available: %s)", name, strings.Join(actionNames(actions), ", "))
+		}
+		var keys []string
+		for _, k := range overrides[name] {
+			if k = strings.TrimSpace(k); k == "space" {
+				k = " "
+			}
+			if k != "" {
+				keys = append(keys, k)
+			}
+		}
+		if len(keys) == 0 {
+			return KeyMap{}, fmt.Errorf("action %q has no keys", name)
+		}
+		b.SetKeys(keys...)
+		b.SetHelp(displayKeys(keys), b.Help().Desc)
+	}
+
+	if err := km.checkConflicts(); err != nil {
+		return KeyMap{}, err
+	}
+	return km, nil
+}
+
+func (km *KeyMap) checkConflicts() error {
+	actions := km.actions()
+	scopes := make([]string, 0, len(keyScopes))
+	for scope := range keyScopes {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+
+	for _, scope := range scopes {
+		owner := map[string]string{}
+		for _, name := range keyScopes[scope] {
+			for _, k := range actions[name].Keys() {
+				if other, taken := owner[k]; taken {
+					return fmt.Errorf("key %q is bound to both %s and %s in the %s view", displayKey(k), other, name, scope)
+				}
+				owner[k] = name
+			}
+		}
+	}
+	return nil
+}
+
+func actionNames(actions map[string]*key.Binding) []string {
+	names := make([]string, 0, len(actions))
+	for name := range actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// keys is the key map the views match against, set with SetKeyMap.
+var keys = DefaultKeyMap()
+
+// SetKeyMap replaces the key bindings used by every view.
+func SetKeyMap(km KeyMap) {
+	keys = km
+}
+
+// Keys returns the key bindings in use.
+func Keys() KeyMap {
+	return keys
+}
+
+// viewportKeys returns the detail pane's scroll bindings.
+func viewportKeys() viewport.KeyMap {
+	km := viewport.DefaultKeyMap()
+	km.Up = keys.Up
+	km.Down = keys.Down
+	km.PageUp = keys.PageUp
+	km.PageDown = keys.PageDown
+	return km
+}
+
+// displayKey returns how a key is shown in help text.
+func displayKey(k string) string {
+	switch k {
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case " ":
+		return "space"
+	case "pgdown":
+		return "pgdn"
+	}
+	return k
+}
+
+func displayKeys(keys []string) string {
+	shown := make([]string, len(keys))
+	for i, k := range keys {
+		shown[i] = displayKey(k)
+	}
+	return strings.Join(shown, "/")
+}
+
+// firstKey returns the main key of b for the one-line help.
+func firstKey(b key.Binding) string {
+	if ks := b.Keys(); len(ks) > 0 {
+		return displayKey(ks[0])
+	}
+	return ""
+}
+
+// shortHelp formats one help entry: the main key and what it does.
+func shortHelp(b key.Binding, desc string) string {
+	return firstKey(b) + " " + desc
+}
+
+// pairHelp formats one help entry for two related bindings, e.g. "↑/↓ navigate".
+func pairHelp(a, b key.Binding, desc string) string {
+	return firstKey(a) + "/" + firstKey(b) + " " + desc
+}
+
+// joinHelp joins help entries into a help line.
+func joinHelp(entries ...string) string {
+	return strings.Join(entries, " • ")
+}
+
+// HelpSection is one titled group of bindings in the help overlay.
+type HelpSection struct {
+	Title    string
+	Bindings []key.Binding
+}
+
+// fixedBinding describes a key that cannot be remapped, for the help
+// overlay.
+func fixedBinding(keys, desc string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys), key.WithHelp(keys, desc))
+}
+
+// RenderHelp renders the help overlay listing every binding in sections,
+// with all the keys of each binding.
+func RenderHelp(sections []HelpSection, width int) string {
+	var b strings.Builder
+	b.WriteString(styles.TitleStyle.Render("Hunter — Keyboard Shortcuts"))
+	b.WriteString("\n\n")
+
+	for _, section := range sections {
+		b.WriteString(styles.HeaderStyle.Render(section.Title))
+		b.WriteString("\n")
+		for _, binding := range section.Bindings {
+			h := binding.Help()
+			b.WriteString(fmt.Sprintf("  %s  %s\n",
+				styles.SelectedStyle.Render(fmt.Sprintf("%-14s", h.Key)),
+				h.Desc))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(helpLine("Keys can be remapped in the tui.keybindings section of the config file • any key closes", width))
+	return b.String()
+}
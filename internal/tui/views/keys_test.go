@@ -0,0 +1,72 @@
+package views
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultKeyMapHasNoConflicts(t *testing.T) {
+	km := DefaultKeyMap()
+	assert.NoError(t, km.checkConflicts())
+}
+
+func TestNewKeyMapOverrides(t *testing.T) {
+	km, err := NewKeyMap(map[string][]string{
+		"quit":   {"ctrl+q", "Q"},
+		"toggle": {"space", "x"},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, key.Matches(tea.KeyMsg{Type: tea.KeyCtrlQ}, km.Quit))
+	assert.False(t, key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}, km.Quit), "overrides replace the default keys")
+	assert.Equal(t, "ctrl+q/Q", km.Quit.Help().Key)
+	assert.True(t, key.Matches(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}, km.Toggle))
+	assert.Equal(t, "space/x", km.Toggle.Help().Key)
+}
+
+func TestNewKeyMapErrors(t *testing.T) {
+	_, err := NewKeyMap(map[string][]string{"launch": {"l"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown action "launch"`)
+
+	_, err = NewKeyMap(map[string][]string{"quit": {" "}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "has no keys")
+
+	_, err = NewKeyMap(map[string][]string{"quit": {"j"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `key "j" is bound to both`)
+}
+
+func TestNewKeyMapAllowsSameKeyInDifferentViews(t *testing.T) {
+	// d deletes in the history view and nothing else uses it in the menu.
+	_, err := NewKeyMap(map[string][]string{"theme": {"d"}})
+	assert.NoError(t, err)
+}
+
+func TestRemappedKeyDrivesViews(t *testing.T) {
+	km, err := NewKeyMap(map[string][]string{"down": {"n"}})
+	require.NoError(t, err)
+	SetKeyMap(km)
+	defer SetKeyMap(DefaultKeyMap())
+
+	m := NewMenuModel([]ScannerItem{{Name: "port"}, {Name: "headers"}})
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	assert.Equal(t, 0, updated.(MenuModel).Cursor())
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	assert.Equal(t, 1, updated.(MenuModel).Cursor())
+	assert.Contains(t, updated.(MenuModel).View(), "↑/n navigate")
+}
+
+func TestRenderHelpListsEveryKey(t *testing.T) {
+	out := RenderHelp([]HelpSection{{Title: "Scanner menu", Bindings: NewMenuModel(nil).Bindings()}}, 80)
+	assert.Contains(t, out, "Scanner menu")
+	assert.Contains(t, out, "↑/k")
+	assert.Contains(t, out, "space")
+	assert.Contains(t, out, "tui.keybindings")
+}
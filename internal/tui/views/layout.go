@@ -0,0 +1,71 @@
+package views
+
+import "github.com/buemura/hunter/internal/tui/styles"
+
+// Layout used until the first tea.WindowSizeMsg arrives.
+const (
+	defaultWidth  = 80
+	defaultHeight = 24
+)
+
+// Smallest layout the views lay themselves out for. Smaller terminals get
+// this layout and clip, rather than columns collapsing to nothing.
+const (
+	minWidth  = 40
+	minHeight = 12
+)
+
+// fitSize returns width and height clamped to the minimum layout. Zero or
+// negative values, e.g. before the terminal size is known, keep current.
+func fitSize(width, height, currentWidth, currentHeight int) (int, int) {
+	if width <= 0 {
+		width = currentWidth
+	}
+	if height <= 0 {
+		height = currentHeight
+	}
+	return max(width, minWidth), max(height, minHeight)
+}
+
+// clamp returns n limited to the range [lo, hi]. lo wins if hi < lo.
+func clamp(n, lo, hi int) int {
+	if n > hi {
+		n = hi
+	}
+	if n < lo {
+		n = lo
+	}
+	return n
+}
+
+// window returns the [start, end) range of rows to show so that cursor stays
+// visible in a list of total rows with room for size of them. offset is the
+// previous start.
+func window(cursor, offset, total, size int) (int, int) {
+	if size < 1 {
+		size = 1
+	}
+	if cursor < offset {
+		offset = cursor
+	}
+	if cursor >= offset+size {
+		offset = cursor - size + 1
+	}
+	if offset > total-size {
+		offset = total - size
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	end := offset + size
+	if end > total {
+		end = total
+	}
+	return offset, end
+}
+
+// helpLine renders a key help line, wrapped at width so narrow terminals
+// get two lines instead of a clipped one.
+func helpLine(help string, width int) string {
+	return styles.HelpStyle.Width(width).Render(help)
+}
@@ -0,0 +1,45 @@
+package views
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFitSize(t *testing.T) {
+	w, h := fitSize(120, 40, defaultWidth, defaultHeight)
+	assert.Equal(t, 120, w)
+	assert.Equal(t, 40, h)
+
+	w, h = fitSize(0, 0, 100, 30)
+	assert.Equal(t, 100, w, "unknown size keeps the current one")
+	assert.Equal(t, 30, h)
+
+	w, h = fitSize(10, 5, defaultWidth, defaultHeight)
+	assert.Equal(t, minWidth, w)
+	assert.Equal(t, minHeight, h)
+}
+
+func TestWindow(t *testing.T) {
+	tests := []struct {
+		name                        string
+		cursor, offset, total, size int
+		wantStart, wantEnd          int
+	}{
+		{"fits", 2, 0, 5, 10, 0, 5},
+		{"cursor at top", 0, 0, 20, 5, 0, 5},
+		{"cursor below window", 7, 0, 20, 5, 3, 8},
+		{"cursor above window", 2, 5, 20, 5, 2, 7},
+		{"keeps offset", 6, 4, 20, 5, 4, 9},
+		{"shrunk past the end", 19, 10, 20, 5, 15, 20},
+		{"grown past the end", 19, 15, 20, 10, 10, 20},
+		{"empty", 0, 0, 0, 5, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := window(tt.cursor, tt.offset, tt.total, tt.size)
+			assert.Equal(t, tt.wantStart, start)
+			assert.Equal(t, tt.wantEnd, end)
+		})
+	}
+}
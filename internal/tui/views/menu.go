@@ -5,7 +5,9 @@ import (
 	"strings"
 
 	"github.com/buemura/hunter/internal/tui/styles"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 // ScannerItem represents a scanner available in the menu.
@@ -14,15 +16,78 @@ type ScannerItem struct {
 	Description string
 }
 
-// MenuModel is the view model for the scanner selection menu.
+// ScannerGroup is a shortcut row in the menu that checks or unchecks a set
+// of scanners at once.
+type ScannerGroup struct {
+	Name        string
+	Description string
+	Members     []string
+}
+
+// MenuModel is the view model for the scanner selection menu. Groups are
+// listed above the individual scanners; the cursor moves over both.
 type MenuModel struct {
-	items  []ScannerItem
-	cursor int
+	items   []ScannerItem
+	groups  []ScannerGroup
+	checked map[string]bool
+	cursor  int
+	offset  int
+	width   int
+	height  int
+	status  string
 }
 
 // NewMenuModel creates a menu with the given scanner items.
 func NewMenuModel(items []ScannerItem) MenuModel {
-	return MenuModel{items: items}
+	return MenuModel{items: items, checked: map[string]bool{}, width: defaultWidth, height: defaultHeight}
+}
+
+// SetSize fits the menu to a terminal of the given size. Rows that do not
+// fit scroll with the cursor.
+func (m *MenuModel) SetSize(width, height int) {
+	m.width, m.height = fitSize(width, height, m.width, m.height)
+	m.offset, _ = window(m.cursor, m.offset, m.rows(), m.maxRows())
+}
+
+func menuHelp() string {
+	return joinHelp(
+		pairHelp(keys.Up, keys.Down, "navigate"),
+		shortHelp(keys.Toggle, "toggle"),
+		shortHelp(keys.Select, "select"),
+		shortHelp(keys.History, "history"),
+		shortHelp(keys.Profiles, "profiles"),
+		shortHelp(keys.Theme, "theme"),
+		shortHelp(keys.Help, "help"),
+		shortHelp(keys.Quit, "quit"),
+	)
+}
+
+// Bindings returns the menu's key bindings for the help overlay.
+func (m MenuModel) Bindings() []key.Binding {
+	return []key.Binding{keys.Up, keys.Down, keys.Toggle, keys.Select, keys.History, keys.Profiles, keys.Theme, keys.Quit}
+}
+
+// maxRows returns how many rows fit between the title and the help line,
+// keeping a line for the gap between groups and scanners and one for the
+// scroll indicator.
+func (m MenuModel) maxRows() int {
+	rows := m.height - 6 - lipgloss.Height(helpLine(menuHelp(), m.width))
+	if m.status != "" {
+		rows--
+	}
+	return max(rows, 3)
+}
+
+// SetGroups sets the group rows shown above the scanners.
+func (m *MenuModel) SetGroups(groups []ScannerGroup) {
+	m.groups = groups
+	m.cursor = 0
+	m.offset = 0
+}
+
+// SetStatus sets a line shown above the help, e.g. the theme just selected.
+func (m *MenuModel) SetStatus(status string) {
+	m.status = status
 }
 
 // Init returns nil (no initial command).
@@ -30,26 +95,89 @@ func (m MenuModel) Init() tea.Cmd {
 	return nil
 }
 
-// Update handles key navigation in the menu.
+// Update handles key navigation and checkbox toggling in the menu.
 func (m MenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "up", "k":
+		switch {
+		case key.Matches(msg, keys.Up):
 			if m.cursor > 0 {
 				m.cursor--
 			}
-		case "down", "j":
-			if m.cursor < len(m.items)-1 {
+		case key.Matches(msg, keys.Down):
+			if m.cursor < m.rows()-1 {
 				m.cursor++
 			}
-		case "q":
+		case key.Matches(msg, keys.Toggle):
+			m.toggle()
+		case key.Matches(msg, keys.Quit):
 			return m, tea.Quit
 		}
+		m.offset, _ = window(m.cursor, m.offset, m.rows(), m.maxRows())
 	}
 	return m, nil
 }
 
+func (m MenuModel) rows() int {
+	return len(m.groups) + len(m.items)
+}
+
+// toggle flips the scanner under the cursor, or for a group checks all of
+// its members unless they are all checked already, in which case it
+// unchecks them.
+func (m *MenuModel) toggle() {
+	if m.cursor < len(m.groups) {
+		group := m.groups[m.cursor]
+		check := m.groupState(group) != groupAll
+		for _, name := range group.Members {
+			m.setChecked(name, check)
+		}
+		return
+	}
+	if item := m.Selected(); item != nil {
+		m.setChecked(item.Name, !m.checked[item.Name])
+	}
+}
+
+func (m *MenuModel) setChecked(name string, on bool) {
+	// Copy on write: MenuModel is passed by value and must not share the
+	// map with earlier copies.
+	checked := make(map[string]bool, len(m.checked)+1)
+	for k, v := range m.checked {
+		checked[k] = v
+	}
+	if on {
+		checked[name] = true
+	} else {
+		delete(checked, name)
+	}
+	m.checked = checked
+}
+
+type groupCheck int
+
+const (
+	groupNone groupCheck = iota
+	groupSome
+	groupAll
+)
+
+func (m MenuModel) groupState(g ScannerGroup) groupCheck {
+	n := 0
+	for _, name := range g.Members {
+		if m.checked[name] {
+			n++
+		}
+	}
+	switch {
+	case n == 0:
+		return groupNone
+	case n == len(g.Members):
+		return groupAll
+	}
+	return groupSome
+}
+
 // View renders the scanner selection menu.
 func (m MenuModel) View() string {
 	var b strings.Builder
@@ -59,33 +187,92 @@ func (m MenuModel) View() string {
 	b.WriteString(styles.HeaderStyle.Render("Select a scan type:"))
 	b.WriteString("\n")
 
-	for i, item := range m.items {
-		cursor := "  "
-		nameStyle := styles.HelpStyle
-		if i == m.cursor {
-			cursor = styles.CursorStyle.Render("> ")
-			nameStyle = styles.SelectedStyle
+	start, end := window(m.cursor, m.offset, m.rows(), m.maxRows())
+	for i := start; i < end; i++ {
+		if i == len(m.groups) && i > start {
+			b.WriteString("\n")
 		}
-
-		b.WriteString(fmt.Sprintf("%s%s  %s\n",
-			cursor,
-			nameStyle.Render(item.Name),
-			styles.HelpStyle.Render(item.Description),
-		))
+		if i < len(m.groups) {
+			g := m.groups[i]
+			box := "[ ]"
+			switch m.groupState(g) {
+			case groupSome:
+				box = "[-]"
+			case groupAll:
+				box = "[x]"
+			}
+			b.WriteString(m.row(i, box, g.Name, g.Description))
+			continue
+		}
+		item := m.items[i-len(m.groups)]
+		box := "[ ]"
+		if m.checked[item.Name] {
+			box = "[x]"
+		}
+		b.WriteString(m.row(i, box, item.Name, item.Description))
+	}
+	if end-start < m.rows() {
+		b.WriteString(styles.HelpStyle.Render(fmt.Sprintf("  %d-%d of %d", start+1, end, m.rows())))
+		b.WriteString("\n")
 	}
 
 	b.WriteString("\n")
-	b.WriteString(styles.HelpStyle.Render("↑/↓ navigate • enter select • q quit"))
+	if m.status != "" {
+		b.WriteString(styles.SelectedStyle.Render(m.status))
+		b.WriteString("\n")
+	}
+	b.WriteString(helpLine(menuHelp(), m.width))
 
 	return b.String()
 }
 
-// Selected returns the currently highlighted scanner item, or nil if empty.
+func (m MenuModel) row(i int, box, name, description string) string {
+	cursor := "  "
+	nameStyle := styles.HelpStyle
+	if i == m.cursor {
+		cursor = styles.CursorStyle.Render("> ")
+		nameStyle = styles.SelectedStyle
+	}
+	// Cursor, checkbox, and the spaces around the name come to 8 columns.
+	description = truncate(description, max(m.width-8-len(name), 0))
+	return fmt.Sprintf("%s%s %s  %s\n",
+		cursor,
+		box,
+		nameStyle.Render(name),
+		styles.HelpStyle.Render(description),
+	)
+}
+
+// Selected returns the scanner item under the cursor, or nil if the menu is
+// empty or the cursor is on a group.
 func (m MenuModel) Selected() *ScannerItem {
-	if len(m.items) == 0 {
+	i := m.cursor - len(m.groups)
+	if i < 0 || i >= len(m.items) {
 		return nil
 	}
-	return &m.items[m.cursor]
+	return &m.items[i]
+}
+
+// Selection returns the scanners to run, in menu order: the checked ones,
+// or when nothing is checked, the scanner or group under the cursor.
+func (m MenuModel) Selection() []string {
+	var names []string
+	for _, item := range m.items {
+		if m.checked[item.Name] {
+			names = append(names, item.Name)
+		}
+	}
+	if len(names) > 0 {
+		return names
+	}
+
+	if m.cursor < len(m.groups) {
+		return m.groups[m.cursor].Members
+	}
+	if item := m.Selected(); item != nil {
+		return []string{item.Name}
+	}
+	return nil
 }
 
 // Cursor returns the current cursor position.
@@ -1,9 +1,12 @@
 package views
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -105,3 +108,88 @@ func TestMenuModelQuit(t *testing.T) {
 	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
 	require.NotNil(t, cmd)
 }
+
+func TestMenuModelSpaceTogglesScanners(t *testing.T) {
+	items := []ScannerItem{
+		{Name: "port", Description: "TCP port scanner"},
+		{Name: "headers", Description: "HTTP header scanner"},
+		{Name: "ssl", Description: "SSL/TLS scanner"},
+	}
+	m := NewMenuModel(items)
+	space := tea.KeyMsg{Type: tea.KeySpace}
+	down := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")}
+
+	// With nothing checked, the scanner under the cursor is selected.
+	assert.Equal(t, []string{"port"}, m.Selection())
+
+	updated, _ := m.Update(space)
+	m = updated.(MenuModel)
+	updated, _ = m.Update(down)
+	m = updated.(MenuModel)
+	updated, _ = m.Update(down)
+	m = updated.(MenuModel)
+	updated, _ = m.Update(space)
+	m = updated.(MenuModel)
+	assert.Equal(t, []string{"port", "ssl"}, m.Selection())
+	assert.Contains(t, m.View(), "[x]")
+
+	updated, _ = m.Update(space)
+	m = updated.(MenuModel)
+	assert.Equal(t, []string{"port"}, m.Selection())
+}
+
+func TestMenuModelGroups(t *testing.T) {
+	items := []ScannerItem{
+		{Name: "headers", Description: "HTTP header scanner"},
+		{Name: "api", Description: "API discovery"},
+		{Name: "api-cors", Description: "API CORS checks"},
+	}
+	m := NewMenuModel(items)
+	m.SetGroups([]ScannerGroup{
+		{Name: "all", Members: []string{"headers", "api", "api-cors"}},
+		{Name: "api", Members: []string{"api", "api-cors"}},
+	})
+	space := tea.KeyMsg{Type: tea.KeySpace}
+	down := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")}
+
+	assert.Nil(t, m.Selected(), "cursor starts on the first group")
+	assert.Equal(t, []string{"headers", "api", "api-cors"}, m.Selection())
+
+	updated, _ := m.Update(down)
+	m = updated.(MenuModel)
+	updated, _ = m.Update(space)
+	m = updated.(MenuModel)
+	assert.Equal(t, []string{"api", "api-cors"}, m.Selection())
+	assert.Contains(t, m.View(), "[-]", "all is partially checked")
+
+	// Toggling a fully checked group unchecks its members.
+	updated, _ = m.Update(space)
+	m = updated.(MenuModel)
+	assert.Equal(t, []string{"api", "api-cors"}, m.Selection(), "falls back to the group under the cursor")
+	assert.NotContains(t, m.View(), "[x]")
+}
+
+func TestMenuModelScrollsOnSmallTerminal(t *testing.T) {
+	var items []ScannerItem
+	for i := 0; i < 20; i++ {
+		items = append(items, ScannerItem{Name: fmt.Sprintf("scanner%02d", i), Description: strings.Repeat("long description ", 10)})
+	}
+	m := NewMenuModel(items)
+	m.SetSize(50, 14)
+
+	view := m.View()
+	assert.Contains(t, view, "scanner00")
+	assert.NotContains(t, view, "scanner19")
+	assert.Contains(t, view, "1-6 of 20")
+	for _, line := range strings.Split(view, "\n") {
+		assert.LessOrEqual(t, lipgloss.Width(line), 50, line)
+	}
+
+	for i := 0; i < 19; i++ {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+		m = updated.(MenuModel)
+	}
+	view = m.View()
+	assert.Contains(t, view, "scanner19")
+	assert.NotContains(t, view, "scanner00")
+}
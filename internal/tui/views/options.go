@@ -0,0 +1,303 @@
+package views
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/tui/styles"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// optionField is one input on the options form.
+type optionField struct {
+	key   string // ExtraArgs key, or one of the fieldX constants
+	label string
+	hint  string
+	input textinput.Model
+}
+
+// Form fields that map to scanner.Options rather than ExtraArgs.
+const (
+	fieldTimeout     = "timeout"
+	fieldConcurrency = "concurrency"
+	fieldHeaders     = "headers"
+	fieldIPVersion   = "ip_version"
+	fieldResolver    = "resolver"
+	fieldResolve     = "resolve"
+)
+
+// OptionsModel is the view model for the scan options form shown between
+// target entry and scanning. Scanner-specific fields only appear when that
+// scanner is selected; blank fields keep the defaults.
+type OptionsModel struct {
+	fields []optionField
+	focus  int
+	note   string
+	err    string
+	width  int
+}
+
+// NewOptionsModel creates an options form for the selected scanners.
+func NewOptionsModel(scannerNames []string) OptionsModel {
+	selected := make(map[string]bool, len(scannerNames))
+	for _, name := range scannerNames {
+		selected[name] = true
+	}
+	defaults := scanner.DefaultOptions()
+
+	var fields []optionField
+	add := func(key, label, hint, placeholder string) {
+		ti := textinput.New()
+		ti.Placeholder = placeholder
+		ti.CharLimit = 512
+		ti.Width = 40
+		ti.PromptStyle = styles.CursorStyle
+		ti.TextStyle = styles.SelectedStyle
+		fields = append(fields, optionField{key: key, label: label, hint: hint, input: ti})
+	}
+
+	if selected["port"] {
+		add("ports", "Ports", "port: list or ranges", "e.g. 22,80,443 or 1-1024 (default: common ports)")
+	}
+	if selected["dirs"] {
+		add("wordlist", "Wordlist", "dirs: path to a wordlist file", "default: built-in wordlist")
+	}
+	if selected["vuln"] {
+		add("checks", "Checks", "vuln: checks to run", "e.g. xss,sqli (default: all)")
+	}
+	add(fieldTimeout, "Timeout", "per connection", defaults.Timeout.String())
+	add(fieldConcurrency, "Concurrency", "parallel operations", strconv.Itoa(defaults.Concurrency))
+	add(fieldHeaders, "Headers", "sent with every HTTP request", "e.g. X-Api-Key: abc; Cookie: session=1")
+	add(fieldIPVersion, "IP version", "address family to scan", "4 or 6 (default: either)")
+	add(fieldResolver, "Resolver", "DNS server for the target", "e.g. 1.1.1.1 (default: system)")
+	add(fieldResolve, "Resolve", "pin host names to addresses", "e.g. example.com:10.0.0.5, api.example.com:10.0.0.6")
+
+	fields[0].input.Focus()
+	return OptionsModel{fields: fields, width: defaultWidth}
+}
+
+// SetSize fits the inputs and hints to a terminal of the given size.
+func (m *OptionsModel) SetSize(width, height int) {
+	m.width, _ = fitSize(width, height, m.width, defaultHeight)
+	for i := range m.fields {
+		m.fields[i].input.Width = clamp(m.width-16, 10, 40)
+	}
+}
+
+// Init returns the text input blink command.
+func (m OptionsModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update moves focus between fields and forwards typing to the focused one.
+func (m OptionsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "tab", "down":
+			return m.setFocus(m.focus + 1)
+		case "shift+tab", "up":
+			return m.setFocus(m.focus - 1)
+		case "enter":
+			if _, err := m.Options(); err != nil {
+				m.err = err.Error()
+			} else {
+				m.err = ""
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.fields[m.focus].input, cmd = m.fields[m.focus].input.Update(msg)
+	m.err = ""
+	return m, cmd
+}
+
+func (m OptionsModel) setFocus(i int) (tea.Model, tea.Cmd) {
+	n := len(m.fields)
+	i = (i%n + n) % n
+	m.fields[m.focus].input.Blur()
+	m.focus = i
+	return m, m.fields[m.focus].input.Focus()
+}
+
+// View renders the options form.
+func (m OptionsModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(styles.TitleStyle.Render("Hunter — Interactive Mode"))
+	b.WriteString("\n\n")
+	b.WriteString(styles.HeaderStyle.Render("Scan options"))
+	b.WriteString("\n")
+	if m.note != "" {
+		b.WriteString(styles.HelpStyle.Render(truncate(m.note, m.width)))
+		b.WriteString("\n\n")
+	}
+
+	for i, f := range m.fields {
+		label := styles.HelpStyle.Render(fmt.Sprintf("%-12s", f.label))
+		if i == m.focus {
+			label = styles.SelectedStyle.Render(fmt.Sprintf("%-12s", f.label))
+		}
+		b.WriteString(fmt.Sprintf("%s %s\n", label, f.input.View()))
+		b.WriteString(styles.HelpStyle.Render(fmt.Sprintf("%-12s %s", "", truncate(f.hint, m.width-13))))
+		b.WriteString("\n")
+	}
+
+	if m.err != "" {
+		b.WriteString("\n")
+		b.WriteString(styles.ErrorStyle.Render(m.err))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpLine("tab/↑/↓ move • enter start scan • esc back", m.width))
+
+	return b.String()
+}
+
+// Bindings returns the options form's keys for the help overlay. They are
+// fixed because every other key is typed into the fields.
+func (m OptionsModel) Bindings() []key.Binding {
+	return []key.Binding{
+		fixedBinding("tab/↓", "next field"),
+		fixedBinding("shift+tab/↑", "previous field"),
+		fixedBinding("enter", "start the scan"),
+		fixedBinding("esc", "back"),
+	}
+}
+
+// SetError sets the error line, e.g. why the scan could not start.
+func (m *OptionsModel) SetError(err string) {
+	m.err = err
+}
+
+// SetNote sets a line shown under the header, e.g. the profile the form was
+// filled in from.
+func (m *OptionsModel) SetNote(note string) {
+	m.note = note
+}
+
+// Value returns the raw text of the field with the given key.
+func (m OptionsModel) Value(key string) string {
+	for _, f := range m.fields {
+		if f.key == key {
+			return strings.TrimSpace(f.input.Value())
+		}
+	}
+	return ""
+}
+
+// Values returns the non-blank fields keyed by field key, for saving the
+// settings of a scan so it can be re-run.
+func (m OptionsModel) Values() map[string]string {
+	values := map[string]string{}
+	for _, f := range m.fields {
+		if v := strings.TrimSpace(f.input.Value()); v != "" {
+			values[f.key] = v
+		}
+	}
+	return values
+}
+
+// SetValue sets the text of the field with the given key, if present.
+func (m *OptionsModel) SetValue(key, value string) {
+	for i := range m.fields {
+		if m.fields[i].key == key {
+			m.fields[i].input.SetValue(value)
+		}
+	}
+}
+
+// Options builds scanner options from the form, starting from the defaults.
+// Scanner-specific fields become ExtraArgs and headers are added to every
+// HTTP request through a HeaderTransport.
+func (m OptionsModel) Options() (scanner.Options, error) {
+	opts := scanner.DefaultOptions()
+	var headers http.Header
+	var nameserver string
+	var overrides []string
+
+	for _, f := range m.fields {
+		value := strings.TrimSpace(f.input.Value())
+		if value == "" {
+			continue
+		}
+		switch f.key {
+		case fieldTimeout:
+			d, err := time.ParseDuration(value)
+			if err != nil || d <= 0 {
+				return opts, fmt.Errorf("timeout: %q is not a positive duration such as 5s", value)
+			}
+			opts.Timeout = d
+		case fieldConcurrency:
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return opts, fmt.Errorf("concurrency: %q is not a positive number", value)
+			}
+			opts.Concurrency = n
+		case fieldHeaders:
+			h, err := parseHeaders(value)
+			if err != nil {
+				return opts, err
+			}
+			headers = h
+		case fieldIPVersion:
+			v, err := scanner.ParseIPVersion(value)
+			if err != nil {
+				return opts, fmt.Errorf("ip version: %q is not 4 or 6", value)
+			}
+			opts.IPVersion = v
+		case fieldResolver:
+			nameserver = value
+		case fieldResolve:
+			for _, o := range strings.Split(value, ",") {
+				if o = strings.TrimSpace(o); o != "" {
+					overrides = append(overrides, o)
+				}
+			}
+		default:
+			if opts.ExtraArgs == nil {
+				opts.ExtraArgs = map[string]interface{}{}
+			}
+			opts.ExtraArgs[f.key] = value
+		}
+	}
+
+	if nameserver != "" || len(overrides) > 0 {
+		r, err := scanner.NewResolver(nameserver, overrides)
+		if err != nil {
+			return opts, err
+		}
+		opts.Resolver = r
+	}
+	opts.Transport = scanner.BaseTransport(opts)
+	if headers != nil {
+		opts.Transport = scanner.NewHeaderTransport(opts.Transport, headers)
+	}
+	return opts, nil
+}
+
+// parseHeaders parses "Name: value" pairs separated by semicolons.
+func parseHeaders(s string) (http.Header, error) {
+	headers := http.Header{}
+	for _, pair := range strings.Split(s, ";") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("headers: %q is not in Name: value form", pair)
+		}
+		headers.Add(name, strings.TrimSpace(value))
+	}
+	return headers, nil
+}
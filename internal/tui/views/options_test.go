@@ -0,0 +1,94 @@
+package views
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionsModelShowsFieldsForSelectedScanners(t *testing.T) {
+	m := NewOptionsModel([]string{"headers"})
+	view := m.View()
+	assert.Contains(t, view, "Timeout")
+	assert.Contains(t, view, "Headers")
+	assert.NotContains(t, view, "Ports")
+	assert.NotContains(t, view, "Wordlist")
+
+	m = NewOptionsModel([]string{"port", "dirs", "vuln"})
+	view = m.View()
+	assert.Contains(t, view, "Ports")
+	assert.Contains(t, view, "Wordlist")
+	assert.Contains(t, view, "Checks")
+}
+
+func TestOptionsModelDefaults(t *testing.T) {
+	opts, err := NewOptionsModel([]string{"port"}).Options()
+	require.NoError(t, err)
+	assert.Equal(t, scanner.DefaultOptions().Timeout, opts.Timeout)
+	assert.Equal(t, scanner.DefaultOptions().Concurrency, opts.Concurrency)
+	assert.Nil(t, opts.ExtraArgs)
+	assert.IsType(t, &http.Transport{}, opts.Transport, "the pooled base transport, without header injection")
+}
+
+func TestOptionsModelBuildsOptions(t *testing.T) {
+	m := NewOptionsModel([]string{"port", "dirs", "vuln"})
+	m.SetValue("ports", "80,443")
+	m.SetValue("wordlist", "/tmp/words.txt")
+	m.SetValue("checks", "xss")
+	m.SetValue(fieldTimeout, "2s")
+	m.SetValue(fieldConcurrency, "4")
+	m.SetValue(fieldHeaders, "X-Api-Key: abc; Cookie: session=1")
+
+	opts, err := m.Options()
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, opts.Timeout)
+	assert.Equal(t, 4, opts.Concurrency)
+	assert.Equal(t, "80,443", opts.StringArg("ports"))
+	assert.Equal(t, "/tmp/words.txt", opts.StringArg("wordlist"))
+	assert.Equal(t, "xss", opts.StringArg("checks"))
+
+	ht, ok := opts.Transport.(*scanner.HeaderTransport)
+	require.True(t, ok)
+	assert.Equal(t, "abc", ht.Headers.Get("X-Api-Key"))
+	assert.Equal(t, "session=1", ht.Headers.Get("Cookie"))
+}
+
+func TestOptionsModelRejectsInvalidValues(t *testing.T) {
+	for key, value := range map[string]string{
+		fieldTimeout:     "soon",
+		fieldConcurrency: "0",
+		fieldHeaders:     "no colon here",
+	} {
+		m := NewOptionsModel(nil)
+		m.SetValue(key, value)
+		_, err := m.Options()
+		assert.Error(t, err, key)
+
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		assert.Contains(t, updated.(OptionsModel).View(), key+":")
+	}
+}
+
+func TestOptionsModelTabMovesFocus(t *testing.T) {
+	m := NewOptionsModel(nil)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = updated.(OptionsModel)
+	assert.Equal(t, 1, m.focus)
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	m = updated.(OptionsModel)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	m = updated.(OptionsModel)
+	assert.Equal(t, len(m.fields)-1, m.focus, "focus wraps around")
+}
+
+func TestParseHeaders(t *testing.T) {
+	h, err := parseHeaders("Authorization: Bearer x; X-Trace: 1;")
+	require.NoError(t, err)
+	assert.Equal(t, http.Header{"Authorization": {"Bearer x"}, "X-Trace": {"1"}}, h)
+}
@@ -0,0 +1,163 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/buemura/hunter/internal/tui/styles"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ProfileItem is a scan profile from the config file as listed on the
+// profiles screen.
+type ProfileItem struct {
+	Name       string
+	Scanners   []string
+	Credential string // empty for none
+}
+
+// ProfilesModel is the view model for the scan profile launcher. Picking a
+// profile is handled by the root model through Selected.
+type ProfilesModel struct {
+	items  []ProfileItem
+	cursor int
+	offset int
+	err    string
+	width  int
+	height int
+}
+
+// NewProfilesModel creates a launcher listing the given profiles.
+func NewProfilesModel(items []ProfileItem) ProfilesModel {
+	return ProfilesModel{items: items, width: defaultWidth, height: defaultHeight}
+}
+
+// SetSize fits the list to a terminal of the given size. Profiles that do
+// not fit scroll with the cursor.
+func (m *ProfilesModel) SetSize(width, height int) {
+	m.width, m.height = fitSize(width, height, m.width, m.height)
+	m.offset, _ = window(m.cursor, m.offset, len(m.items), m.maxRows())
+}
+
+// SetError sets a line shown above the help, e.g. why a profile cannot run.
+func (m *ProfilesModel) SetError(err string) {
+	m.err = err
+}
+
+func profilesHelp() string {
+	return joinHelp(
+		pairHelp(keys.Up, keys.Down, "navigate"),
+		shortHelp(keys.Select, "use profile"),
+		shortHelp(keys.Back, "back"),
+		shortHelp(keys.Quit, "quit"),
+	)
+}
+
+// Bindings returns the launcher's key bindings for the help overlay.
+func (m ProfilesModel) Bindings() []key.Binding {
+	use := keys.Select
+	use.SetHelp(use.Help().Key, "enter a target for the profile's scanners")
+	return []key.Binding{keys.Up, keys.Down, use, keys.Back, keys.Quit}
+}
+
+// maxRows returns how many profiles fit between the header and the help line.
+func (m ProfilesModel) maxRows() int {
+	rows := m.height - 6 - lipgloss.Height(helpLine(profilesHelp(), m.width))
+	if m.err != "" {
+		rows -= 2
+	}
+	return max(rows, 3)
+}
+
+// nameWidth returns the width of the profile name column.
+func (m ProfilesModel) nameWidth() int {
+	width := 7
+	for _, item := range m.items {
+		width = max(width, len(item.Name))
+	}
+	return min(width, clamp(m.width/3, 10, 30))
+}
+
+// Init returns nil (no initial command).
+func (m ProfilesModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles navigation.
+func (m ProfilesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(keyMsg, keys.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case key.Matches(keyMsg, keys.Down):
+			if m.cursor < len(m.items)-1 {
+				m.cursor++
+			}
+		case key.Matches(keyMsg, keys.Quit):
+			return m, tea.Quit
+		}
+		m.err = ""
+		m.offset, _ = window(m.cursor, m.offset, len(m.items), m.maxRows())
+	}
+	return m, nil
+}
+
+// View renders the list of scan profiles.
+func (m ProfilesModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(styles.TitleStyle.Render("Hunter — Scan Profiles"))
+	b.WriteString("\n\n")
+
+	if len(m.items) == 0 {
+		b.WriteString("No scan profiles. Add them under scan_profiles in the config file.\n")
+	} else {
+		nameWidth := m.nameWidth()
+		scannersWidth := max(m.width-2-nameWidth-1, 10)
+		header := fmt.Sprintf("  %-*s %s", nameWidth, "PROFILE", "SCANNERS")
+		b.WriteString(styles.HeaderStyle.Render(header))
+		b.WriteString("\n")
+		start, end := window(m.cursor, m.offset, len(m.items), m.maxRows())
+		for i := start; i < end; i++ {
+			item := m.items[i]
+			cursor := "  "
+			name := fmt.Sprintf("%-*s", nameWidth, truncate(item.Name, nameWidth))
+			if i == m.cursor {
+				cursor = styles.CursorStyle.Render("> ")
+				name = styles.SelectedStyle.Render(name)
+			}
+			scanners := strings.Join(item.Scanners, ", ")
+			if item.Credential != "" {
+				scanners += " as " + item.Credential
+			}
+			b.WriteString(fmt.Sprintf("%s%s %s\n", cursor, name, styles.HelpStyle.Render(truncate(scanners, scannersWidth))))
+		}
+		if end-start < len(m.items) {
+			b.WriteString(styles.HelpStyle.Render(fmt.Sprintf("  %d-%d of %d", start+1, end, len(m.items))))
+			b.WriteString("\n")
+		}
+	}
+
+	if m.err != "" {
+		b.WriteString("\n")
+		b.WriteString(styles.ErrorStyle.Render(m.err))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpLine(profilesHelp(), m.width))
+
+	return b.String()
+}
+
+// Selected returns the profile under the cursor, or nil if there are none.
+func (m ProfilesModel) Selected() *ProfileItem {
+	if len(m.items) == 0 {
+		return nil
+	}
+	return &m.items[m.cursor]
+}
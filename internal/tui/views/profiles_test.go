@@ -0,0 +1,44 @@
+package views
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfilesModelListsAndSelects(t *testing.T) {
+	m := NewProfilesModel([]ProfileItem{
+		{Name: "quick", Scanners: []string{"headers", "ssl"}},
+		{Name: "authed", Scanners: []string{"api"}, Credential: "staging-admin"},
+	})
+	view := m.View()
+	assert.Contains(t, view, "Scan Profiles")
+	assert.Contains(t, view, "headers, ssl")
+	assert.Contains(t, view, "api as staging-admin")
+	require.NotNil(t, m.Selected())
+	assert.Equal(t, "quick", m.Selected().Name)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	m = updated.(ProfilesModel)
+	assert.Equal(t, "authed", m.Selected().Name)
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	assert.Equal(t, "authed", updated.(ProfilesModel).Selected().Name, "stays on the last profile")
+}
+
+func TestProfilesModelEmpty(t *testing.T) {
+	m := NewProfilesModel(nil)
+	assert.Nil(t, m.Selected())
+	assert.Contains(t, m.View(), "No scan profiles")
+}
+
+func TestProfilesModelError(t *testing.T) {
+	m := NewProfilesModel([]ProfileItem{{Name: "broken"}})
+	m.SetError("scan profile broken has no scanners")
+	assert.Contains(t, m.View(), "has no scanners")
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	assert.NotContains(t, updated.(ProfilesModel).View(), "has no scanners", "moving clears the error")
+}
@@ -1,63 +1,173 @@
 package views
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
+	"sort"
 	"strings"
 
 	"github.com/buemura/hunter/internal/tui/styles"
 	"github.com/buemura/hunter/pkg/types"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
-// ResultsModel is the view model for displaying scan results.
+// severityFilter pairs a filter toggle binding with the severity it hides or
+// shows.
+type severityFilter struct {
+	binding  key.Binding
+	severity types.Severity
+}
+
+// severityFilters returns the filter toggles in severity order.
+func severityFilters() []severityFilter {
+	return []severityFilter{
+		{keys.FilterCritical, types.SeverityCritical},
+		{keys.FilterHigh, types.SeverityHigh},
+		{keys.FilterMedium, types.SeverityMedium},
+		{keys.FilterLow, types.SeverityLow},
+		{keys.FilterInfo, types.SeverityInfo},
+	}
+}
+
+// ResultsModel is the view model for displaying scan results. Findings can
+// be narrowed by toggling severities off and by a free-text search, and
+// enter opens a scrollable detail pane for the selected finding.
 type ResultsModel struct {
-	results  []types.ScanResult
-	cursor   int
-	offset   int
-	maxRows  int
-	exported bool
+	results   []types.ScanResult
+	cursor    int
+	offset    int
+	exported  string
 	exportErr string
+	exporting bool
+	export    ExportModel
+	notice    string
+
+	hidden    map[types.Severity]bool
+	search    textinput.Model
+	searching bool
+
+	width    int
+	height   int
+	detail   bool
+	viewport viewport.Model
 }
 
 // NewResultsModel creates a results view from scan results.
 func NewResultsModel(results []types.ScanResult) ResultsModel {
+	search := textinput.New()
+	search.Prompt = "/"
+	search.Placeholder = "search title, description, evidence, scanner"
+	search.CharLimit = 128
+	search.Width = 50
+
 	return ResultsModel{
 		results: results,
-		maxRows: 20,
+		hidden:  map[types.Severity]bool{},
+		search:  search,
+		width:   defaultWidth,
+		height:  defaultHeight,
+	}
+}
+
+// SetSize lays the table and the detail pane out for a terminal of the given
+// size.
+func (m *ResultsModel) SetSize(width, height int) {
+	m.width, m.height = fitSize(width, height, m.width, m.height)
+	m.search.Width = clamp(m.width-4, 10, 50)
+	m.offset, _ = window(m.cursor, m.offset, len(m.visibleFindings()), m.maxRows())
+	if m.detail {
+		m.openDetail()
 	}
 }
 
+// SetNotice sets a line shown under the title, e.g. that the results are
+// partial.
+func (m *ResultsModel) SetNotice(notice string) {
+	m.notice = notice
+}
+
+// Searching reports whether the search box has focus.
+func (m ResultsModel) Searching() bool {
+	return m.searching
+}
+
+// Capturing reports whether a text input (the search box or the export
+// dialog) has focus or the detail pane is open, in which case every key,
+// including esc, belongs to the results view.
+func (m ResultsModel) Capturing() bool {
+	return m.searching || m.exporting || m.detail
+}
+
+// Detail reports whether the finding detail pane is open.
+func (m ResultsModel) Detail() bool {
+	return m.detail
+}
+
 // Init returns nil (no initial command).
 func (m ResultsModel) Init() tea.Cmd {
 	return nil
 }
 
-// Update handles key events for scrolling and export.
+// Update handles key events for scrolling, filtering, and export.
 func (m ResultsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	findings := m.allFindings()
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		m.SetSize(size.Width, size.Height)
+		return m, nil
+	}
+	if m.detail {
+		return m.updateDetail(msg)
+	}
+	if m.searching {
+		return m.updateSearch(msg)
+	}
+	if m.exporting {
+		return m.updateExport(msg)
+	}
+
+	findings := m.visibleFindings()
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "up", "k":
+		for _, f := range severityFilters() {
+			if key.Matches(msg, f.binding) {
+				m.toggleSeverity(f.severity)
+				return m, nil
+			}
+		}
+		switch {
+		case key.Matches(msg, keys.Search):
+			m.searching = true
+			return m, m.search.Focus()
+		case key.Matches(msg, keys.Select):
+			if len(findings) > 0 {
+				m.detail = true
+				m.viewport.YOffset = 0
+				m.openDetail()
+			}
+			return m, nil
+		case key.Matches(msg, keys.Up):
 			if m.cursor > 0 {
 				m.cursor--
 				if m.cursor < m.offset {
 					m.offset = m.cursor
 				}
 			}
-		case "down", "j":
+		case key.Matches(msg, keys.Down):
 			if m.cursor < len(findings)-1 {
 				m.cursor++
-				if m.cursor >= m.offset+m.maxRows {
-					m.offset = m.cursor - m.maxRows + 1
+				if m.cursor >= m.offset+m.maxRows() {
+					m.offset = m.cursor - m.maxRows() + 1
 				}
 			}
-		case "e":
-			m.exportJSON()
-		case "q":
+		case key.Matches(msg, keys.Export):
+			m.exporting = true
+			m.export = NewExportModel()
+			m.export.SetWidth(m.width)
+			return m, textinput.Blink
+		case key.Matches(msg, keys.Quit):
 			return m, tea.Quit
 		}
 	}
@@ -65,35 +175,165 @@ func (m ResultsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// updateDetail scrolls the detail pane. Esc or enter closes it.
+func (m ResultsModel) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(keyMsg, keys.Back, keys.Select):
+			m.detail = false
+			return m, nil
+		case key.Matches(keyMsg, keys.Quit):
+			return m, tea.Quit
+		case key.Matches(keyMsg, keys.Top):
+			m.viewport.GotoTop()
+			return m, nil
+		case key.Matches(keyMsg, keys.Bottom):
+			m.viewport.GotoBottom()
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// openDetail sizes the detail pane to the terminal and fills it with the
+// selected finding, wrapped to the pane width.
+func (m *ResultsModel) openDetail() {
+	findings := m.visibleFindings()
+	if m.cursor >= len(findings) {
+		m.detail = false
+		return
+	}
+	// Leave room for the title above the pane and the status and help lines
+	// below it.
+	height := max(m.height-5-lipgloss.Height(helpLine(detailHelp(), m.width)), 3)
+	offset := m.viewport.YOffset
+	m.viewport = viewport.New(m.width, height)
+	m.viewport.KeyMap = viewportKeys()
+	m.viewport.SetContent(detailContent(findings[m.cursor], m.width))
+	m.viewport.SetYOffset(offset)
+}
+
+// updateExport drives the export dialog. Enter saves the findings currently
+// shown; esc closes the dialog without saving.
+func (m ResultsModel) updateExport(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "enter":
+			if err := m.export.Save(m.visibleResults()); err != nil {
+				m.exportErr = fmt.Sprintf("export failed: %v", err)
+				m.exported = ""
+				return m, nil
+			}
+			m.exporting = false
+			m.exportErr = ""
+			m.exported = fmt.Sprintf("Results exported to %s (%s)", m.export.Path(), m.export.Format())
+			return m, nil
+		case "esc":
+			m.exporting = false
+			m.exportErr = ""
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.export, cmd = m.export.Update(msg)
+	return m, cmd
+}
+
+// updateSearch feeds keys to the search box. The filter applies as you type;
+// enter keeps it and esc clears it.
+func (m ResultsModel) updateSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "enter":
+			m.searching = false
+			m.search.Blur()
+			return m, nil
+		case "esc":
+			m.searching = false
+			m.search.Blur()
+			m.search.SetValue("")
+			m.resetCursor()
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.search, cmd = m.search.Update(msg)
+	m.resetCursor()
+	return m, cmd
+}
+
+func (m *ResultsModel) toggleSeverity(sev types.Severity) {
+	// Copy on write so earlier copies of the model keep their filter.
+	hidden := make(map[types.Severity]bool, len(m.hidden)+1)
+	for k, v := range m.hidden {
+		hidden[k] = v
+	}
+	hidden[sev] = !hidden[sev]
+	m.hidden = hidden
+	m.resetCursor()
+}
+
+func (m *ResultsModel) resetCursor() {
+	m.cursor = 0
+	m.offset = 0
+}
+
+// filtering reports whether any severity is hidden or a search is active.
+func (m ResultsModel) filtering() bool {
+	for _, hidden := range m.hidden {
+		if hidden {
+			return true
+		}
+	}
+	return strings.TrimSpace(m.search.Value()) != ""
+}
+
 // View renders the results table.
 func (m ResultsModel) View() string {
+	if m.detail {
+		return m.detailView()
+	}
+
 	var b strings.Builder
 
 	b.WriteString(styles.TitleStyle.Render("Hunter — Scan Results"))
 	b.WriteString("\n\n")
+	if m.notice != "" {
+		b.WriteString(styles.ErrorStyle.Render(m.notice))
+		b.WriteString("\n\n")
+	}
 
-	findings := m.allFindings()
-	if len(findings) == 0 {
+	all := m.allFindings()
+	findings := m.visibleFindings()
+	if len(all) == 0 {
 		b.WriteString("No findings discovered.\n")
 	} else {
 		// Summary line.
-		b.WriteString(m.summaryLine(findings))
-		b.WriteString("\n\n")
+		b.WriteString(m.summaryLine(findings, len(all)))
+		b.WriteString("\n")
+		if m.searching || m.search.Value() != "" {
+			b.WriteString(m.search.View())
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
 
-		// Table header.
-		header := fmt.Sprintf("  %-10s %-50s %s", "SEVERITY", "TITLE", "SCANNER")
+		// Table header. The title column takes whatever width the severity
+		// and scanner columns leave.
+		titleWidth := m.titleWidth()
+		header := fmt.Sprintf("  %-10s %-*s %s", "SEVERITY", titleWidth, "TITLE", "SCANNER")
 		b.WriteString(styles.HeaderStyle.Render(header))
 		b.WriteString("\n")
-		b.WriteString(strings.Repeat("─", 80))
+		b.WriteString(strings.Repeat("─", m.width))
 		b.WriteString("\n")
 
 		// Table rows with scrolling.
-		end := m.offset + m.maxRows
-		if end > len(findings) {
-			end = len(findings)
-		}
-
-		for i := m.offset; i < end; i++ {
+		start, end := window(m.cursor, m.offset, len(findings), m.maxRows())
+		for i := start; i < end; i++ {
 			f := findings[i]
 			cursor := "  "
 			if i == m.cursor {
@@ -102,28 +342,30 @@ func (m ResultsModel) View() string {
 
 			sevStyle := styles.SeverityStyle(string(f.finding.Severity))
 			severity := sevStyle.Render(fmt.Sprintf("%-10s", f.finding.Severity))
-			title := truncate(f.finding.Title, 50)
-			scanner := styles.HelpStyle.Render(f.scannerName)
+			title := truncate(f.finding.Title, titleWidth)
+			scanner := styles.HelpStyle.Render(truncate(f.scannerName, scannerColumnWidth))
 
-			b.WriteString(fmt.Sprintf("%s%s %-50s %s\n", cursor, severity, title, scanner))
+			b.WriteString(fmt.Sprintf("%s%s %-*s %s\n", cursor, severity, titleWidth, title, scanner))
+		}
+
+		if len(findings) == 0 {
+			b.WriteString("  No findings match the current filter.\n")
 		}
 
 		// Scroll indicator.
-		if len(findings) > m.maxRows {
+		if len(findings) > m.maxRows() {
 			b.WriteString(fmt.Sprintf("\n  Showing %d-%d of %d findings\n",
-				m.offset+1, end, len(findings)))
+				start+1, end, len(findings)))
 		}
 	}
 
-	// Detail view for selected finding.
-	if len(findings) > 0 && m.cursor < len(findings) {
+	if m.exporting {
 		b.WriteString("\n")
-		b.WriteString(m.detailView(findings[m.cursor]))
+		b.WriteString(m.export.View())
 	}
-
-	if m.exported {
+	if m.exported != "" {
 		b.WriteString("\n")
-		b.WriteString(styles.SelectedStyle.Render("Results exported to hunter-results.json"))
+		b.WriteString(styles.SelectedStyle.Render(m.exported))
 	}
 	if m.exportErr != "" {
 		b.WriteString("\n")
@@ -131,11 +373,97 @@ func (m ResultsModel) View() string {
 	}
 
 	b.WriteString("\n")
-	b.WriteString(styles.HelpStyle.Render("↑/↓ scroll • e export JSON • esc back • q quit"))
+	switch {
+	case m.searching:
+		b.WriteString(helpLine("type to search • enter keep • esc clear", m.width))
+	case !m.exporting:
+		b.WriteString(helpLine(resultsHelp(), m.width))
+	}
 
 	return b.String()
 }
 
+// resultsHelp is the help line for the results table.
+func resultsHelp() string {
+	var filters []string
+	for _, f := range severityFilters() {
+		filters = append(filters, firstKey(f.binding))
+	}
+	return joinHelp(
+		pairHelp(keys.Up, keys.Down, "scroll"),
+		shortHelp(keys.Select, "details"),
+		shortHelp(keys.Search, "search"),
+		strings.Join(filters, "/")+" toggle severity",
+		shortHelp(keys.Export, "export"),
+		shortHelp(keys.Rerun, "re-run"),
+		shortHelp(keys.Back, "back"),
+		shortHelp(keys.Quit, "quit"),
+	)
+}
+
+// detailHelp is the help line for the detail pane.
+func detailHelp() string {
+	return joinHelp(
+		pairHelp(keys.Up, keys.Down, "scroll"),
+		pairHelp(keys.PageUp, keys.PageDown, "page"),
+		pairHelp(keys.Top, keys.Bottom, "top/bottom"),
+		shortHelp(keys.Back, "close"),
+		shortHelp(keys.Quit, "quit"),
+	)
+}
+
+// Bindings returns the key bindings of the results table, the detail pane,
+// or the text box that has focus, for the help overlay.
+func (m ResultsModel) Bindings() []key.Binding {
+	switch {
+	case m.searching:
+		return []key.Binding{fixedBinding("enter", "keep the search"), fixedBinding("esc", "clear the search")}
+	case m.exporting:
+		return []key.Binding{fixedBinding("tab", "next format"), fixedBinding("shift+tab", "previous format"),
+			fixedBinding("enter", "save"), fixedBinding("esc", "cancel")}
+	case m.detail:
+		closeKey := keys.Back
+		closeKey.SetHelp(closeKey.Help().Key, "close the detail pane")
+		return []key.Binding{keys.Up, keys.Down, keys.PageUp, keys.PageDown, keys.Top, keys.Bottom, closeKey, keys.Quit}
+	}
+	details, rerun := keys.Select, keys.Rerun
+	details.SetHelp(details.Help().Key, "show finding details")
+	rerun.SetHelp(rerun.Help().Key, "re-run the scan")
+	bindings := []key.Binding{keys.Up, keys.Down, details, keys.Search}
+	for _, f := range severityFilters() {
+		bindings = append(bindings, f.binding)
+	}
+	return append(bindings, keys.Export, rerun, keys.Back, keys.Quit)
+}
+
+// scannerColumnWidth fits every built-in scanner name.
+const scannerColumnWidth = 14
+
+// titleWidth returns the width of the title column: what is left of the
+// terminal after the cursor, severity, and scanner columns.
+func (m ResultsModel) titleWidth() int {
+	return max(m.width-2-10-1-1-scannerColumnWidth, 10)
+}
+
+// maxRows returns how many table rows fit on screen under the title, summary,
+// and table header and above the help line and any open dialog.
+func (m ResultsModel) maxRows() int {
+	rows := m.height - 10 - lipgloss.Height(helpLine(resultsHelp(), m.width))
+	if m.notice != "" {
+		rows -= 2
+	}
+	if m.searching || m.search.Value() != "" {
+		rows--
+	}
+	if m.exporting {
+		rows -= 7
+	}
+	if m.exported != "" || m.exportErr != "" {
+		rows -= 2
+	}
+	return max(rows, 3)
+}
+
 type findingRow struct {
 	finding     types.Finding
 	scannerName string
@@ -151,7 +479,44 @@ func (m ResultsModel) allFindings() []findingRow {
 	return rows
 }
 
-func (m ResultsModel) summaryLine(findings []findingRow) string {
+// visibleFindings returns the findings that pass the severity filter and
+// match the search text, case-insensitively.
+func (m ResultsModel) visibleFindings() []findingRow {
+	query := m.query()
+	var rows []findingRow
+	for _, row := range m.allFindings() {
+		if m.shows(row, query) {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// query returns the lowercased search text.
+func (m ResultsModel) query() string {
+	return strings.ToLower(strings.TrimSpace(m.search.Value()))
+}
+
+// shows reports whether row passes the severity filter and matches query.
+func (m ResultsModel) shows(row findingRow, query string) bool {
+	if m.hidden[row.finding.Severity] {
+		return false
+	}
+	return query == "" || row.matches(query)
+}
+
+func (r findingRow) matches(query string) bool {
+	for _, field := range []string{r.finding.Title, r.finding.Description, r.finding.Evidence, r.scannerName} {
+		if strings.Contains(strings.ToLower(field), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// summaryLine counts the visible findings by severity. When a filter is
+// active it also says how many findings are hidden and how.
+func (m ResultsModel) summaryLine(findings []findingRow, total int) string {
 	counts := map[types.Severity]int{}
 	for _, f := range findings {
 		counts[f.finding.Severity]++
@@ -168,48 +533,117 @@ func (m ResultsModel) summaryLine(findings []findingRow) string {
 		}
 	}
 
-	return fmt.Sprintf("Total: %d findings  [%s]", len(findings), strings.Join(parts, "  "))
-}
-
-func (m ResultsModel) detailView(row findingRow) string {
-	var b strings.Builder
-	b.WriteString(styles.BorderStyle.Render(
-		fmt.Sprintf("Title: %s\nSeverity: %s\nDescription: %s",
-			row.finding.Title,
-			row.finding.Severity,
-			row.finding.Description,
-		),
-	))
+	if !m.filtering() {
+		return fmt.Sprintf("Total: %d findings  [%s]", len(findings), strings.Join(parts, "  "))
+	}
 
-	if row.finding.Evidence != "" {
-		b.WriteString(fmt.Sprintf("\n  Evidence: %s", row.finding.Evidence))
+	var filters []string
+	for _, sev := range []types.Severity{
+		types.SeverityCritical, types.SeverityHigh,
+		types.SeverityMedium, types.SeverityLow, types.SeverityInfo,
+	} {
+		if m.hidden[sev] {
+			filters = append(filters, "-"+string(sev))
+		}
 	}
-	if row.finding.Remediation != "" {
-		b.WriteString(fmt.Sprintf("\n  Remediation: %s", row.finding.Remediation))
+	if q := strings.TrimSpace(m.search.Value()); q != "" {
+		filters = append(filters, fmt.Sprintf("%q", q))
 	}
+	return fmt.Sprintf("Showing %d of %d findings  [%s]  filter: %s",
+		len(findings), total, strings.Join(parts, "  "), strings.Join(filters, " "))
+}
 
+// detailView renders the full-screen detail pane for the selected finding.
+func (m ResultsModel) detailView() string {
+	var b strings.Builder
+	b.WriteString(styles.TitleStyle.Render("Hunter — Finding Detail"))
+	b.WriteString("\n\n")
+	b.WriteString(m.viewport.View())
+	b.WriteString("\n")
+	b.WriteString(styles.HelpStyle.Render(fmt.Sprintf("%3.0f%%", m.viewport.ScrollPercent()*100)))
+	b.WriteString("\n")
+	b.WriteString(helpLine(detailHelp(), m.width))
 	return b.String()
 }
 
-func (m *ResultsModel) exportJSON() {
-	data, err := json.MarshalIndent(m.results, "", "  ")
-	if err != nil {
-		m.exportErr = fmt.Sprintf("export failed: %v", err)
-		return
+// detailContent lays out every field of a finding, word-wrapped to width.
+// Long evidence is wrapped rather than truncated; the pane scrolls instead.
+func detailContent(row findingRow, width int) string {
+	wrap := lipgloss.NewStyle().Width(width - 2)
+	f := row.finding
+
+	var b strings.Builder
+	b.WriteString(wrap.Render(styles.HeaderStyle.Render(f.Title)))
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("Severity: %s  •  Scanner: %s\n",
+		styles.SeverityStyle(string(f.Severity)).Render(string(f.Severity)), row.scannerName))
+
+	section := func(name, text string) {
+		if text == "" {
+			return
+		}
+		b.WriteString("\n")
+		b.WriteString(styles.HeaderStyle.Render(name))
+		b.WriteString("\n")
+		b.WriteString(wrap.Render(text))
+		b.WriteString("\n")
+	}
+	section("Description", f.Description)
+	section("Evidence", f.Evidence)
+	section("Remediation", f.Remediation)
+	if len(f.References) > 0 {
+		refs := make([]string, len(f.References))
+		for i, ref := range f.References {
+			refs[i] = fmt.Sprintf("%s: %s", ref.Title, ref.URL)
+		}
+		section("References", strings.Join(refs, "\n"))
 	}
 
-	if err := os.WriteFile("hunter-results.json", data, 0644); err != nil {
-		m.exportErr = fmt.Sprintf("export failed: %v", err)
-		return
+	if len(f.Metadata) > 0 {
+		keys := make([]string, 0, len(f.Metadata))
+		for k := range f.Metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		lines := make([]string, 0, len(keys))
+		for _, k := range keys {
+			lines = append(lines, fmt.Sprintf("%s: %s", k, f.Metadata[k]))
+		}
+		section("Metadata", strings.Join(lines, "\n"))
 	}
 
-	m.exported = true
-	m.exportErr = ""
+	return strings.TrimRight(b.String(), "\n")
 }
 
+// visibleResults returns the scan results with only the findings that pass
+// the current filter, for export.
+func (m ResultsModel) visibleResults() []types.ScanResult {
+	if !m.filtering() {
+		return m.results
+	}
+	out := make([]types.ScanResult, len(m.results))
+	query := m.query()
+	for i, r := range m.results {
+		out[i] = r
+		out[i].Findings = nil
+		for _, f := range r.Findings {
+			if m.shows(findingRow{finding: f, scannerName: r.ScannerName}, query) {
+				out[i].Findings = append(out[i].Findings, f)
+			}
+		}
+	}
+	return out
+}
+
+// truncate shortens s to at most max characters, marking the cut with an
+// ellipsis.
 func truncate(s string, max int) string {
-	if len(s) <= max {
+	runes := []rune(s)
+	if len(runes) <= max {
 		return s
 	}
-	return s[:max-3] + "..."
+	if max <= 3 {
+		return string(runes[:max])
+	}
+	return string(runes[:max-3]) + "..."
 }
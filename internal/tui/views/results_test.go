@@ -1,9 +1,12 @@
 package views
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/buemura/hunter/pkg/types"
@@ -97,9 +100,79 @@ func TestResultsModelDetailViewIncludesRemediation(t *testing.T) {
 		m = updated.(ResultsModel)
 	}
 
+	// The list no longer shows details inline; enter opens the pane.
+	assert.NotContains(t, m.View(), "Add HSTS header")
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(ResultsModel)
+	assert.True(t, m.Detail())
+
 	view := m.View()
 	assert.Contains(t, view, "Remediation")
 	assert.Contains(t, view, "Add HSTS header")
+	assert.Contains(t, view, "Scanner: headers")
+}
+
+func TestResultsModelDetailShowsMetadata(t *testing.T) {
+	m := NewResultsModel([]types.ScanResult{{
+		ScannerName: "ssl",
+		Findings: []types.Finding{{
+			Title:    "Weak cipher",
+			Severity: types.SeverityMedium,
+			Metadata: map[string]string{"protocol": "TLS1.0", "cipher": "RC4"},
+		}},
+	}})
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(ResultsModel)
+
+	view := m.View()
+	assert.Contains(t, view, "Metadata")
+	assert.Contains(t, view, "cipher: RC4")
+	assert.Contains(t, view, "protocol: TLS1.0")
+	assert.Less(t, strings.Index(view, "cipher"), strings.Index(view, "protocol"))
+}
+
+func TestResultsModelDetailWrapsAndScrolls(t *testing.T) {
+	evidence := strings.Repeat("evidence ", 200)
+	m := NewResultsModel([]types.ScanResult{{
+		ScannerName: "vuln",
+		Findings:    []types.Finding{{Title: "Reflected input", Severity: types.SeverityHigh, Evidence: evidence}},
+	}})
+	m.SetSize(40, 12)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(ResultsModel)
+	for _, line := range strings.Split(m.viewport.View(), "\n") {
+		assert.LessOrEqual(t, lipgloss.Width(line), 40)
+	}
+	assert.True(t, m.viewport.AtTop())
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	m = updated.(ResultsModel)
+	assert.False(t, m.viewport.AtTop())
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("G")})
+	m = updated.(ResultsModel)
+	assert.True(t, m.viewport.AtBottom())
+}
+
+func TestResultsModelDetailClose(t *testing.T) {
+	m := NewResultsModel(newTestResults())
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(ResultsModel)
+	assert.True(t, m.Capturing())
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(ResultsModel)
+	assert.False(t, m.Detail())
+	assert.Contains(t, m.View(), "Scan Results")
+}
+
+func TestResultsModelDetailNoFindings(t *testing.T) {
+	m := NewResultsModel(nil)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(ResultsModel)
+	assert.False(t, m.Detail())
 }
 
 func TestResultsModelQuit(t *testing.T) {
@@ -112,4 +185,114 @@ func TestTruncate(t *testing.T) {
 	assert.Equal(t, "hello", truncate("hello", 10))
 	assert.Equal(t, "hel...", truncate("hello world", 6))
 	assert.Equal(t, "hello world", truncate("hello world", 50))
+	assert.Equal(t, "héllo...", truncate("héllo wörld", 8))
+	assert.Equal(t, "hé", truncate("héllo", 2))
+}
+
+func TestResultsModelSeverityFilter(t *testing.T) {
+	m := NewResultsModel(newTestResults())
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	m = updated.(ResultsModel)
+	assert.Len(t, m.visibleFindings(), 2)
+
+	view := m.View()
+	assert.Contains(t, view, "Showing 2 of 4 findings")
+	assert.Contains(t, view, "-INFO")
+	assert.NotContains(t, view, "Open port: 80")
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	m = updated.(ResultsModel)
+	assert.Len(t, m.visibleFindings(), 4)
+	assert.Contains(t, m.View(), "Total: 4 findings")
+}
+
+func TestResultsModelSearch(t *testing.T) {
+	m := NewResultsModel(newTestResults())
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m = updated.(ResultsModel)
+	assert.True(t, m.Searching())
+
+	// Keys go to the search box, not the severity toggles or quit.
+	for _, r := range "hsts" {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(ResultsModel)
+	}
+	assert.Empty(t, m.hidden[types.SeverityHigh])
+	rows := m.visibleFindings()
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "Missing HSTS", rows[0].finding.Title)
+	assert.Contains(t, m.View(), `filter: "hsts"`)
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(ResultsModel)
+	assert.False(t, m.Searching())
+	assert.Len(t, m.visibleFindings(), 1, "enter keeps the filter")
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m = updated.(ResultsModel)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	m = updated.(ResultsModel)
+	assert.Len(t, m.visibleFindings(), 4, "esc clears the search")
+}
+
+func TestResultsModelSearchNoMatches(t *testing.T) {
+	m := NewResultsModel(newTestResults())
+	m.search.SetValue("nothing like this")
+	assert.Contains(t, m.View(), "No findings match")
+}
+
+func manyFindings(n int) []types.ScanResult {
+	findings := make([]types.Finding, n)
+	for i := range findings {
+		findings[i] = types.Finding{
+			Title:    fmt.Sprintf("Finding %02d with a title long enough to need truncating on narrow terminals", i),
+			Severity: types.SeverityLow,
+		}
+	}
+	return []types.ScanResult{{ScannerName: "headers", Findings: findings}}
+}
+
+func TestResultsModelFitsTerminal(t *testing.T) {
+	m := NewResultsModel(manyFindings(50))
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 60, Height: 20})
+	m = updated.(ResultsModel)
+
+	view := m.View()
+	for _, line := range strings.Split(view, "\n") {
+		assert.LessOrEqual(t, lipgloss.Width(line), 60, line)
+	}
+	assert.LessOrEqual(t, strings.Count(view, "\n")+1, 20)
+	assert.Contains(t, view, "Finding 00")
+	assert.Contains(t, view, "...")
+
+	// The cursor stays on screen when moving past the last visible row.
+	for i := 0; i < 30; i++ {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+		m = updated.(ResultsModel)
+	}
+	view = m.View()
+	assert.Contains(t, view, "Finding 30")
+	assert.NotContains(t, view, "Finding 00")
+}
+
+func TestResultsModelUsesWideTerminal(t *testing.T) {
+	m := NewResultsModel(manyFindings(50))
+	m.SetSize(200, 60)
+
+	view := m.View()
+	assert.Contains(t, view, "truncating on narrow terminals")
+	assert.Contains(t, view, "Finding 40")
+}
+
+func TestResultsModelShrinkKeepsCursorVisible(t *testing.T) {
+	m := NewResultsModel(manyFindings(50))
+	m.SetSize(80, 60)
+	for i := 0; i < 40; i++ {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+		m = updated.(ResultsModel)
+	}
+	m.SetSize(80, 20)
+	assert.Contains(t, m.View(), "Finding 40")
 }
@@ -3,68 +3,199 @@ package views
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/buemura/hunter/internal/scanner"
 	"github.com/buemura/hunter/internal/tui/styles"
 	"github.com/buemura/hunter/pkg/types"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
-// ScanCompleteMsg is sent when a scan finishes.
+// ScanCompleteMsg is sent when all selected scanners have finished, or when
+// the scan was cancelled, in which case Results holds what finished so far.
 type ScanCompleteMsg struct {
+	Results   []types.ScanResult
+	Cancelled bool
+}
+
+// scannerStartedMsg reports that the scanner at Index began running.
+type scannerStartedMsg struct {
+	Index int
+}
+
+// scannerProgressMsg carries incremental progress for the running scanner.
+type scannerProgressMsg struct {
+	Index       int
+	Done, Total int
+}
+
+// scannerProbeMsg reports the endpoint the running scanner just requested.
+type scannerProbeMsg struct {
+	Index    int
+	Endpoint string
+}
+
+// scannerDoneMsg carries the result of the scanner at Index.
+type scannerDoneMsg struct {
+	Index  int
 	Result types.ScanResult
 }
 
-// scanErrorMsg is sent when a scan encounters an error.
-type scanErrorMsg struct {
-	err error
+// scannerState is where a scanner is in its run.
+type scannerState int
+
+const (
+	scannerPending scannerState = iota
+	scannerRunning
+	scannerDone
+	scannerFailed
+	scannerSkipped
+)
+
+// scannerStatus is one row of the scan checklist.
+type scannerStatus struct {
+	name        string
+	state       scannerState
+	done, total int
+	findings    int
+	err         string
 }
 
-// ScanModel is the view model for the scan progress view.
+// ScanModel is the view model for the scan progress view. It runs the
+// selected scanners one after another and shows a live checklist fed by
+// progress messages streamed from the scan goroutine.
 type ScanModel struct {
-	spinner     spinner.Model
-	scanner     scanner.Scanner
-	target      types.Target
-	done        bool
-	err         string
-	result      types.ScanResult
+	spinner  spinner.Model
+	scanners []scanner.Scanner
+	target   types.Target
+	opts     scanner.Options
+	events   chan tea.Msg
+	ctx      context.Context
+	cancel   context.CancelFunc
+
+	cancelling bool
+
+	statuses []scannerStatus
+	endpoint string
+	findings int
+	started  time.Time
+	now      time.Time
+	done     bool
+	results  []types.ScanResult
+
+	width  int
+	height int
 }
 
-// NewScanModel creates a scan progress view for the given scanner and target.
-func NewScanModel(s scanner.Scanner, target types.Target) ScanModel {
+// NewScanModel creates a scan progress view for the given scanners, target,
+// and options.
+func NewScanModel(scanners []scanner.Scanner, target types.Target, opts scanner.Options) ScanModel {
 	sp := spinner.New()
 	sp.Spinner = spinner.Dot
 	sp.Style = lipgloss.NewStyle().Foreground(styles.ColorAccent)
 
+	ctx, cancel := context.WithCancel(context.Background())
+
+	statuses := make([]scannerStatus, len(scanners))
+	for i, s := range scanners {
+		statuses[i] = scannerStatus{name: s.Name()}
+	}
+
 	return ScanModel{
-		spinner: sp,
-		scanner: s,
-		target:  target,
+		spinner:  sp,
+		scanners: scanners,
+		target:   target,
+		opts:     opts,
+		events:   make(chan tea.Msg, 64),
+		ctx:      ctx,
+		cancel:   cancel,
+		statuses: statuses,
+		width:    defaultWidth,
+		height:   defaultHeight,
 	}
 }
 
+// SetSize fits the checklist to a terminal of the given size. When there are
+// more scanners than rows, the list follows the scanner that is running.
+func (m *ScanModel) SetSize(width, height int) {
+	m.width, m.height = fitSize(width, height, m.width, m.height)
+}
+
 // Init starts the spinner and launches the scan.
 func (m ScanModel) Init() tea.Cmd {
-	return tea.Batch(m.spinner.Tick, m.runScan())
+	return tea.Batch(m.spinner.Tick, m.startScan())
 }
 
-// Update handles spinner ticks and scan completion.
+// startScan returns a command that launches the scan goroutine and delivers
+// its first progress message.
+func (m ScanModel) startScan() tea.Cmd {
+	events := m.events
+	return func() tea.Msg {
+		go m.runScan()
+		return <-events
+	}
+}
+
+// Update handles spinner ticks and the progress stream.
 func (m ScanModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case scannerStartedMsg:
+		if m.started.IsZero() {
+			m.started = time.Now()
+			m.now = m.started
+		}
+		m.statuses[msg.Index].state = scannerRunning
+		m.endpoint = ""
+		return m, m.waitForEvent()
+
+	case scannerProgressMsg:
+		m.statuses[msg.Index].done = msg.Done
+		m.statuses[msg.Index].total = msg.Total
+		return m, m.waitForEvent()
+
+	case scannerProbeMsg:
+		m.endpoint = msg.Endpoint
+		return m, m.waitForEvent()
+
+	case scannerDoneMsg:
+		st := &m.statuses[msg.Index]
+		st.findings = len(msg.Result.Findings)
+		st.state = scannerDone
+		if msg.Result.Error != "" {
+			st.state = scannerFailed
+			st.err = msg.Result.Error
+		}
+		m.findings += st.findings
+		return m, m.waitForEvent()
+
 	case ScanCompleteMsg:
 		m.done = true
-		m.result = msg.Result
+		m.results = msg.Results
+		m.endpoint = ""
+		for i := range m.statuses {
+			if m.statuses[i].state == scannerPending {
+				m.statuses[i].state = scannerSkipped
+			}
+		}
 		return m, nil
 
-	case scanErrorMsg:
-		m.done = true
-		m.err = msg.err.Error()
+	case tea.KeyMsg:
+		if key.Matches(msg, keys.Cancel) && !m.done && !m.cancelling {
+			m.cancelling = true
+			m.cancel()
+		}
 		return m, nil
 
 	case spinner.TickMsg:
+		if !m.done {
+			m.now = time.Now()
+		}
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
 		return m, cmd
@@ -73,47 +204,171 @@ func (m ScanModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// View renders the scan progress.
+// View renders the scanner checklist and live counters.
 func (m ScanModel) View() string {
 	var b strings.Builder
 
 	b.WriteString(styles.TitleStyle.Render("Hunter — Interactive Mode"))
 	b.WriteString("\n\n")
+	b.WriteString(fmt.Sprintf("Target: %s\n\n", truncate(targetDisplay(m.target), m.width-8)))
 
-	if m.done {
-		if m.err != "" {
-			b.WriteString(styles.ErrorStyle.Render(fmt.Sprintf("Scan failed: %s", m.err)))
-		} else {
-			b.WriteString(fmt.Sprintf("Scan complete! Found %d findings.\n",
-				len(m.result.Findings)))
-		}
-	} else {
-		b.WriteString(fmt.Sprintf("%s Running %s scanner...\n",
-			m.spinner.View(),
-			styles.SelectedStyle.Render(m.scanner.Name())))
-		b.WriteString(fmt.Sprintf("  Target: %s\n", targetDisplay(m.target)))
+	// Everything but the checklist takes 10 lines, counting the "N-M of T"
+	// line shown when the list scrolls.
+	start, end := window(m.current(), 0, len(m.statuses), max(m.height-10, 3))
+	for _, st := range m.statuses[start:end] {
+		b.WriteString(m.statusLine(st))
+		b.WriteString("\n")
+	}
+	if end-start < len(m.statuses) {
+		b.WriteString(styles.HelpStyle.Render(fmt.Sprintf("%d-%d of %d scanners", start+1, end, len(m.statuses))))
+		b.WriteString("\n")
 	}
 
 	b.WriteString("\n")
-	b.WriteString(styles.HelpStyle.Render("ctrl+c quit"))
+	summary := fmt.Sprintf("Findings: %d  •  Elapsed: %s", m.findings, m.Elapsed().Round(time.Second))
+	switch {
+	case m.done && m.cancelling:
+		summary = "Scan cancelled. " + summary
+	case m.done:
+		summary = "Scan complete! " + summary
+	case m.cancelling:
+		summary = "Cancelling… " + summary
+	}
+	b.WriteString(summary)
+	b.WriteString("\n")
+	if m.endpoint != "" {
+		b.WriteString(styles.HelpStyle.Render("→ " + truncate(m.endpoint, m.width-2)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(styles.HelpStyle.Render(joinHelp(shortHelp(keys.Cancel, "cancel"), "ctrl+c quit")))
 
 	return b.String()
 }
 
-func (m ScanModel) runScan() tea.Cmd {
-	s := m.scanner
-	t := m.target
+func (m ScanModel) statusLine(st scannerStatus) string {
+	switch st.state {
+	case scannerRunning:
+		line := fmt.Sprintf("%s %s", m.spinner.View(), styles.SelectedStyle.Render(st.name))
+		if st.total > 0 {
+			line += styles.HelpStyle.Render(fmt.Sprintf("  %d/%d", st.done, st.total))
+		}
+		return line
+	case scannerDone:
+		return fmt.Sprintf("%s %s  %s", styles.SeverityLowStyle.Render("✓"), st.name,
+			styles.HelpStyle.Render(fmt.Sprintf("%d findings", st.findings)))
+	case scannerFailed:
+		return fmt.Sprintf("%s %s  %s", styles.ErrorStyle.Render("✗"), st.name,
+			styles.ErrorStyle.Render(truncate(st.err, max(m.width-len(st.name)-4, 10))))
+	case scannerSkipped:
+		return styles.HelpStyle.Render("- " + st.name + "  skipped")
+	}
+	return styles.HelpStyle.Render("· " + st.name)
+}
+
+// Bindings returns the scan view's key bindings for the help overlay.
+func (m ScanModel) Bindings() []key.Binding {
+	return []key.Binding{keys.Cancel, fixedBinding("ctrl+c", "quit")}
+}
+
+// current returns the index of the last scanner that has started, which
+// the checklist keeps on screen.
+func (m ScanModel) current() int {
+	current := 0
+	for i, st := range m.statuses {
+		if st.state != scannerPending && st.state != scannerSkipped {
+			current = i
+		}
+	}
+	return current
+}
+
+// Elapsed returns how long the scan has been running.
+func (m ScanModel) Elapsed() time.Duration {
+	if m.started.IsZero() {
+		return 0
+	}
+	return m.now.Sub(m.started)
+}
+
+// waitForEvent returns a command that delivers the next progress message.
+func (m ScanModel) waitForEvent() tea.Cmd {
+	events := m.events
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), scanner.DefaultOptions().Timeout*100)
-		defer cancel()
+		return <-events
+	}
+}
+
+// runScan runs each scanner in turn through a Runner whose hooks stream
+// progress on m.events, finishing with a ScanCompleteMsg. Progress and probe
+// messages are dropped rather than blocking the scan when the UI falls
+// behind. Cancelling m.ctx stops the running scanner and skips the rest.
+func (m ScanModel) runScan() {
+	opts := m.opts
+	ctx, cancel := context.WithTimeout(m.ctx, opts.Timeout*100)
+	defer cancel()
+
+	// Scanners report from their own goroutines, so the index of the
+	// running scanner is read atomically.
+	var current atomic.Int32
+	opts.Transport = &probeTransport{base: scanner.NewCacheTransport(opts.Transport), report: func(req *http.Request) {
+		m.trysend(scannerProbeMsg{Index: int(current.Load()), Endpoint: req.Method + " " + req.URL.String()})
+	}}
+
+	reg := scanner.NewRegistry()
+	for _, s := range m.scanners {
+		reg.Register(s)
+	}
+	results := make([]types.ScanResult, 0, len(m.scanners))
+	runner := scanner.NewRunner(reg).With(scanner.Hooks{
+		OnScannerStart: func(string) {
+			m.events <- scannerStartedMsg{Index: int(current.Load())}
+		},
+		OnProgress: func(_ string, done, total int) {
+			m.trysend(scannerProgressMsg{Index: int(current.Load()), Done: done, Total: total})
+		},
+		OnScannerComplete: func(result types.ScanResult) {
+			if result.Error != "" && m.ctx.Err() != nil {
+				result.Error = "cancelled"
+			}
+			results = append(results, result)
+			m.events <- scannerDoneMsg{Index: int(current.Load()), Result: result}
+		},
+	})
 
-		opts := scanner.DefaultOptions()
-		result, err := s.Run(ctx, t, opts)
-		if err != nil {
-			return scanErrorMsg{err: err}
+	// A failing scanner is recorded in its result so the others still run.
+	for i, s := range m.scanners {
+		if m.ctx.Err() != nil {
+			break
 		}
-		return ScanCompleteMsg{Result: *result}
+		current.Store(int32(i))
+		_, _ = runner.RunOne(ctx, s.Name(), m.target, opts)
+	}
+	m.events <- ScanCompleteMsg{Results: results, Cancelled: m.ctx.Err() != nil}
+}
+
+func (m ScanModel) trysend(msg tea.Msg) {
+	select {
+	case m.events <- msg:
+	default:
+	}
+}
+
+// probeTransport is an http.RoundTripper that reports each request before
+// sending it with base, or http.DefaultTransport when base is nil.
+type probeTransport struct {
+	base   http.RoundTripper
+	report func(req *http.Request)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (p *probeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	p.report(req)
+	if p.base == nil {
+		return http.DefaultTransport.RoundTrip(req)
 	}
+	return p.base.RoundTrip(req)
 }
 
 func targetDisplay(t types.Target) string {
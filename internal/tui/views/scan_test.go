@@ -0,0 +1,131 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeScanner struct {
+	name     string
+	findings int
+	err      error
+}
+
+func (f fakeScanner) Name() string        { return f.name }
+func (f fakeScanner) Description() string { return "fake" }
+func (f fakeScanner) Run(_ context.Context, target types.Target, opts scanner.Options) (*types.ScanResult, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	opts.ReportProgress(f.name, 1, 2)
+	result := &types.ScanResult{ScannerName: f.name, Target: target}
+	for i := 0; i < f.findings; i++ {
+		result.Findings = append(result.Findings, types.Finding{Title: "finding", Severity: types.SeverityLow})
+	}
+	return result, nil
+}
+
+func TestScanModelStreamsProgress(t *testing.T) {
+	target := types.Target{Host: "example.com", Scheme: "https", URL: "https://example.com"}
+	m := NewScanModel([]scanner.Scanner{
+		fakeScanner{name: "headers", findings: 2},
+		fakeScanner{name: "ssl", err: errors.New("handshake failed")},
+		fakeScanner{name: "dirs", findings: 1},
+	}, target, scanner.DefaultOptions())
+
+	view := m.View()
+	assert.Contains(t, view, "headers")
+	assert.Contains(t, view, "Findings: 0")
+
+	go m.runScan()
+	var complete ScanCompleteMsg
+	for {
+		msg := <-m.events
+		if c, ok := msg.(ScanCompleteMsg); ok {
+			complete = c
+		}
+		updated, _ := m.Update(msg)
+		m = updated.(ScanModel)
+		if m.done {
+			break
+		}
+	}
+
+	require.Len(t, complete.Results, 3)
+	assert.Equal(t, "handshake failed", complete.Results[1].Error)
+	assert.Equal(t, scannerDone, m.statuses[0].state)
+	assert.Equal(t, scannerFailed, m.statuses[1].state)
+	assert.Equal(t, 3, m.findings)
+
+	view = m.View()
+	assert.Contains(t, view, "Scan complete")
+	assert.Contains(t, view, "Findings: 3")
+	assert.Contains(t, view, "handshake failed")
+	assert.Contains(t, view, "2 findings")
+}
+
+// blockingScanner runs until its context is cancelled.
+type blockingScanner struct{ name string }
+
+func (b blockingScanner) Name() string        { return b.name }
+func (b blockingScanner) Description() string { return "blocks" }
+func (b blockingScanner) Run(ctx context.Context, _ types.Target, _ scanner.Options) (*types.ScanResult, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestScanModelCancel(t *testing.T) {
+	m := NewScanModel([]scanner.Scanner{
+		fakeScanner{name: "headers", findings: 1},
+		blockingScanner{name: "dirs"},
+		fakeScanner{name: "ssl"},
+	}, types.Target{Host: "example.com"}, scanner.DefaultOptions())
+
+	go m.runScan()
+	var complete ScanCompleteMsg
+	for !m.done {
+		msg := <-m.events
+		if started, ok := msg.(scannerStartedMsg); ok && started.Index == 1 {
+			updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+			m = updated.(ScanModel)
+			assert.Contains(t, m.View(), "Cancelling")
+		}
+		if c, ok := msg.(ScanCompleteMsg); ok {
+			complete = c
+		}
+		updated, _ := m.Update(msg)
+		m = updated.(ScanModel)
+	}
+
+	assert.True(t, complete.Cancelled)
+	require.Len(t, complete.Results, 2, "the skipped scanner has no result")
+	assert.Len(t, complete.Results[0].Findings, 1, "finished results are kept")
+	assert.Equal(t, "cancelled", complete.Results[1].Error)
+	assert.Equal(t, scannerSkipped, m.statuses[2].state)
+	assert.Contains(t, m.View(), "Scan cancelled")
+	assert.Contains(t, m.View(), "skipped")
+}
+
+func TestScanModelShowsRunningScanner(t *testing.T) {
+	m := NewScanModel([]scanner.Scanner{fakeScanner{name: "dirs"}}, types.Target{Host: "example.com"}, scanner.DefaultOptions())
+
+	for _, msg := range []tea.Msg{
+		scannerStartedMsg{Index: 0},
+		scannerProgressMsg{Index: 0, Done: 40, Total: 200},
+		scannerProbeMsg{Index: 0, Endpoint: "GET https://example.com/admin"},
+	} {
+		updated, _ := m.Update(msg)
+		m = updated.(ScanModel)
+	}
+
+	view := m.View()
+	assert.Contains(t, view, "40/200")
+	assert.Contains(t, view, "GET https://example.com/admin")
+}
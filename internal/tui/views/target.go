@@ -1,20 +1,65 @@
 package views
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"strings"
+	"time"
 
 	"github.com/buemura/hunter/internal/tui/styles"
 	"github.com/buemura/hunter/pkg/types"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-// TargetModel is the view model for target URL/host input.
+// lookupHost resolves a target's host before the scan starts.
+// Extracted as a variable for testing.
+var lookupHost = net.DefaultResolver.LookupHost
+
+// resolveTimeout bounds the DNS check on enter.
+const resolveTimeout = 3 * time.Second
+
+// maxSuggestions is how many dropdown entries are shown at once.
+const maxSuggestions = 5
+
+// TargetSuggestion is an entry in the target dropdown: a saved target from
+// the config file or a recently scanned one.
+type TargetSuggestion struct {
+	Label string // e.g. the saved target's name, or "recent"
+	Value string
+}
+
+// TargetReadyMsg is sent once the entered target has been validated and its
+// host resolved, or the user chose to go ahead without it resolving.
+type TargetReadyMsg struct {
+	Target types.Target
+}
+
+// targetResolvedMsg carries the result of the DNS check for value.
+type targetResolvedMsg struct {
+	value string
+	addrs []string
+	err   error
+}
+
+// TargetModel is the view model for target URL/host input. A dropdown below
+// the input offers saved and recent targets matching what has been typed,
+// and enter checks that the host resolves before the scan can start.
 type TargetModel struct {
-	textInput   textinput.Model
-	scannerName string
-	err         string
+	textInput    textinput.Model
+	scannerNames []string
+	err          string
+
+	suggestions []TargetSuggestion
+	highlight   int // index into matches(), or -1 for none
+
+	resolving  bool
+	resolved   string // addresses of the last successful check
+	unresolved string // value whose host did not resolve; enter again proceeds
+
+	authorize func(types.Target) error
 }
 
 // NewTargetModel creates a new target input view.
@@ -27,17 +72,64 @@ func NewTargetModel() TargetModel {
 	ti.PromptStyle = styles.CursorStyle
 	ti.TextStyle = styles.SelectedStyle
 
-	return TargetModel{textInput: ti}
+	return TargetModel{textInput: ti, highlight: -1}
+}
+
+// SetSize fits the input to a terminal of the given size.
+func (m *TargetModel) SetSize(width, height int) {
+	width, _ = fitSize(width, height, defaultWidth, defaultHeight)
+	m.textInput.Width = clamp(width-4, 10, 50)
 }
 
 // SetScannerName sets which scanner this target is for.
 func (m *TargetModel) SetScannerName(name string) {
-	m.scannerName = name
+	m.scannerNames = []string{name}
+}
+
+// SetScannerNames sets the scanners this target is for.
+func (m *TargetModel) SetScannerNames(names []string) {
+	m.scannerNames = names
+}
+
+// SetAuthorize sets a check that refuses the targets it returns an error
+// for; nil allows every target.
+func (m *TargetModel) SetAuthorize(authorize func(types.Target) error) {
+	m.authorize = authorize
+}
+
+// SetSuggestions sets the saved and recent targets offered in the dropdown,
+// in the order they are listed.
+func (m *TargetModel) SetSuggestions(suggestions []TargetSuggestion) {
+	m.suggestions = suggestions
+	m.highlight = -1
 }
 
-// ScannerName returns the selected scanner name.
+// Bindings returns the target view's keys for the help overlay. They are
+// fixed because every other key is typed into the input.
+func (m TargetModel) Bindings() []key.Binding {
+	return []key.Binding{
+		fixedBinding("↑/↓", "highlight a suggestion"),
+		fixedBinding("tab", "fill in the highlighted suggestion"),
+		fixedBinding("enter", "check the target and continue"),
+		fixedBinding("esc", "back"),
+	}
+}
+
+// SetValue replaces the text in the input.
+func (m *TargetModel) SetValue(value string) {
+	m.textInput.SetValue(value)
+	m.textInput.CursorEnd()
+	m.highlight = -1
+}
+
+// ScannerName returns the selected scanner names, comma-separated.
 func (m TargetModel) ScannerName() string {
-	return m.scannerName
+	return strings.Join(m.scannerNames, ", ")
+}
+
+// ScannerNames returns the selected scanner names.
+func (m TargetModel) ScannerNames() []string {
+	return m.scannerNames
 }
 
 // Init returns the text input blink command.
@@ -45,52 +137,212 @@ func (m TargetModel) Init() tea.Cmd {
 	return textinput.Blink
 }
 
-// Update handles input events.
+// Update handles input, dropdown navigation, and the DNS check. Tab fills in
+// the highlighted suggestion; enter fills it in and submits.
 func (m TargetModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" {
-		_, err := types.ParseTarget(m.textInput.Value())
-		if err != nil {
-			m.err = err.Error()
+	switch msg := msg.(type) {
+	case targetResolvedMsg:
+		if msg.value != m.value() {
+			// The input changed while the check was running.
 			return m, nil
 		}
-		m.err = ""
-		return m, nil
+		m.resolving = false
+		if msg.err != nil {
+			m.err = fmt.Sprintf("cannot resolve host: %v — press enter again to scan anyway", msg.err)
+			m.unresolved = msg.value
+			return m, nil
+		}
+		m.resolved = strings.Join(msg.addrs, ", ")
+		return m, m.ready()
+
+	case tea.KeyMsg:
+		matches := m.matches()
+		switch msg.String() {
+		case "down":
+			if m.highlight < len(matches)-1 {
+				m.highlight++
+			}
+			return m, nil
+		case "up":
+			if m.highlight >= 0 {
+				m.highlight--
+			}
+			return m, nil
+		case "tab":
+			if m.highlight >= 0 && m.highlight < len(matches) {
+				m.SetValue(matches[m.highlight].Value)
+				m.clearStatus()
+			}
+			return m, nil
+		case "enter":
+			if m.highlight >= 0 && m.highlight < len(matches) {
+				m.SetValue(matches[m.highlight].Value)
+				m.clearStatus()
+			}
+			return m.submit()
+		}
 	}
 
+	before := m.value()
 	var cmd tea.Cmd
 	m.textInput, cmd = m.textInput.Update(msg)
-	m.err = ""
+	if m.value() != before {
+		m.highlight = -1
+		m.clearStatus()
+	}
 	return m, cmd
 }
 
-// View renders the target input form.
+// submit validates the target and, for host names, starts the DNS check.
+func (m TargetModel) submit() (tea.Model, tea.Cmd) {
+	target, err := m.ValidatedTarget()
+	if err != nil {
+		m.err = err.Error()
+		return m, nil
+	}
+	if m.resolving {
+		return m, nil
+	}
+	if net.ParseIP(target.Host) != nil || m.unresolved == m.value() {
+		return m, m.ready()
+	}
+
+	m.err = ""
+	m.resolving = true
+	value, host := m.value(), target.Host
+	return m, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+		defer cancel()
+		addrs, err := lookupHost(ctx, host)
+		return targetResolvedMsg{value: value, addrs: addrs, err: err}
+	}
+}
+
+// ready returns a command announcing the validated target.
+func (m TargetModel) ready() tea.Cmd {
+	target, err := m.ValidatedTarget()
+	if err != nil {
+		return nil
+	}
+	return func() tea.Msg { return TargetReadyMsg{Target: target} }
+}
+
+func (m *TargetModel) clearStatus() {
+	m.err = ""
+	m.resolving = false
+	m.resolved = ""
+	m.unresolved = ""
+}
+
+func (m TargetModel) value() string {
+	return strings.TrimSpace(m.textInput.Value())
+}
+
+// matches returns the suggestions containing the typed text in their value
+// or label, case-insensitively, at most maxSuggestions of them.
+func (m TargetModel) matches() []TargetSuggestion {
+	query := strings.ToLower(m.value())
+	var out []TargetSuggestion
+	for _, s := range m.suggestions {
+		if s.Value == m.value() {
+			continue
+		}
+		if query == "" || strings.Contains(strings.ToLower(s.Value), query) || strings.Contains(strings.ToLower(s.Label), query) {
+			out = append(out, s)
+			if len(out) == maxSuggestions {
+				break
+			}
+		}
+	}
+	return out
+}
+
+// View renders the target input view.
 func (m TargetModel) View() string {
 	var b strings.Builder
 
 	b.WriteString(styles.TitleStyle.Render("Hunter — Interactive Mode"))
 	b.WriteString("\n\n")
-	b.WriteString(styles.HeaderStyle.Render(fmt.Sprintf("Scanner: %s", m.scannerName)))
+	label := "Scanner"
+	if len(m.scannerNames) > 1 {
+		label = "Scanners"
+	}
+	b.WriteString(styles.HeaderStyle.Render(fmt.Sprintf("%s: %s", label, m.ScannerName())))
 	b.WriteString("\n")
 	b.WriteString("Enter target URL or host:\n\n")
 	b.WriteString(m.textInput.View())
 	b.WriteString("\n")
 
-	if m.err != "" {
+	for i, s := range m.matches() {
+		cursor := "  "
+		value := styles.HelpStyle.Render(s.Value)
+		if i == m.highlight {
+			cursor = styles.CursorStyle.Render("> ")
+			value = styles.SelectedStyle.Render(s.Value)
+		}
+		b.WriteString(fmt.Sprintf("%s%s  %s\n", cursor, value, styles.HelpStyle.Render(s.Label)))
+	}
+
+	if feedback := m.feedback(); feedback != "" {
+		b.WriteString("\n")
+		b.WriteString(feedback)
 		b.WriteString("\n")
-		b.WriteString(styles.ErrorStyle.Render(m.err))
 	}
 
 	b.WriteString("\n")
-	b.WriteString(styles.HelpStyle.Render("enter submit • esc back"))
+	if len(m.suggestions) > 0 {
+		b.WriteString(styles.HelpStyle.Render("↑/↓ suggestions • tab complete • enter submit • esc back"))
+	} else {
+		b.WriteString(styles.HelpStyle.Render("enter submit • esc back"))
+	}
 
 	return b.String()
 }
 
+// feedback returns the validation line shown under the input: an error, the
+// DNS check status, or the URL a scheme-less target will be scanned as.
+func (m TargetModel) feedback() string {
+	switch {
+	case m.err != "":
+		return styles.ErrorStyle.Render(m.err)
+	case m.resolving:
+		return styles.HelpStyle.Render("Resolving host…")
+	case m.value() == "":
+		return ""
+	}
+
+	target, err := m.ValidatedTarget()
+	if err != nil {
+		return styles.ErrorStyle.Render("✗ " + err.Error())
+	}
+	var parts []string
+	if target.URL != m.value() {
+		parts = append(parts, "→ "+target.URL)
+	}
+	if m.resolved != "" {
+		parts = append(parts, "resolves to "+m.resolved)
+	}
+	return styles.HelpStyle.Render(strings.Join(parts, "  •  "))
+}
+
 // ValidatedTarget parses and returns the target, or an error if invalid.
+// Targets without a scheme get one inferred from the port.
 func (m TargetModel) ValidatedTarget() (types.Target, error) {
-	value := strings.TrimSpace(m.textInput.Value())
+	value := m.value()
 	if value == "" {
 		return types.Target{}, fmt.Errorf("target is required")
 	}
-	return types.ParseTarget(value)
+	if _, err := types.ParseTarget(value); err != nil {
+		return types.Target{}, err
+	}
+	target, err := types.ParseTarget(types.InferScheme(value))
+	if err != nil {
+		return types.Target{}, err
+	}
+	if m.authorize != nil {
+		if err := m.authorize(target); err != nil {
+			return types.Target{}, err
+		}
+	}
+	return target, nil
 }
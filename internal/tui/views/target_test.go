@@ -1,9 +1,14 @@
 package views
 
 import (
+	"context"
+	"errors"
 	"testing"
 
+	"github.com/buemura/hunter/pkg/types"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewTargetModel(t *testing.T) {
@@ -34,8 +39,125 @@ func TestTargetModelValidatedTargetEmpty(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestTargetModelAuthorize(t *testing.T) {
+	m := typeTarget(NewTargetModel(), "https://example.org")
+	m.SetAuthorize(func(target types.Target) error {
+		if target.Host != "example.com" {
+			return errors.New("target " + target.Host + " is not in authorized_targets")
+		}
+		return nil
+	})
+
+	_, err := m.ValidatedTarget()
+	assert.ErrorContains(t, err, "not in authorized_targets")
+	assert.Contains(t, m.View(), "not in authorized_targets")
+
+	m.SetValue("https://example.com")
+	_, err = m.ValidatedTarget()
+	assert.NoError(t, err)
+}
+
 func TestTargetModelInit(t *testing.T) {
 	m := NewTargetModel()
 	cmd := m.Init()
 	assert.NotNil(t, cmd)
 }
+
+func typeTarget(m TargetModel, s string) TargetModel {
+	for _, r := range s {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(TargetModel)
+	}
+	return m
+}
+
+func stubLookupHost(t *testing.T, fn func(ctx context.Context, host string) ([]string, error)) {
+	orig := lookupHost
+	lookupHost = fn
+	t.Cleanup(func() { lookupHost = orig })
+}
+
+func TestTargetModelShowsInferredScheme(t *testing.T) {
+	m := typeTarget(NewTargetModel(), "localhost:8080")
+	assert.Contains(t, m.View(), "→ http://localhost:8080")
+
+	target, err := m.ValidatedTarget()
+	require.NoError(t, err)
+	assert.Equal(t, "http", target.Scheme)
+
+	m = typeTarget(NewTargetModel(), "example.com:99999")
+	assert.Contains(t, m.View(), "out of range")
+}
+
+func TestTargetModelSuggestions(t *testing.T) {
+	m := NewTargetModel()
+	m.SetSuggestions([]TargetSuggestion{
+		{Label: "staging", Value: "https://staging.example.com"},
+		{Label: "recent", Value: "https://prod.example.com"},
+	})
+	view := m.View()
+	assert.Contains(t, view, "https://staging.example.com")
+	assert.Contains(t, view, "https://prod.example.com")
+
+	// Typing narrows the dropdown by value or label.
+	m = typeTarget(m, "stag")
+	view = m.View()
+	assert.Contains(t, view, "https://staging.example.com")
+	assert.NotContains(t, view, "https://prod.example.com")
+
+	// Down highlights, tab completes.
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(TargetModel)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = updated.(TargetModel)
+	assert.Equal(t, "https://staging.example.com", m.value())
+}
+
+func TestTargetModelEnterResolvesHost(t *testing.T) {
+	stubLookupHost(t, func(ctx context.Context, host string) ([]string, error) {
+		assert.Equal(t, "example.com", host)
+		return []string{"93.184.216.34"}, nil
+	})
+
+	m := typeTarget(NewTargetModel(), "example.com")
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(TargetModel)
+	require.NotNil(t, cmd)
+	assert.Contains(t, m.View(), "Resolving host")
+
+	updated, cmd = m.Update(cmd())
+	m = updated.(TargetModel)
+	require.NotNil(t, cmd)
+	ready, ok := cmd().(TargetReadyMsg)
+	require.True(t, ok)
+	assert.Equal(t, "https://example.com", ready.Target.URL)
+	assert.Contains(t, m.View(), "resolves to 93.184.216.34")
+}
+
+func TestTargetModelUnresolvedHostNeedsConfirmation(t *testing.T) {
+	stubLookupHost(t, func(ctx context.Context, host string) ([]string, error) {
+		return nil, errors.New("no such host")
+	})
+
+	m := typeTarget(NewTargetModel(), "internal.invalid")
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(TargetModel)
+	updated, cmd = m.Update(cmd())
+	m = updated.(TargetModel)
+	assert.Nil(t, cmd)
+	assert.Contains(t, m.View(), "cannot resolve host")
+
+	// A second enter goes ahead anyway.
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	_, ok := cmd().(TargetReadyMsg)
+	assert.True(t, ok)
+}
+
+func TestTargetModelIgnoresStaleResolution(t *testing.T) {
+	m := typeTarget(NewTargetModel(), "example.org")
+	updated, cmd := m.Update(targetResolvedMsg{value: "example.com", err: errors.New("no such host")})
+	m = updated.(TargetModel)
+	assert.Nil(t, cmd)
+	assert.NotContains(t, m.View(), "cannot resolve")
+}
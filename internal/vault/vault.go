@@ -0,0 +1,146 @@
+// Package vault encrypts scan data with AES-256-GCM, since scan results
+// detail a target's weaknesses. Data is sealed either with a key, for the
+// scan history at rest, or with a password, for reports shared with others.
+//
+// Sealed data starts with a header naming how it was sealed. Data sealed
+// with a key is the header, a nonce, and the ciphertext; data sealed with a
+// password also holds, after the header, the salt its key was derived with.
+package vault
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+const (
+	// KeyHeader starts data sealed with a key.
+	KeyHeader = "HUNTER-K1"
+	// PasswordHeader starts data sealed with a password.
+	PasswordHeader = "HUNTER-P1"
+
+	// SaltSize and NonceSize are the sizes, in bytes, of the salt and nonce
+	// in sealed data.
+	SaltSize  = 16
+	NonceSize = 12
+
+	// Iterations is the PBKDF2-SHA256 work factor keys are derived with.
+	Iterations = 600_000
+)
+
+// keySalt salts keys derived with DeriveKey. Each history entry would
+// otherwise need a derivation of its own to be read.
+var keySalt = []byte("hunter scan history")
+
+// ErrDecrypt reports data that does not open with the key or password
+// given, or was altered after it was sealed.
+var ErrDecrypt = errors.New("wrong key or password, or corrupted data")
+
+// Key is an AES-256 key.
+type Key []byte
+
+// DeriveKey derives a key from a secret, such as a passphrase.
+func DeriveKey(secret string) (Key, error) {
+	if secret == "" {
+		return nil, errors.New("encryption key is empty")
+	}
+	return pbkdf2.Key(sha256.New, secret, keySalt, Iterations, 32)
+}
+
+// Sealed reports whether data was sealed with a key.
+func Sealed(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(KeyHeader))
+}
+
+// PasswordSealed reports whether data was sealed with a password.
+func PasswordSealed(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(PasswordHeader))
+}
+
+// Seal encrypts plaintext with key.
+func Seal(key Key, plaintext []byte) ([]byte, error) {
+	return seal(key, []byte(KeyHeader), plaintext)
+}
+
+// Open decrypts data sealed with key.
+func Open(key Key, data []byte) ([]byte, error) {
+	if !Sealed(data) {
+		return nil, errors.New("data is not sealed with a key")
+	}
+	return open(key, data[len(KeyHeader):])
+}
+
+// SealPassword encrypts plaintext with a key derived from password and a
+// random salt.
+func SealPassword(password string, plaintext []byte) ([]byte, error) {
+	if password == "" {
+		return nil, errors.New("password is empty")
+	}
+	header := make([]byte, len(PasswordHeader)+SaltSize)
+	copy(header, PasswordHeader)
+	salt := header[len(PasswordHeader):]
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	key, err := pbkdf2.Key(sha256.New, password, salt, Iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	return seal(key, header, plaintext)
+}
+
+// OpenPassword decrypts data sealed with password.
+func OpenPassword(password string, data []byte) ([]byte, error) {
+	if !PasswordSealed(data) || len(data) < len(PasswordHeader)+SaltSize {
+		return nil, errors.New("data is not sealed with a password")
+	}
+	salt := data[len(PasswordHeader) : len(PasswordHeader)+SaltSize]
+	key, err := pbkdf2.Key(sha256.New, password, salt, Iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	return open(key, data[len(PasswordHeader)+SaltSize:])
+}
+
+// seal appends a random nonce and the ciphertext of plaintext to header.
+func seal(key Key, header, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, NonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append(header, nonce...)
+	return gcm.Seal(out, nonce, plaintext, nil), nil
+}
+
+// open decrypts a nonce followed by ciphertext.
+func open(key Key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < NonceSize+gcm.Overhead() {
+		return nil, ErrDecrypt
+	}
+	plaintext, err := gcm.Open(nil, data[:NonceSize], data[NonceSize:], nil)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+func newGCM(key Key) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
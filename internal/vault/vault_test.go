@@ -0,0 +1,72 @@
+package vault
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSealOpen(t *testing.T) {
+	key, err := DeriveKey("correct horse battery staple")
+	require.NoError(t, err)
+	plaintext := []byte(`{"findings":[]}`)
+
+	sealed, err := Seal(key, plaintext)
+	require.NoError(t, err)
+	assert.True(t, Sealed(sealed))
+	assert.False(t, PasswordSealed(sealed))
+	assert.False(t, bytes.Contains(sealed, plaintext))
+
+	opened, err := Open(key, sealed)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, opened)
+
+	other, err := DeriveKey("another key")
+	require.NoError(t, err)
+	_, err = Open(other, sealed)
+	assert.ErrorIs(t, err, ErrDecrypt)
+
+	sealed[len(sealed)-1] ^= 1
+	_, err = Open(key, sealed)
+	assert.ErrorIs(t, err, ErrDecrypt, "tampered data must not open")
+
+	_, err = Open(key, plaintext)
+	assert.Error(t, err)
+}
+
+func TestDeriveKey(t *testing.T) {
+	a, err := DeriveKey("secret")
+	require.NoError(t, err)
+	b, err := DeriveKey("secret")
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+	assert.Len(t, a, 32)
+
+	_, err = DeriveKey("")
+	assert.Error(t, err)
+}
+
+func TestSealOpenPassword(t *testing.T) {
+	plaintext := []byte("# Hunter Scan Report")
+
+	sealed, err := SealPassword("hunter2", plaintext)
+	require.NoError(t, err)
+	assert.True(t, PasswordSealed(sealed))
+	assert.False(t, Sealed(sealed))
+
+	again, err := SealPassword("hunter2", plaintext)
+	require.NoError(t, err)
+	assert.NotEqual(t, sealed, again, "each seal has its own salt and nonce")
+
+	opened, err := OpenPassword("hunter2", sealed)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, opened)
+
+	_, err = OpenPassword("hunter3", sealed)
+	assert.ErrorIs(t, err, ErrDecrypt)
+
+	_, err = SealPassword("", plaintext)
+	assert.Error(t, err)
+}
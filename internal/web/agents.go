@@ -0,0 +1,44 @@
+package web
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/buemura/hunter/internal/config"
+)
+
+// agentAuth admits requests bearing the agent token of the current config,
+// read per request so reloads apply without a restart. Without a token the
+// server accepts no agents.
+func (s *Server) agentAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		setting := s.Config().AgentToken
+		if setting == "" {
+			writeAgentError(w, http.StatusForbidden, "agents are disabled; set agent_token to accept them")
+			return
+		}
+		token, err := config.ResolveSecret(setting)
+		if err != nil || token == "" {
+			writeAgentError(w, http.StatusInternalServerError, "agent_token cannot be resolved")
+			return
+		}
+
+		given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			writeAgentError(w, http.StatusUnauthorized, "invalid agent token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeAgentError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": msg,
+		"code":  status,
+	})
+}
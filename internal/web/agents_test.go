@@ -0,0 +1,37 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/buemura/hunter/internal/config"
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAgentAuth(t *testing.T) {
+	cfg := config.Defaults()
+	srv := NewServer(":0", scanner.NewRegistry(), Options{Config: &cfg})
+
+	poll := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/agents/dmz-1/jobs/next", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, poll("anything"), "agents are disabled without a token")
+
+	t.Setenv("HUNTER_TEST_AGENT_TOKEN", "s3cret")
+	updated := cfg
+	updated.AgentToken = "env:HUNTER_TEST_AGENT_TOKEN"
+	srv.SetConfig(&updated)
+
+	assert.Equal(t, http.StatusUnauthorized, poll(""))
+	assert.Equal(t, http.StatusUnauthorized, poll("wrong"))
+	assert.Equal(t, http.StatusNoContent, poll("s3cret"))
+}
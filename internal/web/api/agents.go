@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/buemura/hunter/internal/web/jobs"
+	"github.com/go-chi/chi/v5"
+)
+
+// AgentJob is a scan handed to an agent: the ID to report on and the
+// request to run, a CreateScanRequest.
+type AgentJob struct {
+	ID      string          `json:"id"`
+	Request json.RawMessage `json:"request"`
+}
+
+// ListAgents handles GET /api/v1/agents.
+func (h *Handlers) ListAgents(w http.ResponseWriter, r *http.Request) {
+	type agentSummary struct {
+		jobs.Agent
+		Online bool `json:"online"`
+	}
+
+	now := time.Now()
+	agents := h.Manager.Agents()
+	summaries := make([]agentSummary, len(agents))
+	for i, a := range agents {
+		summaries[i] = agentSummary{Agent: a, Online: a.Online(now)}
+	}
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+// NextAgentJob handles GET /api/v1/agents/{name}/jobs/next. It registers
+// the polling agent and hands it the next scan dispatched to it, or
+// responds 204 when there is none.
+func (h *Handlers) NextAgentJob(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if !jobs.ValidAgentName(name) {
+		writeError(w, http.StatusBadRequest, "invalid agent name")
+		return
+	}
+
+	job := h.Manager.Claim(name)
+	if job == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, AgentJob{ID: job.ID, Request: job.Spec})
+}
+
+// ReportAgentJob handles POST /api/v1/agents/{name}/jobs/{id}, an agent's
+// update on the scan it runs. It responds 404 when the scan was deleted and
+// 409 when it already ended, telling the agent to stop it.
+func (h *Handlers) ReportAgentJob(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	id := chi.URLParam(r, "id")
+	job, err := h.Manager.Get(id)
+	if err != nil || job.Agent != name {
+		writeError(w, http.StatusNotFound, "job not found")
+		return
+	}
+
+	var report jobs.Report
+	if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+	if err := h.Manager.Report(name, id, report); err != nil {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
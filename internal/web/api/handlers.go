@@ -2,9 +2,16 @@ package api
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/buemura/hunter/internal/config"
 	"github.com/buemura/hunter/internal/output"
 	"github.com/buemura/hunter/internal/scanner"
 	"github.com/buemura/hunter/internal/web/jobs"
@@ -16,6 +23,9 @@ import (
 type Handlers struct {
 	Manager  *jobs.Manager
 	Registry *scanner.Registry
+	// Config returns the current configuration; it is called per request so
+	// a reloaded config applies to the next scan. Nil means defaults.
+	Config func() *config.Config
 }
 
 // NewHandlers creates API handlers with the given dependencies.
@@ -31,13 +41,59 @@ func (h *Handlers) CreateScan(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	target, err := types.ParseTarget(req.Target)
+	job, err := h.StartScan(req)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "invalid target: "+err.Error())
+		status := http.StatusInternalServerError
+		var scanErr *ScanError
+		if errors.As(err, &scanErr) {
+			status = scanErr.Status
+		}
+		writeError(w, status, err.Error())
 		return
 	}
 
+	resp := map[string]interface{}{
+		"id":     job.ID,
+		"status": job.Status,
+	}
+	if job.Agent != "" {
+		resp["agent"] = job.Agent
+	}
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// ScanError is an error starting a scan, with the HTTP status it is
+// reported with.
+type ScanError struct {
+	Status int
+	Msg    string
+}
+
+func (e *ScanError) Error() string { return e.Msg }
+
+// StartScan starts the scan a validated request describes, or dispatches it
+// to the request's agent. Errors are *ScanError.
+func (h *Handlers) StartScan(req *CreateScanRequest) (*jobs.Job, error) {
+	target, err := types.ParseTarget(req.Target)
+	if err != nil {
+		return nil, &ScanError{http.StatusBadRequest, "invalid target: " + err.Error()}
+	}
+
+	cfg := h.config()
+	if !req.IAmAuthorized {
+		if err := cfg.AuthorizeTarget(target); err != nil {
+			return nil, &ScanError{http.StatusForbidden, err.Error() + `; set "i_am_authorized" if you have permission to test it`}
+		}
+	}
+
 	scannerNames := req.Scanners
+	if req.Profile != "" {
+		profile := cfg.GetProfile(req.Profile)
+		if profile == nil {
+			return nil, &ScanError{http.StatusBadRequest, "unknown scan profile " + req.Profile}
+		}
+		scannerNames = profile.Scanners
+	}
 	if len(scannerNames) == 0 || (len(scannerNames) == 1 && scannerNames[0] == "all") {
 		all := h.Registry.All()
 		scannerNames = make([]string, len(all))
@@ -46,30 +102,78 @@ func (h *Handlers) CreateScan(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	opts := scanner.Options{
-		Concurrency: req.Concurrency,
-		Timeout:     5 * time.Second,
+	if req.Agent != "" {
+		return h.dispatchScan(req, target, scannerNames, cfg)
 	}
-	if req.Timeout != "" {
-		d, _ := time.ParseDuration(req.Timeout) // already validated
-		opts.Timeout = d
+
+	opts, err := ScanOptions(req, cfg)
+	if err != nil {
+		return nil, &ScanError{http.StatusInternalServerError, "invalid config: " + err.Error()}
 	}
 
 	job := h.Manager.Create(target, scannerNames, opts)
+	if req.IAmAuthorized {
+		h.Manager.Acknowledge(job.ID)
+	}
 	if err := h.Manager.Start(job.ID); err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to start scan: "+err.Error())
-		return
+		return nil, &ScanError{http.StatusInternalServerError, "failed to start scan: " + err.Error()}
 	}
+	return job, nil
+}
 
-	writeJSON(w, http.StatusCreated, map[string]interface{}{
-		"id":     job.ID,
-		"status": job.Status,
-	})
+// dispatchScan hands a scan to the agent named in req. The agent runs it
+// with the scanners, intensity, window, and engagement resolved here, and
+// its own config's per-scanner settings.
+func (h *Handlers) dispatchScan(req *CreateScanRequest, target types.Target, scannerNames []string, cfg *config.Config) (*jobs.Job, error) {
+	spec := *req
+	spec.Scanners = scannerNames
+	spec.Profile = ""
+	spec.Engagement = cfg.Engagement.Merge(req.Engagement)
+	if spec.Intensity == "" {
+		spec.Intensity = cfg.Intensity
+	}
+	if spec.Window == "" {
+		spec.Window = cfg.ScanWindow
+	}
+	spec.Agent = ""
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, &ScanError{http.StatusInternalServerError, "failed to dispatch scan: " + err.Error()}
+	}
+
+	job, err := h.Manager.Dispatch(req.Agent, target, scannerNames, spec.Engagement, data)
+	if err != nil {
+		return nil, &ScanError{http.StatusConflict, err.Error()}
+	}
+	if req.IAmAuthorized {
+		h.Manager.Acknowledge(job.ID)
+	}
+	return job, nil
 }
 
-// ListScans handles GET /api/v1/scans.
+func (h *Handlers) config() *config.Config {
+	if h.Config != nil {
+		if cfg := h.Config(); cfg != nil {
+			return cfg
+		}
+	}
+	cfg := config.Defaults()
+	return &cfg
+}
+
+// ListScans handles GET /api/v1/scans. The status and target query
+// parameters keep the scans with that status, and those whose target
+// contains the text, ignoring case.
 func (h *Handlers) ListScans(w http.ResponseWriter, r *http.Request) {
-	jobList := h.Manager.List()
+	filter := jobs.Filter{
+		Status: jobs.JobStatus(r.URL.Query().Get("status")),
+		Target: r.URL.Query().Get("target"),
+	}
+	if err := filter.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	jobList := filter.Apply(h.Manager.List())
 
 	type scanSummary struct {
 		ID           string         `json:"id"`
@@ -78,21 +182,19 @@ func (h *Handlers) ListScans(w http.ResponseWriter, r *http.Request) {
 		CreatedAt    time.Time      `json:"created_at"`
 		Scanners     []string       `json:"scanners"`
 		FindingCount int            `json:"finding_count"`
+		Agent        string         `json:"agent,omitempty"`
 	}
 
 	summaries := make([]scanSummary, len(jobList))
 	for i, j := range jobList {
-		target := j.Target.Host
-		if j.Target.URL != "" {
-			target = j.Target.URL
-		}
 		summaries[i] = scanSummary{
 			ID:           j.ID,
-			Target:       target,
+			Target:       j.TargetName(),
 			Status:       j.Status,
 			CreatedAt:    j.CreatedAt,
 			Scanners:     j.Scanners,
 			FindingCount: j.FindingCount(),
+			Agent:        j.Agent,
 		}
 	}
 
@@ -108,9 +210,133 @@ func (h *Handlers) GetScan(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Artifacts can be large, so they are only included on request.
+	if r.URL.Query().Get("artifacts") != "true" {
+		stripped := *job
+		stripped.Results = types.WithoutArtifacts(job.Results)
+		job = &stripped
+	}
 	writeJSON(w, http.StatusOK, job)
 }
 
+// GetScanLogs handles GET /api/v1/scans/{id}/logs.
+func (h *Handlers) GetScanLogs(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	logs, dropped, err := h.Manager.Logs(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":      id,
+		"logs":    logs,
+		"dropped": dropped,
+	})
+}
+
+// GetFindingArtifacts handles
+// GET /api/v1/scans/{id}/artifacts/{result}/{finding}. It serves the raw
+// requests and responses behind a finding as a plain-text download.
+func (h *Handlers) GetFindingArtifacts(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	result, err := strconv.Atoi(chi.URLParam(r, "result"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid result index")
+		return
+	}
+	finding, err := strconv.Atoi(chi.URLParam(r, "finding"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid finding index")
+		return
+	}
+
+	artifacts, err := h.Manager.Artifacts(id, result, finding)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if len(artifacts) == 0 {
+		writeError(w, http.StatusNotFound, "finding has no artifacts")
+		return
+	}
+
+	var buf bytes.Buffer
+	for i, a := range artifacts {
+		fmt.Fprintf(&buf, "--- request %d ---\n%s\n--- response %d ---\n%s\n", i+1, a.Request, i+1, a.Response)
+		if a.Truncated {
+			fmt.Fprintf(&buf, "--- truncated to %d bytes per side ---\n", scanner.MaxArtifactSize)
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="scan-%s-artifacts-%d-%d.txt"`, id, result, finding))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// VerifyFinding handles POST /api/v1/scans/{id}/findings/{fingerprint}/verify.
+// It replays the probe behind the finding and reports whether it still
+// reproduces.
+func (h *Handlers) VerifyFinding(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	fingerprint := chi.URLParam(r, "fingerprint")
+	job, err := h.Manager.Get(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if _, _, ok := job.Finding(fingerprint); !ok {
+		writeError(w, http.StatusNotFound, "finding not found")
+		return
+	}
+
+	reproduces, err := h.Manager.Verify(r.Context(), id, fingerprint)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"fingerprint": fingerprint,
+		"reproduces":  reproduces,
+		"verified_at": time.Now(),
+	})
+}
+
+// ListFindings handles GET /api/v1/findings. It lists the findings of the
+// completed scans held, per target, with how long each has been open and
+// whether it is past its severity's SLA. The target query parameter keeps
+// one target's findings, and status=open, closed, or overdue filters by
+// state.
+func (h *Handlers) ListFindings(w http.ResponseWriter, r *http.Request) {
+	sla, err := jobs.ParseSLA(h.config().SLA)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	target := r.URL.Query().Get("target")
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", "open", "closed", "overdue":
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid status %q (available: open, closed, overdue)", status))
+		return
+	}
+
+	findings := []jobs.TrackedFinding{}
+	for _, f := range h.Manager.Findings(sla, time.Now()) {
+		if target != "" && f.Target != target {
+			continue
+		}
+		if (status == "open" && !f.Open) || (status == "closed" && f.Open) || (status == "overdue" && !f.Overdue) {
+			continue
+		}
+		findings = append(findings, f)
+	}
+	writeJSON(w, http.StatusOK, findings)
+}
+
 // GetScanReport handles GET /api/v1/scans/{id}/report.
 func (h *Handlers) GetScanReport(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -132,9 +358,112 @@ func (h *Handlers) GetScanReport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A completed scan's report does not change, so clients can revalidate
+	// it cheaply. It may hold sensitive findings, so shared caches must not
+	// store it.
+	sum := sha256.Sum256(buf.Bytes())
 	w.Header().Set("Content-Type", "text/html")
-	w.WriteHeader(http.StatusOK)
-	w.Write(buf.Bytes())
+	w.Header().Set("ETag", `W/"`+hex.EncodeToString(sum[:8])+`"`)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	http.ServeContent(w, r, "", job.CompletedAt, bytes.NewReader(buf.Bytes()))
+}
+
+// PauseScan handles POST /api/v1/scans/{id}/pause.
+func (h *Handlers) PauseScan(w http.ResponseWriter, r *http.Request) {
+	h.control(w, r, h.Manager.Pause)
+}
+
+// ResumeScan handles POST /api/v1/scans/{id}/resume.
+func (h *Handlers) ResumeScan(w http.ResponseWriter, r *http.Request) {
+	h.control(w, r, h.Manager.Resume)
+}
+
+// CancelScan handles POST /api/v1/scans/{id}/cancel. Scanners still to run
+// are skipped, and the scan fails as canceled once the current one returns.
+func (h *Handlers) CancelScan(w http.ResponseWriter, r *http.Request) {
+	h.control(w, r, h.Manager.Cancel)
+}
+
+// control applies pause, resume, or cancel to the job in the URL and
+// responds with its new status; 409 if the job is not in a state the
+// operation applies to.
+func (h *Handlers) control(w http.ResponseWriter, r *http.Request, apply func(jobID string) error) {
+	id := chi.URLParam(r, "id")
+	if _, err := h.Manager.Get(id); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err := apply(id); err != nil {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	job, err := h.Manager.Snapshot(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":     id,
+		"status": job.Status,
+	})
+}
+
+// RerunScan handles POST /api/v1/scans/{id}/rerun. It starts a new scan
+// with the target, scanners, and options of a finished one; 409 if the
+// scan is still running or its agent is not connected, and 403 if its
+// target is no longer in authorized_targets.
+func (h *Handlers) RerunScan(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if _, err := h.Manager.Get(id); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	job, err := h.Manager.Rerun(id)
+	if errors.Is(err, config.ErrUnauthorizedTarget) {
+		writeError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, h.rerunSummary(job))
+}
+
+// RerunScans handles POST /api/v1/scans/rerun. It reruns every failed
+// scan, and every completed one with failed or partial scanner results,
+// that was not rerun before. Scans that could not be rerun are listed
+// under errors.
+func (h *Handlers) RerunScans(w http.ResponseWriter, r *http.Request) {
+	reruns, errs := h.Manager.RerunIncomplete()
+	scans := make([]map[string]interface{}, len(reruns))
+	for i, job := range reruns {
+		scans[i] = h.rerunSummary(job)
+	}
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"scans":  scans,
+		"errors": messages,
+	})
+}
+
+// rerunSummary describes a job started by a rerun.
+func (h *Handlers) rerunSummary(job *jobs.Job) map[string]interface{} {
+	summary := map[string]interface{}{
+		"id":       job.ID,
+		"rerun_of": job.RerunOf,
+	}
+	if snapshot, err := h.Manager.Snapshot(job.ID); err == nil {
+		summary["status"] = snapshot.Status
+	}
+	if job.Agent != "" {
+		summary["agent"] = job.Agent
+	}
+	return summary
 }
 
 // DeleteScan handles DELETE /api/v1/scans/{id}.
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/buemura/hunter/internal/config"
 	"github.com/buemura/hunter/internal/scanner"
 	"github.com/buemura/hunter/internal/web/jobs"
 	"github.com/buemura/hunter/pkg/types"
@@ -28,11 +29,18 @@ func (m *mockScanner) Run(_ context.Context, target types.Target, _ scanner.Opti
 		ScannerName: m.name,
 		Target:      target,
 		Findings: []types.Finding{
-			{Title: m.name + " finding", Severity: types.SeverityInfo},
+			{Title: m.name + " finding", Severity: types.SeverityInfo, Artifacts: []types.Artifact{
+				{Request: "GET / HTTP/1.1\r\nHost: " + target.Host + "\r\n\r\n", Response: "HTTP/1.1 200 OK\r\n\r\n"},
+			}},
 		},
 	}, nil
 }
 
+// Verify reports the scanner's own finding as reproducing.
+func (m *mockScanner) Verify(_ context.Context, _ types.Target, finding types.Finding, _ scanner.Options) (bool, error) {
+	return finding.Title == m.name+" finding", nil
+}
+
 func setupTestHandlers() (*Handlers, *chi.Mux) {
 	reg := scanner.NewRegistry()
 	reg.Register(&mockScanner{name: "headers"})
@@ -46,7 +54,19 @@ func setupTestHandlers() (*Handlers, *chi.Mux) {
 	r.Get("/api/v1/scans", h.ListScans)
 	r.Get("/api/v1/scans/{id}", h.GetScan)
 	r.Get("/api/v1/scans/{id}/report", h.GetScanReport)
+	r.Get("/api/v1/scans/{id}/logs", h.GetScanLogs)
+	r.Get("/api/v1/scans/{id}/artifacts/{result}/{finding}", h.GetFindingArtifacts)
 	r.Delete("/api/v1/scans/{id}", h.DeleteScan)
+	r.Post("/api/v1/scans/{id}/findings/{fingerprint}/verify", h.VerifyFinding)
+	r.Post("/api/v1/scans/{id}/pause", h.PauseScan)
+	r.Post("/api/v1/scans/{id}/resume", h.ResumeScan)
+	r.Post("/api/v1/scans/{id}/cancel", h.CancelScan)
+	r.Post("/api/v1/scans/{id}/rerun", h.RerunScan)
+	r.Post("/api/v1/scans/rerun", h.RerunScans)
+	r.Get("/api/v1/findings", h.ListFindings)
+	r.Get("/api/v1/agents", h.ListAgents)
+	r.Get("/api/v1/agents/{name}/jobs/next", h.NextAgentJob)
+	r.Post("/api/v1/agents/{name}/jobs/{id}", h.ReportAgentJob)
 	return h, r
 }
 
@@ -68,6 +88,174 @@ func TestCreateScan_ValidBody(t *testing.T) {
 	assert.Equal(t, "running", resp["status"])
 }
 
+func TestCreateScan_Redact(t *testing.T) {
+	h, router := setupTestHandlers()
+
+	body := `{"target": "http://10.1.2.3", "scanners": ["headers"], "no_preflight": true, "redact": true}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/scans", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	id := resp["id"].(string)
+
+	require.Eventually(t, func() bool {
+		j, _ := h.Manager.Get(id)
+		return j.Status == jobs.StatusCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+
+	artifacts, err := h.Manager.Artifacts(id, 0, 0)
+	require.NoError(t, err)
+	assert.Contains(t, artifacts[0].Request, "Host: [REDACTED]\r\n", "stored artifacts are redacted")
+}
+
+func TestCreateScan_Engagement(t *testing.T) {
+	h, router := setupTestHandlers()
+
+	body := `{"target": "https://example.com", "scanners": ["headers"], "no_preflight": true,
+		"engagement": {"client": "ACME Corp", "id": "ENG-42"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/scans", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	id := resp["id"].(string)
+
+	require.Eventually(t, func() bool {
+		j, _ := h.Manager.Get(id)
+		return j.Status == jobs.StatusCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+
+	job, err := h.Manager.Get(id)
+	require.NoError(t, err)
+	assert.Equal(t, types.Engagement{Client: "ACME Corp", ID: "ENG-42"}, job.Engagement)
+	assert.Equal(t, "ACME Corp", job.Results[0].Metadata["engagement_client"])
+}
+
+func TestCreateScan_InvalidIntensity(t *testing.T) {
+	_, router := setupTestHandlers()
+
+	body := `{"target": "https://example.com", "scanners": ["headers"], "intensity": "reckless"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/scans", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid intensity")
+}
+
+func TestCreateScan_InvalidMaxDuration(t *testing.T) {
+	_, router := setupTestHandlers()
+
+	body := `{"target": "https://example.com", "scanners": ["headers"], "max_duration": "-1m"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/scans", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid max_duration")
+}
+
+func TestCreateScan_FailurePolicy(t *testing.T) {
+	h, router := setupTestHandlers()
+
+	body := `{"target": "https://example.com", "scanners": ["port", "headers"], "on_failure": "abort-remaining", "depends_on": {"headers": ["port"]}}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/scans", bytes.NewBufferString(body)))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	job, err := h.Manager.Get(resp["id"].(string))
+	require.NoError(t, err)
+	assert.Equal(t, scanner.FailureAbort, job.Options.OnFailure)
+	assert.Equal(t, map[string][]string{"headers": {"port"}}, job.Options.DependsOn)
+
+	for body, want := range map[string]string{
+		`{"target": "https://example.com", "on_failure": "stop"}`:                                     "invalid failure policy",
+		`{"target": "https://example.com", "depends_on": {"port": ["headers"], "headers": ["port"]}}`: "dependency cycle",
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/scans", bytes.NewBufferString(body)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), want)
+	}
+}
+
+func TestCreateScan_Watchdog(t *testing.T) {
+	h, router := setupTestHandlers()
+
+	body := `{"target": "https://example.com", "scanners": ["port"], "watchdog": "10s", "watchdog_action": "pause"}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/scans", bytes.NewBufferString(body)))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	job, err := h.Manager.Get(resp["id"].(string))
+	require.NoError(t, err)
+	assert.Equal(t, scanner.WatchdogSettings{Interval: 10 * time.Second, Action: scanner.WatchdogPause}, job.Options.Watchdog)
+
+	for body, want := range map[string]string{
+		`{"target": "https://example.com", "watchdog": "-1s"}`:                          "invalid watchdog",
+		`{"target": "https://example.com", "watchdog": "10s", "watchdog_threshold": 2}`: "watchdog_threshold",
+		`{"target": "https://example.com", "watchdog_action": "stop"}`:                  "invalid watchdog action",
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/scans", bytes.NewBufferString(body)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), want)
+	}
+}
+
+func TestCreateScan_Window(t *testing.T) {
+	h, router := setupTestHandlers()
+	cfg := config.Defaults()
+	cfg.ScanWindow = "22:00-06:00 UTC"
+	h.Config = func() *config.Config { return &cfg }
+	create := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/scans", bytes.NewBufferString(body)))
+		return w
+	}
+	window := func(w *httptest.ResponseRecorder) string {
+		var resp map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		job, err := h.Manager.Get(resp["id"].(string))
+		require.NoError(t, err)
+		return job.Options.Window.String()
+	}
+
+	w := create(`{"target": "https://example.com", "scanners": ["port"]}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, "22:00-06:00 UTC", window(w), "the config file's scan_window is the default")
+
+	w = create(`{"target": "https://example.com", "scanners": ["port"], "window": "01:00-02:00 UTC"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, "01:00-02:00 UTC", window(w))
+
+	w = create(`{"target": "https://example.com", "window": "late"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid window")
+}
+
+func TestCreateScan_InvalidCrawlBounds(t *testing.T) {
+	_, router := setupTestHandlers()
+
+	body := `{"target": "https://example.com", "scanners": ["headers"], "crawl": true, "crawl_pages": -1}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/scans", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "crawl_depth and crawl_pages must be non-negative")
+}
+
 func TestCreateScan_EmptyTarget(t *testing.T) {
 	_, router := setupTestHandlers()
 
@@ -106,6 +294,119 @@ func TestCreateScan_AllScanners(t *testing.T) {
 	assert.Equal(t, http.StatusCreated, w.Code)
 }
 
+func TestCreateScan_AuthorizedTargets(t *testing.T) {
+	h, router := setupTestHandlers()
+	cfg := config.Defaults()
+	cfg.AuthorizedTargets = []string{"example.com"}
+	h.Config = func() *config.Config { return &cfg }
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/scans", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post(`{"target": "https://example.com", "scanners": ["headers"]}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = post(`{"target": "https://example.org", "scanners": ["headers"]}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "not in authorized_targets")
+
+	w = post(`{"target": "https://example.org", "scanners": ["headers"], "i_am_authorized": true}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestCreateScan_Agent(t *testing.T) {
+	h, router := setupTestHandlers()
+	cfg := config.Defaults()
+	cfg.Intensity = "safe"
+	cfg.Engagement = types.Engagement{Client: "ACME"}
+	h.Config = func() *config.Config { return &cfg }
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPost, "/api/v1/scans", `{"target": "10.0.0.5", "agent": "dmz-1"}`)
+	assert.Equal(t, http.StatusConflict, w.Code, "the agent has not connected")
+	w = do(http.MethodPost, "/api/v1/scans", `{"target": "10.0.0.5", "agent": "../dmz"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = do(http.MethodGet, "/api/v1/agents/dmz-1/jobs/next", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = do(http.MethodPost, "/api/v1/scans", `{"target": "10.0.0.5", "agent": "dmz-1", "engagement": {"tester": "jane"}}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	id := created["id"].(string)
+	assert.Equal(t, "dmz-1", created["agent"])
+
+	w = do(http.MethodGet, "/api/v1/agents/dmz-1/jobs/next", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var job AgentJob
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+	assert.Equal(t, id, job.ID)
+	var spec CreateScanRequest
+	require.NoError(t, json.Unmarshal(job.Request, &spec))
+	assert.Equal(t, "10.0.0.5", spec.Target)
+	assert.ElementsMatch(t, []string{"headers", "port"}, spec.Scanners, "scanners are resolved on the server")
+	assert.Equal(t, "safe", spec.Intensity)
+	assert.Equal(t, types.Engagement{Client: "ACME", Tester: "jane"}, spec.Engagement)
+	assert.Empty(t, spec.Agent)
+
+	w = do(http.MethodGet, "/api/v1/agents", "")
+	assert.Contains(t, w.Body.String(), `"name":"dmz-1"`)
+	assert.Contains(t, w.Body.String(), `"online":true`)
+
+	w = do(http.MethodPost, "/api/v1/agents/other/jobs/"+id, `{"status": "completed"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = do(http.MethodPost, "/api/v1/agents/dmz-1/jobs/"+id, `{"status": "completed", "progress": {"total_scanners": 2, "completed_scanners": 2}, "results": [{"scanner_name": "port", "findings": [{"title": "Open port: 22/tcp", "severity": "info"}]}]}`)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = do(http.MethodPost, "/api/v1/agents/dmz-1/jobs/"+id, `{"status": "running"}`)
+	assert.Equal(t, http.StatusConflict, w.Code, "the scan already ended")
+
+	w = do(http.MethodGet, "/api/v1/scans/"+id, "")
+	var got jobs.Job
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, jobs.StatusCompleted, got.Status)
+	assert.Equal(t, "dmz-1", got.Agent)
+	assert.Equal(t, 1, got.FindingCount())
+}
+
+func TestCreateScan_Profile(t *testing.T) {
+	h, router := setupTestHandlers()
+	cfg := config.Defaults()
+	h.Config = func() *config.Config { return &cfg }
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/scans", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post(`{"target": "https://example.com", "profile": "quick"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// The config is read per request, so a reloaded profile applies at once.
+	cfg.ScanProfiles = []config.ScanProfile{{Name: "quick", Scanners: []string{"port"}}}
+	w = post(`{"target": "https://example.com", "profile": "quick"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	job, err := h.Manager.Get(resp["id"].(string))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"port"}, job.Scanners)
+}
+
 func TestListScans_ReturnsJobs(t *testing.T) {
 	h, router := setupTestHandlers()
 
@@ -126,6 +427,29 @@ func TestListScans_ReturnsJobs(t *testing.T) {
 	assert.Equal(t, "example.com", list[0]["target"])
 }
 
+func TestListScans_Filter(t *testing.T) {
+	h, router := setupTestHandlers()
+
+	h.Manager.Create(types.Target{Host: "example.com", Scheme: "https"}, []string{"headers"}, scanner.DefaultOptions())
+	h.Manager.Create(types.Target{Host: "other.test", Scheme: "https"}, []string{"headers"}, scanner.DefaultOptions())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/scans?status=pending&target=EXAMPLE", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var list []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list, 1)
+	assert.Equal(t, "example.com", list[0]["target"])
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/scans?status=completed", nil))
+	assert.Equal(t, "[]\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/scans?status=done", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestGetScan_Found(t *testing.T) {
 	h, router := setupTestHandlers()
 
@@ -183,6 +507,15 @@ func TestGetScanReport_ReturnsHTML(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Equal(t, "text/html", w.Header().Get("Content-Type"))
 	assert.Contains(t, w.Body.String(), "<!DOCTYPE html>")
+	assert.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/scans/"+job.ID+"/report", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
 }
 
 func TestGetScanReport_NotCompleted(t *testing.T) {
@@ -200,6 +533,307 @@ func TestGetScanReport_NotCompleted(t *testing.T) {
 	assert.Equal(t, http.StatusConflict, w.Code)
 }
 
+func TestGetScanLogs(t *testing.T) {
+	h, router := setupTestHandlers()
+
+	target := types.Target{Host: "example.com", Scheme: "https"}
+	job := h.Manager.Create(target, []string{"headers"}, scanner.DefaultOptions())
+	h.Manager.Start(job.ID)
+
+	require.Eventually(t, func() bool {
+		j, _ := h.Manager.Get(job.ID)
+		return j.Status == jobs.StatusCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/scans/"+job.ID+"/logs", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		ID      string             `json:"id"`
+		Logs    []scanner.LogEntry `json:"logs"`
+		Dropped int                `json:"dropped"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, job.ID, resp.ID)
+	require.Len(t, resp.Logs, 2)
+	assert.Equal(t, "headers", resp.Logs[0].Scanner)
+	assert.Equal(t, "started", resp.Logs[0].Message)
+	assert.Equal(t, "finished with 1 findings", resp.Logs[1].Message)
+}
+
+func TestGetScanLogs_NotFound(t *testing.T) {
+	_, router := setupTestHandlers()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/scans/nonexistent/logs", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetScan_Artifacts(t *testing.T) {
+	h, router := setupTestHandlers()
+
+	target := types.Target{Host: "example.com", Scheme: "https"}
+	job := h.Manager.Create(target, []string{"headers"}, scanner.DefaultOptions())
+	h.Manager.Start(job.ID)
+
+	require.Eventually(t, func() bool {
+		j, _ := h.Manager.Get(job.ID)
+		return j.Status == jobs.StatusCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+
+	get := func(query string) jobs.Job {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/scans/"+job.ID+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp jobs.Job
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+
+	assert.Empty(t, get("").Results[0].Findings[0].Artifacts)
+	assert.Len(t, get("?artifacts=true").Results[0].Findings[0].Artifacts, 1)
+
+	stored, _ := h.Manager.Get(job.ID)
+	assert.Len(t, stored.Results[0].Findings[0].Artifacts, 1, "the stored job keeps its artifacts")
+}
+
+func TestGetFindingArtifacts(t *testing.T) {
+	h, router := setupTestHandlers()
+
+	target := types.Target{Host: "example.com", Scheme: "https"}
+	job := h.Manager.Create(target, []string{"headers"}, scanner.DefaultOptions())
+	h.Manager.Start(job.ID)
+
+	require.Eventually(t, func() bool {
+		j, _ := h.Manager.Get(job.ID)
+		return j.Status == jobs.StatusCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/scans/"+job.ID+"/artifacts/0/0", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+	assert.Contains(t, w.Body.String(), "--- request 1 ---\nGET / HTTP/1.1\r\nHost: example.com")
+	assert.Contains(t, w.Body.String(), "--- response 1 ---\nHTTP/1.1 200 OK")
+
+	for _, path := range []string{"/artifacts/0/1", "/artifacts/5/0", "/artifacts/x/0"} {
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/scans/"+job.ID+path, nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.GreaterOrEqual(t, w.Code, 400, path)
+	}
+}
+
+func TestVerifyFinding(t *testing.T) {
+	h, router := setupTestHandlers()
+
+	target := types.Target{Host: "example.com", Scheme: "https"}
+	job := h.Manager.Create(target, []string{"headers"}, scanner.DefaultOptions())
+	h.Manager.Start(job.ID)
+
+	require.Eventually(t, func() bool {
+		j, _ := h.Manager.Get(job.ID)
+		return j.Status == jobs.StatusCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+
+	stored, _ := h.Manager.Get(job.ID)
+	fingerprint := stored.Results[0].Findings[0].Fingerprint
+	require.NotEmpty(t, fingerprint)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/scans/"+job.ID+"/findings/"+fingerprint+"/verify", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Fingerprint string `json:"fingerprint"`
+		Reproduces  bool   `json:"reproduces"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, fingerprint, resp.Fingerprint)
+	assert.True(t, resp.Reproduces)
+
+	for _, path := range []string{
+		"/api/v1/scans/" + job.ID + "/findings/000000000000/verify",
+		"/api/v1/scans/nonexistent/findings/" + fingerprint + "/verify",
+	} {
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+		assert.Equal(t, http.StatusNotFound, w.Code, path)
+	}
+}
+
+func TestListFindings(t *testing.T) {
+	h, router := setupTestHandlers()
+	cfg := config.Defaults()
+	cfg.SLA = map[string]int{"info": 7}
+	h.Config = func() *config.Config { return &cfg }
+
+	target := types.Target{Host: "example.com", Scheme: "https"}
+	job := h.Manager.Create(target, []string{"headers", "port"}, scanner.DefaultOptions())
+	h.Manager.Start(job.ID)
+	require.Eventually(t, func() bool {
+		j, _ := h.Manager.Get(job.ID)
+		return j.Status == jobs.StatusCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+	stored, _ := h.Manager.Get(job.ID)
+	stored.CompletedAt = time.Now().Add(-10 * 24 * time.Hour)
+
+	get := func(query string) []jobs.TrackedFinding {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/findings"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code, query)
+		var findings []jobs.TrackedFinding
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &findings))
+		return findings
+	}
+
+	findings := get("")
+	require.Len(t, findings, 2)
+	assert.Equal(t, "example.com", findings[0].Target)
+	assert.Equal(t, 10, findings[0].AgeDays)
+	assert.Equal(t, 7, findings[0].SLADays)
+	assert.True(t, findings[0].Overdue)
+
+	assert.Len(t, get("?status=overdue"), 2)
+	assert.Empty(t, get("?status=closed"))
+	assert.Empty(t, get("?target=other.test"))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/findings?status=stale", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPauseAndResumeScan(t *testing.T) {
+	h, router := setupTestHandlers()
+
+	target := types.Target{Host: "example.com", Scheme: "https"}
+	job := h.Manager.Create(target, []string{"headers"}, scanner.DefaultOptions())
+
+	post := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+		return w
+	}
+
+	// Pending jobs cannot be paused.
+	assert.Equal(t, http.StatusConflict, post("/api/v1/scans/"+job.ID+"/pause").Code)
+	assert.Equal(t, http.StatusNotFound, post("/api/v1/scans/nonexistent/pause").Code)
+
+	// Pause before the scan runs so it cannot complete first.
+	stored, _ := h.Manager.Get(job.ID)
+	stored.Status = jobs.StatusRunning
+	w := post("/api/v1/scans/" + job.ID + "/pause")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"paused"`)
+
+	w = post("/api/v1/scans/" + job.ID + "/resume")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"running"`)
+	assert.Equal(t, http.StatusConflict, post("/api/v1/scans/"+job.ID+"/resume").Code)
+}
+
+func TestCancelScan(t *testing.T) {
+	h, router := setupTestHandlers()
+
+	job := h.Manager.Create(types.Target{Host: "example.com", Scheme: "https"}, []string{"headers"}, scanner.DefaultOptions())
+
+	post := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+		return w
+	}
+
+	assert.Equal(t, http.StatusConflict, post("/api/v1/scans/"+job.ID+"/cancel").Code, "a pending scan cannot be canceled")
+	assert.Equal(t, http.StatusNotFound, post("/api/v1/scans/nonexistent/cancel").Code)
+}
+
+func TestRerunScan(t *testing.T) {
+	h, router := setupTestHandlers()
+
+	target := types.Target{Host: "example.com", Scheme: "https"}
+	job := h.Manager.Create(target, []string{"headers", "port"}, scanner.DefaultOptions())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/scans/"+job.ID+"/rerun", nil))
+	assert.Equal(t, http.StatusConflict, w.Code, "the scan has not run yet")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/scans/missing/rerun", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	h.Manager.Start(job.ID)
+	require.Eventually(t, func() bool {
+		j, _ := h.Manager.Snapshot(job.ID)
+		return j.Status == jobs.StatusCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/scans/"+job.ID+"/rerun", nil))
+	require.Equal(t, http.StatusCreated, w.Code)
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, job.ID, resp["rerun_of"])
+
+	rerun, err := h.Manager.Get(resp["id"].(string))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"headers", "port"}, rerun.Scanners)
+}
+
+func TestRerunScan_Unauthorized(t *testing.T) {
+	h, router := setupTestHandlers()
+	job := h.Manager.Create(types.Target{Host: "example.com", Scheme: "https"}, []string{"headers"}, scanner.DefaultOptions())
+	h.Manager.Start(job.ID)
+	require.Eventually(t, func() bool {
+		j, _ := h.Manager.Snapshot(job.ID)
+		return j.Status == jobs.StatusCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The config was reloaded since the scan ran, and no longer allows it.
+	cfg := &config.Config{AuthorizedTargets: []string{"other.example.com"}}
+	h.Manager.SetAuthorizer(cfg.AuthorizeTarget)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/scans/"+job.ID+"/rerun", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "authorized_targets")
+}
+
+func TestRerunScans(t *testing.T) {
+	h, router := setupTestHandlers()
+
+	// A scanner that is not registered fails, leaving the scan incomplete.
+	job := h.Manager.Create(types.Target{Host: "example.com", Scheme: "https"}, []string{"headers", "missing"}, scanner.DefaultOptions())
+	h.Manager.Start(job.ID)
+	require.Eventually(t, func() bool {
+		j, _ := h.Manager.Snapshot(job.ID)
+		return j.Status == jobs.StatusCompleted && j.Incomplete()
+	}, 5*time.Second, 10*time.Millisecond)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/scans/rerun", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Scans []struct {
+			ID      string `json:"id"`
+			RerunOf string `json:"rerun_of"`
+		} `json:"scans"`
+		Errors []string `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Scans, 1)
+	assert.Equal(t, job.ID, resp.Scans[0].RerunOf)
+	assert.Empty(t, resp.Errors)
+}
+
 func TestDeleteScan_Success(t *testing.T) {
 	h, router := setupTestHandlers()
 
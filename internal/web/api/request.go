@@ -5,14 +5,64 @@ import (
 	"fmt"
 	"net/http"
 	"time"
+
+	"github.com/buemura/hunter/internal/config"
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/web/jobs"
+	"github.com/buemura/hunter/pkg/types"
 )
 
 // CreateScanRequest is the JSON body for POST /api/v1/scans.
 type CreateScanRequest struct {
 	Target      string   `json:"target"`
 	Scanners    []string `json:"scanners"`
+	Profile     string   `json:"profile"`
 	Concurrency int      `json:"concurrency"`
 	Timeout     string   `json:"timeout"`
+	// MaxDuration bounds the whole scan, e.g. "10m". It defaults to the
+	// timeout once per scanner, plus one.
+	MaxDuration string   `json:"max_duration"`
+	NoPreflight bool     `json:"no_preflight"`
+	Intensity   string   `json:"intensity"`
+	IPVersion   string   `json:"ip_version"`
+	Resolver    string   `json:"resolver"`
+	Resolve     []string `json:"resolve"`
+	// Endpoints, if set, replace the paths the api scanners guess.
+	Endpoints []types.Endpoint `json:"endpoints"`
+	// Crawl spiders the target first, up to CrawlDepth links away and
+	// CrawlPages pages, zero meaning the defaults, and adds what it finds
+	// to Endpoints.
+	Crawl      bool `json:"crawl"`
+	CrawlDepth int  `json:"crawl_depth"`
+	CrawlPages int  `json:"crawl_pages"`
+	// Redact masks credentials, tokens, cookies, and IP addresses in the
+	// evidence and artifacts of findings before they are stored.
+	Redact bool `json:"redact"`
+	// Engagement identifies the engagement the scan is run under. Its set
+	// fields override the config file's engagement section.
+	Engagement types.Engagement `json:"engagement"`
+	// IAmAuthorized acknowledges permission to test a target outside the
+	// config file's authorized_targets, which are otherwise refused.
+	IAmAuthorized bool `json:"i_am_authorized"`
+	// Scanners run one at a time in the order given, after those they
+	// depend on in DependsOn. OnFailure is what happens once one fails:
+	// "continue" (the default), "abort-remaining", or "skip-dependents".
+	OnFailure string              `json:"on_failure"`
+	DependsOn map[string][]string `json:"depends_on"`
+	// Watchdog, e.g. "10s", probes the target that often during the scan.
+	// Once WatchdogThreshold of the last probes fail (0.8 if zero), the
+	// scan aborts the scanners not yet started, or with WatchdogAction
+	// "pause" pauses until the target responds again.
+	Watchdog          string  `json:"watchdog"`
+	WatchdogThreshold float64 `json:"watchdog_threshold"`
+	WatchdogAction    string  `json:"watchdog_action"`
+	// Window, e.g. "22:00-06:00 Europe/Berlin", is the daily period the
+	// scan may run in; outside it the scan is paused. It defaults to the
+	// config file's scan_window.
+	Window string `json:"window"`
+	// Agent, if set, names the agent to run the scan on, from the network
+	// it runs in, instead of the server.
+	Agent string `json:"agent"`
 }
 
 // decodeCreateScanRequest reads and validates the request body.
@@ -21,13 +71,20 @@ func decodeCreateScanRequest(r *http.Request) (*CreateScanRequest, error) {
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		return nil, fmt.Errorf("invalid JSON: %w", err)
 	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
 
+// Validate checks the request and fills in the default concurrency.
+func (req *CreateScanRequest) Validate() error {
 	if req.Target == "" {
-		return nil, fmt.Errorf("target is required")
+		return fmt.Errorf("target is required")
 	}
 
 	if req.Concurrency < 0 {
-		return nil, fmt.Errorf("concurrency must be non-negative")
+		return fmt.Errorf("concurrency must be non-negative")
 	}
 	if req.Concurrency == 0 {
 		req.Concurrency = 10
@@ -35,9 +92,115 @@ func decodeCreateScanRequest(r *http.Request) (*CreateScanRequest, error) {
 
 	if req.Timeout != "" {
 		if _, err := time.ParseDuration(req.Timeout); err != nil {
-			return nil, fmt.Errorf("invalid timeout %q: %w", req.Timeout, err)
+			return fmt.Errorf("invalid timeout %q: %w", req.Timeout, err)
 		}
 	}
 
-	return &req, nil
+	if req.MaxDuration != "" {
+		if d, err := time.ParseDuration(req.MaxDuration); err != nil || d <= 0 {
+			return fmt.Errorf("invalid max_duration %q: must be a positive duration", req.MaxDuration)
+		}
+	}
+
+	if req.CrawlDepth < 0 || req.CrawlPages < 0 {
+		return fmt.Errorf("crawl_depth and crawl_pages must be non-negative")
+	}
+
+	if _, err := scanner.ParseIntensity(req.Intensity); err != nil {
+		return err
+	}
+	if _, err := scanner.ParseIPVersion(req.IPVersion); err != nil {
+		return err
+	}
+	if _, err := scanner.NewResolver(req.Resolver, req.Resolve); err != nil {
+		return err
+	}
+	if _, err := scanner.ParseFailurePolicy(req.OnFailure); err != nil {
+		return err
+	}
+	if err := scanner.CheckDependencies(req.DependsOn); err != nil {
+		return err
+	}
+	if req.Watchdog != "" {
+		if d, err := time.ParseDuration(req.Watchdog); err != nil || d <= 0 {
+			return fmt.Errorf("invalid watchdog %q: must be a positive duration", req.Watchdog)
+		}
+	}
+	if req.WatchdogThreshold < 0 || req.WatchdogThreshold > 1 {
+		return fmt.Errorf("watchdog_threshold must be between 0 and 1")
+	}
+	if _, err := scanner.ParseWatchdogAction(req.WatchdogAction); err != nil {
+		return err
+	}
+	if _, err := scanner.ParseWindow(req.Window); err != nil {
+		return err
+	}
+	if req.Agent != "" && !jobs.ValidAgentName(req.Agent) {
+		return fmt.Errorf("invalid agent name %q", req.Agent)
+	}
+
+	return nil
+}
+
+// ScanOptions builds the options to run a validated request with, taking
+// per-scanner settings, severity overrides, the default intensity, and the
+// engagement from cfg. Its errors are errors in cfg.
+func ScanOptions(req *CreateScanRequest, cfg *config.Config) (scanner.Options, error) {
+	overrides, err := scanner.ParseSeverityOverrides(cfg.SeverityOverrides)
+	if err != nil {
+		return scanner.Options{}, err
+	}
+
+	opts := scanner.Options{
+		Concurrency: req.Concurrency,
+		Timeout:     5 * time.Second,
+		ScannerArgs: cfg.Scanners,
+		Overrides:   overrides,
+		Endpoints:   req.Endpoints,
+		Redact:      req.Redact,
+		Engagement:  cfg.Engagement.Merge(req.Engagement),
+		DependsOn:   req.DependsOn,
+	}
+	opts.OnFailure, _ = scanner.ParseFailurePolicy(req.OnFailure) // already validated
+	if req.Timeout != "" {
+		d, _ := time.ParseDuration(req.Timeout) // already validated
+		opts.Timeout = d
+	}
+	if req.MaxDuration != "" {
+		opts.MaxDuration, _ = time.ParseDuration(req.MaxDuration) // already validated
+	}
+	if req.Watchdog != "" {
+		opts.Watchdog.Interval, _ = time.ParseDuration(req.Watchdog) // already validated
+		opts.Watchdog.Threshold = req.WatchdogThreshold
+		opts.Watchdog.Action, _ = scanner.ParseWatchdogAction(req.WatchdogAction) // already validated
+	}
+	intensity := req.Intensity
+	if intensity == "" {
+		intensity = cfg.Intensity
+	}
+	if opts.Intensity, err = scanner.ParseIntensity(intensity); err != nil {
+		return scanner.Options{}, err
+	}
+	opts.IPVersion, _ = scanner.ParseIPVersion(req.IPVersion) // already validated
+	window := req.Window
+	if window == "" {
+		window = cfg.ScanWindow
+	}
+	if opts.Window, err = scanner.ParseWindow(window); err != nil {
+		return scanner.Options{}, err
+	}
+	if req.Resolver != "" || len(req.Resolve) > 0 {
+		opts.Resolver, _ = scanner.NewResolver(req.Resolver, req.Resolve) // already validated
+	}
+	opts.Transport = scanner.BaseTransport(opts)
+	if !req.IAmAuthorized {
+		opts.Authorize = cfg.AuthorizeTarget
+	}
+	if !req.NoPreflight {
+		opts.Preflight = scanner.NewPreflight()
+	}
+	if req.Crawl {
+		opts.Crawler = scanner.NewCrawler(req.CrawlDepth, req.CrawlPages)
+	}
+	return opts, nil
 }
@@ -0,0 +1,55 @@
+package web
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"net/http"
+)
+
+// staticCacheControl lets browsers and CDNs reuse static assets for an hour
+// before revalidating them against their ETag. Asset URLs are not versioned,
+// so a new release is picked up within the hour.
+const staticCacheControl = "public, max-age=3600"
+
+// staticETags returns an ETag for each file of fsys, keyed by its path and
+// derived from its contents. The ETags are weak, as the compression
+// middleware may send a file in several encodings.
+func staticETags(fsys fs.FS) map[string]string {
+	etags := map[string]string{}
+	fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		etags[path] = `W/"` + hex.EncodeToString(sum[:8]) + `"`
+		return nil
+	})
+	return etags
+}
+
+// cacheStatic sets the ETag and Cache-Control headers of the static files
+// next serves, by their path in etags. http.FileServer then answers
+// conditional requests for them with 304 Not Modified.
+func cacheStatic(etags map[string]string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if etag, ok := etags[r.URL.Path]; ok {
+			w.Header().Set("ETag", etag)
+			w.Header().Set("Cache-Control", staticCacheControl)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// noCache makes browsers revalidate everything next serves, so edited
+// static files show on the next page load.
+func noCache(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-cache")
+		next.ServeHTTP(w, r)
+	})
+}
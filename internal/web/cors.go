@@ -0,0 +1,81 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/buemura/hunter/internal/config"
+)
+
+// corsMaxAge is how long, in seconds, browsers may cache a preflight
+// response.
+const corsMaxAge = "600"
+
+// corsMethods are the methods the API answers cross-origin.
+const corsMethods = "GET, POST, DELETE, OPTIONS"
+
+// cors adds CORS headers to API responses for the origins allowed by the
+// current config, and answers preflight requests itself. The config is read
+// per request so reloads apply without a restart.
+func (s *Server) cors(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		settings := s.Config().CORS
+		origin := r.Header.Get("Origin")
+		if len(settings.AllowedOrigins) == 0 || origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Add("Vary", "Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		if preflight {
+			h.Add("Vary", "Access-Control-Request-Method")
+			h.Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		if allowed, wildcard := corsAllowed(settings, origin); allowed {
+			// Browsers refuse credentials with "*", and echoing any origin
+			// with credentials would let every site act as the user, so
+			// credentials are only ever allowed for named origins.
+			if wildcard {
+				h.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				h.Set("Access-Control-Allow-Origin", origin)
+				if settings.AllowCredentials {
+					h.Set("Access-Control-Allow-Credentials", "true")
+				}
+			}
+			if preflight {
+				h.Set("Access-Control-Allow-Methods", corsMethods)
+				if headers := r.Header.Get("Access-Control-Request-Headers"); headers != "" {
+					h.Set("Access-Control-Allow-Headers", headers)
+				}
+				h.Set("Access-Control-Max-Age", corsMaxAge)
+			}
+		}
+
+		// A preflight is answered here whether or not the origin is allowed:
+		// without the headers above the browser blocks the real request.
+		if preflight {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// corsAllowed reports whether origin may call the API, and whether it is
+// allowed by the "*" wildcard rather than by name.
+func corsAllowed(settings config.CORS, origin string) (allowed, wildcard bool) {
+	for _, o := range settings.AllowedOrigins {
+		o = strings.TrimRight(strings.TrimSpace(o), "/")
+		if strings.EqualFold(o, origin) {
+			return true, false
+		}
+		if o == "*" {
+			wildcard = true
+		}
+	}
+	return wildcard, wildcard
+}
@@ -0,0 +1,112 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/buemura/hunter/internal/config"
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func corsServer(t *testing.T, settings config.CORS) *httptest.Server {
+	t.Helper()
+	cfg := config.Defaults()
+	cfg.CORS = settings
+	srv := NewServer(":0", scanner.NewRegistry(), Options{Config: &cfg})
+	ts := httptest.NewServer(srv.Router())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func corsRequest(t *testing.T, method, url, origin string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, nil)
+	require.NoError(t, err)
+	req.Header.Set("Origin", origin)
+	if method == http.MethodOptions {
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		req.Header.Set("Access-Control-Request-Headers", "Content-Type")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp
+}
+
+func TestCORSDisabledByDefault(t *testing.T) {
+	ts := corsServer(t, config.CORS{})
+
+	resp := corsRequest(t, http.MethodGet, ts.URL+"/api/v1/scans", "https://dashboard.example.com")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORSAllowedOrigin(t *testing.T) {
+	ts := corsServer(t, config.CORS{AllowedOrigins: []string{"https://dashboard.example.com/"}, AllowCredentials: true})
+
+	resp := corsRequest(t, http.MethodGet, ts.URL+"/api/v1/scans", "https://dashboard.example.com")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "https://dashboard.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", resp.Header.Get("Access-Control-Allow-Credentials"))
+	assert.Contains(t, resp.Header.Values("Vary"), "Origin")
+
+	resp = corsRequest(t, http.MethodOptions, ts.URL+"/api/v1/scans", "https://dashboard.example.com")
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "https://dashboard.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, resp.Header.Get("Access-Control-Allow-Methods"), "POST")
+	assert.Equal(t, "Content-Type", resp.Header.Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, corsMaxAge, resp.Header.Get("Access-Control-Max-Age"))
+}
+
+func TestCORSRejectedOrigin(t *testing.T) {
+	ts := corsServer(t, config.CORS{AllowedOrigins: []string{"https://dashboard.example.com"}})
+
+	resp := corsRequest(t, http.MethodGet, ts.URL+"/api/v1/scans", "https://evil.example.net")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+
+	resp = corsRequest(t, http.MethodOptions, ts.URL+"/api/v1/scans", "https://evil.example.net")
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, resp.Header.Get("Access-Control-Allow-Methods"))
+}
+
+func TestCORSWildcard(t *testing.T) {
+	ts := corsServer(t, config.CORS{AllowedOrigins: []string{"*"}})
+
+	resp := corsRequest(t, http.MethodGet, ts.URL+"/api/v1/scans", "https://anything.example.org")
+	assert.Equal(t, "*", resp.Header.Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, resp.Header.Get("Access-Control-Allow-Credentials"))
+}
+
+func TestCORSOnlyOnAPI(t *testing.T) {
+	ts := corsServer(t, config.CORS{AllowedOrigins: []string{"*"}})
+
+	resp := corsRequest(t, http.MethodGet, ts.URL+"/health", "https://anything.example.org")
+	assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORSWildcardNeverAllowsCredentials(t *testing.T) {
+	ts := corsServer(t, config.CORS{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+
+	resp := corsRequest(t, http.MethodGet, ts.URL+"/api/v1/scans", "https://anything.example.org")
+	assert.Equal(t, "*", resp.Header.Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, resp.Header.Get("Access-Control-Allow-Credentials"))
+}
+
+func TestAPIMutationsRejectCrossOrigin(t *testing.T) {
+	ts := corsServer(t, config.CORS{AllowedOrigins: []string{"https://dashboard.example"}})
+	cancel := ts.URL + "/api/v1/scans/missing/cancel"
+
+	assert.Equal(t, http.StatusForbidden, corsRequest(t, http.MethodPost, cancel, "https://evil.example").StatusCode)
+	assert.Equal(t, http.StatusNotFound, corsRequest(t, http.MethodPost, cancel, "https://dashboard.example").StatusCode)
+	assert.Equal(t, http.StatusNotFound, corsRequest(t, http.MethodPost, cancel, ts.URL).StatusCode)
+	assert.Equal(t, http.StatusOK, corsRequest(t, http.MethodGet, ts.URL+"/api/v1/scans", "https://evil.example").StatusCode,
+		"reads are left to CORS")
+
+	wildcard := corsServer(t, config.CORS{AllowedOrigins: []string{"*"}})
+	assert.Equal(t, http.StatusForbidden, corsRequest(t, http.MethodPost, wildcard.URL+"/api/v1/scans/missing/cancel", "https://evil.example").StatusCode)
+}
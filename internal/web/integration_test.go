@@ -37,7 +37,7 @@ func newIntegrationServer() (*Server, *httptest.Server) {
 	reg := scanner.NewRegistry()
 	reg.Register(&mockScanner{name: "headers"})
 	reg.Register(&mockScanner{name: "port"})
-	srv := NewServer(":0", reg)
+	srv := NewServer(":0", reg, Options{})
 	ts := httptest.NewServer(srv.Router())
 	return srv, ts
 }
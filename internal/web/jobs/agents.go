@@ -0,0 +1,192 @@
+package jobs
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// AgentTimeout is how long an agent may go without polling or reporting
+// before it is considered offline and the job it was running fails.
+const AgentTimeout = time.Minute
+
+// Agent is a worker that runs scans for the server from its own network
+// segment; see `hunter agent`. Agents register by polling for work.
+type Agent struct {
+	Name     string    `json:"name"`
+	LastSeen time.Time `json:"last_seen"`
+	// Job is the ID of the job the agent is running, if any.
+	Job string `json:"job,omitempty"`
+}
+
+// Online reports whether the agent has been seen within AgentTimeout of now.
+func (a Agent) Online(now time.Time) bool {
+	return now.Sub(a.LastSeen) <= AgentTimeout
+}
+
+// ValidAgentName reports whether name can name an agent: letters, digits,
+// dots, hyphens, and underscores.
+func ValidAgentName(name string) bool {
+	if name == "" || len(name) > 64 {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// Report is an agent's update on the job it runs. Results and Logs hold
+// only what is new since its previous report.
+type Report struct {
+	Status      JobStatus          `json:"status"`
+	Progress    JobProgress        `json:"progress"`
+	Results     []types.ScanResult `json:"results,omitempty"`
+	Logs        []scanner.LogEntry `json:"logs,omitempty"`
+	LogsDropped int                `json:"logs_dropped,omitempty"`
+	Error       string             `json:"error,omitempty"`
+}
+
+// Dispatch creates a pending job for the named agent to run, which it takes
+// with Claim. spec describes the scan to the agent. The agent must be
+// online.
+func (m *Manager) Dispatch(agent string, target types.Target, scanners []string, engagement types.Engagement, spec json.RawMessage) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.expire(now)
+	if a, ok := m.agents[agent]; !ok || !a.Online(now) {
+		return nil, fmt.Errorf("agent %q is not connected", agent)
+	}
+
+	job := newJob(target, scanners, scanner.Options{Engagement: engagement})
+	job.Agent = agent
+	job.Spec = spec
+	m.jobs[job.ID] = job
+	m.prune()
+	return job, nil
+}
+
+// Claim records that the named agent is polling for work and hands it the
+// oldest pending job dispatched to it, now running, or nil if there is
+// none. An agent only polls while idle, so a job it was still running was
+// lost, as when the agent restarted, and fails.
+func (m *Manager) Claim(agent string) *Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	a := m.seen(agent, now)
+	m.expire(now)
+
+	var next *Job
+	for _, j := range m.jobs {
+		if j.Agent != agent {
+			continue
+		}
+		switch j.Status {
+		case StatusRunning:
+			m.finish(j, StatusFailed, fmt.Sprintf("agent %q stopped running the scan", agent), now)
+		case StatusPending:
+			if next == nil || j.CreatedAt.Before(next.CreatedAt) {
+				next = j
+			}
+		}
+	}
+	if next == nil {
+		return nil
+	}
+	next.Status = StatusRunning
+	next.StartedAt = now
+	a.Job = next.ID
+	return next
+}
+
+// Report applies an update from the named agent to the job it runs. It
+// fails if the job was not dispatched to the agent or has already finished,
+// in which case the agent should stop running it.
+func (m *Manager) Report(agent, jobID string, r Report) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.seen(agent, now)
+	job, ok := m.jobs[jobID]
+	if !ok || job.Agent != agent {
+		return fmt.Errorf("job %q not found", jobID)
+	}
+	if job.Status != StatusRunning {
+		return fmt.Errorf("job %q is %s, not running", jobID, job.Status)
+	}
+
+	job.Results = append(job.Results, r.Results...)
+	for _, entry := range r.Logs {
+		job.appendLog(entry)
+	}
+	job.LogsDropped += r.LogsDropped
+	job.Progress = r.Progress
+	if r.Status == StatusCompleted || r.Status == StatusFailed {
+		m.finish(job, r.Status, r.Error, now)
+		m.prune()
+	}
+	return nil
+}
+
+// Agents returns the agents that have polled the server, sorted by name.
+func (m *Manager) Agents() []Agent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expire(time.Now())
+	agents := make([]Agent, 0, len(m.agents))
+	for _, a := range m.agents {
+		agents = append(agents, *a)
+	}
+	sort.Slice(agents, func(i, k int) bool {
+		return agents[i].Name < agents[k].Name
+	})
+	return agents
+}
+
+// seen records that the named agent was heard from. Callers must hold m.mu
+// for writing.
+func (m *Manager) seen(name string, now time.Time) *Agent {
+	a, ok := m.agents[name]
+	if !ok {
+		a = &Agent{Name: name}
+		m.agents[name] = a
+	}
+	a.LastSeen = now
+	return a
+}
+
+// expire fails the running jobs of agents that went offline. Callers must
+// hold m.mu for writing.
+func (m *Manager) expire(now time.Time) {
+	for _, j := range m.jobs {
+		if j.Agent == "" || j.Status != StatusRunning {
+			continue
+		}
+		if a, ok := m.agents[j.Agent]; !ok || !a.Online(now) {
+			m.finish(j, StatusFailed, fmt.Sprintf("agent %q stopped responding", j.Agent), now)
+		}
+	}
+}
+
+// finish ends an agent's job. Callers must hold m.mu for writing.
+func (m *Manager) finish(job *Job, status JobStatus, errMsg string, now time.Time) {
+	job.Status = status
+	job.Error = errMsg
+	job.CompletedAt = now
+	job.Progress.CurrentScanner = ""
+	if a, ok := m.agents[job.Agent]; ok && a.Job == job.ID {
+		a.Job = ""
+	}
+}
@@ -0,0 +1,120 @@
+package jobs
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatch_RequiresOnlineAgent(t *testing.T) {
+	m := newTestManager()
+	target := types.Target{Host: "10.0.0.5"}
+
+	_, err := m.Dispatch("dmz-1", target, []string{"port"}, types.Engagement{}, nil)
+	assert.ErrorContains(t, err, "not connected")
+
+	assert.Nil(t, m.Claim("dmz-1"), "polling registers the agent")
+	m.agents["dmz-1"].LastSeen = time.Now().Add(-2 * AgentTimeout)
+	_, err = m.Dispatch("dmz-1", target, []string{"port"}, types.Engagement{}, nil)
+	assert.ErrorContains(t, err, "not connected")
+}
+
+func TestClaimAndReport(t *testing.T) {
+	m := newTestManager()
+	m.Claim("dmz-1")
+	spec := json.RawMessage(`{"target":"10.0.0.5"}`)
+	job, err := m.Dispatch("dmz-1", types.Target{Host: "10.0.0.5"}, []string{"port", "ssl"}, types.Engagement{Client: "ACME"}, spec)
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, job.Status)
+	assert.Equal(t, "ACME", job.Engagement.Client)
+
+	assert.Nil(t, m.Claim("other"), "jobs go only to their agent")
+	claimed := m.Claim("dmz-1")
+	require.NotNil(t, claimed)
+	assert.Equal(t, job.ID, claimed.ID)
+	assert.Equal(t, spec, claimed.Spec)
+	assert.Equal(t, StatusRunning, claimed.Status)
+	assert.Equal(t, job.ID, m.Agents()[0].Job)
+
+	assert.Error(t, m.Pause(job.ID), "agent jobs cannot be paused from the server")
+	assert.Error(t, m.Report("other", job.ID, Report{}))
+
+	require.NoError(t, m.Report("dmz-1", job.ID, Report{
+		Status:   StatusRunning,
+		Progress: JobProgress{TotalScanners: 2, CompletedScanners: 1, CurrentScanner: "ssl"},
+		Results:  []types.ScanResult{{ScannerName: "port", Findings: []types.Finding{{Title: "Open port: 22/tcp"}}}},
+		Logs:     []scanner.LogEntry{{Scanner: "port", Message: "started"}},
+	}))
+	require.NoError(t, m.Report("dmz-1", job.ID, Report{
+		Status:   StatusCompleted,
+		Progress: JobProgress{TotalScanners: 2, CompletedScanners: 2},
+		Results:  []types.ScanResult{{ScannerName: "ssl"}},
+	}))
+
+	assert.Equal(t, StatusCompleted, job.Status)
+	assert.Len(t, job.Results, 2)
+	assert.Equal(t, 1, job.FindingCount())
+	assert.Len(t, job.Logs, 1)
+	assert.False(t, job.CompletedAt.IsZero())
+	assert.Empty(t, m.Agents()[0].Job)
+
+	assert.Error(t, m.Report("dmz-1", job.ID, Report{Status: StatusRunning}), "finished jobs take no reports")
+}
+
+func TestClaim_FailsJobLostByAgent(t *testing.T) {
+	m := newTestManager()
+	m.Claim("dmz-1")
+	job, err := m.Dispatch("dmz-1", types.Target{Host: "10.0.0.5"}, []string{"port"}, types.Engagement{}, nil)
+	require.NoError(t, err)
+	require.NotNil(t, m.Claim("dmz-1"))
+
+	// An agent polling again while its job runs has restarted.
+	assert.Nil(t, m.Claim("dmz-1"))
+	assert.Equal(t, StatusFailed, job.Status)
+	assert.Contains(t, job.Error, "stopped running")
+}
+
+func TestAgents_FailsJobsOfOfflineAgents(t *testing.T) {
+	m := newTestManager()
+	m.Claim("dmz-1")
+	job, err := m.Dispatch("dmz-1", types.Target{Host: "10.0.0.5"}, []string{"port"}, types.Engagement{}, nil)
+	require.NoError(t, err)
+	require.NotNil(t, m.Claim("dmz-1"))
+
+	m.agents["dmz-1"].LastSeen = time.Now().Add(-2 * AgentTimeout)
+	agents := m.Agents()
+	require.Len(t, agents, 1)
+	assert.False(t, agents[0].Online(time.Now()))
+	assert.Equal(t, StatusFailed, job.Status)
+	assert.Contains(t, job.Error, "stopped responding")
+}
+
+func TestValidAgentName(t *testing.T) {
+	assert.True(t, ValidAgentName("dmz-1.corp_net"))
+	assert.False(t, ValidAgentName(""))
+	assert.False(t, ValidAgentName("dmz 1"))
+	assert.False(t, ValidAgentName("../scans"))
+}
+
+func TestRerun_Agent(t *testing.T) {
+	m := newTestManager()
+	m.Claim("dmz-1")
+	spec := json.RawMessage(`{"target":"10.0.0.5"}`)
+	job, err := m.Dispatch("dmz-1", types.Target{Host: "10.0.0.5"}, []string{"port"}, types.Engagement{Client: "ACME"}, spec)
+	require.NoError(t, err)
+	m.Claim("dmz-1")
+	require.NoError(t, m.Report("dmz-1", job.ID, Report{Status: StatusFailed, Error: "connection refused"}))
+
+	rerun, err := m.Rerun(job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "dmz-1", rerun.Agent)
+	assert.Equal(t, spec, rerun.Spec)
+	assert.Equal(t, "ACME", rerun.Engagement.Client)
+	assert.Equal(t, job.ID, rerun.RerunOf)
+	assert.Equal(t, StatusPending, rerun.Status)
+}
@@ -0,0 +1,143 @@
+package jobs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// SLA is how many days a finding of each severity may stay open. Severities
+// without an entry have no deadline.
+type SLA map[types.Severity]int
+
+// ParseSLA builds an SLA from a map of severity names, in any case, to days,
+// as in the sla section of the config file.
+func ParseSLA(raw map[string]int) (SLA, error) {
+	sla := SLA{}
+	for key, days := range raw {
+		sev := types.Severity(strings.ToUpper(strings.TrimSpace(key)))
+		switch sev {
+		case types.SeverityCritical, types.SeverityHigh, types.SeverityMedium, types.SeverityLow, types.SeverityInfo:
+		default:
+			return nil, fmt.Errorf("sla %q: unknown severity (supported: CRITICAL, HIGH, MEDIUM, LOW, INFO)", key)
+		}
+		if days < 1 {
+			return nil, fmt.Errorf("sla %q: days must be at least 1, got %d", key, days)
+		}
+		sla[sev] = days
+	}
+	return sla, nil
+}
+
+// day is the unit SLAs and finding ages are counted in.
+const day = 24 * time.Hour
+
+// TrackedFinding is a finding followed across the completed scans of a
+// target, identified by its fingerprint.
+type TrackedFinding struct {
+	Target      string         `json:"target"`
+	Fingerprint string         `json:"fingerprint"`
+	Scanner     string         `json:"scanner"`
+	Title       string         `json:"title"`
+	Severity    types.Severity `json:"severity"`
+	// FirstSeen and LastSeen are when the first and latest scans reporting
+	// the finding completed, and Scans counts the scans that did.
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+	Scans     int       `json:"scans"`
+	// Open is false once a later scan of the target ran the finding's
+	// scanner without reporting it; ClosedAt is when that scan completed.
+	Open     bool      `json:"open"`
+	ClosedAt time.Time `json:"closed_at,omitzero"`
+	// AgeDays is how many whole days the finding has been open, or was open
+	// for when closed.
+	AgeDays int `json:"age_days"`
+	// SLADays is the SLA of the finding's severity, zero if it has none;
+	// Overdue is set on open findings older than it.
+	SLADays int  `json:"sla_days,omitempty"`
+	Overdue bool `json:"overdue"`
+}
+
+// Findings tracks the findings of the completed scans the manager holds,
+// per target, and checks the open ones against sla as of now. Overdue
+// findings come first, then open ones, each by severity and age. Only the
+// scans kept by the retention limits are considered, so a finding's age
+// starts at the oldest scan still held.
+func (m *Manager) Findings(sla SLA, now time.Time) []TrackedFinding {
+	m.mu.RLock()
+	var completed []*Job
+	for _, j := range m.jobs {
+		if j.Status == StatusCompleted {
+			completed = append(completed, j)
+		}
+	}
+	sort.Slice(completed, func(i, k int) bool {
+		return completed[i].CompletedAt.Before(completed[k].CompletedAt)
+	})
+
+	type key struct{ target, fingerprint string }
+	tracked := map[key]*TrackedFinding{}
+	var order []key
+	for _, j := range completed {
+		target := j.TargetName()
+		for _, r := range j.Results {
+			if r.Error != "" {
+				// A failed scanner says nothing about what it would
+				// have found.
+				continue
+			}
+			reported := map[string]bool{}
+			for _, f := range r.Findings {
+				reported[f.Fingerprint] = true
+				k := key{target, f.Fingerprint}
+				tf, ok := tracked[k]
+				if !ok {
+					tf = &TrackedFinding{Target: target, Fingerprint: f.Fingerprint, Scanner: r.ScannerName, FirstSeen: j.CompletedAt}
+					tracked[k] = tf
+					order = append(order, k)
+				}
+				tf.Title, tf.Severity = f.Title, f.Severity
+				tf.LastSeen = j.CompletedAt
+				tf.Scans++
+				tf.Open, tf.ClosedAt = true, time.Time{}
+			}
+			for k, tf := range tracked {
+				if k.target == target && tf.Scanner == r.ScannerName && tf.Open && !reported[k.fingerprint] {
+					tf.Open, tf.ClosedAt = false, j.CompletedAt
+				}
+			}
+		}
+	}
+	m.mu.RUnlock()
+
+	findings := make([]TrackedFinding, len(order))
+	for i, k := range order {
+		tf := *tracked[k]
+		end := now
+		if !tf.Open {
+			end = tf.ClosedAt
+		}
+		age := end.Sub(tf.FirstSeen)
+		tf.AgeDays = int(age / day)
+		tf.SLADays = sla[tf.Severity]
+		tf.Overdue = tf.Open && tf.SLADays > 0 && age > time.Duration(tf.SLADays)*day
+		findings[i] = tf
+	}
+	sort.SliceStable(findings, func(i, k int) bool {
+		a, b := findings[i], findings[k]
+		if a.Overdue != b.Overdue {
+			return a.Overdue
+		}
+		if a.Open != b.Open {
+			return a.Open
+		}
+		if ra, rb := types.SeverityRank(a.Severity), types.SeverityRank(b.Severity); ra != rb {
+			return ra < rb
+		}
+		return a.FirstSeen.Before(b.FirstSeen)
+	})
+	return findings
+}
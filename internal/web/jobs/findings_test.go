@@ -0,0 +1,68 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSLA(t *testing.T) {
+	sla, err := ParseSLA(map[string]int{"critical": 7, " High ": 30})
+	require.NoError(t, err)
+	assert.Equal(t, SLA{types.SeverityCritical: 7, types.SeverityHigh: 30}, sla)
+
+	_, err = ParseSLA(map[string]int{"urgent": 1})
+	assert.ErrorContains(t, err, "unknown severity")
+	_, err = ParseSLA(map[string]int{"low": 0})
+	assert.ErrorContains(t, err, "at least 1")
+}
+
+func TestFindings(t *testing.T) {
+	m := newTestManager()
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	finding := func(fingerprint string, sev types.Severity) types.Finding {
+		return types.Finding{Title: fingerprint, Severity: sev, Fingerprint: fingerprint}
+	}
+	add := func(id, host string, daysAgo int, status JobStatus, results ...types.ScanResult) {
+		m.jobs[id] = &Job{ID: id, Target: types.Target{Host: host}, Status: status, CompletedAt: now.Add(-time.Duration(daysAgo) * day), Results: results}
+	}
+
+	add("1", "example.com", 20, StatusCompleted,
+		types.ScanResult{ScannerName: "headers", Findings: []types.Finding{finding("csp", types.SeverityMedium), finding("hsts", types.SeverityHigh)}},
+		types.ScanResult{ScannerName: "ssl", Findings: []types.Finding{finding("weak-tls", types.SeverityHigh)}})
+	// The second scan no longer reports hsts, and its ssl scanner failed,
+	// which leaves weak-tls open.
+	add("2", "example.com", 5, StatusCompleted,
+		types.ScanResult{ScannerName: "headers", Findings: []types.Finding{finding("csp", types.SeverityMedium)}},
+		types.ScanResult{ScannerName: "ssl", Error: "connection refused"})
+	add("3", "other.test", 2, StatusCompleted,
+		types.ScanResult{ScannerName: "headers", Findings: []types.Finding{finding("csp", types.SeverityMedium)}})
+	add("4", "other.test", 1, StatusFailed,
+		types.ScanResult{ScannerName: "headers"})
+
+	findings := m.Findings(SLA{types.SeverityHigh: 14, types.SeverityMedium: 30}, now)
+	require.Len(t, findings, 4)
+
+	assert.Equal(t, "weak-tls", findings[0].Fingerprint)
+	assert.True(t, findings[0].Open)
+	assert.True(t, findings[0].Overdue, "open 20 days against a 14-day SLA")
+	assert.Equal(t, 20, findings[0].AgeDays)
+
+	assert.Equal(t, "csp", findings[1].Fingerprint)
+	assert.Equal(t, "example.com", findings[1].Target)
+	assert.False(t, findings[1].Overdue)
+	assert.Equal(t, 2, findings[1].Scans)
+	assert.Equal(t, 30, findings[1].SLADays)
+
+	assert.Equal(t, "other.test", findings[2].Target, "targets are tracked separately")
+	assert.Equal(t, 1, findings[2].Scans, "failed scans are left out")
+
+	assert.Equal(t, "hsts", findings[3].Fingerprint)
+	assert.False(t, findings[3].Open)
+	assert.False(t, findings[3].Overdue)
+	assert.Equal(t, now.Add(-5*day), findings[3].ClosedAt)
+	assert.Equal(t, 15, findings[3].AgeDays)
+}
@@ -1,6 +1,10 @@
 package jobs
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/buemura/hunter/internal/scanner"
@@ -13,6 +17,7 @@ type JobStatus string
 const (
 	StatusPending   JobStatus = "pending"
 	StatusRunning   JobStatus = "running"
+	StatusPaused    JobStatus = "paused"
 	StatusCompleted JobStatus = "completed"
 	StatusFailed    JobStatus = "failed"
 )
@@ -37,6 +42,116 @@ type Job struct {
 	StartedAt   time.Time          `json:"started_at,omitempty"`
 	CompletedAt time.Time          `json:"completed_at,omitempty"`
 	Progress    JobProgress        `json:"progress"`
+	// Engagement is the engagement the scan is run under, from its options.
+	Engagement types.Engagement `json:"engagement,omitzero"`
+	// Agent names the agent the job was dispatched to; empty for jobs run
+	// by the server itself.
+	Agent string `json:"agent,omitempty"`
+	// Spec describes the scan to the agent that runs it.
+	Spec json.RawMessage `json:"-"`
+	// RerunOf is the ID of the job this one repeats; see Manager.Rerun.
+	RerunOf string `json:"rerun_of,omitempty"`
+	// Acknowledged records that the user vouched for permission to scan
+	// Target when it was created (i_am_authorized), so reruns do not check
+	// it against authorized_targets.
+	Acknowledged bool `json:"-"`
+
+	// Logs holds the job's log lines, served separately from the job by
+	// the logs endpoint. At most maxLogEntries are kept.
+	Logs []scanner.LogEntry `json:"-"`
+	// LogsDropped counts the log lines discarded once Logs was full.
+	LogsDropped int `json:"-"`
+
+	// gate pauses the job's scan; see Manager.Pause.
+	gate *scanner.Gate
+	// cancel stops the job's scan; see Manager.Cancel.
+	cancel context.CancelFunc
+}
+
+// maxLogEntries bounds the log lines kept per job; later lines are counted
+// but discarded.
+const maxLogEntries = 5000
+
+// appendLog records a log line. Callers must hold the manager's lock.
+func (j *Job) appendLog(entry scanner.LogEntry) {
+	if len(j.Logs) >= maxLogEntries {
+		j.LogsDropped++
+		return
+	}
+	j.Logs = append(j.Logs, entry)
+}
+
+// Finding returns the finding with the given fingerprint and the result it
+// belongs to.
+func (j *Job) Finding(fingerprint string) (types.ScanResult, types.Finding, bool) {
+	for _, r := range j.Results {
+		for _, f := range r.Findings {
+			if f.Fingerprint == fingerprint {
+				return r, f, true
+			}
+		}
+	}
+	return types.ScanResult{}, types.Finding{}, false
+}
+
+// TargetName is how the job's target is shown: its URL, or its host when
+// it has none.
+func (j *Job) TargetName() string {
+	if j.Target.URL != "" {
+		return j.Target.URL
+	}
+	return j.Target.Host
+}
+
+// Filter selects jobs by status and target. Zero fields match every job.
+type Filter struct {
+	Status JobStatus
+	// Target matches jobs whose TargetName contains it, ignoring case.
+	Target string
+}
+
+// Validate checks that the filter's status is one jobs can have.
+func (f Filter) Validate() error {
+	switch f.Status {
+	case "", StatusPending, StatusRunning, StatusPaused, StatusCompleted, StatusFailed:
+		return nil
+	}
+	return fmt.Errorf("invalid status %q (available: pending, running, paused, completed, failed)", f.Status)
+}
+
+// Apply returns the jobs of list the filter matches, in order.
+func (f Filter) Apply(list []*Job) []*Job {
+	if f.Status == "" && f.Target == "" {
+		return list
+	}
+	target := strings.ToLower(f.Target)
+	var matched []*Job
+	for _, j := range list {
+		if f.Status != "" && j.Status != f.Status {
+			continue
+		}
+		if !strings.Contains(strings.ToLower(j.TargetName()), target) {
+			continue
+		}
+		matched = append(matched, j)
+	}
+	return matched
+}
+
+// Incomplete reports whether the job failed, or completed with scanners
+// that failed or were stopped with partial results.
+func (j *Job) Incomplete() bool {
+	switch j.Status {
+	case StatusFailed:
+		return true
+	case StatusCompleted:
+		for _, r := range j.Results {
+			if r.Error != "" || r.Metadata["partial"] == "true" {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // FindingCount returns the total number of findings across all results.
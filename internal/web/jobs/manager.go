@@ -23,13 +23,20 @@ func defaultNewUUID() string {
 type Manager struct {
 	mu     sync.RWMutex
 	jobs   map[string]*Job
+	agents map[string]*Agent
 	runner *scanner.Runner
+
+	maxJobs int
+	maxAge  time.Duration
+	// authorize, when set, refuses reruns of targets no longer authorized.
+	authorize func(types.Target) error
 }
 
 // NewManager creates a new job manager backed by the given scanner runner.
 func NewManager(runner *scanner.Runner) *Manager {
 	return &Manager{
 		jobs:   make(map[string]*Job),
+		agents: make(map[string]*Agent),
 		runner: runner,
 	}
 }
@@ -39,19 +46,64 @@ func (m *Manager) Create(target types.Target, scanners []string, opts scanner.Op
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	job := &Job{
-		ID:        newUUID(),
-		Target:    target,
-		Scanners:  scanners,
-		Options:   opts,
-		Status:    StatusPending,
-		CreatedAt: time.Now(),
+	job := newJob(target, scanners, opts)
+	m.jobs[job.ID] = job
+	m.prune()
+	return job
+}
+
+func newJob(target types.Target, scanners []string, opts scanner.Options) *Job {
+	return &Job{
+		ID:         newUUID(),
+		Target:     target,
+		Scanners:   scanners,
+		Options:    opts,
+		Status:     StatusPending,
+		Engagement: opts.Engagement,
+		CreatedAt:  time.Now(),
 		Progress: JobProgress{
 			TotalScanners: len(scanners),
 		},
+		gate: scanner.NewGate(),
+	}
+}
+
+// SetRetention limits the finished jobs kept in memory to the newest maxJobs
+// and to those completed within maxAge. Zero disables a limit. Running and
+// pending jobs are never dropped. Limits are enforced now and whenever a new
+// job is created.
+func (m *Manager) SetRetention(maxJobs int, maxAge time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.maxJobs = maxJobs
+	m.maxAge = maxAge
+	m.prune()
+}
+
+// prune drops finished jobs outside the retention limits. Callers must hold
+// m.mu for writing.
+func (m *Manager) prune() {
+	if m.maxJobs <= 0 && m.maxAge <= 0 {
+		return
+	}
+
+	var finished []*Job
+	for _, j := range m.jobs {
+		if j.Status == StatusCompleted || j.Status == StatusFailed {
+			finished = append(finished, j)
+		}
+	}
+	sort.Slice(finished, func(i, k int) bool {
+		return finished[i].CompletedAt.After(finished[k].CompletedAt)
+	})
+
+	now := time.Now()
+	for i, j := range finished {
+		if (m.maxJobs > 0 && i >= m.maxJobs) || (m.maxAge > 0 && now.Sub(j.CompletedAt) > m.maxAge) {
+			delete(m.jobs, j.ID)
+		}
 	}
-	m.jobs[job.ID] = job
-	return job
 }
 
 // Start launches the scan job in a background goroutine.
@@ -62,15 +114,21 @@ func (m *Manager) Start(jobID string) error {
 		m.mu.Unlock()
 		return fmt.Errorf("job %q not found", jobID)
 	}
+	if job.Agent != "" {
+		m.mu.Unlock()
+		return fmt.Errorf("job %q runs on agent %q", jobID, job.Agent)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
 	job.Status = StatusRunning
 	job.StartedAt = time.Now()
+	job.cancel = cancel
 	m.mu.Unlock()
 
-	go m.execute(job)
+	go m.execute(ctx, job)
 	return nil
 }
 
-func (m *Manager) execute(job *Job) {
+func (m *Manager) execute(parent context.Context, job *Job) {
 	defer func() {
 		if r := recover(); r != nil {
 			m.mu.Lock()
@@ -81,41 +139,381 @@ func (m *Manager) execute(job *Job) {
 		}
 	}()
 
-	ctx := context.Background()
-	if job.Options.Timeout > 0 {
+	opts := job.Options
+	opts.Gate = job.gate
+	// Outside its window the job is paused, as if by its user, until the
+	// window opens.
+	window := opts.Window
+	opts.Window = nil
+	release := window.Hold(parent, job.gate, func(open bool, next time.Time) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		switch {
+		case open && job.Status == StatusPaused:
+			job.Status = StatusRunning
+			job.appendLog(scanner.LogEntry{Time: time.Now(), Level: scanner.LogInfo, Message: fmt.Sprintf("resumed: the scan window %s opened, until %s", window, next.Format(time.RFC3339))})
+		case !open && job.Status == StatusRunning:
+			job.Status = StatusPaused
+			job.appendLog(scanner.LogEntry{Time: time.Now(), Level: scanner.LogWarn, Message: fmt.Sprintf("paused: outside the scan window %s, until %s", window, next.Format(time.RFC3339))})
+		}
+	})
+	defer release()
+
+	// The job's duration budget is divided between its scanners, weighted
+	// by how long they took in earlier jobs. Without one, each scanner is
+	// allowed about one connection timeout. Time spent paused does not
+	// count.
+	if opts.MaxDuration <= 0 && opts.Timeout > 0 {
+		opts.MaxDuration = opts.Timeout * time.Duration(len(job.Scanners)+1)
+	}
+	ctx := parent
+	if opts.MaxDuration > 0 {
+		if opts.Durations == nil {
+			opts.Durations = m.observedDurations()
+		}
+		opts.TimeBudget = scanner.NewTimeBudget(opts.MaxDuration, job.Scanners, opts.Durations, 1, job.gate)
 		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, job.Options.Timeout*time.Duration(len(job.Scanners)+1))
+		ctx, cancel = job.gate.WithTimeout(ctx, opts.MaxDuration)
 		defer cancel()
 	}
 
-	for _, name := range job.Scanners {
+	record := func(entry scanner.LogEntry) {
 		m.mu.Lock()
-		job.Progress.CurrentScanner = name
+		job.appendLog(entry)
 		m.mu.Unlock()
+	}
+	// Scanners run one at a time, so requests belong to the current one.
+	opts.Transport = scanner.NewLoggingTransport(opts.Transport, func(format string, args ...interface{}) {
+		m.mu.RLock()
+		name := job.Progress.CurrentScanner
+		m.mu.RUnlock()
+		record(scanner.LogEntry{Time: time.Now(), Scanner: name, Level: scanner.LogInfo, Message: fmt.Sprintf(format, args...)})
+	})
+	// Repeated requests are answered from the cache and not logged again.
+	opts.Transport = scanner.NewCacheTransport(opts.Transport)
 
-		result, err := m.runner.RunOne(ctx, name, job.Target, job.Options)
-
+	runner := m.runner.With(scanner.Hooks{
+		OnScannerStart: func(name string) {
+			m.mu.Lock()
+			job.Progress.CurrentScanner = name
+			m.mu.Unlock()
+			record(scanner.LogEntry{Time: time.Now(), Scanner: name, Level: scanner.LogInfo, Message: "started"})
+		},
+		OnLog: record,
+		OnFound: func(name string, f types.Finding) {
+			record(scanner.LogEntry{Time: time.Now(), Scanner: name, Level: scanner.LogInfo, Message: "found: " + f.Title})
+		},
+		OnScannerComplete: func(result types.ScanResult) {
+			m.mu.Lock()
+			job.Results = append(job.Results, result)
+			job.Progress.CompletedScanners++
+			m.mu.Unlock()
+			record(completionEntry(result))
+		},
+	})
+	// Scanners run after those they depend on, and the plan skips those
+	// the failure policy rules out.
+	opts.Plan = scanner.NewPlan(job.Scanners, opts)
+	// The watchdog's probes are not logged or cached as the scanners'
+	// requests are.
+	watchdogOpts := opts
+	watchdogOpts.Transport, watchdogOpts.Log = job.Options.Transport, record
+	watchdog := scanner.NewWatchdog(job.Target, watchdogOpts)
+	watchdog.Start(ctx)
+	for _, name := range scanner.Order(job.Scanners, opts.DependsOn) {
+		// Results, including failures, are recorded by the hooks.
+		_, _ = runner.RunOne(ctx, name, job.Target, opts)
+	}
+	if result := watchdog.Stop(); result != nil {
 		m.mu.Lock()
-		if err != nil {
-			job.Results = append(job.Results, types.ScanResult{
-				ScannerName: name,
-				Target:      job.Target,
-				Error:       err.Error(),
-			})
-		} else if result != nil {
-			job.Results = append(job.Results, *result)
-		}
-		job.Progress.CompletedScanners++
+		job.Results = append(job.Results, *result)
 		m.mu.Unlock()
 	}
 
 	m.mu.Lock()
 	job.Status = StatusCompleted
+	if parent.Err() != nil {
+		job.Status = StatusFailed
+		job.Error = "canceled"
+	}
 	job.CompletedAt = time.Now()
 	job.Progress.CurrentScanner = ""
 	m.mu.Unlock()
 }
 
+// SetAuthorizer makes Rerun check targets with authorize, which returns an
+// error for those the current configuration does not allow to be scanned.
+// Jobs whose user vouched for their target, as Acknowledged records, are
+// rerun regardless.
+func (m *Manager) SetAuthorizer(authorize func(types.Target) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.authorize = authorize
+}
+
+// Acknowledge records that the user vouched for permission to scan the
+// target of the job with the given ID, so Rerun does not check it again.
+func (m *Manager) Acknowledge(jobID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if job, ok := m.jobs[jobID]; ok {
+		job.Acknowledged = true
+	}
+}
+
+// Rerun repeats a finished job as a new one with the same target, scanners,
+// and options, and starts it. The rerun probes and crawls the target anew
+// rather than reusing what the first run found. A job that ran on an agent
+// is dispatched to the same agent again.
+func (m *Manager) Rerun(jobID string) (*Job, error) {
+	m.mu.RLock()
+	job, ok := m.jobs[jobID]
+	if !ok {
+		m.mu.RUnlock()
+		return nil, fmt.Errorf("job %q not found", jobID)
+	}
+	if job.Status != StatusCompleted && job.Status != StatusFailed {
+		m.mu.RUnlock()
+		return nil, fmt.Errorf("job %q has not finished", jobID)
+	}
+	target, scanners, opts := job.Target, job.Scanners, rerunOptions(job.Options)
+	agent, engagement, spec := job.Agent, job.Engagement, job.Spec
+	acknowledged, authorize := job.Acknowledged, m.authorize
+	m.mu.RUnlock()
+
+	if authorize != nil && !acknowledged {
+		if err := authorize(target); err != nil {
+			return nil, err
+		}
+	}
+
+	if agent != "" {
+		rerun, err := m.Dispatch(agent, target, scanners, engagement, spec)
+		if err != nil {
+			return nil, err
+		}
+		m.mu.Lock()
+		rerun.RerunOf = jobID
+		rerun.Acknowledged = acknowledged
+		m.mu.Unlock()
+		return rerun, nil
+	}
+
+	m.mu.Lock()
+	rerun := newJob(target, scanners, opts)
+	rerun.RerunOf = jobID
+	rerun.Acknowledged = acknowledged
+	m.jobs[rerun.ID] = rerun
+	m.prune()
+	m.mu.Unlock()
+	if err := m.Start(rerun.ID); err != nil {
+		return nil, err
+	}
+	return rerun, nil
+}
+
+// rerunOptions returns opts with the state a run memoizes replaced by
+// fresh state: its pre-flight probe, crawl, and time budget.
+func rerunOptions(opts scanner.Options) scanner.Options {
+	if opts.Preflight != nil {
+		opts.Preflight = scanner.NewPreflight()
+	}
+	opts.Crawler = opts.Crawler.Fresh()
+	opts.TimeBudget = nil
+	return opts
+}
+
+// RerunIncomplete reruns every job that is Incomplete and not yet rerun,
+// oldest first, and returns the new jobs. Jobs that cannot be rerun, such as
+// those of agents no longer connected, are skipped and their errors
+// returned.
+func (m *Manager) RerunIncomplete() ([]*Job, []error) {
+	m.mu.RLock()
+	rerun := map[string]bool{}
+	for _, j := range m.jobs {
+		if j.RerunOf != "" {
+			rerun[j.RerunOf] = true
+		}
+	}
+	var incomplete []*Job
+	for _, j := range m.jobs {
+		if j.Incomplete() && !rerun[j.ID] {
+			incomplete = append(incomplete, j)
+		}
+	}
+	m.mu.RUnlock()
+	sort.Slice(incomplete, func(i, k int) bool {
+		return incomplete[i].CreatedAt.Before(incomplete[k].CreatedAt)
+	})
+
+	var reruns []*Job
+	var errs []error
+	for _, j := range incomplete {
+		job, err := m.Rerun(j.ID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		reruns = append(reruns, job)
+	}
+	return reruns, errs
+}
+
+// Cancel stops a running or paused job. Scanners still to run are skipped,
+// and the job fails as canceled once the current one returns.
+func (m *Manager) Cancel(jobID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[jobID]
+	if !ok {
+		return fmt.Errorf("job %q not found", jobID)
+	}
+	if job.cancel == nil || (job.Status != StatusRunning && job.Status != StatusPaused) {
+		return fmt.Errorf("job %q is %s, not running", jobID, job.Status)
+	}
+	job.cancel()
+	job.gate.Resume()
+	return nil
+}
+
+// observedDurations returns how long each scanner took on average in the
+// finished jobs still held.
+func (m *Manager) observedDurations() map[string]time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var results []types.ScanResult
+	for _, j := range m.jobs {
+		if j.Status == StatusCompleted {
+			results = append(results, j.Results...)
+		}
+	}
+	return scanner.ObservedDurations(results)
+}
+
+// Pause halts a running job: no further scanner starts and streaming
+// scanners stop before their next port, path, or request until Resume.
+// Requests already in flight complete.
+func (m *Manager) Pause(jobID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[jobID]
+	if !ok {
+		return fmt.Errorf("job %q not found", jobID)
+	}
+	if job.Agent != "" {
+		return fmt.Errorf("job %q runs on agent %q", jobID, job.Agent)
+	}
+	if job.Status != StatusRunning {
+		return fmt.Errorf("job %q is %s, not running", jobID, job.Status)
+	}
+	job.gate.Pause()
+	job.Status = StatusPaused
+	job.appendLog(scanner.LogEntry{Time: time.Now(), Level: scanner.LogInfo, Message: "paused"})
+	return nil
+}
+
+// Resume continues a paused job.
+func (m *Manager) Resume(jobID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[jobID]
+	if !ok {
+		return fmt.Errorf("job %q not found", jobID)
+	}
+	if job.Agent != "" {
+		return fmt.Errorf("job %q runs on agent %q", jobID, job.Agent)
+	}
+	if job.Status != StatusPaused {
+		return fmt.Errorf("job %q is %s, not paused", jobID, job.Status)
+	}
+	job.gate.Resume()
+	job.Status = StatusRunning
+	job.appendLog(scanner.LogEntry{Time: time.Now(), Level: scanner.LogInfo, Message: "resumed"})
+	return nil
+}
+
+// completionEntry is the log line recording how a scanner finished.
+func completionEntry(result types.ScanResult) scanner.LogEntry {
+	entry := scanner.LogEntry{Time: time.Now(), Scanner: result.ScannerName, Level: scanner.LogInfo}
+	switch {
+	case result.Error != "":
+		entry.Level = scanner.LogError
+		entry.Message = "failed: " + result.Error
+	case !result.StartedAt.IsZero() && !result.CompletedAt.IsZero():
+		entry.Message = fmt.Sprintf("finished in %s with %d findings", result.CompletedAt.Sub(result.StartedAt).Round(time.Millisecond), len(result.Findings))
+	default:
+		entry.Message = fmt.Sprintf("finished with %d findings", len(result.Findings))
+	}
+	return entry
+}
+
+// Logs returns a copy of a job's log lines and how many were dropped once
+// the log was full.
+func (m *Manager) Logs(jobID string) ([]scanner.LogEntry, int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	job, ok := m.jobs[jobID]
+	if !ok {
+		return nil, 0, fmt.Errorf("job %q not found", jobID)
+	}
+	logs := make([]scanner.LogEntry, len(job.Logs))
+	copy(logs, job.Logs)
+	return logs, job.LogsDropped, nil
+}
+
+// Artifacts returns the raw request/response artifacts attached to a
+// finding, addressed by the index of its result in the job and its index
+// among the result's findings.
+func (m *Manager) Artifacts(jobID string, result, finding int) ([]types.Artifact, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	job, ok := m.jobs[jobID]
+	if !ok {
+		return nil, fmt.Errorf("job %q not found", jobID)
+	}
+	if result < 0 || result >= len(job.Results) {
+		return nil, fmt.Errorf("job %q has no result %d", jobID, result)
+	}
+	findings := job.Results[result].Findings
+	if finding < 0 || finding >= len(findings) {
+		return nil, fmt.Errorf("result %d of job %q has no finding %d", result, jobID, finding)
+	}
+	return findings[finding].Artifacts, nil
+}
+
+// Verify replays the probe behind the job's finding with the given
+// fingerprint, using the job's scan options, and reports whether the
+// finding still reproduces.
+func (m *Manager) Verify(ctx context.Context, jobID, fingerprint string) (bool, error) {
+	m.mu.RLock()
+	job, ok := m.jobs[jobID]
+	if !ok {
+		m.mu.RUnlock()
+		return false, fmt.Errorf("job %q not found", jobID)
+	}
+	result, finding, found := job.Finding(fingerprint)
+	opts := job.Options
+	agent := job.Agent
+	m.mu.RUnlock()
+
+	if agent != "" {
+		// The server may not reach what the agent scanned.
+		return false, fmt.Errorf("job %q ran on agent %q", jobID, agent)
+	}
+	if !found {
+		return false, fmt.Errorf("job %q has no finding %q", jobID, fingerprint)
+	}
+	return m.runner.Verify(ctx, result.ScannerName, result.Target, finding, opts)
+}
+
 // Get returns a job by ID.
 func (m *Manager) Get(jobID string) (*Job, error) {
 	m.mu.RLock()
@@ -128,6 +526,22 @@ func (m *Manager) Get(jobID string) (*Job, error) {
 	return job, nil
 }
 
+// Snapshot returns a copy of a job that stays safe to read while the job
+// runs.
+func (m *Manager) Snapshot(jobID string) (Job, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	job, ok := m.jobs[jobID]
+	if !ok {
+		return Job{}, fmt.Errorf("job %q not found", jobID)
+	}
+	snapshot := *job
+	snapshot.Results = append([]types.ScanResult(nil), job.Results...)
+	snapshot.Logs = append([]scanner.LogEntry(nil), job.Logs...)
+	return snapshot, nil
+}
+
 // List returns all jobs sorted by CreatedAt descending.
 func (m *Manager) List() []*Job {
 	m.mu.RLock()
@@ -3,6 +3,9 @@ package jobs
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -75,6 +78,40 @@ func TestStartAndComplete(t *testing.T) {
 	assert.False(t, job.CompletedAt.IsZero())
 }
 
+// blockingScanner runs until its context is done.
+type blockingScanner struct{ name string }
+
+func (b *blockingScanner) Name() string        { return b.name }
+func (b *blockingScanner) Description() string { return "blocking" }
+func (b *blockingScanner) Run(ctx context.Context, target types.Target, _ scanner.Options) (*types.ScanResult, error) {
+	<-ctx.Done()
+	return &types.ScanResult{ScannerName: b.name, Target: target}, nil
+}
+
+func TestMaxDurationStopsOverrunningScanners(t *testing.T) {
+	reg := scanner.NewRegistry()
+	reg.Register(&blockingScanner{name: "dirs"})
+	reg.Register(&mockScanner{name: "headers"})
+	m := NewManager(scanner.NewRunner(reg))
+
+	opts := scanner.DefaultOptions()
+	opts.Timeout = time.Hour
+	opts.MaxDuration = 100 * time.Millisecond
+	job := m.Create(types.Target{Host: "example.com", Scheme: "https"}, []string{"dirs", "headers"}, opts)
+	require.NoError(t, m.Start(job.ID))
+
+	assert.Eventually(t, func() bool {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+		return job.Status == StatusCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.Len(t, job.Results, 2)
+	assert.Equal(t, "true", job.Results[0].Metadata["partial"])
+	assert.Equal(t, "headers", job.Results[1].ScannerName, "time left over still goes to later scanners")
+	assert.Empty(t, job.Results[1].Metadata["partial"])
+}
+
 func TestProgressUpdates(t *testing.T) {
 	m := newTestManager("a", "b", "c")
 	target := types.Target{Host: "example.com", Scheme: "https"}
@@ -134,6 +171,42 @@ func TestList_SortedByCreatedAtDesc(t *testing.T) {
 	assert.Equal(t, j1.ID, list[1].ID)
 }
 
+func TestSetRetention_DropsOldestFinishedJobs(t *testing.T) {
+	m := newTestManager("headers")
+	target := types.Target{Host: "example.com", Scheme: "https"}
+
+	counter := 0
+	origUUID := newUUID
+	newUUID = func() string {
+		counter++
+		return fmt.Sprintf("job-%d", counter)
+	}
+	defer func() { newUUID = origUUID }()
+
+	now := time.Now()
+	old := m.Create(target, []string{"headers"}, scanner.DefaultOptions())
+	old.Status, old.CompletedAt = StatusCompleted, now.Add(-2*time.Hour)
+	recent := m.Create(target, []string{"headers"}, scanner.DefaultOptions())
+	recent.Status, recent.CompletedAt = StatusFailed, now.Add(-time.Minute)
+	newest := m.Create(target, []string{"headers"}, scanner.DefaultOptions())
+	newest.Status, newest.CompletedAt = StatusCompleted, now
+	running := m.Create(target, []string{"headers"}, scanner.DefaultOptions())
+	running.Status = StatusRunning
+
+	m.SetRetention(0, time.Hour)
+	_, err := m.Get(old.ID)
+	assert.Error(t, err, "jobs older than max age are dropped")
+	assert.Len(t, m.List(), 3)
+
+	m.SetRetention(1, 0)
+	_, err = m.Get(newest.ID)
+	assert.NoError(t, err)
+	_, err = m.Get(recent.ID)
+	assert.Error(t, err, "only the newest finished job is kept")
+	_, err = m.Get(running.ID)
+	assert.NoError(t, err, "running jobs are never dropped")
+}
+
 func TestDelete_RemovesJob(t *testing.T) {
 	m := newTestManager("headers")
 	target := types.Target{Host: "example.com", Scheme: "https"}
@@ -169,3 +242,334 @@ func TestFindingCount(t *testing.T) {
 	}
 	assert.Equal(t, 3, job.FindingCount())
 }
+
+// requestScanner sends one request to url through the scan's transport and
+// logs a skipped check.
+type requestScanner struct {
+	url string
+}
+
+func (s *requestScanner) Name() string        { return "probe" }
+func (s *requestScanner) Description() string { return "requests url" }
+func (s *requestScanner) Run(ctx context.Context, target types.Target, opts scanner.Options) (*types.ScanResult, error) {
+	opts.Logf(s.Name(), scanner.LogWarn, "redirect check skipped")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := (&http.Client{Transport: opts.HTTPTransport()}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return &types.ScanResult{ScannerName: s.Name(), Target: target}, nil
+}
+
+func TestJobLogs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer srv.Close()
+
+	reg := scanner.NewRegistry()
+	reg.Register(&requestScanner{url: srv.URL + "/admin"})
+	m := NewManager(scanner.NewRunner(reg))
+
+	job := m.Create(types.Target{Host: "example.com"}, []string{"probe", "missing"}, scanner.DefaultOptions())
+	require.NoError(t, m.Start(job.ID))
+	assert.Eventually(t, func() bool {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+		return job.Status == StatusCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+
+	logs, dropped, err := m.Logs(job.ID)
+	require.NoError(t, err)
+	assert.Zero(t, dropped)
+
+	var lines []string
+	for _, e := range logs {
+		lines = append(lines, fmt.Sprintf("%s %s %s", e.Level, e.Scanner, e.Message))
+	}
+	require.Len(t, lines, 5)
+	assert.Equal(t, "info probe started", lines[0])
+	assert.Equal(t, "warn probe redirect check skipped", lines[1])
+	assert.Contains(t, lines[2], "info probe GET "+srv.URL+"/admin → 418")
+	assert.Equal(t, "info probe finished with 0 findings", lines[3])
+	assert.Contains(t, lines[4], "error missing failed:")
+
+	_, _, err = m.Logs("nonexistent")
+	assert.Error(t, err)
+}
+
+func TestStart_FailurePolicy(t *testing.T) {
+	m := newTestManager("port", "headers", "vuln")
+	opts := scanner.DefaultOptions()
+	opts.OnFailure = scanner.FailureSkipDependents
+	opts.DependsOn = map[string][]string{"vuln": {"missing"}, "missing": {"port"}}
+
+	// "missing" is not registered, so it fails, and vuln depends on it.
+	job := m.Create(types.Target{Host: "example.com"}, []string{"vuln", "missing", "headers", "port"}, opts)
+	require.NoError(t, m.Start(job.ID))
+	require.Eventually(t, func() bool {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+		return job.Status == StatusCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+
+	var ran []string
+	errs := map[string]string{}
+	for _, r := range job.Results {
+		ran = append(ran, r.ScannerName)
+		errs[r.ScannerName] = r.Error
+	}
+	assert.Equal(t, []string{"headers", "port", "missing", "vuln"}, ran, "scanners run after their dependencies")
+	assert.Contains(t, errs["vuln"], "skipped: depends on missing")
+	assert.Empty(t, errs["headers"])
+	assert.True(t, job.Incomplete())
+}
+
+func TestStart_WatchdogAbortsWhenTheTargetIsDown(t *testing.T) {
+	// A port nothing listens on any more stands in for a target gone down.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	reg := scanner.NewRegistry()
+	reg.Register(&mockScanner{name: "first", delay: 200 * time.Millisecond})
+	reg.Register(&mockScanner{name: "second"})
+	m := NewManager(scanner.NewRunner(reg))
+	opts := scanner.DefaultOptions()
+	opts.Watchdog = scanner.WatchdogSettings{Interval: 5 * time.Millisecond}
+
+	job := m.Create(types.Target{Host: "127.0.0.1", Ports: []int{port}}, []string{"first", "second"}, opts)
+	require.NoError(t, m.Start(job.ID))
+	require.Eventually(t, func() bool {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+		return job.Status == StatusCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.Len(t, job.Results, 3)
+	assert.Equal(t, "skipped: the target became unresponsive", job.Results[1].Error)
+	assert.Equal(t, scanner.WatchdogName, job.Results[2].ScannerName)
+	assert.Equal(t, "Target became unresponsive", job.Results[2].Findings[0].Title)
+}
+
+func TestStart_PausedOutsideTheWindow(t *testing.T) {
+	now := time.Now().UTC()
+	from, to := now.Add(time.Hour), now.Add(2*time.Hour)
+	window, err := scanner.ParseWindow(fmt.Sprintf("%02d:%02d-%02d:%02d UTC", from.Hour(), from.Minute(), to.Hour(), to.Minute()))
+	require.NoError(t, err)
+
+	m := newTestManager("port")
+	opts := scanner.DefaultOptions()
+	opts.Window = window
+	job := m.Create(types.Target{Host: "example.com"}, []string{"port"}, opts)
+	require.NoError(t, m.Start(job.ID))
+	require.Eventually(t, func() bool {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+		return job.Status == StatusPaused
+	}, 5*time.Second, 10*time.Millisecond)
+
+	m.mu.RLock()
+	assert.Empty(t, job.Results, "no scanner runs outside the window")
+	assert.Contains(t, job.Logs[len(job.Logs)-1].Message, "outside the scan window")
+	m.mu.RUnlock()
+
+	require.NoError(t, m.Resume(job.ID), "the user may resume it anyway")
+	require.Eventually(t, func() bool {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+		return job.Status == StatusCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestJobLogsAreBounded(t *testing.T) {
+	job := &Job{}
+	for i := 0; i < maxLogEntries+3; i++ {
+		job.appendLog(scanner.LogEntry{Message: "line"})
+	}
+	assert.Len(t, job.Logs, maxLogEntries)
+	assert.Equal(t, 3, job.LogsDropped)
+}
+
+func TestPauseAndResume(t *testing.T) {
+	reg := scanner.NewRegistry()
+	reg.Register(&mockScanner{name: "first", delay: 100 * time.Millisecond})
+	reg.Register(&mockScanner{name: "second"})
+	m := NewManager(scanner.NewRunner(reg))
+
+	job := m.Create(types.Target{Host: "example.com"}, []string{"first", "second"}, scanner.DefaultOptions())
+	assert.Error(t, m.Pause(job.ID), "a pending job cannot be paused")
+
+	require.NoError(t, m.Start(job.ID))
+	require.Eventually(t, func() bool {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+		return job.Progress.CurrentScanner == "first"
+	}, 5*time.Second, time.Millisecond)
+	require.NoError(t, m.Pause(job.ID))
+	assert.Error(t, m.Pause(job.ID), "already paused")
+
+	// The running scanner finishes; the next one must not start.
+	time.Sleep(300 * time.Millisecond)
+	m.mu.RLock()
+	assert.Equal(t, StatusPaused, job.Status)
+	assert.Equal(t, 1, job.Progress.CompletedScanners)
+	m.mu.RUnlock()
+
+	require.NoError(t, m.Resume(job.ID))
+	assert.Error(t, m.Resume(job.ID), "not paused")
+	assert.Eventually(t, func() bool {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+		return job.Status == StatusCompleted && len(job.Results) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Error(t, m.Pause("nonexistent"))
+	assert.Error(t, m.Resume("nonexistent"))
+}
+
+func TestCancel(t *testing.T) {
+	reg := scanner.NewRegistry()
+	reg.Register(&mockScanner{name: "first", delay: 100 * time.Millisecond})
+	reg.Register(&mockScanner{name: "second"})
+	m := NewManager(scanner.NewRunner(reg))
+
+	job := m.Create(types.Target{Host: "example.com"}, []string{"first", "second"}, scanner.DefaultOptions())
+	assert.Error(t, m.Cancel(job.ID), "a pending job cannot be canceled")
+
+	require.NoError(t, m.Start(job.ID))
+	require.NoError(t, m.Pause(job.ID))
+	require.NoError(t, m.Cancel(job.ID), "a paused job can be canceled")
+	assert.Eventually(t, func() bool {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+		return job.Status == StatusFailed && job.Error == "canceled"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Error(t, m.Cancel("nonexistent"))
+}
+
+func TestRerun(t *testing.T) {
+	m := newTestManager("headers", "port")
+	opts := scanner.DefaultOptions()
+	opts.Concurrency = 3
+	opts.Preflight = scanner.NewPreflight()
+	opts.Crawler = scanner.NewCrawler(2, 10)
+	job := m.Create(types.Target{Host: "example.com"}, []string{"headers", "port"}, opts)
+
+	_, err := m.Rerun(job.ID)
+	assert.ErrorContains(t, err, "has not finished")
+	_, err = m.Rerun("missing")
+	assert.ErrorContains(t, err, "not found")
+
+	require.NoError(t, m.Start(job.ID))
+	require.Eventually(t, func() bool {
+		j, _ := m.Snapshot(job.ID)
+		return j.Status == StatusCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+
+	rerun, err := m.Rerun(job.ID)
+	require.NoError(t, err)
+	assert.NotEqual(t, job.ID, rerun.ID)
+	assert.Equal(t, job.ID, rerun.RerunOf)
+	assert.Equal(t, job.Target, rerun.Target)
+	assert.Equal(t, []string{"headers", "port"}, rerun.Scanners)
+	assert.Equal(t, 3, rerun.Options.Concurrency)
+	assert.NotSame(t, job.Options.Preflight, rerun.Options.Preflight, "a rerun probes the target again")
+	assert.NotSame(t, job.Options.Crawler, rerun.Options.Crawler, "a rerun crawls the target again")
+	assert.NotNil(t, rerun.Options.Crawler)
+	require.Eventually(t, func() bool {
+		j, _ := m.Snapshot(rerun.ID)
+		return j.Status == StatusCompleted && len(j.Results) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestRerun_RechecksAuthorization(t *testing.T) {
+	m := newTestManager("headers")
+	finished := func(acknowledged bool) *Job {
+		job := m.Create(types.Target{Host: "example.com"}, []string{"headers"}, scanner.DefaultOptions())
+		if acknowledged {
+			m.Acknowledge(job.ID)
+		}
+		require.NoError(t, m.Start(job.ID))
+		require.Eventually(t, func() bool {
+			j, _ := m.Snapshot(job.ID)
+			return j.Status == StatusCompleted
+		}, 5*time.Second, 10*time.Millisecond)
+		return job
+	}
+	job, acknowledged := finished(false), finished(true)
+
+	m.SetAuthorizer(func(target types.Target) error {
+		return fmt.Errorf("target %s is not in authorized_targets", target.Host)
+	})
+	_, err := m.Rerun(job.ID)
+	assert.ErrorContains(t, err, "not in authorized_targets")
+
+	rerun, err := m.Rerun(acknowledged.ID)
+	require.NoError(t, err, "a target the user vouched for is rerun")
+	assert.True(t, rerun.Acknowledged)
+}
+
+func TestRerunIncomplete(t *testing.T) {
+	m := newTestManager("headers")
+	done := func(status JobStatus, results ...types.ScanResult) *Job {
+		job := m.Create(types.Target{Host: "example.com"}, []string{"headers"}, scanner.DefaultOptions())
+		m.mu.Lock()
+		job.Status, job.Results, job.CompletedAt = status, results, time.Now()
+		m.mu.Unlock()
+		return job
+	}
+	failed := done(StatusFailed)
+	partial := done(StatusCompleted, types.ScanResult{ScannerName: "headers", Metadata: map[string]string{"partial": "true"}})
+	errored := done(StatusCompleted, types.ScanResult{ScannerName: "headers", Error: "timeout"})
+	done(StatusCompleted, types.ScanResult{ScannerName: "headers"})
+
+	reruns, errs := m.RerunIncomplete()
+	assert.Empty(t, errs)
+	require.Len(t, reruns, 3)
+	var of []string
+	for _, r := range reruns {
+		of = append(of, r.RerunOf)
+	}
+	assert.ElementsMatch(t, []string{failed.ID, partial.ID, errored.ID}, of)
+
+	for _, r := range reruns {
+		require.Eventually(t, func() bool {
+			j, _ := m.Snapshot(r.ID)
+			return j.Status == StatusCompleted
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+	reruns, errs = m.RerunIncomplete()
+	assert.Empty(t, errs)
+	assert.Empty(t, reruns, "scans already rerun are not rerun again")
+}
+
+func TestFilter(t *testing.T) {
+	list := []*Job{
+		{ID: "a", Target: types.Target{Host: "example.com", URL: "https://Example.com/app"}, Status: StatusCompleted},
+		{ID: "b", Target: types.Target{Host: "other.test"}, Status: StatusFailed},
+		{ID: "c", Target: types.Target{Host: "api.example.com"}, Status: StatusFailed},
+	}
+	ids := func(jobs []*Job) []string {
+		var out []string
+		for _, j := range jobs {
+			out = append(out, j.ID)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"a", "b", "c"}, ids(Filter{}.Apply(list)))
+	assert.Equal(t, []string{"b", "c"}, ids(Filter{Status: StatusFailed}.Apply(list)))
+	assert.Equal(t, []string{"a", "c"}, ids(Filter{Target: "EXAMPLE"}.Apply(list)))
+	assert.Equal(t, []string{"c"}, ids(Filter{Status: StatusFailed, Target: "example"}.Apply(list)))
+	assert.Empty(t, Filter{Target: "nowhere"}.Apply(list))
+
+	assert.NoError(t, Filter{Status: StatusPaused}.Validate())
+	assert.Error(t, Filter{Status: "done"}.Validate())
+}
@@ -0,0 +1,186 @@
+package pages
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/buemura/hunter/internal/web/api"
+	"github.com/buemura/hunter/internal/web/templates"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/go-chi/chi/v5"
+)
+
+// The handlers in this file take the standard form posts of the pages, so
+// every action works without JavaScript; app.js enhances some of them in
+// place. Each responds with a redirect to the page showing the outcome, or
+// renders the error.
+
+// ErrorData is the template data for the error page.
+type ErrorData struct {
+	Title   string
+	Message string
+	// Back links to the page to return to.
+	Back string
+}
+
+// CreateScan starts the scan the scan form describes, as POST /api/v1/scans
+// does, and redirects to it. Invalid forms are shown again with the error.
+func (h *PageHandlers) CreateScan(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.renderIndex(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	req := &api.CreateScanRequest{
+		Target:   strings.TrimSpace(r.PostForm.Get("target")),
+		Scanners: r.PostForm["scanners"],
+		Timeout:  r.PostForm.Get("timeout"),
+		Agent:    r.PostForm.Get("agent"),
+		Window:   strings.TrimSpace(r.PostForm.Get("window")),
+		Engagement: types.Engagement{
+			Client:        strings.TrimSpace(r.PostForm.Get("engagement_client")),
+			ID:            strings.TrimSpace(r.PostForm.Get("engagement_id")),
+			Tester:        strings.TrimSpace(r.PostForm.Get("engagement_tester")),
+			Authorization: strings.TrimSpace(r.PostForm.Get("engagement_authorization")),
+			Notes:         strings.TrimSpace(r.PostForm.Get("engagement_notes")),
+		},
+		IAmAuthorized: r.PostForm.Get("i_am_authorized") != "",
+	}
+	if c := r.PostForm.Get("concurrency"); c != "" {
+		n, err := strconv.Atoi(c)
+		if err != nil {
+			h.renderIndex(w, r, http.StatusBadRequest, "concurrency must be a number")
+			return
+		}
+		req.Concurrency = n
+	}
+	if len(req.Scanners) == 0 {
+		h.renderIndex(w, r, http.StatusBadRequest, "Please select at least one scanner.")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.renderIndex(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	job, err := h.API.StartScan(req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		var scanErr *api.ScanError
+		if errors.As(err, &scanErr) {
+			status = scanErr.Status
+		}
+		h.renderIndex(w, r, status, err.Error())
+		return
+	}
+	h.redirect(w, r, "/scans/"+job.ID)
+}
+
+// DeleteScan deletes a scan and redirects to the scan history.
+func (h *PageHandlers) DeleteScan(w http.ResponseWriter, r *http.Request) {
+	if err := h.manager.Delete(chi.URLParam(r, "id")); err != nil {
+		renderError(w, http.StatusNotFound, "Scan not found", err.Error(), "/scans")
+		return
+	}
+	h.redirect(w, r, "/scans")
+}
+
+// PauseScan pauses a running scan.
+func (h *PageHandlers) PauseScan(w http.ResponseWriter, r *http.Request) {
+	h.control(w, r, "Could not pause the scan", h.manager.Pause)
+}
+
+// ResumeScan resumes a paused scan.
+func (h *PageHandlers) ResumeScan(w http.ResponseWriter, r *http.Request) {
+	h.control(w, r, "Could not resume the scan", h.manager.Resume)
+}
+
+// CancelScan cancels a running or paused scan.
+func (h *PageHandlers) CancelScan(w http.ResponseWriter, r *http.Request) {
+	h.control(w, r, "Could not cancel the scan", h.manager.Cancel)
+}
+
+// control applies pause, resume, or cancel to the scan in the URL and
+// redirects back to it.
+func (h *PageHandlers) control(w http.ResponseWriter, r *http.Request, title string, apply func(jobID string) error) {
+	id := chi.URLParam(r, "id")
+	if _, err := h.manager.Get(id); err != nil {
+		renderError(w, http.StatusNotFound, "Scan not found", err.Error(), "/scans")
+		return
+	}
+	if err := apply(id); err != nil {
+		renderError(w, http.StatusConflict, title, err.Error(), "/scans/"+id)
+		return
+	}
+	h.redirect(w, r, "/scans/"+id)
+}
+
+// RerunScan starts a new scan with the settings of a finished one and
+// redirects to it.
+func (h *PageHandlers) RerunScan(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if _, err := h.manager.Get(id); err != nil {
+		renderError(w, http.StatusNotFound, "Scan not found", err.Error(), "/scans")
+		return
+	}
+	job, err := h.manager.Rerun(id)
+	if err != nil {
+		renderError(w, http.StatusConflict, "Could not re-run the scan", err.Error(), "/scans/"+id)
+		return
+	}
+	h.redirect(w, r, "/scans/"+job.ID)
+}
+
+// RerunScans reruns every failed or partial scan and redirects to the scan
+// history, or lists the scans that could not be rerun.
+func (h *PageHandlers) RerunScans(w http.ResponseWriter, r *http.Request) {
+	_, errs := h.manager.RerunIncomplete()
+	if len(errs) > 0 {
+		renderError(w, http.StatusConflict, "Some scans could not be re-run", errors.Join(errs...).Error(), "/scans")
+		return
+	}
+	h.redirect(w, r, "/scans")
+}
+
+// VerifyFinding replays the probe behind a finding and redirects back to its
+// scan, which shows the outcome next to the finding.
+func (h *PageHandlers) VerifyFinding(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	fingerprint := chi.URLParam(r, "fingerprint")
+	job, err := h.manager.Get(id)
+	if err != nil {
+		renderError(w, http.StatusNotFound, "Scan not found", err.Error(), "/scans")
+		return
+	}
+	if _, _, ok := job.Finding(fingerprint); !ok {
+		renderError(w, http.StatusNotFound, "Finding not found", "The scan has no finding "+fingerprint+".", "/scans/"+id)
+		return
+	}
+
+	q := url.Values{"verified": {fingerprint}}
+	reproduces, err := h.manager.Verify(r.Context(), id, fingerprint)
+	switch {
+	case err != nil:
+		q.Set("verify_error", err.Error())
+	case reproduces:
+		q.Set("reproduces", "true")
+	default:
+		q.Set("reproduces", "false")
+	}
+	h.redirect(w, r, "/scans/"+id+"?"+q.Encode()+"#finding-"+fingerprint)
+}
+
+// redirect sends the browser to an application path with 303 See Other, so
+// reloading the page it lands on does not repeat the post.
+func (h *PageHandlers) redirect(w http.ResponseWriter, r *http.Request, path string) {
+	http.Redirect(w, r, templates.URL(path), http.StatusSeeOther)
+}
+
+// renderError renders the error page with the given status.
+func renderError(w http.ResponseWriter, status int, title, message, back string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	templates.RenderPage(w, "error.html", ErrorData{Title: title, Message: message, Back: back})
+}
@@ -0,0 +1,150 @@
+package pages_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/web/api"
+	"github.com/buemura/hunter/internal/web/jobs"
+	"github.com/buemura/hunter/internal/web/pages"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/go-chi/chi/v5"
+)
+
+// newActionRouter routes the form posts of the pages, as the server does.
+func newActionRouter() (*chi.Mux, *jobs.Manager) {
+	reg := newTestRegistry()
+	mgr := newTestManager(reg)
+	h := pages.NewPageHandlers(mgr, reg)
+	h.API = api.NewHandlers(mgr, reg)
+
+	r := chi.NewRouter()
+	r.Get("/scans", h.ScanList)
+	r.Post("/scans", h.CreateScan)
+	r.Post("/scans/rerun", h.RerunScans)
+	r.Post("/scans/{id}/delete", h.DeleteScan)
+	r.Post("/scans/{id}/pause", h.PauseScan)
+	r.Post("/scans/{id}/cancel", h.CancelScan)
+	r.Post("/scans/{id}/rerun", h.RerunScan)
+	return r, mgr
+}
+
+func postForm(r http.Handler, path string, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCreateScan_RedirectsToTheScan(t *testing.T) {
+	r, mgr := newActionRouter()
+
+	rec := postForm(r, "/scans", url.Values{
+		"target":            {"https://example.com"},
+		"scanners":          {"port", "headers"},
+		"timeout":           {"5s"},
+		"engagement_client": {"ACME Corp"},
+	})
+
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("expected status 303, got %d: %s", rec.Code, rec.Body.String())
+	}
+	list := mgr.List()
+	if len(list) != 1 {
+		t.Fatalf("expected one scan, got %d", len(list))
+	}
+	if got, want := rec.Header().Get("Location"), "/scans/"+list[0].ID; got != want {
+		t.Errorf("expected redirect to %q, got %q", want, got)
+	}
+	if list[0].Engagement.Client != "ACME Corp" {
+		t.Errorf("expected the engagement to be recorded, got %+v", list[0].Engagement)
+	}
+}
+
+func TestCreateScan_ShowsTheFormAgainOnError(t *testing.T) {
+	r, mgr := newActionRouter()
+
+	rec := postForm(r, "/scans", url.Values{"target": {"https://example.com"}})
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "Please select at least one scanner.") {
+		t.Error("expected the error to be shown")
+	}
+	if !strings.Contains(body, `value="https://example.com"`) {
+		t.Error("expected the target to be filled in again")
+	}
+	if len(mgr.List()) != 0 {
+		t.Error("expected no scan to be created")
+	}
+}
+
+func TestDeleteScan_RedirectsToHistory(t *testing.T) {
+	r, mgr := newActionRouter()
+	job := mgr.Create(types.Target{Host: "example.com"}, []string{"port"}, scanner.DefaultOptions())
+
+	rec := postForm(r, "/scans/"+job.ID+"/delete", nil)
+
+	if rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/scans" {
+		t.Fatalf("expected a redirect to /scans, got %d to %q", rec.Code, rec.Header().Get("Location"))
+	}
+	if _, err := mgr.Get(job.ID); err == nil {
+		t.Error("expected the scan to be deleted")
+	}
+
+	rec = postForm(r, "/scans/"+job.ID+"/delete", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for a deleted scan, got %d", rec.Code)
+	}
+}
+
+func TestControlScan_ShowsConflicts(t *testing.T) {
+	r, mgr := newActionRouter()
+	job := mgr.Create(types.Target{Host: "example.com"}, []string{"port"}, scanner.DefaultOptions())
+
+	for _, action := range []string{"pause", "cancel", "rerun"} {
+		rec := postForm(r, "/scans/"+job.ID+"/"+action, nil)
+		if rec.Code != http.StatusConflict {
+			t.Errorf("%s: expected status 409 for a pending scan, got %d", action, rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), `role="alert"`) {
+			t.Errorf("%s: expected the error page", action)
+		}
+	}
+	if rec := postForm(r, "/scans/nonexistent/pause", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for an unknown scan, got %d", rec.Code)
+	}
+}
+
+func TestScanList_FiltersByStatusAndTarget(t *testing.T) {
+	r, mgr := newActionRouter()
+	mgr.Create(types.Target{Host: "example.com"}, []string{"port"}, scanner.DefaultOptions())
+	mgr.Create(types.Target{Host: "other.test"}, []string{"port"}, scanner.DefaultOptions())
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/scans?status=pending&target=example", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "example.com") || strings.Contains(body, "other.test") {
+		t.Error("expected only the matching scan to be listed")
+	}
+	if !strings.Contains(body, `<option value="pending" selected>`) {
+		t.Error("expected the filter form to keep the selected status")
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/scans?status=done", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an unknown status, got %d", rec.Code)
+	}
+}
@@ -2,8 +2,12 @@ package pages
 
 import (
 	"net/http"
+	"net/url"
+	"time"
 
+	"github.com/buemura/hunter/internal/config"
 	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/web/api"
 	"github.com/buemura/hunter/internal/web/jobs"
 	"github.com/buemura/hunter/internal/web/templates"
 	"github.com/go-chi/chi/v5"
@@ -18,17 +22,46 @@ type ScannerInfo struct {
 // IndexData is the template data for the index (scan form) page.
 type IndexData struct {
 	Scanners []ScannerInfo
+	// Agents are the online agents scans can be dispatched to.
+	Agents []jobs.Agent
+	// Error is why the posted form was rejected, and Form its values.
+	Error string
+	Form  url.Values
 }
 
 // ScanListData is the template data for the scan history page.
 type ScanListData struct {
 	Jobs       []*jobs.Job
 	HasRunning bool
+	// Filter selects the jobs listed; Statuses are those it can select.
+	Filter   jobs.Filter
+	Statuses []jobs.JobStatus
+	// HasIncomplete offers to rerun the failed and partial scans.
+	HasIncomplete bool
 }
 
 // ScanDetailData is the template data for the scan detail page.
 type ScanDetailData struct {
 	Job *jobs.Job
+	// Verification is the outcome of verifying one of the job's findings,
+	// when the page follows a verification without JavaScript.
+	Verification *Verification
+}
+
+// Verification is the outcome of replaying a finding's probe.
+type Verification struct {
+	Fingerprint string
+	Reproduces  bool
+	Error       string
+}
+
+// FindingsData is the template data for the findings page.
+type FindingsData struct {
+	Findings []jobs.TrackedFinding
+	// Overdue counts the open findings past their SLA.
+	Overdue int
+	// Error explains why findings could not be tracked.
+	Error string
 }
 
 // NotFoundData is the template data for the 404 page.
@@ -40,6 +73,11 @@ type NotFoundData struct {
 type PageHandlers struct {
 	manager  *jobs.Manager
 	registry *scanner.Registry
+	// Config returns the current configuration, for the SLA of the findings
+	// page. Nil means defaults.
+	Config func() *config.Config
+	// API starts the scans the scan form posts.
+	API *api.Handlers
 }
 
 // NewPageHandlers creates a new PageHandlers.
@@ -52,29 +90,63 @@ func NewPageHandlers(manager *jobs.Manager, registry *scanner.Registry) *PageHan
 
 // Index renders the landing page with the scan form.
 func (h *PageHandlers) Index(w http.ResponseWriter, r *http.Request) {
+	h.renderIndex(w, r, http.StatusOK, "")
+}
+
+// renderIndex renders the scan form with the given status. With an error,
+// the form is filled in with the values posted, so they can be corrected.
+func (h *PageHandlers) renderIndex(w http.ResponseWriter, r *http.Request, status int, errMsg string) {
 	scanners := h.registry.All()
 	info := make([]ScannerInfo, len(scanners))
 	for i, s := range scanners {
 		info[i] = ScannerInfo{Name: s.Name(), Description: s.Description()}
 	}
 
-	data := IndexData{Scanners: info}
-	if err := templates.RenderPage(w, "index.html", data); err != nil {
+	data := IndexData{Scanners: info, Error: errMsg}
+	if errMsg != "" {
+		data.Form = r.PostForm
+	}
+	now := time.Now()
+	for _, a := range h.manager.Agents() {
+		if a.Online(now) {
+			data.Agents = append(data.Agents, a)
+		}
+	}
+	if status != http.StatusOK {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(status)
+	}
+	if err := templates.RenderPage(w, "index.html", data); err != nil && status == http.StatusOK {
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
 
-// ScanList renders the scan history page.
+// ScanList renders the scan history page, filtered by the status and
+// target query parameters.
 func (h *PageHandlers) ScanList(w http.ResponseWriter, r *http.Request) {
-	jobList := h.manager.List()
-	hasRunning := false
-	for _, j := range jobList {
-		if j.Status == jobs.StatusRunning || j.Status == jobs.StatusPending {
-			hasRunning = true
-			break
+	filter := jobs.Filter{
+		Status: jobs.JobStatus(r.URL.Query().Get("status")),
+		Target: r.URL.Query().Get("target"),
+	}
+	if err := filter.Validate(); err != nil {
+		renderError(w, http.StatusBadRequest, "Invalid filter", err.Error(), "/scans")
+		return
+	}
+	all := h.manager.List()
+	jobList := filter.Apply(all)
+	data := ScanListData{
+		Jobs:     jobList,
+		Filter:   filter,
+		Statuses: []jobs.JobStatus{jobs.StatusPending, jobs.StatusRunning, jobs.StatusPaused, jobs.StatusCompleted, jobs.StatusFailed},
+	}
+	for _, j := range all {
+		if j.Status == jobs.StatusRunning || j.Status == jobs.StatusPending || j.Status == jobs.StatusPaused {
+			data.HasRunning = true
+		}
+		if j.Incomplete() {
+			data.HasIncomplete = true
 		}
 	}
-	data := ScanListData{Jobs: jobList, HasRunning: hasRunning}
 	if err := templates.RenderPage(w, "scans.html", data); err != nil {
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
@@ -93,7 +165,39 @@ func (h *PageHandlers) ScanDetail(w http.ResponseWriter, r *http.Request) {
 	}
 
 	data := ScanDetailData{Job: job}
+	if q := r.URL.Query(); q.Get("verified") != "" {
+		data.Verification = &Verification{
+			Fingerprint: q.Get("verified"),
+			Reproduces:  q.Get("reproduces") == "true",
+			Error:       q.Get("verify_error"),
+		}
+	}
 	if err := templates.RenderPage(w, "scan_detail.html", data); err != nil {
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
+
+// Findings renders the findings tracked across the completed scans of each
+// target, flagging those past their SLA.
+func (h *PageHandlers) Findings(w http.ResponseWriter, r *http.Request) {
+	var data FindingsData
+	var raw map[string]int
+	if h.Config != nil {
+		if cfg := h.Config(); cfg != nil {
+			raw = cfg.SLA
+		}
+	}
+	if sla, err := jobs.ParseSLA(raw); err != nil {
+		data.Error = err.Error()
+	} else {
+		data.Findings = h.manager.Findings(sla, time.Now())
+		for _, f := range data.Findings {
+			if f.Overdue {
+				data.Overdue++
+			}
+		}
+	}
+	if err := templates.RenderPage(w, "findings.html", data); err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/buemura/hunter/internal/scanner"
 	"github.com/buemura/hunter/internal/web/jobs"
@@ -26,6 +27,19 @@ func (m *mockScanner) Run(_ context.Context, _ types.Target, _ scanner.Options)
 	return &types.ScanResult{ScannerName: m.name}, nil
 }
 
+// artifactScanner reports one finding with a request/response artifact.
+type artifactScanner struct{}
+
+func (artifactScanner) Name() string        { return "vuln" }
+func (artifactScanner) Description() string { return "finding with evidence" }
+func (artifactScanner) Run(_ context.Context, _ types.Target, _ scanner.Options) (*types.ScanResult, error) {
+	return &types.ScanResult{ScannerName: "vuln", Findings: []types.Finding{{
+		Title:     "Potential reflected XSS",
+		Severity:  types.SeverityHigh,
+		Artifacts: []types.Artifact{{Request: "GET /?q=x HTTP/1.1\r\n", Response: "HTTP/1.1 200 OK\r\n"}},
+	}}}, nil
+}
+
 func newTestRegistry() *scanner.Registry {
 	reg := scanner.NewRegistry()
 	reg.Register(&mockScanner{name: "port", desc: "TCP port scan"})
@@ -79,6 +93,42 @@ func TestIndex_ContainsScannerNames(t *testing.T) {
 	}
 }
 
+func TestIndex_OffersOnlineAgents(t *testing.T) {
+	reg := newTestRegistry()
+	mgr := newTestManager(reg)
+	h := pages.NewPageHandlers(mgr, reg)
+
+	rec := httptest.NewRecorder()
+	h.Index(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if strings.Contains(rec.Body.String(), "Run on") {
+		t.Error("expected no agent select without agents")
+	}
+
+	mgr.Claim("dmz-1")
+	job, err := mgr.Dispatch("dmz-1", types.Target{Host: "10.0.0.5"}, []string{"port"}, types.Engagement{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec = httptest.NewRecorder()
+	h.Index(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !strings.Contains(rec.Body.String(), `<option value="dmz-1">`) {
+		t.Error("expected the online agent to be offered")
+	}
+
+	r := chi.NewRouter()
+	r.Get("/scans/{id}", h.ScanDetail)
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/scans/"+job.ID, nil))
+	body := rec.Body.String()
+	if !strings.Contains(body, "dmz-1") {
+		t.Error("expected the scan detail to name its agent")
+	}
+	if strings.Contains(body, "pause-btn") {
+		t.Error("expected no pause button for a scan run by an agent")
+	}
+}
+
 func TestScanList_Returns200WithScanTable(t *testing.T) {
 	reg := newTestRegistry()
 	mgr := newTestManager(reg)
@@ -153,6 +203,109 @@ func TestScanDetail_Returns200WithScanInfo(t *testing.T) {
 	}
 }
 
+func TestScanDetail_ShowsEngagement(t *testing.T) {
+	reg := newTestRegistry()
+	mgr := newTestManager(reg)
+	h := pages.NewPageHandlers(mgr, reg)
+
+	opts := scanner.DefaultOptions()
+	opts.Engagement = types.Engagement{Client: "ACME Corp", Authorization: "SOW-7", Notes: "Staging only"}
+	job := mgr.Create(types.Target{Host: "example.com", Scheme: "https"}, []string{"port"}, opts)
+
+	r := chi.NewRouter()
+	r.Get("/scans/{id}", h.ScanDetail)
+	req := httptest.NewRequest(http.MethodGet, "/scans/"+job.ID, nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	body := rec.Body.String()
+	for _, want := range []string{"ACME Corp", "SOW-7", "Staging only"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected response to contain %q", want)
+		}
+	}
+	if strings.Contains(body, "Engagement ID") {
+		t.Error("expected fields that are not set to be left out")
+	}
+}
+
+func TestScanDetail_ShowsLogs(t *testing.T) {
+	reg := newTestRegistry()
+	mgr := newTestManager(reg)
+	h := pages.NewPageHandlers(mgr, reg)
+
+	job := mgr.Create(types.Target{Host: "example.com"}, []string{"port"}, scanner.DefaultOptions())
+	if err := mgr.Start(job.ID); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		snapshot, _ := mgr.Snapshot(job.ID)
+		if snapshot.Status == jobs.StatusCompleted {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("scan did not complete")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	r := chi.NewRouter()
+	r.Get("/scans/{id}", h.ScanDetail)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/scans/"+job.ID, nil))
+
+	body := rec.Body.String()
+	if !strings.Contains(body, `class="card logs-card"`) {
+		t.Error("expected a Logs section")
+	}
+	if !strings.Contains(body, "[port] started") {
+		t.Error("expected the scanner's start to be logged")
+	}
+	if !strings.Contains(body, "/api/v1/scans/"+job.ID+"/logs") {
+		t.Error("expected a link to the logs endpoint")
+	}
+}
+
+func TestScanDetail_LinksArtifacts(t *testing.T) {
+	reg := newTestRegistry()
+	reg.Register(artifactScanner{})
+	mgr := newTestManager(reg)
+	h := pages.NewPageHandlers(mgr, reg)
+
+	job := mgr.Create(types.Target{Host: "example.com"}, []string{"port", "vuln"}, scanner.DefaultOptions())
+	if err := mgr.Start(job.ID); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		snapshot, _ := mgr.Snapshot(job.ID)
+		if snapshot.Status == jobs.StatusCompleted {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("scan did not complete")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	r := chi.NewRouter()
+	r.Get("/scans/{id}", h.ScanDetail)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/scans/"+job.ID, nil))
+
+	body := rec.Body.String()
+	if !strings.Contains(body, "/api/v1/scans/"+job.ID+"/artifacts/1/0") {
+		t.Error("expected a download link for the finding's artifacts")
+	}
+	if !strings.Contains(body, "/api/v1/scans/"+job.ID+"?artifacts=true") {
+		t.Error("expected a JSON download with artifacts")
+	}
+	if !strings.Contains(body, "verifyFinding(") {
+		t.Error("expected a Verify button for findings")
+	}
+}
+
 func TestScanDetail_Returns404ForUnknownID(t *testing.T) {
 	reg := newTestRegistry()
 	mgr := newTestManager(reg)
@@ -174,3 +327,39 @@ func TestScanDetail_Returns404ForUnknownID(t *testing.T) {
 		t.Error("expected response to contain not found message")
 	}
 }
+
+func TestFindings_Returns200WithTrackedFindings(t *testing.T) {
+	reg := scanner.NewRegistry()
+	reg.Register(artifactScanner{})
+	mgr := newTestManager(reg)
+	h := pages.NewPageHandlers(mgr, reg)
+
+	job := mgr.Create(types.Target{Host: "example.com"}, []string{"vuln"}, scanner.DefaultOptions())
+	if err := mgr.Start(job.ID); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		snapshot, _ := mgr.Snapshot(job.ID)
+		if snapshot.Status == jobs.StatusCompleted {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("scan did not complete")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	rec := httptest.NewRecorder()
+	h.Findings(rec, httptest.NewRequest(http.MethodGet, "/findings", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"Potential reflected XSS", "example.com", "open"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected response to contain %q", want)
+		}
+	}
+}
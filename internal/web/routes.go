@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"io/fs"
 	"net/http"
+	"path/filepath"
 
 	"github.com/buemura/hunter/internal/web/api"
 	"github.com/buemura/hunter/internal/web/pages"
@@ -13,28 +14,97 @@ import (
 // registerRoutes mounts all route groups on the server's router.
 func (s *Server) registerRoutes() {
 	pageHandlers := pages.NewPageHandlers(s.manager, s.registry)
+	pageHandlers.Config = s.Config
 	apiHandlers := api.NewHandlers(s.manager, s.registry)
+	apiHandlers.Config = s.Config
+	pageHandlers.API = apiHandlers
 
 	// Page routes
 	s.router.Get("/", pageHandlers.Index)
 	s.router.Get("/scans", pageHandlers.ScanList)
 	s.router.Get("/scans/{id}", pageHandlers.ScanDetail)
+	s.router.Get("/findings", pageHandlers.Findings)
+
+	// Form posts of the pages, so they work without JavaScript. Like the
+	// API's mutating endpoints, they are rejected in read-only mode, and
+	// posts from other sites are refused.
+	s.router.Group(func(r chi.Router) {
+		r.Use(sameOrigin)
+		if s.readOnly {
+			r.Use(rejectReadOnly)
+		}
+		r.Post("/scans", pageHandlers.CreateScan)
+		r.Post("/scans/rerun", pageHandlers.RerunScans)
+		r.Post("/scans/{id}/delete", pageHandlers.DeleteScan)
+		r.Post("/scans/{id}/pause", pageHandlers.PauseScan)
+		r.Post("/scans/{id}/resume", pageHandlers.ResumeScan)
+		r.Post("/scans/{id}/cancel", pageHandlers.CancelScan)
+		r.Post("/scans/{id}/rerun", pageHandlers.RerunScan)
+		r.Post("/scans/{id}/findings/{fingerprint}/verify", pageHandlers.VerifyFinding)
+	})
 
 	// Health check
 	s.router.Get("/health", s.handleHealth)
 
 	// REST API
 	s.router.Route("/api/v1", func(r chi.Router) {
-		r.Post("/scans", apiHandlers.CreateScan)
+		r.Use(s.cors)
+
 		r.Get("/scans", apiHandlers.ListScans)
 		r.Get("/scans/{id}", apiHandlers.GetScan)
 		r.Get("/scans/{id}/report", apiHandlers.GetScanReport)
-		r.Delete("/scans/{id}", apiHandlers.DeleteScan)
+		r.Get("/scans/{id}/logs", apiHandlers.GetScanLogs)
+		r.Get("/scans/{id}/artifacts/{result}/{finding}", apiHandlers.GetFindingArtifacts)
+		r.Get("/findings", apiHandlers.ListFindings)
+		r.Get("/agents", apiHandlers.ListAgents)
+
+		// Agents poll for the scans dispatched to them and report back.
+		r.Group(func(r chi.Router) {
+			r.Use(s.agentAuth)
+			r.Get("/agents/{name}/jobs/next", apiHandlers.NextAgentJob)
+			r.Post("/agents/{name}/jobs/{id}", apiHandlers.ReportAgentJob)
+		})
+
+		// Mutating endpoints are rejected in read-only mode, and requests
+		// browsers send for sites the cors section does not name are
+		// refused, as a page elsewhere could otherwise post to them.
+		r.Group(func(r chi.Router) {
+			r.Use(s.apiOrigin)
+			if s.readOnly {
+				r.Use(rejectReadOnly)
+			}
+			r.Post("/scans", apiHandlers.CreateScan)
+			r.Delete("/scans/{id}", apiHandlers.DeleteScan)
+			r.Post("/scans/{id}/pause", apiHandlers.PauseScan)
+			r.Post("/scans/{id}/resume", apiHandlers.ResumeScan)
+			r.Post("/scans/{id}/cancel", apiHandlers.CancelScan)
+			r.Post("/scans/{id}/rerun", apiHandlers.RerunScan)
+			r.Post("/scans/rerun", apiHandlers.RerunScans)
+			r.Post("/scans/{id}/findings/{fingerprint}/verify", apiHandlers.VerifyFinding)
+		})
 	})
 
-	// Embedded static files
+	// Embedded static files, or in development those on disk, which must
+	// not be cached.
 	staticSub, _ := fs.Sub(staticFS, "static")
-	s.router.Handle("/static/*", http.StripPrefix("/static/", http.FileServer(http.FS(staticSub))))
+	static := cacheStatic(staticETags(staticSub), http.FileServer(http.FS(staticSub)))
+	if s.devDir != "" {
+		static = noCache(http.FileServer(http.Dir(filepath.Join(s.devDir, "static"))))
+	}
+	s.router.Handle("/static/*", http.StripPrefix("/static/", static))
+}
+
+// rejectReadOnly responds 403 to every request, for endpoints that are
+// disabled when the server runs in read-only mode.
+func rejectReadOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "server is running in read-only mode",
+			"code":  http.StatusForbidden,
+		})
+	})
 }
 
 // handleHealth returns a simple health check response.
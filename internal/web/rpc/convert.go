@@ -0,0 +1,169 @@
+package rpc
+
+import (
+	"time"
+
+	"github.com/buemura/hunter/internal/web/api"
+	"github.com/buemura/hunter/internal/web/jobs"
+	"github.com/buemura/hunter/pkg/hunterpb"
+	"github.com/buemura/hunter/pkg/types"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var severities = map[types.Severity]hunterpb.Severity{
+	types.SeverityCritical: hunterpb.Severity_SEVERITY_CRITICAL,
+	types.SeverityHigh:     hunterpb.Severity_SEVERITY_HIGH,
+	types.SeverityMedium:   hunterpb.Severity_SEVERITY_MEDIUM,
+	types.SeverityLow:      hunterpb.Severity_SEVERITY_LOW,
+	types.SeverityInfo:     hunterpb.Severity_SEVERITY_INFO,
+}
+
+var statuses = map[jobs.JobStatus]hunterpb.JobStatus{
+	jobs.StatusPending:   hunterpb.JobStatus_JOB_STATUS_PENDING,
+	jobs.StatusRunning:   hunterpb.JobStatus_JOB_STATUS_RUNNING,
+	jobs.StatusPaused:    hunterpb.JobStatus_JOB_STATUS_PAUSED,
+	jobs.StatusCompleted: hunterpb.JobStatus_JOB_STATUS_COMPLETED,
+	jobs.StatusFailed:    hunterpb.JobStatus_JOB_STATUS_FAILED,
+}
+
+// fromCreateScanRequest converts a request to its REST form, which the API
+// handlers validate and start.
+func fromCreateScanRequest(in *hunterpb.CreateScanRequest) *api.CreateScanRequest {
+	return &api.CreateScanRequest{
+		Target:        in.GetTarget(),
+		Scanners:      in.GetScanners(),
+		Profile:       in.GetProfile(),
+		Concurrency:   int(in.GetConcurrency()),
+		Timeout:       durationString(in.GetTimeout()),
+		MaxDuration:   durationString(in.GetMaxDuration()),
+		NoPreflight:   in.GetNoPreflight(),
+		Intensity:     in.GetIntensity(),
+		IPVersion:     in.GetIpVersion(),
+		Resolver:      in.GetResolver(),
+		Resolve:       in.GetResolve(),
+		Crawl:         in.GetCrawl(),
+		CrawlDepth:    int(in.GetCrawlDepth()),
+		CrawlPages:    int(in.GetCrawlPages()),
+		Redact:        in.GetRedact(),
+		Engagement:    fromEngagement(in.GetEngagement()),
+		IAmAuthorized: in.GetIAmAuthorized(),
+		Agent:         in.GetAgent(),
+	}
+}
+
+// durationString formats d as the REST API takes it, empty when unset.
+func durationString(d *durationpb.Duration) string {
+	if d == nil {
+		return ""
+	}
+	return d.AsDuration().String()
+}
+
+func fromEngagement(e *hunterpb.Engagement) types.Engagement {
+	return types.Engagement{
+		Client:        e.GetClient(),
+		ID:            e.GetId(),
+		Tester:        e.GetTester(),
+		Authorization: e.GetAuthorization(),
+		Notes:         e.GetNotes(),
+	}
+}
+
+// toJob converts a job, with its results only if withResults is set and
+// their artifacts only if withArtifacts is also set.
+func toJob(j jobs.Job, withResults, withArtifacts bool) *hunterpb.Job {
+	out := &hunterpb.Job{
+		Id:           j.ID,
+		Target:       toTarget(j.Target),
+		Scanners:     j.Scanners,
+		Status:       statuses[j.Status],
+		Error:        j.Error,
+		CreatedAt:    timestamp(j.CreatedAt),
+		StartedAt:    timestamp(j.StartedAt),
+		CompletedAt:  timestamp(j.CompletedAt),
+		Progress:     toProgress(j.Progress),
+		Agent:        j.Agent,
+		FindingCount: int32(j.FindingCount()),
+	}
+	if !j.Engagement.IsZero() {
+		out.Engagement = &hunterpb.Engagement{
+			Client:        j.Engagement.Client,
+			Id:            j.Engagement.ID,
+			Tester:        j.Engagement.Tester,
+			Authorization: j.Engagement.Authorization,
+			Notes:         j.Engagement.Notes,
+		}
+	}
+	if withResults {
+		for _, r := range j.Results {
+			out.Results = append(out.Results, toResult(r, withArtifacts))
+		}
+	}
+	return out
+}
+
+func toProgress(p jobs.JobProgress) *hunterpb.Progress {
+	return &hunterpb.Progress{
+		TotalScanners:     int32(p.TotalScanners),
+		CompletedScanners: int32(p.CompletedScanners),
+		CurrentScanner:    p.CurrentScanner,
+	}
+}
+
+func toTarget(t types.Target) *hunterpb.Target {
+	out := &hunterpb.Target{Host: t.Host, Url: t.URL, Scheme: t.Scheme}
+	for _, p := range t.Ports {
+		out.Ports = append(out.Ports, int32(p))
+	}
+	return out
+}
+
+func toResult(r types.ScanResult, withArtifacts bool) *hunterpb.ScanResult {
+	out := &hunterpb.ScanResult{
+		Scanner:     r.ScannerName,
+		Target:      toTarget(r.Target),
+		StartedAt:   timestamp(r.StartedAt),
+		CompletedAt: timestamp(r.CompletedAt),
+		Error:       r.Error,
+		Metadata:    r.Metadata,
+	}
+	for _, f := range r.Findings {
+		out.Findings = append(out.Findings, toFinding(f, withArtifacts))
+	}
+	return out
+}
+
+func toFinding(f types.Finding, withArtifacts bool) *hunterpb.Finding {
+	out := &hunterpb.Finding{
+		Title:       f.Title,
+		Description: f.Description,
+		Severity:    severities[f.Severity],
+		Evidence:    f.Evidence,
+		Remediation: f.Remediation,
+		Metadata:    f.Metadata,
+		Fingerprint: f.Fingerprint,
+	}
+	for _, ref := range f.References {
+		out.References = append(out.References, &hunterpb.Reference{Title: ref.Title, Url: ref.URL})
+	}
+	if withArtifacts {
+		for _, a := range f.Artifacts {
+			out.Artifacts = append(out.Artifacts, &hunterpb.Artifact{
+				Request:    a.Request,
+				Response:   a.Response,
+				Truncated:  a.Truncated,
+				Screenshot: a.Screenshot,
+			})
+		}
+	}
+	return out
+}
+
+// timestamp converts t, leaving unset times unset.
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,203 @@
+// Package rpc serves the Hunter gRPC API, defined in
+// proto/hunter/v1/hunter.proto, over the same scans as the REST API.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/buemura/hunter/internal/web/api"
+	"github.com/buemura/hunter/internal/web/jobs"
+	"github.com/buemura/hunter/pkg/hunterpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// watchInterval is how often WatchScan checks a scan for changes.
+var watchInterval = 250 * time.Millisecond
+
+// Server implements hunterpb.HunterServer with the REST API's handlers.
+type Server struct {
+	hunterpb.UnimplementedHunterServer
+
+	api      *api.Handlers
+	readOnly bool
+}
+
+// NewServer creates a Server. With readOnly set, scans cannot be created,
+// paused, resumed, or deleted, as in the web server's read-only mode.
+func NewServer(handlers *api.Handlers, readOnly bool) *Server {
+	return &Server{api: handlers, readOnly: readOnly}
+}
+
+// GRPCServer returns a gRPC server serving s.
+func (s *Server) GRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	g := grpc.NewServer(opts...)
+	hunterpb.RegisterHunterServer(g, s)
+	return g
+}
+
+// ListScanners lists the registered scanners.
+func (s *Server) ListScanners(context.Context, *hunterpb.ListScannersRequest) (*hunterpb.ListScannersResponse, error) {
+	resp := &hunterpb.ListScannersResponse{}
+	for _, sc := range s.api.Registry.All() {
+		resp.Scanners = append(resp.Scanners, &hunterpb.Scanner{Name: sc.Name(), Description: sc.Description()})
+	}
+	return resp, nil
+}
+
+// CreateScan starts a scan as POST /api/v1/scans does.
+func (s *Server) CreateScan(_ context.Context, in *hunterpb.CreateScanRequest) (*hunterpb.Job, error) {
+	if err := s.writable(); err != nil {
+		return nil, err
+	}
+	req := fromCreateScanRequest(in)
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	job, err := s.api.StartScan(req)
+	if err != nil {
+		return nil, scanStatus(err)
+	}
+	return s.job(job.ID, false, false)
+}
+
+// ListScans lists scans, newest first, without their results.
+func (s *Server) ListScans(context.Context, *hunterpb.ListScansRequest) (*hunterpb.ListScansResponse, error) {
+	resp := &hunterpb.ListScansResponse{}
+	for _, j := range s.api.Manager.List() {
+		snapshot, err := s.api.Manager.Snapshot(j.ID)
+		if err != nil {
+			// Deleted since it was listed.
+			continue
+		}
+		resp.Scans = append(resp.Scans, toJob(snapshot, false, false))
+	}
+	return resp, nil
+}
+
+// GetScan returns a scan with its results.
+func (s *Server) GetScan(_ context.Context, in *hunterpb.GetScanRequest) (*hunterpb.Job, error) {
+	return s.job(in.GetId(), true, in.GetArtifacts())
+}
+
+// WatchScan streams a scan's progress and findings until it finishes or the
+// client goes away.
+func (s *Server) WatchScan(in *hunterpb.WatchScanRequest, stream grpc.ServerStreamingServer[hunterpb.ScanEvent]) error {
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	var progress *jobs.JobProgress
+	sent := 0
+	for {
+		job, err := s.api.Manager.Snapshot(in.GetId())
+		if err != nil {
+			return status.Error(codes.NotFound, err.Error())
+		}
+
+		if progress == nil || *progress != job.Progress {
+			progress = &job.Progress
+			event := &hunterpb.ScanEvent{Event: &hunterpb.ScanEvent_Progress{Progress: toProgress(job.Progress)}}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+		for _, r := range job.Results[sent:] {
+			for _, f := range r.Findings {
+				event := &hunterpb.ScanEvent{Event: &hunterpb.ScanEvent_Finding{Finding: &hunterpb.FindingEvent{
+					Scanner: r.ScannerName,
+					Finding: toFinding(f, false),
+				}}}
+				if err := stream.Send(event); err != nil {
+					return err
+				}
+			}
+		}
+		sent = len(job.Results)
+
+		if job.Status == jobs.StatusCompleted || job.Status == jobs.StatusFailed {
+			return stream.Send(&hunterpb.ScanEvent{Event: &hunterpb.ScanEvent_Done{Done: toJob(job, false, false)}})
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// PauseScan pauses a running scan.
+func (s *Server) PauseScan(_ context.Context, in *hunterpb.ScanRef) (*hunterpb.Job, error) {
+	return s.setPaused(in.GetId(), s.api.Manager.Pause)
+}
+
+// ResumeScan resumes a paused scan.
+func (s *Server) ResumeScan(_ context.Context, in *hunterpb.ScanRef) (*hunterpb.Job, error) {
+	return s.setPaused(in.GetId(), s.api.Manager.Resume)
+}
+
+// setPaused applies pause or resume to a job; FailedPrecondition if the job
+// is not in a state the operation applies to.
+func (s *Server) setPaused(id string, apply func(jobID string) error) (*hunterpb.Job, error) {
+	if err := s.writable(); err != nil {
+		return nil, err
+	}
+	if _, err := s.api.Manager.Get(id); err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err := apply(id); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return s.job(id, false, false)
+}
+
+// DeleteScan deletes a scan.
+func (s *Server) DeleteScan(_ context.Context, in *hunterpb.ScanRef) (*hunterpb.DeleteScanResponse, error) {
+	if err := s.writable(); err != nil {
+		return nil, err
+	}
+	if err := s.api.Manager.Delete(in.GetId()); err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &hunterpb.DeleteScanResponse{}, nil
+}
+
+// job returns a snapshot of a job; NotFound if there is none.
+func (s *Server) job(id string, withResults, withArtifacts bool) (*hunterpb.Job, error) {
+	job, err := s.api.Manager.Snapshot(id)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return toJob(job, withResults, withArtifacts), nil
+}
+
+func (s *Server) writable() error {
+	if s.readOnly {
+		return status.Error(codes.PermissionDenied, "server is running in read-only mode")
+	}
+	return nil
+}
+
+// scanStatus maps an error from api.Handlers.StartScan to a gRPC status.
+func scanStatus(err error) error {
+	code := codes.Internal
+	var scanErr *api.ScanError
+	if errors.As(err, &scanErr) {
+		switch scanErr.Status {
+		case http.StatusBadRequest:
+			code = codes.InvalidArgument
+		case http.StatusForbidden:
+			code = codes.PermissionDenied
+		case http.StatusNotFound:
+			code = codes.NotFound
+		case http.StatusConflict:
+			code = codes.FailedPrecondition
+		}
+	}
+	return status.Error(code, err.Error())
+}
@@ -0,0 +1,172 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/buemura/hunter/internal/config"
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/web/api"
+	"github.com/buemura/hunter/internal/web/jobs"
+	"github.com/buemura/hunter/pkg/hunterpb"
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type mockScanner struct{ name string }
+
+func (m *mockScanner) Name() string        { return m.name }
+func (m *mockScanner) Description() string { return "mock " + m.name }
+func (m *mockScanner) Run(_ context.Context, target types.Target, _ scanner.Options) (*types.ScanResult, error) {
+	return &types.ScanResult{
+		ScannerName: m.name,
+		Target:      target,
+		Findings: []types.Finding{{
+			Title:     m.name + " finding",
+			Severity:  types.SeverityHigh,
+			Artifacts: []types.Artifact{{Request: "GET / HTTP/1.1\r\n", Response: "HTTP/1.1 200 OK\r\n"}},
+		}},
+	}, nil
+}
+
+// newClient serves a Server over an in-memory connection until the test
+// ends.
+func newClient(t *testing.T, cfg *config.Config, readOnly bool) (hunterpb.HunterClient, *jobs.Manager) {
+	t.Helper()
+	reg := scanner.NewRegistry()
+	reg.Register(&mockScanner{name: "headers"})
+	reg.Register(&mockScanner{name: "port"})
+	manager := jobs.NewManager(scanner.NewRunner(reg))
+	handlers := api.NewHandlers(manager, reg)
+	if cfg != nil {
+		handlers.Config = func() *config.Config { return cfg }
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	g := NewServer(handlers, readOnly).GRPCServer()
+	go g.Serve(lis)
+	t.Cleanup(g.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return hunterpb.NewHunterClient(conn), manager
+}
+
+func TestListScanners(t *testing.T) {
+	client, _ := newClient(t, nil, false)
+	resp, err := client.ListScanners(context.Background(), &hunterpb.ListScannersRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Scanners, 2)
+	descriptions := map[string]string{}
+	for _, s := range resp.Scanners {
+		descriptions[s.Name] = s.Description
+	}
+	assert.Equal(t, map[string]string{"headers": "mock headers", "port": "mock port"}, descriptions)
+}
+
+func TestCreateAndWatchScan(t *testing.T) {
+	watchInterval = 10 * time.Millisecond
+	client, _ := newClient(t, nil, false)
+	ctx := context.Background()
+
+	job, err := client.CreateScan(ctx, &hunterpb.CreateScanRequest{
+		Target:     "https://example.com",
+		Scanners:   []string{"headers", "port"},
+		Timeout:    durationpb.New(5 * time.Second),
+		Engagement: &hunterpb.Engagement{Client: "ACME"},
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, job.Id)
+	assert.Equal(t, "example.com", job.Target.Host)
+	assert.Equal(t, "ACME", job.Engagement.Client)
+
+	stream, err := client.WatchScan(ctx, &hunterpb.WatchScanRequest{Id: job.Id})
+	require.NoError(t, err)
+	var findings []*hunterpb.FindingEvent
+	var done *hunterpb.Job
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		if f := event.GetFinding(); f != nil {
+			findings = append(findings, f)
+		}
+		if d := event.GetDone(); d != nil {
+			done = d
+		}
+	}
+	require.NotNil(t, done, "the stream ends with the finished scan")
+	assert.Equal(t, hunterpb.JobStatus_JOB_STATUS_COMPLETED, done.Status)
+	assert.EqualValues(t, 2, done.FindingCount)
+	assert.Empty(t, done.Results)
+	require.Len(t, findings, 2)
+	assert.Equal(t, hunterpb.Severity_SEVERITY_HIGH, findings[0].Finding.Severity)
+	assert.Empty(t, findings[0].Finding.Artifacts)
+
+	got, err := client.GetScan(ctx, &hunterpb.GetScanRequest{Id: job.Id})
+	require.NoError(t, err)
+	require.Len(t, got.Results, 2)
+	assert.Empty(t, got.Results[0].Findings[0].Artifacts, "artifacts are left out unless asked for")
+
+	got, err = client.GetScan(ctx, &hunterpb.GetScanRequest{Id: job.Id, Artifacts: true})
+	require.NoError(t, err)
+	assert.NotEmpty(t, got.Results[0].Findings[0].Artifacts)
+
+	list, err := client.ListScans(ctx, &hunterpb.ListScansRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.Scans, 1)
+	assert.Empty(t, list.Scans[0].Results)
+
+	_, err = client.DeleteScan(ctx, &hunterpb.ScanRef{Id: job.Id})
+	require.NoError(t, err)
+	_, err = client.GetScan(ctx, &hunterpb.GetScanRequest{Id: job.Id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestCreateScan_Errors(t *testing.T) {
+	cfg := config.Defaults()
+	cfg.AuthorizedTargets = []string{"example.com"}
+	client, _ := newClient(t, &cfg, false)
+	ctx := context.Background()
+
+	_, err := client.CreateScan(ctx, &hunterpb.CreateScanRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.CreateScan(ctx, &hunterpb.CreateScanRequest{Target: "https://other.test"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.CreateScan(ctx, &hunterpb.CreateScanRequest{Target: "https://example.com", Agent: "dmz-1"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), "the agent is not connected")
+
+	_, err = client.PauseScan(ctx, &hunterpb.ScanRef{Id: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestReadOnly(t *testing.T) {
+	client, manager := newClient(t, nil, true)
+	ctx := context.Background()
+	job := manager.Create(types.Target{Host: "example.com"}, []string{"headers"}, scanner.Options{})
+
+	_, err := client.CreateScan(ctx, &hunterpb.CreateScanRequest{Target: "https://example.com"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = client.DeleteScan(ctx, &hunterpb.ScanRef{Id: job.ID})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.GetScan(ctx, &hunterpb.GetScanRequest{Id: job.ID})
+	assert.NoError(t, err, "reads are allowed")
+}
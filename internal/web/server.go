@@ -3,53 +3,149 @@ package web
 import (
 	"embed"
 	"net/http"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/buemura/hunter/internal/config"
 	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/web/api"
 	"github.com/buemura/hunter/internal/web/jobs"
+	"github.com/buemura/hunter/internal/web/rpc"
+	"github.com/buemura/hunter/internal/web/templates"
+	"github.com/buemura/hunter/pkg/types"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"google.golang.org/grpc"
 )
 
+// compressionLevel is the gzip and deflate level of responses. Level 5
+// shrinks pages, scripts, and JSON nearly as well as higher levels at a
+// fraction of the CPU.
+const compressionLevel = 5
+
 //go:embed static/*
 var staticFS embed.FS
 
+// Options configures optional server behaviour.
+type Options struct {
+	// BasePath mounts the application under a URL prefix (e.g. "/hunter")
+	// for deployments behind a reverse proxy that serves it on a subpath.
+	BasePath string
+	// ReadOnly disables scan creation and deletion, for demo or
+	// reporting-only deployments.
+	ReadOnly bool
+	// Config supplies scan profiles, per-scanner defaults, severity
+	// overrides, retention limits, the API's CORS settings, and the agent
+	// token. It can be replaced while the server runs with SetConfig; nil
+	// means defaults.
+	Config *config.Config
+	// DevDir, when set, is the internal/web directory of a Hunter checkout
+	// to serve static files from on each request instead of the embedded
+	// copies, for developing the UI. Templates are switched separately,
+	// with templates.SetDir.
+	DevDir string
+}
+
 // Server is the HTTP server for the Hunter web application.
 type Server struct {
 	router   chi.Router
+	handler  http.Handler
 	addr     string
+	basePath string
+	readOnly bool
+	devDir   string
 	registry *scanner.Registry
 	runner   *scanner.Runner
 	manager  *jobs.Manager
+	config   atomic.Pointer[config.Config]
 }
 
 // NewServer builds a new Server with middleware and routes configured.
-func NewServer(addr string, reg *scanner.Registry) *Server {
+func NewServer(addr string, reg *scanner.Registry, opts Options) *Server {
 	runner := scanner.NewRunner(reg)
 	s := &Server{
 		router:   chi.NewRouter(),
 		addr:     addr,
+		basePath: normalizeBasePath(opts.BasePath),
+		readOnly: opts.ReadOnly,
+		devDir:   opts.DevDir,
 		registry: reg,
 		runner:   runner,
 		manager:  jobs.NewManager(runner),
 	}
 
+	if opts.Config != nil {
+		s.SetConfig(opts.Config)
+	}
+	// Reruns are checked against the configuration current when they start.
+	s.manager.SetAuthorizer(func(target types.Target) error { return s.Config().AuthorizeTarget(target) })
+
+	templates.SetLayout(templates.Layout{BasePath: s.basePath, ReadOnly: s.readOnly})
+
 	s.router.Use(middleware.Logger)
 	s.router.Use(middleware.Recoverer)
 	s.router.Use(middleware.RequestID)
 	s.router.Use(middleware.Timeout(60 * time.Second))
+	s.router.Use(middleware.Compress(compressionLevel))
 
 	s.registerRoutes()
 
+	s.handler = s.router
+	if s.basePath != "" {
+		s.handler = http.StripPrefix(s.basePath, s.router)
+	}
+
 	return s
 }
 
+// SetConfig atomically replaces the configuration used for new scans and
+// applies its retention limits. Scans already running keep the options they
+// started with.
+func (s *Server) SetConfig(cfg *config.Config) {
+	s.config.Store(cfg)
+	s.manager.SetRetention(cfg.Retention.MaxJobs, cfg.Retention.MaxAge)
+}
+
+// Config returns the configuration currently in use.
+func (s *Server) Config() *config.Config {
+	if cfg := s.config.Load(); cfg != nil {
+		return cfg
+	}
+	cfg := config.Defaults()
+	return &cfg
+}
+
 // Start begins listening on the configured address.
 func (s *Server) Start() error {
-	return http.ListenAndServe(s.addr, s.router)
+	return http.ListenAndServe(s.addr, s.handler)
+}
+
+// GRPCServer returns a gRPC server for the Hunter API over the same scans,
+// config, and read-only mode as the HTTP server.
+func (s *Server) GRPCServer() *grpc.Server {
+	handlers := api.NewHandlers(s.manager, s.registry)
+	handlers.Config = s.Config
+	return rpc.NewServer(handlers, s.readOnly).GRPCServer()
 }
 
-// Router exposes the chi.Router for testing.
+// Router exposes the chi.Router for testing. Routes on it are relative to
+// the base path; use Handler to exercise the fully mounted application.
 func (s *Server) Router() chi.Router {
 	return s.router
 }
+
+// Handler returns the root HTTP handler, including the base path prefix.
+func (s *Server) Handler() http.Handler {
+	return s.handler
+}
+
+// normalizeBasePath returns p with a leading slash and no trailing slash,
+// or an empty string when the application is served from the root.
+func normalizeBasePath(p string) string {
+	p = strings.Trim(strings.TrimSpace(p), "/")
+	if p == "" {
+		return ""
+	}
+	return "/" + p
+}
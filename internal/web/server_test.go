@@ -1,19 +1,26 @@
 package web
 
 import (
+	"compress/gzip"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/web/templates"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func newTestServer() *Server {
 	reg := scanner.NewRegistry()
-	return NewServer(":0", reg)
+	return NewServer(":0", reg, Options{})
 }
 
 func TestHealthEndpoint(t *testing.T) {
@@ -63,3 +70,153 @@ func TestServerHasManager(t *testing.T) {
 	srv := newTestServer()
 	assert.NotNil(t, srv.manager)
 }
+
+func TestNormalizeBasePath(t *testing.T) {
+	assert.Equal(t, "", normalizeBasePath(""))
+	assert.Equal(t, "", normalizeBasePath("/"))
+	assert.Equal(t, "/hunter", normalizeBasePath("hunter"))
+	assert.Equal(t, "/hunter", normalizeBasePath("/hunter/"))
+	assert.Equal(t, "/tools/hunter", normalizeBasePath("/tools/hunter"))
+}
+
+func TestBasePathMountsApplication(t *testing.T) {
+	srv := NewServer(":0", scanner.NewRegistry(), Options{BasePath: "/hunter/"})
+	defer templates.SetLayout(templates.Layout{})
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/hunter/")
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), `href="/hunter/static/css/style.css"`)
+	assert.Contains(t, string(body), `data-base-path="/hunter"`)
+
+	resp, err = http.Get(ts.URL + "/hunter/static/css/style.css")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(ts.URL + "/health")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestReadOnlyRejectsMutations(t *testing.T) {
+	srv := NewServer(":0", scanner.NewRegistry(), Options{ReadOnly: true})
+	defer templates.SetLayout(templates.Layout{})
+	ts := httptest.NewServer(srv.Router())
+	defer ts.Close()
+
+	resp, err := http.Post(ts.URL+"/api/v1/scans", "application/json", strings.NewReader(`{"target":"example.com"}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	req, _ := http.NewRequest(http.MethodDelete, ts.URL+"/api/v1/scans/123", nil)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	resp, err = http.PostForm(ts.URL+"/scans", url.Values{"target": {"example.com"}, "scanners": {"headers"}})
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode, "the page forms are read-only too")
+
+	resp, err = http.Get(ts.URL + "/api/v1/scans")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(ts.URL + "/")
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Contains(t, string(body), "read-only")
+	assert.NotContains(t, string(body), `id="scan-form"`)
+}
+
+func TestPageFormsRejectCrossOriginPosts(t *testing.T) {
+	ts := httptest.NewServer(newTestServer().Router())
+	defer ts.Close()
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+
+	post := func(header, value string) int {
+		req, _ := http.NewRequest(http.MethodPost, ts.URL+"/scans/rerun", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusForbidden, post("Sec-Fetch-Site", "cross-site"))
+	assert.Equal(t, http.StatusForbidden, post("Origin", "https://evil.example"))
+	assert.Equal(t, http.StatusSeeOther, post("Sec-Fetch-Site", "same-origin"))
+	assert.Equal(t, http.StatusSeeOther, post("Origin", ts.URL))
+	assert.Equal(t, http.StatusSeeOther, post("", ""))
+}
+
+func TestStaticAssetsAreCacheable(t *testing.T) {
+	srv := newTestServer()
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/static/css/style.css")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	etag := resp.Header.Get("ETag")
+	assert.NotEmpty(t, etag)
+	assert.Equal(t, staticCacheControl, resp.Header.Get("Cache-Control"))
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/static/css/style.css", nil)
+	req.Header.Set("If-None-Match", etag)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+}
+
+func TestResponsesAreCompressed(t *testing.T) {
+	srv := newTestServer()
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/static/js/app.js", nil)
+	// Setting the header stops the client from decompressing the body.
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+
+	gz, err := gzip.NewReader(resp.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "function submitScan")
+}
+
+func TestDevDirServesStaticFilesFromDisk(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "static", "css"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "static", "css", "style.css"), []byte("body{color:red}"), 0o644))
+
+	srv := NewServer(":0", scanner.NewRegistry(), Options{DevDir: dir})
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/static/css/style.css")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "body{color:red}", string(body))
+	assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+	assert.Empty(t, resp.Header.Get("ETag"))
+}
@@ -1,5 +1,8 @@
 // Hunter Web UI — Vanilla JavaScript
 
+// BASE_PATH is the URL prefix the app is mounted under (empty at the root).
+var BASE_PATH = document.body.getAttribute("data-base-path") || "";
+
 /**
  * submitScan handles the scan form submission via fetch.
  */
@@ -28,7 +31,7 @@ function submitScan(event) {
   btn.textContent = "Starting...";
   hideFormError();
 
-  fetch("/api/v1/scans", {
+  fetch(BASE_PATH + "/api/v1/scans", {
     method: "POST",
     headers: { "Content-Type": "application/json" },
     body: JSON.stringify({
@@ -36,6 +39,16 @@ function submitScan(event) {
       scanners: scanners,
       concurrency: concurrency,
       timeout: timeout,
+      engagement: {
+        client: fieldValue("engagement-client"),
+        id: fieldValue("engagement-id"),
+        tester: fieldValue("engagement-tester"),
+        authorization: fieldValue("engagement-authorization"),
+        notes: fieldValue("engagement-notes"),
+      },
+      i_am_authorized: document.getElementById("i-am-authorized").checked,
+      // The agent select is only shown while agents are online.
+      agent: document.getElementById("agent") ? fieldValue("agent") : "",
     }),
   })
     .then(function (resp) {
@@ -47,7 +60,7 @@ function submitScan(event) {
       return resp.json();
     })
     .then(function (data) {
-      window.location.href = "/scans/" + data.id;
+      window.location.href = BASE_PATH + "/scans/" + data.id;
     })
     .catch(function (err) {
       showFormError(err.message);
@@ -58,6 +71,13 @@ function submitScan(event) {
   return false;
 }
 
+/**
+ * fieldValue returns the trimmed value of the form field with the given ID.
+ */
+function fieldValue(id) {
+  return document.getElementById(id).value.trim();
+}
+
 /**
  * toggleAllScanners toggles all scanner checkboxes.
  */
@@ -73,7 +93,7 @@ function toggleAllScanners(selectAll) {
  */
 function pollScanStatus(scanId) {
   var interval = setInterval(function () {
-    fetch("/api/v1/scans/" + scanId)
+    fetch(BASE_PATH + "/api/v1/scans/" + scanId)
       .then(function (resp) {
         return resp.json();
       })
@@ -84,6 +104,7 @@ function pollScanStatus(scanId) {
           statusEl.textContent = data.status;
           statusEl.className = "status-badge status-" + data.status;
         }
+        showPauseControls(data.status);
 
         // Update progress bar
         if (data.progress) {
@@ -97,6 +118,8 @@ function pollScanStatus(scanId) {
           }
           var bar = document.getElementById("progress-bar");
           if (bar) bar.style.width = pct + "%";
+          var track = document.getElementById("progress-track");
+          if (track) track.setAttribute("aria-valuenow", pct);
 
           var text = document.getElementById("progress-text");
           if (text) {
@@ -128,10 +151,11 @@ function pollScanStatus(scanId) {
 }
 
 /**
- * refreshScanList fetches the scan list API and updates the table.
+ * refreshScanList fetches the scan list API, with the page's filter, and
+ * updates the table.
  */
 function refreshScanList() {
-  fetch("/api/v1/scans")
+  fetch(BASE_PATH + "/api/v1/scans" + window.location.search)
     .then(function (resp) {
       return resp.json();
     })
@@ -143,7 +167,7 @@ function refreshScanList() {
       var hasRunning = false;
 
       scans.forEach(function (scan) {
-        if (scan.status === "running" || scan.status === "pending") {
+        if (scan.status === "running" || scan.status === "pending" || scan.status === "paused") {
           hasRunning = true;
         }
         var tr = document.createElement("tr");
@@ -156,7 +180,9 @@ function refreshScanList() {
         var findingCount = scan.finding_count || 0;
 
         tr.innerHTML =
-          '<td><a href="/scans/' +
+          '<td><a href="' +
+          BASE_PATH +
+          "/scans/" +
           scan.id +
           '" class="link-mono">' +
           escapeHtml(scan.id.substring(0, 8)) +
@@ -191,23 +217,52 @@ function refreshScanList() {
     });
 }
 
+// showPauseControls shows Resume for a paused scan and Pause otherwise.
+function showPauseControls(status) {
+  var pause = document.getElementById("pause-btn");
+  var resume = document.getElementById("resume-btn");
+  if (pause) pause.hidden = status === "paused";
+  if (resume) resume.hidden = status !== "paused";
+}
+
 /**
- * deleteScan deletes a scan and redirects to the scan list.
+ * verifyFinding replays the probe behind a finding in place. Without
+ * JavaScript the form posts to the page handler instead, which reloads the
+ * scan with the outcome.
  */
-function deleteScan(scanId) {
-  if (!confirm("Are you sure you want to delete this scan?")) return;
+function verifyFinding(event, scanId, fingerprint) {
+  event.preventDefault();
+  var form = event.target;
+  var button = form.querySelector("button");
+  var status = form.querySelector(".verify-status");
+  button.disabled = true;
+  status.className = "verify-status";
+  status.textContent = "Verifying…";
 
-  fetch("/api/v1/scans/" + scanId, { method: "DELETE" })
+  fetch(BASE_PATH + "/api/v1/scans/" + scanId + "/findings/" + fingerprint + "/verify", { method: "POST" })
     .then(function (resp) {
-      if (resp.ok) {
-        window.location.href = "/scans";
+      return resp.json().then(function (data) {
+        if (!resp.ok) throw new Error(data.error || "verification failed");
+        return data;
+      });
+    })
+    .then(function (data) {
+      if (data.reproduces) {
+        status.className = "verify-status verify-reproduces";
+        status.textContent = "Still reproduces";
       } else {
-        alert("Failed to delete scan.");
+        status.className = "verify-status verify-fixed";
+        status.textContent = "No longer reproduces";
       }
     })
-    .catch(function () {
-      alert("Failed to delete scan.");
+    .catch(function (err) {
+      status.className = "verify-status verify-error";
+      status.textContent = err.message;
+    })
+    .then(function () {
+      button.disabled = false;
     });
+  return false;
 }
 
 // Helpers
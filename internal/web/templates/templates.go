@@ -2,9 +2,14 @@ package templates
 
 import (
 	"embed"
+	"encoding/base64"
 	"fmt"
 	"html/template"
+	"io/fs"
 	"net/http"
+	"net/url"
+	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -17,34 +22,100 @@ var templateFS embed.FS
 // pages holds a per-page template set, each cloned from the base layout.
 var pages map[string]*template.Template
 
+// Layout holds deployment-wide settings the templates need to build links
+// and decide which actions to offer.
+type Layout struct {
+	// BasePath is the URL prefix the app is mounted under, e.g. "/hunter".
+	// It is empty when served from the root.
+	BasePath string
+	// ReadOnly hides scan creation and deletion controls.
+	ReadOnly bool
+}
+
+// layout is the active Layout, set once at server start-up via SetLayout.
+var layout Layout
+
+// SetLayout configures the deployment settings used by all page templates.
+func SetLayout(l Layout) {
+	layout = l
+}
+
+// funcMap holds the functions available to every template.
+var funcMap = template.FuncMap{
+	"severityColor":  severityColor,
+	"severityClass":  severityClass,
+	"truncateID":     truncateID,
+	"formatDuration": formatDuration,
+	"formatTime":     formatTime,
+	"countSeverity":  countSeverity,
+	"totalFindings":  totalFindings,
+	"progressPct":    progressPct,
+	"screenshots":    screenshots,
+	"formHas":        formHas,
+	"lower":          strings.ToLower,
+	"url":            withBasePath,
+	"basePath":       func() string { return layout.BasePath },
+	"readOnly":       func() bool { return layout.ReadOnly },
+}
+
+// pageNames lists the page templates, each rendered within base.html.
+var pageNames = []string{"index.html", "scans.html", "scan_detail.html", "findings.html", "not_found.html", "error.html"}
+
 func init() {
-	funcMap := template.FuncMap{
-		"severityColor":  severityColor,
-		"severityClass":  severityClass,
-		"truncateID":     truncateID,
-		"formatDuration": formatDuration,
-		"formatTime":     formatTime,
-		"countSeverity":  countSeverity,
-		"totalFindings":  totalFindings,
-		"progressPct":    progressPct,
-		"lower":          strings.ToLower,
+	var err error
+	if pages, err = parse(templateFS); err != nil {
+		panic(err)
 	}
+}
 
+// parse parses the page templates of fsys.
+func parse(fsys fs.FS) (map[string]*template.Template, error) {
 	// Parse the base layout first.
-	base := template.Must(template.New("").Funcs(funcMap).ParseFS(templateFS, "base.html"))
+	base, err := template.New("").Funcs(funcMap).ParseFS(fsys, "base.html")
+	if err != nil {
+		return nil, err
+	}
 
 	// Each page template clones the base and adds its own content block.
-	pageNames := []string{"index.html", "scans.html", "scan_detail.html", "not_found.html"}
-	pages = make(map[string]*template.Template, len(pageNames))
+	parsed := make(map[string]*template.Template, len(pageNames))
 	for _, name := range pageNames {
-		clone := template.Must(base.Clone())
-		pages[name] = template.Must(clone.ParseFS(templateFS, name))
+		clone, err := base.Clone()
+		if err != nil {
+			return nil, err
+		}
+		if parsed[name], err = clone.ParseFS(fsys, name); err != nil {
+			return nil, err
+		}
+	}
+	return parsed, nil
+}
+
+// dir, when set by SetDir, is the directory templates are read from on
+// every render instead of the embedded copies.
+var dir fs.FS
+
+// SetDir reads the templates from the directory path on every render, so
+// edits to them show on the next page load without a rebuild. It is meant
+// for developing the UI, and fails if the templates there do not parse.
+func SetDir(path string) error {
+	fsys := os.DirFS(path)
+	if _, err := parse(fsys); err != nil {
+		return fmt.Errorf("loading templates from %s: %w", path, err)
 	}
+	dir = fsys
+	return nil
 }
 
 // RenderPage executes the named page template into the response writer.
 func RenderPage(w http.ResponseWriter, name string, data interface{}) error {
-	tmpl, ok := pages[name]
+	set := pages
+	if dir != nil {
+		var err error
+		if set, err = parse(dir); err != nil {
+			return fmt.Errorf("render template %q: %w", name, err)
+		}
+	}
+	tmpl, ok := set[name]
 	if !ok {
 		return fmt.Errorf("render template %q: template not found", name)
 	}
@@ -55,6 +126,17 @@ func RenderPage(w http.ResponseWriter, name string, data interface{}) error {
 	return nil
 }
 
+// URL returns the URL of an absolute application path, under the configured
+// base path.
+func URL(path string) string {
+	return withBasePath(path)
+}
+
+// withBasePath prefixes an absolute application path with the configured base path.
+func withBasePath(path string) string {
+	return layout.BasePath + path
+}
+
 // severityColor returns a CSS color for the given severity level.
 func severityColor(s types.Severity) string {
 	switch s {
@@ -135,6 +217,24 @@ func totalFindings(results []types.ScanResult) int {
 	return n
 }
 
+// screenshots returns the screenshots among a finding's artifacts as data
+// URIs.
+func screenshots(f types.Finding) []template.URL {
+	var uris []template.URL
+	for _, a := range f.Artifacts {
+		if len(a.Screenshot) > 0 {
+			uris = append(uris, template.URL("data:image/png;base64,"+base64.StdEncoding.EncodeToString(a.Screenshot)))
+		}
+	}
+	return uris
+}
+
+// formHas reports whether a posted form holds value among the values of
+// key, as for checkboxes sharing a name.
+func formHas(form url.Values, key, value string) bool {
+	return slices.Contains(form[key], value)
+}
+
 // progressPct calculates a progress percentage from completed and total.
 func progressPct(completed, total int) int {
 	if total == 0 {
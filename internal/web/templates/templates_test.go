@@ -2,6 +2,9 @@ package templates
 
 import (
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -11,7 +14,7 @@ import (
 )
 
 func TestAllTemplatesParseWithoutError(t *testing.T) {
-	expectedPages := []string{"index.html", "scans.html", "scan_detail.html", "not_found.html"}
+	expectedPages := []string{"index.html", "scans.html", "scan_detail.html", "findings.html", "not_found.html", "error.html"}
 	for _, name := range expectedPages {
 		if _, ok := pages[name]; !ok {
 			t.Errorf("expected page template %q to be parsed", name)
@@ -51,6 +54,9 @@ func TestRenderPage_IndexContainsExpectedElements(t *testing.T) {
 	}
 	data := struct {
 		Scanners []scannerInfo
+		Agents   []struct{ Name string }
+		Error    string
+		Form     url.Values
 	}{
 		Scanners: []scannerInfo{
 			{Name: "port", Description: "TCP port scan"},
@@ -74,8 +80,11 @@ func TestRenderPage_IndexContainsExpectedElements(t *testing.T) {
 func TestRenderPage_ScansEmptyList(t *testing.T) {
 	rec := httptest.NewRecorder()
 	data := struct {
-		Jobs       []*jobs.Job
-		HasRunning bool
+		Jobs          []*jobs.Job
+		HasRunning    bool
+		HasIncomplete bool
+		Filter        jobs.Filter
+		Statuses      []jobs.JobStatus
 	}{
 		Jobs:       nil,
 		HasRunning: false,
@@ -107,8 +116,11 @@ func TestRenderPage_ScansWithJobs(t *testing.T) {
 		},
 	}
 	data := struct {
-		Jobs       []*jobs.Job
-		HasRunning bool
+		Jobs          []*jobs.Job
+		HasRunning    bool
+		HasIncomplete bool
+		Filter        jobs.Filter
+		Statuses      []jobs.JobStatus
 	}{
 		Jobs:       []*jobs.Job{j},
 		HasRunning: false,
@@ -148,11 +160,13 @@ func TestRenderPage_ScanDetailCompleted(t *testing.T) {
 						Severity:    types.SeverityHigh,
 						Evidence:    "No HSTS header found",
 						Remediation: "Add Strict-Transport-Security header",
+						References:  []types.Reference{{Title: "MDN: Strict-Transport-Security", URL: "https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Strict-Transport-Security"}},
 					},
 					{
 						Title:       "CSP Present",
 						Description: "Content-Security-Policy found",
 						Severity:    types.SeverityInfo,
+						Artifacts:   []types.Artifact{{Screenshot: []byte("PNG")}},
 					},
 				},
 			},
@@ -177,9 +191,11 @@ func TestRenderPage_ScanDetailCompleted(t *testing.T) {
 		"2 total findings",
 		"Show details",
 		"No HSTS header found",
+		`<a href="https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Strict-Transport-Security" target="_blank" rel="noopener noreferrer">MDN: Strict-Transport-Security</a>`,
 		"Download JSON",
 		"View HTML Report",
 		"Delete Scan",
+		`<img class="screenshot" src="data:image/png;base64,UE5H"`,
 	} {
 		if !strings.Contains(body, expected) {
 			t.Errorf("expected scan detail page to contain %q", expected)
@@ -241,6 +257,82 @@ func TestRenderPage_ScanDetailFailed(t *testing.T) {
 	}
 }
 
+func TestRenderPage_ScanDetailVerification(t *testing.T) {
+	rec := httptest.NewRecorder()
+	j := &jobs.Job{
+		ID:     "verified-id-1234567890",
+		Target: types.Target{Host: "example.com"},
+		Status: jobs.StatusCompleted,
+		Results: []types.ScanResult{{
+			ScannerName: "headers",
+			Findings: []types.Finding{
+				{Title: "Missing HSTS", Severity: types.SeverityHigh, Fingerprint: "aaaa"},
+				{Title: "Missing CSP", Severity: types.SeverityMedium, Fingerprint: "bbbb"},
+			},
+		}},
+	}
+	type verification struct {
+		Fingerprint string
+		Reproduces  bool
+		Error       string
+	}
+	data := struct {
+		Job          *jobs.Job
+		Verification *verification
+	}{Job: j, Verification: &verification{Fingerprint: "bbbb"}}
+	err := RenderPage(rec, "scan_detail.html", data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	body := rec.Body.String()
+	for _, expected := range []string{
+		`id="finding-bbbb"`,
+		`action="/scans/verified-id-1234567890/findings/aaaa/verify"`,
+		"No longer reproduces",
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("expected verified scan detail to contain %q", expected)
+		}
+	}
+	if strings.Contains(body, "Still reproduces") {
+		t.Error("expected only the verified finding to show an outcome")
+	}
+}
+
+func TestRenderPage_IndexRefillsRejectedForm(t *testing.T) {
+	rec := httptest.NewRecorder()
+	data := struct {
+		Scanners []struct{ Name, Description string }
+		Agents   []struct{ Name string }
+		Error    string
+		Form     url.Values
+	}{
+		Scanners: []struct{ Name, Description string }{{Name: "port"}, {Name: "headers"}},
+		Error:    "target is not authorized",
+		Form:     url.Values{"target": {"https://other.test"}, "scanners": {"headers"}, "timeout": {"30s"}},
+	}
+	err := RenderPage(rec, "index.html", data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	body := rec.Body.String()
+	for _, expected := range []string{
+		"target is not authorized",
+		`value="https://other.test"`,
+		`value="headers" checked`,
+		`value="30s" selected`,
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("expected rejected form to contain %q", expected)
+		}
+	}
+	if strings.Contains(body, `value="port" checked`) {
+		t.Error("expected unselected scanners to stay unchecked")
+	}
+}
+
 func TestRenderPage_NotFoundContainsMessage(t *testing.T) {
 	rec := httptest.NewRecorder()
 	err := RenderPage(rec, "not_found.html", struct{ Message string }{"Scan not found."})
@@ -378,3 +470,49 @@ func TestProgressPct(t *testing.T) {
 		t.Errorf("progressPct(1,3) = %d, want 33", got)
 	}
 }
+
+func TestSetDir_ReloadsOnEachRender(t *testing.T) {
+	tmp := t.TempDir()
+	for _, name := range append([]string{"base.html"}, pageNames...) {
+		data, err := templateFS.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(tmp, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := SetDir(tmp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { dir = nil })
+
+	notFound := filepath.Join(tmp, "not_found.html")
+	data, _ := os.ReadFile(notFound)
+	if err := os.WriteFile(notFound, []byte(strings.Replace(string(data), "{{.Message}}", "edited: {{.Message}}", 1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	if err := RenderPage(rec, "not_found.html", struct{ Message string }{"gone"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(rec.Body.String(), "edited: gone") {
+		t.Error("expected the edited template to be rendered")
+	}
+
+	if err := os.WriteFile(notFound, []byte("{{define \"content\"}}{{.Message"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := RenderPage(httptest.NewRecorder(), "not_found.html", struct{ Message string }{"gone"}); err == nil {
+		t.Error("expected an error for a template that does not parse")
+	}
+}
+
+func TestSetDir_MissingTemplates(t *testing.T) {
+	if err := SetDir(t.TempDir()); err == nil {
+		t.Error("expected an error for a directory without templates")
+	}
+	if dir != nil {
+		t.Error("expected the embedded templates to stay in use")
+	}
+}
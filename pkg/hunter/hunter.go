@@ -0,0 +1,263 @@
+// Package hunter embeds Hunter's scanners in other Go programs. Build a
+// Registry, describe a scan with Options, and Run it against a target to get
+// its results, with findings delivered to callbacks as each scanner
+// finishes:
+//
+//	h := hunter.New(hunter.DefaultRegistry())
+//	results, err := h.Run(ctx, "https://example.com", hunter.Options{
+//		Scanners: []string{"headers", "ssl"},
+//		OnFinding: func(scanner string, f types.Finding) {
+//			log.Printf("%s: [%s] %s", scanner, f.Severity, f.Title)
+//		},
+//	})
+//
+// Results and findings are the types of package types. Only scan targets
+// you have permission to test.
+package hunter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/buemura/hunter/internal/scanner"
+	"github.com/buemura/hunter/internal/scanner/api"
+	"github.com/buemura/hunter/internal/scanner/cloud"
+	"github.com/buemura/hunter/internal/scanner/csrf"
+	"github.com/buemura/hunter/internal/scanner/custom"
+	"github.com/buemura/hunter/internal/scanner/deserialization"
+	"github.com/buemura/hunter/internal/scanner/dirs"
+	"github.com/buemura/hunter/internal/scanner/forms"
+	"github.com/buemura/hunter/internal/scanner/headers"
+	"github.com/buemura/hunter/internal/scanner/mixedcontent"
+	"github.com/buemura/hunter/internal/scanner/port"
+	"github.com/buemura/hunter/internal/scanner/protocols"
+	"github.com/buemura/hunter/internal/scanner/redirects"
+	"github.com/buemura/hunter/internal/scanner/ssl"
+	"github.com/buemura/hunter/internal/scanner/subdomain"
+	"github.com/buemura/hunter/internal/scanner/tech"
+	"github.com/buemura/hunter/internal/scanner/vuln"
+	"github.com/buemura/hunter/pkg/types"
+)
+
+// DefaultTimeout is the per-request timeout of scans that set none.
+const DefaultTimeout = 5 * time.Second
+
+// Scanner is a scan module. Implement it and register it to run checks of
+// your own alongside the built-in scanners.
+type Scanner = scanner.Scanner
+
+// ScannerOptions are the options a Scanner runs with, built from Options.
+type ScannerOptions = scanner.Options
+
+// Registry holds the scanners a Hunter can run, by name.
+type Registry = scanner.Registry
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return scanner.NewRegistry()
+}
+
+// DefaultRegistry returns a registry of every built-in scanner, as `hunter
+// all` runs them.
+func DefaultRegistry() *Registry {
+	reg := scanner.NewRegistry()
+	// Web scanners
+	reg.Register(port.New())
+	reg.Register(headers.New())
+	reg.Register(ssl.New())
+	reg.Register(protocols.New())
+	reg.Register(cloud.New())
+	reg.Register(dirs.New())
+	reg.Register(vuln.New())
+	reg.Register(forms.New())
+	reg.Register(csrf.New())
+	reg.Register(tech.New())
+	reg.Register(redirects.New())
+	reg.Register(mixedcontent.New())
+	reg.Register(deserialization.New())
+	reg.Register(custom.New())
+	// API scanners
+	reg.Register(api.New())
+	reg.Register(api.NewAuthScanner())
+	reg.Register(api.NewCORSScanner())
+	reg.Register(api.NewDataExposureScanner())
+	reg.Register(api.NewRateLimitScanner())
+	// Recon scanners
+	reg.Register(subdomain.New())
+	return reg
+}
+
+// Options describe a scan. The zero value runs every registered scanner at
+// normal intensity with the default timeout.
+type Options struct {
+	// Scanners names the scanners to run; empty runs all of them.
+	Scanners []string
+	// Concurrency bounds the requests a scanner sends at once; zero lets
+	// each scanner choose.
+	Concurrency int
+	// Timeout bounds each request; zero means DefaultTimeout.
+	Timeout time.Duration
+	// MaxDuration, when positive, bounds the whole scan; scanners still
+	// running when their share of it runs out return what they found.
+	MaxDuration time.Duration
+	// Intensity is "safe", "normal", or "aggressive", scaling how many
+	// requests, paths, and payloads scanners send; empty means normal.
+	Intensity string
+	// IPVersion restricts the scan to IPv4 (4) or IPv6 (6) addresses of the
+	// target; zero allows either.
+	IPVersion int
+	// Resolver is a DNS server to resolve the target with, e.g. "1.1.1.1",
+	// and Resolve pins host names to addresses, as "host:address".
+	Resolver string
+	Resolve  []string
+	// NoPreflight skips the probe that detects whether the target is up and
+	// what it serves before the scanners run.
+	NoPreflight bool
+	// Crawl spiders the target first, up to CrawlDepth links away and
+	// CrawlPages pages, zero meaning the defaults, so scanners test the
+	// pages and forms it finds.
+	Crawl      bool
+	CrawlDepth int
+	CrawlPages int
+	// Redact masks credentials, tokens, cookies, and IP addresses in the
+	// evidence and artifacts of findings.
+	Redact bool
+	// ScannerArgs holds per-scanner settings keyed by scanner name, like the
+	// scanners section of the config file.
+	ScannerArgs map[string]map[string]interface{}
+	// SeverityOverrides remaps finding severities by rule ID or title, like
+	// the severity_overrides section of the config file.
+	SeverityOverrides map[string]string
+	// Engagement is recorded in the metadata of every result.
+	Engagement types.Engagement
+	// Authenticate, when set, adds credentials to the requests of scanners
+	// that test authenticated pages.
+	Authenticate func(req *http.Request)
+	// AuthHosts are the hosts besides the target's that Authenticate
+	// applies to, such as a login host. Requests to other hosts are sent
+	// without credentials.
+	AuthHosts []string
+
+	// OnFinding, when set, is called for each finding as its scanner
+	// finishes, after severity overrides.
+	OnFinding func(scanner string, finding types.Finding)
+	// OnResult, when set, is called with each scanner's result as it
+	// finishes.
+	OnResult func(result types.ScanResult)
+	// OnProgress, when set, receives the progress of scanners that work
+	// through a known number of units, such as ports or paths.
+	OnProgress func(scanner string, done, total int)
+	// OnLog, when set, receives the scanners' diagnostic lines.
+	OnLog func(scanner, message string)
+}
+
+// Hunter runs scans with the scanners of a registry. It is safe for
+// concurrent use.
+type Hunter struct {
+	registry *Registry
+	runner   *scanner.Runner
+}
+
+// New returns a Hunter running the scanners of reg; nil means
+// DefaultRegistry.
+func New(reg *Registry) *Hunter {
+	if reg == nil {
+		reg = DefaultRegistry()
+	}
+	return &Hunter{registry: reg, runner: scanner.NewRunner(reg)}
+}
+
+// Scanners returns the names of the registered scanners, sorted.
+func (h *Hunter) Scanners() []string {
+	all := h.registry.All()
+	names := make([]string, len(all))
+	for i, s := range all {
+		names[i] = s.Name()
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run scans target, a URL, host, or IP address, and returns the result of
+// each scanner in the order they were named. It returns an error only for a
+// target or options it cannot scan with; a scanner that fails is reported
+// in its result's Error. Run stops early when ctx is done.
+func (h *Hunter) Run(ctx context.Context, target string, opts Options) ([]types.ScanResult, error) {
+	t, err := types.ParseTarget(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target: %w", err)
+	}
+	names := opts.Scanners
+	if len(names) == 0 {
+		names = h.Scanners()
+	}
+	for _, name := range names {
+		if _, err := h.registry.Get(name); err != nil {
+			return nil, err
+		}
+	}
+	scanOpts, err := opts.scannerOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	hooks := scanner.Hooks{
+		OnProgress:        opts.OnProgress,
+		OnFinding:         opts.OnFinding,
+		OnScannerComplete: opts.OnResult,
+	}
+	if opts.OnLog != nil {
+		hooks.OnLog = func(e scanner.LogEntry) { opts.OnLog(e.Scanner, e.Message) }
+	}
+	runner := h.runner.With(hooks)
+	return runner.RunAll(ctx, names, t, scanOpts), nil
+}
+
+// scannerOptions builds the options the scanners run with.
+func (o Options) scannerOptions() (scanner.Options, error) {
+	overrides, err := scanner.ParseSeverityOverrides(o.SeverityOverrides)
+	if err != nil {
+		return scanner.Options{}, err
+	}
+	intensity, err := scanner.ParseIntensity(o.Intensity)
+	if err != nil {
+		return scanner.Options{}, err
+	}
+	if o.IPVersion != 0 && o.IPVersion != 4 && o.IPVersion != 6 {
+		return scanner.Options{}, fmt.Errorf("invalid IP version %d (available: 4, 6, or 0 for any)", o.IPVersion)
+	}
+
+	opts := scanner.Options{
+		Concurrency:  o.Concurrency,
+		Timeout:      o.Timeout,
+		ExtraArgs:    map[string]interface{}{},
+		ScannerArgs:  o.ScannerArgs,
+		Overrides:    overrides,
+		Authenticate: o.Authenticate,
+		AuthHosts:    o.AuthHosts,
+		MaxDuration:  o.MaxDuration,
+		Intensity:    intensity,
+		IPVersion:    o.IPVersion,
+		Redact:       o.Redact,
+		Engagement:   o.Engagement,
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if o.Resolver != "" || len(o.Resolve) > 0 {
+		if opts.Resolver, err = scanner.NewResolver(o.Resolver, o.Resolve); err != nil {
+			return scanner.Options{}, err
+		}
+	}
+	opts.Transport = scanner.BaseTransport(opts)
+	if !o.NoPreflight {
+		opts.Preflight = scanner.NewPreflight()
+	}
+	if o.Crawl {
+		opts.Crawler = scanner.NewCrawler(o.CrawlDepth, o.CrawlPages)
+	}
+	return opts, nil
+}
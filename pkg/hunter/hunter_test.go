@@ -0,0 +1,91 @@
+package hunter
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/buemura/hunter/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockScanner struct{ name string }
+
+func (m *mockScanner) Name() string        { return m.name }
+func (m *mockScanner) Description() string { return "mock" }
+func (m *mockScanner) Run(_ context.Context, target types.Target, opts ScannerOptions) (*types.ScanResult, error) {
+	opts.ReportProgress(m.name, 1, 1)
+	return &types.ScanResult{
+		ScannerName: m.name,
+		Target:      target,
+		Findings:    []types.Finding{{Title: m.name + " finding", Severity: types.SeverityLow}},
+	}, nil
+}
+
+func newHunter() *Hunter {
+	reg := NewRegistry()
+	reg.Register(&mockScanner{name: "one"})
+	reg.Register(&mockScanner{name: "two"})
+	return New(reg)
+}
+
+func TestRun(t *testing.T) {
+	h := newHunter()
+	assert.Equal(t, []string{"one", "two"}, h.Scanners())
+
+	var mu sync.Mutex
+	var found []string
+	var progress int
+	results, err := h.Run(context.Background(), "https://example.com", Options{
+		NoPreflight:       true,
+		SeverityOverrides: map[string]string{"one finding": "HIGH"},
+		Engagement:        types.Engagement{Client: "ACME"},
+		OnFinding: func(scanner string, f types.Finding) {
+			mu.Lock()
+			defer mu.Unlock()
+			found = append(found, scanner+": "+f.Title+" "+string(f.Severity))
+		},
+		OnProgress: func(string, int, int) {
+			mu.Lock()
+			defer mu.Unlock()
+			progress++
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "example.com", results[0].Target.Host)
+	assert.Equal(t, "ACME", results[0].Metadata["engagement_client"])
+	assert.ElementsMatch(t, []string{"one: one finding HIGH", "two: two finding LOW"}, found)
+	assert.Equal(t, 2, progress)
+}
+
+func TestRun_SelectedScanners(t *testing.T) {
+	results, err := newHunter().Run(context.Background(), "example.com", Options{Scanners: []string{"two"}, NoPreflight: true})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "two", results[0].ScannerName)
+}
+
+func TestRun_Errors(t *testing.T) {
+	h := newHunter()
+	ctx := context.Background()
+
+	_, err := h.Run(ctx, "", Options{})
+	assert.ErrorContains(t, err, "invalid target")
+	_, err = h.Run(ctx, "example.com", Options{Scanners: []string{"three"}})
+	assert.Error(t, err)
+	_, err = h.Run(ctx, "example.com", Options{Intensity: "reckless"})
+	assert.ErrorContains(t, err, "invalid intensity")
+	_, err = h.Run(ctx, "example.com", Options{IPVersion: 5})
+	assert.ErrorContains(t, err, "invalid IP version")
+	_, err = h.Run(ctx, "example.com", Options{Resolver: "not a server:x:y"})
+	assert.Error(t, err)
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	names := New(nil).Scanners()
+	for _, name := range []string{"port", "headers", "ssl", "dirs", "vuln", "api-discover", "subdomain"} {
+		assert.Contains(t, names, name)
+	}
+}
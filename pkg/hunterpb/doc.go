@@ -0,0 +1,4 @@
+// Package hunterpb is the Go code generated from proto/hunter/v1/hunter.proto
+// for clients and servers of the Hunter gRPC API. Regenerate it with
+// `make proto`.
+package hunterpb
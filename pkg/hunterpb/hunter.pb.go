@@ -0,0 +1,1824 @@
+// The Hunter gRPC API: the scans of `hunter serve`, served alongside its
+// REST API with `--grpc-addr`. Regenerate the Go code in pkg/hunterpb with
+// `make proto` after changing this file.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: hunter/v1/hunter.proto
+
+package hunterpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Severity int32
+
+const (
+	Severity_SEVERITY_UNSPECIFIED Severity = 0
+	Severity_SEVERITY_CRITICAL    Severity = 1
+	Severity_SEVERITY_HIGH        Severity = 2
+	Severity_SEVERITY_MEDIUM      Severity = 3
+	Severity_SEVERITY_LOW         Severity = 4
+	Severity_SEVERITY_INFO        Severity = 5
+)
+
+// Enum value maps for Severity.
+var (
+	Severity_name = map[int32]string{
+		0: "SEVERITY_UNSPECIFIED",
+		1: "SEVERITY_CRITICAL",
+		2: "SEVERITY_HIGH",
+		3: "SEVERITY_MEDIUM",
+		4: "SEVERITY_LOW",
+		5: "SEVERITY_INFO",
+	}
+	Severity_value = map[string]int32{
+		"SEVERITY_UNSPECIFIED": 0,
+		"SEVERITY_CRITICAL":    1,
+		"SEVERITY_HIGH":        2,
+		"SEVERITY_MEDIUM":      3,
+		"SEVERITY_LOW":         4,
+		"SEVERITY_INFO":        5,
+	}
+)
+
+func (x Severity) Enum() *Severity {
+	p := new(Severity)
+	*p = x
+	return p
+}
+
+func (x Severity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Severity) Descriptor() protoreflect.EnumDescriptor {
+	return file_hunter_v1_hunter_proto_enumTypes[0].Descriptor()
+}
+
+func (Severity) Type() protoreflect.EnumType {
+	return &file_hunter_v1_hunter_proto_enumTypes[0]
+}
+
+func (x Severity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Severity.Descriptor instead.
+func (Severity) EnumDescriptor() ([]byte, []int) {
+	return file_hunter_v1_hunter_proto_rawDescGZIP(), []int{0}
+}
+
+type JobStatus int32
+
+const (
+	JobStatus_JOB_STATUS_UNSPECIFIED JobStatus = 0
+	JobStatus_JOB_STATUS_PENDING     JobStatus = 1
+	JobStatus_JOB_STATUS_RUNNING     JobStatus = 2
+	JobStatus_JOB_STATUS_PAUSED      JobStatus = 3
+	JobStatus_JOB_STATUS_COMPLETED   JobStatus = 4
+	JobStatus_JOB_STATUS_FAILED      JobStatus = 5
+)
+
+// Enum value maps for JobStatus.
+var (
+	JobStatus_name = map[int32]string{
+		0: "JOB_STATUS_UNSPECIFIED",
+		1: "JOB_STATUS_PENDING",
+		2: "JOB_STATUS_RUNNING",
+		3: "JOB_STATUS_PAUSED",
+		4: "JOB_STATUS_COMPLETED",
+		5: "JOB_STATUS_FAILED",
+	}
+	JobStatus_value = map[string]int32{
+		"JOB_STATUS_UNSPECIFIED": 0,
+		"JOB_STATUS_PENDING":     1,
+		"JOB_STATUS_RUNNING":     2,
+		"JOB_STATUS_PAUSED":      3,
+		"JOB_STATUS_COMPLETED":   4,
+		"JOB_STATUS_FAILED":      5,
+	}
+)
+
+func (x JobStatus) Enum() *JobStatus {
+	p := new(JobStatus)
+	*p = x
+	return p
+}
+
+func (x JobStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (JobStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_hunter_v1_hunter_proto_enumTypes[1].Descriptor()
+}
+
+func (JobStatus) Type() protoreflect.EnumType {
+	return &file_hunter_v1_hunter_proto_enumTypes[1]
+}
+
+func (x JobStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use JobStatus.Descriptor instead.
+func (JobStatus) EnumDescriptor() ([]byte, []int) {
+	return file_hunter_v1_hunter_proto_rawDescGZIP(), []int{1}
+}
+
+// Target is what a scan runs against.
+type Target struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Host          string                 `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Ports         []int32                `protobuf:"varint,2,rep,packed,name=ports,proto3" json:"ports,omitempty"`
+	Url           string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	Scheme        string                 `protobuf:"bytes,4,opt,name=scheme,proto3" json:"scheme,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Target) Reset() {
+	*x = Target{}
+	mi := &file_hunter_v1_hunter_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Target) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Target) ProtoMessage() {}
+
+func (x *Target) ProtoReflect() protoreflect.Message {
+	mi := &file_hunter_v1_hunter_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Target.ProtoReflect.Descriptor instead.
+func (*Target) Descriptor() ([]byte, []int) {
+	return file_hunter_v1_hunter_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Target) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *Target) GetPorts() []int32 {
+	if x != nil {
+		return x.Ports
+	}
+	return nil
+}
+
+func (x *Target) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Target) GetScheme() string {
+	if x != nil {
+		return x.Scheme
+	}
+	return ""
+}
+
+// Engagement identifies the engagement a scan is run under.
+type Engagement struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Client        string                 `protobuf:"bytes,1,opt,name=client,proto3" json:"client,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Tester        string                 `protobuf:"bytes,3,opt,name=tester,proto3" json:"tester,omitempty"`
+	Authorization string                 `protobuf:"bytes,4,opt,name=authorization,proto3" json:"authorization,omitempty"`
+	Notes         string                 `protobuf:"bytes,5,opt,name=notes,proto3" json:"notes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Engagement) Reset() {
+	*x = Engagement{}
+	mi := &file_hunter_v1_hunter_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Engagement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Engagement) ProtoMessage() {}
+
+func (x *Engagement) ProtoReflect() protoreflect.Message {
+	mi := &file_hunter_v1_hunter_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Engagement.ProtoReflect.Descriptor instead.
+func (*Engagement) Descriptor() ([]byte, []int) {
+	return file_hunter_v1_hunter_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Engagement) GetClient() string {
+	if x != nil {
+		return x.Client
+	}
+	return ""
+}
+
+func (x *Engagement) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Engagement) GetTester() string {
+	if x != nil {
+		return x.Tester
+	}
+	return ""
+}
+
+func (x *Engagement) GetAuthorization() string {
+	if x != nil {
+		return x.Authorization
+	}
+	return ""
+}
+
+func (x *Engagement) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+type Reference struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reference) Reset() {
+	*x = Reference{}
+	mi := &file_hunter_v1_hunter_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reference) ProtoMessage() {}
+
+func (x *Reference) ProtoReflect() protoreflect.Message {
+	mi := &file_hunter_v1_hunter_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reference.ProtoReflect.Descriptor instead.
+func (*Reference) Descriptor() ([]byte, []int) {
+	return file_hunter_v1_hunter_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Reference) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Reference) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+// Artifact is a raw HTTP exchange a finding was based on.
+type Artifact struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Request       string                 `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	Response      string                 `protobuf:"bytes,2,opt,name=response,proto3" json:"response,omitempty"`
+	Truncated     bool                   `protobuf:"varint,3,opt,name=truncated,proto3" json:"truncated,omitempty"`
+	Screenshot    []byte                 `protobuf:"bytes,4,opt,name=screenshot,proto3" json:"screenshot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Artifact) Reset() {
+	*x = Artifact{}
+	mi := &file_hunter_v1_hunter_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Artifact) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Artifact) ProtoMessage() {}
+
+func (x *Artifact) ProtoReflect() protoreflect.Message {
+	mi := &file_hunter_v1_hunter_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Artifact.ProtoReflect.Descriptor instead.
+func (*Artifact) Descriptor() ([]byte, []int) {
+	return file_hunter_v1_hunter_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Artifact) GetRequest() string {
+	if x != nil {
+		return x.Request
+	}
+	return ""
+}
+
+func (x *Artifact) GetResponse() string {
+	if x != nil {
+		return x.Response
+	}
+	return ""
+}
+
+func (x *Artifact) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+func (x *Artifact) GetScreenshot() []byte {
+	if x != nil {
+		return x.Screenshot
+	}
+	return nil
+}
+
+// Finding is a single discovered issue or data point.
+type Finding struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Severity      Severity               `protobuf:"varint,3,opt,name=severity,proto3,enum=hunter.v1.Severity" json:"severity,omitempty"`
+	Evidence      string                 `protobuf:"bytes,4,opt,name=evidence,proto3" json:"evidence,omitempty"`
+	Remediation   string                 `protobuf:"bytes,5,opt,name=remediation,proto3" json:"remediation,omitempty"`
+	References    []*Reference           `protobuf:"bytes,6,rep,name=references,proto3" json:"references,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,7,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Artifacts     []*Artifact            `protobuf:"bytes,8,rep,name=artifacts,proto3" json:"artifacts,omitempty"`
+	Fingerprint   string                 `protobuf:"bytes,9,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Finding) Reset() {
+	*x = Finding{}
+	mi := &file_hunter_v1_hunter_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Finding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Finding) ProtoMessage() {}
+
+func (x *Finding) ProtoReflect() protoreflect.Message {
+	mi := &file_hunter_v1_hunter_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Finding.ProtoReflect.Descriptor instead.
+func (*Finding) Descriptor() ([]byte, []int) {
+	return file_hunter_v1_hunter_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Finding) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Finding) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Finding) GetSeverity() Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Severity_SEVERITY_UNSPECIFIED
+}
+
+func (x *Finding) GetEvidence() string {
+	if x != nil {
+		return x.Evidence
+	}
+	return ""
+}
+
+func (x *Finding) GetRemediation() string {
+	if x != nil {
+		return x.Remediation
+	}
+	return ""
+}
+
+func (x *Finding) GetReferences() []*Reference {
+	if x != nil {
+		return x.References
+	}
+	return nil
+}
+
+func (x *Finding) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Finding) GetArtifacts() []*Artifact {
+	if x != nil {
+		return x.Artifacts
+	}
+	return nil
+}
+
+func (x *Finding) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
+// ScanResult is the output of one scanner.
+type ScanResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Scanner       string                 `protobuf:"bytes,1,opt,name=scanner,proto3" json:"scanner,omitempty"`
+	Target        *Target                `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	Findings      []*Finding             `protobuf:"bytes,5,rep,name=findings,proto3" json:"findings,omitempty"`
+	Error         string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,7,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScanResult) Reset() {
+	*x = ScanResult{}
+	mi := &file_hunter_v1_hunter_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScanResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScanResult) ProtoMessage() {}
+
+func (x *ScanResult) ProtoReflect() protoreflect.Message {
+	mi := &file_hunter_v1_hunter_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScanResult.ProtoReflect.Descriptor instead.
+func (*ScanResult) Descriptor() ([]byte, []int) {
+	return file_hunter_v1_hunter_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ScanResult) GetScanner() string {
+	if x != nil {
+		return x.Scanner
+	}
+	return ""
+}
+
+func (x *ScanResult) GetTarget() *Target {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *ScanResult) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *ScanResult) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *ScanResult) GetFindings() []*Finding {
+	if x != nil {
+		return x.Findings
+	}
+	return nil
+}
+
+func (x *ScanResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ScanResult) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type Progress struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	TotalScanners     int32                  `protobuf:"varint,1,opt,name=total_scanners,json=totalScanners,proto3" json:"total_scanners,omitempty"`
+	CompletedScanners int32                  `protobuf:"varint,2,opt,name=completed_scanners,json=completedScanners,proto3" json:"completed_scanners,omitempty"`
+	CurrentScanner    string                 `protobuf:"bytes,3,opt,name=current_scanner,json=currentScanner,proto3" json:"current_scanner,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Progress) Reset() {
+	*x = Progress{}
+	mi := &file_hunter_v1_hunter_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Progress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Progress) ProtoMessage() {}
+
+func (x *Progress) ProtoReflect() protoreflect.Message {
+	mi := &file_hunter_v1_hunter_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Progress.ProtoReflect.Descriptor instead.
+func (*Progress) Descriptor() ([]byte, []int) {
+	return file_hunter_v1_hunter_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Progress) GetTotalScanners() int32 {
+	if x != nil {
+		return x.TotalScanners
+	}
+	return 0
+}
+
+func (x *Progress) GetCompletedScanners() int32 {
+	if x != nil {
+		return x.CompletedScanners
+	}
+	return 0
+}
+
+func (x *Progress) GetCurrentScanner() string {
+	if x != nil {
+		return x.CurrentScanner
+	}
+	return ""
+}
+
+// Job is a scan.
+type Job struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Target      *Target                `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	Scanners    []string               `protobuf:"bytes,3,rep,name=scanners,proto3" json:"scanners,omitempty"`
+	Status      JobStatus              `protobuf:"varint,4,opt,name=status,proto3,enum=hunter.v1.JobStatus" json:"status,omitempty"`
+	Results     []*ScanResult          `protobuf:"bytes,5,rep,name=results,proto3" json:"results,omitempty"`
+	Error       string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	StartedAt   *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	CompletedAt *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	Progress    *Progress              `protobuf:"bytes,10,opt,name=progress,proto3" json:"progress,omitempty"`
+	Engagement  *Engagement            `protobuf:"bytes,11,opt,name=engagement,proto3" json:"engagement,omitempty"`
+	// agent names the agent the scan was dispatched to, if any.
+	Agent         string `protobuf:"bytes,12,opt,name=agent,proto3" json:"agent,omitempty"`
+	FindingCount  int32  `protobuf:"varint,13,opt,name=finding_count,json=findingCount,proto3" json:"finding_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Job) Reset() {
+	*x = Job{}
+	mi := &file_hunter_v1_hunter_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Job) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Job) ProtoMessage() {}
+
+func (x *Job) ProtoReflect() protoreflect.Message {
+	mi := &file_hunter_v1_hunter_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Job.ProtoReflect.Descriptor instead.
+func (*Job) Descriptor() ([]byte, []int) {
+	return file_hunter_v1_hunter_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Job) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Job) GetTarget() *Target {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *Job) GetScanners() []string {
+	if x != nil {
+		return x.Scanners
+	}
+	return nil
+}
+
+func (x *Job) GetStatus() JobStatus {
+	if x != nil {
+		return x.Status
+	}
+	return JobStatus_JOB_STATUS_UNSPECIFIED
+}
+
+func (x *Job) GetResults() []*ScanResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *Job) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Job) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Job) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *Job) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *Job) GetProgress() *Progress {
+	if x != nil {
+		return x.Progress
+	}
+	return nil
+}
+
+func (x *Job) GetEngagement() *Engagement {
+	if x != nil {
+		return x.Engagement
+	}
+	return nil
+}
+
+func (x *Job) GetAgent() string {
+	if x != nil {
+		return x.Agent
+	}
+	return ""
+}
+
+func (x *Job) GetFindingCount() int32 {
+	if x != nil {
+		return x.FindingCount
+	}
+	return 0
+}
+
+type Scanner struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Scanner) Reset() {
+	*x = Scanner{}
+	mi := &file_hunter_v1_hunter_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Scanner) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Scanner) ProtoMessage() {}
+
+func (x *Scanner) ProtoReflect() protoreflect.Message {
+	mi := &file_hunter_v1_hunter_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Scanner.ProtoReflect.Descriptor instead.
+func (*Scanner) Descriptor() ([]byte, []int) {
+	return file_hunter_v1_hunter_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Scanner) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Scanner) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type ListScannersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScannersRequest) Reset() {
+	*x = ListScannersRequest{}
+	mi := &file_hunter_v1_hunter_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScannersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScannersRequest) ProtoMessage() {}
+
+func (x *ListScannersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hunter_v1_hunter_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScannersRequest.ProtoReflect.Descriptor instead.
+func (*ListScannersRequest) Descriptor() ([]byte, []int) {
+	return file_hunter_v1_hunter_proto_rawDescGZIP(), []int{9}
+}
+
+type ListScannersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Scanners      []*Scanner             `protobuf:"bytes,1,rep,name=scanners,proto3" json:"scanners,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScannersResponse) Reset() {
+	*x = ListScannersResponse{}
+	mi := &file_hunter_v1_hunter_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScannersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScannersResponse) ProtoMessage() {}
+
+func (x *ListScannersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hunter_v1_hunter_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScannersResponse.ProtoReflect.Descriptor instead.
+func (*ListScannersResponse) Descriptor() ([]byte, []int) {
+	return file_hunter_v1_hunter_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListScannersResponse) GetScanners() []*Scanner {
+	if x != nil {
+		return x.Scanners
+	}
+	return nil
+}
+
+// CreateScanRequest mirrors the body of POST /api/v1/scans.
+type CreateScanRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// target is a URL, host, or IP address.
+	Target string `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+	// scanners to run; empty runs all of them.
+	Scanners []string `protobuf:"bytes,2,rep,name=scanners,proto3" json:"scanners,omitempty"`
+	// profile names a scan profile of the server's config to run instead.
+	Profile     string               `protobuf:"bytes,3,opt,name=profile,proto3" json:"profile,omitempty"`
+	Concurrency int32                `protobuf:"varint,4,opt,name=concurrency,proto3" json:"concurrency,omitempty"`
+	Timeout     *durationpb.Duration `protobuf:"bytes,5,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	MaxDuration *durationpb.Duration `protobuf:"bytes,6,opt,name=max_duration,json=maxDuration,proto3" json:"max_duration,omitempty"`
+	NoPreflight bool                 `protobuf:"varint,7,opt,name=no_preflight,json=noPreflight,proto3" json:"no_preflight,omitempty"`
+	Intensity   string               `protobuf:"bytes,8,opt,name=intensity,proto3" json:"intensity,omitempty"`
+	IpVersion   string               `protobuf:"bytes,9,opt,name=ip_version,json=ipVersion,proto3" json:"ip_version,omitempty"`
+	Resolver    string               `protobuf:"bytes,10,opt,name=resolver,proto3" json:"resolver,omitempty"`
+	Resolve     []string             `protobuf:"bytes,11,rep,name=resolve,proto3" json:"resolve,omitempty"`
+	Crawl       bool                 `protobuf:"varint,12,opt,name=crawl,proto3" json:"crawl,omitempty"`
+	CrawlDepth  int32                `protobuf:"varint,13,opt,name=crawl_depth,json=crawlDepth,proto3" json:"crawl_depth,omitempty"`
+	CrawlPages  int32                `protobuf:"varint,14,opt,name=crawl_pages,json=crawlPages,proto3" json:"crawl_pages,omitempty"`
+	Redact      bool                 `protobuf:"varint,15,opt,name=redact,proto3" json:"redact,omitempty"`
+	Engagement  *Engagement          `protobuf:"bytes,16,opt,name=engagement,proto3" json:"engagement,omitempty"`
+	// i_am_authorized acknowledges permission to test a target outside the
+	// server's authorized_targets.
+	IAmAuthorized bool `protobuf:"varint,17,opt,name=i_am_authorized,json=iAmAuthorized,proto3" json:"i_am_authorized,omitempty"`
+	// agent, if set, runs the scan on the named agent.
+	Agent         string `protobuf:"bytes,18,opt,name=agent,proto3" json:"agent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateScanRequest) Reset() {
+	*x = CreateScanRequest{}
+	mi := &file_hunter_v1_hunter_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateScanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateScanRequest) ProtoMessage() {}
+
+func (x *CreateScanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hunter_v1_hunter_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateScanRequest.ProtoReflect.Descriptor instead.
+func (*CreateScanRequest) Descriptor() ([]byte, []int) {
+	return file_hunter_v1_hunter_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CreateScanRequest) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *CreateScanRequest) GetScanners() []string {
+	if x != nil {
+		return x.Scanners
+	}
+	return nil
+}
+
+func (x *CreateScanRequest) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *CreateScanRequest) GetConcurrency() int32 {
+	if x != nil {
+		return x.Concurrency
+	}
+	return 0
+}
+
+func (x *CreateScanRequest) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *CreateScanRequest) GetMaxDuration() *durationpb.Duration {
+	if x != nil {
+		return x.MaxDuration
+	}
+	return nil
+}
+
+func (x *CreateScanRequest) GetNoPreflight() bool {
+	if x != nil {
+		return x.NoPreflight
+	}
+	return false
+}
+
+func (x *CreateScanRequest) GetIntensity() string {
+	if x != nil {
+		return x.Intensity
+	}
+	return ""
+}
+
+func (x *CreateScanRequest) GetIpVersion() string {
+	if x != nil {
+		return x.IpVersion
+	}
+	return ""
+}
+
+func (x *CreateScanRequest) GetResolver() string {
+	if x != nil {
+		return x.Resolver
+	}
+	return ""
+}
+
+func (x *CreateScanRequest) GetResolve() []string {
+	if x != nil {
+		return x.Resolve
+	}
+	return nil
+}
+
+func (x *CreateScanRequest) GetCrawl() bool {
+	if x != nil {
+		return x.Crawl
+	}
+	return false
+}
+
+func (x *CreateScanRequest) GetCrawlDepth() int32 {
+	if x != nil {
+		return x.CrawlDepth
+	}
+	return 0
+}
+
+func (x *CreateScanRequest) GetCrawlPages() int32 {
+	if x != nil {
+		return x.CrawlPages
+	}
+	return 0
+}
+
+func (x *CreateScanRequest) GetRedact() bool {
+	if x != nil {
+		return x.Redact
+	}
+	return false
+}
+
+func (x *CreateScanRequest) GetEngagement() *Engagement {
+	if x != nil {
+		return x.Engagement
+	}
+	return nil
+}
+
+func (x *CreateScanRequest) GetIAmAuthorized() bool {
+	if x != nil {
+		return x.IAmAuthorized
+	}
+	return false
+}
+
+func (x *CreateScanRequest) GetAgent() string {
+	if x != nil {
+		return x.Agent
+	}
+	return ""
+}
+
+type ListScansRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScansRequest) Reset() {
+	*x = ListScansRequest{}
+	mi := &file_hunter_v1_hunter_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScansRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScansRequest) ProtoMessage() {}
+
+func (x *ListScansRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hunter_v1_hunter_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScansRequest.ProtoReflect.Descriptor instead.
+func (*ListScansRequest) Descriptor() ([]byte, []int) {
+	return file_hunter_v1_hunter_proto_rawDescGZIP(), []int{12}
+}
+
+type ListScansResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// scans are listed without their results; see finding_count.
+	Scans         []*Job `protobuf:"bytes,1,rep,name=scans,proto3" json:"scans,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScansResponse) Reset() {
+	*x = ListScansResponse{}
+	mi := &file_hunter_v1_hunter_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScansResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScansResponse) ProtoMessage() {}
+
+func (x *ListScansResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hunter_v1_hunter_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScansResponse.ProtoReflect.Descriptor instead.
+func (*ListScansResponse) Descriptor() ([]byte, []int) {
+	return file_hunter_v1_hunter_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListScansResponse) GetScans() []*Job {
+	if x != nil {
+		return x.Scans
+	}
+	return nil
+}
+
+type GetScanRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// artifacts includes the raw exchanges behind findings.
+	Artifacts     bool `protobuf:"varint,2,opt,name=artifacts,proto3" json:"artifacts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetScanRequest) Reset() {
+	*x = GetScanRequest{}
+	mi := &file_hunter_v1_hunter_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetScanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetScanRequest) ProtoMessage() {}
+
+func (x *GetScanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hunter_v1_hunter_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetScanRequest.ProtoReflect.Descriptor instead.
+func (*GetScanRequest) Descriptor() ([]byte, []int) {
+	return file_hunter_v1_hunter_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetScanRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetScanRequest) GetArtifacts() bool {
+	if x != nil {
+		return x.Artifacts
+	}
+	return false
+}
+
+type WatchScanRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchScanRequest) Reset() {
+	*x = WatchScanRequest{}
+	mi := &file_hunter_v1_hunter_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchScanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchScanRequest) ProtoMessage() {}
+
+func (x *WatchScanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hunter_v1_hunter_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchScanRequest.ProtoReflect.Descriptor instead.
+func (*WatchScanRequest) Descriptor() ([]byte, []int) {
+	return file_hunter_v1_hunter_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *WatchScanRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ScanRef struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScanRef) Reset() {
+	*x = ScanRef{}
+	mi := &file_hunter_v1_hunter_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScanRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScanRef) ProtoMessage() {}
+
+func (x *ScanRef) ProtoReflect() protoreflect.Message {
+	mi := &file_hunter_v1_hunter_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScanRef.ProtoReflect.Descriptor instead.
+func (*ScanRef) Descriptor() ([]byte, []int) {
+	return file_hunter_v1_hunter_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ScanRef) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteScanResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteScanResponse) Reset() {
+	*x = DeleteScanResponse{}
+	mi := &file_hunter_v1_hunter_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteScanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteScanResponse) ProtoMessage() {}
+
+func (x *DeleteScanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hunter_v1_hunter_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteScanResponse.ProtoReflect.Descriptor instead.
+func (*DeleteScanResponse) Descriptor() ([]byte, []int) {
+	return file_hunter_v1_hunter_proto_rawDescGZIP(), []int{17}
+}
+
+// FindingEvent is a finding as its scanner finished.
+type FindingEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Scanner       string                 `protobuf:"bytes,1,opt,name=scanner,proto3" json:"scanner,omitempty"`
+	Finding       *Finding               `protobuf:"bytes,2,opt,name=finding,proto3" json:"finding,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindingEvent) Reset() {
+	*x = FindingEvent{}
+	mi := &file_hunter_v1_hunter_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindingEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindingEvent) ProtoMessage() {}
+
+func (x *FindingEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_hunter_v1_hunter_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindingEvent.ProtoReflect.Descriptor instead.
+func (*FindingEvent) Descriptor() ([]byte, []int) {
+	return file_hunter_v1_hunter_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *FindingEvent) GetScanner() string {
+	if x != nil {
+		return x.Scanner
+	}
+	return ""
+}
+
+func (x *FindingEvent) GetFinding() *Finding {
+	if x != nil {
+		return x.Finding
+	}
+	return nil
+}
+
+// ScanEvent is one update on a watched scan.
+type ScanEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*ScanEvent_Progress
+	//	*ScanEvent_Finding
+	//	*ScanEvent_Done
+	Event         isScanEvent_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScanEvent) Reset() {
+	*x = ScanEvent{}
+	mi := &file_hunter_v1_hunter_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScanEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScanEvent) ProtoMessage() {}
+
+func (x *ScanEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_hunter_v1_hunter_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScanEvent.ProtoReflect.Descriptor instead.
+func (*ScanEvent) Descriptor() ([]byte, []int) {
+	return file_hunter_v1_hunter_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ScanEvent) GetEvent() isScanEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *ScanEvent) GetProgress() *Progress {
+	if x != nil {
+		if x, ok := x.Event.(*ScanEvent_Progress); ok {
+			return x.Progress
+		}
+	}
+	return nil
+}
+
+func (x *ScanEvent) GetFinding() *FindingEvent {
+	if x != nil {
+		if x, ok := x.Event.(*ScanEvent_Finding); ok {
+			return x.Finding
+		}
+	}
+	return nil
+}
+
+func (x *ScanEvent) GetDone() *Job {
+	if x != nil {
+		if x, ok := x.Event.(*ScanEvent_Done); ok {
+			return x.Done
+		}
+	}
+	return nil
+}
+
+type isScanEvent_Event interface {
+	isScanEvent_Event()
+}
+
+type ScanEvent_Progress struct {
+	// progress is sent first and whenever the scan's progress changes.
+	Progress *Progress `protobuf:"bytes,1,opt,name=progress,proto3,oneof"`
+}
+
+type ScanEvent_Finding struct {
+	// finding is sent for each finding, without artifacts.
+	Finding *FindingEvent `protobuf:"bytes,2,opt,name=finding,proto3,oneof"`
+}
+
+type ScanEvent_Done struct {
+	// done is the finished scan, without results, and the last event.
+	Done *Job `protobuf:"bytes,3,opt,name=done,proto3,oneof"`
+}
+
+func (*ScanEvent_Progress) isScanEvent_Event() {}
+
+func (*ScanEvent_Finding) isScanEvent_Event() {}
+
+func (*ScanEvent_Done) isScanEvent_Event() {}
+
+var File_hunter_v1_hunter_proto protoreflect.FileDescriptor
+
+const file_hunter_v1_hunter_proto_rawDesc = "" +
+	"\n" +
+	"\x16hunter/v1/hunter.proto\x12\thunter.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\\\n" +
+	"\x06Target\x12\x12\n" +
+	"\x04host\x18\x01 \x01(\tR\x04host\x12\x14\n" +
+	"\x05ports\x18\x02 \x03(\x05R\x05ports\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12\x16\n" +
+	"\x06scheme\x18\x04 \x01(\tR\x06scheme\"\x88\x01\n" +
+	"\n" +
+	"Engagement\x12\x16\n" +
+	"\x06client\x18\x01 \x01(\tR\x06client\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x16\n" +
+	"\x06tester\x18\x03 \x01(\tR\x06tester\x12$\n" +
+	"\rauthorization\x18\x04 \x01(\tR\rauthorization\x12\x14\n" +
+	"\x05notes\x18\x05 \x01(\tR\x05notes\"3\n" +
+	"\tReference\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\"~\n" +
+	"\bArtifact\x12\x18\n" +
+	"\arequest\x18\x01 \x01(\tR\arequest\x12\x1a\n" +
+	"\bresponse\x18\x02 \x01(\tR\bresponse\x12\x1c\n" +
+	"\ttruncated\x18\x03 \x01(\bR\ttruncated\x12\x1e\n" +
+	"\n" +
+	"screenshot\x18\x04 \x01(\fR\n" +
+	"screenshot\"\xb6\x03\n" +
+	"\aFinding\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12/\n" +
+	"\bseverity\x18\x03 \x01(\x0e2\x13.hunter.v1.SeverityR\bseverity\x12\x1a\n" +
+	"\bevidence\x18\x04 \x01(\tR\bevidence\x12 \n" +
+	"\vremediation\x18\x05 \x01(\tR\vremediation\x124\n" +
+	"\n" +
+	"references\x18\x06 \x03(\v2\x14.hunter.v1.ReferenceR\n" +
+	"references\x12<\n" +
+	"\bmetadata\x18\a \x03(\v2 .hunter.v1.Finding.MetadataEntryR\bmetadata\x121\n" +
+	"\tartifacts\x18\b \x03(\v2\x13.hunter.v1.ArtifactR\tartifacts\x12 \n" +
+	"\vfingerprint\x18\t \x01(\tR\vfingerprint\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x8f\x03\n" +
+	"\n" +
+	"ScanResult\x12\x18\n" +
+	"\ascanner\x18\x01 \x01(\tR\ascanner\x12)\n" +
+	"\x06target\x18\x02 \x01(\v2\x11.hunter.v1.TargetR\x06target\x129\n" +
+	"\n" +
+	"started_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12=\n" +
+	"\fcompleted_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12.\n" +
+	"\bfindings\x18\x05 \x03(\v2\x12.hunter.v1.FindingR\bfindings\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x12?\n" +
+	"\bmetadata\x18\a \x03(\v2#.hunter.v1.ScanResult.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x89\x01\n" +
+	"\bProgress\x12%\n" +
+	"\x0etotal_scanners\x18\x01 \x01(\x05R\rtotalScanners\x12-\n" +
+	"\x12completed_scanners\x18\x02 \x01(\x05R\x11completedScanners\x12'\n" +
+	"\x0fcurrent_scanner\x18\x03 \x01(\tR\x0ecurrentScanner\"\xa9\x04\n" +
+	"\x03Job\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12)\n" +
+	"\x06target\x18\x02 \x01(\v2\x11.hunter.v1.TargetR\x06target\x12\x1a\n" +
+	"\bscanners\x18\x03 \x03(\tR\bscanners\x12,\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x14.hunter.v1.JobStatusR\x06status\x12/\n" +
+	"\aresults\x18\x05 \x03(\v2\x15.hunter.v1.ScanResultR\aresults\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"started_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12=\n" +
+	"\fcompleted_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12/\n" +
+	"\bprogress\x18\n" +
+	" \x01(\v2\x13.hunter.v1.ProgressR\bprogress\x125\n" +
+	"\n" +
+	"engagement\x18\v \x01(\v2\x15.hunter.v1.EngagementR\n" +
+	"engagement\x12\x14\n" +
+	"\x05agent\x18\f \x01(\tR\x05agent\x12#\n" +
+	"\rfinding_count\x18\r \x01(\x05R\ffindingCount\"?\n" +
+	"\aScanner\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\"\x15\n" +
+	"\x13ListScannersRequest\"F\n" +
+	"\x14ListScannersResponse\x12.\n" +
+	"\bscanners\x18\x01 \x03(\v2\x12.hunter.v1.ScannerR\bscanners\"\xf1\x04\n" +
+	"\x11CreateScanRequest\x12\x16\n" +
+	"\x06target\x18\x01 \x01(\tR\x06target\x12\x1a\n" +
+	"\bscanners\x18\x02 \x03(\tR\bscanners\x12\x18\n" +
+	"\aprofile\x18\x03 \x01(\tR\aprofile\x12 \n" +
+	"\vconcurrency\x18\x04 \x01(\x05R\vconcurrency\x123\n" +
+	"\atimeout\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x12<\n" +
+	"\fmax_duration\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\vmaxDuration\x12!\n" +
+	"\fno_preflight\x18\a \x01(\bR\vnoPreflight\x12\x1c\n" +
+	"\tintensity\x18\b \x01(\tR\tintensity\x12\x1d\n" +
+	"\n" +
+	"ip_version\x18\t \x01(\tR\tipVersion\x12\x1a\n" +
+	"\bresolver\x18\n" +
+	" \x01(\tR\bresolver\x12\x18\n" +
+	"\aresolve\x18\v \x03(\tR\aresolve\x12\x14\n" +
+	"\x05crawl\x18\f \x01(\bR\x05crawl\x12\x1f\n" +
+	"\vcrawl_depth\x18\r \x01(\x05R\n" +
+	"crawlDepth\x12\x1f\n" +
+	"\vcrawl_pages\x18\x0e \x01(\x05R\n" +
+	"crawlPages\x12\x16\n" +
+	"\x06redact\x18\x0f \x01(\bR\x06redact\x125\n" +
+	"\n" +
+	"engagement\x18\x10 \x01(\v2\x15.hunter.v1.EngagementR\n" +
+	"engagement\x12&\n" +
+	"\x0fi_am_authorized\x18\x11 \x01(\bR\riAmAuthorized\x12\x14\n" +
+	"\x05agent\x18\x12 \x01(\tR\x05agent\"\x12\n" +
+	"\x10ListScansRequest\"9\n" +
+	"\x11ListScansResponse\x12$\n" +
+	"\x05scans\x18\x01 \x03(\v2\x0e.hunter.v1.JobR\x05scans\">\n" +
+	"\x0eGetScanRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\tartifacts\x18\x02 \x01(\bR\tartifacts\"\"\n" +
+	"\x10WatchScanRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x19\n" +
+	"\aScanRef\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x14\n" +
+	"\x12DeleteScanResponse\"V\n" +
+	"\fFindingEvent\x12\x18\n" +
+	"\ascanner\x18\x01 \x01(\tR\ascanner\x12,\n" +
+	"\afinding\x18\x02 \x01(\v2\x12.hunter.v1.FindingR\afinding\"\xa2\x01\n" +
+	"\tScanEvent\x121\n" +
+	"\bprogress\x18\x01 \x01(\v2\x13.hunter.v1.ProgressH\x00R\bprogress\x123\n" +
+	"\afinding\x18\x02 \x01(\v2\x17.hunter.v1.FindingEventH\x00R\afinding\x12$\n" +
+	"\x04done\x18\x03 \x01(\v2\x0e.hunter.v1.JobH\x00R\x04doneB\a\n" +
+	"\x05event*\x88\x01\n" +
+	"\bSeverity\x12\x18\n" +
+	"\x14SEVERITY_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11SEVERITY_CRITICAL\x10\x01\x12\x11\n" +
+	"\rSEVERITY_HIGH\x10\x02\x12\x13\n" +
+	"\x0fSEVERITY_MEDIUM\x10\x03\x12\x10\n" +
+	"\fSEVERITY_LOW\x10\x04\x12\x11\n" +
+	"\rSEVERITY_INFO\x10\x05*\x9f\x01\n" +
+	"\tJobStatus\x12\x1a\n" +
+	"\x16JOB_STATUS_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12JOB_STATUS_PENDING\x10\x01\x12\x16\n" +
+	"\x12JOB_STATUS_RUNNING\x10\x02\x12\x15\n" +
+	"\x11JOB_STATUS_PAUSED\x10\x03\x12\x18\n" +
+	"\x14JOB_STATUS_COMPLETED\x10\x04\x12\x15\n" +
+	"\x11JOB_STATUS_FAILED\x10\x052\xf9\x03\n" +
+	"\x06Hunter\x12O\n" +
+	"\fListScanners\x12\x1e.hunter.v1.ListScannersRequest\x1a\x1f.hunter.v1.ListScannersResponse\x12:\n" +
+	"\n" +
+	"CreateScan\x12\x1c.hunter.v1.CreateScanRequest\x1a\x0e.hunter.v1.Job\x12F\n" +
+	"\tListScans\x12\x1b.hunter.v1.ListScansRequest\x1a\x1c.hunter.v1.ListScansResponse\x124\n" +
+	"\aGetScan\x12\x19.hunter.v1.GetScanRequest\x1a\x0e.hunter.v1.Job\x12@\n" +
+	"\tWatchScan\x12\x1b.hunter.v1.WatchScanRequest\x1a\x14.hunter.v1.ScanEvent0\x01\x12/\n" +
+	"\tPauseScan\x12\x12.hunter.v1.ScanRef\x1a\x0e.hunter.v1.Job\x120\n" +
+	"\n" +
+	"ResumeScan\x12\x12.hunter.v1.ScanRef\x1a\x0e.hunter.v1.Job\x12?\n" +
+	"\n" +
+	"DeleteScan\x12\x12.hunter.v1.ScanRef\x1a\x1d.hunter.v1.DeleteScanResponseB(Z&github.com/buemura/hunter/pkg/hunterpbb\x06proto3"
+
+var (
+	file_hunter_v1_hunter_proto_rawDescOnce sync.Once
+	file_hunter_v1_hunter_proto_rawDescData []byte
+)
+
+func file_hunter_v1_hunter_proto_rawDescGZIP() []byte {
+	file_hunter_v1_hunter_proto_rawDescOnce.Do(func() {
+		file_hunter_v1_hunter_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_hunter_v1_hunter_proto_rawDesc), len(file_hunter_v1_hunter_proto_rawDesc)))
+	})
+	return file_hunter_v1_hunter_proto_rawDescData
+}
+
+var file_hunter_v1_hunter_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_hunter_v1_hunter_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
+var file_hunter_v1_hunter_proto_goTypes = []any{
+	(Severity)(0),                 // 0: hunter.v1.Severity
+	(JobStatus)(0),                // 1: hunter.v1.JobStatus
+	(*Target)(nil),                // 2: hunter.v1.Target
+	(*Engagement)(nil),            // 3: hunter.v1.Engagement
+	(*Reference)(nil),             // 4: hunter.v1.Reference
+	(*Artifact)(nil),              // 5: hunter.v1.Artifact
+	(*Finding)(nil),               // 6: hunter.v1.Finding
+	(*ScanResult)(nil),            // 7: hunter.v1.ScanResult
+	(*Progress)(nil),              // 8: hunter.v1.Progress
+	(*Job)(nil),                   // 9: hunter.v1.Job
+	(*Scanner)(nil),               // 10: hunter.v1.Scanner
+	(*ListScannersRequest)(nil),   // 11: hunter.v1.ListScannersRequest
+	(*ListScannersResponse)(nil),  // 12: hunter.v1.ListScannersResponse
+	(*CreateScanRequest)(nil),     // 13: hunter.v1.CreateScanRequest
+	(*ListScansRequest)(nil),      // 14: hunter.v1.ListScansRequest
+	(*ListScansResponse)(nil),     // 15: hunter.v1.ListScansResponse
+	(*GetScanRequest)(nil),        // 16: hunter.v1.GetScanRequest
+	(*WatchScanRequest)(nil),      // 17: hunter.v1.WatchScanRequest
+	(*ScanRef)(nil),               // 18: hunter.v1.ScanRef
+	(*DeleteScanResponse)(nil),    // 19: hunter.v1.DeleteScanResponse
+	(*FindingEvent)(nil),          // 20: hunter.v1.FindingEvent
+	(*ScanEvent)(nil),             // 21: hunter.v1.ScanEvent
+	nil,                           // 22: hunter.v1.Finding.MetadataEntry
+	nil,                           // 23: hunter.v1.ScanResult.MetadataEntry
+	(*timestamppb.Timestamp)(nil), // 24: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),   // 25: google.protobuf.Duration
+}
+var file_hunter_v1_hunter_proto_depIdxs = []int32{
+	0,  // 0: hunter.v1.Finding.severity:type_name -> hunter.v1.Severity
+	4,  // 1: hunter.v1.Finding.references:type_name -> hunter.v1.Reference
+	22, // 2: hunter.v1.Finding.metadata:type_name -> hunter.v1.Finding.MetadataEntry
+	5,  // 3: hunter.v1.Finding.artifacts:type_name -> hunter.v1.Artifact
+	2,  // 4: hunter.v1.ScanResult.target:type_name -> hunter.v1.Target
+	24, // 5: hunter.v1.ScanResult.started_at:type_name -> google.protobuf.Timestamp
+	24, // 6: hunter.v1.ScanResult.completed_at:type_name -> google.protobuf.Timestamp
+	6,  // 7: hunter.v1.ScanResult.findings:type_name -> hunter.v1.Finding
+	23, // 8: hunter.v1.ScanResult.metadata:type_name -> hunter.v1.ScanResult.MetadataEntry
+	2,  // 9: hunter.v1.Job.target:type_name -> hunter.v1.Target
+	1,  // 10: hunter.v1.Job.status:type_name -> hunter.v1.JobStatus
+	7,  // 11: hunter.v1.Job.results:type_name -> hunter.v1.ScanResult
+	24, // 12: hunter.v1.Job.created_at:type_name -> google.protobuf.Timestamp
+	24, // 13: hunter.v1.Job.started_at:type_name -> google.protobuf.Timestamp
+	24, // 14: hunter.v1.Job.completed_at:type_name -> google.protobuf.Timestamp
+	8,  // 15: hunter.v1.Job.progress:type_name -> hunter.v1.Progress
+	3,  // 16: hunter.v1.Job.engagement:type_name -> hunter.v1.Engagement
+	10, // 17: hunter.v1.ListScannersResponse.scanners:type_name -> hunter.v1.Scanner
+	25, // 18: hunter.v1.CreateScanRequest.timeout:type_name -> google.protobuf.Duration
+	25, // 19: hunter.v1.CreateScanRequest.max_duration:type_name -> google.protobuf.Duration
+	3,  // 20: hunter.v1.CreateScanRequest.engagement:type_name -> hunter.v1.Engagement
+	9,  // 21: hunter.v1.ListScansResponse.scans:type_name -> hunter.v1.Job
+	6,  // 22: hunter.v1.FindingEvent.finding:type_name -> hunter.v1.Finding
+	8,  // 23: hunter.v1.ScanEvent.progress:type_name -> hunter.v1.Progress
+	20, // 24: hunter.v1.ScanEvent.finding:type_name -> hunter.v1.FindingEvent
+	9,  // 25: hunter.v1.ScanEvent.done:type_name -> hunter.v1.Job
+	11, // 26: hunter.v1.Hunter.ListScanners:input_type -> hunter.v1.ListScannersRequest
+	13, // 27: hunter.v1.Hunter.CreateScan:input_type -> hunter.v1.CreateScanRequest
+	14, // 28: hunter.v1.Hunter.ListScans:input_type -> hunter.v1.ListScansRequest
+	16, // 29: hunter.v1.Hunter.GetScan:input_type -> hunter.v1.GetScanRequest
+	17, // 30: hunter.v1.Hunter.WatchScan:input_type -> hunter.v1.WatchScanRequest
+	18, // 31: hunter.v1.Hunter.PauseScan:input_type -> hunter.v1.ScanRef
+	18, // 32: hunter.v1.Hunter.ResumeScan:input_type -> hunter.v1.ScanRef
+	18, // 33: hunter.v1.Hunter.DeleteScan:input_type -> hunter.v1.ScanRef
+	12, // 34: hunter.v1.Hunter.ListScanners:output_type -> hunter.v1.ListScannersResponse
+	9,  // 35: hunter.v1.Hunter.CreateScan:output_type -> hunter.v1.Job
+	15, // 36: hunter.v1.Hunter.ListScans:output_type -> hunter.v1.ListScansResponse
+	9,  // 37: hunter.v1.Hunter.GetScan:output_type -> hunter.v1.Job
+	21, // 38: hunter.v1.Hunter.WatchScan:output_type -> hunter.v1.ScanEvent
+	9,  // 39: hunter.v1.Hunter.PauseScan:output_type -> hunter.v1.Job
+	9,  // 40: hunter.v1.Hunter.ResumeScan:output_type -> hunter.v1.Job
+	19, // 41: hunter.v1.Hunter.DeleteScan:output_type -> hunter.v1.DeleteScanResponse
+	34, // [34:42] is the sub-list for method output_type
+	26, // [26:34] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
+}
+
+func init() { file_hunter_v1_hunter_proto_init() }
+func file_hunter_v1_hunter_proto_init() {
+	if File_hunter_v1_hunter_proto != nil {
+		return
+	}
+	file_hunter_v1_hunter_proto_msgTypes[19].OneofWrappers = []any{
+		(*ScanEvent_Progress)(nil),
+		(*ScanEvent_Finding)(nil),
+		(*ScanEvent_Done)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_hunter_v1_hunter_proto_rawDesc), len(file_hunter_v1_hunter_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   22,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_hunter_v1_hunter_proto_goTypes,
+		DependencyIndexes: file_hunter_v1_hunter_proto_depIdxs,
+		EnumInfos:         file_hunter_v1_hunter_proto_enumTypes,
+		MessageInfos:      file_hunter_v1_hunter_proto_msgTypes,
+	}.Build()
+	File_hunter_v1_hunter_proto = out.File
+	file_hunter_v1_hunter_proto_goTypes = nil
+	file_hunter_v1_hunter_proto_depIdxs = nil
+}
@@ -0,0 +1,417 @@
+// The Hunter gRPC API: the scans of `hunter serve`, served alongside its
+// REST API with `--grpc-addr`. Regenerate the Go code in pkg/hunterpb with
+// `make proto` after changing this file.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: hunter/v1/hunter.proto
+
+package hunterpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Hunter_ListScanners_FullMethodName = "/hunter.v1.Hunter/ListScanners"
+	Hunter_CreateScan_FullMethodName   = "/hunter.v1.Hunter/CreateScan"
+	Hunter_ListScans_FullMethodName    = "/hunter.v1.Hunter/ListScans"
+	Hunter_GetScan_FullMethodName      = "/hunter.v1.Hunter/GetScan"
+	Hunter_WatchScan_FullMethodName    = "/hunter.v1.Hunter/WatchScan"
+	Hunter_PauseScan_FullMethodName    = "/hunter.v1.Hunter/PauseScan"
+	Hunter_ResumeScan_FullMethodName   = "/hunter.v1.Hunter/ResumeScan"
+	Hunter_DeleteScan_FullMethodName   = "/hunter.v1.Hunter/DeleteScan"
+)
+
+// HunterClient is the client API for Hunter service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Hunter creates, tracks, and controls scans.
+type HunterClient interface {
+	// ListScanners lists the scanners a scan can run.
+	ListScanners(ctx context.Context, in *ListScannersRequest, opts ...grpc.CallOption) (*ListScannersResponse, error)
+	// CreateScan creates and starts a scan, or dispatches it to an agent.
+	CreateScan(ctx context.Context, in *CreateScanRequest, opts ...grpc.CallOption) (*Job, error)
+	// ListScans lists scans, newest first, without their results.
+	ListScans(ctx context.Context, in *ListScansRequest, opts ...grpc.CallOption) (*ListScansResponse, error)
+	// GetScan returns a scan and its results.
+	GetScan(ctx context.Context, in *GetScanRequest, opts ...grpc.CallOption) (*Job, error)
+	// WatchScan streams a scan's progress and findings as they come in,
+	// ending with the finished scan.
+	WatchScan(ctx context.Context, in *WatchScanRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ScanEvent], error)
+	// PauseScan pauses a running scan.
+	PauseScan(ctx context.Context, in *ScanRef, opts ...grpc.CallOption) (*Job, error)
+	// ResumeScan resumes a paused scan.
+	ResumeScan(ctx context.Context, in *ScanRef, opts ...grpc.CallOption) (*Job, error)
+	// DeleteScan deletes a scan.
+	DeleteScan(ctx context.Context, in *ScanRef, opts ...grpc.CallOption) (*DeleteScanResponse, error)
+}
+
+type hunterClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewHunterClient(cc grpc.ClientConnInterface) HunterClient {
+	return &hunterClient{cc}
+}
+
+func (c *hunterClient) ListScanners(ctx context.Context, in *ListScannersRequest, opts ...grpc.CallOption) (*ListScannersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListScannersResponse)
+	err := c.cc.Invoke(ctx, Hunter_ListScanners_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hunterClient) CreateScan(ctx context.Context, in *CreateScanRequest, opts ...grpc.CallOption) (*Job, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Job)
+	err := c.cc.Invoke(ctx, Hunter_CreateScan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hunterClient) ListScans(ctx context.Context, in *ListScansRequest, opts ...grpc.CallOption) (*ListScansResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListScansResponse)
+	err := c.cc.Invoke(ctx, Hunter_ListScans_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hunterClient) GetScan(ctx context.Context, in *GetScanRequest, opts ...grpc.CallOption) (*Job, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Job)
+	err := c.cc.Invoke(ctx, Hunter_GetScan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hunterClient) WatchScan(ctx context.Context, in *WatchScanRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ScanEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Hunter_ServiceDesc.Streams[0], Hunter_WatchScan_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchScanRequest, ScanEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Hunter_WatchScanClient = grpc.ServerStreamingClient[ScanEvent]
+
+func (c *hunterClient) PauseScan(ctx context.Context, in *ScanRef, opts ...grpc.CallOption) (*Job, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Job)
+	err := c.cc.Invoke(ctx, Hunter_PauseScan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hunterClient) ResumeScan(ctx context.Context, in *ScanRef, opts ...grpc.CallOption) (*Job, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Job)
+	err := c.cc.Invoke(ctx, Hunter_ResumeScan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hunterClient) DeleteScan(ctx context.Context, in *ScanRef, opts ...grpc.CallOption) (*DeleteScanResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteScanResponse)
+	err := c.cc.Invoke(ctx, Hunter_DeleteScan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HunterServer is the server API for Hunter service.
+// All implementations must embed UnimplementedHunterServer
+// for forward compatibility.
+//
+// Hunter creates, tracks, and controls scans.
+type HunterServer interface {
+	// ListScanners lists the scanners a scan can run.
+	ListScanners(context.Context, *ListScannersRequest) (*ListScannersResponse, error)
+	// CreateScan creates and starts a scan, or dispatches it to an agent.
+	CreateScan(context.Context, *CreateScanRequest) (*Job, error)
+	// ListScans lists scans, newest first, without their results.
+	ListScans(context.Context, *ListScansRequest) (*ListScansResponse, error)
+	// GetScan returns a scan and its results.
+	GetScan(context.Context, *GetScanRequest) (*Job, error)
+	// WatchScan streams a scan's progress and findings as they come in,
+	// ending with the finished scan.
+	WatchScan(*WatchScanRequest, grpc.ServerStreamingServer[ScanEvent]) error
+	// PauseScan pauses a running scan.
+	PauseScan(context.Context, *ScanRef) (*Job, error)
+	// ResumeScan resumes a paused scan.
+	ResumeScan(context.Context, *ScanRef) (*Job, error)
+	// DeleteScan deletes a scan.
+	DeleteScan(context.Context, *ScanRef) (*DeleteScanResponse, error)
+	mustEmbedUnimplementedHunterServer()
+}
+
+// UnimplementedHunterServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedHunterServer struct{}
+
+func (UnimplementedHunterServer) ListScanners(context.Context, *ListScannersRequest) (*ListScannersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListScanners not implemented")
+}
+func (UnimplementedHunterServer) CreateScan(context.Context, *CreateScanRequest) (*Job, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateScan not implemented")
+}
+func (UnimplementedHunterServer) ListScans(context.Context, *ListScansRequest) (*ListScansResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListScans not implemented")
+}
+func (UnimplementedHunterServer) GetScan(context.Context, *GetScanRequest) (*Job, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetScan not implemented")
+}
+func (UnimplementedHunterServer) WatchScan(*WatchScanRequest, grpc.ServerStreamingServer[ScanEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchScan not implemented")
+}
+func (UnimplementedHunterServer) PauseScan(context.Context, *ScanRef) (*Job, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseScan not implemented")
+}
+func (UnimplementedHunterServer) ResumeScan(context.Context, *ScanRef) (*Job, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeScan not implemented")
+}
+func (UnimplementedHunterServer) DeleteScan(context.Context, *ScanRef) (*DeleteScanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteScan not implemented")
+}
+func (UnimplementedHunterServer) mustEmbedUnimplementedHunterServer() {}
+func (UnimplementedHunterServer) testEmbeddedByValue()                {}
+
+// UnsafeHunterServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to HunterServer will
+// result in compilation errors.
+type UnsafeHunterServer interface {
+	mustEmbedUnimplementedHunterServer()
+}
+
+func RegisterHunterServer(s grpc.ServiceRegistrar, srv HunterServer) {
+	// If the following call pancis, it indicates UnimplementedHunterServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Hunter_ServiceDesc, srv)
+}
+
+func _Hunter_ListScanners_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListScannersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HunterServer).ListScanners(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Hunter_ListScanners_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HunterServer).ListScanners(ctx, req.(*ListScannersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Hunter_CreateScan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateScanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HunterServer).CreateScan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Hunter_CreateScan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HunterServer).CreateScan(ctx, req.(*CreateScanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Hunter_ListScans_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListScansRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HunterServer).ListScans(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Hunter_ListScans_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HunterServer).ListScans(ctx, req.(*ListScansRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Hunter_GetScan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetScanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HunterServer).GetScan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Hunter_GetScan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HunterServer).GetScan(ctx, req.(*GetScanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Hunter_WatchScan_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchScanRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(HunterServer).WatchScan(m, &grpc.GenericServerStream[WatchScanRequest, ScanEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Hunter_WatchScanServer = grpc.ServerStreamingServer[ScanEvent]
+
+func _Hunter_PauseScan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScanRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HunterServer).PauseScan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Hunter_PauseScan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HunterServer).PauseScan(ctx, req.(*ScanRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Hunter_ResumeScan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScanRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HunterServer).ResumeScan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Hunter_ResumeScan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HunterServer).ResumeScan(ctx, req.(*ScanRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Hunter_DeleteScan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScanRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HunterServer).DeleteScan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Hunter_DeleteScan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HunterServer).DeleteScan(ctx, req.(*ScanRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Hunter_ServiceDesc is the grpc.ServiceDesc for Hunter service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Hunter_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "hunter.v1.Hunter",
+	HandlerType: (*HunterServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListScanners",
+			Handler:    _Hunter_ListScanners_Handler,
+		},
+		{
+			MethodName: "CreateScan",
+			Handler:    _Hunter_CreateScan_Handler,
+		},
+		{
+			MethodName: "ListScans",
+			Handler:    _Hunter_ListScans_Handler,
+		},
+		{
+			MethodName: "GetScan",
+			Handler:    _Hunter_GetScan_Handler,
+		},
+		{
+			MethodName: "PauseScan",
+			Handler:    _Hunter_PauseScan_Handler,
+		},
+		{
+			MethodName: "ResumeScan",
+			Handler:    _Hunter_ResumeScan_Handler,
+		},
+		{
+			MethodName: "DeleteScan",
+			Handler:    _Hunter_DeleteScan_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchScan",
+			Handler:       _Hunter_WatchScan_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "hunter/v1/hunter.proto",
+}
@@ -0,0 +1,12 @@
+package types
+
+// Endpoint is one API request in a scan's scope, such as one imported from
+// a HAR file or Postman collection: what to send, not what came back.
+type Endpoint struct {
+	Method string `json:"method"`
+	// URL is absolute, or a path relative to the target.
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body is an example request body, sent as is.
+	Body string `json:"body,omitempty"`
+}
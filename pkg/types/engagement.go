@@ -0,0 +1,90 @@
+package types
+
+import "strings"
+
+// EngagementPrefix starts the keys an Engagement is recorded under in the
+// metadata of scan results, such as "engagement_client".
+const EngagementPrefix = "engagement_"
+
+// Engagement identifies the engagement a scan was run under, as penetration
+// test documentation requires: who it was for, who ran it, and under what
+// authorization. Every field is optional.
+type Engagement struct {
+	Client string `json:"client,omitempty"`
+	// ID is the engagement's reference, such as a contract or ticket number.
+	ID     string `json:"id,omitempty"`
+	Tester string `json:"tester,omitempty"`
+	// Authorization references the permission to test, such as a signed
+	// statement of work.
+	Authorization string `json:"authorization,omitempty"`
+	Notes         string `json:"notes,omitempty"`
+}
+
+// IsZero reports whether no field of e is set.
+func (e Engagement) IsZero() bool {
+	return e == Engagement{}
+}
+
+// Merge returns e with the fields set in over replacing its own.
+func (e Engagement) Merge(over Engagement) Engagement {
+	for _, f := range []struct{ dst, src *string }{
+		{&e.Client, &over.Client},
+		{&e.ID, &over.ID},
+		{&e.Tester, &over.Tester},
+		{&e.Authorization, &over.Authorization},
+		{&e.Notes, &over.Notes},
+	} {
+		if *f.src != "" {
+			*f.dst = *f.src
+		}
+	}
+	return e
+}
+
+// Metadata returns the set fields of e keyed for the metadata of a scan
+// result.
+func (e Engagement) Metadata() map[string]string {
+	m := map[string]string{}
+	for key, value := range map[string]string{
+		"client":        e.Client,
+		"id":            e.ID,
+		"tester":        e.Tester,
+		"authorization": e.Authorization,
+		"notes":         e.Notes,
+	} {
+		if value != "" {
+			m[EngagementPrefix+key] = value
+		}
+	}
+	return m
+}
+
+// EngagementOf returns the engagement recorded in the metadata of results,
+// from the first result that has one.
+func EngagementOf(results []ScanResult) Engagement {
+	for _, r := range results {
+		var e Engagement
+		for k, v := range r.Metadata {
+			key, ok := strings.CutPrefix(k, EngagementPrefix)
+			if !ok {
+				continue
+			}
+			switch key {
+			case "client":
+				e.Client = v
+			case "id":
+				e.ID = v
+			case "tester":
+				e.Tester = v
+			case "authorization":
+				e.Authorization = v
+			case "notes":
+				e.Notes = v
+			}
+		}
+		if !e.IsZero() {
+			return e
+		}
+	}
+	return Engagement{}
+}
@@ -1,6 +1,14 @@
 package types
 
-import "time"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
 
 // Severity represents the severity level of a finding.
 type Severity string
@@ -31,6 +39,18 @@ func SeverityRank(s Severity) int {
 	}
 }
 
+// Confidence levels a check may record under a finding's "confidence"
+// metadata key, from least to most certain. Findings without one are firm.
+const (
+	// ConfidenceTentative is for signs that often have innocent causes,
+	// such as a database error message in a page.
+	ConfidenceTentative = "tentative"
+	// ConfidenceFirm is for behavior a probe reliably triggers.
+	ConfidenceFirm = "firm"
+	// ConfidenceCertain is for behavior confirmed by a control probe.
+	ConfidenceCertain = "certain"
+)
+
 // Finding is a single discovered issue or data point.
 type Finding struct {
 	Title       string            `json:"title"`
@@ -38,7 +58,61 @@ type Finding struct {
 	Severity    Severity          `json:"severity"`
 	Evidence    string            `json:"evidence,omitempty"`
 	Remediation string            `json:"remediation,omitempty"`
+	References  []Reference       `json:"references,omitempty"`
 	Metadata    map[string]string `json:"metadata,omitempty"`
+	Artifacts   []Artifact        `json:"artifacts,omitempty"`
+	Fingerprint string            `json:"fingerprint,omitempty"`
+}
+
+// Reference is documentation on a kind of finding and how to fix it, such
+// as an OWASP cheat sheet or an MDN page.
+type Reference struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// RuleID derives a stable identifier for a kind of finding, such as
+// "headers/missing-strict-transport-security-header", from the scanner name
+// and finding title.
+func RuleID(scanner, title string) string {
+	slug := strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	return scanner + "/" + slug
+}
+
+// Fingerprint returns a short identifier for a finding made by the named
+// scanner, derived from its title and metadata. Severity and confidence are
+// left out, so overrides and surer checks do not change it, and the same
+// probe reproducing in a later scan yields the same fingerprint. So is the
+// rule ID, which follows from the title, so fingerprints stored before rule
+// IDs were recorded still match.
+func Fingerprint(scanner string, f Finding) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s", scanner, f.Title)
+	keys := make([]string, 0, len(f.Metadata))
+	for k := range f.Metadata {
+		if k != "confidence" && k != "rule_id" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(h, "\x00%s=%s", k, f.Metadata[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// Artifact is a raw HTTP exchange a finding was based on, kept so the finding
+// can be verified independently. Request and Response hold the wire form,
+// headers and body, cut at a size limit; Truncated reports whether either
+// was cut. Screenshot is a PNG of the page as a headless browser rendered
+// it, for findings about pages.
+type Artifact struct {
+	Request    string `json:"request"`
+	Response   string `json:"response"`
+	Truncated  bool   `json:"truncated,omitempty"`
+	Screenshot []byte `json:"screenshot,omitempty"`
 }
 
 // ScanResult is the output of a single scanner run.
@@ -49,4 +123,27 @@ type ScanResult struct {
 	CompletedAt time.Time `json:"completed_at"`
 	Findings    []Finding `json:"findings"`
 	Error       string    `json:"error,omitempty"`
+
+	// Metadata describes the scan rather than any one finding, such as what
+	// the pre-flight probe learned about the target.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// WithoutArtifacts returns a copy of results with the artifacts of every
+// finding removed, for exports that leave the raw exchanges out. The input
+// is not modified.
+func WithoutArtifacts(results []ScanResult) []ScanResult {
+	out := make([]ScanResult, len(results))
+	for i, r := range results {
+		out[i] = r
+		if len(r.Findings) == 0 {
+			continue
+		}
+		out[i].Findings = make([]Finding, len(r.Findings))
+		for j, f := range r.Findings {
+			f.Artifacts = nil
+			out[i].Findings[j] = f
+		}
+	}
+	return out
 }
@@ -16,6 +16,39 @@ type Target struct {
 	Scheme string `json:"scheme"`
 }
 
+// plainHTTPPorts are ports on which a scheme-less target is assumed to speak
+// plain HTTP rather than HTTPS.
+var plainHTTPPorts = map[string]bool{"80": true, "8000": true, "8080": true, "8888": true}
+
+// InferScheme turns a scheme-less target into a URL. Well-known plain HTTP
+// ports get http://, everything else https://.
+func InferScheme(raw string) string {
+	if strings.Contains(raw, "://") {
+		return raw
+	}
+	if ip := net.ParseIP(raw); ip != nil && strings.Contains(raw, ":") {
+		return "https://[" + raw + "]"
+	}
+
+	hostPort := raw
+	if i := strings.Index(hostPort, "/"); i >= 0 {
+		hostPort = hostPort[:i]
+	}
+	if _, port, err := net.SplitHostPort(hostPort); err == nil && plainHTTPPorts[port] {
+		return "http://" + raw
+	}
+	return "https://" + raw
+}
+
+// URLHost returns the host as written in a URL: IPv6 literals are
+// bracketed.
+func (t Target) URLHost() string {
+	if strings.Contains(t.Host, ":") {
+		return "[" + t.Host + "]"
+	}
+	return t.Host
+}
+
 // ParseTarget accepts a host, host:port, or full URL and normalizes it into a Target.
 func ParseTarget(raw string) (Target, error) {
 	raw = strings.TrimSpace(raw)
@@ -45,7 +78,10 @@ func ParseTarget(raw string) (Target, error) {
 		}, nil
 	}
 
-	// Plain hostname or IP.
+	// Plain hostname or IP. IPv6 literals may be bracketed, as in URLs.
+	if strings.HasPrefix(raw, "[") && strings.HasSuffix(raw, "]") {
+		raw = raw[1 : len(raw)-1]
+	}
 	return Target{
 		Host:   raw,
 		Scheme: "https",
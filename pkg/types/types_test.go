@@ -49,12 +49,48 @@ func TestParseTarget_IPAddress(t *testing.T) {
 	assert.Equal(t, "https", target.Scheme)
 }
 
+func TestParseTarget_IPv6(t *testing.T) {
+	for _, raw := range []string{"2001:db8::1", "[2001:db8::1]"} {
+		target, err := ParseTarget(raw)
+		require.NoError(t, err)
+		assert.Equal(t, "2001:db8::1", target.Host, raw)
+		assert.Empty(t, target.Ports, raw)
+		assert.Equal(t, "[2001:db8::1]", target.URLHost(), raw)
+	}
+
+	target, err := ParseTarget("[::1]:8443")
+	require.NoError(t, err)
+	assert.Equal(t, "::1", target.Host)
+	assert.Equal(t, []int{8443}, target.Ports)
+
+	target, err = ParseTarget("https://[::1]:8443/api")
+	require.NoError(t, err)
+	assert.Equal(t, "::1", target.Host)
+	assert.Equal(t, []int{8443}, target.Ports)
+}
+
+func TestTarget_URLHost(t *testing.T) {
+	assert.Equal(t, "example.com", Target{Host: "example.com"}.URLHost())
+	assert.Equal(t, "10.0.0.1", Target{Host: "10.0.0.1"}.URLHost())
+	assert.Equal(t, "[::1]", Target{Host: "::1"}.URLHost())
+}
+
 func TestParseTarget_Empty(t *testing.T) {
 	_, err := ParseTarget("")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "empty")
 }
 
+func TestInferScheme(t *testing.T) {
+	assert.Equal(t, "https://example.com", InferScheme("example.com"))
+	assert.Equal(t, "https://example.com/api", InferScheme("example.com/api"))
+	assert.Equal(t, "http://localhost:8080", InferScheme("localhost:8080"))
+	assert.Equal(t, "https://localhost:8443", InferScheme("localhost:8443"))
+	assert.Equal(t, "http://example.com", InferScheme("http://example.com"))
+	assert.Equal(t, "https://[::1]", InferScheme("::1"))
+	assert.Equal(t, "http://[::1]:8080", InferScheme("[::1]:8080"))
+}
+
 func TestParseTarget_Whitespace(t *testing.T) {
 	target, err := ParseTarget("  example.com  ")
 	require.NoError(t, err)
@@ -78,3 +114,66 @@ func TestSeverityRank(t *testing.T) {
 	assert.Less(t, SeverityRank(SeverityMedium), SeverityRank(SeverityLow))
 	assert.Less(t, SeverityRank(SeverityLow), SeverityRank(SeverityInfo))
 }
+
+func TestWithoutArtifacts(t *testing.T) {
+	results := []ScanResult{
+		{ScannerName: "vuln", Findings: []Finding{
+			{Title: "xss", Artifacts: []Artifact{{Request: "GET / HTTP/1.1\r\n", Response: "HTTP/1.1 200 OK\r\n"}}},
+		}},
+		{ScannerName: "headers"},
+	}
+
+	stripped := WithoutArtifacts(results)
+	require.Len(t, stripped, 2)
+	assert.Equal(t, "xss", stripped[0].Findings[0].Title)
+	assert.Nil(t, stripped[0].Findings[0].Artifacts)
+	assert.Len(t, results[0].Findings[0].Artifacts, 1, "input must not be modified")
+}
+
+func TestRuleID(t *testing.T) {
+	assert.Equal(t, "headers/missing-strict-transport-security-header", RuleID("headers", "Missing Strict-Transport-Security header"))
+	assert.Equal(t, "port/open-port-80-http", RuleID("port", "Open port: 80/HTTP"))
+	assert.Equal(t, "deserialization/net-viewstate-without-mac", RuleID("deserialization", ".NET ViewState without MAC"))
+}
+
+func TestFingerprint(t *testing.T) {
+	f := Finding{Title: "Potential reflected XSS", Severity: SeverityHigh, Metadata: map[string]string{"param": "q", "payload": "<script>"}}
+
+	fp := Fingerprint("vuln", f)
+	assert.Len(t, fp, 12)
+
+	lowered := f
+	lowered.Severity = SeverityLow
+	assert.Equal(t, fp, Fingerprint("vuln", lowered), "severity must not change the fingerprint")
+
+	graded := Finding{Title: f.Title, Metadata: map[string]string{"param": "q", "payload": "<script>", "confidence": ConfidenceCertain}}
+	assert.Equal(t, fp, Fingerprint("vuln", graded), "confidence must not change the fingerprint")
+
+	other := Finding{Title: f.Title, Metadata: map[string]string{"param": "id", "payload": "<script>"}}
+	assert.NotEqual(t, fp, Fingerprint("vuln", other))
+	assert.NotEqual(t, fp, Fingerprint("api", f))
+}
+
+func TestEngagement(t *testing.T) {
+	base := Engagement{Client: "ACME Corp", Tester: "jdoe"}
+	assert.True(t, Engagement{}.IsZero())
+	assert.False(t, base.IsZero())
+
+	merged := base.Merge(Engagement{Tester: "asmith", ID: "ENG-42"})
+	assert.Equal(t, Engagement{Client: "ACME Corp", ID: "ENG-42", Tester: "asmith"}, merged)
+
+	m := merged.Metadata()
+	assert.Equal(t, map[string]string{
+		"engagement_client": "ACME Corp",
+		"engagement_id":     "ENG-42",
+		"engagement_tester": "asmith",
+	}, m)
+	assert.Empty(t, Engagement{}.Metadata())
+
+	results := []ScanResult{
+		{ScannerName: "port", Metadata: map[string]string{"server": "nginx"}},
+		{ScannerName: "headers", Metadata: m},
+	}
+	assert.Equal(t, merged, EngagementOf(results))
+	assert.True(t, EngagementOf(results[:1]).IsZero())
+}